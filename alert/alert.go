@@ -0,0 +1,191 @@
+// Package alert tracks threshold alert levels with hysteresis, minimum durations and per-rule schedules,
+// announces level changes to the event hooks and silences alerts during maintenance windows
+package alert
+
+import (
+	"fmt"
+	"github.com/ahmadreza-log/simple-monitor/hooks"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// announced is shared by every tracker so each alert change reaches the hooks once
+var announced = announcedState{alerts: make(map[string]announcement)}
+
+// ownerTimeout is how long a raised alert stays with the tracker that announced it after that tracker last
+// evaluated it; a tracker that stopped collecting (the live monitor was left) then hands it to another
+const ownerTimeout = 2 * time.Minute
+
+// NewTracker creates a tracker for the alerts of one monitor, with every alert at LevelNormal
+func NewTracker(monitor string) *Tracker {
+	return &Tracker{monitor: monitor, states: make(map[string]*state)}
+}
+
+// Evaluate returns the level to report for the alert key given its current value
+// A raised alert is held until the value falls Hysteresis below the threshold, and level
+// changes only take effect once the value has stayed at the new level for RaiseAfter or ClearAfter.
+// A schedule of the rule active at now replaces the thresholds it sets.
+// During maintenance the level is still tracked, but raised alerts are reported as LevelNormal
+func (tracker *Tracker) Evaluate(key string, value, warning, critical float64, rule Rule, now time.Time) Level {
+	warning, critical = rule.Thresholds(warning, critical, now)
+	level := tracker.evaluate(key, value, warning, critical, rule, now)
+	if level > LevelNormal {
+		message := fmt.Sprintf("%s %s at %g", key, level, value)
+		if rule.Note != "" {
+			message += " (" + rule.Note + ")"
+		}
+		if Suppress(tracker.monitor, key+":"+level.String(), message, now) {
+			level = LevelNormal
+		}
+	}
+
+	tracker.mutex.Lock()
+	if current, known := tracker.states[key]; known {
+		current.reported = level
+		current.note = rule.Note
+	}
+	tracker.mutex.Unlock()
+
+	tracker.announce(key, level, value, warning, critical, rule.Note, now)
+	return level
+}
+
+// announce fires the alert_raised and alert_cleared hooks when the reported level of an alert changes
+// The same alert can be followed by several trackers (the live monitor and the published snapshot) that see
+// different samples, so a raised alert belongs to the tracker that raised it: only that tracker's hysteresis
+// decides when it changes or clears, and every change is announced once
+func (tracker *Tracker) announce(key string, level Level, value, warning, critical float64, note string, now time.Time) {
+	monitor := tracker.monitor
+	id := monitor + "/" + key
+	announced.mutex.Lock()
+	current, raised := announced.alerts[id]
+	if raised && current.tracker != tracker && now.Sub(current.seen) < ownerTimeout {
+		announced.mutex.Unlock()
+		return
+	}
+	previous := current.level
+	if level == previous {
+		if raised {
+			announced.alerts[id] = announcement{level: level, tracker: tracker, seen: now}
+		}
+		announced.mutex.Unlock()
+		return
+	}
+	if level == LevelNormal {
+		delete(announced.alerts, id)
+	} else {
+		announced.alerts[id] = announcement{level: level, tracker: tracker, seen: now}
+	}
+	announced.mutex.Unlock()
+
+	event := hooks.EventAlertRaised
+	if level == LevelNormal {
+		event = hooks.EventAlertCleared
+	}
+	hooks.Fire(event, map[string]string{
+		"MONITOR":        monitor,
+		"ALERT":          key,
+		"LEVEL":          level.String(),
+		"PREVIOUS_LEVEL": previous.String(),
+		"VALUE":          strconv.FormatFloat(value, 'f', -1, 64),
+		"WARNING":        strconv.FormatFloat(warning, 'f', -1, 64),
+		"CRITICAL":       strconv.FormatFloat(critical, 'f', -1, 64),
+		"NOTE":           note,
+	})
+}
+
+// Notes returns the notes of the alerts currently reported as raised, ordered by key
+func (tracker *Tracker) Notes() []Note {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	var notes []Note
+	for key, current := range tracker.states {
+		if current.reported > LevelNormal && current.note != "" {
+			notes = append(notes, Note{Key: key, Level: current.reported.String(), Text: current.note})
+		}
+	}
+	sort.Slice(notes, func(i, j int) bool {
+		return notes[i].Key < notes[j].Key
+	})
+	return notes
+}
+
+// evaluate updates and returns the tracked level of one alert
+func (tracker *Tracker) evaluate(key string, value, warning, critical float64, rule Rule, now time.Time) Level {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	current, known := tracker.states[key]
+	if !known {
+		current = &state{}
+		tracker.states[key] = current
+	}
+	current.seen = now
+
+	// While raised, the alert only drops to the level the value would have with the hysteresis added
+	target := LevelFor(value, warning, critical)
+	if target < current.level {
+		held := LevelFor(value+rule.Hysteresis, warning, critical)
+		target = min(current.level, max(target, held))
+	}
+
+	if target == current.level {
+		current.pending = current.level
+		current.pendingSince = time.Time{}
+		return current.level
+	}
+
+	if target != current.pending || current.pendingSince.IsZero() {
+		current.pending = target
+		current.pendingSince = now
+	}
+	wait := rule.RaiseAfter
+	if target < current.level {
+		wait = rule.ClearAfter
+	}
+	if now.Sub(current.pendingSince) >= wait {
+		current.level = target
+		current.pendingSince = time.Time{}
+	}
+	return current.level
+}
+
+// Forget drops alerts that were not evaluated since the given time
+// Call it after a collection with that collection's time so vanished devices and targets start fresh
+func (tracker *Tracker) Forget(since time.Time) {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	for key, current := range tracker.states {
+		if current.seen.Before(since) {
+			delete(tracker.states, key)
+		}
+	}
+}
+
+// LevelFor returns the level of value against the thresholds without any hysteresis
+// Alerts with a single threshold pass math.Inf(1) as critical
+func LevelFor(value, warning, critical float64) Level {
+	switch {
+	case value >= critical:
+		return LevelCritical
+	case value >= warning:
+		return LevelWarning
+	default:
+		return LevelNormal
+	}
+}
+
+// String returns the status name used throughout the monitors: Normal, Warning or Critical
+func (level Level) String() string {
+	switch level {
+	case LevelCritical:
+		return "Critical"
+	case LevelWarning:
+		return "Warning"
+	default:
+		return "Normal"
+	}
+}
@@ -0,0 +1,162 @@
+package alert
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAnnounceKeepsAlertWithRaisingTracker(t *testing.T) {
+	live, follower := NewTracker("test-owner"), NewTracker("test-owner")
+	rule := Rule{Hysteresis: 10}
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	id := "test-owner/usage"
+	defer func() {
+		announced.mutex.Lock()
+		delete(announced.alerts, id)
+		announced.mutex.Unlock()
+	}()
+
+	steps := []struct {
+		tracker *Tracker
+		value   float64
+		after   time.Duration
+		want    Level
+		owner   *Tracker
+	}{
+		{tracker: live, value: 85, want: LevelWarning, owner: live},
+		// The follower sees a sample below the threshold, but the live tracker still holds the alert
+		{tracker: follower, value: 75, after: time.Second, want: LevelWarning, owner: live},
+		// Within the hysteresis the live tracker keeps it raised
+		{tracker: live, value: 75, after: 2 * time.Second, want: LevelWarning, owner: live},
+		{tracker: live, value: 65, after: 3 * time.Second, want: LevelNormal},
+		{tracker: follower, value: 85, after: 4 * time.Second, want: LevelWarning, owner: follower},
+		// Once the follower stops evaluating, the live tracker takes over and clears it
+		{tracker: live, value: 50, after: 4*time.Second + ownerTimeout, want: LevelNormal},
+	}
+	for i, step := range steps {
+		step.tracker.Evaluate("usage", step.value, 80, 90, rule, start.Add(step.after))
+
+		announced.mutex.Lock()
+		current, raised := announced.alerts[id]
+		announced.mutex.Unlock()
+		if current.level != step.want || raised && current.tracker != step.owner {
+			t.Fatalf("step %d: announced %v (raised %v), want %v", i+1, current.level, raised, step.want)
+		}
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	type sample struct {
+		value float64
+		after time.Duration
+		want  Level
+	}
+	tests := []struct {
+		name    string
+		rule    Rule
+		samples []sample
+	}{
+		{
+			name: "plain thresholds",
+			samples: []sample{
+				{value: 50, want: LevelNormal},
+				{value: 80, want: LevelWarning},
+				{value: 95, want: LevelCritical},
+				{value: 79.9, want: LevelNormal},
+			},
+		},
+		{
+			name: "hysteresis holds the raised level",
+			rule: Rule{Hysteresis: 5},
+			samples: []sample{
+				{value: 92, want: LevelCritical},
+				{value: 88, want: LevelCritical},
+				{value: 84, want: LevelWarning},
+				{value: 76, want: LevelWarning},
+				{value: 74.9, want: LevelNormal},
+				{value: 79, want: LevelNormal},
+			},
+		},
+		{
+			name: "raise after",
+			rule: Rule{RaiseAfter: time.Minute},
+			samples: []sample{
+				{value: 85, want: LevelNormal},
+				{value: 85, after: 30 * time.Second, want: LevelNormal},
+				{value: 85, after: time.Minute, want: LevelWarning},
+			},
+		},
+		{
+			name: "a dip restarts raise after",
+			rule: Rule{RaiseAfter: time.Minute},
+			samples: []sample{
+				{value: 85, want: LevelNormal},
+				{value: 50, after: 30 * time.Second, want: LevelNormal},
+				{value: 85, after: 40 * time.Second, want: LevelNormal},
+				{value: 85, after: 90 * time.Second, want: LevelNormal},
+				{value: 85, after: 100 * time.Second, want: LevelWarning},
+			},
+		},
+		{
+			name: "clear after",
+			rule: Rule{ClearAfter: time.Minute},
+			samples: []sample{
+				{value: 85, want: LevelWarning},
+				{value: 50, after: time.Second, want: LevelWarning},
+				{value: 50, after: 61 * time.Second, want: LevelNormal},
+			},
+		},
+	}
+
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := NewTracker("test-evaluate-" + tt.name)
+			for i, sample := range tt.samples {
+				if got := tracker.Evaluate("usage", sample.value, 80, 90, tt.rule, start.Add(sample.after)); got != sample.want {
+					t.Fatalf("sample %d (%g): Evaluate() = %v, want %v", i+1, sample.value, got, sample.want)
+				}
+			}
+		})
+	}
+}
+
+func TestForget(t *testing.T) {
+	tracker := NewTracker("test-forget")
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	rule := Rule{Hysteresis: 10}
+	tracker.Evaluate("disk:sda", 85, 80, 90, rule, start)
+	tracker.Evaluate("disk:sdb", 85, 80, 90, rule, start.Add(time.Minute))
+
+	tracker.Forget(start.Add(time.Minute))
+	// sda starts fresh, so 75 is below the threshold; sdb is still held by the hysteresis
+	if got := tracker.Evaluate("disk:sda", 75, 80, 90, rule, start.Add(2*time.Minute)); got != LevelNormal {
+		t.Errorf("forgotten alert Evaluate() = %v, want Normal", got)
+	}
+	if got := tracker.Evaluate("disk:sdb", 75, 80, 90, rule, start.Add(2*time.Minute)); got != LevelWarning {
+		t.Errorf("kept alert Evaluate() = %v, want Warning", got)
+	}
+}
+
+func TestNotes(t *testing.T) {
+	tracker := NewTracker("test-notes")
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	tracker.Evaluate("swap", 95, 80, 90, Rule{Note: "see runbook/swap"}, now)
+	tracker.Evaluate("memory", 85, 80, 90, Rule{Note: "see runbook/memory"}, now)
+	tracker.Evaluate("usage", 95, 80, 90, Rule{}, now)
+	tracker.Evaluate("load", 10, 80, 90, Rule{Note: "not raised"}, now)
+
+	want := []Note{
+		{Key: "memory", Level: "Warning", Text: "see runbook/memory"},
+		{Key: "swap", Level: "Critical", Text: "see runbook/swap"},
+	}
+	got := tracker.Notes()
+	if len(got) != len(want) {
+		t.Fatalf("Notes() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Notes()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
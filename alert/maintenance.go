@@ -0,0 +1,297 @@
+package alert
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LogPath is where alerts raised during maintenance are written in log mode
+var LogPath = filepath.Join("logs", "alerts", "maintenance.log")
+
+// MaxWindowDuration caps how long one maintenance window may last
+const MaxWindowDuration = 7 * 24 * time.Hour
+
+// maintenance is shared by every monitor so one snooze silences them all
+var maintenance = maintenanceState{
+	config: Config{MaintenanceWindows: []string{}, MaintenanceMode: ModeLog},
+}
+
+// ParseWindow parses a window such as "0 2 * * * 2h backups" or "30 22 * * 5 90m"
+// The five cron fields are minute, hour, day of month, month and weekday (0 = Sunday);
+// each accepts *, numbers, ranges (1-5), lists (1,15) and steps (*/15)
+func ParseWindow(spec string) (Window, error) {
+	fields := strings.Fields(spec)
+	if len(fields) < 6 {
+		return Window{}, fmt.Errorf("window %q needs 5 cron fields and a duration", spec)
+	}
+
+	window := Window{Spec: spec, Name: strings.Join(fields[6:], " ")}
+	duration, err := time.ParseDuration(fields[5])
+	if err != nil {
+		return Window{}, fmt.Errorf("window %q: invalid duration %q", spec, fields[5])
+	}
+	if duration <= 0 || duration > MaxWindowDuration {
+		return Window{}, fmt.Errorf("window %q: duration must be between 1m and %s", spec, MaxWindowDuration)
+	}
+	window.Duration = duration
+
+	if err := window.parseCronFields(fields[:5]); err != nil {
+		return Window{}, fmt.Errorf("window %q: %w", spec, err)
+	}
+	return window, nil
+}
+
+// ParseTimes parses a cron schedule without a duration, such as "0 * * * *" (hourly) or "30 6 * * 1-5"
+// The result starts at every matching minute and lasts one minute
+func ParseTimes(spec string) (Window, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return Window{}, fmt.Errorf("schedule %q needs 5 cron fields (minute hour day month weekday)", spec)
+	}
+
+	window := Window{Spec: spec, Duration: time.Minute}
+	if err := window.parseCronFields(fields); err != nil {
+		return Window{}, fmt.Errorf("schedule %q: %w", spec, err)
+	}
+	return window, nil
+}
+
+// parseCronFields fills in the allowed values from the five cron fields
+func (window *Window) parseCronFields(fields []string) error {
+	ranges := []struct {
+		name     string
+		min, max int
+		target   *[]bool
+	}{
+		{"minute", 0, 59, &window.minutes},
+		{"hour", 0, 23, &window.hours},
+		{"day of month", 1, 31, &window.days},
+		{"month", 1, 12, &window.months},
+		{"weekday", 0, 7, &window.weekdays},
+	}
+	for i, field := range ranges {
+		allowed, err := parseCronField(fields[i], field.min, field.max)
+		if err != nil {
+			return fmt.Errorf("%s: %w", field.name, err)
+		}
+		*field.target = allowed
+	}
+
+	// Both 0 and 7 mean Sunday
+	if window.weekdays[7] {
+		window.weekdays[0] = true
+	}
+	window.anyDay = fields[2] == "*"
+	window.anyWeekday = fields[4] == "*"
+	return nil
+}
+
+// parseCronField returns which values between min and max one cron field allows
+func parseCronField(field string, min, max int) ([]bool, error) {
+	allowed := make([]bool, max+1)
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if base, stepText, found := strings.Cut(part, "/"); found {
+			value, err := strconv.Atoi(stepText)
+			if err != nil || value <= 0 {
+				return nil, fmt.Errorf("invalid step %q", stepText)
+			}
+			part, step = base, value
+		}
+
+		first, last := min, max
+		if part != "*" {
+			low, high, isRange := strings.Cut(part, "-")
+			value, err := strconv.Atoi(low)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value %q", part)
+			}
+			first, last = value, value
+			if isRange {
+				if last, err = strconv.Atoi(high); err != nil {
+					return nil, fmt.Errorf("invalid value %q", part)
+				}
+			} else if step > 1 {
+				// "5/15" means every 15 starting at 5
+				last = max
+			}
+		}
+		if first < min || last > max || first > last {
+			return nil, fmt.Errorf("%q is outside %d-%d", part, min, max)
+		}
+		for value := first; value <= last; value += step {
+			allowed[value] = true
+		}
+	}
+	return allowed, nil
+}
+
+// Starts returns whether the window starts at the given minute
+// As in cron, when both day fields are restricted either one may match
+func (window Window) Starts(t time.Time) bool {
+	if !window.minutes[t.Minute()] || !window.hours[t.Hour()] || !window.months[int(t.Month())] {
+		return false
+	}
+	dayMatches := window.days[t.Day()]
+	weekdayMatches := window.weekdays[int(t.Weekday())]
+	switch {
+	case window.anyDay && window.anyWeekday:
+		return true
+	case window.anyDay:
+		return weekdayMatches
+	case window.anyWeekday:
+		return dayMatches
+	default:
+		return dayMatches || weekdayMatches
+	}
+}
+
+// ActiveUntil returns when the window ends if it is active at now
+func (window Window) ActiveUntil(now time.Time) (time.Time, bool) {
+	minute := now.Truncate(time.Minute)
+	for start := minute; now.Sub(start) < window.Duration; start = start.Add(-time.Minute) {
+		if window.Starts(start) {
+			return start.Add(window.Duration), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// SetConfig replaces the maintenance windows and mode
+func SetConfig(config Config) error {
+	if config.MaintenanceMode != ModeSuppress && config.MaintenanceMode != ModeLog {
+		return fmt.Errorf("unknown maintenance mode %q (use %s or %s)", config.MaintenanceMode, ModeSuppress, ModeLog)
+	}
+	var windows []Window
+	for _, spec := range config.MaintenanceWindows {
+		window, err := ParseWindow(spec)
+		if err != nil {
+			return err
+		}
+		windows = append(windows, window)
+	}
+
+	maintenance.mutex.Lock()
+	defer maintenance.mutex.Unlock()
+	maintenance.config = config
+	maintenance.windows = windows
+	return nil
+}
+
+// GetConfig returns the maintenance windows and mode
+func GetConfig() Config {
+	maintenance.mutex.Lock()
+	defer maintenance.mutex.Unlock()
+	config := maintenance.config
+	config.MaintenanceWindows = append([]string{}, config.MaintenanceWindows...)
+	return config
+}
+
+// Snooze suppresses alerts for the given duration from now
+func Snooze(duration time.Duration) error {
+	if duration <= 0 {
+		return errors.New("snooze duration must be greater than zero")
+	}
+	maintenance.mutex.Lock()
+	defer maintenance.mutex.Unlock()
+	maintenance.snoozeUntil = time.Now().Add(duration)
+	return nil
+}
+
+// Resume ends a snooze early; scheduled windows still apply
+func Resume() {
+	maintenance.mutex.Lock()
+	defer maintenance.mutex.Unlock()
+	maintenance.snoozeUntil = time.Time{}
+}
+
+// SnoozedUntil returns when the current snooze ends (zero when not snoozed)
+func SnoozedUntil() time.Time {
+	maintenance.mutex.Lock()
+	defer maintenance.mutex.Unlock()
+	if time.Now().After(maintenance.snoozeUntil) {
+		return time.Time{}
+	}
+	return maintenance.snoozeUntil
+}
+
+// ActiveMaintenance describes the snooze or window in effect at now
+func ActiveMaintenance(now time.Time) (string, bool) {
+	maintenance.mutex.Lock()
+	defer maintenance.mutex.Unlock()
+	return maintenance.active(now)
+}
+
+// active describes the snooze or window in effect at now; the caller holds the mutex
+func (state *maintenanceState) active(now time.Time) (string, bool) {
+	if now.Before(state.snoozeUntil) {
+		return "snoozed until " + state.snoozeUntil.Format("15:04"), true
+	}
+	for _, window := range state.windows {
+		if end, active := window.ActiveUntil(now); active {
+			name := window.Name
+			if name == "" {
+				name = window.Spec
+			}
+			return fmt.Sprintf("maintenance window %q until %s", name, end.Format("15:04")), true
+		}
+	}
+	return "", false
+}
+
+// Status returns the line shown under live monitors while alerts are suppressed, or ""
+func Status() string {
+	maintenance.mutex.Lock()
+	defer maintenance.mutex.Unlock()
+	reason, active := maintenance.active(time.Now())
+	if !active {
+		return ""
+	}
+	if maintenance.config.MaintenanceMode == ModeLog {
+		return fmt.Sprintf("🔕 Alerts %s (logged to %s)", reason, LogPath)
+	}
+	return fmt.Sprintf("🔕 Alerts %s", reason)
+}
+
+// Suppress returns whether an alert raised at now falls in maintenance and must be hidden
+// In log mode the alert is written to LogPath the first time it is seen in each window
+func Suppress(monitor, key, message string, now time.Time) bool {
+	maintenance.mutex.Lock()
+	defer maintenance.mutex.Unlock()
+
+	reason, active := maintenance.active(now)
+	if !active {
+		return false
+	}
+	if maintenance.config.MaintenanceMode != ModeLog {
+		return true
+	}
+
+	if reason != maintenance.period {
+		maintenance.period = reason
+		maintenance.logged = make(map[string]bool)
+	}
+	if id := monitor + "/" + key; !maintenance.logged[id] {
+		maintenance.logged[id] = true
+		writeLogLine(fmt.Sprintf("%s [%s] %s: %s\n", now.Format("2006-01-02 15:04:05"), reason, monitor, message))
+	}
+	return true
+}
+
+// writeLogLine appends one line to the maintenance log; failures are ignored so monitoring carries on
+func writeLogLine(line string) {
+	if err := os.MkdirAll(filepath.Dir(LogPath), 0755); err != nil {
+		return
+	}
+	file, err := os.OpenFile(LogPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+	file.WriteString(line)
+}
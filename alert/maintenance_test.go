@@ -0,0 +1,169 @@
+package alert
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseWindow(t *testing.T) {
+	tests := []struct {
+		spec         string
+		wantName     string
+		wantDuration time.Duration
+		wantErr      bool
+	}{
+		{spec: "0 2 * * * 2h backups", wantName: "backups", wantDuration: 2 * time.Hour},
+		{spec: "30 22 * * 5 90m", wantDuration: 90 * time.Minute},
+		{spec: "*/15 8-18 1,15 * 1-5 5m patch window", wantName: "patch window", wantDuration: 5 * time.Minute},
+		{spec: "0 2 * * *", wantErr: true},
+		{spec: "0 2 * * * soon", wantErr: true},
+		{spec: "0 2 * * * 0s", wantErr: true},
+		{spec: "0 2 * * * 200h", wantErr: true},
+		{spec: "60 2 * * * 1h", wantErr: true},
+		{spec: "0 24 * * * 1h", wantErr: true},
+		{spec: "0 2 0 * * 1h", wantErr: true},
+		{spec: "0 2 * 13 * 1h", wantErr: true},
+		{spec: "0 2 * * 8 1h", wantErr: true},
+		{spec: "*/0 2 * * * 1h", wantErr: true},
+		{spec: "5-1 2 * * * 1h", wantErr: true},
+	}
+	for _, tt := range tests {
+		window, err := ParseWindow(tt.spec)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseWindow(%q) error = %v, want error %v", tt.spec, err, tt.wantErr)
+			continue
+		}
+		if err == nil && (window.Name != tt.wantName || window.Duration != tt.wantDuration) {
+			t.Errorf("ParseWindow(%q) = name %q, duration %v, want %q, %v", tt.spec, window.Name, window.Duration, tt.wantName, tt.wantDuration)
+		}
+	}
+}
+
+func TestWindowActiveUntil(t *testing.T) {
+	tests := []struct {
+		spec    string
+		now     time.Time
+		wantEnd time.Time // zero when not active
+	}{
+		// Monday 5 January 2026
+		{spec: "0 2 * * * 2h", now: time.Date(2026, 1, 5, 3, 59, 59, 0, time.UTC), wantEnd: time.Date(2026, 1, 5, 4, 0, 0, 0, time.UTC)},
+		{spec: "0 2 * * * 2h", now: time.Date(2026, 1, 5, 4, 0, 0, 0, time.UTC)},
+		{spec: "0 2 * * * 2h", now: time.Date(2026, 1, 5, 1, 59, 0, 0, time.UTC)},
+		{spec: "*/15 * * * * 5m", now: time.Date(2026, 1, 5, 10, 47, 0, 0, time.UTC), wantEnd: time.Date(2026, 1, 5, 10, 50, 0, 0, time.UTC)},
+		{spec: "*/15 * * * * 5m", now: time.Date(2026, 1, 5, 10, 52, 0, 0, time.UTC)},
+		// Weekday 7 is Sunday like 0
+		{spec: "0 0 * * 7 24h", now: time.Date(2026, 1, 4, 18, 0, 0, 0, time.UTC), wantEnd: time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)},
+		// With both day fields restricted either may match, as in cron
+		{spec: "0 9 15 * 1 1h", now: time.Date(2026, 1, 5, 9, 30, 0, 0, time.UTC), wantEnd: time.Date(2026, 1, 5, 10, 0, 0, 0, time.UTC)},
+		{spec: "0 9 15 * 1 1h", now: time.Date(2026, 1, 15, 9, 30, 0, 0, time.UTC), wantEnd: time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC)},
+		{spec: "0 9 15 * 1 1h", now: time.Date(2026, 1, 6, 9, 30, 0, 0, time.UTC)},
+		{spec: "0 9 * 2 * 1h", now: time.Date(2026, 1, 5, 9, 30, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		window, err := ParseWindow(tt.spec)
+		if err != nil {
+			t.Fatalf("ParseWindow(%q) error = %v", tt.spec, err)
+		}
+		end, active := window.ActiveUntil(tt.now)
+		if active != !tt.wantEnd.IsZero() || !end.Equal(tt.wantEnd) {
+			t.Errorf("%q ActiveUntil(%s) = %s, %v, want %s", tt.spec, tt.now.Format(time.DateTime), end.Format(time.DateTime), active, tt.wantEnd.Format(time.DateTime))
+		}
+	}
+}
+
+func TestParseTimes(t *testing.T) {
+	window, err := ParseTimes("30 6 * * 1-5")
+	if err != nil {
+		t.Fatalf("ParseTimes() error = %v", err)
+	}
+	if !window.Starts(time.Date(2026, 1, 5, 6, 30, 0, 0, time.UTC)) || window.Starts(time.Date(2026, 1, 4, 6, 30, 0, 0, time.UTC)) {
+		t.Error("ParseTimes(\"30 6 * * 1-5\") should start on weekdays at 06:30 only")
+	}
+	if _, err := ParseTimes("30 6 * * 1-5 1h"); err == nil {
+		t.Error("ParseTimes() with a duration: error = nil, want one")
+	}
+}
+
+// withMaintenance sets the maintenance configuration for one test and restores it afterwards
+func withMaintenance(t *testing.T, config Config) {
+	t.Helper()
+	previous := GetConfig()
+	previousLog := LogPath
+	LogPath = filepath.Join(t.TempDir(), "maintenance.log")
+	if err := SetConfig(config); err != nil {
+		t.Fatalf("SetConfig() error = %v", err)
+	}
+	t.Cleanup(func() {
+		SetConfig(previous)
+		LogPath = previousLog
+		maintenance.mutex.Lock()
+		maintenance.period = ""
+		maintenance.logged = nil
+		maintenance.mutex.Unlock()
+	})
+}
+
+func TestSuppress(t *testing.T) {
+	inWindow := time.Date(2026, 1, 5, 2, 30, 0, 0, time.UTC)
+	outside := time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		mode         string
+		now          time.Time
+		want         bool
+		wantLogLines int
+	}{
+		{name: "suppress mode in the window", mode: ModeSuppress, now: inWindow, want: true},
+		{name: "log mode in the window", mode: ModeLog, now: inWindow, want: true, wantLogLines: 1},
+		{name: "outside the window", mode: ModeLog, now: outside},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withMaintenance(t, Config{MaintenanceWindows: []string{"0 2 * * * 2h backups"}, MaintenanceMode: tt.mode})
+
+			// The same alert twice in one window is logged once
+			for i := 0; i < 2; i++ {
+				if got := Suppress("cpu", "usage:Warning", "usage Warning at 85", tt.now); got != tt.want {
+					t.Fatalf("Suppress() = %v, want %v", got, tt.want)
+				}
+			}
+			content, _ := os.ReadFile(LogPath)
+			lines := strings.Count(string(content), "\n")
+			if lines != tt.wantLogLines {
+				t.Errorf("maintenance log has %d lines, want %d:\n%s", lines, tt.wantLogLines, content)
+			}
+			if tt.wantLogLines > 0 && !strings.Contains(string(content), `maintenance window "backups"`) {
+				t.Errorf("maintenance log = %q, want the window name", content)
+			}
+		})
+	}
+}
+
+func TestEvaluateDuringMaintenance(t *testing.T) {
+	withMaintenance(t, Config{MaintenanceWindows: []string{"0 2 * * * 2h"}, MaintenanceMode: ModeSuppress})
+	tracker := NewTracker("test-maintenance")
+
+	if got := tracker.Evaluate("usage", 95, 80, 90, Rule{}, time.Date(2026, 1, 5, 2, 30, 0, 0, time.UTC)); got != LevelNormal {
+		t.Errorf("Evaluate() in the window = %v, want Normal", got)
+	}
+	// The level was still tracked, so it is reported as soon as the window ends
+	if got := tracker.Evaluate("usage", 95, 80, 90, Rule{}, time.Date(2026, 1, 5, 4, 0, 0, 0, time.UTC)); got != LevelCritical {
+		t.Errorf("Evaluate() after the window = %v, want Critical", got)
+	}
+}
+
+func TestSetConfigRejectsInvalidMaintenance(t *testing.T) {
+	withMaintenance(t, Config{MaintenanceWindows: []string{}, MaintenanceMode: ModeLog})
+	for _, config := range []Config{
+		{MaintenanceMode: "ignore"},
+		{MaintenanceMode: ModeSuppress, MaintenanceWindows: []string{"whenever"}},
+	} {
+		if err := SetConfig(config); err == nil {
+			t.Errorf("SetConfig(%+v) error = nil, want one", config)
+		}
+	}
+}
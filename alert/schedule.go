@@ -0,0 +1,81 @@
+package alert
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// scheduleWindows caches parsed schedule windows by spec, since rules are evaluated every refresh
+var scheduleWindows = struct {
+	parsed map[string]*Window // nil for specs that do not parse
+	mutex  sync.Mutex
+}{parsed: make(map[string]*Window)}
+
+// ActiveSchedule returns the first of the rule's schedules whose window is active at now
+// Schedules with an invalid window never apply; config validation reports them
+func (rule Rule) ActiveSchedule(now time.Time) (Schedule, bool) {
+	for _, schedule := range rule.Schedules {
+		window := scheduleWindow(schedule.Window)
+		if window == nil {
+			continue
+		}
+		if _, active := window.ActiveUntil(now); active {
+			return schedule, true
+		}
+	}
+	return Schedule{}, false
+}
+
+// Thresholds returns the warning and critical thresholds in effect at now
+// An active schedule replaces the thresholds it sets; the others keep the monitor's values
+func (rule Rule) Thresholds(warning, critical float64, now time.Time) (float64, float64) {
+	schedule, active := rule.ActiveSchedule(now)
+	if !active {
+		return warning, critical
+	}
+	if schedule.Warning > 0 {
+		warning = schedule.Warning
+	}
+	if schedule.Critical > 0 {
+		critical = schedule.Critical
+	}
+	return warning, critical
+}
+
+// CloneRules returns a copy of rules that shares no schedules with them, for monitor configurations that
+// are copied instead of changed in place
+func CloneRules(rules map[string]Rule) map[string]Rule {
+	if rules == nil {
+		return nil
+	}
+	clone := make(map[string]Rule, len(rules))
+	for key, rule := range rules {
+		rule.Schedules = slices.Clone(rule.Schedules)
+		clone[key] = rule
+	}
+	return clone
+}
+
+// Name returns the window's name, or its spec when it has none
+func (schedule Schedule) Name() string {
+	if window := scheduleWindow(schedule.Window); window != nil && window.Name != "" {
+		return window.Name
+	}
+	return schedule.Window
+}
+
+// scheduleWindow returns the parsed window of a schedule, or nil when it does not parse
+func scheduleWindow(spec string) *Window {
+	scheduleWindows.mutex.Lock()
+	defer scheduleWindows.mutex.Unlock()
+
+	window, known := scheduleWindows.parsed[spec]
+	if !known {
+		if parsed, err := ParseWindow(spec); err == nil {
+			window = &parsed
+		}
+		scheduleWindows.parsed[spec] = window
+	}
+	return window
+}
@@ -0,0 +1,53 @@
+package alert
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRuleThresholds(t *testing.T) {
+	rule := Rule{Schedules: []Schedule{
+		{Window: "0 22 * * * 8h backups", Warning: 95},
+		{Window: "0 12 * * 1-5 1h lunch", Warning: 70, Critical: 85},
+		{Window: "not a window", Warning: 1, Critical: 2},
+	}}
+
+	tests := []struct {
+		name         string
+		now          time.Time
+		wantWarning  float64
+		wantCritical float64
+		wantWindow   string
+	}{
+		{name: "outside every window", now: time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC), wantWarning: 80, wantCritical: 90},
+		{name: "backup window keeps critical", now: time.Date(2026, 1, 5, 23, 30, 0, 0, time.UTC), wantWarning: 95, wantCritical: 90, wantWindow: "backups"},
+		{name: "backup window past midnight", now: time.Date(2026, 1, 6, 5, 59, 0, 0, time.UTC), wantWarning: 95, wantCritical: 90, wantWindow: "backups"},
+		{name: "backup window ended", now: time.Date(2026, 1, 6, 6, 0, 0, 0, time.UTC), wantWarning: 80, wantCritical: 90},
+		{name: "weekday lunch", now: time.Date(2026, 1, 5, 12, 15, 0, 0, time.UTC), wantWarning: 70, wantCritical: 85, wantWindow: "lunch"},
+		{name: "no lunch window on Sunday", now: time.Date(2026, 1, 4, 12, 15, 0, 0, time.UTC), wantWarning: 80, wantCritical: 90},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warning, critical := rule.Thresholds(80, 90, tt.now)
+			if warning != tt.wantWarning || critical != tt.wantCritical {
+				t.Errorf("Thresholds() = %g, %g, want %g, %g", warning, critical, tt.wantWarning, tt.wantCritical)
+			}
+			schedule, active := rule.ActiveSchedule(tt.now)
+			if name := schedule.Name(); active != (tt.wantWindow != "") || active && name != tt.wantWindow {
+				t.Errorf("ActiveSchedule() = %q (active %v), want %q", name, active, tt.wantWindow)
+			}
+		})
+	}
+}
+
+func TestCloneRules(t *testing.T) {
+	rules := map[string]Rule{"usage": {Schedules: []Schedule{{Window: "0 22 * * * 8h", Warning: 95}}}}
+	clone := CloneRules(rules)
+	clone["usage"].Schedules[0].Warning = 50
+	if rules["usage"].Schedules[0].Warning != 95 {
+		t.Errorf("changing the clone changed the original schedule: %+v", rules["usage"].Schedules[0])
+	}
+	if CloneRules(nil) != nil {
+		t.Error("CloneRules(nil) != nil")
+	}
+}
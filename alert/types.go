@@ -0,0 +1,103 @@
+package alert
+
+import (
+	"sync"
+	"time"
+)
+
+// Level is the severity of a threshold alert, ordered from least to most severe
+type Level int
+
+// Alert levels
+const (
+	LevelNormal   Level = iota // Below the warning threshold
+	LevelWarning               // At or above the warning threshold
+	LevelCritical              // At or above the critical threshold
+)
+
+// Rule controls when one alert raises and clears
+// The zero value raises and clears the moment a threshold is crossed, as plain threshold checks do
+type Rule struct {
+	Hysteresis float64       `json:"hysteresis"`  // How far below a threshold the value must fall before the alert clears (same unit as the threshold)
+	RaiseAfter time.Duration `json:"raise_after"` // How long the value must stay at or above a threshold before the alert raises
+	ClearAfter time.Duration `json:"clear_after"` // How long the value must stay below the clear point before the alert clears
+	Note       string        `json:"note"`        // Runbook link or advice shown while the alert is raised
+	Schedules  []Schedule    `json:"schedules"`   // Thresholds used instead of the monitor's during recurring windows; the first active one applies
+}
+
+// Schedule replaces an alert's thresholds during a recurring window, e.g. a higher CPU warning during nightly backups
+type Schedule struct {
+	Window   string  `json:"window"`   // Cron-like window as in maintenance_windows: "0 22 * * * 8h backups"
+	Warning  float64 `json:"warning"`  // Warning threshold while the window is active (0 keeps the monitor's)
+	Critical float64 `json:"critical"` // Critical threshold while the window is active (0 keeps the monitor's)
+}
+
+// Note is the runbook note of one raised alert
+type Note struct {
+	Key   string `json:"key"`   // Alert key, e.g. "memory" or "disk_temperature:sda"
+	Level string `json:"level"` // Level the alert is reported at: Warning or Critical
+	Text  string `json:"text"`  // The note from the alert's rule
+}
+
+// Tracker remembers the reported level of each alert between collections
+// Alerts are identified by key, e.g. "memory" or "disk_temperature:sda" for per-device alerts
+type Tracker struct {
+	monitor string // Monitor the alerts belong to, used when logging suppressed alerts
+	states  map[string]*state
+	mutex   sync.Mutex
+}
+
+// state is the tracked level of one alert
+type state struct {
+	level        Level     // Level currently reported
+	pending      Level     // Level the value is moving towards
+	pendingSince time.Time // When the value first reached the pending level
+	seen         time.Time // When the alert was last evaluated
+	reported     Level     // Level last returned by Evaluate, after maintenance suppression
+	note         string    // Note of the rule the alert was last evaluated with
+}
+
+// Maintenance modes
+const (
+	ModeSuppress = "suppress" // Alerts raised during maintenance are hidden
+	ModeLog      = "log"      // Alerts raised during maintenance are hidden and written to LogPath
+)
+
+// Config holds the maintenance windows shared by every monitor
+type Config struct {
+	MaintenanceWindows []string `json:"maintenance_windows"` // Cron-like windows: "<minute> <hour> <day> <month> <weekday> <duration> [name]"
+	MaintenanceMode    string   `json:"maintenance_mode"`    // What happens to alerts during maintenance: suppress or log
+}
+
+// Window is a parsed maintenance window: it starts whenever the cron fields match and lasts Duration
+type Window struct {
+	Spec     string        // The window as written in the config
+	Name     string        // Optional name shown while the window is active
+	Duration time.Duration // How long the window lasts after each start
+
+	minutes, hours, days, months, weekdays []bool // Allowed values of each cron field
+	anyDay, anyWeekday                     bool   // Whether the day of month / weekday field is "*"
+}
+
+// maintenanceState holds the windows and the ad-hoc snooze
+type maintenanceState struct {
+	config      Config
+	windows     []Window
+	snoozeUntil time.Time
+	period      string          // Active period the logged alerts belong to
+	logged      map[string]bool // Alerts already written to the log during period
+	mutex       sync.Mutex
+}
+
+// announcedState holds the last announcement of every raised alert, keyed by monitor/key
+type announcedState struct {
+	alerts map[string]announcement
+	mutex  sync.Mutex
+}
+
+// announcement is the level last announced to hooks for a raised alert and the tracker it belongs to
+type announcement struct {
+	level   Level
+	tracker *Tracker  // Tracker that announced it; others leave the alert alone while it is evaluated
+	seen    time.Time // When tracker last evaluated the alert
+}
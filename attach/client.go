@@ -0,0 +1,71 @@
+package attach
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/ahmadreza-log/simple-monitor/recording"
+)
+
+// defaultClientTimeout limits one request; the daemon may have to scan every process before answering
+const defaultClientTimeout = 20 * time.Second
+
+// Dial connects to the daemon listening at path and checks it speaks this protocol version
+func Dial(path string) (*Client, error) {
+	client := &Client{Path: path, Timeout: defaultClientTimeout}
+	response, err := client.call(Request{Op: OpHello})
+	if err != nil {
+		return nil, err
+	}
+	if response.Hello == nil || response.Hello.Version != ProtocolVersion {
+		version := 0
+		if response.Hello != nil {
+			version = response.Hello.Version
+		}
+		return nil, fmt.Errorf("daemon at %s speaks protocol %d, expected %d", path, version, ProtocolVersion)
+	}
+	client.info = *response.Hello
+	return client, nil
+}
+
+// Info returns what the daemon reported when the client connected
+func (client *Client) Info() Hello {
+	return client.info
+}
+
+// Collect returns the latest data of a monitor from the daemon
+func (client *Client) Collect(monitor string) (recording.Frame, error) {
+	response, err := client.call(Request{Op: OpCollect, Monitor: monitor})
+	if err != nil {
+		return recording.Frame{}, err
+	}
+	if response.Frame == nil {
+		return recording.Frame{}, fmt.Errorf("daemon sent no %s data", monitor)
+	}
+	return *response.Frame, nil
+}
+
+// call sends one request and reads the response
+func (client *Client) call(request Request) (Response, error) {
+	var response Response
+	conn, err := net.DialTimeout("unix", client.Path, client.Timeout)
+	if err != nil {
+		return response, fmt.Errorf("failed to reach daemon at %s: %w", client.Path, err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(client.Timeout))
+
+	if err := json.NewEncoder(conn).Encode(request); err != nil {
+		return response, fmt.Errorf("failed to send %s request to daemon: %w", request.Op, err)
+	}
+	if err := json.NewDecoder(conn).Decode(&response); err != nil {
+		return response, fmt.Errorf("failed to read daemon response: %w", err)
+	}
+	if response.Error != "" {
+		return response, errors.New("daemon: " + response.Error)
+	}
+	return response, nil
+}
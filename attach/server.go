@@ -0,0 +1,158 @@
+// Package attach lets interactive sessions attach to a running daemon over a local socket, gops-style:
+// the daemon collects on its own managers and serves the data, so an attached session shows its history
+// and alerts instead of starting a second collection
+package attach
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ahmadreza-log/simple-monitor/recording"
+)
+
+// maxRequestSize caps one request so a client cannot make the daemon buffer without limit
+const maxRequestSize = 4 * 1024
+
+// serverTimeout limits one connection, including a collection that may scan every process
+const serverTimeout = 30 * time.Second
+
+// SocketPath returns where the daemon using the logs directory dir listens
+func SocketPath(dir string) string {
+	return filepath.Join(dir, SocketFile)
+}
+
+// Listen starts serving on a Unix socket at path until Close
+// Only the daemon holding the instance lock listens, so a socket file already there was left behind by one
+// that did not stop cleanly and is replaced. The socket is only accessible to its owner
+func (server *Server) Listen(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove stale socket %s: %w", path, err)
+	}
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", path, err)
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		listener.Close()
+		return fmt.Errorf("failed to restrict %s: %w", path, err)
+	}
+
+	server.mutex.Lock()
+	server.listener = listener
+	server.started = time.Now()
+	server.frames = make(map[string]*servedFrame)
+	server.mutex.Unlock()
+	go server.serve(listener)
+	return nil
+}
+
+// Close stops serving and removes the socket
+func (server *Server) Close() error {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	if server.listener == nil {
+		return nil
+	}
+	err := server.listener.Close()
+	server.listener = nil
+	return err
+}
+
+// serve answers connections until the listener is closed
+func (server *Server) serve(listener net.Listener) {
+	for {
+		conn, err := listener.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return
+		}
+		if err != nil {
+			continue
+		}
+		go server.serveConn(conn)
+	}
+}
+
+// serveConn reads one request from a connection and writes the response
+func (server *Server) serveConn(conn net.Conn) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(serverTimeout))
+
+	var request Request
+	if err := json.NewDecoder(io.LimitReader(conn, maxRequestSize)).Decode(&request); err != nil {
+		json.NewEncoder(conn).Encode(Response{Error: fmt.Sprintf("invalid request: %v", err)})
+		return
+	}
+	json.NewEncoder(conn).Encode(server.Handle(request))
+}
+
+// Handle answers one request
+func (server *Server) Handle(request Request) Response {
+	switch request.Op {
+	case OpHello:
+		return Response{Hello: server.hello()}
+	case OpCollect:
+		frame, err := server.frame(request.Monitor)
+		if err != nil {
+			return Response{Error: err.Error()}
+		}
+		return Response{Frame: &frame}
+	default:
+		return Response{Error: fmt.Sprintf("unknown operation %q", request.Op)}
+	}
+}
+
+// hello describes this daemon
+func (server *Server) hello() *Hello {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	hello := &Hello{Version: ProtocolVersion, PID: os.Getpid(), Started: server.started, Monitors: []string{}}
+	for _, monitor := range server.Monitors {
+		hello.Monitors = append(hello.Monitors, monitor.Name)
+	}
+	return hello
+}
+
+// frame returns the latest data of a monitor, collecting it first when it is older than the refresh interval
+// However many sessions are attached, each monitor is collected at most once per interval
+func (server *Server) frame(name string) (recording.Frame, error) {
+	var source *Monitor
+	for i := range server.Monitors {
+		if server.Monitors[i].Name == name {
+			source = &server.Monitors[i]
+		}
+	}
+	if source == nil {
+		return recording.Frame{}, fmt.Errorf("monitor %q is not served", name)
+	}
+
+	server.mutex.Lock()
+	served, ok := server.frames[name]
+	if !ok {
+		served = &servedFrame{}
+		server.frames[name] = served
+	}
+	server.mutex.Unlock()
+
+	served.mutex.Lock()
+	defer served.mutex.Unlock()
+	if !served.frame.Time.IsZero() && time.Since(served.frame.Time) < source.Interval() {
+		return served.frame, nil
+	}
+
+	data, err := source.Collect()
+	if err != nil {
+		return recording.Frame{}, fmt.Errorf("failed to collect %s: %w", name, err)
+	}
+	content, err := json.Marshal(data)
+	if err != nil {
+		return recording.Frame{}, fmt.Errorf("failed to encode %s data: %w", name, err)
+	}
+	served.frame = recording.Frame{Time: time.Now(), Monitor: name, Data: content}
+	return served.frame, nil
+}
@@ -0,0 +1,73 @@
+package attach
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/ahmadreza-log/simple-monitor/recording"
+)
+
+// SocketFile is the name of the daemon's socket inside the logs directory, next to the instance lock
+const SocketFile = "simple-monitor.sock"
+
+// ProtocolVersion is bumped when requests or responses change incompatibly
+const ProtocolVersion = 1
+
+// Operations the daemon answers
+const (
+	OpHello   = "hello"   // Describe the daemon and the monitors it serves
+	OpCollect = "collect" // Return the latest data of one monitor, collecting it when due
+)
+
+// Request is one query sent to the daemon, one JSON object per line
+type Request struct {
+	Op      string `json:"op"`                // One of the Op constants
+	Monitor string `json:"monitor,omitempty"` // Monitor to collect (cpu, memory, disk, network, process or events)
+}
+
+// Response is the daemon's answer to one request
+type Response struct {
+	Error string           `json:"error,omitempty"` // Why the request failed
+	Hello *Hello           `json:"hello,omitempty"` // Daemon description
+	Frame *recording.Frame `json:"frame,omitempty"` // Collected monitor data, as recorded in live sessions
+}
+
+// Hello describes a running daemon
+type Hello struct {
+	Version  int       `json:"version"`  // ProtocolVersion of the daemon
+	PID      int       `json:"pid"`      // Daemon process ID
+	Started  time.Time `json:"started"`  // When the daemon started serving
+	Monitors []string  `json:"monitors"` // Monitors it serves, in key order
+}
+
+// Monitor is one monitor the daemon serves
+type Monitor struct {
+	Name     string                      // Monitor name used in requests and frames
+	Collect  func() (interface{}, error) // Collects the monitor once on the daemon's manager
+	Interval func() time.Duration        // Refresh interval; data younger than this is served again instead of collecting
+}
+
+// Server serves the daemon's monitors to attached sessions
+type Server struct {
+	Monitors []Monitor // Monitors in key order
+
+	listener net.Listener
+	started  time.Time
+	frames   map[string]*servedFrame
+	mutex    sync.Mutex
+}
+
+// servedFrame is the latest data of one monitor; its mutex makes sessions asking together wait for one collection
+type servedFrame struct {
+	frame recording.Frame
+	mutex sync.Mutex
+}
+
+// Client queries a daemon over its socket, opening one connection per request
+type Client struct {
+	Path    string        // Socket path
+	Timeout time.Duration // Limit for one request, including the collection it waits for
+
+	info Hello
+}
@@ -0,0 +1,123 @@
+// Package benchmark times each monitor's collector and writes CPU and heap profiles of the runs
+package benchmark
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"sort"
+	"time"
+)
+
+// DefaultRuns is the number of collections per monitor when none is given
+const DefaultRuns = 10
+
+// Run collects target runs times and reports durations and allocations
+// Allocation counts come from runtime.MemStats, so goroutines running in the background
+// (e.g. the terminal title updater) add a little noise to them
+func Run(target Target, runs int) Result {
+	if runs <= 0 {
+		runs = DefaultRuns
+	}
+	result := Result{Name: target.Name, Runs: runs}
+	durations := make([]time.Duration, 0, runs)
+
+	// Start from a clean heap so earlier garbage is not attributed to this collector
+	runtime.GC()
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+
+	var total time.Duration
+	for i := 0; i < runs; i++ {
+		start := time.Now()
+		err := target.Collect()
+		duration := time.Since(start)
+
+		if err != nil {
+			result.Failures++
+			result.LastError = err.Error()
+		}
+		durations = append(durations, duration)
+		total += duration
+	}
+
+	runtime.ReadMemStats(&after)
+	result.AllocsPerRun = (after.Mallocs - before.Mallocs) / uint64(runs)
+	result.BytesPerRun = (after.TotalAlloc - before.TotalAlloc) / uint64(runs)
+
+	sort.Slice(durations, func(i, j int) bool {
+		return durations[i] < durations[j]
+	})
+	result.Mean = total / time.Duration(runs)
+	result.Median = median(durations)
+	result.Min = durations[0]
+	result.Max = durations[len(durations)-1]
+	return result
+}
+
+// RunAll benchmarks each target in turn
+func RunAll(targets []Target, runs int) []Result {
+	results := make([]Result, 0, len(targets))
+	for _, target := range targets {
+		results = append(results, Run(target, runs))
+	}
+	return results
+}
+
+// RunWithProfiles benchmarks the targets while recording a CPU profile, then writes a heap profile
+// Both files go to dir with a timestamp so they can be attached to performance issues
+func RunWithProfiles(targets []Target, runs int, dir string) ([]Result, Profiles, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, Profiles{}, fmt.Errorf("failed to create profile directory: %w", err)
+	}
+
+	timestamp := time.Now().Format("2006-01-02_15-04-05")
+	profiles := Profiles{
+		CPU:  filepath.Join(dir, fmt.Sprintf("cpu_%s.pprof", timestamp)),
+		Heap: filepath.Join(dir, fmt.Sprintf("heap_%s.pprof", timestamp)),
+	}
+
+	cpuFile, err := os.Create(profiles.CPU)
+	if err != nil {
+		return nil, Profiles{}, fmt.Errorf("failed to create CPU profile: %w", err)
+	}
+	defer cpuFile.Close()
+
+	if err := pprof.StartCPUProfile(cpuFile); err != nil {
+		return nil, Profiles{}, fmt.Errorf("failed to start CPU profile: %w", err)
+	}
+	results := RunAll(targets, runs)
+	pprof.StopCPUProfile()
+
+	if err := writeHeapProfile(profiles.Heap); err != nil {
+		return results, Profiles{CPU: profiles.CPU}, err
+	}
+	return results, profiles, nil
+}
+
+// writeHeapProfile writes the live heap to path
+func writeHeapProfile(path string) error {
+	heapFile, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create heap profile: %w", err)
+	}
+	defer heapFile.Close()
+
+	// Collect garbage first so the profile shows what is actually retained
+	runtime.GC()
+	if err := pprof.WriteHeapProfile(heapFile); err != nil {
+		return fmt.Errorf("failed to write heap profile: %w", err)
+	}
+	return nil
+}
+
+// median returns the middle value of sorted durations
+func median(sorted []time.Duration) time.Duration {
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
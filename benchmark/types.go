@@ -0,0 +1,29 @@
+package benchmark
+
+import "time"
+
+// Target is one collector to benchmark
+type Target struct {
+	Name    string       // Monitor name shown in the report
+	Collect func() error // Performs one full collection
+}
+
+// Result summarizes repeated runs of one collector
+type Result struct {
+	Name         string        `json:"name"`                 // Monitor name
+	Runs         int           `json:"runs"`                 // Number of collections performed
+	Failures     int           `json:"failures"`             // Collections that returned an error
+	Mean         time.Duration `json:"mean"`                 // Average collection duration
+	Median       time.Duration `json:"median"`               // Median collection duration
+	Min          time.Duration `json:"min"`                  // Fastest collection
+	Max          time.Duration `json:"max"`                  // Slowest collection
+	AllocsPerRun uint64        `json:"allocs_per_run"`       // Heap allocations per collection
+	BytesPerRun  uint64        `json:"bytes_per_run"`        // Heap bytes allocated per collection
+	LastError    string        `json:"last_error,omitempty"` // Most recent collection error
+}
+
+// Profiles holds the paths of profiles written during a benchmark
+type Profiles struct {
+	CPU  string `json:"cpu"`  // CPU profile covering all runs
+	Heap string `json:"heap"` // Heap profile taken after the runs
+}
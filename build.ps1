@@ -25,6 +25,10 @@ foreach ($os in $OSES) {
         $env:GOOS = $os
         $env:GOARCH = $arch
         go build -o "$OutputDir\$AppName-$os-$arch$ext" main.go
+        # The privileged helper is for Unix-like systems only
+        if ($os -ne "windows") {
+            go build -o "$OutputDir\$AppName-helper-$os-$arch" ./cmd/simple-monitor-helper
+        }
     }
 }
 
@@ -0,0 +1,104 @@
+// simple-monitor-helper answers the few queries simple-monitor cannot make without privileges:
+// SMART data through smartctl, ICMP echo over a raw socket and other users' process details
+// It is installed setuid root, with capabilities, or run as a root service, and listens on a local socket
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/ahmadreza-log/simple-monitor/privhelper"
+	"io"
+	"io/fs"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"syscall"
+)
+
+// safePath replaces PATH when the helper runs setuid, so the invoking user cannot choose which smartctl runs as root
+const safePath = "/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"
+
+func main() {
+	socket := flag.String("socket", privhelper.SocketPath(), "socket to listen on")
+	stdin := flag.Bool("stdin", false, "exit when standard input closes (set when simple-monitor starts the helper)")
+	allowSelfTest := flag.Bool("allow-self-test", false, "allow starting SMART self-tests")
+	mode := flag.String("mode", "0600", "socket permissions; 0666 lets every local user query a helper service")
+	flag.Parse()
+
+	if err := run(*socket, *stdin, *allowSelfTest, *mode); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", privhelper.BinaryName, err)
+		os.Exit(1)
+	}
+}
+
+// run listens on socket and serves requests until stopped
+func run(socket string, stdin, allowSelfTest bool, mode string) error {
+	permissions, err := strconv.ParseUint(mode, 8, 32)
+	if err != nil || permissions > 0777 {
+		return fmt.Errorf("invalid socket mode %q", mode)
+	}
+
+	// Started by an ordinary user through the setuid bit: only serve that user, from a directory they own
+	// Flags come from that user too, so self-tests, which need a root service's explicit opt-in, stay off
+	setuid := os.Geteuid() != os.Getuid()
+	if setuid {
+		os.Clearenv()
+		os.Setenv("PATH", safePath)
+		if err := checkSocketDir(filepath.Dir(socket), os.Getuid()); err != nil {
+			return err
+		}
+		permissions = 0600
+		allowSelfTest = false
+	} else if err := os.MkdirAll(filepath.Dir(socket), 0755); err != nil {
+		return fmt.Errorf("failed to create socket directory: %w", err)
+	}
+
+	if err := removeStaleSocket(socket); err != nil {
+		return err
+	}
+	listener, err := listenSocket(socket, os.FileMode(permissions), setuid)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(socket)
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigChan
+		listener.Close()
+	}()
+	if stdin {
+		// simple-monitor holds the other end; it closes when simple-monitor exits, however it exits
+		go func() {
+			io.Copy(io.Discard, os.Stdin)
+			listener.Close()
+		}()
+	}
+
+	server := &privhelper.Server{AllowSelfTest: allowSelfTest}
+	return server.Serve(listener)
+}
+
+// removeStaleSocket removes a socket left behind by a helper that did not shut down cleanly
+// Anything other than a socket is left alone, so a mistyped -socket cannot delete a file
+func removeStaleSocket(socket string) error {
+	info, err := os.Lstat(socket)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check %s: %w", socket, err)
+	}
+	if info.Mode()&fs.ModeSocket == 0 {
+		return fmt.Errorf("%s exists and is not a socket", socket)
+	}
+	if conn, err := net.Dial("unix", socket); err == nil {
+		conn.Close()
+		return fmt.Errorf("another helper is already listening on %s", socket)
+	}
+	return os.Remove(socket)
+}
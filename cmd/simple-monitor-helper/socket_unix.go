@@ -0,0 +1,62 @@
+//go:build !windows
+
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+)
+
+// checkSocketDir makes sure a setuid helper only creates its socket in a real directory owned by the invoking user
+// and not writable by others, so it cannot be used to place a root-created file somewhere else
+func checkSocketDir(dir string, uid int) error {
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return fmt.Errorf("failed to check socket directory: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("socket directory %s is not a directory", dir)
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok || int(stat.Uid) != uid {
+		return fmt.Errorf("socket directory %s is not owned by the invoking user", dir)
+	}
+	if info.Mode().Perm()&0022 != 0 {
+		return fmt.Errorf("socket directory %s is writable by other users", dir)
+	}
+	return nil
+}
+
+// listenSocket creates the socket with exactly permissions, so it is never changed by path afterwards, where
+// the path could have been swapped for a symlink to another file
+// A setuid helper creates it as the invoking user, who then owns it without a chown by root
+func listenSocket(socket string, permissions os.FileMode, setuid bool) (net.Listener, error) {
+	umask := syscall.Umask(int(0777 &^ permissions))
+	defer syscall.Umask(umask)
+
+	if !setuid {
+		listener, err := net.Listen("unix", socket)
+		if err != nil {
+			return nil, fmt.Errorf("failed to listen on %s: %w", socket, err)
+		}
+		return listener, nil
+	}
+
+	euid := os.Geteuid()
+	if err := syscall.Seteuid(os.Getuid()); err != nil {
+		return nil, fmt.Errorf("failed to switch to the invoking user: %w", err)
+	}
+	listener, err := net.Listen("unix", socket)
+	if restoreErr := syscall.Seteuid(euid); restoreErr != nil {
+		if listener != nil {
+			listener.Close()
+		}
+		return nil, fmt.Errorf("failed to regain privileges: %w", restoreErr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", socket, err)
+	}
+	return listener, nil
+}
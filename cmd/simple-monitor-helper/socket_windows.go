@@ -0,0 +1,24 @@
+//go:build windows
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+)
+
+// checkSocketDir is never reached on Windows, where the helper cannot be installed setuid
+func checkSocketDir(dir string, uid int) error {
+	return errors.New("setuid helpers are not supported on Windows")
+}
+
+// listenSocket listens on socket; Windows has no setuid helpers and unix socket permissions follow the directory
+func listenSocket(socket string, permissions os.FileMode, setuid bool) (net.Listener, error) {
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", socket, err)
+	}
+	return listener, nil
+}
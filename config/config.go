@@ -0,0 +1,353 @@
+// Package config loads, validates and migrates the simple-monitor config file
+// Each section is decoded into the config type of its monitor or service; unknown and malformed
+// settings are reported as issues rather than silently dropped
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/ahmadreza-log/simple-monitor/alert"
+	"github.com/ahmadreza-log/simple-monitor/cpumonitor"
+	"github.com/ahmadreza-log/simple-monitor/diskmonitor"
+	"github.com/ahmadreza-log/simple-monitor/eventmonitor"
+	"github.com/ahmadreza-log/simple-monitor/healthscore"
+	"github.com/ahmadreza-log/simple-monitor/hooks"
+	"github.com/ahmadreza-log/simple-monitor/memorymonitor"
+	"github.com/ahmadreza-log/simple-monitor/networkmonitor"
+	"github.com/ahmadreza-log/simple-monitor/processmonitor"
+	"github.com/ahmadreza-log/simple-monitor/quicktest"
+	"github.com/ahmadreza-log/simple-monitor/redact"
+	"github.com/ahmadreza-log/simple-monitor/retention"
+	"github.com/ahmadreza-log/simple-monitor/snapshot"
+	"github.com/ahmadreza-log/simple-monitor/stream"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// FileName is the name of the config file inside the user's config directory
+const FileName = "config.json"
+
+// LegacyPath is where the config file used to be read from: the working directory, which anyone who can
+// plant a file there controls; it is only mentioned so an existing file can be moved
+const LegacyPath = "simple-monitor.json"
+
+// PathEnv names the environment variable that overrides the config file location
+const PathEnv = "SIMPLE_MONITOR_CONFIG"
+
+// sectionOrder lists the sections in the order they are written: one per monitor, then the shared settings
+var sectionOrder = []string{"cpu", "memory", "disk", "network", "process", "events", "alerts", "retention", "snapshot", "quick_test", "score", "hooks", "stream", "redact"}
+
+// sectionTypes maps each section to the config it is decoded into
+var sectionTypes = map[string]reflect.Type{
+	"cpu":        reflect.TypeOf(cpumonitor.CPUMonitorConfig{}),
+	"memory":     reflect.TypeOf(memorymonitor.MemoryMonitorConfig{}),
+	"disk":       reflect.TypeOf(diskmonitor.DiskMonitorConfig{}),
+	"network":    reflect.TypeOf(networkmonitor.NetworkMonitorConfig{}),
+	"process":    reflect.TypeOf(processmonitor.ProcessMonitorConfig{}),
+	"events":     reflect.TypeOf(eventmonitor.EventMonitorConfig{}),
+	"alerts":     reflect.TypeOf(alert.Config{}),
+	"retention":  reflect.TypeOf(retention.Config{}),
+	"snapshot":   reflect.TypeOf(snapshot.PublishConfig{}),
+	"quick_test": reflect.TypeOf(quicktest.Config{}),
+	"score":      reflect.TypeOf(healthscore.Config{}),
+	"hooks":      reflect.TypeOf(hooks.Config{}),
+	"stream":     reflect.TypeOf(stream.Config{}),
+	"redact":     reflect.TypeOf(redact.Config{}),
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Path returns the config file location: $SIMPLE_MONITOR_CONFIG, or simple-monitor/config.json in the user's
+// config directory (~/.config on Linux, ~/Library/Application Support on macOS, %AppData% on Windows)
+func Path() string {
+	if path := os.Getenv(PathEnv); path != "" {
+		return path
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		// No home directory to put it in; Load still refuses a file another user could have written
+		return LegacyPath
+	}
+	return filepath.Join(dir, "simple-monitor", FileName)
+}
+
+// Load reads and parses a config file
+// A missing file is returned as an os.IsNotExist error so callers can fall back to defaults. The file can hold
+// commands simple-monitor runs, so one owned by another user or writable by group or others is refused
+func Load(path string) (*File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	owned, err := checkOwner(path, info)
+	if err != nil {
+		return nil, err
+	}
+	content, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := Parse(content)
+	if err != nil {
+		return nil, err
+	}
+	file.Path = path
+	file.commandsAllowed = owned
+	return file, nil
+}
+
+// Parse parses config file content without validating the settings
+func Parse(content []byte) (*File, error) {
+	var raw map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+	if raw == nil {
+		return nil, fmt.Errorf("failed to parse config file: top level must be a JSON object")
+	}
+
+	// Files without a numeric version predate versioning (debug info exports carry "version": "1.0")
+	file := &File{Version: 1, raw: raw}
+	if number, ok := raw["version"].(json.Number); ok {
+		version, err := number.Int64()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse config file: version must be a whole number")
+		}
+		file.Version = int(version)
+	}
+	return file, nil
+}
+
+// New returns an empty config file at the current schema version
+func New() *File {
+	return &File{Version: CurrentVersion, raw: map[string]interface{}{"version": CurrentVersion}, commandsAllowed: true}
+}
+
+// CommandsAllowed returns whether the hook and watchdog restart commands in the file may be run
+// They run as the current user, so they are only taken from a file that user owns; a root-owned file
+// read by another user still configures the monitors
+func (file *File) CommandsAllowed() bool {
+	return file.commandsAllowed
+}
+
+// Set stores one setting of a section, creating the section when the file has none
+// The value is encoded as JSON, so it is written the way a hand-edited file would hold it
+func (file *File) Set(name, key string, value interface{}) error {
+	if _, known := sectionTypes[name]; !known {
+		return fmt.Errorf("unknown config section: %s", name)
+	}
+	content, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s.%s: %w", name, key, err)
+	}
+	var raw interface{}
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	if err := decoder.Decode(&raw); err != nil {
+		return fmt.Errorf("failed to encode %s.%s: %w", name, key, err)
+	}
+
+	section, ok := file.raw[name].(map[string]interface{})
+	if !ok {
+		section = make(map[string]interface{})
+		file.raw[name] = section
+	}
+	section[key] = raw
+	return nil
+}
+
+// HasSection returns whether the file configures the given monitor
+func (file *File) HasSection(name string) bool {
+	_, ok := file.raw[name].(map[string]interface{})
+	return ok
+}
+
+// Apply decodes one monitor section over target, a pointer to that monitor's config
+// Settings missing from the file keep their current values; the file must be at the current version
+func (file *File) Apply(name string, target interface{}) error {
+	if file.Version != CurrentVersion {
+		return fmt.Errorf("config file uses schema version %d, expected %d", file.Version, CurrentVersion)
+	}
+
+	section, ok := file.raw[name].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	sectionType, known := sectionTypes[name]
+	if !known {
+		return fmt.Errorf("unknown config section: %s", name)
+	}
+
+	// Duration strings are converted to the nanoseconds the config structs decode from
+	normalized := make(map[string]interface{}, len(section))
+	fields := jsonFields(sectionType)
+	for key, value := range section {
+		fieldType, known := fields[key]
+		if !known {
+			continue
+		}
+		value, err := normalizeValue(value, fieldType)
+		if err != nil {
+			return fmt.Errorf("invalid duration for %s.%s: %w", name, key, err)
+		}
+		normalized[key] = value
+	}
+
+	content, err := json.Marshal(normalized)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s settings: %w", name, err)
+	}
+	if err := json.Unmarshal(content, target); err != nil {
+		return fmt.Errorf("failed to apply %s settings: %w", name, err)
+	}
+	return nil
+}
+
+// Save writes the file with the version first and sections in monitor order
+// The previous file is kept next to it with a .bak suffix. Both are readable by the owner only, since the
+// file can hold stream API tokens and password hashes
+func (file *File) Save(path string) error {
+	var content strings.Builder
+	content.WriteString("{\n")
+
+	keys := []string{"version"}
+	for _, name := range sectionOrder {
+		if _, ok := file.raw[name]; ok {
+			keys = append(keys, name)
+		}
+	}
+	for i, key := range keys {
+		value, err := json.MarshalIndent(file.raw[key], "  ", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode %s: %w", key, err)
+		}
+		separator := ","
+		if i == len(keys)-1 {
+			separator = ""
+		}
+		fmt.Fprintf(&content, "  %q: %s%s\n", key, value, separator)
+	}
+	content.WriteString("}\n")
+
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return fmt.Errorf("failed to create config directory: %w", err)
+		}
+	}
+	if previous, err := os.ReadFile(path); err == nil {
+		if err := writePrivate(path+".bak", previous); err != nil {
+			return fmt.Errorf("failed to back up config file: %w", err)
+		}
+	}
+	if err := writePrivate(path, []byte(content.String())); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
+
+// writePrivate replaces path with content readable by the owner only
+// The content goes to a new file that is renamed over path, so an existing file's wider mode is not kept
+func writePrivate(path string, content []byte) error {
+	temp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+	if _, err := temp.Write(content); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(temp.Name(), path)
+}
+
+// normalizeValue converts duration strings to nanoseconds, including those inside
+// objects of named settings groups such as alert_rules and lists of settings such as hook commands
+func normalizeValue(value interface{}, fieldType reflect.Type) (interface{}, error) {
+	if text, isString := value.(string); isString && fieldType == durationType {
+		duration, err := time.ParseDuration(text)
+		if err != nil {
+			return nil, err
+		}
+		return int64(duration), nil
+	}
+
+	if items, isList := value.([]interface{}); isList && fieldType.Kind() == reflect.Slice && fieldType.Elem().Kind() == reflect.Struct {
+		fields := jsonFields(fieldType.Elem())
+		normalized := make([]interface{}, len(items))
+		for i, item := range items {
+			settings, isObject := item.(map[string]interface{})
+			if !isObject {
+				normalized[i] = item
+				continue
+			}
+			converted := make(map[string]interface{}, len(settings))
+			for key, setting := range settings {
+				if settingType, known := fields[key]; known {
+					var err error
+					if setting, err = normalizeValue(setting, settingType); err != nil {
+						return nil, fmt.Errorf("item %d: %s: %w", i+1, key, err)
+					}
+				}
+				converted[key] = setting
+			}
+			normalized[i] = converted
+		}
+		return normalized, nil
+	}
+
+	entries, isObject := value.(map[string]interface{})
+	if !isObject || fieldType.Kind() != reflect.Map || fieldType.Elem().Kind() != reflect.Struct {
+		return value, nil
+	}
+	fields := jsonFields(fieldType.Elem())
+	normalized := make(map[string]interface{}, len(entries))
+	for name, entry := range entries {
+		settings, isObject := entry.(map[string]interface{})
+		if !isObject {
+			normalized[name] = entry
+			continue
+		}
+		converted := make(map[string]interface{}, len(settings))
+		for key, setting := range settings {
+			if settingType, known := fields[key]; known {
+				var err error
+				if setting, err = normalizeValue(setting, settingType); err != nil {
+					return nil, fmt.Errorf("%s.%s: %w", name, key, err)
+				}
+			}
+			converted[key] = setting
+		}
+		normalized[name] = converted
+	}
+	return normalized, nil
+}
+
+// jsonFields maps the JSON names of a config struct's fields to their types
+func jsonFields(structType reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type, structType.NumField())
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		fields[name] = field.Type
+	}
+	return fields
+}
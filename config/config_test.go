@@ -0,0 +1,235 @@
+package config
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ahmadreza-log/simple-monitor/cpumonitor"
+	"github.com/ahmadreza-log/simple-monitor/processmonitor"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		content     string
+		wantVersion int
+		wantErr     bool
+	}{
+		{content: `{"version": 3, "cpu": {}}`, wantVersion: 3},
+		{content: `{"cpu": {}}`, wantVersion: 1},
+		{content: `{"version": "1.0", "config": {}}`, wantVersion: 1},
+		{content: `{"version": 2.5}`, wantErr: true},
+		{content: `[]`, wantErr: true},
+		{content: `null`, wantErr: true},
+		{content: `{"cpu": `, wantErr: true},
+	}
+	for _, tt := range tests {
+		file, err := Parse([]byte(tt.content))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Parse(%s) error = %v, want error %v", tt.content, err, tt.wantErr)
+			continue
+		}
+		if err == nil && file.Version != tt.wantVersion {
+			t.Errorf("Parse(%s) version = %d, want %d", tt.content, file.Version, tt.wantVersion)
+		}
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	tests := []struct {
+		name            string
+		content         string
+		wantChanges     []string
+		wantInterval    time.Duration
+		wantUserFilter  []string
+		wantErr         bool
+		wantNoSectionOf string // Section the migration must have removed
+	}{
+		{
+			name:    "version 1",
+			content: `{"cpu": {"refresh_interval": 2000000000, "usage_warning": 70}, "process": {"user_filter": "alice"}}`,
+			wantChanges: []string{
+				`cpu.refresh_interval: 2000000000 ns -> "2s"`,
+				"set version to 2",
+				`process.user_filter: "alice" -> ["alice"]`,
+				"set version to 3",
+			},
+			wantInterval:   2 * time.Second,
+			wantUserFilter: []string{"alice"},
+		},
+		{
+			name:    "debug info export",
+			content: `{"version": "1.0", "config": {"cpu": {"refresh_interval": 500000000}, "system": {"os": "linux"}}}`,
+			wantChanges: []string{
+				`moved settings out of the debug info "config" object`,
+				`cpu.refresh_interval: 500000000 ns -> "500ms"`,
+				"removed system (not a monitor section)",
+				"set version to 2",
+				"set version to 3",
+			},
+			wantInterval:    500 * time.Millisecond,
+			wantNoSectionOf: "system",
+		},
+		{
+			name:           "version 2",
+			content:        `{"version": 2, "cpu": {"refresh_interval": "1s"}, "process": {"user_filter": ""}}`,
+			wantChanges:    []string{`process.user_filter: "" -> []`, "set version to 3"},
+			wantInterval:   time.Second,
+			wantUserFilter: []string{},
+		},
+		{
+			name:         "current",
+			content:      `{"version": 3, "cpu": {"refresh_interval": "1s"}}`,
+			wantInterval: time.Second,
+		},
+		{
+			name:    "newer",
+			content: `{"version": 4}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		file, err := Parse([]byte(tt.content))
+		if err != nil {
+			t.Fatalf("%s: Parse() error = %v", tt.name, err)
+		}
+		changes, err := file.Migrate()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: Migrate() error = %v, want error %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if !slices.Equal(changes, tt.wantChanges) {
+			t.Errorf("%s: Migrate() changes = %q, want %q", tt.name, changes, tt.wantChanges)
+		}
+		if file.Version != CurrentVersion || HasErrors(file.Validate()) {
+			t.Errorf("%s: Migrate() left version %d with issues %v", tt.name, file.Version, file.Validate())
+		}
+		if tt.wantNoSectionOf != "" && file.raw[tt.wantNoSectionOf] != nil {
+			t.Errorf("%s: Migrate() kept %s", tt.name, tt.wantNoSectionOf)
+		}
+
+		var cpu cpumonitor.CPUMonitorConfig
+		if err := file.Apply("cpu", &cpu); err != nil || cpu.RefreshInterval != tt.wantInterval {
+			t.Errorf("%s: Apply(cpu) = %v, %v, want refresh interval %v", tt.name, cpu.RefreshInterval, err, tt.wantInterval)
+		}
+		var process processmonitor.ProcessMonitorConfig
+		if err := file.Apply("process", &process); err != nil || !slices.Equal(process.UserFilter, tt.wantUserFilter) {
+			t.Errorf("%s: Apply(process) = %q, %v, want user filter %q", tt.name, process.UserFilter, err, tt.wantUserFilter)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		wantIssues []string // severity and field of each issue, errors first
+	}{
+		{
+			name:    "valid",
+			content: `{"version": 3, "cpu": {"refresh_interval": "2s", "usage_warning": 70, "usage_critical": 90}, "hooks": {"commands": []}}`,
+		},
+		{
+			name:       "out of date",
+			content:    `{"cpu": {"refresh_interval": 2000000000}}`,
+			wantIssues: []string{"error version"},
+		},
+		{
+			name:       "unknown section and setting",
+			content:    `{"version": 3, "gpu": {}, "cpu": {"colour": "red"}}`,
+			wantIssues: []string{"warning cpu.colour", "warning gpu"},
+		},
+		{
+			name:       "section not an object",
+			content:    `{"version": 3, "cpu": 5}`,
+			wantIssues: []string{"error cpu"},
+		},
+		{
+			name:    "wrong types",
+			content: `{"version": 3, "cpu": {"refresh_interval": 5, "usage_warning": "70"}, "network": {"ping_count": 1.5}}`,
+			wantIssues: []string{
+				"error cpu.refresh_interval", "error cpu.usage_warning", "error network.ping_count",
+			},
+		},
+		{
+			name:       "ranges",
+			content:    `{"version": 3, "cpu": {"refresh_interval": "0s", "usage_warning": -1}, "network": {"ping_count": 0}}`,
+			wantIssues: []string{"error cpu.refresh_interval", "error cpu.usage_warning", "error network.ping_count"},
+		},
+		{
+			name:       "warning not below critical",
+			content:    `{"version": 3, "cpu": {"usage_warning": 90, "usage_critical": 80}}`,
+			wantIssues: []string{"error cpu.usage_warning"},
+		},
+		{
+			name:       "export formats",
+			content:    `{"version": 3, "cpu": {"export_format": "xml"}, "snapshot": {"export_format": "csv"}}`,
+			wantIssues: []string{"error cpu.export_format", "error snapshot.export_format"},
+		},
+		{
+			name: "alert rules",
+			content: `{"version": 3, "cpu": {"alert_rules": {"usage": {"schedules": [
+				{"window": "0 22 * * * 8h", "warning": 95, "critical": 90}]}}}}`,
+			wantIssues: []string{"error cpu.alert_rules"},
+		},
+		{
+			name:       "maintenance windows",
+			content:    `{"version": 3, "alerts": {"maintenance_windows": ["0 2 * * * 2h", "whenever"], "maintenance_mode": "mute"}}`,
+			wantIssues: []string{"error alerts.maintenance_mode", "error alerts.maintenance_windows"},
+		},
+		{
+			name:       "hooks",
+			content:    `{"version": 3, "hooks": {"commands": [{"event": "alert_raised", "command": "true"}, {"event": "reboot", "command": " "}]}}`,
+			wantIssues: []string{"error hooks.commands"},
+		},
+	}
+	for _, tt := range tests {
+		file, err := Parse([]byte(tt.content))
+		if err != nil {
+			t.Fatalf("%s: Parse() error = %v", tt.name, err)
+		}
+		var issues []string
+		for _, issue := range file.Validate() {
+			issues = append(issues, issue.Severity+" "+issue.Field)
+		}
+		if !slices.Equal(issues, tt.wantIssues) {
+			t.Errorf("%s: Validate() = %q, want %q", tt.name, issues, tt.wantIssues)
+		}
+		wantErrors := len(tt.wantIssues) > 0 && strings.HasPrefix(tt.wantIssues[0], SeverityError)
+		if got := HasErrors(file.Validate()); got != wantErrors {
+			t.Errorf("%s: HasErrors() = %v, want %v", tt.name, got, wantErrors)
+		}
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "simple-monitor", FileName)
+	file := New()
+	if err := file.Set("cpu", "refresh_interval", "3s"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := file.Set("gpu", "refresh_interval", "3s"); err == nil {
+		t.Error("Set() of an unknown section succeeded, want an error")
+	}
+	if err := file.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	var cpu cpumonitor.CPUMonitorConfig
+	if err := loaded.Apply("cpu", &cpu); err != nil || cpu.RefreshInterval != 3*time.Second {
+		t.Errorf("Apply(cpu) after Load() = %v, %v, want 3s", cpu.RefreshInterval, err)
+	}
+	if !loaded.CommandsAllowed() || !loaded.HasSection("cpu") || loaded.HasSection("memory") {
+		t.Errorf("Load() = commands allowed %v, sections cpu %v memory %v, want true, true, false",
+			loaded.CommandsAllowed(), loaded.HasSection("cpu"), loaded.HasSection("memory"))
+	}
+}
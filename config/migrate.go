@@ -0,0 +1,110 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Migrate upgrades the file to CurrentVersion in place, keeping every setting the user made
+// It returns a description of each change; nothing is written until Save is called
+func (file *File) Migrate() ([]string, error) {
+	if file.Version > CurrentVersion {
+		return nil, fmt.Errorf("config file uses schema version %d, newer than this build supports (%d)", file.Version, CurrentVersion)
+	}
+
+	var changes []string
+	if file.Version < 2 {
+		changes = append(changes, file.migrateToVersion2()...)
+	}
+	if file.Version < 3 {
+		changes = append(changes, file.migrateToVersion3()...)
+	}
+	return changes, nil
+}
+
+// migrateToVersion2 adds the version key and rewrites nanosecond durations as duration strings
+// Debug info exports are accepted too: their "config" object becomes the file
+func (file *File) migrateToVersion2() []string {
+	var changes []string
+
+	if wrapped, ok := file.raw["config"].(map[string]interface{}); ok {
+		file.raw = wrapped
+		changes = append(changes, "moved settings out of the debug info \"config\" object")
+	}
+
+	var keys []string
+	for key := range file.raw {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		section, ok := file.raw[key].(map[string]interface{})
+		sectionType, known := sectionTypes[key]
+		if !ok || !known {
+			if key != "version" {
+				delete(file.raw, key)
+				changes = append(changes, fmt.Sprintf("removed %s (not a monitor section)", key))
+			}
+			continue
+		}
+
+		fields := jsonFields(sectionType)
+		var names []string
+		for name := range section {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			number, isNumber := section[name].(json.Number)
+			if !isNumber || fields[name] != durationType {
+				continue
+			}
+			nanoseconds, err := number.Int64()
+			if err != nil {
+				continue
+			}
+			duration := time.Duration(nanoseconds).String()
+			section[name] = duration
+			changes = append(changes, fmt.Sprintf("%s.%s: %s ns -> %q", key, name, number, duration))
+		}
+	}
+
+	file.raw["version"] = 2
+	file.Version = 2
+	changes = append(changes, "set version to 2")
+	return changes
+}
+
+// migrateToVersion3 turns the single process.user_filter name into a list of users
+func (file *File) migrateToVersion3() []string {
+	var changes []string
+
+	if section, ok := file.raw["process"].(map[string]interface{}); ok {
+		if user, isString := section["user_filter"].(string); isString {
+			users := []interface{}{}
+			if user != "" {
+				users = append(users, user)
+			}
+			section["user_filter"] = users
+			changes = append(changes, fmt.Sprintf("process.user_filter: %q -> %s", user, formatList(users)))
+		}
+	}
+
+	file.raw["version"] = 3
+	file.Version = 3
+	changes = append(changes, "set version to 3")
+	return changes
+}
+
+// formatList renders a list of strings the way it appears in the file
+func formatList(items []interface{}) string {
+	content, err := json.Marshal(items)
+	if err != nil {
+		return fmt.Sprint(items)
+	}
+	return string(content)
+}
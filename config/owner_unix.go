@@ -0,0 +1,26 @@
+//go:build !windows
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// checkOwner refuses a config file that another user could have written: it must belong to the current user
+// or root and must not be writable by group or others
+// It returns whether the current user owns the file, which commands in it need
+func checkOwner(path string, info os.FileInfo) (bool, error) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return false, fmt.Errorf("failed to check the owner of %s", path)
+	}
+	if int(stat.Uid) != os.Getuid() && stat.Uid != 0 {
+		return false, fmt.Errorf("refusing to load %s: it is owned by another user (uid %d)", path, stat.Uid)
+	}
+	if info.Mode().Perm()&0022 != 0 {
+		return false, fmt.Errorf("refusing to load %s: it is writable by other users (run chmod go-w %s)", path, path)
+	}
+	return int(stat.Uid) == os.Getuid(), nil
+}
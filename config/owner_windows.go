@@ -0,0 +1,10 @@
+//go:build windows
+
+package config
+
+import "os"
+
+// checkOwner accepts every config file on Windows, where the file sits in the user's own profile by default
+func checkOwner(path string, info os.FileInfo) (bool, error) {
+	return true, nil
+}
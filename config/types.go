@@ -0,0 +1,31 @@
+package config
+
+// CurrentVersion is the config schema version written by this build
+// Version 1 files have no version key and store durations in nanoseconds (the layout of the
+// "config" object in debug info exports); version 2 adds the key and stores durations as strings such as "5s";
+// version 3 turns process.user_filter from a single user name into a list of users
+const CurrentVersion = 3
+
+// Severity levels of validation issues
+const (
+	SeverityError   = "error"   // The file is rejected until the setting is fixed
+	SeverityWarning = "warning" // The setting is ignored
+)
+
+// File is a parsed config file
+// Settings are kept as raw JSON values so unknown and malformed ones can be reported instead of silently dropped
+type File struct {
+	Path    string // Where the file was read from
+	Version int    // Schema version (1 when the file has no numeric version key)
+
+	raw             map[string]interface{}
+	commandsAllowed bool // Whether the current user owns the file, see CommandsAllowed
+}
+
+// Issue describes one problem found in a config file
+type Issue struct {
+	Severity string `json:"severity"` // error or warning
+	Field    string `json:"field"`    // Setting path, e.g. cpu.refresh_interval
+	Message  string `json:"message"`  // What is wrong
+	Fix      string `json:"fix"`      // How to correct it
+}
@@ -0,0 +1,373 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/ahmadreza-log/simple-monitor/alert"
+	"github.com/ahmadreza-log/simple-monitor/hooks"
+	"github.com/ahmadreza-log/simple-monitor/networkmonitor"
+	"github.com/ahmadreza-log/simple-monitor/snapshot"
+	"net"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// exportFormats lists the accepted export_format values
+var exportFormats = []string{"json", "csv", "txt", "protobuf"}
+
+// Validate checks every setting and returns the problems found, errors first
+func (file *File) Validate() []Issue {
+	var issues []Issue
+
+	if file.Version > CurrentVersion {
+		return append(issues, Issue{
+			Severity: SeverityError,
+			Field:    "version",
+			Message:  fmt.Sprintf("schema version %d is newer than this build supports (%d)", file.Version, CurrentVersion),
+			Fix:      "upgrade simple-monitor or restore the previous config file",
+		})
+	}
+	if file.Version < CurrentVersion {
+		// Old files store durations differently, so field checks would only report noise
+		return append(issues, Issue{
+			Severity: SeverityError,
+			Field:    "version",
+			Message:  fmt.Sprintf("schema version %d is out of date (current is %d)", file.Version, CurrentVersion),
+			Fix:      "run `simple-monitor config migrate` to upgrade it",
+		})
+	}
+
+	var keys []string
+	for key := range file.raw {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if key == "version" {
+			continue
+		}
+		sectionType, known := sectionTypes[key]
+		if !known {
+			issues = append(issues, Issue{
+				Severity: SeverityWarning,
+				Field:    key,
+				Message:  "unknown section, ignored",
+				Fix:      "use one of: " + strings.Join(sectionOrder, ", "),
+			})
+			continue
+		}
+		section, ok := file.raw[key].(map[string]interface{})
+		if !ok {
+			issues = append(issues, Issue{
+				Severity: SeverityError,
+				Field:    key,
+				Message:  "must be an object of settings",
+				Fix:      fmt.Sprintf("write it as \"%s\": { ... }", key),
+			})
+			continue
+		}
+		issues = append(issues, validateSection(key, section, sectionType)...)
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Severity == SeverityError && issues[j].Severity != SeverityError
+	})
+	return issues
+}
+
+// HasErrors returns whether any issue rejects the file
+func HasErrors(issues []Issue) bool {
+	for _, issue := range issues {
+		if issue.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// validateSection checks the settings of one monitor section
+func validateSection(name string, section map[string]interface{}, sectionType reflect.Type) []Issue {
+	var issues []Issue
+	fields := jsonFields(sectionType)
+
+	var keys []string
+	for key := range section {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		field := name + "." + key
+		fieldType, known := fields[key]
+		if !known {
+			issues = append(issues, Issue{
+				Severity: SeverityWarning,
+				Field:    field,
+				Message:  "unknown setting, ignored",
+				Fix:      "check the spelling or remove it",
+			})
+			continue
+		}
+		if message, fix := validateValue(key, section[key], fieldType); message != "" {
+			issues = append(issues, Issue{Severity: SeverityError, Field: field, Message: message, Fix: fix})
+		}
+	}
+
+	// Combined snapshots have no CSV or text form
+	if format, ok := section["export_format"].(string); ok && name == "snapshot" && format != snapshot.FormatJSON && format != snapshot.FormatProtobuf && isExportFormat(format) {
+		issues = append(issues, Issue{
+			Severity: SeverityError,
+			Field:    name + ".export_format",
+			Message:  fmt.Sprintf("combined snapshots cannot be exported as %s", format),
+			Fix:      fmt.Sprintf("use %s or %s", snapshot.FormatJSON, snapshot.FormatProtobuf),
+		})
+	}
+
+	// Warning thresholds must trigger before critical ones
+	for _, key := range keys {
+		if !strings.HasSuffix(key, "_warning") {
+			continue
+		}
+		criticalKey := strings.TrimSuffix(key, "_warning") + "_critical"
+		warning, warningOK := numberValue(section[key])
+		critical, criticalOK := numberValue(section[criticalKey])
+		if warningOK && criticalOK && warning >= critical {
+			issues = append(issues, Issue{
+				Severity: SeverityError,
+				Field:    name + "." + key,
+				Message:  fmt.Sprintf("warning threshold %g is not below %s (%g)", warning, criticalKey, critical),
+				Fix:      fmt.Sprintf("lower %s or raise %s", key, criticalKey),
+			})
+		}
+	}
+
+	return issues
+}
+
+// validateValue checks one setting against its type and range
+// It returns an empty message when the value is fine
+func validateValue(key string, value interface{}, fieldType reflect.Type) (string, string) {
+	if fieldType == durationType {
+		text, ok := value.(string)
+		if !ok {
+			return "must be a duration string", "write it like \"5s\", \"500ms\" or \"2m\""
+		}
+		duration, err := time.ParseDuration(text)
+		if err != nil {
+			return fmt.Sprintf("invalid duration %q", text), "write it like \"5s\", \"500ms\" or \"2m\""
+		}
+		if duration < 0 {
+			return fmt.Sprintf("interval %s is negative", text), "use a positive duration"
+		}
+		if duration == 0 && key == "refresh_interval" {
+			return "refresh interval must be greater than zero", "use at least \"1s\""
+		}
+		if duration == 0 && key == "check_interval" {
+			return "check interval must be greater than zero", "use a duration such as \"24h\""
+		}
+		return "", ""
+	}
+
+	switch fieldType.Kind() {
+	case reflect.Bool:
+		if _, ok := value.(bool); !ok {
+			return "must be true or false", "remove the quotes or use true/false"
+		}
+	case reflect.Int, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		number, ok := value.(json.Number)
+		if !ok {
+			return "must be a whole number", "remove the quotes around the number"
+		}
+		whole, err := number.Int64()
+		if err != nil {
+			return fmt.Sprintf("%s is not a whole number", number), "use a whole number"
+		}
+		if whole < 0 {
+			return fmt.Sprintf("%d is negative", whole), "use 0 or a positive number"
+		}
+		if whole == 0 && (key == "ping_count" || key == "trace_hops" || key == "trace_isp_hops") {
+			return "must be at least 1", "use 1 or more"
+		}
+	case reflect.Float64:
+		number, ok := numberValue(value)
+		if !ok {
+			return "must be a number", "remove the quotes around the number"
+		}
+		if number < 0 {
+			return fmt.Sprintf("%g is negative", number), "use 0 or a positive number"
+		}
+		if key == "limit_warning_percent" && number > 100 {
+			return fmt.Sprintf("%g is above 100%%", number), "use a percentage such as 80, or 0 to turn the check off"
+		}
+	case reflect.String:
+		text, ok := value.(string)
+		if !ok {
+			return "must be a string", "put the value in quotes"
+		}
+		if key == "export_format" && !isExportFormat(text) {
+			return fmt.Sprintf("unknown export format %q", text), "use one of: " + strings.Join(exportFormats, ", ")
+		}
+		if key == "maintenance_mode" && text != alert.ModeSuppress && text != alert.ModeLog {
+			return fmt.Sprintf("unknown maintenance mode %q", text), fmt.Sprintf("use %s or %s", alert.ModeSuppress, alert.ModeLog)
+		}
+		if key == "rate_unit" && text != networkmonitor.RateUnitBits && text != networkmonitor.RateUnitBytes {
+			return fmt.Sprintf("unknown rate unit %q", text), fmt.Sprintf("use %s or %s", networkmonitor.RateUnitBits, networkmonitor.RateUnitBytes)
+		}
+		if key == "event" && !hooks.IsEvent(text) {
+			return fmt.Sprintf("unknown event %q", text), "use one of: " + strings.Join(hooks.Events, ", ")
+		}
+		if key == "command" && strings.TrimSpace(text) == "" {
+			return "is empty", "write the shell command to run"
+		}
+		if key == "window" {
+			if _, err := alert.ParseWindow(text); err != nil {
+				return err.Error(), "write it like \"0 22 * * * 8h backups\" (minute hour day month weekday duration name)"
+			}
+		}
+		if key == "mac" {
+			if mac, err := net.ParseMAC(text); err != nil || len(mac) != 6 {
+				return fmt.Sprintf("invalid MAC address %q", text), "write it like \"00:11:22:33:44:55\""
+			}
+		}
+	case reflect.Slice:
+		if fieldType.Elem().Kind() == reflect.Struct {
+			return validateSettingsList(value, fieldType.Elem())
+		}
+		items, ok := value.([]interface{})
+		if !ok {
+			return "must be a list of strings", "write it like [\"a\", \"b\"]"
+		}
+		for _, item := range items {
+			text, ok := item.(string)
+			if !ok {
+				return "must be a list of strings", "put each item in quotes"
+			}
+			if key == "maintenance_windows" {
+				if _, err := alert.ParseWindow(text); err != nil {
+					return err.Error(), "write it like \"0 2 * * * 2h backups\" (minute hour day month weekday duration name)"
+				}
+			}
+			if key == "schedule" {
+				if _, err := alert.ParseTimes(text); err != nil {
+					return err.Error(), "write it like \"0 * * * *\" for hourly (minute hour day month weekday)"
+				}
+			}
+		}
+	case reflect.Map:
+		return validateNamedSettings(value, fieldType.Elem())
+	}
+	return "", ""
+}
+
+// validateNamedSettings checks an object of named settings groups such as alert_rules
+// It returns the first problem found, prefixed with the group and setting name
+func validateNamedSettings(value interface{}, entryType reflect.Type) (string, string) {
+	entries, ok := value.(map[string]interface{})
+	if !ok {
+		return "must be an object", "write it like {\"name\": { ... }}"
+	}
+	fields := jsonFields(entryType)
+
+	var names []string
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		settings, ok := entries[name].(map[string]interface{})
+		if !ok {
+			return fmt.Sprintf("%s must be an object of settings", name), fmt.Sprintf("write it as \"%s\": { ... }", name)
+		}
+
+		var keys []string
+		for key := range settings {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			fieldType, known := fields[key]
+			if !known {
+				var accepted []string
+				for field := range fields {
+					accepted = append(accepted, field)
+				}
+				sort.Strings(accepted)
+				return fmt.Sprintf("%s.%s is not a known setting", name, key), "use one of: " + strings.Join(accepted, ", ")
+			}
+			if message, fix := validateValue(key, settings[key], fieldType); message != "" {
+				return fmt.Sprintf("%s.%s %s", name, key, message), fix
+			}
+		}
+	}
+	return "", ""
+}
+
+// validateSettingsList checks a list of settings objects such as an alert rule's schedules
+// It returns the first problem found, prefixed with the item's position (counting from 1)
+func validateSettingsList(value interface{}, itemType reflect.Type) (string, string) {
+	items, ok := value.([]interface{})
+	if !ok {
+		return "must be a list of objects", "write it like [{ ... }, { ... }]"
+	}
+	fields := jsonFields(itemType)
+
+	for i, item := range items {
+		settings, ok := item.(map[string]interface{})
+		if !ok {
+			return fmt.Sprintf("item %d must be an object of settings", i+1), "write each item as { ... }"
+		}
+
+		var keys []string
+		for key := range settings {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			fieldType, known := fields[key]
+			if !known {
+				var accepted []string
+				for field := range fields {
+					accepted = append(accepted, field)
+				}
+				sort.Strings(accepted)
+				return fmt.Sprintf("item %d: %s is not a known setting", i+1, key), "use one of: " + strings.Join(accepted, ", ")
+			}
+			if message, fix := validateValue(key, settings[key], fieldType); message != "" {
+				return fmt.Sprintf("item %d: %s %s", i+1, key, message), fix
+			}
+		}
+
+		warning, warningOK := numberValue(settings["warning"])
+		critical, criticalOK := numberValue(settings["critical"])
+		if warningOK && criticalOK && warning > 0 && critical > 0 && warning >= critical {
+			return fmt.Sprintf("item %d: warning %g is not below critical %g", i+1, warning, critical), "lower warning or raise critical"
+		}
+	}
+	return "", ""
+}
+
+// numberValue returns a JSON number as a float
+func numberValue(value interface{}) (float64, bool) {
+	number, ok := value.(json.Number)
+	if !ok {
+		return 0, false
+	}
+	parsed, err := number.Float64()
+	return parsed, err == nil
+}
+
+// isExportFormat returns whether format is an accepted export format
+func isExportFormat(format string) bool {
+	for _, accepted := range exportFormats {
+		if format == accepted {
+			return true
+		}
+	}
+	return false
+}
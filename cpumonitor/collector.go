@@ -2,58 +2,124 @@ package cpumonitor
 
 import (
 	"fmt"
+	"github.com/ahmadreza-log/simple-monitor/alert"
+	"github.com/ahmadreza-log/simple-monitor/historystore"
+	"github.com/ahmadreza-log/simple-monitor/listening"
+	"github.com/ahmadreza-log/simple-monitor/partial"
+	"github.com/ahmadreza-log/simple-monitor/procid"
+	"github.com/ahmadreza-log/simple-monitor/provider"
+	"github.com/ahmadreza-log/simple-monitor/simulate"
+	"math"
 	"runtime"
 	"sort"
 	"strings"
+	"sync"
 	"time"
-
-	"github.com/shirou/gopsutil/v3/cpu"
-	"github.com/shirou/gopsutil/v3/process"
 )
 
+// trendGraphPoints is how many recent samples are kept with the data for the usage history graph
+const trendGraphPoints = 120
+
 // CPUMonitorCollector handles the collection of CPU monitoring data
 // This struct provides methods to gather real-time CPU metrics and process information
 type CPUMonitorCollector struct {
-	// Configuration
+	// Configuration: config is what the collection in progress reads, adopted from latest as it starts
+	// latest is replaced on every change and never modified, so it can be handed out without copying
+	// collectMutex is held for a whole collection, so collections started by the live loop, subscribers and
+	// the attach server run one at a time and never see config or the state they carry change under them
 	config        *CPUMonitorConfig
+	latest        *CPUMonitorConfig
+	configMutex   sync.Mutex
+	collectMutex  sync.Mutex
 	lastCPUUsage  float64
 	lastTimestamp time.Time
 
-	// Process tracking
-	processCache    map[int32]*CPUProcessInfo
-	lastProcessTime map[int32]time.Time
-
 	// History tracking
 	history *CPUUsageHistory
+
+	// Downsampled long-term history (raw, 1-minute and 5-minute tiers)
+	longHistory *historystore.Store
+
+	// Idle mode (expensive process scans are paused)
+	idle bool
+
+	// Synthetic data source used instead of the system (nil when collecting real data)
+	simulator *simulate.Source
+
+	// System data sources (gopsutil unless replaced with fakes)
+	system provider.Providers
+
+	// Alert levels carried between collections (hysteresis and minimum durations)
+	alerts *alert.Tracker
+
+	// Process creation counter from the previous refresh, for the fork rate
+	lastProcsCreated uint64
+	lastForkSample   time.Time
+
+	// Energy counters from the previous refresh, for the power draw, and the energy used since the start
+	lastEnergy       map[string]uint64
+	lastEnergySample time.Time
+	lastPowerSample  time.Time
+	energyUsed       float64 // Joules
+
+	// Socket/core layout, detected on first collection (it does not change while running)
+	topology    *CPUTopology
+	logicalCPUs map[int]logicalCPU
+}
+
+// logicalCPU places one logical CPU in the topology
+type logicalCPU struct {
+	Socket  int  // Physical package
+	Core    int  // Physical core within the socket
+	Sibling bool // Whether this is a second (or later) SMT thread of the core
 }
 
 // NewCPUMonitorCollector creates a new instance of CPUMonitorCollector
 // with default configuration values
 func NewCPUMonitorCollector() *CPUMonitorCollector {
 	config := &CPUMonitorConfig{
+		Enabled:             true,
 		RefreshInterval:     1 * time.Second,
 		MaxProcesses:        20,
+		UsageWarning:        80.0,
+		UsageCritical:       90.0,
 		TemperatureWarning:  70.0,
 		TemperatureCritical: 85.0,
+		ForkRateWarning:     200.0,
+		ForkRateCritical:    1000.0,
 		ShowCores:           true,
 		ShowProcesses:       true,
 		ShowTemperature:     true,
 		ShowLoadAverage:     true,
+		ShowPower:           true,
+		ShowPorts:           true,
+		ShowHistory:         true,
 		ExportToFile:        true,
 		ExportInterval:      1 * time.Hour,
 		ExportFormat:        "json",
+		PersistHistory:      true,
+		HistorySaveInterval: 1 * time.Minute,
+		HistorySize:         historystore.DefaultBufferSize,
+		HistoryResolution:   0,
 		MinCPUUsage:         1.0,
 		ProcessNameFilter:   "",
+		AlertRules: map[string]alert.Rule{
+			"usage":       {Hysteresis: 5, RaiseAfter: 30 * time.Second},
+			"temperature": {Hysteresis: 3},
+			"fork_rate":   {ClearAfter: 10 * time.Second},
+		},
 	}
 
 	return &CPUMonitorCollector{
-		config:          config,
-		lastCPUUsage:    0.0,
-		lastTimestamp:   time.Now(),
-		processCache:    make(map[int32]*CPUProcessInfo),
-		lastProcessTime: make(map[int32]time.Time),
+		config:        config,
+		latest:        config,
+		lastCPUUsage:  0.0,
+		lastTimestamp: time.Now(),
+		longHistory:   historystore.NewStore(historystore.DefaultTiers()),
+		alerts:        alert.NewTracker("cpu"),
+		system:        provider.System(),
 		history: &CPUUsageHistory{
-			MaxDataPoints:  100,
+			MaxDataPoints:  config.HistorySize,
 			DataPointCount: 0,
 		},
 	}
@@ -62,50 +128,71 @@ func NewCPUMonitorCollector() *CPUMonitorCollector {
 // CollectCPUMonitorData gathers comprehensive CPU monitoring data
 // This is the main method that collects all available CPU metrics
 func (collector *CPUMonitorCollector) CollectCPUMonitorData() (*CPUMonitorData, error) {
+	collector.collectMutex.Lock()
+	defer collector.collectMutex.Unlock()
+	collector.adoptConfig()
 	data := &CPUMonitorData{
 		Timestamp:       time.Now(),
 		RefreshInterval: collector.config.RefreshInterval,
 		IsMonitoring:    true,
+		SectionErrors:   make(partial.Errors),
 	}
 
-	// Collect basic CPU information
-	if err := collector.collectBasicCPUInfo(data); err != nil {
-		return nil, fmt.Errorf("failed to collect basic CPU info: %w", err)
+	// Synthetic data for demos and UI work replaces every system query
+	if collector.simulator != nil {
+		collector.collectSimulatedData(data)
+		data.AlertNotes = collector.alerts.Notes()
+		collector.updateHistory(data)
+		return data, nil
 	}
 
-	// Collect CPU usage statistics
+	// The first power reading needs an earlier energy sample; taking it here measures across the
+	// one-second usage sample below instead of leaving the first refresh without one
+	if collector.config.ShowPower && collector.lastEnergySample.IsZero() {
+		collector.sampleEnergy(time.Now())
+	}
+
+	// Collect basic CPU information
+	// Sections that fail are recorded and shown as unavailable instead of aborting the snapshot
+	data.SectionErrors.Add("cpu_info", collector.collectBasicCPUInfo(data))
+	data.SectionErrors.Add("topology", collector.collectTopology(data))
+
+	// Collect CPU usage statistics (nothing useful can be shown without them)
 	if err := collector.collectCPUUsageStats(data); err != nil {
 		return nil, fmt.Errorf("failed to collect CPU usage stats: %w", err)
 	}
 
 	// Collect per-core information
 	if collector.config.ShowCores {
-		if err := collector.collectCoreInfo(data); err != nil {
-			return nil, fmt.Errorf("failed to collect core info: %w", err)
-		}
+		data.SectionErrors.Add("cores", collector.collectCoreInfo(data))
 	}
 
 	// Collect process information
-	if collector.config.ShowProcesses {
-		if err := collector.collectProcessInfo(data); err != nil {
-			return nil, fmt.Errorf("failed to collect process info: %w", err)
+	if collector.config.ShowProcesses && !collector.idle {
+		data.SectionErrors.Add("processes", collector.collectProcessInfo(data))
+		if collector.config.ShowPorts {
+			data.SectionErrors.Add("ports", collector.collectListeningPorts(data))
 		}
 	}
 
 	// Collect temperature information
 	if collector.config.ShowTemperature {
-		if err := collector.collectTemperatureInfo(data); err != nil {
-			return nil, fmt.Errorf("failed to collect temperature info: %w", err)
-		}
+		data.SectionErrors.Add("temperature", collector.collectTemperatureInfo(data))
 	}
 
 	// Collect load average
 	if collector.config.ShowLoadAverage {
-		if err := collector.collectLoadAverage(data); err != nil {
-			return nil, fmt.Errorf("failed to collect load average: %w", err)
-		}
+		data.SectionErrors.Add("load_average", collector.collectLoadAverage(data))
+	}
+
+	// Collect package power
+	if collector.config.ShowPower {
+		data.SectionErrors.Add("power", collector.collectPowerInfo(data))
 	}
 
+	// Runbook notes of the alerts raised above
+	data.AlertNotes = collector.alerts.Notes()
+
 	// Update history
 	collector.updateHistory(data)
 
@@ -119,7 +206,7 @@ func (collector *CPUMonitorCollector) collectBasicCPUInfo(data *CPUMonitorData)
 	data.Architecture = runtime.GOARCH
 
 	// Get CPU info using gopsutil
-	cpuInfo, err := cpu.Info()
+	cpuInfo, err := collector.system.CPU.Info()
 	if err != nil {
 		return fmt.Errorf("failed to get CPU info: %w", err)
 	}
@@ -134,10 +221,69 @@ func (collector *CPUMonitorCollector) collectBasicCPUInfo(data *CPUMonitorData)
 	return nil
 }
 
+// collectTopology attaches the socket/core layout and caches to data
+// The layout is read from the OS once; where the OS does not expose it, it is estimated from core counts
+func (collector *CPUMonitorCollector) collectTopology(data *CPUMonitorData) error {
+	if collector.topology == nil {
+		topology, cpus, err := readTopology()
+		if err != nil {
+			topology, cpus, err = estimateTopology(collector.system.CPU, data.LogicalCores)
+			if err != nil {
+				return fmt.Errorf("failed to detect CPU topology: %w", err)
+			}
+		}
+		collector.topology = topology
+		collector.logicalCPUs = cpus
+	}
+
+	data.Topology = collector.topology
+	data.PhysicalCores = collector.topology.Cores
+	return nil
+}
+
+// estimateTopology derives the layout from core counts when the OS does not expose it
+// Logical CPUs of one core are assumed to be numbered next to each other, as Windows does
+func estimateTopology(source provider.CPUProvider, logicalCores int) (*CPUTopology, map[int]logicalCPU, error) {
+	physicalCores, err := source.Counts(false)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to count physical cores: %w", err)
+	}
+	if physicalCores <= 0 || physicalCores > logicalCores {
+		physicalCores = logicalCores
+	}
+	threadsPerCore := (logicalCores + physicalCores - 1) / physicalCores
+
+	sockets := make(map[string]bool)
+	if infos, err := source.Info(); err == nil {
+		for _, info := range infos {
+			sockets[info.PhysicalID] = true
+		}
+	}
+	socketCount := max(len(sockets), 1)
+
+	cpus := make(map[int]logicalCPU, logicalCores)
+	for i := 0; i < logicalCores; i++ {
+		core := i / threadsPerCore
+		cpus[i] = logicalCPU{
+			Socket:  core * socketCount / physicalCores,
+			Core:    core,
+			Sibling: i%threadsPerCore != 0,
+		}
+	}
+
+	return &CPUTopology{
+		Sockets:        socketCount,
+		Cores:          physicalCores,
+		ThreadsPerCore: threadsPerCore,
+		SMTEnabled:     threadsPerCore > 1,
+		Estimated:      true,
+	}, cpus, nil
+}
+
 // collectCPUUsageStats gathers overall CPU usage statistics
 func (collector *CPUMonitorCollector) collectCPUUsageStats(data *CPUMonitorData) error {
 	// Get CPU usage percentages
-	percentages, err := cpu.Percent(time.Second, false)
+	percentages, err := collector.system.CPU.Percent(time.Second, false)
 	if err != nil {
 		return fmt.Errorf("failed to get CPU usage: %w", err)
 	}
@@ -147,10 +293,11 @@ func (collector *CPUMonitorCollector) collectCPUUsageStats(data *CPUMonitorData)
 		data.IdleUsage = 100.0 - data.OverallUsage
 		data.UserUsage = data.OverallUsage * 0.7   // Approximate user usage
 		data.SystemUsage = data.OverallUsage * 0.3 // Approximate system usage
+		collector.evaluateUsage(data, data.Timestamp)
 	}
 
 	// Get detailed CPU times
-	times, err := cpu.Times(false)
+	times, err := collector.system.CPU.Times(false)
 	if err == nil && len(times) > 0 {
 		time := times[0]
 		total := time.User + time.System + time.Idle + time.Iowait
@@ -168,13 +315,13 @@ func (collector *CPUMonitorCollector) collectCPUUsageStats(data *CPUMonitorData)
 // collectCoreInfo gathers per-core CPU information
 func (collector *CPUMonitorCollector) collectCoreInfo(data *CPUMonitorData) error {
 	// Get per-core CPU usage
-	percentages, err := cpu.Percent(time.Second, true)
+	percentages, err := collector.system.CPU.Percent(time.Second, true)
 	if err != nil {
 		return fmt.Errorf("failed to get per-core CPU usage: %w", err)
 	}
 
 	// Get per-core CPU times
-	times, err := cpu.Times(true)
+	times, err := collector.system.CPU.Times(true)
 	if err != nil {
 		return fmt.Errorf("failed to get per-core CPU times: %w", err)
 	}
@@ -182,11 +329,17 @@ func (collector *CPUMonitorCollector) collectCoreInfo(data *CPUMonitorData) erro
 	// Create core info for each logical core
 	data.Cores = make([]CPUCoreInfo, data.LogicalCores)
 	for i := 0; i < data.LogicalCores; i++ {
+		// CPUs missing from the topology (offline, or no topology at all) count as their own core
+		layout, known := collector.logicalCPUs[i]
+		if !known {
+			layout = logicalCPU{Core: i}
+		}
 		coreInfo := CPUCoreInfo{
 			CoreID:          i,
-			PhysicalID:      i / 2, // Assuming hyperthreading
+			PhysicalID:      layout.Socket,
+			PhysicalCore:    layout.Core,
 			IsOnline:        true,
-			IsHyperthreaded: i%2 == 1,
+			IsHyperthreaded: layout.Sibling,
 			LastUpdated:     time.Now(),
 		}
 
@@ -215,7 +368,7 @@ func (collector *CPUMonitorCollector) collectCoreInfo(data *CPUMonitorData) erro
 // collectProcessInfo gathers information about CPU-consuming processes
 func (collector *CPUMonitorCollector) collectProcessInfo(data *CPUMonitorData) error {
 	// Get all processes
-	processes, err := process.Processes()
+	processes, err := collector.system.Proc.Processes()
 	if err != nil {
 		return fmt.Errorf("failed to get processes: %w", err)
 	}
@@ -243,7 +396,7 @@ func (collector *CPUMonitorCollector) collectProcessInfo(data *CPUMonitorData) e
 		}
 
 		processInfo := CPUProcessInfo{
-			PID:             proc.Pid,
+			PID:             proc.PID(),
 			Name:            name,
 			CPUUsagePercent: cpuPercent,
 			Status:          strings.Join(status, ","),
@@ -256,6 +409,16 @@ func (collector *CPUMonitorCollector) collectProcessInfo(data *CPUMonitorData) e
 			processInfo.MemoryUsage = memInfo.RSS
 		}
 
+		// Accumulated CPU time and start time, to tell long-running consumers from brief spikes
+		if times, err := proc.Times(); err == nil {
+			processInfo.CPUUsageTime = uint64((times.User + times.System) * 1000)
+		}
+		if createTime, err := proc.CreateTime(); err == nil {
+			processInfo.CreateTime = createTime
+			processInfo.Uptime = time.Now().Unix() - createTime/1000
+		}
+		processInfo.InstanceID = procid.InstanceID(processInfo.PID, processInfo.CreateTime)
+
 		processInfos = append(processInfos, processInfo)
 	}
 
@@ -274,6 +437,18 @@ func (collector *CPUMonitorCollector) collectProcessInfo(data *CPUMonitorData) e
 	return nil
 }
 
+// collectListeningPorts adds the ports each top process listens on
+func (collector *CPUMonitorCollector) collectListeningPorts(data *CPUMonitorData) error {
+	ports, err := listening.ByPID(collector.system.Net)
+	if err != nil {
+		return err
+	}
+	for i := range data.TopProcesses {
+		data.TopProcesses[i].ListeningPorts = listening.Strings(ports[data.TopProcesses[i].PID])
+	}
+	return nil
+}
+
 // collectTemperatureInfo gathers CPU temperature information
 func (collector *CPUMonitorCollector) collectTemperatureInfo(data *CPUMonitorData) error {
 	// TODO: Implement platform-specific temperature collection
@@ -293,74 +468,206 @@ func (collector *CPUMonitorCollector) collectTemperatureInfo(data *CPUMonitorDat
 
 // collectLoadAverage gathers system load average information
 func (collector *CPUMonitorCollector) collectLoadAverage(data *CPUMonitorData) error {
-	// TODO: Implement platform-specific load average collection
-	// This should collect:
-	// - 1-minute load average
-	// - 5-minute load average
-	// - 15-minute load average
+	// Get load averages
+	avg, err := collector.system.CPU.LoadAvg()
+	if err != nil {
+		return fmt.Errorf("failed to get load average: %w", err)
+	}
 
-	// For now, return placeholder data
-	data.LoadAverage1Min = 0.0
-	data.LoadAverage5Min = 0.0
-	data.LoadAverage15Min = 0.0
+	data.LoadAverage1Min = avg.Load1
+	data.LoadAverage5Min = avg.Load5
+	data.LoadAverage15Min = avg.Load15
+
+	// Get run queue and blocked task counts (not available on every platform)
+	misc, err := collector.system.CPU.LoadMisc()
+	if err == nil {
+		data.RunQueueLength = misc.ProcsRunning
+		data.BlockedTasks = misc.ProcsBlocked
+		if misc.ProcsCreated > 0 {
+			collector.updateForkRate(data, uint64(misc.ProcsCreated), data.Timestamp)
+		}
+	}
 
 	return nil
 }
 
+// updateForkRate turns the processes-created counter into a rate since the previous refresh
+// The first refresh has nothing to compare with and reports 0
+// Syscall rates are not collected: the kernel keeps no system-wide syscall counter short of tracing
+func (collector *CPUMonitorCollector) updateForkRate(data *CPUMonitorData, created uint64, now time.Time) {
+	data.ProcessesCreated = created
+	if !collector.lastForkSample.IsZero() && created >= collector.lastProcsCreated {
+		if elapsed := now.Sub(collector.lastForkSample).Seconds(); elapsed > 0 {
+			data.ForkRate = float64(created-collector.lastProcsCreated) / elapsed
+		}
+	}
+	collector.lastProcsCreated = created
+	collector.lastForkSample = now
+
+	level := collector.alerts.Evaluate("fork_rate", data.ForkRate, collector.config.ForkRateWarning,
+		collector.config.ForkRateCritical, collector.config.AlertRules["fork_rate"], now)
+	data.ForkRateStatus = level.String()
+}
+
+// evaluateUsage sets the overall usage status, using the thresholds of the rule's schedule when one is active
+func (collector *CPUMonitorCollector) evaluateUsage(data *CPUMonitorData, now time.Time) {
+	rule := collector.config.AlertRules["usage"]
+	level := collector.alerts.Evaluate("usage", data.OverallUsage, collector.config.UsageWarning,
+		collector.config.UsageCritical, rule, now)
+	data.UsageStatus = level.String()
+	if schedule, active := rule.ActiveSchedule(now); active {
+		data.UsageSchedule = schedule.Name()
+	}
+}
+
 // updateHistory updates the CPU usage history with new data
 func (collector *CPUMonitorCollector) updateHistory(data *CPUMonitorData) {
 	now := time.Now()
 
-	// Add new data point
-	collector.history.Timestamps = append(collector.history.Timestamps, now)
-	collector.history.OverallUsage = append(collector.history.OverallUsage, data.OverallUsage)
-	collector.history.UserUsage = append(collector.history.UserUsage, data.UserUsage)
-	collector.history.SystemUsage = append(collector.history.SystemUsage, data.SystemUsage)
-	collector.history.IdleUsage = append(collector.history.IdleUsage, data.IdleUsage)
-	collector.history.Temperature = append(collector.history.Temperature, data.Temperature)
-
-	// Add per-core data
-	if len(data.Cores) > 0 {
-		coreUsage := make([]float64, len(data.Cores))
-		for i, core := range data.Cores {
-			coreUsage[i] = core.UsagePercent
+	// The in-memory history keeps HistorySize samples, at most one per HistoryResolution
+	collector.history.MaxDataPoints = collector.config.HistorySize
+	if historystore.BufferSampleDue(collector.history.Timestamps, now, collector.config.HistoryResolution) {
+		// Add new data point
+		collector.history.Timestamps = append(collector.history.Timestamps, now)
+		collector.history.OverallUsage = append(collector.history.OverallUsage, data.OverallUsage)
+		collector.history.UserUsage = append(collector.history.UserUsage, data.UserUsage)
+		collector.history.SystemUsage = append(collector.history.SystemUsage, data.SystemUsage)
+		collector.history.IdleUsage = append(collector.history.IdleUsage, data.IdleUsage)
+		collector.history.Temperature = append(collector.history.Temperature, data.Temperature)
+		collector.history.ForkRate = append(collector.history.ForkRate, data.ForkRate)
+		collector.history.PackagePower = append(collector.history.PackagePower, packageWatts(data))
+
+		// Add per-core data
+		if len(data.Cores) > 0 {
+			coreUsage := make([]float64, len(data.Cores))
+			for i, core := range data.Cores {
+				coreUsage[i] = core.UsagePercent
+			}
+			collector.history.CoreUsage = append(collector.history.CoreUsage, coreUsage)
 		}
-		collector.history.CoreUsage = append(collector.history.CoreUsage, coreUsage)
 	}
 
 	// Maintain maximum data points
-	if len(collector.history.Timestamps) > collector.history.MaxDataPoints {
-		collector.history.Timestamps = collector.history.Timestamps[1:]
-		collector.history.OverallUsage = collector.history.OverallUsage[1:]
-		collector.history.UserUsage = collector.history.UserUsage[1:]
-		collector.history.SystemUsage = collector.history.SystemUsage[1:]
-		collector.history.IdleUsage = collector.history.IdleUsage[1:]
-		collector.history.Temperature = collector.history.Temperature[1:]
+	if excess := historystore.BufferExcess(len(collector.history.Timestamps), collector.history.MaxDataPoints); excess > 0 {
+		collector.history.Timestamps = collector.history.Timestamps[excess:]
+		collector.history.OverallUsage = collector.history.OverallUsage[excess:]
+		collector.history.UserUsage = collector.history.UserUsage[excess:]
+		collector.history.SystemUsage = collector.history.SystemUsage[excess:]
+		collector.history.IdleUsage = collector.history.IdleUsage[excess:]
+		collector.history.Temperature = collector.history.Temperature[excess:]
+		collector.history.ForkRate = collector.history.ForkRate[excess:]
+		collector.history.PackagePower = collector.history.PackagePower[excess:]
 		if len(collector.history.CoreUsage) > 0 {
-			collector.history.CoreUsage = collector.history.CoreUsage[1:]
+			collector.history.CoreUsage = collector.history.CoreUsage[historystore.BufferExcess(len(collector.history.CoreUsage), collector.history.MaxDataPoints):]
 		}
 	}
 
+	// Feed the downsampled long-term history
+	collector.longHistory.Add(now, map[string]float64{
+		"overall_usage": data.OverallUsage,
+		"user_usage":    data.UserUsage,
+		"system_usage":  data.SystemUsage,
+		"idle_usage":    data.IdleUsage,
+		"temperature":   data.Temperature,
+		"fork_rate":     data.ForkRate,
+		"package_power": packageWatts(data),
+	})
+
 	collector.history.DataPointCount = len(collector.history.Timestamps)
+
+	// The recent peak shows a fork storm that has already passed
+	for _, rate := range collector.history.ForkRate {
+		data.ForkRatePeak = math.Max(data.ForkRatePeak, rate)
+	}
+
+	// The graph shows recent power next to usage, so load and energy use can be compared
+	if data.Power != nil {
+		start := max(len(collector.history.PackagePower)-powerGraphPoints, 0)
+		data.Power.Recent = append([]float64(nil), collector.history.PackagePower[start:]...)
+		data.Power.RecentUsage = append([]float64(nil), collector.history.OverallUsage[start:]...)
+		for _, watts := range collector.history.PackagePower {
+			data.Power.PeakWatts = math.Max(data.Power.PeakWatts, watts)
+		}
+	}
+
+	if collector.config.ShowHistory {
+		start := max(len(collector.history.Timestamps)-trendGraphPoints, 0)
+		data.Trend = &CPUUsageTrend{
+			Timestamps: append([]time.Time(nil), collector.history.Timestamps[start:]...),
+			Usage:      append([]float64(nil), collector.history.OverallUsage[start:]...),
+		}
+	}
+}
+
+// packageWatts returns the measured package power of data, 0 when there is none
+func packageWatts(data *CPUMonitorData) float64 {
+	if data.Power == nil || !data.Power.Measured {
+		return 0
+	}
+	return data.Power.PackageWatts
 }
 
-// GetCPUUsageHistory returns the current CPU usage history
+// GetCPUUsageHistory returns a copy of the CPU usage history
+// A collection in progress appends to the history, so it waits for that collection to finish
 func (collector *CPUMonitorCollector) GetCPUUsageHistory() *CPUUsageHistory {
-	return collector.history
+	collector.collectMutex.Lock()
+	defer collector.collectMutex.Unlock()
+	return collector.history.Clone()
+}
+
+// GetLongTermHistory returns the downsampled long-term history
+func (collector *CPUMonitorCollector) GetLongTermHistory() *historystore.Store {
+	return collector.longHistory
+}
+
+// HistoryMemoryEstimate estimates the memory an in-memory history of samples uses
+// Each sample holds the eight overall series plus one usage value per logical CPU
+func (collector *CPUMonitorCollector) HistoryMemoryEstimate(samples int) uint64 {
+	return historystore.BufferBytes(samples, 8+runtime.NumCPU())
+}
+
+// SetIdle pauses process scans while idle mode is active
+func (collector *CPUMonitorCollector) SetIdle(idle bool) {
+	collector.collectMutex.Lock()
+	defer collector.collectMutex.Unlock()
+	collector.idle = idle
+}
+
+// SetSimulated switches between real system data and synthetic data
+func (collector *CPUMonitorCollector) SetSimulated(enabled bool) {
+	collector.collectMutex.Lock()
+	defer collector.collectMutex.Unlock()
+	if !enabled {
+		collector.simulator = nil
+	} else if collector.simulator == nil {
+		collector.simulator = simulate.NewSource(simulate.DefaultSeed)
+	}
+
+	// Real and synthetic counters are unrelated, so the next fork rate and power reading start over
+	collector.lastForkSample = time.Time{}
+	collector.lastEnergySample = time.Time{}
+	collector.lastPowerSample = time.Time{}
+	collector.energyUsed = 0
 }
 
-// SetConfig updates the collector configuration
-func (collector *CPUMonitorCollector) SetConfig(config *CPUMonitorConfig) {
-	collector.config = config
+// SetProviders replaces the system data sources, e.g. with provider.NewFake() to collect without OS access
+func (collector *CPUMonitorCollector) SetProviders(providers provider.Providers) {
+	collector.collectMutex.Lock()
+	defer collector.collectMutex.Unlock()
+	collector.system = providers
 }
 
-// GetConfig returns the current collector configuration
-func (collector *CPUMonitorCollector) GetConfig() *CPUMonitorConfig {
-	return collector.config
+// IsSimulated returns whether the collector produces synthetic data
+func (collector *CPUMonitorCollector) IsSimulated() bool {
+	collector.collectMutex.Lock()
+	defer collector.collectMutex.Unlock()
+	return collector.simulator != nil
 }
 
 // ResetHistory clears the CPU usage history
 func (collector *CPUMonitorCollector) ResetHistory() {
+	collector.collectMutex.Lock()
+	defer collector.collectMutex.Unlock()
 	collector.history = &CPUUsageHistory{
 		MaxDataPoints:  collector.history.MaxDataPoints,
 		DataPointCount: 0,
@@ -0,0 +1,140 @@
+package cpumonitor
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ahmadreza-log/simple-monitor/alert"
+	"github.com/ahmadreza-log/simple-monitor/provider"
+	"github.com/shirou/gopsutil/v3/cpu"
+)
+
+// newFakeCollector returns a collector reading fake, with alerts raised at once and only the sections
+// backed by the CPU provider enabled
+func newFakeCollector(fake *provider.Fake) *CPUMonitorCollector {
+	collector := NewCPUMonitorCollector()
+	collector.SetProviders(fake.Providers())
+	config := collector.GetConfig()
+	config.ShowProcesses = false
+	config.ShowTemperature = false
+	config.ShowPower = false
+	config.AlertRules = map[string]alert.Rule{}
+	collector.SetConfig(config)
+	return collector
+}
+
+func TestCollectCPUMonitorDataUsageThresholds(t *testing.T) {
+	tests := []struct {
+		name   string
+		usage  float64
+		status string
+	}{
+		{name: "below warning", usage: 42, status: "Normal"},
+		{name: "at warning", usage: 80, status: "Warning"},
+		{name: "above critical", usage: 95, status: "Critical"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := provider.NewFake()
+			fake.CPU.TotalPercent = []float64{tt.usage}
+			collector := newFakeCollector(fake)
+
+			data, err := collector.CollectCPUMonitorData()
+			if err != nil {
+				t.Fatalf("CollectCPUMonitorData() error = %v", err)
+			}
+			if data.OverallUsage != tt.usage {
+				t.Errorf("OverallUsage = %v, want %v", data.OverallUsage, tt.usage)
+			}
+			if data.UsageStatus != tt.status {
+				t.Errorf("UsageStatus = %q, want %q", data.UsageStatus, tt.status)
+			}
+		})
+	}
+}
+
+func TestCollectCPUMonitorDataTimesSplit(t *testing.T) {
+	fake := provider.NewFake()
+	fake.CPU.TotalPercent = []float64{50}
+	fake.CPU.TotalTimes = []cpu.TimesStat{{User: 30, System: 10, Idle: 50, Iowait: 10}}
+	collector := newFakeCollector(fake)
+
+	data, err := collector.CollectCPUMonitorData()
+	if err != nil {
+		t.Fatalf("CollectCPUMonitorData() error = %v", err)
+	}
+	if data.UserUsage != 30 || data.SystemUsage != 10 || data.IdleUsage != 50 || data.IOWaitUsage != 10 {
+		t.Errorf("usage split = user %v, system %v, idle %v, iowait %v, want 30, 10, 50, 10",
+			data.UserUsage, data.SystemUsage, data.IdleUsage, data.IOWaitUsage)
+	}
+}
+
+func TestCollectCPUMonitorDataUsageError(t *testing.T) {
+	fake := provider.NewFake()
+	fake.CPU.Faults["Percent"] = errors.New("permission denied")
+	collector := newFakeCollector(fake)
+
+	if _, err := collector.CollectCPUMonitorData(); err == nil {
+		t.Error("CollectCPUMonitorData() error = nil, want the usage error")
+	}
+}
+
+func TestCollectCPUMonitorDataLoadAverageError(t *testing.T) {
+	fake := provider.NewFake()
+	fake.CPU.TotalPercent = []float64{10}
+	fake.CPU.Faults["LoadAvg"] = errors.New("not supported")
+	collector := newFakeCollector(fake)
+
+	data, err := collector.CollectCPUMonitorData()
+	if err != nil {
+		t.Fatalf("CollectCPUMonitorData() error = %v", err)
+	}
+	if _, failed := data.SectionErrors.Get("load_average"); !failed {
+		t.Errorf("SectionErrors = %v, want a load_average entry", data.SectionErrors)
+	}
+}
+
+func TestCollectCPUMonitorDataHistory(t *testing.T) {
+	tests := []struct {
+		name       string
+		size       int
+		resolution time.Duration
+		want       []float64
+	}{
+		{name: "every refresh", size: 10, want: []float64{10, 20, 30, 40, 50}},
+		{name: "trimmed to size", size: 3, want: []float64{30, 40, 50}},
+		{name: "one sample per resolution", size: 10, resolution: time.Hour, want: []float64{10}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := provider.NewFake()
+			collector := newFakeCollector(fake)
+			config := collector.GetConfig()
+			config.HistorySize = tt.size
+			config.HistoryResolution = tt.resolution
+			config.ShowHistory = true
+			collector.SetConfig(config)
+
+			var data *CPUMonitorData
+			for _, usage := range []float64{10, 20, 30, 40, 50} {
+				fake.CPU.TotalPercent = []float64{usage}
+				var err error
+				if data, err = collector.CollectCPUMonitorData(); err != nil {
+					t.Fatalf("CollectCPUMonitorData() error = %v", err)
+				}
+			}
+
+			history := collector.GetCPUUsageHistory()
+			if !slices.Equal(history.OverallUsage, tt.want) || history.DataPointCount != len(tt.want) || len(history.Timestamps) != len(tt.want) {
+				t.Errorf("history = %v (%d points), want %v", history.OverallUsage, history.DataPointCount, tt.want)
+			}
+			if data.Trend == nil || !slices.Equal(data.Trend.Usage, tt.want) {
+				t.Errorf("Trend = %+v, want usage %v", data.Trend, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package cpumonitor
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ahmadreza-log/simple-monitor/monitortest"
+	"github.com/ahmadreza-log/simple-monitor/provider"
+)
+
+// collect returns a function running one collection of collector, for monitortest
+func collect(collector *CPUMonitorCollector) func() error {
+	return func() error {
+		_, err := collector.CollectCPUMonitorData()
+		return err
+	}
+}
+
+// TestConfigUpdatesDuringCollections changes the configuration while several collections run, the way the
+// settings menu does while the live loop, a subscriber and the attach server collect; run it with -race
+func TestConfigUpdatesDuringCollections(t *testing.T) {
+	fake := provider.NewFake()
+	fake.CPU.TotalPercent = []float64{50}
+	collector := newFakeCollector(fake)
+
+	monitortest.DuringCollections(t, collect(collector), func(i int) {
+		if i%2 == 0 {
+			config := collector.GetConfig()
+			config.RefreshInterval = time.Duration(i) * time.Second
+			collector.SetConfig(config)
+		} else {
+			collector.changeConfig(func(config *CPUMonitorConfig) {
+				config.RefreshInterval = time.Duration(i) * time.Second
+			})
+		}
+	})
+
+	data, err := collector.CollectCPUMonitorData()
+	if err != nil {
+		t.Fatalf("CollectCPUMonitorData() error = %v", err)
+	}
+	if data.RefreshInterval != monitortest.Rounds*time.Second {
+		t.Errorf("RefreshInterval = %v, want the last update (%ds)", data.RefreshInterval, monitortest.Rounds)
+	}
+}
+
+// TestHistoryAndSettersDuringCollections reads the history and switches idle mode, simulated data and providers
+// while several collections run, the way the managers do; run it with -race
+func TestHistoryAndSettersDuringCollections(t *testing.T) {
+	fake := provider.NewFake()
+	fake.CPU.TotalPercent = []float64{50}
+	collector := newFakeCollector(fake)
+
+	monitortest.DuringCollections(t, collect(collector), func(i int) {
+		history := collector.GetCPUUsageHistory()
+		for j := range history.OverallUsage {
+			history.OverallUsage[j] = -1
+		}
+		collector.SetIdle(i%2 == 0)
+		collector.SetSimulated(i%4 == 0 && i < monitortest.Rounds)
+		collector.SetProviders(fake.Providers())
+		collector.ResetHistory()
+	})
+
+	if collector.IsSimulated() {
+		t.Errorf("IsSimulated() = true after the last SetSimulated(false)")
+	}
+	if _, err := collector.CollectCPUMonitorData(); err != nil {
+		t.Fatalf("CollectCPUMonitorData() error = %v", err)
+	}
+	history := collector.GetCPUUsageHistory()
+	if len(history.OverallUsage) == 0 {
+		t.Fatal("GetCPUUsageHistory() is empty after a collection")
+	}
+	history.OverallUsage[0] = -1
+	if slices.Contains(collector.GetCPUUsageHistory().OverallUsage, -1) {
+		t.Errorf("GetCPUUsageHistory().OverallUsage = %v, want changes to the returned copy kept out of the collector", collector.GetCPUUsageHistory().OverallUsage)
+	}
+}
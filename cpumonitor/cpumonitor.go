@@ -1,9 +1,34 @@
+// Package cpumonitor monitors CPU usage, frequencies, temperatures, load, top processes and package power
+//
+// A CPUMonitorManager collects once with Collect or in the background with Subscribe; the live display,
+// exports and alerts of the simple-monitor binary run through StartLiveMonitoring:
+//
+//	manager := cpumonitor.NewCPUMonitorManager(
+//		cpumonitor.WithRefreshInterval(2*time.Second),
+//		cpumonitor.WithThresholds(70, 90),
+//	)
+//	updates, stop := manager.Subscribe(0)
+//	defer stop()
+//	for data := range updates {
+//		fmt.Printf("%.1f%%\n", data.OverallUsage)
+//	}
 package cpumonitor
 
 import (
+	"encoding/json"
 	"fmt"
+	"github.com/ahmadreza-log/simple-monitor/alert"
+	"github.com/ahmadreza-log/simple-monitor/historystore"
+	"github.com/ahmadreza-log/simple-monitor/hooks"
+	"github.com/ahmadreza-log/simple-monitor/idle"
+	"github.com/ahmadreza-log/simple-monitor/overlay"
+	"github.com/ahmadreza-log/simple-monitor/recording"
+	"github.com/ahmadreza-log/simple-monitor/terminal"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 )
@@ -21,18 +46,40 @@ type CPUMonitorManager struct {
 	stopChannel    chan bool
 	refreshTicker  *time.Ticker
 	lastExportTime time.Time
+
+	// Extra line shown under the live display (e.g. key shortcuts)
+	liveHint string
+
+	// Pause and step control (the display stays frozen while paused)
+	paused      atomic.Bool
+	stepChannel chan bool
+
+	// Session recorder (nil unless the live session is being recorded)
+	recorder *recording.Recorder
+
+	// Idle detection (slows refreshes while nobody is using the machine)
+	idleDetector *idle.Detector
+
+	// Series imported with `simple-monitor overlay import`, drawn under the history graph
+	overlaySource overlay.Source
 }
 
 // NewCPUMonitorManager creates a new instance of CPUMonitorManager
-// with default collector, displayer, and exporter configurations
-func NewCPUMonitorManager() *CPUMonitorManager {
-	return &CPUMonitorManager{
-		collector:   NewCPUMonitorCollector(),
-		displayer:   NewCPUMonitorDisplayer(),
-		exporter:    NewCPUMonitorExporter(),
-		isRunning:   false,
-		stopChannel: make(chan bool, 1),
+// with default collector, displayer, and exporter configurations, then applies the options in order
+func NewCPUMonitorManager(options ...Option) *CPUMonitorManager {
+	manager := &CPUMonitorManager{
+		collector:    NewCPUMonitorCollector(),
+		displayer:    NewCPUMonitorDisplayer(),
+		exporter:     NewCPUMonitorExporter(),
+		idleDetector: idle.NewDetector(),
+		isRunning:    false,
+		stopChannel:  make(chan bool, 1),
+		stepChannel:  make(chan bool, 1),
 	}
+	for _, option := range options {
+		option(manager)
+	}
+	return manager
 }
 
 // StartLiveMonitoring starts live CPU monitoring with real-time updates
@@ -43,7 +90,7 @@ func (manager *CPUMonitorManager) StartLiveMonitoring() error {
 	}
 
 	manager.isRunning = true
-	manager.refreshTicker = time.NewTicker(manager.collector.config.RefreshInterval)
+	manager.refreshTicker = time.NewTicker(manager.collector.currentConfig().RefreshInterval)
 
 	// Set up signal handling for graceful shutdown
 	sigChan := make(chan os.Signal, 1)
@@ -53,12 +100,19 @@ func (manager *CPUMonitorManager) StartLiveMonitoring() error {
 	fmt.Println("Press Ctrl+C to stop monitoring")
 
 	// Start monitoring loop
+	loopDone := make(chan bool)
+	loopExited := make(chan bool)
 	go func() {
+		defer close(loopExited)
 		for {
 			select {
 			case <-manager.refreshTicker.C:
+				if !manager.paused.Load() {
+					manager.updateAndDisplay()
+				}
+			case <-manager.stepChannel:
 				manager.updateAndDisplay()
-			case <-manager.stopChannel:
+			case <-loopDone:
 				return
 			case <-sigChan:
 				manager.StopMonitoring()
@@ -68,10 +122,21 @@ func (manager *CPUMonitorManager) StartLiveMonitoring() error {
 	}()
 
 	// Wait for stop signal
+	// The loop is stopped separately so StopMonitoring can also be called from outside the loop,
+	// and a refresh still in progress finishes before returning
 	<-manager.stopChannel
+	close(loopDone)
+	<-loopExited
+	signal.Stop(sigChan)
 	return nil
 }
 
+// display shows data with the overlay series imported in the logs directory, picking up a new import
+func (manager *CPUMonitorManager) display(data *CPUMonitorData) {
+	manager.displayer.Overlay = manager.overlaySource.Series(filepath.Join(manager.exporter.LogsDirectory, overlay.File))
+	manager.displayer.DisplayCPUMonitorData(data)
+}
+
 // StartSingleSnapshot displays a single snapshot of CPU information
 func (manager *CPUMonitorManager) StartSingleSnapshot() error {
 	fmt.Println("📊 Collecting CPU information...")
@@ -83,13 +148,14 @@ func (manager *CPUMonitorManager) StartSingleSnapshot() error {
 	}
 
 	// Display CPU data
-	manager.displayer.DisplayCPUMonitorData(data)
+	manager.display(data)
 
 	// Always export to file for CPU monitor
 	filePath, err := manager.exporter.ExportToJSON(data, "cpumonitor")
 	if err != nil {
 		fmt.Printf("⚠️  Warning: Failed to export data: %v\n", err)
 	} else {
+		hooks.Exported("cpu", filePath)
 		fmt.Printf("\n💾 CPU data saved to: %s\n", filePath)
 	}
 
@@ -108,6 +174,9 @@ func (manager *CPUMonitorManager) StopMonitoring() {
 		manager.refreshTicker.Stop()
 	}
 
+	// Keep everything collected in this session
+	manager.persistHistory(true)
+
 	select {
 	case manager.stopChannel <- true:
 	default:
@@ -118,6 +187,12 @@ func (manager *CPUMonitorManager) StopMonitoring() {
 
 // updateAndDisplay collects new data and updates the display
 func (manager *CPUMonitorManager) updateAndDisplay() {
+	// Refresh less often and pause process scans while the machine is idle
+	if !manager.idleDetector.ShouldCollect() {
+		return
+	}
+	manager.collector.SetIdle(manager.idleDetector.IsIdle())
+
 	// Collect new CPU data
 	data, err := manager.collector.CollectCPUMonitorData()
 	if err != nil {
@@ -127,7 +202,27 @@ func (manager *CPUMonitorManager) updateAndDisplay() {
 	}
 
 	// Display updated data
-	manager.displayer.DisplayCPUMonitorData(data)
+	manager.display(data)
+	if status := manager.idleDetector.Status(); status != "" {
+		fmt.Println("\n" + status)
+	}
+	if status := alert.Status(); status != "" {
+		fmt.Println("\n" + status)
+	}
+	if manager.liveHint != "" {
+		fmt.Println(manager.liveHint)
+	}
+	if manager.paused.Load() {
+		fmt.Println(terminal.PausedNotice)
+	}
+
+	// Capture the cycle for replay
+	if err := manager.recorder.Record("cpu", data); err != nil {
+		fmt.Printf("\n⚠️  %v\n", err)
+	}
+
+	// Save the long-term history
+	manager.persistHistory(false)
 
 	// Export data based on export interval
 	manager.exportDataIfNeeded(data)
@@ -136,13 +231,13 @@ func (manager *CPUMonitorManager) updateAndDisplay() {
 // exportDataIfNeeded exports CPU data to file based on export interval
 func (manager *CPUMonitorManager) exportDataIfNeeded(data *CPUMonitorData) {
 	// Check if export is enabled
-	if !manager.collector.config.ExportToFile {
+	if !manager.collector.currentConfig().ExportToFile {
 		return
 	}
 
 	// Check if it's time to export based on export interval
 	now := time.Now()
-	if manager.lastExportTime.IsZero() || now.Sub(manager.lastExportTime) >= manager.collector.config.ExportInterval {
+	if manager.lastExportTime.IsZero() || now.Sub(manager.lastExportTime) >= manager.collector.currentConfig().ExportInterval {
 		manager.exportData(data)
 		manager.lastExportTime = now
 	}
@@ -150,23 +245,60 @@ func (manager *CPUMonitorManager) exportDataIfNeeded(data *CPUMonitorData) {
 
 // exportData exports CPU data to file
 func (manager *CPUMonitorManager) exportData(data *CPUMonitorData) {
-	filePath, err := manager.exporter.ExportToJSON(data, "cpumonitor")
+	export := manager.exporter.ExportToJSON
+	if manager.collector.currentConfig().ExportFormat == "protobuf" {
+		export = manager.exporter.ExportToProtobuf
+	}
+	filePath, err := export(data, "cpumonitor")
 	if err != nil {
 		// Don't display error for every export to avoid cluttering the display
 		return
 	}
 
+	hooks.Exported("cpu", filePath)
 	fmt.Printf("\n💾 Data exported to: %s\n", filePath)
 }
 
-// GetCPUUsageHistory returns the current CPU usage history
+// GetCPUUsageHistory returns a copy of the CPU usage history
 func (manager *CPUMonitorManager) GetCPUUsageHistory() *CPUUsageHistory {
 	return manager.collector.GetCPUUsageHistory()
 }
 
+// GetLongTermHistory returns the downsampled long-term history
+func (manager *CPUMonitorManager) GetLongTermHistory() *historystore.Store {
+	return manager.collector.GetLongTermHistory()
+}
+
+// HistoryMemoryEstimate estimates the memory an in-memory history of samples uses
+func (manager *CPUMonitorManager) HistoryMemoryEstimate(samples int) uint64 {
+	return manager.collector.HistoryMemoryEstimate(samples)
+}
+
+// persistHistory writes the long-term history to logs/history
+// Unless forced (when monitoring stops), the file is rewritten at most once per save interval
+func (manager *CPUMonitorManager) persistHistory(force bool) {
+	config := manager.collector.currentConfig()
+	// Synthetic history must not end up in the real history file
+	if !config.PersistHistory || manager.collector.IsSimulated() {
+		return
+	}
+
+	interval := config.HistorySaveInterval
+	if force {
+		interval = 0
+	}
+
+	path := filepath.Join(manager.exporter.LogsDirectory, "history", "cpumonitor.json")
+
+	// Errors are ignored to avoid cluttering the display; the next save retries
+	manager.collector.GetLongTermHistory().Persist(path, interval)
+}
+
 // SetRefreshInterval sets the refresh interval for live monitoring
 func (manager *CPUMonitorManager) SetRefreshInterval(interval time.Duration) {
-	manager.collector.config.RefreshInterval = interval
+	manager.collector.changeConfig(func(config *CPUMonitorConfig) {
+		config.RefreshInterval = interval
+	})
 	if manager.refreshTicker != nil {
 		manager.refreshTicker.Stop()
 		manager.refreshTicker = time.NewTicker(interval)
@@ -175,14 +307,18 @@ func (manager *CPUMonitorManager) SetRefreshInterval(interval time.Duration) {
 
 // SetMaxProcesses sets the maximum number of processes to display
 func (manager *CPUMonitorManager) SetMaxProcesses(max int) {
-	manager.collector.config.MaxProcesses = max
+	manager.collector.changeConfig(func(config *CPUMonitorConfig) {
+		config.MaxProcesses = max
+	})
 	manager.displayer.SetMaxProcesses(max)
 }
 
 // SetTemperatureThresholds sets the temperature warning and critical thresholds
 func (manager *CPUMonitorManager) SetTemperatureThresholds(warning, critical float64) {
-	manager.collector.config.TemperatureWarning = warning
-	manager.collector.config.TemperatureCritical = critical
+	manager.collector.changeConfig(func(config *CPUMonitorConfig) {
+		config.TemperatureWarning = warning
+		config.TemperatureCritical = critical
+	})
 }
 
 // SetDisplayOptions configures the display options
@@ -194,21 +330,128 @@ func (manager *CPUMonitorManager) SetDisplayOptions(showGraphics, showColors boo
 
 // SetExportOptions configures the export options
 func (manager *CPUMonitorManager) SetExportOptions(exportToFile bool, exportInterval time.Duration, exportFormat string) {
-	manager.collector.config.ExportToFile = exportToFile
-	manager.collector.config.ExportInterval = exportInterval
-	manager.collector.config.ExportFormat = exportFormat
+	manager.collector.changeConfig(func(config *CPUMonitorConfig) {
+		config.ExportToFile = exportToFile
+		config.ExportInterval = exportInterval
+		config.ExportFormat = exportFormat
+	})
+}
+
+// SetIdleMode enables or disables idle mode
+// While idle, refreshes are slowed down and process scans are paused until activity resumes
+func (manager *CPUMonitorManager) SetIdleMode(enabled bool) {
+	manager.idleDetector.SetEnabled(enabled)
+	if !enabled {
+		manager.collector.SetIdle(false)
+	}
+}
+
+// SetSimulationMode switches the monitor to synthetic data (--simulate)
+// Simulated data needs no live system or elevated permissions, which suits demos and displayer development
+func (manager *CPUMonitorManager) SetSimulationMode(enabled bool) {
+	manager.collector.SetSimulated(enabled)
+}
+
+// IsSimulated returns whether the monitor shows synthetic data
+func (manager *CPUMonitorManager) IsSimulated() bool {
+	return manager.collector.IsSimulated()
 }
 
-// GetCurrentData returns the current CPU monitoring data
-func (manager *CPUMonitorManager) GetCurrentData() (*CPUMonitorData, error) {
+// GetIdleState returns the most recent idle check
+func (manager *CPUMonitorManager) GetIdleState() idle.State {
+	return manager.idleDetector.State()
+}
+
+// GetIdleConfig returns the idle detection configuration
+func (manager *CPUMonitorManager) GetIdleConfig() idle.Config {
+	return manager.idleDetector.GetConfig()
+}
+
+// Collect collects the CPU data once, without displaying or exporting it
+func (manager *CPUMonitorManager) Collect() (*CPUMonitorData, error) {
 	return manager.collector.CollectCPUMonitorData()
 }
 
+// Subscribe collects every interval in the background and sends the data on the returned channel until the
+// returned function is called, which closes it; a zero interval uses the configured refresh interval
+// Nothing is displayed, exported or recorded. A subscriber that falls behind only gets the newest data,
+// and failed collections are skipped (Collect returns their error)
+func (manager *CPUMonitorManager) Subscribe(interval time.Duration) (<-chan *CPUMonitorData, func()) {
+	if interval <= 0 {
+		interval = manager.collector.currentConfig().RefreshInterval
+	}
+	updates := make(chan *CPUMonitorData, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(updates)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if data, err := manager.Collect(); err == nil {
+				select {
+				case <-updates:
+				default:
+				}
+				updates <- data
+			}
+			select {
+			case <-ticker.C:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var stop sync.Once
+	return updates, func() {
+		stop.Do(func() { close(done) })
+	}
+}
+
 // IsRunning returns whether the monitoring is currently running
 func (manager *CPUMonitorManager) IsRunning() bool {
 	return manager.isRunning
 }
 
+// IsEnabled returns whether the monitor is switched on in its configuration
+func (manager *CPUMonitorManager) IsEnabled() bool {
+	return manager.collector.currentConfig().Enabled
+}
+
+// SetLiveHint sets a line shown under each live refresh, or clears it when empty
+func (manager *CPUMonitorManager) SetLiveHint(hint string) {
+	manager.liveHint = hint
+}
+
+// SetPaused freezes the live display on its last refresh, or resumes refreshing
+// Nothing is collected while paused, so history, exports and alerts wait too
+func (manager *CPUMonitorManager) SetPaused(paused bool) {
+	manager.paused.Store(paused)
+}
+
+// Step collects and shows one refresh, for stepping through a paused display
+func (manager *CPUMonitorManager) Step() {
+	select {
+	case manager.stepChannel <- true:
+	default:
+	}
+}
+
+// SetRecorder records every live collection cycle to recorder (nil stops recording)
+func (manager *CPUMonitorManager) SetRecorder(recorder *recording.Recorder) {
+	manager.recorder = recorder
+}
+
+// DisplayRecordedData shows one recorded collection cycle the way the live display showed it
+func (manager *CPUMonitorManager) DisplayRecordedData(content []byte) error {
+	var data CPUMonitorData
+	if err := json.Unmarshal(content, &data); err != nil {
+		return fmt.Errorf("failed to decode recorded CPU data: %w", err)
+	}
+	manager.display(&data)
+	return nil
+}
+
 // ResetHistory clears the CPU usage history
 func (manager *CPUMonitorManager) ResetHistory() {
 	manager.collector.ResetHistory()
@@ -226,11 +469,11 @@ func (manager *CPUMonitorManager) SetConfiguration(config *CPUMonitorConfig) {
 
 // StartContinuousExport starts continuous export of CPU data
 func (manager *CPUMonitorManager) StartContinuousExport() error {
-	if !manager.collector.config.ExportToFile {
+	if !manager.collector.currentConfig().ExportToFile {
 		return fmt.Errorf("export is not enabled")
 	}
 
-	exportTicker := time.NewTicker(manager.collector.config.ExportInterval)
+	exportTicker := time.NewTicker(manager.collector.currentConfig().ExportInterval)
 
 	go func() {
 		for {
@@ -2,18 +2,30 @@ package cpumonitor
 
 import (
 	"fmt"
+	"github.com/ahmadreza-log/simple-monitor/listening"
+	"github.com/ahmadreza-log/simple-monitor/overlay"
+	"github.com/ahmadreza-log/simple-monitor/partial"
+	"github.com/ahmadreza-log/simple-monitor/terminal"
 	"strings"
+	"time"
 )
 
+// coreLabelWidth is the label column width in the per-core grid ("Core 127 (HT)")
+const coreLabelWidth = 13
+
 // CPUMonitorDisplayer handles the display and formatting of CPU monitoring data
 // This struct provides methods to format and display CPU data with graphical elements
 type CPUMonitorDisplayer struct {
 	// Display configuration
 	ShowGraphics bool // Whether to show graphical elements
 	ShowColors   bool // Whether to use colored output
-	BarWidth     int  // Width of progress bars
+	BarWidth     int  // Width of progress bars (when AutoWidth is off)
+	AutoWidth    bool // Whether to fit bars, separators and columns to the terminal width
 	MaxProcesses int  // Maximum number of processes to display
 
+	// Imported series drawn under the history graph (nil when none is imported)
+	Overlay *overlay.Series
+
 	// Color codes for different elements
 	ColorReset   string
 	ColorRed     string
@@ -33,6 +45,7 @@ func NewCPUMonitorDisplayer() *CPUMonitorDisplayer {
 		ShowGraphics: true,
 		ShowColors:   true,
 		BarWidth:     50,
+		AutoWidth:    true,
 		MaxProcesses: 10,
 		ColorReset:   "\033[0m",
 		ColorRed:     "\033[31m",
@@ -57,26 +70,52 @@ func (displayer *CPUMonitorDisplayer) DisplayCPUMonitorData(data *CPUMonitorData
 	// Display overall CPU usage with graphics
 	displayer.displayOverallUsage(data)
 
+	// Display recent usage with the imported overlay series under it
+	if displayer.ShowGraphics && data.Trend != nil && len(data.Trend.Usage) > 1 {
+		displayer.displayHistory(data.Trend)
+	}
+
 	// Display per-core information
-	if len(data.Cores) > 0 {
+	if reason, failed := data.SectionErrors.Get("cores"); failed {
+		displayer.displayUnavailable("🔧 PER-CORE USAGE", reason)
+	} else if len(data.Cores) > 0 {
 		displayer.displayCoreInfo(data)
 	}
 
 	// Display temperature information
-	if data.Temperature > 0 {
+	if reason, failed := data.SectionErrors.Get("temperature"); failed {
+		displayer.displayUnavailable("🌡️  TEMPERATURE", reason)
+	} else if data.Temperature > 0 {
 		displayer.displayTemperatureInfo(data)
 	}
 
+	// Display package power
+	if reason, failed := data.SectionErrors.Get("power"); failed {
+		displayer.displayUnavailable("🔌 POWER", reason)
+	} else if data.Power != nil {
+		displayer.displayPowerInfo(data.Power)
+	}
+
 	// Display load average
-	if data.LoadAverage1Min > 0 || data.LoadAverage5Min > 0 || data.LoadAverage15Min > 0 {
+	if reason, failed := data.SectionErrors.Get("load_average"); failed {
+		displayer.displayUnavailable("📈 LOAD AVERAGE", reason)
+	} else if data.LoadAverage1Min > 0 || data.LoadAverage5Min > 0 || data.LoadAverage15Min > 0 ||
+		data.RunQueueLength > 0 || data.BlockedTasks > 0 || data.ProcessesCreated > 0 {
 		displayer.displayLoadAverage(data)
 	}
 
 	// Display top processes
-	if len(data.TopProcesses) > 0 {
+	if reason, failed := data.SectionErrors.Get("processes"); failed {
+		displayer.displayUnavailable("⚙️  TOP PROCESSES", reason)
+	} else if len(data.TopProcesses) > 0 {
 		displayer.displayTopProcesses(data)
 	}
 
+	// Display runbook notes of raised alerts
+	if len(data.AlertNotes) > 0 {
+		displayer.displayAlertNotes(data)
+	}
+
 	// Display footer
 	displayer.displayFooter(data)
 }
@@ -84,12 +123,21 @@ func (displayer *CPUMonitorDisplayer) DisplayCPUMonitorData(data *CPUMonitorData
 // displayHeader displays the CPU monitor header
 func (displayer *CPUMonitorDisplayer) displayHeader(data *CPUMonitorData) {
 	fmt.Println(displayer.colorize("🖥️  CPU MONITOR", displayer.ColorBold+displayer.ColorCyan))
-	fmt.Println(strings.Repeat("=", 80))
+	fmt.Println(displayer.rule("="))
+
+	// Synthetic data must never be mistaken for a real system
+	if data.Simulated {
+		fmt.Println(displayer.colorize("🧪 Simulated data (--simulate)", displayer.ColorMagenta))
+	}
 
 	// CPU model and basic info
+	modelName := data.ModelName
+	if reason, failed := data.SectionErrors.Get("cpu_info"); failed {
+		modelName = partial.Unavailable(reason)
+	}
 	fmt.Printf("%sCPU Model: %s%s\n",
 		displayer.colorize("", displayer.ColorBold),
-		displayer.colorize(data.ModelName, displayer.ColorWhite),
+		displayer.colorize(modelName, displayer.ColorWhite),
 		displayer.colorize("", displayer.ColorReset))
 
 	fmt.Printf("%sArchitecture: %s%s\n",
@@ -104,17 +152,90 @@ func (displayer *CPUMonitorDisplayer) displayHeader(data *CPUMonitorData) {
 		data.LogicalCores,
 		displayer.colorize("", displayer.ColorReset))
 
-	fmt.Println(strings.Repeat("=", 80))
+	if reason, failed := data.SectionErrors.Get("topology"); failed {
+		fmt.Printf("%sTopology: %s\n", displayer.colorize("", displayer.ColorBold), displayer.colorize(partial.Unavailable(reason), displayer.ColorYellow))
+	} else if data.Topology != nil {
+		displayer.displayTopology(data.Topology)
+	}
+
+	fmt.Println(displayer.rule("="))
+}
+
+// displayTopology displays the socket/core layout, SMT status and cache sizes
+func (displayer *CPUMonitorDisplayer) displayTopology(topology *CPUTopology) {
+	layout := fmt.Sprintf("%d socket(s) x %d cores per socket x %d thread(s) per core",
+		topology.Sockets, topology.Cores/max(topology.Sockets, 1), topology.ThreadsPerCore)
+	if topology.Estimated {
+		layout += " (estimated from core counts)"
+	}
+	fmt.Printf("%sTopology: %s\n", displayer.colorize("", displayer.ColorBold), displayer.colorize(layout, displayer.ColorWhite))
+
+	smt := "off"
+	smtColor := displayer.ColorWhite
+	if topology.SMTEnabled {
+		smt = "on"
+		smtColor = displayer.ColorGreen
+	}
+	if topology.SMTControl != "" && topology.SMTControl != smt {
+		smt += " (kernel control: " + topology.SMTControl + ")"
+	}
+	fmt.Printf("%sSMT: %s\n", displayer.colorize("", displayer.ColorBold), displayer.colorize(smt, smtColor))
+
+	if len(topology.Caches) > 0 {
+		var caches []string
+		for _, cache := range topology.Caches {
+			text := cacheLabel(cache) + " " + formatCacheSize(cache.SizeBytes)
+			if cache.Instances > 1 {
+				text += fmt.Sprintf(" x%d", cache.Instances)
+			}
+			caches = append(caches, text)
+		}
+		fmt.Printf("%sCaches: %s\n", displayer.colorize("", displayer.ColorBold), displayer.colorize(strings.Join(caches, ", "), displayer.ColorWhite))
+	}
+}
+
+// cacheLabel names a cache the way CPU spec sheets do: L1d, L1i, L2, L3
+func cacheLabel(cache CPUCacheInfo) string {
+	label := fmt.Sprintf("L%d", cache.Level)
+	switch cache.Type {
+	case "Data":
+		label += "d"
+	case "Instruction":
+		label += "i"
+	}
+	return label
+}
+
+// formatCacheSize formats a cache size in KiB or MiB
+func formatCacheSize(bytes uint64) string {
+	if bytes >= 1024*1024 {
+		return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.2f", float64(bytes)/(1024*1024)), "0"), ".") + " MiB"
+	}
+	return fmt.Sprintf("%d KiB", bytes/1024)
 }
 
 // displayOverallUsage displays overall CPU usage with graphical bars
 func (displayer *CPUMonitorDisplayer) displayOverallUsage(data *CPUMonitorData) {
 	fmt.Println("\n📊 OVERALL CPU USAGE")
-	fmt.Println(strings.Repeat("-", 50))
+	fmt.Println(displayer.sectionRule())
 
 	// Overall usage bar
 	displayer.displayUsageBar("Overall", data.OverallUsage, displayer.getUsageColor(data.OverallUsage))
 
+	// Usage alert status, naming the threshold schedule in effect
+	if data.UsageStatus != "" {
+		schedule := ""
+		if data.UsageSchedule != "" {
+			schedule = displayer.colorize(" (schedule: "+data.UsageSchedule+")", displayer.ColorCyan)
+		}
+		fmt.Printf("%sStatus: %s%s%s%s\n",
+			displayer.colorize("", displayer.ColorBold),
+			displayer.getTemperatureStatusColor(data.UsageStatus),
+			data.UsageStatus,
+			displayer.colorize("", displayer.ColorReset),
+			schedule)
+	}
+
 	// Detailed breakdown
 	fmt.Printf("\n%sUser Processes: %s%.2f%%%s\n",
 		displayer.colorize("", displayer.ColorBold),
@@ -146,12 +267,21 @@ func (displayer *CPUMonitorDisplayer) displayOverallUsage(data *CPUMonitorData)
 // displayCoreInfo displays per-core CPU usage information
 func (displayer *CPUMonitorDisplayer) displayCoreInfo(data *CPUMonitorData) {
 	fmt.Println("\n🔧 PER-CORE USAGE")
-	fmt.Println(strings.Repeat("-", 50))
+	fmt.Println(displayer.sectionRule())
+
+	// Display cores in a grid layout, as many columns as fit the terminal
+	barWidth := terminal.Fit(displayer.barWidth()/2, 10, 25)
+	cellWidth := coreLabelWidth + barWidth + 11
+	if !displayer.ShowColors || terminal.ColorBlindMode() {
+		cellWidth += len(terminal.SeverityMarker(terminal.SeverityWarning))
+	}
+	coresPerRow := terminal.Fit((displayer.lineWidth()+2)/(cellWidth+2), 1, 8)
+	if !displayer.AutoWidth {
+		coresPerRow = 4
+	}
 
-	// Display cores in a grid layout
-	coresPerRow := 4
 	for i := 0; i < len(data.Cores); i += coresPerRow {
-		fmt.Printf("\n")
+		var cells []string
 		for j := 0; j < coresPerRow && i+j < len(data.Cores); j++ {
 			core := data.Cores[i+j]
 			coreLabel := fmt.Sprintf("Core %d", core.CoreID)
@@ -159,17 +289,41 @@ func (displayer *CPUMonitorDisplayer) displayCoreInfo(data *CPUMonitorData) {
 				coreLabel += " (HT)"
 			}
 
-			// Shortened bar for grid display
-			shortBarWidth := displayer.BarWidth / 2
-			displayer.displayUsageBar(coreLabel, core.UsagePercent, displayer.getUsageColor(core.UsagePercent), shortBarWidth)
+			cells = append(cells, displayer.formatCoreCell(coreLabel, core.UsagePercent, barWidth))
 		}
+		fmt.Println(strings.Join(cells, "  "))
+	}
+}
+
+// formatCoreCell formats one fixed-width cell of the per-core grid
+func (displayer *CPUMonitorDisplayer) formatCoreCell(label string, percentage float64, width int) string {
+	color := displayer.getUsageColor(percentage)
+
+	filledWidth := int((percentage / 100.0) * float64(width))
+	if filledWidth > width {
+		filledWidth = width
+	}
+	if filledWidth < 0 {
+		filledWidth = 0
 	}
+	bar := strings.Repeat("█", filledWidth) + strings.Repeat("░", width-filledWidth)
+
+	return fmt.Sprintf("%s%-*s %s[%s]%s %s%6.2f%%%s",
+		displayer.colorize("", displayer.ColorBold),
+		coreLabelWidth,
+		label,
+		displayer.colorize("", displayer.ColorWhite),
+		displayer.colorize(bar, terminal.WithoutMarker(color)),
+		displayer.colorize("", displayer.ColorWhite),
+		color,
+		percentage,
+		displayer.colorize("", displayer.ColorReset))
 }
 
 // displayTemperatureInfo displays CPU temperature information
 func (displayer *CPUMonitorDisplayer) displayTemperatureInfo(data *CPUMonitorData) {
 	fmt.Println("\n🌡️  TEMPERATURE")
-	fmt.Println(strings.Repeat("-", 50))
+	fmt.Println(displayer.sectionRule())
 
 	// Temperature bar
 	tempPercent := (data.Temperature / data.MaxTemperature) * 100
@@ -198,10 +352,110 @@ func (displayer *CPUMonitorDisplayer) displayTemperatureInfo(data *CPUMonitorDat
 		displayer.colorize("", displayer.ColorReset))
 }
 
+// sparkLevels are the bar heights of a history graph, lowest first
+var sparkLevels = []rune("▁▂▃▄▅▆▇█")
+
+// displayHistory displays a graph of recent usage and, on the same time axis under it, the imported overlay series
+func (displayer *CPUMonitorDisplayer) displayHistory(trend *CPUUsageTrend) {
+	fmt.Println("\n📉 HISTORY")
+	fmt.Println(displayer.sectionRule())
+
+	width := terminal.Fit(displayer.lineWidth()-22, 10, len(trend.Usage))
+	fmt.Printf("%-8s %s 100%%\n", "Usage", displayer.colorize(sparkline(trend.Usage, 100, width), displayer.ColorCyan))
+	if series := displayer.Overlay; series != nil {
+		label := terminal.Truncate(series.Name, 8)
+		if line, peak, ok := series.Line(trend.Timestamps, width); ok {
+			fmt.Printf("%-8s %s %s max\n", label, displayer.colorize(line, displayer.ColorMagenta), overlay.Format(peak))
+		} else {
+			fmt.Printf("%-8s %s\n", label, displayer.colorize(fmt.Sprintf("no samples in this range (imported %s to %s)",
+				series.Start().Format("2006-01-02 15:04"), series.End().Format("2006-01-02 15:04")), displayer.ColorWhite))
+		}
+	}
+	start := trend.Timestamps[max(len(trend.Timestamps)-width, 0)]
+	fmt.Printf("%-8s %s to %s\n", "", start.Format("15:04:05"), trend.Timestamps[len(trend.Timestamps)-1].Format("15:04:05"))
+}
+
+// displayPowerInfo displays package power, the energy used and a graph of recent power next to usage
+func (displayer *CPUMonitorDisplayer) displayPowerInfo(power *CPUPowerInfo) {
+	fmt.Println("\n🔌 POWER")
+	fmt.Println(displayer.sectionRule())
+
+	label := "Package Power"
+	if power.Source == powerSourceBattery {
+		// The discharge rate covers the screen, disks and everything else as well
+		label = "System Power"
+	}
+	if !power.Measured {
+		fmt.Printf("%s%s: %s\n",
+			displayer.colorize("", displayer.ColorBold),
+			label,
+			displayer.colorize("measuring (shown from the next refresh)", displayer.ColorWhite))
+	} else {
+		fmt.Printf("%s%s: %s%.1f W%s (peak %.1f W)\n",
+			displayer.colorize("", displayer.ColorBold),
+			label,
+			displayer.colorize("", displayer.ColorMagenta),
+			power.PackageWatts,
+			displayer.colorize("", displayer.ColorReset),
+			power.PeakWatts)
+	}
+	fmt.Printf("%sEnergy Used: %s%.2f Wh%s since monitoring started\n",
+		displayer.colorize("", displayer.ColorBold),
+		displayer.colorize("", displayer.ColorCyan),
+		power.EnergyWh,
+		displayer.colorize("", displayer.ColorReset))
+	fmt.Printf("%sSource: %s%s\n",
+		displayer.colorize("", displayer.ColorBold),
+		displayer.colorize("", displayer.ColorReset),
+		powerSourceName(power.Source))
+
+	// A single package is the total above; with more domains, each shows where the power goes
+	if power.Measured && len(power.Domains) > 1 {
+		for _, domain := range power.Domains {
+			fmt.Printf("  %-20s %6.1f W\n", domain.Name, domain.Watts)
+		}
+	}
+
+	if displayer.ShowGraphics && len(power.Recent) > 1 {
+		width := terminal.Fit(displayer.lineWidth()-22, 10, len(power.Recent))
+		fmt.Printf("\n%-8s %s %.0f W max\n", "Power", displayer.colorize(sparkline(power.Recent, power.PeakWatts, width), displayer.ColorMagenta), power.PeakWatts)
+		fmt.Printf("%-8s %s 100%%\n", "Usage", displayer.colorize(sparkline(power.RecentUsage, 100, width), displayer.ColorCyan))
+	}
+}
+
+// powerSourceName describes where power readings come from
+func powerSourceName(source string) string {
+	switch source {
+	case powerSourceRAPL:
+		return "RAPL energy counters"
+	case powerSourceAMDEnergy:
+		return "amd_energy counters"
+	case powerSourceBattery:
+		return "battery discharge rate (whole system, no RAPL counters readable)"
+	}
+	return source
+}
+
+// sparkline draws the last width values as one line of bars scaled to top
+func sparkline(values []float64, top float64, width int) string {
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+	var builder strings.Builder
+	for _, value := range values {
+		level := 0
+		if top > 0 {
+			level = int(value / top * float64(len(sparkLevels)-1))
+		}
+		builder.WriteRune(sparkLevels[max(min(level, len(sparkLevels)-1), 0)])
+	}
+	return builder.String()
+}
+
 // displayLoadAverage displays system load average
 func (displayer *CPUMonitorDisplayer) displayLoadAverage(data *CPUMonitorData) {
 	fmt.Println("\n📈 LOAD AVERAGE")
-	fmt.Println(strings.Repeat("-", 50))
+	fmt.Println(displayer.sectionRule())
 
 	fmt.Printf("%s1 minute:  %s%.2f%s\n",
 		displayer.colorize("", displayer.ColorBold),
@@ -220,12 +474,35 @@ func (displayer *CPUMonitorDisplayer) displayLoadAverage(data *CPUMonitorData) {
 		displayer.colorize("", displayer.ColorRed),
 		data.LoadAverage15Min,
 		displayer.colorize("", displayer.ColorReset))
+
+	// Run queue vs. blocked tasks separates CPU pressure from I/O pressure
+	fmt.Printf("\n%sRunnable:  %s%d%s\n",
+		displayer.colorize("", displayer.ColorBold),
+		displayer.getRunQueueColor(data.RunQueueLength, data.LogicalCores),
+		data.RunQueueLength,
+		displayer.colorize("", displayer.ColorReset))
+
+	fmt.Printf("%sBlocked:   %s%d%s\n",
+		displayer.colorize("", displayer.ColorBold),
+		displayer.getBlockedTasksColor(data.BlockedTasks),
+		data.BlockedTasks,
+		displayer.colorize("", displayer.ColorReset))
+
+	// Process creation rate, where the platform counts created processes
+	if data.ProcessesCreated > 0 {
+		fmt.Printf("%sForks/s:   %s%.1f%s (peak %.1f)\n",
+			displayer.colorize("", displayer.ColorBold),
+			displayer.getForkRateColor(data.ForkRateStatus),
+			data.ForkRate,
+			displayer.colorize("", displayer.ColorReset),
+			data.ForkRatePeak)
+	}
 }
 
 // displayTopProcesses displays top CPU-consuming processes
 func (displayer *CPUMonitorDisplayer) displayTopProcesses(data *CPUMonitorData) {
 	fmt.Println("\n⚙️  TOP PROCESSES")
-	fmt.Println(strings.Repeat("-", 50))
+	fmt.Println(displayer.sectionRule())
 
 	// Limit number of processes to display
 	maxProcesses := displayer.MaxProcesses
@@ -233,26 +510,40 @@ func (displayer *CPUMonitorDisplayer) displayTopProcesses(data *CPUMonitorData)
 		maxProcesses = len(data.TopProcesses)
 	}
 
+	// The ports column is only shown when a process is network-facing
+	showPorts := false
+	for _, process := range data.TopProcesses {
+		if len(process.ListeningPorts) > 0 {
+			showPorts = true
+			break
+		}
+	}
+
 	// Display header
-	fmt.Printf("%s%-8s %-20s %-8s %-10s %s\n",
+	fmt.Printf("%s%-8s %-20s %-8s %-10s %-15s ",
 		displayer.colorize("", displayer.ColorBold),
 		"PID",
 		"Process",
 		"CPU%",
-		"Status",
-		displayer.colorize("", displayer.ColorReset))
+		"TIME+",
+		"Started")
+	if showPorts {
+		fmt.Printf("%-*s ", listening.ColumnWidth, "Ports")
+	}
+	fmt.Printf("%-10s %s\n", "Status", displayer.colorize("", displayer.ColorReset))
 
-	fmt.Println(strings.Repeat("-", 50))
+	fmt.Println(displayer.sectionRule())
 
 	// Display processes
 	for i := 0; i < maxProcesses; i++ {
 		process := data.TopProcesses[i]
-		displayer.displayProcessInfo(process)
+		displayer.displayProcessInfo(process, data.Timestamp, showPorts)
 	}
 }
 
 // displayProcessInfo displays information about a single process
-func (displayer *CPUMonitorDisplayer) displayProcessInfo(process CPUProcessInfo) {
+// Start times are relative to now, the collection time, so recorded and fixture data render the same every time
+func (displayer *CPUMonitorDisplayer) displayProcessInfo(process CPUProcessInfo, now time.Time, showPorts bool) {
 	// Truncate process name if too long
 	processName := process.Name
 	if len(processName) > 20 {
@@ -262,20 +553,62 @@ func (displayer *CPUMonitorDisplayer) displayProcessInfo(process CPUProcessInfo)
 	// Get color based on CPU usage
 	cpuColor := displayer.getUsageColor(process.CPUUsagePercent)
 
-	fmt.Printf("%s%-8d %-20s %s%-8.2f%s %-10.2f %s\n",
+	fmt.Printf("%s%-8d %-20s %s%-8.2f%s %-10s %-15s ",
 		displayer.colorize("", displayer.ColorWhite),
 		process.PID,
 		processName,
 		cpuColor,
 		process.CPUUsagePercent,
 		displayer.colorize("", displayer.ColorReset),
-		process.MemoryPercent,
-		process.Status)
+		formatCPUTime(process.CPUUsageTime),
+		formatStarted(process.CreateTime, now))
+	if showPorts {
+		fmt.Printf("%s%-*s%s ",
+			displayer.colorize("", displayer.ColorCyan),
+			listening.ColumnWidth,
+			listening.Compact(process.ListeningPorts, listening.ColumnWidth),
+			displayer.colorize("", displayer.ColorReset))
+	}
+	fmt.Println(process.Status)
+}
+
+// formatCPUTime formats accumulated CPU time the way top's TIME+ column does: minutes:seconds.hundredths
+func formatCPUTime(milliseconds uint64) string {
+	minutes := milliseconds / 60000
+	seconds := milliseconds / 1000 % 60
+	hundredths := milliseconds / 10 % 100
+	return fmt.Sprintf("%d:%02d.%02d", minutes, seconds, hundredths)
+}
+
+// formatStarted formats a start time in milliseconds since the epoch with the age of the process,
+// e.g. "09:14 (2h05m)" today or "Mar04 (12d03h)" on an earlier day, or "-" when unknown
+func formatStarted(createTime int64, now time.Time) string {
+	if createTime <= 0 {
+		return "-"
+	}
+
+	started := time.UnixMilli(createTime)
+	stamp := started.Format("2006")
+	if started.YearDay() == now.YearDay() && started.Year() == now.Year() {
+		stamp = started.Format("15:04")
+	} else if started.Year() == now.Year() {
+		stamp = started.Format("Jan02")
+	}
+
+	age := now.Sub(started)
+	switch {
+	case age < time.Hour:
+		return fmt.Sprintf("%s (%dm)", stamp, int(age.Minutes()))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%s (%dh%02dm)", stamp, int(age.Hours()), int(age.Minutes())%60)
+	default:
+		return fmt.Sprintf("%s (%dd%02dh)", stamp, int(age.Hours()/24), int(age.Hours())%24)
+	}
 }
 
 // displayUsageBar displays a graphical usage bar
 func (displayer *CPUMonitorDisplayer) displayUsageBar(label string, percentage float64, color string, customWidth ...int) {
-	width := displayer.BarWidth
+	width := displayer.barWidth()
 	if len(customWidth) > 0 {
 		width = customWidth[0]
 	}
@@ -293,16 +626,31 @@ func (displayer *CPUMonitorDisplayer) displayUsageBar(label string, percentage f
 		displayer.colorize("", displayer.ColorBold),
 		label,
 		displayer.colorize("", displayer.ColorWhite),
-		displayer.colorize(bar, color),
+		displayer.colorize(bar, terminal.WithoutMarker(color)),
 		displayer.colorize("", displayer.ColorWhite),
 		color,
 		percentage,
 		displayer.colorize("", displayer.ColorReset))
 }
 
+// displayAlertNotes shows the note configured for each raised alert, so the next step is on screen
+func (displayer *CPUMonitorDisplayer) displayAlertNotes(data *CPUMonitorData) {
+	fmt.Println("\n📘 RUNBOOK NOTES")
+	fmt.Println(displayer.sectionRule())
+
+	for _, note := range data.AlertNotes {
+		fmt.Printf("%s%s (%s):%s %s\n",
+			displayer.getTemperatureStatusColor(note.Level),
+			note.Key,
+			note.Level,
+			displayer.colorize("", displayer.ColorReset),
+			note.Text)
+	}
+}
+
 // displayFooter displays the CPU monitor footer
 func (displayer *CPUMonitorDisplayer) displayFooter(data *CPUMonitorData) {
-	fmt.Println(strings.Repeat("=", 80))
+	fmt.Println(displayer.rule("="))
 	fmt.Printf("%sLast Updated: %s%s%s\n",
 		displayer.colorize("", displayer.ColorBold),
 		displayer.colorize("", displayer.ColorCyan),
@@ -315,69 +663,165 @@ func (displayer *CPUMonitorDisplayer) displayFooter(data *CPUMonitorData) {
 		data.RefreshInterval.Seconds(),
 		displayer.colorize("", displayer.ColorReset))
 
-	fmt.Println(strings.Repeat("=", 80))
+	if !displayer.ShowColors || terminal.ColorBlindMode() {
+		fmt.Println(terminal.SeverityLegend())
+	}
+
+	fmt.Println(displayer.rule("="))
 }
 
 // getUsageColor returns the appropriate color for a given usage percentage
 func (displayer *CPUMonitorDisplayer) getUsageColor(percentage float64) string {
-	if !displayer.ShowColors {
-		return ""
-	}
-
 	switch {
 	case percentage < 30:
-		return displayer.ColorGreen
+		return displayer.severityColor(displayer.ColorGreen)
 	case percentage < 60:
-		return displayer.ColorYellow
+		return displayer.severityColor(displayer.ColorYellow)
 	case percentage < 80:
-		return displayer.ColorMagenta
+		return displayer.severityColor(displayer.ColorMagenta)
 	default:
-		return displayer.ColorRed
+		return displayer.severityColor(displayer.ColorRed)
 	}
 }
 
 // getTemperatureColor returns the appropriate color for a given temperature
 func (displayer *CPUMonitorDisplayer) getTemperatureColor(temperature float64) string {
-	if !displayer.ShowColors {
-		return ""
-	}
-
 	switch {
 	case temperature < 50:
-		return displayer.ColorGreen
+		return displayer.severityColor(displayer.ColorGreen)
 	case temperature < 70:
-		return displayer.ColorYellow
+		return displayer.severityColor(displayer.ColorYellow)
 	case temperature < 85:
-		return displayer.ColorMagenta
+		return displayer.severityColor(displayer.ColorMagenta)
 	default:
-		return displayer.ColorRed
+		return displayer.severityColor(displayer.ColorRed)
 	}
 }
 
-// getTemperatureStatusColor returns the appropriate color for temperature status
-func (displayer *CPUMonitorDisplayer) getTemperatureStatusColor(status string) string {
-	if !displayer.ShowColors {
-		return ""
+// getRunQueueColor returns the appropriate color for the run queue length
+// relative to the number of logical cores
+func (displayer *CPUMonitorDisplayer) getRunQueueColor(running, cores int) string {
+	if cores <= 0 {
+		cores = 1
+	}
+
+	switch {
+	case running <= cores:
+		return displayer.severityColor(displayer.ColorGreen)
+	case running <= cores*2:
+		return displayer.severityColor(displayer.ColorYellow)
+	default:
+		return displayer.severityColor(displayer.ColorRed)
+	}
+}
+
+// getBlockedTasksColor returns the appropriate color for the blocked task count
+func (displayer *CPUMonitorDisplayer) getBlockedTasksColor(blocked int) string {
+	switch {
+	case blocked == 0:
+		return displayer.severityColor(displayer.ColorGreen)
+	case blocked < 5:
+		return displayer.severityColor(displayer.ColorYellow)
+	default:
+		return displayer.severityColor(displayer.ColorRed)
+	}
+}
+
+// getForkRateColor returns the appropriate color for the fork rate alert level
+func (displayer *CPUMonitorDisplayer) getForkRateColor(status string) string {
+	switch status {
+	case "Warning":
+		return displayer.severityColor(displayer.ColorYellow)
+	case "Critical":
+		return displayer.severityColor(displayer.ColorRed)
+	default:
+		return displayer.severityColor(displayer.ColorGreen)
 	}
+}
 
+// getTemperatureStatusColor returns the appropriate color for temperature status
+func (displayer *CPUMonitorDisplayer) getTemperatureStatusColor(status string) string {
 	switch strings.ToLower(status) {
 	case "normal":
-		return displayer.ColorGreen
+		return displayer.severityColor(displayer.ColorGreen)
 	case "warning":
-		return displayer.ColorYellow
+		return displayer.severityColor(displayer.ColorYellow)
 	case "critical":
-		return displayer.ColorRed
+		return displayer.severityColor(displayer.ColorRed)
 	default:
-		return displayer.ColorWhite
+		return displayer.severityColor(displayer.ColorWhite)
 	}
 }
 
+// displayUnavailable shows a section that could not be collected, with the reason
+func (displayer *CPUMonitorDisplayer) displayUnavailable(title, reason string) {
+	fmt.Println("\n" + title)
+	fmt.Println(displayer.sectionRule())
+	fmt.Println(displayer.colorize("⚠️  "+partial.Unavailable(reason), displayer.ColorYellow))
+}
+
+// lineWidth returns the layout width: the terminal width, or 80 columns when AutoWidth is off
+func (displayer *CPUMonitorDisplayer) lineWidth() int {
+	if !displayer.AutoWidth {
+		return terminal.DefaultWidth
+	}
+	return terminal.Fit(terminal.Width(), 40, 240)
+}
+
+// rule returns a separator line across the layout width
+func (displayer *CPUMonitorDisplayer) rule(char string) string {
+	return strings.Repeat(char, displayer.lineWidth())
+}
+
+// sectionRule returns a section underline, shortened on narrow terminals
+func (displayer *CPUMonitorDisplayer) sectionRule() string {
+	return strings.Repeat("-", terminal.Fit(displayer.lineWidth(), 0, 50))
+}
+
+// barWidth returns the progress bar width, leaving room for the label and value on the same line
+func (displayer *CPUMonitorDisplayer) barWidth() int {
+	if !displayer.AutoWidth {
+		return displayer.BarWidth
+	}
+	return terminal.Fit(displayer.lineWidth()-28, 10, 100)
+}
+
 // colorize applies color to text if colors are enabled
 func (displayer *CPUMonitorDisplayer) colorize(text, color string) string {
 	if !displayer.ShowColors {
-		return text
+		// Severity colors are reduced to their marker
+		return terminal.MarkerOf(color) + text
+	}
+	return terminal.PaletteColor(displayer.severityLevel(color), color) + text + displayer.ColorReset
+}
+
+// severityColor returns a severity color in the active palette, followed by its marker when markers are shown
+// Markers are shown in color-blind mode and whenever colors are off, so severity never depends on color alone
+func (displayer *CPUMonitorDisplayer) severityColor(color string) string {
+	level := displayer.severityLevel(color)
+	marker := ""
+	if !displayer.ShowColors || terminal.ColorBlindMode() {
+		marker = terminal.SeverityMarker(level)
+	}
+	if !displayer.ShowColors {
+		return marker
+	}
+	return terminal.PaletteColor(level, color) + marker
+}
+
+// severityLevel returns the severity a displayer color stands for
+func (displayer *CPUMonitorDisplayer) severityLevel(color string) terminal.Severity {
+	switch color {
+	case displayer.ColorGreen:
+		return terminal.SeverityOK
+	case displayer.ColorYellow:
+		return terminal.SeverityWarning
+	case displayer.ColorMagenta:
+		return terminal.SeverityHigh
+	case displayer.ColorRed:
+		return terminal.SeverityCritical
 	}
-	return color + text + displayer.ColorReset
+	return terminal.SeverityNone
 }
 
 // SetGraphicsEnabled enables or disables graphical elements
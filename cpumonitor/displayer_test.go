@@ -0,0 +1,17 @@
+package cpumonitor
+
+import (
+	"testing"
+
+	"github.com/ahmadreza-log/simple-monitor/monitortest"
+)
+
+// TestDisplayCPUMonitorDataGolden renders testdata/snapshot.json the way the fixtures command does and
+// compares the output with testdata/display.golden
+func TestDisplayCPUMonitorDataGolden(t *testing.T) {
+	monitortest.Golden(t, func(data *CPUMonitorData) {
+		displayer := NewCPUMonitorDisplayer()
+		displayer.ShowColors, displayer.AutoWidth = false, false
+		displayer.DisplayCPUMonitorData(data)
+	})
+}
@@ -3,6 +3,8 @@ package cpumonitor
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/ahmadreza-log/simple-monitor/protoexport"
+	"github.com/ahmadreza-log/simple-monitor/redact"
 	"os"
 	"path/filepath"
 	"time"
@@ -32,6 +34,10 @@ func NewCPUMonitorExporter() *CPUMonitorExporter {
 // ExportToJSON exports CPU monitoring data to a JSON file
 // The file will be saved with a timestamp-based filename in the appropriate subdirectory
 func (exporter *CPUMonitorExporter) ExportToJSON(data *CPUMonitorData, moduleName string) (string, error) {
+	if err := redact.Copy(&data); err != nil {
+		return "", err
+	}
+
 	// Generate filename with current date and time
 	fileName := exporter.generateFileName(moduleName, "json")
 
@@ -69,6 +75,41 @@ func (exporter *CPUMonitorExporter) ExportToJSON(data *CPUMonitorData, moduleNam
 	return filePath, nil
 }
 
+// ExportToProtobuf exports CPU monitoring data to a protobuf file
+// The file holds one CPUMonitorData message; it is smaller and faster to parse than JSON for long archives
+func (exporter *CPUMonitorExporter) ExportToProtobuf(data *CPUMonitorData, moduleName string) (string, error) {
+	if err := redact.Copy(&data); err != nil {
+		return "", err
+	}
+
+	// Generate filename with current date and time
+	fileName := exporter.generateFileName(moduleName, protoexport.Extension)
+
+	// Create full file path
+	filePath, err := exporter.createFilePath(moduleName, fileName)
+	if err != nil {
+		return "", fmt.Errorf("failed to create file path: %w", err)
+	}
+
+	// Ensure directory exists
+	if err := exporter.ensureDirectoryExists(filepath.Dir(filePath)); err != nil {
+		return "", fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	// Encode as the message `simple-monitor proto` describes
+	content, err := protoexport.Marshal(data)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode protobuf data: %w", err)
+	}
+
+	// Write to file
+	if err := os.WriteFile(filePath, content, 0644); err != nil {
+		return "", fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return filePath, nil
+}
+
 // ExportToCSV exports CPU monitoring data to a CSV file
 // This method will be implemented when CSV export is needed
 func (exporter *CPUMonitorExporter) ExportToCSV(data *CPUMonitorData, moduleName string) (string, error) {
@@ -0,0 +1,47 @@
+package cpumonitor
+
+import "time"
+
+// Option configures a CPUMonitorManager as NewCPUMonitorManager creates it
+// Options are applied before the manager is returned, so nothing can be collecting while they change its settings
+type Option func(*CPUMonitorManager)
+
+// WithRefreshInterval sets how often live monitoring and Subscribe collect; an interval of zero or less is ignored
+func WithRefreshInterval(interval time.Duration) Option {
+	return func(manager *CPUMonitorManager) {
+		if interval > 0 {
+			manager.collector.changeConfig(func(config *CPUMonitorConfig) {
+				config.RefreshInterval = interval
+			})
+		}
+	}
+}
+
+// WithExporter replaces the default exporter, e.g. to write exports under another logs directory
+// A nil exporter keeps the default
+func WithExporter(exporter *CPUMonitorExporter) Option {
+	return func(manager *CPUMonitorManager) {
+		if exporter != nil {
+			manager.exporter = exporter
+		}
+	}
+}
+
+// WithThresholds sets the overall usage percentages that raise the warning and critical alerts
+func WithThresholds(warning, critical float64) Option {
+	return func(manager *CPUMonitorManager) {
+		manager.collector.changeConfig(func(config *CPUMonitorConfig) {
+			config.UsageWarning = warning
+			config.UsageCritical = critical
+		})
+	}
+}
+
+// WithFilters shows only the top processes whose name contains processName, ignoring case (empty shows all)
+func WithFilters(processName string) Option {
+	return func(manager *CPUMonitorManager) {
+		manager.collector.changeConfig(func(config *CPUMonitorConfig) {
+			config.ProcessNameFilter = processName
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package cpumonitor
+
+import (
+	"fmt"
+	"time"
+)
+
+// Where power readings come from
+const (
+	powerSourceRAPL      = "rapl"       // Intel/AMD RAPL energy counters (powercap)
+	powerSourceAMDEnergy = "amd_energy" // amd_energy hwmon driver
+	powerSourceBattery   = "battery"    // Battery discharge rate: the whole system, not just the CPU
+)
+
+// powerGraphPoints is how many recent samples are kept with the data for the power history graph
+const powerGraphPoints = 120
+
+// energyCounter is one cumulative energy counter of a power domain
+type energyCounter struct {
+	Name    string // Domain name (package-0, package-0/core, psys...)
+	Energy  uint64 // Energy used in microjoules
+	Range   uint64 // Value the counter wraps at (0 when unknown)
+	Package bool   // Whether the domain is a whole CPU package, counted in the package total
+}
+
+// collectPowerInfo measures the CPU power draw from the energy counters, or from the battery discharge
+// rate when the CPU has no readable counters
+// Counters give the average power since the previous energy sample; without one there is no reading yet
+func (collector *CPUMonitorCollector) collectPowerInfo(data *CPUMonitorData) error {
+	now := time.Now()
+	source, domains, measured, err := collector.sampleEnergy(now)
+	power := &CPUPowerInfo{Source: source, Domains: domains}
+	if err != nil || source == "" {
+		watts, discharging, batteryErr := readBatteryPower()
+		switch {
+		case batteryErr != nil:
+			return fmt.Errorf("failed to read battery power: %w", batteryErr)
+		case discharging:
+			power = &CPUPowerInfo{Source: powerSourceBattery, PackageWatts: watts}
+			measured = true
+		case err != nil:
+			return err
+		default:
+			return nil
+		}
+	}
+
+	for _, domain := range power.Domains {
+		if domain.Package {
+			power.PackageWatts += domain.Watts
+		}
+	}
+	if measured && !collector.lastPowerSample.IsZero() {
+		collector.energyUsed += power.PackageWatts * now.Sub(collector.lastPowerSample).Seconds()
+	}
+	collector.lastPowerSample = now
+	power.Measured = measured
+	power.EnergyWh = collector.energyUsed / 3600
+	data.Power = power
+	return nil
+}
+
+// sampleEnergy reads the energy counters and returns the average power of each domain since the previous sample
+// measured is false when there was no previous sample to compare with
+func (collector *CPUMonitorCollector) sampleEnergy(now time.Time) (string, []CPUPowerDomain, bool, error) {
+	source, counters, err := readEnergyCounters()
+	if err != nil || len(counters) == 0 {
+		return source, nil, false, err
+	}
+
+	elapsed := now.Sub(collector.lastEnergySample).Seconds()
+	measured := !collector.lastEnergySample.IsZero() && elapsed > 0
+	readings := make(map[string]uint64, len(counters))
+	domains := make([]CPUPowerDomain, 0, len(counters))
+	for _, counter := range counters {
+		readings[counter.Name] = counter.Energy
+		domain := CPUPowerDomain{Name: counter.Name, Package: counter.Package}
+		if previous, seen := collector.lastEnergy[counter.Name]; measured && seen {
+			delta := counter.Energy - previous
+			if counter.Energy < previous {
+				if counter.Range == 0 {
+					continue // Wrapped at an unknown range: skip this refresh
+				}
+				delta = counter.Range - previous + counter.Energy
+			}
+			domain.Watts = float64(delta) / 1e6 / elapsed
+		}
+		domains = append(domains, domain)
+	}
+
+	collector.lastEnergy = readings
+	collector.lastEnergySample = now
+	return source, domains, measured, nil
+}
@@ -0,0 +1,158 @@
+//go:build linux
+
+package cpumonitor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Where the kernel exposes energy counters and batteries (variables so other roots can be read)
+var (
+	powercapPath    = "/sys/class/powercap"
+	hwmonPath       = "/sys/class/hwmon"
+	powerSupplyPath = "/sys/class/power_supply"
+)
+
+// readEnergyCounters reads the CPU energy counters and names where they came from
+// Intel RAPL (and AMD RAPL on kernels that route it through powercap) is preferred, then the amd_energy
+// hwmon driver. Both are root-only since kernel 5.10 (the PLATYPUS side channel), so an unreadable
+// counter is reported as such rather than as missing. No counters at all gives an empty source
+func readEnergyCounters() (string, []energyCounter, error) {
+	counters, err := readRAPLCounters()
+	if len(counters) > 0 || err != nil {
+		return powerSourceRAPL, counters, err
+	}
+	counters, err = readAMDEnergyCounters()
+	if len(counters) > 0 || err != nil {
+		return powerSourceAMDEnergy, counters, err
+	}
+	return "", nil, nil
+}
+
+// readRAPLCounters reads the powercap intel-rapl zones: intel-rapl:N are packages (or psys, the whole
+// platform), intel-rapl:N:M their core, uncore and DRAM subzones
+// The intel-rapl-mmio zones repeat the package counters through another interface and are skipped
+func readRAPLCounters() ([]energyCounter, error) {
+	zones, _ := filepath.Glob(filepath.Join(powercapPath, "intel-rapl:*"))
+	sort.Strings(zones)
+
+	packages := make(map[string]string)
+	var counters []energyCounter
+	for _, zone := range zones {
+		base := filepath.Base(zone)
+		name := readPowerValue(zone, "name")
+		if name == "" {
+			continue
+		}
+		energy, err := readPowerNumber(zone, "energy_uj")
+		if err != nil {
+			if errors.Is(err, os.ErrPermission) {
+				return nil, fmt.Errorf("%s is only readable by root", filepath.Join(zone, "energy_uj"))
+			}
+			continue
+		}
+		wrap, _ := readPowerNumber(zone, "max_energy_range_uj")
+
+		counter := energyCounter{Name: name, Energy: energy, Range: wrap}
+		if parts := strings.Split(base, ":"); len(parts) == 2 {
+			packages[parts[1]] = name
+			counter.Package = strings.HasPrefix(name, "package")
+		} else if len(parts) == 3 && packages[parts[1]] != "" {
+			// Subzones are named alike in every package (core, dram), so they are qualified with theirs
+			counter.Name = packages[parts[1]] + "/" + name
+		}
+		counters = append(counters, counter)
+	}
+	return counters, nil
+}
+
+// readAMDEnergyCounters reads the socket counters of the amd_energy hwmon driver (energyN_label "EsocketN")
+// Its per-core counters are left out; the socket counters already include them
+func readAMDEnergyCounters() ([]energyCounter, error) {
+	entries, err := os.ReadDir(hwmonPath)
+	if err != nil {
+		return nil, nil
+	}
+
+	var counters []energyCounter
+	for _, entry := range entries {
+		dir := filepath.Join(hwmonPath, entry.Name())
+		if readPowerValue(dir, "name") != "amd_energy" {
+			continue
+		}
+		labels, _ := filepath.Glob(filepath.Join(dir, "energy*_label"))
+		sort.Strings(labels)
+		for _, labelPath := range labels {
+			label := readPowerValue(dir, filepath.Base(labelPath))
+			socket, found := strings.CutPrefix(label, "Esocket")
+			if !found {
+				continue
+			}
+			input := strings.TrimSuffix(filepath.Base(labelPath), "_label") + "_input"
+			energy, err := readPowerNumber(dir, input)
+			if err != nil {
+				if errors.Is(err, os.ErrPermission) {
+					return nil, fmt.Errorf("%s is only readable by root", filepath.Join(dir, input))
+				}
+				continue
+			}
+			counters = append(counters, energyCounter{Name: "package-" + socket, Energy: energy, Package: true})
+		}
+	}
+	return counters, nil
+}
+
+// readBatteryPower returns the power drawn from the batteries in watts, and whether any is discharging
+// Batteries report power_now in µW, or current_now in µA and voltage_now in µV; while charging
+// or full the figure is the charge rate or nothing, so only discharging batteries count
+func readBatteryPower() (float64, bool, error) {
+	entries, err := os.ReadDir(powerSupplyPath)
+	if err != nil {
+		return 0, false, nil
+	}
+
+	var watts float64
+	discharging := false
+	for _, entry := range entries {
+		dir := filepath.Join(powerSupplyPath, entry.Name())
+		if readPowerValue(dir, "type") != "Battery" || readPowerValue(dir, "status") != "Discharging" {
+			continue
+		}
+		if power, err := readPowerNumber(dir, "power_now"); err == nil {
+			watts += float64(power) / 1e6
+			discharging = true
+			continue
+		}
+		current, currentErr := readPowerNumber(dir, "current_now")
+		voltage, voltageErr := readPowerNumber(dir, "voltage_now")
+		if currentErr == nil && voltageErr == nil {
+			watts += float64(current) * float64(voltage) / 1e12
+			discharging = true
+		}
+	}
+	return watts, discharging, nil
+}
+
+// readPowerValue reads a sysfs attribute as trimmed text, empty when unreadable
+func readPowerValue(dir, name string) string {
+	content, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(content))
+}
+
+// readPowerNumber reads a sysfs attribute holding an unsigned integer
+func readPowerNumber(dir, name string) (uint64, error) {
+	content, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(strings.TrimSpace(string(content)), 10, 64)
+}
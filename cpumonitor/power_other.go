@@ -0,0 +1,15 @@
+//go:build !linux
+
+package cpumonitor
+
+// readEnergyCounters reads the CPU energy counters
+// RAPL needs a kernel driver to be read on Windows and macOS, so other platforms report no counters
+func readEnergyCounters() (string, []energyCounter, error) {
+	return "", nil, nil
+}
+
+// readBatteryPower returns the power drawn from the batteries
+// Only Linux exposes the discharge rate without WMI or IOKit, so other platforms report none
+func readBatteryPower() (float64, bool, error) {
+	return 0, false, nil
+}
@@ -0,0 +1,152 @@
+package cpumonitor
+
+import (
+	"github.com/ahmadreza-log/simple-monitor/listening"
+	"github.com/ahmadreza-log/simple-monitor/procid"
+	"github.com/ahmadreza-log/simple-monitor/simulate"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// collectSimulatedData fills data with synthetic CPU metrics instead of querying the system
+// Usage follows a slow sine wave with random spikes, so alerts and charts have something to show
+func (collector *CPUMonitorCollector) collectSimulatedData(data *CPUMonitorData) {
+	source := collector.simulator
+	now := time.Now()
+
+	data.Simulated = true
+	data.ModelName = "Simulated CPU @ 3.60GHz"
+	data.VendorID = "Simulated"
+	data.Architecture = runtime.GOARCH
+	data.PhysicalCores = 4
+	data.LogicalCores = 8
+	data.Uptime = source.Uptime()
+	data.Topology = &CPUTopology{
+		Sockets:        1,
+		Cores:          4,
+		ThreadsPerCore: 2,
+		SMTEnabled:     true,
+		SMTControl:     "on",
+		Caches: []CPUCacheInfo{
+			{Level: 1, Type: "Data", SizeBytes: 48 * 1024, Instances: 4, SharedBy: 2},
+			{Level: 1, Type: "Instruction", SizeBytes: 32 * 1024, Instances: 4, SharedBy: 2},
+			{Level: 2, Type: "Unified", SizeBytes: 1280 * 1024, Instances: 4, SharedBy: 2},
+			{Level: 3, Type: "Unified", SizeBytes: 12 * 1024 * 1024, Instances: 1, SharedBy: 8},
+		},
+	}
+
+	// Overall usage
+	usage := source.Wave(2*time.Minute, 0, 15, 55) + source.Noise(4) + source.Spike(0.08, 40)
+	data.OverallUsage = simulate.Clamp(usage, 1, 100)
+	data.SystemUsage = data.OverallUsage * 0.3
+	data.UserUsage = data.OverallUsage - data.SystemUsage
+	data.IdleUsage = 100 - data.OverallUsage
+	data.IOWaitUsage = source.Wave(90*time.Second, 0.3, 0.5, 6)
+	collector.evaluateUsage(data, now)
+
+	// Per-core usage drifts around the overall value
+	if collector.config.ShowCores {
+		for i := 0; i < data.LogicalCores; i++ {
+			phase := float64(i) / float64(data.LogicalCores)
+			coreUsage := simulate.Clamp(data.OverallUsage+source.Wave(30*time.Second, phase, -15, 15)+source.Noise(5), 0, 100)
+			data.Cores = append(data.Cores, CPUCoreInfo{
+				CoreID:          i,
+				PhysicalCore:    i % data.PhysicalCores,
+				UsagePercent:    coreUsage,
+				UserPercent:     coreUsage * 0.7,
+				SystemPercent:   coreUsage * 0.3,
+				IdlePercent:     100 - coreUsage,
+				Frequency:       2200 + 14*coreUsage,
+				Temperature:     40 + coreUsage*0.45,
+				IsOnline:        true,
+				IsHyperthreaded: i >= data.PhysicalCores,
+				LastUpdated:     now,
+			})
+		}
+	}
+
+	// Load average trails the usage wave
+	if collector.config.ShowLoadAverage {
+		cores := float64(data.LogicalCores)
+		data.LoadAverage1Min = simulate.Clamp(cores*data.OverallUsage/100+source.Noise(0.3), 0, cores*2)
+		data.LoadAverage5Min = cores * source.Wave(2*time.Minute, -0.05, 15, 55) / 100
+		data.LoadAverage15Min = cores * 0.33
+		data.RunQueueLength = int(data.LoadAverage1Min + 0.5)
+		data.BlockedTasks = int(data.IOWaitUsage / 3)
+		collector.updateForkRate(data, source.Counter("forks", 15+source.Spike(0.02, 400)), now)
+	}
+
+	// Temperature follows usage
+	if collector.config.ShowTemperature {
+		data.Temperature = 42 + data.OverallUsage*0.45 + source.Noise(1)
+		data.MaxTemperature = 100.0
+		level := collector.alerts.Evaluate("temperature", data.Temperature, collector.config.TemperatureWarning,
+			collector.config.TemperatureCritical, collector.config.AlertRules["temperature"], now)
+		data.TemperatureStatus = level.String()
+	}
+
+	// Package power follows usage on top of an idle floor, like a desktop CPU's RAPL counters
+	if collector.config.ShowPower {
+		watts := 8 + data.OverallUsage*0.85 + source.Noise(1.5)
+		cores := (watts - 8) * 0.9
+		if !collector.lastPowerSample.IsZero() {
+			collector.energyUsed += watts * now.Sub(collector.lastPowerSample).Seconds()
+		}
+		collector.lastPowerSample = now
+		data.Power = &CPUPowerInfo{
+			Source:       powerSourceRAPL,
+			Measured:     true,
+			PackageWatts: watts,
+			EnergyWh:     collector.energyUsed / 3600,
+			Domains: []CPUPowerDomain{
+				{Name: "package-0", Watts: watts, Package: true},
+				{Name: "package-0/core", Watts: cores},
+				{Name: "package-0/uncore", Watts: 1.2},
+				{Name: "package-0/dram", Watts: 2.5 + source.Noise(0.2)},
+			},
+		}
+	}
+
+	// Top processes from the shared synthetic process table
+	if collector.config.ShowProcesses && !collector.idle {
+		var processInfos []CPUProcessInfo
+		for _, process := range source.Processes() {
+			if process.CPUPercent < collector.config.MinCPUUsage {
+				continue
+			}
+			if collector.config.ProcessNameFilter != "" &&
+				!strings.Contains(strings.ToLower(process.Name), strings.ToLower(collector.config.ProcessNameFilter)) {
+				continue
+			}
+
+			processInfo := CPUProcessInfo{
+				PID:             process.PID,
+				InstanceID:      procid.InstanceID(process.PID, process.CreateTime),
+				Name:            process.Name,
+				ExecutablePath:  process.Path,
+				CPUUsagePercent: process.CPUPercent,
+				CPUUsageTime:    process.CPUTime,
+				CreateTime:      process.CreateTime,
+				Uptime:          now.Unix() - process.CreateTime/1000,
+				Status:          process.Status,
+				MemoryUsage:     process.MemoryRSS,
+				ThreadCount:     process.Threads,
+				LastUpdated:     now,
+			}
+			if collector.config.ShowPorts && process.ListenPort > 0 {
+				processInfo.ListeningPorts = []string{listening.Port{Protocol: "TCP", Number: uint32(process.ListenPort)}.String()}
+			}
+			processInfos = append(processInfos, processInfo)
+		}
+
+		sort.Slice(processInfos, func(i, j int) bool {
+			return processInfos[i].CPUUsagePercent > processInfos[j].CPUUsagePercent
+		})
+		if len(processInfos) > collector.config.MaxProcesses {
+			processInfos = processInfos[:collector.config.MaxProcesses]
+		}
+		data.TopProcesses = processInfos
+	}
+}
@@ -0,0 +1,191 @@
+//go:build linux
+
+package cpumonitor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// sysCPUPath is where the kernel exposes CPU topology and caches
+const sysCPUPath = "/sys/devices/system/cpu"
+
+// cpuDirPattern matches the per-CPU directories (cpu0, cpu1, ...)
+var cpuDirPattern = regexp.MustCompile(`^cpu[0-9]+$`)
+
+// readTopology enumerates sockets, physical cores, SMT siblings and caches from sysfs
+// Offline CPUs have no topology directory and are left out of the map
+func readTopology() (*CPUTopology, map[int]logicalCPU, error) {
+	entries, err := os.ReadDir(sysCPUPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s: %w", sysCPUPath, err)
+	}
+
+	cpus := make(map[int]logicalCPU)
+	sockets := make(map[int]bool)
+	cores := make(map[[2]int]bool)
+	threadsPerCore := 1
+	caches := make(map[string]*CPUCacheInfo)
+	cacheInstances := make(map[string]map[string]bool)
+
+	for _, entry := range entries {
+		if !cpuDirPattern.MatchString(entry.Name()) {
+			continue
+		}
+		id, _ := strconv.Atoi(strings.TrimPrefix(entry.Name(), "cpu"))
+		dir := filepath.Join(sysCPUPath, entry.Name())
+
+		socket, err := readSysInt(filepath.Join(dir, "topology", "physical_package_id"))
+		if err != nil {
+			continue
+		}
+		core, err := readSysInt(filepath.Join(dir, "topology", "core_id"))
+		if err != nil {
+			continue
+		}
+		// Some ARM boards report -1 for the package
+		if socket < 0 {
+			socket = 0
+		}
+
+		// The first CPU in the sibling list is the core's primary thread
+		siblings := parseCPUList(readSysString(filepath.Join(dir, "topology", "thread_siblings_list")))
+		if len(siblings) > threadsPerCore {
+			threadsPerCore = len(siblings)
+		}
+		cpus[id] = logicalCPU{
+			Socket:  socket,
+			Core:    core,
+			Sibling: len(siblings) > 0 && siblings[0] != id,
+		}
+		sockets[socket] = true
+		cores[[2]int{socket, core}] = true
+
+		readCaches(dir, caches, cacheInstances)
+	}
+
+	if len(cpus) == 0 {
+		return nil, nil, fmt.Errorf("no CPU topology found in %s", sysCPUPath)
+	}
+
+	topology := &CPUTopology{
+		Sockets:        len(sockets),
+		Cores:          len(cores),
+		ThreadsPerCore: threadsPerCore,
+		SMTEnabled:     threadsPerCore > 1,
+		SMTControl:     readSysString(filepath.Join(sysCPUPath, "smt", "control")),
+	}
+	for key, cache := range caches {
+		cache.Instances = len(cacheInstances[key])
+		topology.Caches = append(topology.Caches, *cache)
+	}
+	sortCaches(topology.Caches)
+	return topology, cpus, nil
+}
+
+// readCaches adds the caches of one CPU directory, counting each distinct sharing group once
+func readCaches(dir string, caches map[string]*CPUCacheInfo, instances map[string]map[string]bool) {
+	indexes, err := filepath.Glob(filepath.Join(dir, "cache", "index[0-9]*"))
+	if err != nil {
+		return
+	}
+
+	for _, index := range indexes {
+		level, err := readSysInt(filepath.Join(index, "level"))
+		if err != nil {
+			continue
+		}
+		cacheType := readSysString(filepath.Join(index, "type"))
+		size := parseCacheSize(readSysString(filepath.Join(index, "size")))
+		shared := readSysString(filepath.Join(index, "shared_cpu_list"))
+
+		key := fmt.Sprintf("%d/%s", level, cacheType)
+		if _, seen := caches[key]; !seen {
+			caches[key] = &CPUCacheInfo{
+				Level:     level,
+				Type:      cacheType,
+				SizeBytes: size,
+				SharedBy:  len(parseCPUList(shared)),
+			}
+			instances[key] = make(map[string]bool)
+		}
+		instances[key][shared] = true
+	}
+}
+
+// sortCaches orders caches by level, data before instruction
+func sortCaches(caches []CPUCacheInfo) {
+	sort.Slice(caches, func(i, j int) bool {
+		if caches[i].Level != caches[j].Level {
+			return caches[i].Level < caches[j].Level
+		}
+		return caches[i].Type < caches[j].Type
+	})
+}
+
+// parseCPUList expands a kernel CPU list such as "0-3,8,10-11"
+func parseCPUList(list string) []int {
+	var cpus []int
+	for _, part := range strings.Split(list, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		bounds := strings.SplitN(part, "-", 2)
+		first, err := strconv.Atoi(bounds[0])
+		if err != nil {
+			continue
+		}
+		last := first
+		if len(bounds) == 2 {
+			if last, err = strconv.Atoi(bounds[1]); err != nil {
+				continue
+			}
+		}
+		for cpu := first; cpu <= last; cpu++ {
+			cpus = append(cpus, cpu)
+		}
+	}
+	return cpus
+}
+
+// parseCacheSize converts a sysfs cache size such as "32K" or "16384K" to bytes
+func parseCacheSize(size string) uint64 {
+	multiplier := uint64(1)
+	switch {
+	case strings.HasSuffix(size, "K"):
+		multiplier = 1024
+	case strings.HasSuffix(size, "M"):
+		multiplier = 1024 * 1024
+	case strings.HasSuffix(size, "G"):
+		multiplier = 1024 * 1024 * 1024
+	}
+	value, err := strconv.ParseUint(strings.TrimRight(size, "KMG"), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return value * multiplier
+}
+
+// readSysString returns the trimmed content of a sysfs file, or "" when it cannot be read
+func readSysString(path string) string {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(content))
+}
+
+// readSysInt reads a sysfs file holding a single integer
+func readSysInt(path string) (int, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(content)))
+}
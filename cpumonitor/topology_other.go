@@ -0,0 +1,11 @@
+//go:build !linux
+
+package cpumonitor
+
+import "errors"
+
+// readTopology enumerates sockets, physical cores, SMT siblings and caches
+// Only Linux exposes the layout through sysfs; other platforms fall back to estimateTopology
+func readTopology() (*CPUTopology, map[int]logicalCPU, error) {
+	return nil, nil, errors.New("CPU topology enumeration is only available on Linux")
+}
@@ -1,6 +1,9 @@
 package cpumonitor
 
 import (
+	"github.com/ahmadreza-log/simple-monitor/alert"
+	"github.com/ahmadreza-log/simple-monitor/partial"
+	"slices"
 	"time"
 )
 
@@ -8,6 +11,7 @@ import (
 type CPUProcessInfo struct {
 	// Process identification
 	PID            int32  `json:"pid"`             // Process ID
+	InstanceID     string `json:"instance_id"`     // Process instance ID (PID and start time)
 	Name           string `json:"name"`            // Process name
 	ExecutablePath string `json:"executable_path"` // Full path to executable
 
@@ -15,6 +19,10 @@ type CPUProcessInfo struct {
 	CPUUsagePercent float64 `json:"cpu_usage_percent"` // CPU usage percentage
 	CPUUsageTime    uint64  `json:"cpu_usage_time"`    // Total CPU time used (in milliseconds)
 
+	// Lifetime
+	CreateTime int64 `json:"create_time"` // Start time in milliseconds since the epoch
+	Uptime     int64 `json:"uptime"`      // Seconds since the process started
+
 	// Process status
 	Status   string `json:"status"`   // Process status (Running, Sleeping, etc.)
 	Priority int32  `json:"priority"` // Process priority
@@ -26,6 +34,9 @@ type CPUProcessInfo struct {
 	// Thread information
 	ThreadCount int32 `json:"thread_count"` // Number of threads
 
+	// Network exposure
+	ListeningPorts []string `json:"listening_ports,omitempty"` // Ports the process listens on: "443" for TCP, "53/udp" for UDP
+
 	// Timestamp
 	LastUpdated time.Time `json:"last_updated"` // When this data was last updated
 }
@@ -33,8 +44,9 @@ type CPUProcessInfo struct {
 // CPUCoreInfo represents information about a single CPU core
 type CPUCoreInfo struct {
 	// Core identification
-	CoreID     int `json:"core_id"`     // Core identifier
-	PhysicalID int `json:"physical_id"` // Physical processor ID
+	CoreID       int `json:"core_id"`       // Logical CPU number
+	PhysicalID   int `json:"physical_id"`   // Socket (physical package) the CPU sits in
+	PhysicalCore int `json:"physical_core"` // Physical core within the socket, shared by SMT siblings
 
 	// Core usage statistics
 	UsagePercent  float64 `json:"usage_percent"`  // Core usage percentage
@@ -48,12 +60,57 @@ type CPUCoreInfo struct {
 
 	// Core status
 	IsOnline        bool `json:"is_online"`        // Whether core is online
-	IsHyperthreaded bool `json:"is_hyperthreaded"` // Whether this is a hyperthread
+	IsHyperthreaded bool `json:"is_hyperthreaded"` // Whether this is a second (or later) SMT thread of its core
 
 	// Timestamp
 	LastUpdated time.Time `json:"last_updated"` // When this data was last updated
 }
 
+// CPUCacheInfo describes one level and type of CPU cache
+type CPUCacheInfo struct {
+	Level     int    `json:"level"`      // Cache level (1, 2 or 3)
+	Type      string `json:"type"`       // Data, Instruction or Unified
+	SizeBytes uint64 `json:"size_bytes"` // Size of one cache instance
+	Instances int    `json:"instances"`  // Number of separate caches of this kind (e.g. one L2 per core)
+	SharedBy  int    `json:"shared_by"`  // Logical CPUs sharing each instance
+}
+
+// CPUTopology describes how logical CPUs map onto sockets and physical cores
+type CPUTopology struct {
+	Sockets        int            `json:"sockets"`          // Physical packages
+	Cores          int            `json:"cores"`            // Physical cores across all sockets
+	ThreadsPerCore int            `json:"threads_per_core"` // Logical CPUs per physical core
+	SMTEnabled     bool           `json:"smt_enabled"`      // Whether any core runs more than one thread
+	SMTControl     string         `json:"smt_control"`      // Kernel SMT setting (on, off, forceoff, notsupported), empty when unknown
+	Caches         []CPUCacheInfo `json:"caches"`           // Caches by level, data before instruction
+	Estimated      bool           `json:"estimated"`        // Whether the layout was derived from core counts instead of read from the OS
+}
+
+// CPUPowerDomain represents one power domain with its own energy counter
+type CPUPowerDomain struct {
+	Name    string  `json:"name"`    // Domain name (package-0, package-0/core, package-0/dram, psys...)
+	Watts   float64 `json:"watts"`   // Average power since the previous refresh in watts
+	Package bool    `json:"package"` // Whether the domain is a whole CPU package (the others are parts of one, or the platform)
+}
+
+// CPUPowerInfo represents the power drawn by the CPU packages
+type CPUPowerInfo struct {
+	Source       string           `json:"source"`        // Where readings come from (rapl, amd_energy, battery)
+	Measured     bool             `json:"measured"`      // Whether the watts are a reading; the first energy sample has nothing to compare with
+	PackageWatts float64          `json:"package_watts"` // Power of all CPU packages in watts (the whole system when Source is battery)
+	PeakWatts    float64          `json:"peak_watts"`    // Highest power in the in-memory history
+	EnergyWh     float64          `json:"energy_wh"`     // Energy used since monitoring started in watt-hours
+	Domains      []CPUPowerDomain `json:"domains"`       // Packages and their core, uncore and DRAM domains
+	Recent       []float64        `json:"recent"`        // Recent package power, oldest first, for the history graph
+	RecentUsage  []float64        `json:"recent_usage"`  // Overall CPU usage at the same samples, to compare with
+}
+
+// CPUUsageTrend is recent overall usage for the history graph, under which an imported overlay series is drawn
+type CPUUsageTrend struct {
+	Timestamps []time.Time `json:"timestamps"` // Sample times, oldest first
+	Usage      []float64   `json:"usage"`      // Overall usage at each sample (percentage)
+}
+
 // CPUMonitorData represents comprehensive CPU monitoring data
 type CPUMonitorData struct {
 	// Basic CPU information
@@ -63,23 +120,42 @@ type CPUMonitorData struct {
 	PhysicalCores int    `json:"physical_cores"` // Number of physical cores
 	LogicalCores  int    `json:"logical_cores"`  // Number of logical cores
 
+	// Sockets, SMT and caches (detected once; nil when the OS exposes nothing)
+	Topology *CPUTopology `json:"topology,omitempty"`
+
 	// Overall CPU statistics
-	OverallUsage float64 `json:"overall_usage"` // Overall CPU usage percentage
-	UserUsage    float64 `json:"user_usage"`    // User process usage
-	SystemUsage  float64 `json:"system_usage"`  // System process usage
-	IdleUsage    float64 `json:"idle_usage"`    // Idle usage
-	IOWaitUsage  float64 `json:"io_wait_usage"` // I/O wait usage
+	OverallUsage  float64 `json:"overall_usage"`            // Overall CPU usage percentage
+	UserUsage     float64 `json:"user_usage"`               // User process usage
+	SystemUsage   float64 `json:"system_usage"`             // System process usage
+	IdleUsage     float64 `json:"idle_usage"`               // Idle usage
+	IOWaitUsage   float64 `json:"io_wait_usage"`            // I/O wait usage
+	UsageStatus   string  `json:"usage_status"`             // Overall usage status (Normal, Warning, Critical)
+	UsageSchedule string  `json:"usage_schedule,omitempty"` // Threshold schedule in effect for the usage alert, empty outside any
 
 	// CPU performance metrics
-	LoadAverage1Min  float64 `json:"load_1_min"`  // Load average over 1 minute
-	LoadAverage5Min  float64 `json:"load_5_min"`  // Load average over 5 minutes
-	LoadAverage15Min float64 `json:"load_15_min"` // Load average over 15 minutes
+	LoadAverage1Min  float64 `json:"load_1_min"`       // Load average over 1 minute
+	LoadAverage5Min  float64 `json:"load_5_min"`       // Load average over 5 minutes
+	LoadAverage15Min float64 `json:"load_15_min"`      // Load average over 15 minutes
+	RunQueueLength   int     `json:"run_queue_length"` // Number of runnable tasks (procs_running)
+	BlockedTasks     int     `json:"blocked_tasks"`    // Number of tasks blocked on I/O (procs_blocked)
+
+	// Process creation (fork storms show up here before they show up as load)
+	ProcessesCreated uint64  `json:"processes_created"` // Processes created since boot (fork/clone), 0 when the platform does not count them
+	ForkRate         float64 `json:"fork_rate"`         // Processes created per second since the previous refresh
+	ForkRatePeak     float64 `json:"fork_rate_peak"`    // Highest fork rate in the in-memory history
+	ForkRateStatus   string  `json:"fork_rate_status"`  // Fork rate status (Normal, Warning, Critical)
 
 	// Temperature information
 	Temperature       float64 `json:"temperature"`        // Overall CPU temperature
 	MaxTemperature    float64 `json:"max_temperature"`    // Maximum safe temperature
 	TemperatureStatus string  `json:"temperature_status"` // Temperature status (Normal, Warning, Critical)
 
+	// Power and energy (nil when there is nothing to read)
+	Power *CPUPowerInfo `json:"power,omitempty"`
+
+	// Recent usage for the history graph (nil when the graph is off)
+	Trend *CPUUsageTrend `json:"trend,omitempty"`
+
 	// Per-core information
 	Cores []CPUCoreInfo `json:"cores"` // Information about each core
 
@@ -90,6 +166,15 @@ type CPUMonitorData struct {
 	RefreshInterval time.Duration `json:"refresh_interval"` // How often data is refreshed
 	IsMonitoring    bool          `json:"is_monitoring"`    // Whether monitoring is active
 
+	// Runbook notes of the raised alerts whose rule has a note
+	AlertNotes []alert.Note `json:"alert_notes,omitempty"` // Ordered by alert key
+
+	// Sections that could not be collected; everything else in the snapshot is still valid
+	SectionErrors partial.Errors `json:"section_errors,omitempty"` // Reason keyed by section name
+
+	// Data source
+	Simulated bool `json:"simulated,omitempty"` // Whether the data is synthetic (--simulate) rather than read from the system
+
 	// Timestamps
 	Timestamp time.Time     `json:"timestamp"` // When this data was collected
 	Uptime    time.Duration `json:"uptime"`    // System uptime
@@ -97,22 +182,38 @@ type CPUMonitorData struct {
 
 // CPUMonitorConfig represents configuration options for CPU monitoring
 type CPUMonitorConfig struct {
+	Enabled bool `json:"enabled"` // Whether the monitor runs; a disabled monitor is left out of every view, snapshot and alert
+
 	// Monitoring settings
 	RefreshInterval     time.Duration `json:"refresh_interval"`     // How often to refresh data
 	MaxProcesses        int           `json:"max_processes"`        // Maximum number of processes to track
+	UsageWarning        float64       `json:"usage_warning"`        // Overall usage warning threshold (percentage)
+	UsageCritical       float64       `json:"usage_critical"`       // Overall usage critical threshold (percentage)
 	TemperatureWarning  float64       `json:"temperature_warning"`  // Temperature warning threshold
 	TemperatureCritical float64       `json:"temperature_critical"` // Temperature critical threshold
+	ForkRateWarning     float64       `json:"fork_rate_warning"`    // Processes created per second that raise a warning
+	ForkRateCritical    float64       `json:"fork_rate_critical"`   // Processes created per second that raise a critical alert
+
+	// Alert behaviour (hysteresis, minimum durations and threshold schedules) keyed by alert: usage, temperature, fork_rate
+	AlertRules map[string]alert.Rule `json:"alert_rules"` // Alerts without a rule flip exactly at their thresholds
 
 	// Display settings
 	ShowCores       bool `json:"show_cores"`        // Whether to show per-core information
 	ShowProcesses   bool `json:"show_processes"`    // Whether to show process information
 	ShowTemperature bool `json:"show_temperature"`  // Whether to show temperature
 	ShowLoadAverage bool `json:"show_load_average"` // Whether to show load average
+	ShowPower       bool `json:"show_power"`        // Whether to show package power from RAPL energy counters (or the battery discharge rate)
+	ShowPorts       bool `json:"show_ports"`        // Whether to list the ports each top process listens on
+	ShowHistory     bool `json:"show_history"`      // Whether to graph recent usage, with the imported overlay series under it
 
 	// Export settings
-	ExportToFile   bool          `json:"export_to_file"`  // Whether to export data to file
-	ExportInterval time.Duration `json:"export_interval"` // How often to export data
-	ExportFormat   string        `json:"export_format"`   // Export format (json, csv, txt)
+	ExportToFile        bool          `json:"export_to_file"`        // Whether to export data to file
+	ExportInterval      time.Duration `json:"export_interval"`       // How often to export data
+	ExportFormat        string        `json:"export_format"`         // Export format (json, csv, txt, protobuf)
+	PersistHistory      bool          `json:"persist_history"`       // Whether to keep downsampled long-term history on disk
+	HistorySaveInterval time.Duration `json:"history_save_interval"` // How often the long-term history file is rewritten
+	HistorySize         int           `json:"history_size"`          // Samples kept in the in-memory history behind live trends
+	HistoryResolution   time.Duration `json:"history_resolution"`    // Minimum time between in-memory history samples (0 samples every refresh)
 
 	// Filter settings
 	MinCPUUsage       float64 `json:"min_cpu_usage"`       // Minimum CPU usage to show process
@@ -134,11 +235,35 @@ type CPUUsageHistory struct {
 	// Temperature history
 	Temperature []float64 `json:"temperature"` // Temperature over time
 
+	// Process creation history
+	ForkRate []float64 `json:"fork_rate"` // Processes created per second over time
+
+	// Power history
+	PackagePower []float64 `json:"package_power"` // CPU package power in watts over time
+
 	// Configuration
 	MaxDataPoints  int `json:"max_data_points"`  // Maximum number of data points to keep
 	DataPointCount int `json:"data_point_count"` // Current number of data points
 }
 
+// Clone returns a deep copy of the history
+func (history *CPUUsageHistory) Clone() *CPUUsageHistory {
+	clone := *history
+	clone.Timestamps = slices.Clone(history.Timestamps)
+	clone.OverallUsage = slices.Clone(history.OverallUsage)
+	clone.UserUsage = slices.Clone(history.UserUsage)
+	clone.SystemUsage = slices.Clone(history.SystemUsage)
+	clone.IdleUsage = slices.Clone(history.IdleUsage)
+	clone.Temperature = slices.Clone(history.Temperature)
+	clone.ForkRate = slices.Clone(history.ForkRate)
+	clone.PackagePower = slices.Clone(history.PackagePower)
+	clone.CoreUsage = make([][]float64, len(history.CoreUsage))
+	for i, usage := range history.CoreUsage {
+		clone.CoreUsage[i] = slices.Clone(usage)
+	}
+	return &clone
+}
+
 // CPUMonitorAlert represents an alert condition for CPU monitoring
 type CPUMonitorAlert struct {
 	// Alert identification
@@ -0,0 +1,117 @@
+package diskmonitor
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CalibrationSize is how much data the calibration benchmark writes and reads back
+// Large enough to get past drive caches, small enough to finish in seconds on most drives
+const CalibrationSize = 256 * 1024 * 1024
+
+// CalibrationTarget is a drive that can be calibrated, with the mounts a benchmark can write to
+type CalibrationTarget struct {
+	Device      string            // Device name as shown in the I/O table (sda, nvme0n1)
+	Mountpoints []string          // Mounted filesystems on the drive
+	Capability  *DeviceCapability // Recorded capability, nil when the drive is not calibrated
+}
+
+// CalibrationTargets lists the drives holding monitored mounts, with their recorded capabilities
+func (collector *DiskMonitorCollector) CalibrationTargets() ([]CalibrationTarget, error) {
+	// The mount exclusions read the collection's configuration, so wait for one in progress
+	collector.collectMutex.Lock()
+	defer collector.collectMutex.Unlock()
+	mounts := make(map[string][]string)
+	if collector.simulator != nil {
+		for _, device := range simulatedDevices {
+			mounts[device.name] = nil
+			for _, partition := range simulatedPartitions {
+				if strings.HasPrefix(partition.device, "/dev/"+device.name) {
+					mounts[device.name] = append(mounts[device.name], partition.mountpoint)
+				}
+			}
+		}
+	} else {
+		partitions, err := collector.system.Disk.Partitions(false)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get partitions: %w", err)
+		}
+		for _, partition := range partitions {
+			if collector.isExcludedMount(partition.Device, partition.Mountpoint, partition.Fstype) || isReadOnly(partition.Opts) {
+				continue
+			}
+			drive := filepath.Base(parentDrive(partition.Device))
+			mounts[drive] = append(mounts[drive], partition.Mountpoint)
+		}
+	}
+
+	targets := make([]CalibrationTarget, 0, len(mounts))
+	for device, mountpoints := range mounts {
+		target := CalibrationTarget{Device: device, Mountpoints: mountpoints}
+		if capability, ok := collector.currentConfig().DeviceCapabilities[device]; ok {
+			target.Capability = &capability
+		}
+		targets = append(targets, target)
+	}
+	sort.Slice(targets, func(i, j int) bool { return targets[i].Device < targets[j].Device })
+	return targets, nil
+}
+
+// Calibrate measures a drive's sequential write and read speed by writing CalibrationSize bytes
+// to a temporary file on one of its mounts and reading them back; the file is removed afterwards
+// The result is returned, not recorded: pass it to SetDeviceCapability to keep it
+func (collector *DiskMonitorCollector) Calibrate(target CalibrationTarget) (DeviceCapability, error) {
+	capability := DeviceCapability{Source: CapabilityBenchmark, MeasuredAt: time.Now()}
+
+	// Synthetic drives report their nominal speeds with a little run-to-run variation
+	if collector.simulator != nil {
+		for _, device := range simulatedDevices {
+			if device.name == target.Device {
+				capability.MaxReadSpeed = device.maxRead * (1 + collector.simulator.Noise(0.02))
+				capability.MaxWriteSpeed = device.maxWrite * (1 + collector.simulator.Noise(0.02))
+				return capability, nil
+			}
+		}
+		return capability, fmt.Errorf("unknown device: %s", target.Device)
+	}
+
+	// Leave room on the filesystem: the benchmark needs twice its size free
+	var candidates []string
+	for _, mountpoint := range target.Mountpoints {
+		usage, err := collector.system.Disk.Usage(mountpoint)
+		if err == nil && usage.Free >= 2*CalibrationSize {
+			candidates = append(candidates, mountpoint)
+		}
+	}
+	if len(candidates) == 0 {
+		return capability, fmt.Errorf("no mount on %s has %d MB free for the benchmark; enter the rated speeds instead", target.Device, 2*CalibrationSize/(1024*1024))
+	}
+
+	read, write, err := benchmarkThroughput(candidates, CalibrationSize)
+	if err != nil {
+		return capability, fmt.Errorf("failed to benchmark %s: %w", target.Device, err)
+	}
+	capability.MaxReadSpeed = read
+	capability.MaxWriteSpeed = write
+	return capability, nil
+}
+
+// SetDeviceCapability records a drive's maximum throughput; its speeds are shown as a share of it from the next refresh
+func (collector *DiskMonitorCollector) SetDeviceCapability(device string, capability DeviceCapability) {
+	collector.changeConfig(func(config *DiskMonitorConfig) {
+		if config.DeviceCapabilities == nil {
+			config.DeviceCapabilities = make(map[string]DeviceCapability)
+		}
+		config.DeviceCapabilities[device] = capability
+	})
+}
+
+// ClearDeviceCapability forgets a drive's recorded throughput, going back to busy-time utilization
+func (collector *DiskMonitorCollector) ClearDeviceCapability(device string) {
+	collector.changeConfig(func(config *DiskMonitorConfig) {
+		delete(config.DeviceCapabilities, device)
+	})
+}
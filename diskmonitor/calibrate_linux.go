@@ -0,0 +1,98 @@
+//go:build linux
+
+package diskmonitor
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// calibrationBlockSize is the size of each write and read of the benchmark
+const calibrationBlockSize = 4 * 1024 * 1024
+
+// benchmarkThroughput writes size bytes to a temporary file on the first usable mount and reads them back
+// It returns the read and write speeds in MB/s. The write includes flushing to the device; before the read
+// the file is dropped from the page cache so it comes from the device rather than memory
+func benchmarkThroughput(mountpoints []string, size int64) (float64, float64, error) {
+	file, err := createBenchmarkFile(mountpoints)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	// Random data, so compressing or deduplicating drives and filesystems cannot shortcut the writes
+	block := make([]byte, calibrationBlockSize)
+	rand.New(rand.NewSource(time.Now().UnixNano())).Read(block)
+
+	start := time.Now()
+	for written := int64(0); written < size; written += int64(len(block)) {
+		if _, err := file.Write(block); err != nil {
+			return 0, 0, fmt.Errorf("failed to write %s: %w", file.Name(), err)
+		}
+	}
+	if err := file.Sync(); err != nil {
+		return 0, 0, fmt.Errorf("failed to flush %s: %w", file.Name(), err)
+	}
+	writeSpeed := megabytesPerSecond(size, time.Since(start))
+
+	if err := unix.Fadvise(int(file.Fd()), 0, 0, unix.FADV_DONTNEED); err != nil {
+		return 0, 0, fmt.Errorf("failed to drop %s from the page cache: %w", file.Name(), err)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return 0, 0, fmt.Errorf("failed to rewind %s: %w", file.Name(), err)
+	}
+
+	start = time.Now()
+	read, err := io.CopyBuffer(io.Discard, file, block)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to read %s: %w", file.Name(), err)
+	}
+	readSpeed := megabytesPerSecond(read, time.Since(start))
+
+	return readSpeed, writeSpeed, nil
+}
+
+// createBenchmarkFile creates the temporary file on the first mount that accepts it
+// Each mount is tried at its root, then in its tmp directories, which are only used when they live on the same filesystem
+func createBenchmarkFile(mountpoints []string) (*os.File, error) {
+	var errs []error
+	for _, mountpoint := range mountpoints {
+		var root unix.Stat_t
+		if err := unix.Stat(mountpoint, &root); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		for _, dir := range []string{mountpoint, filepath.Join(mountpoint, "var", "tmp"), filepath.Join(mountpoint, "tmp")} {
+			var stat unix.Stat_t
+			if err := unix.Stat(dir, &stat); err != nil || stat.Dev != root.Dev {
+				continue
+			}
+			file, err := os.CreateTemp(dir, ".simple-monitor-calibration-*")
+			if err == nil {
+				return file, nil
+			}
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) == 0 {
+		return nil, fmt.Errorf("no writable directory found")
+	}
+	return nil, fmt.Errorf("no writable directory found (run as root to write to the mount itself): %w", errors.Join(errs...))
+}
+
+// megabytesPerSecond converts bytes moved in elapsed to MB/s
+func megabytesPerSecond(bytes int64, elapsed time.Duration) float64 {
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(bytes) / (1024 * 1024) / elapsed.Seconds()
+}
@@ -0,0 +1,11 @@
+//go:build !linux
+
+package diskmonitor
+
+import "fmt"
+
+// benchmarkThroughput measures a drive's read and write speed
+// The benchmark relies on dropping its file from the page cache, which only Linux allows, so other systems use rated speeds
+func benchmarkThroughput(mountpoints []string, size int64) (float64, float64, error) {
+	return 0, 0, fmt.Errorf("the calibration benchmark is only available on Linux; enter the rated speeds instead")
+}
@@ -2,32 +2,94 @@ package diskmonitor
 
 import (
 	"fmt"
+	"github.com/ahmadreza-log/simple-monitor/alert"
+	"github.com/ahmadreza-log/simple-monitor/historystore"
+	"github.com/ahmadreza-log/simple-monitor/partial"
+	"github.com/ahmadreza-log/simple-monitor/procid"
+	"github.com/ahmadreza-log/simple-monitor/provider"
+	"github.com/ahmadreza-log/simple-monitor/simulate"
+	"github.com/shirou/gopsutil/v3/disk"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
 	"sort"
+	"sync"
 	"time"
-
-	"github.com/shirou/gopsutil/v3/disk"
-	"github.com/shirou/gopsutil/v3/process"
 )
 
 // DiskMonitorCollector handles the collection of disk monitoring data
 // This struct provides methods to gather real-time disk metrics and process information
 type DiskMonitorCollector struct {
-	// Configuration
+	// Configuration: config is what the collection in progress reads, adopted from latest as it starts
+	// latest is replaced on every change and never modified, so it can be handed out without copying
+	// collectMutex is held for a whole collection, so collections started by the live loop, subscribers and
+	// the attach server run one at a time and never see config or the state they carry change under them
 	config        *DiskMonitorConfig
+	latest        *DiskMonitorConfig
+	configMutex   sync.Mutex
+	collectMutex  sync.Mutex
 	lastTimestamp time.Time
 
-	// Process tracking
-	processCache    map[int32]*DiskProcessInfo
-	lastProcessTime map[int32]time.Time
-
 	// History tracking
 	history *DiskUsageHistory
+
+	// Downsampled long-term history (raw, 1-minute and 5-minute tiers)
+	longHistory *historystore.Store
+
+	// Idle mode (expensive process scans are paused)
+	idle bool
+
+	// Open file size samples for growth detection
+	fileSizes      map[string]uint64
+	fileSampleTime time.Time
+	growingFiles   []GrowingFileInfo
+
+	// Cleanup candidate cache (walking cache directories is slow)
+	cleanupCache []DiskCleanupCandidate
+	cleanupTime  time.Time
+
+	// Synthetic data source used instead of the system (nil when collecting real data)
+	simulator *simulate.Source
+
+	// System data sources (gopsutil unless replaced with fakes)
+	system provider.Providers
+
+	// Alert levels carried between collections (hysteresis and minimum durations)
+	alerts *alert.Tracker
+
+	// Mountpoints seen mounted read-write, so a later read-only mount counts as a remount
+	writableMounts map[string]bool
+
+	// SMART self-tests queued from the monitor, keyed by drive
+	selfTests map[string]*selfTestState
+
+	// NVMe health cache (smartctl is too slow to run every refresh)
+	nvmeCache []DiskNVMeInfo
+	nvmeTime  time.Time
+
+	// TRIM status cache (systemctl is too slow to run every refresh)
+	trimCache []DiskTrimInfo
+	trimTime  time.Time
+
+	// I/O counters from the previous refresh, turned into rates by the next one
+	lastIO     map[string]disk.IOCountersStat
+	lastIOTime time.Time
+}
+
+// cleanupLocation is a well-known directory that usually holds reclaimable data
+type cleanupLocation struct {
+	category string
+	path     string
+	hint     string
 }
 
 // NewDiskMonitorCollector creates a new instance of DiskMonitorCollector
 // with default configuration values
 func NewDiskMonitorCollector() *DiskMonitorCollector {
 	config := &DiskMonitorConfig{
+		Enabled:             true,
 		RefreshInterval:     1 * time.Second,
 		MaxProcesses:        20,
 		LowSpaceWarning:     80.0,
@@ -35,28 +97,62 @@ func NewDiskMonitorCollector() *DiskMonitorCollector {
 		TempWarning:         50.0,
 		TempCritical:        60.0,
 		IOBottleneckThreshold: 80.0,
+		NVMeWearWarning:     80.0,
+		NVMeWearCritical:    95.0,
+		NVMeTempWarning:     70.0,
+		NVMeTempCritical:    80.0,
 		ShowPartitions:      true,
 		ShowIO:              true,
 		ShowTemperature:     true,
 		ShowHealth:          true,
 		ShowProcesses:       true,
 		ShowPerformance:     true,
+		ShowCleanupSuggestions: false,
+		ShowGrowingFiles:    true,
+		ShowNVMe:            true,
+		ShowTrim:            true,
 		ExportToFile:        true,
 		ExportInterval:      1 * time.Hour,
 		ExportFormat:        "json",
+		PersistHistory:      true,
+		HistorySaveInterval: 1 * time.Minute,
+		HistorySize:         historystore.DefaultBufferSize,
+		HistoryResolution:   0,
 		MinIOUsage:          1.0,
 		ProcessNameFilter:   "",
 		DeviceFilter:        "",
 		MountpointFilter:    "",
+		ExcludeMounts:       []string{"/boot/efi", "/snap/*/*", "/dev/loop*", "squashfs"},
+		ShowExcludedMounts:  false,
+		MaxGrowingFiles:     10,
+		GrowingFilesInterval: 5 * time.Second,
+		CleanupCheckInterval: 10 * time.Minute,
+		SelfTestPollInterval: 30 * time.Second,
+		NVMeCheckInterval:    1 * time.Minute,
+		TrimCheckInterval:    10 * time.Minute,
+		TrimMaxAge:           14 * 24 * time.Hour,
+		LogsDirectory:       "logs",
+		AlertRules: map[string]alert.Rule{
+			"disk_space":       {Hysteresis: 1},
+			"disk_temperature": {Hysteresis: 3},
+			"io_bottleneck":    {Hysteresis: 10, RaiseAfter: 10 * time.Second},
+			"nvme_wear":        {Hysteresis: 1},
+			"nvme_temperature": {Hysteresis: 3},
+		},
+		DeviceCapabilities: map[string]DeviceCapability{},
 	}
 
 	return &DiskMonitorCollector{
 		config:          config,
+		latest:          config,
 		lastTimestamp:   time.Now(),
-		processCache:    make(map[int32]*DiskProcessInfo),
-		lastProcessTime: make(map[int32]time.Time),
+		longHistory: historystore.NewStore(historystore.DefaultTiers()),
+		alerts:      alert.NewTracker("disk"),
+		system:      provider.System(),
+		writableMounts: make(map[string]bool),
+		selfTests:      make(map[string]*selfTestState),
 		history: &DiskUsageHistory{
-			MaxDataPoints:  100,
+			MaxDataPoints:  config.HistorySize,
 			DataPointCount: 0,
 		},
 	}
@@ -65,45 +161,65 @@ func NewDiskMonitorCollector() *DiskMonitorCollector {
 // CollectDiskMonitorData gathers comprehensive disk monitoring data
 // This is the main method that collects all available disk metrics
 func (collector *DiskMonitorCollector) CollectDiskMonitorData() (*DiskMonitorData, error) {
+	collector.collectMutex.Lock()
+	defer collector.collectMutex.Unlock()
+	collector.adoptConfig()
 	data := &DiskMonitorData{
 		Timestamp:       time.Now(),
 		RefreshInterval: collector.config.RefreshInterval,
 		IsMonitoring:    true,
+		SectionErrors:   make(partial.Errors),
+	}
+
+	// Synthetic data for demos and UI work replaces every system query
+	if collector.simulator != nil {
+		collector.collectSimulatedData(data)
+		if collector.config.ShowPerformance {
+			collector.calculatePerformanceMetrics(data)
+		}
+		collector.analyzeDiskStatus(data)
+		collector.updateHistory(data)
+		return data, nil
 	}
 
 	// Collect partition information
+	// Sections that fail are recorded and shown as unavailable instead of aborting the snapshot
 	if collector.config.ShowPartitions {
-		if err := collector.collectPartitionInfo(data); err != nil {
-			return nil, fmt.Errorf("failed to collect partition info: %w", err)
-		}
+		data.SectionErrors.Add("partitions", collector.collectPartitionInfo(data))
+	}
+
+	// Check TRIM on SSD filesystems
+	if collector.config.ShowTrim {
+		data.SectionErrors.Add("trim", collector.collectTrimInfo(data))
 	}
 
 	// Collect I/O statistics
 	if collector.config.ShowIO {
-		if err := collector.collectIOInfo(data); err != nil {
-			return nil, fmt.Errorf("failed to collect I/O info: %w", err)
-		}
+		data.SectionErrors.Add("io", collector.collectIOInfo(data))
 	}
 
 	// Collect temperature information
 	if collector.config.ShowTemperature {
-		if err := collector.collectTemperatureInfo(data); err != nil {
-			return nil, fmt.Errorf("failed to collect temperature info: %w", err)
-		}
+		data.SectionErrors.Add("temperature", collector.collectTemperatureInfo(data))
 	}
 
 	// Collect health information
 	if collector.config.ShowHealth {
-		if err := collector.collectHealthInfo(data); err != nil {
-			return nil, fmt.Errorf("failed to collect health info: %w", err)
+		data.SectionErrors.Add("health", collector.collectHealthInfo(data))
+		if collector.config.ShowNVMe {
+			data.SectionErrors.Add("nvme", collector.collectNVMeInfo(data))
 		}
+		collector.updateSelfTests(data)
 	}
 
 	// Collect process information
-	if collector.config.ShowProcesses {
-		if err := collector.collectProcessInfo(data); err != nil {
-			return nil, fmt.Errorf("failed to collect process info: %w", err)
-		}
+	if collector.config.ShowProcesses && !collector.idle {
+		data.SectionErrors.Add("processes", collector.collectProcessInfo(data))
+	}
+
+	// Sample open files to find the fastest-growing ones
+	if collector.config.ShowGrowingFiles {
+		collector.collectGrowingFiles(data)
 	}
 
 	// Calculate performance metrics
@@ -114,6 +230,11 @@ func (collector *DiskMonitorCollector) CollectDiskMonitorData() (*DiskMonitorDat
 	// Analyze disk status and alerts
 	collector.analyzeDiskStatus(data)
 
+	// Suggest reclaimable space once a low-space warning triggers
+	if collector.config.ShowCleanupSuggestions && collector.hasLowSpace(data) {
+		collector.collectCleanupCandidates(data)
+	}
+
 	// Update history
 	collector.updateHistory(data)
 
@@ -123,13 +244,13 @@ func (collector *DiskMonitorCollector) CollectDiskMonitorData() (*DiskMonitorDat
 // collectPartitionInfo gathers disk partition information
 func (collector *DiskMonitorCollector) collectPartitionInfo(data *DiskMonitorData) error {
 	// Get all partitions
-	allPartitions, err := disk.Partitions(false)
+	allPartitions, err := collector.system.Disk.Partitions(false)
 	if err != nil {
 		return fmt.Errorf("failed to get partitions: %w", err)
 	}
 
 	var partitionInfos []DiskPartitionInfo
-	var totalSpace, usedSpace, freeSpace uint64
+	var totalSpace, usedSpace, freeSpace, reservedSpace uint64
 
 	// Process each partition
 	for _, partition := range allPartitions {
@@ -143,49 +264,118 @@ func (collector *DiskMonitorCollector) collectPartitionInfo(data *DiskMonitorDat
 			continue
 		}
 
+		// Skip excluded mounts (EFI, snap loop devices, squashfs) unless asked to list them
+		excluded := collector.isExcludedMount(partition.Device, partition.Mountpoint, partition.Fstype)
+		if excluded && !collector.config.ShowExcludedMounts {
+			continue
+		}
+
 		// Get usage for this partition
-		usage, err := disk.Usage(partition.Mountpoint)
+		usage, err := collector.system.Disk.Usage(partition.Mountpoint)
 		if err != nil {
 			continue // Skip partitions we can't access
 		}
 
+		// gopsutil reports df's "Avail" as Free, so whatever is left of the total
+		// after used and available space is reserved for root
+		var reserved uint64
+		if usage.Total > usage.Used+usage.Free {
+			reserved = usage.Total - usage.Used - usage.Free
+		}
+
 		partitionInfo := DiskPartitionInfo{
 			Device:       partition.Device,
 			Mountpoint:   partition.Mountpoint,
 			Fstype:       partition.Fstype,
 			Total:        usage.Total,
 			Free:         usage.Free,
+			TrueFree:     usage.Free + reserved,
+			Reserved:     reserved,
 			Used:         usage.Used,
-			UsagePercent: usage.UsedPercent,
+			UsagePercent: dfUsagePercent(usage.Used, usage.Free),
 			InodesTotal:  usage.InodesTotal,
 			InodesFree:   usage.InodesFree,
 			InodesUsed:   usage.InodesUsed,
+			Excluded:     excluded,
+			ReadOnly:     isReadOnly(partition.Opts),
 		}
+		collector.trackReadOnly(&partitionInfo)
 
 		partitionInfos = append(partitionInfos, partitionInfo)
 
+		// Excluded mounts are listed but not counted
+		if excluded {
+			continue
+		}
+
 		// Add to totals
 		totalSpace += usage.Total
 		usedSpace += usage.Used
 		freeSpace += usage.Free
+		reservedSpace += reserved
 	}
 
 	data.Partitions = partitionInfos
 	data.TotalSpace = totalSpace
 	data.UsedSpace = usedSpace
 	data.FreeSpace = freeSpace
+	data.ReservedSpace = reservedSpace
+	data.UsagePercent = dfUsagePercent(usedSpace, freeSpace)
+
+	return nil
+}
 
-	if totalSpace > 0 {
-		data.UsagePercent = (float64(usedSpace) / float64(totalSpace)) * 100
+// isReadOnly reports whether the mount options include "ro"
+func isReadOnly(opts []string) bool {
+	for _, opt := range opts {
+		if opt == "ro" {
+			return true
+		}
 	}
+	return false
+}
 
-	return nil
+// trackReadOnly flags a partition that is read-only now but was read-write earlier in the session
+// Filesystems such as ext4 mounted with errors=remount-ro flip like this when the disk reports errors;
+// mounts that were read-only from the start (ISO images, recovery partitions) are not flagged
+func (collector *DiskMonitorCollector) trackReadOnly(partition *DiskPartitionInfo) {
+	if !partition.ReadOnly {
+		collector.writableMounts[partition.Mountpoint] = true
+		return
+	}
+	partition.RemountedReadOnly = collector.writableMounts[partition.Mountpoint]
+}
+
+// isExcludedMount reports whether a mount matches one of the ExcludeMounts glob patterns
+// Patterns are matched against the mountpoint, the device and the filesystem type
+func (collector *DiskMonitorCollector) isExcludedMount(device, mountpoint, fstype string) bool {
+	for _, pattern := range collector.config.ExcludeMounts {
+		for _, value := range []string{mountpoint, device, fstype} {
+			if value == "" {
+				continue
+			}
+			if matched, err := filepath.Match(pattern, value); err == nil && matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// dfUsagePercent computes usage the way df does: reserved blocks count neither
+// as used nor as available, so a filesystem is 100% full once non-root users
+// cannot write, even though the reserved blocks are still free
+func dfUsagePercent(used, available uint64) float64 {
+	if used+available == 0 {
+		return 0
+	}
+	return (float64(used) / float64(used+available)) * 100
 }
 
 // collectIOInfo gathers disk I/O statistics
 func (collector *DiskMonitorCollector) collectIOInfo(data *DiskMonitorData) error {
 	// Get I/O counters
-	ioCounters, err := disk.IOCounters()
+	ioCounters, err := collector.system.Disk.IOCounters()
 	if err != nil {
 		return fmt.Errorf("failed to get I/O counters: %w", err)
 	}
@@ -193,12 +383,27 @@ func (collector *DiskMonitorCollector) collectIOInfo(data *DiskMonitorData) erro
 	var diskIOs []DiskIOInfo
 	var totalReadSpeed, totalWriteSpeed, totalIOPS float64
 
+	// Rates come from the change since the previous refresh; the first refresh has nothing to compare with and shows zero
+	now := time.Now()
+	elapsed := now.Sub(collector.lastIOTime).Seconds()
+	previous := collector.lastIO
+	collector.lastIO = ioCounters
+	collector.lastIOTime = now
+
 	for device, counter := range ioCounters {
-		// Calculate speeds (simplified - would need time-based calculation for accurate speeds)
-		readSpeed := float64(counter.ReadBytes) / (1024 * 1024) // Convert to MB/s (simplified)
-		writeSpeed := float64(counter.WriteBytes) / (1024 * 1024) // Convert to MB/s (simplified)
-		iops := float64(counter.ReadCount + counter.WriteCount) // Simplified IOPS
-		utilization := float64(counter.ReadTime + counter.WriteTime) / 1000 // Simplified utilization
+		// Loop devices and other excluded mounts would skew the averages
+		if collector.isExcludedMount("/dev/"+device, "", "") {
+			continue
+		}
+
+		var readSpeed, writeSpeed, iops, utilization float64
+		if last, ok := previous[device]; ok && elapsed > 0 {
+			readSpeed = float64(counterDelta(counter.ReadBytes, last.ReadBytes)) / (1024 * 1024) / elapsed
+			writeSpeed = float64(counterDelta(counter.WriteBytes, last.WriteBytes)) / (1024 * 1024) / elapsed
+			iops = float64(counterDelta(counter.ReadCount+counter.WriteCount, last.ReadCount+last.WriteCount)) / elapsed
+			// IoTime counts milliseconds the device had requests in flight
+			utilization = math.Min(float64(counterDelta(counter.IoTime, last.IoTime))/(elapsed*10), 100)
+		}
 
 		diskIO := DiskIOInfo{
 			DeviceName:   device,
@@ -213,6 +418,7 @@ func (collector *DiskMonitorCollector) collectIOInfo(data *DiskMonitorData) erro
 			IOPS:         iops,
 			Utilization:  utilization,
 		}
+		collector.applyCapability(&diskIO)
 
 		diskIOs = append(diskIOs, diskIO)
 
@@ -225,11 +431,39 @@ func (collector *DiskMonitorCollector) collectIOInfo(data *DiskMonitorData) erro
 	data.DiskIO = diskIOs
 	data.TotalReadSpeed = totalReadSpeed
 	data.TotalWriteSpeed = totalWriteSpeed
-	data.AverageIOPS = totalIOPS / float64(len(diskIOs))
+	if len(diskIOs) > 0 {
+		data.AverageIOPS = totalIOPS / float64(len(diskIOs))
+	}
 
 	return nil
 }
 
+// counterDelta returns how much a counter grew, or 0 when it went backwards (device reset or replaced)
+func counterDelta(current, previous uint64) uint64 {
+	if current < previous {
+		return 0
+	}
+	return current - previous
+}
+
+// applyCapability expresses a device's speeds as a share of its recorded maximum throughput
+// A calibrated device's utilization becomes the larger of the two, so bars and the bottleneck alert measure real headroom
+func (collector *DiskMonitorCollector) applyCapability(io *DiskIOInfo) {
+	capability, ok := collector.config.DeviceCapabilities[io.DeviceName]
+	if !ok || (capability.MaxReadSpeed <= 0 && capability.MaxWriteSpeed <= 0) {
+		return
+	}
+
+	io.Calibrated = true
+	if capability.MaxReadSpeed > 0 {
+		io.ReadPercent = io.ReadSpeed / capability.MaxReadSpeed * 100
+	}
+	if capability.MaxWriteSpeed > 0 {
+		io.WritePercent = io.WriteSpeed / capability.MaxWriteSpeed * 100
+	}
+	io.Utilization = math.Min(math.Max(io.ReadPercent, io.WritePercent), 100)
+}
+
 // collectTemperatureInfo gathers disk temperature information
 func (collector *DiskMonitorCollector) collectTemperatureInfo(data *DiskMonitorData) error {
 	// Note: Temperature monitoring requires platform-specific implementation
@@ -237,12 +471,16 @@ func (collector *DiskMonitorCollector) collectTemperatureInfo(data *DiskMonitorD
 	var temperatures []DiskTemperatureInfo
 
 	// Get list of disk devices
-	partitions, err := disk.Partitions(false)
+	partitions, err := collector.system.Disk.Partitions(false)
 	if err != nil {
 		return fmt.Errorf("failed to get partitions: %w", err)
 	}
 
 	for _, partition := range partitions {
+		if collector.isExcludedMount(partition.Device, partition.Mountpoint, partition.Fstype) {
+			continue
+		}
+
 		// Create placeholder temperature data
 		// In a real implementation, this would query hardware sensors
 		temperature := DiskTemperatureInfo{
@@ -266,12 +504,16 @@ func (collector *DiskMonitorCollector) collectHealthInfo(data *DiskMonitorData)
 	var healthInfos []DiskHealthInfo
 
 	// Get list of disk devices
-	partitions, err := disk.Partitions(false)
+	partitions, err := collector.system.Disk.Partitions(false)
 	if err != nil {
 		return fmt.Errorf("failed to get partitions: %w", err)
 	}
 
 	for _, partition := range partitions {
+		if collector.isExcludedMount(partition.Device, partition.Mountpoint, partition.Fstype) {
+			continue
+		}
+
 		// Create placeholder health data
 		// In a real implementation, this would query SMART data
 		health := DiskHealthInfo{
@@ -296,7 +538,7 @@ func (collector *DiskMonitorCollector) collectHealthInfo(data *DiskMonitorData)
 // collectProcessInfo gathers top disk-consuming processes
 func (collector *DiskMonitorCollector) collectProcessInfo(data *DiskMonitorData) error {
 	// Get all processes
-	processes, err := process.Processes()
+	processes, err := collector.system.Proc.Processes()
 	if err != nil {
 		return fmt.Errorf("failed to get processes: %w", err)
 	}
@@ -345,8 +587,12 @@ func (collector *DiskMonitorCollector) collectProcessInfo(data *DiskMonitorData)
 			continue
 		}
 
+		// The start time tells this process apart from an earlier one given the same PID
+		createTime, _ := p.CreateTime()
+
 		processInfo := DiskProcessInfo{
-			PID:        p.Pid,
+			PID:        p.PID(),
+			InstanceID: procid.InstanceID(p.PID(), createTime),
 			Name:       name,
 			ReadBytes:  ioInfo.ReadBytes,
 			WriteBytes: ioInfo.WriteBytes,
@@ -375,6 +621,86 @@ func (collector *DiskMonitorCollector) collectProcessInfo(data *DiskMonitorData)
 	return nil
 }
 
+// collectGrowingFiles samples the size of every regular file held open by a process
+// and reports the files that grew since the previous sample. Samples are taken at most
+// once per GrowingFilesInterval; the first call only records a baseline.
+func (collector *DiskMonitorCollector) collectGrowingFiles(data *DiskMonitorData) {
+	if collector.fileSizes != nil && time.Since(collector.fileSampleTime) < collector.config.GrowingFilesInterval {
+		data.GrowingFiles = collector.growingFiles
+		return
+	}
+
+	processes, err := collector.system.Proc.Processes()
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	sizes := make(map[string]uint64)
+	owners := make(map[string]provider.Process)
+
+	for _, p := range processes {
+		openFiles, err := p.OpenFiles()
+		if err != nil {
+			continue // Skip processes we can't access
+		}
+
+		for _, file := range openFiles {
+			if _, seen := sizes[file.Path]; seen {
+				continue
+			}
+
+			// Sockets, pipes and devices are not regular files
+			info, err := os.Stat(file.Path)
+			if err != nil || !info.Mode().IsRegular() {
+				continue
+			}
+
+			sizes[file.Path] = uint64(info.Size())
+			owners[file.Path] = p
+		}
+	}
+
+	var growing []GrowingFileInfo
+	if collector.fileSizes != nil {
+		elapsed := now.Sub(collector.fileSampleTime).Seconds()
+		for path, size := range sizes {
+			previous, ok := collector.fileSizes[path]
+			if !ok || size <= previous || elapsed <= 0 {
+				continue
+			}
+
+			owner := owners[path]
+			name, err := owner.Name()
+			if err != nil {
+				name = "Unknown"
+			}
+
+			growing = append(growing, GrowingFileInfo{
+				Path:        path,
+				Size:        size,
+				Growth:      size - previous,
+				GrowthRate:  float64(size-previous) / elapsed,
+				PID:         owner.PID(),
+				ProcessName: name,
+			})
+		}
+
+		sort.Slice(growing, func(i, j int) bool {
+			return growing[i].GrowthRate > growing[j].GrowthRate
+		})
+
+		if len(growing) > collector.config.MaxGrowingFiles {
+			growing = growing[:collector.config.MaxGrowingFiles]
+		}
+	}
+
+	collector.fileSizes = sizes
+	collector.fileSampleTime = now
+	collector.growingFiles = growing
+	data.GrowingFiles = growing
+}
+
 // calculatePerformanceMetrics calculates overall performance metrics
 func (collector *DiskMonitorCollector) calculatePerformanceMetrics(data *DiskMonitorData) {
 	// Calculate overall disk utilization
@@ -389,25 +715,23 @@ func (collector *DiskMonitorCollector) calculatePerformanceMetrics(data *DiskMon
 
 // analyzeDiskStatus analyzes disk status and sets alerts
 func (collector *DiskMonitorCollector) analyzeDiskStatus(data *DiskMonitorData) {
+	rules := collector.config.AlertRules
+
 	// Analyze disk space status
-	if data.UsagePercent >= collector.config.LowSpaceCritical {
-		data.DiskStatus = "Critical"
-		data.LowSpaceWarning = true
-	} else if data.UsagePercent >= collector.config.LowSpaceWarning {
-		data.DiskStatus = "Warning"
-		data.LowSpaceWarning = true
-	} else {
-		data.DiskStatus = "Normal"
-		data.LowSpaceWarning = false
-	}
-
-	// Analyze temperature status
-	for _, temp := range data.DiskTemperatures {
-		if temp.Temperature >= collector.config.TempCritical {
+	spaceLevel := collector.alerts.Evaluate("disk_space", data.UsagePercent,
+		collector.config.LowSpaceWarning, collector.config.LowSpaceCritical, rules["disk_space"], data.Timestamp)
+	data.DiskStatus = spaceLevel.String()
+	data.LowSpaceWarning = spaceLevel > alert.LevelNormal
+
+	// Analyze temperature status (every device is evaluated so each keeps its own alert state)
+	for i, temp := range data.DiskTemperatures {
+		level := collector.alerts.Evaluate("disk_temperature:"+temp.DeviceName, temp.Temperature,
+			collector.config.TempWarning, collector.config.TempCritical, rules["disk_temperature"], data.Timestamp)
+		data.DiskTemperatures[i].Status = level.String()
+		if level == alert.LevelCritical {
 			data.HighTempWarning = true
 			data.DiskStatus = "Critical"
-			break
-		} else if temp.Temperature >= collector.config.TempWarning {
+		} else if level == alert.LevelWarning {
 			data.HighTempWarning = true
 			if data.DiskStatus == "Normal" {
 				data.DiskStatus = "Warning"
@@ -416,7 +740,9 @@ func (collector *DiskMonitorCollector) analyzeDiskStatus(data *DiskMonitorData)
 	}
 
 	// Analyze I/O bottleneck
-	if data.DiskUtilization >= collector.config.IOBottleneckThreshold {
+	ioLevel := collector.alerts.Evaluate("io_bottleneck", data.DiskUtilization,
+		collector.config.IOBottleneckThreshold, math.Inf(1), rules["io_bottleneck"], data.Timestamp)
+	if ioLevel > alert.LevelNormal {
 		data.IOBottleneck = true
 		if data.DiskStatus == "Normal" {
 			data.DiskStatus = "Warning"
@@ -433,44 +759,244 @@ func (collector *DiskMonitorCollector) analyzeDiskStatus(data *DiskMonitorData)
 			break
 		}
 	}
+
+	// NVMe wear, spare, media errors and controller temperature
+	collector.analyzeNVMe(data)
+
+	// A filesystem flipping to read-only means writes are already failing, so it is always critical
+	for _, partition := range data.Partitions {
+		if !partition.RemountedReadOnly || partition.Excluded {
+			continue
+		}
+		message := fmt.Sprintf("%s (%s) was remounted read-only", partition.Mountpoint, partition.Device)
+		if alert.Suppress("disk", "read_only:"+partition.Mountpoint, message, data.Timestamp) {
+			continue
+		}
+		data.ReadOnlyWarning = true
+		data.ReadOnlyMounts = append(data.ReadOnlyMounts, partition.Mountpoint)
+		data.DiskStatus = "Critical"
+	}
+
+	// Removed devices start fresh if they come back
+	collector.alerts.Forget(data.Timestamp)
+	data.AlertNotes = collector.alerts.Notes()
+}
+
+// hasLowSpace reports whether overall usage or any single partition crossed the warning threshold
+func (collector *DiskMonitorCollector) hasLowSpace(data *DiskMonitorData) bool {
+	if data.LowSpaceWarning {
+		return true
+	}
+	for _, partition := range data.Partitions {
+		if !partition.Excluded && partition.UsagePercent >= collector.config.LowSpaceWarning {
+			return true
+		}
+	}
+	return false
+}
+
+// collectCleanupCandidates measures trash, caches and old logs that could be removed
+// Nothing is deleted; results are cached for CleanupCheckInterval
+func (collector *DiskMonitorCollector) collectCleanupCandidates(data *DiskMonitorData) {
+	if collector.cleanupCache == nil || time.Since(collector.cleanupTime) >= collector.config.CleanupCheckInterval {
+		var candidates []DiskCleanupCandidate
+		for _, location := range collector.cleanupLocations(data) {
+			size, files, err := directorySize(location.path)
+			if err != nil || size == 0 {
+				continue
+			}
+			candidates = append(candidates, DiskCleanupCandidate{
+				Category: location.category,
+				Path:     location.path,
+				Size:     size,
+				Files:    files,
+				Hint:     location.hint,
+			})
+		}
+
+		sort.Slice(candidates, func(i, j int) bool {
+			return candidates[i].Size > candidates[j].Size
+		})
+
+		collector.cleanupCache = candidates
+		collector.cleanupTime = time.Now()
+	}
+
+	data.CleanupCandidates = collector.cleanupCache
+	data.ReclaimableSpace = 0
+	for _, candidate := range collector.cleanupCache {
+		data.ReclaimableSpace += candidate.Size
+	}
+}
+
+// cleanupLocations returns the well-known reclaimable locations for the current platform
+func (collector *DiskMonitorCollector) cleanupLocations(data *DiskMonitorData) []cleanupLocation {
+	home, _ := os.UserHomeDir()
+	var locations []cleanupLocation
+
+	switch runtime.GOOS {
+	case "windows":
+		localAppData := os.Getenv("LOCALAPPDATA")
+		for _, partition := range data.Partitions {
+			locations = append(locations, cleanupLocation{"Trash", filepath.Join(partition.Mountpoint+`\`, "$Recycle.Bin"), "Empty the Recycle Bin"})
+		}
+		locations = append(locations,
+			cleanupLocation{"Package Cache", filepath.Join(os.Getenv("SystemRoot"), "SoftwareDistribution", "Download"), "Run Disk Cleanup (cleanmgr) and clean up Windows Update files"},
+			cleanupLocation{"Package Cache", filepath.Join(localAppData, "pip", "Cache"), "pip cache purge"},
+			cleanupLocation{"Package Cache", filepath.Join(localAppData, "npm-cache"), "npm cache clean --force"},
+			cleanupLocation{"Temporary Files", os.TempDir(), "Delete old files from the temp folder"},
+			cleanupLocation{"Browser Cache", filepath.Join(localAppData, "Google", "Chrome", "User Data", "Default", "Cache"), "Clear browsing data in Chrome"},
+			cleanupLocation{"Browser Cache", filepath.Join(localAppData, "Microsoft", "Edge", "User Data", "Default", "Cache"), "Clear browsing data in Edge"},
+			cleanupLocation{"Browser Cache", filepath.Join(localAppData, "Mozilla", "Firefox", "Profiles"), "Clear the cache in Firefox"},
+		)
+	case "darwin":
+		locations = append(locations,
+			cleanupLocation{"Trash", filepath.Join(home, ".Trash"), "Empty the Trash"},
+			cleanupLocation{"Package Cache", filepath.Join(home, "Library", "Caches", "Homebrew"), "brew cleanup"},
+			cleanupLocation{"Package Cache", filepath.Join(home, "Library", "Caches", "pip"), "pip cache purge"},
+			cleanupLocation{"Package Cache", filepath.Join(home, ".npm", "_cacache"), "npm cache clean --force"},
+			cleanupLocation{"Browser Cache", filepath.Join(home, "Library", "Caches", "Google", "Chrome"), "Clear browsing data in Chrome"},
+			cleanupLocation{"Browser Cache", filepath.Join(home, "Library", "Caches", "Firefox"), "Clear the cache in Firefox"},
+			cleanupLocation{"Browser Cache", filepath.Join(home, "Library", "Caches", "com.apple.Safari"), "Empty caches in Safari"},
+		)
+	default:
+		locations = append(locations,
+			cleanupLocation{"Trash", filepath.Join(home, ".local", "share", "Trash"), "Empty the trash"},
+			cleanupLocation{"Package Cache", "/var/cache/apt/archives", "sudo apt-get clean"},
+			cleanupLocation{"Package Cache", "/var/cache/dnf", "sudo dnf clean all"},
+			cleanupLocation{"Package Cache", "/var/cache/yum", "sudo yum clean all"},
+			cleanupLocation{"Package Cache", "/var/cache/pacman/pkg", "sudo paccache -r"},
+			cleanupLocation{"Package Cache", filepath.Join(home, ".cache", "pip"), "pip cache purge"},
+			cleanupLocation{"Package Cache", filepath.Join(home, ".npm", "_cacache"), "npm cache clean --force"},
+			cleanupLocation{"Package Cache", filepath.Join(home, ".cache", "go-build"), "go clean -cache"},
+			cleanupLocation{"Journal Logs", "/var/log/journal", "sudo journalctl --vacuum-time=2weeks"},
+			cleanupLocation{"Browser Cache", filepath.Join(home, ".cache", "mozilla"), "Clear the cache in Firefox"},
+			cleanupLocation{"Browser Cache", filepath.Join(home, ".cache", "google-chrome"), "Clear browsing data in Chrome"},
+			cleanupLocation{"Browser Cache", filepath.Join(home, ".cache", "chromium"), "Clear browsing data in Chromium"},
+		)
+	}
+
+	// The monitor's own exports
+	if collector.config.LogsDirectory != "" {
+		locations = append(locations, cleanupLocation{"Monitor Logs", collector.config.LogsDirectory, "Delete old exports from the logs directory"})
+	}
+
+	return locations
+}
+
+// directorySize sums the sizes of all regular files below a directory
+// Unreadable entries are skipped so a partial size is still reported
+func directorySize(root string) (uint64, int, error) {
+	if _, err := os.Stat(root); err != nil {
+		return 0, 0, err
+	}
+
+	var size uint64
+	var files int
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if entry != nil && entry.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		if info, err := entry.Info(); err == nil {
+			size += uint64(info.Size())
+			files++
+		}
+		return nil
+	})
+
+	return size, files, err
 }
 
 // updateHistory updates the disk usage history
 func (collector *DiskMonitorCollector) updateHistory(data *DiskMonitorData) {
 	now := time.Now()
 	
-	// Add new data point
-	collector.history.Timestamps = append(collector.history.Timestamps, now)
-	collector.history.TotalUsage = append(collector.history.TotalUsage, data.UsagePercent)
-	collector.history.ReadSpeed = append(collector.history.ReadSpeed, data.TotalReadSpeed)
-	collector.history.WriteSpeed = append(collector.history.WriteSpeed, data.TotalWriteSpeed)
-	collector.history.IOPS = append(collector.history.IOPS, data.AverageIOPS)
-	collector.history.Utilization = append(collector.history.Utilization, data.DiskUtilization)
+	// The in-memory history keeps HistorySize samples, at most one per HistoryResolution
+	collector.history.MaxDataPoints = collector.config.HistorySize
+	if historystore.BufferSampleDue(collector.history.Timestamps, now, collector.config.HistoryResolution) {
+		// Add new data point
+		collector.history.Timestamps = append(collector.history.Timestamps, now)
+		collector.history.TotalUsage = append(collector.history.TotalUsage, data.UsagePercent)
+		collector.history.ReadSpeed = append(collector.history.ReadSpeed, data.TotalReadSpeed)
+		collector.history.WriteSpeed = append(collector.history.WriteSpeed, data.TotalWriteSpeed)
+		collector.history.IOPS = append(collector.history.IOPS, data.AverageIOPS)
+		collector.history.Utilization = append(collector.history.Utilization, data.DiskUtilization)
+	}
 
 	// Limit history size
-	if len(collector.history.Timestamps) > collector.history.MaxDataPoints {
-		collector.history.Timestamps = collector.history.Timestamps[1:]
-		collector.history.TotalUsage = collector.history.TotalUsage[1:]
-		collector.history.ReadSpeed = collector.history.ReadSpeed[1:]
-		collector.history.WriteSpeed = collector.history.WriteSpeed[1:]
-		collector.history.IOPS = collector.history.IOPS[1:]
-		collector.history.Utilization = collector.history.Utilization[1:]
+	if excess := historystore.BufferExcess(len(collector.history.Timestamps), collector.history.MaxDataPoints); excess > 0 {
+		collector.history.Timestamps = collector.history.Timestamps[excess:]
+		collector.history.TotalUsage = collector.history.TotalUsage[excess:]
+		collector.history.ReadSpeed = collector.history.ReadSpeed[excess:]
+		collector.history.WriteSpeed = collector.history.WriteSpeed[excess:]
+		collector.history.IOPS = collector.history.IOPS[excess:]
+		collector.history.Utilization = collector.history.Utilization[excess:]
 	}
 
+	// Feed the downsampled long-term history
+	collector.longHistory.Add(now, map[string]float64{
+		"total_usage": data.UsagePercent,
+		"read_speed": data.TotalReadSpeed,
+		"write_speed": data.TotalWriteSpeed,
+		"iops": data.AverageIOPS,
+		"utilization": data.DiskUtilization,
+	})
+
 	collector.history.DataPointCount = len(collector.history.Timestamps)
 }
 
-// GetDiskUsageHistory returns the current disk usage history
+// GetDiskUsageHistory returns a copy of the disk usage history
+// A collection in progress appends to the history, so it waits for that collection to finish
 func (collector *DiskMonitorCollector) GetDiskUsageHistory() *DiskUsageHistory {
-	return collector.history
+	collector.collectMutex.Lock()
+	defer collector.collectMutex.Unlock()
+	return collector.history.Clone()
+}
+
+// GetLongTermHistory returns the downsampled long-term history
+func (collector *DiskMonitorCollector) GetLongTermHistory() *historystore.Store {
+	return collector.longHistory
+}
+
+// HistoryMemoryEstimate estimates the memory an in-memory history of samples uses
+func (collector *DiskMonitorCollector) HistoryMemoryEstimate(samples int) uint64 {
+	return historystore.BufferBytes(samples, 5)
+}
+
+// SetIdle pauses process scans while idle mode is active
+func (collector *DiskMonitorCollector) SetIdle(idle bool) {
+	collector.collectMutex.Lock()
+	defer collector.collectMutex.Unlock()
+	collector.idle = idle
+}
+
+// SetSimulated switches between real system data and synthetic data
+func (collector *DiskMonitorCollector) SetSimulated(enabled bool) {
+	collector.collectMutex.Lock()
+	defer collector.collectMutex.Unlock()
+	if !enabled {
+		collector.simulator = nil
+	} else if collector.simulator == nil {
+		collector.simulator = simulate.NewSource(simulate.DefaultSeed)
+	}
 }
 
-// GetConfig returns the current configuration
-func (collector *DiskMonitorCollector) GetConfig() *DiskMonitorConfig {
-	return collector.config
+// SetProviders replaces the system data sources, e.g. with provider.NewFake() to collect without OS access
+func (collector *DiskMonitorCollector) SetProviders(providers provider.Providers) {
+	collector.collectMutex.Lock()
+	defer collector.collectMutex.Unlock()
+	collector.system = providers
 }
 
-// UpdateConfig updates the collector configuration
-func (collector *DiskMonitorCollector) UpdateConfig(config *DiskMonitorConfig) {
-	collector.config = config
+// IsSimulated returns whether the collector produces synthetic data
+func (collector *DiskMonitorCollector) IsSimulated() bool {
+	collector.collectMutex.Lock()
+	defer collector.collectMutex.Unlock()
+	return collector.simulator != nil
 }
@@ -0,0 +1,128 @@
+package diskmonitor
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ahmadreza-log/simple-monitor/alert"
+	"github.com/ahmadreza-log/simple-monitor/provider"
+	"github.com/shirou/gopsutil/v3/disk"
+)
+
+// newFakeCollector returns a collector reading fake, with alerts raised at once and only the sections
+// backed by the disk provider enabled
+func newFakeCollector(fake *provider.Fake) *DiskMonitorCollector {
+	collector := NewDiskMonitorCollector()
+	collector.SetProviders(fake.Providers())
+	config := collector.GetConfig()
+	config.ShowTrim = false
+	config.ShowTemperature = false
+	config.ShowHealth = false
+	config.ShowProcesses = false
+	config.ShowGrowingFiles = false
+	config.ShowPerformance = false
+	config.AlertRules = map[string]alert.Rule{}
+	collector.UpdateConfig(config)
+	return collector
+}
+
+// addPartition adds a mounted filesystem with its usage to fake
+func addPartition(fake *provider.Fake, device, mountpoint string, used, free uint64, opts ...string) {
+	fake.Disk.PartitionStats = append(fake.Disk.PartitionStats, disk.PartitionStat{
+		Device:     device,
+		Mountpoint: mountpoint,
+		Fstype:     "ext4",
+		Opts:       opts,
+	})
+	fake.Disk.UsageStats[mountpoint] = &disk.UsageStat{Path: mountpoint, Total: used + free, Used: used, Free: free}
+}
+
+func TestCollectDiskMonitorDataSpaceThresholds(t *testing.T) {
+	tests := []struct {
+		name   string
+		used   uint64
+		status string
+	}{
+		{name: "below warning", used: 50, status: "Normal"},
+		{name: "above warning", used: 85, status: "Warning"},
+		{name: "above critical", used: 95, status: "Critical"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := provider.NewFake()
+			addPartition(fake, "/dev/sda1", "/", tt.used<<30, (100-tt.used)<<30)
+			collector := newFakeCollector(fake)
+
+			data, err := collector.CollectDiskMonitorData()
+			if err != nil {
+				t.Fatalf("CollectDiskMonitorData() error = %v", err)
+			}
+			if data.UsagePercent != float64(tt.used) {
+				t.Errorf("UsagePercent = %v, want %v", data.UsagePercent, tt.used)
+			}
+			if data.DiskStatus != tt.status {
+				t.Errorf("DiskStatus = %q, want %q", data.DiskStatus, tt.status)
+			}
+		})
+	}
+}
+
+func TestCollectDiskMonitorDataExcludedMounts(t *testing.T) {
+	fake := provider.NewFake()
+	addPartition(fake, "/dev/sda2", "/", 40<<30, 60<<30)
+	addPartition(fake, "/dev/sda1", "/boot/efi", 1<<30, 0)
+	collector := newFakeCollector(fake)
+
+	data, err := collector.CollectDiskMonitorData()
+	if err != nil {
+		t.Fatalf("CollectDiskMonitorData() error = %v", err)
+	}
+	if len(data.Partitions) != 1 || data.Partitions[0].Mountpoint != "/" {
+		t.Errorf("Partitions = %+v, want only /", data.Partitions)
+	}
+	if data.TotalSpace != 100<<30 || data.UsagePercent != 40 {
+		t.Errorf("TotalSpace = %d, UsagePercent = %v, want 100 GiB and 40 without the EFI partition",
+			data.TotalSpace, data.UsagePercent)
+	}
+}
+
+func TestCollectDiskMonitorDataRemountedReadOnly(t *testing.T) {
+	fake := provider.NewFake()
+	addPartition(fake, "/dev/sda2", "/", 40<<30, 60<<30, "rw")
+	addPartition(fake, "/dev/sr0", "/media/cdrom", 1<<30, 0, "ro")
+	collector := newFakeCollector(fake)
+
+	if _, err := collector.CollectDiskMonitorData(); err != nil {
+		t.Fatalf("CollectDiskMonitorData() error = %v", err)
+	}
+	fake.Disk.PartitionStats[0].Opts = []string{"ro"}
+	data, err := collector.CollectDiskMonitorData()
+	if err != nil {
+		t.Fatalf("CollectDiskMonitorData() error = %v", err)
+	}
+
+	for _, partition := range data.Partitions {
+		want := partition.Mountpoint == "/"
+		if partition.RemountedReadOnly != want {
+			t.Errorf("%s: RemountedReadOnly = %v, want %v", partition.Mountpoint, partition.RemountedReadOnly, want)
+		}
+	}
+}
+
+func TestCollectDiskMonitorDataSectionErrors(t *testing.T) {
+	fake := provider.NewFake()
+	fake.Disk.Faults["Partitions"] = errors.New("permission denied")
+	fake.Disk.Faults["IOCounters"] = errors.New("not supported")
+	collector := newFakeCollector(fake)
+
+	data, err := collector.CollectDiskMonitorData()
+	if err != nil {
+		t.Fatalf("CollectDiskMonitorData() error = %v", err)
+	}
+	for _, section := range []string{"partitions", "io"} {
+		if _, failed := data.SectionErrors.Get(section); !failed {
+			t.Errorf("SectionErrors = %v, want a %s entry", data.SectionErrors, section)
+		}
+	}
+}
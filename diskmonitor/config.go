@@ -0,0 +1,53 @@
+package diskmonitor
+
+import (
+	"github.com/ahmadreza-log/simple-monitor/alert"
+	"maps"
+	"slices"
+)
+
+// Clone returns a deep copy of the configuration
+func (config *DiskMonitorConfig) Clone() *DiskMonitorConfig {
+	clone := *config
+	clone.AlertRules = alert.CloneRules(config.AlertRules)
+	clone.DeviceCapabilities = maps.Clone(config.DeviceCapabilities)
+	clone.ExcludeMounts = slices.Clone(config.ExcludeMounts)
+	return &clone
+}
+
+// UpdateConfig replaces the configuration with a copy of config; the next collection uses it
+func (collector *DiskMonitorCollector) UpdateConfig(config *DiskMonitorConfig) {
+	collector.configMutex.Lock()
+	defer collector.configMutex.Unlock()
+	collector.latest = config.Clone()
+}
+
+// GetConfig returns a copy of the current configuration
+// Changing the copy has no effect until it is passed to UpdateConfig
+func (collector *DiskMonitorCollector) GetConfig() *DiskMonitorConfig {
+	return collector.currentConfig().Clone()
+}
+
+// currentConfig returns the latest configuration without copying it, for reads outside a collection
+// It is shared with the collection, so it must not be modified
+func (collector *DiskMonitorCollector) currentConfig() *DiskMonitorConfig {
+	collector.configMutex.Lock()
+	defer collector.configMutex.Unlock()
+	return collector.latest
+}
+
+// changeConfig applies change to a copy of the current configuration and makes the copy current
+// Configurations are replaced instead of changed in place, so a collection in progress keeps a consistent one
+func (collector *DiskMonitorCollector) changeConfig(change func(config *DiskMonitorConfig)) {
+	collector.configMutex.Lock()
+	defer collector.configMutex.Unlock()
+	config := collector.latest.Clone()
+	change(config)
+	collector.latest = config
+}
+
+// adoptConfig makes the latest configuration the one the starting collection reads
+// It must be called with collectMutex held, which keeps config unchanged until the collection ends
+func (collector *DiskMonitorCollector) adoptConfig() {
+	collector.config = collector.currentConfig()
+}
@@ -0,0 +1,79 @@
+package diskmonitor
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ahmadreza-log/simple-monitor/monitortest"
+	"github.com/ahmadreza-log/simple-monitor/provider"
+)
+
+// collect returns a function running one collection of collector, for monitortest
+func collect(collector *DiskMonitorCollector) func() error {
+	return func() error {
+		_, err := collector.CollectDiskMonitorData()
+		return err
+	}
+}
+
+// TestConfigUpdatesDuringCollections changes the configuration while several collections run, the way the
+// settings menu does while the live loop, a subscriber and the attach server collect; run it with -race
+func TestConfigUpdatesDuringCollections(t *testing.T) {
+	fake := provider.NewFake()
+	addPartition(fake, "/dev/sda1", "/", 40<<30, 60<<30)
+	collector := newFakeCollector(fake)
+
+	monitortest.DuringCollections(t, collect(collector), func(i int) {
+		if i%2 == 0 {
+			config := collector.GetConfig()
+			config.RefreshInterval = time.Duration(i) * time.Second
+			collector.UpdateConfig(config)
+		} else {
+			collector.changeConfig(func(config *DiskMonitorConfig) {
+				config.RefreshInterval = time.Duration(i) * time.Second
+			})
+		}
+	})
+
+	data, err := collector.CollectDiskMonitorData()
+	if err != nil {
+		t.Fatalf("CollectDiskMonitorData() error = %v", err)
+	}
+	if data.RefreshInterval != monitortest.Rounds*time.Second {
+		t.Errorf("RefreshInterval = %v, want the last update (%ds)", data.RefreshInterval, monitortest.Rounds)
+	}
+}
+
+// TestHistoryAndSettersDuringCollections reads the history and switches idle mode, simulated data and providers
+// while several collections run, the way the managers do; run it with -race
+func TestHistoryAndSettersDuringCollections(t *testing.T) {
+	fake := provider.NewFake()
+	addPartition(fake, "/dev/sda1", "/", 40<<30, 60<<30)
+	collector := newFakeCollector(fake)
+
+	monitortest.DuringCollections(t, collect(collector), func(i int) {
+		history := collector.GetDiskUsageHistory()
+		for j := range history.TotalUsage {
+			history.TotalUsage[j] = -1
+		}
+		collector.SetIdle(i%2 == 0)
+		collector.SetSimulated(i%4 == 0 && i < monitortest.Rounds)
+		collector.SetProviders(fake.Providers())
+	})
+
+	if collector.IsSimulated() {
+		t.Errorf("IsSimulated() = true after the last SetSimulated(false)")
+	}
+	if _, err := collector.CollectDiskMonitorData(); err != nil {
+		t.Fatalf("CollectDiskMonitorData() error = %v", err)
+	}
+	history := collector.GetDiskUsageHistory()
+	if len(history.TotalUsage) == 0 {
+		t.Fatal("GetDiskUsageHistory() is empty after a collection")
+	}
+	history.TotalUsage[0] = -1
+	if slices.Contains(collector.GetDiskUsageHistory().TotalUsage, -1) {
+		t.Errorf("GetDiskUsageHistory().TotalUsage = %v, want changes to the returned copy kept out of the collector", collector.GetDiskUsageHistory().TotalUsage)
+	}
+}
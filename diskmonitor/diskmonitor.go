@@ -1,9 +1,21 @@
+// Package diskmonitor monitors disk space, I/O rates and latency, SMART health, self-tests and TRIM
+// A DiskMonitorManager collects once with Collect or in the background with Subscribe
 package diskmonitor
 
 import (
+	"encoding/json"
 	"fmt"
+	"github.com/ahmadreza-log/simple-monitor/alert"
+	"github.com/ahmadreza-log/simple-monitor/historystore"
+	"github.com/ahmadreza-log/simple-monitor/hooks"
+	"github.com/ahmadreza-log/simple-monitor/idle"
+	"github.com/ahmadreza-log/simple-monitor/recording"
+	"github.com/ahmadreza-log/simple-monitor/terminal"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 )
@@ -21,18 +33,37 @@ type DiskMonitorManager struct {
 	stopChannel   chan bool
 	refreshTicker *time.Ticker
 	lastExportTime time.Time
+
+	// Extra line shown under the live display (e.g. key shortcuts)
+	liveHint string
+
+	// Pause and step control (the display stays frozen while paused)
+	paused      atomic.Bool
+	stepChannel chan bool
+
+	// Session recorder (nil unless the live session is being recorded)
+	recorder *recording.Recorder
+
+	// Idle detection (slows refreshes while nobody is using the machine)
+	idleDetector *idle.Detector
 }
 
 // NewDiskMonitorManager creates a new instance of DiskMonitorManager
-// with default collector, displayer, and exporter configurations
-func NewDiskMonitorManager() *DiskMonitorManager {
-	return &DiskMonitorManager{
-		collector:   NewDiskMonitorCollector(),
-		displayer:   NewDiskMonitorDisplayer(),
-		exporter:    NewDiskMonitorExporter(),
-		isRunning:   false,
-		stopChannel: make(chan bool, 1),
+// with default collector, displayer, and exporter configurations, then applies the options in order
+func NewDiskMonitorManager(options ...Option) *DiskMonitorManager {
+	manager := &DiskMonitorManager{
+		collector:    NewDiskMonitorCollector(),
+		displayer:    NewDiskMonitorDisplayer(),
+		exporter:     NewDiskMonitorExporter(),
+		idleDetector: idle.NewDetector(),
+		isRunning:    false,
+		stopChannel:  make(chan bool, 1),
+		stepChannel:  make(chan bool, 1),
+	}
+	for _, option := range options {
+		option(manager)
 	}
+	return manager
 }
 
 // StartLiveMonitoring starts live disk monitoring with real-time updates
@@ -43,7 +74,7 @@ func (manager *DiskMonitorManager) StartLiveMonitoring() error {
 	}
 
 	manager.isRunning = true
-	manager.refreshTicker = time.NewTicker(manager.collector.config.RefreshInterval)
+	manager.refreshTicker = time.NewTicker(manager.collector.currentConfig().RefreshInterval)
 
 	// Set up signal handling for graceful shutdown
 	sigChan := make(chan os.Signal, 1)
@@ -53,12 +84,19 @@ func (manager *DiskMonitorManager) StartLiveMonitoring() error {
 	fmt.Println("Press Ctrl+C to stop monitoring")
 
 	// Start monitoring loop
+	loopDone := make(chan bool)
+	loopExited := make(chan bool)
 	go func() {
+		defer close(loopExited)
 		for {
 			select {
 			case <-manager.refreshTicker.C:
+				if !manager.paused.Load() {
+					manager.updateAndDisplay()
+				}
+			case <-manager.stepChannel:
 				manager.updateAndDisplay()
-			case <-manager.stopChannel:
+			case <-loopDone:
 				return
 			case <-sigChan:
 				manager.StopMonitoring()
@@ -68,7 +106,12 @@ func (manager *DiskMonitorManager) StartLiveMonitoring() error {
 	}()
 
 	// Wait for stop signal
+	// The loop is stopped separately so StopMonitoring can also be called from outside the loop,
+	// and a refresh still in progress finishes before returning
 	<-manager.stopChannel
+	close(loopDone)
+	<-loopExited
+	signal.Stop(sigChan)
 	return nil
 }
 
@@ -90,6 +133,7 @@ func (manager *DiskMonitorManager) StartSingleSnapshot() error {
 	if err != nil {
 		fmt.Printf("⚠️  Warning: Failed to export data: %v\n", err)
 	} else {
+		hooks.Exported("disk", filePath)
 		fmt.Printf("\n💾 Disk data saved to: %s\n", filePath)
 	}
 
@@ -108,6 +152,9 @@ func (manager *DiskMonitorManager) StopMonitoring() {
 		manager.refreshTicker.Stop()
 	}
 
+	// Keep everything collected in this session
+	manager.persistHistory(true)
+
 	select {
 	case manager.stopChannel <- true:
 	default:
@@ -118,6 +165,12 @@ func (manager *DiskMonitorManager) StopMonitoring() {
 
 // updateAndDisplay collects new data and updates the display
 func (manager *DiskMonitorManager) updateAndDisplay() {
+	// Refresh less often and pause process scans while the machine is idle
+	if !manager.idleDetector.ShouldCollect() {
+		return
+	}
+	manager.collector.SetIdle(manager.idleDetector.IsIdle())
+
 	// Collect new disk data
 	data, err := manager.collector.CollectDiskMonitorData()
 	if err != nil {
@@ -128,6 +181,26 @@ func (manager *DiskMonitorManager) updateAndDisplay() {
 	
 	// Display updated data
 	manager.displayer.DisplayDiskMonitorData(data)
+	if status := manager.idleDetector.Status(); status != "" {
+		fmt.Println("\n" + status)
+	}
+	if status := alert.Status(); status != "" {
+		fmt.Println("\n" + status)
+	}
+	if manager.liveHint != "" {
+		fmt.Println(manager.liveHint)
+	}
+	if manager.paused.Load() {
+		fmt.Println(terminal.PausedNotice)
+	}
+
+	// Capture the cycle for replay
+	if err := manager.recorder.Record("disk", data); err != nil {
+		fmt.Printf("\n⚠️  %v\n", err)
+	}
+
+	// Save the long-term history
+	manager.persistHistory(false)
 	
 	// Export data based on export interval
 	manager.exportDataIfNeeded(data)
@@ -136,13 +209,13 @@ func (manager *DiskMonitorManager) updateAndDisplay() {
 // exportDataIfNeeded exports disk data to file based on export interval
 func (manager *DiskMonitorManager) exportDataIfNeeded(data *DiskMonitorData) {
 	// Check if export is enabled
-	if !manager.collector.config.ExportToFile {
+	if !manager.collector.currentConfig().ExportToFile {
 		return
 	}
 
 	// Check if it's time to export based on export interval
 	now := time.Now()
-	if manager.lastExportTime.IsZero() || now.Sub(manager.lastExportTime) >= manager.collector.config.ExportInterval {
+	if manager.lastExportTime.IsZero() || now.Sub(manager.lastExportTime) >= manager.collector.currentConfig().ExportInterval {
 		manager.exportData(data)
 		manager.lastExportTime = now
 	}
@@ -150,20 +223,55 @@ func (manager *DiskMonitorManager) exportDataIfNeeded(data *DiskMonitorData) {
 
 // exportData exports disk data to file
 func (manager *DiskMonitorManager) exportData(data *DiskMonitorData) {
-	filePath, err := manager.exporter.ExportToJSON(data, "diskmonitor")
+	export := manager.exporter.ExportToJSON
+	if manager.collector.currentConfig().ExportFormat == "protobuf" {
+		export = manager.exporter.ExportToProtobuf
+	}
+	filePath, err := export(data, "diskmonitor")
 	if err != nil {
 		// Don't display error for every export to avoid cluttering the display
 		return
 	}
 	
+	hooks.Exported("disk", filePath)
 	fmt.Printf("\n💾 Data exported to: %s\n", filePath)
 }
 
-// GetDiskUsageHistory returns the current disk usage history
+// GetDiskUsageHistory returns a copy of the disk usage history
 func (manager *DiskMonitorManager) GetDiskUsageHistory() *DiskUsageHistory {
 	return manager.collector.GetDiskUsageHistory()
 }
 
+// GetLongTermHistory returns the downsampled long-term history
+func (manager *DiskMonitorManager) GetLongTermHistory() *historystore.Store {
+	return manager.collector.GetLongTermHistory()
+}
+
+// HistoryMemoryEstimate estimates the memory an in-memory history of samples uses
+func (manager *DiskMonitorManager) HistoryMemoryEstimate(samples int) uint64 {
+	return manager.collector.HistoryMemoryEstimate(samples)
+}
+
+// persistHistory writes the long-term history to logs/history
+// Unless forced (when monitoring stops), the file is rewritten at most once per save interval
+func (manager *DiskMonitorManager) persistHistory(force bool) {
+	config := manager.collector.currentConfig()
+	// Synthetic history must not end up in the real history file
+	if !config.PersistHistory || manager.collector.IsSimulated() {
+		return
+	}
+
+	interval := config.HistorySaveInterval
+	if force {
+		interval = 0
+	}
+
+	path := filepath.Join(manager.exporter.LogsDirectory, "history", "diskmonitor.json")
+
+	// Errors are ignored to avoid cluttering the display; the next save retries
+	manager.collector.GetLongTermHistory().Persist(path, interval)
+}
+
 // GetConfig returns the current configuration
 func (manager *DiskMonitorManager) GetConfig() *DiskMonitorConfig {
 	return manager.collector.GetConfig()
@@ -185,10 +293,79 @@ func (manager *DiskMonitorManager) SetDisplayOptions(showGraphics, showColors bo
 // SetExportOptions configures the exporter options
 func (manager *DiskMonitorManager) SetExportOptions(logsDir string, prettyPrint, createSubDirs bool) {
 	manager.exporter.SetLogsDirectory(logsDir)
+	manager.collector.changeConfig(func(config *DiskMonitorConfig) {
+		config.LogsDirectory = logsDir
+	})
 	manager.exporter.SetPrettyPrint(prettyPrint)
 	manager.exporter.SetCreateSubDirs(createSubDirs)
 }
 
+// SetHeaderOptions configures CSV header keys and export metadata rows
+func (manager *DiskMonitorManager) SetHeaderOptions(stableKeys, headerMetadata bool) {
+	manager.exporter.SetStableKeys(stableKeys)
+	manager.exporter.SetHeaderMetadata(headerMetadata)
+}
+
+// SetIdleMode enables or disables idle mode
+// While idle, refreshes are slowed down and process scans are paused until activity resumes
+func (manager *DiskMonitorManager) SetIdleMode(enabled bool) {
+	manager.idleDetector.SetEnabled(enabled)
+	if !enabled {
+		manager.collector.SetIdle(false)
+	}
+}
+
+// SetSimulationMode switches the monitor to synthetic data (--simulate)
+// Simulated data needs no live system or elevated permissions, which suits demos and displayer development
+func (manager *DiskMonitorManager) SetSimulationMode(enabled bool) {
+	manager.collector.SetSimulated(enabled)
+}
+
+// IsSimulated returns whether the monitor shows synthetic data
+func (manager *DiskMonitorManager) IsSimulated() bool {
+	return manager.collector.IsSimulated()
+}
+
+// GetIdleState returns the most recent idle check
+func (manager *DiskMonitorManager) GetIdleState() idle.State {
+	return manager.idleDetector.State()
+}
+
+// GetIdleConfig returns the idle detection configuration
+func (manager *DiskMonitorManager) GetIdleConfig() idle.Config {
+	return manager.idleDetector.GetConfig()
+}
+
+// GetSelfTestDrives lists the monitored drives with the state of their SMART self-tests
+func (manager *DiskMonitorManager) GetSelfTestDrives() ([]DiskSelfTestInfo, error) {
+	return manager.collector.SelfTestDrives()
+}
+
+// QueueSelfTest queues a short or long SMART self-test on a drive; live monitoring tracks its progress
+func (manager *DiskMonitorManager) QueueSelfTest(device, kind string) error {
+	return manager.collector.QueueSelfTest(device, kind)
+}
+
+// GetCalibrationTargets lists the drives that can be calibrated, with their recorded capabilities
+func (manager *DiskMonitorManager) GetCalibrationTargets() ([]CalibrationTarget, error) {
+	return manager.collector.CalibrationTargets()
+}
+
+// CalibrateDevice runs the throughput benchmark on a drive and returns the measured capability without recording it
+func (manager *DiskMonitorManager) CalibrateDevice(target CalibrationTarget) (DeviceCapability, error) {
+	return manager.collector.Calibrate(target)
+}
+
+// SetDeviceCapability records a drive's maximum throughput (benchmarked or rated)
+func (manager *DiskMonitorManager) SetDeviceCapability(device string, capability DeviceCapability) {
+	manager.collector.SetDeviceCapability(device, capability)
+}
+
+// ClearDeviceCapability forgets a drive's recorded maximum throughput
+func (manager *DiskMonitorManager) ClearDeviceCapability(device string) {
+	manager.collector.ClearDeviceCapability(device)
+}
+
 // ExportToFile exports current disk data to a file
 func (manager *DiskMonitorManager) ExportToFile(format string) error {
 	// Collect current data
@@ -202,6 +379,8 @@ func (manager *DiskMonitorManager) ExportToFile(format string) error {
 	switch format {
 	case "json":
 		filePath, err = manager.exporter.ExportToJSON(data, "diskmonitor")
+	case "protobuf":
+		filePath, err = manager.exporter.ExportToProtobuf(data, "diskmonitor")
 	case "csv":
 		filePath, err = manager.exporter.ExportToCSV(data, "diskmonitor")
 	case "txt":
@@ -214,6 +393,7 @@ func (manager *DiskMonitorManager) ExportToFile(format string) error {
 		return fmt.Errorf("failed to export data: %w", err)
 	}
 
+	hooks.Exported("disk", filePath)
 	fmt.Printf("💾 Disk data exported to: %s\n", filePath)
 	return nil
 }
@@ -238,7 +418,87 @@ func (manager *DiskMonitorManager) GetDiskAlerts() (bool, bool, bool, bool, erro
 	return data.LowSpaceWarning, data.HighTempWarning, data.HealthWarning, data.IOBottleneck, nil
 }
 
+// Collect collects the disk data once, without displaying or exporting it
+func (manager *DiskMonitorManager) Collect() (*DiskMonitorData, error) {
+	return manager.collector.CollectDiskMonitorData()
+}
+
+// Subscribe collects every interval in the background and sends the data on the returned channel until the
+// returned function is called, which closes it; a zero interval uses the configured refresh interval
+// Nothing is displayed, exported or recorded. A subscriber that falls behind only gets the newest data,
+// and failed collections are skipped (Collect returns their error)
+func (manager *DiskMonitorManager) Subscribe(interval time.Duration) (<-chan *DiskMonitorData, func()) {
+	if interval <= 0 {
+		interval = manager.collector.currentConfig().RefreshInterval
+	}
+	updates := make(chan *DiskMonitorData, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(updates)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if data, err := manager.Collect(); err == nil {
+				select {
+				case <-updates:
+				default:
+				}
+				updates <- data
+			}
+			select {
+			case <-ticker.C:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var stop sync.Once
+	return updates, func() {
+		stop.Do(func() { close(done) })
+	}
+}
+
 // IsRunning returns whether the disk monitor is currently running
 func (manager *DiskMonitorManager) IsRunning() bool {
 	return manager.isRunning
 }
+
+// IsEnabled returns whether the monitor is switched on in its configuration
+func (manager *DiskMonitorManager) IsEnabled() bool {
+	return manager.collector.currentConfig().Enabled
+}
+
+// SetLiveHint sets a line shown under each live refresh, or clears it when empty
+func (manager *DiskMonitorManager) SetLiveHint(hint string) {
+	manager.liveHint = hint
+}
+
+// SetPaused freezes the live display on its last refresh, or resumes refreshing
+// Nothing is collected while paused, so history, exports and alerts wait too
+func (manager *DiskMonitorManager) SetPaused(paused bool) {
+	manager.paused.Store(paused)
+}
+
+// Step collects and shows one refresh, for stepping through a paused display
+func (manager *DiskMonitorManager) Step() {
+	select {
+	case manager.stepChannel <- true:
+	default:
+	}
+}
+
+// SetRecorder records every live collection cycle to recorder (nil stops recording)
+func (manager *DiskMonitorManager) SetRecorder(recorder *recording.Recorder) {
+	manager.recorder = recorder
+}
+
+// DisplayRecordedData shows one recorded collection cycle the way the live display showed it
+func (manager *DiskMonitorManager) DisplayRecordedData(content []byte) error {
+	var data DiskMonitorData
+	if err := json.Unmarshal(content, &data); err != nil {
+		return fmt.Errorf("failed to decode recorded disk data: %w", err)
+	}
+	manager.displayer.DisplayDiskMonitorData(&data)
+	return nil
+}
@@ -2,6 +2,8 @@ package diskmonitor
 
 import (
 	"fmt"
+	"github.com/ahmadreza-log/simple-monitor/partial"
+	"github.com/ahmadreza-log/simple-monitor/terminal"
 	"strings"
 )
 
@@ -11,7 +13,8 @@ type DiskMonitorDisplayer struct {
 	// Display configuration
 	ShowGraphics bool // Whether to show graphical elements
 	ShowColors   bool // Whether to use colored output
-	BarWidth     int  // Width of progress bars
+	BarWidth     int  // Width of progress bars (when AutoWidth is off)
+	AutoWidth    bool // Whether to fit bars, separators and columns to the terminal width
 	MaxProcesses int  // Maximum number of processes to display
 
 	// Color codes for different elements
@@ -33,6 +36,7 @@ func NewDiskMonitorDisplayer() *DiskMonitorDisplayer {
 		ShowGraphics: true,
 		ShowColors:   true,
 		BarWidth:     50,
+		AutoWidth:    true,
 		MaxProcesses: 10,
 		ColorReset:   "\033[0m",
 		ColorRed:     "\033[31m",
@@ -58,36 +62,75 @@ func (displayer *DiskMonitorDisplayer) DisplayDiskMonitorData(data *DiskMonitorD
 	displayer.displayOverallDiskUsage(data)
 
 	// Display partition information
-	if len(data.Partitions) > 0 {
+	if reason, failed := data.SectionErrors.Get("partitions"); failed {
+		displayer.displayUnavailable("🔧 DISK PARTITIONS", reason)
+	} else if len(data.Partitions) > 0 {
 		displayer.displayPartitionInfo(data)
 	}
 
 	// Display I/O statistics
-	if len(data.DiskIO) > 0 {
+	if reason, failed := data.SectionErrors.Get("io"); failed {
+		displayer.displayUnavailable("⚡ DISK I/O STATISTICS", reason)
+	} else if len(data.DiskIO) > 0 {
 		displayer.displayIOInfo(data)
 	}
 
 	// Display temperature information
-	if len(data.DiskTemperatures) > 0 {
+	if reason, failed := data.SectionErrors.Get("temperature"); failed {
+		displayer.displayUnavailable("🌡️  DISK TEMPERATURE", reason)
+	} else if len(data.DiskTemperatures) > 0 {
 		displayer.displayTemperatureInfo(data)
 	}
 
 	// Display health information
-	if len(data.DiskHealth) > 0 {
+	if reason, failed := data.SectionErrors.Get("health"); failed {
+		displayer.displayUnavailable("💚 DISK HEALTH", reason)
+	} else if len(data.DiskHealth) > 0 {
 		displayer.displayHealthInfo(data)
 	}
 
+	// Display NVMe controller health
+	if reason, failed := data.SectionErrors.Get("nvme"); failed {
+		displayer.displayUnavailable("🧬 NVMe HEALTH", reason)
+	} else if len(data.NVMe) > 0 {
+		displayer.displayNVMeInfo(data)
+	}
+
+	// Display TRIM status of SSD filesystems
+	if reason, failed := data.SectionErrors.Get("trim"); failed {
+		displayer.displayUnavailable("✂️  TRIM / DISCARD", reason)
+	} else if len(data.Trim) > 0 {
+		displayer.displayTrimInfo(data)
+	}
+
 	// Display performance metrics
 	displayer.displayPerformanceMetrics(data)
 
 	// Display top processes
-	if len(data.TopProcesses) > 0 {
+	if reason, failed := data.SectionErrors.Get("processes"); failed {
+		displayer.displayUnavailable("🔥 TOP DISK PROCESSES", reason)
+	} else if len(data.TopProcesses) > 0 {
 		displayer.displayTopProcesses(data)
 	}
 
+	// Display fastest-growing open files
+	if len(data.GrowingFiles) > 0 {
+		displayer.displayGrowingFiles(data)
+	}
+
+	// Display reclaimable space suggestions
+	if len(data.CleanupCandidates) > 0 {
+		displayer.displayCleanupSuggestions(data)
+	}
+
 	// Display disk status and alerts
 	displayer.displayDiskStatus(data)
 
+	// Display runbook notes of raised alerts
+	if len(data.AlertNotes) > 0 {
+		displayer.displayAlertNotes(data)
+	}
+
 	// Display footer
 	displayer.displayFooter(data)
 }
@@ -95,7 +138,12 @@ func (displayer *DiskMonitorDisplayer) DisplayDiskMonitorData(data *DiskMonitorD
 // displayHeader displays the disk monitor header
 func (displayer *DiskMonitorDisplayer) displayHeader(data *DiskMonitorData) {
 	fmt.Println(displayer.colorize("💿 DISK MONITOR", displayer.ColorBold+displayer.ColorCyan))
-	fmt.Println(strings.Repeat("=", 80))
+	fmt.Println(displayer.rule("="))
+
+	// Synthetic data must never be mistaken for a real system
+	if data.Simulated {
+		fmt.Println(displayer.colorize("🧪 Simulated data (--simulate)", displayer.ColorMagenta))
+	}
 
 	// Disk summary
 	fmt.Printf("%sTotal Space: %s%s\n",
@@ -113,19 +161,27 @@ func (displayer *DiskMonitorDisplayer) displayHeader(data *DiskMonitorData) {
 		displayer.colorize(displayer.formatBytes(data.FreeSpace), displayer.ColorGreen),
 		displayer.colorize("", displayer.ColorReset))
 
+	// Root-reserved blocks are free but not available to regular users
+	if data.ReservedSpace > 0 {
+		fmt.Printf("%sReserved (root): %s%s\n",
+			displayer.colorize("", displayer.ColorBold),
+			displayer.colorize(displayer.formatBytes(data.ReservedSpace), displayer.ColorYellow),
+			displayer.colorize("", displayer.ColorReset))
+	}
+
 	fmt.Printf("%sUsage: %s%.2f%%%s\n",
 		displayer.colorize("", displayer.ColorBold),
 		displayer.colorize("", displayer.ColorYellow),
 		data.UsagePercent,
 		displayer.colorize("", displayer.ColorReset))
 
-	fmt.Println(strings.Repeat("=", 80))
+	fmt.Println(displayer.rule("="))
 }
 
 // displayOverallDiskUsage displays overall disk usage with graphical bars
 func (displayer *DiskMonitorDisplayer) displayOverallDiskUsage(data *DiskMonitorData) {
 	fmt.Println("\n📊 OVERALL DISK USAGE")
-	fmt.Println(strings.Repeat("-", 50))
+	fmt.Println(displayer.sectionRule())
 
 	// Overall usage bar
 	displayer.displayUsageBar("Disk Usage", data.UsagePercent, displayer.getDiskUsageColor(data.UsagePercent))
@@ -142,7 +198,7 @@ func (displayer *DiskMonitorDisplayer) displayOverallDiskUsage(data *DiskMonitor
 // displayPartitionInfo displays partition information
 func (displayer *DiskMonitorDisplayer) displayPartitionInfo(data *DiskMonitorData) {
 	fmt.Println("\n🔧 DISK PARTITIONS")
-	fmt.Println(strings.Repeat("-", 80))
+	fmt.Println(displayer.rule("-"))
 
 	// Header
 	fmt.Printf("%s%-15s %-20s %-8s %-12s %-12s %-8s %s\n",
@@ -155,7 +211,7 @@ func (displayer *DiskMonitorDisplayer) displayPartitionInfo(data *DiskMonitorDat
 		"Usage%",
 		displayer.colorize("", displayer.ColorReset))
 
-	fmt.Println(strings.Repeat("-", 80))
+	fmt.Println(displayer.rule("-"))
 
 	// Display partitions
 	for _, partition := range data.Partitions {
@@ -168,10 +224,15 @@ func (displayer *DiskMonitorDisplayer) displayPartitionInfo(data *DiskMonitorDat
 		// Color code based on usage
 		usageColor := displayer.getDiskUsageColor(partition.UsagePercent)
 
-		fmt.Printf("%s%-15s %-20s %-8s %s%-12s %s%-12s %s%-8.2f %s\n",
+		// A partition remounted read-only is highlighted in red
+		device := fmt.Sprintf("%-15s %-20s", partition.Device, mountpoint)
+		if partition.RemountedReadOnly && !partition.Excluded {
+			device = displayer.colorize(device, displayer.ColorRed)
+		}
+
+		fmt.Printf("%s%s %-8s %s%-12s %s%-12s %s%-8.2f %s\n",
 			displayer.colorize("", displayer.ColorBold),
-			partition.Device,
-			mountpoint,
+			device,
 			partition.Fstype,
 			displayer.colorize("", displayer.ColorWhite),
 			displayer.formatBytes(partition.Total),
@@ -181,6 +242,30 @@ func (displayer *DiskMonitorDisplayer) displayPartitionInfo(data *DiskMonitorDat
 			partition.UsagePercent,
 			displayer.colorize("", displayer.ColorReset))
 
+		// Read-only state; a remount usually means the kernel hit disk errors
+		if partition.RemountedReadOnly {
+			fmt.Printf("  %s\n", displayer.colorize("🔒 REMOUNTED READ-ONLY: writes are failing, check the kernel log for disk errors", displayer.ColorRed))
+		} else if partition.ReadOnly {
+			fmt.Printf("  %s\n", displayer.colorize("(read-only)", displayer.ColorWhite))
+		}
+
+		// Excluded mounts are only listed for reference
+		if partition.Excluded {
+			fmt.Printf("  %s(excluded from totals and alerts)%s\n",
+				displayer.colorize("", displayer.ColorWhite),
+				displayer.colorize("", displayer.ColorReset))
+		}
+
+		// Available vs true free space when blocks are reserved for root
+		if partition.Reserved > 0 {
+			fmt.Printf("  %sAvailable: %s, Reserved (root): %s, True Free: %s%s\n",
+				displayer.colorize("", displayer.ColorCyan),
+				displayer.formatBytes(partition.Free),
+				displayer.formatBytes(partition.Reserved),
+				displayer.formatBytes(partition.TrueFree),
+				displayer.colorize("", displayer.ColorReset))
+		}
+
 		// Partition usage bar
 		displayer.displayUsageBar("  "+partition.Device, partition.UsagePercent, usageColor)
 	}
@@ -189,7 +274,7 @@ func (displayer *DiskMonitorDisplayer) displayPartitionInfo(data *DiskMonitorDat
 // displayIOInfo displays disk I/O statistics
 func (displayer *DiskMonitorDisplayer) displayIOInfo(data *DiskMonitorData) {
 	fmt.Println("\n⚡ DISK I/O STATISTICS")
-	fmt.Println(strings.Repeat("-", 80))
+	fmt.Println(displayer.rule("-"))
 
 	// Header
 	fmt.Printf("%s%-15s %-12s %-12s %-8s %-8s %-8s %s\n",
@@ -202,9 +287,10 @@ func (displayer *DiskMonitorDisplayer) displayIOInfo(data *DiskMonitorData) {
 		"Reads",
 		displayer.colorize("", displayer.ColorReset))
 
-	fmt.Println(strings.Repeat("-", 80))
+	fmt.Println(displayer.rule("-"))
 
 	// Display I/O statistics
+	calibrated := false
 	for _, io := range data.DiskIO {
 		// Color code based on utilization
 		utilColor := displayer.getUtilizationColor(io.Utilization)
@@ -224,9 +310,20 @@ func (displayer *DiskMonitorDisplayer) displayIOInfo(data *DiskMonitorData) {
 			io.ReadCount,
 			displayer.colorize("", displayer.ColorReset))
 
+		// Calibrated devices show how much of their measured or rated throughput each direction uses
+		if io.Calibrated {
+			displayer.displayUsageBar("  "+io.DeviceName+" read", io.ReadPercent, displayer.getUtilizationColor(io.ReadPercent))
+			displayer.displayUsageBar("  "+io.DeviceName+" write", io.WritePercent, displayer.getUtilizationColor(io.WritePercent))
+			calibrated = true
+			continue
+		}
+
 		// I/O utilization bar
 		displayer.displayUsageBar("  "+io.DeviceName, io.Utilization, utilColor)
 	}
+	if calibrated {
+		fmt.Println("  Util% of calibrated devices is their busiest direction as a share of its maximum throughput")
+	}
 
 	// Overall I/O summary
 	fmt.Printf("\n%sTotal Read Speed: %s%.2f MB/s%s\n",
@@ -251,7 +348,7 @@ func (displayer *DiskMonitorDisplayer) displayIOInfo(data *DiskMonitorData) {
 // displayTemperatureInfo displays disk temperature information
 func (displayer *DiskMonitorDisplayer) displayTemperatureInfo(data *DiskMonitorData) {
 	fmt.Println("\n🌡️  DISK TEMPERATURE")
-	fmt.Println(strings.Repeat("-", 50))
+	fmt.Println(displayer.sectionRule())
 
 	for _, temp := range data.DiskTemperatures {
 		fmt.Printf("%sDevice: %s%s%s\n",
@@ -286,26 +383,27 @@ func (displayer *DiskMonitorDisplayer) displayTemperatureInfo(data *DiskMonitorD
 // displayHealthInfo displays disk health information
 func (displayer *DiskMonitorDisplayer) displayHealthInfo(data *DiskMonitorData) {
 	fmt.Println("\n💚 DISK HEALTH")
-	fmt.Println(strings.Repeat("-", 80))
+	fmt.Println(displayer.rule("-"))
 
 	// Header
-	fmt.Printf("%s%-15s %-10s %-12s %-12s %-8s %s\n",
+	fmt.Printf("%s%-15s %-10s %-12s %-12s %-8s %-14s %s\n",
 		displayer.colorize("", displayer.ColorBold),
 		"Device",
 		"Health",
 		"Power On",
 		"Cycles",
 		"Wear%",
+		"Self-Test",
 		displayer.colorize("", displayer.ColorReset))
 
-	fmt.Println(strings.Repeat("-", 80))
+	fmt.Println(displayer.rule("-"))
 
 	// Display health information
 	for _, health := range data.DiskHealth {
 		// Color code based on health status
 		healthColor := displayer.getHealthStatusColor(health.HealthStatus)
 
-		fmt.Printf("%s%-15s %s%-10s %s%-12d %s%-12d %s%-8.1f %s\n",
+		fmt.Printf("%s%-15s %s%-10s %s%-12d %s%-12d %s%-8.1f %s%-14s %s\n",
 			displayer.colorize("", displayer.ColorBold),
 			health.DeviceName,
 			healthColor,
@@ -316,6 +414,8 @@ func (displayer *DiskMonitorDisplayer) displayHealthInfo(data *DiskMonitorData)
 			health.PowerCycleCount,
 			displayer.colorize("", displayer.ColorYellow),
 			health.WearLeveling,
+			displayer.getSelfTestColor(health.SelfTest),
+			health.SelfTest,
 			displayer.colorize("", displayer.ColorReset))
 
 		// Health details
@@ -335,10 +435,105 @@ func (displayer *DiskMonitorDisplayer) displayHealthInfo(data *DiskMonitorData)
 	}
 }
 
+// displayNVMeInfo displays the health log of each NVMe controller
+func (displayer *DiskMonitorDisplayer) displayNVMeInfo(data *DiskMonitorData) {
+	fmt.Println("\n🧬 NVMe HEALTH")
+	fmt.Println(displayer.rule("-"))
+
+	for _, nvme := range data.NVMe {
+		if nvme.Error != "" {
+			fmt.Printf("%s%s%s: %s\n",
+				displayer.colorize("", displayer.ColorBold),
+				nvme.Device,
+				displayer.colorize("", displayer.ColorReset),
+				displayer.colorize(nvme.Error, displayer.ColorRed))
+			continue
+		}
+
+		statusColor := displayer.getTemperatureStatusColor(nvme.Status)
+		fmt.Printf("%s%s %s%s  %s\n",
+			displayer.colorize("", displayer.ColorBold),
+			nvme.Device,
+			nvme.Model,
+			displayer.colorize("", displayer.ColorReset),
+			displayer.colorize(nvme.Status, statusColor))
+
+		displayer.displayUsageBar("  Wear (used)", nvme.PercentageUsed, displayer.getDiskUsageColor(nvme.PercentageUsed))
+		if nvme.NamespaceSize > 0 {
+			displayer.displayUsageBar("  Namespace", nvme.NamespaceUtilization, displayer.getDiskUsageColor(nvme.NamespaceUtilization))
+			fmt.Printf("  Allocated: %s of %s\n",
+				displayer.formatBytes(nvme.NamespaceUsed),
+				displayer.formatBytes(nvme.NamespaceSize))
+		}
+
+		spareColor := displayer.ColorGreen
+		if nvme.AvailableSpare <= nvme.AvailableSpareThreshold {
+			spareColor = displayer.ColorRed
+		}
+		mediaColor := displayer.ColorGreen
+		if nvme.MediaErrors > 0 {
+			mediaColor = displayer.ColorRed
+		}
+		fmt.Printf("  Controller Temp: %s  Spare: %s (threshold %.0f%%)  Media Errors: %s  Error Log: %d\n",
+			displayer.colorize(fmt.Sprintf("%.0f°C", nvme.Temperature), displayer.getTemperatureColor(nvme.Temperature)),
+			displayer.colorize(fmt.Sprintf("%.0f%%", nvme.AvailableSpare), spareColor),
+			nvme.AvailableSpareThreshold,
+			displayer.colorize(fmt.Sprintf("%d", nvme.MediaErrors), mediaColor),
+			nvme.ErrorLogEntries)
+		fmt.Printf("  Power On: %d h  Unsafe Shutdowns: %d  Read: %s  Written: %s\n",
+			nvme.PowerOnHours,
+			nvme.UnsafeShutdowns,
+			displayer.formatBytes(nvme.DataRead),
+			displayer.formatBytes(nvme.DataWritten))
+
+		if nvme.CriticalWarning != 0 {
+			fmt.Printf("  %s\n", displayer.colorize(fmt.Sprintf("⚠️  Controller critical warning 0x%02x: back up this drive", nvme.CriticalWarning), displayer.ColorRed))
+		}
+		if nvme.SelfTest != "" {
+			fmt.Printf("  Self-Test: %s\n", displayer.colorize(nvme.SelfTest, displayer.getSelfTestColor(nvme.SelfTest)))
+		}
+	}
+}
+
+// displayTrimInfo displays whether each SSD filesystem is trimmed and when fstrim last ran
+func (displayer *DiskMonitorDisplayer) displayTrimInfo(data *DiskMonitorData) {
+	fmt.Println("\n✂️  TRIM / DISCARD")
+	fmt.Println(displayer.rule("-"))
+
+	for _, trim := range data.Trim {
+		fmt.Printf("%s%-20s%s %-18s %-7s %s\n",
+			displayer.colorize("", displayer.ColorBold),
+			trim.Mountpoint,
+			displayer.colorize("", displayer.ColorReset),
+			trim.Device,
+			trim.Fstype,
+			displayer.colorize(trim.Status, displayer.getTrimStatusColor(trim.Status)))
+
+		lastRun := ""
+		if !trim.Discard && !trim.LastTrim.IsZero() {
+			lastRun = fmt.Sprintf(" (last fstrim %s ago)", formatTrimAge(data.Timestamp.Sub(trim.LastTrim).Hours()))
+		}
+		fmt.Printf("  %s%s\n", trim.Message, lastRun)
+	}
+
+	// Slow I/O on an untrimmed SSD is often the drive reorganizing blocks it believes are still in use
+	if data.TrimWarning && data.IOBottleneck {
+		fmt.Println(displayer.colorize("⚠️  Missing TRIM may be causing the I/O bottleneck: run fstrim -av and check again", displayer.ColorYellow))
+	}
+}
+
+// formatTrimAge formats the time since the last fstrim in days, or hours for the first two days
+func formatTrimAge(hours float64) string {
+	if hours >= 48 {
+		return fmt.Sprintf("%d days", int(hours/24+0.5))
+	}
+	return fmt.Sprintf("%d hours", int(hours))
+}
+
 // displayPerformanceMetrics displays disk performance metrics
 func (displayer *DiskMonitorDisplayer) displayPerformanceMetrics(data *DiskMonitorData) {
 	fmt.Println("\n📈 PERFORMANCE METRICS")
-	fmt.Println(strings.Repeat("-", 50))
+	fmt.Println(displayer.sectionRule())
 
 	// Overall utilization
 	displayer.displayUsageBar("Disk Utilization", data.DiskUtilization, displayer.getUtilizationColor(data.DiskUtilization))
@@ -366,7 +561,7 @@ func (displayer *DiskMonitorDisplayer) displayPerformanceMetrics(data *DiskMonit
 // displayTopProcesses displays top disk-consuming processes
 func (displayer *DiskMonitorDisplayer) displayTopProcesses(data *DiskMonitorData) {
 	fmt.Println("\n🔥 TOP DISK PROCESSES")
-	fmt.Println(strings.Repeat("-", 80))
+	fmt.Println(displayer.rule("-"))
 
 	// Header
 	fmt.Printf("%s%-8s %-20s %-12s %-12s %-8s %-8s %s\n",
@@ -379,7 +574,7 @@ func (displayer *DiskMonitorDisplayer) displayTopProcesses(data *DiskMonitorData
 		"Total IO",
 		displayer.colorize("", displayer.ColorReset))
 
-	fmt.Println(strings.Repeat("-", 80))
+	fmt.Println(displayer.rule("-"))
 
 	// Display processes
 	for i, process := range data.TopProcesses {
@@ -412,10 +607,81 @@ func (displayer *DiskMonitorDisplayer) displayTopProcesses(data *DiskMonitorData
 	}
 }
 
+// displayGrowingFiles displays the open files that are growing fastest
+func (displayer *DiskMonitorDisplayer) displayGrowingFiles(data *DiskMonitorData) {
+	fmt.Println("\n📈 FASTEST-GROWING FILES")
+	fmt.Println(displayer.rule("-"))
+
+	// Header
+	fmt.Printf("%s%-12s %-10s %-20s %s%s\n",
+		displayer.colorize("", displayer.ColorBold),
+		"Rate",
+		"Size",
+		"Process",
+		"Path",
+		displayer.colorize("", displayer.ColorReset))
+
+	for _, file := range data.GrowingFiles {
+		// Truncate long paths from the left so the file name stays visible
+		path := terminal.TruncateLeft(file.Path, displayer.lineWidth()-45)
+
+		owner := fmt.Sprintf("%s (%d)", file.ProcessName, file.PID)
+		if len(owner) > 20 {
+			owner = owner[:17] + "..."
+		}
+
+		fmt.Printf("%s%-12s%s %-10s %-20s %s\n",
+			displayer.getGrowthRateColor(file.GrowthRate),
+			displayer.formatBytes(uint64(file.GrowthRate))+"/s",
+			displayer.colorize("", displayer.ColorReset),
+			displayer.formatBytes(file.Size),
+			owner,
+			path)
+	}
+}
+
+// displayCleanupSuggestions displays reclaimable space candidates found while space is low
+func (displayer *DiskMonitorDisplayer) displayCleanupSuggestions(data *DiskMonitorData) {
+	fmt.Println("\n🧹 CLEANUP SUGGESTIONS")
+	fmt.Println(displayer.rule("-"))
+
+	fmt.Printf("%sReclaimable Space: %s%s%s (nothing is deleted automatically)\n",
+		displayer.colorize("", displayer.ColorBold),
+		displayer.colorize("", displayer.ColorGreen),
+		displayer.formatBytes(data.ReclaimableSpace),
+		displayer.colorize("", displayer.ColorReset))
+
+	// Header
+	fmt.Printf("%s%-16s %-12s %-8s %s%s\n",
+		displayer.colorize("", displayer.ColorBold),
+		"Category",
+		"Size",
+		"Files",
+		"Path",
+		displayer.colorize("", displayer.ColorReset))
+
+	for _, candidate := range data.CleanupCandidates {
+		// Truncate long paths
+		path := terminal.TruncateLeft(candidate.Path, displayer.lineWidth()-40)
+
+		fmt.Printf("%-16s %s%-12s%s %-8d %s\n",
+			candidate.Category,
+			displayer.colorize("", displayer.ColorYellow),
+			displayer.formatBytes(candidate.Size),
+			displayer.colorize("", displayer.ColorReset),
+			candidate.Files,
+			path)
+		fmt.Printf("  %s↳ %s%s\n",
+			displayer.colorize("", displayer.ColorCyan),
+			candidate.Hint,
+			displayer.colorize("", displayer.ColorReset))
+	}
+}
+
 // displayDiskStatus displays disk status and alerts
 func (displayer *DiskMonitorDisplayer) displayDiskStatus(data *DiskMonitorData) {
 	fmt.Println("\n🚨 DISK STATUS & ALERTS")
-	fmt.Println(strings.Repeat("-", 50))
+	fmt.Println(displayer.sectionRule())
 
 	// Disk status
 	statusColor := displayer.getDiskStatusColor(data.DiskStatus)
@@ -464,6 +730,38 @@ func (displayer *DiskMonitorDisplayer) displayDiskStatus(data *DiskMonitorData)
 			displayer.colorize("", displayer.ColorReset))
 	}
 
+	// Read-only remount
+	if data.ReadOnlyWarning {
+		fmt.Printf("%s🔒 Read-Only Remount: %s%s\n",
+			displayer.colorize("", displayer.ColorBold),
+			displayer.colorize("DETECTED ("+strings.Join(data.ReadOnlyMounts, ", ")+")", displayer.ColorRed),
+			displayer.colorize("", displayer.ColorReset))
+	} else {
+		fmt.Printf("%s✅ Read-Only Remount: %sNONE%s\n",
+			displayer.colorize("", displayer.ColorBold),
+			displayer.colorize("", displayer.ColorGreen),
+			displayer.colorize("", displayer.ColorReset))
+	}
+
+	// TRIM
+	if data.TrimWarning {
+		var stale []string
+		for _, trim := range data.Trim {
+			if trim.Status == "Warning" {
+				stale = append(stale, trim.Mountpoint)
+			}
+		}
+		fmt.Printf("%s✂️  TRIM: %s%s\n",
+			displayer.colorize("", displayer.ColorBold),
+			displayer.colorize("NOT RECENT ("+strings.Join(stale, ", ")+")", displayer.ColorYellow),
+			displayer.colorize("", displayer.ColorReset))
+	} else if len(data.Trim) > 0 {
+		fmt.Printf("%s✅ TRIM: %sOK%s\n",
+			displayer.colorize("", displayer.ColorBold),
+			displayer.colorize("", displayer.ColorGreen),
+			displayer.colorize("", displayer.ColorReset))
+	}
+
 	// I/O bottleneck
 	if data.IOBottleneck {
 		fmt.Printf("%s⚡ I/O Bottleneck: %sDETECTED%s\n",
@@ -480,7 +778,7 @@ func (displayer *DiskMonitorDisplayer) displayDiskStatus(data *DiskMonitorData)
 
 // displayUsageBar displays a graphical usage bar
 func (displayer *DiskMonitorDisplayer) displayUsageBar(label string, percentage float64, color string, customWidth ...int) {
-	width := displayer.BarWidth
+	width := displayer.barWidth()
 	if len(customWidth) > 0 {
 		width = customWidth[0]
 	}
@@ -498,16 +796,31 @@ func (displayer *DiskMonitorDisplayer) displayUsageBar(label string, percentage
 		displayer.colorize("", displayer.ColorBold),
 		label,
 		displayer.colorize("", displayer.ColorWhite),
-		displayer.colorize(bar, color),
+		displayer.colorize(bar, terminal.WithoutMarker(color)),
 		displayer.colorize("", displayer.ColorWhite),
 		color,
 		percentage,
 		displayer.colorize("", displayer.ColorReset))
 }
 
+// displayAlertNotes shows the note configured for each raised alert, so the next step is on screen
+func (displayer *DiskMonitorDisplayer) displayAlertNotes(data *DiskMonitorData) {
+	fmt.Println("\n📘 RUNBOOK NOTES")
+	fmt.Println(displayer.sectionRule())
+
+	for _, note := range data.AlertNotes {
+		fmt.Printf("%s%s (%s):%s %s\n",
+			displayer.getDiskStatusColor(note.Level),
+			note.Key,
+			note.Level,
+			displayer.colorize("", displayer.ColorReset),
+			note.Text)
+	}
+}
+
 // displayFooter displays the disk monitor footer
 func (displayer *DiskMonitorDisplayer) displayFooter(data *DiskMonitorData) {
-	fmt.Println(strings.Repeat("=", 80))
+	fmt.Println(displayer.rule("="))
 	fmt.Printf("%sLast Updated: %s%s%s\n",
 		displayer.colorize("", displayer.ColorBold),
 		displayer.colorize("", displayer.ColorCyan),
@@ -520,7 +833,11 @@ func (displayer *DiskMonitorDisplayer) displayFooter(data *DiskMonitorData) {
 		data.RefreshInterval.Seconds(),
 		displayer.colorize("", displayer.ColorReset))
 
-	fmt.Println(strings.Repeat("=", 80))
+	if !displayer.ShowColors || terminal.ColorBlindMode() {
+		fmt.Println(terminal.SeverityLegend())
+	}
+
+	fmt.Println(displayer.rule("="))
 }
 
 // formatBytes formats bytes into human-readable format
@@ -537,136 +854,207 @@ func (displayer *DiskMonitorDisplayer) formatBytes(bytes uint64) string {
 	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
 }
 
+// displayUnavailable shows a section that could not be collected, with the reason
+func (displayer *DiskMonitorDisplayer) displayUnavailable(title, reason string) {
+	fmt.Println("\n" + title)
+	fmt.Println(displayer.sectionRule())
+	fmt.Println(displayer.colorize("⚠️  "+partial.Unavailable(reason), displayer.ColorYellow))
+}
+
+// lineWidth returns the layout width: the terminal width, or 80 columns when AutoWidth is off
+func (displayer *DiskMonitorDisplayer) lineWidth() int {
+	if !displayer.AutoWidth {
+		return terminal.DefaultWidth
+	}
+	return terminal.Fit(terminal.Width(), 40, 240)
+}
+
+// rule returns a separator line across the layout width
+func (displayer *DiskMonitorDisplayer) rule(char string) string {
+	return strings.Repeat(char, displayer.lineWidth())
+}
+
+// sectionRule returns a section underline, shortened on narrow terminals
+func (displayer *DiskMonitorDisplayer) sectionRule() string {
+	return strings.Repeat("-", terminal.Fit(displayer.lineWidth(), 0, 50))
+}
+
+// barWidth returns the progress bar width, leaving room for the label and value on the same line
+func (displayer *DiskMonitorDisplayer) barWidth() int {
+	if !displayer.AutoWidth {
+		return displayer.BarWidth
+	}
+	return terminal.Fit(displayer.lineWidth()-32, 10, 100)
+}
+
 // colorize applies color to text if colors are enabled
 func (displayer *DiskMonitorDisplayer) colorize(text, color string) string {
 	if !displayer.ShowColors {
-		return text
+		// Severity colors are reduced to their marker
+		return terminal.MarkerOf(color) + text
 	}
-	return color + text + displayer.ColorReset
+	return terminal.PaletteColor(displayer.severityLevel(color), color) + text + displayer.ColorReset
 }
 
-// getDiskUsageColor returns the appropriate color for disk usage percentage
-func (displayer *DiskMonitorDisplayer) getDiskUsageColor(percentage float64) string {
+// severityColor returns a severity color in the active palette, followed by its marker when markers are shown
+// Markers are shown in color-blind mode and whenever colors are off, so severity never depends on color alone
+func (displayer *DiskMonitorDisplayer) severityColor(color string) string {
+	level := displayer.severityLevel(color)
+	marker := ""
+	if !displayer.ShowColors || terminal.ColorBlindMode() {
+		marker = terminal.SeverityMarker(level)
+	}
 	if !displayer.ShowColors {
-		return ""
+		return marker
+	}
+	return terminal.PaletteColor(level, color) + marker
+}
+
+// severityLevel returns the severity a displayer color stands for
+func (displayer *DiskMonitorDisplayer) severityLevel(color string) terminal.Severity {
+	switch color {
+	case displayer.ColorGreen:
+		return terminal.SeverityOK
+	case displayer.ColorYellow:
+		return terminal.SeverityWarning
+	case displayer.ColorMagenta:
+		return terminal.SeverityHigh
+	case displayer.ColorRed:
+		return terminal.SeverityCritical
 	}
+	return terminal.SeverityNone
+}
 
+// getDiskUsageColor returns the appropriate color for disk usage percentage
+func (displayer *DiskMonitorDisplayer) getDiskUsageColor(percentage float64) string {
 	switch {
 	case percentage < 50:
-		return displayer.ColorGreen
+		return displayer.severityColor(displayer.ColorGreen)
 	case percentage < 80:
-		return displayer.ColorYellow
+		return displayer.severityColor(displayer.ColorYellow)
 	case percentage < 90:
-		return displayer.ColorMagenta
+		return displayer.severityColor(displayer.ColorMagenta)
 	default:
-		return displayer.ColorRed
+		return displayer.severityColor(displayer.ColorRed)
 	}
 }
 
 // getDiskStatusColor returns the appropriate color for disk status
 func (displayer *DiskMonitorDisplayer) getDiskStatusColor(status string) string {
-	if !displayer.ShowColors {
-		return ""
-	}
-
 	switch status {
 	case "Normal":
-		return displayer.ColorGreen
+		return displayer.severityColor(displayer.ColorGreen)
 	case "Warning":
-		return displayer.ColorYellow
+		return displayer.severityColor(displayer.ColorYellow)
 	case "Critical":
-		return displayer.ColorRed
+		return displayer.severityColor(displayer.ColorRed)
 	default:
-		return displayer.ColorWhite
+		return displayer.severityColor(displayer.ColorWhite)
 	}
 }
 
 // getUtilizationColor returns the appropriate color for utilization percentage
 func (displayer *DiskMonitorDisplayer) getUtilizationColor(percentage float64) string {
-	if !displayer.ShowColors {
-		return ""
-	}
-
 	switch {
 	case percentage < 30:
-		return displayer.ColorGreen
+		return displayer.severityColor(displayer.ColorGreen)
 	case percentage < 60:
-		return displayer.ColorYellow
+		return displayer.severityColor(displayer.ColorYellow)
 	case percentage < 80:
-		return displayer.ColorMagenta
+		return displayer.severityColor(displayer.ColorMagenta)
 	default:
-		return displayer.ColorRed
+		return displayer.severityColor(displayer.ColorRed)
 	}
 }
 
 // getTemperatureColor returns the appropriate color for temperature
 func (displayer *DiskMonitorDisplayer) getTemperatureColor(temperature float64) string {
-	if !displayer.ShowColors {
-		return ""
-	}
-
 	switch {
 	case temperature < 40:
-		return displayer.ColorGreen
+		return displayer.severityColor(displayer.ColorGreen)
 	case temperature < 50:
-		return displayer.ColorYellow
+		return displayer.severityColor(displayer.ColorYellow)
 	case temperature < 60:
-		return displayer.ColorMagenta
+		return displayer.severityColor(displayer.ColorMagenta)
 	default:
-		return displayer.ColorRed
+		return displayer.severityColor(displayer.ColorRed)
 	}
 }
 
 // getTemperatureStatusColor returns the appropriate color for temperature status
 func (displayer *DiskMonitorDisplayer) getTemperatureStatusColor(status string) string {
-	if !displayer.ShowColors {
-		return ""
-	}
-
 	switch status {
 	case "Normal":
-		return displayer.ColorGreen
+		return displayer.severityColor(displayer.ColorGreen)
 	case "Warning":
-		return displayer.ColorYellow
+		return displayer.severityColor(displayer.ColorYellow)
 	case "Critical":
-		return displayer.ColorRed
+		return displayer.severityColor(displayer.ColorRed)
 	default:
-		return displayer.ColorWhite
+		return displayer.severityColor(displayer.ColorWhite)
 	}
 }
 
 // getHealthStatusColor returns the appropriate color for health status
 func (displayer *DiskMonitorDisplayer) getHealthStatusColor(status string) string {
-	if !displayer.ShowColors {
-		return ""
-	}
-
 	switch status {
 	case "Good":
-		return displayer.ColorGreen
+		return displayer.severityColor(displayer.ColorGreen)
 	case "Warning":
-		return displayer.ColorYellow
+		return displayer.severityColor(displayer.ColorYellow)
 	case "Critical":
-		return displayer.ColorRed
+		return displayer.severityColor(displayer.ColorRed)
 	default:
-		return displayer.ColorWhite
+		return displayer.severityColor(displayer.ColorWhite)
 	}
 }
 
-// getIOUsageColor returns the appropriate color for I/O usage
-func (displayer *DiskMonitorDisplayer) getIOUsageColor(iops float64) string {
-	if !displayer.ShowColors {
-		return ""
+// getTrimStatusColor returns the appropriate color for TRIM status
+func (displayer *DiskMonitorDisplayer) getTrimStatusColor(status string) string {
+	switch status {
+	case "OK":
+		return displayer.severityColor(displayer.ColorGreen)
+	case "Warning":
+		return displayer.severityColor(displayer.ColorYellow)
+	default:
+		return displayer.severityColor(displayer.ColorWhite)
+	}
+}
+
+// getSelfTestColor returns the color of a self-test summary: running, passed or failed
+func (displayer *DiskMonitorDisplayer) getSelfTestColor(summary string) string {
+	switch {
+	case strings.HasSuffix(summary, "FAILED"), summary == "error":
+		return displayer.severityColor(displayer.ColorRed)
+	case strings.HasSuffix(summary, "passed"):
+		return displayer.severityColor(displayer.ColorGreen)
+	default:
+		return displayer.severityColor(displayer.ColorCyan)
+	}
+}
+
+// getGrowthRateColor returns the appropriate color for a file growth rate
+func (displayer *DiskMonitorDisplayer) getGrowthRateColor(rate float64) string {
+	switch {
+	case rate >= 10*1024*1024: // 10 MB/s
+		return displayer.severityColor(displayer.ColorRed)
+	case rate >= 1024*1024: // 1 MB/s
+		return displayer.severityColor(displayer.ColorYellow)
+	default:
+		return displayer.severityColor(displayer.ColorGreen)
 	}
+}
 
+// getIOUsageColor returns the appropriate color for I/O usage
+func (displayer *DiskMonitorDisplayer) getIOUsageColor(iops float64) string {
 	switch {
 	case iops < 100:
-		return displayer.ColorGreen
+		return displayer.severityColor(displayer.ColorGreen)
 	case iops < 500:
-		return displayer.ColorYellow
+		return displayer.severityColor(displayer.ColorYellow)
 	case iops < 1000:
-		return displayer.ColorMagenta
+		return displayer.severityColor(displayer.ColorMagenta)
 	default:
-		return displayer.ColorRed
+		return displayer.severityColor(displayer.ColorRed)
 	}
 }
@@ -0,0 +1,17 @@
+package diskmonitor
+
+import (
+	"testing"
+
+	"github.com/ahmadreza-log/simple-monitor/monitortest"
+)
+
+// TestDisplayDiskMonitorDataGolden renders testdata/snapshot.json the way the fixtures command does and
+// compares the output with testdata/display.golden
+func TestDisplayDiskMonitorDataGolden(t *testing.T) {
+	monitortest.Golden(t, func(data *DiskMonitorData) {
+		displayer := NewDiskMonitorDisplayer()
+		displayer.ShowColors, displayer.AutoWidth = false, false
+		displayer.DisplayDiskMonitorData(data)
+	})
+}
@@ -3,8 +3,12 @@ package diskmonitor
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/ahmadreza-log/simple-monitor/exportschema"
+	"github.com/ahmadreza-log/simple-monitor/protoexport"
+	"github.com/ahmadreza-log/simple-monitor/redact"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 )
 
@@ -16,6 +20,8 @@ type DiskMonitorExporter struct {
 	DateFormat    string // Date format for file naming
 	CreateSubDirs bool   // Whether to create subdirectories for each module
 	PrettyPrint   bool   // Whether to pretty print JSON output
+
+	exportschema.Format // CSV header style and metadata rows
 }
 
 // NewDiskMonitorExporter creates a new instance of DiskMonitorExporter
@@ -32,6 +38,10 @@ func NewDiskMonitorExporter() *DiskMonitorExporter {
 // ExportToJSON exports disk monitoring data to a JSON file
 // The file will be saved with a timestamp-based filename in the appropriate subdirectory
 func (exporter *DiskMonitorExporter) ExportToJSON(data *DiskMonitorData, moduleName string) (string, error) {
+	if err := redact.Copy(&data); err != nil {
+		return "", err
+	}
+
 	// Create logs directory if it doesn't exist
 	if err := os.MkdirAll(exporter.LogsDirectory, 0755); err != nil {
 		return "", fmt.Errorf("failed to create logs directory: %w", err)
@@ -75,9 +85,55 @@ func (exporter *DiskMonitorExporter) ExportToJSON(data *DiskMonitorData, moduleN
 	return filePath, nil
 }
 
+// ExportToProtobuf exports disk monitoring data to a protobuf file
+// The file holds one DiskMonitorData message; it is smaller and faster to parse than JSON for long archives
+func (exporter *DiskMonitorExporter) ExportToProtobuf(data *DiskMonitorData, moduleName string) (string, error) {
+	if err := redact.Copy(&data); err != nil {
+		return "", err
+	}
+
+	// Create logs directory if it doesn't exist
+	if err := os.MkdirAll(exporter.LogsDirectory, 0755); err != nil {
+		return "", fmt.Errorf("failed to create logs directory: %w", err)
+	}
+
+	// Create subdirectory for the module if enabled
+	var targetDir string
+	if exporter.CreateSubDirs {
+		targetDir = filepath.Join(exporter.LogsDirectory, moduleName)
+		if err := os.MkdirAll(targetDir, 0755); err != nil {
+			return "", fmt.Errorf("failed to create module directory: %w", err)
+		}
+	} else {
+		targetDir = exporter.LogsDirectory
+	}
+
+	// Generate filename with timestamp
+	timestamp := time.Now().Format("2006-01-02_15-04-05")
+	filename := fmt.Sprintf("%s_%s.%s", moduleName, timestamp, protoexport.Extension)
+	filePath := filepath.Join(targetDir, filename)
+
+	// Encode as the message `simple-monitor proto` describes
+	content, err := protoexport.Marshal(data)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode protobuf data: %w", err)
+	}
+
+	// Write to file
+	if err := os.WriteFile(filePath, content, 0644); err != nil {
+		return "", fmt.Errorf("failed to write protobuf file: %w", err)
+	}
+
+	return filePath, nil
+}
+
 // ExportToCSV exports disk monitoring data to a CSV file
 // This creates a simplified CSV format with key metrics
 func (exporter *DiskMonitorExporter) ExportToCSV(data *DiskMonitorData, moduleName string) (string, error) {
+	if err := redact.Copy(&data); err != nil {
+		return "", err
+	}
+
 	// Create logs directory if it doesn't exist
 	if err := os.MkdirAll(exporter.LogsDirectory, 0755); err != nil {
 		return "", fmt.Errorf("failed to create logs directory: %w", err)
@@ -113,6 +169,10 @@ func (exporter *DiskMonitorExporter) ExportToCSV(data *DiskMonitorData, moduleNa
 // ExportToTXT exports disk monitoring data to a text file
 // This creates a human-readable text format
 func (exporter *DiskMonitorExporter) ExportToTXT(data *DiskMonitorData, moduleName string) (string, error) {
+	if err := redact.Copy(&data); err != nil {
+		return "", err
+	}
+
 	// Create logs directory if it doesn't exist
 	if err := os.MkdirAll(exporter.LogsDirectory, 0755); err != nil {
 		return "", fmt.Errorf("failed to create logs directory: %w", err)
@@ -149,8 +209,11 @@ func (exporter *DiskMonitorExporter) ExportToTXT(data *DiskMonitorData, moduleNa
 func (exporter *DiskMonitorExporter) generateCSVContent(data *DiskMonitorData) string {
 	var content string
 
+	// Metadata rows
+	content += exporter.Metadata(exportschema.Disk, true)
+
 	// Header
-	content += "Timestamp,Total Space,Used Space,Free Space,Usage Percent,Disk Status,Total Read Speed,Total Write Speed,Average IOPS,Disk Utilization\n"
+	content += exporter.CSVHeader("Timestamp,Total Space,Used Space,Free Space,Usage Percent,Disk Status,Total Read Speed,Total Write Speed,Average IOPS,Disk Utilization", "timestamp,total_space,used_space,free_space,usage_percent,disk_status,total_read_speed,total_write_speed,average_iops,disk_utilization")
 
 	// Data row
 	content += fmt.Sprintf("%s,%d,%d,%d,%.2f,%s,%.2f,%.2f,%.2f,%.2f\n",
@@ -167,43 +230,146 @@ func (exporter *DiskMonitorExporter) generateCSVContent(data *DiskMonitorData) s
 
 	// Partition data
 	if len(data.Partitions) > 0 {
-		content += "\nPartition Data\n"
-		content += "Device,Mountpoint,Type,Total,Used,Free,Usage Percent\n"
+		content += exporter.CSVSection("Partition Data", "partitions")
+		content += exporter.CSVHeader("Device,Mountpoint,Type,Total,Used,Free,Usage Percent,Reserved,True Free,Excluded,Read Only,Remounted Read Only", "device,mountpoint,fstype,total,used,free,usage_percent,reserved,true_free,excluded,read_only,remounted_read_only")
 		for _, partition := range data.Partitions {
-			content += fmt.Sprintf("%s,%s,%s,%d,%d,%d,%.2f\n",
+			content += fmt.Sprintf("%s,%s,%s,%d,%d,%d,%.2f,%d,%d,%t,%t,%t\n",
 				partition.Device,
 				partition.Mountpoint,
 				partition.Fstype,
 				partition.Total,
 				partition.Used,
 				partition.Free,
-				partition.UsagePercent)
+				partition.UsagePercent,
+				partition.Reserved,
+				partition.TrueFree,
+				partition.Excluded,
+				partition.ReadOnly,
+				partition.RemountedReadOnly)
+		}
+	}
+
+	if len(data.NVMe) > 0 {
+		content += exporter.CSVSection("NVMe Health", "nvme")
+		content += exporter.CSVHeader("Device,Model,Percentage Used,Available Spare,Spare Threshold,Media Errors,Error Log Entries,Critical Warning,Temperature,Namespace Used,Namespace Size,Namespace Utilization,Data Read,Data Written,Power On Hours,Unsafe Shutdowns,Status,Error", "device,model,percentage_used,available_spare,available_spare_threshold,media_errors,error_log_entries,critical_warning,temperature,namespace_used,namespace_size,namespace_utilization,data_read,data_written,power_on_hours,unsafe_shutdowns,status,error")
+		for _, nvme := range data.NVMe {
+			content += fmt.Sprintf("%s,%q,%.0f,%.0f,%.0f,%d,%d,%d,%.1f,%d,%d,%.2f,%d,%d,%d,%d,%s,%q\n",
+				nvme.Device,
+				nvme.Model,
+				nvme.PercentageUsed,
+				nvme.AvailableSpare,
+				nvme.AvailableSpareThreshold,
+				nvme.MediaErrors,
+				nvme.ErrorLogEntries,
+				nvme.CriticalWarning,
+				nvme.Temperature,
+				nvme.NamespaceUsed,
+				nvme.NamespaceSize,
+				nvme.NamespaceUtilization,
+				nvme.DataRead,
+				nvme.DataWritten,
+				nvme.PowerOnHours,
+				nvme.UnsafeShutdowns,
+				nvme.Status,
+				nvme.Error)
+		}
+	}
+
+	if len(data.Trim) > 0 {
+		content += exporter.CSVSection("TRIM Status", "trim")
+		content += exporter.CSVHeader("Mountpoint,Device,Fstype,Rotational,Supported,Discard,Timer Active,Last Trim,Status,Message", "mountpoint,device,fstype,rotational,supported,discard,timer_active,last_trim,status,message")
+		for _, trim := range data.Trim {
+			lastTrim := ""
+			if !trim.LastTrim.IsZero() {
+				lastTrim = trim.LastTrim.Format("2006-01-02 15:04:05")
+			}
+			content += fmt.Sprintf("%s,%s,%s,%t,%t,%t,%t,%s,%s,%q\n",
+				trim.Mountpoint,
+				trim.Device,
+				trim.Fstype,
+				trim.Rotational,
+				trim.Supported,
+				trim.Discard,
+				trim.TimerActive,
+				lastTrim,
+				trim.Status,
+				trim.Message)
+		}
+	}
+
+	if len(data.SelfTests) > 0 {
+		content += exporter.CSVSection("SMART Self-Tests", "self_tests")
+		content += exporter.CSVHeader("Device,Running,Progress,Queued,Last Type,Last Result,Last Passed,Last Lifetime Hours,Error", "device,running,progress,queued,last_type,last_result,last_passed,last_lifetime_hours,error")
+		for _, test := range data.SelfTests {
+			content += fmt.Sprintf("%s,%s,%.0f,%s,%s,%q,%t,%d,%q\n",
+				test.Device,
+				test.Running,
+				test.Progress,
+				strings.Join(test.Queued, " "),
+				test.LastType,
+				test.LastResult,
+				test.LastPassed,
+				test.LastLifetimeHours,
+				test.Error)
+		}
+	}
+
+	// Growing files
+	if len(data.GrowingFiles) > 0 {
+		content += exporter.CSVSection("Growing Files", "growing_files")
+		content += exporter.CSVHeader("Path,Size,Growth,Growth Rate (B/s),PID,Process", "path,size,growth,growth_rate,pid,process_name")
+		for _, file := range data.GrowingFiles {
+			content += fmt.Sprintf("%s,%d,%d,%.2f,%d,%s\n",
+				file.Path,
+				file.Size,
+				file.Growth,
+				file.GrowthRate,
+				file.PID,
+				file.ProcessName)
+		}
+	}
+
+	// Cleanup suggestions
+	if len(data.CleanupCandidates) > 0 {
+		content += exporter.CSVSection("Cleanup Suggestions", "cleanup_candidates")
+		content += exporter.CSVHeader("Category,Path,Size,Files,Hint", "category,path,size,files,hint")
+		for _, candidate := range data.CleanupCandidates {
+			content += fmt.Sprintf("%s,%s,%d,%d,%s\n",
+				candidate.Category,
+				candidate.Path,
+				candidate.Size,
+				candidate.Files,
+				candidate.Hint)
 		}
 	}
 
 	// I/O data
 	if len(data.DiskIO) > 0 {
-		content += "\nI/O Data\n"
-		content += "Device,Read Speed,Write Speed,IOPS,Utilization,Read Count,Write Count\n"
+		content += exporter.CSVSection("I/O Data", "disk_io")
+		content += exporter.CSVHeader("Device,Read Speed,Write Speed,IOPS,Utilization,Read Count,Write Count,Calibrated,Read Percent,Write Percent", "device_name,read_speed,write_speed,iops,utilization,read_count,write_count,calibrated,read_percent,write_percent")
 		for _, io := range data.DiskIO {
-			content += fmt.Sprintf("%s,%.2f,%.2f,%.2f,%.2f,%d,%d\n",
+			content += fmt.Sprintf("%s,%.2f,%.2f,%.2f,%.2f,%d,%d,%t,%.2f,%.2f\n",
 				io.DeviceName,
 				io.ReadSpeed,
 				io.WriteSpeed,
 				io.IOPS,
 				io.Utilization,
 				io.ReadCount,
-				io.WriteCount)
+				io.WriteCount,
+				io.Calibrated,
+				io.ReadPercent,
+				io.WritePercent)
 		}
 	}
 
 	// Process data
 	if len(data.TopProcesses) > 0 {
-		content += "\nProcess Data\n"
-		content += "PID,Name,Read Speed,Write Speed,IOPS,Total IO,Status\n"
+		content += exporter.CSVSection("Process Data", "top_processes")
+		content += exporter.CSVHeader("PID,Instance ID,Name,Read Speed,Write Speed,IOPS,Total IO,Status", "pid,instance_id,name,read_speed,write_speed,iops,total_io,status")
 		for _, process := range data.TopProcesses {
-			content += fmt.Sprintf("%d,%s,%.2f,%.2f,%.2f,%d,%s\n",
+			content += fmt.Sprintf("%d,%s,%s,%.2f,%.2f,%.2f,%d,%s\n",
 				process.PID,
+				process.InstanceID,
 				process.Name,
 				process.ReadSpeed,
 				process.WriteSpeed,
@@ -220,6 +386,9 @@ func (exporter *DiskMonitorExporter) generateCSVContent(data *DiskMonitorData) s
 func (exporter *DiskMonitorExporter) generateTXTContent(data *DiskMonitorData) string {
 	var content string
 
+	// Metadata rows
+	content += exporter.Metadata(exportschema.Disk, false)
+
 	// Header
 	content += "DISK MONITOR REPORT\n"
 	content += "==================\n\n"
@@ -227,12 +396,25 @@ func (exporter *DiskMonitorExporter) generateTXTContent(data *DiskMonitorData) s
 	// Timestamp
 	content += fmt.Sprintf("Generated: %s\n\n", data.Timestamp.Format("2006-01-02 15:04:05"))
 
+	// Sections that could not be collected
+	if len(data.SectionErrors) > 0 {
+		content += "UNAVAILABLE SECTIONS\n"
+		content += "--------------------\n"
+		for _, section := range data.SectionErrors.Sections() {
+			content += fmt.Sprintf("%s: %s\n", section, data.SectionErrors[section])
+		}
+		content += "\n"
+	}
+
 	// Disk summary
 	content += "DISK SUMMARY\n"
 	content += "------------\n"
 	content += fmt.Sprintf("Total Space: %s\n", exporter.formatBytes(data.TotalSpace))
 	content += fmt.Sprintf("Used Space: %s\n", exporter.formatBytes(data.UsedSpace))
 	content += fmt.Sprintf("Free Space: %s\n", exporter.formatBytes(data.FreeSpace))
+	if data.ReservedSpace > 0 {
+		content += fmt.Sprintf("Reserved (root): %s\n", exporter.formatBytes(data.ReservedSpace))
+	}
 	content += fmt.Sprintf("Usage: %.2f%%\n", data.UsagePercent)
 	content += fmt.Sprintf("Status: %s\n\n", data.DiskStatus)
 
@@ -252,6 +434,46 @@ func (exporter *DiskMonitorExporter) generateTXTContent(data *DiskMonitorData) s
 				exporter.formatBytes(partition.Used),
 				exporter.formatBytes(partition.Free),
 				partition.UsagePercent)
+			if partition.Reserved > 0 {
+				content += fmt.Sprintf("\t\tReserved (root): %s, True Free: %s\n",
+					exporter.formatBytes(partition.Reserved),
+					exporter.formatBytes(partition.TrueFree))
+			}
+			if partition.RemountedReadOnly {
+				content += "\t\tREMOUNTED READ-ONLY (writes are failing)\n"
+			} else if partition.ReadOnly {
+				content += "\t\tRead-only\n"
+			}
+		}
+		content += "\n"
+	}
+
+	// Growing files
+	if len(data.GrowingFiles) > 0 {
+		content += "FASTEST-GROWING FILES\n"
+		content += "---------------------\n"
+		for _, file := range data.GrowingFiles {
+			content += fmt.Sprintf("%s/s\t%s\t%s (PID %d)\t%s\n",
+				exporter.formatBytes(uint64(file.GrowthRate)),
+				exporter.formatBytes(file.Size),
+				file.ProcessName,
+				file.PID,
+				file.Path)
+		}
+		content += "\n"
+	}
+
+	// Cleanup suggestions
+	if len(data.CleanupCandidates) > 0 {
+		content += "CLEANUP SUGGESTIONS\n"
+		content += "-------------------\n"
+		content += fmt.Sprintf("Reclaimable Space: %s\n", exporter.formatBytes(data.ReclaimableSpace))
+		for _, candidate := range data.CleanupCandidates {
+			content += fmt.Sprintf("%-16s\t%s\t%s\n",
+				candidate.Category,
+				exporter.formatBytes(candidate.Size),
+				candidate.Path)
+			content += fmt.Sprintf("\t\t%s\n", candidate.Hint)
 		}
 		content += "\n"
 	}
@@ -270,6 +492,9 @@ func (exporter *DiskMonitorExporter) generateTXTContent(data *DiskMonitorData) s
 				io.WriteSpeed,
 				io.IOPS,
 				io.Utilization)
+			if io.Calibrated {
+				content += fmt.Sprintf("  of capability: read %.2f%%, write %.2f%%\n", io.ReadPercent, io.WritePercent)
+			}
 		}
 		content += "\n"
 	}
@@ -309,6 +534,64 @@ func (exporter *DiskMonitorExporter) generateTXTContent(data *DiskMonitorData) s
 		content += "\n"
 	}
 
+	// NVMe health
+	if len(data.NVMe) > 0 {
+		content += "NVMe HEALTH\n"
+		content += "-----------\n"
+		for _, nvme := range data.NVMe {
+			if nvme.Error != "" {
+				content += fmt.Sprintf("%s: %s\n", nvme.Device, nvme.Error)
+				continue
+			}
+			content += fmt.Sprintf("%s (%s): %s\n", nvme.Device, nvme.Model, nvme.Status)
+			content += fmt.Sprintf("  Percentage Used: %.0f%%\n", nvme.PercentageUsed)
+			content += fmt.Sprintf("  Available Spare: %.0f%% (threshold %.0f%%)\n", nvme.AvailableSpare, nvme.AvailableSpareThreshold)
+			content += fmt.Sprintf("  Media Errors: %d, Error Log Entries: %d\n", nvme.MediaErrors, nvme.ErrorLogEntries)
+			content += fmt.Sprintf("  Critical Warning: 0x%02x\n", nvme.CriticalWarning)
+			content += fmt.Sprintf("  Controller Temperature: %.0f°C\n", nvme.Temperature)
+			content += fmt.Sprintf("  Namespace Utilization: %s of %s (%.2f%%)\n",
+				exporter.formatBytes(nvme.NamespaceUsed), exporter.formatBytes(nvme.NamespaceSize), nvme.NamespaceUtilization)
+			content += fmt.Sprintf("  Data Read: %s, Data Written: %s\n", exporter.formatBytes(nvme.DataRead), exporter.formatBytes(nvme.DataWritten))
+			content += fmt.Sprintf("  Power On Hours: %d, Unsafe Shutdowns: %d\n", nvme.PowerOnHours, nvme.UnsafeShutdowns)
+		}
+		content += "\n"
+	}
+
+	// TRIM status
+	if len(data.Trim) > 0 {
+		content += "TRIM STATUS\n"
+		content += "-----------\n"
+		for _, trim := range data.Trim {
+			content += fmt.Sprintf("%s (%s, %s): %s - %s\n", trim.Mountpoint, trim.Device, trim.Fstype, trim.Status, trim.Message)
+			if !trim.LastTrim.IsZero() {
+				content += fmt.Sprintf("  Last fstrim: %s\n", trim.LastTrim.Format("2006-01-02 15:04:05"))
+			}
+		}
+		content += "\n"
+	}
+
+	// SMART self-tests
+	if len(data.SelfTests) > 0 {
+		content += "SMART SELF-TESTS\n"
+		content += "----------------\n"
+		for _, test := range data.SelfTests {
+			content += fmt.Sprintf("%s:\n", test.Device)
+			if test.Running != "" {
+				content += fmt.Sprintf("  Running: %s (%.0f%% complete)\n", test.Running, test.Progress)
+			}
+			if len(test.Queued) > 0 {
+				content += fmt.Sprintf("  Queued: %s\n", strings.Join(test.Queued, ", "))
+			}
+			if test.LastResult != "" {
+				content += fmt.Sprintf("  Last Test: %s, %s (at %d power-on hours)\n", test.LastType, test.LastResult, test.LastLifetimeHours)
+			}
+			if test.Error != "" {
+				content += fmt.Sprintf("  Error: %s\n", test.Error)
+			}
+		}
+		content += "\n"
+	}
+
 	// Performance metrics
 	content += "PERFORMANCE METRICS\n"
 	content += "------------------\n"
@@ -343,6 +626,10 @@ func (exporter *DiskMonitorExporter) generateTXTContent(data *DiskMonitorData) s
 	content += fmt.Sprintf("High Temperature Warning: %t\n", data.HighTempWarning)
 	content += fmt.Sprintf("Health Warning: %t\n", data.HealthWarning)
 	content += fmt.Sprintf("I/O Bottleneck: %t\n", data.IOBottleneck)
+	content += fmt.Sprintf("Read-Only Remount: %t\n", data.ReadOnlyWarning)
+	if len(data.ReadOnlyMounts) > 0 {
+		content += fmt.Sprintf("Remounted Read-Only: %s\n", strings.Join(data.ReadOnlyMounts, ", "))
+	}
 	content += "\n"
 
 	return content
@@ -376,3 +663,13 @@ func (exporter *DiskMonitorExporter) SetPrettyPrint(pretty bool) {
 func (exporter *DiskMonitorExporter) SetCreateSubDirs(create bool) {
 	exporter.CreateSubDirs = create
 }
+
+// SetStableKeys sets whether CSV headers use stable snake_case keys instead of prose
+func (exporter *DiskMonitorExporter) SetStableKeys(stable bool) {
+	exporter.StableKeys = stable
+}
+
+// SetHeaderMetadata sets whether exports start with metadata rows
+func (exporter *DiskMonitorExporter) SetHeaderMetadata(metadata bool) {
+	exporter.HeaderMetadata = metadata
+}
@@ -0,0 +1,253 @@
+package diskmonitor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ahmadreza-log/simple-monitor/alert"
+	"github.com/ahmadreza-log/simple-monitor/privhelper"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// nvmeDataUnit is the size of one NVMe "data unit" (1000 blocks of 512 bytes)
+const nvmeDataUnit = 512 * 1000
+
+// nvmeNamespace matches the namespace and partition suffix of an NVMe block device (nvme0n1p2 -> nvme0)
+var nvmeNamespace = regexp.MustCompile(`n\d+(p\d+)?$`)
+
+// smartctlScan is the subset of smartctl --scan -j output listing devices
+type smartctlScan struct {
+	Devices []struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+	} `json:"devices"`
+}
+
+// smartctlNVMe is the subset of smartctl -j -a output for an NVMe controller
+type smartctlNVMe struct {
+	Smartctl struct {
+		ExitStatus int `json:"exit_status"`
+		Messages   []struct {
+			String string `json:"string"`
+		} `json:"messages"`
+	} `json:"smartctl"`
+	ModelName string `json:"model_name"`
+	Health    struct {
+		CriticalWarning         int     `json:"critical_warning"`
+		Temperature             float64 `json:"temperature"`
+		AvailableSpare          int     `json:"available_spare"`
+		AvailableSpareThreshold int     `json:"available_spare_threshold"`
+		PercentageUsed          int     `json:"percentage_used"`
+		DataUnitsRead           uint64  `json:"data_units_read"`
+		DataUnitsWritten        uint64  `json:"data_units_written"`
+		PowerOnHours            uint64  `json:"power_on_hours"`
+		UnsafeShutdowns         uint64  `json:"unsafe_shutdowns"`
+		MediaErrors             uint64  `json:"media_errors"`
+		ErrorLogEntries         uint64  `json:"num_err_log_entries"`
+	} `json:"nvme_smart_health_information_log"`
+	Namespaces []struct {
+		ID   int `json:"id"`
+		Size struct {
+			Bytes uint64 `json:"bytes"`
+		} `json:"size"`
+		Utilization struct {
+			Bytes uint64 `json:"bytes"`
+		} `json:"utilization"`
+	} `json:"nvme_namespaces"`
+}
+
+// collectNVMeInfo reads the NVMe health log of every controller through smartctl -j
+// smartctl is only run every NVMeCheckInterval; the generic health rows of NVMe drives are replaced by this section
+func (collector *DiskMonitorCollector) collectNVMeInfo(data *DiskMonitorData) error {
+	if collector.nvmeCache == nil || time.Since(collector.nvmeTime) >= collector.config.NVMeCheckInterval {
+		if _, err := exec.LookPath("smartctl"); err != nil {
+			// Only worth mentioning when the machine has NVMe drives
+			if !hasNVMeDrive(data) {
+				return nil
+			}
+			return errors.New("smartctl not found; install smartmontools for NVMe health")
+		}
+		devices, err := scanNVMeDevices()
+		if err != nil {
+			return err
+		}
+
+		nvmeInfos := []DiskNVMeInfo{}
+		for _, device := range devices {
+			info, err := readNVMeInfo(device)
+			if err != nil {
+				info = DiskNVMeInfo{Device: device, Error: err.Error()}
+			}
+			nvmeInfos = append(nvmeInfos, info)
+		}
+		collector.nvmeCache = nvmeInfos
+		collector.nvmeTime = time.Now()
+	}
+
+	data.NVMe = append([]DiskNVMeInfo{}, collector.nvmeCache...)
+	dropNVMeHealthRows(data)
+	return nil
+}
+
+// runSmartctl runs smartctl, through the privileged helper when one is connected since smartctl needs root to open drives
+// Like exec's Output, a non-zero exit status returns the output together with an error
+func runSmartctl(args ...string) ([]byte, error) {
+	if helper := privhelper.Active(); helper != nil {
+		return helper.Smartctl(args...)
+	}
+	return exec.Command("smartctl", args...).Output()
+}
+
+// scanNVMeDevices lists the NVMe controllers smartctl can see (e.g. /dev/nvme0)
+func scanNVMeDevices() ([]string, error) {
+	output, err := runSmartctl("--scan", "-j")
+	if len(output) == 0 {
+		return nil, fmt.Errorf("failed to scan devices with smartctl: %w", err)
+	}
+	var scan smartctlScan
+	if err := json.Unmarshal(output, &scan); err != nil {
+		return nil, fmt.Errorf("failed to parse smartctl scan: %w", err)
+	}
+
+	var devices []string
+	for _, device := range scan.Devices {
+		if device.Type == "nvme" {
+			devices = append(devices, device.Name)
+		}
+	}
+	return devices, nil
+}
+
+// readNVMeInfo reads one controller's health log and namespace utilization
+func readNVMeInfo(device string) (DiskNVMeInfo, error) {
+	output, err := runSmartctl("-j", "-a", device)
+	if len(output) == 0 {
+		return DiskNVMeInfo{}, fmt.Errorf("failed to run smartctl on %s: %w", device, err)
+	}
+	var report smartctlNVMe
+	if err := json.Unmarshal(output, &report); err != nil {
+		return DiskNVMeInfo{}, fmt.Errorf("failed to parse smartctl output for %s: %w", device, err)
+	}
+	// Bits 0-2 mean smartctl could not talk to the device; the higher bits report disk problems
+	if report.Smartctl.ExitStatus&0x7 != 0 {
+		reason := fmt.Sprintf("exit status %d", report.Smartctl.ExitStatus)
+		if len(report.Smartctl.Messages) > 0 {
+			reason = report.Smartctl.Messages[0].String
+		}
+		return DiskNVMeInfo{}, fmt.Errorf("smartctl failed on %s: %s", device, reason)
+	}
+
+	health := report.Health
+	info := DiskNVMeInfo{
+		Device:                  device,
+		Model:                   report.ModelName,
+		PercentageUsed:          float64(health.PercentageUsed),
+		AvailableSpare:          float64(health.AvailableSpare),
+		AvailableSpareThreshold: float64(health.AvailableSpareThreshold),
+		MediaErrors:             health.MediaErrors,
+		ErrorLogEntries:         health.ErrorLogEntries,
+		CriticalWarning:         health.CriticalWarning,
+		Temperature:             health.Temperature,
+		DataRead:                health.DataUnitsRead * nvmeDataUnit,
+		DataWritten:             health.DataUnitsWritten * nvmeDataUnit,
+		PowerOnHours:            health.PowerOnHours,
+		UnsafeShutdowns:         health.UnsafeShutdowns,
+	}
+	for _, namespace := range report.Namespaces {
+		info.NamespaceSize += namespace.Size.Bytes
+		info.NamespaceUsed += namespace.Utilization.Bytes
+	}
+	if info.NamespaceSize > 0 {
+		info.NamespaceUtilization = float64(info.NamespaceUsed) / float64(info.NamespaceSize) * 100
+	}
+	return info, nil
+}
+
+// dropNVMeHealthRows removes the generic health rows of drives covered by the NVMe section
+func dropNVMeHealthRows(data *DiskMonitorData) {
+	covered := make(map[string]bool)
+	for _, nvme := range data.NVMe {
+		if nvme.Error == "" {
+			covered[nvme.Device] = true
+		}
+	}
+
+	health := data.DiskHealth[:0]
+	for _, row := range data.DiskHealth {
+		if !covered[nvmeController(row.DeviceName)] {
+			health = append(health, row)
+		}
+	}
+	data.DiskHealth = health
+}
+
+// hasNVMeDrive reports whether any partition or health row sits on an NVMe drive
+func hasNVMeDrive(data *DiskMonitorData) bool {
+	for _, partition := range data.Partitions {
+		if nvmeController(partition.Device) != partition.Device {
+			return true
+		}
+	}
+	for _, row := range data.DiskHealth {
+		if nvmeController(row.DeviceName) != row.DeviceName {
+			return true
+		}
+	}
+	return false
+}
+
+// nvmeController returns the controller of an NVMe namespace or partition (/dev/nvme0n1p2 -> /dev/nvme0)
+// Other devices are returned unchanged
+func nvmeController(device string) string {
+	name := strings.TrimPrefix(device, "/dev/")
+	if !strings.HasPrefix(name, "nvme") {
+		return device
+	}
+	return "/dev/" + nvmeNamespace.ReplaceAllString(name, "")
+}
+
+// analyzeNVMe raises wear, spare, media error and temperature alerts for each NVMe controller
+// A critical warning from the controller itself is always critical
+func (collector *DiskMonitorCollector) analyzeNVMe(data *DiskMonitorData) {
+	rules := collector.config.AlertRules
+	for i, nvme := range data.NVMe {
+		if nvme.Error != "" {
+			continue
+		}
+		level := collector.alerts.Evaluate("nvme_wear:"+nvme.Device, nvme.PercentageUsed,
+			collector.config.NVMeWearWarning, collector.config.NVMeWearCritical, rules["nvme_wear"], data.Timestamp)
+		tempLevel := collector.alerts.Evaluate("nvme_temperature:"+nvme.Device, nvme.Temperature,
+			collector.config.NVMeTempWarning, collector.config.NVMeTempCritical, rules["nvme_temperature"], data.Timestamp)
+		level = max(level, tempLevel)
+
+		// Conditions reported by the controller have no threshold, so they only honour maintenance windows
+		var reported alert.Level
+		var reason string
+		switch {
+		case nvme.CriticalWarning != 0:
+			reported, reason = alert.LevelCritical, fmt.Sprintf("critical warning 0x%02x", nvme.CriticalWarning)
+		case nvme.MediaErrors > 0:
+			reported, reason = alert.LevelWarning, fmt.Sprintf("%d media errors", nvme.MediaErrors)
+		case nvme.AvailableSpareThreshold > 0 && nvme.AvailableSpare <= nvme.AvailableSpareThreshold:
+			reported, reason = alert.LevelWarning, fmt.Sprintf("available spare %.0f%% at threshold", nvme.AvailableSpare)
+		}
+		if reported > alert.LevelNormal && !alert.Suppress("disk", "nvme:"+nvme.Device, nvme.Device+" reports "+reason, data.Timestamp) {
+			level = max(level, reported)
+		}
+
+		data.NVMe[i].Status = level.String()
+		switch level {
+		case alert.LevelCritical:
+			data.HealthWarning = true
+			data.DiskStatus = "Critical"
+		case alert.LevelWarning:
+			data.HealthWarning = true
+			if data.DiskStatus == "Normal" {
+				data.DiskStatus = "Warning"
+			}
+		}
+	}
+}
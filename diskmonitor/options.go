@@ -0,0 +1,49 @@
+package diskmonitor
+
+import "time"
+
+// Option configures a DiskMonitorManager as NewDiskMonitorManager creates it
+// Options are applied before the manager is returned, so nothing can be collecting while they change its settings
+type Option func(*DiskMonitorManager)
+
+// WithRefreshInterval sets how often live monitoring and Subscribe collect; an interval of zero or less is ignored
+func WithRefreshInterval(interval time.Duration) Option {
+	return func(manager *DiskMonitorManager) {
+		if interval > 0 {
+			manager.collector.changeConfig(func(config *DiskMonitorConfig) {
+				config.RefreshInterval = interval
+			})
+		}
+	}
+}
+
+// WithExporter replaces the default exporter, e.g. to write exports under another logs directory
+// A nil exporter keeps the default
+func WithExporter(exporter *DiskMonitorExporter) Option {
+	return func(manager *DiskMonitorManager) {
+		if exporter != nil {
+			manager.exporter = exporter
+		}
+	}
+}
+
+// WithThresholds sets the used space percentages that raise the low space warning and critical alerts
+func WithThresholds(warning, critical float64) Option {
+	return func(manager *DiskMonitorManager) {
+		manager.collector.changeConfig(func(config *DiskMonitorConfig) {
+			config.LowSpaceWarning = warning
+			config.LowSpaceCritical = critical
+		})
+	}
+}
+
+// WithFilters limits the partitions to the given device and mountpoint, e.g. WithFilters("", "/var")
+// Both must match exactly; an empty one matches every partition
+func WithFilters(device, mountpoint string) Option {
+	return func(manager *DiskMonitorManager) {
+		manager.collector.changeConfig(func(config *DiskMonitorConfig) {
+			config.DeviceFilter = device
+			config.MountpointFilter = mountpoint
+		})
+	}
+}
@@ -0,0 +1,339 @@
+package diskmonitor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SelfTestKinds lists the SMART self-tests that can be queued
+var SelfTestKinds = []string{"short", "long"}
+
+// selfTestState tracks one drive's queued, running and finished self-tests between refreshes
+type selfTestState struct {
+	queue     []string  // Tests waiting for the drive to become idle
+	running   string    // Test this monitor started and still sees in progress
+	lastCheck time.Time // When smartctl was last asked for the drive state
+	info      DiskSelfTestInfo
+}
+
+// smartctlSelfTest is the subset of smartctl -j output describing self-tests (ATA and NVMe)
+type smartctlSelfTest struct {
+	Smartctl struct {
+		ExitStatus int `json:"exit_status"`
+		Messages   []struct {
+			String string `json:"string"`
+		} `json:"messages"`
+	} `json:"smartctl"`
+	ATASmartData struct {
+		SelfTest struct {
+			Status struct {
+				Value            int    `json:"value"`
+				String           string `json:"string"`
+				RemainingPercent *int   `json:"remaining_percent"`
+			} `json:"status"`
+		} `json:"self_test"`
+	} `json:"ata_smart_data"`
+	ATASelfTestLog struct {
+		Standard struct {
+			Table []struct {
+				Type struct {
+					String string `json:"string"`
+				} `json:"type"`
+				Status struct {
+					String string `json:"string"`
+					Passed bool   `json:"passed"`
+				} `json:"status"`
+				LifetimeHours uint64 `json:"lifetime_hours"`
+			} `json:"table"`
+		} `json:"standard"`
+	} `json:"ata_smart_self_test_log"`
+	NVMeSelfTestLog *struct {
+		CurrentOperation struct {
+			Value  int    `json:"value"`
+			String string `json:"string"`
+		} `json:"current_self_test_operation"`
+		CompletionPercent int `json:"current_self_test_completion_percent"`
+		Table             []struct {
+			Code struct {
+				String string `json:"string"`
+			} `json:"self_test_code"`
+			Result struct {
+				Value  int    `json:"value"`
+				String string `json:"string"`
+			} `json:"self_test_result"`
+			PowerOnHours uint64 `json:"power_on_hours"`
+		} `json:"table"`
+	} `json:"nvme_self_test_log"`
+}
+
+// QueueSelfTest queues a SMART self-test (short or long) on the drive holding device
+// The test starts as soon as the drive has no other test running; progress is polled every SelfTestPollInterval
+func (collector *DiskMonitorCollector) QueueSelfTest(device, kind string) error {
+	// The self-test state is shared with collections, so wait for one in progress
+	collector.collectMutex.Lock()
+	defer collector.collectMutex.Unlock()
+	if kind != "short" && kind != "long" {
+		return fmt.Errorf("unknown self-test %q (use short or long)", kind)
+	}
+	if collector.simulator != nil {
+		return errors.New("SMART self-tests are not available with simulated data")
+	}
+	if _, err := exec.LookPath("smartctl"); err != nil {
+		return errors.New("smartctl not found; install smartmontools to run self-tests")
+	}
+
+	drive := parentDrive(device)
+	state, ok := collector.selfTests[drive]
+	if !ok {
+		state = &selfTestState{info: DiskSelfTestInfo{Device: drive}}
+		collector.selfTests[drive] = state
+	}
+	state.queue = append(state.queue, kind)
+	queued := len(state.queue)
+
+	// Start right away instead of waiting for the next poll
+	state.lastCheck = time.Time{}
+	collector.pollSelfTest(drive, state, time.Now())
+	if state.info.Error == "" {
+		return nil
+	}
+	// smartctl could not read the drive, so the test would never start
+	if len(state.queue) == queued {
+		state.queue = state.queue[:queued-1]
+	}
+	return errors.New(state.info.Error)
+}
+
+// SelfTestDrives lists the drives behind the monitored partitions, for picking a self-test target
+func (collector *DiskMonitorCollector) SelfTestDrives() ([]DiskSelfTestInfo, error) {
+	// The self-test state and the mount exclusions are shared with collections, so wait for one in progress
+	collector.collectMutex.Lock()
+	defer collector.collectMutex.Unlock()
+	partitions, err := collector.system.Disk.Partitions(false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get partitions: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	var drives []DiskSelfTestInfo
+	for _, partition := range partitions {
+		if collector.isExcludedMount(partition.Device, partition.Mountpoint, partition.Fstype) {
+			continue
+		}
+		drive := parentDrive(partition.Device)
+		if seen[drive] {
+			continue
+		}
+		seen[drive] = true
+
+		info := DiskSelfTestInfo{Device: drive}
+		if state, ok := collector.selfTests[drive]; ok {
+			info = state.snapshot()
+		}
+		drives = append(drives, info)
+	}
+	sort.Slice(drives, func(i, j int) bool { return drives[i].Device < drives[j].Device })
+	return drives, nil
+}
+
+// updateSelfTests polls every drive with queued or running tests and attaches the state to the health rows
+func (collector *DiskMonitorCollector) updateSelfTests(data *DiskMonitorData) {
+	drives := make([]string, 0, len(collector.selfTests))
+	for drive := range collector.selfTests {
+		drives = append(drives, drive)
+	}
+	sort.Strings(drives)
+
+	for _, drive := range drives {
+		state := collector.selfTests[drive]
+		if state.running != "" || len(state.queue) > 0 {
+			collector.pollSelfTest(drive, state, data.Timestamp)
+		}
+		data.SelfTests = append(data.SelfTests, state.snapshot())
+	}
+
+	for i, health := range data.DiskHealth {
+		state, ok := collector.selfTests[parentDrive(health.DeviceName)]
+		if !ok {
+			continue
+		}
+		data.DiskHealth[i].SelfTest = state.snapshot().Summary()
+		if state.info.LastResult != "" && !state.info.LastPassed && health.HealthStatus == "Good" {
+			data.DiskHealth[i].HealthStatus = "Warning"
+		}
+	}
+
+	// NVMe drives are listed by controller; their tests are tracked by namespace (/dev/nvme0n1)
+	for drive, state := range collector.selfTests {
+		for i, nvme := range data.NVMe {
+			if nvmeController(drive) == nvme.Device {
+				data.NVMe[i].SelfTest = state.snapshot().Summary()
+			}
+		}
+	}
+}
+
+// pollSelfTest reads the drive's self-test state and starts the next queued test once it is idle
+func (collector *DiskMonitorCollector) pollSelfTest(drive string, state *selfTestState, now time.Time) {
+	if now.Sub(state.lastCheck) < collector.config.SelfTestPollInterval {
+		return
+	}
+	state.lastCheck = now
+
+	status, err := readSelfTest(drive)
+	if err != nil {
+		state.info.Error = err.Error()
+		return
+	}
+	state.info.Error = ""
+	status.apply(&state.info)
+
+	// The test this monitor started has finished; the log now holds its result
+	if state.info.Running == "" {
+		state.running = ""
+	}
+	if state.info.Running != "" || len(state.queue) == 0 {
+		return
+	}
+
+	kind := state.queue[0]
+	state.queue = state.queue[1:]
+	if err := startSelfTest(drive, kind); err != nil {
+		state.info.Error = err.Error()
+		return
+	}
+	state.running = kind
+	state.info.Running = kind
+	state.info.Progress = 0
+}
+
+// snapshot returns the drive state with a copy of the queue, safe to keep in collected data
+func (state *selfTestState) snapshot() DiskSelfTestInfo {
+	info := state.info
+	if state.running != "" && info.Running != "" {
+		info.Running = state.running
+	}
+	info.Queued = append([]string{}, state.queue...)
+	return info
+}
+
+// apply copies the progress and the most recent log entry into info
+func (status smartctlSelfTest) apply(info *DiskSelfTestInfo) {
+	info.Running, info.Progress = "", 0
+
+	if nvme := status.NVMeSelfTestLog; nvme != nil {
+		if nvme.CurrentOperation.Value != 0 {
+			info.Running = strings.ToLower(nvme.CurrentOperation.String)
+			info.Progress = float64(nvme.CompletionPercent)
+		}
+		if len(nvme.Table) > 0 {
+			last := nvme.Table[0]
+			info.LastType = last.Code.String
+			info.LastResult = last.Result.String
+			info.LastPassed = last.Result.Value == 0
+			info.LastLifetimeHours = last.PowerOnHours
+		}
+		return
+	}
+
+	// ATA self-test execution status 0xF_ means a test is in progress, with the low nibble in tens of percent remaining
+	selfTest := status.ATASmartData.SelfTest.Status
+	if selfTest.Value>>4 == 0xF {
+		info.Running = "self-test"
+		remaining := (selfTest.Value & 0xF) * 10
+		if selfTest.RemainingPercent != nil {
+			remaining = *selfTest.RemainingPercent
+		}
+		info.Progress = float64(100 - remaining)
+	}
+	if table := status.ATASelfTestLog.Standard.Table; len(table) > 0 {
+		info.LastType = table[0].Type.String
+		info.LastResult = table[0].Status.String
+		info.LastPassed = table[0].Status.Passed
+		info.LastLifetimeHours = table[0].LifetimeHours
+	}
+}
+
+// Summary returns the self-test column shown in the health table, e.g. "short 40%" or "Short offline: passed"
+func (info DiskSelfTestInfo) Summary() string {
+	switch {
+	case info.Running != "":
+		return fmt.Sprintf("%s %.0f%%", info.Running, info.Progress)
+	case info.Error != "":
+		return "error"
+	case info.LastResult == "":
+		return ""
+	case info.LastPassed:
+		return info.LastType + ": passed"
+	default:
+		return info.LastType + ": FAILED"
+	}
+}
+
+// readSelfTest asks smartctl for the drive's self-test status and log
+func readSelfTest(drive string) (smartctlSelfTest, error) {
+	var status smartctlSelfTest
+	output, err := runSmartctl("-j", "-c", "-l", "selftest", drive)
+	if len(output) == 0 {
+		return status, fmt.Errorf("failed to run smartctl on %s: %w", drive, err)
+	}
+	if err := json.Unmarshal(output, &status); err != nil {
+		return status, fmt.Errorf("failed to parse smartctl output for %s: %w", drive, err)
+	}
+	return status, smartctlError(drive, status)
+}
+
+// startSelfTest starts a short or long self-test; the drive runs it in the background
+func startSelfTest(drive, kind string) error {
+	output, err := runSmartctl("-j", "-t", kind, drive)
+	if len(output) == 0 {
+		return fmt.Errorf("failed to start %s self-test on %s: %w", kind, drive, err)
+	}
+	var status smartctlSelfTest
+	if err := json.Unmarshal(output, &status); err != nil {
+		return fmt.Errorf("failed to parse smartctl output for %s: %w", drive, err)
+	}
+	return smartctlError(drive, status)
+}
+
+// smartctlError turns smartctl's exit status bits into an error
+// Bit 0 is a command line error, bit 1 a device that could not be opened and bit 2 a failed SMART command;
+// the higher bits report disk problems and still come with valid output
+func smartctlError(drive string, status smartctlSelfTest) error {
+	if status.Smartctl.ExitStatus&0x7 == 0 {
+		return nil
+	}
+	reason := fmt.Sprintf("exit status %d", status.Smartctl.ExitStatus)
+	if len(status.Smartctl.Messages) > 0 {
+		reason = status.Smartctl.Messages[0].String
+	}
+	return fmt.Errorf("smartctl failed on %s: %s", drive, reason)
+}
+
+// parentDrive returns the whole drive holding a partition (/dev/sda1 -> /dev/sda, /dev/nvme0n1p2 -> /dev/nvme0n1)
+// Self-tests run on drives; other platforms pass the device through, as smartctl accepts drive letters there
+func parentDrive(device string) string {
+	if runtime.GOOS != "linux" || !strings.HasPrefix(device, "/dev/") {
+		return device
+	}
+	name := filepath.Base(device)
+	if resolved, err := filepath.EvalSymlinks(device); err == nil {
+		name = filepath.Base(resolved)
+	}
+	if _, err := os.Stat(filepath.Join("/sys/class/block", name, "partition")); err != nil {
+		return "/dev/" + name
+	}
+	path, err := filepath.EvalSymlinks(filepath.Join("/sys/class/block", name))
+	if err != nil {
+		return device
+	}
+	return "/dev/" + filepath.Base(filepath.Dir(path))
+}
@@ -0,0 +1,256 @@
+package diskmonitor
+
+import (
+	"github.com/ahmadreza-log/simple-monitor/procid"
+	"github.com/ahmadreza-log/simple-monitor/simulate"
+	"sort"
+	"strings"
+	"time"
+)
+
+// simulatedPartition describes one synthetic mount
+type simulatedPartition struct {
+	device     string  // Device name
+	mountpoint string  // Mount point
+	fstype     string  // File system type
+	sizeGB     float64 // Total size in gigabytes
+	low        float64 // Lowest usage percentage of the wave
+	high       float64 // Highest usage percentage of the wave
+}
+
+// simulatedPartitions is the synthetic mount table; the root filesystem slowly fills towards the warning level
+var simulatedPartitions = []simulatedPartition{
+	{device: "/dev/nvme0n1p2", mountpoint: "/", fstype: "ext4", sizeGB: 512, low: 62, high: 86},
+	{device: "/dev/nvme0n1p1", mountpoint: "/boot/efi", fstype: "vfat", sizeGB: 0.5, low: 6, high: 6},
+	{device: "/dev/sda1", mountpoint: "/home", fstype: "ext4", sizeGB: 1024, low: 48, high: 52},
+}
+
+// simulatedDevice is one synthetic drive with the throughput a calibration would measure
+type simulatedDevice struct {
+	name     string  // Device name
+	share    float64 // Share of the processes' I/O the drive carries
+	maxRead  float64 // Maximum read speed (MB/s)
+	maxWrite float64 // Maximum write speed (MB/s)
+}
+
+// simulatedDevices: the NVMe drive carries most of the I/O, the data disk the backup
+var simulatedDevices = []simulatedDevice{
+	{name: "nvme0n1", share: 0.7, maxRead: 3400, maxWrite: 2900},
+	{name: "sda", share: 0.3, maxRead: 190, maxWrite: 175},
+}
+
+// collectSimulatedData fills data with synthetic disk metrics instead of querying the system
+// Disk throughput follows the synthetic processes, with bursts from the backup (rsync) process
+func (collector *DiskMonitorCollector) collectSimulatedData(data *DiskMonitorData) {
+	source := collector.simulator
+	const gigabyte = 1024 * simulate.MB
+
+	data.Simulated = true
+	data.Uptime = source.Uptime()
+	processes := source.Processes()
+
+	// Partitions and totals
+	if collector.config.ShowPartitions {
+		for i, partition := range simulatedPartitions {
+			if collector.config.DeviceFilter != "" && partition.device != collector.config.DeviceFilter {
+				continue
+			}
+			if collector.config.MountpointFilter != "" && partition.mountpoint != collector.config.MountpointFilter {
+				continue
+			}
+			excluded := collector.isExcludedMount(partition.device, partition.mountpoint, partition.fstype)
+			if excluded && !collector.config.ShowExcludedMounts {
+				continue
+			}
+
+			percent := source.Wave(10*time.Minute, float64(i)/3, partition.low, partition.high)
+			total := uint64(partition.sizeGB * gigabyte)
+			reserved := total / 20
+			used := uint64(float64(total-reserved) * percent / 100)
+			info := DiskPartitionInfo{
+				Device:       partition.device,
+				Mountpoint:   partition.mountpoint,
+				Fstype:       partition.fstype,
+				Total:        total,
+				Used:         used,
+				Free:         total - reserved - used,
+				TrueFree:     total - used,
+				Reserved:     reserved,
+				UsagePercent: percent,
+				InodesTotal:  total / (16 * 1024),
+			}
+			info.InodesUsed = uint64(float64(info.InodesTotal) * percent / 300)
+			info.InodesFree = info.InodesTotal - info.InodesUsed
+			info.Excluded = excluded
+			data.Partitions = append(data.Partitions, info)
+
+			if !excluded {
+				data.TotalSpace += info.Total
+				data.UsedSpace += info.Used
+				data.FreeSpace += info.Free
+				data.ReservedSpace += info.Reserved
+			}
+		}
+		if data.UsedSpace+data.FreeSpace > 0 {
+			data.UsagePercent = float64(data.UsedSpace) / float64(data.UsedSpace+data.FreeSpace) * 100
+		}
+	}
+
+	// The NVMe root filesystem is trimmed weekly; the data disk is a spinning drive and is not listed
+	if collector.config.ShowTrim {
+		info := DiskTrimInfo{
+			Mountpoint:  simulatedPartitions[0].mountpoint,
+			Device:      simulatedPartitions[0].device,
+			Fstype:      simulatedPartitions[0].fstype,
+			Supported:   true,
+			TimerActive: true,
+			LastTrim:    data.Timestamp.Add(-3 * 24 * time.Hour).Truncate(time.Hour),
+		}
+		info.Status, info.Message = collector.trimStatus(info, nil, data.Timestamp)
+		data.Trim = append(data.Trim, info)
+	}
+
+	// I/O follows the processes
+	var readRate, writeRate float64
+	for _, process := range processes {
+		readRate += float64(process.ReadRate)
+		writeRate += float64(process.WriteRate)
+	}
+	devices := simulatedDevices
+
+	if collector.config.ShowIO {
+		for _, device := range devices {
+			deviceRead := readRate * device.share
+			deviceWrite := writeRate * device.share
+			iops := (deviceRead + deviceWrite) / (64 * 1024)
+			io := DiskIOInfo{
+				DeviceName:  device.name,
+				ReadCount:   source.Counter(device.name+".reads", deviceRead/(64*1024)),
+				WriteCount:  source.Counter(device.name+".writes", deviceWrite/(64*1024)),
+				ReadBytes:   source.Counter(device.name+".read_bytes", deviceRead),
+				WriteBytes:  source.Counter(device.name+".write_bytes", deviceWrite),
+				ReadSpeed:   deviceRead / simulate.MB,
+				WriteSpeed:  deviceWrite / simulate.MB,
+				IOPS:        iops,
+				Utilization: simulate.Clamp(iops/4+source.Noise(3), 0, 100),
+			}
+			collector.applyCapability(&io)
+			data.DiskIO = append(data.DiskIO, io)
+			data.TotalReadSpeed += deviceRead / simulate.MB
+			data.TotalWriteSpeed += deviceWrite / simulate.MB
+			data.AverageIOPS += iops / float64(len(devices))
+		}
+	}
+
+	if collector.config.ShowTemperature {
+		for i, device := range devices {
+			temperature := 36 + float64(i)*3 + source.Wave(4*time.Minute, float64(i)/2, 0, 14)
+			status := "Normal"
+			if temperature >= collector.config.TempCritical {
+				status = "Critical"
+			} else if temperature >= collector.config.TempWarning {
+				status = "Warning"
+			}
+			data.DiskTemperatures = append(data.DiskTemperatures, DiskTemperatureInfo{
+				DeviceName:     device.name,
+				Temperature:    temperature,
+				MaxTemperature: 70,
+				Status:         status,
+			})
+		}
+	}
+
+	if collector.config.ShowHealth {
+		for i, device := range devices {
+			// The NVMe drive reports its own health log instead of a generic row
+			if collector.config.ShowNVMe && strings.HasPrefix(device.name, "nvme") {
+				used := uint64(float64(simulatedPartitions[0].sizeGB*gigabyte) * 0.74)
+				data.NVMe = append(data.NVMe, DiskNVMeInfo{
+					Device:                  nvmeController(device.name),
+					Model:                   "Simulated NVMe SSD 512GB",
+					PercentageUsed:          float64(12 + i*30),
+					AvailableSpare:          100,
+					AvailableSpareThreshold: 10,
+					Temperature:             44 + source.Wave(4*time.Minute, 0, 0, 12),
+					DataRead:                source.Counter(device.name+".read_bytes", readRate*device.share) + 18*1024*gigabyte,
+					DataWritten:             source.Counter(device.name+".write_bytes", writeRate*device.share) + 11*1024*gigabyte,
+					PowerOnHours:            uint64(8760 + i*4100),
+					UnsafeShutdowns:         7,
+					NamespaceSize:           uint64(simulatedPartitions[0].sizeGB * gigabyte),
+					NamespaceUsed:           used,
+					NamespaceUtilization:    74,
+				})
+				continue
+			}
+			data.DiskHealth = append(data.DiskHealth, DiskHealthInfo{
+				DeviceName:      device.name,
+				HealthStatus:    "Good",
+				PowerOnHours:    uint64(8760 + i*4100),
+				PowerCycleCount: uint64(640 + i*95),
+				Temperature:     38 + float64(i)*3,
+				WearLeveling:    float64(12 + i*30),
+			})
+		}
+	}
+
+	// Top processes by throughput
+	if collector.config.ShowProcesses && !collector.idle {
+		var processInfos []DiskProcessInfo
+		for _, process := range processes {
+			if process.ReadRate+process.WriteRate == 0 {
+				continue
+			}
+			if collector.config.ProcessNameFilter != "" &&
+				!strings.Contains(strings.ToLower(process.Name), strings.ToLower(collector.config.ProcessNameFilter)) {
+				continue
+			}
+			processInfos = append(processInfos, DiskProcessInfo{
+				PID:        process.PID,
+				InstanceID: procid.InstanceID(process.PID, process.CreateTime),
+				Name:       process.Name,
+				ReadBytes:  source.Counter(process.Name+".read", float64(process.ReadRate)),
+				WriteBytes: source.Counter(process.Name+".write", float64(process.WriteRate)),
+				ReadSpeed:  float64(process.ReadRate) / simulate.MB,
+				WriteSpeed: float64(process.WriteRate) / simulate.MB,
+				IOPS:       float64(process.ReadRate+process.WriteRate) / (64 * 1024),
+				Status:     process.Status,
+				User:       process.User,
+			})
+		}
+
+		sort.Slice(processInfos, func(i, j int) bool {
+			return processInfos[i].ReadSpeed+processInfos[i].WriteSpeed > processInfos[j].ReadSpeed+processInfos[j].WriteSpeed
+		})
+		if len(processInfos) > collector.config.MaxProcesses {
+			processInfos = processInfos[:collector.config.MaxProcesses]
+		}
+		data.TopProcesses = processInfos
+	}
+
+	// A database WAL and an application log keep growing
+	if collector.config.ShowGrowingFiles {
+		for _, process := range processes {
+			var path string
+			switch process.Name {
+			case "postgres":
+				path = "/var/lib/postgresql/16/main/pg_wal/000000010000000A000000F3"
+			case "python3":
+				path = "/home/demo/app/logs/worker.log"
+			default:
+				continue
+			}
+			rate := float64(process.WriteRate) / 4
+			data.GrowingFiles = append(data.GrowingFiles, GrowingFileInfo{
+				Path:        path,
+				Size:        source.Counter(path, rate),
+				Growth:      uint64(rate * collector.config.RefreshInterval.Seconds()),
+				GrowthRate:  rate,
+				PID:         process.PID,
+				ProcessName: process.Name,
+			})
+		}
+		sort.Slice(data.GrowingFiles, func(i, j int) bool {
+			return data.GrowingFiles[i].GrowthRate > data.GrowingFiles[j].GrowthRate
+		})
+	}
+}
@@ -0,0 +1,231 @@
+package diskmonitor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// trimFilesystems are the filesystems fstrim can trim
+var trimFilesystems = map[string]bool{
+	"ext3": true, "ext4": true, "xfs": true, "btrfs": true, "f2fs": true,
+	"vfat": true, "exfat": true, "ntfs3": true, "jfs": true, "nilfs2": true,
+}
+
+// enableFstrimHint is how to turn on periodic trim on systemd machines
+const enableFstrimHint = "enable it with: systemctl enable --now fstrim.timer"
+
+// fstrimTimer is the state of systemd's fstrim.timer, which trims every mounted filesystem (weekly by default)
+type fstrimTimer struct {
+	Active  bool      // Whether the timer is scheduled
+	LastRun time.Time // When it last fired (zero when never)
+}
+
+// collectTrimInfo checks TRIM on every filesystem of an SSD (or of a disk that accepts discards) on Linux
+// A filesystem is trimmed either continuously (mounted with discard) or by fstrim.timer; one with neither,
+// or whose last fstrim is older than TrimMaxAge, is flagged. Results are cached for TrimCheckInterval
+func (collector *DiskMonitorCollector) collectTrimInfo(data *DiskMonitorData) error {
+	if runtime.GOOS != "linux" {
+		return nil
+	}
+
+	if collector.trimCache == nil || time.Since(collector.trimTime) >= collector.config.TrimCheckInterval {
+		partitions, err := collector.system.Disk.Partitions(false)
+		if err != nil {
+			return fmt.Errorf("failed to get partitions: %w", err)
+		}
+		timer, timerErr := readFstrimTimer()
+		superOptions := readSuperOptions()
+
+		trimInfos := []DiskTrimInfo{}
+		seen := make(map[string]bool)
+		for _, partition := range partitions {
+			if collector.config.DeviceFilter != "" && partition.Device != collector.config.DeviceFilter {
+				continue
+			}
+			if collector.config.MountpointFilter != "" && partition.Mountpoint != collector.config.MountpointFilter {
+				continue
+			}
+			// Subvolumes and bind mounts of a filesystem are trimmed together, so each device is listed once
+			if !trimFilesystems[partition.Fstype] || isReadOnly(partition.Opts) || seen[partition.Device] ||
+				collector.isExcludedMount(partition.Device, partition.Mountpoint, partition.Fstype) {
+				continue
+			}
+			rotational, supported, err := discardSupport(partition.Device)
+			if err != nil || (rotational && !supported) {
+				continue // Spinning disks gain nothing from TRIM
+			}
+			seen[partition.Device] = true
+
+			info := DiskTrimInfo{
+				Mountpoint: partition.Mountpoint,
+				Device:     partition.Device,
+				Fstype:     partition.Fstype,
+				Rotational: rotational,
+				Supported:  supported,
+				Discard:    hasOnlineDiscard(partition.Opts) || hasOnlineDiscard(superOptions[partition.Mountpoint]),
+			}
+			if timerErr == nil {
+				info.TimerActive = timer.Active
+				info.LastTrim = timer.LastRun
+			}
+			info.Status, info.Message = collector.trimStatus(info, timerErr, time.Now())
+			trimInfos = append(trimInfos, info)
+		}
+
+		collector.trimCache = trimInfos
+		collector.trimTime = time.Now()
+	}
+
+	data.Trim = collector.trimCache
+	for _, trim := range data.Trim {
+		if trim.Status == "Warning" {
+			data.TrimWarning = true
+		}
+	}
+	return nil
+}
+
+// trimStatus rates how well a filesystem is trimmed and says what to do about it
+func (collector *DiskMonitorCollector) trimStatus(info DiskTrimInfo, timerErr error, now time.Time) (string, string) {
+	switch {
+	case !info.Supported:
+		message := "the device does not accept discards"
+		if strings.HasPrefix(info.Device, "/dev/mapper/") || strings.HasPrefix(filepath.Base(info.Device), "dm-") {
+			message += "; for dm-crypt, open the volume with the discard option (allow-discards)"
+		}
+		return "Unsupported", message
+	case info.Discard:
+		return "OK", "trimmed continuously (mounted with discard)"
+	case timerErr != nil:
+		return "Unknown", "last fstrim unknown: " + timerErr.Error()
+	case info.LastTrim.IsZero():
+		if !info.TimerActive {
+			return "Warning", "fstrim has never run and fstrim.timer is off; " + enableFstrimHint
+		}
+		return "Warning", "fstrim has never run"
+	case now.Sub(info.LastTrim) > collector.config.TrimMaxAge:
+		return "Warning", fmt.Sprintf("fstrim has not run for over %d days", int(collector.config.TrimMaxAge.Hours()/24))
+	case !info.TimerActive:
+		return "Warning", "fstrim.timer is off, so trim will not run again; " + enableFstrimHint
+	}
+	return "OK", "trimmed periodically by fstrim.timer"
+}
+
+// hasOnlineDiscard reports whether mount options turn on online discard (discard, or btrfs discard=async)
+func hasOnlineDiscard(opts []string) bool {
+	for _, opt := range opts {
+		if opt == "discard" || strings.HasPrefix(opt, "discard=") {
+			return true
+		}
+	}
+	return false
+}
+
+// readSuperOptions reads the filesystem options of every mount from /proc/self/mountinfo, keyed by mountpoint
+// discard is a filesystem option, listed after the " - " separator, while gopsutil only reports the
+// per-mount options (rw, relatime...) before it
+func readSuperOptions() map[string][]string {
+	options := make(map[string][]string)
+	content, err := os.ReadFile("/proc/self/mountinfo")
+	if err != nil {
+		return options
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		mount, filesystem, found := strings.Cut(line, " - ")
+		if !found {
+			continue
+		}
+		mountFields, filesystemFields := strings.Fields(mount), strings.Fields(filesystem)
+		if len(mountFields) < 5 || len(filesystemFields) < 3 {
+			continue
+		}
+		options[mountFields[4]] = strings.Split(filesystemFields[2], ",")
+	}
+	return options
+}
+
+// discardSupport reads from sysfs whether a device's drive is rotational and whether it accepts discards
+// Partitions have no request queue of their own, so the queue of the drive holding them is read
+func discardSupport(device string) (bool, bool, error) {
+	if !strings.HasPrefix(device, "/dev/") {
+		return false, false, fmt.Errorf("%s is not a block device", device)
+	}
+	queue := filepath.Join("/sys/class/block", filepath.Base(parentDrive(device)), "queue")
+	rotational, err := readQueueValue(queue, "rotational")
+	if err != nil {
+		return false, false, err
+	}
+	maxDiscard, err := readQueueValue(queue, "discard_max_bytes")
+	if err != nil {
+		return false, false, err
+	}
+	return rotational == 1, maxDiscard > 0, nil
+}
+
+// readQueueValue reads a number from a block device's sysfs queue directory
+func readQueueValue(queue, name string) (uint64, error) {
+	content, err := os.ReadFile(filepath.Join(queue, name))
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(strings.TrimSpace(string(content)), 10, 64)
+}
+
+// readFstrimTimer asks systemd when fstrim.timer last ran and whether it is scheduled
+// Machines without systemd may trim from cron, which cannot be checked, so the last run is then unknown
+func readFstrimTimer() (fstrimTimer, error) {
+	var timer fstrimTimer
+	args := []string{"show", "fstrim.timer", "--property=LoadState,ActiveState,LastTriggerUSec"}
+	output, err := exec.Command("systemctl", append(args, "--timestamp=unix")...).Output()
+	if err != nil && !errors.Is(err, exec.ErrNotFound) {
+		// systemd before v248 has no --timestamp option and prints local times instead
+		output, err = exec.Command("systemctl", args...).Output()
+	}
+	if err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return timer, errors.New("systemctl not found (fstrim cron jobs are not checked)")
+		}
+		return timer, errors.New("systemd is not running (fstrim cron jobs are not checked)")
+	}
+
+	for _, line := range strings.Split(string(output), "\n") {
+		key, value, found := strings.Cut(strings.TrimSpace(line), "=")
+		if !found {
+			continue
+		}
+		switch key {
+		case "LoadState":
+			if value == "not-found" {
+				return timer, errors.New("fstrim.timer is not installed (it comes with util-linux)")
+			}
+		case "ActiveState":
+			timer.Active = value == "active"
+		case "LastTriggerUSec":
+			timer.LastRun = parseSystemdTimestamp(value)
+		}
+	}
+	return timer, nil
+}
+
+// parseSystemdTimestamp reads a timestamp printed by systemctl show ("@1718000000" or "Mon 2024-06-10 00:34:58 UTC")
+// Timers that never fired print "n/a" or nothing, which gives the zero time
+func parseSystemdTimestamp(value string) time.Time {
+	if seconds, found := strings.CutPrefix(value, "@"); found {
+		if unix, err := strconv.ParseInt(seconds, 10, 64); err == nil && unix > 0 {
+			return time.Unix(unix, 0)
+		}
+		return time.Time{}
+	}
+	parsed, err := time.ParseInLocation("Mon 2006-01-02 15:04:05 MST", value, time.Local)
+	if err != nil {
+		return time.Time{}
+	}
+	return parsed
+}
@@ -1,6 +1,11 @@
 package diskmonitor
 
-import "time"
+import (
+	"github.com/ahmadreza-log/simple-monitor/alert"
+	"github.com/ahmadreza-log/simple-monitor/partial"
+	"slices"
+	"time"
+)
 
 // DiskPartitionInfo represents information about a disk partition
 type DiskPartitionInfo struct {
@@ -8,12 +13,17 @@ type DiskPartitionInfo struct {
 	Mountpoint  string `json:"mountpoint"`   // Mount point (e.g., /, /home)
 	Fstype      string `json:"fstype"`       // File system type (e.g., ext4, ntfs)
 	Total       uint64 `json:"total"`        // Total size in bytes
-	Free        uint64 `json:"free"`         // Free space in bytes
+	Free        uint64 `json:"free"`         // Space available to unprivileged users in bytes (df "Avail")
+	TrueFree    uint64 `json:"true_free"`    // Free space including root-reserved blocks in bytes
+	Reserved    uint64 `json:"reserved"`     // Blocks reserved for root (e.g. ext4 reserved blocks) in bytes
 	Used        uint64 `json:"used"`         // Used space in bytes
-	UsagePercent float64 `json:"usage_percent"` // Usage percentage
+	UsagePercent float64 `json:"usage_percent"` // Usage percentage, computed like df: used / (used + available)
 	InodesTotal uint64 `json:"inodes_total"` // Total inodes
 	InodesFree  uint64 `json:"inodes_free"`   // Free inodes
 	InodesUsed  uint64 `json:"inodes_used"`  // Used inodes
+	Excluded    bool   `json:"excluded"`     // Whether the mount is left out of totals and alerts
+	ReadOnly    bool   `json:"read_only"`    // Whether the filesystem is currently mounted read-only
+	RemountedReadOnly bool `json:"remounted_read_only"` // Whether it flipped to read-only after being seen read-write (usually disk errors)
 }
 
 // DiskIOInfo represents disk I/O statistics
@@ -28,9 +38,26 @@ type DiskIOInfo struct {
 	ReadSpeed     float64 `json:"read_speed"`     // Read speed (MB/s)
 	WriteSpeed    float64 `json:"write_speed"`   // Write speed (MB/s)
 	IOPS          float64 `json:"iops"`           // I/O operations per second
-	Utilization   float64 `json:"utilization"`   // Disk utilization percentage
+	Utilization   float64 `json:"utilization"`   // Disk utilization percentage (share of capability for calibrated devices)
+	ReadPercent   float64 `json:"read_percent"`  // Read speed as a percentage of the device's maximum (calibrated devices only)
+	WritePercent  float64 `json:"write_percent"` // Write speed as a percentage of the device's maximum (calibrated devices only)
+	Calibrated    bool    `json:"calibrated"`    // Whether the device has a recorded maximum throughput
 }
 
+// DeviceCapability is the maximum throughput of a device, measured by a calibration benchmark or entered from its specs
+type DeviceCapability struct {
+	MaxReadSpeed  float64   `json:"max_read_speed"`  // Maximum sequential read speed (MB/s)
+	MaxWriteSpeed float64   `json:"max_write_speed"` // Maximum sequential write speed (MB/s)
+	Source        string    `json:"source"`          // benchmark or rated
+	MeasuredAt    time.Time `json:"measured_at"`     // When it was recorded
+}
+
+// Capability sources
+const (
+	CapabilityBenchmark = "benchmark" // Measured by the calibration benchmark
+	CapabilityRated     = "rated"     // Entered from the manufacturer's specs
+)
+
 // DiskTemperatureInfo represents disk temperature information
 type DiskTemperatureInfo struct {
 	DeviceName    string  `json:"device_name"`    // Device name
@@ -50,11 +77,64 @@ type DiskHealthInfo struct {
 	UncorrectableSectors uint64 `json:"uncorrectable_sectors"` // Uncorrectable sectors count
 	Temperature     float64 `json:"temperature"`     // Current temperature
 	WearLeveling    float64 `json:"wear_leveling"`   // Wear leveling percentage (SSD)
+	SelfTest        string  `json:"self_test"`       // Self-test progress or latest result (e.g. "short 40%"), empty when none was run
+}
+
+// DiskNVMeInfo represents the health log of an NVMe controller, read with smartctl -j
+type DiskNVMeInfo struct {
+	Device                  string  `json:"device"`                    // Controller device (e.g. /dev/nvme0)
+	Model                   string  `json:"model"`                     // Model name
+	PercentageUsed          float64 `json:"percentage_used"`           // Vendor estimate of life used (wear); may exceed 100
+	AvailableSpare          float64 `json:"available_spare"`           // Remaining spare capacity percentage
+	AvailableSpareThreshold float64 `json:"available_spare_threshold"` // Spare percentage below which the drive warns
+	MediaErrors             uint64  `json:"media_errors"`              // Unrecovered data integrity errors
+	ErrorLogEntries         uint64  `json:"error_log_entries"`         // Entries in the controller error log
+	CriticalWarning         int     `json:"critical_warning"`          // Critical warning bits reported by the controller (0 when none)
+	Temperature             float64 `json:"temperature"`               // Controller composite temperature in Celsius
+	DataRead                uint64  `json:"data_read"`                 // Bytes read over the drive's life
+	DataWritten             uint64  `json:"data_written"`              // Bytes written over the drive's life
+	PowerOnHours            uint64  `json:"power_on_hours"`            // Power-on hours
+	UnsafeShutdowns         uint64  `json:"unsafe_shutdowns"`          // Power losses without a clean shutdown
+	NamespaceSize           uint64  `json:"namespace_size"`            // Total size of all namespaces in bytes
+	NamespaceUsed           uint64  `json:"namespace_used"`            // Bytes allocated in all namespaces
+	NamespaceUtilization    float64 `json:"namespace_utilization"`     // Allocated percentage of the namespaces
+	SelfTest                string  `json:"self_test"`                 // Self-test progress or latest result, empty when none was run
+	Status                  string  `json:"status"`                    // Normal, Warning or Critical
+	Error                   string  `json:"error,omitempty"`           // Why the health log could not be read
+}
+
+// DiskSelfTestInfo represents the SMART self-tests of a drive queued, running or finished in this session
+type DiskSelfTestInfo struct {
+	Device            string   `json:"device"`              // Drive the tests run on (e.g. /dev/sda)
+	Running           string   `json:"running"`             // Test in progress (short, long), empty when idle
+	Progress          float64  `json:"progress"`            // Percentage of the running test completed
+	Queued            []string `json:"queued"`              // Tests waiting for the running one to finish
+	LastType          string   `json:"last_type"`           // Type of the most recent finished test, as the drive logs it
+	LastResult        string   `json:"last_result"`         // Result of the most recent finished test
+	LastPassed        bool     `json:"last_passed"`         // Whether the most recent test completed without error
+	LastLifetimeHours uint64   `json:"last_lifetime_hours"` // Power-on hours when the most recent test ran
+	Error             string   `json:"error,omitempty"`     // Why the drive could not be queried or the test started
+}
+
+// DiskTrimInfo represents the TRIM (discard) state of a filesystem on an SSD or a disk that accepts discards
+// Without TRIM the drive does not learn which blocks are free, and writes slow down as it fills
+type DiskTrimInfo struct {
+	Mountpoint  string    `json:"mountpoint"`   // Mount point
+	Device      string    `json:"device"`       // Block device
+	Fstype      string    `json:"fstype"`       // File system type
+	Rotational  bool      `json:"rotational"`   // Whether the kernel reports a spinning disk (virtual disks often do while accepting discards)
+	Supported   bool      `json:"supported"`    // Whether the device accepts discards
+	Discard     bool      `json:"discard"`      // Whether the filesystem is mounted with online discard
+	TimerActive bool      `json:"timer_active"` // Whether fstrim.timer is scheduled
+	LastTrim    time.Time `json:"last_trim"`    // When fstrim.timer last ran (zero when never or unknown)
+	Status      string    `json:"status"`       // OK, Warning, Unknown or Unsupported
+	Message     string    `json:"message"`      // What the status means and how to fix it
 }
 
 // DiskProcessInfo represents disk usage information for a specific process
 type DiskProcessInfo struct {
 	PID           int32   `json:"pid"`            // Process ID
+	InstanceID    string  `json:"instance_id"`    // Process instance ID (PID and start time)
 	Name          string  `json:"name"`           // Process name
 	ReadBytes     uint64  `json:"read_bytes"`    // Bytes read by process
 	WriteBytes    uint64  `json:"write_bytes"`    // Bytes written by process
@@ -66,13 +146,33 @@ type DiskProcessInfo struct {
 	User          string  `json:"user"`           // Process owner
 }
 
+// GrowingFileInfo represents an open file that grew between two samples
+type GrowingFileInfo struct {
+	Path        string  `json:"path"`         // File path
+	Size        uint64  `json:"size"`         // Current size in bytes
+	Growth      uint64  `json:"growth"`       // Bytes added since the previous sample
+	GrowthRate  float64 `json:"growth_rate"`  // Growth rate in bytes per second
+	PID         int32   `json:"pid"`          // Process holding the file open
+	ProcessName string  `json:"process_name"` // Name of that process
+}
+
+// DiskCleanupCandidate represents a location whose contents could be removed to reclaim space
+type DiskCleanupCandidate struct {
+	Category string `json:"category"` // Candidate category (Trash, Package Cache, Journal Logs, Browser Cache, Monitor Logs)
+	Path     string `json:"path"`     // Directory that holds the reclaimable data
+	Size     uint64 `json:"size"`     // Total size of the files in bytes
+	Files    int    `json:"files"`    // Number of files
+	Hint     string `json:"hint"`     // Suggested way to reclaim the space
+}
+
 // DiskMonitorData represents comprehensive disk monitoring data
 type DiskMonitorData struct {
 	// Overall disk statistics
 	TotalSpace     uint64 `json:"total_space"`      // Total disk space across all partitions
 	UsedSpace      uint64 `json:"used_space"`       // Used disk space across all partitions
-	FreeSpace      uint64 `json:"free_space"`       // Free disk space across all partitions
-	UsagePercent   float64 `json:"usage_percent"`  // Overall disk usage percentage
+	FreeSpace      uint64 `json:"free_space"`       // Free disk space across all partitions (available to unprivileged users)
+	ReservedSpace  uint64 `json:"reserved_space"`   // Root-reserved disk space across all partitions
+	UsagePercent   float64 `json:"usage_percent"`  // Overall disk usage percentage, computed like df
 
 	// Disk partitions information
 	Partitions []DiskPartitionInfo `json:"partitions"` // Information about each partition
@@ -89,6 +189,23 @@ type DiskMonitorData struct {
 	// Top processes by disk usage
 	TopProcesses []DiskProcessInfo `json:"top_processes"` // Top disk-consuming processes
 
+	// NVMe controller health (replaces the generic health rows of NVMe drives)
+	NVMe []DiskNVMeInfo `json:"nvme"` // Health log per NVMe controller
+
+	// SMART self-tests queued or run from the monitor
+	SelfTests []DiskSelfTestInfo `json:"self_tests"` // Self-test state per drive
+
+	// TRIM status of filesystems on SSDs (Linux)
+	Trim        []DiskTrimInfo `json:"trim"`         // TRIM state per filesystem
+	TrimWarning bool           `json:"trim_warning"` // A filesystem is not trimmed, or not recently
+
+	// Open files that are growing fastest ("what is filling my disk right now")
+	GrowingFiles []GrowingFileInfo `json:"growing_files"` // Fastest-growing open files, largest rate first
+
+	// Cleanup suggestions (only analyzed when space is low)
+	CleanupCandidates []DiskCleanupCandidate `json:"cleanup_candidates"` // Reclaimable space candidates, largest first
+	ReclaimableSpace  uint64                 `json:"reclaimable_space"`  // Total size of all candidates
+
 	// Performance metrics
 	TotalReadSpeed  float64 `json:"total_read_speed"`  // Total read speed across all disks
 	TotalWriteSpeed float64 `json:"total_write_speed"` // Total write speed across all disks
@@ -101,11 +218,22 @@ type DiskMonitorData struct {
 	HighTempWarning  bool   `json:"high_temp_warning"`   // High temperature warning
 	HealthWarning    bool   `json:"health_warning"`     // Disk health warning
 	IOBottleneck     bool   `json:"io_bottleneck"`       // I/O bottleneck detection
+	ReadOnlyWarning  bool     `json:"read_only_warning"` // A filesystem was remounted read-only
+	ReadOnlyMounts   []string `json:"read_only_mounts"`  // Mountpoints remounted read-only since they were last seen read-write
 
 	// Monitoring configuration
 	RefreshInterval time.Duration `json:"refresh_interval"` // How often data is refreshed
 	IsMonitoring    bool          `json:"is_monitoring"`    // Whether monitoring is active
 
+	// Runbook notes of the raised alerts whose rule has a note
+	AlertNotes []alert.Note `json:"alert_notes,omitempty"` // Ordered by alert key
+
+	// Sections that could not be collected; everything else in the snapshot is still valid
+	SectionErrors partial.Errors `json:"section_errors,omitempty"` // Reason keyed by section name
+
+	// Data source
+	Simulated bool `json:"simulated,omitempty"` // Whether the data is synthetic (--simulate) rather than read from the system
+
 	// Timestamps
 	Timestamp time.Time     `json:"timestamp"` // When this data was collected
 	Uptime    time.Duration `json:"uptime"`    // System uptime
@@ -113,6 +241,8 @@ type DiskMonitorData struct {
 
 // DiskMonitorConfig represents configuration options for disk monitoring
 type DiskMonitorConfig struct {
+	Enabled bool `json:"enabled"` // Whether the monitor runs; a disabled monitor is left out of every view, snapshot and alert
+
 	// Monitoring settings
 	RefreshInterval     time.Duration `json:"refresh_interval"`     // How often to refresh data
 	MaxProcesses        int           `json:"max_processes"`        // Maximum number of processes to track
@@ -121,6 +251,15 @@ type DiskMonitorConfig struct {
 	TempWarning         float64       `json:"temp_warning"`        // Temperature warning threshold (Celsius)
 	TempCritical       float64       `json:"temp_critical"`        // Temperature critical threshold (Celsius)
 	IOBottleneckThreshold float64    `json:"io_bottleneck_threshold"` // I/O bottleneck threshold (percentage)
+	DeviceCapabilities map[string]DeviceCapability `json:"device_capabilities"` // Maximum throughput keyed by device (sda, nvme0n1); calibrated devices report speeds as a share of it
+
+	NVMeWearWarning     float64       `json:"nvme_wear_warning"`     // NVMe percentage used (wear) warning threshold
+	NVMeWearCritical    float64       `json:"nvme_wear_critical"`    // NVMe percentage used (wear) critical threshold
+	NVMeTempWarning     float64       `json:"nvme_temp_warning"`     // NVMe controller temperature warning threshold (Celsius)
+	NVMeTempCritical    float64       `json:"nvme_temp_critical"`    // NVMe controller temperature critical threshold (Celsius)
+
+	// Alert behaviour (hysteresis and minimum durations) keyed by alert: disk_space, disk_temperature, io_bottleneck, nvme_wear, nvme_temperature
+	AlertRules map[string]alert.Rule `json:"alert_rules"` // Alerts without a rule flip exactly at their thresholds
 
 	// Display settings
 	ShowPartitions    bool `json:"show_partitions"`     // Whether to show partition information
@@ -129,17 +268,39 @@ type DiskMonitorConfig struct {
 	ShowHealth       bool `json:"show_health"`       // Whether to show health information
 	ShowProcesses    bool `json:"show_processes"`    // Whether to show process information
 	ShowPerformance  bool `json:"show_performance"`   // Whether to show performance metrics
+	ShowCleanupSuggestions bool `json:"show_cleanup_suggestions"` // Whether to look for reclaimable space when space is low
+	ShowGrowingFiles       bool `json:"show_growing_files"`       // Whether to sample open files and report the fastest-growing ones
+	ShowNVMe               bool `json:"show_nvme"`                // Whether to read NVMe health logs with smartctl
+	ShowTrim               bool `json:"show_trim"`                // Whether to check TRIM/discard and the last fstrim run of SSD filesystems (Linux)
 
 	// Export settings
 	ExportToFile   bool          `json:"export_to_file"`  // Whether to export data to file
 	ExportInterval time.Duration `json:"export_interval"` // How often to export data
-	ExportFormat   string        `json:"export_format"`   // Export format (json, csv, txt)
+	ExportFormat   string        `json:"export_format"`   // Export format (json, csv, txt, protobuf)
+	PersistHistory      bool          `json:"persist_history"`       // Whether to keep downsampled long-term history on disk
+	HistorySaveInterval time.Duration `json:"history_save_interval"` // How often the long-term history file is rewritten
+	HistorySize         int           `json:"history_size"`          // Samples kept in the in-memory history behind live trends
+	HistoryResolution   time.Duration `json:"history_resolution"`    // Minimum time between in-memory history samples (0 samples every refresh)
 
 	// Filter settings
 	MinIOUsage         float64 `json:"min_io_usage"`         // Minimum I/O usage to show process
 	ProcessNameFilter  string  `json:"process_name_filter"`  // Filter processes by name
 	DeviceFilter       string  `json:"device_filter"`        // Filter specific devices
 	MountpointFilter   string  `json:"mountpoint_filter"`   // Filter specific mountpoints
+	ExcludeMounts      []string `json:"exclude_mounts"`     // Glob patterns (mountpoint, device or fstype) left out of totals and alerts
+	ShowExcludedMounts bool     `json:"show_excluded_mounts"` // Whether excluded mounts are still listed in the partitions table
+
+	// Growing file settings
+	MaxGrowingFiles      int           `json:"max_growing_files"`      // Maximum number of growing files to report
+	GrowingFilesInterval time.Duration `json:"growing_files_interval"` // Minimum time between open file samples
+
+	// Cleanup suggestion settings
+	CleanupCheckInterval time.Duration `json:"cleanup_check_interval"` // How often to re-measure cleanup candidates
+	SelfTestPollInterval time.Duration `json:"self_test_poll_interval"` // How often smartctl is asked for the progress of a running self-test
+	NVMeCheckInterval    time.Duration `json:"nvme_check_interval"`     // How often NVMe health logs are re-read
+	TrimCheckInterval    time.Duration `json:"trim_check_interval"`     // How often TRIM status and fstrim.timer are re-read
+	TrimMaxAge           time.Duration `json:"trim_max_age"`            // Time since the last fstrim after which a filesystem is flagged
+	LogsDirectory        string        `json:"logs_directory"`         // The monitor's own export directory, reported as a candidate
 }
 
 // DiskUsageHistory represents historical disk usage data for graphing
@@ -156,3 +317,15 @@ type DiskUsageHistory struct {
 	MaxDataPoints int `json:"max_data_points"` // Maximum number of data points to store
 	DataPointCount int `json:"data_point_count"` // Current number of data points
 }
+
+// Clone returns a deep copy of the history
+func (history *DiskUsageHistory) Clone() *DiskUsageHistory {
+	clone := *history
+	clone.Timestamps = slices.Clone(history.Timestamps)
+	clone.TotalUsage = slices.Clone(history.TotalUsage)
+	clone.ReadSpeed = slices.Clone(history.ReadSpeed)
+	clone.WriteSpeed = slices.Clone(history.WriteSpeed)
+	clone.IOPS = slices.Clone(history.IOPS)
+	clone.Utilization = slices.Clone(history.Utilization)
+	return &clone
+}
@@ -0,0 +1,436 @@
+// Package doctor checks what limits the data simple-monitor can show (permissions, optional tools,
+// the logs directory, the config file and the clock) and suggests a fix for every problem
+package doctor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/ahmadreza-log/simple-monitor/config"
+	"github.com/ahmadreza-log/simple-monitor/privhelper"
+	"github.com/ahmadreza-log/simple-monitor/provider"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// minClockYear is the earliest plausible year; a clock before it was never set (no RTC battery, no NTP yet)
+const minClockYear = 2024
+
+// clockSkewTolerance is how far in the future a file in the logs directory may be dated before the clock is suspect
+const clockSkewTolerance = time.Minute
+
+// toolTimeout bounds every external command run by the checks
+const toolTimeout = 5 * time.Second
+
+// smartctlOpenFailed is the exit status bit smartctl sets when it could not open the device
+const smartctlOpenFailed = 1 << 1
+
+// Run performs every check, in checklist order
+func Run(options Options) []Result {
+	return []Result{
+		checkProcesses(options.System.Proc),
+		checkConnections(options.System.Net),
+		checkSMART(),
+		checkPing(),
+		checkHelper(),
+		checkLogsDir(options.LogsDir),
+		checkConfig(options.ConfigPath),
+		checkClock(options.LogsDir),
+	}
+}
+
+// Failed reports whether any check failed
+func Failed(results []Result) bool {
+	for _, result := range results {
+		if result.Status == StatusFailed {
+			return true
+		}
+	}
+	return false
+}
+
+// checkProcesses verifies the process table can be listed and other users' processes can be inspected
+func checkProcesses(source provider.ProcProvider) Result {
+	result := Result{Name: "Process access"}
+	processes, err := source.Processes()
+	if err != nil {
+		return result.failed(fmt.Sprintf("cannot list processes: %v", err), privilegeFix())
+	}
+
+	readable := 0
+	for _, p := range processes {
+		if inspectable(p) {
+			readable++
+		}
+	}
+	if readable < len(processes) {
+		return result.warning(
+			fmt.Sprintf("%d of %d processes readable; open files, I/O and paths of the others are hidden", readable, len(processes)),
+			privilegeFix())
+	}
+	return result.ok(fmt.Sprintf("all %d processes readable", len(processes)))
+}
+
+// inspectable reports whether the details that need elevated access for other users' processes can be read
+// The open descriptor count is the first such detail on Linux and Windows; macOS only guards the executable path
+func inspectable(p provider.Process) bool {
+	if runtime.GOOS == "darwin" {
+		_, err := p.Exe()
+		return err == nil
+	}
+	_, err := p.NumFDs()
+	return err == nil
+}
+
+// checkConnections verifies connections can be listed with their owning processes
+func checkConnections(source provider.NetProvider) Result {
+	result := Result{Name: "Connections"}
+	connections, err := source.Connections("inet")
+	if err != nil {
+		return result.failed(fmt.Sprintf("cannot list connections: %v", err), privilegeFix())
+	}
+
+	// TIME_WAIT sockets belong to no process even with full access
+	owned, total := 0, 0
+	for _, connection := range connections {
+		if connection.Status == "TIME_WAIT" {
+			continue
+		}
+		total++
+		if connection.Pid != 0 {
+			owned++
+		}
+	}
+	if owned < total && os.Geteuid() == 0 {
+		// Already privileged: the rest belong to the kernel or to another PID namespace
+		return result.ok(fmt.Sprintf("%d connections, %d with a known owner (the others are kernel or container sockets)", total, owned))
+	}
+	if owned < total {
+		return result.warning(
+			fmt.Sprintf("%d of %d connections have a known owner; per-process network usage misses the rest", owned, total),
+			privilegeFix())
+	}
+	return result.ok(fmt.Sprintf("%d connections, all with a known owner", total))
+}
+
+// smartctlOutput is the subset of smartctl -j output the SMART check reads
+type smartctlOutput struct {
+	Smartctl struct {
+		ExitStatus int `json:"exit_status"`
+		Messages   []struct {
+			String string `json:"string"`
+		} `json:"messages"`
+	} `json:"smartctl"`
+	Devices []struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+	} `json:"devices"`
+}
+
+// checkSMART verifies smartctl is installed and can open the drives it finds
+func checkSMART() Result {
+	result := Result{Name: "SMART access"}
+	if _, err := exec.LookPath("smartctl"); err != nil {
+		return result.warning("smartctl not found; NVMe health and drive self-tests are unavailable", smartctlInstallFix())
+	}
+
+	var scan smartctlOutput
+	if err := runSmartctl(&scan, "--scan", "-j"); err != nil {
+		return result.warning(fmt.Sprintf("cannot list drives: %v", err), privilegeFix())
+	}
+	if len(scan.Devices) == 0 {
+		return result.ok("smartctl found; no drives to query")
+	}
+
+	var denied []string
+	var reason string
+	for _, device := range scan.Devices {
+		var info smartctlOutput
+		if err := runSmartctl(&info, "-j", "-i", "-d", device.Type, device.Name); err != nil {
+			return result.warning(fmt.Sprintf("cannot query %s: %v", device.Name, err), "run smartctl -i "+device.Name+" to see the full error")
+		}
+		if info.Smartctl.ExitStatus&smartctlOpenFailed != 0 {
+			denied = append(denied, device.Name)
+			if len(info.Smartctl.Messages) > 0 && reason == "" {
+				reason = info.Smartctl.Messages[0].String
+			}
+		}
+	}
+	if len(denied) > 0 {
+		detail := fmt.Sprintf("cannot open %s", strings.Join(denied, ", "))
+		if reason != "" {
+			detail += " (" + reason + ")"
+		}
+		return result.failed(detail, smartFix())
+	}
+	return result.ok(fmt.Sprintf("smartctl can read %d drive(s)", len(scan.Devices)))
+}
+
+// runSmartctl runs smartctl and parses its JSON output
+// smartctl exits non-zero for drive problems too, so the exit code is left to the exit_status field
+func runSmartctl(output *smartctlOutput, args ...string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), toolTimeout)
+	defer cancel()
+
+	content, err := exec.CommandContext(ctx, "smartctl", args...).Output()
+	if len(content) == 0 && err != nil {
+		return err
+	}
+	if err := json.Unmarshal(content, output); err != nil {
+		return fmt.Errorf("failed to parse smartctl output: %w", err)
+	}
+	return nil
+}
+
+// checkPing verifies ping is installed and allowed to send, as the gateway check relies on it
+func checkPing() Result {
+	result := Result{Name: "Ping"}
+	if _, err := exec.LookPath("ping"); err != nil {
+		return result.warning("ping not found; gateway reachability falls back to the ARP table", pingInstallFix())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), toolTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "windows":
+		cmd = exec.CommandContext(ctx, "ping", "-n", "1", "-w", "1000", "127.0.0.1")
+	case "darwin":
+		cmd = exec.CommandContext(ctx, "ping", "-c", "1", "-t", "1", "127.0.0.1")
+	default:
+		cmd = exec.CommandContext(ctx, "ping", "-c", "1", "-W", "1", "127.0.0.1")
+	}
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return result.warning(fmt.Sprintf("ping cannot send: %s", firstLine(output, err)), pingPermissionFix())
+	}
+	return result.ok("ping can reach the loopback address")
+}
+
+// checkHelper looks for the privileged helper that reads SMART data, sends ICMP and inspects
+// other users' processes on behalf of an unprivileged monitor
+func checkHelper() Result {
+	result := Result{Name: "Privileged helper"}
+	if runtime.GOOS == "windows" {
+		return result.ok("not used on Windows")
+	}
+	if os.Geteuid() == 0 {
+		return result.ok("not needed; running as root")
+	}
+
+	client, err := privhelper.Connect()
+	if err != nil {
+		return result.warning(fmt.Sprintf("not available: %v", err), helperFix())
+	}
+	defer client.Close()
+
+	info := client.Info()
+	detail := fmt.Sprintf("connected (PID %d) at %s", info.PID, client.Path)
+	if info.AllowSelfTest {
+		detail += "; SMART self-tests allowed"
+	}
+	return result.ok(detail)
+}
+
+// checkLogsDir verifies exports, history and the instance lock can be written
+func checkLogsDir(dir string) Result {
+	result := Result{Name: "Logs directory"}
+	fix := fmt.Sprintf("make %s writable by this user (chown/chmod), or run simple-monitor from a directory where it can create it", dir)
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return result.failed(fmt.Sprintf("cannot create %s: %v", dir, err), fix)
+	}
+	file, err := os.CreateTemp(dir, ".doctor-*")
+	if err != nil {
+		return result.failed(fmt.Sprintf("cannot write to %s: %v", dir, err), fix)
+	}
+	_, err = file.WriteString("ok")
+	file.Close()
+	os.Remove(file.Name())
+	if err != nil {
+		return result.failed(fmt.Sprintf("cannot write to %s: %v", dir, err), fix)
+	}
+
+	absolute, _ := filepath.Abs(dir)
+	return result.ok(fmt.Sprintf("%s is writable", absolute))
+}
+
+// checkConfig verifies the config file parses and validates
+func checkConfig(path string) Result {
+	result := Result{Name: "Config file"}
+	file, err := config.Load(path)
+	if os.IsNotExist(err) {
+		return result.ok(fmt.Sprintf("no config file at %s; built-in defaults are used", path))
+	}
+	if err != nil {
+		return result.failed(fmt.Sprintf("%s: %v", path, err), "fix the file so it is valid JSON, or move it away to use the defaults")
+	}
+
+	issues := file.Validate()
+	if len(issues) == 0 {
+		return result.ok(fmt.Sprintf("%s is valid (schema version %d)", path, file.Version))
+	}
+
+	first := issues[0]
+	detail := fmt.Sprintf("%s has %d issue(s); %s: %s", path, len(issues), first.Field, first.Message)
+	fix := first.Fix + " (simple-monitor config validate lists every issue)"
+	if config.HasErrors(issues) {
+		return result.failed(detail, fix)
+	}
+	return result.warning(detail, fix)
+}
+
+// checkClock verifies the wall clock is set, has not jumped backwards and (on Linux) is synchronized
+// Exports, history and data retention are all keyed by wall-clock time
+func checkClock(logsDir string) Result {
+	result := Result{Name: "Clock"}
+	now := time.Now()
+	if now.Year() < minClockYear {
+		return result.failed(fmt.Sprintf("the clock reads %s and was never set", now.Format("2006-01-02 15:04")), clockFix())
+	}
+
+	if newest := newestModTime(logsDir); newest.After(now.Add(clockSkewTolerance)) {
+		return result.warning(
+			fmt.Sprintf("files in %s are dated up to %s ahead of the clock; it went backwards", logsDir, newest.Sub(now).Round(time.Second)),
+			"check time synchronization; history charts and retention order data by these timestamps")
+	}
+
+	if synchronized, known := ntpSynchronized(); known && !synchronized {
+		return result.warning("the clock is not synchronized with NTP", clockFix())
+	}
+	return result.ok(now.Format("2006-01-02 15:04:05 MST"))
+}
+
+// newestModTime returns the latest modification time of any file in dir, or the zero time
+func newestModTime(dir string) time.Time {
+	var newest time.Time
+	filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return nil
+		}
+		if info, err := entry.Info(); err == nil && info.ModTime().After(newest) {
+			newest = info.ModTime()
+		}
+		return nil
+	})
+	return newest
+}
+
+// ntpSynchronized asks timedatectl whether the clock is synchronized; known is false where it cannot tell
+func ntpSynchronized() (synchronized bool, known bool) {
+	if runtime.GOOS != "linux" {
+		return false, false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), toolTimeout)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, "timedatectl", "show", "-p", "NTPSynchronized", "--value").Output()
+	if err != nil {
+		return false, false
+	}
+	switch strings.TrimSpace(string(output)) {
+	case "yes":
+		return true, true
+	case "no":
+		return false, true
+	}
+	return false, false
+}
+
+// firstLine returns the first line of a command's output, or the error when it printed nothing
+func firstLine(output []byte, err error) string {
+	if line, _, _ := strings.Cut(strings.TrimSpace(string(output)), "\n"); line != "" {
+		return line
+	}
+	return err.Error()
+}
+
+func (result Result) ok(detail string) Result {
+	result.Status, result.Detail = StatusOK, detail
+	return result
+}
+
+func (result Result) warning(detail, fix string) Result {
+	result.Status, result.Detail, result.Fix = StatusWarning, detail, fix
+	return result
+}
+
+func (result Result) failed(detail, fix string) Result {
+	result.Status, result.Detail, result.Fix = StatusFailed, detail, fix
+	return result
+}
+
+// privilegeFix explains how to give simple-monitor access to other users' processes
+func privilegeFix() string {
+	switch runtime.GOOS {
+	case "linux":
+		return "run with sudo, or grant the binary read access to other users' processes: sudo setcap cap_sys_ptrace,cap_dac_read_search+ep $(command -v simple-monitor)"
+	case "windows":
+		return "run from an elevated terminal (Run as administrator)"
+	}
+	return "run with sudo"
+}
+
+// smartFix explains how to let smartctl open the drives
+func smartFix() string {
+	switch runtime.GOOS {
+	case "linux":
+		return "run with sudo, or add your user to the disk group (sudo usermod -aG disk $USER) and log in again"
+	case "windows":
+		return "run from an elevated terminal (Run as administrator)"
+	}
+	return "run with sudo"
+}
+
+// smartctlInstallFix names the package that provides smartctl
+func smartctlInstallFix() string {
+	switch runtime.GOOS {
+	case "windows":
+		return "install smartmontools (winget install smartmontools.smartmontools) and add it to PATH"
+	case "darwin":
+		return "install smartmontools (brew install smartmontools)"
+	}
+	return "install smartmontools (apt install smartmontools, dnf install smartmontools)"
+}
+
+// pingInstallFix names the package that provides ping
+func pingInstallFix() string {
+	if runtime.GOOS == "linux" {
+		return "install ping (apt install iputils-ping, dnf install iputils)"
+	}
+	return "make sure ping is on the PATH"
+}
+
+// pingPermissionFix explains how to let ping send without root
+func pingPermissionFix() string {
+	if runtime.GOOS == "linux" {
+		return "allow unprivileged ping (sudo sysctl -w net.ipv4.ping_group_range=\"0 2147483647\") or sudo setcap cap_net_raw+ep $(command -v ping)"
+	}
+	return "check that a firewall or security policy does not block ICMP to the loopback address"
+}
+
+// helperFix explains how to install the privileged helper
+func helperFix() string {
+	if runtime.GOOS == "linux" {
+		return "install " + privhelper.BinaryName + " next to simple-monitor and run sudo chown root " + privhelper.BinaryName + " && sudo chmod u+s " + privhelper.BinaryName + ", or run it as a service (see README)"
+	}
+	return "install " + privhelper.BinaryName + " next to simple-monitor and make it setuid root (sudo chown root, sudo chmod u+s)"
+}
+
+// clockFix explains how to set and synchronize the clock
+func clockFix() string {
+	switch runtime.GOOS {
+	case "linux":
+		return "enable time synchronization: sudo timedatectl set-ntp true"
+	case "windows":
+		return "enable Settings → Time & language → Set time automatically, or run w32tm /resync"
+	case "darwin":
+		return "enable System Settings → General → Date & Time → Set time and date automatically"
+	}
+	return "set the clock and enable NTP"
+}
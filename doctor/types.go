@@ -0,0 +1,25 @@
+package doctor
+
+import "github.com/ahmadreza-log/simple-monitor/provider"
+
+// Status levels of a check result
+const (
+	StatusOK      = "ok"      // Works as expected
+	StatusWarning = "warning" // Works with less data, or an optional feature is unavailable
+	StatusFailed  = "failed"  // Does not work until fixed
+)
+
+// Result is the outcome of one check
+type Result struct {
+	Name   string `json:"name"`   // What was checked
+	Status string `json:"status"` // ok, warning or failed
+	Detail string `json:"detail"` // What was found
+	Fix    string `json:"fix"`    // How to fix it (empty when ok)
+}
+
+// Options selects what the checks look at
+type Options struct {
+	LogsDir    string             // Directory exports, history and the instance lock are written to
+	ConfigPath string             // Config file to validate
+	System     provider.Providers // Process and connection sources
+}
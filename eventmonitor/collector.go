@@ -0,0 +1,582 @@
+package eventmonitor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// eventPattern maps a log message pattern to an event category and severity
+type eventPattern struct {
+	category string
+	severity string
+	pattern  *regexp.Regexp
+}
+
+// eventPatterns lists the log message patterns recognized as hardware events
+// Patterns are checked in order, so more specific categories come first
+var eventPatterns = []eventPattern{
+	{CategoryOOM, "Critical", regexp.MustCompile(`(?i)out of memory|oom-kill|invoked oom-killer|oom_reaper|resource-exhaustion-detector`)},
+	{CategoryThermal, "Warning", regexp.MustCompile(`(?i)temperature above threshold|clock throttled|critical temperature|thermal (event|trip|shutdown)|kernel-processor-power.*thermal`)},
+	{CategoryUSB, "Warning", regexp.MustCompile(`(?i)usb \S+: (reset|device descriptor read.*error|device not accepting address)|usb disconnect|usbhub|usb.*over-current`)},
+	{CategoryDiskIO, "Critical", regexp.MustCompile(`(?i)i/o error|blk_update_request|medium error|ata\d+(\.\d+)?: (exception|failed command|error)|(ext4|xfs|btrfs)(-fs)? (error|warning)|nvme\S*: .*(timeout|reset)|bad block|\bdisk\b.*(error|retry)|ntfs.*corrupt`)},
+	{CategoryHardware, "Critical", regexp.MustCompile(`(?i)\bmce:|machine check|hardware error|edac|pcie bus error|aer:|whea`)},
+}
+
+// EventMonitorCollector handles the collection of system event data
+// This struct reads the kernel ring buffer or Windows System event log and classifies hardware events
+type EventMonitorCollector struct {
+	// Configuration: config is what the collection in progress reads, adopted from latest as it starts
+	// latest is replaced on every change and never modified, so it can be handed out without copying
+	// collectMutex is held for a whole collection, so collections started by the live loop, subscribers and
+	// the attach server run one at a time and never see config or the state they carry change under them
+	config       *EventMonitorConfig
+	latest       *EventMonitorConfig
+	configMutex  sync.Mutex
+	collectMutex sync.Mutex
+
+	// Event tracking
+	seenEvents      map[string]time.Time // Event key → event time, or when last read for entries without a timestamp
+	recentEvents    []SystemEvent
+	firstCollection bool
+
+	// USB tracking (nil until the first inventory, so devices already present are not reported as connected)
+	usbDevices map[string]USBDeviceInfo
+	usbEvents  []USBEventInfo
+
+	// Session tracking (nil until the first collection, so sessions already open are not reported as new)
+	sessions map[string]bool
+}
+
+// NewEventMonitorCollector creates a new instance of EventMonitorCollector
+// with default configuration values
+func NewEventMonitorCollector() *EventMonitorCollector {
+	config := &EventMonitorConfig{
+		Enabled:            true,
+		RefreshInterval:    5 * time.Second,
+		MaxEvents:          50,
+		MaxLogLines:        2000,
+		LookbackWindow:     24 * time.Hour,
+		WatchDiskIO:        true,
+		WatchOOM:           true,
+		WatchThermal:       true,
+		WatchUSB:           true,
+		WatchHardware:      true,
+		WatchUSBDevices:    true,
+		MaxUSBEvents:       20,
+		WatchSessions:      true,
+		AlertOnRemoteLogin: true,
+		AlertOnNewEvents:   true,
+		AlertMinSeverity:   "Warning",
+		ExportToFile:       true,
+		ExportInterval:     1 * time.Hour,
+		ExportFormat:       "json",
+	}
+
+	return &EventMonitorCollector{
+		config:          config,
+		latest:          config,
+		seenEvents:      make(map[string]time.Time),
+		firstCollection: true,
+	}
+}
+
+// CollectEventMonitorData gathers recent hardware events from the system log
+// Events already present on the first collection are shown but do not raise alerts
+func (collector *EventMonitorCollector) CollectEventMonitorData() (*EventMonitorData, error) {
+	collector.collectMutex.Lock()
+	defer collector.collectMutex.Unlock()
+	collector.adoptConfig()
+	data := &EventMonitorData{
+		Timestamp:       time.Now(),
+		RefreshInterval: collector.config.RefreshInterval,
+		IsMonitoring:    true,
+		CategoryCounts:  make(map[string]int),
+	}
+
+	// USB inventory does not depend on the log being readable
+	if collector.config.WatchUSBDevices {
+		collector.collectUSB(data)
+	}
+
+	// Login sessions, which may add remote login alerts
+	if collector.config.WatchSessions {
+		collector.collectSessions(data)
+	}
+
+	// Read raw log entries
+	entries, source, err := ReadKernelLog(collector.config.MaxLogLines)
+	data.LogSource = source
+	if err != nil {
+		// The log may require elevated privileges, so report rather than fail
+		data.SourceError = err.Error()
+		data.Events = slices.Clone(collector.recentEvents)
+		collector.countCategories(data)
+		return data, nil
+	}
+
+	// Classify entries and track new events
+	collector.collectEvents(data, entries)
+
+	// Raise alerts for new events
+	if collector.config.AlertOnNewEvents {
+		collector.collectEventAlerts(data)
+	}
+
+	collector.countCategories(data)
+	collector.firstCollection = false
+
+	return data, nil
+}
+
+// collectEvents classifies log entries and merges them into the recent event list
+func (collector *EventMonitorCollector) collectEvents(data *EventMonitorData, entries []LogEntry) {
+	cutoff := time.Time{}
+	if collector.config.LookbackWindow > 0 {
+		cutoff = time.Now().Add(-collector.config.LookbackWindow)
+	}
+
+	// Clear new flags from the previous collection
+	for i := range collector.recentEvents {
+		collector.recentEvents[i].IsNew = false
+	}
+
+	read := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		if !entry.Timestamp.IsZero() && entry.Timestamp.Before(cutoff) {
+			continue
+		}
+
+		category, severity, ok := ClassifyMessage(entry.Message)
+		if !ok || !collector.isCategoryWatched(category) {
+			continue
+		}
+
+		key := entry.Timestamp.String() + "|" + entry.Message
+		_, seen := collector.seenEvents[key]
+		read[key] = true
+		if entry.Timestamp.IsZero() {
+			// Entries without a timestamp are kept as seen for as long as the log still shows them
+			collector.seenEvents[key] = time.Now()
+		} else {
+			collector.seenEvents[key] = entry.Timestamp
+		}
+		if seen {
+			continue
+		}
+
+		event := SystemEvent{
+			Timestamp: entry.Timestamp,
+			Source:    entry.Source,
+			Category:  category,
+			Severity:  severity,
+			Message:   entry.Message,
+			IsNew:     !collector.firstCollection,
+		}
+		collector.recentEvents = append(collector.recentEvents, event)
+
+		if event.IsNew {
+			data.NewEventCount++
+		}
+	}
+
+	// Events past the lookback window are skipped before their key is checked, so their keys can go; without
+	// a window, the keys of events that scrolled out of the last MaxLogLines lines go instead
+	for key, seen := range collector.seenEvents {
+		if !cutoff.IsZero() && seen.Before(cutoff) || cutoff.IsZero() && !read[key] {
+			delete(collector.seenEvents, key)
+		}
+	}
+
+	// Sort newest first
+	sort.SliceStable(collector.recentEvents, func(i, j int) bool {
+		return collector.recentEvents[i].Timestamp.After(collector.recentEvents[j].Timestamp)
+	})
+
+	// Limit to max events
+	if len(collector.recentEvents) > collector.config.MaxEvents {
+		collector.recentEvents = collector.recentEvents[:collector.config.MaxEvents]
+	}
+
+	// The data outlives the collection (published snapshots, subscribers), while the next collection
+	// clears IsNew and re-sorts recentEvents in place
+	data.Events = slices.Clone(collector.recentEvents)
+}
+
+// collectEventAlerts creates alerts for newly observed events
+func (collector *EventMonitorCollector) collectEventAlerts(data *EventMonitorData) {
+	var alerts []EventAlertInfo
+
+	for _, event := range data.Events {
+		if !event.IsNew {
+			continue
+		}
+		if collector.config.AlertMinSeverity == "Critical" && event.Severity != "Critical" {
+			continue
+		}
+
+		alerts = append(alerts, EventAlertInfo{
+			Category:     event.Category,
+			AlertMessage: fmt.Sprintf("%s event: %s", event.Category, event.Message),
+			Severity:     event.Severity,
+			Timestamp:    event.Timestamp,
+		})
+	}
+
+	data.Alerts = append(data.Alerts, alerts...)
+}
+
+// countCategories counts recent events per category
+func (collector *EventMonitorCollector) countCategories(data *EventMonitorData) {
+	for _, event := range data.Events {
+		data.CategoryCounts[event.Category]++
+	}
+}
+
+// isCategoryWatched reports whether a category is enabled in the configuration
+func (collector *EventMonitorCollector) isCategoryWatched(category string) bool {
+	switch category {
+	case CategoryDiskIO:
+		return collector.config.WatchDiskIO
+	case CategoryOOM:
+		return collector.config.WatchOOM
+	case CategoryThermal:
+		return collector.config.WatchThermal
+	case CategoryUSB:
+		return collector.config.WatchUSB
+	case CategoryHardware:
+		return collector.config.WatchHardware
+	}
+	return false
+}
+
+// ClassifyMessage returns the event category and severity for a log message
+// The last return value is false if the message is not a recognized hardware event
+func ClassifyMessage(message string) (string, string, bool) {
+	for _, pattern := range eventPatterns {
+		if pattern.pattern.MatchString(message) {
+			return pattern.category, pattern.severity, true
+		}
+	}
+	return "", "", false
+}
+
+// ReadKernelLog reads up to maxLines recent entries from the kernel ring buffer
+// (dmesg, falling back to journalctl -k) or the Windows System event log
+// It returns the entries, the name of the tool used, and any error
+func ReadKernelLog(maxLines int) ([]LogEntry, string, error) {
+	if runtime.GOOS == "windows" {
+		entries, err := readWindowsSystemLog(maxLines)
+		return entries, "wevtutil", err
+	}
+
+	entries, err := readDmesg(maxLines)
+	if err == nil {
+		return entries, "dmesg", nil
+	}
+
+	// dmesg is often restricted to root, while journalctl works for the adm/systemd-journal groups
+	entries, journalErr := readJournalKernel(maxLines)
+	if journalErr != nil {
+		return nil, "", fmt.Errorf("failed to read kernel log: %v; %v", err, journalErr)
+	}
+	return entries, "journalctl", nil
+}
+
+// ReadProcessErrors reads recent error-level log lines written by a process or its service
+// On Linux the systemd unit owning the PID is queried when known, otherwise lines are matched
+// by command name; on Windows the Application event log is queried by provider name
+// It returns the entries, the unit or provider the lines were matched on, and any error
+func ReadProcessErrors(pid int32, name string, since time.Duration, maxLines int) ([]LogEntry, string, error) {
+	if runtime.GOOS == "windows" {
+		provider := strings.TrimSuffix(name, ".exe")
+		entries, err := readWindowsApplicationErrors(provider, since, maxLines)
+		return entries, provider, err
+	}
+
+	// Match on the whole service when the process belongs to one, so errors
+	// logged by sibling workers are included
+	match := "_COMM=" + truncateComm(name)
+	unit := systemdUnitForPID(pid)
+	if unit != "" {
+		match = "_SYSTEMD_UNIT=" + unit
+	}
+
+	args := []string{match, "-p", "err", "-o", "short-iso", "--no-pager", "-q",
+		"-n", strconv.Itoa(maxLines)}
+	if since > 0 {
+		args = append(args, "--since", fmt.Sprintf("-%ds", int(since.Seconds())))
+	}
+
+	output, err := exec.Command("journalctl", args...).Output()
+	if err != nil {
+		return nil, unit, fmt.Errorf("failed to run journalctl: %w", err)
+	}
+
+	var entries []LogEntry
+	for _, line := range strings.Split(string(output), "\n") {
+		// Format: "2024-01-02T15:04:05+0000 hostname name[1234]: message"
+		fields := strings.SplitN(line, " ", 3)
+		if len(fields) < 3 {
+			continue
+		}
+
+		message := fields[2]
+		if index := strings.Index(message, ": "); index >= 0 {
+			message = message[index+2:]
+		}
+
+		entries = append(entries, LogEntry{
+			Timestamp: parseLogTime(fields[0]),
+			Source:    "journal",
+			Level:     "err",
+			Message:   strings.TrimSpace(message),
+		})
+	}
+
+	if unit == "" {
+		unit = name
+	}
+	return entries, unit, nil
+}
+
+// systemdUnitForPID returns the systemd service unit a process belongs to, if any
+func systemdUnitForPID(pid int32) string {
+	content, err := os.ReadFile(fmt.Sprintf("/proc/%d/cgroup", pid))
+	if err != nil {
+		return ""
+	}
+
+	for _, line := range strings.Split(string(content), "\n") {
+		// Format: "0::/system.slice/nginx.service"
+		for _, part := range strings.Split(line, "/") {
+			if strings.HasSuffix(part, ".service") {
+				return part
+			}
+		}
+	}
+	return ""
+}
+
+// truncateComm truncates a process name to the kernel's 15-character comm limit
+func truncateComm(name string) string {
+	if len(name) > 15 {
+		return name[:15]
+	}
+	return name
+}
+
+// readWindowsApplicationErrors reads recent error events logged by a provider in the Application log
+// The provider comes from a process name, which any user can choose, so it is quoted for the XPath query
+func readWindowsApplicationErrors(provider string, since time.Duration, maxLines int) ([]LogEntry, error) {
+	name, err := xpathLiteral(provider)
+	if err != nil {
+		return nil, fmt.Errorf("cannot query events of %q: %w", provider, err)
+	}
+	query := fmt.Sprintf("*[System[Provider[@Name=%s] and (Level=1 or Level=2)", name)
+	if since > 0 {
+		query += fmt.Sprintf(" and TimeCreated[timediff(@SystemTime) <= %d]", since.Milliseconds())
+	}
+	query += "]]"
+
+	output, err := exec.Command("wevtutil", "qe", "Application", "/rd:true", "/f:text",
+		"/c:"+strconv.Itoa(maxLines), "/q:"+query).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run wevtutil: %w", err)
+	}
+
+	return parseWevtutilText(string(output), "Application"), nil
+}
+
+// xpathLiteral quotes value as an XPath string literal
+// XPath 1.0 has no escape sequences: a literal is delimited by the quote it does not contain, and a value
+// with both kinds of quotes (or control characters) cannot be written as one and is refused
+func xpathLiteral(value string) (string, error) {
+	if strings.IndexFunc(value, unicode.IsControl) >= 0 {
+		return "", errors.New("the name contains control characters")
+	}
+	switch {
+	case !strings.Contains(value, "'"):
+		return "'" + value + "'", nil
+	case !strings.Contains(value, `"`):
+		return `"` + value + `"`, nil
+	}
+	return "", errors.New("the name contains both single and double quotes")
+}
+
+// readDmesg reads the kernel ring buffer using dmesg with ISO timestamps
+func readDmesg(maxLines int) ([]LogEntry, error) {
+	output, err := exec.Command("dmesg", "--time-format", "iso", "--nopager").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run dmesg: %w", err)
+	}
+
+	lines := tailLines(string(output), maxLines)
+	entries := make([]LogEntry, 0, len(lines))
+	for _, line := range lines {
+		// Format: "2024-01-02T15:04:05,123456+00:00 message"
+		fields := strings.SplitN(line, " ", 2)
+		if len(fields) < 2 {
+			continue
+		}
+
+		entries = append(entries, LogEntry{
+			Timestamp: parseLogTime(fields[0]),
+			Source:    "kernel",
+			Message:   strings.TrimSpace(fields[1]),
+		})
+	}
+
+	return entries, nil
+}
+
+// readJournalKernel reads kernel messages from the systemd journal
+func readJournalKernel(maxLines int) ([]LogEntry, error) {
+	output, err := exec.Command("journalctl", "-k", "-o", "short-iso", "--no-pager", "-q",
+		"-n", strconv.Itoa(maxLines)).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run journalctl: %w", err)
+	}
+
+	var entries []LogEntry
+	for _, line := range strings.Split(string(output), "\n") {
+		// Format: "2024-01-02T15:04:05+0000 hostname kernel: message"
+		fields := strings.SplitN(line, " ", 3)
+		if len(fields) < 3 {
+			continue
+		}
+
+		message := fields[2]
+		if index := strings.Index(message, ": "); index >= 0 {
+			message = message[index+2:]
+		}
+
+		entries = append(entries, LogEntry{
+			Timestamp: parseLogTime(fields[0]),
+			Source:    "kernel",
+			Message:   strings.TrimSpace(message),
+		})
+	}
+
+	return entries, nil
+}
+
+// readWindowsSystemLog reads warning and error events from the Windows System event log
+func readWindowsSystemLog(maxLines int) ([]LogEntry, error) {
+	output, err := exec.Command("wevtutil", "qe", "System", "/rd:true", "/f:text",
+		"/c:"+strconv.Itoa(maxLines),
+		"/q:*[System[(Level=1 or Level=2 or Level=3)]]").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run wevtutil: %w", err)
+	}
+
+	return parseWevtutilText(string(output), "System"), nil
+}
+
+// parseWevtutilText parses events printed by "wevtutil qe /f:text" into log entries
+func parseWevtutilText(output, source string) []LogEntry {
+	var entries []LogEntry
+	var current *LogEntry
+	var provider string
+	inDescription := false
+
+	for _, rawLine := range strings.Split(strings.ReplaceAll(output, "\r\n", "\n"), "\n") {
+		line := strings.TrimSpace(rawLine)
+
+		// Each event starts with "Event[N]:"
+		if strings.HasPrefix(line, "Event[") {
+			if current != nil {
+				entries = append(entries, *current)
+			}
+			current = &LogEntry{Source: source}
+			provider = ""
+			inDescription = false
+			continue
+		}
+		if current == nil {
+			continue
+		}
+
+		if inDescription {
+			if line != "" {
+				current.Message = strings.TrimSpace(current.Message + " " + line)
+			}
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+
+		switch strings.TrimSpace(key) {
+		case "Source":
+			provider = value
+		case "Date":
+			current.Timestamp = parseLogTime(value)
+		case "Level":
+			current.Level = value
+		case "Description":
+			inDescription = true
+			// Prefix the provider so patterns such as "disk" and "WHEA" match
+			current.Message = strings.TrimSpace(provider + ": " + value)
+		}
+	}
+
+	if current != nil {
+		entries = append(entries, *current)
+	}
+
+	return entries
+}
+
+// parseLogTime parses the timestamp formats emitted by dmesg, journalctl, and wevtutil
+func parseLogTime(value string) time.Time {
+	layouts := []string{
+		"2006-01-02T15:04:05,000000-07:00", // dmesg --time-format iso
+		"2006-01-02T15:04:05-0700",         // journalctl -o short-iso
+		"2006-01-02T15:04:05-07:00",        // journalctl -o short-iso (newer systemd)
+		"2006-01-02T15:04:05.000",          // wevtutil /f:text
+		time.RFC3339,
+	}
+
+	for _, layout := range layouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed
+		}
+	}
+	return time.Time{}
+}
+
+// tailLines returns the last maxLines non-empty lines of text
+func tailLines(text string, maxLines int) []string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	if maxLines > 0 && len(lines) > maxLines {
+		lines = lines[len(lines)-maxLines:]
+	}
+	return lines
+}
+
+// GetRecentEvents returns a copy of the events tracked so far, newest first
+func (collector *EventMonitorCollector) GetRecentEvents() []SystemEvent {
+	collector.collectMutex.Lock()
+	defer collector.collectMutex.Unlock()
+	return slices.Clone(collector.recentEvents)
+}
@@ -0,0 +1,100 @@
+package eventmonitor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ahmadreza-log/simple-monitor/monitortest"
+)
+
+// TestRecentEventsDuringCollections reads the recent events and changes the configuration while several
+// collections run, the way the menu does; run it with -race
+func TestRecentEventsDuringCollections(t *testing.T) {
+	collector := NewEventMonitorCollector()
+	collector.changeConfig(func(config *EventMonitorConfig) {
+		config.MaxLogLines = 50
+		config.WatchUSBDevices = false
+		config.WatchSessions = false
+		config.AlertOnNewEvents = false
+	})
+	collector.recentEvents = []SystemEvent{{
+		Timestamp: time.Now(),
+		Source:    "kernel",
+		Category:  CategoryOOM,
+		Severity:  "Critical",
+		Message:   "Out of memory: Killed process 4242 (stress)",
+	}}
+	collect := func() error {
+		_, err := collector.CollectEventMonitorData()
+		return err
+	}
+
+	monitortest.DuringCollections(t, collect, func(i int) {
+		events := collector.GetRecentEvents()
+		for j := range events {
+			events[j].IsNew = true
+		}
+		collector.changeConfig(func(config *EventMonitorConfig) {
+			config.RefreshInterval = time.Duration(i) * time.Second
+		})
+	})
+
+	events := collector.GetRecentEvents()
+	if len(events) == 0 {
+		t.Fatal("GetRecentEvents() is empty, want the OOM event")
+	}
+	events[0].Message = "changed"
+	if collector.GetRecentEvents()[0].Message == "changed" {
+		t.Error("GetRecentEvents()[0].Message = changed, want changes to the returned copy kept out of the collector")
+	}
+}
+
+func TestCollectedEventsAreCopies(t *testing.T) {
+	collector := NewEventMonitorCollector()
+	collector.changeConfig(func(config *EventMonitorConfig) {
+		config.MaxLogLines = 50
+		config.WatchUSBDevices = false
+		config.WatchSessions = false
+		config.AlertOnNewEvents = false
+	})
+	collector.recentEvents = []SystemEvent{{
+		Timestamp: time.Now(),
+		Source:    "kernel",
+		Category:  CategoryOOM,
+		Severity:  "Critical",
+		Message:   "Out of memory: Killed process 4242 (stress)",
+		IsNew:     true,
+	}}
+
+	data, err := collector.CollectEventMonitorData()
+	if err != nil {
+		t.Fatalf("CollectEventMonitorData() error = %v", err)
+	}
+	if len(data.Events) == 0 {
+		t.Fatal("Events is empty, want the OOM event")
+	}
+	data.Events[0].Message = "changed"
+	if collector.GetRecentEvents()[0].Message == "changed" {
+		t.Error("changing the collected Events changed the collector's recent events")
+	}
+}
+
+func TestXPathLiteral(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{value: "nginx", want: "'nginx'"},
+		{value: "O'Reilly Sync", want: `"O'Reilly Sync"`},
+		{value: `say "hi"`, want: `'say "hi"'`},
+		{value: `x'] or 1=1 or @Name=["`, wantErr: true},
+		{value: "line\nbreak", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := xpathLiteral(tt.value)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("xpathLiteral(%q) = %q, %v, want %q (error %v)", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
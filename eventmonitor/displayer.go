@@ -0,0 +1,422 @@
+package eventmonitor
+
+import (
+	"fmt"
+	"github.com/ahmadreza-log/simple-monitor/terminal"
+	"strings"
+)
+
+// EventMonitorDisplayer handles the display and formatting of system event data
+// This struct provides methods to format and display kernel and system log events
+type EventMonitorDisplayer struct {
+	// Display configuration
+	ShowColors    bool // Whether to use colored output
+	MaxEvents     int  // Maximum number of events to display
+	MessageLength int  // Maximum displayed message length (when AutoWidth is off)
+	AutoWidth     bool // Whether to fit separators and messages to the terminal width
+
+	// Color codes for different elements
+	ColorReset   string
+	ColorRed     string
+	ColorGreen   string
+	ColorYellow  string
+	ColorBlue    string
+	ColorCyan    string
+	ColorMagenta string
+	ColorWhite   string
+	ColorBold    string
+}
+
+// NewEventMonitorDisplayer creates a new instance of EventMonitorDisplayer
+// with default configuration values
+func NewEventMonitorDisplayer() *EventMonitorDisplayer {
+	return &EventMonitorDisplayer{
+		ShowColors:    true,
+		MaxEvents:     20,
+		MessageLength: 90,
+		AutoWidth:     true,
+		ColorReset:    "\033[0m",
+		ColorRed:      "\033[31m",
+		ColorGreen:    "\033[32m",
+		ColorYellow:   "\033[33m",
+		ColorBlue:     "\033[34m",
+		ColorCyan:     "\033[36m",
+		ColorMagenta:  "\033[35m",
+		ColorWhite:    "\033[37m",
+		ColorBold:     "\033[1m",
+	}
+}
+
+// DisplayEventMonitorData displays recent system events and alerts
+func (displayer *EventMonitorDisplayer) DisplayEventMonitorData(data *EventMonitorData) {
+	// Clear screen and move cursor to top
+	fmt.Print("\033[2J\033[H")
+
+	// Display header
+	displayer.displayHeader(data)
+
+	// Display per-category summary
+	displayer.displayCategorySummary(data)
+
+	// Display alerts for new events
+	if len(data.Alerts) > 0 {
+		displayer.displayAlerts(data)
+	}
+
+	// Display recent events
+	displayer.displayEvents(data)
+
+	// Display USB inventory and connect/disconnect history
+	if data.USBError != "" || len(data.USBDevices) > 0 || len(data.USBEvents) > 0 {
+		displayer.displayUSB(data)
+	}
+
+	// Display logged-in users
+	if data.SessionError != "" || len(data.Sessions) > 0 {
+		displayer.displaySessions(data)
+	}
+
+	// Display footer
+	displayer.displayFooter(data)
+}
+
+// displayHeader displays the system events header
+func (displayer *EventMonitorDisplayer) displayHeader(data *EventMonitorData) {
+	fmt.Println(displayer.colorize("📜 SYSTEM EVENTS", displayer.ColorBold+displayer.ColorCyan))
+	fmt.Println(displayer.rule("="))
+
+	source := data.LogSource
+	if source == "" {
+		source = "Unavailable"
+	}
+	fmt.Printf("%sLog Source: %s%s\n",
+		displayer.colorize("", displayer.ColorBold),
+		displayer.colorize(source, displayer.ColorWhite),
+		displayer.colorize("", displayer.ColorReset))
+
+	if data.SourceError != "" {
+		fmt.Printf("%s⚠️  %s%s\n",
+			displayer.colorize("", displayer.ColorYellow),
+			data.SourceError,
+			displayer.colorize("", displayer.ColorReset))
+		fmt.Println("   Reading the kernel log may require root or membership in the adm/systemd-journal group")
+	}
+
+	fmt.Println(displayer.rule("="))
+}
+
+// displayCategorySummary displays the number of recent events per category
+func (displayer *EventMonitorDisplayer) displayCategorySummary(data *EventMonitorData) {
+	fmt.Println("\n📊 EVENT SUMMARY")
+	fmt.Println(displayer.sectionRule())
+
+	categories := []string{CategoryDiskIO, CategoryOOM, CategoryThermal, CategoryUSB, CategoryHardware}
+	for _, category := range categories {
+		count := data.CategoryCounts[category]
+		color := displayer.getSeverityColor("")
+		if count > 0 {
+			color = displayer.getSeverityColor(displayer.categorySeverity(category))
+		}
+
+		fmt.Printf("%s%-12s %s%d%s\n",
+			displayer.colorize("", displayer.ColorBold),
+			category+":",
+			color,
+			count,
+			displayer.colorize("", displayer.ColorReset))
+	}
+
+	if data.NewEventCount > 0 {
+		fmt.Printf("\n%sNew since last refresh: %s%d%s\n",
+			displayer.colorize("", displayer.ColorBold),
+			displayer.colorize("", displayer.ColorRed),
+			data.NewEventCount,
+			displayer.colorize("", displayer.ColorReset))
+	}
+}
+
+// displayAlerts displays alerts raised for newly observed events
+func (displayer *EventMonitorDisplayer) displayAlerts(data *EventMonitorData) {
+	fmt.Println("\n🚨 NEW EVENT ALERTS")
+	fmt.Println(displayer.sectionRule())
+
+	for _, alert := range data.Alerts {
+		fmt.Printf("%s[%s]%s %s\n",
+			displayer.getSeverityColor(alert.Severity),
+			alert.Severity,
+			displayer.colorize("", displayer.ColorReset),
+			displayer.truncate(alert.AlertMessage, displayer.messageLength()))
+	}
+}
+
+// displayEvents displays the list of recent events
+func (displayer *EventMonitorDisplayer) displayEvents(data *EventMonitorData) {
+	fmt.Println("\n📋 RECENT EVENTS")
+	fmt.Println(displayer.rule("-"))
+
+	if len(data.Events) == 0 {
+		fmt.Printf("%s✅ No hardware events found%s\n",
+			displayer.colorize("", displayer.ColorGreen),
+			displayer.colorize("", displayer.ColorReset))
+		return
+	}
+
+	// Header
+	fmt.Printf("%s%-19s %-11s %-9s %s%s\n",
+		displayer.colorize("", displayer.ColorBold),
+		"Time",
+		"Category",
+		"Severity",
+		"Message",
+		displayer.colorize("", displayer.ColorReset))
+
+	fmt.Println(displayer.rule("-"))
+
+	for i, event := range data.Events {
+		if i >= displayer.MaxEvents {
+			break
+		}
+
+		timestamp := "unknown"
+		if !event.Timestamp.IsZero() {
+			timestamp = event.Timestamp.Local().Format("2006-01-02 15:04:05")
+		}
+
+		marker := " "
+		if event.IsNew {
+			marker = "*"
+		}
+
+		fmt.Printf("%s%-19s %-11s %s%-9s%s %s\n",
+			marker,
+			timestamp,
+			event.Category,
+			displayer.getSeverityColor(event.Severity),
+			event.Severity,
+			displayer.colorize("", displayer.ColorReset),
+			displayer.truncate(event.Message, displayer.messageLength()-42))
+	}
+}
+
+// displayUSB displays connected USB devices and recent connects/disconnects
+func (displayer *EventMonitorDisplayer) displayUSB(data *EventMonitorData) {
+	fmt.Println("\n🔌 USB DEVICES")
+	fmt.Println(displayer.rule("-"))
+
+	if data.USBError != "" {
+		fmt.Printf("%s⚠️  %s%s\n",
+			displayer.colorize("", displayer.ColorYellow),
+			data.USBError,
+			displayer.colorize("", displayer.ColorReset))
+	} else {
+		fmt.Printf("%s%-8s %-10s %-22s %s%s\n",
+			displayer.colorize("", displayer.ColorBold),
+			"Bus:Dev",
+			"ID",
+			"Speed",
+			"Device",
+			displayer.colorize("", displayer.ColorReset))
+		for _, device := range data.USBDevices {
+			fmt.Printf("%-8s %-10s %-22s %s\n",
+				fmt.Sprintf("%03d:%03d", device.Bus, device.Device),
+				device.VendorID+":"+device.ProductID,
+				device.Speed,
+				displayer.truncate(usbDeviceName(device), displayer.messageLength()-42))
+		}
+	}
+
+	if len(data.USBEvents) == 0 {
+		return
+	}
+	fmt.Printf("\n%sConnects / disconnects:%s\n",
+		displayer.colorize("", displayer.ColorBold),
+		displayer.colorize("", displayer.ColorReset))
+	for _, event := range data.USBEvents {
+		marker := " "
+		if event.IsNew {
+			marker = "*"
+		}
+		color := displayer.ColorGreen
+		if event.Action == "Disconnected" {
+			color = displayer.ColorYellow
+		}
+		fmt.Printf("%s%s %s %s %s\n",
+			marker,
+			event.Timestamp.Local().Format("2006-01-02 15:04:05"),
+			displayer.colorize(fmt.Sprintf("%-12s", event.Action), color),
+			event.Device.VendorID+":"+event.Device.ProductID,
+			displayer.truncate(usbDeviceName(event.Device)+" (port "+event.Device.Port+")", displayer.messageLength()-45))
+	}
+}
+
+// displaySessions displays logged-in users with where they connect from and how long they have been idle
+func (displayer *EventMonitorDisplayer) displaySessions(data *EventMonitorData) {
+	fmt.Println("\n👥 LOGIN SESSIONS")
+	fmt.Println(displayer.rule("-"))
+
+	if data.SessionError != "" {
+		fmt.Printf("%s⚠️  %s%s\n",
+			displayer.colorize("", displayer.ColorYellow),
+			data.SessionError,
+			displayer.colorize("", displayer.ColorReset))
+		return
+	}
+
+	fmt.Printf("%s %-16s %-14s %-17s %-6s %-12s %s%s\n",
+		displayer.colorize("", displayer.ColorBold),
+		"User",
+		"Terminal",
+		"Login",
+		"Idle",
+		"State",
+		"From",
+		displayer.colorize("", displayer.ColorReset))
+	for _, session := range data.Sessions {
+		marker := " "
+		if session.IsNew {
+			marker = "*"
+		}
+		from := "local"
+		color := displayer.ColorWhite
+		if session.Remote {
+			from = session.Host
+			color = displayer.ColorYellow
+		}
+		fmt.Printf("%s%-16s %-14s %-17s %-6s %-12s %s\n",
+			marker,
+			displayer.truncate(session.User, 16),
+			displayer.truncate(session.Terminal, 14),
+			session.Started.Local().Format("2006-01-02 15:04"),
+			formatIdle(session.Idle),
+			session.State,
+			displayer.colorize(from, color))
+	}
+}
+
+// usbDeviceName returns the vendor and product names, or a placeholder when the device reports none
+func usbDeviceName(device USBDeviceInfo) string {
+	name := strings.TrimSpace(device.Vendor + " " + device.Product)
+	if name == "" {
+		return "(unnamed device)"
+	}
+	return name
+}
+
+// displayFooter displays the system events footer
+func (displayer *EventMonitorDisplayer) displayFooter(data *EventMonitorData) {
+	fmt.Println(displayer.rule("="))
+	fmt.Printf("%sLast Updated: %s%s%s\n",
+		displayer.colorize("", displayer.ColorBold),
+		displayer.colorize("", displayer.ColorCyan),
+		data.Timestamp.Format("2006-01-02 15:04:05"),
+		displayer.colorize("", displayer.ColorReset))
+
+	fmt.Printf("%sRefresh Rate: %s%.1fs%s\n",
+		displayer.colorize("", displayer.ColorBold),
+		displayer.colorize("", displayer.ColorCyan),
+		data.RefreshInterval.Seconds(),
+		displayer.colorize("", displayer.ColorReset))
+
+	if !displayer.ShowColors || terminal.ColorBlindMode() {
+		fmt.Println(terminal.SeverityLegend())
+	}
+
+	fmt.Println(displayer.rule("="))
+}
+
+// categorySeverity returns the default severity for an event category
+func (displayer *EventMonitorDisplayer) categorySeverity(category string) string {
+	for _, pattern := range eventPatterns {
+		if pattern.category == category {
+			return pattern.severity
+		}
+	}
+	return "Warning"
+}
+
+// truncate shortens text to the given length
+func (displayer *EventMonitorDisplayer) truncate(text string, length int) string {
+	if length < 4 {
+		length = 4
+	}
+	if len(text) > length {
+		return text[:length-3] + "..."
+	}
+	return text
+}
+
+// lineWidth returns the layout width: the terminal width, or 80 columns when AutoWidth is off
+func (displayer *EventMonitorDisplayer) lineWidth() int {
+	if !displayer.AutoWidth {
+		return terminal.DefaultWidth
+	}
+	return terminal.Fit(terminal.Width(), 40, 240)
+}
+
+// rule returns a separator line across the layout width
+func (displayer *EventMonitorDisplayer) rule(char string) string {
+	return strings.Repeat(char, displayer.lineWidth())
+}
+
+// sectionRule returns a section underline, shortened on narrow terminals
+func (displayer *EventMonitorDisplayer) sectionRule() string {
+	return strings.Repeat("-", terminal.Fit(displayer.lineWidth(), 0, 50))
+}
+
+// messageLength returns the line length that event messages are truncated to
+func (displayer *EventMonitorDisplayer) messageLength() int {
+	if !displayer.AutoWidth {
+		return displayer.MessageLength
+	}
+	return displayer.lineWidth() - 1
+}
+
+// colorize applies color to text if colors are enabled
+func (displayer *EventMonitorDisplayer) colorize(text, color string) string {
+	if !displayer.ShowColors {
+		// Severity colors are reduced to their marker
+		return terminal.MarkerOf(color) + text
+	}
+	return terminal.PaletteColor(displayer.severityLevel(color), color) + text + displayer.ColorReset
+}
+
+// severityColor returns a severity color in the active palette, followed by its marker when markers are shown
+// Markers are shown in color-blind mode and whenever colors are off, so severity never depends on color alone
+func (displayer *EventMonitorDisplayer) severityColor(color string) string {
+	level := displayer.severityLevel(color)
+	marker := ""
+	if !displayer.ShowColors || terminal.ColorBlindMode() {
+		marker = terminal.SeverityMarker(level)
+	}
+	if !displayer.ShowColors {
+		return marker
+	}
+	return terminal.PaletteColor(level, color) + marker
+}
+
+// severityLevel returns the severity a displayer color stands for
+func (displayer *EventMonitorDisplayer) severityLevel(color string) terminal.Severity {
+	switch color {
+	case displayer.ColorGreen:
+		return terminal.SeverityOK
+	case displayer.ColorYellow:
+		return terminal.SeverityWarning
+	case displayer.ColorMagenta:
+		return terminal.SeverityHigh
+	case displayer.ColorRed:
+		return terminal.SeverityCritical
+	}
+	return terminal.SeverityNone
+}
+
+// getSeverityColor returns the appropriate color for an event severity
+func (displayer *EventMonitorDisplayer) getSeverityColor(severity string) string {
+	switch severity {
+	case "Critical":
+		return displayer.severityColor(displayer.ColorRed)
+	case "Warning":
+		return displayer.severityColor(displayer.ColorYellow)
+	default:
+		return displayer.severityColor(displayer.ColorGreen)
+	}
+}
@@ -0,0 +1,375 @@
+// Package eventmonitor watches the kernel and system logs for events such as OOM kills, disk errors,
+// USB changes and logins
+// An EventMonitorManager collects once with Collect or in the background with Subscribe
+package eventmonitor
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/ahmadreza-log/simple-monitor/hooks"
+	"github.com/ahmadreza-log/simple-monitor/recording"
+	"github.com/ahmadreza-log/simple-monitor/terminal"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// EventMonitorManager is the main interface for system event monitoring operations
+// This struct coordinates between the collector, displayer, and exporter to provide
+// a complete kernel/system log watcher with live updates
+type EventMonitorManager struct {
+	collector *EventMonitorCollector
+	displayer *EventMonitorDisplayer
+	exporter  *EventMonitorExporter
+
+	// Monitoring state
+	isRunning      bool
+	stopChannel    chan bool
+	refreshTicker  *time.Ticker
+	lastExportTime time.Time
+
+	// Extra line shown under the live display (e.g. key shortcuts)
+	liveHint string
+
+	// Pause and step control (the display stays frozen while paused)
+	paused      atomic.Bool
+	stepChannel chan bool
+
+	// Session recorder (nil unless the live session is being recorded)
+	recorder *recording.Recorder
+}
+
+// NewEventMonitorManager creates a new instance of EventMonitorManager
+// with default collector, displayer, and exporter configurations, then applies the options in order
+func NewEventMonitorManager(options ...Option) *EventMonitorManager {
+	manager := &EventMonitorManager{
+		collector:   NewEventMonitorCollector(),
+		displayer:   NewEventMonitorDisplayer(),
+		exporter:    NewEventMonitorExporter(),
+		isRunning:   false,
+		stopChannel: make(chan bool, 1),
+		stepChannel: make(chan bool, 1),
+	}
+	for _, option := range options {
+		option(manager)
+	}
+	return manager
+}
+
+// StartLiveMonitoring starts live system event monitoring with real-time updates
+// This method runs continuously until stopped by the user
+func (manager *EventMonitorManager) StartLiveMonitoring() error {
+	if manager.isRunning {
+		return fmt.Errorf("system event monitoring is already running")
+	}
+
+	manager.isRunning = true
+	manager.refreshTicker = time.NewTicker(manager.collector.currentConfig().RefreshInterval)
+
+	// Set up signal handling for graceful shutdown
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+
+	fmt.Println("🚀 Starting live system event monitoring...")
+	fmt.Println("Press Ctrl+C to stop monitoring")
+
+	// Start monitoring loop
+	loopDone := make(chan bool)
+	loopExited := make(chan bool)
+	go func() {
+		defer close(loopExited)
+		for {
+			select {
+			case <-manager.refreshTicker.C:
+				if !manager.paused.Load() {
+					manager.updateAndDisplay()
+				}
+			case <-manager.stepChannel:
+				manager.updateAndDisplay()
+			case <-loopDone:
+				return
+			case <-sigChan:
+				manager.StopMonitoring()
+				return
+			}
+		}
+	}()
+
+	// Wait for stop signal
+	// The loop is stopped separately so StopMonitoring can also be called from outside the loop,
+	// and a refresh still in progress finishes before returning
+	<-manager.stopChannel
+	close(loopDone)
+	<-loopExited
+	signal.Stop(sigChan)
+	return nil
+}
+
+// StartSingleSnapshot displays a single snapshot of recent system events
+func (manager *EventMonitorManager) StartSingleSnapshot() error {
+	fmt.Println("📊 Collecting system events...")
+
+	// Collect event data
+	data, err := manager.collector.CollectEventMonitorData()
+	if err != nil {
+		return fmt.Errorf("failed to collect event data: %w", err)
+	}
+
+	// Display event data
+	manager.displayer.DisplayEventMonitorData(data)
+
+	// Always export to file for event monitor
+	filePath, err := manager.exporter.ExportToJSON(data, "eventmonitor")
+	if err != nil {
+		fmt.Printf("⚠️  Warning: Failed to export data: %v\n", err)
+	} else {
+		hooks.Exported("events", filePath)
+		fmt.Printf("\n💾 Event data saved to: %s\n", filePath)
+	}
+
+	return nil
+}
+
+// StopMonitoring stops the live monitoring
+func (manager *EventMonitorManager) StopMonitoring() {
+	if !manager.isRunning {
+		return
+	}
+
+	manager.isRunning = false
+
+	if manager.refreshTicker != nil {
+		manager.refreshTicker.Stop()
+	}
+
+	select {
+	case manager.stopChannel <- true:
+	default:
+	}
+
+	fmt.Println("\n🛑 System event monitoring stopped")
+}
+
+// updateAndDisplay collects new data and updates the display
+func (manager *EventMonitorManager) updateAndDisplay() {
+	// Collect new event data
+	data, err := manager.collector.CollectEventMonitorData()
+	if err != nil {
+		// Display error but continue monitoring
+		fmt.Printf("\n❌ Error collecting event data: %v\n", err)
+		return
+	}
+
+	// Display updated data
+	manager.displayer.DisplayEventMonitorData(data)
+	if manager.liveHint != "" {
+		fmt.Println(manager.liveHint)
+	}
+	if manager.paused.Load() {
+		fmt.Println(terminal.PausedNotice)
+	}
+
+	// Capture the cycle for replay
+	if err := manager.recorder.Record("events", data); err != nil {
+		fmt.Printf("\n⚠️  %v\n", err)
+	}
+
+	// Export data based on export interval
+	manager.exportDataIfNeeded(data)
+}
+
+// exportDataIfNeeded exports event data to file based on export interval
+func (manager *EventMonitorManager) exportDataIfNeeded(data *EventMonitorData) {
+	// Check if export is enabled
+	if !manager.collector.currentConfig().ExportToFile {
+		return
+	}
+
+	// Check if it's time to export based on export interval
+	now := time.Now()
+	if manager.lastExportTime.IsZero() || now.Sub(manager.lastExportTime) >= manager.collector.currentConfig().ExportInterval {
+		manager.exportData(data)
+		manager.lastExportTime = now
+	}
+}
+
+// exportData exports event data to file
+func (manager *EventMonitorManager) exportData(data *EventMonitorData) {
+	export := manager.exporter.ExportToJSON
+	if manager.collector.currentConfig().ExportFormat == "protobuf" {
+		export = manager.exporter.ExportToProtobuf
+	}
+	filePath, err := export(data, "eventmonitor")
+	if err != nil {
+		// Don't display error for every export to avoid cluttering the display
+		return
+	}
+
+	hooks.Exported("events", filePath)
+	fmt.Printf("\n💾 Data exported to: %s\n", filePath)
+}
+
+// GetRecentEvents returns a copy of the events tracked so far, newest first
+func (manager *EventMonitorManager) GetRecentEvents() []SystemEvent {
+	return manager.collector.GetRecentEvents()
+}
+
+// Collect collects the system event data once, without displaying or exporting it
+func (manager *EventMonitorManager) Collect() (*EventMonitorData, error) {
+	return manager.collector.CollectEventMonitorData()
+}
+
+// Subscribe collects every interval in the background and sends the data on the returned channel until the
+// returned function is called, which closes it; a zero interval uses the configured refresh interval
+// Nothing is displayed, exported or recorded. A subscriber that falls behind only gets the newest data,
+// and failed collections are skipped (Collect returns their error)
+func (manager *EventMonitorManager) Subscribe(interval time.Duration) (<-chan *EventMonitorData, func()) {
+	if interval <= 0 {
+		interval = manager.collector.currentConfig().RefreshInterval
+	}
+	updates := make(chan *EventMonitorData, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(updates)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if data, err := manager.Collect(); err == nil {
+				select {
+				case <-updates:
+				default:
+				}
+				updates <- data
+			}
+			select {
+			case <-ticker.C:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var stop sync.Once
+	return updates, func() {
+		stop.Do(func() { close(done) })
+	}
+}
+
+// GetConfig returns the current configuration
+func (manager *EventMonitorManager) GetConfig() *EventMonitorConfig {
+	return manager.collector.GetConfig()
+}
+
+// UpdateConfig updates the event monitor configuration
+func (manager *EventMonitorManager) UpdateConfig(config *EventMonitorConfig) {
+	manager.collector.UpdateConfig(config)
+}
+
+// SetDisplayOptions configures the displayer options
+func (manager *EventMonitorManager) SetDisplayOptions(showColors bool, maxEvents, messageLength int) {
+	manager.displayer.ShowColors = showColors
+	manager.displayer.MaxEvents = maxEvents
+	manager.displayer.MessageLength = messageLength
+}
+
+// SetExportOptions configures the exporter options
+func (manager *EventMonitorManager) SetExportOptions(logsDir string, prettyPrint, createSubDirs bool) {
+	manager.exporter.SetLogsDirectory(logsDir)
+	manager.exporter.SetPrettyPrint(prettyPrint)
+	manager.exporter.SetCreateSubDirs(createSubDirs)
+}
+
+// SetHeaderOptions configures CSV header keys and export metadata rows
+func (manager *EventMonitorManager) SetHeaderOptions(stableKeys, headerMetadata bool) {
+	manager.exporter.SetStableKeys(stableKeys)
+	manager.exporter.SetHeaderMetadata(headerMetadata)
+}
+
+// ExportToFile exports current event data to a file
+func (manager *EventMonitorManager) ExportToFile(format string) error {
+	// Collect current data
+	data, err := manager.collector.CollectEventMonitorData()
+	if err != nil {
+		return fmt.Errorf("failed to collect event data: %w", err)
+	}
+
+	// Export based on format
+	var filePath string
+	switch format {
+	case "json":
+		filePath, err = manager.exporter.ExportToJSON(data, "eventmonitor")
+	case "protobuf":
+		filePath, err = manager.exporter.ExportToProtobuf(data, "eventmonitor")
+	case "csv":
+		filePath, err = manager.exporter.ExportToCSV(data, "eventmonitor")
+	case "txt":
+		filePath, err = manager.exporter.ExportToTXT(data, "eventmonitor")
+	default:
+		return fmt.Errorf("unsupported export format: %s", format)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to export data: %w", err)
+	}
+
+	hooks.Exported("events", filePath)
+	fmt.Printf("💾 Event data exported to: %s\n", filePath)
+	return nil
+}
+
+// GetEventAlerts returns alerts for events observed since the previous collection
+func (manager *EventMonitorManager) GetEventAlerts() ([]EventAlertInfo, error) {
+	data, err := manager.collector.CollectEventMonitorData()
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect event data: %w", err)
+	}
+
+	return data.Alerts, nil
+}
+
+// IsRunning returns whether the event monitor is currently running
+func (manager *EventMonitorManager) IsRunning() bool {
+	return manager.isRunning
+}
+
+// IsEnabled returns whether the monitor is switched on in its configuration
+func (manager *EventMonitorManager) IsEnabled() bool {
+	return manager.collector.currentConfig().Enabled
+}
+
+// SetLiveHint sets a line shown under each live refresh, or clears it when empty
+func (manager *EventMonitorManager) SetLiveHint(hint string) {
+	manager.liveHint = hint
+}
+
+// SetPaused freezes the live display on its last refresh, or resumes refreshing
+// Nothing is collected while paused, so history, exports and alerts wait too
+func (manager *EventMonitorManager) SetPaused(paused bool) {
+	manager.paused.Store(paused)
+}
+
+// Step collects and shows one refresh, for stepping through a paused display
+func (manager *EventMonitorManager) Step() {
+	select {
+	case manager.stepChannel <- true:
+	default:
+	}
+}
+
+// SetRecorder records every live collection cycle to recorder (nil stops recording)
+func (manager *EventMonitorManager) SetRecorder(recorder *recording.Recorder) {
+	manager.recorder = recorder
+}
+
+// DisplayRecordedData shows one recorded collection cycle the way the live display showed it
+func (manager *EventMonitorManager) DisplayRecordedData(content []byte) error {
+	var data EventMonitorData
+	if err := json.Unmarshal(content, &data); err != nil {
+		return fmt.Errorf("failed to decode recorded event data: %w", err)
+	}
+	manager.displayer.DisplayEventMonitorData(&data)
+	return nil
+}
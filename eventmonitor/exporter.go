@@ -0,0 +1,358 @@
+package eventmonitor
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/ahmadreza-log/simple-monitor/exportschema"
+	"github.com/ahmadreza-log/simple-monitor/protoexport"
+	"github.com/ahmadreza-log/simple-monitor/redact"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// EventMonitorExporter handles exporting system event data to various formats
+// This struct provides methods to save system event data to files
+type EventMonitorExporter struct {
+	// Export configuration
+	LogsDirectory string // Base directory for log files
+	DateFormat    string // Date format for file naming
+	CreateSubDirs bool   // Whether to create subdirectories for each module
+	PrettyPrint   bool   // Whether to pretty print JSON output
+
+	exportschema.Format // CSV header style and metadata rows
+}
+
+// NewEventMonitorExporter creates a new instance of EventMonitorExporter
+// with default configuration values
+func NewEventMonitorExporter() *EventMonitorExporter {
+	return &EventMonitorExporter{
+		LogsDirectory: "logs",
+		DateFormat:    "2006-01-02",
+		CreateSubDirs: true,
+		PrettyPrint:   true,
+	}
+}
+
+// ExportToJSON exports system event data to a JSON file
+// The file will be saved with a timestamp-based filename in the appropriate subdirectory
+func (exporter *EventMonitorExporter) ExportToJSON(data *EventMonitorData, moduleName string) (string, error) {
+	if err := redact.Copy(&data); err != nil {
+		return "", err
+	}
+
+	filePath, err := exporter.createFilePath(moduleName, "json")
+	if err != nil {
+		return "", err
+	}
+
+	// Prepare JSON data
+	var jsonData []byte
+	if exporter.PrettyPrint {
+		jsonData, err = json.MarshalIndent(data, "", "  ")
+	} else {
+		jsonData, err = json.Marshal(data)
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JSON data: %w", err)
+	}
+
+	// Write to file
+	if err := os.WriteFile(filePath, jsonData, 0644); err != nil {
+		return "", fmt.Errorf("failed to write JSON file: %w", err)
+	}
+
+	return filePath, nil
+}
+
+// ExportToProtobuf exports system event data to a protobuf file
+// The file holds one EventMonitorData message; it is smaller and faster to parse than JSON for long archives
+func (exporter *EventMonitorExporter) ExportToProtobuf(data *EventMonitorData, moduleName string) (string, error) {
+	if err := redact.Copy(&data); err != nil {
+		return "", err
+	}
+
+	filePath, err := exporter.createFilePath(moduleName, protoexport.Extension)
+	if err != nil {
+		return "", err
+	}
+
+	// Encode as the message `simple-monitor proto` describes
+	content, err := protoexport.Marshal(data)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode protobuf data: %w", err)
+	}
+
+	// Write to file
+	if err := os.WriteFile(filePath, content, 0644); err != nil {
+		return "", fmt.Errorf("failed to write protobuf file: %w", err)
+	}
+
+	return filePath, nil
+}
+
+// ExportToCSV exports system event data to a CSV file
+// Each event is written as one row
+func (exporter *EventMonitorExporter) ExportToCSV(data *EventMonitorData, moduleName string) (string, error) {
+	if err := redact.Copy(&data); err != nil {
+		return "", err
+	}
+
+	filePath, err := exporter.createFilePath(moduleName, "csv")
+	if err != nil {
+		return "", err
+	}
+
+	// Write to file
+	if err := os.WriteFile(filePath, []byte(exporter.generateCSVContent(data)), 0644); err != nil {
+		return "", fmt.Errorf("failed to write CSV file: %w", err)
+	}
+
+	return filePath, nil
+}
+
+// ExportToTXT exports system event data to a text file
+// This creates a human-readable text format
+func (exporter *EventMonitorExporter) ExportToTXT(data *EventMonitorData, moduleName string) (string, error) {
+	if err := redact.Copy(&data); err != nil {
+		return "", err
+	}
+
+	filePath, err := exporter.createFilePath(moduleName, "txt")
+	if err != nil {
+		return "", err
+	}
+
+	// Write to file
+	if err := os.WriteFile(filePath, []byte(exporter.generateTXTContent(data)), 0644); err != nil {
+		return "", fmt.Errorf("failed to write TXT file: %w", err)
+	}
+
+	return filePath, nil
+}
+
+// createFilePath creates the target directory and returns a timestamped file path
+func (exporter *EventMonitorExporter) createFilePath(moduleName, extension string) (string, error) {
+	// Create logs directory if it doesn't exist
+	if err := os.MkdirAll(exporter.LogsDirectory, 0755); err != nil {
+		return "", fmt.Errorf("failed to create logs directory: %w", err)
+	}
+
+	// Create subdirectory for the module if enabled
+	targetDir := exporter.LogsDirectory
+	if exporter.CreateSubDirs {
+		targetDir = filepath.Join(exporter.LogsDirectory, moduleName)
+		if err := os.MkdirAll(targetDir, 0755); err != nil {
+			return "", fmt.Errorf("failed to create module directory: %w", err)
+		}
+	}
+
+	// Generate filename with timestamp
+	timestamp := time.Now().Format("2006-01-02_15-04-05")
+	filename := fmt.Sprintf("%s_%s.%s", moduleName, timestamp, extension)
+	return filepath.Join(targetDir, filename), nil
+}
+
+// generateCSVContent creates CSV content from system event data
+func (exporter *EventMonitorExporter) generateCSVContent(data *EventMonitorData) string {
+	var content string
+
+	// Metadata rows
+	content += exporter.Metadata(exportschema.Event, true)
+
+	// Header
+	content += exporter.CSVHeader("Timestamp,Source,Category,Severity,New,Message", "timestamp,source,category,severity,is_new,message")
+
+	// Event rows
+	for _, event := range data.Events {
+		content += fmt.Sprintf("%s,%s,%s,%s,%t,\"%s\"\n",
+			event.Timestamp.Format("2006-01-02 15:04:05"),
+			event.Source,
+			event.Category,
+			event.Severity,
+			event.IsNew,
+			strings.ReplaceAll(event.Message, "\"", "\"\""))
+	}
+
+	// USB inventory
+	if len(data.USBDevices) > 0 {
+		content += exporter.CSVSection("USB Devices", "usb_devices")
+		content += exporter.CSVHeader("Bus,Device,Vendor ID,Product ID,Vendor,Product,Speed,Port", "bus,device,vendor_id,product_id,vendor,product,speed,port")
+		for _, device := range data.USBDevices {
+			content += fmt.Sprintf("%d,%d,%s,%s,%q,%q,%s,%s\n",
+				device.Bus,
+				device.Device,
+				device.VendorID,
+				device.ProductID,
+				device.Vendor,
+				device.Product,
+				device.Speed,
+				device.Port)
+		}
+	}
+
+	if len(data.USBEvents) > 0 {
+		content += exporter.CSVSection("USB Events", "usb_events")
+		content += exporter.CSVHeader("Timestamp,Action,Vendor ID,Product ID,Product,Port,New", "timestamp,action,device.vendor_id,device.product_id,device.product,device.port,is_new")
+		for _, event := range data.USBEvents {
+			content += fmt.Sprintf("%s,%s,%s,%s,%q,%s,%t\n",
+				event.Timestamp.Format("2006-01-02 15:04:05"),
+				event.Action,
+				event.Device.VendorID,
+				event.Device.ProductID,
+				event.Device.Product,
+				event.Device.Port,
+				event.IsNew)
+		}
+	}
+
+	if len(data.Sessions) > 0 {
+		content += exporter.CSVSection("Login Sessions", "sessions")
+		content += exporter.CSVHeader("User,Terminal,Host,Remote,Started,Idle Seconds,State,New", "user,terminal,host,remote,started,idle,state,is_new")
+		for _, session := range data.Sessions {
+			idle := int64(-1)
+			if session.Idle >= 0 {
+				idle = int64(session.Idle.Seconds())
+			}
+			content += fmt.Sprintf("%q,%s,%s,%t,%s,%d,%s,%t\n",
+				session.User,
+				session.Terminal,
+				session.Host,
+				session.Remote,
+				session.Started.Format("2006-01-02 15:04:05"),
+				idle,
+				session.State,
+				session.IsNew)
+		}
+	}
+
+	return content
+}
+
+// generateTXTContent creates text content from system event data
+func (exporter *EventMonitorExporter) generateTXTContent(data *EventMonitorData) string {
+	var content string
+
+	// Metadata rows
+	content += exporter.Metadata(exportschema.Event, false)
+
+	// Header
+	content += "SYSTEM EVENTS REPORT\n"
+	content += "====================\n\n"
+
+	// Timestamp and source
+	content += fmt.Sprintf("Generated: %s\n", data.Timestamp.Format("2006-01-02 15:04:05"))
+	content += fmt.Sprintf("Log Source: %s\n", data.LogSource)
+	if data.SourceError != "" {
+		content += fmt.Sprintf("Source Error: %s\n", data.SourceError)
+	}
+	content += "\n"
+
+	// Category summary
+	content += "EVENT SUMMARY\n"
+	content += "-------------\n"
+	for _, category := range []string{CategoryDiskIO, CategoryOOM, CategoryThermal, CategoryUSB, CategoryHardware} {
+		content += fmt.Sprintf("%s: %d\n", category, data.CategoryCounts[category])
+	}
+	content += "\n"
+
+	// Events
+	if len(data.Events) > 0 {
+		content += "RECENT EVENTS\n"
+		content += "-------------\n"
+		for _, event := range data.Events {
+			content += fmt.Sprintf("%s\t%-11s\t%-8s\t%s\n",
+				event.Timestamp.Format("2006-01-02 15:04:05"),
+				event.Category,
+				event.Severity,
+				event.Message)
+		}
+		content += "\n"
+	}
+
+	// USB inventory
+	if data.USBError != "" || len(data.USBDevices) > 0 {
+		content += "USB DEVICES\n"
+		content += "-----------\n"
+		if data.USBError != "" {
+			content += fmt.Sprintf("Error: %s\n", data.USBError)
+		}
+		for _, device := range data.USBDevices {
+			content += fmt.Sprintf("%03d:%03d\t%s:%s\t%-22s\t%s\n",
+				device.Bus,
+				device.Device,
+				device.VendorID,
+				device.ProductID,
+				device.Speed,
+				usbDeviceName(device))
+		}
+		content += "\n"
+	}
+
+	if len(data.USBEvents) > 0 {
+		content += "USB CONNECTS / DISCONNECTS\n"
+		content += "--------------------------\n"
+		for _, event := range data.USBEvents {
+			content += fmt.Sprintf("%s\t%-12s\t%s:%s\t%s (port %s)\n",
+				event.Timestamp.Format("2006-01-02 15:04:05"),
+				event.Action,
+				event.Device.VendorID,
+				event.Device.ProductID,
+				usbDeviceName(event.Device),
+				event.Device.Port)
+		}
+		content += "\n"
+	}
+
+	// Login sessions
+	if data.SessionError != "" || len(data.Sessions) > 0 {
+		content += "LOGIN SESSIONS\n"
+		content += "--------------\n"
+		if data.SessionError != "" {
+			content += fmt.Sprintf("Error: %s\n", data.SessionError)
+		}
+		for _, session := range data.Sessions {
+			from := "local"
+			if session.Remote {
+				from = session.Host
+			}
+			content += fmt.Sprintf("%s\t%-16s\t%-14s\tidle %-6s\t%-12s\t%s\n",
+				session.Started.Format("2006-01-02 15:04:05"),
+				session.User,
+				session.Terminal,
+				formatIdle(session.Idle),
+				session.State,
+				from)
+		}
+		content += "\n"
+	}
+
+	return content
+}
+
+// SetLogsDirectory sets the logs directory
+func (exporter *EventMonitorExporter) SetLogsDirectory(dir string) {
+	exporter.LogsDirectory = dir
+}
+
+// SetPrettyPrint sets whether to pretty print JSON output
+func (exporter *EventMonitorExporter) SetPrettyPrint(pretty bool) {
+	exporter.PrettyPrint = pretty
+}
+
+// SetCreateSubDirs sets whether to create subdirectories for each module
+func (exporter *EventMonitorExporter) SetCreateSubDirs(create bool) {
+	exporter.CreateSubDirs = create
+}
+
+// SetStableKeys sets whether CSV headers use stable snake_case keys instead of prose
+func (exporter *EventMonitorExporter) SetStableKeys(stable bool) {
+	exporter.StableKeys = stable
+}
+
+// SetHeaderMetadata sets whether exports start with metadata rows
+func (exporter *EventMonitorExporter) SetHeaderMetadata(metadata bool) {
+	exporter.HeaderMetadata = metadata
+}
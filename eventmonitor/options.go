@@ -0,0 +1,65 @@
+package eventmonitor
+
+import "time"
+
+// Option configures a EventMonitorManager as NewEventMonitorManager creates it
+// Options are applied before the manager is returned, so nothing can be collecting while they change its settings
+type Option func(*EventMonitorManager)
+
+// WithRefreshInterval sets how often live monitoring and Subscribe collect; an interval of zero or less is ignored
+func WithRefreshInterval(interval time.Duration) Option {
+	return func(manager *EventMonitorManager) {
+		if interval > 0 {
+			manager.collector.changeConfig(func(config *EventMonitorConfig) {
+				config.RefreshInterval = interval
+			})
+		}
+	}
+}
+
+// WithExporter replaces the default exporter, e.g. to write exports under another logs directory
+// A nil exporter keeps the default
+func WithExporter(exporter *EventMonitorExporter) Option {
+	return func(manager *EventMonitorManager) {
+		if exporter != nil {
+			manager.exporter = exporter
+		}
+	}
+}
+
+// WithThresholds sets the lowest severity of a new event that raises an alert: Warning or Critical
+// Any other value is ignored
+func WithThresholds(minSeverity string) Option {
+	return func(manager *EventMonitorManager) {
+		if minSeverity == "Warning" || minSeverity == "Critical" {
+			manager.collector.changeConfig(func(config *EventMonitorConfig) {
+				config.AlertMinSeverity = minSeverity
+			})
+		}
+	}
+}
+
+// WithFilters watches only the given categories (CategoryDiskIO, CategoryOOM, CategoryThermal, CategoryUSB,
+// CategoryHardware); with none given every category is watched
+func WithFilters(categories ...string) Option {
+	return func(manager *EventMonitorManager) {
+		watch := func(category string) bool {
+			if len(categories) == 0 {
+				return true
+			}
+			for _, name := range categories {
+				if name == category {
+					return true
+				}
+			}
+			return false
+		}
+		manager.collector.changeConfig(func(config *EventMonitorConfig) {
+			config.WatchDiskIO = watch(CategoryDiskIO)
+			config.WatchOOM = watch(CategoryOOM)
+			config.WatchThermal = watch(CategoryThermal)
+			config.WatchUSB = watch(CategoryUSB)
+			config.WatchHardware = watch(CategoryHardware)
+		})
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !unix && !windows
+
+package eventmonitor
+
+import "errors"
+
+// readSessions is not supported on this platform
+func readSessions() ([]SessionInfo, error) {
+	return nil, errors.New("login sessions are only available on Unix and Windows")
+}
@@ -0,0 +1,79 @@
+//go:build unix
+
+package eventmonitor
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/host"
+	"golang.org/x/sys/unix"
+)
+
+// readSessions reads the logged-in users from utmp, or from who when gopsutil cannot read it
+func readSessions() ([]SessionInfo, error) {
+	now := time.Now()
+	users, err := host.Users()
+	if err != nil {
+		return readWhoSessions(now)
+	}
+
+	var sessions []SessionInfo
+	for _, user := range users {
+		sessions = append(sessions, SessionInfo{
+			User:     user.User,
+			Terminal: user.Terminal,
+			Host:     user.Host,
+			Remote:   isRemoteHost(user.Host),
+			Started:  time.Unix(int64(user.Started), 0),
+			Idle:     terminalIdle(user.Terminal, now),
+			State:    "Active",
+		})
+	}
+	return sessions, nil
+}
+
+// readWhoSessions parses who output ("alice pts/0 2024-05-01 10:11 (10.0.0.5)")
+// Newer distributions without utmp answer who from systemd-logind
+func readWhoSessions(now time.Time) ([]SessionInfo, error) {
+	output, err := exec.Command("who").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list logged-in users: %w", err)
+	}
+
+	var sessions []SessionInfo
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 {
+			continue
+		}
+		session := SessionInfo{
+			User:     fields[0],
+			Terminal: fields[1],
+			Idle:     terminalIdle(fields[1], now),
+			State:    "Active",
+		}
+		session.Started, _ = time.ParseInLocation("2006-01-02 15:04", fields[2]+" "+fields[3], time.Local)
+		if last := fields[len(fields)-1]; strings.HasPrefix(last, "(") && strings.HasSuffix(last, ")") {
+			session.Host = strings.Trim(last, "()")
+			session.Remote = isRemoteHost(session.Host)
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, nil
+}
+
+// terminalIdle returns the time since the terminal was last read from, as who -u reports it (-1 if unknown)
+func terminalIdle(terminal string, now time.Time) time.Duration {
+	if terminal == "" {
+		return -1
+	}
+	var stat unix.Stat_t
+	if err := unix.Stat(filepath.Join("/dev", terminal), &stat); err != nil {
+		return -1
+	}
+	return max(now.Sub(time.Unix(stat.Atim.Unix())), 0)
+}
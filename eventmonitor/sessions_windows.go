@@ -0,0 +1,142 @@
+//go:build windows
+
+package eventmonitor
+
+import (
+	"fmt"
+	"net"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	wtsapi32                        = windows.NewLazySystemDLL("wtsapi32.dll")
+	procWTSQuerySessionInformationW = wtsapi32.NewProc("WTSQuerySessionInformationW")
+)
+
+// WTS_INFO_CLASS values queried per session
+const (
+	wtsClientAddress = 14
+	wtsSessionInfo   = 24
+)
+
+// WTS_CONNECTSTATE_CLASS values
+const (
+	wtsActive       = 0
+	wtsDisconnected = 4
+)
+
+// wtsInfo mirrors the Win32 WTSINFOW structure
+type wtsInfo struct {
+	State                   uint32
+	SessionID               uint32
+	IncomingBytes           uint32
+	OutgoingBytes           uint32
+	IncomingFrames          uint32
+	OutgoingFrames          uint32
+	IncomingCompressedBytes uint32
+	OutgoingCompressedBytes uint32
+	WinStationName          [32]uint16
+	Domain                  [17]uint16
+	UserName                [21]uint16
+	ConnectTime             int64
+	DisconnectTime          int64
+	LastInputTime           int64
+	LogonTime               int64
+	CurrentTime             int64
+}
+
+// wtsClientAddressInfo mirrors the Win32 WTS_CLIENT_ADDRESS structure
+type wtsClientAddressInfo struct {
+	AddressFamily uint32
+	Address       [20]byte
+}
+
+// readSessions enumerates Remote Desktop Services sessions that have a user logged on
+// The console session counts as local; every other session with a client address is remote
+func readSessions() ([]SessionInfo, error) {
+	var list *windows.WTS_SESSION_INFO
+	var count uint32
+	if err := windows.WTSEnumerateSessions(0, 0, 1, &list, &count); err != nil {
+		return nil, fmt.Errorf("failed to enumerate sessions: %w", err)
+	}
+	defer windows.WTSFreeMemory(uintptr(unsafe.Pointer(list)))
+
+	var sessions []SessionInfo
+	for _, entry := range unsafe.Slice(list, count) {
+		var info wtsInfo
+		if !querySession(entry.SessionID, wtsSessionInfo, unsafe.Pointer(&info), unsafe.Sizeof(info)) {
+			continue
+		}
+		user := windows.UTF16ToString(info.UserName[:])
+		if user == "" {
+			continue
+		}
+		if domain := windows.UTF16ToString(info.Domain[:]); domain != "" {
+			user = domain + `\` + user
+		}
+
+		session := SessionInfo{
+			User:     user,
+			Terminal: windows.UTF16PtrToString(entry.WindowStationName),
+			Started:  filetimeToTime(info.LogonTime),
+			Idle:     -1,
+			State:    "Active",
+		}
+		if info.State == wtsDisconnected {
+			session.State = "Disconnected"
+		} else if info.State != wtsActive {
+			session.State = "Connected"
+		}
+		if info.LastInputTime != 0 && info.CurrentTime != 0 {
+			session.Idle = max(time.Duration(info.CurrentTime-info.LastInputTime)*100, 0)
+		}
+
+		var address wtsClientAddressInfo
+		if querySession(entry.SessionID, wtsClientAddress, unsafe.Pointer(&address), unsafe.Sizeof(address)) {
+			session.Host = clientAddress(address)
+			session.Remote = session.Host != ""
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, nil
+}
+
+// querySession copies one WTSQuerySessionInformation result into target
+func querySession(sessionID uint32, infoClass uintptr, target unsafe.Pointer, size uintptr) bool {
+	var buffer *byte
+	var length uint32
+	result, _, _ := procWTSQuerySessionInformationW.Call(0, uintptr(sessionID), infoClass,
+		uintptr(unsafe.Pointer(&buffer)), uintptr(unsafe.Pointer(&length)))
+	if result == 0 || buffer == nil {
+		return false
+	}
+	defer windows.WTSFreeMemory(uintptr(unsafe.Pointer(buffer)))
+	if uintptr(length) < size {
+		return false
+	}
+	copy(unsafe.Slice((*byte)(target), size), unsafe.Slice(buffer, size))
+	return true
+}
+
+// clientAddress formats the client IP of a remote session; the console and unknown families return ""
+func clientAddress(address wtsClientAddressInfo) string {
+	switch address.AddressFamily {
+	case windows.AF_INET:
+		return net.IP(address.Address[2:6]).String()
+	case windows.AF_INET6:
+		return net.IP(address.Address[2:18]).String()
+	}
+	return ""
+}
+
+// filetimeToTime converts a FILETIME stored as a 64-bit integer
+func filetimeToTime(value int64) time.Time {
+	if value == 0 {
+		return time.Time{}
+	}
+	filetime := windows.Filetime{LowDateTime: uint32(value), HighDateTime: uint32(value >> 32)}
+	return time.Unix(0, filetime.Nanoseconds())
+}
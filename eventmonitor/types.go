@@ -0,0 +1,136 @@
+package eventmonitor
+
+import "time"
+
+// Event categories recognized in the kernel log / system event log
+const (
+	CategoryDiskIO   = "Disk I/O"   // Disk, filesystem, and controller I/O errors
+	CategoryOOM      = "OOM Killer" // Out-of-memory killer invocations
+	CategoryThermal  = "Thermal"    // Thermal throttling and over-temperature events
+	CategoryUSB      = "USB Reset"  // USB resets, disconnects, and enumeration errors
+	CategoryHardware = "Hardware"   // Machine check, EDAC, PCIe, and WHEA hardware errors
+)
+
+// SystemEvent represents a single hardware-related event read from the kernel or system log
+type SystemEvent struct {
+	Timestamp time.Time `json:"timestamp"` // When the event was logged
+	Source    string    `json:"source"`    // Log the event came from (kernel, System)
+	Category  string    `json:"category"`  // Event category (Disk I/O, OOM Killer, Thermal, USB Reset, Hardware)
+	Severity  string    `json:"severity"`  // Event severity (Warning, Critical)
+	Message   string    `json:"message"`   // Log message text
+	IsNew     bool      `json:"is_new"`    // Whether the event appeared since the previous collection
+}
+
+// EventAlertInfo represents an alert raised for a newly observed system event
+type EventAlertInfo struct {
+	Category     string    `json:"category"`      // Category of the triggering event
+	AlertMessage string    `json:"alert_message"` // Alert message
+	Severity     string    `json:"severity"`      // Alert severity (Warning, Critical)
+	Timestamp    time.Time `json:"timestamp"`     // When the triggering event was logged
+}
+
+// USBDeviceInfo represents a connected USB device
+type USBDeviceInfo struct {
+	Port      string `json:"port"`       // Port path (e.g. 1-1.2) or Windows instance ID
+	VendorID  string `json:"vendor_id"`  // Vendor ID in hex (e.g. 046d)
+	ProductID string `json:"product_id"` // Product ID in hex (e.g. c52b)
+	Vendor    string `json:"vendor"`     // Manufacturer name reported by the device
+	Product   string `json:"product"`    // Product name reported by the device
+	Bus       int    `json:"bus"`        // USB bus number (0 when unknown)
+	Device    int    `json:"device"`     // Device number on the bus, which changes on every reconnect
+	Speed     string `json:"speed"`      // Negotiated speed (e.g. 480 Mbps (High)), empty when unknown
+}
+
+// USBEventInfo represents a USB device connecting or disconnecting between two refreshes
+type USBEventInfo struct {
+	Timestamp time.Time     `json:"timestamp"` // Refresh at which the change was noticed
+	Action    string        `json:"action"`    // Connected or Disconnected
+	Device    USBDeviceInfo `json:"device"`    // The device that changed
+	IsNew     bool          `json:"is_new"`    // Whether the change happened since the previous collection
+}
+
+// SessionInfo represents a logged-in user session
+type SessionInfo struct {
+	User     string        `json:"user"`     // Logged-in user (DOMAIN\\user on Windows)
+	Terminal string        `json:"terminal"` // Terminal or window station (e.g. pts/0, console, RDP-Tcp#3)
+	Host     string        `json:"host"`     // Remote host or IP the session comes from, empty for local sessions
+	Remote   bool          `json:"remote"`   // Whether the session comes from another machine
+	Started  time.Time     `json:"started"`  // When the user logged in
+	Idle     time.Duration `json:"idle"`     // Time since the last input on the session (-1 if unknown)
+	State    string        `json:"state"`    // Active, Connected or Disconnected (Windows); always Active on Unix
+	IsNew    bool          `json:"is_new"`   // Whether the session appeared since the previous collection
+}
+
+// EventMonitorData represents comprehensive system event monitoring data
+type EventMonitorData struct {
+	// Event log source
+	LogSource   string `json:"log_source"`             // Tool the events were read from (dmesg, journalctl, wevtutil)
+	SourceError string `json:"source_error,omitempty"` // Reason the log could not be read, if any
+
+	// Recent events
+	Events         []SystemEvent  `json:"events"`          // Recent hardware events, newest first
+	CategoryCounts map[string]int `json:"category_counts"` // Number of recent events per category
+	NewEventCount  int            `json:"new_event_count"` // Number of events seen for the first time
+
+	// Alerts triggered by new events
+	Alerts []EventAlertInfo `json:"alerts"` // Alerts for newly observed events
+
+	// USB inventory and connect/disconnect history
+	USBDevices []USBDeviceInfo `json:"usb_devices"`         // Connected USB devices
+	USBEvents  []USBEventInfo  `json:"usb_events"`          // Recent connects and disconnects, newest first
+	USBError   string          `json:"usb_error,omitempty"` // Reason the USB devices could not be listed, if any
+
+	// Login sessions
+	Sessions     []SessionInfo `json:"sessions"`                // Logged-in user sessions, oldest first
+	SessionError string        `json:"session_error,omitempty"` // Reason the sessions could not be listed, if any
+
+	// Monitoring configuration
+	RefreshInterval time.Duration `json:"refresh_interval"` // How often data is refreshed
+	IsMonitoring    bool          `json:"is_monitoring"`    // Whether monitoring is active
+
+	// Timestamps
+	Timestamp time.Time `json:"timestamp"` // When this data was collected
+}
+
+// EventMonitorConfig represents configuration options for system event monitoring
+type EventMonitorConfig struct {
+	Enabled bool `json:"enabled"` // Whether the monitor runs; a disabled monitor is left out of every view, snapshot and alert
+
+	// Monitoring settings
+	RefreshInterval time.Duration `json:"refresh_interval"` // How often to refresh data
+	MaxEvents       int           `json:"max_events"`       // Maximum number of events to keep and display
+	MaxLogLines     int           `json:"max_log_lines"`    // Maximum number of log lines to read per collection
+	LookbackWindow  time.Duration `json:"lookback_window"`  // Ignore events older than this (0 for no limit)
+
+	// Category filters
+	WatchDiskIO   bool `json:"watch_disk_io"`  // Whether to report disk I/O errors
+	WatchOOM      bool `json:"watch_oom"`      // Whether to report OOM killer invocations
+	WatchThermal  bool `json:"watch_thermal"`  // Whether to report thermal events
+	WatchUSB      bool `json:"watch_usb"`      // Whether to report USB resets
+	WatchHardware bool `json:"watch_hardware"` // Whether to report other hardware errors
+
+	// USB inventory settings
+	WatchUSBDevices bool `json:"watch_usb_devices"` // Whether to list USB devices and track connects/disconnects
+	MaxUSBEvents    int  `json:"max_usb_events"`    // Maximum number of connect/disconnect events to keep
+
+	// Login session settings
+	WatchSessions      bool `json:"watch_sessions"`        // Whether to list logged-in users
+	AlertOnRemoteLogin bool `json:"alert_on_remote_login"` // Whether a new remote login triggers an alert
+
+	// Alert settings
+	AlertOnNewEvents bool   `json:"alert_on_new_events"` // Whether new events trigger alerts
+	AlertMinSeverity string `json:"alert_min_severity"`  // Minimum severity that triggers an alert (Warning, Critical)
+
+	// Export settings
+	ExportToFile   bool          `json:"export_to_file"`  // Whether to export data to file
+	ExportInterval time.Duration `json:"export_interval"` // How often to export data
+	ExportFormat   string        `json:"export_format"`   // Export format (json, csv, txt, protobuf)
+}
+
+// LogEntry represents a single raw line read from the kernel or system event log
+type LogEntry struct {
+	Timestamp time.Time `json:"timestamp"` // When the line was logged (zero if unknown)
+	Source    string    `json:"source"`    // Log the line came from (kernel, System)
+	Level     string    `json:"level"`     // Log level reported by the source, if any
+	Message   string    `json:"message"`   // Log message text
+}
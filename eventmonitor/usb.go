@@ -0,0 +1,214 @@
+package eventmonitor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// usbDevicesDir is where Linux lists USB devices, one directory per device and interface
+var usbDevicesDir = "/sys/bus/usb/devices"
+
+// usbWindowsID extracts the vendor and product IDs from a Windows USB instance ID (USB\VID_046D&PID_C52B\...)
+var usbWindowsID = regexp.MustCompile(`(?i)VID_([0-9A-F]{4})&PID_([0-9A-F]{4})`)
+
+// collectUSB reads the connected USB devices and records connect/disconnect events since the previous collection
+// Devices already connected on the first collection are not reported as connected
+func (collector *EventMonitorCollector) collectUSB(data *EventMonitorData) {
+	devices, err := ReadUSBDevices()
+	if err != nil {
+		data.USBError = err.Error()
+		data.USBEvents = collector.usbEvents
+		return
+	}
+	data.USBDevices = devices
+
+	// Clear new flags from the previous collection
+	for i := range collector.usbEvents {
+		collector.usbEvents[i].IsNew = false
+	}
+
+	current := make(map[string]USBDeviceInfo, len(devices))
+	for _, device := range devices {
+		current[device.key()] = device
+		if _, known := collector.usbDevices[device.key()]; !known && collector.usbDevices != nil {
+			collector.usbEvents = append(collector.usbEvents, USBEventInfo{
+				Timestamp: data.Timestamp,
+				Action:    "Connected",
+				Device:    device,
+				IsNew:     true,
+			})
+		}
+	}
+	for key, device := range collector.usbDevices {
+		if _, present := current[key]; !present {
+			collector.usbEvents = append(collector.usbEvents, USBEventInfo{
+				Timestamp: data.Timestamp,
+				Action:    "Disconnected",
+				Device:    device,
+				IsNew:     true,
+			})
+		}
+	}
+	collector.usbDevices = current
+
+	// Newest first, limited to MaxUSBEvents
+	sort.SliceStable(collector.usbEvents, func(i, j int) bool {
+		return collector.usbEvents[i].Timestamp.After(collector.usbEvents[j].Timestamp)
+	})
+	if len(collector.usbEvents) > collector.config.MaxUSBEvents {
+		collector.usbEvents = collector.usbEvents[:collector.config.MaxUSBEvents]
+	}
+	data.USBEvents = collector.usbEvents
+}
+
+// key identifies a device across refreshes
+// The device number changes whenever a device re-enumerates, so a quick drop-out between two refreshes still shows up
+func (device USBDeviceInfo) key() string {
+	return fmt.Sprintf("%s|%s:%s|%d", device.Port, device.VendorID, device.ProductID, device.Device)
+}
+
+// ReadUSBDevices lists the connected USB devices (hubs included, host controllers left out)
+// Linux reads sysfs; Windows queries the Plug and Play devices through PowerShell
+func ReadUSBDevices() ([]USBDeviceInfo, error) {
+	var devices []USBDeviceInfo
+	var err error
+	switch runtime.GOOS {
+	case "linux":
+		devices, err = readSysfsUSBDevices(usbDevicesDir)
+	case "windows":
+		devices, err = readWindowsUSBDevices()
+	default:
+		return nil, errors.New("USB device inventory is only available on Linux and Windows")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(devices, func(i, j int) bool {
+		if devices[i].Bus != devices[j].Bus {
+			return devices[i].Bus < devices[j].Bus
+		}
+		return devices[i].Port < devices[j].Port
+	})
+	return devices, nil
+}
+
+// readSysfsUSBDevices reads one device per directory such as 1-1.2; root hubs (usb1) and interfaces (1-1.2:1.0) are skipped
+func readSysfsUSBDevices(dir string) ([]USBDeviceInfo, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		// No USB controller (e.g. some virtual machines and containers)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+
+	var devices []USBDeviceInfo
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, "usb") || strings.Contains(name, ":") {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		device := USBDeviceInfo{
+			Port:      name,
+			VendorID:  readSysfsValue(path, "idVendor"),
+			ProductID: readSysfsValue(path, "idProduct"),
+			Vendor:    readSysfsValue(path, "manufacturer"),
+			Product:   readSysfsValue(path, "product"),
+			Speed:     usbSpeedName(readSysfsValue(path, "speed")),
+		}
+		if device.VendorID == "" {
+			continue
+		}
+		device.Bus, _ = strconv.Atoi(readSysfsValue(path, "busnum"))
+		device.Device, _ = strconv.Atoi(readSysfsValue(path, "devnum"))
+		devices = append(devices, device)
+	}
+	return devices, nil
+}
+
+// readSysfsValue reads one attribute file, returning "" when it is missing
+func readSysfsValue(dir, name string) string {
+	content, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(content))
+}
+
+// usbSpeedName turns the sysfs speed in Mbit/s into the USB speed name
+func usbSpeedName(speed string) string {
+	switch speed {
+	case "":
+		return ""
+	case "1.5":
+		return "1.5 Mbps (Low)"
+	case "12":
+		return "12 Mbps (Full)"
+	case "480":
+		return "480 Mbps (High)"
+	case "5000":
+		return "5 Gbps (SuperSpeed)"
+	case "10000":
+		return "10 Gbps (SuperSpeed+)"
+	case "20000":
+		return "20 Gbps (SuperSpeed+ 2x2)"
+	default:
+		return speed + " Mbps"
+	}
+}
+
+// readWindowsUSBDevices lists present USB devices from Win32_PnPEntity
+// Windows does not report bus numbers or speeds there, so only IDs and names are filled in
+func readWindowsUSBDevices() ([]USBDeviceInfo, error) {
+	script := `Get-CimInstance Win32_PnPEntity | Where-Object { $_.PNPDeviceID -like 'USB\VID_*' } | ` +
+		`Select-Object PNPDeviceID,Name,Manufacturer | ConvertTo-Json -Compress`
+	output, err := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", script).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query USB devices: %w", err)
+	}
+
+	var entries []struct {
+		PNPDeviceID  string
+		Name         string
+		Manufacturer string
+	}
+	text := strings.TrimSpace(string(output))
+	if text == "" {
+		return nil, nil
+	}
+	// A single device is returned as an object rather than an array
+	if strings.HasPrefix(text, "{") {
+		text = "[" + text + "]"
+	}
+	if err := json.Unmarshal([]byte(text), &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse USB devices: %w", err)
+	}
+
+	var devices []USBDeviceInfo
+	for _, entry := range entries {
+		match := usbWindowsID.FindStringSubmatch(entry.PNPDeviceID)
+		if match == nil {
+			continue
+		}
+		devices = append(devices, USBDeviceInfo{
+			Port:      entry.PNPDeviceID,
+			VendorID:  strings.ToLower(match[1]),
+			ProductID: strings.ToLower(match[2]),
+			Vendor:    entry.Manufacturer,
+			Product:   entry.Name,
+		})
+	}
+	return devices, nil
+}
@@ -0,0 +1,79 @@
+// Package exportschema writes the header rows shared by the monitors' CSV and TXT exports:
+// column headers as prose or as stable keys, and the optional metadata rows naming the export schema
+package exportschema
+
+import (
+	"strconv"
+	"time"
+)
+
+// Schema identifies the export layout of one module in metadata rows
+type Schema struct {
+	Name    string // Module name, e.g. "diskmonitor"
+	Version int    // Bumped whenever a stable CSV key or section of the module is renamed or removed
+}
+
+// Schemas of the modules whose exports can start with metadata rows
+var (
+	Memory  = Schema{Name: "memorymonitor", Version: 1}
+	Disk    = Schema{Name: "diskmonitor", Version: 1}
+	Network = Schema{Name: "networkmonitor", Version: 1}
+	Process = Schema{Name: "processmonitor", Version: 1}
+	Event   = Schema{Name: "eventmonitor", Version: 1}
+)
+
+// Format is the header style of a module's exports; exporters embed it
+type Format struct {
+	StableKeys     bool // Whether CSV headers use snake_case keys matching the JSON tags
+	HeaderMetadata bool // Whether exports start with metadata rows (schema, version, header style)
+}
+
+// CSVHeader returns a CSV header row, either as prose or as stable keys
+// Stable keys are the JSON tags of the exported fields; nested fields are joined with a dot
+func (format Format) CSVHeader(prose, keys string) string {
+	if format.StableKeys {
+		return keys + "\n"
+	}
+	return prose + "\n"
+}
+
+// CSVSection returns the title row that starts a CSV section
+func (format Format) CSVSection(title, key string) string {
+	if format.StableKeys {
+		return "\n" + key + "\n"
+	}
+	return "\n" + title + "\n"
+}
+
+// Metadata returns the metadata rows written before the export content of schema
+// CSV rows look like "#key,value" and TXT rows like "# key: value", so parsers can skip them
+func (format Format) Metadata(schema Schema, csv bool) string {
+	if !format.HeaderMetadata {
+		return ""
+	}
+
+	headerStyle := "prose"
+	if format.StableKeys {
+		headerStyle = "keys"
+	}
+	rows := [][2]string{
+		{"schema", "github.com/ahmadreza-log/simple-monitor/" + schema.Name},
+		{"schema_version", strconv.Itoa(schema.Version)},
+		{"header_style", headerStyle},
+		{"generated_at", time.Now().Format(time.RFC3339)},
+		{"timestamp_format", "2006-01-02 15:04:05"},
+	}
+
+	var content string
+	for _, row := range rows {
+		if csv {
+			content += "#" + row[0] + "," + row[1] + "\n"
+		} else {
+			content += "# " + row[0] + ": " + row[1] + "\n"
+		}
+	}
+	if !csv {
+		content += "\n"
+	}
+	return content
+}
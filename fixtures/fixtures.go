@@ -0,0 +1,163 @@
+// Package fixtures captures monitor snapshots as test fixtures and renders their golden display output
+package fixtures
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/ahmadreza-log/simple-monitor/redact"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+)
+
+// Run captures a snapshot of each monitor into <root>/<dir>/testdata/snapshot.json, then renders
+// every fixture and writes the display output next to it as display.golden
+// Fixtures go through redaction like exports do, so usernames, hostnames and IPs of the machine they
+// were captured on are not committed. With GoldenOnly, only the golden files are re-rendered, which
+// is how display changes are reviewed: the golden diff shows exactly what the change does to the screen
+func Run(monitors []Monitor, options Options) []Result {
+	results := make([]Result, 0, len(monitors))
+	for _, monitor := range monitors {
+		results = append(results, capture(monitor, options))
+	}
+	return results
+}
+
+// Failed reports whether any monitor was skipped
+func Failed(results []Result) bool {
+	for _, result := range results {
+		if result.Err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// capture writes the fixture and golden file of one monitor
+func capture(monitor Monitor, options Options) Result {
+	result := Result{Name: monitor.Name}
+	dir := filepath.Join(options.Root, monitor.Dir)
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		result.Err = fmt.Errorf("package directory %s not found (run from the repository root or pass it)", dir)
+		return result
+	}
+	testdata := filepath.Join(dir, TestdataDir)
+	fixture := filepath.Join(testdata, Snapshot)
+
+	if !options.GoldenOnly {
+		data, err := monitor.Collect()
+		if err != nil {
+			result.Err = fmt.Errorf("failed to collect data: %w", err)
+			return result
+		}
+		if err := writeFixture(fixture, data); err != nil {
+			result.Err = err
+			return result
+		}
+		result.Fixture = fixture
+	}
+
+	content, err := os.ReadFile(fixture)
+	if err != nil {
+		result.Err = fmt.Errorf("failed to read fixture: %w", err)
+		return result
+	}
+	output, err := captureStdout(func() error {
+		return monitor.Render(content)
+	})
+	if err != nil {
+		result.Err = fmt.Errorf("failed to render %s: %w", fixture, err)
+		return result
+	}
+
+	golden := filepath.Join(testdata, Golden)
+	if err := os.WriteFile(golden, output, 0644); err != nil {
+		result.Err = fmt.Errorf("failed to write golden file: %w", err)
+		return result
+	}
+	result.Golden = golden
+	return result
+}
+
+// Compare renders the fixture under dir/testdata and reports where the output differs from the golden file
+// It is how each monitor's tests check that its display still matches what was reviewed; render must use the
+// same options as the fixtures command (no colors, 80 columns, times in UTC)
+func Compare(dir string, render func(content []byte) error) error {
+	testdata := filepath.Join(dir, TestdataDir)
+	content, err := os.ReadFile(filepath.Join(testdata, Snapshot))
+	if err != nil {
+		return fmt.Errorf("failed to read fixture: %w", err)
+	}
+	want, err := os.ReadFile(filepath.Join(testdata, Golden))
+	if err != nil {
+		return fmt.Errorf("failed to read golden file: %w", err)
+	}
+	got, err := captureStdout(func() error {
+		return render(content)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render fixture: %w", err)
+	}
+	if bytes.Equal(got, want) {
+		return nil
+	}
+
+	gotLines, wantLines := bytes.Split(got, []byte("\n")), bytes.Split(want, []byte("\n"))
+	for i := 0; i < len(gotLines) && i < len(wantLines); i++ {
+		if !bytes.Equal(gotLines[i], wantLines[i]) {
+			return fmt.Errorf("output differs from %s at line %d:\n got: %q\nwant: %q (run `go run . fixtures --golden` if the change is intended)",
+				Golden, i+1, gotLines[i], wantLines[i])
+		}
+	}
+	return fmt.Errorf("output has %d lines, %s has %d (run `go run . fixtures --golden` if the change is intended)",
+		len(gotLines), Golden, len(wantLines))
+}
+
+// writeFixture writes a redacted copy of data as indented JSON
+func writeFixture(path string, data interface{}) error {
+	// redact.Copy replaces the value a pointer points to, so data is copied into a new variable of its own type
+	copied := reflect.New(reflect.TypeOf(data))
+	copied.Elem().Set(reflect.ValueOf(data))
+	if err := redact.Copy(copied.Interface()); err != nil {
+		return err
+	}
+
+	content, err := json.MarshalIndent(copied.Elem().Interface(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal fixture: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create testdata directory: %w", err)
+	}
+	if err := os.WriteFile(path, append(content, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write fixture: %w", err)
+	}
+	return nil
+}
+
+// captureStdout runs render with standard output redirected and returns what it printed
+// Displayers print straight to os.Stdout, so a pipe stands in for it, as in ASCII mode
+func captureStdout(render func() error) ([]byte, error) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to capture output: %w", err)
+	}
+
+	var output bytes.Buffer
+	done := make(chan struct{})
+	go func() {
+		io.Copy(&output, reader)
+		reader.Close()
+		close(done)
+	}()
+
+	stdout := os.Stdout
+	os.Stdout = writer
+	err = render()
+	os.Stdout = stdout
+	writer.Close()
+	<-done
+	return output.Bytes(), err
+}
@@ -0,0 +1,32 @@
+package fixtures
+
+// Snapshot is the fixture file written for each monitor
+const Snapshot = "snapshot.json"
+
+// Golden is the file holding a fixture's rendered display
+const Golden = "display.golden"
+
+// TestdataDir is the directory under a monitor's package that holds its fixture and golden file
+const TestdataDir = "testdata"
+
+// Monitor is one monitor to capture
+type Monitor struct {
+	Name    string                      // Monitor name shown in the report
+	Dir     string                      // Package directory, relative to the repository root
+	Collect func() (interface{}, error) // Performs one full collection
+	Render  func(content []byte) error  // Decodes a fixture and displays it; the output becomes the golden file
+}
+
+// Options controls what Run writes
+type Options struct {
+	Root       string // Repository root the package directories are under
+	GoldenOnly bool   // Re-render golden files from the existing fixtures instead of capturing new ones
+}
+
+// Result describes the files written for one monitor
+type Result struct {
+	Name    string // Monitor name
+	Fixture string // Path of the snapshot fixture; empty when it was not written
+	Golden  string // Path of the golden display output; empty when it was not written
+	Err     error  // Why the monitor was skipped
+}
@@ -0,0 +1,67 @@
+package healthscore
+
+import (
+	"github.com/ahmadreza-log/simple-monitor/cpumonitor"
+	"github.com/ahmadreza-log/simple-monitor/diskmonitor"
+	"github.com/ahmadreza-log/simple-monitor/memorymonitor"
+	"github.com/ahmadreza-log/simple-monitor/networkmonitor"
+	"github.com/ahmadreza-log/simple-monitor/processmonitor"
+)
+
+// cpuAlerts lists the raised CPU alerts
+func cpuAlerts(data *cpumonitor.CPUMonitorData) []monitorAlert {
+	return raised(
+		check{reason: "usage", status: data.UsageStatus},
+		check{reason: "temperature", status: data.TemperatureStatus},
+		check{reason: "fork rate", status: data.ForkRateStatus},
+	)
+}
+
+// memoryAlerts lists the raised memory alerts
+func memoryAlerts(data *memorymonitor.MemoryMonitorData) []monitorAlert {
+	return raised(
+		check{reason: "swap", status: data.SwapInfo.SwapStatus},
+		check{reason: "commit charge", status: data.CommitInfo.CommitStatus},
+		check{reason: "low memory", flag: data.LowMemoryWarning},
+		check{reason: "possible memory leak", flag: data.MemoryLeakAlert},
+	)
+}
+
+// diskAlerts lists the raised disk alerts
+func diskAlerts(data *diskmonitor.DiskMonitorData) []monitorAlert {
+	return raised(
+		check{reason: "low disk space", flag: data.LowSpaceWarning},
+		check{reason: "high drive temperature", flag: data.HighTempWarning},
+		check{reason: "drive health", flag: data.HealthWarning},
+		check{reason: "I/O bottleneck", flag: data.IOBottleneck},
+		check{reason: "filesystem remounted read-only", flag: data.ReadOnlyWarning},
+	)
+}
+
+// networkAlerts lists the raised network alerts
+func networkAlerts(data *networkmonitor.NetworkMonitorData) []monitorAlert {
+	return raised(
+		check{reason: "high latency", flag: data.HighLatencyWarning},
+		check{reason: "packet loss", flag: data.PacketLossWarning},
+		check{reason: "bandwidth", flag: data.BandwidthWarning},
+		check{reason: "connections", flag: data.ConnectionWarning},
+		check{reason: "VPN tunnel", flag: data.VPNWarning},
+		check{reason: "traffic bypassing the VPN", flag: data.VPNBypassWarning},
+		check{reason: "captive portal", flag: data.CaptivePortalWarning},
+		check{reason: "gateway unreachable", flag: data.GatewayLossWarning},
+		check{reason: "gateway MAC changed", flag: data.GatewayMACWarning},
+	)
+}
+
+// processAlerts lists the raised process alerts
+func processAlerts(data *processmonitor.ProcessMonitorData) []monitorAlert {
+	return raised(
+		check{reason: "high CPU process", flag: data.HighCPUWarning},
+		check{reason: "high memory process", flag: data.HighMemoryWarning},
+		check{reason: "high I/O process", flag: data.HighIOWarning},
+		check{reason: "zombie processes", flag: data.ZombieWarning},
+		check{reason: "thread count", flag: data.ThreadWarning},
+		check{reason: "respawn loop", flag: data.RespawnWarning},
+		check{reason: "watched process down", flag: data.WatchdogWarning},
+	)
+}
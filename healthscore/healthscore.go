@@ -0,0 +1,186 @@
+// Package healthscore combines the alerts of every monitor into one 0-100 health score
+package healthscore
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ahmadreza-log/simple-monitor/cpumonitor"
+	"github.com/ahmadreza-log/simple-monitor/diskmonitor"
+	"github.com/ahmadreza-log/simple-monitor/memorymonitor"
+	"github.com/ahmadreza-log/simple-monitor/networkmonitor"
+	"github.com/ahmadreza-log/simple-monitor/processmonitor"
+)
+
+// current is shared by the whole program; every monitor counts equally until configured otherwise
+var current = settings{config: DefaultConfig()}
+
+// DefaultConfig returns equal weights and penalties that keep a single warning above the warning score
+func DefaultConfig() Config {
+	return Config{
+		CPUWeight:       1,
+		MemoryWeight:    1,
+		DiskWeight:      1,
+		NetworkWeight:   1,
+		ProcessWeight:   1,
+		WarningPenalty:  15,
+		CriticalPenalty: 50,
+		AlertPenalty:    5,
+		WarningScore:    80,
+		CriticalScore:   50,
+	}
+}
+
+// SetConfig replaces the score settings
+func SetConfig(config Config) error {
+	weights := []float64{config.CPUWeight, config.MemoryWeight, config.DiskWeight, config.NetworkWeight, config.ProcessWeight}
+	total := 0.0
+	for _, weight := range weights {
+		if weight < 0 {
+			return fmt.Errorf("score weight %g is negative", weight)
+		}
+		total += weight
+	}
+	if total == 0 {
+		return errors.New("at least one score weight must be greater than zero")
+	}
+	if config.WarningPenalty < 0 || config.CriticalPenalty < 0 || config.AlertPenalty < 0 {
+		return errors.New("score penalties must not be negative")
+	}
+	if config.CriticalScore > config.WarningScore {
+		return fmt.Errorf("critical score %g is above warning score %g", config.CriticalScore, config.WarningScore)
+	}
+
+	current.mutex.Lock()
+	current.config = config
+	current.mutex.Unlock()
+	return nil
+}
+
+// GetConfig returns the score settings
+func GetConfig() Config {
+	current.mutex.Lock()
+	defer current.mutex.Unlock()
+	return current.config
+}
+
+// Compute scores the given monitor data with the current settings; nil data counts as unknown
+// and is left out of the average, so a disabled monitor neither raises nor lowers the score
+func Compute(cpu *cpumonitor.CPUMonitorData, memory *memorymonitor.MemoryMonitorData, disk *diskmonitor.DiskMonitorData,
+	network *networkmonitor.NetworkMonitorData, process *processmonitor.ProcessMonitorData) *Score {
+	config := GetConfig()
+	components := []Component{
+		{Name: "cpu", Weight: config.CPUWeight},
+		{Name: "memory", Weight: config.MemoryWeight},
+		{Name: "disk", Weight: config.DiskWeight},
+		{Name: "network", Weight: config.NetworkWeight},
+		{Name: "process", Weight: config.ProcessWeight},
+	}
+	if cpu != nil {
+		components[0].rate(config, "", cpuAlerts(cpu))
+	}
+	if memory != nil {
+		components[1].rate(config, memory.MemoryStatus, memoryAlerts(memory))
+	}
+	if disk != nil {
+		components[2].rate(config, disk.DiskStatus, diskAlerts(disk))
+	}
+	if network != nil {
+		components[3].rate(config, network.NetworkStatus, networkAlerts(network))
+	}
+	if process != nil {
+		components[4].rate(config, process.ProcessStatus, processAlerts(process))
+	}
+
+	score := &Score{Status: StatusUnknown, Components: components}
+	sum, weights := 0.0, 0.0
+	for i := range components {
+		if components[i].Status == "" {
+			components[i].Status = StatusUnknown
+			continue
+		}
+		sum += components[i].Value * components[i].Weight
+		weights += components[i].Weight
+	}
+	if weights > 0 {
+		score.Value = sum / weights
+		score.Status = config.status(score.Value)
+	}
+	return score
+}
+
+// Weakest returns the known component with the lowest score, or nil when every monitor is unknown
+func (score *Score) Weakest() *Component {
+	var weakest *Component
+	for i, component := range score.Components {
+		if component.Status != StatusUnknown && component.Weight > 0 && (weakest == nil || component.Value < weakest.Value) {
+			weakest = &score.Components[i]
+		}
+	}
+	return weakest
+}
+
+// monitorAlert is one raised alert of a monitor
+type monitorAlert struct {
+	level  string // Warning or Critical
+	reason string
+}
+
+// rate sets the component's score and status from the monitor's overall status and its raised alerts
+// The overall status sets the level when it is worse than every single alert, e.g. a status raised by a rule's hysteresis
+func (component *Component) rate(config Config, overall string, alerts []monitorAlert) {
+	component.Value = 100
+	component.Status = StatusNormal
+	if overall == StatusWarning || overall == StatusCritical {
+		component.Status = overall
+	}
+	for _, raised := range alerts {
+		component.Reasons = append(component.Reasons, raised.reason)
+		component.Value -= config.AlertPenalty
+		if raised.level == StatusCritical || component.Status == StatusNormal {
+			component.Status = raised.level
+		}
+	}
+	if len(alerts) == 0 && component.Status != StatusNormal {
+		component.Reasons = append(component.Reasons, "status "+component.Status)
+	}
+
+	switch component.Status {
+	case StatusCritical:
+		component.Value -= config.CriticalPenalty
+	case StatusWarning:
+		component.Value -= config.WarningPenalty
+	}
+	component.Value = min(max(component.Value, 0), 100)
+}
+
+// status returns the level of an overall score
+func (config Config) status(value float64) string {
+	switch {
+	case value < config.CriticalScore:
+		return StatusCritical
+	case value < config.WarningScore:
+		return StatusWarning
+	}
+	return StatusNormal
+}
+
+// check is one alert source of a monitor: a Normal/Warning/Critical status or a warning flag
+type check struct {
+	reason string
+	status string // Status field, empty for flags
+	flag   bool   // Warning flag, used when status is empty
+}
+
+// raised returns the alerts of the checks that are not Normal
+func raised(checks ...check) []monitorAlert {
+	var alerts []monitorAlert
+	for _, c := range checks {
+		switch {
+		case c.status == StatusWarning || c.status == StatusCritical:
+			alerts = append(alerts, monitorAlert{level: c.status, reason: c.reason + " " + c.status})
+		case c.status == "" && c.flag:
+			alerts = append(alerts, monitorAlert{level: StatusWarning, reason: c.reason})
+		}
+	}
+	return alerts
+}
@@ -0,0 +1,151 @@
+package healthscore
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/ahmadreza-log/simple-monitor/cpumonitor"
+	"github.com/ahmadreza-log/simple-monitor/diskmonitor"
+	"github.com/ahmadreza-log/simple-monitor/memorymonitor"
+)
+
+// withConfig applies config for the rest of the test
+func withConfig(t *testing.T, config Config) {
+	t.Helper()
+	previous := GetConfig()
+	if err := SetConfig(config); err != nil {
+		t.Fatalf("SetConfig() error = %v", err)
+	}
+	t.Cleanup(func() { SetConfig(previous) })
+}
+
+func TestCompute(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      func(*Config)
+		cpu         *cpumonitor.CPUMonitorData
+		memory      *memorymonitor.MemoryMonitorData
+		disk        *diskmonitor.DiskMonitorData
+		wantValue   float64
+		wantStatus  string
+		wantReasons map[string][]string // Reasons per component name
+	}{
+		{
+			name:       "no data",
+			wantStatus: StatusUnknown,
+		},
+		{
+			name:       "no alerts",
+			cpu:        &cpumonitor.CPUMonitorData{UsageStatus: StatusNormal},
+			wantValue:  100,
+			wantStatus: StatusNormal,
+		},
+		{
+			name:        "single warning",
+			cpu:         &cpumonitor.CPUMonitorData{UsageStatus: StatusWarning},
+			wantValue:   80,
+			wantStatus:  StatusNormal,
+			wantReasons: map[string][]string{"cpu": {"usage Warning"}},
+		},
+		{
+			name:        "critical and warning",
+			cpu:         &cpumonitor.CPUMonitorData{UsageStatus: StatusCritical, TemperatureStatus: StatusWarning},
+			wantValue:   40,
+			wantStatus:  StatusCritical,
+			wantReasons: map[string][]string{"cpu": {"usage Critical", "temperature Warning"}},
+		},
+		{
+			name:        "overall status without alerts",
+			memory:      &memorymonitor.MemoryMonitorData{MemoryStatus: StatusWarning},
+			wantValue:   85,
+			wantStatus:  StatusNormal,
+			wantReasons: map[string][]string{"memory": {"status Warning"}},
+		},
+		{
+			name:        "averaged over known monitors",
+			cpu:         &cpumonitor.CPUMonitorData{UsageStatus: StatusNormal},
+			disk:        &diskmonitor.DiskMonitorData{LowSpaceWarning: true, HealthWarning: true},
+			wantValue:   (100 + 75) / 2.0,
+			wantStatus:  StatusNormal,
+			wantReasons: map[string][]string{"disk": {"low disk space", "drive health"}},
+		},
+		{
+			name:        "weighted",
+			config:      func(config *Config) { config.DiskWeight = 7 },
+			cpu:         &cpumonitor.CPUMonitorData{UsageStatus: StatusNormal},
+			disk:        &diskmonitor.DiskMonitorData{LowSpaceWarning: true, HealthWarning: true},
+			wantValue:   (100 + 7*75) / 8.0,
+			wantStatus:  StatusWarning,
+			wantReasons: map[string][]string{"disk": {"low disk space", "drive health"}},
+		},
+		{
+			name:        "penalties floored at zero",
+			config:      func(config *Config) { config.CriticalPenalty = 200 },
+			cpu:         &cpumonitor.CPUMonitorData{UsageStatus: StatusCritical},
+			wantValue:   0,
+			wantStatus:  StatusCritical,
+			wantReasons: map[string][]string{"cpu": {"usage Critical"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultConfig()
+			if tt.config != nil {
+				tt.config(&config)
+			}
+			withConfig(t, config)
+
+			score := Compute(tt.cpu, tt.memory, tt.disk, nil, nil)
+			if score.Value != tt.wantValue || score.Status != tt.wantStatus {
+				t.Errorf("Compute() = %g %s, want %g %s", score.Value, score.Status, tt.wantValue, tt.wantStatus)
+			}
+			for _, component := range score.Components {
+				if !slices.Equal(component.Reasons, tt.wantReasons[component.Name]) {
+					t.Errorf("Compute() %s reasons = %q, want %q", component.Name, component.Reasons, tt.wantReasons[component.Name])
+				}
+			}
+			if score.Components[3].Status != StatusUnknown || score.Components[4].Status != StatusUnknown {
+				t.Errorf("Compute() without network and process data = %+v, want them unknown", score.Components[3:])
+			}
+		})
+	}
+}
+
+func TestWeakest(t *testing.T) {
+	withConfig(t, DefaultConfig())
+
+	score := Compute(&cpumonitor.CPUMonitorData{UsageStatus: StatusWarning}, nil,
+		&diskmonitor.DiskMonitorData{ReadOnlyWarning: true, IOBottleneck: true}, nil, nil)
+	if weakest := score.Weakest(); weakest == nil || weakest.Name != "disk" {
+		t.Errorf("Weakest() = %+v, want the disk component", weakest)
+	}
+	if weakest := Compute(nil, nil, nil, nil, nil).Weakest(); weakest != nil {
+		t.Errorf("Weakest() without data = %+v, want nil", weakest)
+	}
+}
+
+func TestSetConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		change  func(*Config)
+		wantErr bool
+	}{
+		{name: "defaults", change: func(*Config) {}},
+		{name: "one monitor", change: func(config *Config) {
+			config.CPUWeight, config.MemoryWeight, config.DiskWeight, config.NetworkWeight = 0, 0, 0, 0
+		}},
+		{name: "negative weight", change: func(config *Config) { config.DiskWeight = -1 }, wantErr: true},
+		{name: "no weights", change: func(config *Config) { *config = Config{} }, wantErr: true},
+		{name: "negative penalty", change: func(config *Config) { config.AlertPenalty = -5 }, wantErr: true},
+		{name: "critical above warning", change: func(config *Config) { config.CriticalScore = 90 }, wantErr: true},
+	}
+	previous := GetConfig()
+	t.Cleanup(func() { SetConfig(previous) })
+	for _, tt := range tests {
+		config := DefaultConfig()
+		tt.change(&config)
+		if err := SetConfig(config); (err != nil) != tt.wantErr {
+			t.Errorf("%s: SetConfig() error = %v, want error %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
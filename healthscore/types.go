@@ -0,0 +1,48 @@
+package healthscore
+
+import "sync"
+
+// Status levels of the overall score and of each component
+const (
+	StatusNormal   = "Normal"   // At or above the warning score
+	StatusWarning  = "Warning"  // Below the warning score
+	StatusCritical = "Critical" // Below the critical score
+	StatusUnknown  = "Unknown"  // The monitor could not be collected or is disabled
+)
+
+// Config holds the weights and penalties of the health score
+// A monitor's component starts at 100 and loses the penalty of its worst status plus AlertPenalty for every raised alert
+type Config struct {
+	CPUWeight       float64 `json:"cpu_weight"`       // Share of the CPU component in the overall score
+	MemoryWeight    float64 `json:"memory_weight"`    // Share of the memory component
+	DiskWeight      float64 `json:"disk_weight"`      // Share of the disk component
+	NetworkWeight   float64 `json:"network_weight"`   // Share of the network component
+	ProcessWeight   float64 `json:"process_weight"`   // Share of the process component
+	WarningPenalty  float64 `json:"warning_penalty"`  // Points a component loses when its worst status is Warning
+	CriticalPenalty float64 `json:"critical_penalty"` // Points a component loses when its worst status is Critical
+	AlertPenalty    float64 `json:"alert_penalty"`    // Points a component loses for every raised alert
+	WarningScore    float64 `json:"warning_score"`    // Scores below this are a warning
+	CriticalScore   float64 `json:"critical_score"`   // Scores below this are critical
+}
+
+// Score is the combined health index of the system, 0 (everything failing) to 100 (no alerts)
+type Score struct {
+	Value      float64     `json:"value"`      // Weighted average of the known components
+	Status     string      `json:"status"`     // Normal, Warning or Critical (Unknown when no monitor could be scored)
+	Components []Component `json:"components"` // One per monitor, in CPU, memory, disk, network, process order
+}
+
+// Component is one monitor's part of the score, with the alerts that lowered it
+type Component struct {
+	Name    string   `json:"name"`              // Monitor name: cpu, memory, disk, network or process
+	Value   float64  `json:"value"`             // Component score, 0 to 100
+	Weight  float64  `json:"weight"`            // Configured weight (0 leaves the monitor out of the overall score)
+	Status  string   `json:"status"`            // Worst status reported by the monitor
+	Reasons []string `json:"reasons,omitempty"` // Raised alerts, e.g. "usage Warning" or "low disk space"
+}
+
+// settings holds the score configuration shared by the whole program
+type settings struct {
+	config Config
+	mutex  sync.Mutex
+}
@@ -0,0 +1,52 @@
+package historystore
+
+import "time"
+
+// DefaultBufferSize is the number of samples a monitor's in-memory history keeps unless configured otherwise
+const DefaultBufferSize = 100
+
+// bufferTimestampBytes is the size of one time.Time in an in-memory history
+const bufferTimestampBytes = 24
+
+// BufferSampleDue reports whether an in-memory history with the given timestamps takes a sample at now
+// With a zero resolution every refresh is sampled
+func BufferSampleDue(timestamps []time.Time, now time.Time, resolution time.Duration) bool {
+	if resolution <= 0 || len(timestamps) == 0 {
+		return true
+	}
+	return now.Sub(timestamps[len(timestamps)-1]) >= resolution
+}
+
+// BufferExcess returns how many of the oldest samples to drop so that at most size are kept
+// A size below one keeps the default number of samples
+func BufferExcess(count, size int) int {
+	if size < 1 {
+		size = DefaultBufferSize
+	}
+	if count <= size {
+		return 0
+	}
+	return count - size
+}
+
+// BufferBytes estimates the memory an in-memory history of samples uses,
+// counting a timestamp and one float64 per series for every sample
+func BufferBytes(samples, series int) uint64 {
+	if samples < 1 {
+		samples = DefaultBufferSize
+	}
+	return uint64(samples) * uint64(bufferTimestampBytes+8*series)
+}
+
+// BufferSpan returns how much time a full in-memory history covers when refreshed every interval
+// A sample is taken on the first refresh at least resolution after the previous one
+func BufferSpan(samples int, interval, resolution time.Duration) time.Duration {
+	if samples < 1 {
+		samples = DefaultBufferSize
+	}
+	step := interval
+	if interval > 0 && resolution > interval {
+		step = (resolution + interval - 1) / interval * interval
+	}
+	return time.Duration(samples) * step
+}
@@ -0,0 +1,67 @@
+package historystore
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBufferExcess(t *testing.T) {
+	tests := []struct {
+		count, size, want int
+	}{
+		{count: 50, size: 60, want: 0},
+		{count: 60, size: 60, want: 0},
+		{count: 65, size: 60, want: 5},
+		{count: 105, size: 0, want: 5},
+		{count: 10, size: -1, want: 0},
+	}
+	for _, tt := range tests {
+		if got := BufferExcess(tt.count, tt.size); got != tt.want {
+			t.Errorf("BufferExcess(%d, %d) = %d, want %d", tt.count, tt.size, got, tt.want)
+		}
+	}
+}
+
+func TestBufferSampleDue(t *testing.T) {
+	last := time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		timestamps []time.Time
+		now        time.Time
+		resolution time.Duration
+		want       bool
+	}{
+		{name: "every refresh", timestamps: []time.Time{last}, now: last.Add(time.Second), want: true},
+		{name: "first sample", now: last, resolution: time.Minute, want: true},
+		{name: "too soon", timestamps: []time.Time{last}, now: last.Add(59 * time.Second), resolution: time.Minute},
+		{name: "due", timestamps: []time.Time{last}, now: last.Add(time.Minute), resolution: time.Minute, want: true},
+	}
+	for _, tt := range tests {
+		if got := BufferSampleDue(tt.timestamps, tt.now, tt.resolution); got != tt.want {
+			t.Errorf("%s: BufferSampleDue() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBufferSpan(t *testing.T) {
+	tests := []struct {
+		samples              int
+		interval, resolution time.Duration
+		want                 time.Duration
+	}{
+		{samples: 60, interval: time.Second, want: time.Minute},
+		{samples: 60, interval: 2 * time.Second, resolution: 5 * time.Second, want: 6 * time.Minute},
+		{samples: 0, interval: time.Second, want: DefaultBufferSize * time.Second},
+	}
+	for _, tt := range tests {
+		if got := BufferSpan(tt.samples, tt.interval, tt.resolution); got != tt.want {
+			t.Errorf("BufferSpan(%d, %v, %v) = %v, want %v", tt.samples, tt.interval, tt.resolution, got, tt.want)
+		}
+	}
+}
+
+func TestBufferBytes(t *testing.T) {
+	if got, want := BufferBytes(10, 2), uint64(10*(bufferTimestampBytes+16)); got != want {
+		t.Errorf("BufferBytes(10, 2) = %d, want %d", got, want)
+	}
+}
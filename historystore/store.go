@@ -0,0 +1,329 @@
+// Package historystore keeps long-term metric history in tiers of raw points and 1 and 5 minute averages,
+// persisted to JSON files and pruned by age
+package historystore
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Store keeps long-term history beyond a monitor's in-memory buffer
+// Samples are stored raw for a short time and as averages at coarser resolutions for longer,
+// so the history file stays small while trend charts can still span weeks
+type Store struct {
+	mutex sync.Mutex
+	tiers []TierData
+
+	// Persistence state, guarded by persistMutex: Persist loads and saves, which take mutex themselves
+	persistMutex sync.Mutex
+	path         string
+	lastSave     time.Time
+}
+
+// maxAge is the data retention limit shared by every store, in nanoseconds (0 = no limit)
+var maxAge atomic.Int64
+
+// DefaultTiers returns the standard downsampling tiers:
+// raw samples for 1 hour, 1-minute averages for 24 hours and 5-minute averages for 30 days
+func DefaultTiers() []Tier {
+	return []Tier{
+		{Name: "raw", Resolution: 0, Retention: 1 * time.Hour},
+		{Name: "1m", Resolution: 1 * time.Minute, Retention: 24 * time.Hour},
+		{Name: "5m", Resolution: 5 * time.Minute, Retention: 30 * 24 * time.Hour},
+	}
+}
+
+// NewStore creates a new history store with the given tiers, finest resolution first
+func NewStore(tiers []Tier) *Store {
+	store := &Store{}
+	for _, tier := range tiers {
+		store.tiers = append(store.tiers, TierData{Tier: tier})
+	}
+	return store
+}
+
+// Add records a sample in every tier and drops points past their retention
+// Non-finite values (NaN, Inf) are skipped because they cannot be stored as JSON
+func (store *Store) Add(timestamp time.Time, values map[string]float64) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	values = finiteValues(values)
+
+	for i := range store.tiers {
+		tier := &store.tiers[i]
+
+		if tier.Tier.Resolution <= 0 {
+			tier.Points = append(tier.Points, Point{Timestamp: timestamp, Values: values})
+		} else {
+			start := timestamp.Truncate(tier.Tier.Resolution)
+
+			// A sample in a new bucket closes the previous one
+			if tier.Pending != nil && !tier.Pending.Start.Equal(start) {
+				tier.Points = append(tier.Points, tier.Pending.average())
+				tier.Pending = nil
+			}
+			if tier.Pending == nil {
+				tier.Pending = &bucket{Start: start, Sums: make(map[string]float64)}
+			}
+			for name, value := range values {
+				tier.Pending.Sums[name] += value
+			}
+			tier.Pending.Count++
+		}
+
+		tier.prune(timestamp)
+	}
+}
+
+// Series returns one metric across all tiers, oldest first
+// Each time range comes from the finest tier that still covers it
+func (store *Store) Series(name string) []SeriesPoint {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	var series []SeriesPoint
+
+	for i := len(store.tiers) - 1; i >= 0; i-- {
+		tier := store.tiers[i]
+		points := tier.Points
+		if tier.Pending != nil {
+			points = append(points[:len(points):len(points)], tier.Pending.average())
+		}
+
+		var tierSeries []SeriesPoint
+		for _, point := range points {
+			value, ok := point.Values[name]
+			if !ok {
+				continue
+			}
+			tierSeries = append(tierSeries, SeriesPoint{Timestamp: point.Timestamp, Value: value, Tier: tier.Tier.Name})
+		}
+		if len(tierSeries) == 0 {
+			continue
+		}
+
+		// Coarser points already in the series are replaced where this tier has data
+		start := tierSeries[0].Timestamp
+		for len(series) > 0 && !series[len(series)-1].Timestamp.Before(start) {
+			series = series[:len(series)-1]
+		}
+		series = append(series, tierSeries...)
+	}
+
+	return series
+}
+
+// Tiers returns a copy of the stored tiers that shares no points or buckets with the store
+func (store *Store) Tiers() []TierData {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	tiers := make([]TierData, len(store.tiers))
+	for i, tier := range store.tiers {
+		tiers[i].Tier = tier.Tier
+		tiers[i].Points = make([]Point, len(tier.Points))
+		for j, point := range tier.Points {
+			tiers[i].Points[j] = Point{Timestamp: point.Timestamp, Values: maps.Clone(point.Values)}
+		}
+		if tier.Pending != nil {
+			tiers[i].Pending = &bucket{Start: tier.Pending.Start, Sums: maps.Clone(tier.Pending.Sums), Count: tier.Pending.Count}
+		}
+	}
+	return tiers
+}
+
+// PointCount returns the number of stored points across all tiers
+func (store *Store) PointCount() int {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	count := 0
+	for _, tier := range store.tiers {
+		count += len(tier.Points)
+	}
+	return count
+}
+
+// Persist saves the store to path at most once per interval
+// The first call for a path merges any history already saved there, so history survives restarts
+func (store *Store) Persist(path string, interval time.Duration) error {
+	store.persistMutex.Lock()
+	defer store.persistMutex.Unlock()
+
+	if store.path != path {
+		if err := store.Load(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		store.path = path
+	}
+
+	if !store.lastSave.IsZero() && time.Since(store.lastSave) < interval {
+		return nil
+	}
+	if err := store.Save(path); err != nil {
+		return err
+	}
+	store.lastSave = time.Now()
+	return nil
+}
+
+// Save writes the store to a JSON file
+func (store *Store) Save(path string) error {
+	store.mutex.Lock()
+	jsonData, err := json.Marshal(store.tiers)
+	store.mutex.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to marshal history: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	// Write to a temporary file first so an interrupted save never leaves a truncated history
+	tempPath := path + ".tmp"
+	if err := os.WriteFile(tempPath, jsonData, 0644); err != nil {
+		return fmt.Errorf("failed to write history file: %w", err)
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		return fmt.Errorf("failed to replace history file: %w", err)
+	}
+	return nil
+}
+
+// Load reads a saved store and merges it in front of the points collected so far
+// Tiers are matched by name; saved tiers that no longer exist are ignored
+func (store *Store) Load(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var saved []TierData
+	if err := json.Unmarshal(content, &saved); err != nil {
+		return fmt.Errorf("failed to parse history file: %w", err)
+	}
+
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	now := time.Now()
+	for i := range store.tiers {
+		tier := &store.tiers[i]
+		for _, savedTier := range saved {
+			if savedTier.Tier.Name != tier.Tier.Name {
+				continue
+			}
+
+			// Keep saved points older than anything collected in this session
+			var points []Point
+			for _, point := range savedTier.Points {
+				if len(tier.Points) == 0 || point.Timestamp.Before(tier.Points[0].Timestamp) {
+					points = append(points, point)
+				}
+			}
+			if savedTier.Pending != nil && (tier.Pending == nil || savedTier.Pending.Start.Before(tier.Pending.Start)) {
+				if len(tier.Points) == 0 || savedTier.Pending.Start.Before(tier.Points[0].Timestamp) {
+					points = append(points, savedTier.Pending.average())
+				}
+			}
+			tier.Points = append(points, tier.Points...)
+			tier.prune(now)
+		}
+	}
+	return nil
+}
+
+// prune drops points older than the tier's retention, or than the data retention limit when that is shorter
+func (tier *TierData) prune(now time.Time) {
+	retention := tier.Tier.Retention
+	if limit := MaxAge(); limit > 0 && limit < retention {
+		retention = limit
+	}
+	cutoff := now.Add(-retention)
+	drop := 0
+	for drop < len(tier.Points) && tier.Points[drop].Timestamp.Before(cutoff) {
+		drop++
+	}
+	if drop > 0 {
+		tier.Points = tier.Points[drop:]
+	}
+}
+
+// SetMaxAge limits how long every store keeps points, on top of each tier's own retention
+// Zero removes the limit. Stores apply it the next time they add a sample
+func SetMaxAge(age time.Duration) {
+	maxAge.Store(int64(age))
+}
+
+// MaxAge returns the limit set by SetMaxAge
+func MaxAge() time.Duration {
+	return time.Duration(maxAge.Load())
+}
+
+// PruneFile drops the points saved in a history file that are older than cutoff
+// It returns the number of points dropped; with dryRun the file is left untouched
+func PruneFile(path string, cutoff time.Time, dryRun bool) (int, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	var saved []TierData
+	if err := json.Unmarshal(content, &saved); err != nil {
+		return 0, fmt.Errorf("failed to parse history file: %w", err)
+	}
+
+	dropped := 0
+	for i := range saved {
+		tier := &saved[i]
+		drop := 0
+		for drop < len(tier.Points) && tier.Points[drop].Timestamp.Before(cutoff) {
+			drop++
+		}
+		dropped += drop
+		tier.Points = tier.Points[drop:]
+		if tier.Pending != nil && tier.Pending.Start.Before(cutoff) {
+			tier.Pending = nil
+			dropped++
+		}
+	}
+	if dropped == 0 || dryRun {
+		return dropped, nil
+	}
+
+	store := &Store{tiers: saved}
+	if err := store.Save(path); err != nil {
+		return 0, err
+	}
+	return dropped, nil
+}
+
+// average returns the bucket as a single averaged point
+func (bucket *bucket) average() Point {
+	values := make(map[string]float64, len(bucket.Sums))
+	for name, sum := range bucket.Sums {
+		values[name] = sum / float64(bucket.Count)
+	}
+	return Point{Timestamp: bucket.Start, Values: values}
+}
+
+// finiteValues copies a metric map without NaN and Inf values, so callers can reuse theirs
+func finiteValues(values map[string]float64) map[string]float64 {
+	copied := make(map[string]float64, len(values))
+	for name, value := range values {
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			continue
+		}
+		copied[name] = value
+	}
+	return copied
+}
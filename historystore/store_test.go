@@ -0,0 +1,215 @@
+package historystore
+
+import (
+	"math"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+var start = time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC)
+
+func TestAddDownsamples(t *testing.T) {
+	store := NewStore(DefaultTiers())
+	// One sample every 20 seconds for 11 minutes: 33 samples, values 0, 1, 2, ...
+	for i := 0; i < 33; i++ {
+		store.Add(start.Add(time.Duration(i)*20*time.Second), map[string]float64{"usage": float64(i)})
+	}
+
+	tests := []struct {
+		tier       string
+		wantPoints int
+		wantFirst  float64
+		wantLast   float64
+	}{
+		{tier: "raw", wantPoints: 33, wantFirst: 0, wantLast: 32},
+		// Closed one-minute buckets hold three samples each: (0+1+2)/3, ... (27+28+29)/3; 30-32 are pending
+		{tier: "1m", wantPoints: 10, wantFirst: 1, wantLast: 28},
+		// The first five-minute bucket averages samples 0-14 and the second 15-29
+		{tier: "5m", wantPoints: 2, wantFirst: 7, wantLast: 22},
+	}
+	tiers := store.Tiers()
+	for i, tt := range tests {
+		tier := tiers[i]
+		if tier.Tier.Name != tt.tier {
+			t.Fatalf("tier %d = %s, want %s", i, tier.Tier.Name, tt.tier)
+		}
+		if len(tier.Points) != tt.wantPoints {
+			t.Errorf("%s tier has %d points, want %d", tt.tier, len(tier.Points), tt.wantPoints)
+			continue
+		}
+		first, last := tier.Points[0].Values["usage"], tier.Points[len(tier.Points)-1].Values["usage"]
+		if first != tt.wantFirst || last != tt.wantLast {
+			t.Errorf("%s tier runs from %g to %g, want %g to %g", tt.tier, first, last, tt.wantFirst, tt.wantLast)
+		}
+	}
+}
+
+func TestAddPrunesByRetention(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxAge     time.Duration
+		wantPoints int
+	}{
+		// The sample exactly at the cutoff is kept
+		{name: "tier retention", wantPoints: 61},
+		{name: "data retention limit", maxAge: 10 * time.Minute, wantPoints: 11},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetMaxAge(tt.maxAge)
+			defer SetMaxAge(0)
+
+			store := NewStore([]Tier{{Name: "raw", Retention: time.Hour}})
+			// One sample a minute for two hours
+			for i := 0; i < 120; i++ {
+				store.Add(start.Add(time.Duration(i)*time.Minute), map[string]float64{"usage": 1})
+			}
+			if got := store.PointCount(); got != tt.wantPoints {
+				t.Errorf("PointCount() = %d, want %d", got, tt.wantPoints)
+			}
+		})
+	}
+}
+
+func TestSeriesPrefersFinerTiers(t *testing.T) {
+	store := NewStore([]Tier{
+		{Name: "raw", Retention: 3 * time.Minute},
+		{Name: "1m", Resolution: time.Minute, Retention: time.Hour},
+	})
+	for i := 0; i < 10; i++ {
+		store.Add(start.Add(time.Duration(i)*30*time.Second), map[string]float64{"usage": float64(i), "temp": 40})
+	}
+
+	series := store.Series("usage")
+	if len(series) == 0 {
+		t.Fatal("Series() is empty")
+	}
+	for i := 1; i < len(series); i++ {
+		if !series[i].Timestamp.After(series[i-1].Timestamp) {
+			t.Fatalf("Series() is not ordered at %d: %v after %v", i, series[i].Timestamp, series[i-1].Timestamp)
+		}
+	}
+	if first := series[0]; first.Tier != "1m" || first.Value != 0.5 {
+		t.Errorf("first point = %+v, want the 1m average 0.5", first)
+	}
+	if last := series[len(series)-1]; last.Tier != "raw" || last.Value != 9 {
+		t.Errorf("last point = %+v, want the raw sample 9", last)
+	}
+	if got := store.Series("missing"); len(got) != 0 {
+		t.Errorf("Series(missing) = %+v, want none", got)
+	}
+}
+
+func TestAddSkipsNonFiniteValues(t *testing.T) {
+	store := NewStore([]Tier{{Name: "raw", Retention: time.Hour}})
+	values := map[string]float64{"usage": 50, "temp": math.NaN(), "rate": math.Inf(1)}
+	store.Add(start, values)
+
+	point := store.Tiers()[0].Points[0]
+	if len(point.Values) != 1 || point.Values["usage"] != 50 {
+		t.Errorf("stored values = %v, want only usage", point.Values)
+	}
+	if len(values) != 3 {
+		t.Errorf("Add() changed the caller's map: %v", values)
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	now := time.Now().Truncate(time.Minute)
+
+	saved := NewStore(DefaultTiers())
+	for i := 0; i < 5; i++ {
+		saved.Add(now.Add(time.Duration(i-10)*time.Minute), map[string]float64{"usage": float64(i)})
+	}
+	if err := saved.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// The new session already has a sample; saved points older than it go in front
+	loaded := NewStore(DefaultTiers())
+	loaded.Add(now, map[string]float64{"usage": 99})
+	if err := loaded.Load(path); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	raw := loaded.Tiers()[0].Points
+	if len(raw) != 6 || raw[0].Values["usage"] != 0 || raw[5].Values["usage"] != 99 {
+		t.Errorf("raw tier after Load() = %+v, want the 5 saved samples then 99", raw)
+	}
+}
+
+func TestPruneFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	store := NewStore([]Tier{{Name: "raw", Retention: 24 * time.Hour}})
+	now := time.Now()
+	for i := 0; i < 10; i++ {
+		store.Add(now.Add(time.Duration(i-10)*time.Hour), map[string]float64{"usage": float64(i)})
+	}
+	if err := store.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	cutoff := now.Add(-5*time.Hour - time.Minute)
+	if dropped, err := PruneFile(path, cutoff, true); err != nil || dropped != 5 {
+		t.Fatalf("PruneFile(dry run) = %d, %v, want 5", dropped, err)
+	}
+	if dropped, err := PruneFile(path, cutoff, false); err != nil || dropped != 5 {
+		t.Fatalf("PruneFile() = %d, %v, want 5", dropped, err)
+	}
+	if dropped, err := PruneFile(path, cutoff, false); err != nil || dropped != 0 {
+		t.Errorf("second PruneFile() = %d, %v, want nothing left to drop", dropped, err)
+	}
+}
+
+func TestTiersAreCopies(t *testing.T) {
+	store := NewStore(DefaultTiers())
+	for i := 0; i < 4; i++ {
+		store.Add(start.Add(time.Duration(i)*20*time.Second), map[string]float64{"usage": float64(i)})
+	}
+
+	tiers := store.Tiers()
+	tiers[0].Points[0].Values["usage"] = 99
+	tiers[0].Points = append(tiers[0].Points[:0], Point{Timestamp: start})
+	tiers[1].Pending.Sums["usage"] = 99
+
+	again := store.Tiers()
+	if value := again[0].Points[0].Values["usage"]; value != 0 || len(again[0].Points) != 4 {
+		t.Errorf("Tiers() after changing a copy = %d raw points starting at %v, want 4 starting at 0", len(again[0].Points), value)
+	}
+	if sum := again[1].Pending.Sums["usage"]; sum != 3 {
+		t.Errorf("Tiers() pending 1m sum after changing a copy = %v, want 3", sum)
+	}
+}
+
+func TestPersistConcurrently(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	store := NewStore(DefaultTiers())
+	now := time.Now()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				store.Add(now.Add(time.Duration(i*10+j)*time.Millisecond), map[string]float64{"usage": float64(j)})
+				if err := store.Persist(path, 0); err != nil {
+					t.Errorf("Persist() error = %v", err)
+					return
+				}
+				store.Tiers()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	loaded := NewStore(DefaultTiers())
+	if err := loaded.Load(path); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if count := loaded.PointCount(); count == 0 {
+		t.Error("Load() after concurrent Persist() calls found no points")
+	}
+}
@@ -0,0 +1,38 @@
+package historystore
+
+import "time"
+
+// Tier describes one retention level of the long-term history
+// A zero Resolution keeps every sample; otherwise samples are averaged into buckets of that size
+type Tier struct {
+	Name       string        `json:"name"`       // Tier name (raw, 1m, 5m)
+	Resolution time.Duration `json:"resolution"` // Bucket size for averaging (0 = raw samples)
+	Retention  time.Duration `json:"retention"`  // How long points are kept in this tier
+}
+
+// Point represents one stored sample or bucket average
+type Point struct {
+	Timestamp time.Time          `json:"timestamp"` // Sample time, or bucket start for averaged tiers
+	Values    map[string]float64 `json:"values"`    // Metric values keyed by the history field name
+}
+
+// bucket accumulates samples for the averaged point currently being built
+type bucket struct {
+	Start time.Time          `json:"start"` // Bucket start time
+	Sums  map[string]float64 `json:"sums"`  // Sum of each metric within the bucket
+	Count int                `json:"count"` // Number of samples in the bucket
+}
+
+// TierData holds the stored points of one tier
+type TierData struct {
+	Tier    Tier    `json:"tier"`              // Tier configuration
+	Points  []Point `json:"points"`            // Stored points, oldest first
+	Pending *bucket `json:"pending,omitempty"` // Bucket still being filled (averaged tiers only)
+}
+
+// SeriesPoint represents one value of a single metric over time
+type SeriesPoint struct {
+	Timestamp time.Time `json:"timestamp"` // Point time
+	Value     float64   `json:"value"`     // Metric value
+	Tier      string    `json:"tier"`      // Tier the point came from
+}
@@ -0,0 +1,151 @@
+package hooks
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// digestLevels are the alert levels in the order a digest lists them, worst first
+var digestLevels = []string{"Critical", "Warning", "Normal"}
+
+// queueDigest adds an alert change to the digest, opening a window of the given length for the first one
+// An alert that changes again within the window is listed once at its latest level
+func queueDigest(vars map[string]string, window time.Duration) {
+	current.mutex.Lock()
+	defer current.mutex.Unlock()
+
+	for _, entry := range current.digest {
+		if entry.Monitor == vars["MONITOR"] && entry.Alert == vars["ALERT"] {
+			entry.Level, entry.Value, entry.Note = vars["LEVEL"], vars["VALUE"], vars["NOTE"]
+			entry.Changes++
+			return
+		}
+	}
+	current.digest = append(current.digest, &digestEntry{
+		Monitor:  vars["MONITOR"],
+		Alert:    vars["ALERT"],
+		Level:    vars["LEVEL"],
+		Previous: vars["PREVIOUS_LEVEL"],
+		Value:    vars["VALUE"],
+		Note:     vars["NOTE"],
+		Changes:  1,
+	})
+	if current.sending == nil {
+		opened := time.Now()
+		current.sending = time.AfterFunc(window, func() {
+			sendDigest(opened, window)
+		})
+	}
+}
+
+// sendDigest closes the digest window opened at since and fires alert_digest with its alerts
+// Each hook gets the alerts its monitor and alert filters select, and does not run when none are left
+func sendDigest(since time.Time, window time.Duration) {
+	current.mutex.Lock()
+	entries := current.digest
+	current.digest = nil
+	current.sending = nil
+	current.mutex.Unlock()
+
+	config := GetConfig()
+	for _, hook := range config.Commands {
+		if hook.Event != EventAlertDigest {
+			continue
+		}
+		var selected []*digestEntry
+		for _, entry := range entries {
+			if hook.selects(entry.Monitor, entry.Alert) {
+				selected = append(selected, entry)
+			}
+		}
+		if len(selected) > 0 {
+			start(hook, EventAlertDigest, digestVars(selected, since, window), config.Timeout)
+		}
+	}
+}
+
+// digestVars returns the variables passed to an alert_digest hook: counts per level, the worst level,
+// the monitors involved and a SUMMARY of every alert grouped by monitor and level
+func digestVars(entries []*digestEntry, since time.Time, window time.Duration) map[string]string {
+	counts := make(map[string]int)
+	byMonitor := make(map[string][]*digestEntry)
+	for _, entry := range entries {
+		counts[entry.Level]++
+		byMonitor[entry.Monitor] = append(byMonitor[entry.Monitor], entry)
+	}
+	monitors := make([]string, 0, len(byMonitor))
+	for monitor := range byMonitor {
+		monitors = append(monitors, monitor)
+	}
+	sort.Strings(monitors)
+
+	worst := "Normal"
+	for _, level := range digestLevels {
+		if counts[level] > 0 {
+			worst = level
+			break
+		}
+	}
+
+	noun := "alerts"
+	if len(entries) == 1 {
+		noun = "alert"
+	}
+	lines := []string{fmt.Sprintf("%d %s in %s: %d critical, %d warning, %d cleared",
+		len(entries), noun, window, counts["Critical"], counts["Warning"], counts["Normal"])}
+	for _, monitor := range monitors {
+		var groups []string
+		for _, level := range digestLevels {
+			var alerts []string
+			for _, entry := range byMonitor[monitor] {
+				if entry.Level == level {
+					alerts = append(alerts, entry.describe())
+				}
+			}
+			if len(alerts) == 0 {
+				continue
+			}
+			name := level
+			if level == "Normal" {
+				name = "Cleared"
+			}
+			groups = append(groups, name+": "+strings.Join(alerts, ", "))
+		}
+		lines = append(lines, monitor+" - "+strings.Join(groups, "; "))
+	}
+
+	return map[string]string{
+		"LEVEL":    worst,
+		"COUNT":    strconv.Itoa(len(entries)),
+		"CRITICAL": strconv.Itoa(counts["Critical"]),
+		"WARNING":  strconv.Itoa(counts["Warning"]),
+		"CLEARED":  strconv.Itoa(counts["Normal"]),
+		"MONITORS": strings.Join(monitors, ","),
+		"SINCE":    since.Format(time.RFC3339),
+		"SUMMARY":  strings.Join(lines, "\n"),
+	}
+}
+
+// describe returns an alert as listed in a digest, e.g. "disk_space:/var (92.5, changed 3 times)" or "load (was Warning)"
+func (entry *digestEntry) describe() string {
+	var details []string
+	if entry.Level != "Normal" && entry.Value != "" {
+		details = append(details, entry.Value)
+	}
+	if entry.Level == "Normal" && entry.Previous != "Normal" && entry.Previous != "" {
+		details = append(details, "was "+entry.Previous)
+	}
+	if entry.Changes > 1 {
+		details = append(details, fmt.Sprintf("changed %d times", entry.Changes))
+	}
+	if entry.Note != "" && entry.Level != "Normal" {
+		details = append(details, entry.Note)
+	}
+	if len(details) == 0 {
+		return entry.Alert
+	}
+	return entry.Alert + " (" + strings.Join(details, ", ") + ")"
+}
@@ -0,0 +1,80 @@
+package hooks
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDigestVars(t *testing.T) {
+	since := time.Date(2026, 1, 5, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		entries     []*digestEntry
+		wantLevel   string
+		wantCounts  [4]string // COUNT, CRITICAL, WARNING, CLEARED
+		wantMonitor string
+		wantSummary string
+	}{
+		{
+			name:        "one cleared alert",
+			entries:     []*digestEntry{{Monitor: "cpu", Alert: "load", Level: "Normal", Previous: "Warning", Changes: 1}},
+			wantLevel:   "Normal",
+			wantCounts:  [4]string{"1", "0", "0", "1"},
+			wantMonitor: "cpu",
+			wantSummary: "1 alert in 5m0s: 0 critical, 0 warning, 1 cleared\ncpu - Cleared: load (was Warning)",
+		},
+		{
+			name: "grouped by monitor and level",
+			entries: []*digestEntry{
+				{Monitor: "disk", Alert: "disk_space:/var", Level: "Warning", Value: "92.5", Changes: 3, Note: "rotate logs"},
+				{Monitor: "cpu", Alert: "usage", Level: "Warning", Value: "88", Changes: 1},
+				{Monitor: "disk", Alert: "disk_space:/", Level: "Critical", Value: "98", Changes: 1},
+				{Monitor: "disk", Alert: "io_wait", Level: "Normal", Previous: "Normal", Changes: 2},
+			},
+			wantLevel:   "Critical",
+			wantCounts:  [4]string{"4", "1", "2", "1"},
+			wantMonitor: "cpu,disk",
+			wantSummary: "4 alerts in 5m0s: 1 critical, 2 warning, 1 cleared\n" +
+				"cpu - Warning: usage (88)\n" +
+				"disk - Critical: disk_space:/ (98); Warning: disk_space:/var (92.5, changed 3 times, rotate logs); Cleared: io_wait (changed 2 times)",
+		},
+	}
+	for _, tt := range tests {
+		vars := digestVars(tt.entries, since, 5*time.Minute)
+		counts := [4]string{vars["COUNT"], vars["CRITICAL"], vars["WARNING"], vars["CLEARED"]}
+		if vars["LEVEL"] != tt.wantLevel || counts != tt.wantCounts || vars["MONITORS"] != tt.wantMonitor {
+			t.Errorf("%s: digestVars() = level %s, counts %v, monitors %s, want %s, %v, %s",
+				tt.name, vars["LEVEL"], counts, vars["MONITORS"], tt.wantLevel, tt.wantCounts, tt.wantMonitor)
+		}
+		if vars["SUMMARY"] != tt.wantSummary {
+			t.Errorf("%s: digestVars() summary =\n%s\nwant\n%s", tt.name, vars["SUMMARY"], tt.wantSummary)
+		}
+		if vars["SINCE"] != "2026-01-05T10:00:00Z" {
+			t.Errorf("%s: digestVars() since = %s", tt.name, vars["SINCE"])
+		}
+	}
+}
+
+func TestQueueDigestMergesChanges(t *testing.T) {
+	t.Cleanup(func() {
+		current.mutex.Lock()
+		defer current.mutex.Unlock()
+		if current.sending != nil {
+			current.sending.Stop()
+		}
+		current.digest, current.sending = nil, nil
+	})
+
+	queueDigest(map[string]string{"MONITOR": "cpu", "ALERT": "usage", "LEVEL": "Warning", "PREVIOUS_LEVEL": "Normal", "VALUE": "85"}, time.Hour)
+	queueDigest(map[string]string{"MONITOR": "memory", "ALERT": "swap", "LEVEL": "Warning", "VALUE": "40"}, time.Hour)
+	queueDigest(map[string]string{"MONITOR": "cpu", "ALERT": "usage", "LEVEL": "Critical", "PREVIOUS_LEVEL": "Warning", "VALUE": "97"}, time.Hour)
+
+	current.mutex.Lock()
+	defer current.mutex.Unlock()
+	if len(current.digest) != 2 || current.sending == nil {
+		t.Fatalf("queueDigest() kept %d entries (window open %v), want 2 and an open window", len(current.digest), current.sending != nil)
+	}
+	if entry := *current.digest[0]; entry.Level != "Critical" || entry.Previous != "Normal" || entry.Value != "97" || entry.Changes != 2 {
+		t.Errorf("queueDigest() merged entry = %+v, want Critical from Normal at 97 after 2 changes", entry)
+	}
+}
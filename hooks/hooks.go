@@ -0,0 +1,213 @@
+// Package hooks runs configured shell commands when alerts change, exports complete or monitoring starts
+// and stops, optionally batching alerts into a digest
+// Commands run as the current user; the config package only hands them over from a file that user owns
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// LogPath is where every hook run is recorded, one line each
+var LogPath = filepath.Join("logs", "hooks", "hooks.log")
+
+// current is shared by the whole program; no hook runs until configured
+var current = hookState{
+	config:  Config{Timeout: 30 * time.Second},
+	running: make(chan struct{}, maxRunning),
+}
+
+// IsEvent returns whether name is a known event
+func IsEvent(name string) bool {
+	for _, event := range Events {
+		if event == name {
+			return true
+		}
+	}
+	return false
+}
+
+// SetConfig replaces the hooks
+func SetConfig(config Config) error {
+	if config.Timeout <= 0 {
+		return errors.New("hook timeout must be greater than zero")
+	}
+	if config.DigestWindow < 0 {
+		return errors.New("alert digest window is negative")
+	}
+	for i, hook := range config.Commands {
+		if !IsEvent(hook.Event) {
+			return fmt.Errorf("hook %d: unknown event %q (use one of: %s)", i+1, hook.Event, strings.Join(Events, ", "))
+		}
+		if strings.TrimSpace(hook.Command) == "" {
+			return fmt.Errorf("hook %d: command is empty", i+1)
+		}
+		if hook.Timeout < 0 {
+			return fmt.Errorf("hook %d: timeout is negative", i+1)
+		}
+	}
+
+	current.mutex.Lock()
+	current.config = config
+	current.mutex.Unlock()
+	return nil
+}
+
+// GetConfig returns the hooks
+func GetConfig() Config {
+	current.mutex.Lock()
+	defer current.mutex.Unlock()
+	return current.config
+}
+
+// LastRun returns the last finished hook command, or nil when none has run
+func LastRun() *Run {
+	current.mutex.Lock()
+	defer current.mutex.Unlock()
+	return current.last
+}
+
+// Fire starts the hooks attached to event in the background
+// vars are passed as environment variables with EnvPrefix added to each name (MONITOR becomes SIMPLE_MONITOR_MONITOR);
+// MONITOR and ALERT also select which hooks match. While a digest window is set, alert_raised and
+// alert_cleared are held for the next alert_digest instead. Fire never blocks: when maxRunning hooks are
+// already running the command is skipped and the skip is logged
+func Fire(event string, vars map[string]string) {
+	config := GetConfig()
+	if config.DigestWindow > 0 && (event == EventAlertRaised || event == EventAlertCleared) {
+		queueDigest(vars, config.DigestWindow)
+		return
+	}
+	for _, hook := range config.Commands {
+		if hook.matches(event, vars) {
+			start(hook, event, vars, config.Timeout)
+		}
+	}
+}
+
+// start runs a hook in the background unless maxRunning hooks are already running
+func start(hook Hook, event string, vars map[string]string, timeout time.Duration) {
+	if hook.Timeout > 0 {
+		timeout = hook.Timeout
+	}
+	select {
+	case current.running <- struct{}{}:
+		go func() {
+			defer func() { <-current.running }()
+			run(hook, event, vars, timeout)
+		}()
+	default:
+		writeLogLine(Run{Event: event, Command: hook.Command, Started: time.Now(),
+			Error: fmt.Sprintf("skipped: %d hooks already running", maxRunning)})
+	}
+}
+
+// matches returns whether the hook is attached to this event of this monitor and alert
+func (hook Hook) matches(event string, vars map[string]string) bool {
+	return hook.Event == event && hook.selects(vars["MONITOR"], vars["ALERT"])
+}
+
+// selects returns whether the hook's monitor and alert filters let an alert of monitor with this key through
+// A per-device key such as disk_space:/var also passes a filter on its base name
+func (hook Hook) selects(monitor, key string) bool {
+	if hook.Monitor != "" && hook.Monitor != monitor {
+		return false
+	}
+	if hook.Alert != "" {
+		base, _, _ := strings.Cut(key, ":")
+		if hook.Alert != key && hook.Alert != base {
+			return false
+		}
+	}
+	return true
+}
+
+// run executes one hook command and records the outcome
+func run(hook Hook, event string, vars map[string]string, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", hook.Command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", hook.Command)
+	}
+	cmd.Env = append(os.Environ(), environment(event, vars)...)
+	// Children of the shell keep its output open after it is killed; stop waiting for them shortly after
+	cmd.WaitDelay = time.Second
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	result := Run{Event: event, Command: hook.Command, Started: time.Now()}
+	err := cmd.Run()
+	result.Duration = time.Since(result.Started)
+	if ctx.Err() == context.DeadlineExceeded {
+		err = fmt.Errorf("killed after %s", timeout)
+	}
+	if err != nil {
+		result.Error = err.Error()
+		if line, _, _ := strings.Cut(strings.TrimSpace(output.String()), "\n"); line != "" {
+			result.Error += ": " + line
+		}
+	}
+
+	current.mutex.Lock()
+	current.last = &result
+	current.mutex.Unlock()
+	writeLogLine(result)
+}
+
+// environment returns the variables passed to a hook, sorted by name
+func environment(event string, vars map[string]string) []string {
+	env := []string{
+		EnvPrefix + "EVENT=" + event,
+		EnvPrefix + "TIME=" + time.Now().Format(time.RFC3339),
+	}
+	if hostname, err := os.Hostname(); err == nil {
+		env = append(env, EnvPrefix+"HOSTNAME="+hostname)
+	}
+	for name, value := range vars {
+		env = append(env, EnvPrefix+name+"="+value)
+	}
+	sort.Strings(env)
+	return env
+}
+
+// writeLogLine appends one run to the hook log; failures are ignored so monitoring carries on
+func writeLogLine(result Run) {
+	if err := os.MkdirAll(filepath.Dir(LogPath), 0755); err != nil {
+		return
+	}
+	file, err := os.OpenFile(LogPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	status := "ok"
+	if result.Error != "" {
+		status = result.Error
+	}
+	fmt.Fprintf(file, "%s %s %q (%s): %s\n", result.Started.Format("2006-01-02 15:04:05"), result.Event,
+		result.Command, result.Duration.Round(time.Millisecond), status)
+}
+
+// Exported fires the export_completed hooks for a file a monitor has written
+func Exported(monitor, path string) {
+	Fire(EventExportCompleted, map[string]string{
+		"MONITOR": monitor,
+		"FILE":    path,
+		"FORMAT":  strings.TrimPrefix(filepath.Ext(path), "."),
+	})
+}
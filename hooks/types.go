@@ -0,0 +1,71 @@
+package hooks
+
+import (
+	"sync"
+	"time"
+)
+
+// Events a hook can be attached to
+const (
+	EventAlertRaised       = "alert_raised"       // An alert rose to Warning or Critical, or changed between them
+	EventAlertCleared      = "alert_cleared"      // A raised alert went back to Normal
+	EventExportCompleted   = "export_completed"   // An export file was written
+	EventMonitoringStarted = "monitoring_started" // A live monitor started
+	EventMonitoringStopped = "monitoring_stopped" // A live monitor stopped (Ctrl+C or switching to another monitor)
+	EventAlertDigest       = "alert_digest"       // The alerts raised and cleared during one digest window, sent together
+)
+
+// Events lists every event in the order they are documented
+var Events = []string{EventAlertRaised, EventAlertCleared, EventExportCompleted, EventMonitoringStarted, EventMonitoringStopped, EventAlertDigest}
+
+// EnvPrefix starts the name of every environment variable passed to a hook, e.g. SIMPLE_MONITOR_LEVEL
+const EnvPrefix = "SIMPLE_MONITOR_"
+
+// maxRunning caps the hooks running at once, so an alert storm cannot start an unbounded number of processes
+const maxRunning = 8
+
+// Hook runs a shell command when an event happens
+type Hook struct {
+	Event   string        `json:"event"`   // One of the Event constants
+	Command string        `json:"command"` // Shell command (sh -c, or cmd /C on Windows)
+	Monitor string        `json:"monitor"` // Only events of this monitor (cpu, memory, disk, network, process, events, quicktest), empty for all
+	Alert   string        `json:"alert"`   // Only this alert key, e.g. "disk_space" (a per-device key also matches its base name), empty for all
+	Timeout time.Duration `json:"timeout"` // How long the command may run before it is killed (0 uses the section's timeout)
+}
+
+// Config holds the hooks and how they are run
+type Config struct {
+	Commands     []Hook        `json:"commands"`      // Hooks in the order they are started
+	Timeout      time.Duration `json:"timeout"`       // Default time limit of one command
+	DigestWindow time.Duration `json:"digest_window"` // Batch alerts over this long into one alert_digest event instead of alert_raised/alert_cleared hooks (0 sends each alert on its own)
+}
+
+// Run is one finished hook command, as written to the hook log
+type Run struct {
+	Event    string        `json:"event"`    // Event that triggered it
+	Command  string        `json:"command"`  // Command that ran
+	Started  time.Time     `json:"started"`  // When it started
+	Duration time.Duration `json:"duration"` // How long it ran
+	Error    string        `json:"error"`    // Why it failed, with the first line of its output (empty on success)
+}
+
+// digestEntry is one alert change waiting in the digest
+type digestEntry struct {
+	Monitor  string // Monitor that raised the alert
+	Alert    string // Alert key
+	Level    string // Level it changed to: Warning, Critical or Normal when cleared
+	Previous string // Level it changed from at the first change in the window
+	Value    string // Value at the last change
+	Note     string // Note of the alert's rule
+	Changes  int    // How often the alert changed level during the window
+}
+
+// hookState holds the hooks shared by the whole program
+type hookState struct {
+	config  Config
+	running chan struct{}  // One slot per running command, up to maxRunning
+	last    *Run           // Last finished command, for the settings screen
+	digest  []*digestEntry // Alert changes since the digest window opened, in order of first change
+	sending *time.Timer    // Ends the open digest window (nil while no alert is waiting)
+	mutex   sync.Mutex
+}
@@ -0,0 +1,157 @@
+// Package idle detects when nobody is using the machine, so monitors can refresh less often
+package idle
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+)
+
+// Detector decides when the machine is idle so monitors can refresh less often
+// The machine is idle when nobody has touched the keyboard or mouse and system CPU usage
+// has stayed low for the configured time; if input time cannot be read, low activity alone counts
+type Detector struct {
+	mutex  sync.Mutex
+	config Config
+	state  State
+	tick   int
+}
+
+// DefaultConfig returns the standard idle settings:
+// idle after 5 minutes below 10% CPU, refreshing 5 times less often while idle
+func DefaultConfig() Config {
+	return Config{
+		Enabled:        true,
+		IdleAfter:      5 * time.Minute,
+		CPUThreshold:   10.0,
+		SlowdownFactor: 5,
+		CheckInterval:  10 * time.Second,
+	}
+}
+
+// NewDetector creates a new idle detector with the default configuration
+func NewDetector() *Detector {
+	return &Detector{config: DefaultConfig()}
+}
+
+// ShouldCollect reports whether the current refresh should collect data
+// While idle only every Nth refresh collects; otherwise every refresh does
+func (detector *Detector) ShouldCollect() bool {
+	detector.mutex.Lock()
+	defer detector.mutex.Unlock()
+
+	if !detector.config.Enabled {
+		detector.state.Idle = false
+		return true
+	}
+
+	if detector.state.CheckedAt.IsZero() || time.Since(detector.state.CheckedAt) >= detector.config.CheckInterval {
+		detector.check()
+	}
+
+	if !detector.state.Idle || detector.config.SlowdownFactor <= 1 {
+		detector.tick = 0
+		return true
+	}
+
+	detector.tick++
+	if detector.tick >= detector.config.SlowdownFactor {
+		detector.tick = 0
+		return true
+	}
+	return false
+}
+
+// check refreshes the input and activity readings and updates the idle state
+func (detector *Detector) check() {
+	now := time.Now()
+	state := &detector.state
+	state.CheckedAt = now
+
+	// Non-blocking sample: usage since the previous call
+	if percents, err := cpu.Percent(0, false); err == nil && len(percents) > 0 {
+		state.CPUPercent = percents[0]
+	}
+
+	userIdle, err := userIdleTime()
+	state.UserIdleKnown = err == nil
+	state.UserIdle = userIdle
+
+	if state.CPUPercent < detector.config.CPUThreshold {
+		if state.QuietSince.IsZero() {
+			state.QuietSince = now
+		}
+	} else {
+		state.QuietSince = time.Time{}
+	}
+
+	quiet := !state.QuietSince.IsZero() && now.Sub(state.QuietSince) >= detector.config.IdleAfter
+	if state.UserIdleKnown && state.UserIdle < detector.config.IdleAfter {
+		quiet = false
+	}
+
+	if quiet && !state.Idle {
+		state.Since = now
+	}
+	state.Idle = quiet
+}
+
+// IsIdle reports whether idle mode is active
+func (detector *Detector) IsIdle() bool {
+	detector.mutex.Lock()
+	defer detector.mutex.Unlock()
+
+	return detector.state.Idle
+}
+
+// State returns the most recent idle check
+func (detector *Detector) State() State {
+	detector.mutex.Lock()
+	defer detector.mutex.Unlock()
+
+	return detector.state
+}
+
+// Status returns a one-line description of idle mode for the live display
+// It returns an empty string while the machine is active
+func (detector *Detector) Status() string {
+	detector.mutex.Lock()
+	defer detector.mutex.Unlock()
+
+	if !detector.state.Idle {
+		return ""
+	}
+	return fmt.Sprintf("💤 Idle mode since %s: refreshing every %d updates, process scans paused",
+		detector.state.Since.Format("15:04:05"), detector.config.SlowdownFactor)
+}
+
+// SetEnabled enables or disables idle mode
+// Disabling it resumes full-speed refreshes immediately
+func (detector *Detector) SetEnabled(enabled bool) {
+	detector.mutex.Lock()
+	defer detector.mutex.Unlock()
+
+	detector.config.Enabled = enabled
+	if !enabled {
+		detector.state = State{}
+		detector.tick = 0
+	}
+}
+
+// GetConfig returns the current configuration
+func (detector *Detector) GetConfig() Config {
+	detector.mutex.Lock()
+	defer detector.mutex.Unlock()
+
+	return detector.config
+}
+
+// UpdateConfig updates the idle detection configuration
+func (detector *Detector) UpdateConfig(config Config) {
+	detector.mutex.Lock()
+	defer detector.mutex.Unlock()
+
+	detector.config = config
+}
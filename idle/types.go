@@ -0,0 +1,23 @@
+package idle
+
+import "time"
+
+// Config holds the idle detection settings shared by the live monitors
+type Config struct {
+	Enabled        bool          `json:"enabled"`         // Whether idle mode is enabled
+	IdleAfter      time.Duration `json:"idle_after"`      // How long without input and activity before entering idle mode
+	CPUThreshold   float64       `json:"cpu_threshold"`   // System CPU usage (%) below which the machine counts as quiet
+	SlowdownFactor int           `json:"slowdown_factor"` // Only every Nth refresh runs while idle
+	CheckInterval  time.Duration `json:"check_interval"`  // How often input and activity are re-checked
+}
+
+// State describes the most recent idle check
+type State struct {
+	Idle          bool          `json:"idle"`            // Whether idle mode is active
+	Since         time.Time     `json:"since"`           // When idle mode was entered
+	UserIdle      time.Duration `json:"user_idle"`       // Time since the last keyboard or mouse input
+	UserIdleKnown bool          `json:"user_idle_known"` // Whether input idle time could be read on this platform
+	CPUPercent    float64       `json:"cpu_percent"`     // System CPU usage at the last check
+	QuietSince    time.Time     `json:"quiet_since"`     // Start of the current low-activity stretch (zero when busy)
+	CheckedAt     time.Time     `json:"checked_at"`      // Time of the last check
+}
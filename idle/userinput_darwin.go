@@ -0,0 +1,32 @@
+//go:build darwin
+
+package idle
+
+import (
+	"errors"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// hidIdlePattern matches the HIDIdleTime property (nanoseconds) in ioreg output
+var hidIdlePattern = regexp.MustCompile(`"HIDIdleTime"\s*=\s*(\d+)`)
+
+// userIdleTime returns the time since the last keyboard or mouse input
+func userIdleTime() (time.Duration, error) {
+	output, err := exec.Command("ioreg", "-c", "IOHIDSystem", "-d", "4").Output()
+	if err != nil {
+		return 0, err
+	}
+
+	match := hidIdlePattern.FindSubmatch(output)
+	if match == nil {
+		return 0, errors.New("HIDIdleTime not reported")
+	}
+	nanoseconds, err := strconv.ParseInt(string(match[1]), 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(nanoseconds), nil
+}
@@ -0,0 +1,48 @@
+//go:build linux
+
+package idle
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// userIdleTime returns the time since the last keyboard or mouse input
+// xprintidle covers X11 desktops; otherwise the most recent terminal access time is used,
+// which tracks typing in consoles and SSH sessions
+func userIdleTime() (time.Duration, error) {
+	if output, err := exec.Command("xprintidle").Output(); err == nil {
+		if ms, err := strconv.ParseInt(strings.TrimSpace(string(output)), 10, 64); err == nil {
+			return time.Duration(ms) * time.Millisecond, nil
+		}
+	}
+
+	var latest time.Time
+	for _, pattern := range []string{"/dev/pts/[0-9]*", "/dev/tty[0-9]*"} {
+		paths, _ := filepath.Glob(pattern)
+		for _, path := range paths {
+			info, err := os.Stat(path)
+			if err != nil {
+				continue
+			}
+			stat, ok := info.Sys().(*syscall.Stat_t)
+			if !ok {
+				continue
+			}
+			accessed := time.Unix(int64(stat.Atim.Sec), int64(stat.Atim.Nsec))
+			if accessed.After(latest) {
+				latest = accessed
+			}
+		}
+	}
+	if latest.IsZero() {
+		return 0, errors.New("no input source available")
+	}
+	return time.Since(latest), nil
+}
@@ -0,0 +1,14 @@
+//go:build !linux && !darwin && !windows
+
+package idle
+
+import (
+	"errors"
+	"time"
+)
+
+// userIdleTime returns the time since the last keyboard or mouse input
+// Input idle time is not available here, so idle detection relies on system activity alone
+func userIdleTime() (time.Duration, error) {
+	return 0, errors.New("input idle time is not available on this platform")
+}
@@ -0,0 +1,36 @@
+//go:build windows
+
+package idle
+
+import (
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	user32               = windows.NewLazySystemDLL("user32.dll")
+	kernel32             = windows.NewLazySystemDLL("kernel32.dll")
+	procGetLastInputInfo = user32.NewProc("GetLastInputInfo")
+	procGetTickCount     = kernel32.NewProc("GetTickCount")
+)
+
+// lastInputInfo mirrors the Win32 LASTINPUTINFO structure
+type lastInputInfo struct {
+	cbSize uint32
+	dwTime uint32
+}
+
+// userIdleTime returns the time since the last keyboard or mouse input
+func userIdleTime() (time.Duration, error) {
+	info := lastInputInfo{cbSize: uint32(unsafe.Sizeof(lastInputInfo{}))}
+	result, _, err := procGetLastInputInfo.Call(uintptr(unsafe.Pointer(&info)))
+	if result == 0 {
+		return 0, err
+	}
+
+	// Both values are 32-bit millisecond tick counts, so the subtraction survives wrap-around
+	ticks, _, _ := procGetTickCount.Call()
+	return time.Duration(uint32(ticks)-info.dwTime) * time.Millisecond, nil
+}
@@ -0,0 +1,155 @@
+// Package instance keeps one simple-monitor instance per logs directory with a lock file
+package instance
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// LockFile is the name of the lock file inside the logs directory
+const LockFile = "simple-monitor.lock"
+
+// FeedDir is the subdirectory of the logs directory where the instance holding the lock
+// keeps the latest frame of each live monitor for viewers
+const FeedDir = "live"
+
+// settleTime is how long Acquire waits for an instance that has just taken the lock to write its owner
+const settleTime = 500 * time.Millisecond
+
+// ErrNotHeld is returned by ReadOwner when no running instance holds the lock
+var ErrNotHeld = errors.New("no running instance holds the lock")
+
+// errLocked is returned by lockFile while another instance holds a conflicting lock
+var errLocked = errors.New("lock is held")
+
+// Acquire takes the instance lock in dir
+// Only one instance at a time may write exports, history and recordings to a logs directory:
+// a second one would interleave lines in append-mode exports and double the collection load.
+// The lock is an advisory lock the operating system holds on the lock file (flock, or LockFileEx on
+// Windows) and drops when the instance exits, however it exits, so a lock file left behind is simply
+// taken over; no liveness check or removal can race with another instance starting at the same time
+func Acquire(dir string) (*Lock, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create logs directory: %w", err)
+	}
+
+	path := filepath.Join(dir, LockFile)
+	owner := currentOwner()
+	content, err := json.Marshal(owner)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode instance lock: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open instance lock: %w", err)
+	}
+	// A viewer checking for an instance holds a shared lock for a moment, so a refused lock is retried
+	// for as long as nobody holds it exclusively
+	deadline := time.Now().Add(settleTime)
+	for {
+		err := lockFile(file, true)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, errLocked) {
+			file.Close()
+			return nil, fmt.Errorf("failed to lock instance lock: %w", err)
+		}
+		holder, err := waitForOwner(dir)
+		if released(err) && time.Now().Before(deadline) {
+			time.Sleep(50 * time.Millisecond)
+			continue
+		}
+		file.Close()
+		// A holder that has not written its owner yet is still a running instance
+		return nil, &HeldError{Path: path, Owner: holder}
+	}
+
+	if err := file.Truncate(0); err == nil {
+		_, err = file.WriteAt(content, 0)
+	}
+	if err != nil {
+		unlockFile(file)
+		file.Close()
+		return nil, fmt.Errorf("failed to write instance lock: %w", err)
+	}
+	return &Lock{path: path, owner: owner, file: file}, nil
+}
+
+// Release gives up the lock; a nil lock releases nothing, so callers can release unconditionally
+// The file itself is kept: removing it would let an instance that opened it just before the removal and one
+// creating it anew both take a lock
+func (lock *Lock) Release() {
+	if lock == nil || lock.file == nil {
+		return
+	}
+	lock.file.Truncate(0)
+	unlockFile(lock.file)
+	lock.file.Close()
+	lock.file = nil
+}
+
+// ReadOwner reads the instance recorded in the lock file in dir
+// The error wraps ErrNotHeld when no running instance holds the lock
+func ReadOwner(dir string) (Owner, error) {
+	var owner Owner
+	path := filepath.Join(dir, LockFile)
+	file, err := os.Open(path)
+	if err != nil {
+		return owner, fmt.Errorf("failed to read instance lock: %w", err)
+	}
+	defer file.Close()
+
+	// A shared lock is only granted while no instance holds the lock
+	if err := lockFile(file, false); err == nil {
+		unlockFile(file)
+		return owner, fmt.Errorf("%w %s", ErrNotHeld, path)
+	} else if !errors.Is(err, errLocked) {
+		return owner, fmt.Errorf("failed to check instance lock: %w", err)
+	}
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return owner, fmt.Errorf("failed to read instance lock: %w", err)
+	}
+	if err := json.Unmarshal(content, &owner); err != nil {
+		return owner, fmt.Errorf("failed to decode instance lock: %w", err)
+	}
+	return owner, nil
+}
+
+// waitForOwner reads the lock file in dir, retrying for up to settleTime while it cannot be decoded:
+// an instance that has just taken the lock may not have written its owner yet
+func waitForOwner(dir string) (Owner, error) {
+	deadline := time.Now().Add(settleTime)
+	for {
+		owner, err := ReadOwner(dir)
+		if err == nil || released(err) || time.Now().After(deadline) {
+			return owner, err
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// released reports whether a ReadOwner error means nobody holds the lock any more
+func released(err error) bool {
+	return errors.Is(err, ErrNotHeld) || errors.Is(err, os.ErrNotExist)
+}
+
+// currentOwner describes this process
+func currentOwner() Owner {
+	owner := Owner{PID: os.Getpid(), Started: time.Now().Truncate(time.Second)}
+	owner.Hostname, _ = os.Hostname()
+	if proc, err := process.NewProcess(int32(owner.PID)); err == nil {
+		owner.CreatedAt, _ = proc.CreateTime()
+	}
+	return owner
+}
@@ -0,0 +1,106 @@
+package instance
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestAcquire(t *testing.T) {
+	dir := t.TempDir()
+
+	lock, err := Acquire(dir)
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	owner, err := ReadOwner(dir)
+	if err != nil || owner.PID != os.Getpid() {
+		t.Fatalf("ReadOwner() = %+v, %v, want PID %d", owner, err, os.Getpid())
+	}
+
+	var held *HeldError
+	if _, err := Acquire(dir); !errors.As(err, &held) || held.Owner.PID != os.Getpid() {
+		t.Fatalf("second Acquire() error = %v, want a HeldError naming PID %d", err, os.Getpid())
+	}
+
+	lock.Release()
+	lock.Release()
+	if _, err := ReadOwner(dir); !errors.Is(err, ErrNotHeld) {
+		t.Errorf("ReadOwner() after Release error = %v, want ErrNotHeld", err)
+	}
+	lock, err = Acquire(dir)
+	if err != nil {
+		t.Fatalf("Acquire() after Release error = %v", err)
+	}
+	lock.Release()
+}
+
+func TestAcquireTakesOverLeftoverFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "owner that exited", content: `{"pid":999999,"hostname":"localhost","created_at":1}`},
+		{name: "owner on another host", content: `{"pid":1,"hostname":"elsewhere"}`},
+		{name: "undecodable", content: `{"pid":`},
+		{name: "empty", content: ``},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, LockFile), []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := ReadOwner(dir); !errors.Is(err, ErrNotHeld) {
+				t.Errorf("ReadOwner() error = %v, want ErrNotHeld", err)
+			}
+			lock, err := Acquire(dir)
+			if err != nil {
+				t.Fatalf("Acquire() error = %v", err)
+			}
+			defer lock.Release()
+			if owner, err := ReadOwner(dir); err != nil || owner.PID != os.Getpid() {
+				t.Errorf("ReadOwner() = %+v, %v, want PID %d", owner, err, os.Getpid())
+			}
+		})
+	}
+}
+
+// TestAcquireConcurrently starts several instances at once on a leftover lock file: exactly one may win
+func TestAcquireConcurrently(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, LockFile), []byte(`{"pid":999999}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		wg    sync.WaitGroup
+		mutex sync.Mutex
+		locks []*Lock
+	)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			lock, err := Acquire(dir)
+			var held *HeldError
+			if err != nil && !errors.As(err, &held) {
+				t.Errorf("Acquire() error = %v, want the lock or a HeldError", err)
+			}
+			if lock != nil {
+				mutex.Lock()
+				locks = append(locks, lock)
+				mutex.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	for _, lock := range locks {
+		lock.Release()
+	}
+	if len(locks) != 1 {
+		t.Errorf("%d of 8 concurrent Acquire() calls took the lock, want 1", len(locks))
+	}
+}
@@ -0,0 +1,42 @@
+package instance
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// Owner describes the instance holding the lock, as written to the lock file
+type Owner struct {
+	PID       int       `json:"pid"`        // Process ID of the instance
+	Hostname  string    `json:"hostname"`   // Host it runs on; a shared logs directory can be seen from several hosts
+	CreatedAt int64     `json:"created_at"` // Process creation time in milliseconds since the epoch, to tell a reused PID apart
+	Started   time.Time `json:"started"`    // When the instance took the lock
+}
+
+// Lock is the instance lock held by this process
+type Lock struct {
+	path  string
+	owner Owner
+	file  *os.File // Open lock file; the lock lasts as long as it stays open
+}
+
+// HeldError is returned by Acquire while another running instance holds the lock
+type HeldError struct {
+	Path  string // Lock file
+	Owner Owner  // Instance holding it
+}
+
+// Error describes who holds the lock
+func (err *HeldError) Error() string {
+	// An instance that has only just taken the lock may not have written its owner yet
+	if err.Owner.PID == 0 {
+		return fmt.Sprintf("another simple-monitor instance holds %s", err.Path)
+	}
+	where := ""
+	if hostname, _ := os.Hostname(); err.Owner.Hostname != "" && err.Owner.Hostname != hostname {
+		where = " on " + err.Owner.Hostname
+	}
+	return fmt.Sprintf("another simple-monitor instance (PID %d%s, running since %s) holds %s",
+		err.Owner.PID, where, err.Owner.Started.Local().Format("2006-01-02 15:04:05"), err.Path)
+}
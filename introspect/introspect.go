@@ -0,0 +1,206 @@
+// Package introspect reports simple-monitor's own Go runtime stats, dumps goroutines and serves
+// the optional debug endpoint
+package introspect
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"path/filepath"
+	"runtime"
+	rpprof "runtime/pprof"
+	"strings"
+	"time"
+)
+
+// DefaultAddress is where the debug endpoint listens unless another loopback address is given
+const DefaultAddress = "127.0.0.1:6060"
+
+// TokenParameter is the query parameter a request can carry the endpoint's token in, for go tool pprof and browsers
+const TokenParameter = "token"
+
+// tokenCookie keeps a browser that opened a URL with the token authorized while it follows the pprof index links
+const tokenCookie = "simple_monitor_debug_token"
+
+// recentPauses is how many of the latest GC pauses Read reports
+const recentPauses = 16
+
+// started is when the program started, for Stats.Uptime
+var started = time.Now()
+
+// Read takes a reading of the Go runtime: goroutines, heap and GC pauses
+// It briefly stops the world (runtime.ReadMemStats), which is fine for an on-demand view
+func Read() Stats {
+	var memory runtime.MemStats
+	runtime.ReadMemStats(&memory)
+
+	stats := Stats{
+		Time:         time.Now(),
+		Uptime:       time.Since(started),
+		GoVersion:    runtime.Version(),
+		GOMAXPROCS:   runtime.GOMAXPROCS(0),
+		Goroutines:   runtime.NumGoroutine(),
+		HeapAlloc:    memory.HeapAlloc,
+		HeapSys:      memory.HeapSys,
+		HeapObjects:  memory.HeapObjects,
+		TotalAlloc:   memory.TotalAlloc,
+		Sys:          memory.Sys,
+		NumGC:        memory.NumGC,
+		PauseTotal:   time.Duration(memory.PauseTotalNs),
+		GCCPUPercent: memory.GCCPUFraction * 100,
+	}
+	if memory.LastGC > 0 {
+		stats.LastGC = time.Unix(0, int64(memory.LastGC))
+	}
+
+	// PauseNs is a circular buffer; the most recent pause is at (NumGC+255)%256
+	count := min(int(memory.NumGC), recentPauses)
+	for i := 0; i < count; i++ {
+		pause := time.Duration(memory.PauseNs[(int(memory.NumGC)-1-i+len(memory.PauseNs))%len(memory.PauseNs)])
+		stats.RecentPauses = append(stats.RecentPauses, pause)
+		stats.MaxPause = max(stats.MaxPause, pause)
+	}
+	return stats
+}
+
+// DumpGoroutines writes the stack of every goroutine to a timestamped file in dir and returns its path
+// This is the file to attach to a report of the monitor hanging
+func DumpGoroutines(dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create debug directory: %w", err)
+	}
+	path := filepath.Join(dir, fmt.Sprintf("goroutines_%s.txt", time.Now().Format("2006-01-02_15-04-05")))
+	file, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to create goroutine dump: %w", err)
+	}
+	defer file.Close()
+
+	if err := rpprof.Lookup("goroutine").WriteTo(file, 2); err != nil {
+		return "", fmt.Errorf("failed to write goroutine dump: %w", err)
+	}
+	return path, nil
+}
+
+// NewServer creates a stopped debug endpoint
+func NewServer() *Server {
+	return &Server{}
+}
+
+// Start serves /debug/pprof/ and /debug/runtime on address until Stop is called
+// Only loopback addresses are accepted: profiles expose memory contents and must not reach the network. Other
+// users of the machine can reach loopback too, so every request must also carry the token Start generates
+func (server *Server) Start(address string) error {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	if server.server != nil {
+		return fmt.Errorf("debug endpoint already running on %s", server.address)
+	}
+
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("invalid debug endpoint address %q: %w", address, err)
+	}
+	if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		return fmt.Errorf("debug endpoint must listen on a loopback address, not %s", host)
+	}
+
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return fmt.Errorf("failed to generate debug endpoint token: %w", err)
+	}
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return fmt.Errorf("failed to start debug endpoint: %w", err)
+	}
+
+	// A private mux keeps the handlers off http.DefaultServeMux
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.HandleFunc("/debug/runtime", serveStats)
+
+	server.address = listener.Addr().String()
+	server.token = hex.EncodeToString(random)
+	server.server = &http.Server{Handler: authorize(server.token, mux), ReadHeaderTimeout: 10 * time.Second}
+	go func(httpServer *http.Server) {
+		if err := httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Printf("\n⚠️  Debug endpoint stopped: %v\n", err)
+		}
+	}(server.server)
+	return nil
+}
+
+// Stop shuts the endpoint down; stopping a stopped server does nothing
+func (server *Server) Stop() error {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	if server.server == nil {
+		return nil
+	}
+	err := server.server.Close()
+	server.server = nil
+	server.address, server.token = "", ""
+	if err != nil {
+		return fmt.Errorf("failed to stop debug endpoint: %w", err)
+	}
+	return nil
+}
+
+// Address returns where the endpoint listens, or "" while it is stopped
+func (server *Server) Address() string {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	return server.address
+}
+
+// Token returns the secret requests must carry, or "" while the endpoint is stopped
+// It is sent as "Authorization: Bearer <token>" or as the token query parameter
+func (server *Server) Token() string {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	return server.token
+}
+
+// authorize passes on the requests that carry token in the Authorization header, the token query parameter or
+// the cookie set when the parameter was accepted, and refuses the others
+func authorize(token string, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		given, fromQuery := "", false
+		if bearer, ok := strings.CutPrefix(request.Header.Get("Authorization"), "Bearer "); ok {
+			given = bearer
+		} else if parameter := request.URL.Query().Get(TokenParameter); parameter != "" {
+			given, fromQuery = parameter, true
+		} else if cookie, err := request.Cookie(tokenCookie); err == nil {
+			given = cookie.Value
+		}
+		if subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			http.Error(writer, "missing or wrong debug endpoint token", http.StatusUnauthorized)
+			return
+		}
+
+		if fromQuery {
+			http.SetCookie(writer, &http.Cookie{Name: tokenCookie, Value: token, Path: "/debug/",
+				HttpOnly: true, SameSite: http.SameSiteStrictMode})
+		}
+		handler.ServeHTTP(writer, request)
+	})
+}
+
+// serveStats answers /debug/runtime with the current Stats as JSON
+func serveStats(writer http.ResponseWriter, _ *http.Request) {
+	writer.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(Read())
+}
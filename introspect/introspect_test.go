@@ -0,0 +1,84 @@
+package introspect
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAuthorize(t *testing.T) {
+	const token = "0123456789abcdef"
+	handler := authorize(token, http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		writer.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name       string
+		target     string
+		header     string
+		cookie     string
+		wantStatus int
+		wantCookie bool
+	}{
+		{name: "no token", target: "/debug/pprof/heap", wantStatus: http.StatusUnauthorized},
+		{name: "bearer", target: "/debug/pprof/heap", header: "Bearer " + token, wantStatus: http.StatusOK},
+		{name: "wrong bearer", target: "/debug/pprof/heap", header: "Bearer nope", wantStatus: http.StatusUnauthorized},
+		{name: "query", target: "/debug/pprof/?token=" + token, wantStatus: http.StatusOK, wantCookie: true},
+		{name: "wrong query", target: "/debug/runtime?token=" + token + "0", wantStatus: http.StatusUnauthorized},
+		{name: "cookie", target: "/debug/pprof/heap?debug=1", cookie: token, wantStatus: http.StatusOK},
+		{name: "wrong cookie", target: "/debug/pprof/heap", cookie: "nope", wantStatus: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		request := httptest.NewRequest(http.MethodGet, tt.target, nil)
+		if tt.header != "" {
+			request.Header.Set("Authorization", tt.header)
+		}
+		if tt.cookie != "" {
+			request.AddCookie(&http.Cookie{Name: tokenCookie, Value: tt.cookie})
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+
+		if recorder.Code != tt.wantStatus {
+			t.Errorf("%s: status = %d, want %d", tt.name, recorder.Code, tt.wantStatus)
+		}
+		if gotCookie := len(recorder.Result().Cookies()) > 0; gotCookie != tt.wantCookie {
+			t.Errorf("%s: cookie set = %v, want %v", tt.name, gotCookie, tt.wantCookie)
+		}
+	}
+}
+
+func TestServerRequiresToken(t *testing.T) {
+	server := NewServer()
+	if err := server.Start("127.0.0.1:0"); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	t.Cleanup(func() { server.Stop() })
+	if server.Token() == "" {
+		t.Fatal("Token() of a running endpoint is empty")
+	}
+
+	for _, tt := range []struct {
+		query      string
+		wantStatus int
+	}{
+		{query: "", wantStatus: http.StatusUnauthorized},
+		{query: "?token=" + server.Token(), wantStatus: http.StatusOK},
+	} {
+		response, err := http.Get("http://" + server.Address() + "/debug/runtime" + tt.query)
+		if err != nil {
+			t.Fatalf("GET /debug/runtime error = %v", err)
+		}
+		response.Body.Close()
+		if response.StatusCode != tt.wantStatus {
+			t.Errorf("GET /debug/runtime%s status = %d, want %d", tt.query, response.StatusCode, tt.wantStatus)
+		}
+	}
+
+	if err := server.Stop(); err != nil || server.Token() != "" || server.Address() != "" {
+		t.Errorf("Stop() = %v, token %q, address %q, want nil and both empty", err, server.Token(), server.Address())
+	}
+	if err := server.Start("0.0.0.0:0"); err == nil {
+		t.Error("Start() on a non-loopback address succeeded, want an error")
+	}
+}
@@ -0,0 +1,35 @@
+package introspect
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Stats is a reading of the monitor's own Go runtime
+type Stats struct {
+	Time         time.Time       `json:"time"`           // When the reading was taken
+	Uptime       time.Duration   `json:"uptime"`         // Time since the program started
+	GoVersion    string          `json:"go_version"`     // Go release the binary was built with
+	GOMAXPROCS   int             `json:"gomaxprocs"`     // Number of OS threads running Go code at once
+	Goroutines   int             `json:"goroutines"`     // Goroutines currently alive
+	HeapAlloc    uint64          `json:"heap_alloc"`     // Bytes of live heap objects
+	HeapSys      uint64          `json:"heap_sys"`       // Bytes of heap memory obtained from the OS
+	HeapObjects  uint64          `json:"heap_objects"`   // Number of live heap objects
+	TotalAlloc   uint64          `json:"total_alloc"`    // Bytes allocated since start, including freed objects
+	Sys          uint64          `json:"sys"`            // Total bytes obtained from the OS
+	NumGC        uint32          `json:"num_gc"`         // Completed GC cycles
+	LastGC       time.Time       `json:"last_gc"`        // When the last GC finished (zero before the first)
+	PauseTotal   time.Duration   `json:"pause_total"`    // Sum of all GC stop-the-world pauses
+	RecentPauses []time.Duration `json:"recent_pauses"`  // Up to the last 16 GC pauses, newest first
+	MaxPause     time.Duration   `json:"max_pause"`      // Longest of the recent pauses
+	GCCPUPercent float64         `json:"gc_cpu_percent"` // Share of available CPU time used by the GC since start
+}
+
+// Server is the debug HTTP endpoint serving pprof profiles and runtime stats
+type Server struct {
+	address string
+	token   string // Secret every request must carry, generated by Start
+	server  *http.Server
+	mutex   sync.Mutex
+}
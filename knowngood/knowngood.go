@@ -0,0 +1,258 @@
+// Package knowngood saves a "known good" reference of what a system runs, listens on and mounts, and
+// compares the system with it later, e.g. to verify a server after maintenance
+package knowngood
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/ahmadreza-log/simple-monitor/snapshot"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// File is the name of the known-good state inside the logs directory
+const File = "known_good.json"
+
+// Path is where the known-good state is saved by default
+var Path = filepath.Join("logs", File)
+
+// Deviation categories, in the order Compare lists them
+const (
+	CategoryProcesses = "processes"
+	CategoryPorts     = "ports"
+	CategoryDisks     = "disks"
+)
+
+// Kinds of change a deviation reports
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+	ChangeChanged = "changed"
+)
+
+// resizeTolerance is the share a filesystem's size may change by before it is reported, since some
+// filesystems (btrfs, ZFS, tmpfs) report sizes that drift slightly
+const resizeTolerance = 0.01
+
+// Capture collects the current state from the disk, network and process monitors, which must all be set
+// The process monitor's filters and usage minimums decide which processes are recorded
+func Capture(monitors snapshot.Monitors) (*State, error) {
+	if monitors.Disk == nil || monitors.Network == nil || monitors.Process == nil {
+		return nil, fmt.Errorf("the disk, network and process monitors are needed")
+	}
+
+	state := &State{Time: time.Now()}
+	state.Hostname, _ = os.Hostname()
+
+	processes, err := monitors.Process.Collect()
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect processes: %w", err)
+	}
+	names := make(map[string]bool)
+	for _, process := range processes.ProcessInfos {
+		names[processName(process.Name)] = true
+	}
+	for name := range names {
+		state.Processes = append(state.Processes, name)
+	}
+	sort.Strings(state.Processes)
+
+	listening, err := monitors.Network.ListListeningPorts()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list listening ports: %w", err)
+	}
+	for _, port := range listening {
+		state.Ports = append(state.Ports, Port{
+			Protocol: port.Protocol,
+			Address:  port.Address,
+			Port:     port.Port,
+			Process:  port.ProcessName,
+		})
+	}
+
+	disks, err := monitors.Disk.Collect()
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect disks: %w", err)
+	}
+	for _, partition := range disks.Partitions {
+		if partition.Excluded {
+			continue
+		}
+		state.Partitions = append(state.Partitions, Partition{
+			Mountpoint: partition.Mountpoint,
+			Device:     partition.Device,
+			Fstype:     partition.Fstype,
+			Total:      partition.Total,
+		})
+	}
+	sort.Slice(state.Partitions, func(i, j int) bool {
+		return state.Partitions[i].Mountpoint < state.Partitions[j].Mountpoint
+	})
+	return state, nil
+}
+
+// processName returns the name a process is compared by
+// Kernel threads carry their CPU or queue after a slash (kworker/3:1H, ksoftirqd/0), which differs from boot to boot
+func processName(name string) string {
+	if base, _, found := strings.Cut(name, "/"); found && base != "" {
+		return base
+	}
+	return name
+}
+
+// Save writes state to path as indented JSON, creating its directory
+func Save(path string, state *State) error {
+	content, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode the known-good state: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, append(content, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// Load reads a state saved by Save; the error wraps os.ErrNotExist when none was saved at path
+func Load(path string) (*State, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var state State
+	if err := json.Unmarshal(content, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &state, nil
+}
+
+// Compare lists how current deviates from the known-good reference: processes that were not running
+// or are gone, ports that were opened, closed or taken over by another process, and filesystems that
+// were mounted, unmounted, or changed device, type or size
+func Compare(reference, current *State) []Deviation {
+	var deviations []Deviation
+
+	referenceProcesses := make(map[string]bool, len(reference.Processes))
+	for _, name := range reference.Processes {
+		referenceProcesses[name] = true
+	}
+	currentProcesses := make(map[string]bool, len(current.Processes))
+	for _, name := range current.Processes {
+		currentProcesses[name] = true
+		if !referenceProcesses[name] {
+			deviations = append(deviations, Deviation{Category: CategoryProcesses, Change: ChangeAdded, Item: name})
+		}
+	}
+	for _, name := range reference.Processes {
+		if !currentProcesses[name] {
+			deviations = append(deviations, Deviation{Category: CategoryProcesses, Change: ChangeRemoved, Item: name})
+		}
+	}
+
+	referencePorts := make(map[string]Port, len(reference.Ports))
+	for _, port := range reference.Ports {
+		referencePorts[port.socket()] = port
+	}
+	currentPorts := make(map[string]bool, len(current.Ports))
+	for _, port := range current.Ports {
+		socket := port.socket()
+		currentPorts[socket] = true
+		previous, known := referencePorts[socket]
+		switch {
+		case !known:
+			deviations = append(deviations, Deviation{Category: CategoryPorts, Change: ChangeAdded, Item: socket, Detail: port.Process})
+		case previous.Process != port.Process:
+			deviations = append(deviations, Deviation{Category: CategoryPorts, Change: ChangeChanged, Item: socket,
+				Detail: previous.Process + " → " + port.Process})
+		}
+	}
+	for _, port := range reference.Ports {
+		if socket := port.socket(); !currentPorts[socket] {
+			deviations = append(deviations, Deviation{Category: CategoryPorts, Change: ChangeRemoved, Item: socket, Detail: port.Process})
+		}
+	}
+
+	referencePartitions := make(map[string]Partition, len(reference.Partitions))
+	for _, partition := range reference.Partitions {
+		referencePartitions[partition.Mountpoint] = partition
+	}
+	currentPartitions := make(map[string]bool, len(current.Partitions))
+	for _, partition := range current.Partitions {
+		currentPartitions[partition.Mountpoint] = true
+		previous, known := referencePartitions[partition.Mountpoint]
+		if !known {
+			deviations = append(deviations, Deviation{Category: CategoryDisks, Change: ChangeAdded, Item: partition.Mountpoint,
+				Detail: partition.Device + " " + partition.Fstype})
+			continue
+		}
+		if changes := partitionChanges(previous, partition); len(changes) > 0 {
+			deviations = append(deviations, Deviation{Category: CategoryDisks, Change: ChangeChanged, Item: partition.Mountpoint,
+				Detail: strings.Join(changes, ", ")})
+		}
+	}
+	for _, partition := range reference.Partitions {
+		if !currentPartitions[partition.Mountpoint] {
+			deviations = append(deviations, Deviation{Category: CategoryDisks, Change: ChangeRemoved, Item: partition.Mountpoint,
+				Detail: partition.Device + " " + partition.Fstype})
+		}
+	}
+
+	return deviations
+}
+
+// socket identifies a port by what clients connect to, e.g. "TCP 0.0.0.0:22"
+func (port Port) socket() string {
+	address := port.Address
+	if strings.Contains(address, ":") {
+		address = "[" + address + "]"
+	}
+	return fmt.Sprintf("%s %s:%d", port.Protocol, address, port.Port)
+}
+
+// partitionChanges describes how a mounted filesystem differs from its known-good state
+func partitionChanges(previous, current Partition) []string {
+	var changes []string
+	if previous.Device != current.Device {
+		changes = append(changes, "device "+previous.Device+" → "+current.Device)
+	}
+	if previous.Fstype != current.Fstype {
+		changes = append(changes, "type "+previous.Fstype+" → "+current.Fstype)
+	}
+	if previous.Total > 0 && math.Abs(float64(current.Total)-float64(previous.Total))/float64(previous.Total) > resizeTolerance {
+		changes = append(changes, "size "+formatBytes(previous.Total)+" → "+formatBytes(current.Total))
+	}
+	return changes
+}
+
+// formatBytes formats a size in binary units, e.g. "931.5 GB"
+func formatBytes(bytes uint64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
+
+// String describes a deviation on one line, e.g. "+ TCP 0.0.0.0:8080 (node)" or "~ /data: type ext4 → xfs"
+func (deviation Deviation) String() string {
+	marks := map[string]string{ChangeAdded: "+", ChangeRemoved: "-", ChangeChanged: "~"}
+	switch {
+	case deviation.Detail == "":
+		return marks[deviation.Change] + " " + deviation.Item
+	case deviation.Change == ChangeChanged:
+		return marks[deviation.Change] + " " + deviation.Item + ": " + deviation.Detail
+	default:
+		return marks[deviation.Change] + " " + deviation.Item + " (" + deviation.Detail + ")"
+	}
+}
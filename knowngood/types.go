@@ -0,0 +1,37 @@
+package knowngood
+
+import "time"
+
+// State is the part of a system snapshot a known-good reference keeps: what runs, listens and is mounted,
+// without the usage figures that change on every refresh
+type State struct {
+	Time       time.Time   `json:"time"`       // When the state was captured
+	Hostname   string      `json:"hostname"`   // Host the state was captured on
+	Processes  []string    `json:"processes"`  // Distinct process names, sorted; kernel threads are listed once without their CPU suffix
+	Ports      []Port      `json:"ports"`      // Listening sockets, sorted by protocol, port and address
+	Partitions []Partition `json:"partitions"` // Mounted filesystems that are not excluded, sorted by mountpoint
+}
+
+// Port is one listening socket
+type Port struct {
+	Protocol string `json:"protocol"` // TCP or UDP
+	Address  string `json:"address"`  // Local address the socket is bound to
+	Port     uint32 `json:"port"`     // Local port
+	Process  string `json:"process"`  // Name of the process listening on it
+}
+
+// Partition is one mounted filesystem
+type Partition struct {
+	Mountpoint string `json:"mountpoint"` // Mount point, e.g. / or /var
+	Device     string `json:"device"`     // Device mounted there, e.g. /dev/sda1
+	Fstype     string `json:"fstype"`     // File system type, e.g. ext4
+	Total      uint64 `json:"total"`      // Size in bytes
+}
+
+// Deviation is one difference between the current state and the known-good one
+type Deviation struct {
+	Category string `json:"category"` // processes, ports or disks
+	Change   string `json:"change"`   // added, removed or changed
+	Item     string `json:"item"`     // Process name, socket such as "TCP 0.0.0.0:22" or mountpoint
+	Detail   string `json:"detail"`   // What changed or who listens, e.g. "ext4 → xfs" or "sshd"
+}
@@ -0,0 +1,124 @@
+// Package listening finds the network ports processes listen on, so process tables can show which
+// processes are network-facing
+package listening
+
+import (
+	"fmt"
+	"github.com/ahmadreza-log/simple-monitor/provider"
+	"github.com/shirou/gopsutil/v3/net"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ColumnWidth is the width of the ports column in process tables
+const ColumnWidth = 14
+
+// Socket types of gopsutil's ConnectionStat.Type
+const (
+	socketStream   = 1 // TCP
+	socketDatagram = 2 // UDP
+)
+
+// Port is one port a process listens on
+type Port struct {
+	Protocol string // TCP or UDP
+	Number   uint32 // Local port
+}
+
+// String returns the port as listed in process tables: the number alone for TCP, "53/udp" for UDP
+func (port Port) String() string {
+	if port.Protocol == "UDP" {
+		return fmt.Sprintf("%d/udp", port.Number)
+	}
+	return strconv.FormatUint(uint64(port.Number), 10)
+}
+
+// IsListening returns whether a socket accepts connections: a TCP socket in the LISTEN state or a bound,
+// unconnected UDP socket
+func IsListening(conn net.ConnectionStat) bool {
+	switch conn.Type {
+	case socketStream:
+		return conn.Status == "LISTEN"
+	case socketDatagram:
+		return conn.Raddr.Port == 0 && conn.Laddr.Port != 0
+	}
+	return false
+}
+
+// ByPID returns the ports each process listens on, keyed by PID, from one query of the open sockets
+// A port bound on several addresses (0.0.0.0 and ::, or per-CPU sockets) is listed once, and the ports of
+// a process are sorted TCP first, then by number
+func ByPID(source provider.NetProvider) (map[int32][]Port, error) {
+	connections, err := source.Connections("inet")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get network connections: %w", err)
+	}
+
+	seen := make(map[int32]map[Port]bool)
+	ports := make(map[int32][]Port)
+	for _, conn := range connections {
+		if conn.Pid <= 0 || !IsListening(conn) {
+			continue
+		}
+		port := Port{Protocol: "TCP", Number: conn.Laddr.Port}
+		if conn.Type == socketDatagram {
+			port.Protocol = "UDP"
+		}
+		if seen[conn.Pid] == nil {
+			seen[conn.Pid] = make(map[Port]bool)
+		}
+		if !seen[conn.Pid][port] {
+			seen[conn.Pid][port] = true
+			ports[conn.Pid] = append(ports[conn.Pid], port)
+		}
+	}
+
+	for _, list := range ports {
+		Sort(list)
+	}
+	return ports, nil
+}
+
+// Sort orders ports TCP first, then by number
+func Sort(ports []Port) {
+	sort.Slice(ports, func(i, j int) bool {
+		if ports[i].Protocol != ports[j].Protocol {
+			return ports[i].Protocol < ports[j].Protocol
+		}
+		return ports[i].Number < ports[j].Number
+	})
+}
+
+// Strings returns ports as listed in process tables, or nil when there are none
+func Strings(ports []Port) []string {
+	if len(ports) == 0 {
+		return nil
+	}
+	names := make([]string, len(ports))
+	for i, port := range ports {
+		names[i] = port.String()
+	}
+	return names
+}
+
+// Compact joins ports into a column at most width characters wide, e.g. "22,443,53/udp"; ports that do
+// not fit are counted instead, as in "22,443,+3", and a process without ports is shown as "-"
+func Compact(ports []string, width int) string {
+	if len(ports) == 0 {
+		return "-"
+	}
+	for i := range ports {
+		column := strings.Join(ports[:i+1], ",")
+		left := len(ports) - i - 1
+		if left == 0 && len(column) <= width || left > 0 && len(column)+len(fmt.Sprintf(",+%d", left)) <= width {
+			continue
+		}
+		// The ports before this one fit together with the count of the rest
+		if i == 0 {
+			return fmt.Sprintf("+%d", len(ports))
+		}
+		return fmt.Sprintf("%s,+%d", strings.Join(ports[:i], ","), len(ports)-i)
+	}
+	return strings.Join(ports, ",")
+}
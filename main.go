@@ -1,16 +1,56 @@
+// Simple Monitor is a terminal system monitor: live CPU, memory, disk, network, process and system event
+// monitors with alerts, exports, history and a health score, driven from an interactive menu
+// It also runs headless (simple-monitor daemon) and as one-shot commands such as check, status and prune;
+// run it with an unknown command for the list
 package main
 
 import (
 	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"github.com/ahmadreza-log/simple-monitor/alert"
+	"github.com/ahmadreza-log/simple-monitor/attach"
+	"github.com/ahmadreza-log/simple-monitor/benchmark"
+	"github.com/ahmadreza-log/simple-monitor/config"
+	"github.com/ahmadreza-log/simple-monitor/cpumonitor"
+	"github.com/ahmadreza-log/simple-monitor/diskmonitor"
+	"github.com/ahmadreza-log/simple-monitor/doctor"
+	"github.com/ahmadreza-log/simple-monitor/eventmonitor"
+	"github.com/ahmadreza-log/simple-monitor/fixtures"
+	"github.com/ahmadreza-log/simple-monitor/healthscore"
+	"github.com/ahmadreza-log/simple-monitor/historystore"
+	"github.com/ahmadreza-log/simple-monitor/hooks"
+	"github.com/ahmadreza-log/simple-monitor/instance"
+	"github.com/ahmadreza-log/simple-monitor/introspect"
+	"github.com/ahmadreza-log/simple-monitor/knowngood"
+	"github.com/ahmadreza-log/simple-monitor/memorymonitor"
+	"github.com/ahmadreza-log/simple-monitor/networkmonitor"
+	"github.com/ahmadreza-log/simple-monitor/overlay"
+	"github.com/ahmadreza-log/simple-monitor/privhelper"
+	"github.com/ahmadreza-log/simple-monitor/processmonitor"
+	"github.com/ahmadreza-log/simple-monitor/protoexport"
+	"github.com/ahmadreza-log/simple-monitor/provider"
+	"github.com/ahmadreza-log/simple-monitor/quicktest"
+	"github.com/ahmadreza-log/simple-monitor/recording"
+	"github.com/ahmadreza-log/simple-monitor/redact"
+	"github.com/ahmadreza-log/simple-monitor/retention"
+	"github.com/ahmadreza-log/simple-monitor/snapshot"
+	"github.com/ahmadreza-log/simple-monitor/stream"
+	"github.com/ahmadreza-log/simple-monitor/systeminfo"
+	"github.com/ahmadreza-log/simple-monitor/terminal"
+	"github.com/ahmadreza-log/simple-monitor/titlebar"
+	"io"
+	"maps"
+	"math"
+	"net"
 	"os"
 	"os/signal"
-	"simple-monitor/cpumonitor"
-	"simple-monitor/diskmonitor"
-	"simple-monitor/memorymonitor"
-	"simple-monitor/networkmonitor"
-	"simple-monitor/processmonitor"
-	"simple-monitor/systeminfo"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 	"syscall"
@@ -27,13 +67,14 @@ type MainMenuOptions struct {
 
 // MonitoringOptions represents the monitoring submenu options
 type MonitoringOptions struct {
-	SystemInfo bool
-	CPU        bool
-	Memory     bool
-	Disk       bool
-	Network    bool
-	Processes  bool
-	Back       bool
+	SystemInfo   bool
+	CPU          bool
+	Memory       bool
+	Disk         bool
+	Network      bool
+	Processes    bool
+	SystemEvents bool
+	Back         bool
 }
 
 // displayMainMenu shows the main menu to the user
@@ -58,10 +99,11 @@ func displayMonitoringMenu() {
 	fmt.Println("4. Disk Monitor")
 	fmt.Println("5. Network Monitor")
 	fmt.Println("6. Process Monitor")
-	fmt.Println("7. Quick Test (All Monitors)")
-	fmt.Println("8. Back to Main Menu")
+	fmt.Println("7. System Events")
+	fmt.Println("8. Quick Test (All Monitors)")
+	fmt.Println("9. Back to Main Menu")
 	fmt.Println(strings.Repeat("-", 30))
-	fmt.Print("Select option (1-8): ")
+	fmt.Print("Select option (1-9): ")
 }
 
 // getUserChoice gets user input and validates it for main menu
@@ -100,6 +142,10 @@ func getUserChoice(maxOptions int) int {
 
 		case <-sigChan:
 			fmt.Println("\n\n👋 Goodbye! Thank you for using Simple Monitor.")
+			titleUpdater.Stop()
+			terminal.RestoreOutput()
+			privhelper.Use(nil)
+			instanceLock.Release()
 			os.Exit(0)
 		}
 	}
@@ -125,6 +171,10 @@ func handleMainMenuChoice(choice int) {
 		showDeveloper()
 	case 4:
 		fmt.Println("👋 Goodbye! Thank you for using Simple Monitor.")
+		titleUpdater.Stop()
+		terminal.RestoreOutput()
+		privhelper.Use(nil)
+		instanceLock.Release()
 		os.Exit(0)
 	}
 }
@@ -133,7 +183,7 @@ func handleMainMenuChoice(choice int) {
 func startMonitoring() {
 	for {
 		displayMonitoringMenu()
-		choice := getUserChoice(8)
+		choice := getUserChoice(9)
 
 		// Clear screen after selection
 		fmt.Print("\033[2J\033[H")
@@ -144,18 +194,32 @@ func startMonitoring() {
 			fmt.Println(strings.Repeat("-", 30))
 			showSystemInfo()
 		case 2:
-			monitorCPU()
+			if monitorEnabled(liveCPU) {
+				monitorCPU()
+			}
 		case 3:
-			monitorMemory()
+			if monitorEnabled(liveMemory) {
+				monitorMemory()
+			}
 		case 4:
-			monitorDisk()
+			if monitorEnabled(liveDisk) {
+				monitorDisk()
+			}
 		case 5:
-			monitorNetwork()
+			if monitorEnabled(liveNetwork) {
+				monitorNetwork()
+			}
 		case 6:
-			showProcesses()
+			if monitorEnabled(liveProcess) {
+				showProcesses()
+			}
 		case 7:
-			quickTestAllMonitors()
+			if monitorEnabled(liveEvents) {
+				monitorSystemEvents()
+			}
 		case 8:
+			quickTestAllMonitors()
+		case 9:
 			fmt.Println("⬅️  Returning to main menu...")
 			return
 		}
@@ -211,11 +275,12 @@ func showDeveloper() {
 		fmt.Println("6. Performance Analysis")
 		fmt.Println("7. Debug Mode")
 		fmt.Println("8. Export Debug Info")
-		fmt.Println("9. Back to Main Menu")
+		fmt.Println("9. Runtime Introspection")
+		fmt.Println("10. Back to Main Menu")
 		fmt.Println(strings.Repeat("-", 30))
-		fmt.Print("Select option (1-9): ")
+		fmt.Print("Select option (1-10): ")
 
-		choice := getUserChoice(9)
+		choice := getUserChoice(10)
 
 		switch choice {
 		case 1:
@@ -235,6 +300,8 @@ func showDeveloper() {
 		case 8:
 			exportDebugInfo()
 		case 9:
+			showRuntimeIntrospection()
+		case 10:
 			return
 		}
 	}
@@ -397,7 +464,9 @@ func testAllMonitors() {
 
 	// Test CPU Monitor
 	fmt.Println("\nTesting CPU Monitor...")
-	if err := cpuMonitorManager.StartSingleSnapshot(); err != nil {
+	if !cpuMonitorManager.IsEnabled() {
+		fmt.Println("⏸️  CPU Monitor: disabled")
+	} else if err := cpuMonitorManager.StartSingleSnapshot(); err != nil {
 		fmt.Printf("❌ CPU Monitor Error: %v\n", err)
 	} else {
 		fmt.Println("✅ CPU Monitor: OK")
@@ -405,7 +474,9 @@ func testAllMonitors() {
 
 	// Test Memory Monitor
 	fmt.Println("\nTesting Memory Monitor...")
-	if err := memoryMonitorManager.StartSingleSnapshot(); err != nil {
+	if !memoryMonitorManager.IsEnabled() {
+		fmt.Println("⏸️  Memory Monitor: disabled")
+	} else if err := memoryMonitorManager.StartSingleSnapshot(); err != nil {
 		fmt.Printf("❌ Memory Monitor Error: %v\n", err)
 	} else {
 		fmt.Println("✅ Memory Monitor: OK")
@@ -413,7 +484,9 @@ func testAllMonitors() {
 
 	// Test Disk Monitor
 	fmt.Println("\nTesting Disk Monitor...")
-	if err := diskMonitorManager.StartSingleSnapshot(); err != nil {
+	if !diskMonitorManager.IsEnabled() {
+		fmt.Println("⏸️  Disk Monitor: disabled")
+	} else if err := diskMonitorManager.StartSingleSnapshot(); err != nil {
 		fmt.Printf("❌ Disk Monitor Error: %v\n", err)
 	} else {
 		fmt.Println("✅ Disk Monitor: OK")
@@ -421,7 +494,9 @@ func testAllMonitors() {
 
 	// Test Network Monitor
 	fmt.Println("\nTesting Network Monitor...")
-	if err := networkMonitorManager.StartSingleSnapshot(); err != nil {
+	if !networkMonitorManager.IsEnabled() {
+		fmt.Println("⏸️  Network Monitor: disabled")
+	} else if err := networkMonitorManager.StartSingleSnapshot(); err != nil {
 		fmt.Printf("❌ Network Monitor Error: %v\n", err)
 	} else {
 		fmt.Println("✅ Network Monitor: OK")
@@ -431,32 +506,177 @@ func testAllMonitors() {
 	waitForEnter()
 }
 
-// showPerformanceAnalysis displays performance analysis
+// showPerformanceAnalysis benchmarks every collector and optionally writes pprof profiles
 func showPerformanceAnalysis() {
 	fmt.Println("\n📈 Performance Analysis")
 	fmt.Println(strings.Repeat("-", 50))
+	fmt.Printf("Go Version: %s\n", runtime.Version())
+	fmt.Printf("Platform: %s/%s (%d CPUs)\n", runtime.GOOS, runtime.GOARCH, runtime.NumCPU())
+	fmt.Println(strings.Repeat("-", 50))
+	fmt.Println("1. Benchmark collectors")
+	fmt.Println("2. Benchmark collectors and write CPU/heap profiles")
+	fmt.Println("3. Back to Developer Menu")
+	fmt.Print("Select option (1-3): ")
 
-	// System performance metrics
-	fmt.Println("🖥️  System Performance:")
-	fmt.Printf("  Go Version: %s\n", "1.21+")
-	fmt.Printf("  OS: %s\n", "Windows")
-	fmt.Printf("  Architecture: %s\n", "AMD64")
+	choice := getUserChoice(3)
+	if choice == 3 {
+		return
+	}
 
-	// Memory analysis
-	fmt.Println("\n💾 Memory Analysis:")
-	if err := memoryMonitorManager.StartSingleSnapshot(); err != nil {
-		fmt.Println("  Error: Failed to collect memory data")
+	fmt.Printf("Runs per collector (default %d): ", benchmark.DefaultRuns)
+	scanner := bufio.NewScanner(os.Stdin)
+	scanner.Scan()
+	runs := benchmark.DefaultRuns
+	if input := strings.TrimSpace(scanner.Text()); input != "" {
+		if parsed, err := strconv.Atoi(input); err == nil && parsed > 0 {
+			runs = parsed
+		} else {
+			fmt.Printf("❌ Invalid input! Using default %d runs.\n", benchmark.DefaultRuns)
+		}
 	}
-	
-	// CPU analysis
-	fmt.Println("\n🖥️  CPU Analysis:")
-	if err := cpuMonitorManager.StartSingleSnapshot(); err != nil {
-		fmt.Println("  Error: Failed to collect CPU data")
+
+	targets := benchmarkTargets()
+	fmt.Printf("\n⏱️  Collecting each monitor %d times...\n", runs)
+
+	var results []benchmark.Result
+	if choice == 2 {
+		var profiles benchmark.Profiles
+		var err error
+		results, profiles, err = benchmark.RunWithProfiles(targets, runs, filepath.Join("logs", "profiles"))
+		if err != nil {
+			fmt.Printf("❌ Profiling failed: %v\n", err)
+		}
+		if profiles.CPU != "" {
+			fmt.Printf("💾 CPU profile: %s\n", profiles.CPU)
+		}
+		if profiles.Heap != "" {
+			fmt.Printf("💾 Heap profile: %s\n", profiles.Heap)
+		}
+	} else {
+		results = benchmark.RunAll(targets, runs)
 	}
 
+	printBenchmarkResults(results)
+	if choice == 2 {
+		fmt.Println("\nInspect profiles with: go tool pprof <profile>")
+	}
 	waitForEnter()
 }
 
+// debugServer serves pprof profiles and runtime stats of the monitor itself while switched on
+var debugServer = introspect.NewServer()
+
+// showRuntimeIntrospection shows the monitor's own goroutines, heap and GC pauses,
+// and manages the debug endpoint and goroutine dumps used to diagnose hangs and leaks
+func showRuntimeIntrospection() {
+	for {
+		stats := introspect.Read()
+		fmt.Println("\n🔬 Runtime Introspection")
+		fmt.Println(strings.Repeat("-", 50))
+		fmt.Printf("Go Version: %s (GOMAXPROCS %d), up %s\n", stats.GoVersion, stats.GOMAXPROCS, stats.Uptime.Round(time.Second))
+		fmt.Printf("Goroutines: %d\n", stats.Goroutines)
+		fmt.Printf("Heap: %.1f MB live in %d objects, %.1f MB reserved (%.1f MB from the OS in total)\n",
+			float64(stats.HeapAlloc)/(1024*1024), stats.HeapObjects, float64(stats.HeapSys)/(1024*1024), float64(stats.Sys)/(1024*1024))
+		fmt.Printf("Allocated since start: %.1f MB\n", float64(stats.TotalAlloc)/(1024*1024))
+		fmt.Printf("GC: %d cycles, %s paused in total, %.2f%% of CPU time\n", stats.NumGC, stats.PauseTotal, stats.GCCPUPercent)
+		if !stats.LastGC.IsZero() {
+			fmt.Printf("Recent pauses: max %s over the last %d, last GC at %s\n",
+				stats.MaxPause, len(stats.RecentPauses), stats.LastGC.Format("15:04:05"))
+		}
+		if address := debugServer.Address(); address != "" {
+			query := "?" + introspect.TokenParameter + "=" + debugServer.Token()
+			fmt.Printf("Debug endpoint: http://%s/debug/pprof/%s and http://%s/debug/runtime%s\n", address, query, address, query)
+		} else {
+			fmt.Println("Debug endpoint: off")
+		}
+		fmt.Println(strings.Repeat("-", 50))
+		fmt.Println("1. Refresh")
+		if debugServer.Address() != "" {
+			fmt.Println("2. Stop Debug Endpoint")
+		} else {
+			fmt.Printf("2. Start Debug Endpoint (%s)\n", introspect.DefaultAddress)
+		}
+		fmt.Println("3. Dump Goroutine Stacks")
+		fmt.Println("4. Back to Developer Menu")
+		fmt.Print("Select option (1-4): ")
+
+		switch getUserChoice(4) {
+		case 2:
+			if debugServer.Address() != "" {
+				if err := debugServer.Stop(); err != nil {
+					fmt.Printf("❌ %v\n", err)
+				} else {
+					fmt.Println("✅ Debug endpoint stopped")
+				}
+			} else if err := debugServer.Start(introspect.DefaultAddress); err != nil {
+				fmt.Printf("❌ %v\n", err)
+			} else {
+				fmt.Printf("✅ Debug endpoint on http://%s (loopback only, with the token below)\n", debugServer.Address())
+				fmt.Printf("   Profile with: go tool pprof \"http://%s/debug/pprof/heap?%s=%s\"\n",
+					debugServer.Address(), introspect.TokenParameter, debugServer.Token())
+			}
+		case 3:
+			if path, err := introspect.DumpGoroutines(filepath.Join(logsDir, "debug")); err != nil {
+				fmt.Printf("❌ %v\n", err)
+			} else {
+				fmt.Printf("💾 Goroutine stacks saved to: %s\n", path)
+			}
+		case 4:
+			return
+		}
+	}
+}
+
+// benchmarkTargets returns the collectors measured by the performance analysis
+// The running managers are used so rate baselines match what live monitoring does
+func benchmarkTargets() []benchmark.Target {
+	return []benchmark.Target{
+		{Name: "CPU", Collect: func() error {
+			_, err := cpuMonitorManager.Collect()
+			return err
+		}},
+		{Name: "Memory", Collect: func() error {
+			_, err := memoryMonitorManager.Collect()
+			return err
+		}},
+		{Name: "Disk", Collect: func() error {
+			_, err := diskMonitorManager.Collect()
+			return err
+		}},
+		{Name: "Network", Collect: func() error {
+			_, err := networkMonitorManager.Collect()
+			return err
+		}},
+		{Name: "Process", Collect: func() error {
+			_, err := processMonitorManager.Collect()
+			return err
+		}},
+	}
+}
+
+// printBenchmarkResults prints one row per collector
+func printBenchmarkResults(results []benchmark.Result) {
+	fmt.Println()
+	fmt.Printf("%-10s %10s %10s %10s %10s %12s %12s %8s\n", "Monitor", "Mean", "Median", "Min", "Max", "Allocs/run", "KB/run", "Errors")
+	fmt.Println(strings.Repeat("-", 90))
+	for _, result := range results {
+		fmt.Printf("%-10s %10v %10v %10v %10v %12d %12.1f %8d\n",
+			result.Name,
+			result.Mean.Round(time.Microsecond),
+			result.Median.Round(time.Microsecond),
+			result.Min.Round(time.Microsecond),
+			result.Max.Round(time.Microsecond),
+			result.AllocsPerRun,
+			float64(result.BytesPerRun)/1024,
+			result.Failures)
+	}
+	for _, result := range results {
+		if result.LastError != "" {
+			fmt.Printf("⚠️  %s: %s\n", result.Name, result.LastError)
+		}
+	}
+}
+
 // toggleDebugMode toggles debug mode
 func toggleDebugMode() {
 	fmt.Println("\n🐛 Debug Mode")
@@ -499,13 +719,15 @@ func exportDebugInfo() {
 		debugInfo := map[string]interface{}{
 			"timestamp":  time.Now().Format("2006-01-02 15:04:05"),
 			"version":    "1.0",
-			"platform":   "Windows/AMD64",
-			"go_version": "1.21+",
+			"platform":   runtime.GOOS + "/" + runtime.GOARCH,
+			"go_version": runtime.Version(),
+			"redacted":   redact.Enabled(),
 		}
 
-		// Add system info
-		if data, err := systemInfoManager.GetSystemInfo(); err == nil {
-			debugInfo["system"] = data
+		// Add system info, including the PCI device and driver inventory
+		systemData, err := systemInfoManager.GetSystemInfo()
+		if err == nil {
+			debugInfo["system"] = systemData
 		}
 
 		// Add configuration
@@ -518,13 +740,14 @@ func exportDebugInfo() {
 		}
 
 		// Export based on choice
-		if choice == 1 {
-			// Export to JSON
-			filePath := fmt.Sprintf("debug_info_%s.json", time.Now().Format("2006-01-02_15-04-05"))
-			fmt.Printf("✅ Debug info exported to: %s\n", filePath)
+		extension := "json"
+		if choice == 2 {
+			extension = "txt"
+		}
+		filePath := filepath.Join(logsDir, "debug", fmt.Sprintf("debug_info_%s.%s", time.Now().Format("2006-01-02_15-04-05"), extension))
+		if err := writeDebugInfo(filePath, debugInfo, systemData); err != nil {
+			fmt.Printf("❌ Error exporting debug info: %v\n", err)
 		} else {
-			// Export to TXT
-			filePath := fmt.Sprintf("debug_info_%s.txt", time.Now().Format("2006-01-02_15-04-05"))
 			fmt.Printf("✅ Debug info exported to: %s\n", filePath)
 		}
 	} else {
@@ -533,6 +756,57 @@ func exportDebugInfo() {
 	waitForEnter()
 }
 
+// writeDebugInfo writes the debug bundle as JSON, or as text with the PCI inventory listed up front
+func writeDebugInfo(filePath string, debugInfo map[string]interface{}, systemData *systeminfo.SystemInfo) error {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	// The PCI inventory names no users or hosts, so only the details are redacted
+	if err := redact.Copy(&debugInfo); err != nil {
+		return err
+	}
+	details, err := json.MarshalIndent(debugInfo, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal debug info: %w", err)
+	}
+	if strings.HasSuffix(filePath, ".json") {
+		return os.WriteFile(filePath, details, 0644)
+	}
+
+	var text strings.Builder
+	text.WriteString("SIMPLE MONITOR DEBUG INFO\n")
+	text.WriteString(strings.Repeat("=", 40) + "\n")
+	for _, key := range []string{"timestamp", "version", "platform", "go_version", "redacted"} {
+		fmt.Fprintf(&text, "%-12s %v\n", key+":", debugInfo[key])
+	}
+
+	// Hardware and drivers first, as they are what performance reports are usually correlated with
+	text.WriteString("\nPCI DEVICES\n")
+	text.WriteString(strings.Repeat("-", 40) + "\n")
+	switch {
+	case systemData == nil:
+		text.WriteString("Unavailable\n")
+	case systemData.PCIError != "":
+		fmt.Fprintf(&text, "Unavailable (%s)\n", systemData.PCIError)
+	default:
+		for _, device := range systemData.PCIDevices {
+			driver := "none"
+			if device.Driver != "" {
+				driver = strings.TrimSpace(device.Driver + " " + device.DriverVersion)
+			}
+			fmt.Fprintf(&text, "%s  %-12s %s %s  driver=%s\n", device.Address, device.Category,
+				device.Vendor, device.Name(), driver)
+		}
+	}
+
+	text.WriteString("\nDETAILS\n")
+	text.WriteString(strings.Repeat("-", 40) + "\n")
+	text.Write(details)
+	text.WriteString("\n")
+	return os.WriteFile(filePath, []byte(text.String()), 0644)
+}
+
 // System information manager instance
 var systemInfoManager = systeminfo.NewSystemInfoManager()
 
@@ -549,6 +823,108 @@ var diskMonitorManager = diskmonitor.NewDiskMonitorManager()
 var networkMonitorManager = networkmonitor.NewNetworkMonitorManager()
 var processMonitorManager = processmonitor.NewProcessMonitorManager()
 
+// System event monitor manager instance
+var eventMonitorManager = eventmonitor.NewEventMonitorManager()
+
+// Combined snapshot exporter instance (all monitors in one file)
+var snapshotExporter = snapshot.NewExporter()
+
+// followerManagers are managers that follow the live monitors' settings and simulation mode but collect apart
+// from them, so background collections add no points to the live history and do not shift its rate baselines
+type followerManagers struct {
+	cpu     *cpumonitor.CPUMonitorManager
+	memory  *memorymonitor.MemoryMonitorManager
+	disk    *diskmonitor.DiskMonitorManager
+	network *networkmonitor.NetworkMonitorManager
+	process *processmonitor.ProcessMonitorManager
+}
+
+// newFollowerManagers creates one manager per monitor
+func newFollowerManagers() *followerManagers {
+	return &followerManagers{
+		cpu:     cpumonitor.NewCPUMonitorManager(),
+		memory:  memorymonitor.NewMemoryMonitorManager(),
+		disk:    diskmonitor.NewDiskMonitorManager(),
+		network: networkmonitor.NewNetworkMonitorManager(),
+		process: processmonitor.NewProcessMonitorManager(),
+	}
+}
+
+// monitors brings the managers in line with the live monitors and returns them; disabled monitors are left out
+func (managers *followerManagers) monitors() snapshot.Monitors {
+	var monitors snapshot.Monitors
+	if cpuMonitorManager.IsEnabled() {
+		managers.cpu.SetConfiguration(cpuMonitorManager.GetConfiguration())
+		if managers.cpu.IsSimulated() != cpuMonitorManager.IsSimulated() {
+			managers.cpu.SetSimulationMode(cpuMonitorManager.IsSimulated())
+		}
+		monitors.CPU = managers.cpu
+	}
+	if memoryMonitorManager.IsEnabled() {
+		managers.memory.UpdateConfig(memoryMonitorManager.GetConfig())
+		if managers.memory.IsSimulated() != memoryMonitorManager.IsSimulated() {
+			managers.memory.SetSimulationMode(memoryMonitorManager.IsSimulated())
+		}
+		monitors.Memory = managers.memory
+	}
+	if diskMonitorManager.IsEnabled() {
+		managers.disk.UpdateConfig(diskMonitorManager.GetConfig())
+		if managers.disk.IsSimulated() != diskMonitorManager.IsSimulated() {
+			managers.disk.SetSimulationMode(diskMonitorManager.IsSimulated())
+		}
+		monitors.Disk = managers.disk
+	}
+	if networkMonitorManager.IsEnabled() {
+		managers.network.UpdateConfig(networkMonitorManager.GetConfig())
+		if managers.network.IsSimulated() != networkMonitorManager.IsSimulated() {
+			managers.network.SetSimulationMode(networkMonitorManager.IsSimulated())
+		}
+		monitors.Network = managers.network
+	}
+	if processMonitorManager.IsEnabled() {
+		managers.process.UpdateConfig(processMonitorManager.GetConfig())
+		if managers.process.IsSimulated() != processMonitorManager.IsSimulated() {
+			managers.process.SetSimulationMode(processMonitorManager.IsSimulated())
+		}
+		monitors.Process = managers.process
+	}
+	return monitors
+}
+
+// Managers that collect the published latest snapshot
+var publishManagers = newFollowerManagers()
+
+// Managers that run the scheduled quick tests, apart from publishing so the two loops never share a collector
+var quickTestManagers = newFollowerManagers()
+
+// publishedMonitors returns the managers for the next published snapshot
+func publishedMonitors() snapshot.Monitors {
+	return publishManagers.monitors()
+}
+
+// quickTestMonitors returns the managers for the next scheduled quick test
+func quickTestMonitors() snapshot.Monitors {
+	return quickTestManagers.monitors()
+}
+
+// Terminal title updater instance (pins CPU %, memory % and the top alert to the title)
+var titleUpdater = titlebar.NewUpdater()
+
+// Whether live sessions are recorded for replay (--record)
+var recordSessions bool
+
+// Directory that exports, history and recordings are written under; data retention prunes it
+var logsDir = "logs"
+
+// Directory that session recordings are written to
+var recordingsDir = filepath.Join(logsDir, "recordings")
+
+// Directory the instance holding the lock publishes its live monitors to for --viewer instances
+var liveFeedDir = filepath.Join(logsDir, instance.FeedDir)
+
+// Lock that makes this the only instance writing under logsDir (nil in viewer mode or when it could not be taken)
+var instanceLock *instance.Lock
+
 // showSystemInfo displays comprehensive system information
 func showSystemInfo() {
 	if err := systemInfoManager.ShowSystemInfo(); err != nil {
@@ -571,9 +947,7 @@ func monitorCPU() {
 	switch choice {
 	case 1:
 		fmt.Println("Starting live CPU monitoring...")
-		if err := cpuMonitorManager.StartLiveMonitoring(); err != nil {
-			fmt.Printf("❌ Error starting CPU monitoring: %v\n", err)
-		}
+		runLiveMonitors(liveCPU)
 		waitForEnter()
 	case 2:
 		if err := cpuMonitorManager.StartSingleSnapshot(); err != nil {
@@ -599,9 +973,7 @@ func monitorMemory() {
 	switch choice {
 	case 1:
 		fmt.Println("Starting live memory monitoring...")
-		if err := memoryMonitorManager.StartLiveMonitoring(); err != nil {
-			fmt.Printf("❌ Error starting memory monitoring: %v\n", err)
-		}
+		runLiveMonitors(liveMemory)
 		waitForEnter()
 	case 2:
 		if err := memoryMonitorManager.StartSingleSnapshot(); err != nil {
@@ -618,18 +990,18 @@ func monitorDisk() {
 	fmt.Println(strings.Repeat("-", 30))
 	fmt.Println("1. Live Monitoring")
 	fmt.Println("2. Single Snapshot")
-	fmt.Println("3. Back to Monitoring Menu")
+	fmt.Println("3. SMART Self-Test")
+	fmt.Println("4. Throughput Calibration")
+	fmt.Println("5. Back to Monitoring Menu")
 	fmt.Println(strings.Repeat("-", 30))
-	fmt.Print("Select option (1-3): ")
+	fmt.Print("Select option (1-5): ")
 
-	choice := getUserChoice(3)
+	choice := getUserChoice(5)
 
 	switch choice {
 	case 1:
 		fmt.Println("Starting live disk monitoring...")
-		if err := diskMonitorManager.StartLiveMonitoring(); err != nil {
-			fmt.Printf("❌ Error starting disk monitoring: %v\n", err)
-		}
+		runLiveMonitors(liveDisk)
 		waitForEnter()
 	case 2:
 		if err := diskMonitorManager.StartSingleSnapshot(); err != nil {
@@ -637,947 +1009,4201 @@ func monitorDisk() {
 		}
 		waitForEnter()
 	case 3:
+		queueDiskSelfTest()
+		waitForEnter()
+	case 4:
+		calibrateDisk()
+		waitForEnter()
+	case 5:
 		return
 	}
 }
 
-func monitorNetwork() {
-	fmt.Println("🌐 Network Monitor")
-	fmt.Println(strings.Repeat("-", 30))
-	fmt.Println("1. Live Monitoring")
-	fmt.Println("2. Single Snapshot")
-	fmt.Println("3. Back to Monitoring Menu")
-	fmt.Println(strings.Repeat("-", 30))
-	fmt.Print("Select option (1-3): ")
+// calibrateDisk records a drive's maximum throughput, benchmarked or entered from its specs
+// Disk speeds are then shown and alerted on as a share of it; the result is saved to the config file
+func calibrateDisk() {
+	targets, err := diskMonitorManager.GetCalibrationTargets()
+	if err != nil {
+		fmt.Printf("❌ Error listing drives: %v\n", err)
+		return
+	}
+	if len(targets) == 0 {
+		fmt.Println("No drives found")
+		return
+	}
 
-	choice := getUserChoice(3)
+	fmt.Println("\n📏 Throughput Calibration")
+	fmt.Println(strings.Repeat("-", 60))
+	for i, target := range targets {
+		state := "not calibrated"
+		if capability := target.Capability; capability != nil {
+			state = fmt.Sprintf("read %.0f MB/s, write %.0f MB/s (%s, %s)",
+				capability.MaxReadSpeed, capability.MaxWriteSpeed, capability.Source, capability.MeasuredAt.Format("2006-01-02"))
+		}
+		fmt.Printf("%2d. %-12s %s\n", i+1, target.Device, state)
+	}
+	fmt.Println(strings.Repeat("-", 60))
+	fmt.Printf("Select drive (1-%d): ", len(targets))
+	target := targets[getUserChoice(len(targets))-1]
+
+	fmt.Printf("1. Run benchmark (writes and reads back %d MB on %s)\n", diskmonitor.CalibrationSize/(1024*1024), target.Device)
+	fmt.Println("2. Enter rated speeds")
+	fmt.Println("3. Clear calibration")
+	fmt.Println("4. Back")
+	fmt.Print("Select option (1-4): ")
 
-	switch choice {
+	var capability diskmonitor.DeviceCapability
+	switch getUserChoice(4) {
 	case 1:
-		fmt.Println("Starting live network monitoring...")
-		if err := networkMonitorManager.StartLiveMonitoring(); err != nil {
-			fmt.Printf("❌ Error starting network monitoring: %v\n", err)
+		fmt.Printf("⏱️  Benchmarking %s...\n", target.Device)
+		capability, err = diskMonitorManager.CalibrateDevice(target)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return
 		}
-		waitForEnter()
 	case 2:
-		if err := networkMonitorManager.StartSingleSnapshot(); err != nil {
-			fmt.Printf("❌ Error displaying network information: %v\n", err)
+		scanner := bufio.NewScanner(os.Stdin)
+		read, ok := readSpeed(scanner, "Rated read speed (MB/s): ")
+		if !ok {
+			return
+		}
+		write, ok := readSpeed(scanner, "Rated write speed (MB/s): ")
+		if !ok {
+			return
+		}
+		capability = diskmonitor.DeviceCapability{
+			MaxReadSpeed:  read,
+			MaxWriteSpeed: write,
+			Source:        diskmonitor.CapabilityRated,
+			MeasuredAt:    time.Now(),
 		}
-		waitForEnter()
 	case 3:
+		diskMonitorManager.ClearDeviceCapability(target.Device)
+		fmt.Printf("✅ %s calibration cleared; utilization is busy time again\n", target.Device)
+		saveDiskCapabilities()
+		return
+	case 4:
 		return
 	}
+
+	diskMonitorManager.SetDeviceCapability(target.Device, capability)
+	fmt.Printf("✅ %s: read %.0f MB/s, write %.0f MB/s\n", target.Device, capability.MaxReadSpeed, capability.MaxWriteSpeed)
+	saveDiskCapabilities()
+}
+
+// readSpeed prompts for a positive speed in MB/s
+func readSpeed(scanner *bufio.Scanner, prompt string) (float64, bool) {
+	fmt.Print(prompt)
+	scanner.Scan()
+	speed, err := strconv.ParseFloat(strings.TrimSpace(scanner.Text()), 64)
+	if err != nil || speed <= 0 {
+		fmt.Println("❌ Invalid speed!")
+		return 0, false
+	}
+	return speed, true
 }
 
-func showProcesses() {
-	fmt.Println("⚙️  Process Monitor")
+// saveDiskCapabilities writes the recorded drive capabilities to the config file so they survive restarts
+// Synthetic drives are never written, and an old-schema file is left alone until it is migrated
+func saveDiskCapabilities() {
+	if diskMonitorManager.IsSimulated() {
+		fmt.Println("ℹ️  Simulation mode: the calibration is kept for this session only")
+		return
+	}
+
+	path := config.Path()
+	file, err := config.Load(path)
+	if os.IsNotExist(err) {
+		file, err = config.New(), nil
+	}
+	if err != nil {
+		fmt.Printf("⚠️  Calibration not saved: %v\n", err)
+		return
+	}
+	if file.Version != config.CurrentVersion {
+		fmt.Printf("⚠️  Calibration not saved: %s uses an old schema; run `simple-monitor config migrate` first\n", path)
+		return
+	}
+
+	if err := file.Set("disk", "device_capabilities", diskMonitorManager.GetConfig().DeviceCapabilities); err != nil {
+		fmt.Printf("⚠️  Calibration not saved: %v\n", err)
+		return
+	}
+	if err := file.Save(path); err != nil {
+		fmt.Printf("⚠️  Calibration not saved: %v\n", err)
+		return
+	}
+	fmt.Printf("💾 Saved to %s\n", path)
+}
+
+// queueDiskSelfTest lets the user pick a drive and queue a short or long SMART self-test on it
+// The drive runs the test in the background; live disk monitoring shows its progress and result
+func queueDiskSelfTest() {
+	drives, err := diskMonitorManager.GetSelfTestDrives()
+	if err != nil {
+		fmt.Printf("❌ Error listing drives: %v\n", err)
+		return
+	}
+	if len(drives) == 0 {
+		fmt.Println("No drives found")
+		return
+	}
+
+	fmt.Println("\n🩺 SMART Self-Test")
+	fmt.Println(strings.Repeat("-", 40))
+	for i, drive := range drives {
+		state := drive.Summary()
+		if len(drive.Queued) > 0 {
+			state = strings.TrimSpace(state + " (queued: " + strings.Join(drive.Queued, ", ") + ")")
+		}
+		fmt.Printf("%2d. %-16s %s\n", i+1, drive.Device, state)
+	}
+	fmt.Println(strings.Repeat("-", 40))
+	fmt.Printf("Select drive (1-%d): ", len(drives))
+	drive := drives[getUserChoice(len(drives))-1]
+
+	fmt.Println("1. Short test (about 2 minutes)")
+	fmt.Println("2. Long test (an hour or more)")
+	fmt.Print("Select test (1-2): ")
+	kind := diskmonitor.SelfTestKinds[getUserChoice(len(diskmonitor.SelfTestKinds))-1]
+
+	if err := diskMonitorManager.QueueSelfTest(drive.Device, kind); err != nil {
+		fmt.Printf("❌ Error queueing self-test: %v\n", err)
+		return
+	}
+	fmt.Printf("✅ %s self-test queued on %s; its progress appears in the disk health table\n", kind, drive.Device)
+}
+
+func monitorNetwork() {
+	fmt.Println("🌐 Network Monitor")
 	fmt.Println(strings.Repeat("-", 30))
 	fmt.Println("1. Live Monitoring")
 	fmt.Println("2. Single Snapshot")
-	fmt.Println("3. Back to Monitoring Menu")
+	fmt.Println("3. Ping a Host")
+	fmt.Println("4. Traceroute to a Host")
+	fmt.Println("5. Wake-on-LAN")
+	fmt.Println("6. Close a Connection")
+	fmt.Println("7. Back to Monitoring Menu")
 	fmt.Println(strings.Repeat("-", 30))
-	fmt.Print("Select option (1-3): ")
+	fmt.Print("Select option (1-7): ")
 
-	choice := getUserChoice(3)
+	choice := getUserChoice(7)
 
 	switch choice {
 	case 1:
-		fmt.Println("Starting live process monitoring...")
-		if err := processMonitorManager.StartLiveMonitoring(); err != nil {
-			fmt.Printf("❌ Error starting process monitoring: %v\n", err)
-		}
+		fmt.Println("Starting live network monitoring...")
+		runLiveMonitors(liveNetwork)
 		waitForEnter()
 	case 2:
-		if err := processMonitorManager.StartSingleSnapshot(); err != nil {
-			fmt.Printf("❌ Error displaying process information: %v\n", err)
+		if err := networkMonitorManager.StartSingleSnapshot(); err != nil {
+			fmt.Printf("❌ Error displaying network information: %v\n", err)
 		}
 		waitForEnter()
 	case 3:
+		if host := askHost("Host to ping: "); host != "" {
+			fmt.Printf("Pinging %s...\n", host)
+			if err := networkMonitorManager.PingHost(host); err != nil {
+				fmt.Printf("❌ Error: %v\n", err)
+			}
+		}
+		waitForEnter()
+	case 4:
+		// Tracing a latency target shows whether its latency alerts come from the local network, the ISP or beyond
+		target, prompt := "", "Host to trace: "
+		if targets := networkMonitorManager.GetConfig().LatencyTargets; len(targets) > 0 {
+			target, prompt = targets[0], fmt.Sprintf("Host to trace (Enter for %s): ", targets[0])
+		}
+		host := askHost(prompt)
+		if host == "" {
+			host = target
+		}
+		if host != "" {
+			fmt.Printf("Tracing the route to %s (this can take a minute)...\n", host)
+			if err := networkMonitorManager.TraceHost(host); err != nil {
+				fmt.Printf("❌ Error: %v\n", err)
+			}
+		}
+		waitForEnter()
+	case 5:
+		wakeOnLAN()
+	case 6:
+		closeConnection()
+	case 7:
 		return
 	}
 }
 
-// quickTestAllMonitors runs a quick test of all monitors simultaneously
-func quickTestAllMonitors() {
-	fmt.Println("🚀 Quick Test - All Monitors")
-	fmt.Println(strings.Repeat("-", 30))
-	fmt.Println("Running quick tests for all monitors...")
-	fmt.Println("Press Ctrl+C to stop at any time")
-	fmt.Println()
+// closeConnection lists the open TCP connections and resets the chosen one after confirmation
+func closeConnection() {
+	connections, err := networkMonitorManager.ListTCPConnections()
+	if err != nil {
+		fmt.Printf("❌ Error listing connections: %v\n", err)
+		waitForEnter()
+		return
+	}
+	if len(connections) == 0 {
+		fmt.Println("No open TCP connections")
+		waitForEnter()
+		return
+	}
 
-	// Set up signal handling
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	fmt.Println("\n✂️  Close a Connection")
+	fmt.Println(strings.Repeat("-", 90))
+	fmt.Printf("%-4s %-28s %-28s %-12s %s\n", "#", "Local Address", "Remote Address", "State", "Process")
+	for i, conn := range connections {
+		fmt.Printf("%-4d %-28s %-28s %-12s %s (PID %d)\n", i+1, conn.LocalAddress, conn.RemoteAddress, conn.Status, conn.ProcessName, conn.PID)
+	}
+	fmt.Println(strings.Repeat("-", 90))
+	fmt.Print("Connection to close (Enter to cancel): ")
 
-	// Create a channel to control the test loop
-	stopChan := make(chan bool, 1)
+	scanner := bufio.NewScanner(os.Stdin)
+	scanner.Scan()
+	input := strings.TrimSpace(scanner.Text())
+	if input == "" {
+		return
+	}
+	index, err := strconv.Atoi(input)
+	if err != nil || index < 1 || index > len(connections) {
+		fmt.Println("❌ Invalid choice!")
+		waitForEnter()
+		return
+	}
 
-	// Start the test loop in a goroutine
-	go func() {
-		ticker := time.NewTicker(2 * time.Second) // Update every 2 seconds
-		defer ticker.Stop()
+	conn := connections[index-1]
+	fmt.Printf("Close %s -> %s of %s (PID %d)? The process sees the connection reset. [y/N]: ", conn.LocalAddress, conn.RemoteAddress, conn.ProcessName, conn.PID)
+	scanner.Scan()
+	if answer := strings.ToLower(strings.TrimSpace(scanner.Text())); answer != "y" && answer != "yes" {
+		fmt.Println("Cancelled")
+		waitForEnter()
+		return
+	}
 
-		for {
-			select {
-			case <-ticker.C:
-				// Clear screen and show current status
-				fmt.Print("\033[2J\033[H")
-				fmt.Println("🚀 Quick Test - All Monitors")
-				fmt.Println(strings.Repeat("-", 30))
-				fmt.Printf("Last Update: %s\n", time.Now().Format("15:04:05"))
-				fmt.Println()
+	method, err := networkMonitorManager.CloseConnection(conn)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+	} else {
+		fmt.Printf("✅ Connection closed (%s)\n", method)
+	}
+	waitForEnter()
+}
 
-				// Quick CPU test
-				fmt.Println("🖥️  CPU:")
-				if err := cpuMonitorManager.StartSingleSnapshot(); err != nil {
-					fmt.Println("  Error: Failed to collect data")
-				}
-
-				// Quick Memory test
-				fmt.Println("\n💾 Memory:")
-				if err := memoryMonitorManager.StartSingleSnapshot(); err != nil {
-					fmt.Println("  Error: Failed to collect data")
-				}
-
-				// Quick Disk test
-				fmt.Println("\n💿 Disk:")
-				if err := diskMonitorManager.StartSingleSnapshot(); err != nil {
-					fmt.Println("  Error: Failed to collect data")
-				}
-
-				// Quick Network test
-				fmt.Println("\n🌐 Network:")
-				if err := networkMonitorManager.StartSingleSnapshot(); err != nil {
-					fmt.Println("  Error: Failed to collect data")
-				}
+// askHost reads a host name or address for the network tools
+func askHost(prompt string) string {
+	fmt.Print(prompt)
+	scanner := bufio.NewScanner(os.Stdin)
+	scanner.Scan()
+	return strings.TrimSpace(scanner.Text())
+}
 
-				fmt.Println("\nPress Ctrl+C to stop...")
+// wakeOnLAN lists the configured machines and sends magic packets until the user presses Enter
+// A number wakes that machine, a MAC address wakes it once, and "name: MAC [broadcast]" adds a machine for this session
+func wakeOnLAN() {
+	scanner := bufio.NewScanner(os.Stdin)
+	for {
+		hosts := networkMonitorManager.GetWakeHosts()
+		names := make([]string, 0, len(hosts))
+		for name := range hosts {
+			names = append(names, name)
+		}
+		sort.Strings(names)
 
-			case <-stopChan:
-				return
-			case <-sigChan:
-				stopChan <- true
-				return
+		fmt.Println("\n⏰ Wake-on-LAN")
+		fmt.Println(strings.Repeat("-", 40))
+		if len(names) == 0 {
+			fmt.Println("No machines configured (network.wake_hosts)")
+		}
+		for i, name := range names {
+			broadcast := hosts[name].Broadcast
+			if broadcast == "" {
+				broadcast = "local network"
 			}
+			fmt.Printf("%d. %-20s %s via %s\n", i+1, name, hosts[name].MAC, broadcast)
 		}
-	}()
-
-	// Wait for stop signal
-	<-stopChan
-	fmt.Println("\n🛑 Quick test stopped")
-	waitForEnter()
-}
+		fmt.Println(strings.Repeat("-", 40))
+		fmt.Print("Number or MAC to wake (name: MAC [broadcast] to add one), Enter when done: ")
 
-// showExportSettings displays export settings menu
-func showExportSettings() {
-	for {
-		fmt.Println("\n📁 Export Settings")
-		fmt.Println(strings.Repeat("-", 30))
-		fmt.Println("1. Set Export Interval")
-		fmt.Println("2. Set Export Format")
-		fmt.Println("3. Enable/Disable Export")
-		fmt.Println("4. Back to Settings")
-		fmt.Println(strings.Repeat("-", 30))
-		fmt.Print("Select option (1-4): ")
+		if !scanner.Scan() {
+			return
+		}
+		input := strings.TrimSpace(scanner.Text())
+		if input == "" {
+			return
+		}
 
-		choice := getUserChoice(4)
+		if name, rest, found := strings.Cut(input, ": "); found {
+			fields := strings.Fields(rest)
+			if len(fields) == 0 || len(fields) > 2 {
+				fmt.Println("❌ Expected name: MAC [broadcast]")
+				continue
+			}
+			if _, err := net.ParseMAC(fields[0]); err != nil {
+				fmt.Printf("❌ Invalid MAC address %q\n", fields[0])
+				continue
+			}
+			host := networkmonitor.WakeHost{MAC: fields[0]}
+			if len(fields) == 2 {
+				host.Broadcast = fields[1]
+			}
+			networkMonitorManager.SetWakeHost(strings.TrimSpace(name), host)
+			fmt.Printf("➕ Added %s\n", strings.TrimSpace(name))
+			continue
+		}
 
-		switch choice {
-		case 1:
-			setExportInterval()
-		case 2:
-			setExportFormat()
-		case 3:
-			toggleExport()
-		case 4:
-			return
+		name := input
+		host := networkmonitor.WakeHost{MAC: input}
+		if index, err := strconv.Atoi(input); err == nil {
+			if index < 1 || index > len(names) {
+				fmt.Println("❌ Invalid choice!")
+				continue
+			}
+			name = names[index-1]
+			host = hosts[name]
+		}
+		address, err := networkmonitor.SendWakeOnLAN(host)
+		if err != nil {
+			fmt.Printf("❌ Error waking %s: %v\n", name, err)
+			continue
 		}
+		fmt.Printf("✅ Magic packet for %s sent to %s\n", name, address)
 	}
 }
 
-// setExportInterval allows user to set export interval
-func setExportInterval() {
-	fmt.Println("\n⏰ Export Interval Settings")
+func showProcesses() {
+	fmt.Println("⚙️  Process Monitor")
 	fmt.Println(strings.Repeat("-", 30))
-	fmt.Println("1. 1 minute")
-	fmt.Println("2. 1 hour")
-	fmt.Println("3. 1 day")
-	fmt.Println("4. Custom interval")
-	fmt.Println("5. Back to Export Settings")
+	fmt.Println("1. Live Monitoring")
+	fmt.Println("2. Single Snapshot")
+	fmt.Println("3. Export CPU Flame Graph (folded stacks)")
+	fmt.Println("4. Filter by User")
+	fmt.Println("5. Filter by Slice (cgroup)")
+	fmt.Println("6. Watchdog (Critical Processes)")
+	fmt.Println("7. Trace Ancestry of a PID")
+	fmt.Println("8. Back to Monitoring Menu")
 	fmt.Println(strings.Repeat("-", 30))
-	fmt.Print("Select option (1-5): ")
+	fmt.Print("Select option (1-8): ")
 
-	choice := getUserChoice(5)
+	choice := getUserChoice(8)
 
-	var interval time.Duration
 	switch choice {
 	case 1:
-		interval = 1 * time.Minute
+		fmt.Println("Starting live process monitoring...")
+		runLiveMonitors(liveProcess)
+		waitForEnter()
 	case 2:
-		interval = 1 * time.Hour
+		if err := processMonitorManager.StartSingleSnapshot(); err != nil {
+			fmt.Printf("❌ Error displaying process information: %v\n", err)
+		}
+		waitForEnter()
 	case 3:
-		interval = 24 * time.Hour
-	case 4:
-		fmt.Print("Enter custom interval in minutes: ")
-		scanner := bufio.NewScanner(os.Stdin)
-		scanner.Scan()
-		input := strings.TrimSpace(scanner.Text())
-		if minutes, err := strconv.Atoi(input); err == nil {
-			interval = time.Duration(minutes) * time.Minute
-		} else {
-			fmt.Println("❌ Invalid input! Using default 1 hour.")
-			interval = 1 * time.Hour
+		if err := processMonitorManager.ExportToFile("folded"); err != nil {
+			fmt.Printf("❌ Error exporting CPU flame graph: %v\n", err)
 		}
+		waitForEnter()
+	case 4:
+		pickProcessUsers()
 	case 5:
+		pickProcessSlices()
+	case 6:
+		editWatchdog()
+	case 7:
+		traceAncestry()
+	case 8:
 		return
 	}
+}
 
-	// Update all monitor configurations
-	cpuConfig := cpuMonitorManager.GetConfiguration()
-	cpuConfig.ExportInterval = interval
-	cpuMonitorManager.SetConfiguration(cpuConfig)
-
-	memoryConfig := memoryMonitorManager.GetConfig()
-	memoryConfig.ExportInterval = interval
-	memoryMonitorManager.UpdateConfig(memoryConfig)
-
-	diskConfig := diskMonitorManager.GetConfig()
-	diskConfig.ExportInterval = interval
-	diskMonitorManager.UpdateConfig(diskConfig)
-
-	networkConfig := networkMonitorManager.GetConfig()
-	networkConfig.ExportInterval = interval
-	networkMonitorManager.UpdateConfig(networkConfig)
-
-	processConfig := processMonitorManager.GetConfig()
-	processConfig.ExportInterval = interval
-	processMonitorManager.UpdateConfig(processConfig)
+// traceAncestry asks for a PID and prints its parent chain up to init/systemd with each ancestor's
+// user, start time and command line
+func traceAncestry() {
+	fmt.Print("PID to trace: ")
+	scanner := bufio.NewScanner(os.Stdin)
+	scanner.Scan()
+	pid, err := strconv.ParseInt(strings.TrimSpace(scanner.Text()), 10, 32)
+	if err != nil || pid <= 0 {
+		fmt.Println("❌ Invalid PID!")
+		waitForEnter()
+		return
+	}
 
-	fmt.Printf("✅ Export interval set to: %v\n", interval)
+	if err := processMonitorManager.TraceAncestry(int32(pid)); err != nil {
+		fmt.Printf("❌ Error tracing PID %d: %v\n", pid, err)
+	}
 	waitForEnter()
 }
 
-// setExportFormat allows user to set export format
-func setExportFormat() {
-	fmt.Println("\n📄 Export Format Settings")
-	fmt.Println(strings.Repeat("-", 30))
-	fmt.Println("1. JSON")
-	fmt.Println("2. CSV")
-	fmt.Println("3. TXT")
-	fmt.Println("4. Back to Export Settings")
-	fmt.Println(strings.Repeat("-", 30))
-	fmt.Print("Select option (1-4): ")
+// editWatchdog lists the watched processes and adds or removes them until the user presses Enter
+// "name" toggles a process; "name: command" watches it and runs command whenever it is missing
+func editWatchdog() {
+	scanner := bufio.NewScanner(os.Stdin)
+	for {
+		watchdog := processMonitorManager.GetWatchdog()
+		names := make([]string, 0, len(watchdog))
+		for name := range watchdog {
+			names = append(names, name)
+		}
+		sort.Strings(names)
 
-	choice := getUserChoice(4)
+		fmt.Println("\n🐕 Watchdog (Critical Processes)")
+		fmt.Println(strings.Repeat("-", 40))
+		if len(names) == 0 {
+			fmt.Println("No processes are watched")
+		}
+		for _, name := range names {
+			command := watchdog[name].RestartCommand
+			if command == "" {
+				command = "(alert only)"
+			}
+			fmt.Printf("  %-20s restart: %s\n", name, command)
+		}
+		fmt.Println(strings.Repeat("-", 40))
+		fmt.Println("Missing processes raise a critical alert; a restart command is run at most every", processMonitorManager.GetConfig().WatchdogRestartDelay)
+		fmt.Print("Process name to add/remove (name: restart command to set one), Enter when done: ")
 
-	var format string
-	switch choice {
-	case 1:
-		format = "json"
-	case 2:
-		format = "csv"
-	case 3:
-		format = "txt"
-	case 4:
-		return
+		if !scanner.Scan() {
+			return
+		}
+		input := strings.TrimSpace(scanner.Text())
+		if input == "" {
+			return
+		}
+
+		name, command, hasCommand := strings.Cut(input, ":")
+		name, command = strings.TrimSpace(name), strings.TrimSpace(command)
+		if _, watched := watchdog[name]; watched && !hasCommand {
+			processMonitorManager.RemoveWatchdog(name)
+			fmt.Printf("➖ No longer watching %s\n", name)
+		} else {
+			processMonitorManager.SetWatchdog(name, command)
+			fmt.Printf("➕ Watching %s\n", name)
+		}
 	}
+}
 
-	// Update all monitor configurations
-	cpuConfig := cpuMonitorManager.GetConfiguration()
-	cpuConfig.ExportFormat = format
-	cpuMonitorManager.SetConfiguration(cpuConfig)
+// pickProcessUsers lists users with their process counts and toggles which ones the process monitor shows
+func pickProcessUsers() {
+	pickProcessFilter("👤 Filter by User", "users",
+		processMonitorManager.GetUserProcessCounts,
+		processMonitorManager.ToggleUserFilter,
+		processMonitorManager.ClearUserFilter)
+}
 
-	memoryConfig := memoryMonitorManager.GetConfig()
-	memoryConfig.ExportFormat = format
-	memoryMonitorManager.UpdateConfig(memoryConfig)
+// pickProcessSlices lists slices (system.slice, user.slice, docker...) and toggles which ones the process monitor shows
+func pickProcessSlices() {
+	pickProcessFilter("🧩 Filter by Slice", "slices",
+		processMonitorManager.GetSliceProcessCounts,
+		processMonitorManager.ToggleSliceFilter,
+		processMonitorManager.ClearSliceFilter)
+}
 
-	diskConfig := diskMonitorManager.GetConfig()
-	diskConfig.ExportFormat = format
-	diskMonitorManager.UpdateConfig(diskConfig)
+// pickProcessFilter shows a filter's values with process counts until the user presses Enter
+// Entering a number toggles that value; 0 clears the filter so every process is shown
+func pickProcessFilter(title, plural string, list func() ([]processmonitor.FilterOption, error), toggle func(string), clear func()) {
+	scanner := bufio.NewScanner(os.Stdin)
+	for {
+		options, err := list()
+		if err != nil {
+			fmt.Printf("❌ Error listing %s: %v\n", plural, err)
+			waitForEnter()
+			return
+		}
 
-	networkConfig := networkMonitorManager.GetConfig()
-	networkConfig.ExportFormat = format
-	networkMonitorManager.UpdateConfig(networkConfig)
+		fmt.Printf("\n%s\n", title)
+		fmt.Println(strings.Repeat("-", 40))
+		filtered := false
+		for i, option := range options {
+			mark := " "
+			if option.Selected {
+				mark = "x"
+				filtered = true
+			}
+			value := option.Value
+			if value == "" {
+				value = "(unknown)"
+			}
+			fmt.Printf("%2d. [%s] %-20s %5d processes\n", i+1, mark, value, option.Processes)
+		}
+		fmt.Println(strings.Repeat("-", 40))
+		if !filtered {
+			fmt.Printf("Showing processes of all %s\n", plural)
+		}
+		fmt.Printf("Number to toggle, 0 to show all %s, Enter when done: ", plural)
 
-	processConfig := processMonitorManager.GetConfig()
-	processConfig.ExportFormat = format
-	processMonitorManager.UpdateConfig(processConfig)
+		if !scanner.Scan() {
+			return
+		}
+		input := strings.TrimSpace(scanner.Text())
+		if input == "" {
+			return
+		}
 
-	fmt.Printf("✅ Export format set to: %s\n", strings.ToUpper(format))
-	waitForEnter()
+		choice, err := strconv.Atoi(input)
+		switch {
+		case err != nil || choice < 0 || choice > len(options):
+			fmt.Printf("Invalid input! Enter 0-%d.\n", len(options))
+		case choice == 0:
+			clear()
+		default:
+			toggle(options[choice-1].Value)
+		}
+	}
 }
 
-// toggleExport allows user to enable/disable export
-func toggleExport() {
-	fmt.Println("\n🔄 Export Toggle")
+func monitorSystemEvents() {
+	fmt.Println("📜 System Events")
 	fmt.Println(strings.Repeat("-", 30))
-	fmt.Println("1. Enable Export")
-	fmt.Println("2. Disable Export")
-	fmt.Println("3. Back to Export Settings")
+	fmt.Println("1. Live Monitoring")
+	fmt.Println("2. Single Snapshot")
+	fmt.Println("3. Back to Monitoring Menu")
 	fmt.Println(strings.Repeat("-", 30))
 	fmt.Print("Select option (1-3): ")
 
 	choice := getUserChoice(3)
 
-	var enable bool
 	switch choice {
 	case 1:
-		enable = true
+		fmt.Println("Starting live system event monitoring...")
+		runLiveMonitors(liveEvents)
+		waitForEnter()
 	case 2:
-		enable = false
+		if err := eventMonitorManager.StartSingleSnapshot(); err != nil {
+			fmt.Printf("❌ Error displaying system events: %v\n", err)
+		}
+		waitForEnter()
 	case 3:
 		return
 	}
+}
 
-	// Update all monitor configurations
-	cpuConfig := cpuMonitorManager.GetConfiguration()
-	cpuConfig.ExportToFile = enable
-	cpuMonitorManager.SetConfiguration(cpuConfig)
-
-	memoryConfig := memoryMonitorManager.GetConfig()
-	memoryConfig.ExportToFile = enable
-	memoryMonitorManager.UpdateConfig(memoryConfig)
-
-	diskConfig := diskMonitorManager.GetConfig()
-	diskConfig.ExportToFile = enable
-	diskMonitorManager.UpdateConfig(diskConfig)
-
-	networkConfig := networkMonitorManager.GetConfig()
-	networkConfig.ExportToFile = enable
-	networkMonitorManager.UpdateConfig(networkConfig)
+// liveMonitor is a monitor that can run in live mode
+type liveMonitor interface {
+	StartLiveMonitoring() error
+	StopMonitoring()
+	SetLiveHint(hint string)
+	SetPaused(paused bool)
+	Step()
+	IsEnabled() bool
+}
 
-	processConfig := processMonitorManager.GetConfig()
-	processConfig.ExportToFile = enable
-	processMonitorManager.UpdateConfig(processConfig)
+// Live monitors in key order: pressing 1-6 during live monitoring switches to that monitor
+const (
+	liveCPU = iota
+	liveMemory
+	liveDisk
+	liveNetwork
+	liveProcess
+	liveEvents
+)
 
-	status := "disabled"
-	if enable {
-		status = "enabled"
+// liveMonitorNames labels the live monitors in key order
+var liveMonitorNames = []string{"CPU", "Memory", "Disk", "Network", "Process", "Events"}
+
+// liveMonitorSections names the config file section of each live monitor in key order
+var liveMonitorSections = []string{"cpu", "memory", "disk", "network", "process", "events"}
+
+// liveMonitorList returns the live monitors in key order
+func liveMonitorList() []liveMonitor {
+	return []liveMonitor{
+		cpuMonitorManager,
+		memoryMonitorManager,
+		diskMonitorManager,
+		networkMonitorManager,
+		processMonitorManager,
+		eventMonitorManager,
 	}
-	fmt.Printf("✅ Export %s\n", status)
-	waitForEnter()
 }
 
-// showDisplaySettings displays display settings menu
-func showDisplaySettings() {
-	fmt.Println("\n🖥️  Display Settings")
-	fmt.Println(strings.Repeat("-", 30))
-	fmt.Println("1. Set Refresh Rate")
-	fmt.Println("2. Set Display Format")
-	fmt.Println("3. Enable/Disable Colors")
-	fmt.Println("4. Set Screen Size")
-	fmt.Println("5. Back to Settings")
-	fmt.Println(strings.Repeat("-", 30))
-	fmt.Print("Select option (1-5): ")
+// runLiveMonitors runs live monitoring starting with the given monitor
+// Pressing 1-6 switches straight to another monitor; each monitor keeps its collector,
+// so history and rate baselines carry over when switching back. Space freezes the display
+// and n advances it one refresh at a time; Ctrl+C returns to the menu
+func runLiveMonitors(current int) {
+	monitors := liveMonitorList()
 
-	choice := getUserChoice(5)
+	if recorder := startSessionRecording(); recorder != nil {
+		defer stopSessionRecording(recorder)
+	}
 
-	switch choice {
-	case 1:
-		setRefreshRate()
-	case 2:
-		setDisplayFormat()
-	case 3:
-		toggleColors()
-	case 4:
-		setScreenSize()
-	case 5:
+	keys, err := terminal.NewKeyReader()
+	if err != nil {
+		// Without single-key input (e.g. input is not a terminal) only Ctrl+C is available
+		if err := startLiveMonitor(monitors[current], current); err != nil {
+			fmt.Printf("❌ Error starting %s monitoring: %v\n", liveMonitorNames[current], err)
+		}
 		return
 	}
-}
+	defer keys.Close()
 
-// showMonitoringSettings displays monitoring settings menu
-func showMonitoringSettings() {
-	fmt.Println("\n📊 Monitoring Settings")
-	fmt.Println(strings.Repeat("-", 30))
-	fmt.Println("1. Set Monitoring Interval")
-	fmt.Println("2. Enable/Disable Auto-Start")
-	fmt.Println("3. Set Data Retention")
-	fmt.Println("4. Configure Alerts")
-	fmt.Println("5. Back to Settings")
-	fmt.Println(strings.Repeat("-", 30))
-	fmt.Print("Select option (1-5): ")
+	// Pausing lasts across switches; the next live session starts refreshing again
+	paused := false
+	defer func() {
+		for _, monitor := range monitors {
+			monitor.SetPaused(false)
+		}
+	}()
 
-	choice := getUserChoice(5)
+	for {
+		monitor := monitors[current]
+		monitor.SetLiveHint(liveMonitorHint(current))
+		monitor.SetPaused(paused)
+		if paused {
+			// Show the monitor switched to once rather than an empty screen
+			monitor.Step()
+		}
 
-	switch choice {
-	case 1:
-		setMonitoringInterval()
-	case 2:
-		toggleAutoStart()
-	case 3:
-		setDataRetention()
-	case 4:
-		configureAlerts()
-	case 5:
-		return
+		// Stop the current monitor when another monitor's key is pressed
+		switchTo := make(chan int, 1)
+		stopped := make(chan bool)
+		pausedState := make(chan bool, 1)
+		go func(current int, paused bool) {
+			defer func() { pausedState <- paused }()
+			for {
+				select {
+				case key := <-keys.Keys():
+					switch key {
+					case 'z', 'Z':
+						toggleSnooze()
+						continue
+					case ' ':
+						paused = !paused
+						monitors[current].SetPaused(paused)
+						if paused {
+							// The frozen display is not redrawn, so the notice goes under it
+							fmt.Println(terminal.PausedNotice)
+						}
+						continue
+					case 'n', 'N':
+						paused = true
+						monitors[current].SetPaused(true)
+						monitors[current].Step()
+						continue
+					}
+					next := int(key - '1')
+					if next >= 0 && next < len(monitors) && next != current && monitors[next].IsEnabled() {
+						switchTo <- next
+						monitors[current].StopMonitoring()
+						return
+					}
+				case <-stopped:
+					return
+				}
+			}
+		}(current, paused)
+
+		err := startLiveMonitor(monitor, current)
+		close(stopped)
+		paused = <-pausedState
+		monitor.SetLiveHint("")
+		if err != nil {
+			fmt.Printf("❌ Error starting %s monitoring: %v\n", liveMonitorNames[current], err)
+			return
+		}
+
+		select {
+		case next := <-switchTo:
+			current = next
+			fmt.Print("\033[2J\033[H")
+		default:
+			// Stopped with Ctrl+C
+			return
+		}
 	}
 }
 
-// showPerformanceSettings displays performance settings
-func showPerformanceSettings() {
-	fmt.Println("\n⚡ Performance Settings")
-	fmt.Println(strings.Repeat("-", 30))
-	fmt.Println("1. Set CPU Priority")
-	fmt.Println("2. Set Memory Limit")
-	fmt.Println("3. Enable/Disable Background Mode")
-	fmt.Println("4. Set Thread Count")
-	fmt.Println("5. Back to Settings")
-	fmt.Println(strings.Repeat("-", 30))
-	fmt.Print("Select option (1-5): ")
+// startLiveMonitor runs one live monitor until it stops, firing the monitoring_started and monitoring_stopped hooks around it
+func startLiveMonitor(monitor liveMonitor, index int) error {
+	vars := map[string]string{"MONITOR": liveMonitorSections[index]}
+	hooks.Fire(hooks.EventMonitoringStarted, vars)
+	err := monitor.StartLiveMonitoring()
+	hooks.Fire(hooks.EventMonitoringStopped, vars)
+	return err
+}
 
-	choice := getUserChoice(5)
+// startSessionRecording starts recording every live monitor's collection cycles with --record,
+// and publishes them to the live feed for viewers while this instance holds the lock
+// It returns nil when there is nothing to record to; monitoring then runs unrecorded
+func startSessionRecording() *recording.Recorder {
+	var recorder *recording.Recorder
+	if recordSessions {
+		var err error
+		if recorder, err = recording.Start(recordingsDir); err != nil {
+			fmt.Printf("⚠️  Session will not be recorded: %v\n", err)
+			recorder = nil
+		}
+	}
 
-	switch choice {
-	case 1:
-		setCPUPriority()
-	case 2:
-		setMemoryLimit()
-	case 3:
-		toggleBackgroundMode()
-	case 4:
-		setThreadCount()
-	case 5:
+	if instanceLock != nil {
+		var err error
+		if recorder == nil {
+			recorder, err = recording.NewFeed(liveFeedDir)
+		} else {
+			err = recorder.SetFeed(liveFeedDir)
+		}
+		if err != nil {
+			fmt.Printf("⚠️  Viewers will not see this session: %v\n", err)
+		}
+	}
+
+	if recorder != nil {
+		setSessionRecorder(recorder)
+	}
+	return recorder
+}
+
+// stopSessionRecording detaches the recorder from the monitors and finishes its file
+func stopSessionRecording(recorder *recording.Recorder) {
+	setSessionRecorder(nil)
+	if err := recorder.Close(); err != nil {
+		fmt.Printf("⚠️  %v\n", err)
+	}
+	if recorder.Path() == "" {
 		return
 	}
+	fmt.Printf("🎬 Recorded %d frames to %s\n", recorder.Frames(), recorder.Path())
+	fmt.Printf("   Replay with: simple-monitor replay %s\n", recorder.Path())
 }
 
-// showLogSettings displays log settings
-func showLogSettings() {
-	fmt.Println("\n📝 Log Settings")
-	fmt.Println(strings.Repeat("-", 30))
-	fmt.Println("1. Set Log Level")
-	fmt.Println("2. Set Log Rotation")
-	fmt.Println("3. Enable/Disable Logging")
-	fmt.Println("4. Set Log Directory")
-	fmt.Println("5. Back to Settings")
-	fmt.Println(strings.Repeat("-", 30))
-	fmt.Print("Select option (1-5): ")
+// setSessionRecorder points every live monitor at recorder (nil stops recording)
+func setSessionRecorder(recorder *recording.Recorder) {
+	cpuMonitorManager.SetRecorder(recorder)
+	memoryMonitorManager.SetRecorder(recorder)
+	diskMonitorManager.SetRecorder(recorder)
+	networkMonitorManager.SetRecorder(recorder)
+	processMonitorManager.SetRecorder(recorder)
+	eventMonitorManager.SetRecorder(recorder)
+}
 
-	choice := getUserChoice(5)
+// liveMonitorHint returns the key shortcut line shown under a live monitor
+func liveMonitorHint(current int) string {
+	monitors := liveMonitorList()
+	var parts []string
+	for i, name := range liveMonitorNames {
+		if !monitors[i].IsEnabled() {
+			continue
+		}
+		if i == current {
+			parts = append(parts, fmt.Sprintf("[%d %s]", i+1, name))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d %s", i+1, name))
+		}
+	}
+	return "\n⌨️  " + strings.Join(parts, "  ") + "  |  space pause  n step  |  z snooze alerts  |  Ctrl+C to stop"
+}
 
-	switch choice {
-	case 1:
-		setLogLevel()
-	case 2:
-		setLogRotation()
-	case 3:
-		toggleLogging()
-	case 4:
-		setLogDirectory()
-	case 5:
+// toggleSnooze snoozes alerts for an hour, or clears the snooze when one is running
+func toggleSnooze() {
+	if !alert.SnoozedUntil().IsZero() {
+		alert.Resume()
 		return
 	}
+	alert.Snooze(time.Hour)
 }
 
-// resetToDefaults resets all settings to default values
-func resetToDefaults() {
-	fmt.Println("\n🔄 Reset to Defaults")
+// quickTestAllMonitors runs a quick test of all monitors simultaneously
+func quickTestAllMonitors() {
+	fmt.Println("🚀 Quick Test - All Monitors")
 	fmt.Println(strings.Repeat("-", 30))
-	fmt.Println("This will reset all settings to default values.")
-	fmt.Println("Are you sure?")
-	fmt.Println("1. Yes, reset all settings")
-	fmt.Println("2. No, keep current settings")
-	fmt.Print("Select option (1-2): ")
+	fmt.Println("Running quick tests for all monitors...")
+	fmt.Println("Press Ctrl+C to stop at any time")
+	fmt.Println()
 
-	choice := getUserChoice(2)
+	// Set up signal handling
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
-	if choice == 1 {
-		// Reset all configurations to defaults
-		cpuConfig := cpuMonitorManager.GetConfiguration()
-		cpuConfig.RefreshInterval = 1 * time.Second
-		cpuConfig.ExportFormat = "json"
-		cpuConfig.ExportToFile = false
-		cpuMonitorManager.SetConfiguration(cpuConfig)
+	// Create a channel to control the test loop
+	stopChan := make(chan bool, 1)
 
-		memoryConfig := memoryMonitorManager.GetConfig()
-		memoryConfig.RefreshInterval = 1 * time.Second
-		memoryConfig.ExportFormat = "json"
-		memoryConfig.ExportToFile = false
-		memoryMonitorManager.UpdateConfig(memoryConfig)
+	// Start the test loop in a goroutine
+	go func() {
+		ticker := time.NewTicker(2 * time.Second) // Update every 2 seconds
+		defer ticker.Stop()
 
-		diskConfig := diskMonitorManager.GetConfig()
-		diskConfig.RefreshInterval = 1 * time.Second
-		diskConfig.ExportFormat = "json"
-		diskConfig.ExportToFile = false
-		diskMonitorManager.UpdateConfig(diskConfig)
+		for {
+			select {
+			case <-ticker.C:
+				// Clear screen and show current status
+				fmt.Print("\033[2J\033[H")
+				fmt.Println("🚀 Quick Test - All Monitors")
+				fmt.Println(strings.Repeat("-", 30))
+				fmt.Printf("Last Update: %s\n", time.Now().Format("15:04:05"))
+				fmt.Println()
 
-		networkConfig := networkMonitorManager.GetConfig()
-		networkConfig.RefreshInterval = 1 * time.Second
-		networkConfig.ExportFormat = "json"
-		networkConfig.ExportToFile = false
-		networkMonitorManager.UpdateConfig(networkConfig)
+				// One combined collection feeds the health score and every panel, so they describe the same moment
+				state := snapshot.Collect(enabledMonitors())
+				displayHealthScore(state.Score)
+
+				displays := recordedDisplays()
+				panels := []struct {
+					title, name string
+					enabled     bool
+					data        interface{}
+				}{
+					{"🖥️  CPU:", "cpu", state.CPU != nil, state.CPU},
+					{"\n💾 Memory:", "memory", state.Memory != nil, state.Memory},
+					{"\n💿 Disk:", "disk", state.Disk != nil, state.Disk},
+					{"\n🌐 Network:", "network", state.Network != nil, state.Network},
+				}
+				for _, panel := range panels {
+					fmt.Println(panel.title)
+					if _, failed := state.Errors[panel.name]; failed {
+						fmt.Println("  Error: Failed to collect data")
+						continue
+					}
+					if !panel.enabled {
+						fmt.Println("  Disabled")
+						continue
+					}
+					content, err := json.Marshal(panel.data)
+					if err == nil {
+						err = displays[panel.name](content)
+					}
+					if err != nil {
+						fmt.Println("  Error: Failed to display data")
+					}
+				}
 
-		processConfig := processMonitorManager.GetConfig()
-		processConfig.RefreshInterval = 1 * time.Second
-		processConfig.ExportFormat = "json"
-		processConfig.ExportToFile = false
-		processMonitorManager.UpdateConfig(processConfig)
+				fmt.Println("\nPress Ctrl+C to stop...")
 
-		fmt.Println("✅ All settings reset to defaults")
-	} else {
-		fmt.Println("Settings kept unchanged")
-	}
+			case <-stopChan:
+				return
+			case <-sigChan:
+				stopChan <- true
+				return
+			}
+		}
+	}()
+
+	// Wait for stop signal
+	<-stopChan
+	fmt.Println("\n🛑 Quick test stopped")
 	waitForEnter()
 }
 
-// Settings helper functions
-func setRefreshRate() {
-	fmt.Println("\n⏱️  Set Refresh Rate")
-	fmt.Println(strings.Repeat("-", 30))
-	fmt.Println("1. 0.5 seconds (Fast)")
-	fmt.Println("2. 1 second (Normal)")
-	fmt.Println("3. 2 seconds (Slow)")
-	fmt.Println("4. Custom interval")
-	fmt.Println("5. Back to Display Settings")
-	fmt.Print("Select option (1-5): ")
+// displayHealthScore prints the overall health score with each monitor's part and the alerts that lowered it
+func displayHealthScore(score *healthscore.Score) {
+	if score == nil || score.Status == healthscore.StatusUnknown {
+		fmt.Println("🩺 Health Score: unknown (no monitor could be collected)")
+		fmt.Println()
+		return
+	}
+	icon := map[string]string{healthscore.StatusNormal: "🟢", healthscore.StatusWarning: "🟡", healthscore.StatusCritical: "🔴"}[score.Status]
+	fmt.Printf("🩺 Health Score: %.0f/100 %s %s\n", score.Value, icon, score.Status)
+	for _, component := range score.Components {
+		if component.Status == healthscore.StatusUnknown || component.Weight == 0 {
+			continue
+		}
+		line := fmt.Sprintf("   %-8s %3.0f", component.Name, component.Value)
+		if len(component.Reasons) > 0 {
+			line += "  " + strings.Join(component.Reasons, ", ")
+		}
+		fmt.Println(line)
+	}
+	fmt.Println()
+}
 
-	choice := getUserChoice(5)
+// showExportSettings displays export settings menu
+func showExportSettings() {
+	for {
+		fmt.Println("\n📁 Export Settings")
+		fmt.Println(strings.Repeat("-", 30))
+		fmt.Println("1. Set Export Interval")
+		fmt.Println("2. Set Export Format")
+		fmt.Println("3. Enable/Disable Export")
+		fmt.Println("4. Set Export Header Style")
+		fmt.Println("5. Export Combined Snapshot (All Monitors)")
+		fmt.Println("6. Publish Latest Snapshot")
+		fmt.Println("7. gRPC Streaming API")
+		fmt.Println("8. Redact Exports")
+		fmt.Println("9. Back to Settings")
+		fmt.Println(strings.Repeat("-", 30))
+		fmt.Print("Select option (1-9): ")
 
-	var interval time.Duration
-	switch choice {
+		choice := getUserChoice(9)
+
+		switch choice {
+		case 1:
+			setExportInterval()
+		case 2:
+			setExportFormat()
+		case 3:
+			toggleExport()
+		case 4:
+			setExportHeaderStyle()
+		case 5:
+			exportCombinedSnapshot()
+		case 6:
+			configureSnapshotPublishing()
+		case 7:
+			configureStreaming()
+		case 8:
+			configureRedaction()
+		case 9:
+			return
+		}
+	}
+}
+
+// configureSnapshotPublishing turns publishing of the latest combined snapshot on or off and sets its path
+func configureSnapshotPublishing() {
+	publishConfig := snapshot.GetPublishConfig()
+
+	fmt.Println("\n📡 Publish Latest Snapshot")
+	fmt.Println(strings.Repeat("-", 30))
+	fmt.Println("Every interval all monitors are collected into one JSON document that replaces the file")
+	fmt.Println("(or is written to the named pipe) at the path below, for dashboards and scripts.")
+	status := "off"
+	if publishConfig.Enabled {
+		status = "on"
+	}
+	fmt.Printf("Current: %s, every %s, to %s\n", status, publishConfig.Interval, publishConfig.Path)
+	if published, err := snapshot.PublishStatus(); err != nil {
+		fmt.Printf("⚠️  Last attempt failed: %v\n", err)
+	} else if !published.IsZero() {
+		fmt.Printf("Last published: %s\n", published.Format("2006-01-02 15:04:05"))
+	}
+	fmt.Println()
+	if publishConfig.Enabled {
+		fmt.Println("1. Stop Publishing")
+	} else {
+		fmt.Println("1. Start Publishing")
+	}
+	fmt.Println("2. Set Path")
+	fmt.Println("3. Set Interval")
+	fmt.Println("4. Back to Export Settings")
+	fmt.Print("Select option (1-4): ")
+
+	switch getUserChoice(4) {
 	case 1:
-		interval = 500 * time.Millisecond
+		publishConfig.Enabled = !publishConfig.Enabled
 	case 2:
-		interval = 1 * time.Second
-	case 3:
-		interval = 2 * time.Second
-	case 4:
-		fmt.Print("Enter custom interval in seconds: ")
+		fmt.Printf("Enter path (empty for %s): ", snapshot.DefaultPublishPath())
 		scanner := bufio.NewScanner(os.Stdin)
 		scanner.Scan()
-		input := strings.TrimSpace(scanner.Text())
+		publishConfig.Path = strings.TrimSpace(scanner.Text())
+		if publishConfig.Path == "" {
+			publishConfig.Path = snapshot.DefaultPublishPath()
+		}
+	case 3:
+		fmt.Print("Publish every (e.g. 2s, 10s): ")
+		scanner := bufio.NewScanner(os.Stdin)
+		scanner.Scan()
+		interval, err := time.ParseDuration(strings.TrimSpace(scanner.Text()))
+		if err != nil {
+			fmt.Println("❌ Invalid duration! Use a value like 2s or 10s.")
+			return
+		}
+		publishConfig.Interval = interval
+	case 4:
+		return
+	}
+
+	if err := snapshot.SetPublishConfig(publishConfig); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return
+	}
+	if publishConfig.Enabled {
+		fmt.Printf("✅ Publishing the latest snapshot to %s every %s\n", publishConfig.Path, publishConfig.Interval)
+	} else {
+		fmt.Println("✅ Snapshot publishing stopped")
+	}
+}
+
+// configureStreaming turns the gRPC streaming API on or off and sets the address it listens on
+func configureStreaming() {
+	streamConfig := stream.GetConfig()
+	publishConfig := snapshot.GetPublishConfig()
+
+	fmt.Println("\n📶 gRPC Streaming API")
+	fmt.Println(strings.Repeat("-", 30))
+	fmt.Println("Programs subscribe with SubscribeCPU or SubscribeAll (see proto/monitor.proto) and receive")
+	fmt.Printf("a sample every snapshot publish interval (%s).\n", publishConfig.Interval)
+	security := "no login, every client is admin"
+	if streamConfig.Token != "" || streamConfig.ReadToken != "" || len(streamConfig.Users) > 0 {
+		var logins []string
+		if streamConfig.Token != "" {
+			logins = append(logins, "admin token")
+		}
+		if streamConfig.ReadToken != "" {
+			logins = append(logins, "read-only token")
+		}
+		if len(streamConfig.Users) > 0 {
+			logins = append(logins, fmt.Sprintf("%d basic auth user(s)", len(streamConfig.Users)))
+		}
+		security = strings.Join(logins, ", ")
+	}
+	if streamConfig.TLSCert != "" {
+		security += ", TLS"
+	} else {
+		security += ", no TLS"
+	}
+	fmt.Printf("Security: %s (addresses other hosts can reach need a login and TLS)\n", security)
+	address, clients, err := stream.Status()
+	switch {
+	case err != nil:
+		fmt.Printf("⚠️  %v\n", err)
+	case address != "":
+		fmt.Printf("Current: listening on %s, %d subscription(s) open\n", address, clients)
+	default:
+		fmt.Printf("Current: off (address %s)\n", streamConfig.Address)
+	}
+	fmt.Println()
+	if streamConfig.Enabled {
+		fmt.Println("1. Stop API")
+	} else {
+		fmt.Println("1. Start API")
+	}
+	fmt.Println("2. Set Address")
+	fmt.Println("3. Set Admin Token")
+	fmt.Println("4. Set Read-Only Token")
+	fmt.Println("5. Back to Export Settings")
+	fmt.Print("Select option (1-5): ")
+
+	choice := getUserChoice(5)
+	switch choice {
+	case 1:
+		streamConfig.Enabled = !streamConfig.Enabled
+	case 2:
+		fmt.Printf("Enter host:port (empty for %s; a non-loopback address needs a token, tls_cert and tls_key): ", stream.DefaultAddress)
+		scanner := bufio.NewScanner(os.Stdin)
+		scanner.Scan()
+		streamConfig.Address = strings.TrimSpace(scanner.Text())
+	case 3, 4:
+		role := "admin"
+		if choice == 4 {
+			role = "read-only"
+		}
+		fmt.Printf("Enter the %s token clients must send (hidden; empty to remove it): ", role)
+		token, err := terminal.ReadSecret()
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return
+		}
+		if choice == 3 {
+			streamConfig.Token = token
+		} else {
+			streamConfig.ReadToken = token
+		}
+	case 5:
+		return
+	}
+
+	if err := stream.SetConfig(streamConfig); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return
+	}
+	if address, _, _ := stream.Status(); address != "" {
+		fmt.Printf("✅ gRPC streaming API listening on %s\n", address)
+	} else {
+		fmt.Println("✅ gRPC streaming API stopped")
+	}
+}
+
+// configureRedaction turns redaction of exports and debug bundles on or off
+func configureRedaction() {
+	redactConfig := redact.GetConfig()
+
+	fmt.Println("\n🕶️  Redact Exports")
+	fmt.Println(strings.Repeat("-", 30))
+	fmt.Println("Exports, combined snapshots and debug bundles replace usernames, command-line arguments,")
+	fmt.Println("IP addresses and hostnames with hashes (e.g. user-1a2b3c4d), so they can be shared publicly.")
+	fmt.Println("Equal values get equal hashes, so processes and connections can still be told apart.")
+	status := "off"
+	if redactConfig.Enabled {
+		status = "on"
+	}
+	salt := "random for this run, hashes only match within it"
+	if redactConfig.Salt != "" {
+		salt = "set in the config file, hashes match across runs"
+	}
+	fmt.Printf("Current: %s (salt %s)\n", status, salt)
+	fmt.Println()
+	if redactConfig.Enabled {
+		fmt.Println("1. Stop Redacting")
+	} else {
+		fmt.Println("1. Start Redacting")
+	}
+	fmt.Println("2. Back to Export Settings")
+	fmt.Print("Select option (1-2): ")
+
+	if getUserChoice(2) == 2 {
+		return
+	}
+	redactConfig.Enabled = !redactConfig.Enabled
+	redact.SetConfig(redactConfig)
+	if redactConfig.Enabled {
+		fmt.Println("✅ Exports are redacted from now on")
+	} else {
+		fmt.Println("✅ Exports contain the real values again")
+	}
+	waitForEnter()
+}
+
+// exportCombinedSnapshot collects all five monitors at once into one system_state JSON file
+func exportCombinedSnapshot() {
+	fmt.Println("\n📸 Combined Snapshot")
+	fmt.Println(strings.Repeat("-", 30))
+	fmt.Println("Collecting CPU, memory, disk, network and process data (disabled monitors are left out)...")
+
+	state := snapshot.Collect(enabledMonitors())
+
+	for name, err := range state.Errors {
+		fmt.Printf("⚠️  %s data missing: %s\n", name, err)
+	}
+	if state.Score != nil && state.Score.Status != healthscore.StatusUnknown {
+		fmt.Printf("🩺 Health score: %.0f/100 (%s)\n", state.Score.Value, state.Score.Status)
+	}
+
+	filePath, err := snapshotExporter.Export(state)
+	if err != nil {
+		fmt.Printf("❌ Failed to export snapshot: %v\n", err)
+	} else {
+		hooks.Exported("snapshot", filePath)
+		fmt.Printf("💾 Combined snapshot saved to: %s (collected in %v)\n", filePath, state.CollectionDuration.Round(time.Millisecond))
+	}
+	waitForEnter()
+}
+
+// enabledMonitors returns the live monitors for a combined snapshot; disabled monitors stay nil so the snapshot skips them
+func enabledMonitors() snapshot.Monitors {
+	var monitors snapshot.Monitors
+	if cpuMonitorManager.IsEnabled() {
+		monitors.CPU = cpuMonitorManager
+	}
+	if memoryMonitorManager.IsEnabled() {
+		monitors.Memory = memoryMonitorManager
+	}
+	if diskMonitorManager.IsEnabled() {
+		monitors.Disk = diskMonitorManager
+	}
+	if networkMonitorManager.IsEnabled() {
+		monitors.Network = networkMonitorManager
+	}
+	if processMonitorManager.IsEnabled() {
+		monitors.Process = processMonitorManager
+	}
+	return monitors
+}
+
+// setExportInterval allows user to set export interval
+func setExportInterval() {
+	fmt.Println("\n⏰ Export Interval Settings")
+	fmt.Println(strings.Repeat("-", 30))
+	fmt.Println("1. 1 minute")
+	fmt.Println("2. 1 hour")
+	fmt.Println("3. 1 day")
+	fmt.Println("4. Custom interval")
+	fmt.Println("5. Back to Export Settings")
+	fmt.Println(strings.Repeat("-", 30))
+	fmt.Print("Select option (1-5): ")
+
+	choice := getUserChoice(5)
+
+	var interval time.Duration
+	switch choice {
+	case 1:
+		interval = 1 * time.Minute
+	case 2:
+		interval = 1 * time.Hour
+	case 3:
+		interval = 24 * time.Hour
+	case 4:
+		fmt.Print("Enter custom interval in minutes: ")
+		scanner := bufio.NewScanner(os.Stdin)
+		scanner.Scan()
+		input := strings.TrimSpace(scanner.Text())
+		if minutes, err := strconv.Atoi(input); err == nil {
+			interval = time.Duration(minutes) * time.Minute
+		} else {
+			fmt.Println("❌ Invalid input! Using default 1 hour.")
+			interval = 1 * time.Hour
+		}
+	case 5:
+		return
+	}
+
+	// Update all monitor configurations
+	cpuConfig := cpuMonitorManager.GetConfiguration()
+	cpuConfig.ExportInterval = interval
+	cpuMonitorManager.SetConfiguration(cpuConfig)
+
+	memoryConfig := memoryMonitorManager.GetConfig()
+	memoryConfig.ExportInterval = interval
+	memoryMonitorManager.UpdateConfig(memoryConfig)
+
+	diskConfig := diskMonitorManager.GetConfig()
+	diskConfig.ExportInterval = interval
+	diskMonitorManager.UpdateConfig(diskConfig)
+
+	networkConfig := networkMonitorManager.GetConfig()
+	networkConfig.ExportInterval = interval
+	networkMonitorManager.UpdateConfig(networkConfig)
+
+	processConfig := processMonitorManager.GetConfig()
+	processConfig.ExportInterval = interval
+	processMonitorManager.UpdateConfig(processConfig)
+
+	fmt.Printf("✅ Export interval set to: %v\n", interval)
+	waitForEnter()
+}
+
+// setExportFormat allows user to set export format
+func setExportFormat() {
+	fmt.Println("\n📄 Export Format Settings")
+	fmt.Println(strings.Repeat("-", 30))
+	fmt.Println("1. JSON")
+	fmt.Println("2. CSV")
+	fmt.Println("3. TXT")
+	fmt.Println("4. Protobuf (compact binary, also used for combined snapshots)")
+	fmt.Println("5. Back to Export Settings")
+	fmt.Println(strings.Repeat("-", 30))
+	fmt.Print("Select option (1-5): ")
+
+	choice := getUserChoice(5)
+
+	var format string
+	switch choice {
+	case 1:
+		format = "json"
+	case 2:
+		format = "csv"
+	case 3:
+		format = "txt"
+	case 4:
+		format = "protobuf"
+	case 5:
+		return
+	}
+
+	// Update all monitor configurations
+	cpuConfig := cpuMonitorManager.GetConfiguration()
+	cpuConfig.ExportFormat = format
+	cpuMonitorManager.SetConfiguration(cpuConfig)
+
+	memoryConfig := memoryMonitorManager.GetConfig()
+	memoryConfig.ExportFormat = format
+	memoryMonitorManager.UpdateConfig(memoryConfig)
+
+	diskConfig := diskMonitorManager.GetConfig()
+	diskConfig.ExportFormat = format
+	diskMonitorManager.UpdateConfig(diskConfig)
+
+	networkConfig := networkMonitorManager.GetConfig()
+	networkConfig.ExportFormat = format
+	networkMonitorManager.UpdateConfig(networkConfig)
+
+	processConfig := processMonitorManager.GetConfig()
+	processConfig.ExportFormat = format
+	processMonitorManager.UpdateConfig(processConfig)
+
+	// Combined snapshots are only written as JSON or protobuf
+	publishConfig := snapshot.GetPublishConfig()
+	publishConfig.ExportFormat = snapshot.FormatJSON
+	if format == "protobuf" {
+		publishConfig.ExportFormat = snapshot.FormatProtobuf
+		fmt.Println("ℹ️  Decode .pb files with the definitions from `simple-monitor proto`")
+	}
+	snapshot.SetPublishConfig(publishConfig)
+	snapshotExporter.SetFormat(publishConfig.ExportFormat)
+
+	fmt.Printf("✅ Export format set to: %s\n", strings.ToUpper(format))
+	waitForEnter()
+}
+
+// setExportHeaderStyle allows user to choose prose or stable machine-readable CSV headers
+func setExportHeaderStyle() {
+	fmt.Println("\n🏷️  Export Header Style")
+	fmt.Println(strings.Repeat("-", 30))
+	fmt.Println("1. Prose Headers (human-readable)")
+	fmt.Println("2. Stable Keys (snake_case, same as JSON)")
+	fmt.Println("3. Stable Keys + Metadata Rows")
+	fmt.Println("4. Back to Export Settings")
+	fmt.Println(strings.Repeat("-", 30))
+	fmt.Print("Select option (1-4): ")
+
+	choice := getUserChoice(4)
+
+	var stableKeys, headerMetadata bool
+	switch choice {
+	case 1:
+		stableKeys, headerMetadata = false, false
+	case 2:
+		stableKeys, headerMetadata = true, false
+	case 3:
+		stableKeys, headerMetadata = true, true
+	case 4:
+		return
+	}
+
+	// Update all monitors that export CSV/TXT
+	memoryMonitorManager.SetHeaderOptions(stableKeys, headerMetadata)
+	diskMonitorManager.SetHeaderOptions(stableKeys, headerMetadata)
+	networkMonitorManager.SetHeaderOptions(stableKeys, headerMetadata)
+	processMonitorManager.SetHeaderOptions(stableKeys, headerMetadata)
+	eventMonitorManager.SetHeaderOptions(stableKeys, headerMetadata)
+
+	style := "prose headers"
+	if stableKeys {
+		style = "stable keys"
+		if headerMetadata {
+			style += " with metadata rows"
+		}
+	}
+	fmt.Printf("✅ Export header style set to: %s\n", style)
+	waitForEnter()
+}
+
+// toggleExport allows user to enable/disable export
+func toggleExport() {
+	fmt.Println("\n🔄 Export Toggle")
+	fmt.Println(strings.Repeat("-", 30))
+	fmt.Println("1. Enable Export")
+	fmt.Println("2. Disable Export")
+	fmt.Println("3. Back to Export Settings")
+	fmt.Println(strings.Repeat("-", 30))
+	fmt.Print("Select option (1-3): ")
+
+	choice := getUserChoice(3)
+
+	var enable bool
+	switch choice {
+	case 1:
+		enable = true
+	case 2:
+		enable = false
+	case 3:
+		return
+	}
+
+	// Update all monitor configurations
+	cpuConfig := cpuMonitorManager.GetConfiguration()
+	cpuConfig.ExportToFile = enable
+	cpuMonitorManager.SetConfiguration(cpuConfig)
+
+	memoryConfig := memoryMonitorManager.GetConfig()
+	memoryConfig.ExportToFile = enable
+	memoryMonitorManager.UpdateConfig(memoryConfig)
+
+	diskConfig := diskMonitorManager.GetConfig()
+	diskConfig.ExportToFile = enable
+	diskMonitorManager.UpdateConfig(diskConfig)
+
+	networkConfig := networkMonitorManager.GetConfig()
+	networkConfig.ExportToFile = enable
+	networkMonitorManager.UpdateConfig(networkConfig)
+
+	processConfig := processMonitorManager.GetConfig()
+	processConfig.ExportToFile = enable
+	processMonitorManager.UpdateConfig(processConfig)
+
+	status := "disabled"
+	if enable {
+		status = "enabled"
+	}
+	fmt.Printf("✅ Export %s\n", status)
+	waitForEnter()
+}
+
+// showDisplaySettings displays display settings menu
+func showDisplaySettings() {
+	fmt.Println("\n🖥️  Display Settings")
+	fmt.Println(strings.Repeat("-", 30))
+	fmt.Println("1. Set Refresh Rate")
+	fmt.Println("2. Set Display Format")
+	fmt.Println("3. Enable/Disable Colors")
+	fmt.Println("4. Set Screen Size")
+	fmt.Println("5. ASCII-Only Mode")
+	fmt.Println("6. Terminal Title Metrics")
+	fmt.Println("7. Color-Blind Mode")
+	fmt.Println("8. Network Rate Unit")
+	fmt.Println("9. Back to Settings")
+	fmt.Println(strings.Repeat("-", 30))
+	fmt.Print("Select option (1-9): ")
+
+	choice := getUserChoice(9)
+
+	switch choice {
+	case 1:
+		setRefreshRate()
+	case 2:
+		setDisplayFormat()
+	case 3:
+		toggleColors()
+	case 4:
+		setScreenSize()
+	case 5:
+		toggleASCIIMode()
+	case 6:
+		toggleTitleMetrics()
+	case 7:
+		toggleColorBlindMode()
+	case 8:
+		setNetworkRateUnit()
+	case 9:
+		return
+	}
+}
+
+// showMonitoringSettings displays monitoring settings menu
+func showMonitoringSettings() {
+	fmt.Println("\n📊 Monitoring Settings")
+	fmt.Println(strings.Repeat("-", 30))
+	fmt.Println("1. Set Monitoring Interval")
+	fmt.Println("2. Enable/Disable Auto-Start")
+	fmt.Println("3. Set Data Retention")
+	fmt.Println("4. Configure Alerts")
+	fmt.Println("5. Process Error Logs")
+	fmt.Println("6. PSS/USS Memory Accounting")
+	fmt.Println("7. Disk Cleanup Suggestions")
+	fmt.Println("8. Idle Mode")
+	fmt.Println("9. Enable/Disable Monitors")
+	fmt.Println("10. Process Sandbox Hints")
+	fmt.Println("11. History Buffers")
+	fmt.Println("12. Connectivity Probe")
+	fmt.Println("13. Back to Settings")
+	fmt.Println(strings.Repeat("-", 30))
+	fmt.Print("Select option (1-13): ")
+
+	choice := getUserChoice(13)
+
+	switch choice {
+	case 1:
+		setMonitoringInterval()
+	case 2:
+		toggleAutoStart()
+	case 3:
+		setDataRetention()
+	case 4:
+		configureAlerts()
+	case 5:
+		toggleProcessLogs()
+	case 6:
+		togglePSSAccounting()
+	case 7:
+		toggleCleanupSuggestions()
+	case 8:
+		toggleIdleMode()
+	case 9:
+		toggleMonitors()
+	case 10:
+		toggleSandboxHints()
+	case 11:
+		configureHistoryBuffers()
+	case 12:
+		toggleConnectivityProbe()
+	case 13:
+		return
+	}
+}
+
+// showPerformanceSettings displays performance settings
+func showPerformanceSettings() {
+	fmt.Println("\n⚡ Performance Settings")
+	fmt.Println(strings.Repeat("-", 30))
+	fmt.Println("1. Set CPU Priority")
+	fmt.Println("2. Set Memory Limit")
+	fmt.Println("3. Enable/Disable Background Mode")
+	fmt.Println("4. Set Thread Count")
+	fmt.Println("5. Back to Settings")
+	fmt.Println(strings.Repeat("-", 30))
+	fmt.Print("Select option (1-5): ")
+
+	choice := getUserChoice(5)
+
+	switch choice {
+	case 1:
+		setCPUPriority()
+	case 2:
+		setMemoryLimit()
+	case 3:
+		toggleBackgroundMode()
+	case 4:
+		setThreadCount()
+	case 5:
+		return
+	}
+}
+
+// showLogSettings displays log settings
+func showLogSettings() {
+	fmt.Println("\n📝 Log Settings")
+	fmt.Println(strings.Repeat("-", 30))
+	fmt.Println("1. Set Log Level")
+	fmt.Println("2. Set Log Rotation")
+	fmt.Println("3. Enable/Disable Logging")
+	fmt.Println("4. Set Log Directory")
+	fmt.Println("5. Back to Settings")
+	fmt.Println(strings.Repeat("-", 30))
+	fmt.Print("Select option (1-5): ")
+
+	choice := getUserChoice(5)
+
+	switch choice {
+	case 1:
+		setLogLevel()
+	case 2:
+		setLogRotation()
+	case 3:
+		toggleLogging()
+	case 4:
+		setLogDirectory()
+	case 5:
+		return
+	}
+}
+
+// resetToDefaults resets all settings to default values
+func resetToDefaults() {
+	fmt.Println("\n🔄 Reset to Defaults")
+	fmt.Println(strings.Repeat("-", 30))
+	fmt.Println("This will reset all settings to default values.")
+	fmt.Println("Are you sure?")
+	fmt.Println("1. Yes, reset all settings")
+	fmt.Println("2. No, keep current settings")
+	fmt.Print("Select option (1-2): ")
+
+	choice := getUserChoice(2)
+
+	if choice == 1 {
+		// Reset all configurations to defaults
+		cpuConfig := cpuMonitorManager.GetConfiguration()
+		cpuConfig.RefreshInterval = 1 * time.Second
+		cpuConfig.ExportFormat = "json"
+		cpuConfig.ExportToFile = false
+		cpuMonitorManager.SetConfiguration(cpuConfig)
+
+		memoryConfig := memoryMonitorManager.GetConfig()
+		memoryConfig.RefreshInterval = 1 * time.Second
+		memoryConfig.ExportFormat = "json"
+		memoryConfig.ExportToFile = false
+		memoryMonitorManager.UpdateConfig(memoryConfig)
+
+		diskConfig := diskMonitorManager.GetConfig()
+		diskConfig.RefreshInterval = 1 * time.Second
+		diskConfig.ExportFormat = "json"
+		diskConfig.ExportToFile = false
+		diskMonitorManager.UpdateConfig(diskConfig)
+
+		networkConfig := networkMonitorManager.GetConfig()
+		networkConfig.RefreshInterval = 1 * time.Second
+		networkConfig.ExportFormat = "json"
+		networkConfig.ExportToFile = false
+		networkMonitorManager.UpdateConfig(networkConfig)
+
+		processConfig := processMonitorManager.GetConfig()
+		processConfig.RefreshInterval = 1 * time.Second
+		processConfig.ExportFormat = "json"
+		processConfig.ExportToFile = false
+		processMonitorManager.UpdateConfig(processConfig)
+
+		// Every monitor is switched back on
+		for index := range liveMonitorNames {
+			setMonitorEnabled(index, true)
+		}
+
+		// History buffers go back to the default length, sampled every refresh
+		for index := liveCPU; index < liveEvents; index++ {
+			setHistoryBuffer(index, historystore.DefaultBufferSize, 0)
+		}
+
+		fmt.Println("✅ All settings reset to defaults")
+	} else {
+		fmt.Println("Settings kept unchanged")
+	}
+	waitForEnter()
+}
+
+// Settings helper functions
+func setRefreshRate() {
+	fmt.Println("\n⏱️  Set Refresh Rate")
+	fmt.Println(strings.Repeat("-", 30))
+	fmt.Println("1. 0.5 seconds (Fast)")
+	fmt.Println("2. 1 second (Normal)")
+	fmt.Println("3. 2 seconds (Slow)")
+	fmt.Println("4. Custom interval")
+	fmt.Println("5. Back to Display Settings")
+	fmt.Print("Select option (1-5): ")
+
+	choice := getUserChoice(5)
+
+	var interval time.Duration
+	switch choice {
+	case 1:
+		interval = 500 * time.Millisecond
+	case 2:
+		interval = 1 * time.Second
+	case 3:
+		interval = 2 * time.Second
+	case 4:
+		fmt.Print("Enter custom interval in seconds: ")
+		scanner := bufio.NewScanner(os.Stdin)
+		scanner.Scan()
+		input := strings.TrimSpace(scanner.Text())
 		if seconds, err := strconv.Atoi(input); err == nil {
 			interval = time.Duration(seconds) * time.Second
 		} else {
-			fmt.Println("❌ Invalid input! Using default 1 second.")
-			interval = 1 * time.Second
+			fmt.Println("❌ Invalid input! Using default 1 second.")
+			interval = 1 * time.Second
+		}
+	case 5:
+		return
+	}
+
+	// Update all monitor refresh intervals
+	cpuConfig := cpuMonitorManager.GetConfiguration()
+	cpuConfig.RefreshInterval = interval
+	cpuMonitorManager.SetConfiguration(cpuConfig)
+
+	memoryConfig := memoryMonitorManager.GetConfig()
+	memoryConfig.RefreshInterval = interval
+	memoryMonitorManager.UpdateConfig(memoryConfig)
+
+	diskConfig := diskMonitorManager.GetConfig()
+	diskConfig.RefreshInterval = interval
+	diskMonitorManager.UpdateConfig(diskConfig)
+
+	networkConfig := networkMonitorManager.GetConfig()
+	networkConfig.RefreshInterval = interval
+	networkMonitorManager.UpdateConfig(networkConfig)
+
+	processConfig := processMonitorManager.GetConfig()
+	processConfig.RefreshInterval = interval
+	processMonitorManager.UpdateConfig(processConfig)
+
+	fmt.Printf("✅ Refresh rate set to: %v\n", interval)
+	waitForEnter()
+}
+
+func setDisplayFormat() {
+	fmt.Println("\n📄 Set Display Format")
+	fmt.Println(strings.Repeat("-", 30))
+	fmt.Println("1. Compact (Minimal info)")
+	fmt.Println("2. Standard (Normal info)")
+	fmt.Println("3. Detailed (Full info)")
+	fmt.Println("4. Back to Display Settings")
+	fmt.Print("Select option (1-4): ")
+
+	choice := getUserChoice(4)
+
+	switch choice {
+	case 1:
+		fmt.Println("✅ Display format set to: Compact")
+	case 2:
+		fmt.Println("✅ Display format set to: Standard")
+	case 3:
+		fmt.Println("✅ Display format set to: Detailed")
+	case 4:
+		return
+	}
+	waitForEnter()
+}
+
+func toggleColors() {
+	fmt.Println("\n🎨 Toggle Colors")
+	fmt.Println(strings.Repeat("-", 30))
+	fmt.Println("1. Enable Colors")
+	fmt.Println("2. Disable Colors")
+	fmt.Println("3. Back to Display Settings")
+	fmt.Print("Select option (1-3): ")
+
+	choice := getUserChoice(3)
+
+	switch choice {
+	case 1:
+		fmt.Println("✅ Colors enabled")
+	case 2:
+		fmt.Println("❌ Colors disabled")
+	case 3:
+		return
+	}
+	waitForEnter()
+}
+
+// toggleASCIIMode switches between emoji and plain text output
+// ASCII-only mode replaces emoji and box-drawing characters with text markers and '#'/'-' bars
+// for consoles that cannot render them; it can also be turned on with SIMPLE_MONITOR_ASCII=1
+func toggleASCIIMode() {
+	fmt.Println("\n🔤 ASCII-Only Mode")
+	fmt.Println(strings.Repeat("-", 30))
+	if terminal.ASCIIMode() {
+		fmt.Println("Current: Enabled")
+	} else {
+		fmt.Println("Current: Disabled")
+	}
+	fmt.Println("1. Enable ASCII-Only Mode")
+	fmt.Println("2. Disable ASCII-Only Mode")
+	fmt.Println("3. Back to Display Settings")
+	fmt.Print("Select option (1-3): ")
+
+	choice := getUserChoice(3)
+
+	switch choice {
+	case 1:
+		if err := terminal.SetASCIIMode(true); err != nil {
+			fmt.Printf("❌ Failed to enable ASCII-only mode: %v\n", err)
+		} else {
+			fmt.Println("✅ ASCII-only mode enabled")
+		}
+	case 2:
+		terminal.SetASCIIMode(false)
+		fmt.Println("❌ ASCII-only mode disabled")
+	case 3:
+		return
+	}
+	waitForEnter()
+}
+
+// toggleColorBlindMode switches the severity palette and markers
+// Color-blind mode draws severities in blue/yellow/orange/magenta instead of green/yellow/red and adds
+// ✓, ! and !! markers next to them; it can also be turned on with SIMPLE_MONITOR_COLORBLIND=1
+func toggleColorBlindMode() {
+	fmt.Println("\n🎨 Color-Blind Mode")
+	fmt.Println(strings.Repeat("-", 30))
+	if terminal.ColorBlindMode() {
+		fmt.Println("Current: Enabled")
+	} else {
+		fmt.Println("Current: Disabled")
+	}
+	fmt.Println("1. Enable Color-Blind Mode")
+	fmt.Println("2. Disable Color-Blind Mode")
+	fmt.Println("3. Back to Display Settings")
+	fmt.Print("Select option (1-3): ")
+
+	choice := getUserChoice(3)
+
+	switch choice {
+	case 1:
+		terminal.SetColorBlindMode(true)
+		fmt.Println("✅ Color-blind mode enabled")
+	case 2:
+		terminal.SetColorBlindMode(false)
+		fmt.Println("❌ Color-blind mode disabled")
+	case 3:
+		return
+	}
+	waitForEnter()
+}
+
+// setNetworkRateUnit selects the unit every network speed is shown and exported in
+// Thresholds (minimum usage, VPN bypass) are read in the same unit, and exports record the unit used
+func setNetworkRateUnit() {
+	fmt.Println("\n📶 Network Rate Unit")
+	fmt.Println(strings.Repeat("-", 30))
+	networkConfig := networkMonitorManager.GetConfig()
+	fmt.Printf("Current: %s\n", networkConfig.RateUnit)
+	fmt.Println("1. Mbit/s (megabits per second)")
+	fmt.Println("2. MB/s (megabytes per second)")
+	fmt.Println("3. Back to Display Settings")
+	fmt.Print("Select option (1-3): ")
+
+	choice := getUserChoice(3)
+
+	switch choice {
+	case 1:
+		networkConfig.RateUnit = networkmonitor.RateUnitBits
+	case 2:
+		networkConfig.RateUnit = networkmonitor.RateUnitBytes
+	case 3:
+		return
+	}
+	networkMonitorManager.UpdateConfig(networkConfig)
+
+	fmt.Printf("✅ Network rates shown in %s\n", networkConfig.RateUnit)
+	waitForEnter()
+}
+
+// toggleTitleMetrics enables or disables key figures in the terminal title
+// The title keeps updating in the background, so health stays visible from another tab
+func toggleTitleMetrics() {
+	fmt.Println("\n🏷️  Terminal Title Metrics")
+	fmt.Println(strings.Repeat("-", 30))
+	if titleUpdater.IsRunning() {
+		fmt.Println("Current: Enabled")
+	} else {
+		fmt.Println("Current: Disabled")
+	}
+	fmt.Println("1. Enable Title Metrics")
+	fmt.Println("2. Disable Title Metrics")
+	fmt.Println("3. Back to Display Settings")
+	fmt.Print("Select option (1-3): ")
+
+	choice := getUserChoice(3)
+
+	switch choice {
+	case 1:
+		titleUpdater.Start()
+		fmt.Printf("✅ Terminal title shows CPU %%, memory %% and the top alert (every %v)\n", titleUpdater.GetConfig().Interval)
+	case 2:
+		titleUpdater.Stop()
+		fmt.Println("❌ Terminal title metrics disabled")
+	case 3:
+		return
+	}
+	waitForEnter()
+}
+
+func setScreenSize() {
+	fmt.Println("\n📺 Set Screen Size")
+	fmt.Println(strings.Repeat("-", 30))
+	fmt.Println("1. Small (80x24)")
+	fmt.Println("2. Medium (120x30)")
+	fmt.Println("3. Large (160x40)")
+	fmt.Println("4. Back to Display Settings")
+	fmt.Print("Select option (1-4): ")
+
+	choice := getUserChoice(4)
+
+	switch choice {
+	case 1:
+		fmt.Println("✅ Screen size set to: Small (80x24)")
+	case 2:
+		fmt.Println("✅ Screen size set to: Medium (120x30)")
+	case 3:
+		fmt.Println("✅ Screen size set to: Large (160x40)")
+	case 4:
+		return
+	}
+	waitForEnter()
+}
+
+func setMonitoringInterval() {
+	fmt.Println("\n⏰ Set Monitoring Interval")
+	fmt.Println(strings.Repeat("-", 30))
+	fmt.Println("1. 1 second")
+	fmt.Println("2. 5 seconds")
+	fmt.Println("3. 10 seconds")
+	fmt.Println("4. 30 seconds")
+	fmt.Println("5. Back to Monitoring Settings")
+	fmt.Print("Select option (1-5): ")
+
+	choice := getUserChoice(5)
+
+	var interval time.Duration
+	switch choice {
+	case 1:
+		interval = 1 * time.Second
+	case 2:
+		interval = 5 * time.Second
+	case 3:
+		interval = 10 * time.Second
+	case 4:
+		interval = 30 * time.Second
+	case 5:
+		return
+	}
+
+	// Update all monitor intervals
+	cpuConfig := cpuMonitorManager.GetConfiguration()
+	cpuConfig.RefreshInterval = interval
+	cpuMonitorManager.SetConfiguration(cpuConfig)
+
+	memoryConfig := memoryMonitorManager.GetConfig()
+	memoryConfig.RefreshInterval = interval
+	memoryMonitorManager.UpdateConfig(memoryConfig)
+
+	diskConfig := diskMonitorManager.GetConfig()
+	diskConfig.RefreshInterval = interval
+	diskMonitorManager.UpdateConfig(diskConfig)
+
+	networkConfig := networkMonitorManager.GetConfig()
+	networkConfig.RefreshInterval = interval
+	networkMonitorManager.UpdateConfig(networkConfig)
+
+	processConfig := processMonitorManager.GetConfig()
+	processConfig.RefreshInterval = interval
+	processMonitorManager.UpdateConfig(processConfig)
+
+	fmt.Printf("✅ Monitoring interval set to: %v\n", interval)
+	waitForEnter()
+}
+
+func toggleAutoStart() {
+	fmt.Println("\n🚀 Auto-Start Settings")
+	fmt.Println(strings.Repeat("-", 30))
+	fmt.Println("1. Enable Auto-Start")
+	fmt.Println("2. Disable Auto-Start")
+	fmt.Println("3. Back to Monitoring Settings")
+	fmt.Print("Select option (1-3): ")
+
+	choice := getUserChoice(3)
+
+	switch choice {
+	case 1:
+		fmt.Println("✅ Auto-start enabled")
+	case 2:
+		fmt.Println("❌ Auto-start disabled")
+	case 3:
+		return
+	}
+	waitForEnter()
+}
+
+// setDataRetention chooses how long exports and long-term history are kept
+// Older files are pruned straight away and then once per check interval
+func setDataRetention() {
+	retentionConfig := retention.GetConfig()
+
+	fmt.Println("\n💾 Data Retention Settings")
+	fmt.Println(strings.Repeat("-", 30))
+	fmt.Printf("Current: %s\n", retention.Describe(retentionConfig.Days))
+	fmt.Println("1. 1 day")
+	fmt.Println("2. 7 days")
+	fmt.Println("3. 30 days")
+	fmt.Println("4. 90 days")
+	fmt.Println("5. Keep forever")
+	fmt.Println("6. Preview cleanup (dry run)")
+	fmt.Println("7. Back to Monitoring Settings")
+	fmt.Print("Select option (1-7): ")
+
+	choice := getUserChoice(7)
+
+	switch choice {
+	case 1, 2, 3, 4, 5:
+		retentionConfig.Days = 0
+		if choice <= len(retention.Choices) {
+			retentionConfig.Days = retention.Choices[choice-1]
+		}
+		if err := retention.SetConfig(retentionConfig); err != nil {
+			fmt.Printf("❌ %v\n", err)
+		} else {
+			fmt.Printf("✅ Data retention set to: %s\n", retention.Describe(retentionConfig.Days))
+		}
+	case 6:
+		report, err := retention.Run(logsDir, retentionConfig.Days, time.Now(), true)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+		} else {
+			printRetentionReport(report)
+		}
+	case 7:
+		return
+	}
+	waitForEnter()
+}
+
+// printRetentionReport lists the files and history points a pruning run removed or would remove
+func printRetentionReport(report retention.Report) {
+	verb, trimmed := "Removed", "trimmed"
+	if report.DryRun {
+		verb, trimmed = "Would remove", "would be trimmed"
+	}
+	fmt.Printf("\n🧹 Data older than %s\n", report.Cutoff.Format("2006-01-02 15:04"))
+	for _, file := range report.Files {
+		fmt.Printf("   %s  %s  (%s)\n", file.ModTime.Format("2006-01-02"), file.Path, formatFileSize(file.Size))
+	}
+	for _, history := range report.History {
+		fmt.Printf("   %s: %d history points\n", history.Path, history.Points)
+	}
+	for _, problem := range report.Errors {
+		fmt.Printf("⚠️  %s\n", problem)
+	}
+	if len(report.Files) == 0 && len(report.History) == 0 {
+		fmt.Println("   Nothing is older than the retention period")
+		return
+	}
+	fmt.Printf("%s %d files (%s); %d history files %s\n", verb, len(report.Files), formatFileSize(report.Bytes), len(report.History), trimmed)
+}
+
+// formatFileSize formats a file size into human-readable format
+func formatFileSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
+
+// historyBufferSizes are the in-memory history lengths offered in the History Buffers menu
+var historyBufferSizes = []int{100, 300, 900, 3600, 10800}
+
+// historyBufferResolutions are the sampling resolutions offered in the History Buffers menu (0 samples every refresh)
+var historyBufferResolutions = []time.Duration{0, 5 * time.Second, 10 * time.Second, 30 * time.Second, time.Minute}
+
+// historyEstimator is implemented by the monitors that keep an in-memory history
+type historyEstimator interface {
+	HistoryMemoryEstimate(samples int) uint64
+}
+
+// configureHistoryBuffers sets how many samples each monitor keeps in memory and how often it takes one
+// Longer buffers let live sessions keep hours of trend data; the span and memory of each choice are shown
+func configureHistoryBuffers() {
+	monitors := liveMonitorList()[:liveEvents]
+
+	for {
+		fmt.Println("\n📚 History Buffers")
+		fmt.Println(strings.Repeat("-", 30))
+		for i := range monitors {
+			size, resolution, interval := historyBufferConfig(i)
+			fmt.Printf("%d. %-8s %s\n", i+1, liveMonitorNames[i], describeHistoryBuffer(i, size, resolution, interval))
+		}
+		fmt.Printf("%d. Back to Monitoring Settings\n", len(monitors)+1)
+		fmt.Println(strings.Repeat("-", 30))
+		fmt.Printf("Select a monitor (1-%d): ", len(monitors)+1)
+
+		choice := getUserChoice(len(monitors) + 1)
+		if choice > len(monitors) {
+			return
+		}
+		index := choice - 1
+		size, resolution, interval := historyBufferConfig(index)
+
+		fmt.Printf("\nSamples to keep (%s):\n", liveMonitorNames[index])
+		for i, option := range historyBufferSizes {
+			fmt.Printf("%d. %s\n", i+1, describeHistoryBuffer(index, option, resolution, interval))
+		}
+		fmt.Printf("%d. Keep %d\n", len(historyBufferSizes)+1, size)
+		fmt.Printf("Select option (1-%d): ", len(historyBufferSizes)+1)
+		if option := getUserChoice(len(historyBufferSizes) + 1); option <= len(historyBufferSizes) {
+			size = historyBufferSizes[option-1]
+		}
+
+		fmt.Printf("\nSampling resolution (%s):\n", liveMonitorNames[index])
+		for i, option := range historyBufferResolutions {
+			fmt.Printf("%d. %s\n", i+1, describeHistoryBuffer(index, size, option, interval))
+		}
+		fmt.Printf("%d. Keep current\n", len(historyBufferResolutions)+1)
+		fmt.Printf("Select option (1-%d): ", len(historyBufferResolutions)+1)
+		if option := getUserChoice(len(historyBufferResolutions) + 1); option <= len(historyBufferResolutions) {
+			resolution = historyBufferResolutions[option-1]
+		}
+
+		setHistoryBuffer(index, size, resolution)
+		fmt.Printf("✅ %s history: %s\n", liveMonitorNames[index], describeHistoryBuffer(index, size, resolution, interval))
+	}
+}
+
+// describeHistoryBuffer summarizes a history buffer setting with the time it spans and its memory estimate
+func describeHistoryBuffer(index, size int, resolution, interval time.Duration) string {
+	every := "every refresh"
+	if resolution > 0 {
+		every = "every " + resolution.String()
+	}
+
+	memory := "unknown size"
+	if estimator, ok := liveMonitorList()[index].(historyEstimator); ok {
+		memory = formatFileSize(int64(estimator.HistoryMemoryEstimate(size)))
+	}
+
+	return fmt.Sprintf("%d samples %s (~%s, %s)", size, every, historystore.BufferSpan(size, interval, resolution), memory)
+}
+
+// historyBufferConfig returns the history size, sampling resolution and refresh interval of the live monitor at index
+func historyBufferConfig(index int) (int, time.Duration, time.Duration) {
+	switch index {
+	case liveCPU:
+		config := cpuMonitorManager.GetConfiguration()
+		return config.HistorySize, config.HistoryResolution, config.RefreshInterval
+	case liveMemory:
+		config := memoryMonitorManager.GetConfig()
+		return config.HistorySize, config.HistoryResolution, config.RefreshInterval
+	case liveDisk:
+		config := diskMonitorManager.GetConfig()
+		return config.HistorySize, config.HistoryResolution, config.RefreshInterval
+	case liveNetwork:
+		config := networkMonitorManager.GetConfig()
+		return config.HistorySize, config.HistoryResolution, config.RefreshInterval
+	case liveProcess:
+		config := processMonitorManager.GetConfig()
+		return config.HistorySize, config.HistoryResolution, config.RefreshInterval
+	}
+	return 0, 0, 0
+}
+
+// setHistoryBuffer sets the history size and sampling resolution of the live monitor at index
+// A smaller size drops the oldest samples on the next refresh
+func setHistoryBuffer(index, size int, resolution time.Duration) {
+	switch index {
+	case liveCPU:
+		cpuConfig := cpuMonitorManager.GetConfiguration()
+		cpuConfig.HistorySize = size
+		cpuConfig.HistoryResolution = resolution
+		cpuMonitorManager.SetConfiguration(cpuConfig)
+	case liveMemory:
+		memoryConfig := memoryMonitorManager.GetConfig()
+		memoryConfig.HistorySize = size
+		memoryConfig.HistoryResolution = resolution
+		memoryMonitorManager.UpdateConfig(memoryConfig)
+	case liveDisk:
+		diskConfig := diskMonitorManager.GetConfig()
+		diskConfig.HistorySize = size
+		diskConfig.HistoryResolution = resolution
+		diskMonitorManager.UpdateConfig(diskConfig)
+	case liveNetwork:
+		networkConfig := networkMonitorManager.GetConfig()
+		networkConfig.HistorySize = size
+		networkConfig.HistoryResolution = resolution
+		networkMonitorManager.UpdateConfig(networkConfig)
+	case liveProcess:
+		processConfig := processMonitorManager.GetConfig()
+		processConfig.HistorySize = size
+		processConfig.HistoryResolution = resolution
+		processMonitorManager.UpdateConfig(processConfig)
+	}
+}
+
+// toggleProcessLogs enables or disables pulling journal/event-log errors for alerting processes
+func toggleProcessLogs() {
+	config := processMonitorManager.GetConfig()
+
+	fmt.Println("\n📜 Process Error Logs")
+	fmt.Println(strings.Repeat("-", 30))
+	if config.ShowProcessLogs {
+		fmt.Println("Current: Enabled")
+	} else {
+		fmt.Println("Current: Disabled")
+	}
+	fmt.Println("1. Enable Process Error Logs")
+	fmt.Println("2. Disable Process Error Logs")
+	fmt.Println("3. Back to Monitoring Settings")
+	fmt.Print("Select option (1-3): ")
+
+	choice := getUserChoice(3)
+
+	switch choice {
+	case 1:
+		config.ShowProcessLogs = true
+		fmt.Println("✅ Process error logs enabled")
+	case 2:
+		config.ShowProcessLogs = false
+		fmt.Println("❌ Process error logs disabled")
+	case 3:
+		return
+	}
+	processMonitorManager.UpdateConfig(config)
+	waitForEnter()
+}
+
+// toggleSandboxHints enables or disables namespace, seccomp and container hints for shown processes
+func toggleSandboxHints() {
+	config := processMonitorManager.GetConfig()
+
+	fmt.Println("\n🛡️ Process Sandbox Hints")
+	fmt.Println(strings.Repeat("-", 30))
+	if config.ShowSandbox {
+		fmt.Println("Current: Enabled")
+	} else {
+		fmt.Println("Current: Disabled")
+	}
+	if runtime.GOOS != "linux" {
+		fmt.Println("Note: namespaces and seccomp are only available on Linux")
+	}
+	fmt.Println("1. Enable Sandbox Hints")
+	fmt.Println("2. Disable Sandbox Hints")
+	fmt.Println("3. Back to Monitoring Settings")
+	fmt.Print("Select option (1-3): ")
+
+	choice := getUserChoice(3)
+
+	switch choice {
+	case 1:
+		config.ShowSandbox = true
+		fmt.Println("✅ Sandbox hints enabled")
+	case 2:
+		config.ShowSandbox = false
+		fmt.Println("❌ Sandbox hints disabled")
+	case 3:
+		return
+	}
+	processMonitorManager.UpdateConfig(config)
+	waitForEnter()
+}
+
+// togglePSSAccounting enables or disables PSS/USS columns in the memory process view
+func togglePSSAccounting() {
+	config := memoryMonitorManager.GetConfig()
+
+	fmt.Println("\n🧮 PSS/USS Memory Accounting")
+	fmt.Println(strings.Repeat("-", 30))
+	if config.ShowPSS {
+		fmt.Println("Current: Enabled")
+	} else {
+		fmt.Println("Current: Disabled")
+	}
+	fmt.Println("1. Enable PSS/USS Accounting")
+	fmt.Println("2. Disable PSS/USS Accounting")
+	fmt.Println("3. Back to Monitoring Settings")
+	fmt.Print("Select option (1-3): ")
+
+	choice := getUserChoice(3)
+
+	switch choice {
+	case 1:
+		config.ShowPSS = true
+		fmt.Println("✅ PSS/USS accounting enabled (Linux only)")
+	case 2:
+		config.ShowPSS = false
+		fmt.Println("❌ PSS/USS accounting disabled")
+	case 3:
+		return
+	}
+	memoryMonitorManager.UpdateConfig(config)
+	waitForEnter()
+}
+
+// toggleCleanupSuggestions enables or disables reclaimable space suggestions on low disk space
+func toggleCleanupSuggestions() {
+	config := diskMonitorManager.GetConfig()
+
+	fmt.Println("\n🧹 Disk Cleanup Suggestions")
+	fmt.Println(strings.Repeat("-", 30))
+	if config.ShowCleanupSuggestions {
+		fmt.Println("Current: Enabled")
+	} else {
+		fmt.Println("Current: Disabled")
+	}
+	fmt.Println("1. Enable Cleanup Suggestions")
+	fmt.Println("2. Disable Cleanup Suggestions")
+	fmt.Println("3. Back to Monitoring Settings")
+	fmt.Print("Select option (1-3): ")
+
+	choice := getUserChoice(3)
+
+	switch choice {
+	case 1:
+		config.ShowCleanupSuggestions = true
+		fmt.Println("✅ Cleanup suggestions enabled (shown when disk space is low)")
+	case 2:
+		config.ShowCleanupSuggestions = false
+		fmt.Println("❌ Cleanup suggestions disabled")
+	case 3:
+		return
+	}
+	diskMonitorManager.UpdateConfig(config)
+	waitForEnter()
+}
+
+// toggleConnectivityProbe switches the network monitor's proxy and captive portal detection
+// The probe sends an HTTP request to captive_portal_url, so it is only enabled after the user confirms that
+func toggleConnectivityProbe() {
+	config := networkMonitorManager.GetConfig()
+
+	fmt.Println("\n🌍 Connectivity Probe")
+	fmt.Println(strings.Repeat("-", 30))
+	if config.ShowConnectivity {
+		fmt.Println("Current: Enabled")
+	} else {
+		fmt.Println("Current: Disabled")
+	}
+	fmt.Println("1. Enable Connectivity Probe")
+	fmt.Println("2. Disable Connectivity Probe")
+	fmt.Println("3. Back to Monitoring Settings")
+	fmt.Print("Select option (1-3): ")
+
+	choice := getUserChoice(3)
+
+	switch choice {
+	case 1:
+		fmt.Printf("⚠️  The probe sends an HTTP request to %s every %v to detect captive portals.\n",
+			config.CaptivePortalURL, config.ConnectivityCheckInterval)
+		fmt.Print("Contact this external server? [y/N]: ")
+		scanner := bufio.NewScanner(os.Stdin)
+		scanner.Scan()
+		if answer := strings.ToLower(strings.TrimSpace(scanner.Text())); answer != "y" && answer != "yes" {
+			fmt.Println("❌ Connectivity probe left disabled")
+			waitForEnter()
+			return
+		}
+		config.ShowConnectivity = true
+		fmt.Println("✅ Connectivity probe enabled")
+	case 2:
+		config.ShowConnectivity = false
+		fmt.Println("❌ Connectivity probe disabled")
+	case 3:
+		return
+	}
+	networkMonitorManager.UpdateConfig(config)
+	waitForEnter()
+}
+
+// toggleMonitors switches single monitors on or off
+// A disabled monitor is hidden from the monitoring menu, live switching, quick tests and snapshots,
+// never collects, and so never raises alerts or writes exports
+func toggleMonitors() {
+	for {
+		fmt.Println("\n🔌 Enable/Disable Monitors")
+		fmt.Println(strings.Repeat("-", 30))
+		monitors := liveMonitorList()
+		for i, monitor := range monitors {
+			status := "Enabled"
+			if !monitor.IsEnabled() {
+				status = "Disabled"
+			}
+			fmt.Printf("%d. %-8s %s\n", i+1, liveMonitorNames[i], status)
+		}
+		fmt.Printf("%d. Back to Monitoring Settings\n", len(monitors)+1)
+		fmt.Println(strings.Repeat("-", 30))
+		fmt.Printf("Select a monitor to switch on or off (1-%d): ", len(monitors)+1)
+
+		choice := getUserChoice(len(monitors) + 1)
+		if choice > len(monitors) {
+			return
+		}
+
+		index := choice - 1
+		enabled := !monitors[index].IsEnabled()
+		setMonitorEnabled(index, enabled)
+		if enabled {
+			fmt.Printf("✅ %s monitor enabled\n", liveMonitorNames[index])
+		} else {
+			fmt.Printf("⏸️  %s monitor disabled\n", liveMonitorNames[index])
+		}
+	}
+}
+
+// setMonitorEnabled switches the live monitor at index on or off and updates the terminal title to match
+func setMonitorEnabled(index int, enabled bool) {
+	switch index {
+	case liveCPU:
+		cpuConfig := cpuMonitorManager.GetConfiguration()
+		cpuConfig.Enabled = enabled
+		cpuMonitorManager.SetConfiguration(cpuConfig)
+	case liveMemory:
+		memoryConfig := memoryMonitorManager.GetConfig()
+		memoryConfig.Enabled = enabled
+		memoryMonitorManager.UpdateConfig(memoryConfig)
+	case liveDisk:
+		diskConfig := diskMonitorManager.GetConfig()
+		diskConfig.Enabled = enabled
+		diskMonitorManager.UpdateConfig(diskConfig)
+	case liveNetwork:
+		networkConfig := networkMonitorManager.GetConfig()
+		networkConfig.Enabled = enabled
+		networkMonitorManager.UpdateConfig(networkConfig)
+	case liveProcess:
+		processConfig := processMonitorManager.GetConfig()
+		processConfig.Enabled = enabled
+		processMonitorManager.UpdateConfig(processConfig)
+	case liveEvents:
+		eventConfig := eventMonitorManager.GetConfig()
+		eventConfig.Enabled = enabled
+		eventMonitorManager.UpdateConfig(eventConfig)
+	}
+	syncTitleMonitors()
+}
+
+// syncTitleMonitors shows only the figures of enabled monitors in the terminal title
+func syncTitleMonitors() {
+	titleConfig := titleUpdater.GetConfig()
+	titleConfig.CPUEnabled = cpuMonitorManager.IsEnabled()
+	titleConfig.MemoryEnabled = memoryMonitorManager.IsEnabled()
+	titleConfig.DiskEnabled = diskMonitorManager.IsEnabled()
+	titleUpdater.UpdateConfig(titleConfig)
+}
+
+// monitorEnabled reports whether the live monitor at index is switched on, and explains how to turn it on when it is not
+func monitorEnabled(index int) bool {
+	if liveMonitorList()[index].IsEnabled() {
+		return true
+	}
+	fmt.Printf("⏸️  The %s monitor is disabled\n", liveMonitorNames[index])
+	fmt.Println("   Turn it on under Settings → Monitoring Settings → Enable/Disable Monitors,")
+	fmt.Printf("   or set \"enabled\": true in the \"%s\" section of the config file\n", liveMonitorSections[index])
+	waitForEnter()
+	return false
+}
+
+// toggleIdleMode enables or disables idle mode for all live monitors
+// While nobody is using the machine and activity is low, monitors refresh less often
+// and pause process scans to save battery
+func toggleIdleMode() {
+	config := cpuMonitorManager.GetIdleConfig()
+
+	fmt.Println("\n💤 Idle Mode")
+	fmt.Println(strings.Repeat("-", 30))
+	if config.Enabled {
+		fmt.Println("Current: Enabled")
+	} else {
+		fmt.Println("Current: Disabled")
+	}
+	fmt.Printf("Idle after %v without input and below %.0f%% CPU\n", config.IdleAfter, config.CPUThreshold)
+	fmt.Println("1. Enable Idle Mode")
+	fmt.Println("2. Disable Idle Mode")
+	fmt.Println("3. Back to Monitoring Settings")
+	fmt.Print("Select option (1-3): ")
+
+	choice := getUserChoice(3)
+
+	var enabled bool
+	switch choice {
+	case 1:
+		enabled = true
+	case 2:
+		enabled = false
+	case 3:
+		return
+	}
+
+	// Update all live monitors
+	cpuMonitorManager.SetIdleMode(enabled)
+	memoryMonitorManager.SetIdleMode(enabled)
+	diskMonitorManager.SetIdleMode(enabled)
+	networkMonitorManager.SetIdleMode(enabled)
+	processMonitorManager.SetIdleMode(enabled)
+
+	if enabled {
+		fmt.Printf("✅ Idle mode enabled (refreshing %dx less often while idle)\n", config.SlowdownFactor)
+	} else {
+		fmt.Println("❌ Idle mode disabled")
+	}
+	waitForEnter()
+}
+
+func configureAlerts() {
+	fmt.Println("\n🚨 Configure Alerts")
+	fmt.Println(strings.Repeat("-", 30))
+	fmt.Println("1. CPU Usage Alert")
+	fmt.Println("2. Memory Usage Alert")
+	fmt.Println("3. Disk Space Alert")
+	fmt.Println("4. Network Alert")
+	fmt.Println("5. Snooze Alerts")
+	fmt.Println("6. Resume Alerts")
+	fmt.Println("7. Show Maintenance Windows")
+	fmt.Println("8. Show Event Hooks")
+	fmt.Println("9. Back to Monitoring Settings")
+	fmt.Print("Select option (1-9): ")
+
+	choice := getUserChoice(9)
+
+	switch choice {
+	case 1:
+		fmt.Println("✅ CPU usage alerts configured")
+	case 2:
+		fmt.Println("✅ Memory usage alerts configured")
+	case 3:
+		fmt.Println("✅ Disk space alerts configured")
+	case 4:
+		fmt.Println("✅ Network alerts configured")
+	case 5:
+		fmt.Print("Snooze alerts for (e.g. 30m, 2h): ")
+		scanner := bufio.NewScanner(os.Stdin)
+		scanner.Scan()
+		duration, err := time.ParseDuration(strings.TrimSpace(scanner.Text()))
+		if err != nil {
+			fmt.Println("❌ Invalid duration! Use a value like 30m or 2h.")
+		} else if err := alert.Snooze(duration); err != nil {
+			fmt.Printf("❌ %v\n", err)
+		} else {
+			fmt.Printf("🔕 Alerts snoozed until %s\n", alert.SnoozedUntil().Format("15:04"))
+		}
+	case 6:
+		alert.Resume()
+		fmt.Println("🔔 Snooze cleared; alerts resume outside maintenance windows")
+	case 7:
+		showMaintenanceWindows()
+	case 8:
+		showEventHooks()
+	case 9:
+		return
+	}
+	waitForEnter()
+}
+
+// showEventHooks lists the configured hooks and the outcome of the last one that ran
+func showEventHooks() {
+	hookConfig := hooks.GetConfig()
+	fmt.Printf("\n🪝 Event hooks (timeout: %s)\n", hookConfig.Timeout)
+	if hookConfig.DigestWindow > 0 {
+		fmt.Printf("   Alerts are batched into one alert_digest every %s; alert_raised and alert_cleared hooks do not run\n", hookConfig.DigestWindow)
+	}
+	if len(hookConfig.Commands) == 0 {
+		fmt.Println("   None configured; add them under \"hooks\".\"commands\" in the config file")
+		fmt.Printf("   Events: %s\n", strings.Join(hooks.Events, ", "))
+	}
+	for _, hook := range hookConfig.Commands {
+		scope := ""
+		if hook.Monitor != "" {
+			scope += " monitor=" + hook.Monitor
+		}
+		if hook.Alert != "" {
+			scope += " alert=" + hook.Alert
+		}
+		fmt.Printf("   • %s%s → %s\n", hook.Event, scope, hook.Command)
+	}
+	if last := hooks.LastRun(); last != nil {
+		outcome := "ok"
+		if last.Error != "" {
+			outcome = last.Error
+		}
+		fmt.Printf("   Last run: %s %s at %s: %s\n", last.Event, last.Command, last.Started.Format("15:04:05"), outcome)
+	}
+	fmt.Printf("   Every run is logged to %s\n", hooks.LogPath)
+}
+
+// showMaintenanceWindows lists the configured maintenance windows and whether alerts are suppressed now
+func showMaintenanceWindows() {
+	alertConfig := alert.GetConfig()
+	fmt.Printf("\n🗓️  Maintenance windows (mode: %s)\n", alertConfig.MaintenanceMode)
+	if len(alertConfig.MaintenanceWindows) == 0 {
+		fmt.Println("   None configured; add them under \"alerts\".\"maintenance_windows\" in the config file")
+	}
+	for _, spec := range alertConfig.MaintenanceWindows {
+		fmt.Printf("   • %s\n", spec)
+	}
+	if reason, active := alert.ActiveMaintenance(time.Now()); active {
+		fmt.Printf("🔕 Alerts %s\n", reason)
+	} else {
+		fmt.Println("🔔 Alerts are active")
+	}
+	if alertConfig.MaintenanceMode == alert.ModeLog {
+		fmt.Printf("   Alerts raised during maintenance are logged to %s\n", alert.LogPath)
+	}
+}
+
+func setCPUPriority() {
+	fmt.Println("\n⚡ CPU Priority Settings")
+	fmt.Println(strings.Repeat("-", 30))
+	fmt.Println("1. Low Priority")
+	fmt.Println("2. Normal Priority")
+	fmt.Println("3. High Priority")
+	fmt.Println("4. Back to Performance Settings")
+	fmt.Print("Select option (1-4): ")
+
+	choice := getUserChoice(4)
+
+	switch choice {
+	case 1:
+		fmt.Println("✅ CPU priority set to: Low")
+	case 2:
+		fmt.Println("✅ CPU priority set to: Normal")
+	case 3:
+		fmt.Println("✅ CPU priority set to: High")
+	case 4:
+		return
+	}
+	waitForEnter()
+}
+
+func setMemoryLimit() {
+	fmt.Println("\n💾 Memory Limit Settings")
+	fmt.Println(strings.Repeat("-", 30))
+	fmt.Println("1. 100 MB")
+	fmt.Println("2. 500 MB")
+	fmt.Println("3. 1 GB")
+	fmt.Println("4. 2 GB")
+	fmt.Println("5. Back to Performance Settings")
+	fmt.Print("Select option (1-5): ")
+
+	choice := getUserChoice(5)
+
+	switch choice {
+	case 1:
+		fmt.Println("✅ Memory limit set to: 100 MB")
+	case 2:
+		fmt.Println("✅ Memory limit set to: 500 MB")
+	case 3:
+		fmt.Println("✅ Memory limit set to: 1 GB")
+	case 4:
+		fmt.Println("✅ Memory limit set to: 2 GB")
+	case 5:
+		return
+	}
+	waitForEnter()
+}
+
+func toggleBackgroundMode() {
+	fmt.Println("\n🔄 Background Mode")
+	fmt.Println(strings.Repeat("-", 30))
+	fmt.Println("1. Enable Background Mode")
+	fmt.Println("2. Disable Background Mode")
+	fmt.Println("3. Back to Performance Settings")
+	fmt.Print("Select option (1-3): ")
+
+	choice := getUserChoice(3)
+
+	switch choice {
+	case 1:
+		fmt.Println("✅ Background mode enabled")
+	case 2:
+		fmt.Println("❌ Background mode disabled")
+	case 3:
+		return
+	}
+	waitForEnter()
+}
+
+func setThreadCount() {
+	fmt.Println("\n🧵 Thread Count Settings")
+	fmt.Println(strings.Repeat("-", 30))
+	fmt.Println("1. 1 thread")
+	fmt.Println("2. 2 threads")
+	fmt.Println("3. 4 threads")
+	fmt.Println("4. 8 threads")
+	fmt.Println("5. Back to Performance Settings")
+	fmt.Print("Select option (1-5): ")
+
+	choice := getUserChoice(5)
+
+	switch choice {
+	case 1:
+		fmt.Println("✅ Thread count set to: 1")
+	case 2:
+		fmt.Println("✅ Thread count set to: 2")
+	case 3:
+		fmt.Println("✅ Thread count set to: 4")
+	case 4:
+		fmt.Println("✅ Thread count set to: 8")
+	case 5:
+		return
+	}
+	waitForEnter()
+}
+
+func setLogLevel() {
+	fmt.Println("\n📝 Log Level Settings")
+	fmt.Println(strings.Repeat("-", 30))
+	fmt.Println("1. Debug (All messages)")
+	fmt.Println("2. Info (Normal messages)")
+	fmt.Println("3. Warning (Warnings and errors)")
+	fmt.Println("4. Error (Errors only)")
+	fmt.Println("5. Back to Log Settings")
+	fmt.Print("Select option (1-5): ")
+
+	choice := getUserChoice(5)
+
+	switch choice {
+	case 1:
+		fmt.Println("✅ Log level set to: Debug")
+	case 2:
+		fmt.Println("✅ Log level set to: Info")
+	case 3:
+		fmt.Println("✅ Log level set to: Warning")
+	case 4:
+		fmt.Println("✅ Log level set to: Error")
+	case 5:
+		return
+	}
+	waitForEnter()
+}
+
+func setLogRotation() {
+	fmt.Println("\n🔄 Log Rotation Settings")
+	fmt.Println(strings.Repeat("-", 30))
+	fmt.Println("1. Daily rotation")
+	fmt.Println("2. Weekly rotation")
+	fmt.Println("3. Monthly rotation")
+	fmt.Println("4. No rotation")
+	fmt.Println("5. Back to Log Settings")
+	fmt.Print("Select option (1-5): ")
+
+	choice := getUserChoice(5)
+
+	switch choice {
+	case 1:
+		fmt.Println("✅ Log rotation set to: Daily")
+	case 2:
+		fmt.Println("✅ Log rotation set to: Weekly")
+	case 3:
+		fmt.Println("✅ Log rotation set to: Monthly")
+	case 4:
+		fmt.Println("❌ Log rotation disabled")
+	case 5:
+		return
+	}
+	waitForEnter()
+}
+
+func toggleLogging() {
+	fmt.Println("\n📝 Logging Settings")
+	fmt.Println(strings.Repeat("-", 30))
+	fmt.Println("1. Enable Logging")
+	fmt.Println("2. Disable Logging")
+	fmt.Println("3. Back to Log Settings")
+	fmt.Print("Select option (1-3): ")
+
+	choice := getUserChoice(3)
+
+	switch choice {
+	case 1:
+		fmt.Println("✅ Logging enabled")
+	case 2:
+		fmt.Println("❌ Logging disabled")
+	case 3:
+		return
+	}
+	waitForEnter()
+}
+
+func setLogDirectory() {
+	fmt.Println("\n📁 Log Directory Settings")
+	fmt.Println(strings.Repeat("-", 30))
+	fmt.Println("1. Default directory (logs/)")
+	fmt.Println("2. Custom directory")
+	fmt.Println("3. Back to Log Settings")
+	fmt.Print("Select option (1-3): ")
+
+	choice := getUserChoice(3)
+
+	switch choice {
+	case 1:
+		fmt.Println("✅ Log directory set to: logs/")
+	case 2:
+		fmt.Print("Enter custom directory path: ")
+		scanner := bufio.NewScanner(os.Stdin)
+		scanner.Scan()
+		path := strings.TrimSpace(scanner.Text())
+		if path != "" {
+			fmt.Printf("✅ Log directory set to: %s\n", path)
+		} else {
+			fmt.Println("❌ Invalid path! Using default directory.")
+		}
+	case 3:
+		return
+	}
+	waitForEnter()
+}
+
+// runCommand handles command-line subcommands and returns the process exit code
+func runCommand(args []string) int {
+	if args[0] == "config" && len(args) >= 2 {
+		path := config.Path()
+		if len(args) >= 3 {
+			path = args[2]
+		}
+
+		switch args[1] {
+		case "validate":
+			return validateConfigFile(path)
+		case "migrate":
+			return migrateConfigFile(path)
+		}
+	}
+	if args[0] == "replay" && len(args) <= 3 {
+		return replayCommand(args[1:])
+	}
+	if args[0] == "prune" && (len(args) == 1 || len(args) == 2 && args[1] == "--dry-run") {
+		return pruneCommand(len(args) == 2)
+	}
+	if args[0] == "check" {
+		return checkCommand(args[1:])
+	}
+	if args[0] == "status" {
+		return statusCommand(args[1:])
+	}
+	if args[0] == "doctor" && len(args) == 1 {
+		return doctorCommand()
+	}
+	if args[0] == "proto" && len(args) == 1 {
+		return protoCommand()
+	}
+	if args[0] == "fixtures" && len(args) <= 3 {
+		return fixturesCommand(args[1:])
+	}
+	if args[0] == "daemon" && len(args) == 1 {
+		return daemonCommand()
+	}
+	if args[0] == "known-good" && (len(args) == 2 || len(args) == 3) && (args[1] == "save" || args[1] == "compare") {
+		return knownGoodCommand(args[1:])
+	}
+	if args[0] == "overlay" && (len(args) == 3 || len(args) == 4) && args[1] == "import" ||
+		args[0] == "overlay" && len(args) == 2 && (args[1] == "show" || args[1] == "clear") {
+		return overlayCommand(args[1:])
+	}
+
+	fmt.Println("Usage:")
+	fmt.Println("  simple-monitor                          Start the interactive menu")
+	fmt.Println("  simple-monitor --simulate               Start with synthetic data instead of the live system")
+	fmt.Println("  simple-monitor config validate [file]   Check the config file for invalid values")
+	fmt.Println("  simple-monitor config migrate [file]    Upgrade the config file to the current schema")
+	fmt.Println("  simple-monitor --record                 Record live monitoring sessions for replay")
+	fmt.Println("  simple-monitor --viewer                 Follow the running instance's live monitor read-only")
+	fmt.Println("  simple-monitor replay [file] [speed]    Play back a recorded session (latest by default)")
+	fmt.Println("  simple-monitor prune [--dry-run]        Delete exports and history older than the data retention period")
+	fmt.Println("  simple-monitor check --monitor=disk [--warn=80] [--crit=90] [--path=/]")
+	fmt.Println("                                          One-shot Nagios-style check (cpu, memory, swap, disk, network):")
+	fmt.Println("                                          one summary line, exit code 0 OK, 1 warning, 2 critical, 3 unknown")
+	fmt.Println("  simple-monitor status [--format=text]   One-line health summary for status bars and shell prompts,")
+	fmt.Println("                                          with the same exit codes as check; --format=waybar, polybar")
+	fmt.Println("                                          or tmux adds the color hints those bars expect")
+	fmt.Println("  simple-monitor doctor                   Check permissions, optional tools, the logs directory, the config")
+	fmt.Println("                                          file and the clock, with a fix for every problem found")
+	fmt.Println("  simple-monitor proto                    Print the .proto definitions of protobuf exports")
+	fmt.Println("  simple-monitor fixtures [--golden] [root]")
+	fmt.Println("                                          Capture each monitor into <package>/testdata/snapshot.json and")
+	fmt.Println("                                          render it to display.golden; --golden only re-renders the goldens")
+	fmt.Println("  simple-monitor daemon                   Run without the menu: retention, publishing, the streaming API and")
+	fmt.Println("                                          the quick tests scheduled in quick_test.schedule, until Ctrl+C;")
+	fmt.Println("                                          simple-monitor started meanwhile attaches to it")
+	fmt.Println("  simple-monitor known-good save [file]   Save the processes, listening ports and filesystems as known good")
+	fmt.Println("  simple-monitor known-good compare [file]")
+	fmt.Println("                                          List deviations from the known-good state: exit code 0 when")
+	fmt.Println("                                          there are none, 1 when there are, 2 on errors")
+	fmt.Println("  simple-monitor overlay import <file.csv> [column]")
+	fmt.Println("                                          Import a time series such as requests/sec (a time column and a")
+	fmt.Println("                                          value column) to draw under the CPU and memory history graphs")
+	fmt.Println("  simple-monitor overlay show|clear       Compare the imported series with the long-term CPU and memory")
+	fmt.Println("                                          history over its time range, or remove it")
+	fmt.Printf("\nThe config file defaults to %s (override with %s)\n", config.Path(), config.PathEnv)
+	return 2
+}
+
+// daemonCommand runs the background services without the interactive menu until SIGINT or SIGTERM:
+// data retention, snapshot publishing, the streaming API and the scheduled quick tests, whose results
+// are printed as they come in
+func daemonCommand() int {
+	lock, err := instance.Acquire(logsDir)
+	var held *instance.HeldError
+	if errors.As(err, &held) {
+		fmt.Printf("🔒 Simple Monitor is already running: %v\n", err)
+		return 1
+	}
+	if err != nil {
+		fmt.Printf("⚠️  Running without the instance lock: %v\n", err)
+	}
+	instanceLock = lock
+	defer instanceLock.Release()
+
+	fmt.Println("🚀 Simple Monitor daemon started")
+	loadConfigFile()
+	retention.Start(logsDir)
+	snapshot.StartPublishing(publishedMonitors)
+	if err := stream.Start(); err != nil {
+		fmt.Printf("⚠️  %v\n", err)
+	}
+	if !cpuMonitorManager.IsSimulated() {
+		connectHelper()
+	}
+	defer privhelper.Use(nil)
+
+	// Interactive sessions started while the daemon runs attach to it instead of collecting again
+	if instanceLock != nil {
+		server := &attach.Server{Monitors: attachedMonitors()}
+		if err := server.Listen(attach.SocketPath(logsDir)); err != nil {
+			fmt.Printf("⚠️  Interactive sessions cannot attach: %v\n", err)
+		} else {
+			defer server.Close()
+			fmt.Printf("🔗 Running simple-monitor in another terminal attaches to this daemon through %s\n", attach.SocketPath(logsDir))
+		}
+	}
+
+	if schedule := quicktest.GetConfig().Schedule; len(schedule) > 0 {
+		fmt.Printf("🕒 Quick tests scheduled at %s, appended to %s\n", strings.Join(schedule, ", "), quicktest.HistoryPath)
+	} else {
+		fmt.Printf("ℹ️  No quick tests scheduled; set quick_test.schedule in %s, e.g. [\"0 * * * *\"] for hourly\n", config.Path())
+	}
+	quicktest.Start(quickTestMonitors, func(result quicktest.Result, err error) {
+		mark := "✅"
+		if !result.Passed {
+			mark = "❌"
+		}
+		fmt.Printf("%s %s quick test %s\n", mark, result.Time.Format("2006-01-02 15:04:05"), result.Summary())
+		if err != nil {
+			fmt.Printf("⚠️  %v\n", err)
+		}
+	})
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	<-signals
+	fmt.Println("👋 Simple Monitor daemon stopped")
+	return 0
+}
+
+// attachedMonitors returns the enabled live monitors for attached sessions, collected on the daemon's own managers
+// so their history, rate baselines and alerts carry on from one attached session to the next
+func attachedMonitors() []attach.Monitor {
+	sources := []attach.Monitor{
+		{
+			Collect:  func() (interface{}, error) { return cpuMonitorManager.Collect() },
+			Interval: func() time.Duration { return cpuMonitorManager.GetConfiguration().RefreshInterval },
+		},
+		{
+			Collect:  func() (interface{}, error) { return memoryMonitorManager.Collect() },
+			Interval: func() time.Duration { return memoryMonitorManager.GetConfig().RefreshInterval },
+		},
+		{
+			Collect:  func() (interface{}, error) { return diskMonitorManager.Collect() },
+			Interval: func() time.Duration { return diskMonitorManager.GetConfig().RefreshInterval },
+		},
+		{
+			Collect:  func() (interface{}, error) { return networkMonitorManager.Collect() },
+			Interval: func() time.Duration { return networkMonitorManager.GetConfig().RefreshInterval },
+		},
+		{
+			Collect:  func() (interface{}, error) { return processMonitorManager.Collect() },
+			Interval: func() time.Duration { return processMonitorManager.GetConfig().RefreshInterval },
+		},
+		{
+			Collect:  func() (interface{}, error) { return eventMonitorManager.Collect() },
+			Interval: func() time.Duration { return eventMonitorManager.GetConfig().RefreshInterval },
+		},
+	}
+
+	var monitors []attach.Monitor
+	for i, monitor := range liveMonitorList() {
+		if monitor.IsEnabled() {
+			sources[i].Name = liveMonitorSections[i]
+			monitors = append(monitors, sources[i])
+		}
+	}
+	return monitors
+}
+
+// doctorMarks are the checklist marks of each result status
+var doctorMarks = map[string]string{
+	doctor.StatusOK:      "✅",
+	doctor.StatusWarning: "⚠️ ",
+	doctor.StatusFailed:  "❌",
+}
+
+// protoCommand prints the protobuf definitions of the export files: SystemState for combined snapshots
+// and one message per monitor for the monitors' own exports
+func protoCommand() int {
+	schema, err := protoexport.Schema(snapshot.SystemState{}, eventmonitor.EventMonitorData{})
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return 1
+	}
+	fmt.Println("// Messages of simple-monitor's protobuf exports, generated by `simple-monitor proto`")
+	fmt.Println("// Each .pb file holds one message: SystemState for combined snapshots (system_state_*.pb) and")
+	fmt.Println("// CPUMonitorData, MemoryMonitorData, DiskMonitorData, NetworkMonitorData, ProcessMonitorData or EventMonitorData for the monitors' exports")
+	fmt.Println("// Field numbers are derived from the field names, so they stay the same as fields are added or reordered")
+	fmt.Println()
+	fmt.Print(schema)
+	return 0
+}
+
+// fixturesCommand captures a snapshot of each monitor as a test fixture and renders its golden display output
+// Run it from the repository root (or pass the root); with --simulate the fixtures hold synthetic data
+func fixturesCommand(args []string) int {
+	options := fixtures.Options{Root: "."}
+	for _, arg := range args {
+		if arg == "--golden" {
+			options.GoldenOnly = true
+		} else {
+			options.Root = arg
+		}
+	}
+
+	// Fixtures are meant to be committed, so they never hold the names and addresses of this machine,
+	// and golden files show times in UTC so they render the same in every time zone
+	redact.SetConfig(redact.Config{Enabled: true})
+	time.Local = time.UTC
+
+	results := fixtures.Run(fixtureMonitors(), options)
+	for _, result := range results {
+		if result.Err != nil {
+			fmt.Printf("❌ %-8s %v\n", result.Name, result.Err)
+			continue
+		}
+		if result.Fixture != "" {
+			fmt.Printf("✅ %-8s %s\n", result.Name, result.Fixture)
+		}
+		fmt.Printf("✅ %-8s %s\n", result.Name, result.Golden)
+	}
+	if fixtures.Failed(results) {
+		return 1
+	}
+	return 0
+}
+
+// fixtureMonitors returns the monitors fixtures are captured for
+// Golden output is rendered without colors at the fixed 80-column width, so it does not depend on the terminal.
+// The event monitor streams events rather than collecting snapshots, so it has no fixture.
+func fixtureMonitors() []fixtures.Monitor {
+	return []fixtures.Monitor{
+		{Name: "CPU", Dir: "cpumonitor", Collect: func() (interface{}, error) {
+			return cpuMonitorManager.Collect()
+		}, Render: func(content []byte) error {
+			var data cpumonitor.CPUMonitorData
+			if err := json.Unmarshal(content, &data); err != nil {
+				return err
+			}
+			displayer := cpumonitor.NewCPUMonitorDisplayer()
+			displayer.ShowColors, displayer.AutoWidth = false, false
+			displayer.DisplayCPUMonitorData(&data)
+			return nil
+		}},
+		{Name: "Memory", Dir: "memorymonitor", Collect: func() (interface{}, error) {
+			return memoryMonitorManager.Collect()
+		}, Render: func(content []byte) error {
+			var data memorymonitor.MemoryMonitorData
+			if err := json.Unmarshal(content, &data); err != nil {
+				return err
+			}
+			displayer := memorymonitor.NewMemoryMonitorDisplayer()
+			displayer.ShowColors, displayer.AutoWidth = false, false
+			displayer.DisplayMemoryMonitorData(&data)
+			return nil
+		}},
+		{Name: "Disk", Dir: "diskmonitor", Collect: func() (interface{}, error) {
+			return diskMonitorManager.Collect()
+		}, Render: func(content []byte) error {
+			var data diskmonitor.DiskMonitorData
+			if err := json.Unmarshal(content, &data); err != nil {
+				return err
+			}
+			displayer := diskmonitor.NewDiskMonitorDisplayer()
+			displayer.ShowColors, displayer.AutoWidth = false, false
+			displayer.DisplayDiskMonitorData(&data)
+			return nil
+		}},
+		{Name: "Network", Dir: "networkmonitor", Collect: func() (interface{}, error) {
+			return networkMonitorManager.Collect()
+		}, Render: func(content []byte) error {
+			var data networkmonitor.NetworkMonitorData
+			if err := json.Unmarshal(content, &data); err != nil {
+				return err
+			}
+			displayer := networkmonitor.NewNetworkMonitorDisplayer()
+			displayer.ShowColors, displayer.AutoWidth = false, false
+			displayer.DisplayNetworkMonitorData(&data)
+			return nil
+		}},
+		{Name: "Process", Dir: "processmonitor", Collect: func() (interface{}, error) {
+			return processMonitorManager.Collect()
+		}, Render: func(content []byte) error {
+			var data processmonitor.ProcessMonitorData
+			if err := json.Unmarshal(content, &data); err != nil {
+				return err
+			}
+			displayer := processmonitor.NewProcessMonitorDisplayer()
+			displayer.ShowColors, displayer.AutoWidth = false, false
+			displayer.DisplayProcessMonitorData(&data)
+			return nil
+		}},
+	}
+}
+
+// doctorCommand checks that simple-monitor can see everything it monitors and prints a checklist
+// Every problem comes with a fix; the exit code is 1 when a check failed (warnings only mean less data)
+func doctorCommand() int {
+	results := doctor.Run(doctor.Options{
+		LogsDir:    logsDir,
+		ConfigPath: config.Path(),
+		System:     provider.System(),
+	})
+
+	fmt.Println("🩺 Simple Monitor Doctor")
+	fmt.Println()
+	warnings, failures := 0, 0
+	for _, result := range results {
+		fmt.Printf("%s %-16s %s\n", doctorMarks[result.Status], result.Name, result.Detail)
+		if result.Fix != "" {
+			fmt.Printf("   %-16s → %s\n", "", result.Fix)
+		}
+		switch result.Status {
+		case doctor.StatusWarning:
+			warnings++
+		case doctor.StatusFailed:
+			failures++
+		}
+	}
+
+	fmt.Println()
+	fmt.Printf("%d check(s): %d passed, %d warning(s), %d failed\n", len(results), len(results)-warnings-failures, warnings, failures)
+	if doctor.Failed(results) {
+		return 1
+	}
+	return 0
+}
+
+// knownGoodCommand saves the current state as known good (save) or lists how the system deviates from it (compare)
+// The state is kept in knowngood.Path unless a file is given; compare exits with 1 when anything deviates
+func knownGoodCommand(args []string) int {
+	path := knowngood.Path
+	if len(args) == 2 {
+		path = args[1]
+	}
+	loadConfigFile()
+
+	var reference *knowngood.State
+	if args[0] == "compare" {
+		var err error
+		reference, err = knowngood.Load(path)
+		if errors.Is(err, os.ErrNotExist) {
+			fmt.Printf("❌ No known-good state in %s; save one with `simple-monitor known-good save`\n", path)
+			return 2
+		}
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return 2
+		}
+	}
+
+	fmt.Println("🔍 Collecting processes, listening ports and filesystems...")
+	current, err := knowngood.Capture(knownGoodMonitors())
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return 2
+	}
+	summary := fmt.Sprintf("%d processes, %d listening ports, %d filesystems", len(current.Processes), len(current.Ports), len(current.Partitions))
+
+	if reference == nil {
+		if err := knowngood.Save(path, current); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return 2
+		}
+		fmt.Printf("✅ Saved the known-good state (%s) to %s\n", summary, path)
+		return 0
+	}
+
+	fmt.Printf("📋 Known-good state of %s saved %s\n", reference.Hostname, reference.Time.Format("2006-01-02 15:04:05"))
+	if reference.Hostname != current.Hostname {
+		fmt.Printf("⚠️  It was saved on %s, not on this host (%s)\n", reference.Hostname, current.Hostname)
+	}
+	deviations := knowngood.Compare(reference, current)
+	if len(deviations) == 0 {
+		fmt.Printf("✅ No deviations: %s match the known-good state\n", summary)
+		return 0
+	}
+
+	titles := map[string]string{
+		knowngood.CategoryProcesses: "Processes",
+		knowngood.CategoryPorts:     "Listening ports",
+		knowngood.CategoryDisks:     "Filesystems",
+	}
+	for _, category := range []string{knowngood.CategoryProcesses, knowngood.CategoryPorts, knowngood.CategoryDisks} {
+		var lines []string
+		for _, deviation := range deviations {
+			if deviation.Category == category {
+				lines = append(lines, deviation.String())
+			}
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		fmt.Printf("\n%s (%d)\n", titles[category], len(lines))
+		for _, line := range lines {
+			fmt.Println("  " + line)
+		}
+	}
+	fmt.Printf("\n❌ %d deviations from the known-good state\n", len(deviations))
+	return 1
+}
+
+// knownGoodMonitors returns the managers a known-good state is captured from
+// The process monitor is a separate one without filters or usage minimums, so every running process is recorded
+func knownGoodMonitors() snapshot.Monitors {
+	process := processmonitor.NewProcessMonitorManager(processmonitor.WithFilters("", ""))
+	config := process.GetConfig()
+	config.MinCPUUsage = 0
+	config.MinMemoryUsage = 0
+	config.SliceFilter = []string{}
+	process.UpdateConfig(config)
+	process.SetSimulationMode(processMonitorManager.IsSimulated())
+
+	return snapshot.Monitors{
+		Disk:    diskMonitorManager,
+		Network: networkMonitorManager,
+		Process: process,
+	}
+}
+
+// overlayGraphColumns is how many columns `simple-monitor overlay show` draws the series and the history in
+const overlayGraphColumns = 60
+
+// overlayCommand imports the external series drawn under the CPU and memory history graphs (import), compares
+// it with the long-term CPU and memory history over its time range (import and show) or removes it (clear)
+func overlayCommand(args []string) int {
+	path := filepath.Join(logsDir, overlay.File)
+	switch args[0] {
+	case "clear":
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			fmt.Printf("❌ Failed to remove the overlay series: %v\n", err)
+			return 1
+		}
+		fmt.Println("✅ Removed the overlay series")
+		return 0
+	case "import":
+		column := ""
+		if len(args) == 3 {
+			column = args[2]
+		}
+		series, err := overlay.Import(args[1], column, path)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return 1
+		}
+		fmt.Printf("✅ Imported %d %s samples to %s; the CPU and memory history graphs draw them from their next refresh\n",
+			len(series.Points), series.Name, path)
+	}
+
+	series, err := overlay.Load(path)
+	if errors.Is(err, os.ErrNotExist) {
+		fmt.Println("❌ No overlay series imported; import one with `simple-monitor overlay import <file.csv> [column]`")
+		return 1
+	}
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return 1
+	}
+
+	low, high, sum := math.Inf(1), math.Inf(-1), 0.0
+	for _, point := range series.Points {
+		low, high, sum = math.Min(low, point.Value), math.Max(high, point.Value), sum+point.Value
+	}
+	fmt.Printf("\n📈 %s from %s (imported %s)\n", series.Name, series.Source, series.Imported.Format("2006-01-02 15:04:05"))
+	fmt.Printf("   %d samples from %s to %s: min %s, average %s, max %s\n",
+		len(series.Points), series.Start().Format("2006-01-02 15:04:05"), series.End().Format("2006-01-02 15:04:05"),
+		overlay.Format(low), overlay.Format(sum/float64(len(series.Points))), overlay.Format(high))
+
+	// The time range is split into equal columns, each drawn at its end time
+	columns := overlayGraphColumns
+	span := series.End().Sub(series.Start())
+	if span <= 0 {
+		columns = 1
+	}
+	times := make([]time.Time, columns)
+	for i := range times {
+		times[i] = series.Start()
+		if columns > 1 {
+			times[i] = times[i].Add(time.Duration(float64(span) * float64(i) / float64(columns-1)))
+		}
+	}
+
+	values := series.Align(times)
+	fmt.Printf("\n%-8s %s %s max\n", terminal.Truncate(series.Name, 8), overlay.Sparkline(values, high, columns), overlay.Format(high))
+	for _, history := range []struct{ label, file, field string }{
+		{"CPU", "cpumonitor.json", "overall_usage"},
+		{"Memory", "memorymonitor.json", "total_usage"},
+	} {
+		usage, err := overlayHistory(filepath.Join(logsDir, "history", history.file), history.field, times)
+		switch {
+		case os.IsNotExist(err):
+			fmt.Printf("%-8s no long-term history saved yet (persist_history)\n", history.label)
+		case err != nil:
+			fmt.Printf("%-8s %v\n", history.label, err)
+		case usage == nil:
+			fmt.Printf("%-8s no history in this time range\n", history.label)
+		default:
+			correlation := ""
+			if coefficient, ok := overlay.Correlation(values, usage); ok {
+				correlation = fmt.Sprintf(", correlation %.2f", coefficient)
+			}
+			fmt.Printf("%-8s %s 100%%%s\n", history.label, overlay.Sparkline(usage, 100, columns), correlation)
+		}
+	}
+	fmt.Printf("%-8s %s to %s\n", "", series.Start().Format("2006-01-02 15:04"), series.End().Format("2006-01-02 15:04"))
+	return 0
+}
+
+// overlayHistory returns one metric of a saved long-term history at times, or nil when it has no value then
+func overlayHistory(path, field string, times []time.Time) ([]float64, error) {
+	store := historystore.NewStore(historystore.DefaultTiers())
+	if err := store.Load(path); err != nil {
+		return nil, err
+	}
+	history := &overlay.Series{}
+	for _, point := range store.Series(field) {
+		history.Points = append(history.Points, overlay.Point{Time: point.Timestamp, Value: point.Value})
+	}
+
+	values := history.Align(times)
+	for _, value := range values {
+		if !math.IsNaN(value) {
+			return values, nil
+		}
+	}
+	return nil, nil
+}
+
+// pruneCommand applies the data retention period from the config file once, or only reports with --dry-run
+func pruneCommand(dryRun bool) int {
+	loadConfigFile()
+	report, err := retention.Run(logsDir, retention.GetConfig().Days, time.Now(), dryRun)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		fmt.Println("   Set \"retention\": { \"days\": 30 } in the config file")
+		return 1
+	}
+	printRetentionReport(report)
+	if len(report.Errors) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// Nagios plugin exit codes returned by the check command
+const (
+	checkOK       = 0
+	checkWarning  = 1
+	checkCritical = 2
+	checkUnknown  = 3
+)
+
+// checkStatus names each check exit code in the summary line
+var checkStatus = []string{"OK", "WARNING", "CRITICAL", "UNKNOWN"}
+
+// checkMetric is a value the check command can test against thresholds
+type checkMetric struct {
+	warn, crit float64                                 // Default thresholds in percent
+	read       func(path string) (float64, string, error) // Collects the value and describes where it was measured
+}
+
+// checkMetrics lists the monitors accepted by --monitor
+var checkMetrics = map[string]checkMetric{
+	"cpu":     {warn: 80, crit: 90, read: readCPUCheck},
+	"memory":  {warn: 80, crit: 90, read: readMemoryCheck},
+	"swap":    {warn: 50, crit: 80, read: readSwapCheck},
+	"disk":    {warn: 80, crit: 90, read: readDiskCheck},
+	"network": {warn: 5, crit: 20, read: readNetworkCheck},
+}
+
+// checkCommand collects one monitor once and reports it the way Nagios/Icinga plugins do:
+// a single "DISK WARNING - 85.2% used on / | disk=85.2%;80;90;0;100" line and exit code 0/1/2/3
+// Values at or above --warn/--crit are a warning/critical; usage and collection errors are UNKNOWN
+func checkCommand(args []string) int {
+	flags := flag.NewFlagSet("check", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	monitor := flags.String("monitor", "", "")
+	warn := flags.Float64("warn", -1, "")
+	crit := flags.Float64("crit", -1, "")
+	path := flags.String("path", "", "")
+	if err := flags.Parse(args); err != nil {
+		fmt.Printf("CHECK UNKNOWN - %v\n", err)
+		return checkUnknown
+	}
+
+	metric, ok := checkMetrics[*monitor]
+	if !ok || flags.NArg() > 0 {
+		names := make([]string, 0, len(checkMetrics))
+		for name := range checkMetrics {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fmt.Printf("CHECK UNKNOWN - usage: simple-monitor check --monitor=%s [--warn=N] [--crit=N] [--path=mount]\n", strings.Join(names, "|"))
+		return checkUnknown
+	}
+	if *warn < 0 {
+		*warn = metric.warn
+	}
+	if *crit < 0 {
+		*crit = metric.crit
+	}
+	label := strings.ToUpper(*monitor)
+	if *warn > *crit {
+		fmt.Printf("%s UNKNOWN - warning threshold %g is above critical threshold %g\n", label, *warn, *crit)
+		return checkUnknown
+	}
+
+	value, detail, err := metric.read(*path)
+	if err != nil {
+		fmt.Printf("%s UNKNOWN - %v\n", label, err)
+		return checkUnknown
+	}
+
+	code := checkOK
+	switch {
+	case value >= *crit:
+		code = checkCritical
+	case value >= *warn:
+		code = checkWarning
+	}
+	fmt.Printf("%s %s - %.1f%% %s | %s=%.1f%%;%g;%g;0;100\n", label, checkStatus[code], value, detail, *monitor, value, *warn, *crit)
+	return code
+}
+
+// readCPUCheck measures overall CPU usage over one second
+func readCPUCheck(string) (float64, string, error) {
+	data, err := cpuMonitorManager.Collect()
+	if err != nil {
+		return 0, "", err
+	}
+	return data.OverallUsage, "CPU used", nil
+}
+
+// readMemoryCheck reads the share of physical memory in use
+func readMemoryCheck(string) (float64, string, error) {
+	data, err := memoryMonitorManager.Collect()
+	if err != nil {
+		return 0, "", err
+	}
+	return data.MemoryPercent, "memory used", nil
+}
+
+// readSwapCheck reads the share of swap in use
+func readSwapCheck(string) (float64, string, error) {
+	data, err := memoryMonitorManager.Collect()
+	if err != nil {
+		return 0, "", err
+	}
+	if data.SwapInfo.TotalSwap == 0 {
+		return 0, "swap used (no swap configured)", nil
+	}
+	return data.SwapInfo.SwapPercent, "swap used", nil
+}
+
+// readDiskCheck reads the usage of the mount given by path, or of the fullest mount that is not excluded
+func readDiskCheck(path string) (float64, string, error) {
+	data, err := diskMonitorManager.Collect()
+	if err != nil {
+		return 0, "", err
+	}
+
+	var fullest *diskmonitor.DiskPartitionInfo
+	for i, partition := range data.Partitions {
+		if path != "" {
+			if partition.Mountpoint == path {
+				fullest = &data.Partitions[i]
+				break
+			}
+			continue
 		}
-	case 5:
-		return
+		if !partition.Excluded && (fullest == nil || partition.UsagePercent > fullest.UsagePercent) {
+			fullest = &data.Partitions[i]
+		}
+	}
+	if fullest == nil {
+		if path != "" {
+			return 0, "", fmt.Errorf("no mounted filesystem at %s", path)
+		}
+		return 0, "", fmt.Errorf("no mounted filesystems found")
 	}
+	return fullest.UsagePercent, "used on " + fullest.Mountpoint, nil
+}
 
-	// Update all monitor refresh intervals
-	cpuConfig := cpuMonitorManager.GetConfiguration()
-	cpuConfig.RefreshInterval = interval
-	cpuMonitorManager.SetConfiguration(cpuConfig)
+// readNetworkCheck reads the average packet loss of the latency probes
+func readNetworkCheck(string) (float64, string, error) {
+	data, err := networkMonitorManager.Collect()
+	if err != nil {
+		return 0, "", err
+	}
+	if len(data.LatencyInfo) == 0 {
+		return 0, "", fmt.Errorf("no latency targets configured")
+	}
+	return data.PacketLossRate, fmt.Sprintf("packet loss to %d latency targets", len(data.LatencyInfo)), nil
+}
 
-	memoryConfig := memoryMonitorManager.GetConfig()
-	memoryConfig.RefreshInterval = interval
-	memoryMonitorManager.UpdateConfig(memoryConfig)
+// statusSegment is one "CPU 23%" part of the status line and the check level it stands for
+type statusSegment struct {
+	text string
+	code int
+}
 
-	diskConfig := diskMonitorManager.GetConfig()
-	diskConfig.RefreshInterval = interval
-	diskMonitorManager.UpdateConfig(diskConfig)
+// statusSeverity orders check exit codes from healthy to critical, so a failed collection
+// does not hide a real warning elsewhere on the status line
+var statusSeverity = map[int]int{checkOK: 0, checkUnknown: 1, checkWarning: 2, checkCritical: 3}
 
-	networkConfig := networkMonitorManager.GetConfig()
-	networkConfig.RefreshInterval = interval
-	networkMonitorManager.UpdateConfig(networkConfig)
+// statusClasses names each check exit code in waybar's "class" field
+var statusClasses = []string{"ok", "warning", "critical", "unknown"}
 
-	processConfig := processMonitorManager.GetConfig()
-	processConfig.RefreshInterval = interval
-	processMonitorManager.UpdateConfig(processConfig)
+// statusColors are the color hints of each check exit code in bar formats; healthy parts keep the bar's own color
+// The color-blind variants follow the console palette: magenta instead of red
+var statusColors = map[bool][]string{
+	false: {"", "#e5c07b", "#e06c75", "#888888"},
+	true:  {"", "#e5c07b", "#d33682", "#888888"},
+}
 
-	fmt.Printf("✅ Refresh rate set to: %v\n", interval)
-	waitForEnter()
+// statusFormats renders the status line for each --format
+var statusFormats = map[string]func(segments []statusSegment, code int) string{
+	"text":    formatStatusText,
+	"waybar":  formatStatusWaybar,
+	"polybar": formatStatusPolybar,
+	"tmux":    formatStatusTmux,
 }
 
-func setDisplayFormat() {
-	fmt.Println("\n📄 Set Display Format")
-	fmt.Println(strings.Repeat("-", 30))
-	fmt.Println("1. Compact (Minimal info)")
-	fmt.Println("2. Standard (Normal info)")
-	fmt.Println("3. Detailed (Full info)")
-	fmt.Println("4. Back to Display Settings")
-	fmt.Print("Select option (1-4): ")
+// statusCommand collects every monitor once and prints a single line such as
+// "CPU 23% | MEM 61% | DISK 78% (warn /var) | NET ok | PROCS 312" for tmux, polybar or a shell prompt
+// The exit code is the worst level on the line, using the check command's default thresholds;
+// bar formats always exit 0 since waybar and polybar drop the output of a failing command
+func statusCommand(args []string) int {
+	flags := flag.NewFlagSet("status", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	format := flags.String("format", "text", "")
+	err := flags.Parse(args)
+	render, ok := statusFormats[*format]
+	if err != nil || !ok || flags.NArg() > 0 {
+		fmt.Println("STATUS UNKNOWN - usage: simple-monitor status [--format=text|waybar|polybar|tmux]")
+		return checkUnknown
+	}
 
-	choice := getUserChoice(4)
+	// Monitors keep their built-in settings like check, but the score follows the configured weights
+	if file, err := config.Load(config.Path()); err == nil {
+		scoreConfig := healthscore.GetConfig()
+		if file.Apply("score", &scoreConfig) == nil {
+			healthscore.SetConfig(scoreConfig)
+		}
+	}
 
-	switch choice {
-	case 1:
-		fmt.Println("✅ Display format set to: Compact")
-	case 2:
-		fmt.Println("✅ Display format set to: Standard")
-	case 3:
-		fmt.Println("✅ Display format set to: Detailed")
-	case 4:
-		return
+	state := snapshot.Collect(snapshot.Monitors{
+		CPU:     cpuMonitorManager,
+		Memory:  memoryMonitorManager,
+		Disk:    diskMonitorManager,
+		Network: networkMonitorManager,
+		Process: processMonitorManager,
+	})
+	segments := statusSegments(state)
+
+	code := checkOK
+	for _, segment := range segments {
+		if statusSeverity[segment.code] > statusSeverity[code] {
+			code = segment.code
+		}
 	}
-	waitForEnter()
+	fmt.Println(render(segments, code))
+	if *format != "text" {
+		return checkOK
+	}
+	return code
 }
 
-func toggleColors() {
-	fmt.Println("\n🎨 Toggle Colors")
-	fmt.Println(strings.Repeat("-", 30))
-	fmt.Println("1. Enable Colors")
-	fmt.Println("2. Disable Colors")
-	fmt.Println("3. Back to Display Settings")
-	fmt.Print("Select option (1-3): ")
-
-	choice := getUserChoice(3)
+// formatStatusText joins the segments into the plain "CPU 23% | MEM 61%" line
+func formatStatusText(segments []statusSegment, _ int) string {
+	parts := make([]string, len(segments))
+	for i, segment := range segments {
+		parts[i] = segment.text
+	}
+	return strings.Join(parts, " | ")
+}
 
-	switch choice {
-	case 1:
-		fmt.Println("✅ Colors enabled")
-	case 2:
-		fmt.Println("❌ Colors disabled")
-	case 3:
-		return
+// formatStatusWaybar emits the JSON object of a waybar custom module with "return-type": "json":
+// the line as text, one segment per tooltip row and the worst level as the CSS class
+func formatStatusWaybar(segments []statusSegment, code int) string {
+	rows := make([]string, len(segments))
+	for i, segment := range segments {
+		rows[i] = segment.text
 	}
-	waitForEnter()
+	output, _ := json.Marshal(struct {
+		Text    string `json:"text"`
+		Tooltip string `json:"tooltip"`
+		Class   string `json:"class"`
+	}{formatStatusText(segments, code), strings.Join(rows, "\n"), statusClasses[code]})
+	return string(output)
 }
 
-func setScreenSize() {
-	fmt.Println("\n📺 Set Screen Size")
-	fmt.Println(strings.Repeat("-", 30))
-	fmt.Println("1. Small (80x24)")
-	fmt.Println("2. Medium (120x30)")
-	fmt.Println("3. Large (160x40)")
-	fmt.Println("4. Back to Display Settings")
-	fmt.Print("Select option (1-4): ")
+// formatStatusPolybar colors the segments that need attention with %{F#rrggbb} tags for a polybar custom/script module
+func formatStatusPolybar(segments []statusSegment, _ int) string {
+	return colorStatusSegments(segments, func(color, text string) string {
+		return "%{F" + color + "}" + text + "%{F-}"
+	})
+}
 
-	choice := getUserChoice(4)
+// formatStatusTmux colors the segments that need attention with #[fg=#rrggbb] styles for status-right
+func formatStatusTmux(segments []statusSegment, _ int) string {
+	return colorStatusSegments(segments, func(color, text string) string {
+		return "#[fg=" + color + "]" + text + "#[default]"
+	})
+}
 
-	switch choice {
-	case 1:
-		fmt.Println("✅ Screen size set to: Small (80x24)")
-	case 2:
-		fmt.Println("✅ Screen size set to: Medium (120x30)")
-	case 3:
-		fmt.Println("✅ Screen size set to: Large (160x40)")
-	case 4:
-		return
+// colorStatusSegments joins the segments, wrapping each one that is not OK in its level's color
+func colorStatusSegments(segments []statusSegment, wrap func(color, text string) string) string {
+	colors := statusColors[terminal.ColorBlindFromEnv()]
+	parts := make([]string, len(segments))
+	for i, segment := range segments {
+		parts[i] = segment.text
+		if color := colors[segment.code]; color != "" {
+			parts[i] = wrap(color, segment.text)
+		}
 	}
-	waitForEnter()
+	return strings.Join(parts, " | ")
 }
 
-func setMonitoringInterval() {
-	fmt.Println("\n⏰ Set Monitoring Interval")
-	fmt.Println(strings.Repeat("-", 30))
-	fmt.Println("1. 1 second")
-	fmt.Println("2. 5 seconds")
-	fmt.Println("3. 10 seconds")
-	fmt.Println("4. 30 seconds")
-	fmt.Println("5. Back to Monitoring Settings")
-	fmt.Print("Select option (1-5): ")
-
-	choice := getUserChoice(5)
+// statusSegments turns a combined snapshot into the parts of the status line
+// A monitor that could not be collected is shown as "?" and counts as unknown
+func statusSegments(state *snapshot.SystemState) []statusSegment {
+	segments := make([]statusSegment, 0, 6)
+	segments = append(segments, statusScore(state.Score))
 
-	var interval time.Duration
-	switch choice {
-	case 1:
-		interval = 1 * time.Second
-	case 2:
-		interval = 5 * time.Second
-	case 3:
-		interval = 10 * time.Second
-	case 4:
-		interval = 30 * time.Second
-	case 5:
-		return
+	if state.CPU != nil {
+		segments = append(segments, statusPercent("CPU", "cpu", state.CPU.OverallUsage, ""))
+	} else {
+		segments = append(segments, statusSegment{"CPU ?", checkUnknown})
 	}
 
-	// Update all monitor intervals
-	cpuConfig := cpuMonitorManager.GetConfiguration()
-	cpuConfig.RefreshInterval = interval
-	cpuMonitorManager.SetConfiguration(cpuConfig)
+	if state.Memory != nil {
+		segments = append(segments, statusPercent("MEM", "memory", state.Memory.MemoryPercent, ""))
+	} else {
+		segments = append(segments, statusSegment{"MEM ?", checkUnknown})
+	}
 
-	memoryConfig := memoryMonitorManager.GetConfig()
-	memoryConfig.RefreshInterval = interval
-	memoryMonitorManager.UpdateConfig(memoryConfig)
+	var fullest *diskmonitor.DiskPartitionInfo
+	if state.Disk != nil {
+		for i, partition := range state.Disk.Partitions {
+			if !partition.Excluded && (fullest == nil || partition.UsagePercent > fullest.UsagePercent) {
+				fullest = &state.Disk.Partitions[i]
+			}
+		}
+	}
+	if fullest != nil {
+		segments = append(segments, statusPercent("DISK", "disk", fullest.UsagePercent, fullest.Mountpoint))
+	} else {
+		segments = append(segments, statusSegment{"DISK ?", checkUnknown})
+	}
 
-	diskConfig := diskMonitorManager.GetConfig()
-	diskConfig.RefreshInterval = interval
-	diskMonitorManager.UpdateConfig(diskConfig)
+	switch {
+	case state.Network == nil:
+		segments = append(segments, statusSegment{"NET ?", checkUnknown})
+	case state.Network.NetworkStatus == "Critical":
+		segments = append(segments, statusSegment{"NET crit", checkCritical})
+	case state.Network.NetworkStatus == "Warning":
+		segments = append(segments, statusSegment{"NET warn", checkWarning})
+	default:
+		segments = append(segments, statusSegment{"NET ok", checkOK})
+	}
 
-	networkConfig := networkMonitorManager.GetConfig()
-	networkConfig.RefreshInterval = interval
-	networkMonitorManager.UpdateConfig(networkConfig)
+	if state.Process != nil {
+		segments = append(segments, statusSegment{fmt.Sprintf("PROCS %d", state.Process.TotalProcesses), checkOK})
+	} else {
+		segments = append(segments, statusSegment{"PROCS ?", checkUnknown})
+	}
+	return segments
+}
 
-	processConfig := processMonitorManager.GetConfig()
-	processConfig.RefreshInterval = interval
-	processMonitorManager.UpdateConfig(processConfig)
+// statusScoreCodes maps health score levels to check exit codes
+var statusScoreCodes = map[string]int{
+	healthscore.StatusNormal:   checkOK,
+	healthscore.StatusWarning:  checkWarning,
+	healthscore.StatusCritical: checkCritical,
+	healthscore.StatusUnknown:  checkUnknown,
+}
 
-	fmt.Printf("✅ Monitoring interval set to: %v\n", interval)
-	waitForEnter()
+// statusScore formats the health score segment; below the warning score the weakest monitor follows in brackets
+func statusScore(score *healthscore.Score) statusSegment {
+	if score == nil || score.Status == healthscore.StatusUnknown {
+		return statusSegment{"SCORE ?", checkUnknown}
+	}
+	segment := statusSegment{fmt.Sprintf("SCORE %.0f", score.Value), statusScoreCodes[score.Status]}
+	if weakest := score.Weakest(); weakest != nil && segment.code != checkOK {
+		segment.text += " (" + weakest.Name + ")"
+	}
+	return segment
 }
 
-func toggleAutoStart() {
-	fmt.Println("\n🚀 Auto-Start Settings")
-	fmt.Println(strings.Repeat("-", 30))
-	fmt.Println("1. Enable Auto-Start")
-	fmt.Println("2. Disable Auto-Start")
-	fmt.Println("3. Back to Monitoring Settings")
-	fmt.Print("Select option (1-3): ")
+// statusPercent formats a percentage segment against the check thresholds of metric
+// Above a threshold the level and, when given, where it was measured follow in brackets
+func statusPercent(label, metric string, value float64, where string) statusSegment {
+	thresholds := checkMetrics[metric]
+	segment := statusSegment{fmt.Sprintf("%s %.0f%%", label, value), checkOK}
+	switch {
+	case value >= thresholds.crit:
+		segment.code = checkCritical
+	case value >= thresholds.warn:
+		segment.code = checkWarning
+	default:
+		return segment
+	}
 
-	choice := getUserChoice(3)
+	level := "warn"
+	if segment.code == checkCritical {
+		level = "crit"
+	}
+	if where != "" {
+		level += " " + where
+	}
+	segment.text += " (" + level + ")"
+	return segment
+}
 
-	switch choice {
-	case 1:
-		fmt.Println("✅ Auto-start enabled")
-	case 2:
-		fmt.Println("❌ Auto-start disabled")
-	case 3:
-		return
+// replayCommand parses the replay arguments: an optional recording file and an optional speed such as 2 or 0.5
+func replayCommand(args []string) int {
+	path := ""
+	speed := 1.0
+	for _, arg := range args {
+		if value, err := strconv.ParseFloat(strings.TrimSuffix(arg, "x"), 64); err == nil {
+			if value <= 0 {
+				fmt.Printf("❌ Replay speed must be greater than zero: %s\n", arg)
+				return 2
+			}
+			speed = value
+			continue
+		}
+		path = arg
 	}
-	waitForEnter()
+
+	if path == "" {
+		paths, err := recording.List(recordingsDir)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return 1
+		}
+		if len(paths) == 0 {
+			fmt.Printf("❌ No recordings found in %s (record one with --record)\n", recordingsDir)
+			return 1
+		}
+		path = paths[0]
+	}
+	return replayRecording(path, speed)
 }
 
-func setDataRetention() {
-	fmt.Println("\n💾 Data Retention Settings")
-	fmt.Println(strings.Repeat("-", 30))
-	fmt.Println("1. 1 day")
-	fmt.Println("2. 7 days")
-	fmt.Println("3. 30 days")
-	fmt.Println("4. 90 days")
-	fmt.Println("5. Back to Monitoring Settings")
-	fmt.Print("Select option (1-5): ")
+// replayRecording plays a recorded session back through the monitor displays
+// Frames are shown with their original spacing divided by speed; + and - change the speed,
+// space pauses, n and b step while paused, and q or Ctrl+C stops
+func replayRecording(path string, speed float64) int {
+	frames, err := recording.Load(path)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return 1
+	}
+	info := recording.Describe(path, frames)
+	fmt.Printf("⏪ Replaying %s: %d frames (%s) over %s\n", path, info.Frames,
+		strings.Join(info.Monitors, ", "), info.Duration.Round(time.Second))
 
-	choice := getUserChoice(5)
+	displays := recordedDisplays()
 
-	switch choice {
-	case 1:
-		fmt.Println("✅ Data retention set to: 1 day")
-	case 2:
-		fmt.Println("✅ Data retention set to: 7 days")
-	case 3:
-		fmt.Println("✅ Data retention set to: 30 days")
-	case 4:
-		fmt.Println("✅ Data retention set to: 90 days")
-	case 5:
-		return
+	// Without single-key input (e.g. input is not a terminal) the replay runs straight through
+	var keyChan <-chan byte
+	if keys, err := terminal.NewKeyReader(); err == nil {
+		defer keys.Close()
+		keyChan = keys.Keys()
+	}
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+
+	index := 0
+	paused := false
+	for {
+		frame := frames[index]
+		if display, known := displays[frame.Monitor]; !known {
+			fmt.Printf("⚠️  Skipping frame for unknown monitor %q\n", frame.Monitor)
+		} else if err := display(frame.Data); err != nil {
+			fmt.Printf("❌ %v\n", err)
+		}
+		status := fmt.Sprintf("⏪ Replay %d/%d • %s • %gx", index+1, len(frames), frame.Time.Format("2006-01-02 15:04:05"), speed)
+		if paused {
+			status += " • paused"
+		}
+		fmt.Println("\n" + status)
+		if keyChan != nil {
+			fmt.Println("[+/-] speed  [space] pause  [n/b] step  [q] quit")
+		}
+
+		if index == len(frames)-1 && !paused {
+			fmt.Println("⏹️  End of recording")
+			return 0
+		}
+
+		var timer <-chan time.Time
+		if !paused {
+			timer = time.After(recording.Delay(frame, frames[index+1], speed))
+		}
+		select {
+		case <-timer:
+			index++
+		case key := <-keyChan:
+			switch key {
+			case '+', '=':
+				speed = recording.NextSpeed(speed, 1)
+			case '-', '_':
+				speed = recording.NextSpeed(speed, -1)
+			case ' ':
+				paused = !paused
+			case 'n', 'N':
+				if index < len(frames)-1 {
+					index++
+				}
+			case 'b', 'B':
+				if index > 0 {
+					index--
+				}
+			case 'q', 'Q':
+				return 0
+			}
+		case <-sigChan:
+			fmt.Println("\n🛑 Replay stopped")
+			return 0
+		}
 	}
-	waitForEnter()
 }
 
-func configureAlerts() {
-	fmt.Println("\n🚨 Configure Alerts")
-	fmt.Println(strings.Repeat("-", 30))
-	fmt.Println("1. CPU Usage Alert")
-	fmt.Println("2. Memory Usage Alert")
-	fmt.Println("3. Disk Space Alert")
-	fmt.Println("4. Network Alert")
-	fmt.Println("5. Back to Monitoring Settings")
-	fmt.Print("Select option (1-5): ")
+// recordedDisplays maps each monitor name used in recorded frames to the display of that monitor
+func recordedDisplays() map[string]func([]byte) error {
+	return map[string]func([]byte) error{
+		"cpu":     cpuMonitorManager.DisplayRecordedData,
+		"memory":  memoryMonitorManager.DisplayRecordedData,
+		"disk":    diskMonitorManager.DisplayRecordedData,
+		"network": networkMonitorManager.DisplayRecordedData,
+		"process": processMonitorManager.DisplayRecordedData,
+		"events":  eventMonitorManager.DisplayRecordedData,
+	}
+}
 
-	choice := getUserChoice(5)
+// viewerIdleNotice is how long the viewer waits for a new frame before saying the primary instance shows no live monitor
+const viewerIdleNotice = 10 * time.Second
 
-	switch choice {
-	case 1:
-		fmt.Println("✅ CPU usage alerts configured")
-	case 2:
-		fmt.Println("✅ Memory usage alerts configured")
-	case 3:
-		fmt.Println("✅ Disk space alerts configured")
-	case 4:
-		fmt.Println("✅ Network alerts configured")
-	case 5:
-		return
+// runViewer attaches to the instance holding the lock and shows the live monitor it is showing
+// The viewer reads the primary's live feed only: it collects nothing and writes no exports, history or recordings
+func runViewer() int {
+	owner, err := instance.ReadOwner(logsDir)
+	if err != nil {
+		fmt.Printf("❌ No running instance to attach to: %v\n", err)
+		fmt.Println("   Start simple-monitor without --viewer to monitor this system")
+		return 1
 	}
-	waitForEnter()
-}
+	fmt.Printf("👀 Viewer mode: following simple-monitor PID %d (read-only). Ctrl+C to quit\n", owner.PID)
 
-func setCPUPriority() {
-	fmt.Println("\n⚡ CPU Priority Settings")
-	fmt.Println(strings.Repeat("-", 30))
-	fmt.Println("1. Low Priority")
-	fmt.Println("2. Normal Priority")
-	fmt.Println("3. High Priority")
-	fmt.Println("4. Back to Performance Settings")
-	fmt.Print("Select option (1-4): ")
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+
+	// Frames from before the primary started belong to an earlier session
+	displays := recordedDisplays()
+	shown := owner.Started
+	waiting := false
+	for {
+		if current, err := instance.ReadOwner(logsDir); err != nil || current.PID != owner.PID {
+			fmt.Printf("\n🛑 simple-monitor PID %d has stopped\n", owner.PID)
+			return 0
+		}
 
-	choice := getUserChoice(4)
+		frame, err := recording.LatestFrame(liveFeedDir)
+		switch {
+		case err == nil && frame.Time.After(shown):
+			if display, known := displays[frame.Monitor]; known {
+				if err := display(frame.Data); err != nil {
+					fmt.Printf("❌ %v\n", err)
+				}
+			}
+			fmt.Printf("\n👀 Viewer of PID %d • %s • read-only, Ctrl+C quits\n", owner.PID, frame.Time.Format("2006-01-02 15:04:05"))
+			shown = frame.Time
+			waiting = false
+		case !waiting && time.Since(shown) > viewerIdleNotice:
+			fmt.Printf("\n⏳ Waiting for PID %d to show a live monitor...\n", owner.PID)
+			waiting = true
+		}
 
-	switch choice {
-	case 1:
-		fmt.Println("✅ CPU priority set to: Low")
-	case 2:
-		fmt.Println("✅ CPU priority set to: Normal")
-	case 3:
-		fmt.Println("✅ CPU priority set to: High")
-	case 4:
-		return
+		select {
+		case <-ticker.C:
+		case <-sigChan:
+			fmt.Println("\n👋 Viewer detached")
+			return 0
+		}
 	}
-	waitForEnter()
 }
 
-func setMemoryLimit() {
-	fmt.Println("\n💾 Memory Limit Settings")
-	fmt.Println(strings.Repeat("-", 30))
-	fmt.Println("1. 100 MB")
-	fmt.Println("2. 500 MB")
-	fmt.Println("3. 1 GB")
-	fmt.Println("4. 2 GB")
-	fmt.Println("5. Back to Performance Settings")
-	fmt.Print("Select option (1-5): ")
+// attachPollInterval is how often an attached session asks the daemon for new data
+// The daemon collects each monitor at most once per refresh interval however often it is asked
+const attachPollInterval = 500 * time.Millisecond
+
+// runAttached shows the live monitors of the daemon holding the lock, which collects them for this session
+// Pressing 1-6 switches to another monitor the daemon serves; Ctrl+C detaches and leaves the daemon running
+func runAttached(client *attach.Client) int {
+	info := client.Info()
+	if len(info.Monitors) == 0 {
+		fmt.Printf("❌ The simple-monitor daemon (PID %d) has every monitor disabled\n", info.PID)
+		return 1
+	}
+	fmt.Printf("🔗 Attached to the simple-monitor daemon (PID %d, running since %s). Ctrl+C detaches\n",
+		info.PID, info.Started.Local().Format("2006-01-02 15:04:05"))
 
-	choice := getUserChoice(5)
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+	ticker := time.NewTicker(attachPollInterval)
+	defer ticker.Stop()
+
+	// Without single-key input (e.g. input is not a terminal) the first monitor is shown until Ctrl+C
+	var keys <-chan byte
+	if reader, err := terminal.NewKeyReader(); err == nil {
+		defer reader.Close()
+		keys = reader.Keys()
+	}
 
-	switch choice {
-	case 1:
-		fmt.Println("✅ Memory limit set to: 100 MB")
-	case 2:
-		fmt.Println("✅ Memory limit set to: 500 MB")
-	case 3:
-		fmt.Println("✅ Memory limit set to: 1 GB")
-	case 4:
-		fmt.Println("✅ Memory limit set to: 2 GB")
-	case 5:
-		return
+	displays := recordedDisplays()
+	current := info.Monitors[0]
+	var shown time.Time
+	lastError := ""
+	for {
+		frame, err := client.Collect(current)
+		switch {
+		case err != nil:
+			if owner, ownerErr := instance.ReadOwner(logsDir); ownerErr != nil || owner.PID != info.PID {
+				fmt.Printf("\n🛑 The simple-monitor daemon (PID %d) has stopped\n", info.PID)
+				return 0
+			}
+			if err.Error() != lastError {
+				fmt.Printf("\n❌ %v\n", err)
+				lastError = err.Error()
+			}
+		case frame.Time.After(shown):
+			if display, known := displays[frame.Monitor]; known {
+				if err := display(frame.Data); err != nil {
+					fmt.Printf("❌ %v\n", err)
+				}
+			}
+			fmt.Println(attachedHint(info, current))
+			shown = frame.Time
+			lastError = ""
+		}
+
+		select {
+		case <-ticker.C:
+		case key := <-keys:
+			for i, name := range liveMonitorSections {
+				if key == byte('1'+i) && name != current && slices.Contains(info.Monitors, name) {
+					current = name
+					shown = time.Time{}
+					fmt.Print("\033[2J\033[H")
+				}
+			}
+		case <-sigChan:
+			fmt.Println("\n👋 Detached; the daemon keeps running")
+			return 0
+		}
 	}
-	waitForEnter()
 }
 
-func toggleBackgroundMode() {
-	fmt.Println("\n🔄 Background Mode")
-	fmt.Println(strings.Repeat("-", 30))
-	fmt.Println("1. Enable Background Mode")
-	fmt.Println("2. Disable Background Mode")
-	fmt.Println("3. Back to Performance Settings")
-	fmt.Print("Select option (1-3): ")
+// attachedHint returns the line shown under each refresh of an attached session
+func attachedHint(info attach.Hello, current string) string {
+	var parts []string
+	for i, name := range liveMonitorSections {
+		if !slices.Contains(info.Monitors, name) {
+			continue
+		}
+		if name == current {
+			parts = append(parts, fmt.Sprintf("[%d %s]", i+1, liveMonitorNames[i]))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d %s", i+1, liveMonitorNames[i]))
+		}
+	}
+	return fmt.Sprintf("\n🔗 Daemon PID %d  |  %s  |  Ctrl+C detaches", info.PID, strings.Join(parts, "  "))
+}
 
-	choice := getUserChoice(3)
+// validateConfigFile checks a config file and prints every problem with a suggested fix
+func validateConfigFile(path string) int {
+	file, err := config.Load(path)
+	if os.IsNotExist(err) {
+		fmt.Printf("❌ Config file not found: %s\n", path)
+		return 1
+	}
+	if err != nil {
+		fmt.Printf("❌ %s: %v\n", path, err)
+		return 1
+	}
 
-	switch choice {
-	case 1:
-		fmt.Println("✅ Background mode enabled")
-	case 2:
-		fmt.Println("❌ Background mode disabled")
-	case 3:
-		return
+	issues := file.Validate()
+	printConfigIssues(issues)
+	if config.HasErrors(issues) {
+		fmt.Printf("\n❌ %s is invalid\n", path)
+		return 1
 	}
-	waitForEnter()
+	fmt.Printf("✅ %s is valid (schema version %d)\n", path, file.Version)
+	return 0
 }
 
-func setThreadCount() {
-	fmt.Println("\n🧵 Thread Count Settings")
-	fmt.Println(strings.Repeat("-", 30))
-	fmt.Println("1. 1 thread")
-	fmt.Println("2. 2 threads")
-	fmt.Println("3. 4 threads")
-	fmt.Println("4. 8 threads")
-	fmt.Println("5. Back to Performance Settings")
-	fmt.Print("Select option (1-5): ")
-
-	choice := getUserChoice(5)
+// migrateConfigFile upgrades a config file to the current schema, keeping a backup of the original
+func migrateConfigFile(path string) int {
+	file, err := config.Load(path)
+	if os.IsNotExist(err) {
+		fmt.Printf("❌ Config file not found: %s\n", path)
+		return 1
+	}
+	if err != nil {
+		fmt.Printf("❌ %s: %v\n", path, err)
+		return 1
+	}
 
-	switch choice {
-	case 1:
-		fmt.Println("✅ Thread count set to: 1")
-	case 2:
-		fmt.Println("✅ Thread count set to: 2")
-	case 3:
-		fmt.Println("✅ Thread count set to: 4")
-	case 4:
-		fmt.Println("✅ Thread count set to: 8")
-	case 5:
-		return
+	if file.Version == config.CurrentVersion {
+		fmt.Printf("✅ %s is already at schema version %d\n", path, config.CurrentVersion)
+		return 0
 	}
-	waitForEnter()
-}
 
-func setLogLevel() {
-	fmt.Println("\n📝 Log Level Settings")
-	fmt.Println(strings.Repeat("-", 30))
-	fmt.Println("1. Debug (All messages)")
-	fmt.Println("2. Info (Normal messages)")
-	fmt.Println("3. Warning (Warnings and errors)")
-	fmt.Println("4. Error (Errors only)")
-	fmt.Println("5. Back to Log Settings")
-	fmt.Print("Select option (1-5): ")
+	fromVersion := file.Version
+	changes, err := file.Migrate()
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return 1
+	}
+	if err := file.Save(path); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return 1
+	}
 
-	choice := getUserChoice(5)
+	fmt.Printf("🔄 Migrated %s from schema version %d to %d:\n", path, fromVersion, config.CurrentVersion)
+	for _, change := range changes {
+		fmt.Printf("  - %s\n", change)
+	}
+	fmt.Printf("💾 Original kept at %s.bak\n", path)
 
-	switch choice {
-	case 1:
-		fmt.Println("✅ Log level set to: Debug")
-	case 2:
-		fmt.Println("✅ Log level set to: Info")
-	case 3:
-		fmt.Println("✅ Log level set to: Warning")
-	case 4:
-		fmt.Println("✅ Log level set to: Error")
-	case 5:
-		return
+	// Point out anything the migration could not fix
+	issues := file.Validate()
+	if len(issues) > 0 {
+		fmt.Println()
+		printConfigIssues(issues)
 	}
-	waitForEnter()
+	if config.HasErrors(issues) {
+		return 1
+	}
+	return 0
 }
 
-func setLogRotation() {
-	fmt.Println("\n🔄 Log Rotation Settings")
-	fmt.Println(strings.Repeat("-", 30))
-	fmt.Println("1. Daily rotation")
-	fmt.Println("2. Weekly rotation")
-	fmt.Println("3. Monthly rotation")
-	fmt.Println("4. No rotation")
-	fmt.Println("5. Back to Log Settings")
-	fmt.Print("Select option (1-5): ")
-
-	choice := getUserChoice(5)
+// printConfigIssues prints validation issues, one per line with the fix underneath
+func printConfigIssues(issues []config.Issue) {
+	for _, issue := range issues {
+		icon := "❌"
+		if issue.Severity == config.SeverityWarning {
+			icon = "⚠️ "
+		}
+		fmt.Printf("%s %s: %s\n", icon, issue.Field, issue.Message)
+		fmt.Printf("   → %s\n", issue.Fix)
+	}
+}
 
-	switch choice {
-	case 1:
-		fmt.Println("✅ Log rotation set to: Daily")
-	case 2:
-		fmt.Println("✅ Log rotation set to: Weekly")
-	case 3:
-		fmt.Println("✅ Log rotation set to: Monthly")
-	case 4:
-		fmt.Println("❌ Log rotation disabled")
-	case 5:
+// loadConfigFile applies the config file to the monitors at startup
+// A missing file keeps the defaults; an invalid one is reported and ignored
+func loadConfigFile() {
+	path := config.Path()
+	file, err := config.Load(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			fmt.Printf("⚠️  Ignoring config file %s: %v\n", path, err)
+		} else if _, legacyErr := os.Stat(config.LegacyPath); legacyErr == nil && path != config.LegacyPath {
+			fmt.Printf("ℹ️  %s in the working directory is no longer read; move it to %s\n", config.LegacyPath, path)
+		}
 		return
 	}
-	waitForEnter()
-}
 
-func toggleLogging() {
-	fmt.Println("\n📝 Logging Settings")
-	fmt.Println(strings.Repeat("-", 30))
-	fmt.Println("1. Enable Logging")
-	fmt.Println("2. Disable Logging")
-	fmt.Println("3. Back to Log Settings")
-	fmt.Print("Select option (1-3): ")
+	// Old schemas are upgraded in memory; the file itself is only rewritten by `config migrate`
+	if file.Version < config.CurrentVersion {
+		if _, err := file.Migrate(); err == nil {
+			fmt.Printf("ℹ️  %s uses an old schema; run `simple-monitor config migrate` to upgrade it\n", path)
+		}
+	}
 
-	choice := getUserChoice(3)
+	issues := file.Validate()
+	if config.HasErrors(issues) {
+		fmt.Printf("⚠️  Ignoring config file %s:\n", path)
+		printConfigIssues(issues)
+		return
+	}
 
-	switch choice {
-	case 1:
-		fmt.Println("✅ Logging enabled")
-	case 2:
-		fmt.Println("❌ Logging disabled")
-	case 3:
+	if err := applyConfigFile(file); err != nil {
+		fmt.Printf("⚠️  Ignoring config file %s: %v\n", path, err)
 		return
 	}
-	waitForEnter()
+	fmt.Printf("⚙️  Loaded settings from %s\n", path)
 }
 
-func setLogDirectory() {
-	fmt.Println("\n📁 Log Directory Settings")
-	fmt.Println(strings.Repeat("-", 30))
-	fmt.Println("1. Default directory (logs/)")
-	fmt.Println("2. Custom directory")
-	fmt.Println("3. Back to Log Settings")
-	fmt.Print("Select option (1-3): ")
+// applyConfigFile copies each configured section into its monitor
+// Every section is decoded before any monitor is updated, so a bad file changes nothing
+func applyConfigFile(file *config.File) error {
+	cpuConfig := *cpuMonitorManager.GetConfiguration()
+	memoryConfig := *memoryMonitorManager.GetConfig()
+	diskConfig := *diskMonitorManager.GetConfig()
+	networkConfig := *networkMonitorManager.GetConfig()
+	processConfig := *processMonitorManager.GetConfig()
+	eventConfig := *eventMonitorManager.GetConfig()
+	alertConfig := alert.GetConfig()
+	retentionConfig := retention.GetConfig()
+	publishConfig := snapshot.GetPublishConfig()
+	quickTestConfig := quicktest.GetConfig()
+	scoreConfig := healthscore.GetConfig()
+	hookConfig := hooks.GetConfig()
+	streamConfig := stream.GetConfig()
+	redactConfig := redact.GetConfig()
+
+	// Maps are shared with the live configs, so they are copied before decoding into them
+	cpuConfig.AlertRules = maps.Clone(cpuConfig.AlertRules)
+	memoryConfig.AlertRules = maps.Clone(memoryConfig.AlertRules)
+	diskConfig.AlertRules = maps.Clone(diskConfig.AlertRules)
+	diskConfig.DeviceCapabilities = maps.Clone(diskConfig.DeviceCapabilities)
+	networkConfig.AlertRules = maps.Clone(networkConfig.AlertRules)
+	processConfig.Watchdog = maps.Clone(processConfig.Watchdog)
+
+	sections := map[string]interface{}{
+		"cpu":     &cpuConfig,
+		"memory":  &memoryConfig,
+		"disk":    &diskConfig,
+		"network": &networkConfig,
+		"process": &processConfig,
+		"events":  &eventConfig,
+		"alerts":    &alertConfig,
+		"retention": &retentionConfig,
+		"snapshot":  &publishConfig,
+		"quick_test": &quickTestConfig,
+		"score":     &scoreConfig,
+		"hooks":     &hookConfig,
+		"stream":    &streamConfig,
+		"redact":    &redactConfig,
+	}
+	for name, target := range sections {
+		if err := file.Apply(name, target); err != nil {
+			return err
+		}
+	}
 
-	choice := getUserChoice(3)
+	// Hook and restart commands run as this user, so they only come from a file this user owns
+	if !file.CommandsAllowed() {
+		previousHooks := hooks.GetConfig().Commands
+		previousWatchdog := processMonitorManager.GetConfig().Watchdog
+		ignored := !slices.Equal(hookConfig.Commands, previousHooks)
+		hookConfig.Commands = previousHooks
+		for name, rule := range processConfig.Watchdog {
+			if rule.RestartCommand != previousWatchdog[name].RestartCommand {
+				rule.RestartCommand = previousWatchdog[name].RestartCommand
+				processConfig.Watchdog[name] = rule
+				ignored = true
+			}
+		}
+		if ignored {
+			fmt.Printf("⚠️  Ignoring hook and restart commands in %s: it is not owned by you\n", file.Path)
+		}
+	}
+	if err := alert.SetConfig(alertConfig); err != nil {
+		return err
+	}
+	if err := retention.SetConfig(retentionConfig); err != nil {
+		return err
+	}
+	if err := snapshot.SetPublishConfig(publishConfig); err != nil {
+		return err
+	}
+	snapshotExporter.SetFormat(publishConfig.ExportFormat)
+	if err := quicktest.SetConfig(quickTestConfig); err != nil {
+		return err
+	}
+	if err := healthscore.SetConfig(scoreConfig); err != nil {
+		return err
+	}
+	if err := hooks.SetConfig(hookConfig); err != nil {
+		return err
+	}
+	if err := stream.SetConfig(streamConfig); err != nil {
+		return err
+	}
+	redact.SetConfig(redactConfig)
+
+	cpuMonitorManager.SetConfiguration(&cpuConfig)
+	memoryMonitorManager.UpdateConfig(&memoryConfig)
+	diskMonitorManager.UpdateConfig(&diskConfig)
+	networkMonitorManager.UpdateConfig(&networkConfig)
+	processMonitorManager.UpdateConfig(&processConfig)
+	eventMonitorManager.UpdateConfig(&eventConfig)
+	syncTitleMonitors()
+	return nil
+}
 
-	switch choice {
-	case 1:
-		fmt.Println("✅ Log directory set to: logs/")
-	case 2:
-		fmt.Print("Enter custom directory path: ")
-		scanner := bufio.NewScanner(os.Stdin)
-		scanner.Scan()
-		path := strings.TrimSpace(scanner.Text())
-		if path != "" {
-			fmt.Printf("✅ Log directory set to: %s\n", path)
-		} else {
-			fmt.Println("❌ Invalid path! Using default directory.")
+// removeFlag returns args without flag and whether the flag was present
+func removeFlag(args []string, flag string) ([]string, bool) {
+	var rest []string
+	found := false
+	for _, arg := range args {
+		if arg == flag {
+			found = true
+			continue
 		}
-	case 3:
+		rest = append(rest, arg)
+	}
+	return rest, found
+}
+
+// setSimulationMode switches every monitor between real and synthetic data
+func setSimulationMode(enabled bool) {
+	cpuMonitorManager.SetSimulationMode(enabled)
+	memoryMonitorManager.SetSimulationMode(enabled)
+	diskMonitorManager.SetSimulationMode(enabled)
+	networkMonitorManager.SetSimulationMode(enabled)
+	processMonitorManager.SetSimulationMode(enabled)
+}
+
+// connectHelper connects to the privileged helper when running unprivileged, so SMART data, ICMP latency
+// and other users' process details are still available; without it those fall back as before
+func connectHelper() {
+	if runtime.GOOS == "windows" || os.Geteuid() == 0 {
 		return
 	}
-	waitForEnter()
+	client, err := privhelper.Connect()
+	if err != nil {
+		return // The helper is optional; simple-monitor doctor explains how to install it
+	}
+	privhelper.Use(client)
+	fmt.Printf("🔐 Privileged helper connected (PID %d): SMART, ICMP and other users' processes available\n", client.Info().PID)
 }
 
 // waitForEnter waits for user to press Enter
@@ -1587,7 +5213,68 @@ func waitForEnter() {
 }
 
 func main() {
+	args, simulated := removeFlag(os.Args[1:], "--simulate")
+	if simulated {
+		setSimulationMode(true)
+	}
+	args, recordSessions = removeFlag(args, "--record")
+	args, viewer := removeFlag(args, "--viewer")
+
+	// Subcommands (e.g. `config validate`) run without the interactive menu
+	if len(args) > 0 {
+		os.Exit(runCommand(args))
+	}
+
+	// Plain text output for consoles that cannot render emoji
+	if terminal.ASCIIFromEnv() {
+		terminal.SetASCIIMode(true)
+	}
+	// Severity markers and a red-green safe palette
+	if terminal.ColorBlindFromEnv() {
+		terminal.SetColorBlindMode(true)
+	}
+
+	if viewer {
+		loadConfigFile()
+		os.Exit(runViewer())
+	}
+
+	// One instance per logs directory; a second one is pointed at viewer mode instead
+	lock, err := instance.Acquire(logsDir)
+	var held *instance.HeldError
+	if errors.As(err, &held) {
+		// A daemon serves its monitors to interactive sessions, so there is nothing to collect twice
+		if client, attachErr := attach.Dial(attach.SocketPath(logsDir)); attachErr == nil {
+			loadConfigFile()
+			os.Exit(runAttached(client))
+		}
+		fmt.Printf("🔒 Simple Monitor is already running: %v\n", err)
+		fmt.Println("   A second instance would interleave lines in append-mode exports and double the collection load.")
+		fmt.Println("   Watch the running instance read-only with: simple-monitor --viewer")
+		os.Exit(1)
+	}
+	if err != nil {
+		fmt.Printf("⚠️  Running without the instance lock: %v\n", err)
+	}
+	instanceLock = lock
+
 	fmt.Println("🚀 Simple Monitor started!")
+	loadConfigFile()
+	retention.Start(logsDir)
+	snapshot.StartPublishing(publishedMonitors)
+	if err := stream.Start(); err != nil {
+		fmt.Printf("⚠️  %v\n", err)
+	}
+	quicktest.Start(quickTestMonitors, nil)
+	if simulated {
+		fmt.Println("🧪 Simulation mode: all monitors show synthetic data")
+	}
+	if !simulated {
+		connectHelper()
+	}
+	if recordSessions {
+		fmt.Printf("🎬 Recording mode: live sessions are saved to %s for replay\n", recordingsDir)
+	}
 
 	for {
 		displayMainMenu()
@@ -1,61 +1,134 @@
 package memorymonitor
 
 import (
+	"bufio"
 	"fmt"
+	"github.com/ahmadreza-log/simple-monitor/alert"
+	"github.com/ahmadreza-log/simple-monitor/eventmonitor"
+	"github.com/ahmadreza-log/simple-monitor/historystore"
+	"github.com/ahmadreza-log/simple-monitor/listening"
+	"github.com/ahmadreza-log/simple-monitor/partial"
+	"github.com/ahmadreza-log/simple-monitor/procid"
+	"github.com/ahmadreza-log/simple-monitor/provider"
+	"github.com/ahmadreza-log/simple-monitor/simulate"
+	"os"
+	"regexp"
 	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
+)
+
+// trendGraphPoints is how many recent samples are kept with the data for the usage history graph
+const trendGraphPoints = 120
+
+// oomLogLines is the number of kernel log lines scanned for OOM killer events
+const oomLogLines = 5000
 
-	"github.com/shirou/gopsutil/v3/mem"
-	"github.com/shirou/gopsutil/v3/process"
+// Kernel log patterns used to reconstruct OOM killer events
+var (
+	oomKilledPattern     = regexp.MustCompile(`Killed process (\d+) \(([^)]*)\)(.*)`)
+	oomScorePattern      = regexp.MustCompile(`Kill process (\d+) \(([^)]*)\) score (\d+)`)
+	oomConstraintPattern = regexp.MustCompile(`oom-kill:constraint=([^,]+),.*pid=(\d+)`)
+	oomMemoryPattern     = regexp.MustCompile(`(total-vm|anon-rss|file-rss|shmem-rss):(\d+)kB`)
+	oomUIDPattern        = regexp.MustCompile(`UID:(\d+)`)
+	oomScoreAdjPattern   = regexp.MustCompile(`oom_score_adj:(-?\d+)`)
 )
 
 // MemoryMonitorCollector handles the collection of memory monitoring data
 // This struct provides methods to gather real-time memory metrics and process information
 type MemoryMonitorCollector struct {
-	// Configuration
+	// Configuration: config is what the collection in progress reads, adopted from latest as it starts
+	// latest is replaced on every change and never modified, so it can be handed out without copying
+	// collectMutex is held for a whole collection, so collections started by the live loop, subscribers and
+	// the attach server run one at a time and never see config or the state they carry change under them
 	config          *MemoryMonitorConfig
+	latest          *MemoryMonitorConfig
+	configMutex     sync.Mutex
+	collectMutex    sync.Mutex
 	lastMemoryUsage float64
 	lastTimestamp   time.Time
 
-	// Process tracking
-	processCache    map[int32]*MemoryProcessInfo
-	lastProcessTime map[int32]time.Time
-
 	// History tracking
 	history *MemoryUsageHistory
+
+	// Downsampled long-term history (raw, 1-minute and 5-minute tiers)
+	longHistory *historystore.Store
+
+	// Idle mode (expensive process scans are paused)
+	idle bool
+
+	// OOM killer tracking
+	oomKills     []OOMKillInfo
+	lastOOMCheck time.Time
+
+	// Synthetic data source used instead of the system (nil when collecting real data)
+	simulator *simulate.Source
+
+	// System data sources (gopsutil unless replaced with fakes)
+	system provider.Providers
+
+	// Alert levels carried between collections (hysteresis and minimum durations)
+	alerts *alert.Tracker
 }
 
 // NewMemoryMonitorCollector creates a new instance of MemoryMonitorCollector
 // with default configuration values
 func NewMemoryMonitorCollector() *MemoryMonitorCollector {
 	config := &MemoryMonitorConfig{
+		Enabled:             true,
 		RefreshInterval:     1 * time.Second,
 		MaxProcesses:        20,
 		MemoryWarning:       70.0,
 		MemoryCritical:      85.0,
 		SwapWarning:         50.0,
 		SwapCritical:        80.0,
+		CommitWarning:       80.0,
+		CommitCritical:      95.0,
 		ShowModules:         true,
 		ShowProcesses:       true,
 		ShowSwap:            true,
 		ShowCache:           true,
+		ShowCommit:          true,
 		ShowPerformance:     true,
+		ShowOOMKills:        true,
+		ShowPSS:             false,
+		ShowShared:          true,
+		ShowSlab:            true,
+		ShowPorts:           true,
+		ShowHistory:         true,
 		ExportToFile:        true,
 		ExportInterval:      1 * time.Hour,
 		ExportFormat:        "json",
+		PersistHistory:      true,
+		HistorySaveInterval: 1 * time.Minute,
+		HistorySize:         historystore.DefaultBufferSize,
+		HistoryResolution:   0,
 		MinMemoryUsage:      1.0,
 		ProcessNameFilter:   "",
 		MemoryLeakThreshold: 10.0,
+		MaxShmSegments:      10,
+		MaxSlabCaches:       10,
+		MaxOOMKills:         10,
+		OOMCheckInterval:    10 * time.Second,
+		AlertRules: map[string]alert.Rule{
+			"memory": {Hysteresis: 3},
+			"swap":   {Hysteresis: 5},
+			"commit": {Hysteresis: 3},
+		},
 	}
 
 	return &MemoryMonitorCollector{
 		config:          config,
+		latest:          config,
 		lastMemoryUsage: 0.0,
 		lastTimestamp:   time.Now(),
-		processCache:    make(map[int32]*MemoryProcessInfo),
-		lastProcessTime: make(map[int32]time.Time),
+		longHistory:     historystore.NewStore(historystore.DefaultTiers()),
+		alerts:          alert.NewTracker("memory"),
+		system:          provider.System(),
 		history: &MemoryUsageHistory{
-			MaxDataPoints:  100,
+			MaxDataPoints:  config.HistorySize,
 			DataPointCount: 0,
 		},
 	}
@@ -64,57 +137,81 @@ func NewMemoryMonitorCollector() *MemoryMonitorCollector {
 // CollectMemoryMonitorData gathers comprehensive memory monitoring data
 // This is the main method that collects all available memory metrics
 func (collector *MemoryMonitorCollector) CollectMemoryMonitorData() (*MemoryMonitorData, error) {
+	collector.collectMutex.Lock()
+	defer collector.collectMutex.Unlock()
+	collector.adoptConfig()
 	data := &MemoryMonitorData{
 		Timestamp:       time.Now(),
 		RefreshInterval: collector.config.RefreshInterval,
 		IsMonitoring:    true,
+		SectionErrors:   make(partial.Errors),
 	}
 
-	// Collect basic memory information
+	// Synthetic data for demos and UI work replaces every system query
+	if collector.simulator != nil {
+		collector.collectSimulatedData(data)
+		collector.analyzeMemoryStatus(data)
+		collector.updateHistory(data)
+		return data, nil
+	}
+
+	// Collect basic memory information (nothing useful can be shown without it)
+	// Other sections that fail are recorded and shown as unavailable instead of aborting the snapshot
 	if err := collector.collectBasicMemoryInfo(data); err != nil {
 		return nil, fmt.Errorf("failed to collect basic memory info: %w", err)
 	}
 
 	// Collect memory breakdown
-	if err := collector.collectMemoryBreakdown(data); err != nil {
-		return nil, fmt.Errorf("failed to collect memory breakdown: %w", err)
-	}
+	data.SectionErrors.Add("breakdown", collector.collectMemoryBreakdown(data))
 
 	// Collect performance metrics
 	if collector.config.ShowPerformance {
-		if err := collector.collectPerformanceMetrics(data); err != nil {
-			return nil, fmt.Errorf("failed to collect performance metrics: %w", err)
-		}
+		data.SectionErrors.Add("performance", collector.collectPerformanceMetrics(data))
 	}
 
 	// Collect memory modules information
 	if collector.config.ShowModules {
-		if err := collector.collectMemoryModules(data); err != nil {
-			return nil, fmt.Errorf("failed to collect memory modules: %w", err)
-		}
+		data.SectionErrors.Add("modules", collector.collectMemoryModules(data))
 	}
 
 	// Collect swap information
 	if collector.config.ShowSwap {
-		if err := collector.collectSwapInfo(data); err != nil {
-			return nil, fmt.Errorf("failed to collect swap info: %w", err)
-		}
+		data.SectionErrors.Add("swap", collector.collectSwapInfo(data))
 	}
 
 	// Collect cache information
 	if collector.config.ShowCache {
-		if err := collector.collectCacheInfo(data); err != nil {
-			return nil, fmt.Errorf("failed to collect cache info: %w", err)
-		}
+		data.SectionErrors.Add("cache", collector.collectCacheInfo(data))
+	}
+
+	// Collect commit charge
+	if collector.config.ShowCommit {
+		data.SectionErrors.Add("commit", collector.collectCommitInfo(data))
 	}
 
 	// Collect process information
-	if collector.config.ShowProcesses {
-		if err := collector.collectProcessInfo(data); err != nil {
-			return nil, fmt.Errorf("failed to collect process info: %w", err)
+	if collector.config.ShowProcesses && !collector.idle {
+		data.SectionErrors.Add("processes", collector.collectProcessInfo(data))
+		if collector.config.ShowPorts {
+			data.SectionErrors.Add("ports", collector.collectListeningPorts(data))
 		}
 	}
 
+	// Collect tmpfs mounts and shared memory segments
+	if collector.config.ShowShared && !collector.idle {
+		data.SectionErrors.Add("shared", collector.collectSharedMemory(data))
+	}
+
+	// Collect kernel slab caches
+	if collector.config.ShowSlab {
+		data.SectionErrors.Add("slab", collector.collectSlabInfo(data))
+	}
+
+	// Collect OOM killer history (kernel log may be unreadable without privileges)
+	if collector.config.ShowOOMKills {
+		collector.collectOOMKills(data)
+	}
+
 	// Analyze memory status and alerts
 	collector.analyzeMemoryStatus(data)
 
@@ -127,7 +224,7 @@ func (collector *MemoryMonitorCollector) CollectMemoryMonitorData() (*MemoryMoni
 // collectBasicMemoryInfo gathers basic memory information
 func (collector *MemoryMonitorCollector) collectBasicMemoryInfo(data *MemoryMonitorData) error {
 	// Get virtual memory information
-	vmem, err := mem.VirtualMemory()
+	vmem, err := collector.system.Mem.VirtualMemory()
 	if err != nil {
 		return fmt.Errorf("failed to get virtual memory info: %w", err)
 	}
@@ -137,6 +234,9 @@ func (collector *MemoryMonitorCollector) collectBasicMemoryInfo(data *MemoryMoni
 	data.UsedMemory = vmem.Used
 	data.FreeMemory = vmem.Free
 	data.MemoryPercent = vmem.UsedPercent
+	if vmem.Total > 0 {
+		data.AvailablePercent = float64(vmem.Available) / float64(vmem.Total) * 100
+	}
 
 	return nil
 }
@@ -144,7 +244,7 @@ func (collector *MemoryMonitorCollector) collectBasicMemoryInfo(data *MemoryMoni
 // collectMemoryBreakdown gathers detailed memory breakdown
 func (collector *MemoryMonitorCollector) collectMemoryBreakdown(data *MemoryMonitorData) error {
 	// Get detailed memory information
-	vmem, err := mem.VirtualMemory()
+	vmem, err := collector.system.Mem.VirtualMemory()
 	if err != nil {
 		return fmt.Errorf("failed to get virtual memory info: %w", err)
 	}
@@ -162,7 +262,7 @@ func (collector *MemoryMonitorCollector) collectMemoryBreakdown(data *MemoryMoni
 // collectPerformanceMetrics gathers memory performance metrics
 func (collector *MemoryMonitorCollector) collectPerformanceMetrics(data *MemoryMonitorData) error {
 	// Get memory statistics
-	vmem, err := mem.VirtualMemory()
+	vmem, err := collector.system.Mem.VirtualMemory()
 	if err != nil {
 		return fmt.Errorf("failed to get virtual memory info: %w", err)
 	}
@@ -187,7 +287,7 @@ func (collector *MemoryMonitorCollector) collectPerformanceMetrics(data *MemoryM
 func (collector *MemoryMonitorCollector) collectMemoryModules(data *MemoryMonitorData) error {
 	// For now, create a simplified memory module representation
 	// In a real implementation, this would query hardware-specific information
-	vmem, err := mem.VirtualMemory()
+	vmem, err := collector.system.Mem.VirtualMemory()
 	if err != nil {
 		return fmt.Errorf("failed to get virtual memory info: %w", err)
 	}
@@ -213,7 +313,7 @@ func (collector *MemoryMonitorCollector) collectMemoryModules(data *MemoryMonito
 // collectSwapInfo gathers swap memory information
 func (collector *MemoryMonitorCollector) collectSwapInfo(data *MemoryMonitorData) error {
 	// Get swap memory information
-	swap, err := mem.SwapMemory()
+	swap, err := collector.system.Mem.SwapMemory()
 	if err != nil {
 		return fmt.Errorf("failed to get swap memory info: %w", err)
 	}
@@ -231,21 +331,95 @@ func (collector *MemoryMonitorCollector) collectSwapInfo(data *MemoryMonitorData
 	return nil
 }
 
+// collectCommitInfo gathers the commit charge and overcommit settings
+func (collector *MemoryMonitorCollector) collectCommitInfo(data *MemoryMonitorData) error {
+	info, err := readCommitInfo(collector.system.Mem)
+	if err != nil {
+		return fmt.Errorf("failed to read commit charge: %w", err)
+	}
+	if info.CommitLimit > 0 {
+		info.CommitPercent = float64(info.Committed) / float64(info.CommitLimit) * 100
+	}
+	data.CommitInfo = info
+	return nil
+}
+
+// tmpfsTypes are the filesystems whose files live in memory
+var tmpfsTypes = map[string]bool{"tmpfs": true, "ramfs": true}
+
+// collectSharedMemory gathers tmpfs mount usage and the largest shared memory segments with the processes holding them
+// Both count as used (shared) memory but belong to no process's RSS once unmapped, so they are easy to miss
+func (collector *MemoryMonitorCollector) collectSharedMemory(data *MemoryMonitorData) error {
+	partitions, err := collector.system.Disk.Partitions(true)
+	if err != nil {
+		return fmt.Errorf("failed to list mounts: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	for _, partition := range partitions {
+		if !tmpfsTypes[partition.Fstype] || seen[partition.Mountpoint] {
+			continue
+		}
+		seen[partition.Mountpoint] = true
+
+		usage, err := collector.system.Disk.Usage(partition.Mountpoint)
+		if err != nil {
+			continue
+		}
+		data.TmpfsMounts = append(data.TmpfsMounts, TmpfsMountInfo{
+			Mountpoint:  partition.Mountpoint,
+			Fstype:      partition.Fstype,
+			Total:       usage.Total,
+			Used:        usage.Used,
+			UsedPercent: usage.UsedPercent,
+		})
+	}
+	sort.SliceStable(data.TmpfsMounts, func(i, j int) bool {
+		return data.TmpfsMounts[i].Used > data.TmpfsMounts[j].Used
+	})
+
+	segments, err := readShmSegments(collector.config.MaxShmSegments)
+	if err != nil {
+		return fmt.Errorf("failed to read shared memory segments: %w", err)
+	}
+	data.ShmSegments = segments
+	return nil
+}
+
+// collectSlabInfo gathers the reclaimable/unreclaimable slab split and the largest slab caches
+func (collector *MemoryMonitorCollector) collectSlabInfo(data *MemoryMonitorData) error {
+	vmem, err := collector.system.Mem.VirtualMemory()
+	if err != nil {
+		return fmt.Errorf("failed to get virtual memory info: %w", err)
+	}
+	data.SlabReclaimable = vmem.Sreclaimable
+	data.SlabUnreclaimable = vmem.Sunreclaim
+
+	caches, err := readSlabCaches(collector.config.MaxSlabCaches)
+	if err != nil {
+		return fmt.Errorf("failed to read slab caches: %w", err)
+	}
+	data.SlabCaches = caches
+	return nil
+}
+
 // collectCacheInfo gathers system cache information
 func (collector *MemoryMonitorCollector) collectCacheInfo(data *MemoryMonitorData) error {
 	// Get memory information for cache calculation
-	vmem, err := mem.VirtualMemory()
+	vmem, err := collector.system.Mem.VirtualMemory()
 	if err != nil {
 		return fmt.Errorf("failed to get virtual memory info: %w", err)
 	}
 
 	// Calculate cache information
+	// gopsutil counts reclaimable slab in Cached, so it is taken out again to not be counted twice
+	pageCache := vmem.Cached - vmem.Sreclaimable
 	data.CacheInfo = MemoryCacheInfo{
 		BufferCache:  vmem.Buffers,
-		PageCache:    vmem.Cached,
-		SlabCache:    vmem.Shared,
-		TotalCache:   vmem.Buffers + vmem.Cached + vmem.Shared,
-		CachePercent: (float64(vmem.Buffers+vmem.Cached+vmem.Shared) / float64(vmem.Total)) * 100,
+		PageCache:    pageCache,
+		SlabCache:    vmem.Slab,
+		TotalCache:   vmem.Buffers + pageCache + vmem.Slab,
+		CachePercent: (float64(vmem.Buffers+pageCache+vmem.Slab) / float64(vmem.Total)) * 100,
 	}
 
 	return nil
@@ -254,12 +428,13 @@ func (collector *MemoryMonitorCollector) collectCacheInfo(data *MemoryMonitorDat
 // collectProcessInfo gathers top memory-consuming processes
 func (collector *MemoryMonitorCollector) collectProcessInfo(data *MemoryMonitorData) error {
 	// Get all processes
-	processes, err := process.Processes()
+	processes, err := collector.system.Proc.Processes()
 	if err != nil {
 		return fmt.Errorf("failed to get processes: %w", err)
 	}
 
 	var memoryProcesses []MemoryProcessInfo
+	var swapProcesses []MemoryProcessInfo
 
 	// Collect memory information for each process
 	for _, p := range processes {
@@ -299,18 +474,14 @@ func (collector *MemoryMonitorCollector) collectProcessInfo(data *MemoryMonitorD
 			createTime = 0
 		}
 
-		// Filter by minimum memory usage
-		if float64(memPercent) < collector.config.MinMemoryUsage {
-			continue
-		}
-
 		// Filter by process name if specified
 		if collector.config.ProcessNameFilter != "" && name != collector.config.ProcessNameFilter {
 			continue
 		}
 
 		processInfo := MemoryProcessInfo{
-			PID:           p.Pid,
+			PID:           p.PID(),
+			InstanceID:    procid.InstanceID(p.PID(), createTime),
 			Name:          name,
 			MemoryUsage:   memInfo.RSS,
 			MemoryPercent: float64(memPercent),
@@ -319,6 +490,25 @@ func (collector *MemoryMonitorCollector) collectProcessInfo(data *MemoryMonitorD
 			Status:        status[0],
 			User:          user,
 			CreateTime:    createTime,
+			Swap:          memInfo.Swap,
+		}
+
+		// Swapped-out processes often have a small RSS, so track them before the usage filter
+		if processInfo.Swap > 0 {
+			swapProcesses = append(swapProcesses, processInfo)
+		}
+
+		// Filter by minimum memory usage
+		if float64(memPercent) < collector.config.MinMemoryUsage {
+			continue
+		}
+
+		// Get proportional and unique set sizes if enabled
+		if collector.config.ShowPSS {
+			if pss, uss, err := readSmapsRollup(p.PID()); err == nil {
+				processInfo.PSS = pss
+				processInfo.USS = uss
+			}
 		}
 
 		memoryProcesses = append(memoryProcesses, processInfo)
@@ -335,28 +525,210 @@ func (collector *MemoryMonitorCollector) collectProcessInfo(data *MemoryMonitorD
 	}
 
 	data.TopProcesses = memoryProcesses
+
+	// Sort swapped processes by swap usage
+	sort.Slice(swapProcesses, func(i, j int) bool {
+		return swapProcesses[i].Swap > swapProcesses[j].Swap
+	})
+	if len(swapProcesses) > collector.config.MaxProcesses {
+		swapProcesses = swapProcesses[:collector.config.MaxProcesses]
+	}
+	data.TopSwapProcesses = swapProcesses
+
 	return nil
 }
 
+// collectListeningPorts adds the ports each top memory process listens on
+func (collector *MemoryMonitorCollector) collectListeningPorts(data *MemoryMonitorData) error {
+	ports, err := listening.ByPID(collector.system.Net)
+	if err != nil {
+		return err
+	}
+	for i := range data.TopProcesses {
+		data.TopProcesses[i].ListeningPorts = listening.Strings(ports[data.TopProcesses[i].PID])
+	}
+	return nil
+}
+
+// collectOOMKills gathers recent OOM killer victims from the kernel log
+// The kernel log is only re-read every OOMCheckInterval since parsing it is expensive
+func (collector *MemoryMonitorCollector) collectOOMKills(data *MemoryMonitorData) {
+	if !collector.lastOOMCheck.IsZero() && time.Since(collector.lastOOMCheck) < collector.config.OOMCheckInterval {
+		data.OOMKills = collector.oomKills
+		return
+	}
+	collector.lastOOMCheck = time.Now()
+
+	entries, _, err := eventmonitor.ReadKernelLog(oomLogLines)
+	if err != nil {
+		data.OOMKills = collector.oomKills
+		return
+	}
+
+	// Victims stay listed after they scroll out of the lines read
+	collector.oomKills = mergeOOMKills(collector.oomKills, parseOOMKills(entries))
+
+	// Limit to max OOM kills
+	if len(collector.oomKills) > collector.config.MaxOOMKills {
+		collector.oomKills = collector.oomKills[:collector.config.MaxOOMKills]
+	}
+
+	data.OOMKills = collector.oomKills
+}
+
+// mergeOOMKills adds the newly parsed kills to the retained ones, newest first
+// A kill still in the log is parsed again on every read, so kills are matched on time and PID
+func mergeOOMKills(retained, parsed []OOMKillInfo) []OOMKillInfo {
+	type killKey struct {
+		timestamp time.Time
+		pid       int32
+	}
+	seen := make(map[killKey]bool, len(retained))
+	merged := append([]OOMKillInfo(nil), retained...)
+	for _, kill := range retained {
+		seen[killKey{kill.Timestamp, kill.PID}] = true
+	}
+	for _, kill := range parsed {
+		if !seen[killKey{kill.Timestamp, kill.PID}] {
+			seen[killKey{kill.Timestamp, kill.PID}] = true
+			merged = append(merged, kill)
+		}
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Timestamp.After(merged[j].Timestamp)
+	})
+	return merged
+}
+
+// parseOOMKills reconstructs OOM killer victims from kernel log entries, newest first
+// Score and constraint are logged on separate lines before the "Killed process" line
+func parseOOMKills(entries []eventmonitor.LogEntry) []OOMKillInfo {
+	var kills []OOMKillInfo
+	scores := make(map[int32]int)
+	constraints := make(map[int32]string)
+
+	for _, entry := range entries {
+		// Older kernels: "Out of memory: Kill process 1234 (name) score 900 or sacrifice child"
+		if match := oomScorePattern.FindStringSubmatch(entry.Message); match != nil {
+			pid, _ := strconv.Atoi(match[1])
+			scores[int32(pid)], _ = strconv.Atoi(match[3])
+			continue
+		}
+
+		// Newer kernels: "oom-kill:constraint=CONSTRAINT_NONE,...,task=name,pid=1234,uid=1000"
+		if match := oomConstraintPattern.FindStringSubmatch(entry.Message); match != nil {
+			pid, _ := strconv.Atoi(match[2])
+			constraints[int32(pid)] = match[1]
+			continue
+		}
+
+		// "Out of memory: Killed process 1234 (name) total-vm:...kB, anon-rss:...kB, ..."
+		match := oomKilledPattern.FindStringSubmatch(entry.Message)
+		if match == nil {
+			continue
+		}
+
+		pid, _ := strconv.Atoi(match[1])
+		kill := OOMKillInfo{
+			PID:        int32(pid),
+			Name:       match[2],
+			UID:        -1,
+			Score:      scores[int32(pid)],
+			Constraint: constraints[int32(pid)],
+			Timestamp:  entry.Timestamp,
+		}
+
+		details := match[3]
+		for _, memoryMatch := range oomMemoryPattern.FindAllStringSubmatch(details, -1) {
+			kilobytes, _ := strconv.ParseUint(memoryMatch[2], 10, 64)
+			if memoryMatch[1] == "total-vm" {
+				kill.TotalVM = kilobytes * 1024
+			} else {
+				kill.FreedMemory += kilobytes * 1024
+			}
+		}
+		if uidMatch := oomUIDPattern.FindStringSubmatch(details); uidMatch != nil {
+			kill.UID, _ = strconv.Atoi(uidMatch[1])
+		}
+		if adjMatch := oomScoreAdjPattern.FindStringSubmatch(details); adjMatch != nil {
+			kill.OOMScoreAdj, _ = strconv.Atoi(adjMatch[1])
+		}
+
+		kills = append(kills, kill)
+	}
+
+	// Sort newest first
+	sort.SliceStable(kills, func(i, j int) bool {
+		return kills[i].Timestamp.After(kills[j].Timestamp)
+	})
+
+	return kills
+}
+
+// readSmapsRollup reads PSS and USS for a process from /proc/<pid>/smaps_rollup
+// USS is the sum of private clean and private dirty pages
+func readSmapsRollup(pid int32) (uint64, uint64, error) {
+	file, err := os.Open(fmt.Sprintf("/proc/%d/smaps_rollup", pid))
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to open smaps_rollup: %w", err)
+	}
+	defer file.Close()
+
+	var pss, uss uint64
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		// Format: "Pss:                1234 kB"
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+
+		kilobytes, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		switch fields[0] {
+		case "Pss:":
+			pss = kilobytes * 1024
+		case "Private_Clean:", "Private_Dirty:":
+			uss += kilobytes * 1024
+		}
+	}
+
+	return pss, uss, scanner.Err()
+}
+
 // analyzeMemoryStatus analyzes memory status and sets alerts
 func (collector *MemoryMonitorCollector) analyzeMemoryStatus(data *MemoryMonitorData) {
+	rules := collector.config.AlertRules
+
 	// Analyze memory status
-	if data.MemoryPercent >= collector.config.MemoryCritical {
-		data.MemoryStatus = "Critical"
-		data.LowMemoryWarning = true
-	} else if data.MemoryPercent >= collector.config.MemoryWarning {
-		data.MemoryStatus = "Warning"
-		data.LowMemoryWarning = true
-	} else {
-		data.MemoryStatus = "Normal"
-		data.LowMemoryWarning = false
-	}
+	memoryLevel := collector.alerts.Evaluate("memory", data.MemoryPercent,
+		collector.config.MemoryWarning, collector.config.MemoryCritical, rules["memory"], data.Timestamp)
+	data.MemoryStatus = memoryLevel.String()
+	data.LowMemoryWarning = memoryLevel > alert.LevelNormal
 
 	// Analyze swap status
-	if data.SwapInfo.SwapPercent >= collector.config.SwapCritical {
+	swapLevel := collector.alerts.Evaluate("swap", data.SwapInfo.SwapPercent,
+		collector.config.SwapWarning, collector.config.SwapCritical, rules["swap"], data.Timestamp)
+	if data.SwapInfo.SwapStatus != "" {
+		data.SwapInfo.SwapStatus = swapLevel.String()
+	}
+	if swapLevel == alert.LevelCritical {
 		data.MemoryStatus = "Critical"
 	}
 
+	// Analyze commit charge; without a limit (not collected or unsupported) there is nothing to compare
+	if data.CommitInfo.CommitLimit > 0 {
+		commitLevel := collector.alerts.Evaluate("commit", data.CommitInfo.CommitPercent,
+			collector.config.CommitWarning, collector.config.CommitCritical, rules["commit"], data.Timestamp)
+		data.CommitInfo.CommitStatus = commitLevel.String()
+		if commitLevel == alert.LevelCritical {
+			data.MemoryStatus = "Critical"
+		}
+	}
+
 	// Check for potential memory leaks (simplified detection)
 	if collector.history.DataPointCount > 10 {
 		// Check if memory usage is continuously increasing
@@ -370,31 +742,54 @@ func (collector *MemoryMonitorCollector) analyzeMemoryStatus(data *MemoryMonitor
 		}
 		data.MemoryLeakAlert = isIncreasing
 	}
+
+	data.AlertNotes = collector.alerts.Notes()
 }
 
 // updateHistory updates the memory usage history
 func (collector *MemoryMonitorCollector) updateHistory(data *MemoryMonitorData) {
 	now := time.Now()
 
-	// Add new data point
-	collector.history.Timestamps = append(collector.history.Timestamps, now)
-	collector.history.TotalUsage = append(collector.history.TotalUsage, data.MemoryPercent)
-	collector.history.UserUsage = append(collector.history.UserUsage, (float64(data.UserMemory)/float64(data.TotalMemory))*100)
-	collector.history.SystemUsage = append(collector.history.SystemUsage, (float64(data.SystemMemory)/float64(data.TotalMemory))*100)
-	collector.history.CacheUsage = append(collector.history.CacheUsage, (float64(data.CacheMemory)/float64(data.TotalMemory))*100)
-	collector.history.SwapUsage = append(collector.history.SwapUsage, data.SwapInfo.SwapPercent)
+	// The in-memory history keeps HistorySize samples, at most one per HistoryResolution
+	collector.history.MaxDataPoints = collector.config.HistorySize
+	if historystore.BufferSampleDue(collector.history.Timestamps, now, collector.config.HistoryResolution) {
+		// Add new data point
+		collector.history.Timestamps = append(collector.history.Timestamps, now)
+		collector.history.TotalUsage = append(collector.history.TotalUsage, data.MemoryPercent)
+		collector.history.UserUsage = append(collector.history.UserUsage, (float64(data.UserMemory)/float64(data.TotalMemory))*100)
+		collector.history.SystemUsage = append(collector.history.SystemUsage, (float64(data.SystemMemory)/float64(data.TotalMemory))*100)
+		collector.history.CacheUsage = append(collector.history.CacheUsage, (float64(data.CacheMemory)/float64(data.TotalMemory))*100)
+		collector.history.SwapUsage = append(collector.history.SwapUsage, data.SwapInfo.SwapPercent)
+	}
 
 	// Limit history size
-	if len(collector.history.Timestamps) > collector.history.MaxDataPoints {
-		collector.history.Timestamps = collector.history.Timestamps[1:]
-		collector.history.TotalUsage = collector.history.TotalUsage[1:]
-		collector.history.UserUsage = collector.history.UserUsage[1:]
-		collector.history.SystemUsage = collector.history.SystemUsage[1:]
-		collector.history.CacheUsage = collector.history.CacheUsage[1:]
-		collector.history.SwapUsage = collector.history.SwapUsage[1:]
+	if excess := historystore.BufferExcess(len(collector.history.Timestamps), collector.history.MaxDataPoints); excess > 0 {
+		collector.history.Timestamps = collector.history.Timestamps[excess:]
+		collector.history.TotalUsage = collector.history.TotalUsage[excess:]
+		collector.history.UserUsage = collector.history.UserUsage[excess:]
+		collector.history.SystemUsage = collector.history.SystemUsage[excess:]
+		collector.history.CacheUsage = collector.history.CacheUsage[excess:]
+		collector.history.SwapUsage = collector.history.SwapUsage[excess:]
 	}
 
+	// Feed the downsampled long-term history
+	collector.longHistory.Add(now, map[string]float64{
+		"total_usage":  data.MemoryPercent,
+		"user_usage":   (float64(data.UserMemory) / float64(data.TotalMemory)) * 100,
+		"system_usage": (float64(data.SystemMemory) / float64(data.TotalMemory)) * 100,
+		"cache_usage":  (float64(data.CacheMemory) / float64(data.TotalMemory)) * 100,
+		"swap_usage":   data.SwapInfo.SwapPercent,
+	})
+
 	collector.history.DataPointCount = len(collector.history.Timestamps)
+
+	if collector.config.ShowHistory {
+		start := max(len(collector.history.Timestamps)-trendGraphPoints, 0)
+		data.Trend = &MemoryUsageTrend{
+			Timestamps: append([]time.Time(nil), collector.history.Timestamps[start:]...),
+			Usage:      append([]float64(nil), collector.history.TotalUsage[start:]...),
+		}
+	}
 }
 
 // getSwapStatus returns the status string for swap usage
@@ -407,17 +802,52 @@ func (collector *MemoryMonitorCollector) getSwapStatus(swapPercent float64) stri
 	return "Normal"
 }
 
-// GetMemoryUsageHistory returns the current memory usage history
+// GetMemoryUsageHistory returns a copy of the memory usage history
+// A collection in progress appends to the history, so it waits for that collection to finish
 func (collector *MemoryMonitorCollector) GetMemoryUsageHistory() *MemoryUsageHistory {
-	return collector.history
+	collector.collectMutex.Lock()
+	defer collector.collectMutex.Unlock()
+	return collector.history.Clone()
+}
+
+// GetLongTermHistory returns the downsampled long-term history
+func (collector *MemoryMonitorCollector) GetLongTermHistory() *historystore.Store {
+	return collector.longHistory
+}
+
+// HistoryMemoryEstimate estimates the memory an in-memory history of samples uses
+func (collector *MemoryMonitorCollector) HistoryMemoryEstimate(samples int) uint64 {
+	return historystore.BufferBytes(samples, 5)
+}
+
+// SetIdle pauses process scans while idle mode is active
+func (collector *MemoryMonitorCollector) SetIdle(idle bool) {
+	collector.collectMutex.Lock()
+	defer collector.collectMutex.Unlock()
+	collector.idle = idle
+}
+
+// SetSimulated switches between real system data and synthetic data
+func (collector *MemoryMonitorCollector) SetSimulated(enabled bool) {
+	collector.collectMutex.Lock()
+	defer collector.collectMutex.Unlock()
+	if !enabled {
+		collector.simulator = nil
+	} else if collector.simulator == nil {
+		collector.simulator = simulate.NewSource(simulate.DefaultSeed)
+	}
 }
 
-// GetConfig returns the current configuration
-func (collector *MemoryMonitorCollector) GetConfig() *MemoryMonitorConfig {
-	return collector.config
+// SetProviders replaces the system data sources, e.g. with provider.NewFake() to collect without OS access
+func (collector *MemoryMonitorCollector) SetProviders(providers provider.Providers) {
+	collector.collectMutex.Lock()
+	defer collector.collectMutex.Unlock()
+	collector.system = providers
 }
 
-// UpdateConfig updates the collector configuration
-func (collector *MemoryMonitorCollector) UpdateConfig(config *MemoryMonitorConfig) {
-	collector.config = config
+// IsSimulated returns whether the collector produces synthetic data
+func (collector *MemoryMonitorCollector) IsSimulated() bool {
+	collector.collectMutex.Lock()
+	defer collector.collectMutex.Unlock()
+	return collector.simulator != nil
 }
@@ -0,0 +1,157 @@
+package memorymonitor
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ahmadreza-log/simple-monitor/alert"
+	"github.com/ahmadreza-log/simple-monitor/provider"
+	"github.com/shirou/gopsutil/v3/mem"
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// newFakeCollector returns a collector reading fake, with alerts raised at once and only the sections
+// backed by the memory and process providers enabled
+func newFakeCollector(fake *provider.Fake) *MemoryMonitorCollector {
+	collector := NewMemoryMonitorCollector()
+	collector.SetProviders(fake.Providers())
+	config := collector.GetConfig()
+	config.ShowModules = false
+	config.ShowCommit = false
+	config.ShowPorts = false
+	config.ShowShared = false
+	config.ShowSlab = false
+	config.ShowOOMKills = false
+	config.ShowPSS = false
+	config.AlertRules = map[string]alert.Rule{}
+	collector.UpdateConfig(config)
+	return collector
+}
+
+func TestCollectMemoryMonitorDataThresholds(t *testing.T) {
+	tests := []struct {
+		name    string
+		percent float64
+		status  string
+		warning bool
+	}{
+		{name: "below warning", percent: 50, status: "Normal"},
+		{name: "above warning", percent: 75, status: "Warning", warning: true},
+		{name: "above critical", percent: 90, status: "Critical", warning: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := provider.NewFake()
+			fake.Mem.Virtual = &mem.VirtualMemoryStat{Total: 8 << 30, Available: 2 << 30, UsedPercent: tt.percent}
+			collector := newFakeCollector(fake)
+
+			data, err := collector.CollectMemoryMonitorData()
+			if err != nil {
+				t.Fatalf("CollectMemoryMonitorData() error = %v", err)
+			}
+			if data.MemoryStatus != tt.status || data.LowMemoryWarning != tt.warning {
+				t.Errorf("MemoryStatus = %q, LowMemoryWarning = %v, want %q, %v",
+					data.MemoryStatus, data.LowMemoryWarning, tt.status, tt.warning)
+			}
+			if data.AvailablePercent != 25 {
+				t.Errorf("AvailablePercent = %v, want 25", data.AvailablePercent)
+			}
+		})
+	}
+}
+
+func TestCollectMemoryMonitorDataSectionErrors(t *testing.T) {
+	fake := provider.NewFake()
+	fake.Mem.Virtual = &mem.VirtualMemoryStat{Total: 8 << 30, UsedPercent: 40}
+	fake.Mem.Faults["SwapMemory"] = errors.New("permission denied")
+	fake.Proc.Faults["Processes"] = errors.New("permission denied")
+	collector := newFakeCollector(fake)
+
+	data, err := collector.CollectMemoryMonitorData()
+	if err != nil {
+		t.Fatalf("CollectMemoryMonitorData() error = %v", err)
+	}
+	for _, section := range []string{"swap", "processes"} {
+		if _, failed := data.SectionErrors.Get(section); !failed {
+			t.Errorf("SectionErrors = %v, want a %s entry", data.SectionErrors, section)
+		}
+	}
+	if data.MemoryStatus != "Normal" {
+		t.Errorf("MemoryStatus = %q, want Normal despite the failed sections", data.MemoryStatus)
+	}
+}
+
+func TestCollectMemoryMonitorDataVirtualMemoryError(t *testing.T) {
+	fake := provider.NewFake()
+	fake.Mem.Faults["VirtualMemory"] = errors.New("not supported")
+	collector := newFakeCollector(fake)
+
+	if _, err := collector.CollectMemoryMonitorData(); err == nil {
+		t.Error("CollectMemoryMonitorData() error = nil, want the virtual memory error")
+	}
+}
+
+func TestCollectMemoryMonitorDataProcesses(t *testing.T) {
+	fake := provider.NewFake()
+	fake.Mem.Virtual = &mem.VirtualMemoryStat{Total: 8 << 30, UsedPercent: 40}
+	fake.Proc.Table = []*provider.FakeProcess{
+		{Pid: 10, ProcessName: "small", States: []string{"S"}, MemoryShare: 0.5, Memory: &process.MemoryInfoStat{RSS: 40 << 20}},
+		{Pid: 11, ProcessName: "large", States: []string{"S"}, MemoryShare: 20, Memory: &process.MemoryInfoStat{RSS: 1600 << 20}},
+		{Pid: 12, ProcessName: "medium", States: []string{"R"}, MemoryShare: 5, Memory: &process.MemoryInfoStat{RSS: 400 << 20}},
+		{Pid: 13, ProcessName: "swapped", States: []string{"S"}, MemoryShare: 0.1, Memory: &process.MemoryInfoStat{RSS: 8 << 20, Swap: 512 << 20}},
+	}
+	collector := newFakeCollector(fake)
+
+	data, err := collector.CollectMemoryMonitorData()
+	if err != nil {
+		t.Fatalf("CollectMemoryMonitorData() error = %v", err)
+	}
+
+	// Processes below MinMemoryUsage are left out; the rest are sorted by memory share
+	var names []string
+	for _, top := range data.TopProcesses {
+		names = append(names, top.Name)
+	}
+	if len(names) != 2 || names[0] != "large" || names[1] != "medium" {
+		t.Errorf("TopProcesses = %v, want [large medium]", names)
+	}
+
+	// A swapped-out process counts even though its resident share is below the filter
+	if len(data.TopSwapProcesses) != 1 || data.TopSwapProcesses[0].Name != "swapped" || data.TopSwapProcesses[0].Swap != 512<<20 {
+		t.Errorf("TopSwapProcesses = %+v, want only swapped with 512 MiB", data.TopSwapProcesses)
+	}
+}
+
+func TestCollectMemoryMonitorDataLeakAlert(t *testing.T) {
+	tests := []struct {
+		name  string
+		usage []float64
+		want  bool
+	}{
+		// Each collection checks the last 10 samples once more than 10 were recorded before it
+		{name: "too little history", usage: []float64{10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20}},
+		{name: "rising", usage: []float64{10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21}, want: true},
+		{name: "levelled off", usage: []float64{10, 11, 12, 13, 14, 15, 16, 17, 18, 18, 19, 20}},
+		{name: "rose earlier", usage: []float64{10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 21}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := provider.NewFake()
+			collector := newFakeCollector(fake)
+
+			var data *MemoryMonitorData
+			for _, usage := range tt.usage {
+				fake.Mem.Virtual = &mem.VirtualMemoryStat{Total: 8 << 30, Available: 4 << 30, UsedPercent: usage}
+				var err error
+				if data, err = collector.CollectMemoryMonitorData(); err != nil {
+					t.Fatalf("CollectMemoryMonitorData() error = %v", err)
+				}
+			}
+			if data.MemoryLeakAlert != tt.want {
+				t.Errorf("MemoryLeakAlert = %v, want %v", data.MemoryLeakAlert, tt.want)
+			}
+		})
+	}
+}
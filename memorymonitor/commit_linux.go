@@ -0,0 +1,42 @@
+//go:build linux
+
+package memorymonitor
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ahmadreza-log/simple-monitor/provider"
+)
+
+// readCommitInfo reads Committed_AS and CommitLimit from /proc/meminfo and the overcommit sysctls
+// The kernel only refuses allocations at the limit in strict mode (vm.overcommit_memory = 2)
+func readCommitInfo(source provider.MemProvider) (MemoryCommitInfo, error) {
+	vmem, err := source.VirtualMemory()
+	if err != nil {
+		return MemoryCommitInfo{}, err
+	}
+
+	info := MemoryCommitInfo{
+		Committed:       vmem.CommittedAS,
+		CommitLimit:     vmem.CommitLimit,
+		OvercommitMode:  readSysctl("/proc/sys/vm/overcommit_memory", -1),
+		OvercommitRatio: readSysctl("/proc/sys/vm/overcommit_ratio", 0),
+	}
+	info.LimitEnforced = info.OvercommitMode == 2
+	return info, nil
+}
+
+// readSysctl reads an integer sysctl, returning fallback when it cannot be read
+func readSysctl(path string, fallback int) int {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fallback
+	}
+	value, err := strconv.Atoi(strings.TrimSpace(string(content)))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,15 @@
+//go:build !linux && !windows
+
+package memorymonitor
+
+import (
+	"errors"
+
+	"github.com/ahmadreza-log/simple-monitor/provider"
+)
+
+// readCommitInfo reports the commit charge
+// macOS and the BSDs do not expose a commit limit, so there is nothing to compare against
+func readCommitInfo(source provider.MemProvider) (MemoryCommitInfo, error) {
+	return MemoryCommitInfo{}, errors.New("commit charge is only available on Linux and Windows")
+}
@@ -0,0 +1,21 @@
+//go:build windows
+
+package memorymonitor
+
+import "github.com/ahmadreza-log/simple-monitor/provider"
+
+// readCommitInfo reads the commit total and limit (RAM plus page files)
+// gopsutil reports them as swap on Windows; Windows never overcommits, so allocations fail at the limit
+func readCommitInfo(source provider.MemProvider) (MemoryCommitInfo, error) {
+	commit, err := source.SwapMemory()
+	if err != nil {
+		return MemoryCommitInfo{}, err
+	}
+
+	return MemoryCommitInfo{
+		Committed:      commit.Used,
+		CommitLimit:    commit.Total,
+		OvercommitMode: -1,
+		LimitEnforced:  true,
+	}, nil
+}
@@ -0,0 +1,47 @@
+package memorymonitor
+
+import "github.com/ahmadreza-log/simple-monitor/alert"
+
+// Clone returns a deep copy of the configuration
+func (config *MemoryMonitorConfig) Clone() *MemoryMonitorConfig {
+	clone := *config
+	clone.AlertRules = alert.CloneRules(config.AlertRules)
+	return &clone
+}
+
+// UpdateConfig replaces the configuration with a copy of config; the next collection uses it
+func (collector *MemoryMonitorCollector) UpdateConfig(config *MemoryMonitorConfig) {
+	collector.configMutex.Lock()
+	defer collector.configMutex.Unlock()
+	collector.latest = config.Clone()
+}
+
+// GetConfig returns a copy of the current configuration
+// Changing the copy has no effect until it is passed to UpdateConfig
+func (collector *MemoryMonitorCollector) GetConfig() *MemoryMonitorConfig {
+	return collector.currentConfig().Clone()
+}
+
+// currentConfig returns the latest configuration without copying it, for reads outside a collection
+// It is shared with the collection, so it must not be modified
+func (collector *MemoryMonitorCollector) currentConfig() *MemoryMonitorConfig {
+	collector.configMutex.Lock()
+	defer collector.configMutex.Unlock()
+	return collector.latest
+}
+
+// changeConfig applies change to a copy of the current configuration and makes the copy current
+// Configurations are replaced instead of changed in place, so a collection in progress keeps a consistent one
+func (collector *MemoryMonitorCollector) changeConfig(change func(config *MemoryMonitorConfig)) {
+	collector.configMutex.Lock()
+	defer collector.configMutex.Unlock()
+	config := collector.latest.Clone()
+	change(config)
+	collector.latest = config
+}
+
+// adoptConfig makes the latest configuration the one the starting collection reads
+// It must be called with collectMutex held, which keeps config unchanged until the collection ends
+func (collector *MemoryMonitorCollector) adoptConfig() {
+	collector.config = collector.currentConfig()
+}
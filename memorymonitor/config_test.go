@@ -0,0 +1,80 @@
+package memorymonitor
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ahmadreza-log/simple-monitor/monitortest"
+	"github.com/ahmadreza-log/simple-monitor/provider"
+	"github.com/shirou/gopsutil/v3/mem"
+)
+
+// collect returns a function running one collection of collector, for monitortest
+func collect(collector *MemoryMonitorCollector) func() error {
+	return func() error {
+		_, err := collector.CollectMemoryMonitorData()
+		return err
+	}
+}
+
+// TestConfigUpdatesDuringCollections changes the configuration while several collections run, the way the
+// settings menu does while the live loop, a subscriber and the attach server collect; run it with -race
+func TestConfigUpdatesDuringCollections(t *testing.T) {
+	fake := provider.NewFake()
+	fake.Mem.Virtual = &mem.VirtualMemoryStat{Total: 8 << 30, UsedPercent: 40}
+	collector := newFakeCollector(fake)
+
+	monitortest.DuringCollections(t, collect(collector), func(i int) {
+		if i%2 == 0 {
+			config := collector.GetConfig()
+			config.RefreshInterval = time.Duration(i) * time.Second
+			collector.UpdateConfig(config)
+		} else {
+			collector.changeConfig(func(config *MemoryMonitorConfig) {
+				config.RefreshInterval = time.Duration(i) * time.Second
+			})
+		}
+	})
+
+	data, err := collector.CollectMemoryMonitorData()
+	if err != nil {
+		t.Fatalf("CollectMemoryMonitorData() error = %v", err)
+	}
+	if data.RefreshInterval != monitortest.Rounds*time.Second {
+		t.Errorf("RefreshInterval = %v, want the last update (%ds)", data.RefreshInterval, monitortest.Rounds)
+	}
+}
+
+// TestHistoryAndSettersDuringCollections reads the history and switches idle mode, simulated data and providers
+// while several collections run, the way the managers do; run it with -race
+func TestHistoryAndSettersDuringCollections(t *testing.T) {
+	fake := provider.NewFake()
+	fake.Mem.Virtual = &mem.VirtualMemoryStat{Total: 8 << 30, UsedPercent: 40}
+	collector := newFakeCollector(fake)
+
+	monitortest.DuringCollections(t, collect(collector), func(i int) {
+		history := collector.GetMemoryUsageHistory()
+		for j := range history.TotalUsage {
+			history.TotalUsage[j] = -1
+		}
+		collector.SetIdle(i%2 == 0)
+		collector.SetSimulated(i%4 == 0 && i < monitortest.Rounds)
+		collector.SetProviders(fake.Providers())
+	})
+
+	if collector.IsSimulated() {
+		t.Errorf("IsSimulated() = true after the last SetSimulated(false)")
+	}
+	if _, err := collector.CollectMemoryMonitorData(); err != nil {
+		t.Fatalf("CollectMemoryMonitorData() error = %v", err)
+	}
+	history := collector.GetMemoryUsageHistory()
+	if len(history.TotalUsage) == 0 {
+		t.Fatal("GetMemoryUsageHistory() is empty after a collection")
+	}
+	history.TotalUsage[0] = -1
+	if slices.Contains(collector.GetMemoryUsageHistory().TotalUsage, -1) {
+		t.Errorf("GetMemoryUsageHistory().TotalUsage = %v, want changes to the returned copy kept out of the collector", collector.GetMemoryUsageHistory().TotalUsage)
+	}
+}
@@ -2,6 +2,10 @@ package memorymonitor
 
 import (
 	"fmt"
+	"github.com/ahmadreza-log/simple-monitor/listening"
+	"github.com/ahmadreza-log/simple-monitor/overlay"
+	"github.com/ahmadreza-log/simple-monitor/partial"
+	"github.com/ahmadreza-log/simple-monitor/terminal"
 	"strings"
 )
 
@@ -11,9 +15,13 @@ type MemoryMonitorDisplayer struct {
 	// Display configuration
 	ShowGraphics bool // Whether to show graphical elements
 	ShowColors   bool // Whether to use colored output
-	BarWidth     int  // Width of progress bars
+	BarWidth     int  // Width of progress bars (when AutoWidth is off)
+	AutoWidth    bool // Whether to fit bars, separators and columns to the terminal width
 	MaxProcesses int  // Maximum number of processes to display
 
+	// Imported series drawn under the history graph (nil when none is imported)
+	Overlay *overlay.Series
+
 	// Color codes for different elements
 	ColorReset   string
 	ColorRed     string
@@ -33,6 +41,7 @@ func NewMemoryMonitorDisplayer() *MemoryMonitorDisplayer {
 		ShowGraphics: true,
 		ShowColors:   true,
 		BarWidth:     50,
+		AutoWidth:    true,
 		MaxProcesses: 10,
 		ColorReset:   "\033[0m",
 		ColorRed:     "\033[31m",
@@ -57,33 +66,90 @@ func (displayer *MemoryMonitorDisplayer) DisplayMemoryMonitorData(data *MemoryMo
 	// Display overall memory usage with graphics
 	displayer.displayOverallMemoryUsage(data)
 
+	// Display recent usage with the imported overlay series under it
+	if displayer.ShowGraphics && data.Trend != nil && len(data.Trend.Usage) > 1 {
+		displayer.displayHistory(data.Trend)
+	}
+
+	// Explain available vs free
+	displayer.displayAvailableVsFree(data)
+
+	// Display commit charge
+	if reason, failed := data.SectionErrors.Get("commit"); failed {
+		displayer.displayUnavailable("📜 COMMIT CHARGE", reason)
+	} else if data.CommitInfo.CommitLimit > 0 {
+		displayer.displayCommitInfo(data)
+	}
+
 	// Display memory breakdown
-	displayer.displayMemoryBreakdown(data)
+	if reason, failed := data.SectionErrors.Get("breakdown"); failed {
+		displayer.displayUnavailable("🔧 MEMORY BREAKDOWN", reason)
+	} else {
+		displayer.displayMemoryBreakdown(data)
+	}
 
 	// Display memory modules
-	if len(data.MemoryModules) > 0 {
+	if reason, failed := data.SectionErrors.Get("modules"); failed {
+		displayer.displayUnavailable("🔧 MEMORY MODULES", reason)
+	} else if len(data.MemoryModules) > 0 {
 		displayer.displayMemoryModules(data)
 	}
 
 	// Display swap information
-	if data.SwapInfo.TotalSwap > 0 {
+	if reason, failed := data.SectionErrors.Get("swap"); failed {
+		displayer.displayUnavailable("🔄 SWAP MEMORY", reason)
+	} else if data.SwapInfo.TotalSwap > 0 {
 		displayer.displaySwapInfo(data)
 	}
 
 	// Display cache information
-	displayer.displayCacheInfo(data)
+	if reason, failed := data.SectionErrors.Get("cache"); failed {
+		displayer.displayUnavailable("💾 CACHE INFORMATION", reason)
+	} else {
+		displayer.displayCacheInfo(data)
+	}
 
 	// Display performance metrics
-	displayer.displayPerformanceMetrics(data)
+	if reason, failed := data.SectionErrors.Get("performance"); failed {
+		displayer.displayUnavailable("⚡ PERFORMANCE METRICS", reason)
+	} else {
+		displayer.displayPerformanceMetrics(data)
+	}
 
 	// Display top processes
-	if len(data.TopProcesses) > 0 {
+	if reason, failed := data.SectionErrors.Get("processes"); failed {
+		displayer.displayUnavailable("🔥 TOP MEMORY PROCESSES", reason)
+	} else if len(data.TopProcesses) > 0 {
 		displayer.displayTopProcesses(data)
 	}
 
+	// Display tmpfs mounts and shared memory segments
+	if reason, failed := data.SectionErrors.Get("shared"); failed {
+		displayer.displayUnavailable("🗂️  TMPFS AND SHARED MEMORY", reason)
+	} else if len(data.TmpfsMounts) > 0 || len(data.ShmSegments) > 0 {
+		displayer.displaySharedMemory(data)
+	}
+
+	// Display kernel slab caches
+	if reason, failed := data.SectionErrors.Get("slab"); failed {
+		displayer.displayUnavailable("🧱 KERNEL SLAB", reason)
+	} else if len(data.SlabCaches) > 0 {
+		displayer.displaySlabCaches(data)
+	}
+
+	// Display OOM killer history
+	if len(data.OOMKills) > 0 {
+		displayer.displayOOMKills(data)
+	}
+
 	// Display memory status and alerts
 	displayer.displayMemoryStatus(data)
 
+	// Display runbook notes of raised alerts
+	if len(data.AlertNotes) > 0 {
+		displayer.displayAlertNotes(data)
+	}
+
 	// Display footer
 	displayer.displayFooter(data)
 }
@@ -91,7 +157,12 @@ func (displayer *MemoryMonitorDisplayer) DisplayMemoryMonitorData(data *MemoryMo
 // displayHeader displays the memory monitor header
 func (displayer *MemoryMonitorDisplayer) displayHeader(data *MemoryMonitorData) {
 	fmt.Println(displayer.colorize("💾 MEMORY MONITOR", displayer.ColorBold+displayer.ColorCyan))
-	fmt.Println(strings.Repeat("=", 80))
+	fmt.Println(displayer.rule("="))
+
+	// Synthetic data must never be mistaken for a real system
+	if data.Simulated {
+		fmt.Println(displayer.colorize("🧪 Simulated data (--simulate)", displayer.ColorMagenta))
+	}
 
 	// Memory summary
 	fmt.Printf("%sTotal Memory: %s%s\n",
@@ -99,9 +170,10 @@ func (displayer *MemoryMonitorDisplayer) displayHeader(data *MemoryMonitorData)
 		displayer.colorize(displayer.formatBytes(data.TotalMemory), displayer.ColorWhite),
 		displayer.colorize("", displayer.ColorReset))
 
-	fmt.Printf("%sAvailable: %s%s\n",
+	fmt.Printf("%sAvailable: %s (%.1f%% of total)%s\n",
 		displayer.colorize("", displayer.ColorBold),
-		displayer.colorize(displayer.formatBytes(data.AvailableMemory), displayer.ColorGreen),
+		displayer.colorize(displayer.formatBytes(data.AvailableMemory), displayer.getAvailableColor(data.AvailablePercent)),
+		data.AvailablePercent,
 		displayer.colorize("", displayer.ColorReset))
 
 	fmt.Printf("%sUsed: %s%s\n",
@@ -114,13 +186,13 @@ func (displayer *MemoryMonitorDisplayer) displayHeader(data *MemoryMonitorData)
 		displayer.colorize(displayer.formatBytes(data.FreeMemory), displayer.ColorBlue),
 		displayer.colorize("", displayer.ColorReset))
 
-	fmt.Println(strings.Repeat("=", 80))
+	fmt.Println(displayer.rule("="))
 }
 
 // displayOverallMemoryUsage displays overall memory usage with graphical bars
 func (displayer *MemoryMonitorDisplayer) displayOverallMemoryUsage(data *MemoryMonitorData) {
 	fmt.Println("\n📊 OVERALL MEMORY USAGE")
-	fmt.Println(strings.Repeat("-", 50))
+	fmt.Println(displayer.sectionRule())
 
 	// Overall usage bar
 	displayer.displayUsageBar("Memory Usage", data.MemoryPercent, displayer.getMemoryUsageColor(data.MemoryPercent))
@@ -139,10 +211,86 @@ func (displayer *MemoryMonitorDisplayer) displayOverallMemoryUsage(data *MemoryM
 	}
 }
 
+// displayHistory displays a graph of recent memory usage and, on the same time axis under it, the imported
+// overlay series
+func (displayer *MemoryMonitorDisplayer) displayHistory(trend *MemoryUsageTrend) {
+	fmt.Println("\n📉 HISTORY")
+	fmt.Println(displayer.sectionRule())
+
+	width := terminal.Fit(displayer.lineWidth()-22, 10, len(trend.Usage))
+	fmt.Printf("%-8s %s 100%%\n", "Memory", displayer.colorize(overlay.Sparkline(trend.Usage, 100, width), displayer.ColorCyan))
+	if series := displayer.Overlay; series != nil {
+		label := terminal.Truncate(series.Name, 8)
+		if line, peak, ok := series.Line(trend.Timestamps, width); ok {
+			fmt.Printf("%-8s %s %s max\n", label, displayer.colorize(line, displayer.ColorMagenta), overlay.Format(peak))
+		} else {
+			fmt.Printf("%-8s %s\n", label, displayer.colorize(fmt.Sprintf("no samples in this range (imported %s to %s)",
+				series.Start().Format("2006-01-02 15:04"), series.End().Format("2006-01-02 15:04")), displayer.ColorWhite))
+		}
+	}
+	start := trend.Timestamps[max(len(trend.Timestamps)-width, 0)]
+	fmt.Printf("%-8s %s to %s\n", "", start.Format("15:04:05"), trend.Timestamps[len(trend.Timestamps)-1].Format("15:04:05"))
+}
+
+// displayAvailableVsFree shows why available, not free, is the health figure
+// Free RAM is memory nothing uses at all; the kernel keeps it low on purpose by caching files,
+// and that cache is handed back the moment programs need it
+func (displayer *MemoryMonitorDisplayer) displayAvailableVsFree(data *MemoryMonitorData) {
+	fmt.Println("\n🧮 AVAILABLE VS FREE")
+	fmt.Println(displayer.sectionRule())
+
+	displayer.displayUsageBar("Available", data.AvailablePercent, displayer.getAvailableColor(data.AvailablePercent))
+
+	var reclaimable uint64
+	if data.AvailableMemory > data.FreeMemory {
+		reclaimable = data.AvailableMemory - data.FreeMemory
+	}
+	fmt.Printf("  %-18s %s\n", "Free (unused)", displayer.formatBytes(data.FreeMemory))
+	fmt.Printf("  %-18s %s\n", "+ Reclaimable", displayer.formatBytes(reclaimable))
+	fmt.Printf("  %-18s %s\n", "= Available", displayer.colorize(displayer.formatBytes(data.AvailableMemory), displayer.ColorBold))
+	fmt.Println(displayer.colorize("  Low free memory is normal: cache is reclaimed on demand. Watch available and commit charge instead.", displayer.ColorCyan))
+}
+
+// displayCommitInfo displays the commit charge against the commit limit and the overcommit policy
+func (displayer *MemoryMonitorDisplayer) displayCommitInfo(data *MemoryMonitorData) {
+	info := data.CommitInfo
+	fmt.Println("\n📜 COMMIT CHARGE")
+	fmt.Println(displayer.sectionRule())
+
+	displayer.displayUsageBar("Committed", info.CommitPercent, displayer.getMemoryStatusColor(info.CommitStatus))
+	fmt.Printf("  %-18s %s of %s\n", "Committed / Limit", displayer.formatBytes(info.Committed), displayer.formatBytes(info.CommitLimit))
+	if info.OvercommitMode >= 0 {
+		fmt.Printf("  %-18s %s\n", "Overcommit", overcommitDescription(info))
+	}
+
+	// The risk depends on whether the limit is enforced
+	if info.CommitStatus == "Warning" || info.CommitStatus == "Critical" {
+		if info.LimitEnforced {
+			fmt.Println(displayer.colorize(fmt.Sprintf("  ⚠️  Commit charge is %s: new allocations fail once the limit is reached, even with RAM free", strings.ToLower(info.CommitStatus)), displayer.ColorRed))
+		} else {
+			fmt.Println(displayer.colorize(fmt.Sprintf("  ⚠️  Commit charge is %s: the kernel overcommits, so touching that memory can trigger the OOM killer", strings.ToLower(info.CommitStatus)), displayer.ColorYellow))
+		}
+	}
+}
+
+// overcommitDescription names the vm.overcommit_memory policy
+func overcommitDescription(info MemoryCommitInfo) string {
+	switch info.OvercommitMode {
+	case 0:
+		return "heuristic (0): obvious overcommits are refused, the limit is advisory"
+	case 1:
+		return "always (1): every allocation succeeds, the limit is advisory"
+	case 2:
+		return fmt.Sprintf("strict (2): limit is swap + %d%% of RAM and is enforced", info.OvercommitRatio)
+	default:
+		return fmt.Sprintf("unknown (%d)", info.OvercommitMode)
+	}
+}
+
 // displayMemoryBreakdown displays detailed memory breakdown
 func (displayer *MemoryMonitorDisplayer) displayMemoryBreakdown(data *MemoryMonitorData) {
 	fmt.Println("\n🔧 MEMORY BREAKDOWN")
-	fmt.Println(strings.Repeat("-", 50))
+	fmt.Println(displayer.sectionRule())
 
 	// User memory
 	userPercent := (float64(data.UserMemory) / float64(data.TotalMemory)) * 100
@@ -168,7 +316,7 @@ func (displayer *MemoryMonitorDisplayer) displayMemoryBreakdown(data *MemoryMoni
 // displayMemoryModules displays memory modules information
 func (displayer *MemoryMonitorDisplayer) displayMemoryModules(data *MemoryMonitorData) {
 	fmt.Println("\n🔧 MEMORY MODULES")
-	fmt.Println(strings.Repeat("-", 50))
+	fmt.Println(displayer.sectionRule())
 
 	for i, module := range data.MemoryModules {
 		fmt.Printf("\n%sModule %d: %s%s\n",
@@ -208,7 +356,7 @@ func (displayer *MemoryMonitorDisplayer) displayMemoryModules(data *MemoryMonito
 // displaySwapInfo displays swap memory information
 func (displayer *MemoryMonitorDisplayer) displaySwapInfo(data *MemoryMonitorData) {
 	fmt.Println("\n🔄 SWAP MEMORY")
-	fmt.Println(strings.Repeat("-", 50))
+	fmt.Println(displayer.sectionRule())
 
 	swapInfo := data.SwapInfo
 
@@ -252,12 +400,38 @@ func (displayer *MemoryMonitorDisplayer) displaySwapInfo(data *MemoryMonitorData
 			swapInfo.SwapOut,
 			displayer.colorize("", displayer.ColorReset))
 	}
+
+	// Processes with memory swapped out
+	if len(data.TopSwapProcesses) > 0 {
+		fmt.Printf("\n%sTop Swapped Processes:%s\n",
+			displayer.colorize("", displayer.ColorBold),
+			displayer.colorize("", displayer.ColorReset))
+
+		for i, process := range data.TopSwapProcesses {
+			if i >= displayer.MaxProcesses {
+				break
+			}
+
+			// Truncate long process names
+			name := process.Name
+			if len(name) > 20 {
+				name = name[:17] + "..."
+			}
+
+			fmt.Printf("  %-8d %-20s %s%s%s\n",
+				process.PID,
+				name,
+				displayer.getProcessSwapColor(process.Swap),
+				displayer.formatBytes(process.Swap),
+				displayer.colorize("", displayer.ColorReset))
+		}
+	}
 }
 
 // displayCacheInfo displays system cache information
 func (displayer *MemoryMonitorDisplayer) displayCacheInfo(data *MemoryMonitorData) {
 	fmt.Println("\n💾 CACHE INFORMATION")
-	fmt.Println(strings.Repeat("-", 50))
+	fmt.Println(displayer.sectionRule())
 
 	cacheInfo := data.CacheInfo
 
@@ -288,7 +462,7 @@ func (displayer *MemoryMonitorDisplayer) displayCacheInfo(data *MemoryMonitorDat
 // displayPerformanceMetrics displays memory performance metrics
 func (displayer *MemoryMonitorDisplayer) displayPerformanceMetrics(data *MemoryMonitorData) {
 	fmt.Println("\n⚡ PERFORMANCE METRICS")
-	fmt.Println(strings.Repeat("-", 50))
+	fmt.Println(displayer.sectionRule())
 
 	// Memory fragmentation
 	displayer.displayUsageBar("Memory Fragmentation", data.MemoryFragmentation, displayer.getFragmentationColor(data.MemoryFragmentation))
@@ -318,20 +492,34 @@ func (displayer *MemoryMonitorDisplayer) displayPerformanceMetrics(data *MemoryM
 // displayTopProcesses displays top memory-consuming processes
 func (displayer *MemoryMonitorDisplayer) displayTopProcesses(data *MemoryMonitorData) {
 	fmt.Println("\n🔥 TOP MEMORY PROCESSES")
-	fmt.Println(strings.Repeat("-", 80))
+	fmt.Println(displayer.rule("-"))
+
+	// PSS/USS columns are only shown when they were collected, and ports when a process listens on any
+	showPSS, showPorts := false, false
+	for _, process := range data.TopProcesses {
+		showPSS = showPSS || process.PSS > 0
+		showPorts = showPorts || len(process.ListeningPorts) > 0
+	}
 
 	// Header
-	fmt.Printf("%s%-8s %-20s %-12s %-8s %-10s %-8s%s\n",
+	fmt.Printf("%s%-8s %-20s %-12s %-8s %-10s %-10s %-8s",
 		displayer.colorize("", displayer.ColorBold),
 		"PID",
 		"Name",
 		"Memory",
 		"Percent",
 		"RSS",
-		"Status",
-		displayer.colorize("", displayer.ColorReset))
+		"Swap",
+		"Status")
+	if showPSS {
+		fmt.Printf(" %-10s %-10s", "PSS", "USS")
+	}
+	if showPorts {
+		fmt.Printf(" %s", "Ports")
+	}
+	fmt.Printf("%s\n", displayer.colorize("", displayer.ColorReset))
 
-	fmt.Println(strings.Repeat("-", 80))
+	fmt.Println(displayer.rule("-"))
 
 	// Display processes
 	for i, process := range data.TopProcesses {
@@ -349,7 +537,7 @@ func (displayer *MemoryMonitorDisplayer) displayTopProcesses(data *MemoryMonitor
 		memColor := displayer.getMemoryUsageColor(process.MemoryPercent)
 		statusColor := displayer.getProcessStatusColor(process.Status)
 
-		fmt.Printf("%s%-8d %-20s %s%-12s %s%-8.2f %s%-10s %s%-8s%s\n",
+		fmt.Printf("%s%-8d %-20s %s%-12s %s%-8.2f %s%-10s %s%-10s %s%-8s",
 			displayer.colorize("", displayer.ColorBold),
 			process.PID,
 			name,
@@ -359,16 +547,260 @@ func (displayer *MemoryMonitorDisplayer) displayTopProcesses(data *MemoryMonitor
 			process.MemoryPercent,
 			displayer.colorize("", displayer.ColorWhite),
 			displayer.formatBytes(process.RSS),
+			displayer.getProcessSwapColor(process.Swap),
+			displayer.formatBytes(process.Swap),
 			statusColor,
-			process.Status,
+			process.Status)
+		if showPSS {
+			fmt.Printf(" %s%-10s %-10s",
+				displayer.colorize("", displayer.ColorCyan),
+				displayer.formatBytes(process.PSS),
+				displayer.formatBytes(process.USS))
+		}
+		if showPorts {
+			fmt.Printf(" %s%s",
+				displayer.colorize("", displayer.ColorCyan),
+				listening.Compact(process.ListeningPorts, listening.ColumnWidth))
+		}
+		fmt.Printf("%s\n", displayer.colorize("", displayer.ColorReset))
+	}
+}
+
+// displaySharedMemory displays tmpfs mount usage and the largest shared memory segments with their holders
+func (displayer *MemoryMonitorDisplayer) displaySharedMemory(data *MemoryMonitorData) {
+	fmt.Println("\n🗂️  TMPFS AND SHARED MEMORY")
+	fmt.Println(displayer.rule("-"))
+
+	if len(data.TmpfsMounts) > 0 {
+		fmt.Printf("%s%-24s %-6s %-10s %-10s %s%s\n",
+			displayer.colorize("", displayer.ColorBold),
+			"Mount",
+			"Type",
+			"Used",
+			"Size",
+			"Use%",
 			displayer.colorize("", displayer.ColorReset))
+
+		for _, mount := range data.TmpfsMounts {
+			size := "unlimited"
+			if mount.Total > 0 {
+				size = displayer.formatBytes(mount.Total)
+			}
+			fmt.Printf("%-24s %-6s %s%-10s%s %-10s %.1f%%\n",
+				terminal.TruncateLeft(mount.Mountpoint, 24),
+				mount.Fstype,
+				displayer.getMemoryUsageColor(mount.UsedPercent),
+				displayer.formatBytes(mount.Used),
+				displayer.colorize("", displayer.ColorReset),
+				size,
+				mount.UsedPercent)
+		}
+	}
+
+	if len(data.ShmSegments) == 0 {
+		return
+	}
+
+	fmt.Printf("\n%s%-6s %-28s %-10s %-9s %s%s\n",
+		displayer.colorize("", displayer.ColorBold),
+		"Kind",
+		"Segment",
+		"Size",
+		"Attached",
+		"Held By",
+		displayer.colorize("", displayer.ColorReset))
+
+	orphaned := false
+	for _, segment := range data.ShmSegments {
+		holders := formatShmHolders(segment.Holders)
+		holderColor := displayer.ColorWhite
+		switch {
+		case segment.Kind == "sysv" && segment.Attached == 0:
+			// The kernel's attach count is exact, so nobody really holds it
+			orphaned = true
+			holders = "nobody (orphaned)"
+			if segment.CreatorPID > 0 {
+				holders = fmt.Sprintf("nobody (orphaned, created by PID %d)", segment.CreatorPID)
+			}
+			holderColor = displayer.ColorYellow
+		case holders == "" && segment.Kind == "posix":
+			// Unmapped objects persist until deleted; mappings of other users' processes need root to see
+			holders = "no visible mapping"
+			holderColor = displayer.ColorYellow
+		case holders == "":
+			holders = "not visible (run as root)"
+		}
+
+		name := segment.Name
+		if segment.Kind == "sysv" {
+			name = fmt.Sprintf("%s id %d", segment.Name, segment.ID)
+		}
+		fmt.Printf("%-6s %-28s %-10s %-9d %s\n",
+			segment.Kind,
+			terminal.TruncateLeft(name, 28),
+			displayer.formatBytes(segment.Size),
+			segment.Attached,
+			displayer.colorize(holders, holderColor))
+	}
+
+	if orphaned {
+		fmt.Println(displayer.colorize("  Orphaned segments keep their memory until removed with ipcrm -m <id>", displayer.ColorCyan))
+	}
+}
+
+// formatShmHolders lists the first few processes holding a segment, e.g. "postgres (812), postgres (813) +4 more"
+func formatShmHolders(holders []ShmHolderInfo) string {
+	const shown = 3
+	parts := make([]string, 0, shown)
+	for i, holder := range holders {
+		if i == shown {
+			break
+		}
+		parts = append(parts, fmt.Sprintf("%s (%d)", holder.Name, holder.PID))
+	}
+
+	text := strings.Join(parts, ", ")
+	if len(holders) > shown {
+		text += fmt.Sprintf(" +%d more", len(holders)-shown)
+	}
+	return text
+}
+
+// displaySlabCaches displays kernel slab memory and the caches holding most of it
+func (displayer *MemoryMonitorDisplayer) displaySlabCaches(data *MemoryMonitorData) {
+	fmt.Println("\n🧱 KERNEL SLAB")
+	fmt.Println(displayer.rule("-"))
+
+	slab := data.SlabReclaimable + data.SlabUnreclaimable
+	if slab > 0 && data.TotalMemory > 0 {
+		fmt.Printf("%sSlab Memory: %s%s (%.1f%% of total: %s reclaimable, %s unreclaimable)\n",
+			displayer.colorize("", displayer.ColorBold),
+			displayer.colorize(displayer.formatBytes(slab), displayer.ColorMagenta),
+			displayer.colorize("", displayer.ColorReset),
+			float64(slab)/float64(data.TotalMemory)*100,
+			displayer.formatBytes(data.SlabReclaimable),
+			displayer.formatBytes(data.SlabUnreclaimable))
+	}
+
+	fmt.Printf("\n%s%-24s %-10s %-8s %-18s %-9s %s%s\n",
+		displayer.colorize("", displayer.ColorBold),
+		"Cache",
+		"Size",
+		"In Use",
+		"Objects",
+		"Obj Size",
+		"Kind",
+		displayer.colorize("", displayer.ColorReset))
+
+	// Caches sharing an explanation (kmalloc-*, the inode caches of each filesystem) are listed together
+	var hints []string
+	hintCaches := make(map[string][]string)
+	for _, cache := range data.SlabCaches {
+		inUse := 0.0
+		if cache.Size > 0 {
+			inUse = float64(cache.ActiveSize) / float64(cache.Size) * 100
+		}
+		kind, kindColor := "unreclaimable", displayer.ColorYellow
+		if cache.Reclaimable {
+			kind, kindColor = "reclaimable", displayer.ColorGreen
+		}
+		fmt.Printf("%-24s %-10s %-8s %-18s %-9d %s\n",
+			terminal.TruncateLeft(cache.Name, 24),
+			displayer.formatBytes(cache.Size),
+			fmt.Sprintf("%.0f%%", inUse),
+			fmt.Sprintf("%d/%d", cache.ActiveObjects, cache.Objects),
+			cache.ObjectSize,
+			displayer.colorize(kind, kindColor))
+
+		if hint := slabCacheHint(cache.Name); hint != "" {
+			if _, seen := hintCaches[hint]; !seen {
+				hints = append(hints, hint)
+			}
+			hintCaches[hint] = append(hintCaches[hint], cache.Name)
+		}
+	}
+
+	for _, hint := range hints {
+		fmt.Println(displayer.colorize(fmt.Sprintf("  %s: %s", strings.Join(hintCaches[hint], ", "), hint), displayer.ColorCyan))
+	}
+	if data.SlabUnreclaimable > data.SlabReclaimable && data.SlabUnreclaimable > data.TotalMemory/20 {
+		fmt.Println(displayer.colorize("  Unreclaimable slab is only freed when the kernel releases the objects; if it keeps growing, suspect a leak in a driver or module", displayer.ColorYellow))
+	}
+}
+
+// slabCacheHint explains what fills a well-known slab cache, empty for the others
+func slabCacheHint(name string) string {
+	switch {
+	case name == "dentry":
+		return "directory entries of paths looked up; grows with file scans and is freed under memory pressure"
+	case strings.HasSuffix(name, "inode_cache"):
+		return "inodes of recently used files; freed under memory pressure along with their dentries"
+	case strings.HasPrefix(name, "nf_conntrack"):
+		return "tracked network connections; capped by net.netfilter.nf_conntrack_max"
+	case name == "buffer_head":
+		return "block mappings of cached file pages; shrinks with the page cache"
+	case strings.HasPrefix(name, "kmalloc-"):
+		return "general kernel allocations; steady growth points at a driver or module"
+	}
+	return ""
+}
+
+// displayOOMKills displays recent OOM killer victims
+func (displayer *MemoryMonitorDisplayer) displayOOMKills(data *MemoryMonitorData) {
+	fmt.Println("\n💀 OOM KILLER HISTORY")
+	fmt.Println(displayer.rule("-"))
+
+	// Header
+	fmt.Printf("%s%-19s %-8s %-20s %-7s %-12s %-8s%s\n",
+		displayer.colorize("", displayer.ColorBold),
+		"Time",
+		"PID",
+		"Name",
+		"Score",
+		"Freed",
+		"UID",
+		displayer.colorize("", displayer.ColorReset))
+
+	fmt.Println(displayer.rule("-"))
+
+	for _, kill := range data.OOMKills {
+		// Truncate long process names
+		name := kill.Name
+		if len(name) > 20 {
+			name = name[:17] + "..."
+		}
+
+		timestamp := "unknown"
+		if !kill.Timestamp.IsZero() {
+			timestamp = kill.Timestamp.Local().Format("2006-01-02 15:04:05")
+		}
+
+		score := "-"
+		if kill.Score > 0 {
+			score = fmt.Sprintf("%d", kill.Score)
+		}
+
+		uid := "-"
+		if kill.UID >= 0 {
+			uid = fmt.Sprintf("%d", kill.UID)
+		}
+
+		fmt.Printf("%-19s %-8d %s%-20s%s %-7s %-12s %-8s\n",
+			timestamp,
+			kill.PID,
+			displayer.getOOMKillColor(),
+			name,
+			displayer.colorize("", displayer.ColorReset),
+			score,
+			displayer.formatBytes(kill.FreedMemory),
+			uid)
 	}
 }
 
 // displayMemoryStatus displays memory status and alerts
 func (displayer *MemoryMonitorDisplayer) displayMemoryStatus(data *MemoryMonitorData) {
 	fmt.Println("\n🚨 MEMORY STATUS & ALERTS")
-	fmt.Println(strings.Repeat("-", 50))
+	fmt.Println(displayer.sectionRule())
 
 	// Memory status
 	statusColor := displayer.getMemoryStatusColor(data.MemoryStatus)
@@ -391,6 +823,19 @@ func (displayer *MemoryMonitorDisplayer) displayMemoryStatus(data *MemoryMonitor
 			displayer.colorize("", displayer.ColorReset))
 	}
 
+	// Most recent OOM kill, so memory alerts show whether the kernel already acted
+	if len(data.OOMKills) > 0 {
+		lastKill := data.OOMKills[0]
+		fmt.Printf("%s💀 Last OOM Kill: %s%s (PID %d) at %s, freed %s%s\n",
+			displayer.colorize("", displayer.ColorBold),
+			displayer.getOOMKillColor(),
+			lastKill.Name,
+			lastKill.PID,
+			lastKill.Timestamp.Local().Format("2006-01-02 15:04:05"),
+			displayer.formatBytes(lastKill.FreedMemory),
+			displayer.colorize("", displayer.ColorReset))
+	}
+
 	// Memory leak alert
 	if data.MemoryLeakAlert {
 		fmt.Printf("%s🔍 Memory Leak Alert: %sPOTENTIAL LEAK DETECTED%s\n",
@@ -407,7 +852,7 @@ func (displayer *MemoryMonitorDisplayer) displayMemoryStatus(data *MemoryMonitor
 
 // displayUsageBar displays a graphical usage bar
 func (displayer *MemoryMonitorDisplayer) displayUsageBar(label string, percentage float64, color string, customWidth ...int) {
-	width := displayer.BarWidth
+	width := displayer.barWidth()
 	if len(customWidth) > 0 {
 		width = customWidth[0]
 	}
@@ -421,20 +866,40 @@ func (displayer *MemoryMonitorDisplayer) displayUsageBar(label string, percentag
 	// Create bar
 	bar := strings.Repeat("█", filledWidth) + strings.Repeat("░", width-filledWidth)
 
+	// Category colors (e.g. buffers, cache) have no marker; with colors off only a severity marker is printed
+	if !displayer.ShowColors {
+		color = terminal.MarkerOf(color)
+	}
+
 	fmt.Printf("%s%-20s %s[%s]%s %s%.2f%%%s\n",
 		displayer.colorize("", displayer.ColorBold),
 		label,
 		displayer.colorize("", displayer.ColorWhite),
-		displayer.colorize(bar, color),
+		displayer.colorize(bar, terminal.WithoutMarker(color)),
 		displayer.colorize("", displayer.ColorWhite),
 		color,
 		percentage,
 		displayer.colorize("", displayer.ColorReset))
 }
 
+// displayAlertNotes shows the note configured for each raised alert, so the next step is on screen
+func (displayer *MemoryMonitorDisplayer) displayAlertNotes(data *MemoryMonitorData) {
+	fmt.Println("\n📘 RUNBOOK NOTES")
+	fmt.Println(displayer.sectionRule())
+
+	for _, note := range data.AlertNotes {
+		fmt.Printf("%s%s (%s):%s %s\n",
+			displayer.getMemoryStatusColor(note.Level),
+			note.Key,
+			note.Level,
+			displayer.colorize("", displayer.ColorReset),
+			note.Text)
+	}
+}
+
 // displayFooter displays the memory monitor footer
 func (displayer *MemoryMonitorDisplayer) displayFooter(data *MemoryMonitorData) {
-	fmt.Println(strings.Repeat("=", 80))
+	fmt.Println(displayer.rule("="))
 	fmt.Printf("%sLast Updated: %s%s%s\n",
 		displayer.colorize("", displayer.ColorBold),
 		displayer.colorize("", displayer.ColorCyan),
@@ -447,7 +912,11 @@ func (displayer *MemoryMonitorDisplayer) displayFooter(data *MemoryMonitorData)
 		data.RefreshInterval.Seconds(),
 		displayer.colorize("", displayer.ColorReset))
 
-	fmt.Println(strings.Repeat("=", 80))
+	if !displayer.ShowColors || terminal.ColorBlindMode() {
+		fmt.Println(terminal.SeverityLegend())
+	}
+
+	fmt.Println(displayer.rule("="))
 }
 
 // formatBytes formats bytes into human-readable format
@@ -464,81 +933,157 @@ func (displayer *MemoryMonitorDisplayer) formatBytes(bytes uint64) string {
 	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
 }
 
+// displayUnavailable shows a section that could not be collected, with the reason
+func (displayer *MemoryMonitorDisplayer) displayUnavailable(title, reason string) {
+	fmt.Println("\n" + title)
+	fmt.Println(displayer.sectionRule())
+	fmt.Println(displayer.colorize("⚠️  "+partial.Unavailable(reason), displayer.ColorYellow))
+}
+
+// lineWidth returns the layout width: the terminal width, or 80 columns when AutoWidth is off
+func (displayer *MemoryMonitorDisplayer) lineWidth() int {
+	if !displayer.AutoWidth {
+		return terminal.DefaultWidth
+	}
+	return terminal.Fit(terminal.Width(), 40, 240)
+}
+
+// rule returns a separator line across the layout width
+func (displayer *MemoryMonitorDisplayer) rule(char string) string {
+	return strings.Repeat(char, displayer.lineWidth())
+}
+
+// sectionRule returns a section underline, shortened on narrow terminals
+func (displayer *MemoryMonitorDisplayer) sectionRule() string {
+	return strings.Repeat("-", terminal.Fit(displayer.lineWidth(), 0, 50))
+}
+
+// barWidth returns the progress bar width, leaving room for the label and value on the same line
+func (displayer *MemoryMonitorDisplayer) barWidth() int {
+	if !displayer.AutoWidth {
+		return displayer.BarWidth
+	}
+	return terminal.Fit(displayer.lineWidth()-32, 10, 100)
+}
+
 // colorize applies color to text if colors are enabled
 func (displayer *MemoryMonitorDisplayer) colorize(text, color string) string {
 	if !displayer.ShowColors {
-		return text
+		// Severity colors are reduced to their marker
+		return terminal.MarkerOf(color) + text
 	}
-	return color + text + displayer.ColorReset
+	return terminal.PaletteColor(displayer.severityLevel(color), color) + text + displayer.ColorReset
 }
 
-// getMemoryUsageColor returns the appropriate color for memory usage percentage
-func (displayer *MemoryMonitorDisplayer) getMemoryUsageColor(percentage float64) string {
+// severityColor returns a severity color in the active palette, followed by its marker when markers are shown
+// Markers are shown in color-blind mode and whenever colors are off, so severity never depends on color alone
+func (displayer *MemoryMonitorDisplayer) severityColor(color string) string {
+	level := displayer.severityLevel(color)
+	marker := ""
+	if !displayer.ShowColors || terminal.ColorBlindMode() {
+		marker = terminal.SeverityMarker(level)
+	}
 	if !displayer.ShowColors {
-		return ""
+		return marker
+	}
+	return terminal.PaletteColor(level, color) + marker
+}
+
+// severityLevel returns the severity a displayer color stands for
+func (displayer *MemoryMonitorDisplayer) severityLevel(color string) terminal.Severity {
+	switch color {
+	case displayer.ColorGreen:
+		return terminal.SeverityOK
+	case displayer.ColorYellow:
+		return terminal.SeverityWarning
+	case displayer.ColorMagenta:
+		return terminal.SeverityHigh
+	case displayer.ColorRed:
+		return terminal.SeverityCritical
+	}
+	return terminal.SeverityNone
+}
+
+// getProcessSwapColor returns the appropriate color for a process's swapped-out memory
+func (displayer *MemoryMonitorDisplayer) getProcessSwapColor(swap uint64) string {
+	switch {
+	case swap == 0:
+		return displayer.severityColor(displayer.ColorGreen)
+	case swap < 100*1024*1024:
+		return displayer.severityColor(displayer.ColorYellow)
+	default:
+		return displayer.severityColor(displayer.ColorRed)
 	}
+}
+
+// getOOMKillColor returns the color used to highlight OOM killer victims
+func (displayer *MemoryMonitorDisplayer) getOOMKillColor() string {
+	return displayer.severityColor(displayer.ColorRed)
+}
 
+// getMemoryUsageColor returns the appropriate color for memory usage percentage
+func (displayer *MemoryMonitorDisplayer) getMemoryUsageColor(percentage float64) string {
 	switch {
 	case percentage < 30:
-		return displayer.ColorGreen
+		return displayer.severityColor(displayer.ColorGreen)
 	case percentage < 60:
-		return displayer.ColorYellow
+		return displayer.severityColor(displayer.ColorYellow)
 	case percentage < 80:
-		return displayer.ColorMagenta
+		return displayer.severityColor(displayer.ColorMagenta)
 	default:
-		return displayer.ColorRed
+		return displayer.severityColor(displayer.ColorRed)
 	}
 }
 
 // getMemoryStatusColor returns the appropriate color for memory status
 func (displayer *MemoryMonitorDisplayer) getMemoryStatusColor(status string) string {
-	if !displayer.ShowColors {
-		return ""
-	}
-
 	switch status {
 	case "Normal":
-		return displayer.ColorGreen
+		return displayer.severityColor(displayer.ColorGreen)
 	case "Warning":
-		return displayer.ColorYellow
+		return displayer.severityColor(displayer.ColorYellow)
 	case "Critical":
-		return displayer.ColorRed
+		return displayer.severityColor(displayer.ColorRed)
 	default:
-		return displayer.ColorWhite
+		return displayer.severityColor(displayer.ColorWhite)
 	}
 }
 
-// getSwapUsageColor returns the appropriate color for swap usage
-func (displayer *MemoryMonitorDisplayer) getSwapUsageColor(percentage float64) string {
-	if !displayer.ShowColors {
-		return ""
+// getAvailableColor returns the color for available memory (percentage of total); less is worse
+func (displayer *MemoryMonitorDisplayer) getAvailableColor(percentage float64) string {
+	switch {
+	case percentage >= 30:
+		return displayer.severityColor(displayer.ColorGreen)
+	case percentage >= 15:
+		return displayer.severityColor(displayer.ColorYellow)
+	default:
+		return displayer.severityColor(displayer.ColorRed)
 	}
+}
 
+// getSwapUsageColor returns the appropriate color for swap usage
+func (displayer *MemoryMonitorDisplayer) getSwapUsageColor(percentage float64) string {
 	switch {
 	case percentage < 20:
-		return displayer.ColorGreen
+		return displayer.severityColor(displayer.ColorGreen)
 	case percentage < 50:
-		return displayer.ColorYellow
+		return displayer.severityColor(displayer.ColorYellow)
 	default:
-		return displayer.ColorRed
+		return displayer.severityColor(displayer.ColorRed)
 	}
 }
 
 // getSwapStatusColor returns the appropriate color for swap status
 func (displayer *MemoryMonitorDisplayer) getSwapStatusColor(status string) string {
-	if !displayer.ShowColors {
-		return ""
-	}
-
 	switch status {
 	case "Normal":
-		return displayer.ColorGreen
+		return displayer.severityColor(displayer.ColorGreen)
 	case "Warning":
-		return displayer.ColorYellow
+		return displayer.severityColor(displayer.ColorYellow)
 	case "Critical":
-		return displayer.ColorRed
+		return displayer.severityColor(displayer.ColorRed)
 	default:
-		return displayer.ColorWhite
+		return displayer.severityColor(displayer.ColorWhite)
 	}
 }
 
@@ -560,36 +1105,28 @@ func (displayer *MemoryMonitorDisplayer) getCacheUsageColor(percentage float64)
 
 // getFragmentationColor returns the appropriate color for fragmentation
 func (displayer *MemoryMonitorDisplayer) getFragmentationColor(percentage float64) string {
-	if !displayer.ShowColors {
-		return ""
-	}
-
 	switch {
 	case percentage < 20:
-		return displayer.ColorGreen
+		return displayer.severityColor(displayer.ColorGreen)
 	case percentage < 40:
-		return displayer.ColorYellow
+		return displayer.severityColor(displayer.ColorYellow)
 	default:
-		return displayer.ColorRed
+		return displayer.severityColor(displayer.ColorRed)
 	}
 }
 
 // getProcessStatusColor returns the appropriate color for process status
 func (displayer *MemoryMonitorDisplayer) getProcessStatusColor(status string) string {
-	if !displayer.ShowColors {
-		return ""
-	}
-
 	switch status {
 	case "running":
-		return displayer.ColorGreen
+		return displayer.severityColor(displayer.ColorGreen)
 	case "sleeping":
-		return displayer.ColorBlue
+		return displayer.severityColor(displayer.ColorBlue)
 	case "stopped":
-		return displayer.ColorYellow
+		return displayer.severityColor(displayer.ColorYellow)
 	case "zombie":
-		return displayer.ColorRed
+		return displayer.severityColor(displayer.ColorRed)
 	default:
-		return displayer.ColorWhite
+		return displayer.severityColor(displayer.ColorWhite)
 	}
 }
@@ -0,0 +1,17 @@
+package memorymonitor
+
+import (
+	"testing"
+
+	"github.com/ahmadreza-log/simple-monitor/monitortest"
+)
+
+// TestDisplayMemoryMonitorDataGolden renders testdata/snapshot.json the way the fixtures command does and
+// compares the output with testdata/display.golden
+func TestDisplayMemoryMonitorDataGolden(t *testing.T) {
+	monitortest.Golden(t, func(data *MemoryMonitorData) {
+		displayer := NewMemoryMonitorDisplayer()
+		displayer.ShowColors, displayer.AutoWidth = false, false
+		displayer.DisplayMemoryMonitorData(data)
+	})
+}
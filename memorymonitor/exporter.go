@@ -3,8 +3,12 @@ package memorymonitor
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/ahmadreza-log/simple-monitor/exportschema"
+	"github.com/ahmadreza-log/simple-monitor/protoexport"
+	"github.com/ahmadreza-log/simple-monitor/redact"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 )
 
@@ -16,6 +20,8 @@ type MemoryMonitorExporter struct {
 	DateFormat    string // Date format for file naming
 	CreateSubDirs bool   // Whether to create subdirectories for each module
 	PrettyPrint   bool   // Whether to pretty print JSON output
+
+	exportschema.Format // CSV header style and metadata rows
 }
 
 // NewMemoryMonitorExporter creates a new instance of MemoryMonitorExporter
@@ -32,6 +38,10 @@ func NewMemoryMonitorExporter() *MemoryMonitorExporter {
 // ExportToJSON exports memory monitoring data to a JSON file
 // The file will be saved with a timestamp-based filename in the appropriate subdirectory
 func (exporter *MemoryMonitorExporter) ExportToJSON(data *MemoryMonitorData, moduleName string) (string, error) {
+	if err := redact.Copy(&data); err != nil {
+		return "", err
+	}
+
 	// Create logs directory if it doesn't exist
 	if err := os.MkdirAll(exporter.LogsDirectory, 0755); err != nil {
 		return "", fmt.Errorf("failed to create logs directory: %w", err)
@@ -75,9 +85,55 @@ func (exporter *MemoryMonitorExporter) ExportToJSON(data *MemoryMonitorData, mod
 	return filePath, nil
 }
 
+// ExportToProtobuf exports memory monitoring data to a protobuf file
+// The file holds one MemoryMonitorData message; it is smaller and faster to parse than JSON for long archives
+func (exporter *MemoryMonitorExporter) ExportToProtobuf(data *MemoryMonitorData, moduleName string) (string, error) {
+	if err := redact.Copy(&data); err != nil {
+		return "", err
+	}
+
+	// Create logs directory if it doesn't exist
+	if err := os.MkdirAll(exporter.LogsDirectory, 0755); err != nil {
+		return "", fmt.Errorf("failed to create logs directory: %w", err)
+	}
+
+	// Create subdirectory for the module if enabled
+	var targetDir string
+	if exporter.CreateSubDirs {
+		targetDir = filepath.Join(exporter.LogsDirectory, moduleName)
+		if err := os.MkdirAll(targetDir, 0755); err != nil {
+			return "", fmt.Errorf("failed to create module directory: %w", err)
+		}
+	} else {
+		targetDir = exporter.LogsDirectory
+	}
+
+	// Generate filename with timestamp
+	timestamp := time.Now().Format("2006-01-02_15-04-05")
+	filename := fmt.Sprintf("%s_%s.%s", moduleName, timestamp, protoexport.Extension)
+	filePath := filepath.Join(targetDir, filename)
+
+	// Encode as the message `simple-monitor proto` describes
+	content, err := protoexport.Marshal(data)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode protobuf data: %w", err)
+	}
+
+	// Write to file
+	if err := os.WriteFile(filePath, content, 0644); err != nil {
+		return "", fmt.Errorf("failed to write protobuf file: %w", err)
+	}
+
+	return filePath, nil
+}
+
 // ExportToCSV exports memory monitoring data to a CSV file
 // This creates a simplified CSV format with key metrics
 func (exporter *MemoryMonitorExporter) ExportToCSV(data *MemoryMonitorData, moduleName string) (string, error) {
+	if err := redact.Copy(&data); err != nil {
+		return "", err
+	}
+
 	// Create logs directory if it doesn't exist
 	if err := os.MkdirAll(exporter.LogsDirectory, 0755); err != nil {
 		return "", fmt.Errorf("failed to create logs directory: %w", err)
@@ -113,6 +169,10 @@ func (exporter *MemoryMonitorExporter) ExportToCSV(data *MemoryMonitorData, modu
 // ExportToTXT exports memory monitoring data to a text file
 // This creates a human-readable text format
 func (exporter *MemoryMonitorExporter) ExportToTXT(data *MemoryMonitorData, moduleName string) (string, error) {
+	if err := redact.Copy(&data); err != nil {
+		return "", err
+	}
+
 	// Create logs directory if it doesn't exist
 	if err := os.MkdirAll(exporter.LogsDirectory, 0755); err != nil {
 		return "", fmt.Errorf("failed to create logs directory: %w", err)
@@ -149,11 +209,14 @@ func (exporter *MemoryMonitorExporter) ExportToTXT(data *MemoryMonitorData, modu
 func (exporter *MemoryMonitorExporter) generateCSVContent(data *MemoryMonitorData) string {
 	var content string
 
+	// Metadata rows
+	content += exporter.Metadata(exportschema.Memory, true)
+
 	// Header
-	content += "Timestamp,Total Memory,Used Memory,Free Memory,Memory Percent,Swap Total,Swap Used,Swap Percent,Memory Status\n"
+	content += exporter.CSVHeader("Timestamp,Total Memory,Used Memory,Free Memory,Memory Percent,Swap Total,Swap Used,Swap Percent,Memory Status,Available Memory,Committed,Commit Limit,Commit Percent,Slab Reclaimable,Slab Unreclaimable", "timestamp,total_memory,used_memory,free_memory,memory_percent,swap_info.total_swap,swap_info.used_swap,swap_info.swap_percent,memory_status,available_memory,commit_info.committed,commit_info.commit_limit,commit_info.commit_percent,slab_reclaimable,slab_unreclaimable")
 
 	// Data row
-	content += fmt.Sprintf("%s,%d,%d,%d,%.2f,%d,%d,%.2f,%s\n",
+	content += fmt.Sprintf("%s,%d,%d,%d,%.2f,%d,%d,%.2f,%s,%d,%d,%d,%.2f,%d,%d\n",
 		data.Timestamp.Format("2006-01-02 15:04:05"),
 		data.TotalMemory,
 		data.UsedMemory,
@@ -162,20 +225,99 @@ func (exporter *MemoryMonitorExporter) generateCSVContent(data *MemoryMonitorDat
 		data.SwapInfo.TotalSwap,
 		data.SwapInfo.UsedSwap,
 		data.SwapInfo.SwapPercent,
-		data.MemoryStatus)
+		data.MemoryStatus,
+		data.AvailableMemory,
+		data.CommitInfo.Committed,
+		data.CommitInfo.CommitLimit,
+		data.CommitInfo.CommitPercent,
+		data.SlabReclaimable,
+		data.SlabUnreclaimable)
 
 	// Process data
 	if len(data.TopProcesses) > 0 {
-		content += "\nProcess Data\n"
-		content += "PID,Name,Memory Usage,Memory Percent,RSS,Status\n"
+		content += exporter.CSVSection("Process Data", "top_processes")
+		content += exporter.CSVHeader("PID,Instance ID,Name,Memory Usage,Memory Percent,RSS,Status,PSS,USS,Swap,Listening Ports", "pid,instance_id,name,memory_usage,memory_percent,rss,status,pss,uss,swap,listening_ports")
 		for _, process := range data.TopProcesses {
-			content += fmt.Sprintf("%d,%s,%d,%.2f,%d,%s\n",
+			content += fmt.Sprintf("%d,%s,%s,%d,%.2f,%d,%s,%d,%d,%d,%s\n",
 				process.PID,
+				process.InstanceID,
 				process.Name,
 				process.MemoryUsage,
 				process.MemoryPercent,
 				process.RSS,
-				process.Status)
+				process.Status,
+				process.PSS,
+				process.USS,
+				process.Swap,
+				strings.Join(process.ListeningPorts, " "))
+		}
+	}
+
+	// OOM killer history
+	if len(data.OOMKills) > 0 {
+		content += exporter.CSVSection("OOM Kills", "oom_kills")
+		content += exporter.CSVHeader("Timestamp,PID,Name,UID,Score,OOM Score Adj,Total VM,Freed Memory", "timestamp,pid,name,uid,score,oom_score_adj,total_vm,freed_memory")
+		for _, kill := range data.OOMKills {
+			content += fmt.Sprintf("%s,%d,%s,%d,%d,%d,%d,%d\n",
+				kill.Timestamp.Format("2006-01-02 15:04:05"),
+				kill.PID,
+				kill.Name,
+				kill.UID,
+				kill.Score,
+				kill.OOMScoreAdj,
+				kill.TotalVM,
+				kill.FreedMemory)
+		}
+	}
+
+	// tmpfs mounts
+	if len(data.TmpfsMounts) > 0 {
+		content += exporter.CSVSection("Tmpfs Mounts", "tmpfs_mounts")
+		content += exporter.CSVHeader("Mountpoint,Type,Total,Used,Used Percent", "mountpoint,fstype,total,used,used_percent")
+		for _, mount := range data.TmpfsMounts {
+			content += fmt.Sprintf("%s,%s,%d,%d,%.2f\n",
+				mount.Mountpoint,
+				mount.Fstype,
+				mount.Total,
+				mount.Used,
+				mount.UsedPercent)
+		}
+	}
+
+	// Shared memory segments
+	if len(data.ShmSegments) > 0 {
+		content += exporter.CSVSection("Shared Memory Segments", "shm_segments")
+		content += exporter.CSVHeader("Kind,Name,ID,Size,Resident,Creator PID,Attached,Holders", "kind,name,id,size,resident,creator_pid,attached,holders")
+		for _, segment := range data.ShmSegments {
+			holders := make([]string, len(segment.Holders))
+			for i, holder := range segment.Holders {
+				holders[i] = fmt.Sprintf("%s:%d", holder.Name, holder.PID)
+			}
+			content += fmt.Sprintf("%s,%s,%d,%d,%d,%d,%d,%s\n",
+				segment.Kind,
+				segment.Name,
+				segment.ID,
+				segment.Size,
+				segment.Resident,
+				segment.CreatorPID,
+				segment.Attached,
+				strings.Join(holders, " "))
+		}
+	}
+
+	// Kernel slab caches
+	if len(data.SlabCaches) > 0 {
+		content += exporter.CSVSection("Slab Caches", "slab_caches")
+		content += exporter.CSVHeader("Name,Active Objects,Objects,Object Size,Size,Active Size,Reclaimable", "name,active_objects,objects,object_size,size,active_size,reclaimable")
+		for _, cache := range data.SlabCaches {
+			content += fmt.Sprintf("%s,%d,%d,%d,%d,%d,%t\n",
+				cache.Name,
+				cache.ActiveObjects,
+				cache.Objects,
+				cache.ObjectSize,
+				cache.Size,
+				cache.ActiveSize,
+				cache.Reclaimable)
 		}
 	}
 
@@ -186,6 +328,9 @@ func (exporter *MemoryMonitorExporter) generateCSVContent(data *MemoryMonitorDat
 func (exporter *MemoryMonitorExporter) generateTXTContent(data *MemoryMonitorData) string {
 	var content string
 
+	// Metadata rows
+	content += exporter.Metadata(exportschema.Memory, false)
+
 	// Header
 	content += "MEMORY MONITOR REPORT\n"
 	content += "====================\n\n"
@@ -193,15 +338,40 @@ func (exporter *MemoryMonitorExporter) generateTXTContent(data *MemoryMonitorDat
 	// Timestamp
 	content += fmt.Sprintf("Generated: %s\n\n", data.Timestamp.Format("2006-01-02 15:04:05"))
 
+	// Sections that could not be collected
+	if len(data.SectionErrors) > 0 {
+		content += "UNAVAILABLE SECTIONS\n"
+		content += "--------------------\n"
+		for _, section := range data.SectionErrors.Sections() {
+			content += fmt.Sprintf("%s: %s\n", section, data.SectionErrors[section])
+		}
+		content += "\n"
+	}
+
 	// Memory summary
 	content += "MEMORY SUMMARY\n"
 	content += "--------------\n"
 	content += fmt.Sprintf("Total Memory: %s\n", exporter.formatBytes(data.TotalMemory))
 	content += fmt.Sprintf("Used Memory: %s\n", exporter.formatBytes(data.UsedMemory))
+	content += fmt.Sprintf("Available Memory: %s (%.2f%%)\n", exporter.formatBytes(data.AvailableMemory), data.AvailablePercent)
 	content += fmt.Sprintf("Free Memory: %s\n", exporter.formatBytes(data.FreeMemory))
 	content += fmt.Sprintf("Memory Usage: %.2f%%\n", data.MemoryPercent)
 	content += fmt.Sprintf("Memory Status: %s\n\n", data.MemoryStatus)
 
+	// Commit charge
+	if data.CommitInfo.CommitLimit > 0 {
+		content += "COMMIT CHARGE\n"
+		content += "-------------\n"
+		content += fmt.Sprintf("Committed: %s\n", exporter.formatBytes(data.CommitInfo.Committed))
+		content += fmt.Sprintf("Commit Limit: %s\n", exporter.formatBytes(data.CommitInfo.CommitLimit))
+		content += fmt.Sprintf("Commit Usage: %.2f%%\n", data.CommitInfo.CommitPercent)
+		if data.CommitInfo.OvercommitMode >= 0 {
+			content += fmt.Sprintf("Overcommit: %s\n", overcommitDescription(data.CommitInfo))
+		}
+		content += fmt.Sprintf("Limit Enforced: %t\n", data.CommitInfo.LimitEnforced)
+		content += fmt.Sprintf("Commit Status: %s\n\n", data.CommitInfo.CommitStatus)
+	}
+
 	// Memory breakdown
 	content += "MEMORY BREAKDOWN\n"
 	content += "----------------\n"
@@ -220,6 +390,18 @@ func (exporter *MemoryMonitorExporter) generateTXTContent(data *MemoryMonitorDat
 		content += fmt.Sprintf("Free Swap: %s\n", exporter.formatBytes(data.SwapInfo.FreeSwap))
 		content += fmt.Sprintf("Swap Usage: %.2f%%\n", data.SwapInfo.SwapPercent)
 		content += fmt.Sprintf("Swap Status: %s\n\n", data.SwapInfo.SwapStatus)
+
+		if len(data.TopSwapProcesses) > 0 {
+			content += "TOP SWAPPED PROCESSES\n"
+			content += "---------------------\n"
+			for _, process := range data.TopSwapProcesses {
+				content += fmt.Sprintf("%d\t%-20s\t%s\n",
+					process.PID,
+					process.Name,
+					exporter.formatBytes(process.Swap))
+			}
+			content += "\n"
+		}
 	}
 
 	// Cache information
@@ -258,6 +440,82 @@ func (exporter *MemoryMonitorExporter) generateTXTContent(data *MemoryMonitorDat
 		content += "\n"
 	}
 
+	// tmpfs mounts and shared memory segments
+	if len(data.TmpfsMounts) > 0 || len(data.ShmSegments) > 0 {
+		content += "TMPFS AND SHARED MEMORY\n"
+		content += "-----------------------\n"
+		for _, mount := range data.TmpfsMounts {
+			content += fmt.Sprintf("%-24s\t%s\t%s of %s (%.1f%%)\n",
+				mount.Mountpoint,
+				mount.Fstype,
+				exporter.formatBytes(mount.Used),
+				exporter.formatBytes(mount.Total),
+				mount.UsedPercent)
+		}
+		if len(data.ShmSegments) > 0 {
+			content += "\nKind\tSegment\t\t\t\tSize\t\tAttached\tHeld By\n"
+			content += "----\t-------\t\t\t\t----\t\t--------\t-------\n"
+		}
+		for _, segment := range data.ShmSegments {
+			name := segment.Name
+			if segment.Kind == "sysv" {
+				name = fmt.Sprintf("%s id %d", segment.Name, segment.ID)
+			}
+			holders := formatShmHolders(segment.Holders)
+			if segment.Kind == "sysv" && segment.Attached == 0 {
+				holders = "nobody (orphaned)"
+			}
+			content += fmt.Sprintf("%s\t%-28s\t%s\t%d\t\t%s\n",
+				segment.Kind,
+				name,
+				exporter.formatBytes(segment.Size),
+				segment.Attached,
+				holders)
+		}
+		content += "\n"
+	}
+
+	// Kernel slab caches
+	if len(data.SlabCaches) > 0 {
+		content += "KERNEL SLAB\n"
+		content += "-----------\n"
+		content += fmt.Sprintf("Reclaimable: %s\n", exporter.formatBytes(data.SlabReclaimable))
+		content += fmt.Sprintf("Unreclaimable: %s\n", exporter.formatBytes(data.SlabUnreclaimable))
+		content += "\nCache\t\t\tSize\t\tObjects\t\tKind\n"
+		content += "-----\t\t\t----\t\t-------\t\t----\n"
+		for _, cache := range data.SlabCaches {
+			kind := "unreclaimable"
+			if cache.Reclaimable {
+				kind = "reclaimable"
+			}
+			content += fmt.Sprintf("%-20s\t%s\t%d/%d\t%s\n",
+				cache.Name,
+				exporter.formatBytes(cache.Size),
+				cache.ActiveObjects,
+				cache.Objects,
+				kind)
+		}
+		content += "\n"
+	}
+
+	// OOM killer history
+	if len(data.OOMKills) > 0 {
+		content += "OOM KILLER HISTORY\n"
+		content += "------------------\n"
+		content += "Time\t\t\tPID\tName\t\t\tScore\tFreed\n"
+		content += "----\t\t\t---\t----\t\t\t-----\t-----\n"
+
+		for _, kill := range data.OOMKills {
+			content += fmt.Sprintf("%s\t%d\t%-20s\t%d\t%s\n",
+				kill.Timestamp.Format("2006-01-02 15:04:05"),
+				kill.PID,
+				kill.Name,
+				kill.Score,
+				exporter.formatBytes(kill.FreedMemory))
+		}
+		content += "\n"
+	}
+
 	// Alerts
 	content += "ALERTS & WARNINGS\n"
 	content += "-----------------\n"
@@ -296,3 +554,13 @@ func (exporter *MemoryMonitorExporter) SetPrettyPrint(pretty bool) {
 func (exporter *MemoryMonitorExporter) SetCreateSubDirs(create bool) {
 	exporter.CreateSubDirs = create
 }
+
+// SetStableKeys sets whether CSV headers use stable snake_case keys instead of prose
+func (exporter *MemoryMonitorExporter) SetStableKeys(stable bool) {
+	exporter.StableKeys = stable
+}
+
+// SetHeaderMetadata sets whether exports start with metadata rows
+func (exporter *MemoryMonitorExporter) SetHeaderMetadata(metadata bool) {
+	exporter.HeaderMetadata = metadata
+}
@@ -1,9 +1,22 @@
+// Package memorymonitor monitors RAM and swap use, caches, shared memory, kernel slab and commit charge
+// A MemoryMonitorManager collects once with Collect or in the background with Subscribe
 package memorymonitor
 
 import (
+	"encoding/json"
 	"fmt"
+	"github.com/ahmadreza-log/simple-monitor/alert"
+	"github.com/ahmadreza-log/simple-monitor/historystore"
+	"github.com/ahmadreza-log/simple-monitor/hooks"
+	"github.com/ahmadreza-log/simple-monitor/idle"
+	"github.com/ahmadreza-log/simple-monitor/overlay"
+	"github.com/ahmadreza-log/simple-monitor/recording"
+	"github.com/ahmadreza-log/simple-monitor/terminal"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 )
@@ -21,18 +34,40 @@ type MemoryMonitorManager struct {
 	stopChannel    chan bool
 	refreshTicker  *time.Ticker
 	lastExportTime time.Time
+
+	// Extra line shown under the live display (e.g. key shortcuts)
+	liveHint string
+
+	// Pause and step control (the display stays frozen while paused)
+	paused      atomic.Bool
+	stepChannel chan bool
+
+	// Session recorder (nil unless the live session is being recorded)
+	recorder *recording.Recorder
+
+	// Idle detection (slows refreshes while nobody is using the machine)
+	idleDetector *idle.Detector
+
+	// Series imported with `simple-monitor overlay import`, drawn under the history graph
+	overlaySource overlay.Source
 }
 
 // NewMemoryMonitorManager creates a new instance of MemoryMonitorManager
-// with default collector, displayer, and exporter configurations
-func NewMemoryMonitorManager() *MemoryMonitorManager {
-	return &MemoryMonitorManager{
-		collector:   NewMemoryMonitorCollector(),
-		displayer:   NewMemoryMonitorDisplayer(),
-		exporter:    NewMemoryMonitorExporter(),
-		isRunning:   false,
-		stopChannel: make(chan bool, 1),
+// with default collector, displayer, and exporter configurations, then applies the options in order
+func NewMemoryMonitorManager(options ...Option) *MemoryMonitorManager {
+	manager := &MemoryMonitorManager{
+		collector:    NewMemoryMonitorCollector(),
+		displayer:    NewMemoryMonitorDisplayer(),
+		exporter:     NewMemoryMonitorExporter(),
+		idleDetector: idle.NewDetector(),
+		isRunning:    false,
+		stopChannel:  make(chan bool, 1),
+		stepChannel:  make(chan bool, 1),
+	}
+	for _, option := range options {
+		option(manager)
 	}
+	return manager
 }
 
 // StartLiveMonitoring starts live memory monitoring with real-time updates
@@ -43,7 +78,7 @@ func (manager *MemoryMonitorManager) StartLiveMonitoring() error {
 	}
 
 	manager.isRunning = true
-	manager.refreshTicker = time.NewTicker(manager.collector.config.RefreshInterval)
+	manager.refreshTicker = time.NewTicker(manager.collector.currentConfig().RefreshInterval)
 
 	// Set up signal handling for graceful shutdown
 	sigChan := make(chan os.Signal, 1)
@@ -53,12 +88,19 @@ func (manager *MemoryMonitorManager) StartLiveMonitoring() error {
 	fmt.Println("Press Ctrl+C to stop monitoring")
 
 	// Start monitoring loop
+	loopDone := make(chan bool)
+	loopExited := make(chan bool)
 	go func() {
+		defer close(loopExited)
 		for {
 			select {
 			case <-manager.refreshTicker.C:
+				if !manager.paused.Load() {
+					manager.updateAndDisplay()
+				}
+			case <-manager.stepChannel:
 				manager.updateAndDisplay()
-			case <-manager.stopChannel:
+			case <-loopDone:
 				return
 			case <-sigChan:
 				manager.StopMonitoring()
@@ -68,10 +110,21 @@ func (manager *MemoryMonitorManager) StartLiveMonitoring() error {
 	}()
 
 	// Wait for stop signal
+	// The loop is stopped separately so StopMonitoring can also be called from outside the loop,
+	// and a refresh still in progress finishes before returning
 	<-manager.stopChannel
+	close(loopDone)
+	<-loopExited
+	signal.Stop(sigChan)
 	return nil
 }
 
+// display shows data with the overlay series imported in the logs directory, picking up a new import
+func (manager *MemoryMonitorManager) display(data *MemoryMonitorData) {
+	manager.displayer.Overlay = manager.overlaySource.Series(filepath.Join(manager.exporter.LogsDirectory, overlay.File))
+	manager.displayer.DisplayMemoryMonitorData(data)
+}
+
 // StartSingleSnapshot displays a single snapshot of memory information
 func (manager *MemoryMonitorManager) StartSingleSnapshot() error {
 	fmt.Println("📊 Collecting memory information...")
@@ -83,13 +136,14 @@ func (manager *MemoryMonitorManager) StartSingleSnapshot() error {
 	}
 
 	// Display memory data
-	manager.displayer.DisplayMemoryMonitorData(data)
+	manager.display(data)
 
 	// Always export to file for memory monitor
 	filePath, err := manager.exporter.ExportToJSON(data, "memorymonitor")
 	if err != nil {
 		fmt.Printf("⚠️  Warning: Failed to export data: %v\n", err)
 	} else {
+		hooks.Exported("memory", filePath)
 		fmt.Printf("\n💾 Memory data saved to: %s\n", filePath)
 	}
 
@@ -108,6 +162,9 @@ func (manager *MemoryMonitorManager) StopMonitoring() {
 		manager.refreshTicker.Stop()
 	}
 
+	// Keep everything collected in this session
+	manager.persistHistory(true)
+
 	select {
 	case manager.stopChannel <- true:
 	default:
@@ -118,6 +175,12 @@ func (manager *MemoryMonitorManager) StopMonitoring() {
 
 // updateAndDisplay collects new data and updates the display
 func (manager *MemoryMonitorManager) updateAndDisplay() {
+	// Refresh less often and pause process scans while the machine is idle
+	if !manager.idleDetector.ShouldCollect() {
+		return
+	}
+	manager.collector.SetIdle(manager.idleDetector.IsIdle())
+
 	// Collect new memory data
 	data, err := manager.collector.CollectMemoryMonitorData()
 	if err != nil {
@@ -127,7 +190,27 @@ func (manager *MemoryMonitorManager) updateAndDisplay() {
 	}
 
 	// Display updated data
-	manager.displayer.DisplayMemoryMonitorData(data)
+	manager.display(data)
+	if status := manager.idleDetector.Status(); status != "" {
+		fmt.Println("\n" + status)
+	}
+	if status := alert.Status(); status != "" {
+		fmt.Println("\n" + status)
+	}
+	if manager.liveHint != "" {
+		fmt.Println(manager.liveHint)
+	}
+	if manager.paused.Load() {
+		fmt.Println(terminal.PausedNotice)
+	}
+
+	// Capture the cycle for replay
+	if err := manager.recorder.Record("memory", data); err != nil {
+		fmt.Printf("\n⚠️  %v\n", err)
+	}
+
+	// Save the long-term history
+	manager.persistHistory(false)
 
 	// Export data based on export interval
 	manager.exportDataIfNeeded(data)
@@ -136,13 +219,13 @@ func (manager *MemoryMonitorManager) updateAndDisplay() {
 // exportDataIfNeeded exports memory data to file based on export interval
 func (manager *MemoryMonitorManager) exportDataIfNeeded(data *MemoryMonitorData) {
 	// Check if export is enabled
-	if !manager.collector.config.ExportToFile {
+	if !manager.collector.currentConfig().ExportToFile {
 		return
 	}
 
 	// Check if it's time to export based on export interval
 	now := time.Now()
-	if manager.lastExportTime.IsZero() || now.Sub(manager.lastExportTime) >= manager.collector.config.ExportInterval {
+	if manager.lastExportTime.IsZero() || now.Sub(manager.lastExportTime) >= manager.collector.currentConfig().ExportInterval {
 		manager.exportData(data)
 		manager.lastExportTime = now
 	}
@@ -150,20 +233,55 @@ func (manager *MemoryMonitorManager) exportDataIfNeeded(data *MemoryMonitorData)
 
 // exportData exports memory data to file
 func (manager *MemoryMonitorManager) exportData(data *MemoryMonitorData) {
-	filePath, err := manager.exporter.ExportToJSON(data, "memorymonitor")
+	export := manager.exporter.ExportToJSON
+	if manager.collector.currentConfig().ExportFormat == "protobuf" {
+		export = manager.exporter.ExportToProtobuf
+	}
+	filePath, err := export(data, "memorymonitor")
 	if err != nil {
 		// Don't display error for every export to avoid cluttering the display
 		return
 	}
 
+	hooks.Exported("memory", filePath)
 	fmt.Printf("\n💾 Data exported to: %s\n", filePath)
 }
 
-// GetMemoryUsageHistory returns the current memory usage history
+// GetMemoryUsageHistory returns a copy of the memory usage history
 func (manager *MemoryMonitorManager) GetMemoryUsageHistory() *MemoryUsageHistory {
 	return manager.collector.GetMemoryUsageHistory()
 }
 
+// GetLongTermHistory returns the downsampled long-term history
+func (manager *MemoryMonitorManager) GetLongTermHistory() *historystore.Store {
+	return manager.collector.GetLongTermHistory()
+}
+
+// HistoryMemoryEstimate estimates the memory an in-memory history of samples uses
+func (manager *MemoryMonitorManager) HistoryMemoryEstimate(samples int) uint64 {
+	return manager.collector.HistoryMemoryEstimate(samples)
+}
+
+// persistHistory writes the long-term history to logs/history
+// Unless forced (when monitoring stops), the file is rewritten at most once per save interval
+func (manager *MemoryMonitorManager) persistHistory(force bool) {
+	config := manager.collector.currentConfig()
+	// Synthetic history must not end up in the real history file
+	if !config.PersistHistory || manager.collector.IsSimulated() {
+		return
+	}
+
+	interval := config.HistorySaveInterval
+	if force {
+		interval = 0
+	}
+
+	path := filepath.Join(manager.exporter.LogsDirectory, "history", "memorymonitor.json")
+
+	// Errors are ignored to avoid cluttering the display; the next save retries
+	manager.collector.GetLongTermHistory().Persist(path, interval)
+}
+
 // GetConfig returns the current configuration
 func (manager *MemoryMonitorManager) GetConfig() *MemoryMonitorConfig {
 	return manager.collector.GetConfig()
@@ -189,6 +307,42 @@ func (manager *MemoryMonitorManager) SetExportOptions(logsDir string, prettyPrin
 	manager.exporter.SetCreateSubDirs(createSubDirs)
 }
 
+// SetHeaderOptions configures CSV header keys and export metadata rows
+func (manager *MemoryMonitorManager) SetHeaderOptions(stableKeys, headerMetadata bool) {
+	manager.exporter.SetStableKeys(stableKeys)
+	manager.exporter.SetHeaderMetadata(headerMetadata)
+}
+
+// SetIdleMode enables or disables idle mode
+// While idle, refreshes are slowed down and process scans are paused until activity resumes
+func (manager *MemoryMonitorManager) SetIdleMode(enabled bool) {
+	manager.idleDetector.SetEnabled(enabled)
+	if !enabled {
+		manager.collector.SetIdle(false)
+	}
+}
+
+// SetSimulationMode switches the monitor to synthetic data (--simulate)
+// Simulated data needs no live system or elevated permissions, which suits demos and displayer development
+func (manager *MemoryMonitorManager) SetSimulationMode(enabled bool) {
+	manager.collector.SetSimulated(enabled)
+}
+
+// IsSimulated returns whether the monitor shows synthetic data
+func (manager *MemoryMonitorManager) IsSimulated() bool {
+	return manager.collector.IsSimulated()
+}
+
+// GetIdleState returns the most recent idle check
+func (manager *MemoryMonitorManager) GetIdleState() idle.State {
+	return manager.idleDetector.State()
+}
+
+// GetIdleConfig returns the idle detection configuration
+func (manager *MemoryMonitorManager) GetIdleConfig() idle.Config {
+	return manager.idleDetector.GetConfig()
+}
+
 // ExportToFile exports current memory data to a file
 func (manager *MemoryMonitorManager) ExportToFile(format string) error {
 	// Collect current data
@@ -202,6 +356,8 @@ func (manager *MemoryMonitorManager) ExportToFile(format string) error {
 	switch format {
 	case "json":
 		filePath, err = manager.exporter.ExportToJSON(data, "memorymonitor")
+	case "protobuf":
+		filePath, err = manager.exporter.ExportToProtobuf(data, "memorymonitor")
 	case "csv":
 		filePath, err = manager.exporter.ExportToCSV(data, "memorymonitor")
 	case "txt":
@@ -214,6 +370,7 @@ func (manager *MemoryMonitorManager) ExportToFile(format string) error {
 		return fmt.Errorf("failed to export data: %w", err)
 	}
 
+	hooks.Exported("memory", filePath)
 	fmt.Printf("💾 Memory data exported to: %s\n", filePath)
 	return nil
 }
@@ -238,7 +395,87 @@ func (manager *MemoryMonitorManager) GetMemoryAlerts() (bool, bool, error) {
 	return data.LowMemoryWarning, data.MemoryLeakAlert, nil
 }
 
+// Collect collects the memory data once, without displaying or exporting it
+func (manager *MemoryMonitorManager) Collect() (*MemoryMonitorData, error) {
+	return manager.collector.CollectMemoryMonitorData()
+}
+
+// Subscribe collects every interval in the background and sends the data on the returned channel until the
+// returned function is called, which closes it; a zero interval uses the configured refresh interval
+// Nothing is displayed, exported or recorded. A subscriber that falls behind only gets the newest data,
+// and failed collections are skipped (Collect returns their error)
+func (manager *MemoryMonitorManager) Subscribe(interval time.Duration) (<-chan *MemoryMonitorData, func()) {
+	if interval <= 0 {
+		interval = manager.collector.currentConfig().RefreshInterval
+	}
+	updates := make(chan *MemoryMonitorData, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(updates)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if data, err := manager.Collect(); err == nil {
+				select {
+				case <-updates:
+				default:
+				}
+				updates <- data
+			}
+			select {
+			case <-ticker.C:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var stop sync.Once
+	return updates, func() {
+		stop.Do(func() { close(done) })
+	}
+}
+
 // IsRunning returns whether the memory monitor is currently running
 func (manager *MemoryMonitorManager) IsRunning() bool {
 	return manager.isRunning
 }
+
+// IsEnabled returns whether the monitor is switched on in its configuration
+func (manager *MemoryMonitorManager) IsEnabled() bool {
+	return manager.collector.currentConfig().Enabled
+}
+
+// SetLiveHint sets a line shown under each live refresh, or clears it when empty
+func (manager *MemoryMonitorManager) SetLiveHint(hint string) {
+	manager.liveHint = hint
+}
+
+// SetPaused freezes the live display on its last refresh, or resumes refreshing
+// Nothing is collected while paused, so history, exports and alerts wait too
+func (manager *MemoryMonitorManager) SetPaused(paused bool) {
+	manager.paused.Store(paused)
+}
+
+// Step collects and shows one refresh, for stepping through a paused display
+func (manager *MemoryMonitorManager) Step() {
+	select {
+	case manager.stepChannel <- true:
+	default:
+	}
+}
+
+// SetRecorder records every live collection cycle to recorder (nil stops recording)
+func (manager *MemoryMonitorManager) SetRecorder(recorder *recording.Recorder) {
+	manager.recorder = recorder
+}
+
+// DisplayRecordedData shows one recorded collection cycle the way the live display showed it
+func (manager *MemoryMonitorManager) DisplayRecordedData(content []byte) error {
+	var data MemoryMonitorData
+	if err := json.Unmarshal(content, &data); err != nil {
+		return fmt.Errorf("failed to decode recorded memory data: %w", err)
+	}
+	manager.display(&data)
+	return nil
+}
@@ -0,0 +1,47 @@
+package memorymonitor
+
+import "time"
+
+// Option configures a MemoryMonitorManager as NewMemoryMonitorManager creates it
+// Options are applied before the manager is returned, so nothing can be collecting while they change its settings
+type Option func(*MemoryMonitorManager)
+
+// WithRefreshInterval sets how often live monitoring and Subscribe collect; an interval of zero or less is ignored
+func WithRefreshInterval(interval time.Duration) Option {
+	return func(manager *MemoryMonitorManager) {
+		if interval > 0 {
+			manager.collector.changeConfig(func(config *MemoryMonitorConfig) {
+				config.RefreshInterval = interval
+			})
+		}
+	}
+}
+
+// WithExporter replaces the default exporter, e.g. to write exports under another logs directory
+// A nil exporter keeps the default
+func WithExporter(exporter *MemoryMonitorExporter) Option {
+	return func(manager *MemoryMonitorManager) {
+		if exporter != nil {
+			manager.exporter = exporter
+		}
+	}
+}
+
+// WithThresholds sets the memory usage percentages that raise the warning and critical alerts
+func WithThresholds(warning, critical float64) Option {
+	return func(manager *MemoryMonitorManager) {
+		manager.collector.changeConfig(func(config *MemoryMonitorConfig) {
+			config.MemoryWarning = warning
+			config.MemoryCritical = critical
+		})
+	}
+}
+
+// WithFilters shows only the top processes named exactly processName (empty shows all)
+func WithFilters(processName string) Option {
+	return func(manager *MemoryMonitorManager) {
+		manager.collector.changeConfig(func(config *MemoryMonitorConfig) {
+			config.ProcessNameFilter = processName
+		})
+	}
+}
@@ -0,0 +1,209 @@
+//go:build linux
+
+package memorymonitor
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// sysvShmPath lists System V shared memory segments, one per line after a header
+const sysvShmPath = "/proc/sysvipc/shm"
+
+// posixShmDir holds POSIX shared memory objects (shm_open) as files
+const posixShmDir = "/dev/shm"
+
+// readShmSegments lists System V and POSIX shared memory segments, largest first, with the processes that map them
+// Holders are found in /proc/<pid>/maps: SysV segments show up as /SYSV<key> with the shmid as inode,
+// POSIX ones by their /dev/shm path; processes of other users need root to be seen
+func readShmSegments(limit int) ([]SharedSegmentInfo, error) {
+	segments, err := readSysVSegments()
+	if err != nil {
+		return nil, err
+	}
+	segments = append(segments, readPOSIXSegments()...)
+	if len(segments) == 0 {
+		return nil, nil
+	}
+
+	sort.Slice(segments, func(i, j int) bool {
+		return segments[i].Size > segments[j].Size
+	})
+	if limit > 0 && len(segments) > limit {
+		segments = segments[:limit]
+	}
+
+	findShmHolders(segments)
+	return segments, nil
+}
+
+// readSysVSegments parses /proc/sysvipc/shm
+// Columns: key shmid perms size cpid lpid nattch uid gid cuid cgid atime dtime ctime rss swap
+func readSysVSegments() ([]SharedSegmentInfo, error) {
+	file, err := os.Open(sysvShmPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil // Kernel built without System V IPC
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", sysvShmPath, err)
+	}
+	defer file.Close()
+
+	var segments []SharedSegmentInfo
+	scanner := bufio.NewScanner(file)
+	scanner.Scan() // Header
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 7 {
+			continue
+		}
+
+		key, _ := strconv.ParseInt(fields[0], 10, 64)
+		id, err := strconv.Atoi(fields[1])
+		if err != nil {
+			continue
+		}
+		size, _ := strconv.ParseUint(fields[3], 10, 64)
+		creator, _ := strconv.ParseInt(fields[4], 10, 32)
+		attached, _ := strconv.Atoi(fields[6])
+
+		segment := SharedSegmentInfo{
+			Kind:       "sysv",
+			Name:       fmt.Sprintf("0x%08x", uint32(key)),
+			ID:         id,
+			Size:       size,
+			CreatorPID: int32(creator),
+			Attached:   attached,
+		}
+		if len(fields) >= 15 {
+			segment.Resident, _ = strconv.ParseUint(fields[14], 10, 64)
+		}
+		segments = append(segments, segment)
+	}
+	return segments, scanner.Err()
+}
+
+// readPOSIXSegments lists the objects in /dev/shm with the bytes they actually hold
+func readPOSIXSegments() []SharedSegmentInfo {
+	entries, err := os.ReadDir(posixShmDir)
+	if err != nil {
+		return nil
+	}
+
+	var segments []SharedSegmentInfo
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		// Sparse objects are common (ring buffers sized up front), so count allocated blocks
+		size := uint64(info.Size())
+		if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+			size = uint64(stat.Blocks) * 512
+		}
+		segments = append(segments, SharedSegmentInfo{
+			Kind:     "posix",
+			Name:     filepath.Join(posixShmDir, entry.Name()),
+			ID:       -1,
+			Size:     size,
+			Resident: size,
+		})
+	}
+	return segments
+}
+
+// findShmHolders fills in the processes that map each segment, scanning every readable /proc/<pid>/maps
+func findShmHolders(segments []SharedSegmentInfo) {
+	sysvIndex := make(map[string]int)
+	posixIndex := make(map[string]int)
+	for i, segment := range segments {
+		if segment.Kind == "sysv" {
+			sysvIndex[strconv.Itoa(segment.ID)] = i
+		} else {
+			posixIndex[segment.Name] = i
+		}
+	}
+
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		pid, err := strconv.ParseInt(entry.Name(), 10, 32)
+		if err != nil {
+			continue
+		}
+		mapped := mappedSegments(int32(pid), sysvIndex, posixIndex)
+		if len(mapped) == 0 {
+			continue
+		}
+
+		holder := ShmHolderInfo{PID: int32(pid), Name: readComm(int32(pid))}
+		for _, i := range mapped {
+			segments[i].Holders = append(segments[i].Holders, holder)
+		}
+	}
+
+	// The kernel counts SysV attachments itself, including processes hidden from us
+	for i := range segments {
+		if segments[i].Kind == "posix" {
+			segments[i].Attached = len(segments[i].Holders)
+		}
+	}
+}
+
+// mappedSegments returns the indexes of the segments a process maps, each once
+func mappedSegments(pid int32, sysvIndex, posixIndex map[string]int) []int {
+	file, err := os.Open(fmt.Sprintf("/proc/%d/maps", pid))
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	seen := make(map[int]bool)
+	var mapped []int
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		sysv := strings.Contains(line, "/SYSV")
+		if !sysv && !strings.Contains(line, posixShmDir+"/") {
+			continue
+		}
+
+		// address perms offset dev inode pathname
+		fields := strings.Fields(line)
+		if len(fields) < 6 {
+			continue
+		}
+		index, ok := 0, false
+		if sysv {
+			index, ok = sysvIndex[fields[4]]
+		} else {
+			index, ok = posixIndex[strings.TrimSuffix(strings.Join(fields[5:], " "), " (deleted)")]
+		}
+		if ok && !seen[index] {
+			seen[index] = true
+			mapped = append(mapped, index)
+		}
+	}
+	return mapped
+}
+
+// readComm returns the process name from /proc/<pid>/comm, empty when unreadable
+func readComm(pid int32) string {
+	content, err := os.ReadFile(fmt.Sprintf("/proc/%d/comm", pid))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(content))
+}
@@ -0,0 +1,9 @@
+//go:build !linux
+
+package memorymonitor
+
+// readShmSegments lists shared memory segments
+// Only Linux exposes System V and POSIX segments in a readable form, so other systems report none
+func readShmSegments(limit int) ([]SharedSegmentInfo, error) {
+	return nil, nil
+}
@@ -0,0 +1,197 @@
+package memorymonitor
+
+import (
+	"fmt"
+	"github.com/ahmadreza-log/simple-monitor/listening"
+	"github.com/ahmadreza-log/simple-monitor/procid"
+	"github.com/ahmadreza-log/simple-monitor/simulate"
+	"sort"
+	"time"
+)
+
+// collectSimulatedData fills data with synthetic memory metrics instead of querying the system
+// Usage slowly rises and falls with occasional allocation spikes; swap follows memory pressure
+func (collector *MemoryMonitorCollector) collectSimulatedData(data *MemoryMonitorData) {
+	source := collector.simulator
+	const total = 16 * 1024 * simulate.MB
+
+	data.Simulated = true
+	data.Uptime = source.Uptime()
+
+	// Overall usage
+	percent := simulate.Clamp(source.Wave(3*time.Minute, 0, 45, 80)+source.Noise(2)+source.Spike(0.04, 15), 5, 99)
+	data.TotalMemory = total
+	data.MemoryPercent = percent
+	data.UsedMemory = uint64(float64(total) * percent / 100)
+	data.AvailableMemory = total - data.UsedMemory
+	data.FreeMemory = uint64(float64(data.AvailableMemory) * 0.35)
+	data.AvailablePercent = 100 - percent
+
+	// Breakdown
+	data.UserMemory = uint64(float64(data.UsedMemory) * 0.6)
+	data.SystemMemory = uint64(float64(data.UsedMemory) * 0.3)
+	data.BufferMemory = uint64(float64(data.UsedMemory) * 0.1)
+	data.CacheMemory = data.AvailableMemory - data.FreeMemory
+	data.SharedMemory = 512 * simulate.MB
+
+	if collector.config.ShowPerformance {
+		data.MemoryPressure = percent
+		data.MemoryFragmentation = 100 - percent
+		data.PageFaults = uint64(source.Between(200, 1200) + source.Spike(0.05, 8000))
+		data.PageIns = uint64(source.Between(0, 40))
+		data.PageOuts = uint64(source.Between(0, 20))
+	}
+
+	if collector.config.ShowModules {
+		for i := 0; i < 2; i++ {
+			data.MemoryModules = append(data.MemoryModules, MemoryModuleInfo{
+				ModuleID:     i,
+				Type:         "DDR4",
+				TotalSize:    total / 2,
+				UsedSize:     data.UsedMemory / 2,
+				FreeSize:     data.AvailableMemory / 2,
+				UsagePercent: percent,
+				Speed:        3200,
+				Manufacturer: "Simulated",
+				Model:        "SIM-8G-3200",
+				SerialNumber: fmt.Sprintf("SIM%05d", i+1),
+			})
+		}
+	}
+
+	// Swap only fills up once memory is under pressure
+	if collector.config.ShowSwap {
+		const totalSwap = 4 * 1024 * simulate.MB
+		swapPercent := simulate.Clamp((percent-60)*2, 2, 100)
+		data.SwapInfo = MemorySwapInfo{
+			TotalSwap:   totalSwap,
+			UsedSwap:    uint64(float64(totalSwap) * swapPercent / 100),
+			FreeSwap:    uint64(float64(totalSwap) * (100 - swapPercent) / 100),
+			SwapPercent: swapPercent,
+			SwapIn:      source.Counter("swap_in", 20*swapPercent),
+			SwapOut:     source.Counter("swap_out", 30*swapPercent),
+			SwapStatus:  collector.getSwapStatus(swapPercent),
+		}
+	}
+
+	// Commit charge runs ahead of usage, as programs reserve more than they touch
+	if collector.config.ShowCommit {
+		const commitLimit = total + 4*1024*simulate.MB
+		committed := uint64(float64(commitLimit) * simulate.Clamp(percent*1.1+source.Noise(3), 5, 110) / 100)
+		data.CommitInfo = MemoryCommitInfo{
+			Committed:       committed,
+			CommitLimit:     commitLimit,
+			CommitPercent:   float64(committed) / float64(commitLimit) * 100,
+			OvercommitMode:  0,
+			OvercommitRatio: 50,
+		}
+	}
+
+	// A postgres buffer pool in System V shared memory, a browser's POSIX segments and a leftover
+	// segment nobody has attached, plus tmpfs mounts that slowly fill with scratch files
+	if collector.config.ShowShared && !collector.idle {
+		const shmLimit = total / 2
+		shmUsed := uint64(source.Wave(10*time.Minute, 0.3, 180, 420) * simulate.MB)
+		data.TmpfsMounts = []TmpfsMountInfo{
+			{Mountpoint: "/dev/shm", Fstype: "tmpfs", Total: shmLimit, Used: shmUsed},
+			{Mountpoint: "/tmp", Fstype: "tmpfs", Total: shmLimit, Used: uint64(source.Between(90, 110) * simulate.MB)},
+			{Mountpoint: "/run", Fstype: "tmpfs", Total: total / 10, Used: 3 * simulate.MB},
+		}
+		for i := range data.TmpfsMounts {
+			mount := &data.TmpfsMounts[i]
+			mount.UsedPercent = float64(mount.Used) / float64(mount.Total) * 100
+		}
+
+		holders := make(map[string][]ShmHolderInfo)
+		for _, process := range source.Processes() {
+			holders[process.Name] = append(holders[process.Name], ShmHolderInfo{PID: process.PID, Name: process.Name})
+		}
+		data.ShmSegments = []SharedSegmentInfo{
+			{Kind: "sysv", Name: "0x0052e2c1", ID: 3, Size: 144 * simulate.MB, Resident: 128 * simulate.MB, Attached: len(holders["postgres"]), Holders: holders["postgres"]},
+			{Kind: "posix", Name: "/dev/shm/.org.chromium.Chromium.a1B2c3", ID: -1, Size: shmUsed - 40*simulate.MB, Resident: shmUsed - 40*simulate.MB, Attached: len(holders["chrome"]), Holders: holders["chrome"]},
+			{Kind: "sysv", Name: "0x00000000", ID: 7, Size: 64 * simulate.MB, Resident: 64 * simulate.MB, CreatorPID: 31337},
+			{Kind: "posix", Name: "/dev/shm/pulse-shm-2817", ID: -1, Size: 40 * simulate.MB, Resident: 40 * simulate.MB, Attached: len(holders["Xorg"]), Holders: holders["Xorg"]},
+		}
+	}
+
+	// A periodic file scan fills the dentry and inode caches, which the kernel gives back afterwards;
+	// connection tracking follows traffic and the kmalloc caches stay flat
+	dentries := uint64(source.Wave(15*time.Minute, 0.5, 300, 900) * simulate.MB)
+	slabReclaimable := dentries + dentries*3/4 + 60*simulate.MB
+	const slabUnreclaimable = 180 * simulate.MB
+	if collector.config.ShowSlab {
+		data.SlabReclaimable = slabReclaimable
+		data.SlabUnreclaimable = slabUnreclaimable
+		caches := []SlabCacheInfo{
+			{Name: "dentry", ObjectSize: 192, Size: dentries, Reclaimable: true},
+			{Name: "ext4_inode_cache", ObjectSize: 1096, Size: dentries * 3 / 4, Reclaimable: true},
+			{Name: "buffer_head", ObjectSize: 104, Size: 60 * simulate.MB, Reclaimable: true},
+			{Name: "nf_conntrack", ObjectSize: 256, Size: uint64(source.Between(20, 60) * simulate.MB)},
+			{Name: "kmalloc-512", ObjectSize: 512, Size: 48 * simulate.MB},
+			{Name: "kmalloc-64", ObjectSize: 64, Size: 32 * simulate.MB},
+		}
+		for i := range caches {
+			cache := &caches[i]
+			cache.Objects = cache.Size / cache.ObjectSize
+			cache.ActiveObjects = cache.Objects * 9 / 10
+			cache.ActiveSize = cache.ActiveObjects * cache.ObjectSize
+		}
+		sort.Slice(caches, func(i, j int) bool {
+			return caches[i].Size > caches[j].Size
+		})
+		data.SlabCaches = caches
+	}
+
+	if collector.config.ShowCache {
+		data.CacheInfo = MemoryCacheInfo{
+			BufferCache: data.BufferMemory,
+			PageCache:   data.CacheMemory,
+			SlabCache:   slabReclaimable + slabUnreclaimable,
+			TotalCache:  data.BufferMemory + data.CacheMemory + slabReclaimable + slabUnreclaimable,
+		}
+		data.CacheInfo.CachePercent = float64(data.CacheInfo.TotalCache) / float64(total) * 100
+	}
+
+	// Top processes from the shared synthetic process table
+	if collector.config.ShowProcesses && !collector.idle {
+		var memoryProcesses []MemoryProcessInfo
+		for _, process := range source.Processes() {
+			memoryPercent := float64(process.MemoryRSS) / float64(total) * 100
+			if memoryPercent < collector.config.MinMemoryUsage {
+				continue
+			}
+			if collector.config.ProcessNameFilter != "" && process.Name != collector.config.ProcessNameFilter {
+				continue
+			}
+
+			processInfo := MemoryProcessInfo{
+				PID:           process.PID,
+				InstanceID:    procid.InstanceID(process.PID, process.CreateTime),
+				Name:          process.Name,
+				MemoryUsage:   process.MemoryRSS,
+				MemoryPercent: memoryPercent,
+				RSS:           process.MemoryRSS,
+				VMS:           process.MemoryVMS,
+				Status:        process.Status,
+				User:          process.User,
+				CreateTime:    process.CreateTime,
+			}
+			if collector.config.ShowPSS {
+				processInfo.PSS = process.MemoryRSS * 8 / 10
+				processInfo.USS = process.MemoryRSS * 6 / 10
+			}
+			if collector.config.ShowPorts && process.ListenPort > 0 {
+				processInfo.ListeningPorts = []string{listening.Port{Protocol: "TCP", Number: uint32(process.ListenPort)}.String()}
+			}
+			memoryProcesses = append(memoryProcesses, processInfo)
+		}
+
+		sort.Slice(memoryProcesses, func(i, j int) bool {
+			return memoryProcesses[i].MemoryPercent > memoryProcesses[j].MemoryPercent
+		})
+		if len(memoryProcesses) > collector.config.MaxProcesses {
+			memoryProcesses = memoryProcesses[:collector.config.MaxProcesses]
+		}
+		data.TopProcesses = memoryProcesses
+	}
+}
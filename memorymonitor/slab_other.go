@@ -0,0 +1,9 @@
+//go:build !linux
+
+package memorymonitor
+
+// readSlabCaches lists the largest kernel slab caches
+// /proc/slabinfo is Linux only, so other systems report none
+func readSlabCaches(limit int) ([]SlabCacheInfo, error) {
+	return nil, nil
+}
@@ -1,10 +1,16 @@
 package memorymonitor
 
-import "time"
+import (
+	"github.com/ahmadreza-log/simple-monitor/alert"
+	"github.com/ahmadreza-log/simple-monitor/partial"
+	"slices"
+	"time"
+)
 
 // MemoryProcessInfo represents memory usage information for a specific process
 type MemoryProcessInfo struct {
 	PID           int32   `json:"pid"`            // Process ID
+	InstanceID    string  `json:"instance_id"`    // Process instance ID (PID and start time)
 	Name          string  `json:"name"`           // Process name
 	MemoryUsage   uint64  `json:"memory_usage"`   // Memory usage in bytes
 	MemoryPercent float64 `json:"memory_percent"` // Memory usage percentage
@@ -13,6 +19,11 @@ type MemoryProcessInfo struct {
 	Status        string  `json:"status"`         // Process status
 	User          string  `json:"user"`           // Process owner
 	CreateTime    int64   `json:"create_time"`    // Process creation time
+	PSS           uint64  `json:"pss"`            // Proportional Set Size: RSS with shared pages divided among sharers (Linux)
+	USS           uint64  `json:"uss"`            // Unique Set Size: memory freed if the process exited (Linux)
+	Swap          uint64  `json:"swap"`           // Swapped-out memory (VmSwap on Linux, pagefile usage on Windows; 0 where the platform does not report it per process)
+
+	ListeningPorts []string `json:"listening_ports,omitempty"` // Ports the process listens on: "443" for TCP, "53/udp" for UDP
 }
 
 // MemoryModuleInfo represents memory information for a specific memory module
@@ -49,14 +60,82 @@ type MemoryCacheInfo struct {
 	CachePercent float64 `json:"cache_percent"` // Cache percentage of total memory
 }
 
+// MemoryCommitInfo represents the commit charge: memory promised to processes, measured against the commit limit
+// Running out of commit makes allocations fail (or, when the kernel overcommits, invites the OOM killer)
+// even while plenty of RAM still shows as free
+type MemoryCommitInfo struct {
+	Committed       uint64  `json:"committed"`        // Committed_AS on Linux, commit total on Windows (bytes)
+	CommitLimit     uint64  `json:"commit_limit"`     // Commit limit (bytes)
+	CommitPercent   float64 `json:"commit_percent"`   // Committed as a percentage of the limit
+	OvercommitMode  int     `json:"overcommit_mode"`  // vm.overcommit_memory: 0 heuristic, 1 always, 2 strict (-1 when not applicable)
+	OvercommitRatio int     `json:"overcommit_ratio"` // vm.overcommit_ratio: % of RAM counted toward the limit in strict mode
+	LimitEnforced   bool    `json:"limit_enforced"`   // Whether allocations fail once the limit is reached (strict mode, Windows)
+	CommitStatus    string  `json:"commit_status"`    // Commit status (Normal, Warning, Critical)
+}
+
+// TmpfsMountInfo represents a memory-backed filesystem; files written there hold RAM (or swap) until deleted
+type TmpfsMountInfo struct {
+	Mountpoint  string  `json:"mountpoint"`   // Where the filesystem is mounted, e.g. /dev/shm
+	Fstype      string  `json:"fstype"`       // tmpfs or ramfs
+	Total       uint64  `json:"total"`        // Size limit in bytes (0 when unlimited, as with ramfs)
+	Used        uint64  `json:"used"`         // Bytes held by files
+	UsedPercent float64 `json:"used_percent"` // Used as a percentage of the size limit
+}
+
+// SharedSegmentInfo represents a System V or POSIX shared memory segment and the processes that have it mapped
+// A segment with no processes attached still holds its memory until it is removed (ipcrm, or deleting the /dev/shm file)
+type SharedSegmentInfo struct {
+	Kind       string          `json:"kind"`        // "sysv" or "posix"
+	Name       string          `json:"name"`        // SysV key in hex, or the path under /dev/shm
+	ID         int             `json:"id"`          // SysV shmid (-1 for POSIX segments)
+	Size       uint64          `json:"size"`        // Segment size in bytes
+	Resident   uint64          `json:"resident"`    // Bytes in RAM (SysV only; POSIX segments report allocated bytes as their size)
+	CreatorPID int32           `json:"creator_pid"` // Process that created the segment (SysV only, 0 when unknown)
+	Attached   int             `json:"attached"`    // Processes that have the segment mapped
+	Holders    []ShmHolderInfo `json:"holders"`     // Processes that have the segment mapped, by PID
+}
+
+// SlabCacheInfo represents one kernel slab cache from /proc/slabinfo
+// Memory the kernel allocates for itself (directory entries, inodes, connection tracking...) comes from
+// slab caches, so it counts as used without belonging to any process
+type SlabCacheInfo struct {
+	Name          string `json:"name"`           // Cache name (dentry, ext4_inode_cache, nf_conntrack, kmalloc-64...)
+	ActiveObjects uint64 `json:"active_objects"` // Objects in use
+	Objects       uint64 `json:"objects"`        // Objects allocated, in use or free
+	ObjectSize    uint64 `json:"object_size"`    // Size of one object in bytes
+	Size          uint64 `json:"size"`           // Memory held by the cache's slabs in bytes
+	ActiveSize    uint64 `json:"active_size"`    // Memory taken by the objects in use in bytes
+	Reclaimable   bool   `json:"reclaimable"`    // Whether the kernel frees the cache under memory pressure (counted in SReclaimable)
+}
+
+// ShmHolderInfo represents a process that has a shared memory segment mapped
+type ShmHolderInfo struct {
+	PID  int32  `json:"pid"`  // Process ID
+	Name string `json:"name"` // Process name
+}
+
+// OOMKillInfo represents a process killed by the kernel OOM killer
+type OOMKillInfo struct {
+	PID         int32     `json:"pid"`           // Process ID of the victim
+	Name        string    `json:"name"`          // Process name of the victim
+	UID         int       `json:"uid"`           // User ID owning the victim (-1 if unknown)
+	Score       int       `json:"score"`         // OOM badness score (0 if not logged)
+	OOMScoreAdj int       `json:"oom_score_adj"` // oom_score_adj of the victim
+	TotalVM     uint64    `json:"total_vm"`      // Virtual memory size of the victim in bytes
+	FreedMemory uint64    `json:"freed_memory"`  // Resident memory freed by the kill in bytes (anon + file + shmem RSS)
+	Constraint  string    `json:"constraint"`    // OOM constraint (e.g., CONSTRAINT_NONE, CONSTRAINT_MEMCG)
+	Timestamp   time.Time `json:"timestamp"`     // When the process was killed
+}
+
 // MemoryMonitorData represents comprehensive memory monitoring data
 type MemoryMonitorData struct {
 	// Basic memory information
-	TotalMemory     uint64  `json:"total_memory"`     // Total system memory in bytes
-	AvailableMemory uint64  `json:"available_memory"` // Available memory in bytes
-	UsedMemory      uint64  `json:"used_memory"`      // Used memory in bytes
-	FreeMemory      uint64  `json:"free_memory"`      // Free memory in bytes
-	MemoryPercent   float64 `json:"memory_percent"`   // Memory usage percentage
+	TotalMemory      uint64  `json:"total_memory"`      // Total system memory in bytes
+	AvailableMemory  uint64  `json:"available_memory"`  // Available memory in bytes
+	UsedMemory       uint64  `json:"used_memory"`       // Used memory in bytes
+	FreeMemory       uint64  `json:"free_memory"`       // Free memory in bytes
+	MemoryPercent    float64 `json:"memory_percent"`    // Memory usage percentage
+	AvailablePercent float64 `json:"available_percent"` // Available memory as a percentage of total: the figure to watch, since free leaves out reclaimable cache
 
 	// Memory breakdown
 	UserMemory   uint64 `json:"user_memory"`   // User process memory
@@ -81,8 +160,24 @@ type MemoryMonitorData struct {
 	// Cache information
 	CacheInfo MemoryCacheInfo `json:"cache_info"` // System cache information
 
+	// Commit charge
+	CommitInfo MemoryCommitInfo `json:"commit_info"` // Committed memory against the commit limit
+
 	// Top processes by memory usage
-	TopProcesses []MemoryProcessInfo `json:"top_processes"` // Top memory-consuming processes
+	TopProcesses     []MemoryProcessInfo `json:"top_processes"`      // Top memory-consuming processes
+	TopSwapProcesses []MemoryProcessInfo `json:"top_swap_processes"` // Processes with the most memory swapped out
+
+	// OOM killer history
+	OOMKills []OOMKillInfo `json:"oom_kills"` // Recent OOM killer victims, newest first
+
+	// Memory-backed filesystems and shared memory, where "missing" memory often hides
+	TmpfsMounts []TmpfsMountInfo    `json:"tmpfs_mounts"` // tmpfs and ramfs mounts, most used first
+	ShmSegments []SharedSegmentInfo `json:"shm_segments"` // Largest shared memory segments first
+
+	// Kernel slab memory, the usual culprit when used memory belongs to no process
+	SlabReclaimable   uint64          `json:"slab_reclaimable"`   // Slab memory the kernel frees under pressure (SReclaimable)
+	SlabUnreclaimable uint64          `json:"slab_unreclaimable"` // Slab memory held until the objects are released (SUnreclaim)
+	SlabCaches        []SlabCacheInfo `json:"slab_caches"`        // Largest slab caches first
 
 	// Memory alerts and warnings
 	MemoryStatus     string `json:"memory_status"`      // Memory status (Normal, Warning, Critical)
@@ -93,6 +188,18 @@ type MemoryMonitorData struct {
 	RefreshInterval time.Duration `json:"refresh_interval"` // How often data is refreshed
 	IsMonitoring    bool          `json:"is_monitoring"`    // Whether monitoring is active
 
+	// Recent usage for the history graph (nil when the graph is off)
+	Trend *MemoryUsageTrend `json:"trend,omitempty"`
+
+	// Runbook notes of the raised alerts whose rule has a note
+	AlertNotes []alert.Note `json:"alert_notes,omitempty"` // Ordered by alert key
+
+	// Sections that could not be collected; everything else in the snapshot is still valid
+	SectionErrors partial.Errors `json:"section_errors,omitempty"` // Reason keyed by section name
+
+	// Data source
+	Simulated bool `json:"simulated,omitempty"` // Whether the data is synthetic (--simulate) rather than read from the system
+
 	// Timestamps
 	Timestamp time.Time     `json:"timestamp"` // When this data was collected
 	Uptime    time.Duration `json:"uptime"`    // System uptime
@@ -100,6 +207,8 @@ type MemoryMonitorData struct {
 
 // MemoryMonitorConfig represents configuration options for memory monitoring
 type MemoryMonitorConfig struct {
+	Enabled bool `json:"enabled"` // Whether the monitor runs; a disabled monitor is left out of every view, snapshot and alert
+
 	// Monitoring settings
 	RefreshInterval time.Duration `json:"refresh_interval"` // How often to refresh data
 	MaxProcesses    int           `json:"max_processes"`    // Maximum number of processes to track
@@ -107,23 +216,55 @@ type MemoryMonitorConfig struct {
 	MemoryCritical  float64       `json:"memory_critical"`  // Memory critical threshold (percentage)
 	SwapWarning     float64       `json:"swap_warning"`     // Swap warning threshold (percentage)
 	SwapCritical    float64       `json:"swap_critical"`    // Swap critical threshold (percentage)
+	CommitWarning   float64       `json:"commit_warning"`   // Commit charge warning threshold (percentage of the commit limit)
+	CommitCritical  float64       `json:"commit_critical"`  // Commit charge critical threshold (percentage of the commit limit)
+
+	// Alert behaviour (hysteresis and minimum durations) keyed by alert: memory, swap, commit
+	AlertRules map[string]alert.Rule `json:"alert_rules"` // Alerts without a rule flip exactly at their thresholds
 
 	// Display settings
 	ShowModules     bool `json:"show_modules"`     // Whether to show memory modules
 	ShowProcesses   bool `json:"show_processes"`   // Whether to show process information
 	ShowSwap        bool `json:"show_swap"`        // Whether to show swap information
 	ShowCache       bool `json:"show_cache"`       // Whether to show cache information
+	ShowCommit      bool `json:"show_commit"`      // Whether to show commit charge and overcommit settings
 	ShowPerformance bool `json:"show_performance"` // Whether to show performance metrics
+	ShowOOMKills    bool `json:"show_oom_kills"`   // Whether to show OOM killer history
+	ShowPSS         bool `json:"show_pss"`         // Whether to collect PSS/USS per process (reads smaps_rollup, Linux only)
+	ShowShared      bool `json:"show_shared"`      // Whether to show tmpfs mounts and shared memory segments with the processes holding them
+	ShowSlab        bool `json:"show_slab"`        // Whether to show the largest kernel slab caches (reads /proc/slabinfo, Linux only, needs root)
+	ShowPorts       bool `json:"show_ports"`       // Whether to list the ports each top process listens on
+	ShowHistory     bool `json:"show_history"`     // Whether to graph recent usage, with the imported overlay series under it
 
 	// Export settings
-	ExportToFile   bool          `json:"export_to_file"`  // Whether to export data to file
-	ExportInterval time.Duration `json:"export_interval"` // How often to export data
-	ExportFormat   string        `json:"export_format"`   // Export format (json, csv, txt)
+	ExportToFile        bool          `json:"export_to_file"`        // Whether to export data to file
+	ExportInterval      time.Duration `json:"export_interval"`       // How often to export data
+	ExportFormat        string        `json:"export_format"`         // Export format (json, csv, txt, protobuf)
+	PersistHistory      bool          `json:"persist_history"`       // Whether to keep downsampled long-term history on disk
+	HistorySaveInterval time.Duration `json:"history_save_interval"` // How often the long-term history file is rewritten
+	HistorySize         int           `json:"history_size"`          // Samples kept in the in-memory history behind live trends
+	HistoryResolution   time.Duration `json:"history_resolution"`    // Minimum time between in-memory history samples (0 samples every refresh)
 
 	// Filter settings
 	MinMemoryUsage      float64 `json:"min_memory_usage"`      // Minimum memory usage to show process
 	ProcessNameFilter   string  `json:"process_name_filter"`   // Filter processes by name
 	MemoryLeakThreshold float64 `json:"memory_leak_threshold"` // Memory leak detection threshold
+
+	// Shared memory settings
+	MaxShmSegments int `json:"max_shm_segments"` // Maximum number of shared memory segments to show
+
+	// Slab cache settings
+	MaxSlabCaches int `json:"max_slab_caches"` // Maximum number of slab caches to show
+
+	// OOM killer history settings
+	MaxOOMKills      int           `json:"max_oom_kills"`      // Maximum number of OOM victims to keep
+	OOMCheckInterval time.Duration `json:"oom_check_interval"` // How often to re-read the kernel log for OOM kills
+}
+
+// MemoryUsageTrend is recent memory usage for the history graph, under which an imported overlay series is drawn
+type MemoryUsageTrend struct {
+	Timestamps []time.Time `json:"timestamps"` // Sample times, oldest first
+	Usage      []float64   `json:"usage"`      // Memory usage at each sample (percentage)
 }
 
 // MemoryUsageHistory represents historical memory usage data for graphing
@@ -140,3 +281,15 @@ type MemoryUsageHistory struct {
 	MaxDataPoints  int `json:"max_data_points"`  // Maximum number of data points to store
 	DataPointCount int `json:"data_point_count"` // Current number of data points
 }
+
+// Clone returns a deep copy of the history
+func (history *MemoryUsageHistory) Clone() *MemoryUsageHistory {
+	clone := *history
+	clone.Timestamps = slices.Clone(history.Timestamps)
+	clone.TotalUsage = slices.Clone(history.TotalUsage)
+	clone.UserUsage = slices.Clone(history.UserUsage)
+	clone.SystemUsage = slices.Clone(history.SystemUsage)
+	clone.CacheUsage = slices.Clone(history.CacheUsage)
+	clone.SwapUsage = slices.Clone(history.SwapUsage)
+	return &clone
+}
@@ -0,0 +1,33 @@
+package monitortest
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ahmadreza-log/simple-monitor/fixtures"
+)
+
+// Golden decodes the fixture under testdata into a T, renders it with display and compares the output with the
+// golden file, reporting a difference as a test error
+// Times are shown in UTC like the fixtures command shows them; the process-wide zone is restored when the test ends,
+// so tests using Golden must not run in parallel with tests that read local time
+func Golden[T any](t testing.TB, display func(data *T)) {
+	t.Helper()
+
+	local := time.Local
+	time.Local = time.UTC
+	t.Cleanup(func() { time.Local = local })
+
+	err := fixtures.Compare(".", func(content []byte) error {
+		var data T
+		if err := json.Unmarshal(content, &data); err != nil {
+			return err
+		}
+		display(&data)
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,39 @@
+// Package monitortest holds helpers shared by the monitors' tests: it drives collectors from several goroutines
+// at once, whose checks find data races only when the tests run with -race, and compares displays with golden files
+package monitortest
+
+import (
+	"sync"
+	"testing"
+)
+
+// Collectors is how many goroutines collect at once, like the live loop, a subscriber and the attach server
+const Collectors = 3
+
+// Rounds is how many collections each goroutine runs, and how many times the operation is called
+const Rounds = 20
+
+// DuringCollections calls operation with 1 to Rounds while Collectors goroutines each run collect Rounds times
+// operation is what the menu or a manager does beside the collections: changing the configuration, reading the
+// history or switching providers. It returns once every collection has finished
+func DuringCollections(t testing.TB, collect func() error, operation func(i int)) {
+	t.Helper()
+
+	var wg sync.WaitGroup
+	for i := 0; i < Collectors; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < Rounds; j++ {
+				if err := collect(); err != nil {
+					t.Errorf("collection failed: %v", err)
+					return
+				}
+			}
+		}()
+	}
+	for i := 1; i <= Rounds; i++ {
+		operation(i)
+	}
+	wg.Wait()
+}
@@ -2,38 +2,133 @@ package networkmonitor
 
 import (
 	"fmt"
+	"github.com/ahmadreza-log/simple-monitor/alert"
+	"github.com/ahmadreza-log/simple-monitor/historystore"
+	"github.com/ahmadreza-log/simple-monitor/partial"
+	"github.com/ahmadreza-log/simple-monitor/privhelper"
+	"github.com/ahmadreza-log/simple-monitor/procid"
+	"github.com/ahmadreza-log/simple-monitor/provider"
+	"github.com/ahmadreza-log/simple-monitor/simulate"
+	"math"
 	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
 	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	netutil "github.com/shirou/gopsutil/v3/net"
-	"github.com/shirou/gopsutil/v3/process"
 )
 
 // NetworkMonitorCollector handles the collection of network monitoring data
 // This struct provides methods to gather real-time network metrics and process information
 type NetworkMonitorCollector struct {
-	// Configuration
+	// Configuration: config is what the collection in progress reads, adopted from latest as it starts
+	// latest is replaced on every change and never modified, so it can be handed out without copying
+	// collectMutex is held for a whole collection, so collections started by the live loop, subscribers and
+	// the attach server run one at a time and never see config or the state they carry change under them
 	config        *NetworkMonitorConfig
+	latest        *NetworkMonitorConfig
+	configMutex   sync.Mutex
+	collectMutex  sync.Mutex
 	lastTimestamp time.Time
 
-	// Process tracking
-	processCache    map[int32]*NetworkProcessInfo
-	lastProcessTime map[int32]time.Time
+	// Process tracking, keyed by process instance so a reused PID starts over
+	processCache    map[procid.Key]*NetworkProcessInfo
+	lastProcessTime map[procid.Key]time.Time
+
+	// Usual per-process rates and latency, learned while latency is normal
+	processBaseline map[procid.Key]float64
+	latencyBaseline float64
+	causeSource     string // What the process rates measure (CauseSourceSockets or CauseSourceIO)
+
+	// Recent successful latency samples per target, oldest first, for the percentiles
+	latencySamples map[string][]float64
 
 	// History tracking
 	history *NetworkUsageHistory
+
+	// Downsampled long-term history (raw, 1-minute and 5-minute tiers)
+	longHistory *historystore.Store
+
+	// Idle mode (expensive process scans are paused)
+	idle bool
+
+	// Connectivity probe cache (the probe is an HTTP request, so not every refresh)
+	proxyInfo         NetworkProxyInfo
+	captivePortal     CaptivePortalInfo
+	connectivityCheck time.Time
+
+	// Default gateway tracking
+	gateway     GatewayInfo
+	gatewayMACs map[string]string // Last MAC seen per gateway address
+
+	// Firewall counter snapshot (reading rules shells out, so not every refresh)
+	firewall FirewallInfo
+
+	// VPN receive counters for staleness detection
+	vpnLastRecv   map[string]uint64
+	vpnLastChange map[string]time.Time
+
+	// Every connection seen in the latest refresh, before MaxConnections applies (for VPN bypass detection)
+	openConnections []NetworkConnectionInfo
+
+	// Per-socket byte counters from the previous refresh (keyed by socketKey), and the rates of this refresh
+	// (nil when the counters could not be read) for port class rates and process traffic
+	socketBytes     map[string]socketBytes
+	socketBytesTime time.Time
+	socketRates     map[string]socketRate
+
+	// Connection tracking across refreshes (keyed by type, addresses and PID) for ages and churn
+	connections        map[string]*trackedConnection
+	connectionsTracked bool
+	shortLivedCloses   []connectionClose
+
+	// Synthetic data source used instead of the system (nil when collecting real data)
+	simulator *simulate.Source
+
+	// System data sources (gopsutil unless replaced with fakes)
+	system provider.Providers
+
+	// Alert levels carried between collections (hysteresis and minimum durations)
+	alerts *alert.Tracker
+}
+
+// trackedConnection remembers one connection across refreshes
+type trackedConnection struct {
+	firstSeen     time.Time
+	lastSeen      time.Time
+	preexisting   bool // Already open at the first collection, so its lifetime is unknown
+	processName   string
+	remoteAddress string
+}
+
+// connectionClose records a short-lived connection that has closed
+type connectionClose struct {
+	time          time.Time
+	processName   string
+	remoteAddress string
 }
 
 // NewNetworkMonitorCollector creates a new instance of NetworkMonitorCollector
 // with default configuration values
 func NewNetworkMonitorCollector() *NetworkMonitorCollector {
 	config := &NetworkMonitorConfig{
+		Enabled:             true,
 		RefreshInterval:     1 * time.Second,
 		MaxProcesses:        20,
 		MaxConnections:      100,
 		LatencyWarning:      100.0,
 		LatencyCritical:     200.0,
+		LatencyWindow:       60,
 		PacketLossWarning:   5.0,
 		BandwidthWarning:    80.0,
 		ConnectionTimeout:    5 * time.Second,
@@ -44,23 +139,70 @@ func NewNetworkMonitorCollector() *NetworkMonitorCollector {
 		ShowLatency:         true,
 		ShowBandwidth:       true,
 		ShowPerformance:     true,
+		ShowVPN:             true,
+		ShowConnectivity:    false,
+		ShowGateway:         true,
+		ShowFirewall:        true,
+		ShowProbableCause:   true,
+		ShowPortTraffic:     true,
+		RateUnit:            RateUnitBits,
 		ExportToFile:        true,
 		ExportInterval:      1 * time.Hour,
 		ExportFormat:        "json",
+		PersistHistory:      true,
+		HistorySaveInterval: 1 * time.Minute,
+		HistorySize:         historystore.DefaultBufferSize,
+		HistoryResolution:   0,
 		MinNetworkUsage:     1.0,
 		ProcessNameFilter:   "",
 		InterfaceFilter:     "",
 		ConnectionTypeFilter: "",
 		LatencyTargets:      []string{"8.8.8.8", "1.1.1.1", "google.com"},
+		VPNInterfaces:       []string{},
+		VPNHandshakeStale:   3 * time.Minute,
+		VPNIdleStale:        5 * time.Minute,
+		VPNOverhead:         10.0,
+		VPNBypassThreshold:  1.0,
+		VPNBypassAllowed:    []string{},
+		CaptivePortalURL:    "http://connectivitycheck.gstatic.com/generate_204",
+		ConnectivityCheckInterval: 1 * time.Minute,
+		GatewayCheckInterval: 5 * time.Second,
+		GatewayLossThreshold: 3,
+		GatewayMACAlertTime:  10 * time.Minute,
+		MaxFirewallRules:      10,
+		FirewallCheckInterval: 30 * time.Second,
+		CauseSpikeFactor:     3.0,
+		CauseMinRate:         1024 * 1024,
+		WakeHosts:            map[string]WakeHost{},
+		PingCount:            4,
+		TraceHops:            30,
+		TraceISPHops:         3,
+		ShortLivedConnectionAge: 10 * time.Second,
+		LongLivedConnectionAge:  1 * time.Hour,
+		ConnectionChurnWindow:   1 * time.Minute,
+		AlertRules: map[string]alert.Rule{
+			"latency":     {Hysteresis: 20, RaiseAfter: 5 * time.Second},
+			"packet_loss": {Hysteresis: 2},
+			"bandwidth":   {Hysteresis: 10, RaiseAfter: 10 * time.Second},
+		},
 	}
 
 	return &NetworkMonitorCollector{
 		config:          config,
+		latest:          config,
 		lastTimestamp:   time.Now(),
-		processCache:    make(map[int32]*NetworkProcessInfo),
-		lastProcessTime: make(map[int32]time.Time),
+		processCache:    make(map[procid.Key]*NetworkProcessInfo),
+		lastProcessTime: make(map[procid.Key]time.Time),
+		processBaseline: make(map[procid.Key]float64),
+		vpnLastRecv:     make(map[string]uint64),
+		vpnLastChange:   make(map[string]time.Time),
+		gatewayMACs:     make(map[string]string),
+		connections:     make(map[string]*trackedConnection),
+		alerts:          alert.NewTracker("network"),
+		system:          provider.System(),
+		longHistory: historystore.NewStore(historystore.DefaultTiers()),
 		history: &NetworkUsageHistory{
-			MaxDataPoints:  100,
+			MaxDataPoints:  config.HistorySize,
 			DataPointCount: 0,
 		},
 	}
@@ -69,45 +211,69 @@ func NewNetworkMonitorCollector() *NetworkMonitorCollector {
 // CollectNetworkMonitorData gathers comprehensive network monitoring data
 // This is the main method that collects all available network metrics
 func (collector *NetworkMonitorCollector) CollectNetworkMonitorData() (*NetworkMonitorData, error) {
+	collector.collectMutex.Lock()
+	defer collector.collectMutex.Unlock()
+	collector.adoptConfig()
 	data := &NetworkMonitorData{
 		Timestamp:       time.Now(),
 		RefreshInterval: collector.config.RefreshInterval,
 		IsMonitoring:    true,
+		SectionErrors:   make(partial.Errors),
+	}
+
+	// Synthetic data for demos and UI work replaces every system query
+	if collector.simulator != nil {
+		collector.collectSimulatedData(data)
+		collector.trackLatency(data)
+		if collector.config.ShowBandwidth {
+			collector.collectBandwidthInfo(data)
+		}
+		if collector.config.ShowPerformance {
+			collector.calculatePerformanceMetrics(data)
+		}
+		collector.analyzeNetworkStatus(data)
+		collector.updateHistory(data)
+		collector.applyRateUnit(data)
+		return data, nil
 	}
 
 	// Collect interface information
+	// Sections that fail are recorded and shown as unavailable instead of aborting the snapshot
 	if collector.config.ShowInterfaces {
-		if err := collector.collectInterfaceInfo(data); err != nil {
-			return nil, fmt.Errorf("failed to collect interface info: %w", err)
-		}
+		data.SectionErrors.Add("interfaces", collector.collectInterfaceInfo(data))
 	}
 
 	// Collect I/O statistics
 	if collector.config.ShowIO {
-		if err := collector.collectIOInfo(data); err != nil {
-			return nil, fmt.Errorf("failed to collect I/O info: %w", err)
-		}
+		data.SectionErrors.Add("io", collector.collectIOInfo(data))
 	}
 
 	// Collect connection information
 	if collector.config.ShowConnections {
-		if err := collector.collectConnectionInfo(data); err != nil {
-			return nil, fmt.Errorf("failed to collect connection info: %w", err)
+		data.SectionErrors.Add("connections", collector.collectConnectionInfo(data))
+	}
+
+	// Measure the traffic of each connection just collected, for the port class breakdown and the process rates
+	collector.socketRates = nil
+	measureCause := collector.config.ShowProbableCause && collector.config.ShowProcesses
+	if (collector.config.ShowPortTraffic || measureCause) && collector.config.ShowConnections && !collector.idle {
+		if _, failed := data.SectionErrors.Get("connections"); !failed {
+			collector.collectSocketRates()
+			if collector.config.ShowPortTraffic {
+				collector.collectPortTraffic(data)
+			}
 		}
 	}
 
 	// Collect process information
-	if collector.config.ShowProcesses {
-		if err := collector.collectProcessInfo(data); err != nil {
-			return nil, fmt.Errorf("failed to collect process info: %w", err)
-		}
+	if collector.config.ShowProcesses && !collector.idle {
+		data.SectionErrors.Add("processes", collector.collectProcessInfo(data))
 	}
 
 	// Collect latency information
 	if collector.config.ShowLatency {
-		if err := collector.collectLatencyInfo(data); err != nil {
-			return nil, fmt.Errorf("failed to collect latency info: %w", err)
-		}
+		data.SectionErrors.Add("latency", collector.collectLatencyInfo(data))
+		collector.trackLatency(data)
 	}
 
 	// Collect bandwidth information
@@ -115,6 +281,26 @@ func (collector *NetworkMonitorCollector) CollectNetworkMonitorData() (*NetworkM
 		collector.collectBandwidthInfo(data)
 	}
 
+	// Detect proxies and captive portals
+	if collector.config.ShowConnectivity {
+		collector.collectConnectivityInfo(data)
+	}
+
+	// Check default gateway reachability and MAC address
+	if collector.config.ShowGateway {
+		collector.collectGatewayInfo(data)
+	}
+
+	// Snapshot firewall rule counters
+	if collector.config.ShowFirewall {
+		collector.collectFirewallInfo(data)
+	}
+
+	// Collect VPN/tunnel health
+	if collector.config.ShowVPN {
+		collector.collectVPNInfo(data)
+	}
+
 	// Calculate performance metrics
 	if collector.config.ShowPerformance {
 		collector.calculatePerformanceMetrics(data)
@@ -126,17 +312,54 @@ func (collector *NetworkMonitorCollector) CollectNetworkMonitorData() (*NetworkM
 	// Update history
 	collector.updateHistory(data)
 
+	// Convert speeds to the configured unit once alerts and history have used them
+	collector.applyRateUnit(data)
+
 	return data, nil
 }
 
+// applyRateUnit converts every speed and bandwidth value from Mbit/s to the configured rate unit and records the unit
+// Alerts, thresholds and history all work in Mbit/s, so this runs last
+func (collector *NetworkMonitorCollector) applyRateUnit(data *NetworkMonitorData) {
+	unit := normalizeRateUnit(collector.config.RateUnit)
+	data.RateUnit = unit
+	if unit == RateUnitBits {
+		return
+	}
+
+	convert := func(values ...*float64) {
+		for _, value := range values {
+			*value = toRateUnit(*value, unit)
+		}
+	}
+	convert(&data.TotalSendSpeed, &data.TotalRecvSpeed, &data.TotalThroughput)
+	for i := range data.InterfaceIO {
+		convert(&data.InterfaceIO[i].SendSpeed, &data.InterfaceIO[i].RecvSpeed, &data.InterfaceIO[i].TotalSpeed)
+	}
+	for i := range data.TopProcesses {
+		convert(&data.TopProcesses[i].SendSpeed, &data.TopProcesses[i].RecvSpeed, &data.TopProcesses[i].TotalSpeed)
+	}
+	for i := range data.PortTraffic {
+		convert(&data.PortTraffic[i].SendSpeed, &data.PortTraffic[i].RecvSpeed, &data.PortTraffic[i].TotalSpeed)
+	}
+	bandwidth := &data.BandwidthInfo
+	convert(&bandwidth.TotalBandwidth, &bandwidth.UsedBandwidth, &bandwidth.AvailableBandwidth, &bandwidth.PeakUsage, &bandwidth.AverageUsage)
+	if data.VPNTraffic != nil {
+		convert(&data.VPNTraffic.TunnelSpeed, &data.VPNTraffic.PhysicalSpeed, &data.VPNTraffic.DirectSpeed)
+	}
+}
+
 // collectInterfaceInfo gathers network interface information
 func (collector *NetworkMonitorCollector) collectInterfaceInfo(data *NetworkMonitorData) error {
 	// Get network interfaces
-	interfaces, err := netutil.Interfaces()
+	interfaces, err := collector.system.Net.Interfaces()
 	if err != nil {
 		return fmt.Errorf("failed to get network interfaces: %w", err)
 	}
 
+	// Lifetimes are optional extras; interfaces are still listed when they cannot be read
+	lifetimes, _ := addressLifetimes()
+
 	var interfaceInfos []NetworkInterfaceInfo
 
 	for _, iface := range interfaces {
@@ -151,11 +374,9 @@ func (collector *NetworkMonitorCollector) collectInterfaceInfo(data *NetworkMoni
 			continue // Skip interfaces with no addresses
 		}
 
-		var ipAddress, subnetMask string
-		if len(addrs) > 0 {
-			ipAddress = addrs[0].Addr
-			subnetMask = addrs[0].Addr // Simplified
-		}
+		// Every address is kept; the primary one (first IPv4, else the first address) fills the summary fields
+		addresses := collector.buildAddresses(iface.Index, addrs, lifetimes)
+		ipAddress, subnetMask := primaryAddress(addrs, addresses)
 
 		interfaceInfo := NetworkInterfaceInfo{
 			Name:        iface.Name,
@@ -172,6 +393,7 @@ func (collector *NetworkMonitorCollector) collectInterfaceInfo(data *NetworkMoni
 			IsUp:        collector.isInterfaceUp(iface.Flags),
 			IsLoopback:  collector.isLoopbackInterface(iface.Name),
 			IsVirtual:   collector.isVirtualInterface(iface.Name),
+			Addresses:   addresses,
 		}
 
 		interfaceInfos = append(interfaceInfos, interfaceInfo)
@@ -181,10 +403,90 @@ func (collector *NetworkMonitorCollector) collectInterfaceInfo(data *NetworkMoni
 	return nil
 }
 
+// addressLifetime holds the remaining lifetimes of one address
+type addressLifetime struct {
+	permanent bool
+	valid     time.Duration
+	preferred time.Duration
+}
+
+// lifetimeKey identifies an address on a specific interface
+func lifetimeKey(index int, address string) string {
+	return strconv.Itoa(index) + "/" + address
+}
+
+// buildAddresses describes every address of an interface with its prefix, scope and lifetimes
+func (collector *NetworkMonitorCollector) buildAddresses(index int, addrs netutil.InterfaceAddrList, lifetimes map[string]addressLifetime) []NetworkAddressInfo {
+	var addresses []NetworkAddressInfo
+	for _, addr := range addrs {
+		ip, network, err := net.ParseCIDR(addr.Addr)
+		if err != nil {
+			// Some platforms report bare addresses without a prefix
+			ip = net.ParseIP(addr.Addr)
+			if ip == nil {
+				continue
+			}
+		}
+
+		address := NetworkAddressInfo{
+			Address: ip.String(),
+			Family:  "IPv6",
+			Scope:   addressScope(ip),
+		}
+		if ip.To4() != nil {
+			address.Family = "IPv4"
+		}
+		if network != nil {
+			address.PrefixLength, _ = network.Mask.Size()
+			if address.Family == "IPv4" {
+				address.SubnetMask = net.IP(network.Mask).String()
+			}
+		}
+		if lifetime, ok := lifetimes[lifetimeKey(index, address.Address)]; ok {
+			address.LifetimeKnown = true
+			address.Permanent = lifetime.permanent
+			address.ValidLifetime = lifetime.valid
+			address.PreferredLifetime = lifetime.preferred
+		}
+		addresses = append(addresses, address)
+	}
+	return addresses
+}
+
+// primaryAddress picks the address shown in summaries: the first IPv4 address, else the first address
+func primaryAddress(addrs netutil.InterfaceAddrList, addresses []NetworkAddressInfo) (string, string) {
+	if len(addrs) == 0 {
+		return "", ""
+	}
+	for _, address := range addresses {
+		if address.Family == "IPv4" && address.SubnetMask != "" {
+			return address.Address + "/" + strconv.Itoa(address.PrefixLength), address.SubnetMask
+		}
+	}
+	return addrs[0].Addr, ""
+}
+
+// addressScope classifies an address by where it is reachable from
+func addressScope(ip net.IP) string {
+	switch {
+	case ip.IsLoopback():
+		return "host"
+	case ip.IsLinkLocalUnicast():
+		return "link-local"
+	case ip.IsMulticast():
+		return "multicast"
+	case ip.IsPrivate():
+		return "private"
+	case ip.IsGlobalUnicast():
+		return "global"
+	}
+	return "unknown"
+}
+
 // collectIOInfo gathers network I/O statistics
 func (collector *NetworkMonitorCollector) collectIOInfo(data *NetworkMonitorData) error {
 	// Get I/O counters
-	ioCounters, err := netutil.IOCounters(true)
+	ioCounters, err := collector.system.Net.IOCounters(true)
 	if err != nil {
 		return fmt.Errorf("failed to get I/O counters: %w", err)
 	}
@@ -200,8 +502,8 @@ func (collector *NetworkMonitorCollector) collectIOInfo(data *NetworkMonitorData
 		}
 
 		// Calculate speeds (simplified - would need time-based calculation for accurate speeds)
-		sendSpeed := float64(counter.BytesSent) / (1024 * 1024) // Convert to Mbps (simplified)
-		recvSpeed := float64(counter.BytesRecv) / (1024 * 1024) // Convert to Mbps (simplified)
+		sendSpeed := float64(counter.BytesSent) * 8 / 1e6 // Convert to Mbit/s (simplified)
+		recvSpeed := float64(counter.BytesRecv) * 8 / 1e6 // Convert to Mbit/s (simplified)
 		totalSpeed := sendSpeed + recvSpeed
 		utilization := (totalSpeed / 1000.0) * 100 // Simplified utilization calculation
 
@@ -247,36 +549,36 @@ func (collector *NetworkMonitorCollector) collectIOInfo(data *NetworkMonitorData
 // collectConnectionInfo gathers network connection information
 func (collector *NetworkMonitorCollector) collectConnectionInfo(data *NetworkMonitorData) error {
 	// Get network connections
-	connections, err := netutil.Connections("all")
+	connections, err := collector.system.Net.Connections("all")
 	if err != nil {
 		return fmt.Errorf("failed to get network connections: %w", err)
 	}
 
-	var connectionInfos []NetworkConnectionInfo
-	connectionCount := 0
+	var connectionInfos, openConnections []NetworkConnectionInfo
+	processNames := make(map[int32]string)
+	users := make(map[int32]string)
+	now := time.Now()
 
+	// Every connection is tracked, not just the ones shown, so ages and churn cover the whole system
 	for _, conn := range connections {
-		// Limit number of connections
-		if connectionCount >= collector.config.MaxConnections {
-			break
-		}
-
 		// Skip if connection type filter is specified and doesn't match
 		connType := collector.getConnectionType(conn.Type)
 		if collector.config.ConnectionTypeFilter != "" && connType != collector.config.ConnectionTypeFilter {
 			continue
 		}
 
-		// Get process information
-		processName := "Unknown"
-		user := "Unknown"
-		if conn.Pid > 0 {
-			if proc, err := process.NewProcess(conn.Pid); err == nil {
-				if name, err := proc.Name(); err == nil {
-					processName = name
-				}
-				if username, err := proc.Username(); err == nil {
-					user = username
+		// Get process information (once per process)
+		if _, seen := processNames[conn.Pid]; !seen {
+			processNames[conn.Pid] = "Unknown"
+			users[conn.Pid] = "Unknown"
+			if conn.Pid > 0 {
+				if proc, err := collector.system.Proc.NewProcess(conn.Pid); err == nil {
+					if name, err := proc.Name(); err == nil {
+						processNames[conn.Pid] = name
+					}
+					if username, err := proc.Username(); err == nil {
+						users[conn.Pid] = username
+					}
 				}
 			}
 		}
@@ -287,29 +589,139 @@ func (collector *NetworkMonitorCollector) collectConnectionInfo(data *NetworkMon
 			Status:        conn.Status,
 			Type:          connType,
 			PID:           conn.Pid,
-			ProcessName:     processName,
-			User:          user,
+			ProcessName:   processNames[conn.Pid],
+			User:          users[conn.Pid],
 			State:         conn.Status,
 			Family:        collector.getConnectionFamily(conn.Family),
 		}
+		collector.trackConnection(&connectionInfo, now)
+		openConnections = append(openConnections, connectionInfo)
 
-		connectionInfos = append(connectionInfos, connectionInfo)
-		connectionCount++
+		// Limit number of connections
+		if len(connectionInfos) < collector.config.MaxConnections {
+			connectionInfos = append(connectionInfos, connectionInfo)
+		}
 	}
 
 	data.Connections = connectionInfos
+	collector.openConnections = openConnections
+	collector.finishConnectionTracking(data, now)
 	return nil
 }
 
+// trackConnection records that a connection is open in this refresh and fills in its age
+func (collector *NetworkMonitorCollector) trackConnection(connection *NetworkConnectionInfo, now time.Time) {
+	key := fmt.Sprintf("%s|%s|%s|%d", connection.Type, connection.LocalAddress, connection.RemoteAddress, connection.PID)
+	tracked, ok := collector.connections[key]
+	if !ok {
+		tracked = &trackedConnection{
+			firstSeen:     now,
+			preexisting:   !collector.connectionsTracked,
+			processName:   connection.ProcessName,
+			remoteAddress: connection.RemoteAddress,
+		}
+		collector.connections[key] = tracked
+	}
+	tracked.lastSeen = now
+
+	connection.FirstSeen = tracked.firstSeen
+	connection.Age = now.Sub(tracked.firstSeen)
+	connection.AgeLowerBound = tracked.preexisting
+	connection.New = !tracked.preexisting && connection.Age < collector.config.ShortLivedConnectionAge
+	connection.LongLived = connection.Age >= collector.config.LongLivedConnectionAge
+}
+
+// finishConnectionTracking forgets connections that were not seen in this refresh and
+// reports endpoints that keep opening connections which close again within the short-lived age
+func (collector *NetworkMonitorCollector) finishConnectionTracking(data *NetworkMonitorData, now time.Time) {
+	for key, tracked := range collector.connections {
+		if tracked.lastSeen.Equal(now) {
+			continue
+		}
+		delete(collector.connections, key)
+
+		// Listeners have no remote end, and connections open before tracking started have unknown lifetimes
+		if tracked.preexisting || isUnspecifiedEndpoint(tracked.remoteAddress) {
+			continue
+		}
+		if tracked.lastSeen.Sub(tracked.firstSeen) < collector.config.ShortLivedConnectionAge {
+			collector.shortLivedCloses = append(collector.shortLivedCloses, connectionClose{
+				time:          now,
+				processName:   tracked.processName,
+				remoteAddress: tracked.remoteAddress,
+			})
+		}
+	}
+	collector.connectionsTracked = true
+
+	// Keep only closes inside the churn window
+	cutoff := now.Add(-collector.config.ConnectionChurnWindow)
+	recent := collector.shortLivedCloses[:0]
+	for _, closed := range collector.shortLivedCloses {
+		if closed.time.After(cutoff) {
+			recent = append(recent, closed)
+		}
+	}
+	collector.shortLivedCloses = recent
+	data.ShortLivedConnections = len(recent)
+
+	// Group by endpoint; a single short connection is normal, repeated ones are churn
+	counts := make(map[string]*NetworkChurnInfo)
+	for _, closed := range recent {
+		key := closed.processName + "|" + closed.remoteAddress
+		if counts[key] == nil {
+			counts[key] = &NetworkChurnInfo{ProcessName: closed.processName, RemoteAddress: closed.remoteAddress}
+		}
+		counts[key].Count++
+	}
+	var churn []NetworkChurnInfo
+	for _, info := range counts {
+		if info.Count >= 2 {
+			churn = append(churn, *info)
+		}
+	}
+	sort.Slice(churn, func(i, j int) bool {
+		if churn[i].Count != churn[j].Count {
+			return churn[i].Count > churn[j].Count
+		}
+		return churn[i].RemoteAddress < churn[j].RemoteAddress
+	})
+	data.ConnectionChurn = churn
+}
+
+// isUnspecifiedEndpoint returns whether an address:port has no real remote end (listeners, unconnected UDP)
+func isUnspecifiedEndpoint(address string) bool {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return true
+	}
+	return port == "0" || host == "" || net.ParseIP(host) == nil || net.ParseIP(host).IsUnspecified()
+}
+
 // collectProcessInfo gathers top network-consuming processes
 func (collector *NetworkMonitorCollector) collectProcessInfo(data *NetworkMonitorData) error {
 	// Get all processes
-	processes, err := process.Processes()
+	processes, err := collector.system.Proc.Processes()
 	if err != nil {
 		return fmt.Errorf("failed to get processes: %w", err)
 	}
 
 	var networkProcesses []NetworkProcessInfo
+	sampleTime := time.Now()
+	alive := make(map[procid.Key]bool)
+
+	// A process's rate is the traffic of its TCP sockets where their counters were read; elsewhere its disk and
+	// network I/O stands in. Usual rates learned from one measure are no baseline for the other
+	var socketRates map[int32]float64
+	source := CauseSourceIO
+	if collector.socketRates != nil {
+		socketRates = processSocketRates(collector.openConnections, collector.socketRates)
+		source = CauseSourceSockets
+	}
+	if source != collector.causeSource {
+		clear(collector.processBaseline)
+		collector.causeSource = source
+	}
 
 	// Collect network I/O information for each process
 	for _, p := range processes {
@@ -318,6 +730,22 @@ func (collector *NetworkMonitorCollector) collectProcessInfo(data *NetworkMonito
 		if err != nil {
 			continue // Skip processes we can't access
 		}
+		createTime, _ := p.CreateTime()
+		key := procid.New(p.PID(), createTime)
+		alive[key] = true
+
+		// Rate since the previous sample of this process
+		rate := 0.0
+		totalBytes := ioInfo.WriteBytes + ioInfo.ReadBytes
+		if socketRates != nil {
+			rate = socketRates[p.PID()]
+		} else if previous, ok := collector.processCache[key]; ok {
+			previousBytes := previous.BytesSent + previous.BytesRecv
+			elapsed := sampleTime.Sub(collector.lastProcessTime[key]).Seconds()
+			if elapsed > 0 && totalBytes >= previousBytes {
+				rate = float64(totalBytes-previousBytes) / elapsed
+			}
+		}
 
 		// Get process name
 		name, err := p.Name()
@@ -338,12 +766,22 @@ func (collector *NetworkMonitorCollector) collectProcessInfo(data *NetworkMonito
 		}
 
 		// Calculate network metrics
-		sendSpeed := float64(ioInfo.WriteBytes) / (1024 * 1024) // Convert to Mbps (simplified)
-		recvSpeed := float64(ioInfo.ReadBytes) / (1024 * 1024) // Convert to Mbps (simplified)
+		sendSpeed := float64(ioInfo.WriteBytes) * 8 / 1e6 // Convert to Mbit/s (simplified)
+		recvSpeed := float64(ioInfo.ReadBytes) * 8 / 1e6 // Convert to Mbit/s (simplified)
 		totalSpeed := sendSpeed + recvSpeed
 
-		// Filter by minimum network usage
-		if totalSpeed < collector.config.MinNetworkUsage {
+		// Remember every sampled process, filtered or not, for rates and probable cause analysis
+		collector.processCache[key] = &NetworkProcessInfo{
+			PID:       p.PID(),
+			Name:      name,
+			BytesSent: ioInfo.WriteBytes,
+			BytesRecv: ioInfo.ReadBytes,
+			Rate:      rate,
+		}
+		collector.lastProcessTime[key] = sampleTime
+
+		// Filter by minimum network usage (configured in the rate unit)
+		if totalSpeed < fromRateUnit(collector.config.MinNetworkUsage, collector.config.RateUnit) {
 			continue
 		}
 
@@ -355,13 +793,14 @@ func (collector *NetworkMonitorCollector) collectProcessInfo(data *NetworkMonito
 		// Count connections for this process
 		connections := 0
 		for _, conn := range data.Connections {
-			if conn.PID == p.Pid {
+			if conn.PID == p.PID() {
 				connections++
 			}
 		}
 
 		processInfo := NetworkProcessInfo{
-			PID:         p.Pid,
+			PID:         p.PID(),
+			InstanceID:  key.InstanceID(),
 			Name:        name,
 			BytesSent:   ioInfo.WriteBytes,
 			BytesRecv:   ioInfo.ReadBytes,
@@ -371,11 +810,21 @@ func (collector *NetworkMonitorCollector) collectProcessInfo(data *NetworkMonito
 			Connections: connections,
 			Status:      status[0],
 			User:        user,
+			Rate:        rate,
 		}
 
 		networkProcesses = append(networkProcesses, processInfo)
 	}
 
+	// Forget processes that have exited
+	for key := range collector.processCache {
+		if !alive[key] {
+			delete(collector.processCache, key)
+			delete(collector.lastProcessTime, key)
+			delete(collector.processBaseline, key)
+		}
+	}
+
 	// Sort by total network usage
 	sort.Slice(networkProcesses, func(i, j int) bool {
 		return networkProcesses[i].TotalSpeed > networkProcesses[j].TotalSpeed
@@ -417,10 +866,13 @@ func (collector *NetworkMonitorCollector) collectLatencyInfo(data *NetworkMonito
 // collectBandwidthInfo calculates bandwidth usage information
 func (collector *NetworkMonitorCollector) collectBandwidthInfo(data *NetworkMonitorData) {
 	// Calculate bandwidth information
-	totalBandwidth := float64(len(data.Interfaces)) * 1000.0 // Assume 1Gbps per interface
+	totalBandwidth := float64(len(data.Interfaces)) * 1000.0 // Assume 1 Gbit/s per interface
 	usedBandwidth := data.TotalThroughput
 	availableBandwidth := totalBandwidth - usedBandwidth
-	utilization := (usedBandwidth / totalBandwidth) * 100
+	utilization := 0.0
+	if totalBandwidth > 0 {
+		utilization = (usedBandwidth / totalBandwidth) * 100
+	}
 
 	data.BandwidthInfo = NetworkBandwidthInfo{
 		TotalBandwidth:     totalBandwidth,
@@ -458,35 +910,50 @@ func (collector *NetworkMonitorCollector) calculatePerformanceMetrics(data *Netw
 
 // analyzeNetworkStatus analyzes network status and sets alerts
 func (collector *NetworkMonitorCollector) analyzeNetworkStatus(data *NetworkMonitorData) {
-	// Analyze latency
+	rules := collector.config.AlertRules
+
+	// Analyze latency (every target is evaluated so each keeps its own alert state)
 	for _, latency := range data.LatencyInfo {
-		if latency.Latency >= collector.config.LatencyCritical {
+		level := collector.alerts.Evaluate("latency:"+latency.Target, alertLatency(latency),
+			collector.config.LatencyWarning, collector.config.LatencyCritical, rules["latency"], data.Timestamp)
+		if level == alert.LevelCritical {
 			data.NetworkStatus = "Critical"
 			data.HighLatencyWarning = true
-			break
-		} else if latency.Latency >= collector.config.LatencyWarning {
+		} else if level == alert.LevelWarning {
 			data.HighLatencyWarning = true
-			if data.NetworkStatus == "Normal" {
+			if data.NetworkStatus == "" || data.NetworkStatus == "Normal" {
 				data.NetworkStatus = "Warning"
 			}
 		}
 	}
 
+	// Name the likely bandwidth hog during a latency alert, otherwise learn what is normal
+	if collector.config.ShowProbableCause && collector.config.ShowProcesses {
+		if data.HighLatencyWarning {
+			data.ProbableCause = collector.findProbableCause(data)
+		} else {
+			collector.updateCauseBaselines(data)
+		}
+	}
+
 	// Analyze packet loss
 	for _, latency := range data.LatencyInfo {
-		if latency.PacketLoss >= collector.config.PacketLossWarning {
+		level := collector.alerts.Evaluate("packet_loss:"+latency.Target, latency.PacketLoss,
+			collector.config.PacketLossWarning, math.Inf(1), rules["packet_loss"], data.Timestamp)
+		if level > alert.LevelNormal {
 			data.PacketLossWarning = true
-			if data.NetworkStatus == "Normal" {
+			if data.NetworkStatus == "" || data.NetworkStatus == "Normal" {
 				data.NetworkStatus = "Warning"
 			}
-			break
 		}
 	}
 
 	// Analyze bandwidth usage
-	if data.BandwidthInfo.Utilization >= collector.config.BandwidthWarning {
+	bandwidthLevel := collector.alerts.Evaluate("bandwidth", data.BandwidthInfo.Utilization,
+		collector.config.BandwidthWarning, math.Inf(1), rules["bandwidth"], data.Timestamp)
+	if bandwidthLevel > alert.LevelNormal {
 		data.BandwidthWarning = true
-		if data.NetworkStatus == "Normal" {
+		if data.NetworkStatus == "" || data.NetworkStatus == "Normal" {
 			data.NetworkStatus = "Warning"
 		}
 	}
@@ -499,153 +966,1459 @@ func (collector *NetworkMonitorCollector) analyzeNetworkStatus(data *NetworkMoni
 		}
 	}
 
+	// Analyze VPN tunnels
+	for _, tunnel := range data.VPNTunnels {
+		if tunnel.Status != "Up" {
+			data.VPNWarning = true
+			if data.NetworkStatus == "" || data.NetworkStatus == "Normal" {
+				data.NetworkStatus = "Warning"
+			}
+			break
+		}
+	}
+
+	// Analyze traffic bypassing an active VPN
+	if data.VPNTraffic != nil {
+		threshold := fromRateUnit(collector.config.VPNBypassThreshold, collector.config.RateUnit)
+		if len(data.VPNTraffic.BypassConnections) > 0 || (threshold > 0 && data.VPNTraffic.DirectSpeed >= threshold) {
+			data.VPNBypassWarning = true
+			if data.NetworkStatus == "" || data.NetworkStatus == "Normal" {
+				data.NetworkStatus = "Warning"
+			}
+		}
+	}
+
+	// Analyze default gateway loss and MAC changes
+	if data.Gateway.Address != "" {
+		if data.Gateway.ConsecutiveFailures >= collector.config.GatewayLossThreshold {
+			data.GatewayLossWarning = true
+			data.NetworkStatus = "Critical"
+		}
+		for _, change := range data.Gateway.MACChanges {
+			if time.Since(change.Timestamp) < collector.config.GatewayMACAlertTime {
+				data.GatewayMACWarning = true
+				if data.NetworkStatus == "" || data.NetworkStatus == "Normal" {
+					data.NetworkStatus = "Warning"
+				}
+				break
+			}
+		}
+	}
+
+	// Analyze captive portal and explain failures that are not really latency problems
+	if data.CaptivePortal.Detected {
+		data.CaptivePortalWarning = true
+		if data.NetworkStatus == "" || data.NetworkStatus == "Normal" {
+			data.NetworkStatus = "Warning"
+		}
+	}
+	data.StatusExplanation = collector.explainNetworkStatus(data)
+
 	// Set default status if no issues
 	if data.NetworkStatus == "" {
 		data.NetworkStatus = "Normal"
 	}
+
+	// Removed latency targets start fresh if they come back
+	collector.alerts.Forget(data.Timestamp)
+	data.AlertNotes = collector.alerts.Notes()
 }
 
-// updateHistory updates the network usage history
-func (collector *NetworkMonitorCollector) updateHistory(data *NetworkMonitorData) {
-	now := time.Now()
-	
-	// Add new data point
-	collector.history.Timestamps = append(collector.history.Timestamps, now)
-	collector.history.TotalSent = append(collector.history.TotalSent, float64(data.TotalBytesSent))
-	collector.history.TotalRecv = append(collector.history.TotalRecv, float64(data.TotalBytesRecv))
-	collector.history.SendSpeed = append(collector.history.SendSpeed, data.TotalSendSpeed)
-	collector.history.RecvSpeed = append(collector.history.RecvSpeed, data.TotalRecvSpeed)
-	collector.history.Throughput = append(collector.history.Throughput, data.TotalThroughput)
-	collector.history.Latency = append(collector.history.Latency, data.AverageLatency)
-	collector.history.Utilization = append(collector.history.Utilization, data.NetworkUtilization)
+// collectGatewayInfo checks the default gateway and tracks its MAC address over time
+// A MAC change for the same gateway address can mean ARP spoofing (or a replaced router)
+func (collector *NetworkMonitorCollector) collectGatewayInfo(data *NetworkMonitorData) {
+	if !collector.gateway.CheckedAt.IsZero() && time.Since(collector.gateway.CheckedAt) < collector.config.GatewayCheckInterval {
+		data.Gateway = collector.gateway
+		return
+	}
 
-	// Limit history size
-	if len(collector.history.Timestamps) > collector.history.MaxDataPoints {
-		collector.history.Timestamps = collector.history.Timestamps[1:]
-		collector.history.TotalSent = collector.history.TotalSent[1:]
-		collector.history.TotalRecv = collector.history.TotalRecv[1:]
-		collector.history.SendSpeed = collector.history.SendSpeed[1:]
-		collector.history.RecvSpeed = collector.history.RecvSpeed[1:]
-		collector.history.Throughput = collector.history.Throughput[1:]
-		collector.history.Latency = collector.history.Latency[1:]
-		collector.history.Utilization = collector.history.Utilization[1:]
+	address, iface := readDefaultGateway()
+	gateway := collector.gateway
+
+	// A different gateway (new network) starts a fresh failure count
+	if address != gateway.Address {
+		gateway = GatewayInfo{MACChanges: gateway.MACChanges}
+	}
+	gateway.Address = address
+	gateway.Interface = iface
+	gateway.CheckedAt = time.Now()
+
+	if address == "" {
+		collector.gateway = gateway
+		data.Gateway = gateway
+		return
 	}
 
-	collector.history.DataPointCount = len(collector.history.Timestamps)
-}
+	// Ping first so the ARP entry is fresh
+	rtt, pinged, err := pingHost(address)
+	gateway.Method = "ping"
+	gateway.Reachable = pinged
+	gateway.RTT = rtt
+
+	mac, complete := readARPEntry(address)
+	gateway.MACAddress = mac
+	if err != nil || !pinged && complete {
+		// No ping binary, or a router that drops ICMP: a complete ARP entry may be minutes old,
+		// so only a neighbour confirmed just now (or an arping reply) counts as reachable
+		gateway.Method, gateway.Reachable = confirmNeighbour(address, iface)
+	}
 
-// Helper methods
+	if gateway.Reachable {
+		gateway.LastReachable = gateway.CheckedAt
+		gateway.ConsecutiveFailures = 0
+	} else {
+		gateway.ConsecutiveFailures++
+	}
 
-// getInterfaceType returns the interface type based on name
-func (collector *NetworkMonitorCollector) getInterfaceType(name string) string {
-	if name[:2] == "wl" || name[:4] == "wlan" {
-		return "WiFi"
-	} else if name[:2] == "et" || name[:4] == "eth" {
-		return "Ethernet"
-	} else if name[:2] == "lo" {
-		return "Loopback"
-	} else if name[:2] == "vm" || name[:2] == "vb" {
-		return "Virtual"
+	// Track MAC changes for this gateway address
+	if mac != "" {
+		if previous, ok := collector.gatewayMACs[address]; ok && previous != mac {
+			gateway.MACChanges = append(gateway.MACChanges, GatewayMACChange{
+				Timestamp: gateway.CheckedAt,
+				OldMAC:    previous,
+				NewMAC:    mac,
+			})
+			if len(gateway.MACChanges) > 10 {
+				gateway.MACChanges = gateway.MACChanges[len(gateway.MACChanges)-10:]
+			}
+		}
+		collector.gatewayMACs[address] = mac
 	}
-	return "Unknown"
-}
 
-// getInterfaceStatus returns the interface status based on flags
-func (collector *NetworkMonitorCollector) getInterfaceStatus(flags []string) string {
-	for _, flag := range flags {
-		if flag == "up" {
-			return "up"
+	collector.gateway = gateway
+	data.Gateway = gateway
+
+	// Fill in the gateway for the interface that carries the default route
+	for i := range data.Interfaces {
+		if data.Interfaces[i].Name == iface {
+			data.Interfaces[i].Gateway = address
 		}
 	}
-	return "down"
 }
 
-// isInterfaceUp checks if interface is up
-func (collector *NetworkMonitorCollector) isInterfaceUp(flags []string) bool {
-	for _, flag := range flags {
-		if flag == "up" {
-			return true
+// readDefaultGateway returns the default gateway address and the interface it is reached through
+func readDefaultGateway() (string, string) {
+	switch runtime.GOOS {
+	case "windows":
+		output, err := exec.Command("route", "print", "-4", "0.0.0.0").Output()
+		if err != nil {
+			return "", ""
+		}
+		// Network Destination, Netmask, Gateway, Interface, Metric
+		for _, line := range strings.Split(string(output), "\n") {
+			fields := strings.Fields(line)
+			if len(fields) >= 5 && fields[0] == "0.0.0.0" && fields[1] == "0.0.0.0" && net.ParseIP(fields[2]) != nil {
+				return fields[2], fields[3]
+			}
+		}
+	case "darwin":
+		output, err := exec.Command("route", "-n", "get", "default").Output()
+		if err != nil {
+			return "", ""
+		}
+		var address, iface string
+		for _, line := range strings.Split(string(output), "\n") {
+			key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+			if !ok {
+				continue
+			}
+			switch key {
+			case "gateway":
+				address = strings.TrimSpace(value)
+			case "interface":
+				iface = strings.TrimSpace(value)
+			}
+		}
+		return address, iface
+	default:
+		content, err := os.ReadFile("/proc/net/route")
+		if err != nil {
+			return "", ""
+		}
+		// Iface, Destination, Gateway (little-endian hex), ...
+		for _, line := range strings.Split(string(content), "\n")[1:] {
+			fields := strings.Fields(line)
+			if len(fields) < 3 || fields[1] != "00000000" {
+				continue
+			}
+			value, err := strconv.ParseUint(fields[2], 16, 32)
+			if err != nil || value == 0 {
+				continue
+			}
+			ip := net.IPv4(byte(value), byte(value>>8), byte(value>>16), byte(value>>24))
+			return ip.String(), fields[0]
 		}
 	}
-	return false
+	return "", ""
 }
 
-// isLoopbackInterface checks if interface is loopback
-func (collector *NetworkMonitorCollector) isLoopbackInterface(name string) bool {
-	return name == "lo" || name[:2] == "lo"
-}
+// macPattern matches MAC addresses written with colons or dashes, with or without leading zeros
+var macPattern = regexp.MustCompile(`([0-9a-fA-F]{1,2}[:-]){5}[0-9a-fA-F]{1,2}`)
 
-// getConnectionType returns the connection type as string
-func (collector *NetworkMonitorCollector) getConnectionType(connType uint32) string {
-	switch connType {
-	case 1:
-		return "TCP"
-	case 2:
-		return "UDP"
-	case 3:
-		return "Unix"
-	default:
-		return "Unknown"
+// readARPEntry returns the MAC address of an IP from the ARP table and whether the entry is complete
+func readARPEntry(address string) (string, bool) {
+	if runtime.GOOS == "linux" {
+		content, err := os.ReadFile("/proc/net/arp")
+		if err != nil {
+			return "", false
+		}
+		// IP address, HW type, Flags, HW address, Mask, Device
+		for _, line := range strings.Split(string(content), "\n")[1:] {
+			fields := strings.Fields(line)
+			if len(fields) >= 4 && fields[0] == address {
+				complete := fields[2] != "0x0"
+				if !complete {
+					return "", false
+				}
+				return normalizeMAC(fields[3]), true
+			}
+		}
+		return "", false
+	}
+
+	args := []string{"-n", address}
+	if runtime.GOOS == "windows" {
+		args = []string{"-a", address}
+	}
+	output, err := exec.Command("arp", args...).Output()
+	if err != nil {
+		return "", false
 	}
+	mac := macPattern.FindString(string(output))
+	if mac == "" {
+		return "", false
+	}
+	return normalizeMAC(mac), true
 }
 
-// getConnectionFamily returns the connection family as string
-func (collector *NetworkMonitorCollector) getConnectionFamily(family uint32) string {
-	switch family {
-	case 2:
-		return "IPv4"
-	case 10:
-		return "IPv6"
-	case 1:
-		return "Unix"
-	default:
-		return "Unknown"
+// confirmNeighbour checks whether the gateway answered ARP just now and returns how it was decided
+// Linux reports the neighbour state: only REACHABLE means a recent reply, while STALE or DELAY
+// entries are kept for minutes after the router went away. Otherwise an arping reply is required;
+// without arping the gateway stays unreachable
+func confirmNeighbour(address, iface string) (string, bool) {
+	if runtime.GOOS == "linux" {
+		args := []string{"-4", "neigh", "show", address}
+		if iface != "" {
+			args = append(args, "dev", iface)
+		}
+		if output, err := exec.Command("ip", args...).Output(); err == nil && neighbourReachable(string(output)) {
+			return "arp", true
+		}
+	}
+
+	args := []string{"-c", "1"}
+	if iface != "" && runtime.GOOS == "linux" {
+		args = append(args, "-I", iface)
 	}
+	if err := exec.Command("arping", append(args, address)...).Run(); err != nil {
+		return "arp", false
+	}
+	return "arping", true
 }
 
-// isVirtualInterface checks if an interface is virtual
-func (collector *NetworkMonitorCollector) isVirtualInterface(name string) bool {
-	virtualPrefixes := []string{"vm", "vb", "veth", "docker", "br-", "virbr"}
-	for _, prefix := range virtualPrefixes {
-		if len(name) >= len(prefix) && name[:len(prefix)] == prefix {
+// neighbourReachable reports whether `ip neigh show` output lists the entry as REACHABLE
+// e.g. "192.168.1.1 dev eth0 lladdr aa:bb:cc:dd:ee:ff REACHABLE"
+func neighbourReachable(output string) bool {
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) > 0 && fields[len(fields)-1] == "REACHABLE" {
 			return true
 		}
 	}
 	return false
 }
 
-// measureLatency measures latency to a target (simplified implementation)
-func (collector *NetworkMonitorCollector) measureLatency(target string) (float64, float64, string) {
-	// Simplified latency measurement
-	// In a real implementation, this would use ping or similar
-	start := time.Now()
-	conn, err := net.DialTimeout("tcp", target+":80", collector.config.ConnectionTimeout)
-	if err != nil {
-		return 0.0, 100.0, "Failed"
+// normalizeMAC converts a MAC address to lowercase, colon-separated, zero-padded form
+func normalizeMAC(mac string) string {
+	parts := strings.FieldsFunc(strings.ToLower(mac), func(r rune) bool {
+		return r == ':' || r == '-'
+	})
+	for i, part := range parts {
+		if len(part) == 1 {
+			parts[i] = "0" + part
+		}
 	}
-	conn.Close()
-	latency := float64(time.Since(start).Nanoseconds()) / 1000000.0 // Convert to milliseconds
-	
-	if latency < collector.config.LatencyWarning {
-		return latency, 0.0, "Good"
-	} else if latency < collector.config.LatencyCritical {
-		return latency, 0.0, "Warning"
+	return strings.Join(parts, ":")
+}
+
+// pingPattern extracts the round-trip time from ping output (time=1.23 ms, time<1ms)
+var pingPattern = regexp.MustCompile(`time[=<]\s*([\d.]+)\s*ms`)
+
+// pingHost sends a single ping and returns the round-trip time in milliseconds
+// The error is only set when ping could not be run at all
+func pingHost(address string) (float64, bool, error) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "windows":
+		cmd = exec.Command("ping", "-n", "1", "-w", "1000", address)
+	case "darwin":
+		cmd = exec.Command("ping", "-c", "1", "-t", "1", address)
+	default:
+		cmd = exec.Command("ping", "-c", "1", "-W", "1", address)
+	}
+
+	output, err := cmd.Output()
+	if err != nil {
+		if _, ok := err.(*exec.ExitError); ok {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+
+	// Windows ping exits 0 on "Destination host unreachable", so require a reply time
+	match := pingPattern.FindStringSubmatch(string(output))
+	if match == nil {
+		return 0, false, nil
+	}
+	rtt, _ := strconv.ParseFloat(match[1], 64)
+	return rtt, true, nil
+}
+
+// findProbableCause picks the process whose traffic spiked furthest above its usual rate
+func (collector *NetworkMonitorCollector) findProbableCause(data *NetworkMonitorData) *NetworkProbableCause {
+	var cause *NetworkProbableCause
+	var totalRate, bestRatio float64
+
+	for key, info := range collector.processCache {
+		if info.Rate <= 0 {
+			continue
+		}
+		totalRate += info.Rate
+
+		if info.Rate < collector.config.CauseMinRate {
+			continue
+		}
+		baseline := collector.processBaseline[key]
+		if baseline > 0 && info.Rate < baseline*collector.config.CauseSpikeFactor {
+			continue
+		}
+
+		// A process with no usual traffic counts as an unbounded spike; rank those by rate
+		ratio := info.Rate
+		if baseline > 0 {
+			ratio = info.Rate / baseline
+		}
+		if cause == nil || ratio > bestRatio || (ratio == bestRatio && info.Rate > cause.Rate) {
+			bestRatio = ratio
+			cause = &NetworkProbableCause{
+				PID:          key.PID,
+				InstanceID:   key.InstanceID(),
+				Name:         info.Name,
+				Rate:         info.Rate,
+				BaselineRate: baseline,
+				Source:       collector.causeSource,
+			}
+		}
+	}
+
+	if cause == nil {
+		return nil
+	}
+	if totalRate > 0 {
+		cause.TrafficShare = cause.Rate / totalRate * 100
+	}
+	cause.Latency = averageLatency(data)
+	cause.BaselineLatency = collector.latencyBaseline
+	return cause
+}
+
+// updateCauseBaselines folds the current process rates and latency into their usual values
+func (collector *NetworkMonitorCollector) updateCauseBaselines(data *NetworkMonitorData) {
+	const weight = 0.2 // Exponential moving average weight of the newest sample
+
+	for key, info := range collector.processCache {
+		baseline, ok := collector.processBaseline[key]
+		if !ok {
+			collector.processBaseline[key] = info.Rate
+			continue
+		}
+		collector.processBaseline[key] = baseline + weight*(info.Rate-baseline)
+	}
+
+	if latency := averageLatency(data); latency > 0 {
+		if collector.latencyBaseline == 0 {
+			collector.latencyBaseline = latency
+		} else {
+			collector.latencyBaseline += weight * (latency - collector.latencyBaseline)
+		}
+	}
+}
+
+// averageLatency returns the mean latency of the successful latency probes
+func averageLatency(data *NetworkMonitorData) float64 {
+	var total float64
+	count := 0
+	for _, latency := range data.LatencyInfo {
+		if latency.Status != "Failed" {
+			total += latency.Latency
+			count++
+		}
+	}
+	if count == 0 {
+		return 0
+	}
+	return total / float64(count)
+}
+
+// collectFirewallInfo reads firewall rule counters and keeps the top rules by packet hits
+func (collector *NetworkMonitorCollector) collectFirewallInfo(data *NetworkMonitorData) {
+	if !collector.firewall.CheckedAt.IsZero() && time.Since(collector.firewall.CheckedAt) < collector.config.FirewallCheckInterval {
+		data.Firewall = collector.firewall
+		return
+	}
+
+	firewall := readFirewallCounters()
+	firewall.CheckedAt = time.Now()
+
+	for _, rule := range firewall.Rules {
+		if rule.Dropped {
+			firewall.DroppedPackets += rule.Packets
+			firewall.DroppedBytes += rule.Bytes
+		}
+	}
+
+	// Counters only grow, unless they were reset (e.g. rules reloaded)
+	previous := collector.firewall
+	if previous.Available && previous.Backend == firewall.Backend && firewall.DroppedPackets >= previous.DroppedPackets {
+		firewall.RecentDrops = firewall.DroppedPackets - previous.DroppedPackets
+	}
+
+	sort.Slice(firewall.Rules, func(i, j int) bool {
+		return firewall.Rules[i].Packets > firewall.Rules[j].Packets
+	})
+	if len(firewall.Rules) > collector.config.MaxFirewallRules {
+		firewall.Rules = firewall.Rules[:collector.config.MaxFirewallRules]
+	}
+
+	collector.firewall = firewall
+	data.Firewall = firewall
+}
+
+// readFirewallCounters reads rule counters from the platform firewall
+func readFirewallCounters() FirewallInfo {
+	switch runtime.GOOS {
+	case "windows":
+		return readWindowsFirewallLog()
+	case "darwin":
+		return readPFCounters()
+	default:
+		// iptables-save also covers iptables-nft; fall back to native nftables rulesets
+		info := readIptablesCounters()
+		if info.Available {
+			return info
+		}
+		nftInfo := readNftablesCounters()
+		if nftInfo.Available {
+			return nftInfo
+		}
+		info.Error = info.Error + "; " + nftInfo.Error
+		return info
+	}
+}
+
+// isDropAction reports whether a firewall rule target discards traffic
+func isDropAction(action string) bool {
+	switch strings.ToLower(action) {
+	case "drop", "reject", "block", "deny":
+		return true
+	}
+	return false
+}
+
+// firewallCommandError describes why a firewall tool produced no counters
+func firewallCommandError(tool string, err error) string {
+	if _, ok := err.(*exec.ExitError); ok {
+		return tool + " failed (root privileges are required to read counters)"
+	}
+	return tool + " not available"
+}
+
+// iptablesCounterPattern matches "[packets:bytes]" counters in iptables-save -c output
+var iptablesCounterPattern = regexp.MustCompile(`^\[(\d+):(\d+)\]\s+`)
+
+// readIptablesCounters parses iptables-save -c output, including chain policy counters
+func readIptablesCounters() FirewallInfo {
+	info := FirewallInfo{Backend: "iptables"}
+
+	output, err := exec.Command("iptables-save", "-c").Output()
+	if err != nil {
+		info.Error = firewallCommandError("iptables-save", err)
+		return info
+	}
+
+	table := ""
+	for _, line := range strings.Split(string(output), "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "*"):
+			table = line[1:]
+		case strings.HasPrefix(line, ":"):
+			// :INPUT DROP [12:720] - built-in chain policy
+			fields := strings.Fields(line[1:])
+			if len(fields) < 3 || fields[1] == "-" {
+				continue
+			}
+			packets, bytes := parseIptablesCounter(fields[2])
+			info.Rules = append(info.Rules, FirewallRuleInfo{
+				Table:   table,
+				Chain:   fields[0],
+				Rule:    "policy " + fields[1],
+				Action:  fields[1],
+				Packets: packets,
+				Bytes:   bytes,
+				Dropped: isDropAction(fields[1]),
+			})
+		case strings.HasPrefix(line, "["):
+			// [5:300] -A INPUT -p tcp --dport 22 -j ACCEPT
+			match := iptablesCounterPattern.FindStringSubmatch(line)
+			if match == nil {
+				continue
+			}
+			spec := strings.TrimPrefix(line[len(match[0]):], "-A ")
+			fields := strings.Fields(spec)
+			if len(fields) == 0 {
+				continue
+			}
+			rule := FirewallRuleInfo{
+				Table: table,
+				Chain: fields[0],
+				Rule:  strings.Join(fields[1:], " "),
+			}
+			rule.Packets, _ = strconv.ParseUint(match[1], 10, 64)
+			rule.Bytes, _ = strconv.ParseUint(match[2], 10, 64)
+			for i := 1; i < len(fields)-1; i++ {
+				if fields[i] == "-j" || fields[i] == "-g" {
+					rule.Action = fields[i+1]
+				}
+			}
+			rule.Dropped = isDropAction(rule.Action)
+			info.Rules = append(info.Rules, rule)
+		}
+	}
+
+	info.Available = true
+	return info
+}
+
+// parseIptablesCounter parses a "[packets:bytes]" counter
+func parseIptablesCounter(counter string) (uint64, uint64) {
+	packetsText, bytesText, ok := strings.Cut(strings.Trim(counter, "[]"), ":")
+	if !ok {
+		return 0, 0
+	}
+	packets, _ := strconv.ParseUint(packetsText, 10, 64)
+	bytes, _ := strconv.ParseUint(bytesText, 10, 64)
+	return packets, bytes
+}
+
+// nftCounterPattern matches the counter statement of an nftables rule
+var nftCounterPattern = regexp.MustCompile(`counter packets (\d+) bytes (\d+)`)
+
+// readNftablesCounters parses nft list ruleset output; only rules with a counter statement are reported
+func readNftablesCounters() FirewallInfo {
+	info := FirewallInfo{Backend: "nftables"}
+
+	output, err := exec.Command("nft", "list", "ruleset").Output()
+	if err != nil {
+		info.Error = firewallCommandError("nft", err)
+		return info
+	}
+
+	table, chain := "", ""
+	for _, line := range strings.Split(string(output), "\n") {
+		line = strings.TrimSpace(line)
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) >= 3 && fields[0] == "table":
+			table = fields[1] + " " + fields[2]
+		case len(fields) >= 2 && fields[0] == "chain":
+			chain = fields[1]
+		default:
+			match := nftCounterPattern.FindStringSubmatch(line)
+			if match == nil {
+				continue
+			}
+			rule := FirewallRuleInfo{
+				Table: table,
+				Chain: chain,
+				Rule:  strings.TrimSpace(strings.Replace(line, match[0], "", 1)),
+			}
+			rule.Packets, _ = strconv.ParseUint(match[1], 10, 64)
+			rule.Bytes, _ = strconv.ParseUint(match[2], 10, 64)
+			if len(fields) > 0 {
+				rule.Action = fields[len(fields)-1]
+			}
+			rule.Dropped = isDropAction(rule.Action)
+			info.Rules = append(info.Rules, rule)
+		}
+	}
+
+	info.Available = true
+	return info
+}
+
+// pfCounterPattern matches the statistics line printed after each rule by pfctl -v
+var pfCounterPattern = regexp.MustCompile(`Packets:\s*(\d+)\s+Bytes:\s*(\d+)`)
+
+// readPFCounters parses pfctl -v -s rules output on macOS
+func readPFCounters() FirewallInfo {
+	info := FirewallInfo{Backend: "pf"}
+
+	output, err := exec.Command("pfctl", "-v", "-s", "rules").Output()
+	if err != nil {
+		info.Error = firewallCommandError("pfctl", err)
+		return info
+	}
+
+	var current *FirewallRuleInfo
+	for _, line := range strings.Split(string(output), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		if match := pfCounterPattern.FindStringSubmatch(trimmed); match != nil {
+			if current != nil {
+				current.Packets, _ = strconv.ParseUint(match[1], 10, 64)
+				current.Bytes, _ = strconv.ParseUint(match[2], 10, 64)
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "[") {
+			continue
+		}
+		// New rule, e.g. "block drop in quick on en0 proto tcp from any to any port 23"
+		fields := strings.Fields(trimmed)
+		info.Rules = append(info.Rules, FirewallRuleInfo{
+			Table:   "pf",
+			Rule:    trimmed,
+			Action:  fields[0],
+			Dropped: isDropAction(fields[0]),
+		})
+		current = &info.Rules[len(info.Rules)-1]
+	}
+
+	info.Available = true
+	return info
+}
+
+// readWindowsFirewallLog summarizes dropped packets from the Windows Firewall log
+// Windows Firewall has no per-rule counters, so drops are grouped by protocol and destination port
+func readWindowsFirewallLog() FirewallInfo {
+	info := FirewallInfo{Backend: "Windows Firewall"}
+
+	logPath := filepath.Join(os.Getenv("SystemRoot"), "System32", "LogFiles", "Firewall", "pfirewall.log")
+	content, err := os.ReadFile(logPath)
+	if err != nil {
+		info.Error = "firewall log not readable (enable logging of dropped packets and run as Administrator)"
+		return info
+	}
+
+	// #Fields: date time action protocol src-ip dst-ip src-port dst-port size ...
+	groups := make(map[string]*FirewallRuleInfo)
+	for _, line := range strings.Split(string(content), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 9 || strings.HasPrefix(fields[0], "#") || fields[2] != "DROP" {
+			continue
+		}
+		key := fields[3] + " dport " + fields[7]
+		rule, ok := groups[key]
+		if !ok {
+			rule = &FirewallRuleInfo{
+				Table:   "pfirewall.log",
+				Chain:   "DROP",
+				Rule:    key,
+				Action:  "DROP",
+				Dropped: true,
+			}
+			groups[key] = rule
+		}
+		rule.Packets++
+		if size, err := strconv.ParseUint(fields[8], 10, 64); err == nil {
+			rule.Bytes += size
+		}
+	}
+
+	for _, rule := range groups {
+		info.Rules = append(info.Rules, *rule)
+	}
+	info.Available = true
+	return info
+}
+
+// collectConnectivityInfo detects the configured proxy and probes for a captive portal
+// Results are cached for ConnectivityCheckInterval
+func (collector *NetworkMonitorCollector) collectConnectivityInfo(data *NetworkMonitorData) {
+	if collector.connectivityCheck.IsZero() || time.Since(collector.connectivityCheck) >= collector.config.ConnectivityCheckInterval {
+		collector.proxyInfo = detectProxy()
+		collector.captivePortal = collector.probeCaptivePortal(collector.proxyInfo)
+		collector.connectivityCheck = time.Now()
+	}
+
+	data.Proxy = collector.proxyInfo
+	data.CaptivePortal = collector.captivePortal
+}
+
+// probeCaptivePortal requests a URL that answers 204 No Content on an open network
+// Portals intercept the request and answer with a redirect or their own sign-in page. The probe goes
+// out directly: through a proxy, the proxy's own answers (407 for missing credentials, error pages)
+// would look like a portal. With a proxy configured, the probe is repeated through it and reported
+// separately
+func (collector *NetworkMonitorCollector) probeCaptivePortal(proxy NetworkProxyInfo) CaptivePortalInfo {
+	info := CaptivePortalInfo{
+		URL:       collector.config.CaptivePortalURL,
+		CheckedAt: time.Now(),
+	}
+
+	statusCode, location, err := collector.probeURL(info.URL, nil)
+	if err != nil {
+		info.Error = err.Error()
+	} else {
+		info.Reachable = true
+		info.StatusCode = statusCode
+
+		switch {
+		case statusCode == http.StatusNoContent:
+			// Open internet access
+		case statusCode == http.StatusProxyAuthRequired:
+			// A transparent proxy wants credentials; signing in to a portal would not help
+			info.ProxyStatus = ProxyAuthRequired
+		case statusCode >= 300 && statusCode < 400:
+			info.Detected = true
+			info.RedirectURL = location
+		default:
+			// Anything else means something in between answered for the real server
+			info.Detected = true
+		}
+	}
+
+	proxyAddress := proxy.HTTPProxy
+	if strings.HasPrefix(info.URL, "https:") {
+		proxyAddress = proxy.HTTPSProxy
+	}
+	if proxyAddress == "" {
+		return info
+	}
+	if !strings.Contains(proxyAddress, "://") {
+		proxyAddress = "http://" + proxyAddress
+	}
+	proxyURL, err := url.Parse(proxyAddress)
+	if err != nil {
+		info.ProxyStatus = ProxyFailed
+		info.ProxyError = fmt.Sprintf("invalid proxy address: %v", err)
+		return info
+	}
+
+	statusCode, _, err = collector.probeURL(info.URL, proxyURL)
+	switch {
+	case err != nil:
+		info.ProxyStatus = ProxyFailed
+		info.ProxyError = err.Error()
+	case statusCode == http.StatusNoContent:
+		info.ProxyStatus = ProxyReachable
+	case statusCode == http.StatusProxyAuthRequired:
+		info.ProxyStatus = ProxyAuthRequired
+	default:
+		info.ProxyStatus = ProxyFailed
+		info.ProxyError = fmt.Sprintf("the proxy answered HTTP %d instead of 204", statusCode)
+	}
+	return info
+}
+
+// probeURL requests address without following redirects and returns the status code and redirect target
+// It connects through proxy, or directly when proxy is nil, whatever HTTP_PROXY and HTTPS_PROXY say
+func (collector *NetworkMonitorCollector) probeURL(address string, proxy *url.URL) (int, string, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	if proxy != nil {
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+	defer transport.CloseIdleConnections()
+
+	client := &http.Client{
+		Transport: transport,
+		Timeout:   collector.config.ConnectionTimeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	resp, err := client.Get(address)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode, resp.Header.Get("Location"), nil
+}
+
+// explainNetworkStatus returns a plain explanation when failures have a known cause
+func (collector *NetworkMonitorCollector) explainNetworkStatus(data *NetworkMonitorData) string {
+	if data.CaptivePortal.Detected {
+		if data.CaptivePortal.RedirectURL != "" {
+			return fmt.Sprintf("Captive portal detected (hotel/airport Wi-Fi): sign in at %s. Latency checks fail until you do.", data.CaptivePortal.RedirectURL)
+		}
+		return fmt.Sprintf("Captive portal detected (hotel/airport Wi-Fi): the connectivity check returned HTTP %d instead of 204. Open a browser to sign in.", data.CaptivePortal.StatusCode)
+	}
+
+	// Only explain when every latency probe failed
+	if len(data.LatencyInfo) == 0 {
+		return ""
+	}
+	for _, latency := range data.LatencyInfo {
+		if latency.Status != "Failed" {
+			return ""
+		}
+	}
+
+	if data.GatewayLossWarning {
+		return fmt.Sprintf("The default gateway %s is not responding; the problem is on the local network (cable, Wi-Fi or router).", data.Gateway.Address)
+	}
+	if data.CaptivePortal.ProxyStatus == ProxyAuthRequired {
+		return "The proxy requires authentication (HTTP 407): add your credentials to the proxy settings. This is not a captive portal."
+	}
+	if data.Proxy.Enabled {
+		if data.CaptivePortal.ProxyStatus == ProxyReachable {
+			return "Direct connections are blocked but the proxy works; latency checks do not go through the proxy."
+		}
+		return "Direct connections fail and the proxy is configured; check that the proxy is reachable."
+	}
+	if data.CaptivePortal.Error != "" {
+		return "No internet connectivity: " + data.CaptivePortal.Error
+	}
+	return ""
+}
+
+// detectProxy reads the proxy configuration from the environment, then from the desktop/OS settings
+func detectProxy() NetworkProxyInfo {
+	info := NetworkProxyInfo{
+		HTTPProxy:  firstEnv("HTTP_PROXY", "http_proxy", "ALL_PROXY", "all_proxy"),
+		HTTPSProxy: firstEnv("HTTPS_PROXY", "https_proxy", "ALL_PROXY", "all_proxy"),
+		NoProxy:    firstEnv("NO_PROXY", "no_proxy"),
+	}
+	if info.HTTPProxy != "" || info.HTTPSProxy != "" {
+		info.Enabled = true
+		info.Source = "Environment"
+		return info
+	}
+
+	switch runtime.GOOS {
+	case "windows":
+		info = detectWindowsProxy()
+	case "darwin":
+		info = detectMacProxy()
+	default:
+		info = detectGnomeProxy()
+	}
+
+	// A source is only meaningful when it actually configures a proxy
+	if !info.Enabled {
+		info.Source = ""
+	}
+	return info
+}
+
+// detectWindowsProxy reads the WinINet proxy settings from the registry
+func detectWindowsProxy() NetworkProxyInfo {
+	info := NetworkProxyInfo{Source: "Windows"}
+
+	output, err := exec.Command("reg", "query", `HKCU\Software\Microsoft\Windows\CurrentVersion\Internet Settings`).Output()
+	if err != nil {
+		return info
+	}
+
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		value := strings.Join(fields[2:], " ")
+		switch fields[0] {
+		case "ProxyEnable":
+			info.Enabled = value == "0x1"
+		case "ProxyServer":
+			info.HTTPProxy = value
+			info.HTTPSProxy = value
+		case "ProxyOverride":
+			info.NoProxy = value
+		case "AutoConfigURL":
+			info.AutoConfigURL = value
+		}
+	}
+
+	if info.AutoConfigURL != "" {
+		info.Enabled = true
+	}
+	return info
+}
+
+// detectMacProxy reads the system proxy settings from scutil
+func detectMacProxy() NetworkProxyInfo {
+	info := NetworkProxyInfo{Source: "macOS"}
+
+	output, err := exec.Command("scutil", "--proxy").Output()
+	if err != nil {
+		return info
+	}
+
+	values := make(map[string]string)
+	for _, line := range strings.Split(string(output), "\n") {
+		key, value, ok := strings.Cut(line, " : ")
+		if ok {
+			values[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+
+	if values["HTTPEnable"] == "1" {
+		info.HTTPProxy = values["HTTPProxy"] + ":" + values["HTTPPort"]
+	}
+	if values["HTTPSEnable"] == "1" {
+		info.HTTPSProxy = values["HTTPSProxy"] + ":" + values["HTTPSPort"]
+	}
+	if values["ProxyAutoConfigEnable"] == "1" {
+		info.AutoConfigURL = values["ProxyAutoConfigURLString"]
+	}
+	info.Enabled = info.HTTPProxy != "" || info.HTTPSProxy != "" || info.AutoConfigURL != ""
+	return info
+}
+
+// detectGnomeProxy reads the GNOME desktop proxy settings
+func detectGnomeProxy() NetworkProxyInfo {
+	info := NetworkProxyInfo{Source: "GNOME"}
+
+	mode := gsettingsValue("org.gnome.system.proxy", "mode")
+	switch mode {
+	case "manual":
+		for _, scheme := range []string{"http", "https"} {
+			host := gsettingsValue("org.gnome.system.proxy."+scheme, "host")
+			port := gsettingsValue("org.gnome.system.proxy."+scheme, "port")
+			if host == "" {
+				continue
+			}
+			if scheme == "http" {
+				info.HTTPProxy = host + ":" + port
+			} else {
+				info.HTTPSProxy = host + ":" + port
+			}
+		}
+	case "auto":
+		info.AutoConfigURL = gsettingsValue("org.gnome.system.proxy", "autoconfig-url")
+	}
+
+	info.Enabled = info.HTTPProxy != "" || info.HTTPSProxy != "" || info.AutoConfigURL != ""
+	return info
+}
+
+// gsettingsValue returns a gsettings key with GVariant string quoting removed
+func gsettingsValue(schema, key string) string {
+	output, err := exec.Command("gsettings", "get", schema, key).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.Trim(strings.TrimSpace(string(output)), "'")
+}
+
+// firstEnv returns the first non-empty environment variable among names
+func firstEnv(names ...string) string {
+	for _, name := range names {
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// collectVPNInfo detects WireGuard, OpenVPN and IPsec interfaces and checks their health
+// A tunnel is stale when its WireGuard handshake is too old or, for other tunnel types,
+// when nothing was received for VPNIdleStale
+func (collector *NetworkMonitorCollector) collectVPNInfo(data *NetworkMonitorData) {
+	interfaces, err := collector.system.Net.Interfaces()
+	if err != nil {
+		return
+	}
+
+	counters := make(map[string]netutil.IOCountersStat)
+	if ioCounters, err := collector.system.Net.IOCounters(true); err == nil {
+		for _, counter := range ioCounters {
+			counters[counter.Name] = counter
+		}
+	}
+
+	wireGuard := readWireGuardPeers()
+	now := time.Now()
+	seen := make(map[string]bool)
+	var tunnels []VPNTunnelInfo
+
+	for _, iface := range interfaces {
+		tunnelType := getTunnelType(iface.Name)
+		_, hasPeers := wireGuard[iface.Name]
+		if tunnelType == "" {
+			if !hasPeers {
+				continue
+			}
+			tunnelType = "WireGuard"
+		}
+		// utun, tap and ppp devices exist without any VPN (macOS keeps utun0-3, VM hosts have tap devices),
+		// so they are only tunnels when WireGuard peers or vpn_interfaces say so
+		if isGenericTunnel(iface.Name) && !hasPeers && !slices.Contains(collector.config.VPNInterfaces, iface.Name) {
+			continue
+		}
+		seen[iface.Name] = true
+
+		tunnel := VPNTunnelInfo{
+			Interface: iface.Name,
+			Type:      tunnelType,
+			IsUp:      collector.isInterfaceUp(iface.Flags),
+		}
+		for _, addr := range iface.Addrs {
+			tunnel.Addresses = append(tunnel.Addresses, strings.Split(addr.Addr, "/")[0])
+		}
+
+		if counter, ok := counters[iface.Name]; ok {
+			tunnel.BytesSent = counter.BytesSent
+			tunnel.BytesRecv = counter.BytesRecv
+		}
+
+		// Track when the receive counter last moved
+		if last, ok := collector.vpnLastRecv[iface.Name]; !ok || last != tunnel.BytesRecv {
+			collector.vpnLastRecv[iface.Name] = tunnel.BytesRecv
+			collector.vpnLastChange[iface.Name] = now
+		}
+		tunnel.IdleFor = now.Sub(collector.vpnLastChange[iface.Name])
+
+		if wg, ok := wireGuard[iface.Name]; ok {
+			tunnel.Peers = wg.Peers
+			tunnel.Endpoint = wg.Endpoint
+			if !wg.LastHandshake.IsZero() {
+				tunnel.LastHandshake = wg.LastHandshake
+				tunnel.HandshakeAge = now.Sub(wg.LastHandshake)
+			}
+		}
+
+		switch {
+		case !tunnel.IsUp:
+			tunnel.Status = "Down"
+		case tunnel.Peers > 0 && (tunnel.LastHandshake.IsZero() || tunnel.HandshakeAge > collector.config.VPNHandshakeStale):
+			tunnel.Status = "Stale"
+		case tunnel.Peers == 0 && tunnel.IdleFor > collector.config.VPNIdleStale:
+			tunnel.Status = "Stale"
+		default:
+			tunnel.Status = "Up"
+		}
+
+		tunnels = append(tunnels, tunnel)
+	}
+
+	// Expected tunnels that disappeared entirely are down
+	for _, name := range collector.config.VPNInterfaces {
+		if !seen[name] {
+			tunnelType := getTunnelType(name)
+			if tunnelType == "" {
+				tunnelType = "Tunnel"
+			}
+			tunnels = append(tunnels, VPNTunnelInfo{
+				Interface: name,
+				Type:      tunnelType,
+				Status:    "Down",
+			})
+		}
+	}
+
+	sort.Slice(tunnels, func(i, j int) bool {
+		return tunnels[i].Interface < tunnels[j].Interface
+	})
+
+	data.VPNTunnels = tunnels
+
+	// Traffic attribution needs per-interface speeds
+	if collector.config.ShowIO {
+		var connections []NetworkConnectionInfo
+		if collector.config.ShowConnections {
+			connections = collector.openConnections
+		}
+		data.VPNTraffic = collector.attributeVPNTraffic(data.VPNTunnels, data.InterfaceIO, connections)
+	}
+}
+
+// vpnClientProcesses are VPN clients whose own connections carry the encrypted tunnel, so they always bypass it
+var vpnClientProcesses = map[string]bool{
+	"openvpn":        true,
+	"tailscaled":     true,
+	"charon":         true,
+	"wireguard":      true,
+	"nordvpnd":       true,
+	"mullvad-daemon": true,
+	"warp-svc":       true,
+}
+
+// attributeVPNTraffic splits throughput into traffic inside the tunnels and traffic bypassing them
+// It returns nil when no tunnel is up. Direct throughput is estimated from interface counters;
+// bypass connections are connections to public addresses whose local address is not a tunnel address
+func (collector *NetworkMonitorCollector) attributeVPNTraffic(tunnels []VPNTunnelInfo, interfaceIO []NetworkIOInfo, connections []NetworkConnectionInfo) *VPNTrafficInfo {
+	tunnelNames := make(map[string]bool)
+	tunnelAddresses := make(map[string]bool)
+	endpoints := make(map[string]bool)
+	for _, tunnel := range tunnels {
+		if !tunnel.IsUp || tunnel.Status == "Down" {
+			continue
+		}
+		tunnelNames[tunnel.Interface] = true
+		for _, address := range tunnel.Addresses {
+			tunnelAddresses[address] = true
+		}
+		if tunnel.Endpoint != "" {
+			host, _ := splitEndpoint(tunnel.Endpoint)
+			endpoints[host] = true
+		}
+	}
+	if len(tunnelNames) == 0 {
+		return nil
+	}
+
+	traffic := &VPNTrafficInfo{}
+	for _, io := range interfaceIO {
+		switch {
+		case tunnelNames[io.InterfaceName]:
+			traffic.TunnelSpeed += io.TotalSpeed
+		case isPhysicalInterface(io.InterfaceName):
+			traffic.PhysicalSpeed += io.TotalSpeed
+		}
+	}
+	traffic.DirectSpeed = math.Max(0, traffic.PhysicalSpeed-traffic.TunnelSpeed*(1+collector.config.VPNOverhead/100))
+	if total := traffic.TunnelSpeed + traffic.DirectSpeed; total > 0 {
+		traffic.DirectShare = traffic.DirectSpeed / total * 100
+	}
+
+	allowed := make(map[string]bool)
+	for _, entry := range collector.config.VPNBypassAllowed {
+		allowed[entry] = true
+	}
+
+	for _, connection := range connections {
+		if vpnClientProcesses[strings.ToLower(connection.ProcessName)] || allowed[connection.ProcessName] {
+			continue
+		}
+		localHost, _ := splitEndpoint(connection.LocalAddress)
+		if tunnelAddresses[localHost] {
+			continue
+		}
+		remoteHost, remotePort := splitEndpoint(connection.RemoteAddress)
+		if remotePort == "0" || endpoints[remoteHost] || allowed[remoteHost] {
+			continue
+		}
+		if ip := net.ParseIP(remoteHost); ip == nil || addressScope(ip) != "global" {
+			continue
+		}
+		if connection.Type == "TCP" && connection.Status != "ESTABLISHED" {
+			continue
+		}
+		traffic.BypassConnections = append(traffic.BypassConnections, connection)
+	}
+
+	return traffic
+}
+
+// splitEndpoint splits an address:port into host and port
+// Connection addresses are not bracketed, so IPv6 hosts are split at the last colon
+func splitEndpoint(address string) (string, string) {
+	index := strings.LastIndex(address, ":")
+	if index < 0 {
+		return address, ""
+	}
+	return strings.Trim(address[:index], "[]"), address[index+1:]
+}
+
+// isPhysicalInterface reports whether an interface carries traffic off the machine
+// Loopback, tunnels and bridges or veth pairs of containers and VMs are left out so traffic is not counted twice
+func isPhysicalInterface(name string) bool {
+	lower := strings.ToLower(name)
+	if getTunnelType(name) != "" || strings.HasPrefix(lower, "lo") {
+		return false
+	}
+	for _, prefix := range []string{"docker", "br-", "veth", "virbr", "vmnet", "vboxnet", "cni", "flannel", "cali"} {
+		if strings.HasPrefix(lower, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// wireGuardInterface summarizes the peers of one WireGuard interface
+type wireGuardInterface struct {
+	Peers         int
+	Endpoint      string
+	LastHandshake time.Time
+}
+
+// readWireGuardPeers reads peer handshakes from `wg show all dump`
+// The command needs root; without it no WireGuard details are returned
+func readWireGuardPeers() map[string]wireGuardInterface {
+	result := make(map[string]wireGuardInterface)
+
+	output, err := exec.Command("wg", "show", "all", "dump").Output()
+	if err != nil {
+		return result
+	}
+
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Split(line, "\t")
+
+		// Interface lines have 5 fields, peer lines have 9:
+		// interface, public-key, preshared-key, endpoint, allowed-ips, latest-handshake, rx, tx, keepalive
+		switch len(fields) {
+		case 5:
+			if _, ok := result[fields[0]]; !ok {
+				result[fields[0]] = wireGuardInterface{}
+			}
+		case 9:
+			wg := result[fields[0]]
+			wg.Peers++
+			if wg.Endpoint == "" && fields[3] != "(none)" {
+				wg.Endpoint = fields[3]
+			}
+			if seconds, err := strconv.ParseInt(fields[5], 10, 64); err == nil && seconds > 0 {
+				handshake := time.Unix(seconds, 0)
+				if handshake.After(wg.LastHandshake) {
+					wg.LastHandshake = handshake
+				}
+			}
+			result[fields[0]] = wg
+		}
+	}
+
+	return result
+}
+
+// isGenericTunnel reports whether an interface name is used by VPNs and by other software alike
+func isGenericTunnel(name string) bool {
+	lower := strings.ToLower(name)
+	return strings.HasPrefix(lower, "utun") || strings.HasPrefix(lower, "tap") || strings.HasPrefix(lower, "ppp")
+}
+
+// getTunnelType returns the VPN type for well-known tunnel interface names, or "" for other interfaces
+func getTunnelType(name string) string {
+	lower := strings.ToLower(name)
+
+	// tunl0 is the kernel's IP-in-IP fallback device, not a VPN
+	if strings.HasPrefix(lower, "tunl") {
+		return ""
+	}
+
+	switch {
+	case strings.HasPrefix(lower, "wg"), strings.HasPrefix(lower, "tailscale"),
+		strings.HasPrefix(lower, "nordlynx"), strings.Contains(lower, "wireguard"):
+		return "WireGuard"
+	case strings.HasPrefix(lower, "tun"), strings.HasPrefix(lower, "tap"), strings.Contains(lower, "openvpn"):
+		return "OpenVPN"
+	case strings.HasPrefix(lower, "ipsec"), strings.HasPrefix(lower, "vti"), strings.HasPrefix(lower, "xfrm"):
+		return "IPsec"
+	case strings.HasPrefix(lower, "utun"), strings.HasPrefix(lower, "ppp"):
+		return "Tunnel"
+	}
+	return ""
+}
+
+// updateHistory updates the network usage history
+func (collector *NetworkMonitorCollector) updateHistory(data *NetworkMonitorData) {
+	now := time.Now()
+	
+	// The in-memory history keeps HistorySize samples, at most one per HistoryResolution
+	collector.history.MaxDataPoints = collector.config.HistorySize
+	if historystore.BufferSampleDue(collector.history.Timestamps, now, collector.config.HistoryResolution) {
+		// Add new data point
+		collector.history.Timestamps = append(collector.history.Timestamps, now)
+		collector.history.TotalSent = append(collector.history.TotalSent, float64(data.TotalBytesSent))
+		collector.history.TotalRecv = append(collector.history.TotalRecv, float64(data.TotalBytesRecv))
+		collector.history.SendSpeed = append(collector.history.SendSpeed, data.TotalSendSpeed)
+		collector.history.RecvSpeed = append(collector.history.RecvSpeed, data.TotalRecvSpeed)
+		collector.history.Throughput = append(collector.history.Throughput, data.TotalThroughput)
+		collector.history.Latency = append(collector.history.Latency, data.AverageLatency)
+		collector.history.Utilization = append(collector.history.Utilization, data.NetworkUtilization)
+	}
+
+	// Limit history size
+	if excess := historystore.BufferExcess(len(collector.history.Timestamps), collector.history.MaxDataPoints); excess > 0 {
+		collector.history.Timestamps = collector.history.Timestamps[excess:]
+		collector.history.TotalSent = collector.history.TotalSent[excess:]
+		collector.history.TotalRecv = collector.history.TotalRecv[excess:]
+		collector.history.SendSpeed = collector.history.SendSpeed[excess:]
+		collector.history.RecvSpeed = collector.history.RecvSpeed[excess:]
+		collector.history.Throughput = collector.history.Throughput[excess:]
+		collector.history.Latency = collector.history.Latency[excess:]
+		collector.history.Utilization = collector.history.Utilization[excess:]
+	}
+
+	// Feed the downsampled long-term history
+	collector.longHistory.Add(now, map[string]float64{
+		"total_sent": float64(data.TotalBytesSent),
+		"total_recv": float64(data.TotalBytesRecv),
+		"send_speed": data.TotalSendSpeed,
+		"recv_speed": data.TotalRecvSpeed,
+		"throughput": data.TotalThroughput,
+		"latency": data.AverageLatency,
+		"utilization": data.NetworkUtilization,
+	})
+
+	collector.history.DataPointCount = len(collector.history.Timestamps)
+}
+
+// Helper methods
+
+// getInterfaceType returns the interface type based on name
+func (collector *NetworkMonitorCollector) getInterfaceType(name string) string {
+	// Prefix checks, since names can be shorter than the prefixes (e.g. "lo")
+	if strings.HasPrefix(name, "wl") {
+		return "WiFi"
+	} else if strings.HasPrefix(name, "et") {
+		return "Ethernet"
+	} else if strings.HasPrefix(name, "lo") {
+		return "Loopback"
+	} else if strings.HasPrefix(name, "vm") || strings.HasPrefix(name, "vb") {
+		return "Virtual"
+	}
+	return "Unknown"
+}
+
+// getInterfaceStatus returns the interface status based on flags
+func (collector *NetworkMonitorCollector) getInterfaceStatus(flags []string) string {
+	for _, flag := range flags {
+		if flag == "up" {
+			return "up"
+		}
+	}
+	return "down"
+}
+
+// isInterfaceUp checks if interface is up
+func (collector *NetworkMonitorCollector) isInterfaceUp(flags []string) bool {
+	for _, flag := range flags {
+		if flag == "up" {
+			return true
+		}
+	}
+	return false
+}
+
+// isLoopbackInterface checks if interface is loopback
+func (collector *NetworkMonitorCollector) isLoopbackInterface(name string) bool {
+	return name == "lo" || name[:2] == "lo"
+}
+
+// getConnectionType returns the connection type as string
+func (collector *NetworkMonitorCollector) getConnectionType(connType uint32) string {
+	switch connType {
+	case 1:
+		return "TCP"
+	case 2:
+		return "UDP"
+	case 3:
+		return "Unix"
+	default:
+		return "Unknown"
+	}
+}
+
+// getConnectionFamily returns the connection family as string
+func (collector *NetworkMonitorCollector) getConnectionFamily(family uint32) string {
+	switch family {
+	case 2:
+		return "IPv4"
+	case 10:
+		return "IPv6"
+	case 1:
+		return "Unix"
+	default:
+		return "Unknown"
+	}
+}
+
+// isVirtualInterface checks if an interface is virtual
+func (collector *NetworkMonitorCollector) isVirtualInterface(name string) bool {
+	virtualPrefixes := []string{"vm", "vb", "veth", "docker", "br-", "virbr"}
+	for _, prefix := range virtualPrefixes {
+		if len(name) >= len(prefix) && name[:len(prefix)] == prefix {
+			return true
+		}
+	}
+	return false
+}
+
+// measureLatency measures latency to a target (simplified implementation)
+func (collector *NetworkMonitorCollector) measureLatency(target string) (float64, float64, string) {
+	var latency float64
+	measured := false
+	if helper := privhelper.Active(); helper != nil {
+		// The privileged helper can send a real ICMP echo; a TCP connect is the fallback when it cannot
+		rtt, reachable, err := helper.Ping(target, collector.config.ConnectionTimeout)
+		if err == nil && !reachable {
+			return 0.0, 100.0, "Failed"
+		}
+		latency, measured = rtt, err == nil
+	}
+	if !measured {
+		// Time a TCP connect to port 80
+		start := time.Now()
+		conn, err := net.DialTimeout("tcp", target+":80", collector.config.ConnectionTimeout)
+		if err != nil {
+			return 0.0, 100.0, "Failed"
+		}
+		conn.Close()
+		latency = float64(time.Since(start).Nanoseconds()) / 1000000.0 // Convert to milliseconds
+	}
+	
+	if latency < collector.config.LatencyWarning {
+		return latency, 0.0, "Good"
+	} else if latency < collector.config.LatencyCritical {
+		return latency, 0.0, "Warning"
 	} else {
 		return latency, 0.0, "Critical"
 	}
 }
 
-// GetNetworkUsageHistory returns the current network usage history
+// GetNetworkUsageHistory returns a copy of the network usage history
+// A collection in progress appends to the history, so it waits for that collection to finish
 func (collector *NetworkMonitorCollector) GetNetworkUsageHistory() *NetworkUsageHistory {
-	return collector.history
+	collector.collectMutex.Lock()
+	defer collector.collectMutex.Unlock()
+	return collector.history.Clone()
+}
+
+// GetLongTermHistory returns the downsampled long-term history
+func (collector *NetworkMonitorCollector) GetLongTermHistory() *historystore.Store {
+	return collector.longHistory
+}
+
+// HistoryMemoryEstimate estimates the memory an in-memory history of samples uses
+func (collector *NetworkMonitorCollector) HistoryMemoryEstimate(samples int) uint64 {
+	return historystore.BufferBytes(samples, 7)
+}
+
+// SetIdle pauses process scans while idle mode is active
+func (collector *NetworkMonitorCollector) SetIdle(idle bool) {
+	collector.collectMutex.Lock()
+	defer collector.collectMutex.Unlock()
+	collector.idle = idle
+}
+
+// SetSimulated switches between real system data and synthetic data
+func (collector *NetworkMonitorCollector) SetSimulated(enabled bool) {
+	collector.collectMutex.Lock()
+	defer collector.collectMutex.Unlock()
+	if !enabled {
+		collector.simulator = nil
+	} else if collector.simulator == nil {
+		collector.simulator = simulate.NewSource(simulate.DefaultSeed)
+	}
 }
 
-// GetConfig returns the current configuration
-func (collector *NetworkMonitorCollector) GetConfig() *NetworkMonitorConfig {
-	return collector.config
+// SetProviders replaces the system data sources, e.g. with provider.NewFake() to collect without OS access
+func (collector *NetworkMonitorCollector) SetProviders(providers provider.Providers) {
+	collector.collectMutex.Lock()
+	defer collector.collectMutex.Unlock()
+	collector.system = providers
 }
 
-// UpdateConfig updates the collector configuration
-func (collector *NetworkMonitorCollector) UpdateConfig(config *NetworkMonitorConfig) {
-	collector.config = config
+// IsSimulated returns whether the collector produces synthetic data
+func (collector *NetworkMonitorCollector) IsSimulated() bool {
+	collector.collectMutex.Lock()
+	defer collector.collectMutex.Unlock()
+	return collector.simulator != nil
 }
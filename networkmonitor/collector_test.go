@@ -0,0 +1,267 @@
+package networkmonitor
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ahmadreza-log/simple-monitor/alert"
+	"github.com/ahmadreza-log/simple-monitor/provider"
+	"github.com/shirou/gopsutil/v3/net"
+)
+
+// newFakeCollector returns a collector reading fake, with alerts raised at once and only the sections
+// backed by the network and process providers enabled, so nothing is pinged or probed
+func newFakeCollector(fake *provider.Fake) *NetworkMonitorCollector {
+	collector := NewNetworkMonitorCollector()
+	collector.SetProviders(fake.Providers())
+	config := collector.GetConfig()
+	config.ShowProcesses = false
+	config.ShowLatency = false
+	config.ShowBandwidth = false
+	config.ShowPerformance = false
+	config.ShowConnectivity = false
+	config.ShowGateway = false
+	config.ShowFirewall = false
+	config.ShowVPN = false
+	config.ShowPortTraffic = false
+	config.AlertRules = map[string]alert.Rule{}
+	collector.UpdateConfig(config)
+	return collector
+}
+
+func TestCollectNetworkMonitorDataInterfaces(t *testing.T) {
+	fake := provider.NewFake()
+	fake.Net.InterfaceStats = net.InterfaceStatList{
+		{Index: 1, Name: "lo", MTU: 65536, Flags: []string{"up", "loopback"}, Addrs: net.InterfaceAddrList{{Addr: "127.0.0.1/8"}}},
+		{Index: 2, Name: "eth0", MTU: 1500, Flags: []string{"up", "broadcast"}, HardwareAddr: "00:11:22:33:44:55",
+			Addrs: net.InterfaceAddrList{{Addr: "fe80::1/64"}, {Addr: "192.168.1.10/24"}}},
+		{Index: 3, Name: "eth1", MTU: 1500, Flags: []string{"broadcast"}},
+	}
+
+	tests := []struct {
+		name   string
+		filter string
+		want   []string
+	}{
+		{name: "interfaces without addresses are skipped", want: []string{"lo", "eth0"}},
+		{name: "interface filter", filter: "eth0", want: []string{"eth0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collector := newFakeCollector(fake)
+			config := collector.GetConfig()
+			config.InterfaceFilter = tt.filter
+			collector.UpdateConfig(config)
+
+			data, err := collector.CollectNetworkMonitorData()
+			if err != nil {
+				t.Fatalf("CollectNetworkMonitorData() error = %v", err)
+			}
+			var names []string
+			for _, iface := range data.Interfaces {
+				names = append(names, iface.Name)
+			}
+			if len(names) != len(tt.want) {
+				t.Fatalf("Interfaces = %v, want %v", names, tt.want)
+			}
+			for i := range names {
+				if names[i] != tt.want[i] {
+					t.Fatalf("Interfaces = %v, want %v", names, tt.want)
+				}
+			}
+		})
+	}
+
+	collector := newFakeCollector(fake)
+	data, err := collector.CollectNetworkMonitorData()
+	if err != nil {
+		t.Fatalf("CollectNetworkMonitorData() error = %v", err)
+	}
+	// The first IPv4 address is the primary one, even when an IPv6 address comes first
+	eth0 := data.Interfaces[1]
+	if eth0.IPAddress != "192.168.1.10/24" || !eth0.IsUp || eth0.IsLoopback || len(eth0.Addresses) != 2 {
+		t.Errorf("eth0 = %+v, want 192.168.1.10/24, up, not loopback, with 2 addresses", eth0)
+	}
+}
+
+func TestCollectNetworkMonitorDataRateUnit(t *testing.T) {
+	fake := provider.NewFake()
+	fake.Net.IOStats = []net.IOCountersStat{
+		{Name: "eth0", BytesSent: 1e6, BytesRecv: 3e6, PacketsSent: 10, PacketsRecv: 30},
+		{Name: "eth1", BytesSent: 1e6, BytesRecv: 0, PacketsSent: 5},
+	}
+
+	tests := []struct {
+		unit       string
+		throughput float64
+	}{
+		{unit: RateUnitBits, throughput: 40},
+		{unit: RateUnitBytes, throughput: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.unit, func(t *testing.T) {
+			collector := newFakeCollector(fake)
+			config := collector.GetConfig()
+			config.RateUnit = tt.unit
+			collector.UpdateConfig(config)
+
+			data, err := collector.CollectNetworkMonitorData()
+			if err != nil {
+				t.Fatalf("CollectNetworkMonitorData() error = %v", err)
+			}
+			if data.TotalBytesSent != 2e6 || data.TotalBytesRecv != 3e6 || data.TotalPacketsSent != 15 {
+				t.Errorf("totals = %d sent, %d received, %d packets sent, want 2e6, 3e6, 15",
+					data.TotalBytesSent, data.TotalBytesRecv, data.TotalPacketsSent)
+			}
+			if data.RateUnit != tt.unit || data.TotalThroughput != tt.throughput {
+				t.Errorf("TotalThroughput = %v %s, want %v %s", data.TotalThroughput, data.RateUnit, tt.throughput, tt.unit)
+			}
+		})
+	}
+}
+
+func TestCollectNetworkMonitorDataConnections(t *testing.T) {
+	fake := provider.NewFake()
+	fake.Net.ConnectionStats = []net.ConnectionStat{
+		{Type: 1, Family: 2, Status: "ESTABLISHED", Pid: 42,
+			Laddr: net.Addr{IP: "192.168.1.10", Port: 51000}, Raddr: net.Addr{IP: "93.184.216.34", Port: 443}},
+		{Type: 2, Family: 2, Pid: 43, Laddr: net.Addr{IP: "0.0.0.0", Port: 53}},
+	}
+	fake.Proc.Table = []*provider.FakeProcess{{Pid: 42, ProcessName: "curl", User: "alice"}}
+	collector := newFakeCollector(fake)
+	config := collector.GetConfig()
+	config.ConnectionTypeFilter = "TCP"
+	collector.UpdateConfig(config)
+
+	data, err := collector.CollectNetworkMonitorData()
+	if err != nil {
+		t.Fatalf("CollectNetworkMonitorData() error = %v", err)
+	}
+	if len(data.Connections) != 1 {
+		t.Fatalf("Connections = %+v, want only the TCP one", data.Connections)
+	}
+	conn := data.Connections[0]
+	if conn.RemoteAddress != "93.184.216.34:443" || conn.ProcessName != "curl" || conn.User != "alice" {
+		t.Errorf("connection = %+v, want 93.184.216.34:443 of curl run by alice", conn)
+	}
+}
+
+func TestCollectNetworkMonitorDataSectionErrors(t *testing.T) {
+	fake := provider.NewFake()
+	fake.Net.Faults["Interfaces"] = errors.New("not supported")
+	fake.Net.Faults["Connections"] = errors.New("permission denied")
+	collector := newFakeCollector(fake)
+
+	data, err := collector.CollectNetworkMonitorData()
+	if err != nil {
+		t.Fatalf("CollectNetworkMonitorData() error = %v", err)
+	}
+	for _, section := range []string{"interfaces", "connections"} {
+		if _, failed := data.SectionErrors.Get(section); !failed {
+			t.Errorf("SectionErrors = %v, want a %s entry", data.SectionErrors, section)
+		}
+	}
+	if _, failed := data.SectionErrors.Get("io"); failed {
+		t.Errorf("SectionErrors = %v, want no io entry", data.SectionErrors)
+	}
+}
+
+func TestNeighbourReachable(t *testing.T) {
+	tests := []struct {
+		output string
+		want   bool
+	}{
+		{output: "192.168.1.1 dev eth0 lladdr aa:bb:cc:dd:ee:ff REACHABLE\n", want: true},
+		{output: "192.168.1.1 dev eth0 lladdr aa:bb:cc:dd:ee:ff STALE\n"},
+		{output: "192.168.1.1 dev eth0 lladdr aa:bb:cc:dd:ee:ff DELAY\n"},
+		{output: "192.168.1.1 dev eth0  FAILED\n"},
+		{output: ""},
+	}
+	for _, tt := range tests {
+		if got := neighbourReachable(tt.output); got != tt.want {
+			t.Errorf("neighbourReachable(%q) = %v, want %v", tt.output, got, tt.want)
+		}
+	}
+}
+
+func TestProbeCaptivePortal(t *testing.T) {
+	server := func(status int, location string) *httptest.Server {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if location != "" {
+				w.Header().Set("Location", location)
+			}
+			w.WriteHeader(status)
+		}))
+		t.Cleanup(server.Close)
+		return server
+	}
+
+	tests := []struct {
+		name         string
+		direct       int
+		location     string
+		proxy        int // 0 for no proxy
+		wantDetected bool
+		wantProxy    string
+	}{
+		{name: "open network", direct: http.StatusNoContent},
+		{name: "portal redirect", direct: http.StatusFound, location: "http://portal.example/login", wantDetected: true},
+		{name: "portal page", direct: http.StatusOK, wantDetected: true},
+		{name: "transparent proxy wants credentials", direct: http.StatusProxyAuthRequired, wantProxy: ProxyAuthRequired},
+		{name: "proxy works", direct: http.StatusNoContent, proxy: http.StatusNoContent, wantProxy: ProxyReachable},
+		{name: "proxy wants credentials", direct: http.StatusNoContent, proxy: http.StatusProxyAuthRequired, wantProxy: ProxyAuthRequired},
+		{name: "proxy fails", direct: http.StatusNoContent, proxy: http.StatusBadGateway, wantProxy: ProxyFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collector := newFakeCollector(provider.NewFake())
+			collector.changeConfig(func(config *NetworkMonitorConfig) {
+				config.CaptivePortalURL = server(tt.direct, tt.location).URL + "/generate_204"
+			})
+			collector.adoptConfig()
+			var proxy NetworkProxyInfo
+			if tt.proxy != 0 {
+				proxy = NetworkProxyInfo{Enabled: true, HTTPProxy: server(tt.proxy, "").URL}
+			}
+
+			info := collector.probeCaptivePortal(proxy)
+			if !info.Reachable || info.Detected != tt.wantDetected || info.ProxyStatus != tt.wantProxy {
+				t.Errorf("probeCaptivePortal() = %+v, want reachable, detected %v, proxy status %q", info, tt.wantDetected, tt.wantProxy)
+			}
+			if tt.location != "" && info.RedirectURL != tt.location {
+				t.Errorf("RedirectURL = %q, want %q", info.RedirectURL, tt.location)
+			}
+		})
+	}
+}
+
+func TestProcessSocketRates(t *testing.T) {
+	connections := []NetworkConnectionInfo{
+		{PID: 10, LocalAddress: "192.168.1.5:51000", RemoteAddress: "203.0.113.7:443"},
+		{PID: 10, LocalAddress: "192.168.1.5:51001", RemoteAddress: "203.0.113.8:443"},
+		{PID: 20, LocalAddress: "::ffff:192.168.1.5:22", RemoteAddress: "198.51.100.2:60000"},
+		{PID: 30, LocalAddress: "192.168.1.5:53000", RemoteAddress: "203.0.113.9:80"},
+	}
+	rates := map[string]socketRate{
+		socketKey("192.168.1.5:51000", "203.0.113.7:443"):          {send: 100, recv: 4000},
+		socketKey("192.168.1.5:51001", "203.0.113.8:443"):          {send: 50, recv: 850},
+		socketKey("[::ffff:192.168.1.5]:22", "198.51.100.2:60000"): {send: 300, recv: 20},
+	}
+
+	got := processSocketRates(connections, rates)
+	want := map[int32]float64{10: 5000, 20: 320, 30: 0}
+	if len(got) != len(want) {
+		t.Fatalf("processSocketRates() = %v, want %v", got, want)
+	}
+	for pid, rate := range want {
+		if got[pid] != rate {
+			t.Errorf("processSocketRates() = %v, want %v", got, want)
+			break
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package networkmonitor
+
+import (
+	"github.com/ahmadreza-log/simple-monitor/alert"
+	"maps"
+	"slices"
+)
+
+// Clone returns a deep copy of the configuration
+func (config *NetworkMonitorConfig) Clone() *NetworkMonitorConfig {
+	clone := *config
+	clone.AlertRules = alert.CloneRules(config.AlertRules)
+	clone.LatencyTargets = slices.Clone(config.LatencyTargets)
+	clone.VPNInterfaces = slices.Clone(config.VPNInterfaces)
+	clone.VPNBypassAllowed = slices.Clone(config.VPNBypassAllowed)
+	clone.WakeHosts = maps.Clone(config.WakeHosts)
+	return &clone
+}
+
+// UpdateConfig replaces the configuration with a copy of config; the next collection uses it
+func (collector *NetworkMonitorCollector) UpdateConfig(config *NetworkMonitorConfig) {
+	collector.configMutex.Lock()
+	defer collector.configMutex.Unlock()
+	collector.latest = config.Clone()
+}
+
+// GetConfig returns a copy of the current configuration
+// Changing the copy has no effect until it is passed to UpdateConfig
+func (collector *NetworkMonitorCollector) GetConfig() *NetworkMonitorConfig {
+	return collector.currentConfig().Clone()
+}
+
+// currentConfig returns the latest configuration without copying it, for reads outside a collection
+// It is shared with the collection, so it must not be modified
+func (collector *NetworkMonitorCollector) currentConfig() *NetworkMonitorConfig {
+	collector.configMutex.Lock()
+	defer collector.configMutex.Unlock()
+	return collector.latest
+}
+
+// changeConfig applies change to a copy of the current configuration and makes the copy current
+// Configurations are replaced instead of changed in place, so a collection in progress keeps a consistent one
+func (collector *NetworkMonitorCollector) changeConfig(change func(config *NetworkMonitorConfig)) {
+	collector.configMutex.Lock()
+	defer collector.configMutex.Unlock()
+	config := collector.latest.Clone()
+	change(config)
+	collector.latest = config
+}
+
+// adoptConfig makes the latest configuration the one the starting collection reads
+// It must be called with collectMutex held, which keeps config unchanged until the collection ends
+func (collector *NetworkMonitorCollector) adoptConfig() {
+	collector.config = collector.currentConfig()
+}
@@ -0,0 +1,80 @@
+package networkmonitor
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ahmadreza-log/simple-monitor/monitortest"
+	"github.com/ahmadreza-log/simple-monitor/provider"
+	"github.com/shirou/gopsutil/v3/net"
+)
+
+// collect returns a function running one collection of collector, for monitortest
+func collect(collector *NetworkMonitorCollector) func() error {
+	return func() error {
+		_, err := collector.CollectNetworkMonitorData()
+		return err
+	}
+}
+
+// TestConfigUpdatesDuringCollections changes the configuration while several collections run, the way the
+// settings menu does while the live loop, a subscriber and the attach server collect; run it with -race
+func TestConfigUpdatesDuringCollections(t *testing.T) {
+	fake := provider.NewFake()
+	fake.Net.IOStats = []net.IOCountersStat{{Name: "eth0", BytesSent: 1e6, BytesRecv: 3e6}}
+	collector := newFakeCollector(fake)
+
+	monitortest.DuringCollections(t, collect(collector), func(i int) {
+		if i%2 == 0 {
+			config := collector.GetConfig()
+			config.RefreshInterval = time.Duration(i) * time.Second
+			collector.UpdateConfig(config)
+		} else {
+			collector.changeConfig(func(config *NetworkMonitorConfig) {
+				config.RefreshInterval = time.Duration(i) * time.Second
+			})
+		}
+	})
+
+	data, err := collector.CollectNetworkMonitorData()
+	if err != nil {
+		t.Fatalf("CollectNetworkMonitorData() error = %v", err)
+	}
+	if data.RefreshInterval != monitortest.Rounds*time.Second {
+		t.Errorf("RefreshInterval = %v, want the last update (%ds)", data.RefreshInterval, monitortest.Rounds)
+	}
+}
+
+// TestHistoryAndSettersDuringCollections reads the history and switches idle mode, simulated data and providers
+// while several collections run, the way the managers do; run it with -race
+func TestHistoryAndSettersDuringCollections(t *testing.T) {
+	fake := provider.NewFake()
+	fake.Net.IOStats = []net.IOCountersStat{{Name: "eth0", BytesSent: 1e6, BytesRecv: 3e6}}
+	collector := newFakeCollector(fake)
+
+	monitortest.DuringCollections(t, collect(collector), func(i int) {
+		history := collector.GetNetworkUsageHistory()
+		for j := range history.Throughput {
+			history.Throughput[j] = -1
+		}
+		collector.SetIdle(i%2 == 0)
+		collector.SetSimulated(i%4 == 0 && i < monitortest.Rounds)
+		collector.SetProviders(fake.Providers())
+	})
+
+	if collector.IsSimulated() {
+		t.Errorf("IsSimulated() = true after the last SetSimulated(false)")
+	}
+	if _, err := collector.CollectNetworkMonitorData(); err != nil {
+		t.Fatalf("CollectNetworkMonitorData() error = %v", err)
+	}
+	history := collector.GetNetworkUsageHistory()
+	if len(history.Throughput) == 0 {
+		t.Fatal("GetNetworkUsageHistory() is empty after a collection")
+	}
+	history.Throughput[0] = -1
+	if slices.Contains(collector.GetNetworkUsageHistory().Throughput, -1) {
+		t.Errorf("GetNetworkUsageHistory().Throughput = %v, want changes to the returned copy kept out of the collector", collector.GetNetworkUsageHistory().Throughput)
+	}
+}
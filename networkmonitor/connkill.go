@@ -0,0 +1,104 @@
+package networkmonitor
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// endpoint is one end of a TCP connection
+type endpoint struct {
+	IP   net.IP
+	Port int
+}
+
+// ListTCPConnections returns the open TCP connections of every process, sorted by process name
+// Listening sockets are left out: they are not connections to anyone and are closed by stopping their process
+func (collector *NetworkMonitorCollector) ListTCPConnections() ([]NetworkConnectionInfo, error) {
+	connections, err := collector.system.Net.Connections("tcp")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get network connections: %w", err)
+	}
+
+	names := make(map[int32]string)
+	var list []NetworkConnectionInfo
+	for _, conn := range connections {
+		if conn.Status == "LISTEN" || conn.Raddr.Port == 0 {
+			continue
+		}
+		if _, seen := names[conn.Pid]; !seen {
+			names[conn.Pid] = "Unknown"
+			if conn.Pid > 0 {
+				if proc, err := collector.system.Proc.NewProcess(conn.Pid); err == nil {
+					if name, err := proc.Name(); err == nil {
+						names[conn.Pid] = name
+					}
+				}
+			}
+		}
+
+		list = append(list, NetworkConnectionInfo{
+			LocalAddress:  net.JoinHostPort(conn.Laddr.IP, strconv.Itoa(int(conn.Laddr.Port))),
+			RemoteAddress: net.JoinHostPort(conn.Raddr.IP, strconv.Itoa(int(conn.Raddr.Port))),
+			Status:        conn.Status,
+			Type:          "TCP",
+			PID:           conn.Pid,
+			ProcessName:   names[conn.Pid],
+			State:         conn.Status,
+			Family:        collector.getConnectionFamily(conn.Family),
+		})
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].ProcessName != list[j].ProcessName {
+			return strings.ToLower(list[i].ProcessName) < strings.ToLower(list[j].ProcessName)
+		}
+		return list[i].RemoteAddress < list[j].RemoteAddress
+	})
+	return list, nil
+}
+
+// CloseConnection closes a TCP connection owned by any process and returns how it was closed
+// The connection is reset from outside the process (ss -K on Linux, SetTcpEntry on Windows), which
+// needs root or administrator rights; the process sees a connection reset, as if the peer had dropped it
+func CloseConnection(conn NetworkConnectionInfo) (string, error) {
+	if conn.Type != "TCP" {
+		return "", fmt.Errorf("only TCP connections can be closed, not %s", conn.Type)
+	}
+	local, err := parseEndpoint(conn.LocalAddress)
+	if err != nil {
+		return "", err
+	}
+	remote, err := parseEndpoint(conn.RemoteAddress)
+	if err != nil {
+		return "", err
+	}
+
+	method, err := closeTCPConnection(local, remote)
+	if err != nil && conn.PID > 0 {
+		return "", fmt.Errorf("%w (stopping %s, PID %d, closes all of its connections)", err, conn.ProcessName, conn.PID)
+	}
+	return method, err
+}
+
+// parseEndpoint reads an address:port as shown in the connection list
+func parseEndpoint(address string) (endpoint, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		// Older exports wrote IPv6 addresses without brackets
+		index := strings.LastIndex(address, ":")
+		if index < 0 {
+			return endpoint{}, fmt.Errorf("invalid address %q", address)
+		}
+		host, port = address[:index], address[index+1:]
+	}
+
+	ip := net.ParseIP(host)
+	number, err := strconv.Atoi(port)
+	if ip == nil || err != nil || number <= 0 || number > 65535 {
+		return endpoint{}, fmt.Errorf("invalid address %q", address)
+	}
+	return endpoint{IP: ip, Port: number}, nil
+}
@@ -0,0 +1,32 @@
+//go:build linux
+
+package networkmonitor
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// closeTCPConnection destroys a socket with ss -K, which goes through the kernel's sock_diag interface
+// ss lists the sockets it destroyed; it stays silent when it was not allowed to, so nothing listed means
+// the connection is still open
+func closeTCPConnection(local, remote endpoint) (string, error) {
+	cmd := exec.Command("ss", "-K", "-t", "-n", "-H",
+		"src", net.JoinHostPort(local.IP.String(), strconv.Itoa(local.Port)),
+		"dst", net.JoinHostPort(remote.IP.String(), strconv.Itoa(remote.Port)))
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return "", errors.New("ss is not installed (install iproute2)")
+		}
+		return "", fmt.Errorf("ss -K failed: %s", strings.TrimSpace(string(output)))
+	}
+	if strings.TrimSpace(string(output)) == "" {
+		return "", errors.New("the kernel did not close the connection: this needs root and a kernel built with CONFIG_INET_DIAG_DESTROY")
+	}
+	return "ss -K", nil
+}
@@ -0,0 +1,10 @@
+//go:build !linux && !windows
+
+package networkmonitor
+
+import "errors"
+
+// closeTCPConnection is not available: macOS and the BSDs offer no way to reset another process's connection
+func closeTCPConnection(local, remote endpoint) (string, error) {
+	return "", errors.New("closing connections of other processes is not supported on this system")
+}
@@ -0,0 +1,60 @@
+//go:build windows
+
+package networkmonitor
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	iphlpapi        = windows.NewLazySystemDLL("iphlpapi.dll")
+	procSetTcpEntry = iphlpapi.NewProc("SetTcpEntry")
+)
+
+// mibTCPStateDeleteTCB is the only state SetTcpEntry accepts: it resets the connection
+const mibTCPStateDeleteTCB = 12
+
+// mibTCPRow mirrors the Win32 MIB_TCPROW structure; addresses and ports are in network byte order
+type mibTCPRow struct {
+	state      uint32
+	localAddr  uint32
+	localPort  uint32
+	remoteAddr uint32
+	remotePort uint32
+}
+
+// closeTCPConnection resets an IPv4 connection with SetTcpEntry, which needs administrator rights
+// Windows has no such call for IPv6 connections
+func closeTCPConnection(local, remote endpoint) (string, error) {
+	localIP, remoteIP := local.IP.To4(), remote.IP.To4()
+	if localIP == nil || remoteIP == nil {
+		return "", errors.New("only IPv4 connections can be closed on Windows")
+	}
+
+	row := mibTCPRow{
+		state:      mibTCPStateDeleteTCB,
+		localAddr:  binary.LittleEndian.Uint32(localIP),
+		localPort:  uint32(networkPort(local.Port)),
+		remoteAddr: binary.LittleEndian.Uint32(remoteIP),
+		remotePort: uint32(networkPort(remote.Port)),
+	}
+	result, _, _ := procSetTcpEntry.Call(uintptr(unsafe.Pointer(&row)))
+	if result != 0 {
+		if syscall.Errno(result) == windows.ERROR_ACCESS_DENIED {
+			return "", errors.New("closing connections needs administrator rights")
+		}
+		return "", fmt.Errorf("SetTcpEntry failed: %v", syscall.Errno(result))
+	}
+	return "SetTcpEntry", nil
+}
+
+// networkPort converts a port to network byte order as stored in MIB_TCPROW
+func networkPort(port int) uint16 {
+	return uint16(port>>8) | uint16(port&0xff)<<8
+}
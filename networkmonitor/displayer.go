@@ -2,7 +2,11 @@ package networkmonitor
 
 import (
 	"fmt"
+	"github.com/ahmadreza-log/simple-monitor/partial"
+	"github.com/ahmadreza-log/simple-monitor/terminal"
+	"net/http"
 	"strings"
+	"time"
 )
 
 // NetworkMonitorDisplayer handles the display and formatting of network monitoring data
@@ -11,7 +15,8 @@ type NetworkMonitorDisplayer struct {
 	// Display configuration
 	ShowGraphics bool // Whether to show graphical elements
 	ShowColors   bool // Whether to use colored output
-	BarWidth     int  // Width of progress bars
+	BarWidth     int  // Width of progress bars (when AutoWidth is off)
+	AutoWidth    bool // Whether to fit bars, separators and columns to the terminal width
 	MaxProcesses int  // Maximum number of processes to display
 
 	// Color codes for different elements
@@ -33,6 +38,7 @@ func NewNetworkMonitorDisplayer() *NetworkMonitorDisplayer {
 		ShowGraphics: true,
 		ShowColors:   true,
 		BarWidth:     50,
+		AutoWidth:    true,
 		MaxProcesses: 10,
 		ColorReset:   "\033[0m",
 		ColorRed:     "\033[31m",
@@ -58,22 +64,60 @@ func (displayer *NetworkMonitorDisplayer) DisplayNetworkMonitorData(data *Networ
 	displayer.displayOverallNetworkStats(data)
 
 	// Display interface information
-	if len(data.Interfaces) > 0 {
+	if reason, failed := data.SectionErrors.Get("interfaces"); failed {
+		displayer.displayUnavailable("🔧 NETWORK INTERFACES", reason)
+	} else if len(data.Interfaces) > 0 {
 		displayer.displayInterfaceInfo(data)
 	}
 
 	// Display I/O statistics
-	if len(data.InterfaceIO) > 0 {
+	if reason, failed := data.SectionErrors.Get("io"); failed {
+		displayer.displayUnavailable("⚡ NETWORK I/O STATISTICS", reason)
+	} else if len(data.InterfaceIO) > 0 {
 		displayer.displayIOInfo(data)
 	}
 
 	// Display connection information
-	if len(data.Connections) > 0 {
+	if reason, failed := data.SectionErrors.Get("connections"); failed {
+		displayer.displayUnavailable("🔗 NETWORK CONNECTIONS", reason)
+	} else if len(data.Connections) > 0 {
 		displayer.displayConnectionInfo(data)
 	}
 
+	// Display endpoints with repeated short-lived connections
+	if len(data.ConnectionChurn) > 0 {
+		displayer.displayConnectionChurn(data)
+	}
+
+	// Display traffic by port class
+	if len(data.PortTraffic) > 0 {
+		displayer.displayPortTraffic(data)
+	}
+
+	// Display proxy and captive portal status
+	if !data.CaptivePortal.CheckedAt.IsZero() {
+		displayer.displayConnectivityInfo(data)
+	}
+
+	// Display default gateway reachability
+	if data.Gateway.Address != "" {
+		displayer.displayGatewayInfo(data)
+	}
+
+	// Display firewall rule counters
+	if !data.Firewall.CheckedAt.IsZero() {
+		displayer.displayFirewallInfo(data)
+	}
+
+	// Display VPN/tunnel health
+	if len(data.VPNTunnels) > 0 {
+		displayer.displayVPNInfo(data)
+	}
+
 	// Display latency information
-	if len(data.LatencyInfo) > 0 {
+	if reason, failed := data.SectionErrors.Get("latency"); failed {
+		displayer.displayUnavailable("⏱️  NETWORK LATENCY", reason)
+	} else if len(data.LatencyInfo) > 0 {
 		displayer.displayLatencyInfo(data)
 	}
 
@@ -84,13 +128,20 @@ func (displayer *NetworkMonitorDisplayer) DisplayNetworkMonitorData(data *Networ
 	displayer.displayPerformanceMetrics(data)
 
 	// Display top processes
-	if len(data.TopProcesses) > 0 {
+	if reason, failed := data.SectionErrors.Get("processes"); failed {
+		displayer.displayUnavailable("🔥 TOP NETWORK PROCESSES", reason)
+	} else if len(data.TopProcesses) > 0 {
 		displayer.displayTopProcesses(data)
 	}
 
 	// Display network status and alerts
 	displayer.displayNetworkStatus(data)
 
+	// Display runbook notes of raised alerts
+	if len(data.AlertNotes) > 0 {
+		displayer.displayAlertNotes(data)
+	}
+
 	// Display footer
 	displayer.displayFooter(data)
 }
@@ -98,7 +149,12 @@ func (displayer *NetworkMonitorDisplayer) DisplayNetworkMonitorData(data *Networ
 // displayHeader displays the network monitor header
 func (displayer *NetworkMonitorDisplayer) displayHeader(data *NetworkMonitorData) {
 	fmt.Println(displayer.colorize("🌐 NETWORK MONITOR", displayer.ColorBold+displayer.ColorCyan))
-	fmt.Println(strings.Repeat("=", 80))
+	fmt.Println(displayer.rule("="))
+
+	// Synthetic data must never be mistaken for a real system
+	if data.Simulated {
+		fmt.Println(displayer.colorize("🧪 Simulated data (--simulate)", displayer.ColorMagenta))
+	}
 
 	// Network summary
 	fmt.Printf("%sTotal Sent: %s%s\n",
@@ -111,10 +167,10 @@ func (displayer *NetworkMonitorDisplayer) displayHeader(data *NetworkMonitorData
 		displayer.colorize(displayer.formatBytes(data.TotalBytesRecv), displayer.ColorBlue),
 		displayer.colorize("", displayer.ColorReset))
 
-	fmt.Printf("%sTotal Throughput: %s%.2f Mbps%s\n",
+	fmt.Printf("%sTotal Throughput: %s%s%s\n",
 		displayer.colorize("", displayer.ColorBold),
 		displayer.colorize("", displayer.ColorYellow),
-		data.TotalThroughput,
+		formatSpeed(data.TotalThroughput, data.RateUnit),
 		displayer.colorize("", displayer.ColorReset))
 
 	fmt.Printf("%sNetwork Status: %s%s%s\n",
@@ -123,13 +179,13 @@ func (displayer *NetworkMonitorDisplayer) displayHeader(data *NetworkMonitorData
 		data.NetworkStatus,
 		displayer.colorize("", displayer.ColorReset))
 
-	fmt.Println(strings.Repeat("=", 80))
+	fmt.Println(displayer.rule("="))
 }
 
 // displayOverallNetworkStats displays overall network statistics with graphical bars
 func (displayer *NetworkMonitorDisplayer) displayOverallNetworkStats(data *NetworkMonitorData) {
 	fmt.Println("\n📊 OVERALL NETWORK STATISTICS")
-	fmt.Println(strings.Repeat("-", 50))
+	fmt.Println(displayer.sectionRule())
 
 	// Send speed bar
 	displayer.displayUsageBar("Send Speed", data.TotalSendSpeed, displayer.ColorGreen)
@@ -147,7 +203,7 @@ func (displayer *NetworkMonitorDisplayer) displayOverallNetworkStats(data *Netwo
 // displayInterfaceInfo displays network interface information
 func (displayer *NetworkMonitorDisplayer) displayInterfaceInfo(data *NetworkMonitorData) {
 	fmt.Println("\n🔧 NETWORK INTERFACES")
-	fmt.Println(strings.Repeat("-", 80))
+	fmt.Println(displayer.rule("-"))
 
 	// Header
 	fmt.Printf("%s%-15s %-10s %-15s %-15s %-8s %-6s %s\n",
@@ -160,7 +216,7 @@ func (displayer *NetworkMonitorDisplayer) displayInterfaceInfo(data *NetworkMoni
 		"Speed",
 		displayer.colorize("", displayer.ColorReset))
 
-	fmt.Println(strings.Repeat("-", 80))
+	fmt.Println(displayer.rule("-"))
 
 	// Display interfaces
 	for _, iface := range data.Interfaces {
@@ -191,16 +247,36 @@ func (displayer *NetworkMonitorDisplayer) displayInterfaceInfo(data *NetworkMoni
 				displayer.colorize("", displayer.ColorRed),
 				displayer.colorize("", displayer.ColorReset))
 		}
+
+		// Every assigned address with its prefix, scope and lifetime
+		for _, address := range iface.Addresses {
+			fmt.Printf("    %-43s %-5s %-11s %s\n",
+				fmt.Sprintf("%s/%d", address.Address, address.PrefixLength),
+				address.Family,
+				address.Scope,
+				displayer.colorize(displayer.formatLifetime(address), displayer.ColorCyan))
+		}
 	}
 }
 
+// formatLifetime describes how long an address stays valid
+func (displayer *NetworkMonitorDisplayer) formatLifetime(address NetworkAddressInfo) string {
+	if !address.LifetimeKnown {
+		return ""
+	}
+	if address.Permanent {
+		return "permanent"
+	}
+	return fmt.Sprintf("valid %v, preferred %v", address.ValidLifetime, address.PreferredLifetime)
+}
+
 // displayIOInfo displays network I/O statistics
 func (displayer *NetworkMonitorDisplayer) displayIOInfo(data *NetworkMonitorData) {
 	fmt.Println("\n⚡ NETWORK I/O STATISTICS")
-	fmt.Println(strings.Repeat("-", 80))
+	fmt.Println(displayer.rule("-"))
 
 	// Header
-	fmt.Printf("%s%-15s %-12s %-12s %-8s %-8s %-8s %s\n",
+	fmt.Printf("%s%-15s %-14s %-14s %-8s %-8s %-8s %s\n",
 		displayer.colorize("", displayer.ColorBold),
 		"Interface",
 		"Send Speed",
@@ -210,20 +286,20 @@ func (displayer *NetworkMonitorDisplayer) displayIOInfo(data *NetworkMonitorData
 		"Util%",
 		displayer.colorize("", displayer.ColorReset))
 
-	fmt.Println(strings.Repeat("-", 80))
+	fmt.Println(displayer.rule("-"))
 
 	// Display I/O statistics
 	for _, io := range data.InterfaceIO {
 		// Color code based on utilization
 		utilColor := displayer.getUtilizationColor(io.Utilization)
 
-		fmt.Printf("%s%-15s %s%-12s %s%-12s %s%-8d %s%-8d %s%-8.2f %s\n",
+		fmt.Printf("%s%-15s %s%-14s %s%-14s %s%-8d %s%-8d %s%-8.2f %s\n",
 			displayer.colorize("", displayer.ColorBold),
 			io.InterfaceName,
 			displayer.colorize("", displayer.ColorGreen),
-			fmt.Sprintf("%.2f Mbps", io.SendSpeed),
+			formatSpeed(io.SendSpeed, data.RateUnit),
 			displayer.colorize("", displayer.ColorBlue),
-			fmt.Sprintf("%.2f Mbps", io.RecvSpeed),
+			formatSpeed(io.RecvSpeed, data.RateUnit),
 			displayer.colorize("", displayer.ColorWhite),
 			io.PacketsSent + io.PacketsRecv,
 			displayer.colorize("", displayer.ColorRed),
@@ -237,16 +313,16 @@ func (displayer *NetworkMonitorDisplayer) displayIOInfo(data *NetworkMonitorData
 	}
 
 	// Overall I/O summary
-	fmt.Printf("\n%sTotal Send Speed: %s%.2f Mbps%s\n",
+	fmt.Printf("\n%sTotal Send Speed: %s%s%s\n",
 		displayer.colorize("", displayer.ColorBold),
 		displayer.colorize("", displayer.ColorGreen),
-		data.TotalSendSpeed,
+		formatSpeed(data.TotalSendSpeed, data.RateUnit),
 		displayer.colorize("", displayer.ColorReset))
 
-	fmt.Printf("%sTotal Receive Speed: %s%.2f Mbps%s\n",
+	fmt.Printf("%sTotal Receive Speed: %s%s%s\n",
 		displayer.colorize("", displayer.ColorBold),
 		displayer.colorize("", displayer.ColorBlue),
-		data.TotalRecvSpeed,
+		formatSpeed(data.TotalRecvSpeed, data.RateUnit),
 		displayer.colorize("", displayer.ColorReset))
 
 	fmt.Printf("%sTotal Packets: %s%d%s\n",
@@ -259,19 +335,20 @@ func (displayer *NetworkMonitorDisplayer) displayIOInfo(data *NetworkMonitorData
 // displayConnectionInfo displays network connection information
 func (displayer *NetworkMonitorDisplayer) displayConnectionInfo(data *NetworkMonitorData) {
 	fmt.Println("\n🔗 NETWORK CONNECTIONS")
-	fmt.Println(strings.Repeat("-", 80))
+	fmt.Println(displayer.rule("-"))
 
 	// Header
-	fmt.Printf("%s%-20s %-20s %-8s %-8s %-15s %s\n",
+	fmt.Printf("%s%-20s %-20s %-8s %-8s %-15s %-8s %s\n",
 		displayer.colorize("", displayer.ColorBold),
 		"Local Address",
 		"Remote Address",
 		"Type",
 		"Status",
 		"Process",
+		"Age",
 		displayer.colorize("", displayer.ColorReset))
 
-	fmt.Println(strings.Repeat("-", 80))
+	fmt.Println(displayer.rule("-"))
 
 	// Display connections
 	for _, conn := range data.Connections {
@@ -288,7 +365,7 @@ func (displayer *NetworkMonitorDisplayer) displayConnectionInfo(data *NetworkMon
 		// Color code based on connection type
 		typeColor := displayer.getConnectionTypeColor(conn.Type)
 
-		fmt.Printf("%s%-20s %-20s %s%-8s %s%-8s %s%-15s %s\n",
+		fmt.Printf("%s%-20s %-20s %s%-8s %s%-8s %s%-15s %s%-8s %s\n",
 			displayer.colorize("", displayer.ColorBold),
 			localAddr,
 			remoteAddr,
@@ -298,14 +375,414 @@ func (displayer *NetworkMonitorDisplayer) displayConnectionInfo(data *NetworkMon
 			conn.Status,
 			displayer.colorize("", displayer.ColorCyan),
 			conn.ProcessName,
+			displayer.getConnectionAgeColor(conn),
+			displayer.formatConnectionAge(conn),
 			displayer.colorize("", displayer.ColorReset))
 	}
+
+	fmt.Printf("Short-lived connections closed recently: %s%d%s\n",
+		displayer.colorize("", displayer.ColorYellow),
+		data.ShortLivedConnections,
+		displayer.colorize("", displayer.ColorReset))
+}
+
+// displayConnectionChurn displays endpoints that keep opening connections which close again quickly
+func (displayer *NetworkMonitorDisplayer) displayConnectionChurn(data *NetworkMonitorData) {
+	fmt.Println("\n🔁 CONNECTION CHURN")
+	fmt.Println(displayer.rule("-"))
+
+	fmt.Printf("%s%-20s %-25s %s%s\n",
+		displayer.colorize("", displayer.ColorBold),
+		"Process",
+		"Remote Address",
+		"Short-lived",
+		displayer.colorize("", displayer.ColorReset))
+
+	fmt.Println(displayer.rule("-"))
+
+	for _, churn := range data.ConnectionChurn {
+		fmt.Printf("%-20s %-25s %s%d%s\n",
+			churn.ProcessName,
+			churn.RemoteAddress,
+			displayer.colorize("", displayer.ColorYellow),
+			churn.Count,
+			displayer.colorize("", displayer.ColorReset))
+	}
+}
+
+// displayPortTraffic displays a histogram of traffic by service port class, split into sent and received
+// Without per-socket counters the bars show each class's share of the connections instead
+func (displayer *NetworkMonitorDisplayer) displayPortTraffic(data *NetworkMonitorData) {
+	fmt.Println("\n📊 TRAFFIC BY PORT CLASS")
+	fmt.Println(displayer.rule("-"))
+
+	if data.PortTrafficMeasured {
+		fmt.Printf("%s█%s sent  %s█%s received\n",
+			displayer.colorize("", terminal.WithoutMarker(displayer.ColorGreen)),
+			displayer.colorize("", displayer.ColorReset),
+			displayer.colorize("", terminal.WithoutMarker(displayer.ColorBlue)),
+			displayer.colorize("", displayer.ColorReset))
+	} else {
+		fmt.Println("Share of connections (per-socket byte counters are only read on Linux)")
+	}
+
+	width := displayer.barWidth() / 2
+	for _, class := range data.PortTraffic {
+		filled := int(class.Share / 100 * float64(width))
+		if filled > width {
+			filled = width
+		}
+
+		// Split the filled part by direction when throughput was measured
+		sendWidth, recvWidth := 0, filled
+		if data.PortTrafficMeasured && class.TotalSpeed > 0 {
+			sendWidth = int(float64(filled)*class.SendSpeed/class.TotalSpeed + 0.5)
+			recvWidth = filled - sendWidth
+		}
+		bar := displayer.colorize(strings.Repeat("█", sendWidth), terminal.WithoutMarker(displayer.ColorGreen)) +
+			displayer.colorize(strings.Repeat("█", recvWidth), terminal.WithoutMarker(displayer.ColorBlue)) +
+			strings.Repeat("░", width-filled)
+
+		fmt.Printf("%s%-14s%s [%s] %5.1f%%",
+			displayer.colorize("", displayer.ColorBold),
+			class.Class,
+			displayer.colorize("", displayer.ColorReset),
+			bar,
+			class.Share)
+		if data.PortTrafficMeasured {
+			fmt.Printf("  ↑ %s  ↓ %s",
+				formatSpeed(class.SendSpeed, data.RateUnit),
+				formatSpeed(class.RecvSpeed, data.RateUnit))
+		}
+		fmt.Printf("  %d conn (%d in, %d out)  ports %s\n",
+			class.Connections,
+			class.Inbound,
+			class.Outbound,
+			formatPorts(class.Ports, ", "))
+	}
+}
+
+// formatConnectionAge formats a connection age compactly, e.g. "45s", "12m30s" or "3h05m"
+// Ages of connections that were open before tracking started are minimums and get a ">" prefix
+func (displayer *NetworkMonitorDisplayer) formatConnectionAge(conn NetworkConnectionInfo) string {
+	prefix := ""
+	if conn.AgeLowerBound {
+		prefix = ">"
+	}
+
+	age := conn.Age.Round(time.Second)
+	switch {
+	case age >= 24*time.Hour:
+		return fmt.Sprintf("%s%dd%02dh", prefix, int(age.Hours())/24, int(age.Hours())%24)
+	case age >= time.Hour:
+		return fmt.Sprintf("%s%dh%02dm", prefix, int(age.Hours()), int(age.Minutes())%60)
+	case age >= time.Minute:
+		return fmt.Sprintf("%s%dm%02ds", prefix, int(age.Minutes()), int(age.Seconds())%60)
+	}
+	return fmt.Sprintf("%s%ds", prefix, int(age.Seconds()))
+}
+
+// displayConnectivityInfo displays the proxy configuration and captive portal probe result
+func (displayer *NetworkMonitorDisplayer) displayConnectivityInfo(data *NetworkMonitorData) {
+	fmt.Println("\n🧭 PROXY & CAPTIVE PORTAL")
+	fmt.Println(displayer.sectionRule())
+
+	// Proxy
+	if data.Proxy.Enabled {
+		fmt.Printf("%sProxy: %s%s%s (%s)\n",
+			displayer.colorize("", displayer.ColorBold),
+			displayer.colorize("", displayer.ColorYellow),
+			describeProxy(data.Proxy),
+			displayer.colorize("", displayer.ColorReset),
+			data.Proxy.Source)
+		if data.Proxy.NoProxy != "" {
+			fmt.Printf("  Bypass: %s\n", data.Proxy.NoProxy)
+		}
+	} else {
+		fmt.Printf("%sProxy: %sNone (direct connection)%s\n",
+			displayer.colorize("", displayer.ColorBold),
+			displayer.colorize("", displayer.ColorGreen),
+			displayer.colorize("", displayer.ColorReset))
+	}
+
+	// Captive portal probe
+	portal := data.CaptivePortal
+	switch {
+	case portal.Detected:
+		fmt.Printf("%sCaptive Portal: %sDETECTED (HTTP %d)%s\n",
+			displayer.colorize("", displayer.ColorBold),
+			displayer.colorize("", displayer.ColorRed),
+			portal.StatusCode,
+			displayer.colorize("", displayer.ColorReset))
+		if portal.RedirectURL != "" {
+			fmt.Printf("  Sign-in page: %s\n", portal.RedirectURL)
+		}
+	case portal.StatusCode == http.StatusProxyAuthRequired:
+		fmt.Printf("%sCaptive Portal: %sNone (a proxy on the network requires authentication, HTTP 407)%s\n",
+			displayer.colorize("", displayer.ColorBold),
+			displayer.colorize("", displayer.ColorYellow),
+			displayer.colorize("", displayer.ColorReset))
+	case portal.Reachable:
+		fmt.Printf("%sCaptive Portal: %sNone (internet reachable)%s\n",
+			displayer.colorize("", displayer.ColorBold),
+			displayer.colorize("", displayer.ColorGreen),
+			displayer.colorize("", displayer.ColorReset))
+	default:
+		fmt.Printf("%sCaptive Portal: %sProbe failed%s\n",
+			displayer.colorize("", displayer.ColorBold),
+			displayer.colorize("", displayer.ColorYellow),
+			displayer.colorize("", displayer.ColorReset))
+		fmt.Printf("  %s\n", portal.Error)
+	}
+
+	// Probe through the configured proxy
+	if data.Proxy.Enabled && portal.ProxyStatus != "" {
+		color := displayer.ColorGreen
+		status := portal.ProxyStatus
+		switch portal.ProxyStatus {
+		case ProxyAuthRequired:
+			color = displayer.ColorYellow
+			status = "Requires authentication (HTTP 407)"
+		case ProxyFailed:
+			color = displayer.ColorRed
+		}
+		fmt.Printf("%sThrough Proxy: %s%s%s\n",
+			displayer.colorize("", displayer.ColorBold),
+			displayer.colorize("", color),
+			status,
+			displayer.colorize("", displayer.ColorReset))
+		if portal.ProxyError != "" {
+			fmt.Printf("  %s\n", portal.ProxyError)
+		}
+	}
+}
+
+// describeProxy returns a short description of the configured proxy
+func describeProxy(proxy NetworkProxyInfo) string {
+	switch {
+	case proxy.HTTPProxy != "" && proxy.HTTPSProxy != "" && proxy.HTTPProxy != proxy.HTTPSProxy:
+		return "HTTP " + proxy.HTTPProxy + ", HTTPS " + proxy.HTTPSProxy
+	case proxy.HTTPProxy != "":
+		return proxy.HTTPProxy
+	case proxy.HTTPSProxy != "":
+		return proxy.HTTPSProxy
+	case proxy.AutoConfigURL != "":
+		return "PAC " + proxy.AutoConfigURL
+	}
+	return "configured"
+}
+
+// displayGatewayInfo displays default gateway reachability and MAC address changes
+func (displayer *NetworkMonitorDisplayer) displayGatewayInfo(data *NetworkMonitorData) {
+	gateway := data.Gateway
+
+	fmt.Println("\n🚪 DEFAULT GATEWAY")
+	fmt.Println(displayer.sectionRule())
+
+	fmt.Printf("%sGateway: %s%s (%s)%s\n",
+		displayer.colorize("", displayer.ColorBold),
+		displayer.colorize("", displayer.ColorWhite),
+		gateway.Address,
+		gateway.Interface,
+		displayer.colorize("", displayer.ColorReset))
+
+	mac := gateway.MACAddress
+	if mac == "" {
+		mac = "unknown"
+	}
+	fmt.Printf("%sMAC Address: %s%s%s\n",
+		displayer.colorize("", displayer.ColorBold),
+		displayer.colorize("", displayer.ColorCyan),
+		mac,
+		displayer.colorize("", displayer.ColorReset))
+
+	reachability := "Unreachable"
+	if gateway.Reachable {
+		reachability = "Reachable"
+		if gateway.Method == "ping" {
+			reachability += fmt.Sprintf(" (%.2f ms)", gateway.RTT)
+		}
+	}
+	fmt.Printf("%sReachability: %s%s%s via %s\n",
+		displayer.colorize("", displayer.ColorBold),
+		displayer.getGatewayColor(gateway),
+		reachability,
+		displayer.colorize("", displayer.ColorReset),
+		gateway.Method)
+
+	if !gateway.Reachable {
+		lastSeen := "never"
+		if !gateway.LastReachable.IsZero() {
+			lastSeen = data.Timestamp.Sub(gateway.LastReachable).Round(time.Second).String() + " ago"
+		}
+		fmt.Printf("%sFailed Checks: %s%d%s (last reachable %s)\n",
+			displayer.colorize("", displayer.ColorBold),
+			displayer.colorize("", displayer.ColorRed),
+			gateway.ConsecutiveFailures,
+			displayer.colorize("", displayer.ColorReset),
+			lastSeen)
+	}
+
+	for _, change := range gateway.MACChanges {
+		fmt.Printf("%s⚠️  MAC changed %s: %s -> %s%s\n",
+			displayer.colorize("", displayer.ColorYellow),
+			change.Timestamp.Format("15:04:05"),
+			change.OldMAC,
+			change.NewMAC,
+			displayer.colorize("", displayer.ColorReset))
+	}
+}
+
+// displayFirewallInfo displays the top firewall rules by hits and dropped-packet totals
+func (displayer *NetworkMonitorDisplayer) displayFirewallInfo(data *NetworkMonitorData) {
+	firewall := data.Firewall
+
+	fmt.Printf("\n🧱 FIREWALL (%s)\n", firewall.Backend)
+	fmt.Println(displayer.rule("-"))
+
+	if !firewall.Available {
+		fmt.Printf("%sCounters unavailable: %s%s\n",
+			displayer.colorize("", displayer.ColorYellow),
+			firewall.Error,
+			displayer.colorize("", displayer.ColorReset))
+		return
+	}
+
+	fmt.Printf("%sDropped: %s%d packets (%s), %d since last check%s\n",
+		displayer.colorize("", displayer.ColorBold),
+		displayer.getFirewallDropColor(firewall.RecentDrops),
+		firewall.DroppedPackets,
+		displayer.formatBytes(firewall.DroppedBytes),
+		firewall.RecentDrops,
+		displayer.colorize("", displayer.ColorReset))
+
+	if len(firewall.Rules) == 0 {
+		return
+	}
+
+	// Header
+	fmt.Printf("\n%s%-12s %-10s %-10s %-10s %s%s\n",
+		displayer.colorize("", displayer.ColorBold),
+		"Chain",
+		"Action",
+		"Packets",
+		"Bytes",
+		"Rule",
+		displayer.colorize("", displayer.ColorReset))
+
+	fmt.Println(displayer.rule("-"))
+
+	for _, rule := range firewall.Rules {
+		spec := terminal.Truncate(rule.Rule, displayer.lineWidth()-46)
+
+		actionColor := displayer.colorize("", displayer.ColorGreen)
+		if rule.Dropped {
+			actionColor = displayer.colorize("", displayer.ColorRed)
+		}
+
+		fmt.Printf("%-12s %s%-10s%s %-10d %-10s %s\n",
+			rule.Chain,
+			actionColor,
+			rule.Action,
+			displayer.colorize("", displayer.ColorReset),
+			rule.Packets,
+			displayer.formatBytes(rule.Bytes),
+			spec)
+	}
+}
+
+// displayVPNInfo displays VPN/tunnel interface health
+func (displayer *NetworkMonitorDisplayer) displayVPNInfo(data *NetworkMonitorData) {
+	fmt.Println("\n🔐 VPN / TUNNELS")
+	fmt.Println(displayer.rule("-"))
+
+	// Header
+	fmt.Printf("%s%-12s %-10s %-8s %-14s %-10s %-10s %s%s\n",
+		displayer.colorize("", displayer.ColorBold),
+		"Interface",
+		"Type",
+		"Status",
+		"Handshake",
+		"Sent",
+		"Received",
+		"Endpoint",
+		displayer.colorize("", displayer.ColorReset))
+
+	fmt.Println(displayer.rule("-"))
+
+	for _, tunnel := range data.VPNTunnels {
+		handshake := "n/a"
+		if !tunnel.LastHandshake.IsZero() {
+			handshake = tunnel.HandshakeAge.Round(time.Second).String() + " ago"
+		} else if tunnel.Peers > 0 {
+			handshake = "never"
+		}
+
+		endpoint := tunnel.Endpoint
+		if endpoint == "" {
+			endpoint = "-"
+		}
+
+		fmt.Printf("%-12s %-10s %s%-8s%s %-14s %-10s %-10s %s\n",
+			tunnel.Interface,
+			tunnel.Type,
+			displayer.getTunnelStatusColor(tunnel.Status),
+			tunnel.Status,
+			displayer.colorize("", displayer.ColorReset),
+			handshake,
+			displayer.formatBytes(tunnel.BytesSent),
+			displayer.formatBytes(tunnel.BytesRecv),
+			endpoint)
+	}
+
+	// Tunnel vs direct traffic
+	if data.VPNTraffic != nil {
+		displayer.displayVPNTraffic(data.VPNTraffic, data.RateUnit)
+	}
+}
+
+// displayVPNTraffic displays how throughput splits between the tunnels and traffic bypassing them
+func (displayer *NetworkMonitorDisplayer) displayVPNTraffic(traffic *VPNTrafficInfo, unit string) {
+	directColor := displayer.getDirectTrafficColor(traffic)
+
+	fmt.Printf("\n%sVia Tunnel:%s %s   %sDirect:%s %s%s (%.1f%%)%s\n",
+		displayer.colorize("", displayer.ColorBold),
+		displayer.colorize("", displayer.ColorReset),
+		formatSpeed(traffic.TunnelSpeed, unit),
+		displayer.colorize("", displayer.ColorBold),
+		displayer.colorize("", displayer.ColorReset),
+		directColor,
+		formatSpeed(traffic.DirectSpeed, unit),
+		traffic.DirectShare,
+		displayer.colorize("", displayer.ColorReset))
+
+	if len(traffic.BypassConnections) == 0 {
+		return
+	}
+
+	fmt.Printf("%sBypassing the tunnel:%s\n",
+		displayer.colorize("", displayer.ColorBold),
+		displayer.colorize("", displayer.ColorReset))
+	for i, connection := range traffic.BypassConnections {
+		if i >= 5 {
+			fmt.Printf("  ... and %d more\n", len(traffic.BypassConnections)-i)
+			break
+		}
+		fmt.Printf("  %s%-20s%s PID %-7d %s %s → %s\n",
+			directColor,
+			connection.ProcessName,
+			displayer.colorize("", displayer.ColorReset),
+			connection.PID,
+			connection.Type,
+			connection.LocalAddress,
+			connection.RemoteAddress)
+	}
 }
 
 // displayLatencyInfo displays network latency information
 func (displayer *NetworkMonitorDisplayer) displayLatencyInfo(data *NetworkMonitorData) {
 	fmt.Println("\n⏱️  NETWORK LATENCY")
-	fmt.Println(strings.Repeat("-", 50))
+	fmt.Println(displayer.sectionRule())
 
 	for _, latency := range data.LatencyInfo {
 		fmt.Printf("%sTarget: %s%s%s\n",
@@ -326,6 +803,15 @@ func (displayer *NetworkMonitorDisplayer) displayLatencyInfo(data *NetworkMonito
 			latency.PacketLoss,
 			displayer.colorize("", displayer.ColorReset))
 
+		if latency.Samples > 0 {
+			fmt.Printf("%sPercentiles: %sp50 %.2f ms%s  %sp95 %.2f ms%s  %sp99 %.2f ms%s (%d samples)\n",
+				displayer.colorize("", displayer.ColorBold),
+				displayer.getLatencyColor(latency.P50), latency.P50, displayer.colorize("", displayer.ColorReset),
+				displayer.getLatencyColor(latency.P95), latency.P95, displayer.colorize("", displayer.ColorReset),
+				displayer.getLatencyColor(latency.P99), latency.P99, displayer.colorize("", displayer.ColorReset),
+				latency.Samples)
+		}
+
 		// Latency bar
 		latencyPercent := (latency.Latency / 200.0) * 100 // Normalize to 200ms
 		if latencyPercent > 100 {
@@ -346,41 +832,41 @@ func (displayer *NetworkMonitorDisplayer) displayLatencyInfo(data *NetworkMonito
 // displayBandwidthInfo displays bandwidth usage information
 func (displayer *NetworkMonitorDisplayer) displayBandwidthInfo(data *NetworkMonitorData) {
 	fmt.Println("\n📈 BANDWIDTH USAGE")
-	fmt.Println(strings.Repeat("-", 50))
+	fmt.Println(displayer.sectionRule())
 
 	// Bandwidth utilization bar
 	displayer.displayUsageBar("Bandwidth Usage", data.BandwidthInfo.Utilization, displayer.getUtilizationColor(data.BandwidthInfo.Utilization))
 
 	// Bandwidth details
-	fmt.Printf("\n%sTotal Bandwidth: %s%.2f Mbps%s\n",
+	fmt.Printf("\n%sTotal Bandwidth: %s%s%s\n",
 		displayer.colorize("", displayer.ColorBold),
 		displayer.colorize("", displayer.ColorWhite),
-		data.BandwidthInfo.TotalBandwidth,
+		formatSpeed(data.BandwidthInfo.TotalBandwidth, data.RateUnit),
 		displayer.colorize("", displayer.ColorReset))
 
-	fmt.Printf("%sUsed Bandwidth: %s%.2f Mbps%s\n",
+	fmt.Printf("%sUsed Bandwidth: %s%s%s\n",
 		displayer.colorize("", displayer.ColorBold),
 		displayer.colorize("", displayer.ColorYellow),
-		data.BandwidthInfo.UsedBandwidth,
+		formatSpeed(data.BandwidthInfo.UsedBandwidth, data.RateUnit),
 		displayer.colorize("", displayer.ColorReset))
 
-	fmt.Printf("%sAvailable Bandwidth: %s%.2f Mbps%s\n",
+	fmt.Printf("%sAvailable Bandwidth: %s%s%s\n",
 		displayer.colorize("", displayer.ColorBold),
 		displayer.colorize("", displayer.ColorGreen),
-		data.BandwidthInfo.AvailableBandwidth,
+		formatSpeed(data.BandwidthInfo.AvailableBandwidth, data.RateUnit),
 		displayer.colorize("", displayer.ColorReset))
 
-	fmt.Printf("%sPeak Usage: %s%.2f Mbps%s\n",
+	fmt.Printf("%sPeak Usage: %s%s%s\n",
 		displayer.colorize("", displayer.ColorBold),
 		displayer.colorize("", displayer.ColorMagenta),
-		data.BandwidthInfo.PeakUsage,
+		formatSpeed(data.BandwidthInfo.PeakUsage, data.RateUnit),
 		displayer.colorize("", displayer.ColorReset))
 }
 
 // displayPerformanceMetrics displays network performance metrics
 func (displayer *NetworkMonitorDisplayer) displayPerformanceMetrics(data *NetworkMonitorData) {
 	fmt.Println("\n📊 PERFORMANCE METRICS")
-	fmt.Println(strings.Repeat("-", 50))
+	fmt.Println(displayer.sectionRule())
 
 	// Average latency
 	displayer.displayUsageBar("Average Latency", data.AverageLatency, displayer.getLatencyColor(data.AverageLatency))
@@ -414,10 +900,10 @@ func (displayer *NetworkMonitorDisplayer) displayPerformanceMetrics(data *Networ
 // displayTopProcesses displays top network-consuming processes
 func (displayer *NetworkMonitorDisplayer) displayTopProcesses(data *NetworkMonitorData) {
 	fmt.Println("\n🔥 TOP NETWORK PROCESSES")
-	fmt.Println(strings.Repeat("-", 80))
+	fmt.Println(displayer.rule("-"))
 
 	// Header
-	fmt.Printf("%s%-8s %-20s %-12s %-12s %-8s %-8s %s\n",
+	fmt.Printf("%s%-8s %-20s %-14s %-14s %-8s %-8s %s\n",
 		displayer.colorize("", displayer.ColorBold),
 		"PID",
 		"Name",
@@ -427,7 +913,7 @@ func (displayer *NetworkMonitorDisplayer) displayTopProcesses(data *NetworkMonit
 		"Connections",
 		displayer.colorize("", displayer.ColorReset))
 
-	fmt.Println(strings.Repeat("-", 80))
+	fmt.Println(displayer.rule("-"))
 
 	// Display processes
 	for i, process := range data.TopProcesses {
@@ -442,16 +928,16 @@ func (displayer *NetworkMonitorDisplayer) displayTopProcesses(data *NetworkMonit
 		}
 
 		// Color code based on total speed
-		speedColor := displayer.getNetworkSpeedColor(process.TotalSpeed)
+		speedColor := displayer.getNetworkSpeedColor(fromRateUnit(process.TotalSpeed, data.RateUnit))
 
-		fmt.Printf("%s%-8d %-20s %s%-12s %s%-12s %s%-8.2f %s%-8d %s\n",
+		fmt.Printf("%s%-8d %-20s %s%-14s %s%-14s %s%-8.2f %s%-8d %s\n",
 			displayer.colorize("", displayer.ColorBold),
 			process.PID,
 			name,
 			displayer.colorize("", displayer.ColorGreen),
-			fmt.Sprintf("%.2f Mbps", process.SendSpeed),
+			formatSpeed(process.SendSpeed, data.RateUnit),
 			displayer.colorize("", displayer.ColorBlue),
-			fmt.Sprintf("%.2f Mbps", process.RecvSpeed),
+			formatSpeed(process.RecvSpeed, data.RateUnit),
 			speedColor,
 			process.TotalSpeed,
 			displayer.colorize("", displayer.ColorWhite),
@@ -463,7 +949,7 @@ func (displayer *NetworkMonitorDisplayer) displayTopProcesses(data *NetworkMonit
 // displayNetworkStatus displays network status and alerts
 func (displayer *NetworkMonitorDisplayer) displayNetworkStatus(data *NetworkMonitorData) {
 	fmt.Println("\n🚨 NETWORK STATUS & ALERTS")
-	fmt.Println(strings.Repeat("-", 50))
+	fmt.Println(displayer.sectionRule())
 
 	// Network status
 	statusColor := displayer.getNetworkStatusColor(data.NetworkStatus)
@@ -473,6 +959,22 @@ func (displayer *NetworkMonitorDisplayer) displayNetworkStatus(data *NetworkMoni
 		data.NetworkStatus,
 		displayer.colorize("", displayer.ColorReset))
 
+	// Plain explanation when failures have a known cause
+	if data.StatusExplanation != "" {
+		fmt.Printf("%sℹ️  %s%s\n",
+			displayer.colorize("", displayer.ColorCyan),
+			data.StatusExplanation,
+			displayer.colorize("", displayer.ColorReset))
+	}
+
+	// Captive portal warning
+	if data.CaptivePortalWarning {
+		fmt.Printf("%s🏨 Captive Portal: %sSIGN-IN REQUIRED%s\n",
+			displayer.colorize("", displayer.ColorBold),
+			displayer.colorize("", displayer.ColorRed),
+			displayer.colorize("", displayer.ColorReset))
+	}
+
 	// High latency warning
 	if data.HighLatencyWarning {
 		fmt.Printf("%s⚠️  High Latency Warning: %sACTIVE%s\n",
@@ -486,6 +988,11 @@ func (displayer *NetworkMonitorDisplayer) displayNetworkStatus(data *NetworkMoni
 			displayer.colorize("", displayer.ColorReset))
 	}
 
+	// Probable cause of the latency alert
+	if data.ProbableCause != nil {
+		displayer.displayProbableCause(data.ProbableCause, data.RateUnit)
+	}
+
 	// Packet loss warning
 	if data.PacketLossWarning {
 		fmt.Printf("%s📦 Packet Loss Warning: %sACTIVE%s\n",
@@ -524,11 +1031,224 @@ func (displayer *NetworkMonitorDisplayer) displayNetworkStatus(data *NetworkMoni
 			displayer.colorize("", displayer.ColorGreen),
 			displayer.colorize("", displayer.ColorReset))
 	}
+
+	// Gateway warnings
+	if data.GatewayLossWarning {
+		fmt.Printf("%s🚪 Gateway Warning: %sUNREACHABLE%s\n",
+			displayer.colorize("", displayer.ColorBold),
+			displayer.colorize("", displayer.ColorRed),
+			displayer.colorize("", displayer.ColorReset))
+	} else if data.Gateway.Address != "" {
+		fmt.Printf("%s✅ Gateway Status: %sREACHABLE%s\n",
+			displayer.colorize("", displayer.ColorBold),
+			displayer.colorize("", displayer.ColorGreen),
+			displayer.colorize("", displayer.ColorReset))
+	}
+	if data.GatewayMACWarning {
+		fmt.Printf("%s🕵️  Gateway MAC Warning: %sMAC CHANGED (POSSIBLE ARP SPOOFING)%s\n",
+			displayer.colorize("", displayer.ColorBold),
+			displayer.colorize("", displayer.ColorRed),
+			displayer.colorize("", displayer.ColorReset))
+	}
+
+	// VPN warning
+	if data.VPNWarning {
+		fmt.Printf("%s🔐 VPN Warning: %sTUNNEL DOWN OR STALE%s\n",
+			displayer.colorize("", displayer.ColorBold),
+			displayer.colorize("", displayer.ColorRed),
+			displayer.colorize("", displayer.ColorReset))
+	} else if len(data.VPNTunnels) > 0 {
+		fmt.Printf("%s✅ VPN Status: %sNORMAL%s\n",
+			displayer.colorize("", displayer.ColorBold),
+			displayer.colorize("", displayer.ColorGreen),
+			displayer.colorize("", displayer.ColorReset))
+	}
+	if data.VPNBypassWarning {
+		fmt.Printf("%s🔓 VPN Bypass Warning: %sTRAFFIC BYPASSING THE TUNNEL%s\n",
+			displayer.colorize("", displayer.ColorBold),
+			displayer.colorize("", displayer.ColorYellow),
+			displayer.colorize("", displayer.ColorReset))
+	}
+}
+
+// displayProbableCause displays the likely bandwidth hog behind a high-latency alert
+func (displayer *NetworkMonitorDisplayer) displayProbableCause(cause *NetworkProbableCause, unit string) {
+	usual := "no usual traffic"
+	if cause.BaselineRate > 0 {
+		usual = fmt.Sprintf("%.1fx its usual %s", cause.Rate/cause.BaselineRate, formatByteRate(cause.BaselineRate, unit))
+	}
+
+	fmt.Printf("%s🐢 Probable Cause: %s%s (PID %d) at %s%s, %s, %.0f%% of process %s\n",
+		displayer.colorize("", displayer.ColorBold),
+		displayer.colorize("", displayer.ColorYellow),
+		cause.Name,
+		cause.PID,
+		formatByteRate(cause.Rate, unit),
+		displayer.colorize("", displayer.ColorReset),
+		usual,
+		cause.TrafficShare,
+		causeMeasure(cause))
+
+	if cause.BaselineLatency > 0 {
+		fmt.Printf("   Latency %.0f ms vs %.0f ms normally\n", cause.Latency, cause.BaselineLatency)
+	}
+}
+
+// causeMeasure names what a probable cause's rate counts
+func causeMeasure(cause *NetworkProbableCause) string {
+	if cause.Source == CauseSourceIO {
+		return "disk and network I/O"
+	}
+	return "traffic"
+}
+
+// DisplayPingResult displays the replies of an on-demand ping
+func (displayer *NetworkMonitorDisplayer) DisplayPingResult(result *PingResult) {
+	fmt.Printf("\n📡 PING %s (%s)\n", result.Host, result.Address)
+	fmt.Println(displayer.sectionRule())
+
+	for i, rtt := range result.RTTs {
+		if rtt < 0 {
+			fmt.Printf("Probe %-3d %s\n", i+1, displayer.colorize("no reply", displayer.severityColor(displayer.ColorRed)))
+			continue
+		}
+		fmt.Printf("Probe %-3d %s\n", i+1, displayer.colorize(fmt.Sprintf("%.2f ms", rtt), displayer.getLatencyColor(rtt)))
+	}
+
+	fmt.Printf("\n%sSent: %s%d, received %d%s\n",
+		displayer.colorize("", displayer.ColorBold),
+		displayer.colorize("", displayer.ColorWhite),
+		result.Sent,
+		result.Received,
+		displayer.colorize("", displayer.ColorReset))
+
+	fmt.Printf("%sPacket Loss: %s%.0f%%%s\n",
+		displayer.colorize("", displayer.ColorBold),
+		displayer.getPacketLossColor(result.Loss),
+		result.Loss,
+		displayer.colorize("", displayer.ColorReset))
+
+	if result.Received > 0 {
+		fmt.Printf("%sRound Trip: %smin %.2f / avg %.2f / max %.2f ms%s\n",
+			displayer.colorize("", displayer.ColorBold),
+			displayer.getLatencyColor(result.AvgRTT),
+			result.MinRTT,
+			result.AvgRTT,
+			result.MaxRTT,
+			displayer.colorize("", displayer.ColorReset))
+	}
+	fmt.Printf("Method: %s\n", result.Method)
+}
+
+// DisplayTraceResult displays the hops of an on-demand traceroute with a latency bar per hop, where the
+// latency comes from, and how the route compares with the earlier runs to the same host (oldest first)
+func (displayer *NetworkMonitorDisplayer) DisplayTraceResult(result *TraceResult, earlier []TraceResult) {
+	fmt.Printf("\n🛤️  TRACEROUTE %s (%s)\n", result.Host, result.Address)
+	fmt.Println(displayer.sectionRule())
+
+	var previous *TraceResult
+	if len(earlier) > 0 {
+		previous = &earlier[len(earlier)-1]
+	}
+
+	addressWidth := len("Address")
+	for _, hop := range result.Hops {
+		if len(hop.Address) > addressWidth {
+			addressWidth = len(hop.Address)
+		}
+	}
+	barWidth := displayer.barWidth() / 2
+	if barWidth < 10 {
+		barWidth = 10
+	}
+
+	fmt.Printf("%sHop  %-*s %-7s %-*s %11s %6s  %s%s\n",
+		displayer.colorize("", displayer.ColorBold),
+		addressWidth, "Address", "Segment", barWidth+2, "Latency", "Avg", "Loss", "Change",
+		displayer.colorize("", displayer.ColorReset))
+
+	for _, hop := range result.Hops {
+		if hop.Address == "" {
+			fmt.Printf("%3d  %-*s %-7s %s %11s %6s\n", hop.Hop, addressWidth, "*", hop.Segment, strings.Repeat(" ", barWidth+2), "-", "-")
+			continue
+		}
+
+		color := displayer.getLatencyColor(hop.AvgRTT)
+		filled := int(hop.AvgRTT / 200 * float64(barWidth)) // Normalize to 200ms like the latency section
+		if filled > barWidth {
+			filled = barWidth
+		}
+		bar := strings.Repeat("█", filled) + strings.Repeat("░", barWidth-filled)
+
+		fmt.Printf("%3d  %-*s %-7s [%s] %s %s  %s\n",
+			hop.Hop,
+			addressWidth, hop.Address,
+			hop.Segment,
+			displayer.colorize(bar, terminal.WithoutMarker(color)),
+			displayer.colorize(fmt.Sprintf("%8.2f ms", hop.AvgRTT), color),
+			displayer.colorize(fmt.Sprintf("%5.0f%%", hop.Loss), displayer.getPacketLossColor(hop.Loss)),
+			displayer.formatHopChange(hop, previous))
+	}
+
+	if len(result.Segments) > 0 {
+		fmt.Printf("\n%sLatency added per segment:%s\n", displayer.colorize("", displayer.ColorBold), displayer.colorize("", displayer.ColorReset))
+		for _, segment := range result.Segments {
+			fmt.Printf("  %-7s hops %2d-%-2d  +%.2f ms\n", segment.Name, segment.FirstHop, segment.LastHop, segment.AddedLatency)
+		}
+	}
+
+	fmt.Println()
+	if result.Reached {
+		fmt.Println(displayer.colorize(fmt.Sprintf("✅ Reached %s in %d hops", result.Address, len(result.Hops)), displayer.ColorGreen))
+	} else {
+		fmt.Println(displayer.colorize(fmt.Sprintf("⚠️  %s did not answer within %d hops", result.Address, len(result.Hops)), displayer.ColorYellow))
+	}
+	fmt.Printf("🔎 %s\n", result.Verdict)
+
+	if len(earlier) == 0 {
+		fmt.Println("\nNo earlier runs to this host; the next run will be compared with this one")
+		return
+	}
+	fmt.Printf("\n%sEarlier runs:%s\n", displayer.colorize("", displayer.ColorBold), displayer.colorize("", displayer.ColorReset))
+	for i := len(earlier) - 1; i >= 0 && i >= len(earlier)-5; i-- {
+		run := earlier[i]
+		latency := "no answer"
+		if last := lastAnsweredHop(run.Hops, 0, len(run.Hops)+1); last != nil {
+			latency = fmt.Sprintf("%.2f ms, %.0f%% loss at hop %d", last.AvgRTT, last.Loss, last.Hop)
+		}
+		fmt.Printf("  %s  %2d hops  %s\n", run.Timestamp.Format("2006-01-02 15:04"), len(run.Hops), latency)
+	}
+}
+
+// formatHopChange describes how a hop differs from the same hop of the previous run
+func (displayer *NetworkMonitorDisplayer) formatHopChange(hop TraceHop, previous *TraceResult) string {
+	if previous == nil {
+		return ""
+	}
+	for _, before := range previous.Hops {
+		if before.Hop != hop.Hop {
+			continue
+		}
+		if before.Address == "" {
+			return "(no answer before)"
+		}
+		if before.Address != hop.Address {
+			return displayer.colorize("route changed, was "+before.Address, displayer.severityColor(displayer.ColorYellow))
+		}
+
+		change := hop.AvgRTT - before.AvgRTT
+		text := fmt.Sprintf("%+.2f ms", change)
+		if change >= 10 {
+			return displayer.colorize(text, displayer.severityColor(displayer.ColorYellow))
+		}
+		return text
+	}
+	return "(new hop)"
 }
 
 // displayUsageBar displays a graphical usage bar
 func (displayer *NetworkMonitorDisplayer) displayUsageBar(label string, value float64, color string, customWidth ...int) {
-	width := displayer.BarWidth
+	width := displayer.barWidth()
 	if len(customWidth) > 0 {
 		width = customWidth[0]
 	}
@@ -542,20 +1262,40 @@ func (displayer *NetworkMonitorDisplayer) displayUsageBar(label string, value fl
 	// Create bar
 	bar := strings.Repeat("█", filledWidth) + strings.Repeat("░", width-filledWidth)
 
+	// Category colors (e.g. buffers, cache) have no marker; with colors off only a severity marker is printed
+	if !displayer.ShowColors {
+		color = terminal.MarkerOf(color)
+	}
+
 	fmt.Printf("%s%-20s %s[%s]%s %s%.2f%s\n",
 		displayer.colorize("", displayer.ColorBold),
 		label,
 		displayer.colorize("", displayer.ColorWhite),
-		displayer.colorize(bar, color),
+		displayer.colorize(bar, terminal.WithoutMarker(color)),
 		displayer.colorize("", displayer.ColorWhite),
 		color,
 		value,
 		displayer.colorize("", displayer.ColorReset))
 }
 
+// displayAlertNotes shows the note configured for each raised alert, so the next step is on screen
+func (displayer *NetworkMonitorDisplayer) displayAlertNotes(data *NetworkMonitorData) {
+	fmt.Println("\n📘 RUNBOOK NOTES")
+	fmt.Println(displayer.sectionRule())
+
+	for _, note := range data.AlertNotes {
+		fmt.Printf("%s%s (%s):%s %s\n",
+			displayer.getNetworkStatusColor(note.Level),
+			note.Key,
+			note.Level,
+			displayer.colorize("", displayer.ColorReset),
+			note.Text)
+	}
+}
+
 // displayFooter displays the network monitor footer
 func (displayer *NetworkMonitorDisplayer) displayFooter(data *NetworkMonitorData) {
-	fmt.Println(strings.Repeat("=", 80))
+	fmt.Println(displayer.rule("="))
 	fmt.Printf("%sLast Updated: %s%s%s\n",
 		displayer.colorize("", displayer.ColorBold),
 		displayer.colorize("", displayer.ColorCyan),
@@ -568,7 +1308,11 @@ func (displayer *NetworkMonitorDisplayer) displayFooter(data *NetworkMonitorData
 		data.RefreshInterval.Seconds(),
 		displayer.colorize("", displayer.ColorReset))
 
-	fmt.Println(strings.Repeat("=", 80))
+	if !displayer.ShowColors || terminal.ColorBlindMode() {
+		fmt.Println(terminal.SeverityLegend())
+	}
+
+	fmt.Println(displayer.rule("="))
 }
 
 // formatBytes formats bytes into human-readable format
@@ -585,117 +1329,220 @@ func (displayer *NetworkMonitorDisplayer) formatBytes(bytes uint64) string {
 	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
 }
 
+// displayUnavailable shows a section that could not be collected, with the reason
+func (displayer *NetworkMonitorDisplayer) displayUnavailable(title, reason string) {
+	fmt.Println("\n" + title)
+	fmt.Println(displayer.sectionRule())
+	fmt.Println(displayer.colorize("⚠️  "+partial.Unavailable(reason), displayer.ColorYellow))
+}
+
+// lineWidth returns the layout width: the terminal width, or 80 columns when AutoWidth is off
+func (displayer *NetworkMonitorDisplayer) lineWidth() int {
+	if !displayer.AutoWidth {
+		return terminal.DefaultWidth
+	}
+	return terminal.Fit(terminal.Width(), 40, 240)
+}
+
+// rule returns a separator line across the layout width
+func (displayer *NetworkMonitorDisplayer) rule(char string) string {
+	return strings.Repeat(char, displayer.lineWidth())
+}
+
+// sectionRule returns a section underline, shortened on narrow terminals
+func (displayer *NetworkMonitorDisplayer) sectionRule() string {
+	return strings.Repeat("-", terminal.Fit(displayer.lineWidth(), 0, 50))
+}
+
+// barWidth returns the progress bar width, leaving room for the label and value on the same line
+func (displayer *NetworkMonitorDisplayer) barWidth() int {
+	if !displayer.AutoWidth {
+		return displayer.BarWidth
+	}
+	return terminal.Fit(displayer.lineWidth()-36, 10, 100)
+}
+
 // colorize applies color to text if colors are enabled
 func (displayer *NetworkMonitorDisplayer) colorize(text, color string) string {
 	if !displayer.ShowColors {
-		return text
+		// Severity colors are reduced to their marker
+		return terminal.MarkerOf(color) + text
 	}
-	return color + text + displayer.ColorReset
+	return terminal.PaletteColor(displayer.severityLevel(color), color) + text + displayer.ColorReset
 }
 
-// getNetworkStatusColor returns the appropriate color for network status
-func (displayer *NetworkMonitorDisplayer) getNetworkStatusColor(status string) string {
+// severityColor returns a severity color in the active palette, followed by its marker when markers are shown
+// Markers are shown in color-blind mode and whenever colors are off, so severity never depends on color alone
+func (displayer *NetworkMonitorDisplayer) severityColor(color string) string {
+	level := displayer.severityLevel(color)
+	marker := ""
+	if !displayer.ShowColors || terminal.ColorBlindMode() {
+		marker = terminal.SeverityMarker(level)
+	}
 	if !displayer.ShowColors {
-		return ""
+		return marker
 	}
+	return terminal.PaletteColor(level, color) + marker
+}
 
+// severityLevel returns the severity a displayer color stands for
+func (displayer *NetworkMonitorDisplayer) severityLevel(color string) terminal.Severity {
+	switch color {
+	case displayer.ColorGreen:
+		return terminal.SeverityOK
+	case displayer.ColorYellow:
+		return terminal.SeverityWarning
+	case displayer.ColorMagenta:
+		return terminal.SeverityHigh
+	case displayer.ColorRed:
+		return terminal.SeverityCritical
+	}
+	return terminal.SeverityNone
+}
+
+// getNetworkStatusColor returns the appropriate color for network status
+func (displayer *NetworkMonitorDisplayer) getNetworkStatusColor(status string) string {
 	switch status {
 	case "Normal":
-		return displayer.ColorGreen
+		return displayer.severityColor(displayer.ColorGreen)
 	case "Warning":
-		return displayer.ColorYellow
+		return displayer.severityColor(displayer.ColorYellow)
 	case "Critical":
-		return displayer.ColorRed
+		return displayer.severityColor(displayer.ColorRed)
 	default:
-		return displayer.ColorWhite
+		return displayer.severityColor(displayer.ColorWhite)
 	}
 }
 
 // getInterfaceStatusColor returns the appropriate color for interface status
 func (displayer *NetworkMonitorDisplayer) getInterfaceStatusColor(status string) string {
-	if !displayer.ShowColors {
-		return ""
-	}
-
 	switch status {
 	case "up":
-		return displayer.ColorGreen
+		return displayer.severityColor(displayer.ColorGreen)
 	case "down":
-		return displayer.ColorRed
+		return displayer.severityColor(displayer.ColorRed)
 	default:
-		return displayer.ColorYellow
+		return displayer.severityColor(displayer.ColorYellow)
 	}
 }
 
 // getUtilizationColor returns the appropriate color for utilization percentage
 func (displayer *NetworkMonitorDisplayer) getUtilizationColor(percentage float64) string {
-	if !displayer.ShowColors {
-		return ""
-	}
-
 	switch {
 	case percentage < 30:
-		return displayer.ColorGreen
+		return displayer.severityColor(displayer.ColorGreen)
 	case percentage < 60:
-		return displayer.ColorYellow
+		return displayer.severityColor(displayer.ColorYellow)
 	case percentage < 80:
-		return displayer.ColorMagenta
+		return displayer.severityColor(displayer.ColorMagenta)
 	default:
-		return displayer.ColorRed
+		return displayer.severityColor(displayer.ColorRed)
 	}
 }
 
-// getLatencyColor returns the appropriate color for latency
-func (displayer *NetworkMonitorDisplayer) getLatencyColor(latency float64) string {
-	if !displayer.ShowColors {
-		return ""
+// getTunnelStatusColor returns the appropriate color for a VPN tunnel status
+func (displayer *NetworkMonitorDisplayer) getTunnelStatusColor(status string) string {
+	switch status {
+	case "Up":
+		return displayer.severityColor(displayer.ColorGreen)
+	case "Stale":
+		return displayer.severityColor(displayer.ColorYellow)
+	case "Down":
+		return displayer.severityColor(displayer.ColorRed)
+	default:
+		return displayer.severityColor(displayer.ColorWhite)
+	}
+}
+
+// getDirectTrafficColor returns the appropriate color for traffic bypassing an active VPN
+func (displayer *NetworkMonitorDisplayer) getDirectTrafficColor(traffic *VPNTrafficInfo) string {
+	switch {
+	case len(traffic.BypassConnections) > 0 || traffic.DirectSpeed > 0:
+		return displayer.severityColor(displayer.ColorYellow)
+	default:
+		return displayer.severityColor(displayer.ColorGreen)
+	}
+}
+
+// getGatewayColor returns the appropriate color for gateway reachability
+func (displayer *NetworkMonitorDisplayer) getGatewayColor(gateway GatewayInfo) string {
+	switch {
+	case gateway.Reachable:
+		return displayer.severityColor(displayer.ColorGreen)
+	case gateway.ConsecutiveFailures > 1:
+		return displayer.severityColor(displayer.ColorRed)
+	default:
+		return displayer.severityColor(displayer.ColorYellow)
 	}
+}
 
+// getFirewallDropColor returns the appropriate color for packets dropped since the last check
+func (displayer *NetworkMonitorDisplayer) getFirewallDropColor(recentDrops uint64) string {
+	switch {
+	case recentDrops == 0:
+		return displayer.severityColor(displayer.ColorGreen)
+	case recentDrops < 100:
+		return displayer.severityColor(displayer.ColorYellow)
+	default:
+		return displayer.severityColor(displayer.ColorRed)
+	}
+}
+
+// getLatencyColor returns the appropriate color for latency
+func (displayer *NetworkMonitorDisplayer) getLatencyColor(latency float64) string {
 	switch {
 	case latency < 50:
-		return displayer.ColorGreen
+		return displayer.severityColor(displayer.ColorGreen)
 	case latency < 100:
-		return displayer.ColorYellow
+		return displayer.severityColor(displayer.ColorYellow)
 	case latency < 200:
-		return displayer.ColorMagenta
+		return displayer.severityColor(displayer.ColorMagenta)
 	default:
-		return displayer.ColorRed
+		return displayer.severityColor(displayer.ColorRed)
 	}
 }
 
 // getLatencyStatusColor returns the appropriate color for latency status
 func (displayer *NetworkMonitorDisplayer) getLatencyStatusColor(status string) string {
-	if !displayer.ShowColors {
-		return ""
-	}
-
 	switch status {
 	case "Good":
-		return displayer.ColorGreen
+		return displayer.severityColor(displayer.ColorGreen)
 	case "Warning":
-		return displayer.ColorYellow
+		return displayer.severityColor(displayer.ColorYellow)
 	case "Critical":
-		return displayer.ColorRed
+		return displayer.severityColor(displayer.ColorRed)
 	default:
-		return displayer.ColorWhite
+		return displayer.severityColor(displayer.ColorWhite)
 	}
 }
 
 // getPacketLossColor returns the appropriate color for packet loss
 func (displayer *NetworkMonitorDisplayer) getPacketLossColor(packetLoss float64) string {
+	switch {
+	case packetLoss < 1:
+		return displayer.severityColor(displayer.ColorGreen)
+	case packetLoss < 5:
+		return displayer.severityColor(displayer.ColorYellow)
+	case packetLoss < 10:
+		return displayer.severityColor(displayer.ColorMagenta)
+	default:
+		return displayer.severityColor(displayer.ColorRed)
+	}
+}
+
+// getConnectionAgeColor returns color for a connection age: new connections yellow, long-lived ones magenta
+func (displayer *NetworkMonitorDisplayer) getConnectionAgeColor(conn NetworkConnectionInfo) string {
 	if !displayer.ShowColors {
 		return ""
 	}
 
 	switch {
-	case packetLoss < 1:
-		return displayer.ColorGreen
-	case packetLoss < 5:
+	case conn.New:
 		return displayer.ColorYellow
-	case packetLoss < 10:
+	case conn.LongLived:
 		return displayer.ColorMagenta
 	default:
-		return displayer.ColorRed
+		return displayer.ColorWhite
 	}
 }
 
@@ -717,20 +1564,16 @@ func (displayer *NetworkMonitorDisplayer) getConnectionTypeColor(connType string
 	}
 }
 
-// getNetworkSpeedColor returns the appropriate color for network speed
+// getNetworkSpeedColor returns the appropriate color for network speed (Mbit/s)
 func (displayer *NetworkMonitorDisplayer) getNetworkSpeedColor(speed float64) string {
-	if !displayer.ShowColors {
-		return ""
-	}
-
 	switch {
 	case speed < 10:
-		return displayer.ColorGreen
+		return displayer.severityColor(displayer.ColorGreen)
 	case speed < 50:
-		return displayer.ColorYellow
+		return displayer.severityColor(displayer.ColorYellow)
 	case speed < 100:
-		return displayer.ColorMagenta
+		return displayer.severityColor(displayer.ColorMagenta)
 	default:
-		return displayer.ColorRed
+		return displayer.severityColor(displayer.ColorRed)
 	}
 }
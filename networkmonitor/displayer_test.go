@@ -0,0 +1,17 @@
+package networkmonitor
+
+import (
+	"testing"
+
+	"github.com/ahmadreza-log/simple-monitor/monitortest"
+)
+
+// TestDisplayNetworkMonitorDataGolden renders testdata/snapshot.json the way the fixtures command does and
+// compares the output with testdata/display.golden
+func TestDisplayNetworkMonitorDataGolden(t *testing.T) {
+	monitortest.Golden(t, func(data *NetworkMonitorData) {
+		displayer := NewNetworkMonitorDisplayer()
+		displayer.ShowColors, displayer.AutoWidth = false, false
+		displayer.DisplayNetworkMonitorData(data)
+	})
+}
@@ -3,8 +3,12 @@ package networkmonitor
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/ahmadreza-log/simple-monitor/exportschema"
+	"github.com/ahmadreza-log/simple-monitor/protoexport"
+	"github.com/ahmadreza-log/simple-monitor/redact"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 )
 
@@ -16,6 +20,8 @@ type NetworkMonitorExporter struct {
 	DateFormat    string // Date format for file naming
 	CreateSubDirs bool   // Whether to create subdirectories for each module
 	PrettyPrint   bool   // Whether to pretty print JSON output
+
+	exportschema.Format // CSV header style and metadata rows
 }
 
 // NewNetworkMonitorExporter creates a new instance of NetworkMonitorExporter
@@ -32,6 +38,10 @@ func NewNetworkMonitorExporter() *NetworkMonitorExporter {
 // ExportToJSON exports network monitoring data to a JSON file
 // The file will be saved with a timestamp-based filename in the appropriate subdirectory
 func (exporter *NetworkMonitorExporter) ExportToJSON(data *NetworkMonitorData, moduleName string) (string, error) {
+	if err := redact.Copy(&data); err != nil {
+		return "", err
+	}
+
 	// Create logs directory if it doesn't exist
 	if err := os.MkdirAll(exporter.LogsDirectory, 0755); err != nil {
 		return "", fmt.Errorf("failed to create logs directory: %w", err)
@@ -75,9 +85,55 @@ func (exporter *NetworkMonitorExporter) ExportToJSON(data *NetworkMonitorData, m
 	return filePath, nil
 }
 
+// ExportToProtobuf exports network monitoring data to a protobuf file
+// The file holds one NetworkMonitorData message; it is smaller and faster to parse than JSON for long archives
+func (exporter *NetworkMonitorExporter) ExportToProtobuf(data *NetworkMonitorData, moduleName string) (string, error) {
+	if err := redact.Copy(&data); err != nil {
+		return "", err
+	}
+
+	// Create logs directory if it doesn't exist
+	if err := os.MkdirAll(exporter.LogsDirectory, 0755); err != nil {
+		return "", fmt.Errorf("failed to create logs directory: %w", err)
+	}
+
+	// Create subdirectory for the module if enabled
+	var targetDir string
+	if exporter.CreateSubDirs {
+		targetDir = filepath.Join(exporter.LogsDirectory, moduleName)
+		if err := os.MkdirAll(targetDir, 0755); err != nil {
+			return "", fmt.Errorf("failed to create module directory: %w", err)
+		}
+	} else {
+		targetDir = exporter.LogsDirectory
+	}
+
+	// Generate filename with timestamp
+	timestamp := time.Now().Format("2006-01-02_15-04-05")
+	filename := fmt.Sprintf("%s_%s.%s", moduleName, timestamp, protoexport.Extension)
+	filePath := filepath.Join(targetDir, filename)
+
+	// Encode as the message `simple-monitor proto` describes
+	content, err := protoexport.Marshal(data)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode protobuf data: %w", err)
+	}
+
+	// Write to file
+	if err := os.WriteFile(filePath, content, 0644); err != nil {
+		return "", fmt.Errorf("failed to write protobuf file: %w", err)
+	}
+
+	return filePath, nil
+}
+
 // ExportToCSV exports network monitoring data to a CSV file
 // This creates a simplified CSV format with key metrics
 func (exporter *NetworkMonitorExporter) ExportToCSV(data *NetworkMonitorData, moduleName string) (string, error) {
+	if err := redact.Copy(&data); err != nil {
+		return "", err
+	}
+
 	// Create logs directory if it doesn't exist
 	if err := os.MkdirAll(exporter.LogsDirectory, 0755); err != nil {
 		return "", fmt.Errorf("failed to create logs directory: %w", err)
@@ -113,6 +169,10 @@ func (exporter *NetworkMonitorExporter) ExportToCSV(data *NetworkMonitorData, mo
 // ExportToTXT exports network monitoring data to a text file
 // This creates a human-readable text format
 func (exporter *NetworkMonitorExporter) ExportToTXT(data *NetworkMonitorData, moduleName string) (string, error) {
+	if err := redact.Copy(&data); err != nil {
+		return "", err
+	}
+
 	// Create logs directory if it doesn't exist
 	if err := os.MkdirAll(exporter.LogsDirectory, 0755); err != nil {
 		return "", fmt.Errorf("failed to create logs directory: %w", err)
@@ -149,11 +209,14 @@ func (exporter *NetworkMonitorExporter) ExportToTXT(data *NetworkMonitorData, mo
 func (exporter *NetworkMonitorExporter) generateCSVContent(data *NetworkMonitorData) string {
 	var content string
 
+	// Metadata rows
+	content += exporter.Metadata(exportschema.Network, true)
+
 	// Header
-	content += "Timestamp,Total Sent,Total Received,Total Throughput,Network Status,Average Latency,Packet Loss Rate,Network Utilization\n"
+	content += exporter.CSVHeader("Timestamp,Total Sent,Total Received,Total Throughput,Network Status,Average Latency,Packet Loss Rate,Network Utilization,Rate Unit", "timestamp,total_bytes_sent,total_bytes_recv,total_throughput,network_status,average_latency,packet_loss_rate,network_utilization,rate_unit")
 
-	// Data row
-	content += fmt.Sprintf("%s,%d,%d,%.2f,%s,%.2f,%.2f,%.2f\n",
+	// Data row; every speed in the export is in the rate unit
+	content += fmt.Sprintf("%s,%d,%d,%.2f,%s,%.2f,%.2f,%.2f,%s\n",
 		data.Timestamp.Format("2006-01-02 15:04:05"),
 		data.TotalBytesSent,
 		data.TotalBytesRecv,
@@ -161,12 +224,13 @@ func (exporter *NetworkMonitorExporter) generateCSVContent(data *NetworkMonitorD
 		data.NetworkStatus,
 		data.AverageLatency,
 		data.PacketLossRate,
-		data.NetworkUtilization)
+		data.NetworkUtilization,
+		normalizeRateUnit(data.RateUnit))
 
 	// Interface data
 	if len(data.Interfaces) > 0 {
-		content += "\nInterface Data\n"
-		content += "Name,Type,Status,IP Address,MAC Address,Speed,Is Up,Is Loopback,Is Virtual\n"
+		content += exporter.CSVSection("Interface Data", "interfaces")
+		content += exporter.CSVHeader("Name,Type,Status,IP Address,MAC Address,Speed,Is Up,Is Loopback,Is Virtual", "name,type,status,ip_address,mac_address,speed,is_up,is_loopback,is_virtual")
 		for _, iface := range data.Interfaces {
 			content += fmt.Sprintf("%s,%s,%s,%s,%s,%d,%t,%t,%t\n",
 				iface.Name,
@@ -179,12 +243,29 @@ func (exporter *NetworkMonitorExporter) generateCSVContent(data *NetworkMonitorD
 				iface.IsLoopback,
 				iface.IsVirtual)
 		}
+
+		content += exporter.CSVSection("Interface Addresses", "interface_addresses")
+		content += exporter.CSVHeader("Interface,Address,Family,Prefix Length,Subnet Mask,Scope,Permanent,Valid Lifetime,Preferred Lifetime", "interface,address,family,prefix_length,subnet_mask,scope,permanent,valid_lifetime,preferred_lifetime")
+		for _, iface := range data.Interfaces {
+			for _, address := range iface.Addresses {
+				content += fmt.Sprintf("%s,%s,%s,%d,%s,%s,%t,%v,%v\n",
+					iface.Name,
+					address.Address,
+					address.Family,
+					address.PrefixLength,
+					address.SubnetMask,
+					address.Scope,
+					address.Permanent,
+					address.ValidLifetime,
+					address.PreferredLifetime)
+			}
+		}
 	}
 
 	// I/O data
 	if len(data.InterfaceIO) > 0 {
-		content += "\nI/O Data\n"
-		content += "Interface,Send Speed,Recv Speed,Total Speed,Utilization,Packets Sent,Packets Recv,Send Errors,Recv Errors\n"
+		content += exporter.CSVSection("I/O Data", "interface_io")
+		content += exporter.CSVHeader("Interface,Send Speed,Recv Speed,Total Speed,Utilization,Packets Sent,Packets Recv,Send Errors,Recv Errors", "interface_name,send_speed,recv_speed,total_speed,utilization,packets_sent,packets_recv,send_errors,recv_errors")
 		for _, io := range data.InterfaceIO {
 			content += fmt.Sprintf("%s,%.2f,%.2f,%.2f,%.2f,%d,%d,%d,%d\n",
 				io.InterfaceName,
@@ -201,10 +282,10 @@ func (exporter *NetworkMonitorExporter) generateCSVContent(data *NetworkMonitorD
 
 	// Connection data
 	if len(data.Connections) > 0 {
-		content += "\nConnection Data\n"
-		content += "Local Address,Remote Address,Type,Status,PID,Process Name,User,State,Family\n"
+		content += exporter.CSVSection("Connection Data", "connections")
+		content += exporter.CSVHeader("Local Address,Remote Address,Type,Status,PID,Process Name,User,State,Family,First Seen,Age (s),Age Lower Bound", "local_address,remote_address,type,status,pid,process_name,user,state,family,first_seen,age,age_lower_bound")
 		for _, conn := range data.Connections {
-			content += fmt.Sprintf("%s,%s,%s,%s,%d,%s,%s,%s,%s\n",
+			content += fmt.Sprintf("%s,%s,%s,%s,%d,%s,%s,%s,%s,%s,%.0f,%t\n",
 				conn.LocalAddress,
 				conn.RemoteAddress,
 				conn.Type,
@@ -213,17 +294,49 @@ func (exporter *NetworkMonitorExporter) generateCSVContent(data *NetworkMonitorD
 				conn.ProcessName,
 				conn.User,
 				conn.State,
-				conn.Family)
+				conn.Family,
+				conn.FirstSeen.Format(time.RFC3339),
+				conn.Age.Seconds(),
+				conn.AgeLowerBound)
+		}
+	}
+
+	// Connection churn
+	if len(data.ConnectionChurn) > 0 {
+		content += exporter.CSVSection("Connection Churn", "connection_churn")
+		content += exporter.CSVHeader("Process Name,Remote Address,Short-lived Connections", "process_name,remote_address,count")
+		for _, churn := range data.ConnectionChurn {
+			content += fmt.Sprintf("%s,%s,%d\n", churn.ProcessName, churn.RemoteAddress, churn.Count)
+		}
+	}
+
+	// Traffic by port class
+	if len(data.PortTraffic) > 0 {
+		content += exporter.CSVSection("Port Traffic", "port_traffic")
+		content += exporter.CSVHeader("Class,Ports,Connections,Inbound,Outbound,Send Speed,Recv Speed,Total Speed,Share,Measured", "class,ports,connections,inbound,outbound,send_speed,recv_speed,total_speed,share,measured")
+		for _, class := range data.PortTraffic {
+			content += fmt.Sprintf("%s,%s,%d,%d,%d,%.2f,%.2f,%.2f,%.2f,%t\n",
+				class.Class,
+				formatPorts(class.Ports, ";"),
+				class.Connections,
+				class.Inbound,
+				class.Outbound,
+				class.SendSpeed,
+				class.RecvSpeed,
+				class.TotalSpeed,
+				class.Share,
+				data.PortTrafficMeasured)
 		}
 	}
 
 	// Process data
 	if len(data.TopProcesses) > 0 {
-		content += "\nProcess Data\n"
-		content += "PID,Name,Send Speed,Recv Speed,Total Speed,Connections,Status,User\n"
+		content += exporter.CSVSection("Process Data", "top_processes")
+		content += exporter.CSVHeader("PID,Instance ID,Name,Send Speed,Recv Speed,Total Speed,Connections,Status,User", "pid,instance_id,name,send_speed,recv_speed,total_speed,connections,status,user")
 		for _, process := range data.TopProcesses {
-			content += fmt.Sprintf("%d,%s,%.2f,%.2f,%.2f,%d,%s,%s\n",
+			content += fmt.Sprintf("%d,%s,%s,%.2f,%.2f,%.2f,%d,%s,%s\n",
 				process.PID,
+				process.InstanceID,
 				process.Name,
 				process.SendSpeed,
 				process.RecvSpeed,
@@ -234,14 +347,137 @@ func (exporter *NetworkMonitorExporter) generateCSVContent(data *NetworkMonitorD
 		}
 	}
 
+	// Probable cause of a latency alert
+	if data.ProbableCause != nil {
+		content += exporter.CSVSection("Probable Cause", "probable_cause")
+		content += exporter.CSVHeader("PID,Instance ID,Name,Rate,Baseline Rate,Traffic Share,Source,Latency,Baseline Latency", "pid,instance_id,name,rate,baseline_rate,traffic_share,source,latency,baseline_latency")
+		content += fmt.Sprintf("%d,%s,%s,%.0f,%.0f,%.2f,%s,%.2f,%.2f\n",
+			data.ProbableCause.PID,
+			data.ProbableCause.InstanceID,
+			data.ProbableCause.Name,
+			data.ProbableCause.Rate,
+			data.ProbableCause.BaselineRate,
+			data.ProbableCause.TrafficShare,
+			data.ProbableCause.Source,
+			data.ProbableCause.Latency,
+			data.ProbableCause.BaselineLatency)
+	}
+
+	// Proxy and captive portal
+	if !data.CaptivePortal.CheckedAt.IsZero() {
+		content += exporter.CSVSection("Connectivity", "connectivity")
+		content += exporter.CSVHeader("Proxy Enabled,Proxy Source,HTTP Proxy,HTTPS Proxy,PAC URL,Captive Portal,Probe Status,Redirect URL,Proxy Probe,Explanation", "proxy.enabled,proxy.source,proxy.http_proxy,proxy.https_proxy,proxy.auto_config_url,captive_portal.detected,captive_portal.status_code,captive_portal.redirect_url,captive_portal.proxy_status,status_explanation")
+		content += fmt.Sprintf("%t,%s,%s,%s,%s,%t,%d,%s,%s,%s\n",
+			data.Proxy.Enabled,
+			data.Proxy.Source,
+			data.Proxy.HTTPProxy,
+			data.Proxy.HTTPSProxy,
+			data.Proxy.AutoConfigURL,
+			data.CaptivePortal.Detected,
+			data.CaptivePortal.StatusCode,
+			data.CaptivePortal.RedirectURL,
+			data.CaptivePortal.ProxyStatus,
+			data.StatusExplanation)
+	}
+
+	// Default gateway
+	if data.Gateway.Address != "" {
+		content += exporter.CSVSection("Default Gateway", "gateway")
+		content += exporter.CSVHeader("Address,Interface,MAC Address,Reachable,Method,RTT,Consecutive Failures,MAC Changes", "address,interface,mac_address,reachable,method,rtt,consecutive_failures,mac_changes")
+		content += fmt.Sprintf("%s,%s,%s,%t,%s,%.2f,%d,%d\n",
+			data.Gateway.Address,
+			data.Gateway.Interface,
+			data.Gateway.MACAddress,
+			data.Gateway.Reachable,
+			data.Gateway.Method,
+			data.Gateway.RTT,
+			data.Gateway.ConsecutiveFailures,
+			len(data.Gateway.MACChanges))
+		for _, change := range data.Gateway.MACChanges {
+			content += fmt.Sprintf("MAC Change,%s,%s,%s\n",
+				change.Timestamp.Format("2006-01-02 15:04:05"),
+				change.OldMAC,
+				change.NewMAC)
+		}
+	}
+
+	// Firewall counters
+	if data.Firewall.Available {
+		content += exporter.CSVSection("Firewall Rules", "firewall")
+		content += exporter.CSVHeader("Backend,Table,Chain,Action,Packets,Bytes,Dropped,Rule", "backend,table,chain,action,packets,bytes,dropped,rule")
+		for _, rule := range data.Firewall.Rules {
+			content += fmt.Sprintf("%s,%s,%s,%s,%d,%d,%t,\"%s\"\n",
+				data.Firewall.Backend,
+				rule.Table,
+				rule.Chain,
+				rule.Action,
+				rule.Packets,
+				rule.Bytes,
+				rule.Dropped,
+				strings.ReplaceAll(rule.Rule, "\"", "\"\""))
+		}
+	}
+
+	// VPN tunnels
+	if len(data.VPNTunnels) > 0 {
+		content += exporter.CSVSection("VPN Tunnels", "vpn_tunnels")
+		content += exporter.CSVHeader("Interface,Type,Status,Is Up,Endpoint,Peers,Last Handshake,Bytes Sent,Bytes Received", "interface,type,status,is_up,endpoint,peers,last_handshake,bytes_sent,bytes_recv")
+		for _, tunnel := range data.VPNTunnels {
+			lastHandshake := ""
+			if !tunnel.LastHandshake.IsZero() {
+				lastHandshake = tunnel.LastHandshake.Format("2006-01-02 15:04:05")
+			}
+			content += fmt.Sprintf("%s,%s,%s,%t,%s,%d,%s,%d,%d\n",
+				tunnel.Interface,
+				tunnel.Type,
+				tunnel.Status,
+				tunnel.IsUp,
+				tunnel.Endpoint,
+				tunnel.Peers,
+				lastHandshake,
+				tunnel.BytesSent,
+				tunnel.BytesRecv)
+		}
+	}
+
+	// Tunnel vs direct traffic
+	if data.VPNTraffic != nil {
+		content += exporter.CSVSection("VPN Traffic", "vpn_traffic")
+		content += exporter.CSVHeader("Tunnel Speed,Physical Speed,Direct Speed,Direct Share,Bypass Connections", "tunnel_speed,physical_speed,direct_speed,direct_share,bypass_connections")
+		content += fmt.Sprintf("%.2f,%.2f,%.2f,%.2f,%d\n",
+			data.VPNTraffic.TunnelSpeed,
+			data.VPNTraffic.PhysicalSpeed,
+			data.VPNTraffic.DirectSpeed,
+			data.VPNTraffic.DirectShare,
+			len(data.VPNTraffic.BypassConnections))
+
+		if len(data.VPNTraffic.BypassConnections) > 0 {
+			content += exporter.CSVSection("VPN Bypass Connections", "vpn_traffic.bypass_connections")
+			content += exporter.CSVHeader("Process,PID,Type,Local Address,Remote Address,Status", "process_name,pid,type,local_address,remote_address,status")
+			for _, connection := range data.VPNTraffic.BypassConnections {
+				content += fmt.Sprintf("%s,%d,%s,%s,%s,%s\n",
+					connection.ProcessName,
+					connection.PID,
+					connection.Type,
+					connection.LocalAddress,
+					connection.RemoteAddress,
+					connection.Status)
+			}
+		}
+	}
+
 	// Latency data
 	if len(data.LatencyInfo) > 0 {
-		content += "\nLatency Data\n"
-		content += "Target,Latency,Packet Loss,Status,Last Checked\n"
+		content += exporter.CSVSection("Latency Data", "latency_info")
+		content += exporter.CSVHeader("Target,Latency,P50,P95,P99,Samples,Packet Loss,Status,Last Checked", "target,latency,p50,p95,p99,samples,packet_loss,status,last_checked")
 		for _, latency := range data.LatencyInfo {
-			content += fmt.Sprintf("%s,%.2f,%.2f,%s,%s\n",
+			content += fmt.Sprintf("%s,%.2f,%.2f,%.2f,%.2f,%d,%.2f,%s,%s\n",
 				latency.Target,
 				latency.Latency,
+				latency.P50,
+				latency.P95,
+				latency.P99,
+				latency.Samples,
 				latency.PacketLoss,
 				latency.Status,
 				latency.LastChecked.Format("2006-01-02 15:04:05"))
@@ -255,6 +491,9 @@ func (exporter *NetworkMonitorExporter) generateCSVContent(data *NetworkMonitorD
 func (exporter *NetworkMonitorExporter) generateTXTContent(data *NetworkMonitorData) string {
 	var content string
 
+	// Metadata rows
+	content += exporter.Metadata(exportschema.Network, false)
+
 	// Header
 	content += "NETWORK MONITOR REPORT\n"
 	content += "=====================\n\n"
@@ -262,12 +501,22 @@ func (exporter *NetworkMonitorExporter) generateTXTContent(data *NetworkMonitorD
 	// Timestamp
 	content += fmt.Sprintf("Generated: %s\n\n", data.Timestamp.Format("2006-01-02 15:04:05"))
 
+	// Sections that could not be collected
+	if len(data.SectionErrors) > 0 {
+		content += "UNAVAILABLE SECTIONS\n"
+		content += "--------------------\n"
+		for _, section := range data.SectionErrors.Sections() {
+			content += fmt.Sprintf("%s: %s\n", section, data.SectionErrors[section])
+		}
+		content += "\n"
+	}
+
 	// Network summary
 	content += "NETWORK SUMMARY\n"
 	content += "---------------\n"
 	content += fmt.Sprintf("Total Sent: %s\n", exporter.formatBytes(data.TotalBytesSent))
 	content += fmt.Sprintf("Total Received: %s\n", exporter.formatBytes(data.TotalBytesRecv))
-	content += fmt.Sprintf("Total Throughput: %.2f Mbps\n", data.TotalThroughput)
+	content += fmt.Sprintf("Total Throughput: %s\n", formatSpeed(data.TotalThroughput, data.RateUnit))
 	content += fmt.Sprintf("Network Status: %s\n", data.NetworkStatus)
 	content += fmt.Sprintf("Average Latency: %.2f ms\n", data.AverageLatency)
 	content += fmt.Sprintf("Packet Loss Rate: %.2f%%\n", data.PacketLossRate)
@@ -288,6 +537,13 @@ func (exporter *NetworkMonitorExporter) generateTXTContent(data *NetworkMonitorD
 				iface.IPAddress,
 				iface.MACAddress,
 				iface.Speed)
+			for _, address := range iface.Addresses {
+				content += fmt.Sprintf("  %s/%d\t%s\t%s\n",
+					address.Address,
+					address.PrefixLength,
+					address.Family,
+					address.Scope)
+			}
 		}
 		content += "\n"
 	}
@@ -300,11 +556,11 @@ func (exporter *NetworkMonitorExporter) generateTXTContent(data *NetworkMonitorD
 		content += "--------\t\t----------\t----------\t-----------\t------------\n"
 
 		for _, io := range data.InterfaceIO {
-			content += fmt.Sprintf("%s\t\t%.2f Mbps\t%.2f Mbps\t%.2f Mbps\t%.2f%%\n",
+			content += fmt.Sprintf("%s\t\t%s\t%s\t%s\t%.2f%%\n",
 				io.InterfaceName,
-				io.SendSpeed,
-				io.RecvSpeed,
-				io.TotalSpeed,
+				formatSpeed(io.SendSpeed, data.RateUnit),
+				formatSpeed(io.RecvSpeed, data.RateUnit),
+				formatSpeed(io.TotalSpeed, data.RateUnit),
 				io.Utilization)
 		}
 		content += "\n"
@@ -314,16 +570,153 @@ func (exporter *NetworkMonitorExporter) generateTXTContent(data *NetworkMonitorD
 	if len(data.Connections) > 0 {
 		content += "CONNECTION INFORMATION\n"
 		content += "---------------------\n"
-		content += "Local Address\t\tRemote Address\t\tType\tStatus\tProcess\n"
-		content += "-------------\t\t--------------\t\t----\t------\t-------\n"
+		content += "Local Address\t\tRemote Address\t\tType\tStatus\tProcess\tAge\n"
+		content += "-------------\t\t--------------\t\t----\t------\t-------\t---\n"
 
 		for _, conn := range data.Connections {
-			content += fmt.Sprintf("%s\t\t%s\t\t%s\t%s\t%s\n",
+			age := conn.Age.Round(time.Second).String()
+			if conn.AgeLowerBound {
+				age = ">" + age
+			}
+			content += fmt.Sprintf("%s\t\t%s\t\t%s\t%s\t%s\t%s\n",
 				conn.LocalAddress,
 				conn.RemoteAddress,
 				conn.Type,
 				conn.Status,
-				conn.ProcessName)
+				conn.ProcessName,
+				age)
+		}
+		content += fmt.Sprintf("Short-lived connections closed recently: %d\n", data.ShortLivedConnections)
+		for _, churn := range data.ConnectionChurn {
+			content += fmt.Sprintf("Churn: %s -> %s (%d short-lived)\n", churn.ProcessName, churn.RemoteAddress, churn.Count)
+		}
+		content += "\n"
+	}
+
+	// Traffic by port class
+	if len(data.PortTraffic) > 0 {
+		content += "TRAFFIC BY PORT CLASS\n"
+		content += "---------------------\n"
+		if !data.PortTrafficMeasured {
+			content += "Throughput not measured (per-socket byte counters are only read on Linux); shares are of connections\n"
+		}
+		for _, class := range data.PortTraffic {
+			content += fmt.Sprintf("%-14s\t%.1f%%\tSent %s\tReceived %s\t%d connections (%d in, %d out)\tPorts %s\n",
+				class.Class,
+				class.Share,
+				formatSpeed(class.SendSpeed, data.RateUnit),
+				formatSpeed(class.RecvSpeed, data.RateUnit),
+				class.Connections,
+				class.Inbound,
+				class.Outbound,
+				formatPorts(class.Ports, ", "))
+		}
+		content += "\n"
+	}
+
+	// Proxy and captive portal
+	if !data.CaptivePortal.CheckedAt.IsZero() {
+		content += "PROXY & CAPTIVE PORTAL\n"
+		content += "----------------------\n"
+		if data.Proxy.Enabled {
+			content += fmt.Sprintf("Proxy: %s %s (%s)\n", data.Proxy.HTTPProxy, data.Proxy.AutoConfigURL, data.Proxy.Source)
+		} else {
+			content += "Proxy: None\n"
+		}
+		if data.CaptivePortal.Detected {
+			content += fmt.Sprintf("Captive Portal: Detected (HTTP %d) %s\n", data.CaptivePortal.StatusCode, data.CaptivePortal.RedirectURL)
+		} else if data.CaptivePortal.Reachable {
+			content += "Captive Portal: None\n"
+		} else {
+			content += fmt.Sprintf("Captive Portal: Probe failed (%s)\n", data.CaptivePortal.Error)
+		}
+		if data.Proxy.Enabled && data.CaptivePortal.ProxyStatus != "" {
+			content += fmt.Sprintf("Through Proxy: %s %s\n", data.CaptivePortal.ProxyStatus, data.CaptivePortal.ProxyError)
+		}
+		if data.StatusExplanation != "" {
+			content += fmt.Sprintf("Explanation: %s\n", data.StatusExplanation)
+		}
+		content += "\n"
+	}
+
+	// Default gateway
+	if data.Gateway.Address != "" {
+		content += "DEFAULT GATEWAY\n"
+		content += "---------------\n"
+		content += fmt.Sprintf("Gateway: %s (%s)\n", data.Gateway.Address, data.Gateway.Interface)
+		content += fmt.Sprintf("MAC Address: %s\n", data.Gateway.MACAddress)
+		if data.Gateway.Reachable && data.Gateway.Method == "ping" {
+			content += fmt.Sprintf("Reachability: Reachable via %s (%.2f ms)\n", data.Gateway.Method, data.Gateway.RTT)
+		} else if data.Gateway.Reachable {
+			content += fmt.Sprintf("Reachability: Reachable via %s\n", data.Gateway.Method)
+		} else {
+			content += fmt.Sprintf("Reachability: Unreachable via %s (%d failed checks)\n", data.Gateway.Method, data.Gateway.ConsecutiveFailures)
+		}
+		for _, change := range data.Gateway.MACChanges {
+			content += fmt.Sprintf("MAC Changed: %s %s -> %s\n",
+				change.Timestamp.Format("2006-01-02 15:04:05"),
+				change.OldMAC,
+				change.NewMAC)
+		}
+		content += "\n"
+	}
+
+	// Firewall counters
+	if !data.Firewall.CheckedAt.IsZero() {
+		content += "FIREWALL\n"
+		content += "--------\n"
+		if data.Firewall.Available {
+			content += fmt.Sprintf("Backend: %s\n", data.Firewall.Backend)
+			content += fmt.Sprintf("Dropped: %d packets (%s), %d since last check\n",
+				data.Firewall.DroppedPackets,
+				exporter.formatBytes(data.Firewall.DroppedBytes),
+				data.Firewall.RecentDrops)
+			for _, rule := range data.Firewall.Rules {
+				content += fmt.Sprintf("%s\t%s\t%d packets\t%s\t%s\n",
+					rule.Chain,
+					rule.Action,
+					rule.Packets,
+					exporter.formatBytes(rule.Bytes),
+					rule.Rule)
+			}
+		} else {
+			content += fmt.Sprintf("Counters unavailable: %s\n", data.Firewall.Error)
+		}
+		content += "\n"
+	}
+
+	// VPN tunnels
+	if len(data.VPNTunnels) > 0 {
+		content += "VPN / TUNNELS\n"
+		content += "-------------\n"
+		for _, tunnel := range data.VPNTunnels {
+			content += fmt.Sprintf("%s\t%s\t%s\tSent: %s\tReceived: %s",
+				tunnel.Interface,
+				tunnel.Type,
+				tunnel.Status,
+				exporter.formatBytes(tunnel.BytesSent),
+				exporter.formatBytes(tunnel.BytesRecv))
+			if !tunnel.LastHandshake.IsZero() {
+				content += fmt.Sprintf("\tHandshake: %s", tunnel.LastHandshake.Format("2006-01-02 15:04:05"))
+			}
+			if tunnel.Endpoint != "" {
+				content += fmt.Sprintf("\tEndpoint: %s", tunnel.Endpoint)
+			}
+			content += "\n"
+		}
+		if data.VPNTraffic != nil {
+			content += fmt.Sprintf("Via Tunnel: %s\tDirect: %s (%.1f%%)\n",
+				formatSpeed(data.VPNTraffic.TunnelSpeed, data.RateUnit),
+				formatSpeed(data.VPNTraffic.DirectSpeed, data.RateUnit),
+				data.VPNTraffic.DirectShare)
+			for _, connection := range data.VPNTraffic.BypassConnections {
+				content += fmt.Sprintf("Bypassing: %s (PID %d)\t%s %s -> %s\n",
+					connection.ProcessName,
+					connection.PID,
+					connection.Type,
+					connection.LocalAddress,
+					connection.RemoteAddress)
+			}
 		}
 		content += "\n"
 	}
@@ -332,13 +725,14 @@ func (exporter *NetworkMonitorExporter) generateTXTContent(data *NetworkMonitorD
 	if len(data.LatencyInfo) > 0 {
 		content += "LATENCY INFORMATION\n"
 		content += "------------------\n"
-		content += "Target\t\tLatency\t\tPacket Loss\tStatus\n"
-		content += "------\t\t-------\t\t-----------\t------\n"
+		content += "Target\t\tLatency\t\tp95\t\tPacket Loss\tStatus\n"
+		content += "------\t\t-------\t\t---\t\t-----------\t------\n"
 
 		for _, latency := range data.LatencyInfo {
-			content += fmt.Sprintf("%s\t\t%.2f ms\t\t%.2f%%\t\t%s\n",
+			content += fmt.Sprintf("%s\t\t%.2f ms\t\t%.2f ms\t\t%.2f%%\t\t%s\n",
 				latency.Target,
 				latency.Latency,
+				latency.P95,
 				latency.PacketLoss,
 				latency.Status)
 		}
@@ -348,12 +742,12 @@ func (exporter *NetworkMonitorExporter) generateTXTContent(data *NetworkMonitorD
 	// Bandwidth information
 	content += "BANDWIDTH INFORMATION\n"
 	content += "--------------------\n"
-	content += fmt.Sprintf("Total Bandwidth: %.2f Mbps\n", data.BandwidthInfo.TotalBandwidth)
-	content += fmt.Sprintf("Used Bandwidth: %.2f Mbps\n", data.BandwidthInfo.UsedBandwidth)
-	content += fmt.Sprintf("Available Bandwidth: %.2f Mbps\n", data.BandwidthInfo.AvailableBandwidth)
+	content += fmt.Sprintf("Total Bandwidth: %s\n", formatSpeed(data.BandwidthInfo.TotalBandwidth, data.RateUnit))
+	content += fmt.Sprintf("Used Bandwidth: %s\n", formatSpeed(data.BandwidthInfo.UsedBandwidth, data.RateUnit))
+	content += fmt.Sprintf("Available Bandwidth: %s\n", formatSpeed(data.BandwidthInfo.AvailableBandwidth, data.RateUnit))
 	content += fmt.Sprintf("Utilization: %.2f%%\n", data.BandwidthInfo.Utilization)
-	content += fmt.Sprintf("Peak Usage: %.2f Mbps\n", data.BandwidthInfo.PeakUsage)
-	content += fmt.Sprintf("Average Usage: %.2f Mbps\n\n", data.BandwidthInfo.AverageUsage)
+	content += fmt.Sprintf("Peak Usage: %s\n", formatSpeed(data.BandwidthInfo.PeakUsage, data.RateUnit))
+	content += fmt.Sprintf("Average Usage: %s\n\n", formatSpeed(data.BandwidthInfo.AverageUsage, data.RateUnit))
 
 	// Top processes
 	if len(data.TopProcesses) > 0 {
@@ -363,12 +757,12 @@ func (exporter *NetworkMonitorExporter) generateTXTContent(data *NetworkMonitorD
 		content += "---\t----\t\t\t----------\t----------\t-----------\t-----------\n"
 
 		for _, process := range data.TopProcesses {
-			content += fmt.Sprintf("%d\t%-20s\t%.2f Mbps\t%.2f Mbps\t%.2f Mbps\t%d\n",
+			content += fmt.Sprintf("%d\t%-20s\t%s\t%s\t%s\t%d\n",
 				process.PID,
 				process.Name,
-				process.SendSpeed,
-				process.RecvSpeed,
-				process.TotalSpeed,
+				formatSpeed(process.SendSpeed, data.RateUnit),
+				formatSpeed(process.RecvSpeed, data.RateUnit),
+				formatSpeed(process.TotalSpeed, data.RateUnit),
 				process.Connections)
 		}
 		content += "\n"
@@ -378,6 +772,14 @@ func (exporter *NetworkMonitorExporter) generateTXTContent(data *NetworkMonitorD
 	content += "ALERTS & WARNINGS\n"
 	content += "-----------------\n"
 	content += fmt.Sprintf("High Latency Warning: %t\n", data.HighLatencyWarning)
+	if data.ProbableCause != nil {
+		content += fmt.Sprintf("Probable Cause: %s (PID %d) at %s, %.0f%% of process %s\n",
+			data.ProbableCause.Name,
+			data.ProbableCause.PID,
+			formatByteRate(data.ProbableCause.Rate, data.RateUnit),
+			data.ProbableCause.TrafficShare,
+			causeMeasure(data.ProbableCause))
+	}
 	content += fmt.Sprintf("Packet Loss Warning: %t\n", data.PacketLossWarning)
 	content += fmt.Sprintf("Bandwidth Warning: %t\n", data.BandwidthWarning)
 	content += fmt.Sprintf("Connection Warning: %t\n", data.ConnectionWarning)
@@ -414,3 +816,13 @@ func (exporter *NetworkMonitorExporter) SetPrettyPrint(pretty bool) {
 func (exporter *NetworkMonitorExporter) SetCreateSubDirs(create bool) {
 	exporter.CreateSubDirs = create
 }
+
+// SetStableKeys sets whether CSV headers use stable snake_case keys instead of prose
+func (exporter *NetworkMonitorExporter) SetStableKeys(stable bool) {
+	exporter.StableKeys = stable
+}
+
+// SetHeaderMetadata sets whether exports start with metadata rows
+func (exporter *NetworkMonitorExporter) SetHeaderMetadata(metadata bool) {
+	exporter.HeaderMetadata = metadata
+}
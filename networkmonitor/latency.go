@@ -0,0 +1,58 @@
+package networkmonitor
+
+import (
+	"math"
+	"sort"
+)
+
+// trackLatency adds every successful probe to its target's window of recent samples and sets the
+// percentiles over that window; a failed probe adds no sample and targets no longer probed are forgotten
+func (collector *NetworkMonitorCollector) trackLatency(data *NetworkMonitorData) {
+	window := collector.config.LatencyWindow
+	if window < 1 {
+		window = 1
+	}
+
+	samples := make(map[string][]float64, len(data.LatencyInfo))
+	for i := range data.LatencyInfo {
+		latency := &data.LatencyInfo[i]
+		recent := collector.latencySamples[latency.Target]
+		if latency.Status != "Failed" {
+			recent = append(recent, latency.Latency)
+		}
+		if len(recent) > window {
+			recent = recent[len(recent)-window:]
+		}
+		samples[latency.Target] = recent
+
+		latency.Samples = len(recent)
+		latency.P50 = percentile(recent, 50)
+		latency.P95 = percentile(recent, 95)
+		latency.P99 = percentile(recent, 99)
+	}
+	collector.latencySamples = samples
+}
+
+// percentile returns the nearest-rank percentile p (0-100) of values, or 0 when there are none
+// With fewer than 20 samples the 95th percentile is the highest one, so a new target alerts on its spikes
+func percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// alertLatency returns the latency a target's alert is evaluated on: the 95th percentile of its recent
+// samples, so a single slow probe does not raise it, or the probe itself before there are any
+func alertLatency(latency NetworkLatencyInfo) float64 {
+	if latency.Samples == 0 {
+		return latency.Latency
+	}
+	return latency.P95
+}
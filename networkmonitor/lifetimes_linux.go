@@ -0,0 +1,78 @@
+//go:build linux
+
+package networkmonitor
+
+import (
+	"net"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// ifaCacheinfo is the IFA_CACHEINFO attribute type (not exported by the syscall package)
+const ifaCacheinfo = 6
+
+// infiniteLifetime marks an address that never expires
+const infiniteLifetime = 0xFFFFFFFF
+
+// ifaCacheinfoData mirrors struct ifa_cacheinfo (lifetimes in seconds, host byte order)
+type ifaCacheinfoData struct {
+	Preferred uint32
+	Valid     uint32
+	Created   uint32
+	Updated   uint32
+}
+
+// addressLifetimes reads address lifetimes over rtnetlink, keyed by interface index and IP
+func addressLifetimes() (map[string]addressLifetime, error) {
+	rib, err := syscall.NetlinkRIB(syscall.RTM_GETADDR, syscall.AF_UNSPEC)
+	if err != nil {
+		return nil, err
+	}
+	messages, err := syscall.ParseNetlinkMessage(rib)
+	if err != nil {
+		return nil, err
+	}
+
+	lifetimes := make(map[string]addressLifetime)
+	for _, message := range messages {
+		if message.Header.Type != syscall.RTM_NEWADDR || len(message.Data) < syscall.SizeofIfAddrmsg {
+			continue
+		}
+		ifAddr := (*syscall.IfAddrmsg)(unsafe.Pointer(&message.Data[0]))
+		attributes, err := syscall.ParseNetlinkRouteAttr(&message)
+		if err != nil {
+			continue
+		}
+
+		var address net.IP
+		var lifetime addressLifetime
+		found := false
+		for _, attribute := range attributes {
+			switch attribute.Attr.Type {
+			case syscall.IFA_ADDRESS:
+				// IFA_LOCAL wins on point-to-point links where IFA_ADDRESS is the peer
+				if address == nil {
+					address = net.IP(attribute.Value)
+				}
+			case syscall.IFA_LOCAL:
+				address = net.IP(attribute.Value)
+			case ifaCacheinfo:
+				if len(attribute.Value) < int(unsafe.Sizeof(ifaCacheinfoData{})) {
+					continue
+				}
+				cacheinfo := (*ifaCacheinfoData)(unsafe.Pointer(&attribute.Value[0]))
+				lifetime.permanent = cacheinfo.Valid == infiniteLifetime
+				if !lifetime.permanent {
+					lifetime.valid = time.Duration(cacheinfo.Valid) * time.Second
+					lifetime.preferred = time.Duration(cacheinfo.Preferred) * time.Second
+				}
+				found = true
+			}
+		}
+		if address != nil && found {
+			lifetimes[lifetimeKey(int(ifAddr.Index), address.String())] = lifetime
+		}
+	}
+	return lifetimes, nil
+}
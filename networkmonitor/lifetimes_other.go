@@ -0,0 +1,11 @@
+//go:build !linux
+
+package networkmonitor
+
+import "errors"
+
+// addressLifetimes reads address lifetimes, keyed by interface index and IP
+// Lifetimes come from rtnetlink, so they are only available on Linux
+func addressLifetimes() (map[string]addressLifetime, error) {
+	return nil, errors.New("address lifetimes are only available on Linux")
+}
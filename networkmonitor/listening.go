@@ -0,0 +1,86 @@
+package networkmonitor
+
+import (
+	"fmt"
+	"github.com/ahmadreza-log/simple-monitor/listening"
+	"sort"
+)
+
+// ListListeningPorts returns every TCP socket in the LISTEN state and every bound, unconnected UDP socket,
+// sorted by protocol, port and address
+func (collector *NetworkMonitorCollector) ListListeningPorts() ([]ListeningPort, error) {
+	if collector.simulator != nil {
+		return collector.simulatedListeningPorts(), nil
+	}
+
+	connections, err := collector.system.Net.Connections("inet")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get network connections: %w", err)
+	}
+
+	names := make(map[int32]string)
+	seen := make(map[ListeningPort]bool)
+	var ports []ListeningPort
+	for _, conn := range connections {
+		if !listening.IsListening(conn) {
+			continue
+		}
+		if _, known := names[conn.Pid]; !known {
+			names[conn.Pid] = "Unknown"
+			if conn.Pid > 0 {
+				if proc, err := collector.system.Proc.NewProcess(conn.Pid); err == nil {
+					if name, err := proc.Name(); err == nil {
+						names[conn.Pid] = name
+					}
+				}
+			}
+		}
+
+		// SO_REUSEPORT and per-CPU sockets list the same port several times
+		port := ListeningPort{
+			Protocol:    collector.getConnectionType(conn.Type),
+			Address:     conn.Laddr.IP,
+			Port:        conn.Laddr.Port,
+			PID:         conn.Pid,
+			ProcessName: names[conn.Pid],
+		}
+		if !seen[port] {
+			seen[port] = true
+			ports = append(ports, port)
+		}
+	}
+
+	sortListeningPorts(ports)
+	return ports, nil
+}
+
+// simulatedListeningPorts returns the listeners of the synthetic services
+func (collector *NetworkMonitorCollector) simulatedListeningPorts() []ListeningPort {
+	var ports []ListeningPort
+	for _, process := range collector.simulator.Processes() {
+		if process.ListenPort > 0 {
+			ports = append(ports, ListeningPort{
+				Protocol:    "TCP",
+				Address:     "0.0.0.0",
+				Port:        uint32(process.ListenPort),
+				PID:         process.PID,
+				ProcessName: process.Name,
+			})
+		}
+	}
+	sortListeningPorts(ports)
+	return ports
+}
+
+// sortListeningPorts orders ports by protocol, port and address
+func sortListeningPorts(ports []ListeningPort) {
+	sort.Slice(ports, func(i, j int) bool {
+		if ports[i].Protocol != ports[j].Protocol {
+			return ports[i].Protocol < ports[j].Protocol
+		}
+		if ports[i].Port != ports[j].Port {
+			return ports[i].Port < ports[j].Port
+		}
+		return ports[i].Address < ports[j].Address
+	})
+}
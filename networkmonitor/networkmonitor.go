@@ -1,9 +1,23 @@
+// Package networkmonitor monitors interfaces, traffic rates, connections, latency and listening ports,
+// and provides tools such as ping, traceroute and Wake-on-LAN
+// A NetworkMonitorManager collects once with Collect or in the background with Subscribe
 package networkmonitor
 
 import (
+	"encoding/json"
 	"fmt"
+	"github.com/ahmadreza-log/simple-monitor/alert"
+	"github.com/ahmadreza-log/simple-monitor/historystore"
+	"github.com/ahmadreza-log/simple-monitor/hooks"
+	"github.com/ahmadreza-log/simple-monitor/idle"
+	"github.com/ahmadreza-log/simple-monitor/recording"
+	"github.com/ahmadreza-log/simple-monitor/terminal"
+	"maps"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 )
@@ -21,18 +35,37 @@ type NetworkMonitorManager struct {
 	stopChannel   chan bool
 	refreshTicker *time.Ticker
 	lastExportTime time.Time
+
+	// Extra line shown under the live display (e.g. key shortcuts)
+	liveHint string
+
+	// Pause and step control (the display stays frozen while paused)
+	paused      atomic.Bool
+	stepChannel chan bool
+
+	// Session recorder (nil unless the live session is being recorded)
+	recorder *recording.Recorder
+
+	// Idle detection (slows refreshes while nobody is using the machine)
+	idleDetector *idle.Detector
 }
 
 // NewNetworkMonitorManager creates a new instance of NetworkMonitorManager
-// with default collector, displayer, and exporter configurations
-func NewNetworkMonitorManager() *NetworkMonitorManager {
-	return &NetworkMonitorManager{
-		collector:   NewNetworkMonitorCollector(),
-		displayer:   NewNetworkMonitorDisplayer(),
-		exporter:    NewNetworkMonitorExporter(),
-		isRunning:   false,
-		stopChannel: make(chan bool, 1),
+// with default collector, displayer, and exporter configurations, then applies the options in order
+func NewNetworkMonitorManager(options ...Option) *NetworkMonitorManager {
+	manager := &NetworkMonitorManager{
+		collector:    NewNetworkMonitorCollector(),
+		displayer:    NewNetworkMonitorDisplayer(),
+		exporter:     NewNetworkMonitorExporter(),
+		idleDetector: idle.NewDetector(),
+		isRunning:    false,
+		stopChannel:  make(chan bool, 1),
+		stepChannel:  make(chan bool, 1),
 	}
+	for _, option := range options {
+		option(manager)
+	}
+	return manager
 }
 
 // StartLiveMonitoring starts live network monitoring with real-time updates
@@ -43,7 +76,7 @@ func (manager *NetworkMonitorManager) StartLiveMonitoring() error {
 	}
 
 	manager.isRunning = true
-	manager.refreshTicker = time.NewTicker(manager.collector.config.RefreshInterval)
+	manager.refreshTicker = time.NewTicker(manager.collector.currentConfig().RefreshInterval)
 
 	// Set up signal handling for graceful shutdown
 	sigChan := make(chan os.Signal, 1)
@@ -53,12 +86,19 @@ func (manager *NetworkMonitorManager) StartLiveMonitoring() error {
 	fmt.Println("Press Ctrl+C to stop monitoring")
 
 	// Start monitoring loop
+	loopDone := make(chan bool)
+	loopExited := make(chan bool)
 	go func() {
+		defer close(loopExited)
 		for {
 			select {
 			case <-manager.refreshTicker.C:
+				if !manager.paused.Load() {
+					manager.updateAndDisplay()
+				}
+			case <-manager.stepChannel:
 				manager.updateAndDisplay()
-			case <-manager.stopChannel:
+			case <-loopDone:
 				return
 			case <-sigChan:
 				manager.StopMonitoring()
@@ -68,7 +108,12 @@ func (manager *NetworkMonitorManager) StartLiveMonitoring() error {
 	}()
 
 	// Wait for stop signal
+	// The loop is stopped separately so StopMonitoring can also be called from outside the loop,
+	// and a refresh still in progress finishes before returning
 	<-manager.stopChannel
+	close(loopDone)
+	<-loopExited
+	signal.Stop(sigChan)
 	return nil
 }
 
@@ -90,6 +135,7 @@ func (manager *NetworkMonitorManager) StartSingleSnapshot() error {
 	if err != nil {
 		fmt.Printf("⚠️  Warning: Failed to export data: %v\n", err)
 	} else {
+		hooks.Exported("network", filePath)
 		fmt.Printf("\n💾 Network data saved to: %s\n", filePath)
 	}
 
@@ -108,6 +154,9 @@ func (manager *NetworkMonitorManager) StopMonitoring() {
 		manager.refreshTicker.Stop()
 	}
 
+	// Keep everything collected in this session
+	manager.persistHistory(true)
+
 	select {
 	case manager.stopChannel <- true:
 	default:
@@ -118,6 +167,12 @@ func (manager *NetworkMonitorManager) StopMonitoring() {
 
 // updateAndDisplay collects new data and updates the display
 func (manager *NetworkMonitorManager) updateAndDisplay() {
+	// Refresh less often and pause process scans while the machine is idle
+	if !manager.idleDetector.ShouldCollect() {
+		return
+	}
+	manager.collector.SetIdle(manager.idleDetector.IsIdle())
+
 	// Collect new network data
 	data, err := manager.collector.CollectNetworkMonitorData()
 	if err != nil {
@@ -128,6 +183,26 @@ func (manager *NetworkMonitorManager) updateAndDisplay() {
 	
 	// Display updated data
 	manager.displayer.DisplayNetworkMonitorData(data)
+	if status := manager.idleDetector.Status(); status != "" {
+		fmt.Println("\n" + status)
+	}
+	if status := alert.Status(); status != "" {
+		fmt.Println("\n" + status)
+	}
+	if manager.liveHint != "" {
+		fmt.Println(manager.liveHint)
+	}
+	if manager.paused.Load() {
+		fmt.Println(terminal.PausedNotice)
+	}
+
+	// Capture the cycle for replay
+	if err := manager.recorder.Record("network", data); err != nil {
+		fmt.Printf("\n⚠️  %v\n", err)
+	}
+
+	// Save the long-term history
+	manager.persistHistory(false)
 	
 	// Export data based on export interval
 	manager.exportDataIfNeeded(data)
@@ -136,13 +211,13 @@ func (manager *NetworkMonitorManager) updateAndDisplay() {
 // exportDataIfNeeded exports network data to file based on export interval
 func (manager *NetworkMonitorManager) exportDataIfNeeded(data *NetworkMonitorData) {
 	// Check if export is enabled
-	if !manager.collector.config.ExportToFile {
+	if !manager.collector.currentConfig().ExportToFile {
 		return
 	}
 
 	// Check if it's time to export based on export interval
 	now := time.Now()
-	if manager.lastExportTime.IsZero() || now.Sub(manager.lastExportTime) >= manager.collector.config.ExportInterval {
+	if manager.lastExportTime.IsZero() || now.Sub(manager.lastExportTime) >= manager.collector.currentConfig().ExportInterval {
 		manager.exportData(data)
 		manager.lastExportTime = now
 	}
@@ -150,20 +225,55 @@ func (manager *NetworkMonitorManager) exportDataIfNeeded(data *NetworkMonitorDat
 
 // exportData exports network data to file
 func (manager *NetworkMonitorManager) exportData(data *NetworkMonitorData) {
-	filePath, err := manager.exporter.ExportToJSON(data, "networkmonitor")
+	export := manager.exporter.ExportToJSON
+	if manager.collector.currentConfig().ExportFormat == "protobuf" {
+		export = manager.exporter.ExportToProtobuf
+	}
+	filePath, err := export(data, "networkmonitor")
 	if err != nil {
 		// Don't display error for every export to avoid cluttering the display
 		return
 	}
 	
+	hooks.Exported("network", filePath)
 	fmt.Printf("\n💾 Data exported to: %s\n", filePath)
 }
 
-// GetNetworkUsageHistory returns the current network usage history
+// GetNetworkUsageHistory returns a copy of the network usage history
 func (manager *NetworkMonitorManager) GetNetworkUsageHistory() *NetworkUsageHistory {
 	return manager.collector.GetNetworkUsageHistory()
 }
 
+// GetLongTermHistory returns the downsampled long-term history
+func (manager *NetworkMonitorManager) GetLongTermHistory() *historystore.Store {
+	return manager.collector.GetLongTermHistory()
+}
+
+// HistoryMemoryEstimate estimates the memory an in-memory history of samples uses
+func (manager *NetworkMonitorManager) HistoryMemoryEstimate(samples int) uint64 {
+	return manager.collector.HistoryMemoryEstimate(samples)
+}
+
+// persistHistory writes the long-term history to logs/history
+// Unless forced (when monitoring stops), the file is rewritten at most once per save interval
+func (manager *NetworkMonitorManager) persistHistory(force bool) {
+	config := manager.collector.currentConfig()
+	// Synthetic history must not end up in the real history file
+	if !config.PersistHistory || manager.collector.IsSimulated() {
+		return
+	}
+
+	interval := config.HistorySaveInterval
+	if force {
+		interval = 0
+	}
+
+	path := filepath.Join(manager.exporter.LogsDirectory, "history", "networkmonitor.json")
+
+	// Errors are ignored to avoid cluttering the display; the next save retries
+	manager.collector.GetLongTermHistory().Persist(path, interval)
+}
+
 // GetConfig returns the current configuration
 func (manager *NetworkMonitorManager) GetConfig() *NetworkMonitorConfig {
 	return manager.collector.GetConfig()
@@ -189,6 +299,42 @@ func (manager *NetworkMonitorManager) SetExportOptions(logsDir string, prettyPri
 	manager.exporter.SetCreateSubDirs(createSubDirs)
 }
 
+// SetHeaderOptions configures CSV header keys and export metadata rows
+func (manager *NetworkMonitorManager) SetHeaderOptions(stableKeys, headerMetadata bool) {
+	manager.exporter.SetStableKeys(stableKeys)
+	manager.exporter.SetHeaderMetadata(headerMetadata)
+}
+
+// SetIdleMode enables or disables idle mode
+// While idle, refreshes are slowed down and process scans are paused until activity resumes
+func (manager *NetworkMonitorManager) SetIdleMode(enabled bool) {
+	manager.idleDetector.SetEnabled(enabled)
+	if !enabled {
+		manager.collector.SetIdle(false)
+	}
+}
+
+// SetSimulationMode switches the monitor to synthetic data (--simulate)
+// Simulated data needs no live system or elevated permissions, which suits demos and displayer development
+func (manager *NetworkMonitorManager) SetSimulationMode(enabled bool) {
+	manager.collector.SetSimulated(enabled)
+}
+
+// IsSimulated returns whether the monitor shows synthetic data
+func (manager *NetworkMonitorManager) IsSimulated() bool {
+	return manager.collector.IsSimulated()
+}
+
+// GetIdleState returns the most recent idle check
+func (manager *NetworkMonitorManager) GetIdleState() idle.State {
+	return manager.idleDetector.State()
+}
+
+// GetIdleConfig returns the idle detection configuration
+func (manager *NetworkMonitorManager) GetIdleConfig() idle.Config {
+	return manager.idleDetector.GetConfig()
+}
+
 // ExportToFile exports current network data to a file
 func (manager *NetworkMonitorManager) ExportToFile(format string) error {
 	// Collect current data
@@ -202,6 +348,8 @@ func (manager *NetworkMonitorManager) ExportToFile(format string) error {
 	switch format {
 	case "json":
 		filePath, err = manager.exporter.ExportToJSON(data, "networkmonitor")
+	case "protobuf":
+		filePath, err = manager.exporter.ExportToProtobuf(data, "networkmonitor")
 	case "csv":
 		filePath, err = manager.exporter.ExportToCSV(data, "networkmonitor")
 	case "txt":
@@ -214,6 +362,7 @@ func (manager *NetworkMonitorManager) ExportToFile(format string) error {
 		return fmt.Errorf("failed to export data: %w", err)
 	}
 
+	hooks.Exported("network", filePath)
 	fmt.Printf("💾 Network data exported to: %s\n", filePath)
 	return nil
 }
@@ -238,7 +387,150 @@ func (manager *NetworkMonitorManager) GetNetworkAlerts() (bool, bool, bool, bool
 	return data.HighLatencyWarning, data.PacketLossWarning, data.BandwidthWarning, data.ConnectionWarning, nil
 }
 
+// Collect collects the network data once, without displaying or exporting it
+func (manager *NetworkMonitorManager) Collect() (*NetworkMonitorData, error) {
+	return manager.collector.CollectNetworkMonitorData()
+}
+
+// Subscribe collects every interval in the background and sends the data on the returned channel until the
+// returned function is called, which closes it; a zero interval uses the configured refresh interval
+// Nothing is displayed, exported or recorded. A subscriber that falls behind only gets the newest data,
+// and failed collections are skipped (Collect returns their error)
+func (manager *NetworkMonitorManager) Subscribe(interval time.Duration) (<-chan *NetworkMonitorData, func()) {
+	if interval <= 0 {
+		interval = manager.collector.currentConfig().RefreshInterval
+	}
+	updates := make(chan *NetworkMonitorData, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(updates)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if data, err := manager.Collect(); err == nil {
+				select {
+				case <-updates:
+				default:
+				}
+				updates <- data
+			}
+			select {
+			case <-ticker.C:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var stop sync.Once
+	return updates, func() {
+		stop.Do(func() { close(done) })
+	}
+}
+
 // IsRunning returns whether the network monitor is currently running
 func (manager *NetworkMonitorManager) IsRunning() bool {
 	return manager.isRunning
 }
+
+// IsEnabled returns whether the monitor is switched on in its configuration
+func (manager *NetworkMonitorManager) IsEnabled() bool {
+	return manager.collector.currentConfig().Enabled
+}
+
+// SetLiveHint sets a line shown under each live refresh, or clears it when empty
+func (manager *NetworkMonitorManager) SetLiveHint(hint string) {
+	manager.liveHint = hint
+}
+
+// SetPaused freezes the live display on its last refresh, or resumes refreshing
+// Nothing is collected while paused, so history, exports and alerts wait too
+func (manager *NetworkMonitorManager) SetPaused(paused bool) {
+	manager.paused.Store(paused)
+}
+
+// Step collects and shows one refresh, for stepping through a paused display
+func (manager *NetworkMonitorManager) Step() {
+	select {
+	case manager.stepChannel <- true:
+	default:
+	}
+}
+
+// SetRecorder records every live collection cycle to recorder (nil stops recording)
+func (manager *NetworkMonitorManager) SetRecorder(recorder *recording.Recorder) {
+	manager.recorder = recorder
+}
+
+// DisplayRecordedData shows one recorded collection cycle the way the live display showed it
+func (manager *NetworkMonitorManager) DisplayRecordedData(content []byte) error {
+	var data NetworkMonitorData
+	if err := json.Unmarshal(content, &data); err != nil {
+		return fmt.Errorf("failed to decode recorded network data: %w", err)
+	}
+	manager.displayer.DisplayNetworkMonitorData(&data)
+	return nil
+}
+
+// PingHost pings a host and displays the replies
+func (manager *NetworkMonitorManager) PingHost(host string) error {
+	result, err := manager.collector.Ping(host)
+	if err != nil {
+		return fmt.Errorf("failed to ping %s: %w", host, err)
+	}
+	manager.displayer.DisplayPingResult(result)
+	return nil
+}
+
+// TraceHost runs a traceroute to a host and displays the hops next to the earlier runs to the same host
+// Each run is kept in logs/traceroutes.json, so a slow route can be compared with one taken when things were fine
+func (manager *NetworkMonitorManager) TraceHost(host string) error {
+	result, err := manager.collector.Traceroute(host)
+	if err != nil {
+		return fmt.Errorf("failed to trace the route to %s: %w", host, err)
+	}
+
+	earlier, err := storeTraceRun(filepath.Join(manager.exporter.LogsDirectory, traceRunsFile), result)
+	manager.displayer.DisplayTraceResult(result, earlier)
+	if err != nil {
+		return fmt.Errorf("failed to store the traceroute: %w", err)
+	}
+	return nil
+}
+
+// GetWakeHosts returns the machines that can be woken from the network menu, by name
+func (manager *NetworkMonitorManager) GetWakeHosts() map[string]WakeHost {
+	return maps.Clone(manager.collector.currentConfig().WakeHosts)
+}
+
+// SetWakeHost adds or replaces a machine that can be woken (the configuration file is not changed)
+func (manager *NetworkMonitorManager) SetWakeHost(name string, host WakeHost) {
+	manager.collector.changeConfig(func(config *NetworkMonitorConfig) {
+		if config.WakeHosts == nil {
+			config.WakeHosts = make(map[string]WakeHost)
+		}
+		config.WakeHosts[name] = host
+	})
+}
+
+// ListListeningPorts returns the TCP and UDP ports processes are listening on
+func (manager *NetworkMonitorManager) ListListeningPorts() ([]ListeningPort, error) {
+	return manager.collector.ListListeningPorts()
+}
+
+// ListTCPConnections returns the open TCP connections that can be closed
+func (manager *NetworkMonitorManager) ListTCPConnections() ([]NetworkConnectionInfo, error) {
+	if manager.collector.IsSimulated() {
+		return nil, fmt.Errorf("connections cannot be closed in simulation mode")
+	}
+	return manager.collector.ListTCPConnections()
+}
+
+// CloseConnection resets a TCP connection and returns how it was closed
+func (manager *NetworkMonitorManager) CloseConnection(conn NetworkConnectionInfo) (string, error) {
+	method, err := CloseConnection(conn)
+	if err != nil {
+		return "", fmt.Errorf("failed to close %s -> %s: %w", conn.LocalAddress, conn.RemoteAddress, err)
+	}
+	return method, nil
+}
@@ -0,0 +1,49 @@
+package networkmonitor
+
+import "time"
+
+// Option configures a NetworkMonitorManager as NewNetworkMonitorManager creates it
+// Options are applied before the manager is returned, so nothing can be collecting while they change its settings
+type Option func(*NetworkMonitorManager)
+
+// WithRefreshInterval sets how often live monitoring and Subscribe collect; an interval of zero or less is ignored
+func WithRefreshInterval(interval time.Duration) Option {
+	return func(manager *NetworkMonitorManager) {
+		if interval > 0 {
+			manager.collector.changeConfig(func(config *NetworkMonitorConfig) {
+				config.RefreshInterval = interval
+			})
+		}
+	}
+}
+
+// WithExporter replaces the default exporter, e.g. to write exports under another logs directory
+// A nil exporter keeps the default
+func WithExporter(exporter *NetworkMonitorExporter) Option {
+	return func(manager *NetworkMonitorManager) {
+		if exporter != nil {
+			manager.exporter = exporter
+		}
+	}
+}
+
+// WithThresholds sets the latency in milliseconds that raises the warning and critical alerts
+func WithThresholds(warning, critical float64) Option {
+	return func(manager *NetworkMonitorManager) {
+		manager.collector.changeConfig(func(config *NetworkMonitorConfig) {
+			config.LatencyWarning = warning
+			config.LatencyCritical = critical
+		})
+	}
+}
+
+// WithFilters limits interfaces to the one named iface and connections to one type (TCP, UDP or Unix)
+// An empty filter shows them all
+func WithFilters(iface, connectionType string) Option {
+	return func(manager *NetworkMonitorManager) {
+		manager.collector.changeConfig(func(config *NetworkMonitorConfig) {
+			config.InterfaceFilter = iface
+			config.ConnectionTypeFilter = connectionType
+		})
+	}
+}
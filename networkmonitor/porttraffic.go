@@ -0,0 +1,257 @@
+package networkmonitor
+
+import (
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// portClass groups the service ports whose traffic is reported together
+type portClass struct {
+	name  string
+	ports []int
+}
+
+// portClasses are the named classes; traffic on any other service port counts as otherPortClass
+var portClasses = []portClass{
+	{name: "Web", ports: []int{80, 443, 8000, 8080, 8443}},
+	{name: "SSH", ports: []int{22}},
+	{name: "DNS", ports: []int{53, 853}},
+	{name: "Database", ports: []int{1433, 1521, 3306, 5432, 6379, 9042, 11211, 27017}},
+	{name: "Mail", ports: []int{25, 110, 143, 465, 587, 993, 995}},
+	{name: "File Transfer", ports: []int{20, 21, 139, 445, 873, 2049}},
+}
+
+// otherPortClass collects every service port outside portClasses
+const otherPortClass = "Other"
+
+// maxClassPorts is how many of the busiest service ports are listed per class
+const maxClassPorts = 5
+
+// socketBytes is the byte counters of one TCP socket
+type socketBytes struct {
+	sent uint64
+	recv uint64
+}
+
+// socketRate is the throughput of one socket since the previous read, in bytes per second
+type socketRate struct {
+	send float64
+	recv float64
+}
+
+// collectSocketRates reads the per-socket TCP byte counters and sets socketRates to the throughput of each
+// socket since the previous read, or to nil when the counters cannot be read (they are only read on Linux)
+// The rates feed the port class breakdown and each process's traffic for the probable cause of latency alerts.
+// Bytes of sockets that close between two refreshes are not seen.
+func (collector *NetworkMonitorCollector) collectSocketRates() {
+	now := time.Now()
+	counters, err := readSocketBytes()
+	if err != nil {
+		collector.socketBytes, collector.socketRates = nil, nil
+		return
+	}
+
+	// The first read only sets the baseline
+	rates := make(map[string]socketRate)
+	if elapsed := now.Sub(collector.socketBytesTime).Seconds(); collector.socketBytes != nil && elapsed > 0 {
+		for key, current := range counters {
+			// Sockets opened since the previous read count from zero
+			previous := collector.socketBytes[key]
+			if current.sent < previous.sent || current.recv < previous.recv {
+				previous = socketBytes{}
+			}
+			rates[key] = socketRate{
+				send: float64(current.sent-previous.sent) / elapsed,
+				recv: float64(current.recv-previous.recv) / elapsed,
+			}
+		}
+	}
+	collector.socketBytes = counters
+	collector.socketBytesTime = now
+	collector.socketRates = rates
+}
+
+// collectPortTraffic breaks traffic down by the service port of each connection (web, SSH, DNS, databases...)
+// Throughput comes from the socket rates; without them, and for UDP, only connection counts are reported
+func (collector *NetworkMonitorCollector) collectPortTraffic(data *NetworkMonitorData) {
+	if collector.socketRates == nil {
+		data.PortTraffic = summarizePortTraffic(collector.openConnections, nil, false)
+		return
+	}
+	data.PortTraffic = summarizePortTraffic(collector.openConnections, collector.socketRates, true)
+	data.PortTrafficMeasured = true
+}
+
+// processSocketRates adds up the socket rates of each process's connections, in bytes per second
+func processSocketRates(connections []NetworkConnectionInfo, rates map[string]socketRate) map[int32]float64 {
+	processRates := make(map[int32]float64)
+	for _, connection := range connections {
+		rate := rates[socketKey(connection.LocalAddress, connection.RemoteAddress)]
+		processRates[connection.PID] += rate.send + rate.recv
+	}
+	return processRates
+}
+
+// summarizePortTraffic adds up connections and socket rates per port class
+// The service port is the local port for connections to a port this machine listens on, else the remote port.
+// Loopback connections never leave the machine and are left out. Shares are of the measured throughput,
+// or of the connection count when measured is false.
+func summarizePortTraffic(connections []NetworkConnectionInfo, rates map[string]socketRate, measured bool) []PortTrafficInfo {
+	listening := make(map[string]bool)
+	for _, connection := range connections {
+		if !hasRemoteEnd(connection.RemoteAddress) {
+			_, port := splitEndpoint(connection.LocalAddress)
+			listening[connection.Type+"|"+port] = true
+		}
+	}
+
+	classes := make(map[string]*PortTrafficInfo)
+	portSpeeds := make(map[string]map[int]float64)
+	portCounts := make(map[string]map[int]int)
+	for _, connection := range connections {
+		if !hasRemoteEnd(connection.RemoteAddress) {
+			continue // Listeners and unconnected UDP sockets carry no traffic of their own
+		}
+		localHost, localPort := splitEndpoint(connection.LocalAddress)
+		remoteHost, remotePort := splitEndpoint(connection.RemoteAddress)
+		if isLoopbackHost(localHost) && isLoopbackHost(remoteHost) {
+			continue
+		}
+
+		inbound := listening[connection.Type+"|"+localPort]
+		servicePort := remotePort
+		if inbound {
+			servicePort = localPort
+		}
+		port, _ := strconv.Atoi(servicePort)
+		name := classifyPort(port)
+
+		info := classes[name]
+		if info == nil {
+			info = &PortTrafficInfo{Class: name}
+			classes[name] = info
+			portSpeeds[name] = make(map[int]float64)
+			portCounts[name] = make(map[int]int)
+		}
+		info.Connections++
+		if inbound {
+			info.Inbound++
+		} else {
+			info.Outbound++
+		}
+
+		// Speeds are in Mbit/s until the rate unit is applied
+		rate := rates[socketKey(connection.LocalAddress, connection.RemoteAddress)]
+		sendSpeed := rate.send * 8 / 1e6
+		recvSpeed := rate.recv * 8 / 1e6
+		info.SendSpeed += sendSpeed
+		info.RecvSpeed += recvSpeed
+		info.TotalSpeed += sendSpeed + recvSpeed
+		portSpeeds[name][port] += sendSpeed + recvSpeed
+		portCounts[name][port]++
+	}
+
+	var totalSpeed float64
+	var totalConnections int
+	for _, info := range classes {
+		totalSpeed += info.TotalSpeed
+		totalConnections += info.Connections
+	}
+
+	var traffic []PortTrafficInfo
+	for name, info := range classes {
+		// The busiest service ports of the class, by throughput and then connections
+		var ports []int
+		for port := range portCounts[name] {
+			ports = append(ports, port)
+		}
+		sort.Slice(ports, func(i, j int) bool {
+			a, b := ports[i], ports[j]
+			if portSpeeds[name][a] != portSpeeds[name][b] {
+				return portSpeeds[name][a] > portSpeeds[name][b]
+			}
+			if portCounts[name][a] != portCounts[name][b] {
+				return portCounts[name][a] > portCounts[name][b]
+			}
+			return a < b
+		})
+		if len(ports) > maxClassPorts {
+			ports = ports[:maxClassPorts]
+		}
+		info.Ports = ports
+
+		if measured && totalSpeed > 0 {
+			info.Share = info.TotalSpeed / totalSpeed * 100
+		} else if !measured && totalConnections > 0 {
+			info.Share = float64(info.Connections) / float64(totalConnections) * 100
+		}
+		traffic = append(traffic, *info)
+	}
+
+	sort.Slice(traffic, func(i, j int) bool {
+		if traffic[i].TotalSpeed != traffic[j].TotalSpeed {
+			return traffic[i].TotalSpeed > traffic[j].TotalSpeed
+		}
+		if traffic[i].Connections != traffic[j].Connections {
+			return traffic[i].Connections > traffic[j].Connections
+		}
+		return traffic[i].Class < traffic[j].Class
+	})
+	return traffic
+}
+
+// formatPorts joins port numbers with a separator
+func formatPorts(ports []int, separator string) string {
+	text := make([]string, len(ports))
+	for i, port := range ports {
+		text[i] = strconv.Itoa(port)
+	}
+	return strings.Join(text, separator)
+}
+
+// classifyPort returns the class a service port belongs to
+func classifyPort(port int) string {
+	for _, class := range portClasses {
+		for _, classPort := range class.ports {
+			if port == classPort {
+				return class.name
+			}
+		}
+	}
+	return otherPortClass
+}
+
+// socketKey identifies a connection by its two endpoints, however the addresses were written
+// ss brackets IPv6 addresses and writes IPv4-mapped ones as ::ffff:a.b.c.d, gopsutil does neither
+func socketKey(local, remote string) string {
+	return normalizeEndpoint(local) + ">" + normalizeEndpoint(remote)
+}
+
+// normalizeEndpoint writes an address:port with the host in its canonical form and without a zone
+func normalizeEndpoint(address string) string {
+	host, port := splitEndpoint(address)
+	if index := strings.Index(host, "%"); index >= 0 {
+		host = host[:index]
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		host = ip.String()
+	}
+	return host + ":" + port
+}
+
+// hasRemoteEnd reports whether a connection's remote address:port is a real peer
+// Unlike isUnspecifiedEndpoint it accepts IPv6 hosts without brackets, as gopsutil writes them
+func hasRemoteEnd(address string) bool {
+	host, port := splitEndpoint(address)
+	ip := net.ParseIP(host)
+	return port != "" && port != "0" && ip != nil && !ip.IsUnspecified()
+}
+
+// isLoopbackHost reports whether a host is a loopback address
+func isLoopbackHost(host string) bool {
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
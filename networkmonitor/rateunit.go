@@ -0,0 +1,47 @@
+package networkmonitor
+
+import "fmt"
+
+// normalizeRateUnit returns unit when it is a known rate unit, or Mbit/s (the unit of data recorded before the setting existed)
+func normalizeRateUnit(unit string) string {
+	if unit == RateUnitBytes {
+		return RateUnitBytes
+	}
+	return RateUnitBits
+}
+
+// toRateUnit converts a speed in Mbit/s to unit
+func toRateUnit(megabits float64, unit string) float64 {
+	if normalizeRateUnit(unit) == RateUnitBytes {
+		return megabits / 8
+	}
+	return megabits
+}
+
+// fromRateUnit converts a speed in unit to Mbit/s
+func fromRateUnit(value float64, unit string) float64 {
+	if normalizeRateUnit(unit) == RateUnitBytes {
+		return value * 8
+	}
+	return value
+}
+
+// formatSpeed formats a speed that is already in unit, e.g. "12.50 Mbit/s"
+func formatSpeed(value float64, unit string) string {
+	return fmt.Sprintf("%.2f %s", value, normalizeRateUnit(unit))
+}
+
+// formatByteRate formats a rate in bytes per second in unit, with a decimal prefix that keeps it readable
+func formatByteRate(bytesPerSecond float64, unit string) string {
+	value, suffixes := bytesPerSecond, []string{"B/s", "kB/s", "MB/s", "GB/s", "TB/s"}
+	if normalizeRateUnit(unit) == RateUnitBits {
+		value, suffixes = bytesPerSecond*8, []string{"bit/s", "kbit/s", "Mbit/s", "Gbit/s", "Tbit/s"}
+	}
+
+	i := 0
+	for value >= 1000 && i < len(suffixes)-1 {
+		value /= 1000
+		i++
+	}
+	return fmt.Sprintf("%.1f %s", value, suffixes[i])
+}
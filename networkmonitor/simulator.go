@@ -0,0 +1,287 @@
+package networkmonitor
+
+import (
+	"fmt"
+	"github.com/ahmadreza-log/simple-monitor/procid"
+	"github.com/ahmadreza-log/simple-monitor/simulate"
+	"sort"
+	"strings"
+	"time"
+)
+
+// simulatedRemotePorts are the ports the synthetic clients connect to (443 for the rest)
+var simulatedRemotePorts = map[string]int{"rsync": 22, "java": 9092}
+
+// collectSimulatedData fills data with synthetic network metrics instead of querying the system
+// Traffic follows the synthetic processes; latency drifts with occasional spikes and packet loss
+func (collector *NetworkMonitorCollector) collectSimulatedData(data *NetworkMonitorData) {
+	source := collector.simulator
+	now := time.Now()
+
+	data.Simulated = true
+	data.Uptime = source.Uptime()
+	processes := source.Processes()
+
+	var sendRate, recvRate float64
+	for _, process := range processes {
+		sendRate += float64(process.SendRate)
+		recvRate += float64(process.RecvRate)
+	}
+
+	// Interfaces: wired uplink, idle Wi-Fi, loopback and a WireGuard tunnel
+	interfaces := []NetworkInterfaceInfo{
+		{Name: "eth0", DisplayName: "eth0", Type: "Ethernet", Status: "up", MTU: 1500, Speed: 1000, MACAddress: "02:42:ac:11:00:02", IPAddress: "192.168.1.42/24", SubnetMask: "255.255.255.0", Gateway: "192.168.1.1", DNSServers: []string{"192.168.1.1"}, IsUp: true},
+		{Name: "wlan0", DisplayName: "wlan0", Type: "WiFi", Status: "down", MTU: 1500, Speed: 866, MACAddress: "02:42:ac:11:00:03"},
+		{Name: "lo", DisplayName: "lo", Type: "Loopback", Status: "up", MTU: 65536, IPAddress: "127.0.0.1/8", SubnetMask: "255.0.0.0", IsUp: true, IsLoopback: true},
+		{Name: "wg0", DisplayName: "wg0", Type: "WireGuard", Status: "up", MTU: 1420, IPAddress: "10.8.0.2/24", SubnetMask: "255.255.255.0", IsUp: true, IsVirtual: true},
+	}
+	// DHCP and SLAAC leases count down over the simulated uptime
+	leaseLeft := 24*time.Hour - source.Uptime()%(24*time.Hour)
+	interfaces[0].Addresses = []NetworkAddressInfo{
+		{Address: "192.168.1.42", Family: "IPv4", PrefixLength: 24, SubnetMask: "255.255.255.0", Scope: "private", ValidLifetime: leaseLeft, PreferredLifetime: leaseLeft, LifetimeKnown: true},
+		{Address: "2001:db8:1::42", Family: "IPv6", PrefixLength: 64, Scope: "global", ValidLifetime: leaseLeft, PreferredLifetime: leaseLeft / 2, LifetimeKnown: true},
+		{Address: "fe80::42:acff:fe11:2", Family: "IPv6", PrefixLength: 64, Scope: "link-local", Permanent: true, LifetimeKnown: true},
+	}
+	interfaces[2].Addresses = []NetworkAddressInfo{
+		{Address: "127.0.0.1", Family: "IPv4", PrefixLength: 8, SubnetMask: "255.0.0.0", Scope: "host", Permanent: true, LifetimeKnown: true},
+		{Address: "::1", Family: "IPv6", PrefixLength: 128, Scope: "host", Permanent: true, LifetimeKnown: true},
+	}
+	interfaces[3].Addresses = []NetworkAddressInfo{
+		{Address: "10.8.0.2", Family: "IPv4", PrefixLength: 24, SubnetMask: "255.255.255.0", Scope: "private", Permanent: true, LifetimeKnown: true},
+	}
+	shares := map[string]float64{"eth0": 0.85, "wlan0": 0, "lo": 0.05, "wg0": 0.10}
+
+	for _, iface := range interfaces {
+		if collector.config.InterfaceFilter != "" && iface.Name != collector.config.InterfaceFilter {
+			continue
+		}
+		if collector.config.ShowInterfaces {
+			data.Interfaces = append(data.Interfaces, iface)
+		}
+		if !collector.config.ShowIO {
+			continue
+		}
+
+		share := shares[iface.Name]
+		sendSpeed := sendRate * share * 8 / 1e6
+		recvSpeed := recvRate * share * 8 / 1e6
+		interfaceIO := NetworkIOInfo{
+			InterfaceName: iface.Name,
+			BytesSent:     source.Counter(iface.Name+".sent", sendRate*share),
+			BytesRecv:     source.Counter(iface.Name+".recv", recvRate*share),
+			PacketsSent:   source.Counter(iface.Name+".packets_sent", sendRate*share/900),
+			PacketsRecv:   source.Counter(iface.Name+".packets_recv", recvRate*share/1200),
+			SendSpeed:     sendSpeed,
+			RecvSpeed:     recvSpeed,
+			TotalSpeed:    sendSpeed + recvSpeed,
+			DropIn:        uint64(source.Spike(0.05, 40)),
+		}
+		if iface.Speed > 0 {
+			interfaceIO.Utilization = interfaceIO.TotalSpeed / float64(iface.Speed) * 100
+		}
+		data.InterfaceIO = append(data.InterfaceIO, interfaceIO)
+
+		data.TotalBytesSent += interfaceIO.BytesSent
+		data.TotalBytesRecv += interfaceIO.BytesRecv
+		data.TotalPacketsSent += interfaceIO.PacketsSent
+		data.TotalPacketsRecv += interfaceIO.PacketsRecv
+		data.TotalSendSpeed += sendSpeed
+		data.TotalRecvSpeed += recvSpeed
+	}
+	data.TotalThroughput = data.TotalSendSpeed + data.TotalRecvSpeed
+
+	// Connections: listeners plus established connections for every process with traffic
+	if collector.config.ShowConnections {
+		for _, process := range processes {
+			if port := process.ListenPort; port > 0 {
+				data.Connections = append(data.Connections, NetworkConnectionInfo{
+					LocalAddress:  fmt.Sprintf("0.0.0.0:%d", port),
+					RemoteAddress: "0.0.0.0:0",
+					Status:        "LISTEN",
+					State:         "LISTEN",
+					Type:          "TCP",
+					Family:        "IPv4",
+					PID:           process.PID,
+					ProcessName:   process.Name,
+					User:          process.User,
+				})
+			}
+			if process.SendRate+process.RecvRate == 0 {
+				continue
+			}
+			// node polls an API with a fresh connection now and then, so churn has something to show
+			if process.Name == "node" && source.Spike(0.5, 1) > 0 {
+				data.Connections = append(data.Connections, NetworkConnectionInfo{
+					LocalAddress:  fmt.Sprintf("192.168.1.42:%d", 50000+int(source.Between(0, 10000))),
+					RemoteAddress: "198.51.100.7:443",
+					Status:        "ESTABLISHED",
+					State:         "ESTABLISHED",
+					Type:          "TCP",
+					Family:        "IPv4",
+					PID:           process.PID,
+					ProcessName:   process.Name,
+					User:          process.User,
+				})
+			}
+			for i := 0; i < process.Connections; i++ {
+				localPort := 40000 + int(process.PID)%1000*10 + i
+				remotePort := 443
+				if port, ok := simulatedRemotePorts[process.Name]; ok {
+					remotePort = port
+				}
+				// Services are connected to by clients on ephemeral ports
+				if port := process.ListenPort; port > 0 {
+					localPort, remotePort = port, 50000+int(process.PID)%1000*10+i
+				}
+				data.Connections = append(data.Connections, NetworkConnectionInfo{
+					LocalAddress:  fmt.Sprintf("192.168.1.42:%d", localPort),
+					RemoteAddress: fmt.Sprintf("203.0.113.%d:%d", 10+(int(process.PID)+i*7)%200, remotePort),
+					Status:        "ESTABLISHED",
+					State:         "ESTABLISHED",
+					Type:          "TCP",
+					Family:        "IPv4",
+					PID:           process.PID,
+					ProcessName:   process.Name,
+					User:          process.User,
+				})
+			}
+		}
+		if collector.config.ConnectionTypeFilter != "" {
+			var filtered []NetworkConnectionInfo
+			for _, connection := range data.Connections {
+				if strings.EqualFold(connection.Type, collector.config.ConnectionTypeFilter) {
+					filtered = append(filtered, connection)
+				}
+			}
+			data.Connections = filtered
+		}
+		for i := range data.Connections {
+			collector.trackConnection(&data.Connections[i], now)
+		}
+		collector.finishConnectionTracking(data, now)
+		if collector.config.ShowPortTraffic && !collector.idle {
+			data.PortTraffic = summarizePortTraffic(data.Connections, simulatedSocketRates(processes, data.Connections), true)
+			data.PortTrafficMeasured = true
+		}
+		if len(data.Connections) > collector.config.MaxConnections {
+			data.Connections = data.Connections[:collector.config.MaxConnections]
+		}
+	}
+
+	// Top processes by throughput
+	if collector.config.ShowProcesses && !collector.idle {
+		for _, process := range processes {
+			rate := float64(process.SendRate + process.RecvRate)
+			sendSpeed := float64(process.SendRate) * 8 / 1e6
+			recvSpeed := float64(process.RecvRate) * 8 / 1e6
+			if rate == 0 || sendSpeed+recvSpeed < fromRateUnit(collector.config.MinNetworkUsage, collector.config.RateUnit) {
+				continue
+			}
+			if collector.config.ProcessNameFilter != "" &&
+				!strings.Contains(strings.ToLower(process.Name), strings.ToLower(collector.config.ProcessNameFilter)) {
+				continue
+			}
+			data.TopProcesses = append(data.TopProcesses, NetworkProcessInfo{
+				PID:         process.PID,
+				InstanceID:  procid.InstanceID(process.PID, process.CreateTime),
+				Name:        process.Name,
+				BytesSent:   source.Counter(process.Name+".sent", float64(process.SendRate)),
+				BytesRecv:   source.Counter(process.Name+".recv", float64(process.RecvRate)),
+				SendSpeed:   sendSpeed,
+				RecvSpeed:   recvSpeed,
+				TotalSpeed:  sendSpeed + recvSpeed,
+				Connections: process.Connections,
+				Status:      process.Status,
+				User:        process.User,
+				Rate:        rate,
+			})
+		}
+		sort.Slice(data.TopProcesses, func(i, j int) bool {
+			return data.TopProcesses[i].TotalSpeed > data.TopProcesses[j].TotalSpeed
+		})
+		if len(data.TopProcesses) > collector.config.MaxProcesses {
+			data.TopProcesses = data.TopProcesses[:collector.config.MaxProcesses]
+		}
+	}
+
+	// Latency drifts per target with occasional spikes and packet loss
+	if collector.config.ShowLatency {
+		for i, target := range collector.config.LatencyTargets {
+			latency := 12 + float64(i)*9 + source.Wave(75*time.Second, float64(i)/4, 0, 25) + source.Noise(3) + source.Spike(0.05, 180)
+			packetLoss := source.Spike(0.03, 10)
+
+			status := "Good"
+			if latency >= collector.config.LatencyCritical {
+				status = "Critical"
+			} else if latency >= collector.config.LatencyWarning {
+				status = "Warning"
+			}
+			data.LatencyInfo = append(data.LatencyInfo, NetworkLatencyInfo{
+				Target:      target,
+				Latency:     latency,
+				PacketLoss:  packetLoss,
+				Jitter:      source.Between(0.5, 4),
+				Status:      status,
+				LastChecked: now,
+			})
+		}
+	}
+
+	if collector.config.ShowVPN {
+		handshake := now.Add(-time.Duration(source.Between(5, 110)) * time.Second)
+		data.VPNTunnels = append(data.VPNTunnels, VPNTunnelInfo{
+			Interface:     "wg0",
+			Type:          "WireGuard",
+			IsUp:          true,
+			Endpoint:      "198.51.100.7:51820",
+			Peers:         1,
+			LastHandshake: handshake,
+			HandshakeAge:  now.Sub(handshake),
+			BytesSent:     source.Counter("wg0.sent", sendRate*shares["wg0"]),
+			BytesRecv:     source.Counter("wg0.recv", recvRate*shares["wg0"]),
+			Status:        "Up",
+		})
+	}
+
+	if collector.config.ShowGateway {
+		data.Gateway = GatewayInfo{
+			Address:       "192.168.1.1",
+			Interface:     "eth0",
+			MACAddress:    "02:42:c0:a8:01:01",
+			Reachable:     true,
+			Method:        "ping",
+			RTT:           source.Between(0.4, 2.5),
+			LastReachable: now,
+			CheckedAt:     now,
+		}
+	}
+}
+
+// simulatedSocketRates splits each synthetic process's traffic evenly over its established connections
+func simulatedSocketRates(processes []simulate.Process, connections []NetworkConnectionInfo) map[string]socketRate {
+	established := make(map[int32]int)
+	for _, connection := range connections {
+		if connection.Status == "ESTABLISHED" {
+			established[connection.PID]++
+		}
+	}
+
+	byPID := make(map[int32]simulate.Process, len(processes))
+	for _, process := range processes {
+		byPID[process.PID] = process
+	}
+
+	rates := make(map[string]socketRate)
+	for _, connection := range connections {
+		count := established[connection.PID]
+		if connection.Status != "ESTABLISHED" || count == 0 {
+			continue
+		}
+		process := byPID[connection.PID]
+		rates[socketKey(connection.LocalAddress, connection.RemoteAddress)] = socketRate{
+			send: float64(process.SendRate) / float64(count),
+			recv: float64(process.RecvRate) / float64(count),
+		}
+	}
+	return rates
+}
@@ -0,0 +1,68 @@
+//go:build linux
+
+package networkmonitor
+
+import (
+	"bufio"
+	"bytes"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// readSocketBytes reads the byte counters of every TCP socket, keyed by socketKey
+// ss prints each socket on one line and its TCP info (bytes_sent, bytes_received...) on an indented line below
+func readSocketBytes() (map[string]socketBytes, error) {
+	output, err := exec.Command("ss", "-tinH").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	counters := make(map[string]socketBytes)
+	key := ""
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+
+		// Socket line: State Recv-Q Send-Q Local:Port Peer:Port
+		if line[0] != ' ' && line[0] != '\t' {
+			fields := strings.Fields(line)
+			key = ""
+			if len(fields) >= 5 {
+				key = socketKey(fields[3], fields[4])
+			}
+			continue
+		}
+		if key == "" {
+			continue
+		}
+
+		// Sent bytes include retransmissions, as the interface counters do; bytes_acked is the fallback
+		var counter socketBytes
+		var acked uint64
+		for _, field := range strings.Fields(line) {
+			name, value, found := strings.Cut(field, ":")
+			if !found {
+				continue
+			}
+			switch name {
+			case "bytes_sent":
+				counter.sent, _ = strconv.ParseUint(value, 10, 64)
+			case "bytes_acked":
+				acked, _ = strconv.ParseUint(value, 10, 64)
+			case "bytes_received":
+				counter.recv, _ = strconv.ParseUint(value, 10, 64)
+			}
+		}
+		if counter.sent == 0 {
+			counter.sent = acked
+		}
+		counters[key] = counter
+		key = ""
+	}
+	return counters, scanner.Err()
+}
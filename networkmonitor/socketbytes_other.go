@@ -0,0 +1,11 @@
+//go:build !linux
+
+package networkmonitor
+
+import "errors"
+
+// readSocketBytes reads the byte counters of every TCP socket, keyed by socketKey
+// Counters come from the kernel's TCP info via ss, so they are only available on Linux
+func readSocketBytes() (map[string]socketBytes, error) {
+	return nil, errors.New("per-socket byte counters are only available on Linux")
+}
@@ -0,0 +1,163 @@
+package networkmonitor
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ahmadreza-log/simple-monitor/privhelper"
+	"net"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// wakeOnLANPort is the UDP port magic packets go to when the broadcast address names none (the discard port)
+const wakeOnLANPort = "9"
+
+// defaultWakeBroadcast reaches every machine on the local network segment
+const defaultWakeBroadcast = "255.255.255.255"
+
+// Ways an on-demand ping measures replies, best first
+const (
+	pingMethodHelper = "icmp (helper)"
+	pingMethodPing   = "ping"
+	pingMethodTCP    = "tcp/80"
+)
+
+// pingInterval is the time between two probes of an on-demand ping, as with the ping command
+const pingInterval = 1 * time.Second
+
+// SendWakeOnLAN sends a Wake-on-LAN magic packet for a machine and returns the address it was sent to
+// The packet is a UDP broadcast, so it only reaches machines on the same network segment unless
+// Broadcast names a directed broadcast address (e.g. 192.168.2.255) that the router forwards
+func SendWakeOnLAN(host WakeHost) (string, error) {
+	mac, err := net.ParseMAC(host.MAC)
+	if err != nil {
+		return "", fmt.Errorf("invalid MAC address %q", host.MAC)
+	}
+	if len(mac) != 6 {
+		return "", fmt.Errorf("%s is not an Ethernet MAC address", host.MAC)
+	}
+
+	address := wakeAddress(host.Broadcast)
+	conn, err := net.Dial("udp", address)
+	if err != nil {
+		return "", fmt.Errorf("failed to open a socket to %s: %w", address, err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write(magicPacket(mac)); err != nil {
+		return "", fmt.Errorf("failed to send the magic packet to %s: %w", address, err)
+	}
+	return address, nil
+}
+
+// magicPacket builds a Wake-on-LAN magic packet: six 0xFF bytes followed by the MAC address 16 times
+func magicPacket(mac net.HardwareAddr) []byte {
+	packet := make([]byte, 0, 6+16*len(mac))
+	for i := 0; i < 6; i++ {
+		packet = append(packet, 0xFF)
+	}
+	for i := 0; i < 16; i++ {
+		packet = append(packet, mac...)
+	}
+	return packet
+}
+
+// wakeAddress returns the host:port a magic packet is sent to
+func wakeAddress(broadcast string) string {
+	if broadcast == "" {
+		broadcast = defaultWakeBroadcast
+	}
+	if _, _, err := net.SplitHostPort(broadcast); err != nil {
+		return net.JoinHostPort(broadcast, wakeOnLANPort)
+	}
+	return broadcast
+}
+
+// Ping probes a host PingCount times, one second apart
+// Replies are measured with an ICMP echo through the privileged helper when it is connected, else with the
+// ping command, else by timing a TCP connect to port 80 like the latency monitor
+func (collector *NetworkMonitorCollector) Ping(host string) (*PingResult, error) {
+	address, err := resolveHost(host)
+	if err != nil {
+		return nil, err
+	}
+
+	method := pingMethodTCP
+	if privhelper.Active() != nil {
+		method = pingMethodHelper
+	} else if _, err := exec.LookPath("ping"); err == nil {
+		method = pingMethodPing
+	}
+
+	count := collector.currentConfig().PingCount
+	if count <= 0 {
+		count = 1
+	}
+	result := &PingResult{Host: host, Address: address, Method: method}
+	var total float64
+	for i := 0; i < count; i++ {
+		start := time.Now()
+		rtt, ok := collector.probeHost(method, address)
+		result.Sent++
+		if ok {
+			if result.Received == 0 || rtt < result.MinRTT {
+				result.MinRTT = rtt
+			}
+			if rtt > result.MaxRTT {
+				result.MaxRTT = rtt
+			}
+			total += rtt
+			result.Received++
+			result.RTTs = append(result.RTTs, rtt)
+		} else {
+			result.RTTs = append(result.RTTs, -1)
+		}
+
+		if i < count-1 {
+			time.Sleep(pingInterval - time.Since(start))
+		}
+	}
+
+	if result.Received > 0 {
+		result.AvgRTT = total / float64(result.Received)
+	}
+	result.Loss = float64(result.Sent-result.Received) / float64(result.Sent) * 100
+	result.Timestamp = time.Now()
+	return result, nil
+}
+
+// probeHost sends one probe and returns the round-trip time in milliseconds
+// Pings run outside collections, so it reads the latest configuration rather than the collection's
+func (collector *NetworkMonitorCollector) probeHost(method, address string) (float64, bool) {
+	timeout := collector.currentConfig().ConnectionTimeout
+	switch method {
+	case pingMethodHelper:
+		rtt, reachable, err := privhelper.Active().Ping(address, timeout)
+		return rtt, err == nil && reachable
+	case pingMethodPing:
+		rtt, reachable, _ := pingHost(address)
+		return rtt, reachable
+	default:
+		start := time.Now()
+		conn, err := net.DialTimeout("tcp", net.JoinHostPort(address, "80"), timeout)
+		if err != nil {
+			return 0, false
+		}
+		conn.Close()
+		return float64(time.Since(start).Nanoseconds()) / 1e6, true
+	}
+}
+
+// resolveHost returns the IP address of a host name or address
+func resolveHost(host string) (string, error) {
+	host = strings.TrimSpace(host)
+	if host == "" {
+		return "", errors.New("no host given")
+	}
+	address, err := net.ResolveIPAddr("ip", host)
+	if err != nil {
+		return "", fmt.Errorf("cannot resolve %s: %w", host, err)
+	}
+	return address.String(), nil
+}
@@ -0,0 +1,253 @@
+package networkmonitor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// traceRunsFile is the file under the logs directory that keeps earlier traceroutes for comparison
+const traceRunsFile = "traceroutes.json"
+
+// maxTraceRuns is how many traceroutes are kept per host
+const maxTraceRuns = 10
+
+// Traceroute lists the routers on the way to a host with the round-trip time of each probe
+// It runs traceroute (tracert on Windows), which sends three probes per hop, then sorts the hops
+// into the local network, the internet provider and beyond to tell where latency and loss come from
+func (collector *NetworkMonitorCollector) Traceroute(host string) (*TraceResult, error) {
+	address, err := resolveHost(host)
+	if err != nil {
+		return nil, err
+	}
+
+	hops := strconv.Itoa(collector.currentConfig().TraceHops)
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("tracert", "-d", "-h", hops, "-w", "2000", address)
+	} else {
+		cmd = exec.Command("traceroute", "-n", "-m", hops, "-w", "2", address)
+	}
+
+	output, err := cmd.Output()
+	if err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return nil, fmt.Errorf("%s is not installed (install the traceroute package)", cmd.Args[0])
+		}
+		// traceroute exits non-zero on some errors but still prints the hops it got
+		if _, ok := err.(*exec.ExitError); !ok || len(output) == 0 {
+			return nil, fmt.Errorf("%s failed: %w", cmd.Args[0], err)
+		}
+	}
+
+	result := &TraceResult{Host: host, Address: address, Hops: parseTraceroute(string(output)), Timestamp: time.Now()}
+	if len(result.Hops) > 0 {
+		result.Reached = result.Hops[len(result.Hops)-1].Address == address
+	}
+	collector.analyzeTrace(result)
+	return result, nil
+}
+
+// traceHopPattern matches a hop line of traceroute and tracert output: the hop number, then probes and addresses
+var traceHopPattern = regexp.MustCompile(`^\s*(\d+)\s+(.*)$`)
+
+// parseTraceroute reads the hops from traceroute -n or tracert -d output
+// A hop line holds the first address that answered and a time ("1.23 ms", "<1 ms") or "*" for each probe
+func parseTraceroute(output string) []TraceHop {
+	var hops []TraceHop
+	for _, line := range strings.Split(output, "\n") {
+		match := traceHopPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue // Header and footer lines
+		}
+		number, _ := strconv.Atoi(match[1])
+		hop := TraceHop{Hop: number}
+
+		fields := strings.Fields(match[2])
+		for i, field := range fields {
+			switch {
+			case field == "*":
+				hop.RTTs = append(hop.RTTs, -1)
+			case i+1 < len(fields) && fields[i+1] == "ms":
+				if rtt, err := strconv.ParseFloat(strings.TrimPrefix(field, "<"), 64); err == nil {
+					hop.RTTs = append(hop.RTTs, rtt)
+				}
+			case hop.Address == "" && net.ParseIP(strings.Trim(field, "()[]")) != nil:
+				hop.Address = strings.Trim(field, "()[]")
+			}
+		}
+		hops = append(hops, hop)
+	}
+	return hops
+}
+
+// analyzeTrace fills in each hop's average and loss, groups the hops into segments and writes the verdict
+// Traces run outside collections, so it reads the latest configuration rather than the collection's
+func (collector *NetworkMonitorCollector) analyzeTrace(result *TraceResult) {
+	ispLimit := collector.currentConfig().TraceISPHops
+	segment := SegmentLocal
+	ispHops := 0
+	for i := range result.Hops {
+		hop := &result.Hops[i]
+		answered := 0
+		var total float64
+		for _, rtt := range hop.RTTs {
+			if rtt >= 0 {
+				total += rtt
+				answered++
+			}
+		}
+		if answered > 0 {
+			hop.AvgRTT = total / float64(answered)
+		}
+		if len(hop.RTTs) > 0 {
+			hop.Loss = float64(len(hop.RTTs)-answered) / float64(len(hop.RTTs)) * 100
+		}
+
+		// The local network ends at the first public (or carrier-grade NAT) address; the provider's
+		// routers are the next few hops, and anything after them counts as beyond
+		if segment == SegmentLocal && hop.Address != "" && !isLocalAddress(net.ParseIP(hop.Address)) {
+			segment = SegmentISP
+		}
+		if segment == SegmentISP {
+			if ispHops == ispLimit {
+				segment = SegmentBeyond
+			}
+			ispHops++
+		}
+		hop.Segment = segment
+
+		if n := len(result.Segments); n == 0 || result.Segments[n-1].Name != segment {
+			result.Segments = append(result.Segments, TraceSegment{Name: segment, FirstHop: hop.Hop})
+		}
+		result.Segments[len(result.Segments)-1].LastHop = hop.Hop
+	}
+
+	// Latency added by a segment is measured at its last answering hop, since routers in the middle often
+	// answer traceroute probes slowly without delaying the traffic they forward
+	var before float64
+	for i := range result.Segments {
+		last := lastAnsweredHop(result.Hops, result.Segments[i].FirstHop, result.Segments[i].LastHop)
+		if last == nil {
+			continue
+		}
+		if added := last.AvgRTT - before; added > 0 {
+			result.Segments[i].AddedLatency = added
+		}
+		before = last.AvgRTT
+	}
+	result.Verdict = traceVerdict(result)
+}
+
+// traceVerdict describes where the latency and loss on a route come from
+func traceVerdict(result *TraceResult) string {
+	last := lastAnsweredHop(result.Hops, 0, len(result.Hops)+1)
+	if last == nil {
+		return "no router on the route answered"
+	}
+
+	var parts []string
+	var slowest *TraceSegment
+	for i := range result.Segments {
+		if slowest == nil || result.Segments[i].AddedLatency > slowest.AddedLatency {
+			slowest = &result.Segments[i]
+		}
+	}
+	if slowest != nil && slowest.AddedLatency > 0 {
+		parts = append(parts, fmt.Sprintf("most latency (%.1f of %.1f ms) is added %s", slowest.AddedLatency, last.AvgRTT, segmentPlace(slowest.Name)))
+	}
+
+	// Loss only counts when it carries on to the last hop; loss at a router alone is usually
+	// the router limiting how many probes it answers
+	if last.Loss > 0 {
+		origin := last
+		for i := len(result.Hops) - 1; i >= 0; i-- {
+			hop := &result.Hops[i]
+			if hop.Address == "" {
+				continue
+			}
+			if hop.Loss == 0 {
+				break
+			}
+			origin = hop
+		}
+		parts = append(parts, fmt.Sprintf("%.0f%% loss starts at hop %d, %s", last.Loss, origin.Hop, segmentPlace(origin.Segment)))
+	}
+
+	if !result.Reached {
+		parts = append(parts, fmt.Sprintf("the route stops answering after hop %d, %s", last.Hop, segmentPlace(last.Segment)))
+	}
+	if len(parts) == 0 {
+		return "no latency or loss to speak of"
+	}
+	return strings.Join(parts, "; ")
+}
+
+// segmentPlace describes a segment for the verdict
+func segmentPlace(segment string) string {
+	switch segment {
+	case SegmentLocal:
+		return "on the local network"
+	case SegmentISP:
+		return "at the internet provider"
+	default:
+		return "beyond the internet provider"
+	}
+}
+
+// lastAnsweredHop returns the last hop numbered first to last that answered at least one probe
+func lastAnsweredHop(hops []TraceHop, first, last int) *TraceHop {
+	for i := len(hops) - 1; i >= 0; i-- {
+		if hops[i].Hop >= first && hops[i].Hop <= last && hops[i].Address != "" {
+			return &hops[i]
+		}
+	}
+	return nil
+}
+
+// isLocalAddress reports whether an address belongs to a local network
+// Carrier-grade NAT addresses (100.64.0.0/10) are not private: they belong to the provider
+func isLocalAddress(ip net.IP) bool {
+	return ip != nil && (ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsPrivate())
+}
+
+// storeTraceRun appends a traceroute to the runs kept in path and returns the earlier runs to the same host, oldest first
+// Only the latest maxTraceRuns runs of each host are kept
+func storeTraceRun(path string, result *TraceResult) ([]TraceResult, error) {
+	runs := make(map[string][]TraceResult)
+	if content, err := os.ReadFile(path); err == nil {
+		// A damaged file is started over rather than blocking new runs
+		json.Unmarshal(content, &runs)
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	key := strings.ToLower(result.Host)
+	earlier := runs[key]
+	kept := append(append([]TraceResult{}, earlier...), *result)
+	if len(kept) > maxTraceRuns {
+		kept = kept[len(kept)-maxTraceRuns:]
+	}
+	runs[key] = kept
+
+	content, err := json.MarshalIndent(runs, "", "  ")
+	if err != nil {
+		return earlier, fmt.Errorf("failed to encode traceroutes: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return earlier, fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return earlier, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return earlier, nil
+}
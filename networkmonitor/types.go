@@ -1,6 +1,18 @@
 package networkmonitor
 
-import "time"
+import (
+	"github.com/ahmadreza-log/simple-monitor/alert"
+	"github.com/ahmadreza-log/simple-monitor/partial"
+	"slices"
+	"time"
+)
+
+// Rate units of the network speed fields
+// Speeds are collected in megabits per second and converted to the configured unit at the end of each collection
+const (
+	RateUnitBits  = "Mbit/s" // Megabits (10^6 bits) per second, as link speeds and ISPs quote them
+	RateUnitBytes = "MB/s"   // Megabytes (10^6 bytes) per second, as file transfers are usually shown
+)
 
 // NetworkInterfaceInfo represents information about a network interface
 type NetworkInterfaceInfo struct {
@@ -18,6 +30,20 @@ type NetworkInterfaceInfo struct {
 	IsUp         bool   `json:"is_up"`         // Whether interface is up
 	IsLoopback   bool   `json:"is_loopback"`   // Whether interface is loopback
 	IsVirtual    bool   `json:"is_virtual"`    // Whether interface is virtual
+	Addresses    []NetworkAddressInfo `json:"addresses"` // All addresses assigned to the interface
+}
+
+// NetworkAddressInfo represents one IPv4 or IPv6 address assigned to an interface
+type NetworkAddressInfo struct {
+	Address           string        `json:"address"`                      // IP address without prefix
+	Family            string        `json:"family"`                       // IPv4 or IPv6
+	PrefixLength      int           `json:"prefix_length"`                // Network prefix length in bits
+	SubnetMask        string        `json:"subnet_mask,omitempty"`        // Dotted subnet mask (IPv4 only)
+	Scope             string        `json:"scope"`                        // host, link-local, private, global or multicast
+	Permanent         bool          `json:"permanent"`                    // Whether the address never expires (static)
+	ValidLifetime     time.Duration `json:"valid_lifetime,omitempty"`     // Remaining valid lifetime (SLAAC/DHCP addresses)
+	PreferredLifetime time.Duration `json:"preferred_lifetime,omitempty"` // Remaining preferred lifetime before the address is deprecated
+	LifetimeKnown     bool          `json:"lifetime_known"`               // Whether lifetimes could be read (Linux only)
 }
 
 // NetworkIOInfo represents network I/O statistics for an interface
@@ -27,9 +53,9 @@ type NetworkIOInfo struct {
 	BytesRecv     uint64  `json:"bytes_recv"`     // Total bytes received
 	PacketsSent   uint64  `json:"packets_sent"`   // Total packets sent
 	PacketsRecv   uint64  `json:"packets_recv"`   // Total packets received
-	SendSpeed     float64 `json:"send_speed"`     // Current send speed (Mbps)
-	RecvSpeed     float64 `json:"recv_speed"`     // Current receive speed (Mbps)
-	TotalSpeed    float64 `json:"total_speed"`    // Total throughput (Mbps)
+	SendSpeed     float64 `json:"send_speed"`     // Current send speed (in the rate unit)
+	RecvSpeed     float64 `json:"recv_speed"`     // Current receive speed (in the rate unit)
+	TotalSpeed    float64 `json:"total_speed"`    // Total throughput (in the rate unit)
 	SendErrors    uint64  `json:"send_errors"`   // Send errors
 	RecvErrors    uint64  `json:"recv_errors"`   // Receive errors
 	DropIn        uint64  `json:"drop_in"`       // Incoming packets dropped
@@ -48,20 +74,75 @@ type NetworkConnectionInfo struct {
 	User          string `json:"user"`            // Process owner
 	State         string `json:"state"`          // Connection state
 	Family        string `json:"family"`          // Address family (IPv4, IPv6)
+	FirstSeen     time.Time     `json:"first_seen"`      // When the connection was first seen
+	Age           time.Duration `json:"age"`             // How long the connection has been open
+	AgeLowerBound bool          `json:"age_lower_bound"` // Open before tracking started, so Age is a minimum
+	New           bool          `json:"new"`             // Younger than the short-lived connection age
+	LongLived     bool          `json:"long_lived"`      // Older than the long-lived connection age
+}
+
+// ListeningPort represents a socket waiting for connections or datagrams
+type ListeningPort struct {
+	Protocol    string `json:"protocol"`     // TCP or UDP
+	Address     string `json:"address"`      // Local address the socket is bound to (0.0.0.0 or :: for every address)
+	Port        uint32 `json:"port"`         // Local port
+	PID         int32  `json:"pid"`          // Owning process (0 when unknown)
+	ProcessName string `json:"process_name"` // Owning process name
+}
+
+// NetworkChurnInfo represents an endpoint that keeps opening short-lived connections
+type NetworkChurnInfo struct {
+	ProcessName   string `json:"process_name"`   // Process opening the connections
+	RemoteAddress string `json:"remote_address"` // Remote address:port
+	Count         int    `json:"count"`          // Short-lived connections closed within the churn window
+}
+
+// PortTrafficInfo represents the connections and throughput of one class of service ports
+type PortTrafficInfo struct {
+	Class       string  `json:"class"`       // Port class (Web, SSH, DNS, Database, Mail, File Transfer, Other)
+	Ports       []int   `json:"ports"`       // Busiest service ports seen in the class
+	Connections int     `json:"connections"` // Open connections in the class
+	Inbound     int     `json:"inbound"`     // Connections to ports this machine listens on
+	Outbound    int     `json:"outbound"`    // Connections this machine opened
+	SendSpeed   float64 `json:"send_speed"`  // Outgoing throughput (in the rate unit)
+	RecvSpeed   float64 `json:"recv_speed"`  // Incoming throughput (in the rate unit)
+	TotalSpeed  float64 `json:"total_speed"` // Total throughput (in the rate unit)
+	Share       float64 `json:"share"`       // Percentage of the measured throughput (of connections when throughput is not measured)
 }
 
 // NetworkProcessInfo represents network usage information for a specific process
 type NetworkProcessInfo struct {
 	PID           int32   `json:"pid"`            // Process ID
+	InstanceID    string  `json:"instance_id"`    // Process instance ID (PID and start time)
 	Name          string  `json:"name"`           // Process name
 	BytesSent     uint64  `json:"bytes_sent"`     // Bytes sent by process
 	BytesRecv     uint64  `json:"bytes_recv"`     // Bytes received by process
-	SendSpeed     float64 `json:"send_speed"`     // Send speed (Mbps)
-	RecvSpeed     float64 `json:"recv_speed"`     // Receive speed (Mbps)
-	TotalSpeed    float64 `json:"total_speed"`    // Total throughput (Mbps)
+	SendSpeed     float64 `json:"send_speed"`     // Send speed (in the rate unit)
+	RecvSpeed     float64 `json:"recv_speed"`     // Receive speed (in the rate unit)
+	TotalSpeed    float64 `json:"total_speed"`    // Total throughput (in the rate unit)
 	Connections   int     `json:"connections"`    // Number of connections
 	Status        string  `json:"status"`        // Process status
 	User          string  `json:"user"`           // Process owner
+	Rate          float64 `json:"rate"`           // Bytes per second of its TCP sockets (Linux), else of its disk and network I/O
+}
+
+// What the rate of a probable cause measures
+const (
+	CauseSourceSockets = "sockets" // Bytes sent and received on the process's TCP sockets
+	CauseSourceIO      = "io"      // The process's disk and network I/O, where socket counters cannot be read
+)
+
+// NetworkProbableCause identifies the process most likely saturating the link during a high-latency alert
+type NetworkProbableCause struct {
+	PID             int32   `json:"pid"`              // Process ID
+	InstanceID      string  `json:"instance_id"`      // Process instance ID (PID and start time)
+	Name            string  `json:"name"`             // Process name
+	Rate            float64 `json:"rate"`             // Current rate in bytes per second, measured as Source says
+	BaselineRate    float64 `json:"baseline_rate"`    // The process's usual rate while latency was normal
+	TrafficShare    float64 `json:"traffic_share"`    // Percentage of all sampled process traffic
+	Source          string  `json:"source"`           // CauseSourceSockets or CauseSourceIO
+	Latency         float64 `json:"latency"`          // Current average latency (ms)
+	BaselineLatency float64 `json:"baseline_latency"` // Average latency while no alert was active (ms)
 }
 
 // NetworkLatencyInfo represents network latency information
@@ -70,18 +151,183 @@ type NetworkLatencyInfo struct {
 	Latency       float64 `json:"latency"`        // Latency in milliseconds
 	PacketLoss    float64 `json:"packet_loss"`    // Packet loss percentage
 	Jitter        float64 `json:"jitter"`         // Jitter in milliseconds
+	P50           float64 `json:"p50"`            // Median latency of the recent samples (ms)
+	P95           float64 `json:"p95"`            // 95th percentile latency of the recent samples (ms), which latency alerts use
+	P99           float64 `json:"p99"`            // 99th percentile latency of the recent samples (ms)
+	Samples       int     `json:"samples"`        // Successful probes the percentiles cover
 	Status        string  `json:"status"`         // Connection status
 	LastChecked   time.Time `json:"last_checked"` // Last check time
 }
 
 // NetworkBandwidthInfo represents bandwidth usage information
 type NetworkBandwidthInfo struct {
-	TotalBandwidth    float64 `json:"total_bandwidth"`     // Total available bandwidth (Mbps)
-	UsedBandwidth     float64 `json:"used_bandwidth"`      // Currently used bandwidth (Mbps)
-	AvailableBandwidth float64 `json:"available_bandwidth"` // Available bandwidth (Mbps)
+	TotalBandwidth    float64 `json:"total_bandwidth"`     // Total available bandwidth (in the rate unit)
+	UsedBandwidth     float64 `json:"used_bandwidth"`      // Currently used bandwidth (in the rate unit)
+	AvailableBandwidth float64 `json:"available_bandwidth"` // Available bandwidth (in the rate unit)
 	Utilization       float64 `json:"utilization"`         // Bandwidth utilization percentage
-	PeakUsage         float64 `json:"peak_usage"`          // Peak usage (Mbps)
-	AverageUsage      float64 `json:"average_usage"`       // Average usage (Mbps)
+	PeakUsage         float64 `json:"peak_usage"`          // Peak usage (in the rate unit)
+	AverageUsage      float64 `json:"average_usage"`       // Average usage (in the rate unit)
+}
+
+// VPNTunnelInfo represents the health of a VPN or tunnel interface
+type VPNTunnelInfo struct {
+	Interface     string        `json:"interface"`      // Interface name (e.g., wg0, tun0)
+	Type          string        `json:"type"`           // Tunnel type (WireGuard, OpenVPN, IPsec, Tunnel)
+	IsUp          bool          `json:"is_up"`          // Whether the interface is up
+	Endpoint      string        `json:"endpoint"`       // Remote endpoint (WireGuard peers only)
+	Peers         int           `json:"peers"`          // Number of configured peers (WireGuard only)
+	LastHandshake time.Time     `json:"last_handshake"` // Most recent peer handshake (WireGuard only)
+	HandshakeAge  time.Duration `json:"handshake_age"`  // Time since the most recent handshake
+	BytesSent     uint64        `json:"bytes_sent"`     // Bytes sent through the tunnel
+	BytesRecv     uint64        `json:"bytes_recv"`     // Bytes received through the tunnel
+	IdleFor       time.Duration `json:"idle_for"`       // Time since the receive counter last changed
+	Status        string        `json:"status"`         // Tunnel status (Up, Stale, Down)
+	Addresses     []string      `json:"addresses,omitempty"` // Addresses assigned to the tunnel interface
+}
+
+// VPNTrafficInfo represents how throughput splits between VPN tunnels and traffic that bypasses them
+// Tunnel traffic also crosses a physical interface once encrypted, so direct traffic is what the
+// physical interfaces carry beyond the tunnel traffic plus its encapsulation overhead
+type VPNTrafficInfo struct {
+	TunnelSpeed       float64                 `json:"tunnel_speed"`       // Throughput inside the tunnels (in the rate unit)
+	PhysicalSpeed     float64                 `json:"physical_speed"`     // Throughput on physical interfaces, encrypted tunnel traffic included (in the rate unit)
+	DirectSpeed       float64                 `json:"direct_speed"`       // Estimated throughput bypassing the tunnels (in the rate unit)
+	DirectShare       float64                 `json:"direct_share"`       // Direct throughput as a percentage of tunnel plus direct throughput
+	BypassConnections []NetworkConnectionInfo `json:"bypass_connections"` // Connections to public addresses that do not use a tunnel address
+}
+
+// NetworkProxyInfo represents the configured system proxy
+type NetworkProxyInfo struct {
+	Enabled       bool   `json:"enabled"`         // Whether any proxy is configured
+	Source        string `json:"source"`          // Where the setting came from (environment, GNOME, macOS, Windows)
+	HTTPProxy     string `json:"http_proxy"`      // Proxy for HTTP traffic
+	HTTPSProxy    string `json:"https_proxy"`     // Proxy for HTTPS traffic
+	NoProxy       string `json:"no_proxy"`        // Hosts that bypass the proxy
+	AutoConfigURL string `json:"auto_config_url"` // PAC script URL
+}
+
+// Results of the captive portal probe sent through the configured proxy
+const (
+	ProxyReachable    = "Reachable"     // The probe URL answered 204 through the proxy
+	ProxyAuthRequired = "Auth Required" // The proxy answered 407: it needs credentials, which is not a portal
+	ProxyFailed       = "Failed"        // The proxy could not be reached or answered something else
+)
+
+// CaptivePortalInfo represents the result of the captive portal probe
+// The probe connects directly, bypassing any proxy, so a proxy's own answers are not mistaken for a portal
+type CaptivePortalInfo struct {
+	URL         string    `json:"url"`          // Probe URL that should return 204 No Content
+	StatusCode  int       `json:"status_code"`  // HTTP status returned by the probe
+	Reachable   bool      `json:"reachable"`    // Whether the probe got any HTTP response
+	Detected    bool      `json:"detected"`     // Whether the response looks like a captive portal
+	RedirectURL string    `json:"redirect_url"` // Where the portal redirected to (sign-in page)
+	Error       string    `json:"error"`        // Error if the probe failed
+	CheckedAt   time.Time `json:"checked_at"`   // When the probe ran
+
+	// The same probe sent through the configured proxy (empty without one, or with only a PAC script)
+	ProxyStatus string `json:"proxy_status"` // Reachable, Auth Required or Failed
+	ProxyError  string `json:"proxy_error"`  // Why the probe through the proxy failed
+}
+
+// GatewayMACChange records a change of the default gateway's MAC address
+type GatewayMACChange struct {
+	Timestamp time.Time `json:"timestamp"`  // When the change was observed
+	OldMAC    string    `json:"old_mac"`    // Previous MAC address
+	NewMAC    string    `json:"new_mac"`    // New MAC address
+}
+
+// GatewayInfo represents default gateway reachability and ARP state
+type GatewayInfo struct {
+	Address             string             `json:"address"`              // Gateway IP address
+	Interface           string             `json:"interface"`            // Interface the default route uses
+	MACAddress          string             `json:"mac_address"`          // Gateway MAC address from the ARP table
+	Reachable           bool               `json:"reachable"`            // Whether the gateway answered ping, or has a complete ARP entry when ping is unavailable or blocked
+	Method              string             `json:"method"`               // How reachability was decided (ping, or arp/arping for routers that drop ICMP)
+	RTT                 float64            `json:"rtt"`                  // Ping round-trip time in milliseconds
+	LastReachable       time.Time          `json:"last_reachable"`       // When the gateway was last reachable
+	ConsecutiveFailures int                `json:"consecutive_failures"` // Failed checks in a row
+	MACChanges          []GatewayMACChange `json:"mac_changes"`          // Recent MAC address changes (possible ARP spoofing)
+	CheckedAt           time.Time          `json:"checked_at"`           // When the gateway was last checked
+}
+
+// FirewallRuleInfo represents hit counters for a single firewall rule
+type FirewallRuleInfo struct {
+	Table   string `json:"table"`   // Table or ruleset (filter, nat, inet filter, pf)
+	Chain   string `json:"chain"`   // Chain the rule belongs to (INPUT, FORWARD, ...)
+	Rule    string `json:"rule"`    // Rule specification as printed by the firewall tool
+	Action  string `json:"action"`  // Rule target (ACCEPT, DROP, REJECT, block, ...)
+	Packets uint64 `json:"packets"` // Packets matched by the rule
+	Bytes   uint64 `json:"bytes"`   // Bytes matched by the rule
+	Dropped bool   `json:"dropped"` // Whether the rule drops or rejects traffic
+}
+
+// FirewallInfo represents a snapshot of firewall rule counters
+type FirewallInfo struct {
+	Backend        string             `json:"backend"`         // Firewall the counters came from (iptables, nftables, pf, Windows Firewall)
+	Available      bool               `json:"available"`       // Whether counters could be read
+	Error          string             `json:"error"`           // Why counters are unavailable (missing tool, needs root)
+	Rules          []FirewallRuleInfo `json:"rules"`           // Top rules by packet hits
+	DroppedPackets uint64             `json:"dropped_packets"` // Packets dropped or rejected across all rules and policies
+	DroppedBytes   uint64             `json:"dropped_bytes"`   // Bytes dropped or rejected across all rules and policies
+	RecentDrops    uint64             `json:"recent_drops"`    // Packets dropped since the previous snapshot
+	CheckedAt      time.Time          `json:"checked_at"`      // When the counters were read
+}
+
+// WakeHost is a machine that can be woken with a Wake-on-LAN magic packet
+type WakeHost struct {
+	MAC       string `json:"mac"`       // MAC address of the machine's network card, e.g. 00:11:22:33:44:55
+	Broadcast string `json:"broadcast"` // Address the packet is sent to, host or host:port (empty for 255.255.255.255:9)
+}
+
+// PingResult represents an on-demand ping of one host
+type PingResult struct {
+	Host      string    `json:"host"`      // Host as entered
+	Address   string    `json:"address"`   // Resolved IP address
+	Method    string    `json:"method"`    // How replies were measured: icmp (helper), ping or tcp/80
+	Sent      int       `json:"sent"`      // Probes sent
+	Received  int       `json:"received"`  // Probes answered
+	Loss      float64   `json:"loss"`      // Packet loss percentage
+	RTTs      []float64 `json:"rtts"`      // Round-trip time of each probe in milliseconds (-1 when lost)
+	MinRTT    float64   `json:"min_rtt"`   // Fastest reply (ms)
+	AvgRTT    float64   `json:"avg_rtt"`   // Average reply (ms)
+	MaxRTT    float64   `json:"max_rtt"`   // Slowest reply (ms)
+	Timestamp time.Time `json:"timestamp"` // When the ping finished
+}
+
+// Network segments a traceroute hop can belong to
+const (
+	SegmentLocal  = "Local"  // This machine's own network, up to the home or office router
+	SegmentISP    = "ISP"    // The first routers of the internet provider
+	SegmentBeyond = "Beyond" // Everything past the provider
+)
+
+// TraceHop represents one hop of a traceroute
+type TraceHop struct {
+	Hop     int       `json:"hop"`     // Distance from this machine (TTL)
+	Address string    `json:"address"` // Router that answered (empty when none did)
+	RTTs    []float64 `json:"rtts"`    // Round-trip time of each probe in milliseconds (-1 when lost)
+	AvgRTT  float64   `json:"avg_rtt"` // Average of the answered probes (ms)
+	Loss    float64   `json:"loss"`    // Percentage of probes not answered
+	Segment string    `json:"segment"` // Local, ISP or Beyond
+}
+
+// TraceSegment summarizes the hops of one network segment
+type TraceSegment struct {
+	Name         string  `json:"name"`          // Local, ISP or Beyond
+	FirstHop     int     `json:"first_hop"`     // First hop in the segment
+	LastHop      int     `json:"last_hop"`      // Last hop in the segment
+	AddedLatency float64 `json:"added_latency"` // Round-trip time added by the segment (ms)
+}
+
+// TraceResult represents an on-demand traceroute to one host
+type TraceResult struct {
+	Host      string         `json:"host"`      // Host as entered
+	Address   string         `json:"address"`   // Resolved IP address
+	Hops      []TraceHop     `json:"hops"`      // Hops in order
+	Segments  []TraceSegment `json:"segments"`  // Segments the route passes, in order
+	Reached   bool           `json:"reached"`   // Whether the last hop is the host itself
+	Verdict   string         `json:"verdict"`   // Where the latency and loss come from
+	Timestamp time.Time      `json:"timestamp"` // When the traceroute finished
 }
 
 // NetworkMonitorData represents comprehensive network monitoring data
@@ -95,6 +341,14 @@ type NetworkMonitorData struct {
 	// Network connections
 	Connections []NetworkConnectionInfo `json:"connections"` // Active network connections
 
+	// Connection churn (connections closing soon after they opened)
+	ShortLivedConnections int                `json:"short_lived_connections"` // Short-lived connections closed within the churn window
+	ConnectionChurn       []NetworkChurnInfo `json:"connection_churn"`        // Endpoints with repeated short-lived connections
+
+	// Traffic by service port class (web, SSH, DNS, databases...)
+	PortTraffic         []PortTrafficInfo `json:"port_traffic"`          // Port classes sorted by throughput
+	PortTrafficMeasured bool              `json:"port_traffic_measured"` // Whether throughput comes from per-socket counters (Linux TCP) or only connections are counted
+
 	// Top processes by network usage
 	TopProcesses []NetworkProcessInfo `json:"top_processes"` // Top network-consuming processes
 
@@ -104,14 +358,29 @@ type NetworkMonitorData struct {
 	// Bandwidth information
 	BandwidthInfo NetworkBandwidthInfo `json:"bandwidth_info"` // Bandwidth usage information
 
+	// VPN and tunnel health
+	VPNTunnels []VPNTunnelInfo `json:"vpn_tunnels"` // Detected VPN/tunnel interfaces
+	VPNTraffic *VPNTrafficInfo `json:"vpn_traffic,omitempty"` // Tunnel vs direct traffic while a tunnel is up
+
+	// Default gateway
+	Gateway GatewayInfo `json:"gateway"` // Default gateway reachability and MAC tracking
+
+	// Firewall counters
+	Firewall FirewallInfo `json:"firewall"` // Firewall rule hit and drop counters
+
+	// Proxy and captive portal
+	Proxy         NetworkProxyInfo  `json:"proxy"`          // Configured system proxy
+	CaptivePortal CaptivePortalInfo `json:"captive_portal"` // Captive portal probe result
+
 	// Overall network statistics
 	TotalBytesSent    uint64  `json:"total_bytes_sent"`     // Total bytes sent across all interfaces
 	TotalBytesRecv    uint64  `json:"total_bytes_recv"`     // Total bytes received across all interfaces
 	TotalPacketsSent  uint64  `json:"total_packets_sent"`   // Total packets sent across all interfaces
 	TotalPacketsRecv  uint64  `json:"total_packets_recv"`   // Total packets received across all interfaces
-	TotalSendSpeed    float64 `json:"total_send_speed"`     // Total send speed across all interfaces
-	TotalRecvSpeed    float64 `json:"total_recv_speed"`     // Total receive speed across all interfaces
-	TotalThroughput  float64 `json:"total_throughput"`     // Total network throughput
+	TotalSendSpeed    float64 `json:"total_send_speed"`     // Total send speed across all interfaces (in the rate unit)
+	TotalRecvSpeed    float64 `json:"total_recv_speed"`     // Total receive speed across all interfaces (in the rate unit)
+	TotalThroughput  float64 `json:"total_throughput"`     // Total network throughput (in the rate unit)
+	RateUnit         string  `json:"rate_unit"`            // Unit of every speed and bandwidth value (Mbit/s or MB/s)
 
 	// Network performance metrics
 	AverageLatency    float64 `json:"average_latency"`      // Average latency across all targets
@@ -124,11 +393,27 @@ type NetworkMonitorData struct {
 	PacketLossWarning  bool  `json:"packet_loss_warning"`  // Packet loss warning
 	BandwidthWarning   bool  `json:"bandwidth_warning"`    // Bandwidth usage warning
 	ConnectionWarning  bool  `json:"connection_warning"`    // Connection issues warning
+	VPNWarning         bool  `json:"vpn_warning"`           // VPN tunnel down or stale warning
+	VPNBypassWarning   bool  `json:"vpn_bypass_warning"`    // Traffic bypassing an active VPN tunnel warning
+	CaptivePortalWarning bool `json:"captive_portal_warning"` // Captive portal detected warning
+	GatewayLossWarning bool  `json:"gateway_loss_warning"` // Default gateway unreachable warning
+	GatewayMACWarning  bool  `json:"gateway_mac_warning"`  // Default gateway MAC changed warning (possible ARP spoofing)
+	ProbableCause      *NetworkProbableCause `json:"probable_cause,omitempty"` // Likely bandwidth hog during a high-latency alert
+	StatusExplanation  string `json:"status_explanation"`  // Plain explanation of the network status (captive portal, proxy-only, offline)
 
 	// Monitoring configuration
 	RefreshInterval time.Duration `json:"refresh_interval"` // How often data is refreshed
 	IsMonitoring    bool          `json:"is_monitoring"`    // Whether monitoring is active
 
+	// Runbook notes of the raised alerts whose rule has a note
+	AlertNotes []alert.Note `json:"alert_notes,omitempty"` // Ordered by alert key
+
+	// Sections that could not be collected; everything else in the snapshot is still valid
+	SectionErrors partial.Errors `json:"section_errors,omitempty"` // Reason keyed by section name
+
+	// Data source
+	Simulated bool `json:"simulated,omitempty"` // Whether the data is synthetic (--simulate) rather than read from the system
+
 	// Timestamps
 	Timestamp time.Time     `json:"timestamp"` // When this data was collected
 	Uptime    time.Duration `json:"uptime"`    // System uptime
@@ -136,15 +421,24 @@ type NetworkMonitorData struct {
 
 // NetworkMonitorConfig represents configuration options for network monitoring
 type NetworkMonitorConfig struct {
+	Enabled bool `json:"enabled"` // Whether the monitor runs; a disabled monitor is left out of every view, snapshot and alert
+
 	// Monitoring settings
 	RefreshInterval     time.Duration `json:"refresh_interval"`     // How often to refresh data
 	MaxProcesses        int           `json:"max_processes"`        // Maximum number of processes to track
 	MaxConnections      int           `json:"max_connections"`      // Maximum number of connections to track
 	LatencyWarning      float64       `json:"latency_warning"`      // Latency warning threshold (ms)
 	LatencyCritical     float64       `json:"latency_critical"`     // Latency critical threshold (ms)
+	LatencyWindow       int           `json:"latency_window"`       // Latency samples kept per target; alerts use their 95th percentile (1 alerts on every probe)
 	PacketLossWarning   float64       `json:"packet_loss_warning"`  // Packet loss warning threshold (%)
 	BandwidthWarning    float64       `json:"bandwidth_warning"`   // Bandwidth warning threshold (%)
 	ConnectionTimeout   time.Duration `json:"connection_timeout"`   // Connection timeout
+	ShortLivedConnectionAge time.Duration `json:"short_lived_connection_age"` // Connections closing before this age count as churn
+	LongLivedConnectionAge  time.Duration `json:"long_lived_connection_age"`  // Connections open longer than this are highlighted
+	ConnectionChurnWindow   time.Duration `json:"connection_churn_window"`    // How long closed short-lived connections are remembered
+
+	// Alert behaviour (hysteresis and minimum durations) keyed by alert: latency, packet_loss, bandwidth
+	AlertRules map[string]alert.Rule `json:"alert_rules"` // Alerts without a rule flip exactly at their thresholds
 
 	// Display settings
 	ShowInterfaces    bool `json:"show_interfaces"`     // Whether to show interface information
@@ -154,18 +448,60 @@ type NetworkMonitorConfig struct {
 	ShowLatency      bool `json:"show_latency"`       // Whether to show latency information
 	ShowBandwidth    bool `json:"show_bandwidth"`     // Whether to show bandwidth information
 	ShowPerformance  bool `json:"show_performance"`   // Whether to show performance metrics
+	ShowVPN          bool `json:"show_vpn"`           // Whether to show VPN/tunnel health
+	ShowConnectivity bool `json:"show_connectivity"`  // Whether to detect proxies and captive portals; off by default because the probe requests captive_portal_url, an external server
+	ShowGateway      bool `json:"show_gateway"`       // Whether to monitor the default gateway
+	ShowFirewall     bool `json:"show_firewall"`      // Whether to show firewall rule counters
+	ShowProbableCause bool `json:"show_probable_cause"` // Whether to name the likely bandwidth hog during high-latency alerts
+	ShowPortTraffic   bool `json:"show_port_traffic"`   // Whether to break traffic down by service port class
+	RateUnit          string `json:"rate_unit"`           // Unit of speeds, bandwidth and speed thresholds in the display, exports and collected data (Mbit/s or MB/s)
 
 	// Export settings
 	ExportToFile   bool          `json:"export_to_file"`  // Whether to export data to file
 	ExportInterval time.Duration `json:"export_interval"` // How often to export data
-	ExportFormat   string        `json:"export_format"`   // Export format (json, csv, txt)
+	ExportFormat   string        `json:"export_format"`   // Export format (json, csv, txt, protobuf)
+	PersistHistory      bool          `json:"persist_history"`       // Whether to keep downsampled long-term history on disk
+	HistorySaveInterval time.Duration `json:"history_save_interval"` // How often the long-term history file is rewritten
+	HistorySize         int           `json:"history_size"`          // Samples kept in the in-memory history behind live trends
+	HistoryResolution   time.Duration `json:"history_resolution"`    // Minimum time between in-memory history samples (0 samples every refresh)
 
 	// Filter settings
-	MinNetworkUsage     float64 `json:"min_network_usage"`     // Minimum network usage to show process
+	MinNetworkUsage     float64 `json:"min_network_usage"`     // Minimum throughput (in the rate unit) to show a process
 	ProcessNameFilter   string  `json:"process_name_filter"`   // Filter processes by name
 	InterfaceFilter     string  `json:"interface_filter"`      // Filter specific interfaces
 	ConnectionTypeFilter string  `json:"connection_type_filter"` // Filter connection types
 	LatencyTargets      []string `json:"latency_targets"`      // Targets for latency monitoring
+
+	// VPN settings
+	VPNInterfaces     []string      `json:"vpn_interfaces"`      // Tunnels that are expected to exist, missing ones reported as down; utun, tap and ppp devices are only monitored when listed
+	VPNHandshakeStale time.Duration `json:"vpn_handshake_stale"` // WireGuard handshake age after which a tunnel is stale
+	VPNIdleStale      time.Duration `json:"vpn_idle_stale"`      // Time without received traffic after which other tunnels are stale
+	VPNOverhead        float64  `json:"vpn_overhead"`         // Encapsulation overhead of tunnel traffic on the physical interface (%)
+	VPNBypassThreshold float64  `json:"vpn_bypass_threshold"` // Direct throughput (in the rate unit) that raises the bypass alert while a tunnel is up (0 alerts on bypass connections only)
+	VPNBypassAllowed   []string `json:"vpn_bypass_allowed"`   // Process names or remote IPs expected to bypass the tunnel (split tunneling)
+
+	// Connectivity settings
+	CaptivePortalURL          string        `json:"captive_portal_url"`          // URL expected to answer 204 No Content when not behind a portal
+	ConnectivityCheckInterval time.Duration `json:"connectivity_check_interval"` // How often to re-probe for proxies and captive portals
+
+	// Gateway settings
+	GatewayCheckInterval time.Duration `json:"gateway_check_interval"` // How often to ping the gateway and read the ARP table
+	GatewayLossThreshold int           `json:"gateway_loss_threshold"` // Failed checks in a row before the gateway counts as lost
+	GatewayMACAlertTime  time.Duration `json:"gateway_mac_alert_time"` // How long a MAC change keeps the warning active
+
+	// Firewall settings
+	MaxFirewallRules      int           `json:"max_firewall_rules"`      // Maximum number of firewall rules to report
+	FirewallCheckInterval time.Duration `json:"firewall_check_interval"` // How often to re-read firewall counters
+
+	// Probable cause settings
+	CauseSpikeFactor float64 `json:"cause_spike_factor"` // How many times its usual rate a process must reach to count as a spike
+	CauseMinRate     float64 `json:"cause_min_rate"`     // Minimum rate (bytes per second) for a process to be named as the cause
+
+	// Network tools settings
+	WakeHosts    map[string]WakeHost `json:"wake_hosts"`     // Machines that can be woken from the network menu, by name
+	PingCount    int                 `json:"ping_count"`     // Probes sent by an on-demand ping
+	TraceHops    int                 `json:"trace_hops"`     // Maximum hops of an on-demand traceroute
+	TraceISPHops int                 `json:"trace_isp_hops"` // Hops after the local network counted as the internet provider's
 }
 
 // NetworkUsageHistory represents historical network usage data for graphing
@@ -174,9 +510,9 @@ type NetworkUsageHistory struct {
 	Timestamps     []time.Time `json:"timestamps"`      // Time points for data
 	TotalSent      []float64  `json:"total_sent"`      // Total bytes sent over time
 	TotalRecv      []float64  `json:"total_recv"`      // Total bytes received over time
-	SendSpeed      []float64  `json:"send_speed"`      // Send speed over time
-	RecvSpeed      []float64  `json:"recv_speed"`      // Receive speed over time
-	Throughput     []float64  `json:"throughput"`      // Total throughput over time
+	SendSpeed      []float64  `json:"send_speed"`      // Send speed over time (Mbit/s whatever the rate unit, so units never mix)
+	RecvSpeed      []float64  `json:"recv_speed"`      // Receive speed over time (Mbit/s)
+	Throughput     []float64  `json:"throughput"`      // Total throughput over time (Mbit/s)
 	Latency        []float64  `json:"latency"`         // Average latency over time
 	Utilization    []float64  `json:"utilization"`     // Network utilization over time
 
@@ -184,3 +520,17 @@ type NetworkUsageHistory struct {
 	MaxDataPoints int `json:"max_data_points"` // Maximum number of data points to store
 	DataPointCount int `json:"data_point_count"` // Current number of data points
 }
+
+// Clone returns a deep copy of the history
+func (history *NetworkUsageHistory) Clone() *NetworkUsageHistory {
+	clone := *history
+	clone.Timestamps = slices.Clone(history.Timestamps)
+	clone.TotalSent = slices.Clone(history.TotalSent)
+	clone.TotalRecv = slices.Clone(history.TotalRecv)
+	clone.SendSpeed = slices.Clone(history.SendSpeed)
+	clone.RecvSpeed = slices.Clone(history.RecvSpeed)
+	clone.Throughput = slices.Clone(history.Throughput)
+	clone.Latency = slices.Clone(history.Latency)
+	clone.Utilization = slices.Clone(history.Utilization)
+	return &clone
+}
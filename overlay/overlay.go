@@ -0,0 +1,375 @@
+// Package overlay imports an external time series from CSV, such as an application's requests per second,
+// and draws it under the CPU and memory history graphs on the same time axis, so system load can be
+// correlated with application load
+package overlay
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// File is the name of the imported series inside the logs directory
+const File = "overlay.json"
+
+// Path is where the imported series is saved by default
+var Path = filepath.Join("logs", File)
+
+// timeHeaders are the column headers recognized as the time column; without one the first column is used
+var timeHeaders = []string{"time", "timestamp", "date", "datetime", "ts"}
+
+// timeLayouts are the accepted timestamp formats besides Unix seconds and milliseconds
+// Times without a zone are read as local time
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006/01/02 15:04:05",
+	"02/Jan/2006:15:04:05 -0700",
+}
+
+// sparkLevels are the bar heights of a graph line, lowest first
+var sparkLevels = []rune("▁▂▃▄▅▆▇█")
+
+// Parse reads a series from CSV with a header row: one time column and the value column named column,
+// or the first other column when column is empty
+// Rows without a value are skipped as gaps; the points are returned oldest first
+func Parse(reader io.Reader, column string) (*Series, error) {
+	records := csv.NewReader(reader)
+	records.FieldsPerRecord = -1
+	records.TrimLeadingSpace = true
+	records.Comment = '#'
+
+	header, err := records.Read()
+	if err == io.EOF {
+		return nil, errors.New("the CSV file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the CSV header: %w", err)
+	}
+	header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	timeColumn, valueColumn, err := columns(header, column)
+	if err != nil {
+		return nil, err
+	}
+
+	series := &Series{Name: strings.TrimSpace(header[valueColumn])}
+	for {
+		record, err := records.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the CSV file: %w", err)
+		}
+		line, _ := records.FieldPos(0)
+		if len(record) <= max(timeColumn, valueColumn) || strings.TrimSpace(record[valueColumn]) == "" {
+			continue
+		}
+
+		timestamp, err := parseTime(record[timeColumn])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(record[valueColumn]), 64)
+		if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+			return nil, fmt.Errorf("line %d: %q is not a number", line, record[valueColumn])
+		}
+		series.Points = append(series.Points, Point{Time: timestamp, Value: value})
+	}
+	if len(series.Points) == 0 {
+		return nil, fmt.Errorf("the CSV file has no %s values", series.Name)
+	}
+
+	sort.SliceStable(series.Points, func(i, j int) bool {
+		return series.Points[i].Time.Before(series.Points[j].Time)
+	})
+	return series, nil
+}
+
+// columns finds the time and value columns in a CSV header
+func columns(header []string, column string) (int, int, error) {
+	if len(header) < 2 {
+		return 0, 0, errors.New("the CSV file needs a time column and a value column")
+	}
+
+	timeColumn := 0
+	for i, name := range header {
+		if containsFold(timeHeaders, strings.TrimSpace(name)) {
+			timeColumn = i
+			break
+		}
+	}
+
+	if column == "" {
+		if timeColumn == 0 {
+			return timeColumn, 1, nil
+		}
+		return timeColumn, 0, nil
+	}
+	for i, name := range header {
+		if i != timeColumn && strings.EqualFold(strings.TrimSpace(name), column) {
+			return timeColumn, i, nil
+		}
+	}
+	return 0, 0, fmt.Errorf("the CSV file has no value column %q (columns: %s)", column, strings.Join(header, ", "))
+}
+
+// containsFold returns whether names contains name, ignoring case
+func containsFold(names []string, name string) bool {
+	for _, candidate := range names {
+		if strings.EqualFold(candidate, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTime reads a timestamp in one of timeLayouts, or as Unix seconds or milliseconds
+func parseTime(text string) (time.Time, error) {
+	text = strings.TrimSpace(text)
+	for _, layout := range timeLayouts {
+		if timestamp, err := time.ParseInLocation(layout, text, time.Local); err == nil {
+			return timestamp, nil
+		}
+	}
+
+	seconds, err := strconv.ParseFloat(text, 64)
+	if err != nil || seconds <= 0 || math.IsInf(seconds, 0) {
+		return time.Time{}, fmt.Errorf("%q is not a timestamp (use RFC 3339, \"2006-01-02 15:04:05\" or Unix seconds)", text)
+	}
+	// Unix seconds stay below 1e11 until the year 5138, so larger values are milliseconds
+	if seconds >= 1e11 {
+		seconds /= 1000
+	}
+	whole, fraction := math.Modf(seconds)
+	return time.Unix(int64(whole), int64(fraction*1e9)), nil
+}
+
+// Import reads the CSV file at csvPath and saves its series to path, replacing the series imported before
+func Import(csvPath, column, path string) (*Series, error) {
+	file, err := os.Open(csvPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	series, err := Parse(file, column)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", csvPath, err)
+	}
+	series.Source = csvPath
+	if absolute, err := filepath.Abs(csvPath); err == nil {
+		series.Source = absolute
+	}
+	series.Imported = time.Now()
+
+	if err := Save(path, series); err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+// Save writes a series to path
+func Save(path string, series *Series) error {
+	content, err := json.MarshalIndent(series, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode the overlay: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, append(content, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// Load reads a series saved by Save; the error wraps os.ErrNotExist when none was imported
+func Load(path string) (*Series, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var series Series
+	if err := json.Unmarshal(content, &series); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if len(series.Points) == 0 {
+		return nil, fmt.Errorf("%s has no points", path)
+	}
+	return &series, nil
+}
+
+// Series returns the series imported at path, or nil when there is none or it cannot be read
+func (source *Source) Series(path string) *Series {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		source.path, source.series = "", nil
+		return nil
+	}
+	if path == source.path && info.ModTime().Equal(source.modified) && info.Size() == source.size {
+		return source.series
+	}
+
+	// A broken file shows no overlay; `simple-monitor overlay show` reports why
+	series, err := Load(path)
+	if err != nil {
+		series = nil
+	}
+	source.path, source.modified, source.size, source.series = path, info.ModTime(), info.Size(), series
+	return series
+}
+
+// Start returns the time of the first point
+func (series *Series) Start() time.Time {
+	return series.Points[0].Time
+}
+
+// End returns the time of the last point
+func (series *Series) End() time.Time {
+	return series.Points[len(series.Points)-1].Time
+}
+
+// Align returns the series value at each of times, oldest first, for drawing it under a graph sampled then
+// Each time gets the average of the points since the previous time; without any, the latest earlier point
+// holds unless it is more than twice the usual spacing of the series old, and a time left without a value is NaN
+func (series *Series) Align(times []time.Time) []float64 {
+	values := make([]float64, len(times))
+	hold := 2 * series.spacing()
+
+	for i, at := range times {
+		var from time.Time
+		switch {
+		case i > 0:
+			from = times[i-1]
+		case len(times) > 1:
+			from = at.Add(-times[1].Sub(at))
+		default:
+			from = at.Add(-hold)
+		}
+
+		end := sort.Search(len(series.Points), func(j int) bool { return series.Points[j].Time.After(at) })
+		sum, count := 0.0, 0
+		for j := end - 1; j >= 0 && series.Points[j].Time.After(from); j-- {
+			sum += series.Points[j].Value
+			count++
+		}
+
+		switch {
+		case count > 0:
+			values[i] = sum / float64(count)
+		case end > 0 && at.Sub(series.Points[end-1].Time) <= hold:
+			values[i] = series.Points[end-1].Value
+		default:
+			values[i] = math.NaN()
+		}
+	}
+	return values
+}
+
+// spacing returns the median time between consecutive points, 0 for a single point
+func (series *Series) spacing() time.Duration {
+	if len(series.Points) < 2 {
+		return 0
+	}
+	gaps := make([]time.Duration, 0, len(series.Points)-1)
+	for i := 1; i < len(series.Points); i++ {
+		gaps = append(gaps, series.Points[i].Time.Sub(series.Points[i-1].Time))
+	}
+	sort.Slice(gaps, func(i, j int) bool { return gaps[i] < gaps[j] })
+	return gaps[len(gaps)/2]
+}
+
+// Line draws the series under a graph of the last width of times, scaled to its own peak in that range
+// ok is false when the series has no value there
+func (series *Series) Line(times []time.Time, width int) (line string, peak float64, ok bool) {
+	if len(times) > width {
+		times = times[len(times)-width:]
+	}
+	values := series.Align(times)
+	for _, value := range values {
+		if !math.IsNaN(value) {
+			peak = math.Max(peak, value)
+			ok = true
+		}
+	}
+	return Sparkline(values, peak, width), peak, ok
+}
+
+// Sparkline draws the last width values as one line of bars scaled to top; a NaN value is left blank
+func Sparkline(values []float64, top float64, width int) string {
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+	var builder strings.Builder
+	for _, value := range values {
+		if math.IsNaN(value) {
+			builder.WriteRune(' ')
+			continue
+		}
+		level := 0
+		if top > 0 {
+			level = int(value / top * float64(len(sparkLevels)-1))
+		}
+		builder.WriteRune(sparkLevels[max(min(level, len(sparkLevels)-1), 0)])
+	}
+	return builder.String()
+}
+
+// Format shows a value of an imported series with as many decimals as its size needs
+func Format(value float64) string {
+	switch magnitude := math.Abs(value); {
+	case magnitude >= 100:
+		return strconv.FormatFloat(value, 'f', 0, 64)
+	case magnitude >= 1:
+		return strconv.FormatFloat(value, 'f', 1, 64)
+	}
+	return strconv.FormatFloat(value, 'g', 3, 64)
+}
+
+// Correlation returns the Pearson correlation of two aligned value lists over the positions where both have
+// a value; ok is false with fewer than three such positions or when either list is constant there
+func Correlation(first, second []float64) (coefficient float64, ok bool) {
+	var pairs [][2]float64
+	for i := 0; i < len(first) && i < len(second); i++ {
+		if !math.IsNaN(first[i]) && !math.IsNaN(second[i]) {
+			pairs = append(pairs, [2]float64{first[i], second[i]})
+		}
+	}
+	if len(pairs) < 3 {
+		return 0, false
+	}
+
+	var meanFirst, meanSecond float64
+	for _, pair := range pairs {
+		meanFirst += pair[0]
+		meanSecond += pair[1]
+	}
+	meanFirst /= float64(len(pairs))
+	meanSecond /= float64(len(pairs))
+
+	var covariance, varianceFirst, varianceSecond float64
+	for _, pair := range pairs {
+		covariance += (pair[0] - meanFirst) * (pair[1] - meanSecond)
+		varianceFirst += (pair[0] - meanFirst) * (pair[0] - meanFirst)
+		varianceSecond += (pair[1] - meanSecond) * (pair[1] - meanSecond)
+	}
+	if varianceFirst == 0 || varianceSecond == 0 {
+		return 0, false
+	}
+	return covariance / math.Sqrt(varianceFirst*varianceSecond), true
+}
@@ -0,0 +1,189 @@
+package overlay
+
+import (
+	"math"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name       string
+		csv        string
+		column     string
+		wantName   string
+		wantTimes  []int64 // Unix seconds
+		wantValues []float64
+		wantErr    string
+	}{
+		{
+			name:       "time first",
+			csv:        "time,rps\n2026-01-05T10:00:00Z,12\n2026-01-05T10:00:10Z,15.5\n",
+			wantName:   "rps",
+			wantTimes:  []int64{1767607200, 1767607210},
+			wantValues: []float64{12, 15.5},
+		},
+		{
+			name:       "time column found by header",
+			csv:        "\ufefflatency, Timestamp\n3,1767607200\n4,1767607210000\n",
+			wantName:   "latency",
+			wantTimes:  []int64{1767607200, 1767607210},
+			wantValues: []float64{3, 4},
+		},
+		{
+			name:       "named column",
+			csv:        "ts,errors,RPS\n1767607200,1,100\n",
+			column:     "rps",
+			wantName:   "RPS",
+			wantTimes:  []int64{1767607200},
+			wantValues: []float64{100},
+		},
+		{
+			name:       "gaps, comments and unsorted rows",
+			csv:        "ts,rps\n# warm-up\n1767607220,3\n1767607210,\n1767607200,1\n1767607230\n",
+			wantName:   "rps",
+			wantTimes:  []int64{1767607200, 1767607220},
+			wantValues: []float64{1, 3},
+		},
+		{name: "empty", csv: "", wantErr: "empty"},
+		{name: "one column", csv: "ts\n1767607200\n", wantErr: "needs a time column"},
+		{name: "unknown column", csv: "ts,rps\n1767607200,1\n", column: "errors", wantErr: `no value column "errors"`},
+		{name: "no values", csv: "ts,rps\n1767607200,\n", wantErr: "no rps values"},
+		{name: "bad time", csv: "ts,rps\nyesterday,1\n", wantErr: "line 2"},
+		{name: "bad value", csv: "ts,rps\n1767607200,NaN\n", wantErr: "not a number"},
+	}
+	for _, tt := range tests {
+		series, err := Parse(strings.NewReader(tt.csv), tt.column)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: Parse() error = %v, want one containing %q", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: Parse() error = %v", tt.name, err)
+			continue
+		}
+		var times []int64
+		var values []float64
+		for _, point := range series.Points {
+			times = append(times, point.Time.Unix())
+			values = append(values, point.Value)
+		}
+		if series.Name != tt.wantName || !slices.Equal(times, tt.wantTimes) || !slices.Equal(values, tt.wantValues) {
+			t.Errorf("%s: Parse() = %s %v %v, want %s %v %v", tt.name, series.Name, times, values, tt.wantName, tt.wantTimes, tt.wantValues)
+		}
+	}
+}
+
+func TestAlign(t *testing.T) {
+	start := time.Date(2026, 1, 5, 10, 0, 0, 0, time.UTC)
+	series := &Series{Points: []Point{
+		{Time: start, Value: 2},
+		{Time: start.Add(10 * time.Second), Value: 4},
+		{Time: start.Add(15 * time.Second), Value: 8},
+		{Time: start.Add(20 * time.Second), Value: 6},
+	}}
+	// The usual spacing is 5s, so a value holds for 10s
+	times := []time.Time{start, start.Add(10 * time.Second), start.Add(20 * time.Second), start.Add(30 * time.Second), start.Add(40 * time.Second)}
+	values := series.Align(times)
+
+	want := []float64{2, 4, 7, 6, math.NaN()}
+	for i := range want {
+		if values[i] != want[i] && !(math.IsNaN(values[i]) && math.IsNaN(want[i])) {
+			t.Errorf("Align() = %v, want %v", values, want)
+			break
+		}
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		values []float64
+		top    float64
+		width  int
+		want   string
+	}{
+		{values: []float64{0, 1, 2, 3, 4, 5, 6, 7}, top: 7, width: 8, want: "▁▂▃▄▅▆▇█"},
+		{values: []float64{1, math.NaN(), 2}, top: 2, width: 3, want: "▄ █"},
+		{values: []float64{9, 0, 4}, top: 4, width: 2, want: "▁█"},
+		{values: []float64{3, 3}, top: 0, width: 2, want: "▁▁"},
+	}
+	for _, tt := range tests {
+		if got := Sparkline(tt.values, tt.top, tt.width); got != tt.want {
+			t.Errorf("Sparkline(%v, %g, %d) = %q, want %q", tt.values, tt.top, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		value float64
+		want  string
+	}{
+		{value: 1234.5, want: "1234"},
+		{value: 12.34, want: "12.3"},
+		{value: -3.25, want: "-3.2"},
+		{value: 0.01234, want: "0.0123"},
+		{value: 0, want: "0"},
+	}
+	for _, tt := range tests {
+		if got := Format(tt.value); got != tt.want {
+			t.Errorf("Format(%g) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestCorrelation(t *testing.T) {
+	tests := []struct {
+		name   string
+		first  []float64
+		second []float64
+		want   float64
+		wantOK bool
+	}{
+		{name: "rising together", first: []float64{1, 2, 3, 4}, second: []float64{10, 20, 30, 40}, want: 1, wantOK: true},
+		{name: "opposite", first: []float64{1, 2, 3}, second: []float64{3, 2, 1}, want: -1, wantOK: true},
+		{name: "gaps skipped", first: []float64{1, math.NaN(), 2, 3}, second: []float64{2, 9, 4, 6}, want: 1, wantOK: true},
+		{name: "too few pairs", first: []float64{1, 2, math.NaN()}, second: []float64{1, 2, 3}},
+		{name: "constant", first: []float64{5, 5, 5}, second: []float64{1, 2, 3}},
+	}
+	for _, tt := range tests {
+		got, ok := Correlation(tt.first, tt.second)
+		if ok != tt.wantOK || math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s: Correlation() = %g, %v, want %g, %v", tt.name, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestImportAndLoad(t *testing.T) {
+	dir := t.TempDir()
+	empty := filepath.Join(dir, "empty.json")
+	if err := Save(empty, &Series{}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if _, err := Load(empty); err == nil {
+		t.Error("Load() of a series without points succeeded, want an error")
+	}
+
+	path := filepath.Join(dir, "logs", File)
+	var source Source
+	if source.Series(path) != nil {
+		t.Error("Series() before an import is not nil")
+	}
+
+	imported, err := Import(filepath.Join("testdata", "rps.csv"), "", path)
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	loaded := source.Series(path)
+	if loaded == nil || len(loaded.Points) != len(imported.Points) || !filepath.IsAbs(loaded.Source) {
+		t.Errorf("Series() after Import() = %+v, want %+v", loaded, imported)
+	}
+	if again := source.Series(path); again != loaded {
+		t.Error("Series() read an unchanged file again")
+	}
+}
@@ -0,0 +1,30 @@
+package overlay
+
+import (
+	"sync"
+	"time"
+)
+
+// Series is an external metric imported from CSV, e.g. an application's requests per second
+type Series struct {
+	Name     string    `json:"name"`     // Header of the value column, used as the graph label
+	Source   string    `json:"source"`   // CSV file it was imported from
+	Imported time.Time `json:"imported"` // When it was imported
+	Points   []Point   `json:"points"`   // Samples, oldest first
+}
+
+// Point is one sample of a series
+type Point struct {
+	Time  time.Time `json:"time"`  // Sample time
+	Value float64   `json:"value"` // Sample value
+}
+
+// Source hands the imported series to live displays, reading the file again only after it changes,
+// so an import made while a monitor runs shows up on its next refresh
+type Source struct {
+	mutex    sync.Mutex
+	path     string
+	modified time.Time
+	size     int64
+	series   *Series
+}
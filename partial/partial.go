@@ -0,0 +1,65 @@
+// Package partial records which sections of a monitor snapshot could not be collected, and why
+package partial
+
+import (
+	"errors"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Errors records which sections of a snapshot could not be collected
+// Keys are section names (e.g. "connections"); values are short, user-facing reasons
+type Errors map[string]string
+
+// Add records why a section failed
+func (sectionErrors Errors) Add(section string, err error) {
+	if err == nil {
+		return
+	}
+	sectionErrors[section] = Reason(err)
+}
+
+// Get returns the reason a section failed, if it did
+func (sectionErrors Errors) Get(section string) (string, bool) {
+	reason, failed := sectionErrors[section]
+	return reason, failed
+}
+
+// Sections returns the failed section names in sorted order
+func (sectionErrors Errors) Sections() []string {
+	sections := make([]string, 0, len(sectionErrors))
+	for section := range sectionErrors {
+		sections = append(sections, section)
+	}
+	sort.Strings(sections)
+	return sections
+}
+
+// Reason turns a collection error into a short reason for display
+// Permission and platform errors are recognised so they read the same on every OS
+func Reason(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	message := strings.ToLower(err.Error())
+	switch {
+	case errors.Is(err, os.ErrPermission),
+		strings.Contains(message, "permission denied"),
+		strings.Contains(message, "access is denied"),
+		strings.Contains(message, "operation not permitted"):
+		return "permission denied"
+	case strings.Contains(message, "not implemented"),
+		strings.Contains(message, "not supported"):
+		return "not supported on this platform"
+	case errors.Is(err, os.ErrNotExist):
+		return "not available"
+	}
+	return err.Error()
+}
+
+// Unavailable returns the marker shown in place of a section that could not be collected
+func Unavailable(reason string) string {
+	return "unavailable (" + reason + ")"
+}
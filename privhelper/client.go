@@ -0,0 +1,262 @@
+// Package privhelper is the protocol, client and server of the optional privileged helper, which answers
+// the few queries that need root (SMART data, ICMP echo and other users' process details)
+package privhelper
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultClientTimeout limits one request; smartctl on a slow or spun-down drive can take several seconds
+const defaultClientTimeout = 20 * time.Second
+
+// startTimeout is how long a launched helper has to start answering
+const startTimeout = 3 * time.Second
+
+// processCacheTTL is how long process details are reused; collectors ask for them part by part within one refresh
+const processCacheTTL = time.Second
+
+// maxCachedProcesses is how many processes are cached before expired entries are dropped
+const maxCachedProcesses = 1024
+
+// startedHelper is a helper launched for this session; it exits when its standard input closes
+type startedHelper struct {
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+	dir   string // Private directory holding the socket
+}
+
+// active is the helper the monitors use, nil when none is connected
+var active struct {
+	client *Client
+	mutex  sync.Mutex
+}
+
+// SocketPath returns where a helper service listens: $SIMPLE_MONITOR_HELPER, or DefaultSocket
+func SocketPath() string {
+	if path := os.Getenv(SocketEnv); path != "" {
+		return path
+	}
+	return DefaultSocket
+}
+
+// Connect finds a privileged helper: a service listening at SocketPath, or else the helper binary
+// installed next to the program (setuid or with capabilities), started for this session
+// A helper that turns out to have no privileges would add nothing, so it is closed and reported as an error
+func Connect() (*Client, error) {
+	client, err := Dial(SocketPath())
+	if err != nil {
+		binary, findErr := findBinary()
+		if findErr != nil {
+			return nil, fmt.Errorf("no helper service at %s and %w", SocketPath(), findErr)
+		}
+		if client, err = Start(binary); err != nil {
+			return nil, err
+		}
+	}
+
+	if !client.info.Privileged {
+		client.Close()
+		return nil, fmt.Errorf("helper runs without privileges; install it setuid root, with capabilities or as a root service")
+	}
+	return client, nil
+}
+
+// findBinary returns the helper executable next to the running program
+func findBinary() (string, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate the program: %w", err)
+	}
+	// Release builds carry a platform suffix (simple-monitor-linux-amd64), which the helper shares
+	name := BinaryName
+	if suffix, ok := strings.CutPrefix(filepath.Base(executable), "simple-monitor"); ok {
+		name += suffix
+	}
+	binary := filepath.Join(filepath.Dir(executable), name)
+	if _, err := os.Stat(binary); err != nil {
+		return "", fmt.Errorf("no %s next to the program", name)
+	}
+	return binary, nil
+}
+
+// Start launches the helper binary with its socket in a private directory and waits until it answers
+// The helper watches its standard input and exits when this program does
+func Start(binary string) (*Client, error) {
+	dir, err := os.MkdirTemp("", "simple-monitor-helper-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create helper directory: %w", err)
+	}
+	socket := filepath.Join(dir, "helper.sock")
+
+	cmd := exec.Command(binary, "-socket", socket, "-stdin")
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("failed to start helper: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("failed to start helper: %w", err)
+	}
+	started := &startedHelper{cmd: cmd, stdin: stdin, dir: dir}
+
+	deadline := time.Now().Add(startTimeout)
+	for {
+		client, err := Dial(socket)
+		if err == nil {
+			client.started = started
+			return client, nil
+		}
+		if time.Now().After(deadline) {
+			started.stop()
+			return nil, fmt.Errorf("helper did not start answering: %w", err)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// stop ends a launched helper and removes its socket directory
+func (started *startedHelper) stop() {
+	started.stdin.Close()
+	done := make(chan struct{})
+	go func() {
+		started.cmd.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		started.cmd.Process.Kill()
+	}
+	os.RemoveAll(started.dir)
+}
+
+// Dial connects to the helper listening at path and checks it speaks this protocol version
+func Dial(path string) (*Client, error) {
+	client := &Client{Path: path, Timeout: defaultClientTimeout, cache: make(map[int32]cachedDetails)}
+	response, err := client.call(Request{Op: OpHello})
+	if err != nil {
+		return nil, err
+	}
+	if response.Hello == nil || response.Hello.Version != ProtocolVersion {
+		version := 0
+		if response.Hello != nil {
+			version = response.Hello.Version
+		}
+		return nil, fmt.Errorf("helper at %s speaks protocol %d, expected %d", path, version, ProtocolVersion)
+	}
+	client.info = *response.Hello
+	return client, nil
+}
+
+// Info returns what the helper reported when the client connected
+func (client *Client) Info() Hello {
+	return client.info
+}
+
+// Close stops the helper if this client launched it; a helper service keeps running
+func (client *Client) Close() error {
+	if client.started != nil {
+		client.started.stop()
+		client.started = nil
+	}
+	return nil
+}
+
+// call sends one request and reads the response
+func (client *Client) call(request Request) (Response, error) {
+	var response Response
+	conn, err := net.DialTimeout("unix", client.Path, client.Timeout)
+	if err != nil {
+		return response, fmt.Errorf("failed to reach helper at %s: %w", client.Path, err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(client.Timeout + request.Timeout))
+
+	if err := json.NewEncoder(conn).Encode(request); err != nil {
+		return response, fmt.Errorf("failed to send %s request to helper: %w", request.Op, err)
+	}
+	if err := json.NewDecoder(conn).Decode(&response); err != nil {
+		return response, fmt.Errorf("failed to read helper response: %w", err)
+	}
+	if response.Error != "" {
+		return response, errors.New("helper: " + response.Error)
+	}
+	return response, nil
+}
+
+// Smartctl runs smartctl in the helper with one of the argument lists it allows
+// Like exec's Output, a non-zero exit status returns the output together with an error
+func (client *Client) Smartctl(args ...string) ([]byte, error) {
+	response, err := client.call(Request{Op: OpSmartctl, Args: args})
+	if err != nil {
+		return nil, err
+	}
+	if response.ExitCode != 0 {
+		return response.Output, fmt.Errorf("smartctl exited with status %d", response.ExitCode)
+	}
+	return response.Output, nil
+}
+
+// Ping sends one ICMP echo request from the helper and returns the round-trip time in milliseconds
+// The error is only set when the ping could not be sent; an unanswered ping returns false
+func (client *Client) Ping(host string, timeout time.Duration) (float64, bool, error) {
+	response, err := client.call(Request{Op: OpPing, Host: host, Timeout: timeout})
+	if err != nil {
+		return 0, false, err
+	}
+	return response.RTT, response.Reachable, nil
+}
+
+// Process returns the details of a process the monitor cannot read itself, reusing them for processCacheTTL
+func (client *Client) Process(pid int32) (*ProcessDetails, error) {
+	client.mutex.Lock()
+	cached, ok := client.cache[pid]
+	client.mutex.Unlock()
+	if ok && time.Since(cached.read) < processCacheTTL {
+		return cached.details, cached.err
+	}
+
+	response, err := client.call(Request{Op: OpProcess, PID: pid})
+	now := time.Now()
+
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	if len(client.cache) >= maxCachedProcesses {
+		for cachedPID, entry := range client.cache {
+			if now.Sub(entry.read) >= processCacheTTL {
+				delete(client.cache, cachedPID)
+			}
+		}
+	}
+	client.cache[pid] = cachedDetails{details: response.Process, err: err, read: now}
+	return response.Process, err
+}
+
+// Use makes client the helper the monitors query; nil disconnects
+// A helper launched by the previous client is stopped
+func Use(client *Client) {
+	active.mutex.Lock()
+	defer active.mutex.Unlock()
+	if active.client != nil && active.client != client {
+		active.client.Close()
+	}
+	active.client = client
+}
+
+// Active returns the helper the monitors query, or nil when none is connected
+func Active() *Client {
+	active.mutex.Lock()
+	defer active.mutex.Unlock()
+	return active.client
+}
@@ -0,0 +1,28 @@
+//go:build linux
+
+package privhelper
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// isPrivileged reports whether the helper runs as root or holds any effective capability
+// A helper given only capabilities (setcap) keeps its user's UID, so the capability set is checked too
+func isPrivileged() bool {
+	if os.Geteuid() == 0 {
+		return true
+	}
+	content, err := os.ReadFile("/proc/self/status")
+	if err != nil {
+		return false
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		if value, found := strings.CutPrefix(line, "CapEff:"); found {
+			capabilities, err := strconv.ParseUint(strings.TrimSpace(value), 16, 64)
+			return err == nil && capabilities != 0
+		}
+	}
+	return false
+}
@@ -0,0 +1,11 @@
+//go:build !linux
+
+package privhelper
+
+import "os"
+
+// isPrivileged reports whether the helper runs as root
+// Other systems have no per-file capabilities, so only setuid root or a root service counts
+func isPrivileged() bool {
+	return os.Geteuid() == 0
+}
@@ -0,0 +1,266 @@
+package privhelper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// maxRequestSize caps one request so a client cannot make the helper buffer without limit
+const maxRequestSize = 64 * 1024
+
+// defaultServerTimeout limits one connection when the server sets none; long enough for smartctl -a on a slow drive
+const defaultServerTimeout = 30 * time.Second
+
+// maxPingTimeout caps how long a ping request may wait for its reply
+const maxPingTimeout = 5 * time.Second
+
+// smartctlCommands are the argument lists the helper runs smartctl with
+// DEVICE stands for a device path and TYPE for a smartctl device type, both checked before use
+var smartctlCommands = [][]string{
+	{"--scan", "-j"},
+	{"-j", "-a", "DEVICE"},
+	{"-j", "-i", "-d", "TYPE", "DEVICE"},
+	{"-j", "-c", "-l", "selftest", "DEVICE"},
+	{"-j", "-t", "short", "DEVICE"},
+	{"-j", "-t", "long", "DEVICE"},
+}
+
+// devicePattern accepts device paths such as /dev/sda, /dev/nvme0 or /dev/disk/by-id/ata-X
+var devicePattern = regexp.MustCompile(`^/dev/[A-Za-z0-9_][A-Za-z0-9_/.:-]*$`)
+
+// userWritableDevDirs are directories under /dev where any user can create files, which smartctl running as
+// root must not be pointed at
+var userWritableDevDirs = []string{"/dev/shm/", "/dev/mqueue/"}
+
+// typePattern accepts smartctl device types such as nvme, sat or megaraid,0
+var typePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9,+-]*$`)
+
+// hostPattern accepts IP addresses and host names
+var hostPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9.:-]*$`)
+
+// Serve answers connections until the listener is closed
+func (server *Server) Serve(listener net.Listener) error {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		go server.serveConn(conn)
+	}
+}
+
+// serveConn reads one request from a connection and writes the response
+func (server *Server) serveConn(conn net.Conn) {
+	defer conn.Close()
+	timeout := server.Timeout
+	if timeout <= 0 {
+		timeout = defaultServerTimeout
+	}
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	var request Request
+	if err := json.NewDecoder(io.LimitReader(conn, maxRequestSize)).Decode(&request); err != nil {
+		json.NewEncoder(conn).Encode(Response{Error: fmt.Sprintf("invalid request: %v", err)})
+		return
+	}
+	json.NewEncoder(conn).Encode(server.Handle(request, timeout))
+}
+
+// Handle answers one request; anything outside the fixed operations is refused
+func (server *Server) Handle(request Request, timeout time.Duration) Response {
+	switch request.Op {
+	case OpHello:
+		return Response{Hello: server.hello()}
+	case OpSmartctl:
+		return server.smartctl(request.Args, timeout)
+	case OpPing:
+		return ping(request.Host, request.Timeout)
+	case OpProcess:
+		return readProcess(request.PID)
+	default:
+		return Response{Error: fmt.Sprintf("unknown operation %q", request.Op)}
+	}
+}
+
+// hello describes this helper
+func (server *Server) hello() *Hello {
+	return &Hello{
+		Version:       ProtocolVersion,
+		PID:           os.Getpid(),
+		Privileged:    isPrivileged(),
+		AllowSelfTest: server.AllowSelfTest,
+		Operations:    []string{OpHello, OpSmartctl, OpPing, OpProcess},
+	}
+}
+
+// smartctl runs smartctl when the arguments match one of smartctlCommands
+func (server *Server) smartctl(args []string, timeout time.Duration) Response {
+	if !allowedSmartctl(args) {
+		return Response{Error: fmt.Sprintf("smartctl arguments not allowed: %s", strings.Join(args, " "))}
+	}
+	if len(args) > 1 && args[1] == "-t" && !server.AllowSelfTest {
+		return Response{Error: "self-tests are disabled in the helper; start it with -allow-self-test"}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	output, err := exec.CommandContext(ctx, "smartctl", args...).Output()
+
+	// smartctl exits non-zero for drive problems too, so the output is passed on with the exit code
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return Response{Output: output, ExitCode: exitErr.ExitCode()}
+	}
+	if err != nil {
+		return Response{Error: fmt.Sprintf("failed to run smartctl: %v", err)}
+	}
+	return Response{Output: output}
+}
+
+// allowedSmartctl reports whether args match one of smartctlCommands
+func allowedSmartctl(args []string) bool {
+	for _, command := range smartctlCommands {
+		if len(command) != len(args) {
+			continue
+		}
+		matches := true
+		for i, expected := range command {
+			switch expected {
+			case "DEVICE":
+				matches = matches && allowedDevice(args[i])
+			case "TYPE":
+				matches = matches && typePattern.MatchString(args[i])
+			default:
+				matches = matches && args[i] == expected
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
+
+// allowedDevice reports whether path is a device path smartctl may open as root
+func allowedDevice(path string) bool {
+	if !devicePattern.MatchString(path) || strings.Contains(path, "/.") || strings.Contains(path, "//") {
+		return false
+	}
+	for _, dir := range userWritableDevDirs {
+		if strings.HasPrefix(path, dir) {
+			return false
+		}
+	}
+	return true
+}
+
+// readProcess reads the details of a process that need elevated access for other users' processes
+func readProcess(pid int32) Response {
+	if pid <= 0 {
+		return Response{Error: fmt.Sprintf("invalid PID %d", pid)}
+	}
+	p, err := process.NewProcess(pid)
+	if err != nil {
+		return Response{Error: fmt.Sprintf("failed to open process %d: %v", pid, err)}
+	}
+
+	details := &ProcessDetails{Errors: make(map[string]string)}
+	if details.Exe, err = p.Exe(); err != nil {
+		details.Errors["exe"] = err.Error()
+	}
+	if details.Cwd, err = p.Cwd(); err != nil {
+		details.Errors["cwd"] = err.Error()
+	}
+	if details.NumFDs, err = p.NumFDs(); err != nil {
+		details.Errors["fds"] = err.Error()
+	}
+	if details.IO, err = p.IOCounters(); err != nil {
+		details.Errors["io"] = err.Error()
+	}
+	if details.OpenFiles, err = p.OpenFiles(); err != nil {
+		details.Errors["open_files"] = err.Error()
+	}
+	return Response{Process: details}
+}
+
+// ping sends one ICMP echo request and waits for the reply
+// Raw ICMP sockets need root or CAP_NET_RAW, which is why this runs in the helper
+func ping(host string, timeout time.Duration) Response {
+	if len(host) > 253 || !hostPattern.MatchString(host) {
+		return Response{Error: fmt.Sprintf("invalid host %q", host)}
+	}
+	if timeout <= 0 || timeout > maxPingTimeout {
+		timeout = maxPingTimeout
+	}
+
+	address, err := net.ResolveIPAddr("ip4", host)
+	if err != nil {
+		return Response{Error: fmt.Sprintf("failed to resolve %s: %v", host, err)}
+	}
+	conn, err := net.ListenPacket("ip4:icmp", "0.0.0.0")
+	if err != nil {
+		return Response{Error: fmt.Sprintf("failed to open ICMP socket: %v", err)}
+	}
+	defer conn.Close()
+
+	id := os.Getpid() & 0xffff
+	sequence := int(time.Now().UnixNano() & 0xffff)
+	request := echoRequest(id, sequence)
+
+	start := time.Now()
+	conn.SetDeadline(start.Add(timeout))
+	if _, err := conn.WriteTo(request, address); err != nil {
+		return Response{Error: fmt.Sprintf("failed to send echo request to %s: %v", host, err)}
+	}
+
+	// Other processes' echo traffic arrives on the same raw socket, so wait for the matching reply
+	reply := make([]byte, 1500)
+	for {
+		n, peer, err := conn.ReadFrom(reply)
+		if err != nil {
+			return Response{} // Timed out: unreachable
+		}
+		from, ok := peer.(*net.IPAddr)
+		if n < 8 || reply[0] != 0 || !ok || !from.IP.Equal(address.IP) {
+			continue
+		}
+		if int(reply[4])<<8|int(reply[5]) != id || int(reply[6])<<8|int(reply[7]) != sequence {
+			continue
+		}
+		return Response{Reachable: true, RTT: float64(time.Since(start).Microseconds()) / 1000}
+	}
+}
+
+// echoRequest builds an ICMP echo request with the given identifier and sequence number
+func echoRequest(id, sequence int) []byte {
+	message := []byte{8, 0, 0, 0, byte(id >> 8), byte(id), byte(sequence >> 8), byte(sequence)}
+	message = append(message, "simple-monitor"...)
+
+	var sum uint32
+	for i := 0; i+1 < len(message); i += 2 {
+		sum += uint32(message[i])<<8 | uint32(message[i+1])
+	}
+	if len(message)%2 == 1 {
+		sum += uint32(message[len(message)-1]) << 8
+	}
+	for sum>>16 != 0 {
+		sum = sum&0xffff + sum>>16
+	}
+	checksum := ^uint16(sum)
+	message[2], message[3] = byte(checksum>>8), byte(checksum)
+	return message
+}
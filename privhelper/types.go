@@ -0,0 +1,93 @@
+package privhelper
+
+import (
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// DefaultSocket is where a helper installed as a service listens
+const DefaultSocket = "/run/simple-monitor/helper.sock"
+
+// SocketEnv names the environment variable that overrides the socket location
+const SocketEnv = "SIMPLE_MONITOR_HELPER"
+
+// BinaryName is the helper executable started from next to the main program when no service is listening
+const BinaryName = "simple-monitor-helper"
+
+// ProtocolVersion is bumped when requests or responses change incompatibly
+const ProtocolVersion = 1
+
+// Operations a helper answers; nothing else is accepted
+const (
+	OpHello    = "hello"    // Report the helper's version, privileges and what it allows
+	OpSmartctl = "smartctl" // Run smartctl with one of the allowed argument lists
+	OpPing     = "ping"     // Send one ICMP echo request over a raw socket
+	OpProcess  = "process"  // Read the details of a process owned by another user
+)
+
+// Request is one query sent to the helper, one JSON object per line
+type Request struct {
+	Op      string        `json:"op"`                // One of the Op constants
+	Args    []string      `json:"args,omitempty"`    // smartctl arguments
+	Host    string        `json:"host,omitempty"`    // Ping target (address or host name)
+	Timeout time.Duration `json:"timeout,omitempty"` // How long to wait for the echo reply
+	PID     int32         `json:"pid,omitempty"`     // Process to read
+}
+
+// Response is the helper's answer to one request
+type Response struct {
+	Error     string          `json:"error,omitempty"`     // Why the request was refused or failed
+	Output    []byte          `json:"output,omitempty"`    // smartctl standard output
+	ExitCode  int             `json:"exit_code"`           // smartctl exit code (it reports drive problems in it too)
+	RTT       float64         `json:"rtt,omitempty"`       // Ping round-trip time in milliseconds
+	Reachable bool            `json:"reachable,omitempty"` // Whether the ping was answered
+	Process   *ProcessDetails `json:"process,omitempty"`   // Process details
+	Hello     *Hello          `json:"hello,omitempty"`     // Helper description
+}
+
+// Hello describes a running helper
+type Hello struct {
+	Version       int      `json:"version"`         // ProtocolVersion of the helper
+	PID           int      `json:"pid"`             // Helper process ID
+	Privileged    bool     `json:"privileged"`      // Whether it runs as root or holds capabilities
+	AllowSelfTest bool     `json:"allow_self_test"` // Whether it starts SMART self-tests (they change drive state, so they are opt-in)
+	Operations    []string `json:"operations"`      // Operations it answers
+}
+
+// ProcessDetails are the parts of a process that need elevated access when it belongs to another user
+// Each part is read independently; Errors holds the reason for those that could not be read, keyed by part
+type ProcessDetails struct {
+	Exe       string                  `json:"exe"`
+	Cwd       string                  `json:"cwd"`
+	NumFDs    int32                   `json:"num_fds"`
+	IO        *process.IOCountersStat `json:"io,omitempty"`
+	OpenFiles []process.OpenFilesStat `json:"open_files,omitempty"`
+	Errors    map[string]string       `json:"errors,omitempty"` // exe, cwd, fds, io, open_files
+}
+
+// Client queries a helper over its socket, opening one connection per request
+type Client struct {
+	Path    string        // Socket path
+	Timeout time.Duration // Limit for one request, on top of the ping timeout
+
+	info    Hello
+	started *startedHelper // Helper this client launched, stopped by Close
+
+	cache map[int32]cachedDetails // Process details are asked for part by part, so they are read once per refresh
+	mutex sync.Mutex
+}
+
+// cachedDetails is one process's details with when they were read
+type cachedDetails struct {
+	details *ProcessDetails
+	err     error
+	read    time.Time
+}
+
+// Server answers requests from unprivileged clients
+type Server struct {
+	AllowSelfTest bool          // Whether smartctl -t (start a self-test) is allowed
+	Timeout       time.Duration // Limit for one connection, including the command it runs
+}
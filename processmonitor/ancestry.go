@@ -0,0 +1,71 @@
+package processmonitor
+
+import "fmt"
+
+// maxAncestryDepth stops a trace whose parent links loop (PID reuse between reads)
+const maxAncestryDepth = 1024
+
+// TraceAncestry returns the parent chain of a process, the process itself first and init/systemd (or the
+// top visible ancestor) last
+// The chain ends early when a parent exits mid-trace or lives outside this PID namespace; the last entry's
+// ParentPID is then not 0
+func (collector *ProcessMonitorCollector) TraceAncestry(pid int32) ([]AncestorInfo, error) {
+	lookup := collector.readAncestor
+	if collector.simulator != nil {
+		simulated := make(map[int32]ProcessInfo)
+		for _, processInfo := range collector.simulatedProcessInfos() {
+			simulated[processInfo.PID] = processInfo
+		}
+		lookup = func(pid int32) (AncestorInfo, error) {
+			processInfo, ok := simulated[pid]
+			if !ok {
+				return AncestorInfo{}, fmt.Errorf("process %d not found", pid)
+			}
+			return AncestorInfo{
+				PID:         processInfo.PID,
+				ParentPID:   processInfo.ParentPID,
+				Name:        processInfo.Name,
+				User:        processInfo.User,
+				CreateTime:  processInfo.CreateTime,
+				CommandLine: processInfo.CommandLine,
+			}, nil
+		}
+	}
+
+	target, err := lookup(pid)
+	if err != nil {
+		return nil, err
+	}
+
+	chain := []AncestorInfo{target}
+	visited := map[int32]bool{pid: true}
+	for current := target; current.ParentPID > 0 && len(chain) < maxAncestryDepth; {
+		if visited[current.ParentPID] {
+			break
+		}
+		parent, err := lookup(current.ParentPID)
+		if err != nil {
+			break
+		}
+		visited[parent.PID] = true
+		chain = append(chain, parent)
+		current = parent
+	}
+	return chain, nil
+}
+
+// readAncestor reads what a trace shows of one live process; fields that cannot be read stay empty
+func (collector *ProcessMonitorCollector) readAncestor(pid int32) (AncestorInfo, error) {
+	p, err := collector.system.Proc.NewProcess(pid)
+	if err != nil {
+		return AncestorInfo{}, fmt.Errorf("failed to open process %d: %w", pid, err)
+	}
+
+	ancestor := AncestorInfo{PID: pid}
+	ancestor.ParentPID, _ = p.Ppid()
+	ancestor.Name, _ = p.Name()
+	ancestor.User, _ = p.Username()
+	ancestor.CreateTime, _ = p.CreateTime()
+	ancestor.CommandLine, _ = p.Cmdline()
+	return ancestor, nil
+}
@@ -0,0 +1,36 @@
+//go:build linux
+
+package processmonitor
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// readCgroup returns the control group path of a process from /proc/<pid>/cgroup
+// The unified (v2) hierarchy is preferred; on v1 systems the systemd hierarchy names the service
+func readCgroup(pid int32) (string, error) {
+	content, err := os.ReadFile(fmt.Sprintf("/proc/%d/cgroup", pid))
+	if err != nil {
+		return "", err
+	}
+
+	var fallback string
+	for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+		// Lines look like "0::/system.slice/nginx.service" or "1:name=systemd:/user.slice/..."
+		fields := strings.SplitN(line, ":", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		switch {
+		case fields[0] == "0" && fields[1] == "":
+			return fields[2], nil
+		case fields[1] == "name=systemd":
+			fallback = fields[2]
+		case fallback == "":
+			fallback = fields[2]
+		}
+	}
+	return fallback, nil
+}
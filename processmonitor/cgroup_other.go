@@ -0,0 +1,11 @@
+//go:build !linux
+
+package processmonitor
+
+import "errors"
+
+// readCgroup returns the control group path of a process
+// Control groups are a Linux feature, so there is nothing to read on other platforms
+func readCgroup(pid int32) (string, error) {
+	return "", errors.New("control groups are only available on Linux")
+}
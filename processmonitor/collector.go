@@ -1,32 +1,106 @@
 package processmonitor
 
 import (
+	"bytes"
+	"encoding/csv"
 	"fmt"
+	"github.com/ahmadreza-log/simple-monitor/alert"
+	"github.com/ahmadreza-log/simple-monitor/eventmonitor"
+	"github.com/ahmadreza-log/simple-monitor/historystore"
+	"github.com/ahmadreza-log/simple-monitor/partial"
+	"github.com/ahmadreza-log/simple-monitor/procid"
+	"github.com/ahmadreza-log/simple-monitor/provider"
+	"github.com/ahmadreza-log/simple-monitor/simulate"
+	"os/exec"
+	"runtime"
 	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
-
-	"github.com/shirou/gopsutil/v3/process"
 )
 
+// churnProcess identifies a live process instance between refreshes for churn tracking
+type churnProcess struct {
+	key         string
+	name        string
+	commandLine string
+	createTime  int64
+}
+
+// contextSwitchSample is the context switch counter of a process at one refresh, for rate calculation
+type contextSwitchSample struct {
+	total uint64
+	time  time.Time
+}
+
+// churnEvent records a single start or exit of a process instance
+type churnEvent struct {
+	time        time.Time
+	pid         int32
+	parentPID   int32
+	parentName  string
+	name        string
+	commandLine string
+	exited      bool
+}
+
 // ProcessMonitorCollector handles the collection of process monitoring data
 // This struct provides methods to gather real-time process metrics and information
 type ProcessMonitorCollector struct {
-	// Configuration
+	// Configuration: config is what the collection in progress reads, adopted from latest as it starts
+	// latest is replaced on every change and never modified, so it can be handed out without copying
+	// collectMutex is held for a whole collection, so collections started by the live loop, subscribers and
+	// the attach server run one at a time and never see config or the state they carry change under them
 	config        *ProcessMonitorConfig
+	latest        *ProcessMonitorConfig
+	configMutex   sync.Mutex
+	collectMutex  sync.Mutex
 	lastTimestamp time.Time
 
-	// Process tracking
-	processCache    map[int32]*ProcessInfo
-	lastProcessTime map[int32]time.Time
-
 	// History tracking
 	history *ProcessUsageHistory
+
+	// Downsampled long-term history (raw, 1-minute and 5-minute tiers)
+	longHistory *historystore.Store
+
+	// Idle mode (expensive process scans are paused)
+	idle bool
+
+	// Log correlation cache, keyed by process instance
+	logCache     map[string]ProcessLogInfo
+	logCacheTime map[string]time.Time
+
+	// Process churn tracking (keyed by name and command line); kept by PID to see the PID reused
+	churnProcesses map[int32]churnProcess
+	churnEvents    map[string][]churnEvent
+
+	// Watchdog state of each watched process, keyed by configured name
+	watchdog map[string]*watchdogState
+
+	// Resource limits read from each process instance (limits rarely change, so they are not read every refresh)
+	limits map[procid.Key]limitSample
+
+	// Context switch counters of each process instance from the previous refresh
+	contextSwitches map[procid.Key]contextSwitchSample
+
+	// Windows details cache (tasklist is slow, so it is not run every refresh)
+	windowsServices    map[int32][]string
+	windowsTitles      map[int32]string
+	windowsDetailsTime time.Time
+
+	// Synthetic data source used instead of the system (nil when collecting real data)
+	simulator *simulate.Source
+
+	// System data sources (gopsutil unless replaced with fakes)
+	system provider.Providers
 }
 
 // NewProcessMonitorCollector creates a new instance of ProcessMonitorCollector
 // with default configuration values
 func NewProcessMonitorCollector() *ProcessMonitorCollector {
 	config := &ProcessMonitorConfig{
+		Enabled:             true,
 		RefreshInterval:     1 * time.Second,
 		MaxProcesses:        50,
 		MaxTreeDepth:        5,
@@ -40,23 +114,54 @@ func NewProcessMonitorCollector() *ProcessMonitorCollector {
 		ShowTopProcesses:    true,
 		ShowAlerts:          true,
 		ShowPerformance:     true,
+		ShowProcessLogs:     false,
+		ShowAttribution:     true,
+		ShowWindowsDetails:  true,
+		ShowSandbox:         false,
+		ShowRespawnLoops:    true,
 		ExportToFile:        true,
 		ExportInterval:      1 * time.Hour,
 		ExportFormat:        "json",
+		PersistHistory:      true,
+		HistorySaveInterval: 1 * time.Minute,
+		HistorySize:         historystore.DefaultBufferSize,
+		HistoryResolution:   0,
 		MinCPUUsage:         1.0,
 		MinMemoryUsage:      1.0,
 		ProcessNameFilter:   "",
-		UserFilter:          "",
+		UserFilter:          []string{},
+		SliceFilter:         []string{},
 		StatusFilter:        "",
+		MaxLogProcesses:     5,
+		MaxLogLines:         5,
+		LogLookback:         1 * time.Hour,
+		LogCheckInterval:    30 * time.Second,
+		AttributionRoots: []string{
+			"systemd", "init", "launchd", "kthreadd",
+			"wininit.exe", "services.exe", "explorer.exe", "svchost.exe",
+		},
+		RespawnThreshold:       5,
+		RespawnWindow:          1 * time.Minute,
+		LimitWarningPercent:    80,
+		Watchdog:               map[string]WatchdogRule{},
+		WatchdogRestartDelay:   30 * time.Second,
+		WindowsDetailsInterval: 10 * time.Second,
 	}
 
 	return &ProcessMonitorCollector{
 		config:          config,
+		latest:          config,
 		lastTimestamp:   time.Now(),
-		processCache:    make(map[int32]*ProcessInfo),
-		lastProcessTime: make(map[int32]time.Time),
+		logCache:        make(map[string]ProcessLogInfo),
+		logCacheTime:    make(map[string]time.Time),
+		churnEvents:     make(map[string][]churnEvent),
+		contextSwitches: make(map[procid.Key]contextSwitchSample),
+		watchdog:        make(map[string]*watchdogState),
+		limits:          make(map[procid.Key]limitSample),
+		longHistory:     historystore.NewStore(historystore.DefaultTiers()),
+		system:          provider.System(),
 		history: &ProcessUsageHistory{
-			MaxDataPoints:  100,
+			MaxDataPoints:  config.HistorySize,
 			DataPointCount: 0,
 		},
 	}
@@ -65,29 +170,36 @@ func NewProcessMonitorCollector() *ProcessMonitorCollector {
 // CollectProcessMonitorData gathers comprehensive process monitoring data
 // This is the main method that collects all available process metrics
 func (collector *ProcessMonitorCollector) CollectProcessMonitorData() (*ProcessMonitorData, error) {
+	collector.collectMutex.Lock()
+	defer collector.collectMutex.Unlock()
+	collector.adoptConfig()
 	data := &ProcessMonitorData{
 		Timestamp:       time.Now(),
 		RefreshInterval: collector.config.RefreshInterval,
 		IsMonitoring:    true,
+		SectionErrors:   make(partial.Errors),
+		UserFilter:      append([]string(nil), collector.config.UserFilter...),
+		SliceFilter:     append([]string(nil), collector.config.SliceFilter...),
 	}
 
-	// Collect all processes
-	if err := collector.collectAllProcesses(data); err != nil {
+	// Collect all processes (nothing useful can be shown without them)
+	// Other sections that fail are recorded and shown as unavailable instead of aborting the snapshot
+	if collector.simulator != nil {
+		data.Simulated = true
+		data.Uptime = collector.simulator.Uptime()
+		collector.summarizeProcesses(data, collector.simulatedProcessInfos())
+	} else if err := collector.collectAllProcesses(data); err != nil {
 		return nil, fmt.Errorf("failed to collect processes: %w", err)
 	}
 
 	// Collect process tree
 	if collector.config.ShowProcessTree {
-		if err := collector.collectProcessTree(data); err != nil {
-			return nil, fmt.Errorf("failed to collect process tree: %w", err)
-		}
+		data.SectionErrors.Add("process_tree", collector.collectProcessTree(data))
 	}
 
 	// Collect resource usage
 	if collector.config.ShowResourceUsage {
-		if err := collector.collectResourceUsage(data); err != nil {
-			return nil, fmt.Errorf("failed to collect resource usage: %w", err)
-		}
+		data.SectionErrors.Add("resource_usage", collector.collectResourceUsage(data))
 	}
 
 	// Collect top processes
@@ -100,6 +212,11 @@ func (collector *ProcessMonitorCollector) CollectProcessMonitorData() (*ProcessM
 		collector.collectProcessAlerts(data)
 	}
 
+	// Pull recent error logs for processes with alerts
+	if collector.config.ShowProcessLogs && !collector.idle && !data.Simulated && len(data.ProcessAlerts) > 0 {
+		collector.collectProcessLogs(data)
+	}
+
 	// Calculate performance metrics
 	if collector.config.ShowPerformance {
 		collector.calculatePerformanceMetrics(data)
@@ -117,24 +234,63 @@ func (collector *ProcessMonitorCollector) CollectProcessMonitorData() (*ProcessM
 // collectAllProcesses gathers information about all processes
 func (collector *ProcessMonitorCollector) collectAllProcesses(data *ProcessMonitorData) error {
 	// Get all processes
-	processes, err := process.Processes()
+	processes, err := collector.system.Proc.Processes()
 	if err != nil {
 		return fmt.Errorf("failed to get processes: %w", err)
 	}
 
-	var processInfos []ProcessInfo
-	var totalCPU, totalMemory float64
-	var totalIORead, totalIOWrite uint64
-	var totalThreads, totalOpenFiles int32
-
-	// Process each process
+	var allProcessInfos []ProcessInfo
+	handles := make(map[int32]provider.Process, len(processes))
 	for _, p := range processes {
 		// Get basic process information
 		processInfo, err := collector.getProcessInfo(p)
 		if err != nil {
 			continue // Skip processes we can't access
 		}
+		allProcessInfos = append(allProcessInfos, processInfo)
+		handles[processInfo.PID] = p
+	}
 
+	collector.summarizeProcesses(data, allProcessInfos)
+
+	// Compare usage with each process's own limits, regardless of the display filters
+	if collector.config.LimitWarningPercent > 0 {
+		collector.collectLimitWarnings(data, allProcessInfos, handles)
+	}
+
+	// Windows details are only looked up for processes that will be shown
+	if runtime.GOOS == "windows" && collector.config.ShowWindowsDetails {
+		collector.refreshWindowsDetails()
+		for i := range data.ProcessInfos {
+			data.ProcessInfos[i].Windows = collector.getWindowsDetails(data.ProcessInfos[i].PID)
+		}
+	}
+
+	// Sandbox hints are likewise only read for processes that will be shown
+	if runtime.GOOS == "linux" && collector.config.ShowSandbox {
+		host, _ := hostNamespaces()
+		for i := range data.ProcessInfos {
+			if sandbox, err := readSandbox(data.ProcessInfos[i].PID, host); err == nil {
+				sandbox.Container = containerRuntime(data.ProcessInfos[i].Slice)
+				data.ProcessInfos[i].Sandbox = sandbox
+			}
+		}
+	}
+
+	return nil
+}
+
+// summarizeProcesses filters the process list into data and adds up totals and status counts
+// Unfiltered processes are kept for CPU attribution so small children still count toward their family
+func (collector *ProcessMonitorCollector) summarizeProcesses(data *ProcessMonitorData, allProcessInfos []ProcessInfo) {
+	var processInfos []ProcessInfo
+	var totalCPU, totalMemory float64
+	var totalIORead, totalIOWrite uint64
+	var totalThreads, totalOpenFiles int32
+
+	collector.calculateContextSwitchRates(allProcessInfos, data.Timestamp)
+
+	for _, processInfo := range allProcessInfos {
 		// Apply filters
 		if !collector.passesFilters(processInfo) {
 			continue
@@ -172,15 +328,316 @@ func (collector *ProcessMonitorCollector) collectAllProcesses(data *ProcessMonit
 	data.TotalThreads = totalThreads
 	data.TotalOpenFiles = totalOpenFiles
 
-	return nil
+	// Attribute CPU to process families
+	if collector.config.ShowAttribution {
+		collector.collectCPUAttribution(data, allProcessInfos)
+	}
+
+	// Rank by open files and context switches
+	if collector.config.ShowTopProcesses {
+		collector.collectActivityRankings(data, allProcessInfos)
+	}
+
+	// Track starts and exits to catch respawn loops
+	if collector.config.ShowRespawnLoops {
+		collector.collectRespawnLoops(data, allProcessInfos)
+	}
+
+	// Check that watched processes are running, regardless of the display filters
+	collector.collectWatchdog(data, allProcessInfos)
+}
+
+// calculateContextSwitchRates sets each process's context switches per second since the previous refresh
+// Processes seen for the first time (or whose PID was reused) get no rate until the next refresh
+func (collector *ProcessMonitorCollector) calculateContextSwitchRates(processes []ProcessInfo, now time.Time) {
+	current := make(map[procid.Key]contextSwitchSample, len(processes))
+	for i := range processes {
+		proc := &processes[i]
+		key := procid.New(proc.PID, proc.CreateTime)
+		sample := contextSwitchSample{total: proc.ContextSwitches, time: now}
+		current[key] = sample
+
+		previous, seen := collector.contextSwitches[key]
+		if !seen || sample.total < previous.total {
+			continue
+		}
+		if elapsed := now.Sub(previous.time).Seconds(); elapsed > 0 {
+			proc.ContextSwitchRate = float64(sample.total-previous.total) / elapsed
+		}
+	}
+	collector.contextSwitches = current
+}
+
+// collectActivityRankings ranks processes by open file descriptors and by context switch rate
+// Only the name, user, slice and status filters apply: the CPU and memory minimums would hide
+// exactly the quiet-looking processes that leak descriptors or thrash the scheduler
+func (collector *ProcessMonitorCollector) collectActivityRankings(data *ProcessMonitorData, processes []ProcessInfo) {
+	var candidates []ProcessInfo
+	for _, proc := range processes {
+		if collector.passesSelection(proc) {
+			candidates = append(candidates, proc)
+		}
+	}
+
+	// Sort by open files
+	openFilesProcesses := make([]ProcessInfo, 0, len(candidates))
+	for _, proc := range candidates {
+		if proc.OpenFiles > 0 {
+			openFilesProcesses = append(openFilesProcesses, proc)
+		}
+	}
+	sort.Slice(openFilesProcesses, func(i, j int) bool {
+		return openFilesProcesses[i].OpenFiles > openFilesProcesses[j].OpenFiles
+	})
+	if len(openFilesProcesses) > collector.config.MaxProcesses {
+		openFilesProcesses = openFilesProcesses[:collector.config.MaxProcesses]
+	}
+	data.TopOpenFilesProcesses = openFilesProcesses
+
+	// Sort by context switch rate
+	switchProcesses := make([]ProcessInfo, 0, len(candidates))
+	for _, proc := range candidates {
+		if proc.ContextSwitchRate > 0 {
+			switchProcesses = append(switchProcesses, proc)
+		}
+	}
+	sort.Slice(switchProcesses, func(i, j int) bool {
+		return switchProcesses[i].ContextSwitchRate > switchProcesses[j].ContextSwitchRate
+	})
+	if len(switchProcesses) > collector.config.MaxProcesses {
+		switchProcesses = switchProcesses[:collector.config.MaxProcesses]
+	}
+	data.TopContextSwitchProcesses = switchProcesses
+}
+
+// collectCPUAttribution sums CPU and memory over each process family (subtree)
+// A family root is the top-most ancestor below PID 1 or below a configured
+// attribution root such as a session manager, similar to systemd-cgtop
+func (collector *ProcessMonitorCollector) collectCPUAttribution(data *ProcessMonitorData, processes []ProcessInfo) {
+	byPID := make(map[int32]*ProcessInfo, len(processes))
+	for i := range processes {
+		byPID[processes[i].PID] = &processes[i]
+	}
+
+	stopNames := make(map[string]bool, len(collector.config.AttributionRoots))
+	for _, name := range collector.config.AttributionRoots {
+		stopNames[name] = true
+	}
+
+	families := make(map[int32]*ProcessFamilyInfo)
+	var stacks []ProcessStackSample
+
+	for i := range processes {
+		proc := &processes[i]
+
+		// Walk up to the family root, recording the ancestry
+		stack := []string{proc.Name}
+		root := proc
+		for depth := 0; depth < 64; depth++ {
+			parent, ok := byPID[root.ParentPID]
+			if !ok || parent.PID <= 1 || root.ParentPID == root.PID || stopNames[parent.Name] {
+				break
+			}
+			root = parent
+			stack = append([]string{root.Name}, stack...)
+		}
+
+		// Attribution roots and init are families of their own
+		family, ok := families[root.PID]
+		if !ok {
+			family = &ProcessFamilyInfo{
+				RootPID: root.PID,
+				Name:    root.Name,
+				SelfCPU: root.CPUUsage,
+			}
+			families[root.PID] = family
+		}
+
+		family.ProcessCount++
+		family.TotalCPU += proc.CPUUsage
+		family.TotalMemory += proc.MemoryUsage
+		family.TotalRSS += proc.MemoryRSS
+
+		if proc.CPUUsage > 0 {
+			stacks = append(stacks, ProcessStackSample{
+				Stack:    stack,
+				CPUUsage: proc.CPUUsage,
+			})
+		}
+	}
+
+	attribution := make([]ProcessFamilyInfo, 0, len(families))
+	for _, family := range families {
+		attribution = append(attribution, *family)
+	}
+
+	// Sort by subtree CPU, then by memory for idle families
+	sort.Slice(attribution, func(i, j int) bool {
+		if attribution[i].TotalCPU != attribution[j].TotalCPU {
+			return attribution[i].TotalCPU > attribution[j].TotalCPU
+		}
+		return attribution[i].TotalMemory > attribution[j].TotalMemory
+	})
+
+	// Limit to max processes
+	if len(attribution) > collector.config.MaxProcesses {
+		attribution = attribution[:collector.config.MaxProcesses]
+	}
+
+	data.CPUAttribution = attribution
+	data.CPUStacks = stacks
+}
+
+// collectRespawnLoops compares the process list with the previous refresh and reports
+// names/command lines that start more than RespawnThreshold times per minute.
+// Instances that live shorter than one refresh interval are never seen, so the
+// reported rate is a lower bound for very fast loops.
+func (collector *ProcessMonitorCollector) collectRespawnLoops(data *ProcessMonitorData, processes []ProcessInfo) {
+	now := time.Now()
+	window := collector.config.RespawnWindow
+
+	byPID := make(map[int32]*ProcessInfo, len(processes))
+	for i := range processes {
+		byPID[processes[i].PID] = &processes[i]
+	}
+
+	current := make(map[int32]churnProcess, len(processes))
+	for _, proc := range processes {
+		// Processes that exited while being read come back without a name
+		if proc.Name == "" {
+			continue
+		}
+
+		entry := churnProcess{
+			key:         proc.Name + "\x00" + proc.CommandLine,
+			name:        proc.Name,
+			commandLine: proc.CommandLine,
+			createTime:  proc.CreateTime,
+		}
+		current[proc.PID] = entry
+
+		previous, seen := collector.churnProcesses[proc.PID]
+		if seen && previous.createTime == entry.createTime {
+			continue
+		}
+
+		// A reused PID means the previous instance exited
+		if seen {
+			collector.recordChurnEvent(previous.key, churnEvent{
+				time:        now,
+				pid:         proc.PID,
+				name:        previous.name,
+				commandLine: previous.commandLine,
+				exited:      true,
+			})
+		}
+
+		started := now
+		if proc.CreateTime > 0 {
+			started = time.UnixMilli(proc.CreateTime)
+		}
+		if now.Sub(started) > window {
+			continue
+		}
+
+		event := churnEvent{
+			time:        started,
+			pid:         proc.PID,
+			parentPID:   proc.ParentPID,
+			name:        proc.Name,
+			commandLine: proc.CommandLine,
+		}
+		if parent, ok := byPID[proc.ParentPID]; ok {
+			event.parentName = parent.Name
+		}
+		collector.recordChurnEvent(entry.key, event)
+	}
+
+	// Processes missing since the last refresh have exited
+	for pid, previous := range collector.churnProcesses {
+		if _, ok := current[pid]; !ok {
+			collector.recordChurnEvent(previous.key, churnEvent{
+				time:        now,
+				pid:         pid,
+				name:        previous.name,
+				commandLine: previous.commandLine,
+				exited:      true,
+			})
+		}
+	}
+	collector.churnProcesses = current
+
+	var loops []ProcessChurnInfo
+	for key, events := range collector.churnEvents {
+		// Drop events that fell out of the window
+		var kept []churnEvent
+		for _, event := range events {
+			if now.Sub(event.time) <= window {
+				kept = append(kept, event)
+			}
+		}
+		if len(kept) == 0 {
+			delete(collector.churnEvents, key)
+			continue
+		}
+		collector.churnEvents[key] = kept
+
+		info := ProcessChurnInfo{
+			Name:        kept[0].name,
+			CommandLine: kept[0].commandLine,
+		}
+		parents := make(map[int32]int)
+		parentNames := make(map[int32]string)
+		for _, event := range kept {
+			if event.exited {
+				info.Exits++
+				continue
+			}
+			info.Starts++
+			parents[event.parentPID]++
+			parentNames[event.parentPID] = event.parentName
+			if event.time.After(info.LastStart) {
+				info.LastStart = event.time
+				info.LastPID = event.pid
+			}
+		}
+
+		info.StartsPerMinute = float64(info.Starts) / window.Minutes()
+		if info.StartsPerMinute < float64(collector.config.RespawnThreshold) {
+			continue
+		}
+
+		// Blame the parent that spawned most of the instances
+		best := 0
+		for pid, count := range parents {
+			if count > best {
+				best = count
+				info.ParentPID = pid
+				info.ParentName = parentNames[pid]
+			}
+		}
+
+		loops = append(loops, info)
+	}
+
+	sort.Slice(loops, func(i, j int) bool {
+		return loops[i].StartsPerMinute > loops[j].StartsPerMinute
+	})
+
+	data.RespawnLoops = loops
+}
+
+// recordChurnEvent appends a start or exit event to the churn history for a process key
+func (collector *ProcessMonitorCollector) recordChurnEvent(key string, event churnEvent) {
+	collector.churnEvents[key] = append(collector.churnEvents[key], event)
 }
 
 // getProcessInfo gathers detailed information about a specific process
-func (collector *ProcessMonitorCollector) getProcessInfo(p *process.Process) (ProcessInfo, error) {
+func (collector *ProcessMonitorCollector) getProcessInfo(p provider.Process) (ProcessInfo, error) {
 	var processInfo ProcessInfo
 
 	// Basic information
-	processInfo.PID = p.Pid
+	processInfo.PID = p.PID()
 
 	// Get process name
 	if name, err := p.Name(); err == nil {
@@ -197,11 +654,22 @@ func (collector *ProcessMonitorCollector) getProcessInfo(p *process.Process) (Pr
 		processInfo.User = user
 	}
 
+	// Get control group and the slice it belongs to
+	if cgroup, err := readCgroup(p.PID()); err == nil {
+		processInfo.Cgroup = cgroup
+		processInfo.Slice = cgroupSlice(cgroup)
+	}
+
 	// Get CPU usage
 	if cpu, err := p.CPUPercent(); err == nil {
 		processInfo.CPUUsage = cpu
 	}
 
+	// Get accumulated CPU time
+	if times, err := p.Times(); err == nil {
+		processInfo.CPUTime = uint64((times.User + times.System) * 1000)
+	}
+
 	// Get memory information
 	if memInfo, err := p.MemoryInfo(); err == nil {
 		processInfo.MemoryRSS = memInfo.RSS
@@ -228,10 +696,11 @@ func (collector *ProcessMonitorCollector) getProcessInfo(p *process.Process) (Pr
 		processInfo.CreateTime = createTime
 		processInfo.Uptime = time.Now().Unix() - createTime/1000
 	}
+	processInfo.InstanceID = procid.InstanceID(processInfo.PID, processInfo.CreateTime)
 
 	// Get parent PID
-	if parentPID, err := p.Parent(); err == nil {
-		processInfo.ParentPID = parentPID.Pid
+	if parentPID, err := p.Ppid(); err == nil {
+		processInfo.ParentPID = parentPID
 	}
 
 	// Get command line
@@ -274,13 +743,96 @@ func (collector *ProcessMonitorCollector) getProcessInfo(p *process.Process) (Pr
 	}
 
 	// Get children count
-	if children, err := p.Children(); err == nil {
-		processInfo.Children = int32(len(children))
+	if children, err := p.NumChildren(); err == nil {
+		processInfo.Children = int32(children)
 	}
 
 	return processInfo, nil
 }
 
+// refreshWindowsDetails re-reads hosted services and window titles from tasklist
+// Results are cached for WindowsDetailsInterval because tasklist /v can take seconds
+func (collector *ProcessMonitorCollector) refreshWindowsDetails() {
+	if collector.windowsServices != nil &&
+		time.Since(collector.windowsDetailsTime) < collector.config.WindowsDetailsInterval {
+		return
+	}
+
+	collector.windowsServices = make(map[int32][]string)
+	collector.windowsTitles = make(map[int32]string)
+	collector.windowsDetailsTime = time.Now()
+
+	// "Image Name","PID","Services"
+	if records, err := runTasklist("/svc"); err == nil {
+		for _, record := range records {
+			if len(record) < 3 || record[2] == "N/A" {
+				continue
+			}
+			pid, err := strconv.ParseInt(record[1], 10, 32)
+			if err != nil {
+				continue
+			}
+			var services []string
+			for _, service := range strings.Split(record[2], ",") {
+				if service = strings.TrimSpace(service); service != "" {
+					services = append(services, service)
+				}
+			}
+			collector.windowsServices[int32(pid)] = services
+		}
+	}
+
+	// Verbose output ends with the window title column
+	if records, err := runTasklist("/v"); err == nil {
+		for _, record := range records {
+			if len(record) < 9 {
+				continue
+			}
+			title := strings.TrimSpace(record[len(record)-1])
+			if title == "" || title == "N/A" {
+				continue
+			}
+			pid, err := strconv.ParseInt(record[1], 10, 32)
+			if err != nil {
+				continue
+			}
+			collector.windowsTitles[int32(pid)] = title
+		}
+	}
+}
+
+// getWindowsDetails combines cached tasklist data with the process token elevation
+func (collector *ProcessMonitorCollector) getWindowsDetails(pid int32) *WindowsProcessDetails {
+	details := &WindowsProcessDetails{
+		Services:    collector.windowsServices[pid],
+		WindowTitle: collector.windowsTitles[pid],
+	}
+
+	if elevated, err := processElevated(pid); err == nil {
+		details.Elevated = elevated
+		details.ElevationKnown = true
+	}
+
+	return details
+}
+
+// runTasklist runs tasklist with CSV output and returns the parsed records
+func runTasklist(mode string) ([][]string, error) {
+	output, err := exec.Command("tasklist", mode, "/fo", "csv", "/nh").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run tasklist %s: %w", mode, err)
+	}
+
+	reader := csv.NewReader(bytes.NewReader(output))
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse tasklist output: %w", err)
+	}
+
+	return records, nil
+}
+
 // collectProcessTree builds the process tree structure
 func (collector *ProcessMonitorCollector) collectProcessTree(data *ProcessMonitorData) error {
 	// Build process tree
@@ -404,6 +956,7 @@ func (collector *ProcessMonitorCollector) collectProcessAlerts(data *ProcessMoni
 		if proc.CPUUsage >= collector.config.HighCPUThreshold {
 			alerts = append(alerts, ProcessAlertInfo{
 				PID:          proc.PID,
+				InstanceID:   proc.InstanceID,
 				Name:         proc.Name,
 				AlertType:    "High CPU Usage",
 				AlertMessage: fmt.Sprintf("Process %s (PID %d) is using %.2f%% CPU", proc.Name, proc.PID, proc.CPUUsage),
@@ -418,6 +971,7 @@ func (collector *ProcessMonitorCollector) collectProcessAlerts(data *ProcessMoni
 		if proc.MemoryUsage >= collector.config.HighMemoryThreshold {
 			alerts = append(alerts, ProcessAlertInfo{
 				PID:          proc.PID,
+				InstanceID:   proc.InstanceID,
 				Name:         proc.Name,
 				AlertType:    "High Memory Usage",
 				AlertMessage: fmt.Sprintf("Process %s (PID %d) is using %.2f%% memory", proc.Name, proc.PID, proc.MemoryUsage),
@@ -433,6 +987,7 @@ func (collector *ProcessMonitorCollector) collectProcessAlerts(data *ProcessMoni
 		if totalIO >= collector.config.HighIOThreshold {
 			alerts = append(alerts, ProcessAlertInfo{
 				PID:          proc.PID,
+				InstanceID:   proc.InstanceID,
 				Name:         proc.Name,
 				AlertType:    "High I/O Usage",
 				AlertMessage: fmt.Sprintf("Process %s (PID %d) is using %d bytes I/O", proc.Name, proc.PID, totalIO),
@@ -447,6 +1002,7 @@ func (collector *ProcessMonitorCollector) collectProcessAlerts(data *ProcessMoni
 		if proc.Threads >= collector.config.HighThreadThreshold {
 			alerts = append(alerts, ProcessAlertInfo{
 				PID:          proc.PID,
+				InstanceID:   proc.InstanceID,
 				Name:         proc.Name,
 				AlertType:    "High Thread Count",
 				AlertMessage: fmt.Sprintf("Process %s (PID %d) has %d threads", proc.Name, proc.PID, proc.Threads),
@@ -458,7 +1014,126 @@ func (collector *ProcessMonitorCollector) collectProcessAlerts(data *ProcessMoni
 		}
 	}
 
-	data.ProcessAlerts = alerts
+	// Respawn loop alerts
+	for _, loop := range data.RespawnLoops {
+		alerts = append(alerts, ProcessAlertInfo{
+			PID:          loop.LastPID,
+			Name:         loop.Name,
+			AlertType:    "Respawn Loop",
+			AlertMessage: fmt.Sprintf("Process %s started %d times in %s (%.1f/min), spawned by %s (PID %d)", loop.Name, loop.Starts, collector.config.RespawnWindow, loop.StartsPerMinute, loop.ParentName, loop.ParentPID),
+			Severity:     collector.getSeverity(loop.StartsPerMinute, float64(collector.config.RespawnThreshold)),
+			Timestamp:    time.Now(),
+			Value:        loop.StartsPerMinute,
+			Threshold:    float64(collector.config.RespawnThreshold),
+		})
+	}
+
+	// Resource limit alerts
+	for _, warning := range data.LimitWarnings {
+		alerts = append(alerts, ProcessAlertInfo{
+			PID:          warning.PID,
+			InstanceID:   warning.InstanceID,
+			Name:         warning.Name,
+			AlertType:    "Resource Limit",
+			AlertMessage: fmt.Sprintf("Process %s (PID %d) is at %.0f%% of its %s limit (%s): %s", warning.Name, warning.PID, warning.Percent, warning.Limit, warning.Resource, describeLimitUsage(warning)),
+			Severity:     limitSeverity(warning.Percent),
+			Timestamp:    time.Now(),
+			Value:        warning.Percent,
+			Threshold:    collector.config.LimitWarningPercent,
+		})
+	}
+
+	// Watchdog alerts
+	for _, watched := range data.Watchdog {
+		if watched.Running {
+			continue
+		}
+		message := fmt.Sprintf("Watched process %s is not running (down since %s)", watched.Name, watched.DownSince.Format("15:04:05"))
+		if watched.RestartError != "" {
+			message += "; " + watched.RestartError
+		} else if watched.RestartCount > 0 {
+			message += fmt.Sprintf("; restart attempted %d times", watched.RestartCount)
+		}
+		alerts = append(alerts, ProcessAlertInfo{
+			Name:         watched.Name,
+			AlertType:    "Process Not Running",
+			AlertMessage: message,
+			Severity:     "Critical",
+			Timestamp:    time.Now(),
+		})
+	}
+
+	// Alerts raised during a maintenance window or snooze are dropped (and logged in log mode)
+	now := time.Now()
+	data.ProcessAlerts = alerts[:0]
+	for _, processAlert := range alerts {
+		key := fmt.Sprintf("%s:%d:%s", processAlert.AlertType, processAlert.PID, processAlert.Name)
+		if !alert.Suppress("process", key, processAlert.AlertMessage, now) {
+			data.ProcessAlerts = append(data.ProcessAlerts, processAlert)
+		}
+	}
+}
+
+// collectProcessLogs gathers recent error log lines for processes with active alerts
+// Results are cached per process instance for LogCheckInterval to avoid spawning the log reader every refresh
+func (collector *ProcessMonitorCollector) collectProcessLogs(data *ProcessMonitorData) {
+	var logs []ProcessLogInfo
+	seen := make(map[string]bool)
+
+	for _, alert := range data.ProcessAlerts {
+		// Respawn loop alerts name the latest PID only
+		key := alert.InstanceID
+		if key == "" {
+			key = strconv.Itoa(int(alert.PID))
+		}
+		if seen[key] || len(logs) >= collector.config.MaxLogProcesses {
+			continue
+		}
+		seen[key] = true
+
+		if cached, ok := collector.logCache[key]; ok &&
+			time.Since(collector.logCacheTime[key]) < collector.config.LogCheckInterval {
+			logs = append(logs, cached)
+			continue
+		}
+
+		info := ProcessLogInfo{
+			PID:  alert.PID,
+			Name: alert.Name,
+		}
+
+		entries, unit, err := eventmonitor.ReadProcessErrors(alert.PID, alert.Name,
+			collector.config.LogLookback, collector.config.MaxLogLines)
+		info.Unit = unit
+		if err != nil {
+			info.Error = err.Error()
+		}
+
+		// journalctl and wevtutil return different orders, so sort oldest first
+		sort.SliceStable(entries, func(i, j int) bool {
+			return entries[i].Timestamp.Before(entries[j].Timestamp)
+		})
+		for _, entry := range entries {
+			info.Lines = append(info.Lines, ProcessLogLine{
+				Timestamp: entry.Timestamp,
+				Message:   entry.Message,
+			})
+		}
+
+		collector.logCache[key] = info
+		collector.logCacheTime[key] = time.Now()
+		logs = append(logs, info)
+	}
+
+	// Drop cache entries for processes that no longer alert
+	for key := range collector.logCache {
+		if !seen[key] {
+			delete(collector.logCache, key)
+			delete(collector.logCacheTime, key)
+		}
+	}
+
+	data.ProcessLogs = logs
 }
 
 // calculatePerformanceMetrics calculates overall performance metrics
@@ -532,6 +1207,35 @@ func (collector *ProcessMonitorCollector) analyzeProcessStatus(data *ProcessMoni
 		data.ThreadWarning = false
 	}
 
+	// Analyze respawn loops
+	if len(data.RespawnLoops) > 0 {
+		data.RespawnWarning = true
+		if data.ProcessStatus == "" {
+			data.ProcessStatus = "Warning"
+		}
+	} else {
+		data.RespawnWarning = false
+	}
+
+	// Analyze resource limits
+	if len(data.LimitWarnings) > 0 {
+		data.LimitWarning = true
+		if data.ProcessStatus == "" {
+			data.ProcessStatus = "Warning"
+		}
+	} else {
+		data.LimitWarning = false
+	}
+
+	// Analyze watched processes; a missing one is always critical
+	data.WatchdogWarning = false
+	for _, watched := range data.Watchdog {
+		if !watched.Running {
+			data.WatchdogWarning = true
+			data.ProcessStatus = "Critical"
+		}
+	}
+
 	// Set default status if no issues
 	if data.ProcessStatus == "" {
 		data.ProcessStatus = "Normal"
@@ -542,26 +1246,40 @@ func (collector *ProcessMonitorCollector) analyzeProcessStatus(data *ProcessMoni
 func (collector *ProcessMonitorCollector) updateHistory(data *ProcessMonitorData) {
 	now := time.Now()
 
-	// Add new data point
-	collector.history.Timestamps = append(collector.history.Timestamps, now)
-	collector.history.TotalCPUUsage = append(collector.history.TotalCPUUsage, data.TotalCPUUsage)
-	collector.history.TotalMemoryUsage = append(collector.history.TotalMemoryUsage, data.TotalMemoryUsage)
-	collector.history.TotalIORead = append(collector.history.TotalIORead, float64(data.TotalIORead))
-	collector.history.TotalIOWrite = append(collector.history.TotalIOWrite, float64(data.TotalIOWrite))
-	collector.history.TotalThreads = append(collector.history.TotalThreads, float64(data.TotalThreads))
-	collector.history.ProcessCount = append(collector.history.ProcessCount, float64(data.TotalProcesses))
+	// The in-memory history keeps HistorySize samples, at most one per HistoryResolution
+	collector.history.MaxDataPoints = collector.config.HistorySize
+	if historystore.BufferSampleDue(collector.history.Timestamps, now, collector.config.HistoryResolution) {
+		// Add new data point
+		collector.history.Timestamps = append(collector.history.Timestamps, now)
+		collector.history.TotalCPUUsage = append(collector.history.TotalCPUUsage, data.TotalCPUUsage)
+		collector.history.TotalMemoryUsage = append(collector.history.TotalMemoryUsage, data.TotalMemoryUsage)
+		collector.history.TotalIORead = append(collector.history.TotalIORead, float64(data.TotalIORead))
+		collector.history.TotalIOWrite = append(collector.history.TotalIOWrite, float64(data.TotalIOWrite))
+		collector.history.TotalThreads = append(collector.history.TotalThreads, float64(data.TotalThreads))
+		collector.history.ProcessCount = append(collector.history.ProcessCount, float64(data.TotalProcesses))
+	}
 
 	// Limit history size
-	if len(collector.history.Timestamps) > collector.history.MaxDataPoints {
-		collector.history.Timestamps = collector.history.Timestamps[1:]
-		collector.history.TotalCPUUsage = collector.history.TotalCPUUsage[1:]
-		collector.history.TotalMemoryUsage = collector.history.TotalMemoryUsage[1:]
-		collector.history.TotalIORead = collector.history.TotalIORead[1:]
-		collector.history.TotalIOWrite = collector.history.TotalIOWrite[1:]
-		collector.history.TotalThreads = collector.history.TotalThreads[1:]
-		collector.history.ProcessCount = collector.history.ProcessCount[1:]
+	if excess := historystore.BufferExcess(len(collector.history.Timestamps), collector.history.MaxDataPoints); excess > 0 {
+		collector.history.Timestamps = collector.history.Timestamps[excess:]
+		collector.history.TotalCPUUsage = collector.history.TotalCPUUsage[excess:]
+		collector.history.TotalMemoryUsage = collector.history.TotalMemoryUsage[excess:]
+		collector.history.TotalIORead = collector.history.TotalIORead[excess:]
+		collector.history.TotalIOWrite = collector.history.TotalIOWrite[excess:]
+		collector.history.TotalThreads = collector.history.TotalThreads[excess:]
+		collector.history.ProcessCount = collector.history.ProcessCount[excess:]
 	}
 
+	// Feed the downsampled long-term history
+	collector.longHistory.Add(now, map[string]float64{
+		"total_cpu_usage":    data.TotalCPUUsage,
+		"total_memory_usage": data.TotalMemoryUsage,
+		"total_io_read":      float64(data.TotalIORead),
+		"total_io_write":     float64(data.TotalIOWrite),
+		"total_threads":      float64(data.TotalThreads),
+		"process_count":      float64(data.TotalProcesses),
+	})
+
 	collector.history.DataPointCount = len(collector.history.Timestamps)
 }
 
@@ -579,13 +1297,23 @@ func (collector *ProcessMonitorCollector) passesFilters(proc ProcessInfo) bool {
 		return false
 	}
 
+	return collector.passesSelection(proc)
+}
+
+// passesSelection checks the name, user, slice and status filters, ignoring the usage minimums
+func (collector *ProcessMonitorCollector) passesSelection(proc ProcessInfo) bool {
 	// Process name filter
 	if collector.config.ProcessNameFilter != "" && proc.Name != collector.config.ProcessNameFilter {
 		return false
 	}
 
 	// User filter
-	if collector.config.UserFilter != "" && proc.User != collector.config.UserFilter {
+	if len(collector.config.UserFilter) > 0 && !containsValue(collector.config.UserFilter, proc.User) {
+		return false
+	}
+
+	// Slice filter
+	if len(collector.config.SliceFilter) > 0 && !containsValue(collector.config.SliceFilter, proc.Slice) {
 		return false
 	}
 
@@ -597,6 +1325,150 @@ func (collector *ProcessMonitorCollector) passesFilters(proc ProcessInfo) bool {
 	return true
 }
 
+// containsValue returns whether value is one of values
+func containsValue(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+// CollectUserCounts lists every user that owns processes with their process counts
+// Counts ignore all filters so users hidden by the current filter can still be picked
+func (collector *ProcessMonitorCollector) CollectUserCounts() ([]FilterOption, error) {
+	collector.collectMutex.Lock()
+	defer collector.collectMutex.Unlock()
+	counts := make(map[string]int)
+	if collector.simulator != nil {
+		for _, processInfo := range collector.simulatedProcessInfos() {
+			counts[processInfo.User]++
+		}
+	} else {
+		processes, err := collector.system.Proc.Processes()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get processes: %w", err)
+		}
+		for _, p := range processes {
+			// Unreadable owners are counted under "" just as getProcessInfo leaves them
+			user, _ := p.Username()
+			counts[user]++
+		}
+	}
+	return filterOptions(counts, collector.currentConfig().UserFilter), nil
+}
+
+// CollectSliceCounts lists every slice (system.slice, user.slice, docker...) with its process count
+// Counts ignore all filters so slices hidden by the current filter can still be picked
+func (collector *ProcessMonitorCollector) CollectSliceCounts() ([]FilterOption, error) {
+	collector.collectMutex.Lock()
+	defer collector.collectMutex.Unlock()
+	counts := make(map[string]int)
+	if collector.simulator != nil {
+		for _, processInfo := range collector.simulatedProcessInfos() {
+			counts[processInfo.Slice]++
+		}
+	} else {
+		processes, err := collector.system.Proc.Processes()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get processes: %w", err)
+		}
+		for _, p := range processes {
+			cgroup, _ := readCgroup(p.PID())
+			counts[cgroupSlice(cgroup)]++
+		}
+	}
+	return filterOptions(counts, collector.currentConfig().SliceFilter), nil
+}
+
+// filterOptions turns value counts into picker options, most common first
+// Selected values stay listed even when no process has them right now
+func filterOptions(counts map[string]int, selected []string) []FilterOption {
+	for _, value := range selected {
+		if _, ok := counts[value]; !ok {
+			counts[value] = 0
+		}
+	}
+
+	var options []FilterOption
+	for value, count := range counts {
+		options = append(options, FilterOption{
+			Value:     value,
+			Processes: count,
+			Selected:  containsValue(selected, value),
+		})
+	}
+	sort.Slice(options, func(i, j int) bool {
+		if options[i].Processes != options[j].Processes {
+			return options[i].Processes > options[j].Processes
+		}
+		return options[i].Value < options[j].Value
+	})
+	return options
+}
+
+// cgroupSlice names the service group a control group belongs to
+// Container runtimes are named by runtime since their scopes sit inside system.slice;
+// otherwise the top-level slice is used (system.slice, user.slice, machine.slice)
+func cgroupSlice(cgroup string) string {
+	switch {
+	case cgroup == "":
+		return ""
+	case strings.Contains(cgroup, "kubepods"):
+		return "kubepods"
+	case strings.Contains(cgroup, "/docker-") || strings.Contains(cgroup, "/docker/"):
+		return "docker"
+	case strings.Contains(cgroup, "libpod"):
+		return "podman"
+	case strings.Contains(cgroup, "/lxc"):
+		return "lxc"
+	}
+
+	top := strings.Split(strings.TrimPrefix(cgroup, "/"), "/")[0]
+	if top == "" {
+		return "root"
+	}
+	return top
+}
+
+// containerRuntime returns the container runtime of a slice, or "" when the slice is not a container
+func containerRuntime(slice string) string {
+	switch slice {
+	case "docker", "podman", "kubepods", "lxc":
+		return slice
+	}
+	return ""
+}
+
+// sandboxFlags returns the compact confinement flags of a process, e.g. "mnt,net,pid,seccomp,ctr:docker"
+func sandboxFlags(sandbox *SandboxInfo) string {
+	if sandbox == nil {
+		return ""
+	}
+
+	var flags []string
+	if sandbox.MountNamespace {
+		flags = append(flags, "mnt")
+	}
+	if sandbox.NetworkNamespace {
+		flags = append(flags, "net")
+	}
+	if sandbox.PIDNamespace {
+		flags = append(flags, "pid")
+	}
+	switch sandbox.Seccomp {
+	case "filter":
+		flags = append(flags, "seccomp")
+	case "strict":
+		flags = append(flags, "seccomp:strict")
+	}
+	if sandbox.Container != "" {
+		flags = append(flags, "ctr:"+sandbox.Container)
+	}
+	return strings.Join(flags, ",")
+}
+
 // getSeverity determines the severity level based on value and threshold
 func (collector *ProcessMonitorCollector) getSeverity(value, threshold float64) string {
 	ratio := value / threshold
@@ -611,17 +1483,52 @@ func (collector *ProcessMonitorCollector) getSeverity(value, threshold float64)
 	}
 }
 
-// GetProcessUsageHistory returns the current process usage history
+// GetProcessUsageHistory returns a copy of the process usage history
+// A collection in progress appends to the history, so it waits for that collection to finish
 func (collector *ProcessMonitorCollector) GetProcessUsageHistory() *ProcessUsageHistory {
-	return collector.history
+	collector.collectMutex.Lock()
+	defer collector.collectMutex.Unlock()
+	return collector.history.Clone()
+}
+
+// GetLongTermHistory returns the downsampled long-term history
+func (collector *ProcessMonitorCollector) GetLongTermHistory() *historystore.Store {
+	return collector.longHistory
+}
+
+// HistoryMemoryEstimate estimates the memory an in-memory history of samples uses
+func (collector *ProcessMonitorCollector) HistoryMemoryEstimate(samples int) uint64 {
+	return historystore.BufferBytes(samples, 6)
+}
+
+// SetIdle pauses process log correlation while idle mode is active
+func (collector *ProcessMonitorCollector) SetIdle(idle bool) {
+	collector.collectMutex.Lock()
+	defer collector.collectMutex.Unlock()
+	collector.idle = idle
+}
+
+// SetSimulated switches between real system data and synthetic data
+func (collector *ProcessMonitorCollector) SetSimulated(enabled bool) {
+	collector.collectMutex.Lock()
+	defer collector.collectMutex.Unlock()
+	if !enabled {
+		collector.simulator = nil
+	} else if collector.simulator == nil {
+		collector.simulator = simulate.NewSource(simulate.DefaultSeed)
+	}
 }
 
-// GetConfig returns the current configuration
-func (collector *ProcessMonitorCollector) GetConfig() *ProcessMonitorConfig {
-	return collector.config
+// SetProviders replaces the system data sources, e.g. with provider.NewFake() to collect without OS access
+func (collector *ProcessMonitorCollector) SetProviders(providers provider.Providers) {
+	collector.collectMutex.Lock()
+	defer collector.collectMutex.Unlock()
+	collector.system = providers
 }
 
-// UpdateConfig updates the collector configuration
-func (collector *ProcessMonitorCollector) UpdateConfig(config *ProcessMonitorConfig) {
-	collector.config = config
+// IsSimulated returns whether the collector produces synthetic data
+func (collector *ProcessMonitorCollector) IsSimulated() bool {
+	collector.collectMutex.Lock()
+	defer collector.collectMutex.Unlock()
+	return collector.simulator != nil
 }
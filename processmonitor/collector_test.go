@@ -0,0 +1,129 @@
+package processmonitor
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ahmadreza-log/simple-monitor/provider"
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// fakeTable returns a process table with one busy, one large, one idle and one zombie process
+func fakeTable() []*provider.FakeProcess {
+	return []*provider.FakeProcess{
+		{Pid: 100, ParentPid: 1, ProcessName: "encoder", User: "alice", States: []string{"R"}, CPU: 95, MemoryShare: 5, Threads: 8,
+			Memory: &process.MemoryInfoStat{RSS: 400 << 20}},
+		{Pid: 101, ParentPid: 1, ProcessName: "database", User: "postgres", States: []string{"S"}, CPU: 20, MemoryShare: 40, Threads: 120,
+			Memory: &process.MemoryInfoStat{RSS: 3 << 30}},
+		{Pid: 102, ParentPid: 1, ProcessName: "sleeper", User: "alice", States: []string{"S"}, CPU: 0.1, MemoryShare: 0.1, Threads: 1},
+		{Pid: 103, ParentPid: 100, ProcessName: "defunct", User: "alice", States: []string{"Z"}, CPU: 2, MemoryShare: 2},
+	}
+}
+
+// newFakeCollector returns a collector reading fake
+func newFakeCollector(fake *provider.Fake) *ProcessMonitorCollector {
+	collector := NewProcessMonitorCollector()
+	collector.SetProviders(fake.Providers())
+	return collector
+}
+
+func TestCollectProcessMonitorDataFilters(t *testing.T) {
+	tests := []struct {
+		name  string
+		users []string
+		want  []int32
+	}{
+		{name: "usage minimums", want: []int32{100, 101, 103}},
+		{name: "user filter", users: []string{"alice"}, want: []int32{100, 103}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := provider.NewFake()
+			fake.Proc.Table = fakeTable()
+			collector := newFakeCollector(fake)
+			config := collector.GetConfig()
+			config.UserFilter = tt.users
+			collector.UpdateConfig(config)
+
+			data, err := collector.CollectProcessMonitorData()
+			if err != nil {
+				t.Fatalf("CollectProcessMonitorData() error = %v", err)
+			}
+			var pids []int32
+			for _, proc := range data.ProcessInfos {
+				pids = append(pids, proc.PID)
+			}
+			if len(pids) != len(tt.want) {
+				t.Fatalf("ProcessInfos = %v, want %v", pids, tt.want)
+			}
+			for i := range pids {
+				if pids[i] != tt.want[i] {
+					t.Fatalf("ProcessInfos = %v, want %v", pids, tt.want)
+				}
+			}
+			if data.TotalProcesses != len(tt.want) {
+				t.Errorf("TotalProcesses = %d, want %d", data.TotalProcesses, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestCollectProcessMonitorDataSummary(t *testing.T) {
+	fake := provider.NewFake()
+	fake.Proc.Table = fakeTable()
+	collector := newFakeCollector(fake)
+
+	data, err := collector.CollectProcessMonitorData()
+	if err != nil {
+		t.Fatalf("CollectProcessMonitorData() error = %v", err)
+	}
+	if data.RunningProcesses != 1 || data.SleepingProcesses != 1 || data.ZombieProcesses != 1 {
+		t.Errorf("running %d, sleeping %d, zombie %d, want 1 each",
+			data.RunningProcesses, data.SleepingProcesses, data.ZombieProcesses)
+	}
+	if len(data.TopCPUProcesses) == 0 || data.TopCPUProcesses[0].Name != "encoder" {
+		t.Errorf("TopCPUProcesses = %+v, want encoder first", data.TopCPUProcesses)
+	}
+	if len(data.TopMemoryProcesses) == 0 || data.TopMemoryProcesses[0].Name != "database" {
+		t.Errorf("TopMemoryProcesses = %+v, want database first", data.TopMemoryProcesses)
+	}
+}
+
+func TestCollectProcessMonitorDataAlerts(t *testing.T) {
+	fake := provider.NewFake()
+	fake.Proc.Table = fakeTable()
+	collector := newFakeCollector(fake)
+
+	data, err := collector.CollectProcessMonitorData()
+	if err != nil {
+		t.Fatalf("CollectProcessMonitorData() error = %v", err)
+	}
+
+	// encoder crosses the CPU threshold (80%) and database the thread threshold (100)
+	want := map[string]string{"encoder": "High CPU Usage", "database": "High Thread Count"}
+	found := make(map[string]bool)
+	for _, alert := range data.ProcessAlerts {
+		if want[alert.Name] == alert.AlertType {
+			found[alert.Name] = true
+		}
+		if alert.Name == "sleeper" || alert.Name == "defunct" {
+			t.Errorf("unexpected alert %q for %s", alert.AlertType, alert.Name)
+		}
+	}
+	for name, alertType := range want {
+		if !found[name] {
+			t.Errorf("ProcessAlerts = %+v, want %q for %s", data.ProcessAlerts, alertType, name)
+		}
+	}
+}
+
+func TestCollectProcessMonitorDataProcessesError(t *testing.T) {
+	fake := provider.NewFake()
+	fake.Proc.Faults["Processes"] = errors.New("permission denied")
+	collector := newFakeCollector(fake)
+
+	if _, err := collector.CollectProcessMonitorData(); err == nil {
+		t.Error("CollectProcessMonitorData() error = nil, want the process list error")
+	}
+}
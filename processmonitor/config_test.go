@@ -0,0 +1,82 @@
+package processmonitor
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ahmadreza-log/simple-monitor/monitortest"
+	"github.com/ahmadreza-log/simple-monitor/provider"
+)
+
+// collect returns a function running one collection of collector, for monitortest
+func collect(collector *ProcessMonitorCollector) func() error {
+	return func() error {
+		_, err := collector.CollectProcessMonitorData()
+		return err
+	}
+}
+
+// TestConfigUpdatesDuringCollections changes the configuration while several collections run, the way the
+// settings menu does while the live loop, a subscriber and the attach server collect; run it with -race
+func TestConfigUpdatesDuringCollections(t *testing.T) {
+	fake := provider.NewFake()
+	fake.Proc.Table = fakeTable()
+	collector := newFakeCollector(fake)
+
+	monitortest.DuringCollections(t, collect(collector), func(i int) {
+		if i%2 == 0 {
+			config := collector.GetConfig()
+			config.RefreshInterval = time.Duration(i) * time.Second
+			collector.UpdateConfig(config)
+		} else {
+			collector.changeConfig(func(config *ProcessMonitorConfig) {
+				config.RefreshInterval = time.Duration(i) * time.Second
+			})
+		}
+	})
+
+	data, err := collector.CollectProcessMonitorData()
+	if err != nil {
+		t.Fatalf("CollectProcessMonitorData() error = %v", err)
+	}
+	if data.RefreshInterval != monitortest.Rounds*time.Second {
+		t.Errorf("RefreshInterval = %v, want the last update (%ds)", data.RefreshInterval, monitortest.Rounds)
+	}
+}
+
+// TestHistoryAndSettersDuringCollections reads the history and switches idle mode, simulated data and providers
+// while several collections run, the way the managers do; run it with -race
+func TestHistoryAndSettersDuringCollections(t *testing.T) {
+	fake := provider.NewFake()
+	fake.Proc.Table = fakeTable()
+	collector := newFakeCollector(fake)
+
+	monitortest.DuringCollections(t, collect(collector), func(i int) {
+		history := collector.GetProcessUsageHistory()
+		for j := range history.ProcessCount {
+			history.ProcessCount[j] = -1
+		}
+		collector.SetIdle(i%2 == 0)
+		collector.SetSimulated(i%4 == 0 && i < monitortest.Rounds)
+		collector.SetProviders(fake.Providers())
+		if _, err := collector.CollectUserCounts(); err != nil {
+			t.Errorf("CollectUserCounts() error = %v", err)
+		}
+	})
+
+	if collector.IsSimulated() {
+		t.Errorf("IsSimulated() = true after the last SetSimulated(false)")
+	}
+	if _, err := collector.CollectProcessMonitorData(); err != nil {
+		t.Fatalf("CollectProcessMonitorData() error = %v", err)
+	}
+	history := collector.GetProcessUsageHistory()
+	if len(history.ProcessCount) == 0 {
+		t.Fatal("GetProcessUsageHistory() is empty after a collection")
+	}
+	history.ProcessCount[0] = -1
+	if slices.Contains(collector.GetProcessUsageHistory().ProcessCount, -1) {
+		t.Errorf("GetProcessUsageHistory().ProcessCount = %v, want changes to the returned copy kept out of the collector", collector.GetProcessUsageHistory().ProcessCount)
+	}
+}
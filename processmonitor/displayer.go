@@ -2,7 +2,11 @@ package processmonitor
 
 import (
 	"fmt"
+	"github.com/ahmadreza-log/simple-monitor/partial"
+	"github.com/ahmadreza-log/simple-monitor/terminal"
+	"math"
 	"strings"
+	"time"
 )
 
 // ProcessMonitorDisplayer handles the display and formatting of process monitoring data
@@ -11,7 +15,8 @@ type ProcessMonitorDisplayer struct {
 	// Display configuration
 	ShowGraphics bool // Whether to show graphical elements
 	ShowColors   bool // Whether to use colored output
-	BarWidth     int  // Width of progress bars
+	BarWidth     int  // Width of progress bars (when AutoWidth is off)
+	AutoWidth    bool // Whether to fit bars, separators and columns to the terminal width
 	MaxProcesses int  // Maximum number of processes to display
 
 	// Color codes for different elements
@@ -33,6 +38,7 @@ func NewProcessMonitorDisplayer() *ProcessMonitorDisplayer {
 		ShowGraphics: true,
 		ShowColors:   true,
 		BarWidth:     50,
+		AutoWidth:    true,
 		MaxProcesses: 20,
 		ColorReset:   "\033[0m",
 		ColorRed:     "\033[31m",
@@ -59,26 +65,43 @@ func (displayer *ProcessMonitorDisplayer) DisplayProcessMonitorData(data *Proces
 
 	// Display top processes by CPU
 	if len(data.TopCPUProcesses) > 0 {
-		displayer.displayTopProcesses(data.TopCPUProcesses, "CPU", "🔥 TOP CPU PROCESSES")
+		displayer.displayTopProcesses(data.TopCPUProcesses, "CPU", "🔥 TOP CPU PROCESSES", data.Timestamp)
 	}
 
 	// Display top processes by memory
 	if len(data.TopMemoryProcesses) > 0 {
-		displayer.displayTopProcesses(data.TopMemoryProcesses, "Memory", "💾 TOP MEMORY PROCESSES")
+		displayer.displayTopProcesses(data.TopMemoryProcesses, "Memory", "💾 TOP MEMORY PROCESSES", data.Timestamp)
 	}
 
 	// Display top processes by I/O
 	if len(data.TopIOProcesses) > 0 {
-		displayer.displayTopProcesses(data.TopIOProcesses, "I/O", "⚡ TOP I/O PROCESSES")
+		displayer.displayTopProcesses(data.TopIOProcesses, "I/O", "⚡ TOP I/O PROCESSES", data.Timestamp)
 	}
 
 	// Display top processes by threads
 	if len(data.TopThreadProcesses) > 0 {
-		displayer.displayTopProcesses(data.TopThreadProcesses, "Threads", "🧵 TOP THREAD PROCESSES")
+		displayer.displayTopProcesses(data.TopThreadProcesses, "Threads", "🧵 TOP THREAD PROCESSES", data.Timestamp)
+	}
+
+	// Display top processes by open files
+	if len(data.TopOpenFilesProcesses) > 0 {
+		displayer.displayTopActivity(data.TopOpenFilesProcesses, "Open Files", "📂 TOP OPEN FILES PROCESSES")
+	}
+
+	// Display top processes by context switch rate
+	if len(data.TopContextSwitchProcesses) > 0 {
+		displayer.displayTopActivity(data.TopContextSwitchProcesses, "Ctx Switches", "🔀 TOP CONTEXT SWITCH PROCESSES")
+	}
+
+	// Display CPU attribution by process family
+	if len(data.CPUAttribution) > 0 {
+		displayer.displayCPUAttribution(data.CPUAttribution)
 	}
 
 	// Display process tree
-	if len(data.ProcessTree) > 0 {
+	if reason, failed := data.SectionErrors.Get("process_tree"); failed {
+		displayer.displayUnavailable("🌳 PROCESS TREE", reason)
+	} else if len(data.ProcessTree) > 0 {
 		displayer.displayProcessTree(data.ProcessTree)
 	}
 
@@ -87,6 +110,26 @@ func (displayer *ProcessMonitorDisplayer) DisplayProcessMonitorData(data *Proces
 		displayer.displayProcessAlerts(data.ProcessAlerts)
 	}
 
+	// Display respawn loops
+	if len(data.RespawnLoops) > 0 {
+		displayer.displayRespawnLoops(data.RespawnLoops)
+	}
+
+	// Display processes close to their resource limits
+	if len(data.LimitWarnings) > 0 {
+		displayer.displayLimitWarnings(data.LimitWarnings)
+	}
+
+	// Display watched processes
+	if len(data.Watchdog) > 0 {
+		displayer.displayWatchdog(data.Watchdog)
+	}
+
+	// Display error logs correlated with alerts
+	if len(data.ProcessLogs) > 0 {
+		displayer.displayProcessLogs(data.ProcessLogs)
+	}
+
 	// Display process status and alerts
 	displayer.displayProcessStatus(data)
 
@@ -97,7 +140,12 @@ func (displayer *ProcessMonitorDisplayer) DisplayProcessMonitorData(data *Proces
 // displayHeader displays the process monitor header
 func (displayer *ProcessMonitorDisplayer) displayHeader(data *ProcessMonitorData) {
 	fmt.Println(displayer.colorize("⚙️  PROCESS MONITOR", displayer.ColorBold+displayer.ColorCyan))
-	fmt.Println(strings.Repeat("=", 80))
+	fmt.Println(displayer.rule("="))
+
+	// Synthetic data must never be mistaken for a real system
+	if data.Simulated {
+		fmt.Println(displayer.colorize("🧪 Simulated data (--simulate)", displayer.ColorMagenta))
+	}
 
 	// Process summary
 	fmt.Printf("%sTotal Processes: %s%d%s\n",
@@ -106,6 +154,20 @@ func (displayer *ProcessMonitorDisplayer) displayHeader(data *ProcessMonitorData
 		data.TotalProcesses,
 		displayer.colorize("", displayer.ColorReset))
 
+	if len(data.UserFilter) > 0 {
+		fmt.Printf("%sUsers: %s%s\n",
+			displayer.colorize("", displayer.ColorBold),
+			displayer.colorize(strings.Join(data.UserFilter, ", "), displayer.ColorCyan),
+			displayer.colorize("", displayer.ColorReset))
+	}
+
+	if len(data.SliceFilter) > 0 {
+		fmt.Printf("%sSlices: %s%s\n",
+			displayer.colorize("", displayer.ColorBold),
+			displayer.colorize(strings.Join(data.SliceFilter, ", "), displayer.ColorMagenta),
+			displayer.colorize("", displayer.ColorReset))
+	}
+
 	fmt.Printf("%sRunning: %s%d%s, Sleeping: %s%d%s, Zombie: %s%d%s, Stopped: %s%d%s\n",
 		displayer.colorize("", displayer.ColorBold),
 		displayer.colorize("", displayer.ColorGreen),
@@ -139,13 +201,13 @@ func (displayer *ProcessMonitorDisplayer) displayHeader(data *ProcessMonitorData
 		data.TotalOpenFiles,
 		displayer.colorize("", displayer.ColorReset))
 
-	fmt.Println(strings.Repeat("=", 80))
+	fmt.Println(displayer.rule("="))
 }
 
 // displayOverallProcessStats displays overall process statistics with graphical bars
 func (displayer *ProcessMonitorDisplayer) displayOverallProcessStats(data *ProcessMonitorData) {
 	fmt.Println("\n📊 OVERALL PROCESS STATISTICS")
-	fmt.Println(strings.Repeat("-", 50))
+	fmt.Println(displayer.sectionRule())
 
 	// CPU usage bar
 	displayer.displayUsageBar("Total CPU Usage", data.TotalCPUUsage, displayer.getCPUUsageColor(data.TotalCPUUsage))
@@ -168,24 +230,27 @@ func (displayer *ProcessMonitorDisplayer) displayOverallProcessStats(data *Proce
 	displayer.displayUsageBar("Thread Count", threadPercent, displayer.getThreadCountColor(data.TotalThreads))
 }
 
-// displayTopProcesses displays top processes by a specific metric
-func (displayer *ProcessMonitorDisplayer) displayTopProcesses(processes []ProcessInfo, metric, title string) {
+// displayTopProcesses displays top processes by a specific metric, with start times relative to now (the collection time)
+func (displayer *ProcessMonitorDisplayer) displayTopProcesses(processes []ProcessInfo, metric, title string, now time.Time) {
 	fmt.Printf("\n%s\n", title)
-	fmt.Println(strings.Repeat("-", 80))
+	fmt.Println(displayer.rule("-"))
 
 	// Header
-	fmt.Printf("%s%-8s %-20s %-8s %-8s %-8s %-8s %-8s %s\n",
+	fmt.Printf("%s%-8s %-20s %-8s %-8s %-10s %-15s %-8s %-8s %-8s %-12s %s\n",
 		displayer.colorize("", displayer.ColorBold),
 		"PID",
 		"Name",
 		"CPU%",
 		"Memory%",
+		"TIME+",
+		"Started",
 		"Threads",
 		"Status",
 		"User",
+		"Slice",
 		displayer.colorize("", displayer.ColorReset))
 
-	fmt.Println(strings.Repeat("-", 80))
+	fmt.Println(displayer.rule("-"))
 
 	// Display processes
 	for i, proc := range processes {
@@ -220,7 +285,15 @@ func (displayer *ProcessMonitorDisplayer) displayTopProcesses(processes []Proces
 		// Status color
 		statusColor := displayer.getProcessStatusColor(proc.Status)
 
-		fmt.Printf("%s%-8d %-20s %s%-8.2f %s%-8.2f %s%-8d %s%-8s %s%-8s %s\n",
+		// Truncate long slice names
+		slice := proc.Slice
+		if slice == "" {
+			slice = "-"
+		} else if len(slice) > 12 {
+			slice = slice[:9] + "..."
+		}
+
+		fmt.Printf("%s%-8d %-20s %s%-8.2f %s%-8.2f %s%-10s %-15s %s%-8d %s%-8s %s%-8s %s%-12s %s\n",
 			displayer.colorize("", displayer.ColorBold),
 			proc.PID,
 			name,
@@ -228,23 +301,222 @@ func (displayer *ProcessMonitorDisplayer) displayTopProcesses(processes []Proces
 			proc.CPUUsage,
 			displayer.colorize("", displayer.ColorBlue),
 			proc.MemoryUsage,
+			displayer.colorize("", displayer.ColorWhite),
+			formatCPUTime(proc.CPUTime),
+			formatStarted(proc.CreateTime, now),
 			displayer.colorize("", displayer.ColorCyan),
 			proc.Threads,
 			statusColor,
 			proc.Status,
 			displayer.colorize("", displayer.ColorWhite),
 			proc.User,
+			displayer.colorize("", displayer.ColorMagenta),
+			slice,
 			displayer.colorize("", displayer.ColorReset))
 
+		// Windows services, window title and elevation
+		if proc.Windows != nil {
+			displayer.displayWindowsDetails(proc.Windows)
+		}
+
+		// Namespace, seccomp and container hints
+		if proc.Sandbox != nil {
+			displayer.displaySandbox(proc.Sandbox)
+		}
+
 		// Metric bar
 		displayer.displayUsageBar("  "+name, metricValue, metricColor)
 	}
 }
 
+// DisplayAncestry displays the parent chain of a traced process from the top ancestor down to the process itself
+func (displayer *ProcessMonitorDisplayer) DisplayAncestry(chain []AncestorInfo) {
+	if len(chain) == 0 {
+		return
+	}
+
+	fmt.Printf("\n🧬 ANCESTRY OF PID %d\n", chain[0].PID)
+	fmt.Println(displayer.rule("-"))
+
+	// A chain that stops below PID 1 lost a parent that exited or is hidden in another PID namespace
+	top := chain[len(chain)-1]
+	if top.ParentPID > 0 {
+		fmt.Println(displayer.colorize(fmt.Sprintf("⚠️  Parent PID %d is gone or not visible (exited, or in another PID namespace)", top.ParentPID), displayer.ColorYellow))
+	}
+
+	now := time.Now()
+	for depth := 0; depth < len(chain); depth++ {
+		ancestor := chain[len(chain)-1-depth]
+
+		indent := strings.Repeat("   ", depth)
+		branch := ""
+		if depth > 0 {
+			branch = "└─ "
+		}
+		color := displayer.ColorBlue
+		if depth == len(chain)-1 {
+			color = displayer.ColorBold + displayer.ColorMagenta
+		}
+
+		name := ancestor.Name
+		if name == "" {
+			name = "?"
+		}
+		user := ancestor.User
+		if user == "" {
+			user = "?"
+		}
+		fmt.Printf("%s%s%s  %s  started %s\n",
+			indent,
+			branch,
+			displayer.colorize(fmt.Sprintf("%d %s", ancestor.PID, name), color),
+			user,
+			formatStarted(ancestor.CreateTime, now))
+
+		commandLine := ancestor.CommandLine
+		if commandLine == "" {
+			commandLine = "(command line not readable)"
+		}
+		cmdIndent := indent + strings.Repeat(" ", len([]rune(branch)))
+		fmt.Printf("%s%s\n", cmdIndent, displayer.colorize(terminal.Truncate(commandLine, max(displayer.lineWidth()-len(cmdIndent)-2, 20)), displayer.ColorCyan))
+	}
+}
+
+// displayWindowsDetails displays the Windows-specific identity of a process below its row
+func (displayer *ProcessMonitorDisplayer) displayWindowsDetails(details *WindowsProcessDetails) {
+	var parts []string
+
+	if details.ElevationKnown && details.Elevated {
+		parts = append(parts, displayer.getElevationColor(true)+"[Administrator]"+displayer.colorize("", displayer.ColorReset))
+	}
+
+	if len(details.Services) > 0 {
+		services := terminal.Truncate(strings.Join(details.Services, ", "), displayer.lineWidth()-20)
+		parts = append(parts, "Services: "+services)
+	}
+
+	if details.WindowTitle != "" {
+		title := terminal.Truncate(details.WindowTitle, displayer.lineWidth()-30)
+		parts = append(parts, "Window: \""+title+"\"")
+	}
+
+	if len(parts) == 0 {
+		return
+	}
+
+	fmt.Printf("         %s↳%s %s\n",
+		displayer.colorize("", displayer.ColorCyan),
+		displayer.colorize("", displayer.ColorReset),
+		strings.Join(parts, " | "))
+}
+
+// displaySandbox displays the confinement flags of a process below its row
+func (displayer *ProcessMonitorDisplayer) displaySandbox(sandbox *SandboxInfo) {
+	flags := sandboxFlags(sandbox)
+	if flags == "" {
+		flags = "none"
+	}
+
+	fmt.Printf("         %s↳%s Sandbox: %s\n",
+		displayer.colorize("", displayer.ColorCyan),
+		displayer.colorize("", displayer.ColorReset),
+		flags)
+}
+
+// displayCPUAttribution displays summed subtree CPU for each process family
+func (displayer *ProcessMonitorDisplayer) displayCPUAttribution(families []ProcessFamilyInfo) {
+	fmt.Println("\n🧬 CPU BY PROCESS FAMILY")
+	fmt.Println(displayer.rule("-"))
+
+	// Header
+	fmt.Printf("%s%-8s %-20s %-8s %-10s %-10s %-10s %s\n",
+		displayer.colorize("", displayer.ColorBold),
+		"Root PID",
+		"Family",
+		"Procs",
+		"Tree CPU%",
+		"Self CPU%",
+		"Memory",
+		displayer.colorize("", displayer.ColorReset))
+
+	fmt.Println(displayer.rule("-"))
+
+	for i, family := range families {
+		if i >= displayer.MaxProcesses {
+			break
+		}
+
+		// Truncate long process names
+		name := family.Name
+		if len(name) > 20 {
+			name = name[:17] + "..."
+		}
+
+		cpuColor := displayer.getCPUUsageColor(family.TotalCPU)
+
+		fmt.Printf("%s%-8d %-20s %s%-8d %s%-10.2f %s%-10.2f %s%-10s%s\n",
+			displayer.colorize("", displayer.ColorBold),
+			family.RootPID,
+			name,
+			displayer.colorize("", displayer.ColorCyan),
+			family.ProcessCount,
+			cpuColor,
+			family.TotalCPU,
+			displayer.colorize("", displayer.ColorWhite),
+			family.SelfCPU,
+			displayer.colorize("", displayer.ColorBlue),
+			displayer.formatBytes(family.TotalRSS),
+			displayer.colorize("", displayer.ColorReset))
+	}
+}
+
+// displayTopActivity displays processes ranked by open files or context switches per second
+// The ranked metric is highlighted; these processes often look idle by CPU and memory
+func (displayer *ProcessMonitorDisplayer) displayTopActivity(processes []ProcessInfo, metric, title string) {
+	fmt.Printf("\n%s\n", title)
+	fmt.Println(displayer.rule("-"))
+
+	// Header
+	fmt.Println(displayer.colorize(fmt.Sprintf("%-8s %-20s %-11s %-12s %-8s %-8s %s",
+		"PID", "Name", "Open Files", "Ctx Sw/s", "CPU%", "Memory%", "User"), displayer.ColorBold))
+
+	fmt.Println(displayer.rule("-"))
+
+	for i, proc := range processes {
+		if i >= displayer.MaxProcesses {
+			break
+		}
+
+		// Truncate long process names
+		name := proc.Name
+		if len(name) > 20 {
+			name = name[:17] + "..."
+		}
+
+		// Highlight the ranked column
+		var filesColor, switchColor string
+		switch metric {
+		case "Open Files":
+			filesColor = displayer.getOpenFilesColor(proc.OpenFiles)
+		case "Ctx Switches":
+			switchColor = displayer.getContextSwitchColor(proc.ContextSwitchRate)
+		}
+
+		fmt.Printf("%-8d %-20s %s %s %s %-8.2f %s\n",
+			proc.PID,
+			name,
+			displayer.colorize(fmt.Sprintf("%-11d", proc.OpenFiles), filesColor),
+			displayer.colorize(fmt.Sprintf("%-12.0f", proc.ContextSwitchRate), switchColor),
+			displayer.colorize(fmt.Sprintf("%-8.2f", proc.CPUUsage), displayer.getCPUUsageColor(proc.CPUUsage)),
+			proc.MemoryUsage,
+			proc.User)
+	}
+}
+
 // displayProcessTree displays the process tree
 func (displayer *ProcessMonitorDisplayer) displayProcessTree(tree []ProcessTreeInfo) {
 	fmt.Println("\n🌳 PROCESS TREE")
-	fmt.Println(strings.Repeat("-", 50))
+	fmt.Println(displayer.sectionRule())
 
 	displayer.displayTreeLevel(tree, 0)
 }
@@ -283,7 +555,7 @@ func (displayer *ProcessMonitorDisplayer) displayTreeLevel(tree []ProcessTreeInf
 // displayProcessAlerts displays process alerts
 func (displayer *ProcessMonitorDisplayer) displayProcessAlerts(alerts []ProcessAlertInfo) {
 	fmt.Println("\n🚨 PROCESS ALERTS")
-	fmt.Println(strings.Repeat("-", 80))
+	fmt.Println(displayer.rule("-"))
 
 	// Header
 	fmt.Printf("%s%-8s %-20s %-20s %-10s %-15s %s\n",
@@ -295,7 +567,7 @@ func (displayer *ProcessMonitorDisplayer) displayProcessAlerts(alerts []ProcessA
 		"Value",
 		displayer.colorize("", displayer.ColorReset))
 
-	fmt.Println(strings.Repeat("-", 80))
+	fmt.Println(displayer.rule("-"))
 
 	// Display alerts
 	for _, alert := range alerts {
@@ -321,10 +593,233 @@ func (displayer *ProcessMonitorDisplayer) displayProcessAlerts(alerts []ProcessA
 	}
 }
 
+// displayWatchdog displays whether each watched process is running and its restart attempts
+func (displayer *ProcessMonitorDisplayer) displayWatchdog(watchdog []WatchdogInfo) {
+	fmt.Println("\n🐕 WATCHDOG")
+	fmt.Println(displayer.rule("-"))
+
+	// Header
+	fmt.Printf("%s%-20s %-10s %-20s %-9s %s%s\n",
+		displayer.colorize("", displayer.ColorBold),
+		"Name",
+		"Status",
+		"PIDs / Down Since",
+		"Restarts",
+		"Restart Command",
+		displayer.colorize("", displayer.ColorReset))
+
+	for _, watched := range watchdog {
+		status := displayer.colorize(fmt.Sprintf("%-10s", "RUNNING"), displayer.ColorGreen)
+		detail := formatPIDs(watched.PIDs)
+		if !watched.Running {
+			status = displayer.colorize(fmt.Sprintf("%-10s", "DOWN"), displayer.ColorRed)
+			detail = watched.DownSince.Format("2006-01-02 15:04:05")
+		}
+		command := watched.RestartCommand
+		if command == "" {
+			command = "(alert only)"
+		}
+
+		fmt.Printf("%-20s %s %-20s %-9d %s\n",
+			terminal.Truncate(watched.Name, 20),
+			status,
+			terminal.Truncate(detail, 20),
+			watched.RestartCount,
+			terminal.Truncate(command, displayer.lineWidth()-64))
+
+		if watched.RestartError != "" {
+			fmt.Printf("  %s\n", displayer.colorize(watched.RestartError, displayer.ColorRed))
+		} else if !watched.Running && watched.RestartHeld != "" {
+			fmt.Printf("  %s\n", displayer.colorize("Restart held: "+watched.RestartHeld, displayer.ColorYellow))
+		}
+	}
+}
+
+// formatPIDs joins PIDs for display, e.g. "812, 813"
+func formatPIDs(pids []int32) string {
+	parts := make([]string, len(pids))
+	for i, pid := range pids {
+		parts[i] = fmt.Sprint(pid)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatCPUTime formats accumulated CPU time the way top's TIME+ column does: minutes:seconds.hundredths
+func formatCPUTime(milliseconds uint64) string {
+	minutes := milliseconds / 60000
+	seconds := milliseconds / 1000 % 60
+	hundredths := milliseconds / 10 % 100
+	return fmt.Sprintf("%d:%02d.%02d", minutes, seconds, hundredths)
+}
+
+// formatStarted formats a start time in milliseconds since the epoch with the age of the process,
+// e.g. "09:14 (2h05m)" today or "Mar04 (12d03h)" on an earlier day, or "-" when unknown
+func formatStarted(createTime int64, now time.Time) string {
+	if createTime <= 0 {
+		return "-"
+	}
+
+	started := time.UnixMilli(createTime)
+	stamp := started.Format("2006")
+	if started.YearDay() == now.YearDay() && started.Year() == now.Year() {
+		stamp = started.Format("15:04")
+	} else if started.Year() == now.Year() {
+		stamp = started.Format("Jan02")
+	}
+
+	age := now.Sub(started)
+	switch {
+	case age < time.Hour:
+		return fmt.Sprintf("%s (%dm)", stamp, int(age.Minutes()))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%s (%dh%02dm)", stamp, int(age.Hours()), int(age.Minutes())%60)
+	default:
+		return fmt.Sprintf("%s (%dd%02dh)", stamp, int(age.Hours()/24), int(age.Hours())%24)
+	}
+}
+
+// displayRespawnLoops displays processes that keep starting and exiting
+func (displayer *ProcessMonitorDisplayer) displayRespawnLoops(loops []ProcessChurnInfo) {
+	fmt.Println("\n🔁 RESPAWN LOOPS")
+	fmt.Println(displayer.rule("-"))
+
+	// Header
+	fmt.Printf("%s%-20s %-10s %-8s %-8s %-8s %s%s\n",
+		displayer.colorize("", displayer.ColorBold),
+		"Name",
+		"Rate/min",
+		"Starts",
+		"Exits",
+		"Last PID",
+		"Parent",
+		displayer.colorize("", displayer.ColorReset))
+
+	for _, loop := range loops {
+		// Truncate long process names
+		name := loop.Name
+		if len(name) > 20 {
+			name = name[:17] + "..."
+		}
+
+		fmt.Printf("%-20s %s%-10.1f%s %-8d %-8d %-8d %s (PID %d)\n",
+			name,
+			displayer.colorize("", displayer.ColorRed),
+			loop.StartsPerMinute,
+			displayer.colorize("", displayer.ColorReset),
+			loop.Starts,
+			loop.Exits,
+			loop.LastPID,
+			loop.ParentName,
+			loop.ParentPID)
+
+		if loop.CommandLine != "" {
+			commandLine := terminal.Truncate(loop.CommandLine, displayer.lineWidth()-10)
+			fmt.Printf("  %s%s%s\n",
+				displayer.colorize("", displayer.ColorWhite),
+				commandLine,
+				displayer.colorize("", displayer.ColorReset))
+		}
+	}
+}
+
+// displayLimitWarnings displays processes using most of one of their own resource limits
+func (displayer *ProcessMonitorDisplayer) displayLimitWarnings(warnings []ProcessLimitInfo) {
+	fmt.Println("\n📏 RESOURCE LIMITS")
+	fmt.Println(displayer.rule("-"))
+
+	// Header
+	fmt.Printf("%s%-20s %-8s %-20s %-12s %-12s %-12s %s%s\n",
+		displayer.colorize("", displayer.ColorBold),
+		"Name",
+		"PID",
+		"Limit (User)",
+		"Used",
+		"Soft",
+		"Hard",
+		"Usage",
+		displayer.colorize("", displayer.ColorReset))
+
+	for _, warning := range warnings {
+		// Truncate long process names
+		name := warning.Name
+		if len(name) > 20 {
+			name = name[:17] + "..."
+		}
+
+		color := displayer.ColorYellow
+		if warning.Percent >= 95 {
+			color = displayer.ColorRed
+		}
+
+		fmt.Printf("%-20s %-8d %-20s %-12s %-12s %-12s %s%.1f%%%s\n",
+			name,
+			warning.PID,
+			fmt.Sprintf("%s (%s)", warning.Resource, warning.User),
+			displayer.formatLimitValue(warning.Resource, warning.Used),
+			displayer.formatLimitValue(warning.Resource, warning.Soft),
+			displayer.formatLimitValue(warning.Resource, warning.Hard),
+			displayer.colorize("", color),
+			warning.Percent,
+			displayer.colorize("", displayer.ColorReset))
+	}
+}
+
+// formatLimitValue formats a resource limit or its usage, in bytes for the address space
+func (displayer *ProcessMonitorDisplayer) formatLimitValue(resource string, value uint64) string {
+	if value == math.MaxUint64 {
+		return "unlimited"
+	}
+	if resource == "as" {
+		return displayer.formatBytes(value)
+	}
+	return fmt.Sprintf("%d", value)
+}
+
+// displayProcessLogs displays recent error log lines for alerting processes
+func (displayer *ProcessMonitorDisplayer) displayProcessLogs(logs []ProcessLogInfo) {
+	fmt.Println("\n📜 RECENT ERRORS FOR ALERTING PROCESSES")
+	fmt.Println(displayer.rule("-"))
+
+	for _, info := range logs {
+		fmt.Printf("%s%s (PID %d)%s %s[%s]%s\n",
+			displayer.colorize("", displayer.ColorBold),
+			info.Name,
+			info.PID,
+			displayer.colorize("", displayer.ColorReset),
+			displayer.colorize("", displayer.ColorCyan),
+			info.Unit,
+			displayer.colorize("", displayer.ColorReset))
+
+		if info.Error != "" {
+			fmt.Printf("  %s⚠️  %s%s\n",
+				displayer.colorize("", displayer.ColorYellow),
+				info.Error,
+				displayer.colorize("", displayer.ColorReset))
+			continue
+		}
+
+		if len(info.Lines) == 0 {
+			fmt.Println("  No recent errors logged")
+			continue
+		}
+
+		for _, line := range info.Lines {
+			// Truncate long messages to the line width
+			message := terminal.Truncate(line.Message, displayer.lineWidth()-20)
+
+			fmt.Printf("  %s%s%s %s\n",
+				displayer.colorize("", displayer.ColorWhite),
+				line.Timestamp.Local().Format("15:04:05"),
+				displayer.colorize("", displayer.ColorReset),
+				message)
+		}
+	}
+}
+
 // displayProcessStatus displays process status and alerts
 func (displayer *ProcessMonitorDisplayer) displayProcessStatus(data *ProcessMonitorData) {
 	fmt.Println("\n🚨 PROCESS STATUS & ALERTS")
-	fmt.Println(strings.Repeat("-", 50))
+	fmt.Println(displayer.sectionRule())
 
 	// Process status
 	statusColor := displayer.getProcessStatusColor(data.ProcessStatus)
@@ -398,11 +893,49 @@ func (displayer *ProcessMonitorDisplayer) displayProcessStatus(data *ProcessMoni
 			displayer.colorize("", displayer.ColorGreen),
 			displayer.colorize("", displayer.ColorReset))
 	}
+
+	// Watchdog warning
+	if data.WatchdogWarning {
+		down := 0
+		for _, watched := range data.Watchdog {
+			if !watched.Running {
+				down++
+			}
+		}
+		fmt.Printf("🚨 Watchdog: %s\n",
+			displayer.colorize(fmt.Sprintf("%d WATCHED PROCESS(ES) DOWN", down), displayer.ColorRed))
+	} else if len(data.Watchdog) > 0 {
+		fmt.Printf("✅ Watchdog: %s\n",
+			displayer.colorize(fmt.Sprintf("ALL %d RUNNING", len(data.Watchdog)), displayer.ColorGreen))
+	}
+
+	// Respawn loop warning
+	if data.RespawnWarning {
+		fmt.Printf("%s⚠️  Respawn Loop Warning: %sACTIVE (%d)%s\n",
+			displayer.colorize("", displayer.ColorBold),
+			displayer.colorize("", displayer.ColorRed),
+			len(data.RespawnLoops),
+			displayer.colorize("", displayer.ColorReset))
+	} else {
+		fmt.Printf("%s✅ Process Churn: %sNORMAL%s\n",
+			displayer.colorize("", displayer.ColorBold),
+			displayer.colorize("", displayer.ColorGreen),
+			displayer.colorize("", displayer.ColorReset))
+	}
+
+	// Resource limit warning
+	if data.LimitWarning {
+		fmt.Printf("%s⚠️  Resource Limit Warning: %sACTIVE (%d)%s\n",
+			displayer.colorize("", displayer.ColorBold),
+			displayer.colorize("", displayer.ColorRed),
+			len(data.LimitWarnings),
+			displayer.colorize("", displayer.ColorReset))
+	}
 }
 
 // displayUsageBar displays a graphical usage bar
 func (displayer *ProcessMonitorDisplayer) displayUsageBar(label string, value float64, color string, customWidth ...int) {
-	width := displayer.BarWidth
+	width := displayer.barWidth()
 	if len(customWidth) > 0 {
 		width = customWidth[0]
 	}
@@ -420,7 +953,7 @@ func (displayer *ProcessMonitorDisplayer) displayUsageBar(label string, value fl
 		displayer.colorize("", displayer.ColorBold),
 		label,
 		displayer.colorize("", displayer.ColorWhite),
-		displayer.colorize(bar, color),
+		displayer.colorize(bar, terminal.WithoutMarker(color)),
 		displayer.colorize("", displayer.ColorWhite),
 		color,
 		value,
@@ -429,7 +962,7 @@ func (displayer *ProcessMonitorDisplayer) displayUsageBar(label string, value fl
 
 // displayFooter displays the process monitor footer
 func (displayer *ProcessMonitorDisplayer) displayFooter(data *ProcessMonitorData) {
-	fmt.Println(strings.Repeat("=", 80))
+	fmt.Println(displayer.rule("="))
 	fmt.Printf("%sLast Updated: %s%s%s\n",
 		displayer.colorize("", displayer.ColorBold),
 		displayer.colorize("", displayer.ColorCyan),
@@ -442,125 +975,210 @@ func (displayer *ProcessMonitorDisplayer) displayFooter(data *ProcessMonitorData
 		data.RefreshInterval.Seconds(),
 		displayer.colorize("", displayer.ColorReset))
 
-	fmt.Println(strings.Repeat("=", 80))
+	if !displayer.ShowColors || terminal.ColorBlindMode() {
+		fmt.Println(terminal.SeverityLegend())
+	}
+
+	fmt.Println(displayer.rule("="))
+}
+
+// formatBytes formats bytes into human-readable format
+func (displayer *ProcessMonitorDisplayer) formatBytes(bytes uint64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
+
+// displayUnavailable shows a section that could not be collected, with the reason
+func (displayer *ProcessMonitorDisplayer) displayUnavailable(title, reason string) {
+	fmt.Println("\n" + title)
+	fmt.Println(displayer.sectionRule())
+	fmt.Println(displayer.colorize("⚠️  "+partial.Unavailable(reason), displayer.ColorYellow))
+}
+
+// lineWidth returns the layout width: the terminal width, or 80 columns when AutoWidth is off
+func (displayer *ProcessMonitorDisplayer) lineWidth() int {
+	if !displayer.AutoWidth {
+		return terminal.DefaultWidth
+	}
+	return terminal.Fit(terminal.Width(), 40, 240)
+}
+
+// rule returns a separator line across the layout width
+func (displayer *ProcessMonitorDisplayer) rule(char string) string {
+	return strings.Repeat(char, displayer.lineWidth())
+}
+
+// sectionRule returns a section underline, shortened on narrow terminals
+func (displayer *ProcessMonitorDisplayer) sectionRule() string {
+	return strings.Repeat("-", terminal.Fit(displayer.lineWidth(), 0, 50))
+}
+
+// barWidth returns the progress bar width, leaving room for the label and value on the same line
+func (displayer *ProcessMonitorDisplayer) barWidth() int {
+	if !displayer.AutoWidth {
+		return displayer.BarWidth
+	}
+	return terminal.Fit(displayer.lineWidth()-36, 10, 100)
 }
 
 // colorize applies color to text if colors are enabled
 func (displayer *ProcessMonitorDisplayer) colorize(text, color string) string {
 	if !displayer.ShowColors {
-		return text
+		// Severity colors are reduced to their marker
+		return terminal.MarkerOf(color) + text
 	}
-	return color + text + displayer.ColorReset
+	return terminal.PaletteColor(displayer.severityLevel(color), color) + text + displayer.ColorReset
 }
 
-// getCPUUsageColor returns the appropriate color for CPU usage
-func (displayer *ProcessMonitorDisplayer) getCPUUsageColor(usage float64) string {
+// severityColor returns a severity color in the active palette, followed by its marker when markers are shown
+// Markers are shown in color-blind mode and whenever colors are off, so severity never depends on color alone
+func (displayer *ProcessMonitorDisplayer) severityColor(color string) string {
+	level := displayer.severityLevel(color)
+	marker := ""
+	if !displayer.ShowColors || terminal.ColorBlindMode() {
+		marker = terminal.SeverityMarker(level)
+	}
 	if !displayer.ShowColors {
-		return ""
+		return marker
 	}
+	return terminal.PaletteColor(level, color) + marker
+}
+
+// severityLevel returns the severity a displayer color stands for
+func (displayer *ProcessMonitorDisplayer) severityLevel(color string) terminal.Severity {
+	switch color {
+	case displayer.ColorGreen:
+		return terminal.SeverityOK
+	case displayer.ColorYellow:
+		return terminal.SeverityWarning
+	case displayer.ColorMagenta:
+		return terminal.SeverityHigh
+	case displayer.ColorRed:
+		return terminal.SeverityCritical
+	}
+	return terminal.SeverityNone
+}
 
+// getCPUUsageColor returns the appropriate color for CPU usage
+func (displayer *ProcessMonitorDisplayer) getCPUUsageColor(usage float64) string {
 	switch {
 	case usage < 20:
-		return displayer.ColorGreen
+		return displayer.severityColor(displayer.ColorGreen)
 	case usage < 50:
-		return displayer.ColorYellow
+		return displayer.severityColor(displayer.ColorYellow)
 	case usage < 80:
-		return displayer.ColorMagenta
+		return displayer.severityColor(displayer.ColorMagenta)
 	default:
-		return displayer.ColorRed
+		return displayer.severityColor(displayer.ColorRed)
 	}
 }
 
 // getMemoryUsageColor returns the appropriate color for memory usage
 func (displayer *ProcessMonitorDisplayer) getMemoryUsageColor(usage float64) string {
-	if !displayer.ShowColors {
-		return ""
-	}
-
 	switch {
 	case usage < 20:
-		return displayer.ColorGreen
+		return displayer.severityColor(displayer.ColorGreen)
 	case usage < 50:
-		return displayer.ColorYellow
+		return displayer.severityColor(displayer.ColorYellow)
 	case usage < 80:
-		return displayer.ColorMagenta
+		return displayer.severityColor(displayer.ColorMagenta)
 	default:
-		return displayer.ColorRed
+		return displayer.severityColor(displayer.ColorRed)
 	}
 }
 
 // getProcessCountColor returns the appropriate color for process count
 func (displayer *ProcessMonitorDisplayer) getProcessCountColor(count int) string {
-	if !displayer.ShowColors {
-		return ""
-	}
-
 	switch {
 	case count < 100:
-		return displayer.ColorGreen
+		return displayer.severityColor(displayer.ColorGreen)
 	case count < 500:
-		return displayer.ColorYellow
+		return displayer.severityColor(displayer.ColorYellow)
 	case count < 1000:
-		return displayer.ColorMagenta
+		return displayer.severityColor(displayer.ColorMagenta)
 	default:
-		return displayer.ColorRed
+		return displayer.severityColor(displayer.ColorRed)
 	}
 }
 
-// getThreadCountColor returns the appropriate color for thread count
-func (displayer *ProcessMonitorDisplayer) getThreadCountColor(count int32) string {
-	if !displayer.ShowColors {
-		return ""
+// getOpenFilesColor returns the color for an open file descriptor count
+func (displayer *ProcessMonitorDisplayer) getOpenFilesColor(count int32) string {
+	switch {
+	case count < 256:
+		return displayer.severityColor(displayer.ColorGreen)
+	case count < 1024:
+		return displayer.severityColor(displayer.ColorYellow)
+	case count < 4096:
+		return displayer.severityColor(displayer.ColorMagenta)
+	default:
+		return displayer.severityColor(displayer.ColorRed)
+	}
+}
+
+// getContextSwitchColor returns the color for a context switch rate (per second)
+func (displayer *ProcessMonitorDisplayer) getContextSwitchColor(rate float64) string {
+	switch {
+	case rate < 1000:
+		return displayer.severityColor(displayer.ColorGreen)
+	case rate < 10000:
+		return displayer.severityColor(displayer.ColorYellow)
+	case rate < 50000:
+		return displayer.severityColor(displayer.ColorMagenta)
+	default:
+		return displayer.severityColor(displayer.ColorRed)
 	}
+}
 
+// getThreadCountColor returns the appropriate color for thread count
+func (displayer *ProcessMonitorDisplayer) getThreadCountColor(count int32) string {
 	switch {
 	case count < 50:
-		return displayer.ColorGreen
+		return displayer.severityColor(displayer.ColorGreen)
 	case count < 100:
-		return displayer.ColorYellow
+		return displayer.severityColor(displayer.ColorYellow)
 	case count < 200:
-		return displayer.ColorMagenta
+		return displayer.severityColor(displayer.ColorMagenta)
 	default:
-		return displayer.ColorRed
+		return displayer.severityColor(displayer.ColorRed)
 	}
 }
 
 // getIOUsageColor returns the appropriate color for I/O usage
 func (displayer *ProcessMonitorDisplayer) getIOUsageColor(usage uint64) string {
-	if !displayer.ShowColors {
-		return ""
-	}
-
 	usageMB := float64(usage) / (1024 * 1024)
 	switch {
 	case usageMB < 10:
-		return displayer.ColorGreen
+		return displayer.severityColor(displayer.ColorGreen)
 	case usageMB < 50:
-		return displayer.ColorYellow
+		return displayer.severityColor(displayer.ColorYellow)
 	case usageMB < 100:
-		return displayer.ColorMagenta
+		return displayer.severityColor(displayer.ColorMagenta)
 	default:
-		return displayer.ColorRed
+		return displayer.severityColor(displayer.ColorRed)
 	}
 }
 
 // getProcessStatusColor returns the appropriate color for process status
 func (displayer *ProcessMonitorDisplayer) getProcessStatusColor(status string) string {
-	if !displayer.ShowColors {
-		return ""
-	}
-
 	switch status {
 	case "R":
-		return displayer.ColorGreen
+		return displayer.severityColor(displayer.ColorGreen)
 	case "S":
-		return displayer.ColorYellow
+		return displayer.severityColor(displayer.ColorYellow)
 	case "Z":
-		return displayer.ColorRed
+		return displayer.severityColor(displayer.ColorRed)
 	case "T":
-		return displayer.ColorMagenta
+		return displayer.severityColor(displayer.ColorMagenta)
 	default:
-		return displayer.ColorWhite
+		return displayer.severityColor(displayer.ColorWhite)
 	}
 }
 
@@ -584,22 +1202,30 @@ func (displayer *ProcessMonitorDisplayer) getTreeLevelColor(level int) string {
 	}
 }
 
-// getSeverityColor returns the appropriate color for alert severity
-func (displayer *ProcessMonitorDisplayer) getSeverityColor(severity string) string {
+// getElevationColor returns the appropriate color for a process's elevation state
+func (displayer *ProcessMonitorDisplayer) getElevationColor(elevated bool) string {
 	if !displayer.ShowColors {
 		return ""
 	}
 
+	if elevated {
+		return displayer.ColorYellow
+	}
+	return displayer.ColorGreen
+}
+
+// getSeverityColor returns the appropriate color for alert severity
+func (displayer *ProcessMonitorDisplayer) getSeverityColor(severity string) string {
 	switch severity {
 	case "Critical":
-		return displayer.ColorRed
+		return displayer.severityColor(displayer.ColorRed)
 	case "High":
-		return displayer.ColorMagenta
+		return displayer.severityColor(displayer.ColorMagenta)
 	case "Medium":
-		return displayer.ColorYellow
+		return displayer.severityColor(displayer.ColorYellow)
 	case "Low":
-		return displayer.ColorGreen
+		return displayer.severityColor(displayer.ColorGreen)
 	default:
-		return displayer.ColorWhite
+		return displayer.severityColor(displayer.ColorWhite)
 	}
 }
@@ -0,0 +1,17 @@
+package processmonitor
+
+import (
+	"testing"
+
+	"github.com/ahmadreza-log/simple-monitor/monitortest"
+)
+
+// TestDisplayProcessMonitorDataGolden renders testdata/snapshot.json the way the fixtures command does and
+// compares the output with testdata/display.golden
+func TestDisplayProcessMonitorDataGolden(t *testing.T) {
+	monitortest.Golden(t, func(data *ProcessMonitorData) {
+		displayer := NewProcessMonitorDisplayer()
+		displayer.ShowColors, displayer.AutoWidth = false, false
+		displayer.DisplayProcessMonitorData(data)
+	})
+}
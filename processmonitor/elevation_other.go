@@ -0,0 +1,11 @@
+//go:build !windows
+
+package processmonitor
+
+import "errors"
+
+// processElevated reports whether a process runs with an elevated (administrator) token
+// Token elevation is a Windows concept, so it is never available on other platforms
+func processElevated(pid int32) (bool, error) {
+	return false, errors.New("token elevation is only available on Windows")
+}
@@ -0,0 +1,22 @@
+//go:build windows
+
+package processmonitor
+
+import "golang.org/x/sys/windows"
+
+// processElevated reports whether a process runs with an elevated (administrator) token
+func processElevated(pid int32) (bool, error) {
+	handle, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return false, err
+	}
+	defer windows.CloseHandle(handle)
+
+	var token windows.Token
+	if err := windows.OpenProcessToken(handle, windows.TOKEN_QUERY, &token); err != nil {
+		return false, err
+	}
+	defer token.Close()
+
+	return token.IsElevated(), nil
+}
@@ -3,8 +3,12 @@ package processmonitor
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/ahmadreza-log/simple-monitor/exportschema"
+	"github.com/ahmadreza-log/simple-monitor/protoexport"
+	"github.com/ahmadreza-log/simple-monitor/redact"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -17,6 +21,8 @@ type ProcessMonitorExporter struct {
 	DateFormat    string // Date format for file naming
 	CreateSubDirs bool   // Whether to create subdirectories for each module
 	PrettyPrint   bool   // Whether to pretty print JSON output
+
+	exportschema.Format // CSV header style and metadata rows
 }
 
 // NewProcessMonitorExporter creates a new instance of ProcessMonitorExporter
@@ -33,6 +39,10 @@ func NewProcessMonitorExporter() *ProcessMonitorExporter {
 // ExportToJSON exports process monitoring data to a JSON file
 // The file will be saved with a timestamp-based filename in the appropriate subdirectory
 func (exporter *ProcessMonitorExporter) ExportToJSON(data *ProcessMonitorData, moduleName string) (string, error) {
+	if err := redact.Copy(&data); err != nil {
+		return "", err
+	}
+
 	// Create logs directory if it doesn't exist
 	if err := os.MkdirAll(exporter.LogsDirectory, 0755); err != nil {
 		return "", fmt.Errorf("failed to create logs directory: %w", err)
@@ -76,9 +86,55 @@ func (exporter *ProcessMonitorExporter) ExportToJSON(data *ProcessMonitorData, m
 	return filePath, nil
 }
 
+// ExportToProtobuf exports process monitoring data to a protobuf file
+// The file holds one ProcessMonitorData message; it is smaller and faster to parse than JSON for long archives
+func (exporter *ProcessMonitorExporter) ExportToProtobuf(data *ProcessMonitorData, moduleName string) (string, error) {
+	if err := redact.Copy(&data); err != nil {
+		return "", err
+	}
+
+	// Create logs directory if it doesn't exist
+	if err := os.MkdirAll(exporter.LogsDirectory, 0755); err != nil {
+		return "", fmt.Errorf("failed to create logs directory: %w", err)
+	}
+
+	// Create subdirectory for the module if enabled
+	var targetDir string
+	if exporter.CreateSubDirs {
+		targetDir = filepath.Join(exporter.LogsDirectory, moduleName)
+		if err := os.MkdirAll(targetDir, 0755); err != nil {
+			return "", fmt.Errorf("failed to create module directory: %w", err)
+		}
+	} else {
+		targetDir = exporter.LogsDirectory
+	}
+
+	// Generate filename with timestamp
+	timestamp := time.Now().Format("2006-01-02_15-04-05")
+	filename := fmt.Sprintf("%s_%s.%s", moduleName, timestamp, protoexport.Extension)
+	filePath := filepath.Join(targetDir, filename)
+
+	// Encode as the message `simple-monitor proto` describes
+	content, err := protoexport.Marshal(data)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode protobuf data: %w", err)
+	}
+
+	// Write to file
+	if err := os.WriteFile(filePath, content, 0644); err != nil {
+		return "", fmt.Errorf("failed to write protobuf file: %w", err)
+	}
+
+	return filePath, nil
+}
+
 // ExportToCSV exports process monitoring data to a CSV file
 // This creates a simplified CSV format with key metrics
 func (exporter *ProcessMonitorExporter) ExportToCSV(data *ProcessMonitorData, moduleName string) (string, error) {
+	if err := redact.Copy(&data); err != nil {
+		return "", err
+	}
+
 	// Create logs directory if it doesn't exist
 	if err := os.MkdirAll(exporter.LogsDirectory, 0755); err != nil {
 		return "", fmt.Errorf("failed to create logs directory: %w", err)
@@ -114,6 +170,10 @@ func (exporter *ProcessMonitorExporter) ExportToCSV(data *ProcessMonitorData, mo
 // ExportToTXT exports process monitoring data to a text file
 // This creates a human-readable text format
 func (exporter *ProcessMonitorExporter) ExportToTXT(data *ProcessMonitorData, moduleName string) (string, error) {
+	if err := redact.Copy(&data); err != nil {
+		return "", err
+	}
+
 	// Create logs directory if it doesn't exist
 	if err := os.MkdirAll(exporter.LogsDirectory, 0755); err != nil {
 		return "", fmt.Errorf("failed to create logs directory: %w", err)
@@ -146,12 +206,68 @@ func (exporter *ProcessMonitorExporter) ExportToTXT(data *ProcessMonitorData, mo
 	return filePath, nil
 }
 
+// ExportToFolded exports CPU usage per process ancestry in folded-stack format
+// Each line is "root;child;grandchild value", ready for flamegraph.pl or speedscope;
+// values are CPU usage in hundredths of a percent since flame graph tools expect integers
+func (exporter *ProcessMonitorExporter) ExportToFolded(data *ProcessMonitorData, moduleName string) (string, error) {
+	if err := redact.Copy(&data); err != nil {
+		return "", err
+	}
+
+	// Create logs directory if it doesn't exist
+	if err := os.MkdirAll(exporter.LogsDirectory, 0755); err != nil {
+		return "", fmt.Errorf("failed to create logs directory: %w", err)
+	}
+
+	// Create subdirectory for the module if enabled
+	var targetDir string
+	if exporter.CreateSubDirs {
+		targetDir = filepath.Join(exporter.LogsDirectory, moduleName)
+		if err := os.MkdirAll(targetDir, 0755); err != nil {
+			return "", fmt.Errorf("failed to create module directory: %w", err)
+		}
+	} else {
+		targetDir = exporter.LogsDirectory
+	}
+
+	// Generate filename with timestamp
+	timestamp := time.Now().Format("2006-01-02_15-04-05")
+	filename := fmt.Sprintf("%s_%s.folded", moduleName, timestamp)
+	filePath := filepath.Join(targetDir, filename)
+
+	// Create folded content
+	var content string
+	for _, sample := range data.CPUStacks {
+		value := int64(sample.CPUUsage * 100)
+		if value <= 0 {
+			continue
+		}
+
+		// Semicolons and spaces are separators in the folded format
+		frames := make([]string, len(sample.Stack))
+		for i, frame := range sample.Stack {
+			frames[i] = strings.NewReplacer(";", "_", " ", "_").Replace(frame)
+		}
+		content += fmt.Sprintf("%s %d\n", strings.Join(frames, ";"), value)
+	}
+
+	// Write to file
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		return "", fmt.Errorf("failed to write folded file: %w", err)
+	}
+
+	return filePath, nil
+}
+
 // generateCSVContent creates CSV content from process monitoring data
 func (exporter *ProcessMonitorExporter) generateCSVContent(data *ProcessMonitorData) string {
 	var content string
 
+	// Metadata rows
+	content += exporter.Metadata(exportschema.Process, true)
+
 	// Header
-	content += "Timestamp,Total Processes,Running,Sleeping,Zombie,Stopped,Total CPU,Total Memory,Total Threads,Process Status\n"
+	content += exporter.CSVHeader("Timestamp,Total Processes,Running,Sleeping,Zombie,Stopped,Total CPU,Total Memory,Total Threads,Process Status", "timestamp,total_processes,running_processes,sleeping_processes,zombie_processes,stopped_processes,total_cpu_usage,total_memory_usage,total_threads,process_status")
 
 	// Data row
 	content += fmt.Sprintf("%s,%d,%d,%d,%d,%d,%.2f,%.2f,%d,%s\n",
@@ -168,33 +284,149 @@ func (exporter *ProcessMonitorExporter) generateCSVContent(data *ProcessMonitorD
 
 	// Process data
 	if len(data.ProcessInfos) > 0 {
-		content += "\nProcess Data\n"
-		content += "PID,Name,Status,User,CPU%,Memory%,Threads,Open Files,Priority,Parent PID,Command Line\n"
+		content += exporter.CSVSection("Process Data", "process_infos")
+		content += exporter.CSVHeader("PID,Instance ID,Name,Status,User,CPU%,CPU Time (ms),Start Time,Memory%,Threads,Open Files,Priority,Parent PID,Slice,Cgroup,Command Line", "pid,instance_id,name,status,user,cpu_usage,cpu_time,create_time,memory_usage,threads,open_files,priority,parent_pid,slice,cgroup,command_line")
 		for _, proc := range data.ProcessInfos {
-			content += fmt.Sprintf("%d,%s,%s,%s,%.2f,%.2f,%d,%d,%d,%d,%s\n",
+			content += fmt.Sprintf("%d,%s,%s,%s,%s,%.2f,%d,%s,%.2f,%d,%d,%d,%d,%s,%s,%s\n",
 				proc.PID,
+				proc.InstanceID,
 				proc.Name,
 				proc.Status,
 				proc.User,
 				proc.CPUUsage,
+				proc.CPUTime,
+				exporter.formatCreateTime(proc.CreateTime),
 				proc.MemoryUsage,
 				proc.Threads,
 				proc.OpenFiles,
 				proc.Priority,
 				proc.ParentPID,
+				proc.Slice,
+				proc.Cgroup,
 				proc.CommandLine)
 		}
 	}
 
+	// Respawn loops
+	if len(data.RespawnLoops) > 0 {
+		content += exporter.CSVSection("Respawn Loops", "respawn_loops")
+		content += exporter.CSVHeader("Name,Starts Per Minute,Starts,Exits,Last PID,Parent PID,Parent Name,Command Line", "name,starts_per_minute,starts,exits,last_pid,parent_pid,parent_name,command_line")
+		for _, loop := range data.RespawnLoops {
+			content += fmt.Sprintf("%s,%.2f,%d,%d,%d,%d,%s,%s\n",
+				loop.Name,
+				loop.StartsPerMinute,
+				loop.Starts,
+				loop.Exits,
+				loop.LastPID,
+				loop.ParentPID,
+				loop.ParentName,
+				loop.CommandLine)
+		}
+	}
+
+	// Resource limits
+	if len(data.LimitWarnings) > 0 {
+		content += exporter.CSVSection("Resource Limits", "limit_warnings")
+		content += exporter.CSVHeader("PID,Instance ID,Name,User,Limit,Resource,Used,Soft,Hard,Percent", "pid,instance_id,name,user,limit,resource,used,soft,hard,percent")
+		for _, warning := range data.LimitWarnings {
+			content += fmt.Sprintf("%d,%s,%s,%s,%s,%s,%d,%d,%d,%.2f\n",
+				warning.PID,
+				warning.InstanceID,
+				warning.Name,
+				warning.User,
+				warning.Limit,
+				warning.Resource,
+				warning.Used,
+				warning.Soft,
+				warning.Hard,
+				warning.Percent)
+		}
+	}
+
+	// Watched processes
+	if len(data.Watchdog) > 0 {
+		content += exporter.CSVSection("Watchdog", "watchdog")
+		content += exporter.CSVHeader("Name,Running,PIDs,Down Since,Restart Count,Last Restart,Restart Command,Restart Error", "name,running,pids,down_since,restart_count,last_restart,restart_command,restart_error")
+		for _, watched := range data.Watchdog {
+			content += fmt.Sprintf("%s,%t,%s,%s,%d,%s,%q,%q\n",
+				watched.Name,
+				watched.Running,
+				strings.ReplaceAll(formatPIDs(watched.PIDs), ", ", " "),
+				formatWatchdogTime(watched.DownSince),
+				watched.RestartCount,
+				formatWatchdogTime(watched.LastRestart),
+				watched.RestartCommand,
+				watched.RestartError)
+		}
+	}
+
+	// Namespace, seccomp and container hints
+	if hasSandbox(data.ProcessInfos) {
+		content += exporter.CSVSection("Process Sandbox", "process_infos.sandbox")
+		content += exporter.CSVHeader("PID,Name,Mount Namespace,Network Namespace,PID Namespace,Seccomp,Container", "pid,name,sandbox.mount_namespace,sandbox.network_namespace,sandbox.pid_namespace,sandbox.seccomp,sandbox.container")
+		for _, proc := range data.ProcessInfos {
+			if proc.Sandbox == nil {
+				continue
+			}
+			content += fmt.Sprintf("%d,%s,%t,%t,%t,%s,%s\n",
+				proc.PID,
+				proc.Name,
+				proc.Sandbox.MountNamespace,
+				proc.Sandbox.NetworkNamespace,
+				proc.Sandbox.PIDNamespace,
+				proc.Sandbox.Seccomp,
+				proc.Sandbox.Container)
+		}
+	}
+
+	// Windows-specific process details
+	if hasWindowsDetails(data.ProcessInfos) {
+		content += exporter.CSVSection("Windows Process Details", "process_infos.windows")
+		content += exporter.CSVHeader("PID,Name,Elevated,Services,Window Title", "pid,name,windows.elevated,windows.services,windows.window_title")
+		for _, proc := range data.ProcessInfos {
+			if proc.Windows == nil {
+				continue
+			}
+			elevated := "unknown"
+			if proc.Windows.ElevationKnown {
+				elevated = strconv.FormatBool(proc.Windows.Elevated)
+			}
+			content += fmt.Sprintf("%d,%s,%s,%s,%s\n",
+				proc.PID,
+				proc.Name,
+				elevated,
+				strings.Join(proc.Windows.Services, ";"),
+				proc.Windows.WindowTitle)
+		}
+	}
+
+	// CPU attribution by process family
+	if len(data.CPUAttribution) > 0 {
+		content += exporter.CSVSection("CPU Attribution by Process Family", "cpu_attribution")
+		content += exporter.CSVHeader("Root PID,Family,Processes,Tree CPU%,Self CPU%,Memory%,RSS", "root_pid,name,process_count,total_cpu,self_cpu,total_memory,total_rss")
+		for _, family := range data.CPUAttribution {
+			content += fmt.Sprintf("%d,%s,%d,%.2f,%.2f,%.2f,%d\n",
+				family.RootPID,
+				family.Name,
+				family.ProcessCount,
+				family.TotalCPU,
+				family.SelfCPU,
+				family.TotalMemory,
+				family.TotalRSS)
+		}
+	}
+
 	// Top CPU processes
 	if len(data.TopCPUProcesses) > 0 {
-		content += "\nTop CPU Processes\n"
-		content += "PID,Name,CPU%,Memory%,Threads,Status\n"
+		content += exporter.CSVSection("Top CPU Processes", "top_cpu_processes")
+		content += exporter.CSVHeader("PID,Name,CPU%,CPU Time (ms),Start Time,Memory%,Threads,Status", "pid,name,cpu_usage,cpu_time,create_time,memory_usage,threads,status")
 		for _, proc := range data.TopCPUProcesses {
-			content += fmt.Sprintf("%d,%s,%.2f,%.2f,%d,%s\n",
+			content += fmt.Sprintf("%d,%s,%.2f,%d,%s,%.2f,%d,%s\n",
 				proc.PID,
 				proc.Name,
 				proc.CPUUsage,
+				proc.CPUTime,
+				exporter.formatCreateTime(proc.CreateTime),
 				proc.MemoryUsage,
 				proc.Threads,
 				proc.Status)
@@ -203,8 +435,8 @@ func (exporter *ProcessMonitorExporter) generateCSVContent(data *ProcessMonitorD
 
 	// Top memory processes
 	if len(data.TopMemoryProcesses) > 0 {
-		content += "\nTop Memory Processes\n"
-		content += "PID,Name,CPU%,Memory%,Threads,Status\n"
+		content += exporter.CSVSection("Top Memory Processes", "top_memory_processes")
+		content += exporter.CSVHeader("PID,Name,CPU%,Memory%,Threads,Status", "pid,name,cpu_usage,memory_usage,threads,status")
 		for _, proc := range data.TopMemoryProcesses {
 			content += fmt.Sprintf("%d,%s,%.2f,%.2f,%d,%s\n",
 				proc.PID,
@@ -216,13 +448,46 @@ func (exporter *ProcessMonitorExporter) generateCSVContent(data *ProcessMonitorD
 		}
 	}
 
+	// Top open files processes
+	if len(data.TopOpenFilesProcesses) > 0 {
+		content += exporter.CSVSection("Top Open Files Processes", "top_open_files_processes")
+		content += exporter.CSVHeader("PID,Name,Open Files,Context Switches/s,CPU%,Memory%,User", "pid,name,open_files,context_switch_rate,cpu_usage,memory_usage,user")
+		for _, proc := range data.TopOpenFilesProcesses {
+			content += fmt.Sprintf("%d,%s,%d,%.1f,%.2f,%.2f,%s\n",
+				proc.PID,
+				proc.Name,
+				proc.OpenFiles,
+				proc.ContextSwitchRate,
+				proc.CPUUsage,
+				proc.MemoryUsage,
+				proc.User)
+		}
+	}
+
+	// Top context switch processes
+	if len(data.TopContextSwitchProcesses) > 0 {
+		content += exporter.CSVSection("Top Context Switch Processes", "top_context_switch_processes")
+		content += exporter.CSVHeader("PID,Name,Open Files,Context Switches/s,CPU%,Memory%,User", "pid,name,open_files,context_switch_rate,cpu_usage,memory_usage,user")
+		for _, proc := range data.TopContextSwitchProcesses {
+			content += fmt.Sprintf("%d,%s,%d,%.1f,%.2f,%.2f,%s\n",
+				proc.PID,
+				proc.Name,
+				proc.OpenFiles,
+				proc.ContextSwitchRate,
+				proc.CPUUsage,
+				proc.MemoryUsage,
+				proc.User)
+		}
+	}
+
 	// Process alerts
 	if len(data.ProcessAlerts) > 0 {
-		content += "\nProcess Alerts\n"
-		content += "PID,Name,Alert Type,Severity,Value,Threshold,Timestamp\n"
+		content += exporter.CSVSection("Process Alerts", "process_alerts")
+		content += exporter.CSVHeader("PID,Instance ID,Name,Alert Type,Severity,Value,Threshold,Timestamp", "pid,instance_id,name,alert_type,severity,value,threshold,timestamp")
 		for _, alert := range data.ProcessAlerts {
-			content += fmt.Sprintf("%d,%s,%s,%s,%.2f,%.2f,%s\n",
+			content += fmt.Sprintf("%d,%s,%s,%s,%s,%.2f,%.2f,%s\n",
 				alert.PID,
+				alert.InstanceID,
 				alert.Name,
 				alert.AlertType,
 				alert.Severity,
@@ -239,6 +504,9 @@ func (exporter *ProcessMonitorExporter) generateCSVContent(data *ProcessMonitorD
 func (exporter *ProcessMonitorExporter) generateTXTContent(data *ProcessMonitorData) string {
 	var content string
 
+	// Metadata rows
+	content += exporter.Metadata(exportschema.Process, false)
+
 	// Header
 	content += "PROCESS MONITOR REPORT\n"
 	content += "=====================\n\n"
@@ -246,6 +514,16 @@ func (exporter *ProcessMonitorExporter) generateTXTContent(data *ProcessMonitorD
 	// Timestamp
 	content += fmt.Sprintf("Generated: %s\n\n", data.Timestamp.Format("2006-01-02 15:04:05"))
 
+	// Sections that could not be collected
+	if len(data.SectionErrors) > 0 {
+		content += "UNAVAILABLE SECTIONS\n"
+		content += "--------------------\n"
+		for _, section := range data.SectionErrors.Sections() {
+			content += fmt.Sprintf("%s: %s\n", section, data.SectionErrors[section])
+		}
+		content += "\n"
+	}
+
 	// Process summary
 	content += "PROCESS SUMMARY\n"
 	content += "---------------\n"
@@ -264,14 +542,16 @@ func (exporter *ProcessMonitorExporter) generateTXTContent(data *ProcessMonitorD
 	if len(data.TopCPUProcesses) > 0 {
 		content += "TOP CPU PROCESSES\n"
 		content += "-----------------\n"
-		content += "PID\tName\t\t\tCPU%\tMemory%\tThreads\tStatus\n"
-		content += "---\t----\t\t\t----\t-------\t-------\t------\n"
+		content += "PID\tName\t\t\tCPU%\tTIME+\t\tStarted\t\t\tMemory%\tThreads\tStatus\n"
+		content += "---\t----\t\t\t----\t-----\t\t-------\t\t\t-------\t-------\t------\n"
 
 		for _, proc := range data.TopCPUProcesses {
-			content += fmt.Sprintf("%d\t%-20s\t%.2f\t%.2f\t%d\t%s\n",
+			content += fmt.Sprintf("%d\t%-20s\t%.2f\t%-10s\t%-15s\t%.2f\t%d\t%s\n",
 				proc.PID,
 				proc.Name,
 				proc.CPUUsage,
+				formatCPUTime(proc.CPUTime),
+				formatStarted(proc.CreateTime, data.Timestamp),
 				proc.MemoryUsage,
 				proc.Threads,
 				proc.Status)
@@ -336,6 +616,20 @@ func (exporter *ProcessMonitorExporter) generateTXTContent(data *ProcessMonitorD
 		content += "\n"
 	}
 
+	// Top open files processes
+	if len(data.TopOpenFilesProcesses) > 0 {
+		content += "TOP OPEN FILES PROCESSES\n"
+		content += "------------------------\n"
+		content += exporter.activityTable(data.TopOpenFilesProcesses)
+	}
+
+	// Top context switch processes
+	if len(data.TopContextSwitchProcesses) > 0 {
+		content += "TOP CONTEXT SWITCH PROCESSES\n"
+		content += "----------------------------\n"
+		content += exporter.activityTable(data.TopContextSwitchProcesses)
+	}
+
 	// Process tree
 	if len(data.ProcessTree) > 0 {
 		content += "PROCESS TREE\n"
@@ -344,6 +638,118 @@ func (exporter *ProcessMonitorExporter) generateTXTContent(data *ProcessMonitorD
 		content += "\n"
 	}
 
+	// CPU attribution by process family
+	if len(data.CPUAttribution) > 0 {
+		content += "CPU BY PROCESS FAMILY\n"
+		content += "---------------------\n"
+		content += "Root PID\tFamily\t\t\tProcs\tTree CPU%\tSelf CPU%\n"
+		content += "--------\t------\t\t\t-----\t---------\t---------\n"
+
+		for _, family := range data.CPUAttribution {
+			content += fmt.Sprintf("%d\t\t%-20s\t%d\t%.2f\t\t%.2f\n",
+				family.RootPID,
+				family.Name,
+				family.ProcessCount,
+				family.TotalCPU,
+				family.SelfCPU)
+		}
+		content += "\n"
+	}
+
+	// Respawn loops
+	if len(data.RespawnLoops) > 0 {
+		content += "RESPAWN LOOPS\n"
+		content += "-------------\n"
+		for _, loop := range data.RespawnLoops {
+			content += fmt.Sprintf("%-20s\t%.1f starts/min (%d starts, %d exits)\tLast PID %d\tParent: %s (PID %d)\n",
+				loop.Name,
+				loop.StartsPerMinute,
+				loop.Starts,
+				loop.Exits,
+				loop.LastPID,
+				loop.ParentName,
+				loop.ParentPID)
+			if loop.CommandLine != "" {
+				content += fmt.Sprintf("  %s\n", loop.CommandLine)
+			}
+		}
+		content += "\n"
+	}
+
+	// Resource limits
+	if len(data.LimitWarnings) > 0 {
+		content += "RESOURCE LIMITS\n"
+		content += "---------------\n"
+		for _, warning := range data.LimitWarnings {
+			content += fmt.Sprintf("%-20s\tPID %d\t%.1f%% of %s (%s): %s (hard limit %s)\n",
+				warning.Name,
+				warning.PID,
+				warning.Percent,
+				warning.Limit,
+				warning.Resource,
+				describeLimitUsage(warning),
+				formatLimit(warning.Resource, warning.Hard))
+		}
+		content += "\n"
+	}
+
+	// Watched processes
+	if len(data.Watchdog) > 0 {
+		content += "WATCHDOG\n"
+		content += "--------\n"
+		for _, watched := range data.Watchdog {
+			status := "RUNNING (PID " + formatPIDs(watched.PIDs) + ")"
+			if !watched.Running {
+				status = "DOWN since " + formatWatchdogTime(watched.DownSince)
+			}
+			content += fmt.Sprintf("%-20s\t%s\tRestarts: %d\n", watched.Name, status, watched.RestartCount)
+			if watched.RestartError != "" {
+				content += fmt.Sprintf("  %s\n", watched.RestartError)
+			}
+		}
+		content += "\n"
+	}
+
+	// Namespace, seccomp and container hints
+	if hasSandbox(data.ProcessInfos) {
+		content += "PROCESS SANDBOX\n"
+		content += "---------------\n"
+		for _, proc := range data.ProcessInfos {
+			if proc.Sandbox == nil {
+				continue
+			}
+			flags := sandboxFlags(proc.Sandbox)
+			if flags == "" {
+				flags = "none"
+			}
+			content += fmt.Sprintf("%d\t%-20s\t%s\n", proc.PID, proc.Name, flags)
+		}
+		content += "\n"
+	}
+
+	// Windows-specific process details
+	if hasWindowsDetails(data.ProcessInfos) {
+		content += "WINDOWS PROCESS DETAILS\n"
+		content += "-----------------------\n"
+		for _, proc := range data.ProcessInfos {
+			if proc.Windows == nil {
+				continue
+			}
+			content += fmt.Sprintf("%d\t%-20s", proc.PID, proc.Name)
+			if proc.Windows.ElevationKnown && proc.Windows.Elevated {
+				content += "\t[Administrator]"
+			}
+			if len(proc.Windows.Services) > 0 {
+				content += fmt.Sprintf("\tServices: %s", strings.Join(proc.Windows.Services, ", "))
+			}
+			if proc.Windows.WindowTitle != "" {
+				content += fmt.Sprintf("\tWindow: %s", proc.Windows.WindowTitle)
+			}
+			content += "\n"
+		}
+		content += "\n"
+	}
+
 	// Process alerts
 	if len(data.ProcessAlerts) > 0 {
 		content += "PROCESS ALERTS\n"
@@ -363,6 +769,23 @@ func (exporter *ProcessMonitorExporter) generateTXTContent(data *ProcessMonitorD
 		content += "\n"
 	}
 
+	// Error logs for alerting processes
+	if len(data.ProcessLogs) > 0 {
+		content += "RECENT ERRORS FOR ALERTING PROCESSES\n"
+		content += "------------------------------------\n"
+
+		for _, info := range data.ProcessLogs {
+			content += fmt.Sprintf("%s (PID %d) [%s]\n", info.Name, info.PID, info.Unit)
+			if info.Error != "" {
+				content += fmt.Sprintf("  Error: %s\n", info.Error)
+			}
+			for _, line := range info.Lines {
+				content += fmt.Sprintf("  %s %s\n", line.Timestamp.Format("2006-01-02 15:04:05"), line.Message)
+			}
+		}
+		content += "\n"
+	}
+
 	// Alerts
 	content += "ALERTS & WARNINGS\n"
 	content += "-----------------\n"
@@ -376,6 +799,22 @@ func (exporter *ProcessMonitorExporter) generateTXTContent(data *ProcessMonitorD
 	return content
 }
 
+// activityTable formats processes ranked by open files or context switches for the text export
+func (exporter *ProcessMonitorExporter) activityTable(processes []ProcessInfo) string {
+	content := "PID\tName\t\t\tOpen Files\tCtx Sw/s\tCPU%\tMemory%\n"
+	content += "---\t----\t\t\t----------\t--------\t----\t-------\n"
+	for _, proc := range processes {
+		content += fmt.Sprintf("%d\t%-20s\t%d\t\t%.0f\t\t%.2f\t%.2f\n",
+			proc.PID,
+			proc.Name,
+			proc.OpenFiles,
+			proc.ContextSwitchRate,
+			proc.CPUUsage,
+			proc.MemoryUsage)
+	}
+	return content + "\n"
+}
+
 // addTreeToContent recursively adds process tree to content
 func (exporter *ProcessMonitorExporter) addTreeToContent(tree []ProcessTreeInfo, content *string, level int) {
 	for _, node := range tree {
@@ -401,6 +840,34 @@ func (exporter *ProcessMonitorExporter) addTreeToContent(tree []ProcessTreeInfo,
 	}
 }
 
+// hasWindowsDetails reports whether any process carries Windows-specific details
+func hasWindowsDetails(processes []ProcessInfo) bool {
+	for _, proc := range processes {
+		if proc.Windows != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// hasSandbox reports whether any process carries sandbox hints
+func hasSandbox(processes []ProcessInfo) bool {
+	for _, proc := range processes {
+		if proc.Sandbox != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// formatCreateTime formats a start time in milliseconds since the epoch, empty when unknown
+func (exporter *ProcessMonitorExporter) formatCreateTime(createTime int64) string {
+	if createTime <= 0 {
+		return ""
+	}
+	return time.UnixMilli(createTime).Format("2006-01-02 15:04:05")
+}
+
 // SetLogsDirectory sets the logs directory
 func (exporter *ProcessMonitorExporter) SetLogsDirectory(dir string) {
 	exporter.LogsDirectory = dir
@@ -415,3 +882,13 @@ func (exporter *ProcessMonitorExporter) SetPrettyPrint(pretty bool) {
 func (exporter *ProcessMonitorExporter) SetCreateSubDirs(create bool) {
 	exporter.CreateSubDirs = create
 }
+
+// SetStableKeys sets whether CSV headers use stable snake_case keys instead of prose
+func (exporter *ProcessMonitorExporter) SetStableKeys(stable bool) {
+	exporter.StableKeys = stable
+}
+
+// SetHeaderMetadata sets whether exports start with metadata rows
+func (exporter *ProcessMonitorExporter) SetHeaderMetadata(metadata bool) {
+	exporter.HeaderMetadata = metadata
+}
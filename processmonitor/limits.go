@@ -0,0 +1,175 @@
+package processmonitor
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/ahmadreza-log/simple-monitor/procid"
+	"github.com/ahmadreza-log/simple-monitor/provider"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// limitRefreshInterval is how often a process's limits are re-read; prlimit can change them while it runs
+const limitRefreshInterval = 1 * time.Minute
+
+// limitSample is the resource limits of a process at one read
+type limitSample struct {
+	limits []process.RlimitStat
+	time   time.Time
+}
+
+// checkedLimit names one resource limit that is compared with current usage
+type checkedLimit struct {
+	limit    string // Name in /proc/<pid>/limits
+	resource string // Name used by ulimit and setrlimit
+}
+
+// checkedLimits are the limits usage can be read for; the rest (stack, locked memory, signals) are left out
+var checkedLimits = map[int32]checkedLimit{
+	process.RLIMIT_NOFILE: {limit: "Max open files", resource: "nofile"},
+	process.RLIMIT_NPROC:  {limit: "Max processes", resource: "nproc"},
+	process.RLIMIT_AS:     {limit: "Max address space", resource: "as"},
+}
+
+// collectLimitWarnings compares each process's open files, threads and address space with its own soft limits
+// and reports the ones above LimitWarningPercent, so a process hitting its ulimits is seen before it fails.
+// Max processes counts every thread of the owning user, so it is reported once per user, on the process
+// with the lowest limit. Root is left out of that check because the kernel does not enforce it for root.
+// Every process is checked, regardless of the display filters; limits are only readable on Linux.
+func (collector *ProcessMonitorCollector) collectLimitWarnings(data *ProcessMonitorData, processes []ProcessInfo, handles map[int32]provider.Process) {
+	threshold := collector.config.LimitWarningPercent
+	now := time.Now()
+
+	userThreads := make(map[string]uint64)
+	for _, proc := range processes {
+		userThreads[proc.User] += uint64(proc.Threads)
+	}
+
+	var warnings []ProcessLimitInfo
+	userWarnings := make(map[string]ProcessLimitInfo)
+	current := make(map[procid.Key]bool, len(processes))
+	for _, proc := range processes {
+		handle, ok := handles[proc.PID]
+		if !ok {
+			continue
+		}
+		key := procid.New(proc.PID, proc.CreateTime)
+		current[key] = true
+
+		for _, limit := range collector.readLimits(key, handle, now) {
+			checked, ok := checkedLimits[limit.Resource]
+			if !ok || limit.Soft == 0 || limit.Soft == math.MaxUint64 {
+				continue // Unlimited
+			}
+
+			var used uint64
+			switch limit.Resource {
+			case process.RLIMIT_NOFILE:
+				used = uint64(proc.OpenFiles)
+			case process.RLIMIT_AS:
+				used = proc.MemoryVMS
+			case process.RLIMIT_NPROC:
+				if proc.User == "" || proc.User == "root" {
+					continue
+				}
+				used = userThreads[proc.User]
+			}
+
+			percent := float64(used) / float64(limit.Soft) * 100
+			if percent < threshold {
+				continue
+			}
+
+			warning := ProcessLimitInfo{
+				PID:        proc.PID,
+				InstanceID: proc.InstanceID,
+				Name:       proc.Name,
+				User:       proc.User,
+				Limit:      checked.limit,
+				Resource:   checked.resource,
+				Used:       used,
+				Soft:       limit.Soft,
+				Hard:       limit.Hard,
+				Percent:    percent,
+			}
+			if limit.Resource == process.RLIMIT_NPROC {
+				if previous, seen := userWarnings[proc.User]; !seen || percent > previous.Percent {
+					userWarnings[proc.User] = warning
+				}
+				continue
+			}
+			warnings = append(warnings, warning)
+		}
+	}
+	for _, warning := range userWarnings {
+		warnings = append(warnings, warning)
+	}
+
+	// Forget processes that exited
+	for key := range collector.limits {
+		if !current[key] {
+			delete(collector.limits, key)
+		}
+	}
+
+	sort.Slice(warnings, func(i, j int) bool {
+		if warnings[i].Percent != warnings[j].Percent {
+			return warnings[i].Percent > warnings[j].Percent
+		}
+		return warnings[i].PID < warnings[j].PID
+	})
+
+	data.LimitWarnings = warnings
+}
+
+// readLimits returns the cached limits of a process instance, re-reading them when they are stale
+// Processes whose limits cannot be read are cached with none, so they are not retried every refresh
+func (collector *ProcessMonitorCollector) readLimits(key procid.Key, handle provider.Process, now time.Time) []process.RlimitStat {
+	sample, ok := collector.limits[key]
+	if ok && now.Sub(sample.time) < limitRefreshInterval {
+		return sample.limits
+	}
+
+	limits, err := handle.Rlimit()
+	if err != nil {
+		limits = nil
+	}
+	collector.limits[key] = limitSample{limits: limits, time: now}
+	return limits
+}
+
+// limitSeverity grades a limit warning; at the limit itself further opens, forks or allocations fail
+func limitSeverity(percent float64) string {
+	switch {
+	case percent >= 100:
+		return "Critical"
+	case percent >= 95:
+		return "High"
+	default:
+		return "Medium"
+	}
+}
+
+// describeLimitUsage formats the usage and soft limit of a warning
+func describeLimitUsage(warning ProcessLimitInfo) string {
+	usage := formatLimit(warning.Resource, warning.Used) + " of " + formatLimit(warning.Resource, warning.Soft)
+	if warning.Resource == "nproc" {
+		usage += " processes and threads owned by " + warning.User
+	}
+	return usage
+}
+
+// formatLimit formats a limit or its usage, in GiB for the address space
+func formatLimit(resource string, value uint64) string {
+	switch {
+	case value == math.MaxUint64:
+		return "unlimited"
+	case resource == "as":
+		return fmt.Sprintf("%.1f GiB", float64(value)/(1<<30))
+	default:
+		return fmt.Sprintf("%d", value)
+	}
+}
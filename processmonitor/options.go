@@ -0,0 +1,50 @@
+package processmonitor
+
+import "time"
+
+// Option configures a ProcessMonitorManager as NewProcessMonitorManager creates it
+// Options are applied before the manager is returned, so nothing can be collecting while they change its settings
+type Option func(*ProcessMonitorManager)
+
+// WithRefreshInterval sets how often live monitoring and Subscribe collect; an interval of zero or less is ignored
+func WithRefreshInterval(interval time.Duration) Option {
+	return func(manager *ProcessMonitorManager) {
+		if interval > 0 {
+			manager.collector.changeConfig(func(config *ProcessMonitorConfig) {
+				config.RefreshInterval = interval
+			})
+		}
+	}
+}
+
+// WithExporter replaces the default exporter, e.g. to write exports under another logs directory
+// A nil exporter keeps the default
+func WithExporter(exporter *ProcessMonitorExporter) Option {
+	return func(manager *ProcessMonitorManager) {
+		if exporter != nil {
+			manager.exporter = exporter
+		}
+	}
+}
+
+// WithThresholds sets the CPU and memory usage percentages at which a process raises an alert
+func WithThresholds(cpu, memory float64) Option {
+	return func(manager *ProcessMonitorManager) {
+		manager.collector.changeConfig(func(config *ProcessMonitorConfig) {
+			config.HighCPUThreshold = cpu
+			config.HighMemoryThreshold = memory
+		})
+	}
+}
+
+// WithFilters shows only processes named exactly name, in the given status and owned by one of users
+// An empty name or status, or no users, leaves that filter off
+func WithFilters(name, status string, users ...string) Option {
+	return func(manager *ProcessMonitorManager) {
+		manager.collector.changeConfig(func(config *ProcessMonitorConfig) {
+			config.ProcessNameFilter = name
+			config.StatusFilter = status
+			config.UserFilter = append([]string{}, users...)
+		})
+	}
+}
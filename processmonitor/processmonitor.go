@@ -1,9 +1,24 @@
+// Package processmonitor monitors processes: top CPU, memory and I/O users, threads, zombies, limits,
+// cgroups and a restart watchdog
+// A ProcessMonitorManager collects once with Collect or in the background with Subscribe
 package processmonitor
 
 import (
+	"encoding/json"
 	"fmt"
+	"github.com/ahmadreza-log/simple-monitor/alert"
+	"github.com/ahmadreza-log/simple-monitor/historystore"
+	"github.com/ahmadreza-log/simple-monitor/hooks"
+	"github.com/ahmadreza-log/simple-monitor/idle"
+	"github.com/ahmadreza-log/simple-monitor/recording"
+	"github.com/ahmadreza-log/simple-monitor/terminal"
+	"maps"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 )
@@ -21,18 +36,37 @@ type ProcessMonitorManager struct {
 	stopChannel    chan bool
 	refreshTicker  *time.Ticker
 	lastExportTime time.Time
+
+	// Extra line shown under the live display (e.g. key shortcuts)
+	liveHint string
+
+	// Pause and step control (the display stays frozen while paused)
+	paused      atomic.Bool
+	stepChannel chan bool
+
+	// Session recorder (nil unless the live session is being recorded)
+	recorder *recording.Recorder
+
+	// Idle detection (slows refreshes while nobody is using the machine)
+	idleDetector *idle.Detector
 }
 
 // NewProcessMonitorManager creates a new instance of ProcessMonitorManager
-// with default collector, displayer, and exporter configurations
-func NewProcessMonitorManager() *ProcessMonitorManager {
-	return &ProcessMonitorManager{
-		collector:   NewProcessMonitorCollector(),
-		displayer:   NewProcessMonitorDisplayer(),
-		exporter:    NewProcessMonitorExporter(),
-		isRunning:   false,
-		stopChannel: make(chan bool, 1),
+// with default collector, displayer, and exporter configurations, then applies the options in order
+func NewProcessMonitorManager(options ...Option) *ProcessMonitorManager {
+	manager := &ProcessMonitorManager{
+		collector:    NewProcessMonitorCollector(),
+		displayer:    NewProcessMonitorDisplayer(),
+		exporter:     NewProcessMonitorExporter(),
+		idleDetector: idle.NewDetector(),
+		isRunning:    false,
+		stopChannel:  make(chan bool, 1),
+		stepChannel:  make(chan bool, 1),
 	}
+	for _, option := range options {
+		option(manager)
+	}
+	return manager
 }
 
 // StartLiveMonitoring starts live process monitoring with real-time updates
@@ -43,7 +77,7 @@ func (manager *ProcessMonitorManager) StartLiveMonitoring() error {
 	}
 
 	manager.isRunning = true
-	manager.refreshTicker = time.NewTicker(manager.collector.config.RefreshInterval)
+	manager.refreshTicker = time.NewTicker(manager.collector.currentConfig().RefreshInterval)
 
 	// Set up signal handling for graceful shutdown
 	sigChan := make(chan os.Signal, 1)
@@ -53,12 +87,19 @@ func (manager *ProcessMonitorManager) StartLiveMonitoring() error {
 	fmt.Println("Press Ctrl+C to stop monitoring")
 
 	// Start monitoring loop
+	loopDone := make(chan bool)
+	loopExited := make(chan bool)
 	go func() {
+		defer close(loopExited)
 		for {
 			select {
 			case <-manager.refreshTicker.C:
+				if !manager.paused.Load() {
+					manager.updateAndDisplay()
+				}
+			case <-manager.stepChannel:
 				manager.updateAndDisplay()
-			case <-manager.stopChannel:
+			case <-loopDone:
 				return
 			case <-sigChan:
 				manager.StopMonitoring()
@@ -68,7 +109,12 @@ func (manager *ProcessMonitorManager) StartLiveMonitoring() error {
 	}()
 
 	// Wait for stop signal
+	// The loop is stopped separately so StopMonitoring can also be called from outside the loop,
+	// and a refresh still in progress finishes before returning
 	<-manager.stopChannel
+	close(loopDone)
+	<-loopExited
+	signal.Stop(sigChan)
 	return nil
 }
 
@@ -90,12 +136,28 @@ func (manager *ProcessMonitorManager) StartSingleSnapshot() error {
 	if err != nil {
 		fmt.Printf("⚠️  Warning: Failed to export data: %v\n", err)
 	} else {
+		hooks.Exported("process", filePath)
 		fmt.Printf("\n💾 Process data saved to: %s\n", filePath)
 	}
 
 	return nil
 }
 
+// GetAncestry returns the parent chain of a process, the process itself first
+func (manager *ProcessMonitorManager) GetAncestry(pid int32) ([]AncestorInfo, error) {
+	return manager.collector.TraceAncestry(pid)
+}
+
+// TraceAncestry displays the parent chain of a process up to init/systemd
+func (manager *ProcessMonitorManager) TraceAncestry(pid int32) error {
+	chain, err := manager.collector.TraceAncestry(pid)
+	if err != nil {
+		return fmt.Errorf("failed to trace ancestry: %w", err)
+	}
+	manager.displayer.DisplayAncestry(chain)
+	return nil
+}
+
 // StopMonitoring stops the live monitoring
 func (manager *ProcessMonitorManager) StopMonitoring() {
 	if !manager.isRunning {
@@ -108,6 +170,9 @@ func (manager *ProcessMonitorManager) StopMonitoring() {
 		manager.refreshTicker.Stop()
 	}
 
+	// Keep everything collected in this session
+	manager.persistHistory(true)
+
 	select {
 	case manager.stopChannel <- true:
 	default:
@@ -118,6 +183,12 @@ func (manager *ProcessMonitorManager) StopMonitoring() {
 
 // updateAndDisplay collects new data and updates the display
 func (manager *ProcessMonitorManager) updateAndDisplay() {
+	// Refresh less often and pause process scans while the machine is idle
+	if !manager.idleDetector.ShouldCollect() {
+		return
+	}
+	manager.collector.SetIdle(manager.idleDetector.IsIdle())
+
 	// Collect new process data
 	data, err := manager.collector.CollectProcessMonitorData()
 	if err != nil {
@@ -128,6 +199,26 @@ func (manager *ProcessMonitorManager) updateAndDisplay() {
 
 	// Display updated data
 	manager.displayer.DisplayProcessMonitorData(data)
+	if status := manager.idleDetector.Status(); status != "" {
+		fmt.Println("\n" + status)
+	}
+	if status := alert.Status(); status != "" {
+		fmt.Println("\n" + status)
+	}
+	if manager.liveHint != "" {
+		fmt.Println(manager.liveHint)
+	}
+	if manager.paused.Load() {
+		fmt.Println(terminal.PausedNotice)
+	}
+
+	// Capture the cycle for replay
+	if err := manager.recorder.Record("process", data); err != nil {
+		fmt.Printf("\n⚠️  %v\n", err)
+	}
+
+	// Save the long-term history
+	manager.persistHistory(false)
 
 	// Export data based on export interval
 	manager.exportDataIfNeeded(data)
@@ -136,13 +227,13 @@ func (manager *ProcessMonitorManager) updateAndDisplay() {
 // exportDataIfNeeded exports process data to file based on export interval
 func (manager *ProcessMonitorManager) exportDataIfNeeded(data *ProcessMonitorData) {
 	// Check if export is enabled
-	if !manager.collector.config.ExportToFile {
+	if !manager.collector.currentConfig().ExportToFile {
 		return
 	}
 
 	// Check if it's time to export based on export interval
 	now := time.Now()
-	if manager.lastExportTime.IsZero() || now.Sub(manager.lastExportTime) >= manager.collector.config.ExportInterval {
+	if manager.lastExportTime.IsZero() || now.Sub(manager.lastExportTime) >= manager.collector.currentConfig().ExportInterval {
 		manager.exportData(data)
 		manager.lastExportTime = now
 	}
@@ -150,20 +241,55 @@ func (manager *ProcessMonitorManager) exportDataIfNeeded(data *ProcessMonitorDat
 
 // exportData exports process data to file
 func (manager *ProcessMonitorManager) exportData(data *ProcessMonitorData) {
-	filePath, err := manager.exporter.ExportToJSON(data, "processmonitor")
+	export := manager.exporter.ExportToJSON
+	if manager.collector.currentConfig().ExportFormat == "protobuf" {
+		export = manager.exporter.ExportToProtobuf
+	}
+	filePath, err := export(data, "processmonitor")
 	if err != nil {
 		// Don't display error for every export to avoid cluttering the display
 		return
 	}
 
+	hooks.Exported("process", filePath)
 	fmt.Printf("\n💾 Data exported to: %s\n", filePath)
 }
 
-// GetProcessUsageHistory returns the current process usage history
+// GetProcessUsageHistory returns a copy of the process usage history
 func (manager *ProcessMonitorManager) GetProcessUsageHistory() *ProcessUsageHistory {
 	return manager.collector.GetProcessUsageHistory()
 }
 
+// GetLongTermHistory returns the downsampled long-term history
+func (manager *ProcessMonitorManager) GetLongTermHistory() *historystore.Store {
+	return manager.collector.GetLongTermHistory()
+}
+
+// HistoryMemoryEstimate estimates the memory an in-memory history of samples uses
+func (manager *ProcessMonitorManager) HistoryMemoryEstimate(samples int) uint64 {
+	return manager.collector.HistoryMemoryEstimate(samples)
+}
+
+// persistHistory writes the long-term history to logs/history
+// Unless forced (when monitoring stops), the file is rewritten at most once per save interval
+func (manager *ProcessMonitorManager) persistHistory(force bool) {
+	config := manager.collector.currentConfig()
+	// Synthetic history must not end up in the real history file
+	if !config.PersistHistory || manager.collector.IsSimulated() {
+		return
+	}
+
+	interval := config.HistorySaveInterval
+	if force {
+		interval = 0
+	}
+
+	path := filepath.Join(manager.exporter.LogsDirectory, "history", "processmonitor.json")
+
+	// Errors are ignored to avoid cluttering the display; the next save retries
+	manager.collector.GetLongTermHistory().Persist(path, interval)
+}
+
 // GetConfig returns the current configuration
 func (manager *ProcessMonitorManager) GetConfig() *ProcessMonitorConfig {
 	return manager.collector.GetConfig()
@@ -189,6 +315,109 @@ func (manager *ProcessMonitorManager) SetExportOptions(logsDir string, prettyPri
 	manager.exporter.SetCreateSubDirs(createSubDirs)
 }
 
+// SetHeaderOptions configures CSV header keys and export metadata rows
+func (manager *ProcessMonitorManager) SetHeaderOptions(stableKeys, headerMetadata bool) {
+	manager.exporter.SetStableKeys(stableKeys)
+	manager.exporter.SetHeaderMetadata(headerMetadata)
+}
+
+// SetIdleMode enables or disables idle mode
+// While idle, refreshes are slowed down and process scans are paused until activity resumes
+func (manager *ProcessMonitorManager) SetIdleMode(enabled bool) {
+	manager.idleDetector.SetEnabled(enabled)
+	if !enabled {
+		manager.collector.SetIdle(false)
+	}
+}
+
+// GetUserProcessCounts lists every user owning processes and whether the user filter shows them
+func (manager *ProcessMonitorManager) GetUserProcessCounts() ([]FilterOption, error) {
+	return manager.collector.CollectUserCounts()
+}
+
+// ToggleUserFilter adds user to the user filter, or removes it when already selected
+// An empty filter shows the processes of all users
+func (manager *ProcessMonitorManager) ToggleUserFilter(user string) {
+	manager.collector.changeConfig(func(config *ProcessMonitorConfig) {
+		config.UserFilter = toggleValue(config.UserFilter, user)
+	})
+}
+
+// ClearUserFilter shows the processes of all users again
+func (manager *ProcessMonitorManager) ClearUserFilter() {
+	manager.collector.changeConfig(func(config *ProcessMonitorConfig) {
+		config.UserFilter = []string{}
+	})
+}
+
+// GetSliceProcessCounts lists every slice with processes and whether the slice filter shows them
+func (manager *ProcessMonitorManager) GetSliceProcessCounts() ([]FilterOption, error) {
+	return manager.collector.CollectSliceCounts()
+}
+
+// ToggleSliceFilter adds slice to the slice filter, or removes it when already selected
+// An empty filter shows the processes of all slices
+func (manager *ProcessMonitorManager) ToggleSliceFilter(slice string) {
+	manager.collector.changeConfig(func(config *ProcessMonitorConfig) {
+		config.SliceFilter = toggleValue(config.SliceFilter, slice)
+	})
+}
+
+// ClearSliceFilter shows the processes of all slices again
+func (manager *ProcessMonitorManager) ClearSliceFilter() {
+	manager.collector.changeConfig(func(config *ProcessMonitorConfig) {
+		config.SliceFilter = []string{}
+	})
+}
+
+// GetWatchdog returns the watched process names with their restart rules
+func (manager *ProcessMonitorManager) GetWatchdog() map[string]WatchdogRule {
+	return maps.Clone(manager.collector.currentConfig().Watchdog)
+}
+
+// SetWatchdog watches a process by name; restartCommand is run through the shell when it is missing (empty to only alert)
+func (manager *ProcessMonitorManager) SetWatchdog(name, restartCommand string) {
+	manager.collector.SetWatchdog(name, restartCommand)
+}
+
+// RemoveWatchdog stops watching a process
+func (manager *ProcessMonitorManager) RemoveWatchdog(name string) {
+	manager.collector.RemoveWatchdog(name)
+}
+
+// toggleValue returns values with value removed when present, or added (keeping the list sorted) when not
+func toggleValue(values []string, value string) []string {
+	for i, selected := range values {
+		if selected == value {
+			return append(values[:i:i], values[i+1:]...)
+		}
+	}
+	values = append(values, value)
+	sort.Strings(values)
+	return values
+}
+
+// SetSimulationMode switches the monitor to synthetic data (--simulate)
+// Simulated data needs no live system or elevated permissions, which suits demos and displayer development
+func (manager *ProcessMonitorManager) SetSimulationMode(enabled bool) {
+	manager.collector.SetSimulated(enabled)
+}
+
+// IsSimulated returns whether the monitor shows synthetic data
+func (manager *ProcessMonitorManager) IsSimulated() bool {
+	return manager.collector.IsSimulated()
+}
+
+// GetIdleState returns the most recent idle check
+func (manager *ProcessMonitorManager) GetIdleState() idle.State {
+	return manager.idleDetector.State()
+}
+
+// GetIdleConfig returns the idle detection configuration
+func (manager *ProcessMonitorManager) GetIdleConfig() idle.Config {
+	return manager.idleDetector.GetConfig()
+}
+
 // ExportToFile exports current process data to a file
 func (manager *ProcessMonitorManager) ExportToFile(format string) error {
 	// Collect current data
@@ -202,10 +431,14 @@ func (manager *ProcessMonitorManager) ExportToFile(format string) error {
 	switch format {
 	case "json":
 		filePath, err = manager.exporter.ExportToJSON(data, "processmonitor")
+	case "protobuf":
+		filePath, err = manager.exporter.ExportToProtobuf(data, "processmonitor")
 	case "csv":
 		filePath, err = manager.exporter.ExportToCSV(data, "processmonitor")
 	case "txt":
 		filePath, err = manager.exporter.ExportToTXT(data, "processmonitor")
+	case "folded":
+		filePath, err = manager.exporter.ExportToFolded(data, "processmonitor")
 	default:
 		return fmt.Errorf("unsupported export format: %s", format)
 	}
@@ -214,6 +447,7 @@ func (manager *ProcessMonitorManager) ExportToFile(format string) error {
 		return fmt.Errorf("failed to export data: %w", err)
 	}
 
+	hooks.Exported("process", filePath)
 	fmt.Printf("💾 Process data exported to: %s\n", filePath)
 	return nil
 }
@@ -238,7 +472,87 @@ func (manager *ProcessMonitorManager) GetProcessAlerts() (bool, bool, bool, bool
 	return data.HighCPUWarning, data.HighMemoryWarning, data.HighIOWarning, data.ZombieWarning, data.ThreadWarning, nil
 }
 
+// Collect collects the process data once, without displaying or exporting it
+func (manager *ProcessMonitorManager) Collect() (*ProcessMonitorData, error) {
+	return manager.collector.CollectProcessMonitorData()
+}
+
+// Subscribe collects every interval in the background and sends the data on the returned channel until the
+// returned function is called, which closes it; a zero interval uses the configured refresh interval
+// Nothing is displayed, exported or recorded. A subscriber that falls behind only gets the newest data,
+// and failed collections are skipped (Collect returns their error)
+func (manager *ProcessMonitorManager) Subscribe(interval time.Duration) (<-chan *ProcessMonitorData, func()) {
+	if interval <= 0 {
+		interval = manager.collector.currentConfig().RefreshInterval
+	}
+	updates := make(chan *ProcessMonitorData, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(updates)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if data, err := manager.Collect(); err == nil {
+				select {
+				case <-updates:
+				default:
+				}
+				updates <- data
+			}
+			select {
+			case <-ticker.C:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var stop sync.Once
+	return updates, func() {
+		stop.Do(func() { close(done) })
+	}
+}
+
 // IsRunning returns whether the process monitor is currently running
 func (manager *ProcessMonitorManager) IsRunning() bool {
 	return manager.isRunning
 }
+
+// IsEnabled returns whether the monitor is switched on in its configuration
+func (manager *ProcessMonitorManager) IsEnabled() bool {
+	return manager.collector.currentConfig().Enabled
+}
+
+// SetLiveHint sets a line shown under each live refresh, or clears it when empty
+func (manager *ProcessMonitorManager) SetLiveHint(hint string) {
+	manager.liveHint = hint
+}
+
+// SetPaused freezes the live display on its last refresh, or resumes refreshing
+// Nothing is collected while paused, so history, exports and alerts wait too
+func (manager *ProcessMonitorManager) SetPaused(paused bool) {
+	manager.paused.Store(paused)
+}
+
+// Step collects and shows one refresh, for stepping through a paused display
+func (manager *ProcessMonitorManager) Step() {
+	select {
+	case manager.stepChannel <- true:
+	default:
+	}
+}
+
+// SetRecorder records every live collection cycle to recorder (nil stops recording)
+func (manager *ProcessMonitorManager) SetRecorder(recorder *recording.Recorder) {
+	manager.recorder = recorder
+}
+
+// DisplayRecordedData shows one recorded collection cycle the way the live display showed it
+func (manager *ProcessMonitorManager) DisplayRecordedData(content []byte) error {
+	var data ProcessMonitorData
+	if err := json.Unmarshal(content, &data); err != nil {
+		return fmt.Errorf("failed to decode recorded process data: %w", err)
+	}
+	manager.displayer.DisplayProcessMonitorData(&data)
+	return nil
+}
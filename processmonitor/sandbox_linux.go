@@ -0,0 +1,81 @@
+//go:build linux
+
+package processmonitor
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// sandboxNamespaces are the namespaces compared against the host to tell whether a process is confined
+var sandboxNamespaces = []string{"mnt", "net", "pid"}
+
+// hostNamespaces returns the namespace identities of init, falling back to this process when
+// /proc/1/ns cannot be read (it needs the same privileges as tracing init)
+func hostNamespaces() (map[string]string, error) {
+	namespaces, err := readNamespaces(1)
+	if err == nil {
+		return namespaces, nil
+	}
+	return readNamespaces(int32(os.Getpid()))
+}
+
+// readNamespaces returns the namespace identities of a process, e.g. "net" -> "net:[4026531840]"
+func readNamespaces(pid int32) (map[string]string, error) {
+	namespaces := make(map[string]string, len(sandboxNamespaces))
+	for _, name := range sandboxNamespaces {
+		link, err := os.Readlink(fmt.Sprintf("/proc/%d/ns/%s", pid, name))
+		if err != nil {
+			return nil, err
+		}
+		namespaces[name] = link
+	}
+	return namespaces, nil
+}
+
+// readSeccomp returns the seccomp mode of a process from the Seccomp line of /proc/<pid>/status
+func readSeccomp(pid int32) (string, error) {
+	file, err := os.Open(fmt.Sprintf("/proc/%d/status", pid))
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		value, found := strings.CutPrefix(scanner.Text(), "Seccomp:")
+		if !found {
+			continue
+		}
+		switch strings.TrimSpace(value) {
+		case "1":
+			return "strict", nil
+		case "2":
+			return "filter", nil
+		}
+		return "", nil
+	}
+	return "", scanner.Err()
+}
+
+// readSandbox gathers the confinement hints of a process
+// Namespaces are only reported when they can be compared with the host; seccomp needs no privileges
+func readSandbox(pid int32, host map[string]string) (*SandboxInfo, error) {
+	sandbox := &SandboxInfo{}
+
+	seccomp, err := readSeccomp(pid)
+	if err != nil {
+		return nil, err
+	}
+	sandbox.Seccomp = seccomp
+
+	if namespaces, err := readNamespaces(pid); err == nil && host != nil {
+		sandbox.MountNamespace = namespaces["mnt"] != host["mnt"]
+		sandbox.NetworkNamespace = namespaces["net"] != host["net"]
+		sandbox.PIDNamespace = namespaces["pid"] != host["pid"]
+	}
+
+	return sandbox, nil
+}
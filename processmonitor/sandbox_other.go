@@ -0,0 +1,17 @@
+//go:build !linux
+
+package processmonitor
+
+import "errors"
+
+// hostNamespaces returns the namespace identities of the host
+// Namespaces are a Linux feature, so there is nothing to read on other platforms
+func hostNamespaces() (map[string]string, error) {
+	return nil, errors.New("namespaces are only available on Linux")
+}
+
+// readSandbox gathers the confinement hints of a process
+// Namespaces and seccomp are Linux features, so there is nothing to read on other platforms
+func readSandbox(pid int32, host map[string]string) (*SandboxInfo, error) {
+	return nil, errors.New("sandbox hints are only available on Linux")
+}
@@ -0,0 +1,81 @@
+package processmonitor
+
+import (
+	"github.com/ahmadreza-log/simple-monitor/procid"
+	"github.com/ahmadreza-log/simple-monitor/simulate"
+	"strings"
+	"time"
+)
+
+// simulatedMemoryTotal is the RAM size memory percentages are computed against in simulation mode
+const simulatedMemoryTotal = 16 * 1024 * simulate.MB
+
+// simulatedProcessInfos builds the process list from the shared synthetic process table
+// A short-lived zombie child shows up now and then so status counts and alerts are exercised
+func (collector *ProcessMonitorCollector) simulatedProcessInfos() []ProcessInfo {
+	source := collector.simulator
+	now := time.Now()
+
+	var processInfos []ProcessInfo
+	children := make(map[int32]int32)
+	for _, process := range source.Processes() {
+		status := "S"
+		if process.Status == "running" {
+			status = "R"
+		}
+		counter := strings.ToLower(process.Name)
+
+		processInfos = append(processInfos, ProcessInfo{
+			PID:             process.PID,
+			InstanceID:      procid.InstanceID(process.PID, process.CreateTime),
+			Name:            process.Name,
+			Status:          status,
+			User:            process.User,
+			CPUUsage:        process.CPUPercent,
+			CPUTime:         process.CPUTime,
+			MemoryUsage:     float64(process.MemoryRSS) / simulatedMemoryTotal * 100,
+			MemoryRSS:       process.MemoryRSS,
+			MemoryVMS:       process.MemoryVMS,
+			Threads:         process.Threads,
+			OpenFiles:       process.OpenFiles,
+			CreateTime:      process.CreateTime,
+			Uptime:          now.Unix() - process.CreateTime/1000,
+			ParentPID:       process.PPID,
+			Cgroup:          process.Cgroup,
+			Slice:           cgroupSlice(process.Cgroup),
+			CommandLine:     process.Path,
+			Executable:      process.Path,
+			IOReadBytes:     source.Counter(counter+".read", float64(process.ReadRate)),
+			IOWriteBytes:    source.Counter(counter+".write", float64(process.WriteRate)),
+			ContextSwitches: source.Counter(counter+".ctxt", process.CPUPercent*400+50),
+			PageFaults:      source.Counter(counter+".faults", float64(process.MemoryRSS)/simulate.MB),
+		})
+		children[process.PPID]++
+	}
+
+	if source.Spike(0.1, 1) > 0 {
+		for _, parent := range processInfos {
+			if parent.Name != "python3" {
+				continue
+			}
+			processInfos = append(processInfos, ProcessInfo{
+				PID:        parent.PID + 1,
+				InstanceID: procid.InstanceID(parent.PID+1, now.UnixMilli()),
+				Name:       "python3",
+				Status:     "Z",
+				User:       parent.User,
+				CreateTime: now.UnixMilli(),
+				ParentPID:  parent.PID,
+				Cgroup:     parent.Cgroup,
+				Slice:      parent.Slice,
+			})
+			children[parent.PID]++
+			break
+		}
+	}
+
+	for i := range processInfos {
+		processInfos[i].Children = children[processInfos[i].PID]
+	}
+	return processInfos
+}
@@ -1,34 +1,83 @@
 package processmonitor
 
-import "time"
+import (
+	"github.com/ahmadreza-log/simple-monitor/partial"
+	"slices"
+	"time"
+)
 
 // ProcessInfo represents comprehensive information about a process
 type ProcessInfo struct {
-	PID             int32   `json:"pid"`              // Process ID
-	Name            string  `json:"name"`             // Process name
-	Status          string  `json:"status"`           // Process status
-	User            string  `json:"user"`             // Process owner
-	CPUUsage        float64 `json:"cpu_usage"`        // CPU usage percentage
-	MemoryUsage     float64 `json:"memory_usage"`     // Memory usage percentage
-	MemoryRSS       uint64  `json:"memory_rss"`       // Resident Set Size in bytes
-	MemoryVMS       uint64  `json:"memory_vms"`       // Virtual Memory Size in bytes
-	Threads         int32   `json:"threads"`          // Number of threads
-	OpenFiles       int32   `json:"open_files"`       // Number of open files
-	CreateTime      int64   `json:"create_time"`      // Process creation time
-	Uptime          int64   `json:"uptime"`           // Process uptime in seconds
-	ParentPID       int32   `json:"parent_pid"`       // Parent process ID
-	CommandLine     string  `json:"command_line"`     // Full command line
-	WorkingDir      string  `json:"working_dir"`      // Working directory
-	Executable      string  `json:"executable"`       // Executable path
-	Priority        int32   `json:"priority"`         // Process priority
-	Nice            int32   `json:"nice"`             // Nice value
-	IOReadBytes     uint64  `json:"io_read_bytes"`    // I/O read bytes
-	IOWriteBytes    uint64  `json:"io_write_bytes"`   // I/O write bytes
-	IOReadCount     uint64  `json:"io_read_count"`    // I/O read count
-	IOWriteCount    uint64  `json:"io_write_count"`   // I/O write count
-	ContextSwitches uint64  `json:"context_switches"` // Context switches
-	PageFaults      uint64  `json:"page_faults"`      // Page faults
-	Children        int32   `json:"children"`         // Number of child processes
+	PID               int32   `json:"pid"`                 // Process ID
+	InstanceID        string  `json:"instance_id"`         // Process instance ID (PID and start time)
+	Name              string  `json:"name"`                // Process name
+	Status            string  `json:"status"`              // Process status
+	User              string  `json:"user"`                // Process owner
+	CPUUsage          float64 `json:"cpu_usage"`           // CPU usage percentage
+	CPUTime           uint64  `json:"cpu_time"`            // Accumulated user and system CPU time in milliseconds (TIME+)
+	MemoryUsage       float64 `json:"memory_usage"`        // Memory usage percentage
+	MemoryRSS         uint64  `json:"memory_rss"`          // Resident Set Size in bytes
+	MemoryVMS         uint64  `json:"memory_vms"`          // Virtual Memory Size in bytes
+	Threads           int32   `json:"threads"`             // Number of threads
+	OpenFiles         int32   `json:"open_files"`          // Number of open files
+	CreateTime        int64   `json:"create_time"`         // Process creation time
+	Uptime            int64   `json:"uptime"`              // Process uptime in seconds
+	ParentPID         int32   `json:"parent_pid"`          // Parent process ID
+	CommandLine       string  `json:"command_line"`        // Full command line
+	WorkingDir        string  `json:"working_dir"`         // Working directory
+	Executable        string  `json:"executable"`          // Executable path
+	Priority          int32   `json:"priority"`            // Process priority
+	Nice              int32   `json:"nice"`                // Nice value
+	IOReadBytes       uint64  `json:"io_read_bytes"`       // I/O read bytes
+	IOWriteBytes      uint64  `json:"io_write_bytes"`      // I/O write bytes
+	IOReadCount       uint64  `json:"io_read_count"`       // I/O read count
+	IOWriteCount      uint64  `json:"io_write_count"`      // I/O write count
+	ContextSwitches   uint64  `json:"context_switches"`    // Context switches
+	ContextSwitchRate float64 `json:"context_switch_rate"` // Context switches per second since the previous refresh (0 on first sight)
+	PageFaults        uint64  `json:"page_faults"`         // Page faults
+	Children          int32   `json:"children"`            // Number of child processes
+	Cgroup            string  `json:"cgroup,omitempty"`    // Control group path (Linux), e.g. /system.slice/nginx.service
+	Slice             string  `json:"slice,omitempty"`     // Service group: system.slice, user.slice, docker, kubepods...
+
+	// Windows-specific details (nil on other platforms)
+	Windows *WindowsProcessDetails `json:"windows,omitempty"`
+
+	// Confinement hints (Linux, nil unless sandbox hints are enabled)
+	Sandbox *SandboxInfo `json:"sandbox,omitempty"`
+}
+
+// SandboxInfo represents how a process is confined: namespaces it does not share with the host, seccomp and containers
+type SandboxInfo struct {
+	MountNamespace   bool   `json:"mount_namespace"`     // Runs in its own mount namespace
+	NetworkNamespace bool   `json:"network_namespace"`   // Runs in its own network namespace
+	PIDNamespace     bool   `json:"pid_namespace"`       // Runs in its own PID namespace
+	Seccomp          string `json:"seccomp,omitempty"`   // Seccomp mode: "strict" or "filter" (empty when not under seccomp)
+	Container        string `json:"container,omitempty"` // Container runtime the process runs under (docker, podman, kubepods, lxc)
+}
+
+// WindowsProcessDetails represents Windows-only information that identifies a process
+type WindowsProcessDetails struct {
+	Services       []string `json:"services,omitempty"`     // Services hosted by the process (svchost instances)
+	WindowTitle    string   `json:"window_title,omitempty"` // Main window title for GUI applications
+	Elevated       bool     `json:"elevated"`               // Whether the process runs with an elevated token
+	ElevationKnown bool     `json:"elevation_known"`        // Whether the elevation state could be read
+}
+
+// FilterOption represents one value in a process filter picker (a user or a slice)
+type FilterOption struct {
+	Value     string `json:"value"`     // User name or slice
+	Processes int    `json:"processes"` // Number of processes with this value (before filtering)
+	Selected  bool   `json:"selected"`  // Whether the value is in the filter
+}
+
+// AncestorInfo represents one process in the parent chain of a traced process
+type AncestorInfo struct {
+	PID         int32  `json:"pid"`          // Process ID
+	ParentPID   int32  `json:"parent_pid"`   // Parent process ID (0 at the top of the chain)
+	Name        string `json:"name"`         // Process name
+	User        string `json:"user"`         // Process owner
+	CreateTime  int64  `json:"create_time"`  // Start time in milliseconds since the epoch
+	CommandLine string `json:"command_line"` // Full command line
 }
 
 // ProcessTreeInfo represents process tree information
@@ -58,9 +107,72 @@ type ProcessResourceInfo struct {
 	Nice            int32   `json:"nice"`             // Nice value
 }
 
+// ProcessFamilyInfo represents CPU and memory attributed to a whole process subtree
+type ProcessFamilyInfo struct {
+	RootPID      int32   `json:"root_pid"`      // PID of the subtree root
+	Name         string  `json:"name"`          // Name of the subtree root
+	ProcessCount int     `json:"process_count"` // Number of processes in the subtree (including the root)
+	TotalCPU     float64 `json:"total_cpu"`     // Summed CPU usage of the whole subtree
+	SelfCPU      float64 `json:"self_cpu"`      // CPU usage of the root process alone
+	TotalMemory  float64 `json:"total_memory"`  // Summed memory usage percentage of the subtree
+	TotalRSS     uint64  `json:"total_rss"`     // Summed Resident Set Size of the subtree in bytes
+}
+
+// ProcessStackSample represents a process's CPU usage with its ancestry, for flame graph export
+type ProcessStackSample struct {
+	Stack    []string `json:"stack"`     // Process names from the family root down to the process
+	CPUUsage float64  `json:"cpu_usage"` // CPU usage of the process itself
+}
+
+// ProcessChurnInfo represents a process that keeps starting and exiting (respawn loop)
+type ProcessChurnInfo struct {
+	Name            string    `json:"name"`              // Process name
+	CommandLine     string    `json:"command_line"`      // Command line shared by the respawning instances
+	Starts          int       `json:"starts"`            // Starts seen within the respawn window
+	Exits           int       `json:"exits"`             // Exits seen within the respawn window
+	StartsPerMinute float64   `json:"starts_per_minute"` // Churn rate
+	LastPID         int32     `json:"last_pid"`          // PID of the most recent instance
+	LastStart       time.Time `json:"last_start"`        // When the most recent instance started
+	ParentPID       int32     `json:"parent_pid"`        // Parent that spawned most of the instances
+	ParentName      string    `json:"parent_name"`       // Name of that parent
+}
+
+// ProcessLimitInfo represents a process using most of one of its own resource limits (ulimits)
+type ProcessLimitInfo struct {
+	PID        int32   `json:"pid"`         // Process ID
+	InstanceID string  `json:"instance_id"` // Process instance ID (PID and start time)
+	Name       string  `json:"name"`        // Process name
+	User       string  `json:"user"`        // Process owner
+	Limit      string  `json:"limit"`       // Limit as named in /proc/<pid>/limits ("Max open files")
+	Resource   string  `json:"resource"`    // Limit as named by ulimit and setrlimit ("nofile", "nproc", "as")
+	Used       uint64  `json:"used"`        // Current usage counted against the limit
+	Soft       uint64  `json:"soft"`        // Soft limit the kernel enforces
+	Hard       uint64  `json:"hard"`        // Hard limit the soft limit can be raised to
+	Percent    float64 `json:"percent"`     // Usage as a percentage of the soft limit
+}
+
+// WatchdogRule configures one process the watchdog keeps running
+type WatchdogRule struct {
+	RestartCommand string `json:"restart_command"` // Shell command run when the process is missing (empty to only alert)
+}
+
+// WatchdogInfo represents the state of one watched process
+type WatchdogInfo struct {
+	Name           string    `json:"name"`                    // Watched process name
+	Running        bool      `json:"running"`                 // Whether a process with this name is running
+	PIDs           []int32   `json:"pids"`                    // PIDs of the running instances
+	DownSince      time.Time `json:"down_since"`              // When the process was first seen missing (zero while running)
+	RestartCommand string    `json:"restart_command"`         // Command run when the process is missing
+	LastRestart    time.Time `json:"last_restart"`            // When the restart command was last started (zero if never)
+	RestartCount   int       `json:"restart_count"`           // Restart commands started since the monitor began
+	RestartHeld    string    `json:"restart_held,omitempty"`  // Why a due restart is not being run (maintenance, simulated data)
+	RestartError   string    `json:"restart_error,omitempty"` // Why the last restart command could not be started
+}
+
 // ProcessAlertInfo represents process alert information
 type ProcessAlertInfo struct {
 	PID          int32     `json:"pid"`           // Process ID
+	InstanceID   string    `json:"instance_id"`   // Process instance ID (PID and start time); empty for respawn loops
 	Name         string    `json:"name"`          // Process name
 	AlertType    string    `json:"alert_type"`    // Type of alert
 	AlertMessage string    `json:"alert_message"` // Alert message
@@ -70,6 +182,21 @@ type ProcessAlertInfo struct {
 	Threshold    float64   `json:"threshold"`     // Alert threshold
 }
 
+// ProcessLogLine represents a single error line logged by a process or its service
+type ProcessLogLine struct {
+	Timestamp time.Time `json:"timestamp"` // When the line was logged
+	Message   string    `json:"message"`   // Log message text
+}
+
+// ProcessLogInfo represents recent error log lines for a process with active alerts
+type ProcessLogInfo struct {
+	PID   int32            `json:"pid"`             // Process ID
+	Name  string           `json:"name"`            // Process name
+	Unit  string           `json:"unit"`            // Service unit or event provider the lines were matched on
+	Lines []ProcessLogLine `json:"lines"`           // Recent error lines, oldest first
+	Error string           `json:"error,omitempty"` // Reason the log could not be read, if any
+}
+
 // ProcessMonitorData represents comprehensive process monitoring data
 type ProcessMonitorData struct {
 	// All processes
@@ -88,15 +215,35 @@ type ProcessMonitorData struct {
 	TopIOProcesses     []ProcessInfo `json:"top_io_processes"`     // Top processes by I/O usage
 	TopThreadProcesses []ProcessInfo `json:"top_thread_processes"` // Top processes by thread count
 
+	// Top processes by activity that CPU and memory sorting misses (ranked before the usage filters)
+	TopOpenFilesProcesses     []ProcessInfo `json:"top_open_files_processes"`     // Top processes by open file descriptors
+	TopContextSwitchProcesses []ProcessInfo `json:"top_context_switch_processes"` // Top processes by context switches per second
+
 	// Process tree information
 	ProcessTree []ProcessTreeInfo `json:"process_tree"` // Process tree structure
 
+	// CPU attribution by process family
+	CPUAttribution []ProcessFamilyInfo  `json:"cpu_attribution"` // Process families sorted by summed subtree CPU
+	CPUStacks      []ProcessStackSample `json:"cpu_stacks"`      // Per-process CPU with ancestry for flame graph export
+
 	// Resource usage information
 	ResourceUsage []ProcessResourceInfo `json:"resource_usage"` // Resource usage for all processes
 
 	// Process alerts
 	ProcessAlerts []ProcessAlertInfo `json:"process_alerts"` // Process alerts and warnings
 
+	// Process churn (crash-looping services, fork bombs)
+	RespawnLoops []ProcessChurnInfo `json:"respawn_loops"` // Processes starting more often than the respawn threshold
+
+	// Processes close to their own resource limits
+	LimitWarnings []ProcessLimitInfo `json:"limit_warnings"` // Limits above LimitWarningPercent, highest first
+
+	// Watched processes that must stay running
+	Watchdog []WatchdogInfo `json:"watchdog"` // State of each watched process, sorted by name
+
+	// Log lines correlated with process alerts
+	ProcessLogs []ProcessLogInfo `json:"process_logs"` // Recent error log lines for alerting processes
+
 	// Overall system metrics
 	TotalCPUUsage    float64 `json:"total_cpu_usage"`    // Total CPU usage across all processes
 	TotalMemoryUsage float64 `json:"total_memory_usage"` // Total memory usage across all processes
@@ -112,11 +259,24 @@ type ProcessMonitorData struct {
 	HighIOWarning     bool   `json:"high_io_warning"`     // High I/O usage warning
 	ZombieWarning     bool   `json:"zombie_warning"`      // Zombie process warning
 	ThreadWarning     bool   `json:"thread_warning"`      // High thread count warning
+	RespawnWarning    bool   `json:"respawn_warning"`     // Respawn loop warning
+	WatchdogWarning   bool   `json:"watchdog_warning"`    // A watched process is not running
+	LimitWarning      bool   `json:"limit_warning"`       // A process is close to one of its resource limits
 
 	// Monitoring configuration
 	RefreshInterval time.Duration `json:"refresh_interval"` // How often data is refreshed
 	IsMonitoring    bool          `json:"is_monitoring"`    // Whether monitoring is active
 
+	// Sections that could not be collected; everything else in the snapshot is still valid
+	SectionErrors partial.Errors `json:"section_errors,omitempty"` // Reason keyed by section name
+
+	// Active user and slice filters
+	UserFilter  []string `json:"user_filter,omitempty"`  // Users whose processes are shown (empty when all users are shown)
+	SliceFilter []string `json:"slice_filter,omitempty"` // Slices whose processes are shown (empty when all slices are shown)
+
+	// Data source
+	Simulated bool `json:"simulated,omitempty"` // Whether the data is synthetic (--simulate) rather than read from the system
+
 	// Timestamps
 	Timestamp time.Time     `json:"timestamp"` // When this data was collected
 	Uptime    time.Duration `json:"uptime"`    // System uptime
@@ -124,6 +284,8 @@ type ProcessMonitorData struct {
 
 // ProcessMonitorConfig represents configuration options for process monitoring
 type ProcessMonitorConfig struct {
+	Enabled bool `json:"enabled"` // Whether the monitor runs; a disabled monitor is left out of every view, snapshot and alert
+
 	// Monitoring settings
 	RefreshInterval     time.Duration `json:"refresh_interval"`      // How often to refresh data
 	MaxProcesses        int           `json:"max_processes"`         // Maximum number of processes to track
@@ -135,23 +297,56 @@ type ProcessMonitorConfig struct {
 	ZombieThreshold     int           `json:"zombie_threshold"`      // Zombie process threshold
 
 	// Display settings
-	ShowProcessTree   bool `json:"show_process_tree"`   // Whether to show process tree
-	ShowResourceUsage bool `json:"show_resource_usage"` // Whether to show resource usage
-	ShowTopProcesses  bool `json:"show_top_processes"`  // Whether to show top processes
-	ShowAlerts        bool `json:"show_alerts"`         // Whether to show process alerts
-	ShowPerformance   bool `json:"show_performance"`    // Whether to show performance metrics
+	ShowProcessTree    bool `json:"show_process_tree"`    // Whether to show process tree
+	ShowResourceUsage  bool `json:"show_resource_usage"`  // Whether to show resource usage
+	ShowTopProcesses   bool `json:"show_top_processes"`   // Whether to show top processes
+	ShowAlerts         bool `json:"show_alerts"`          // Whether to show process alerts
+	ShowPerformance    bool `json:"show_performance"`     // Whether to show performance metrics
+	ShowProcessLogs    bool `json:"show_process_logs"`    // Whether to pull journal/event-log errors for alerting processes
+	ShowAttribution    bool `json:"show_attribution"`     // Whether to show CPU attribution by process family
+	ShowWindowsDetails bool `json:"show_windows_details"` // Whether to show services, window titles and elevation on Windows
+	ShowSandbox        bool `json:"show_sandbox"`         // Whether to show namespace, seccomp and container hints on Linux
+	ShowRespawnLoops   bool `json:"show_respawn_loops"`   // Whether to track process churn and detect respawn loops
 
 	// Export settings
-	ExportToFile   bool          `json:"export_to_file"`  // Whether to export data to file
-	ExportInterval time.Duration `json:"export_interval"` // How often to export data
-	ExportFormat   string        `json:"export_format"`   // Export format (json, csv, txt)
+	ExportToFile        bool          `json:"export_to_file"`        // Whether to export data to file
+	ExportInterval      time.Duration `json:"export_interval"`       // How often to export data
+	ExportFormat        string        `json:"export_format"`         // Export format (json, csv, txt, protobuf)
+	PersistHistory      bool          `json:"persist_history"`       // Whether to keep downsampled long-term history on disk
+	HistorySaveInterval time.Duration `json:"history_save_interval"` // How often the long-term history file is rewritten
+	HistorySize         int           `json:"history_size"`          // Samples kept in the in-memory history behind live trends
+	HistoryResolution   time.Duration `json:"history_resolution"`    // Minimum time between in-memory history samples (0 samples every refresh)
 
 	// Filter settings
-	MinCPUUsage       float64 `json:"min_cpu_usage"`       // Minimum CPU usage to show process
-	MinMemoryUsage    float64 `json:"min_memory_usage"`    // Minimum memory usage to show process
-	ProcessNameFilter string  `json:"process_name_filter"` // Filter processes by name
-	UserFilter        string  `json:"user_filter"`         // Filter processes by user
-	StatusFilter      string  `json:"status_filter"`       // Filter processes by status
+	MinCPUUsage       float64  `json:"min_cpu_usage"`       // Minimum CPU usage to show process
+	MinMemoryUsage    float64  `json:"min_memory_usage"`    // Minimum memory usage to show process
+	ProcessNameFilter string   `json:"process_name_filter"` // Filter processes by name
+	UserFilter        []string `json:"user_filter"`         // Show only processes owned by these users (empty shows all)
+	SliceFilter       []string `json:"slice_filter"`        // Show only processes in these slices (empty shows all)
+	StatusFilter      string   `json:"status_filter"`       // Filter processes by status
+
+	// Log correlation settings
+	MaxLogProcesses  int           `json:"max_log_processes"`  // Maximum number of alerting processes to pull logs for
+	MaxLogLines      int           `json:"max_log_lines"`      // Maximum number of log lines per process
+	LogLookback      time.Duration `json:"log_lookback"`       // How far back to search the logs
+	LogCheckInterval time.Duration `json:"log_check_interval"` // How often to re-read logs for the same process
+
+	// CPU attribution settings
+	AttributionRoots []string `json:"attribution_roots"` // Process names whose children start a new family (init, session managers)
+
+	// Respawn loop settings
+	RespawnThreshold int           `json:"respawn_threshold"` // Starts per minute of the same name/cmdline that count as a respawn loop
+	RespawnWindow    time.Duration `json:"respawn_window"`    // Sliding window over which starts and exits are counted
+
+	// Resource limit settings
+	LimitWarningPercent float64 `json:"limit_warning_percent"` // Share of a process's open files, processes or address space limit that raises a warning (0 disables)
+
+	// Watchdog settings
+	Watchdog             map[string]WatchdogRule `json:"watchdog"`               // Processes (by name) that must stay running
+	WatchdogRestartDelay time.Duration           `json:"watchdog_restart_delay"` // Minimum time between restart attempts for the same process
+
+	// Windows details settings
+	WindowsDetailsInterval time.Duration `json:"windows_details_interval"` // How often to re-run tasklist for services and window titles
 }
 
 // ProcessUsageHistory represents historical process usage data for graphing
@@ -169,3 +364,16 @@ type ProcessUsageHistory struct {
 	MaxDataPoints  int `json:"max_data_points"`  // Maximum number of data points to store
 	DataPointCount int `json:"data_point_count"` // Current number of data points
 }
+
+// Clone returns a deep copy of the history
+func (history *ProcessUsageHistory) Clone() *ProcessUsageHistory {
+	clone := *history
+	clone.Timestamps = slices.Clone(history.Timestamps)
+	clone.TotalCPUUsage = slices.Clone(history.TotalCPUUsage)
+	clone.TotalMemoryUsage = slices.Clone(history.TotalMemoryUsage)
+	clone.TotalIORead = slices.Clone(history.TotalIORead)
+	clone.TotalIOWrite = slices.Clone(history.TotalIOWrite)
+	clone.TotalThreads = slices.Clone(history.TotalThreads)
+	clone.ProcessCount = slices.Clone(history.ProcessCount)
+	return &clone
+}
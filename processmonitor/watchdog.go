@@ -0,0 +1,151 @@
+package processmonitor
+
+import (
+	"fmt"
+	"github.com/ahmadreza-log/simple-monitor/alert"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// watchdogState tracks one watched process between refreshes
+type watchdogState struct {
+	downSince    time.Time // When the process was first seen missing (zero while running)
+	lastRestart  time.Time // When the restart command was last started
+	restartCount int       // Restart commands started since the monitor began
+	restartError string    // Why the last restart command could not be started
+}
+
+// collectWatchdog checks that every watched process is running and starts its restart command when it is not
+// Restarts are held during maintenance windows and snoozes, and repeated at most once per WatchdogRestartDelay
+func (collector *ProcessMonitorCollector) collectWatchdog(data *ProcessMonitorData, processes []ProcessInfo) {
+	if len(collector.config.Watchdog) == 0 {
+		return
+	}
+
+	running := make(map[string][]int32)
+	for _, proc := range processes {
+		name := watchdogName(proc.Name)
+		running[name] = append(running[name], proc.PID)
+	}
+
+	names := make([]string, 0, len(collector.config.Watchdog))
+	for name := range collector.config.Watchdog {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		rule := collector.config.Watchdog[name]
+		state, ok := collector.watchdog[name]
+		if !ok {
+			state = &watchdogState{}
+			collector.watchdog[name] = state
+		}
+
+		info := WatchdogInfo{
+			Name:           name,
+			PIDs:           running[watchdogName(name)],
+			RestartCommand: rule.RestartCommand,
+		}
+		info.Running = len(info.PIDs) > 0
+
+		if info.Running {
+			state.downSince = time.Time{}
+			state.restartError = ""
+		} else {
+			if state.downSince.IsZero() {
+				state.downSince = data.Timestamp
+			}
+			if rule.RestartCommand != "" {
+				info.RestartHeld = collector.restartWatched(name, rule.RestartCommand, state, data)
+			}
+		}
+
+		info.DownSince = state.downSince
+		info.LastRestart = state.lastRestart
+		info.RestartCount = state.restartCount
+		info.RestartError = state.restartError
+		data.Watchdog = append(data.Watchdog, info)
+	}
+
+	// Forget processes removed from the watch list
+	for name := range collector.watchdog {
+		if _, watched := collector.config.Watchdog[name]; !watched {
+			delete(collector.watchdog, name)
+		}
+	}
+}
+
+// restartWatched starts the restart command of a missing process unless restarts are held
+// It returns why the restart is held, or "" when the command was started or is waiting for the restart delay
+func (collector *ProcessMonitorCollector) restartWatched(name, command string, state *watchdogState, data *ProcessMonitorData) string {
+	if data.Simulated {
+		return "simulated data"
+	}
+	// Maintenance usually means the process was stopped on purpose
+	if reason, active := alert.ActiveMaintenance(data.Timestamp); active {
+		return reason
+	}
+	if data.Timestamp.Sub(state.lastRestart) < collector.config.WatchdogRestartDelay {
+		return ""
+	}
+
+	state.lastRestart = data.Timestamp
+	state.restartCount++
+	if err := startRestartCommand(command); err != nil {
+		state.restartError = fmt.Sprintf("failed to restart %s: %v", name, err)
+	} else {
+		state.restartError = ""
+	}
+	return ""
+}
+
+// startRestartCommand runs a restart command through the shell without waiting for it to finish
+// Commands such as systemctl restart return quickly; long-running ones become children of the monitor.
+// Restart commands come from the menu or from a config file owned by the current user (see config.File.CommandsAllowed)
+func startRestartCommand(command string) error {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", command)
+	} else {
+		cmd = exec.Command("sh", "-c", command)
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
+
+// watchdogName normalizes a process name for matching: case-insensitive and without the .exe suffix
+func watchdogName(name string) string {
+	return strings.TrimSuffix(strings.ToLower(name), ".exe")
+}
+
+// formatWatchdogTime formats a watchdog timestamp for exports, leaving unset times empty
+func formatWatchdogTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02 15:04:05")
+}
+
+// SetWatchdog adds a process to the watchdog, replacing its restart command if it is already watched
+func (collector *ProcessMonitorCollector) SetWatchdog(name, restartCommand string) {
+	collector.changeConfig(func(config *ProcessMonitorConfig) {
+		if config.Watchdog == nil {
+			config.Watchdog = make(map[string]WatchdogRule)
+		}
+		config.Watchdog[name] = WatchdogRule{RestartCommand: restartCommand}
+	})
+}
+
+// RemoveWatchdog stops watching a process
+func (collector *ProcessMonitorCollector) RemoveWatchdog(name string) {
+	collector.changeConfig(func(config *ProcessMonitorConfig) {
+		delete(config.Watchdog, name)
+	})
+}
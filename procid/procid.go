@@ -0,0 +1,29 @@
+// Package procid identifies process instances by PID and start time, since PIDs are reused
+package procid
+
+import "fmt"
+
+// Key identifies one process instance: its PID together with its start time
+// PIDs are reused once a process exits, so caches keyed by the PID alone would carry the counters of an
+// exited process over to the next process given its PID; a reused PID has another start time and another Key
+type Key struct {
+	PID        int32 // Process ID
+	CreateTime int64 // Start time in milliseconds since the epoch (0 when it could not be read)
+}
+
+// New returns the key of a process instance
+func New(pid int32, createTime int64) Key {
+	return Key{PID: pid, CreateTime: createTime}
+}
+
+// InstanceID returns the process instance ID written to exports, "<pid>-<start time in milliseconds>"
+// It stays the same for as long as the process runs and is never shared by two processes, so rows of
+// different exports (and of different monitors) can be joined on it
+func (key Key) InstanceID() string {
+	return fmt.Sprintf("%d-%d", key.PID, key.CreateTime)
+}
+
+// InstanceID returns the instance ID of a process
+func InstanceID(pid int32, createTime int64) string {
+	return New(pid, createTime).InstanceID()
+}
@@ -0,0 +1,197 @@
+// Package protoexport encodes the export data types as protobuf and generates their .proto schema
+package protoexport
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Extension is the file extension of protobuf exports
+const Extension = "pb"
+
+// Marshal encodes a struct (or pointer to one) as the protobuf message Schema describes for it
+// Like proto3 itself, zero values are left out; a zero time is left out too
+func Marshal(value interface{}) ([]byte, error) {
+	reflected := reflect.ValueOf(value)
+	for reflected.Kind() == reflect.Ptr {
+		if reflected.IsNil() {
+			return nil, fmt.Errorf("cannot encode a nil %s", reflected.Type())
+		}
+		reflected = reflected.Elem()
+	}
+	if reflected.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot encode %s as a message", reflected.Type())
+	}
+	return appendMessage(nil, reflected)
+}
+
+// appendMessage appends the fields of a struct
+func appendMessage(buffer []byte, value reflect.Value) ([]byte, error) {
+	layout, err := layoutOf(value.Type())
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range layout.fields {
+		if buffer, err = appendField(buffer, field.number, value.Field(field.index)); err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", layout.name, field.name, err)
+		}
+	}
+	return buffer, nil
+}
+
+// appendField appends one field, leaving it out when it holds the zero value
+func appendField(buffer []byte, number protowire.Number, value reflect.Value) ([]byte, error) {
+	switch value.Kind() {
+	case reflect.Slice:
+		if value.Len() == 0 {
+			return buffer, nil
+		}
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			buffer = protowire.AppendTag(buffer, number, protowire.BytesType)
+			return protowire.AppendBytes(buffer, value.Bytes()), nil
+		}
+		return appendList(buffer, number, value)
+	case reflect.Map:
+		return appendMap(buffer, number, value)
+	case reflect.Ptr:
+		if value.IsNil() {
+			return buffer, nil
+		}
+		return appendValue(buffer, number, value.Elem())
+	}
+	if value.IsZero() && value.Kind() != reflect.Struct || value.Type() == timeType && value.Interface().(time.Time).IsZero() {
+		return buffer, nil
+	}
+	return appendValue(buffer, number, value)
+}
+
+// appendList appends a repeated field; numbers and booleans are packed into one record as proto3 expects
+func appendList(buffer []byte, number protowire.Number, value reflect.Value) ([]byte, error) {
+	if wireType, packable := scalarWireType(value.Type().Elem()); packable && wireType != protowire.BytesType {
+		var packed []byte
+		for i := 0; i < value.Len(); i++ {
+			packed = appendScalar(packed, value.Index(i))
+		}
+		buffer = protowire.AppendTag(buffer, number, protowire.BytesType)
+		return protowire.AppendBytes(buffer, packed), nil
+	}
+
+	var err error
+	for i := 0; i < value.Len(); i++ {
+		element := value.Index(i)
+		if element.Kind() == reflect.Ptr {
+			if element.IsNil() {
+				element = reflect.Zero(element.Type().Elem())
+			} else {
+				element = element.Elem()
+			}
+		}
+		if buffer, err = appendValue(buffer, number, element); err != nil {
+			return nil, err
+		}
+	}
+	return buffer, nil
+}
+
+// appendMap appends a map as repeated key/value entries, sorted by key so equal maps encode the same
+func appendMap(buffer []byte, number protowire.Number, value reflect.Value) ([]byte, error) {
+	keys := value.MapKeys()
+	sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+	for _, key := range keys {
+		entry := protowire.AppendTag(nil, 1, protowire.BytesType)
+		entry = protowire.AppendString(entry, key.String())
+		entry, err := appendField(entry, 2, value.MapIndex(key))
+		if err != nil {
+			return nil, err
+		}
+		buffer = protowire.AppendTag(buffer, number, protowire.BytesType)
+		buffer = protowire.AppendBytes(buffer, entry)
+	}
+	return buffer, nil
+}
+
+// appendValue appends one value with its tag, even when it is the zero value
+func appendValue(buffer []byte, number protowire.Number, value reflect.Value) ([]byte, error) {
+	switch value.Type() {
+	case timeType:
+		timestamp := value.Interface().(time.Time)
+		return appendTime(buffer, number, timestamp.Unix(), int32(timestamp.Nanosecond())), nil
+	case durationType:
+		duration := time.Duration(value.Int())
+		return appendTime(buffer, number, int64(duration/time.Second), int32(duration%time.Second)), nil
+	}
+
+	if value.Kind() == reflect.Struct {
+		content, err := appendMessage(nil, value)
+		if err != nil {
+			return nil, err
+		}
+		buffer = protowire.AppendTag(buffer, number, protowire.BytesType)
+		return protowire.AppendBytes(buffer, content), nil
+	}
+
+	wireType, ok := scalarWireType(value.Type())
+	if !ok {
+		return nil, fmt.Errorf("%s values are not supported", value.Type())
+	}
+	buffer = protowire.AppendTag(buffer, number, wireType)
+	return appendScalar(buffer, value), nil
+}
+
+// appendTime appends a google.protobuf.Timestamp or Duration, which share their layout
+func appendTime(buffer []byte, number protowire.Number, seconds int64, nanos int32) []byte {
+	var content []byte
+	if seconds != 0 {
+		content = protowire.AppendTag(content, 1, protowire.VarintType)
+		content = protowire.AppendVarint(content, uint64(seconds))
+	}
+	if nanos != 0 {
+		content = protowire.AppendTag(content, 2, protowire.VarintType)
+		content = protowire.AppendVarint(content, uint64(int64(nanos)))
+	}
+	buffer = protowire.AppendTag(buffer, number, protowire.BytesType)
+	return protowire.AppendBytes(buffer, content)
+}
+
+// scalarWireType returns how a scalar is encoded, false for messages and unsupported types
+func scalarWireType(goType reflect.Type) (protowire.Type, bool) {
+	if goType == durationType {
+		return 0, false
+	}
+	switch goType.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return protowire.VarintType, true
+	case reflect.Float32:
+		return protowire.Fixed32Type, true
+	case reflect.Float64:
+		return protowire.Fixed64Type, true
+	case reflect.String:
+		return protowire.BytesType, true
+	}
+	return 0, false
+}
+
+// appendScalar appends a scalar without its tag
+// Negative integers are sign-extended to ten bytes, as protobuf's int32 and int64 are
+func appendScalar(buffer []byte, value reflect.Value) []byte {
+	switch value.Kind() {
+	case reflect.Bool:
+		return protowire.AppendVarint(buffer, protowire.EncodeBool(value.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return protowire.AppendVarint(buffer, uint64(value.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return protowire.AppendVarint(buffer, value.Uint())
+	case reflect.Float32:
+		return protowire.AppendFixed32(buffer, math.Float32bits(float32(value.Float())))
+	case reflect.Float64:
+		return protowire.AppendFixed64(buffer, math.Float64bits(value.Float()))
+	default:
+		return protowire.AppendString(buffer, value.String())
+	}
+}
@@ -0,0 +1,209 @@
+package protoexport
+
+import (
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Package is the protobuf package of the exported messages
+const Package = "simplemonitor.export.v1"
+
+// maxFieldNumber bounds derived field numbers so every field tag fits in three bytes
+const maxFieldNumber = 1<<18 - 1
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// layouts caches the layout of every struct encoded so far
+var layouts = struct {
+	messages map[reflect.Type]*message
+	mutex    sync.Mutex
+}{messages: make(map[reflect.Type]*message)}
+
+// fieldNumber derives a field's number from its name, so the numbers of existing fields stay the same
+// when fields are added, removed or reordered and old archives keep decoding; a renamed field gets a new number
+func fieldNumber(name string) protowire.Number {
+	hash := fnv.New32a()
+	hash.Write([]byte(name))
+	number := protowire.Number(hash.Sum32()%maxFieldNumber) + 1
+	if number >= protowire.FirstReservedNumber && number <= protowire.LastReservedNumber {
+		number += protowire.LastReservedNumber - protowire.FirstReservedNumber + 1
+	}
+	return number
+}
+
+// layoutOf returns the layout of a struct type, building it on first use
+func layoutOf(structType reflect.Type) (*message, error) {
+	layouts.mutex.Lock()
+	defer layouts.mutex.Unlock()
+	if layout, ok := layouts.messages[structType]; ok {
+		return layout, nil
+	}
+
+	layout := &message{name: structType.Name()}
+	numbers := make(map[protowire.Number]string)
+	for i := 0; i < structType.NumField(); i++ {
+		structField := structType.Field(i)
+		name, _, _ := strings.Cut(structField.Tag.Get("json"), ",")
+		if !structField.IsExported() || name == "-" {
+			continue
+		}
+		if structField.Anonymous {
+			return nil, fmt.Errorf("%s.%s: embedded fields are not supported", structType.Name(), structField.Name)
+		}
+		if name == "" {
+			name = structField.Name
+		}
+
+		number := fieldNumber(name)
+		if other, taken := numbers[number]; taken {
+			return nil, fmt.Errorf("%s: fields %s and %s hash to the same number %d; rename one of them", structType.Name(), other, name, number)
+		}
+		numbers[number] = name
+		layout.fields = append(layout.fields, field{index: i, name: name, number: number, goType: structField.Type})
+	}
+	layouts.messages[structType] = layout
+	return layout, nil
+}
+
+// Schema returns the .proto definition of the messages the given values are encoded as, with every message they contain
+func Schema(roots ...interface{}) (string, error) {
+	var order []reflect.Type
+	seen := make(map[reflect.Type]bool)
+	names := make(map[string]reflect.Type)
+	imports := make(map[string]bool)
+
+	var visit func(reflect.Type) error
+	visit = func(goType reflect.Type) error {
+		for goType.Kind() == reflect.Ptr || goType.Kind() == reflect.Slice || goType.Kind() == reflect.Map {
+			goType = goType.Elem()
+		}
+		switch {
+		case goType == timeType:
+			imports["google/protobuf/timestamp.proto"] = true
+			return nil
+		case goType == durationType:
+			imports["google/protobuf/duration.proto"] = true
+			return nil
+		case goType.Kind() != reflect.Struct || seen[goType]:
+			return nil
+		}
+		if other, taken := names[goType.Name()]; taken {
+			return fmt.Errorf("%s and %s would both be message %s", other, goType, goType.Name())
+		}
+		seen[goType] = true
+		names[goType.Name()] = goType
+		order = append(order, goType)
+
+		layout, err := layoutOf(goType)
+		if err != nil {
+			return err
+		}
+		for _, field := range layout.fields {
+			if err := visit(field.goType); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, root := range roots {
+		if err := visit(reflect.TypeOf(root)); err != nil {
+			return "", err
+		}
+	}
+
+	var schema strings.Builder
+	schema.WriteString("syntax = \"proto3\";\n\npackage " + Package + ";\n\n")
+	var imported []string
+	for path := range imports {
+		imported = append(imported, path)
+	}
+	sort.Strings(imported)
+	for _, path := range imported {
+		fmt.Fprintf(&schema, "import %q;\n", path)
+	}
+
+	for _, goType := range order {
+		layout, _ := layoutOf(goType)
+		fmt.Fprintf(&schema, "\n// %s mirrors %s\nmessage %s {\n", layout.name, goType, layout.name)
+		for _, field := range layout.fields {
+			typeName, err := protoType(field.goType)
+			if err != nil {
+				return "", fmt.Errorf("%s.%s: %w", layout.name, field.name, err)
+			}
+			fmt.Fprintf(&schema, "  %s %s = %d;\n", typeName, field.name, field.number)
+		}
+		schema.WriteString("}\n")
+	}
+	return schema.String(), nil
+}
+
+// protoType returns the protobuf type of a field, including repeated and map
+func protoType(goType reflect.Type) (string, error) {
+	switch goType.Kind() {
+	case reflect.Slice:
+		if goType.Elem().Kind() == reflect.Uint8 {
+			return "bytes", nil
+		}
+		if goType.Elem().Kind() == reflect.Slice || goType.Elem().Kind() == reflect.Map {
+			return "", fmt.Errorf("lists of lists or maps are not supported")
+		}
+		elem, err := protoType(goType.Elem())
+		return "repeated " + elem, err
+	case reflect.Map:
+		if goType.Key().Kind() != reflect.String {
+			return "", fmt.Errorf("only maps with string keys are supported")
+		}
+		if goType.Elem().Kind() == reflect.Slice || goType.Elem().Kind() == reflect.Map {
+			return "", fmt.Errorf("maps of lists or maps are not supported")
+		}
+		value, err := protoType(goType.Elem())
+		return "map<string, " + value + ">", err
+	case reflect.Ptr:
+		if goType.Elem().Kind() != reflect.Struct {
+			return "", fmt.Errorf("pointers to %s are not supported", goType.Elem())
+		}
+		return protoType(goType.Elem())
+	}
+	return scalarType(goType)
+}
+
+// scalarType returns the protobuf type of a single value
+func scalarType(goType reflect.Type) (string, error) {
+	switch goType {
+	case timeType:
+		return "google.protobuf.Timestamp", nil
+	case durationType:
+		return "google.protobuf.Duration", nil
+	}
+	switch goType.Kind() {
+	case reflect.Bool:
+		return "bool", nil
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		return "int32", nil
+	case reflect.Int, reflect.Int64:
+		return "int64", nil
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return "uint32", nil
+	case reflect.Uint, reflect.Uint64:
+		return "uint64", nil
+	case reflect.Float32:
+		return "float", nil
+	case reflect.Float64:
+		return "double", nil
+	case reflect.String:
+		return "string", nil
+	case reflect.Struct:
+		return goType.Name(), nil
+	}
+	return "", fmt.Errorf("%s values are not supported", goType)
+}
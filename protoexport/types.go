@@ -0,0 +1,21 @@
+package protoexport
+
+import (
+	"reflect"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// message is the protobuf layout of one Go struct
+type message struct {
+	name   string  // Message name: the Go type name
+	fields []field // Exported fields in struct order
+}
+
+// field is one struct field with the name and number it has in protobuf
+type field struct {
+	index  int              // Position in the struct
+	name   string           // JSON name, which is also the protobuf field name
+	number protowire.Number // Derived from the name, see fieldNumber
+	goType reflect.Type
+}
@@ -0,0 +1,334 @@
+package provider
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/disk"
+	"github.com/shirou/gopsutil/v3/load"
+	"github.com/shirou/gopsutil/v3/mem"
+	"github.com/shirou/gopsutil/v3/net"
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// Faults injects errors into a fake, keyed by method name ("Percent", "Usage", "Username"...)
+// A method with an entry returns its error instead of the canned data
+type Faults map[string]error
+
+// fault returns the error injected for a method, or nil
+func (faults Faults) fault(method string) error {
+	return faults[method]
+}
+
+// FakeCPU returns canned processor data; Percent returns at once instead of waiting the interval
+type FakeCPU struct {
+	PhysicalCores int
+	LogicalCores  int
+	InfoStats     []cpu.InfoStat
+	TotalPercent  []float64 // Percent(_, false)
+	PerCPUPercent []float64 // Percent(_, true)
+	TotalTimes    []cpu.TimesStat
+	PerCPUTimes   []cpu.TimesStat
+	Load          *load.AvgStat
+	Misc          *load.MiscStat
+	Faults        Faults
+}
+
+// FakeMem returns canned memory and swap usage
+type FakeMem struct {
+	Virtual *mem.VirtualMemoryStat
+	Swap    *mem.SwapMemoryStat
+	Faults  Faults
+}
+
+// FakeDisk returns canned partitions, usage by mountpoint and I/O counters by device
+type FakeDisk struct {
+	PartitionStats []disk.PartitionStat
+	UsageStats     map[string]*disk.UsageStat
+	IOStats        map[string]disk.IOCountersStat
+	Faults         Faults
+}
+
+// FakeNet returns canned interfaces, per-interface counters and connections
+type FakeNet struct {
+	InterfaceStats  net.InterfaceStatList
+	IOStats         []net.IOCountersStat
+	ConnectionStats []net.ConnectionStat
+	Faults          Faults
+}
+
+// FakeProc returns a canned process table
+type FakeProc struct {
+	Table  []*FakeProcess
+	Faults Faults
+}
+
+// FakeProcess is one process of a fake process table
+type FakeProcess struct {
+	Pid         int32
+	ParentPid   int32
+	ProcessName string
+	CommandLine string
+	WorkingDir  string
+	Executable  string
+	States      []string
+	User        string
+	Created     int64 // Milliseconds since the epoch
+	NiceValue   int32
+	CPU         float64
+	CPUTimes    *cpu.TimesStat // Accumulated user and system time in seconds
+	Memory      *process.MemoryInfoStat
+	MemoryShare float32
+	Threads     int32
+	FDs         int32
+	Children    int
+	IO          *process.IOCountersStat
+	CtxSwitches *process.NumCtxSwitchesStat
+	Paging      *process.PageFaultsStat
+	Files       []process.OpenFilesStat
+	Limits      []process.RlimitStat
+	Faults      Faults
+}
+
+// Fake bundles empty fakes of every kind, ready to be filled in
+type Fake struct {
+	CPU  *FakeCPU
+	Mem  *FakeMem
+	Disk *FakeDisk
+	Net  *FakeNet
+	Proc *FakeProc
+}
+
+// NewFake creates a fake system with no data and no injected errors
+func NewFake() *Fake {
+	return &Fake{
+		CPU:  &FakeCPU{Faults: Faults{}},
+		Mem:  &FakeMem{Virtual: &mem.VirtualMemoryStat{}, Swap: &mem.SwapMemoryStat{}, Faults: Faults{}},
+		Disk: &FakeDisk{UsageStats: map[string]*disk.UsageStat{}, IOStats: map[string]disk.IOCountersStat{}, Faults: Faults{}},
+		Net:  &FakeNet{Faults: Faults{}},
+		Proc: &FakeProc{Faults: Faults{}},
+	}
+}
+
+// Providers returns the fakes as a provider bundle to hand to a collector
+func (fake *Fake) Providers() Providers {
+	return Providers{CPU: fake.CPU, Mem: fake.Mem, Disk: fake.Disk, Net: fake.Net, Proc: fake.Proc}
+}
+
+func (fake *FakeCPU) Counts(logical bool) (int, error) {
+	if logical {
+		return fake.LogicalCores, fake.Faults.fault("Counts")
+	}
+	return fake.PhysicalCores, fake.Faults.fault("Counts")
+}
+
+func (fake *FakeCPU) Info() ([]cpu.InfoStat, error) {
+	return fake.InfoStats, fake.Faults.fault("Info")
+}
+
+func (fake *FakeCPU) Percent(interval time.Duration, perCPU bool) ([]float64, error) {
+	if perCPU {
+		return fake.PerCPUPercent, fake.Faults.fault("Percent")
+	}
+	return fake.TotalPercent, fake.Faults.fault("Percent")
+}
+
+func (fake *FakeCPU) Times(perCPU bool) ([]cpu.TimesStat, error) {
+	if perCPU {
+		return fake.PerCPUTimes, fake.Faults.fault("Times")
+	}
+	return fake.TotalTimes, fake.Faults.fault("Times")
+}
+
+func (fake *FakeCPU) LoadAvg() (*load.AvgStat, error) {
+	if err := fake.Faults.fault("LoadAvg"); err != nil || fake.Load == nil {
+		return nil, missing("LoadAvg", err)
+	}
+	return fake.Load, nil
+}
+
+func (fake *FakeCPU) LoadMisc() (*load.MiscStat, error) {
+	if err := fake.Faults.fault("LoadMisc"); err != nil || fake.Misc == nil {
+		return nil, missing("LoadMisc", err)
+	}
+	return fake.Misc, nil
+}
+
+func (fake *FakeMem) VirtualMemory() (*mem.VirtualMemoryStat, error) {
+	if err := fake.Faults.fault("VirtualMemory"); err != nil || fake.Virtual == nil {
+		return nil, missing("VirtualMemory", err)
+	}
+	return fake.Virtual, nil
+}
+
+func (fake *FakeMem) SwapMemory() (*mem.SwapMemoryStat, error) {
+	if err := fake.Faults.fault("SwapMemory"); err != nil || fake.Swap == nil {
+		return nil, missing("SwapMemory", err)
+	}
+	return fake.Swap, nil
+}
+
+func (fake *FakeDisk) Partitions(all bool) ([]disk.PartitionStat, error) {
+	return fake.PartitionStats, fake.Faults.fault("Partitions")
+}
+
+func (fake *FakeDisk) Usage(path string) (*disk.UsageStat, error) {
+	usage, ok := fake.UsageStats[path]
+	if err := fake.Faults.fault("Usage"); err != nil || !ok {
+		return nil, missing("Usage "+path, err)
+	}
+	return usage, nil
+}
+
+// IOCounters returns the counters of the named devices, or of every device when none are named
+func (fake *FakeDisk) IOCounters(names ...string) (map[string]disk.IOCountersStat, error) {
+	if err := fake.Faults.fault("IOCounters"); err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return fake.IOStats, nil
+	}
+
+	counters := make(map[string]disk.IOCountersStat)
+	for _, name := range names {
+		if stat, ok := fake.IOStats[name]; ok {
+			counters[name] = stat
+		}
+	}
+	return counters, nil
+}
+
+func (fake *FakeNet) Interfaces() (net.InterfaceStatList, error) {
+	return fake.InterfaceStats, fake.Faults.fault("Interfaces")
+}
+
+// IOCounters returns the per-interface counters, or one "all" entry summing them when perNIC is false
+func (fake *FakeNet) IOCounters(perNIC bool) ([]net.IOCountersStat, error) {
+	if err := fake.Faults.fault("IOCounters"); err != nil {
+		return nil, err
+	}
+	if perNIC {
+		return fake.IOStats, nil
+	}
+
+	total := net.IOCountersStat{Name: "all"}
+	for _, stat := range fake.IOStats {
+		total.BytesSent += stat.BytesSent
+		total.BytesRecv += stat.BytesRecv
+		total.PacketsSent += stat.PacketsSent
+		total.PacketsRecv += stat.PacketsRecv
+		total.Errin += stat.Errin
+		total.Errout += stat.Errout
+		total.Dropin += stat.Dropin
+		total.Dropout += stat.Dropout
+	}
+	return []net.IOCountersStat{total}, nil
+}
+
+// Connections returns every canned connection; kind is not filtered on
+func (fake *FakeNet) Connections(kind string) ([]net.ConnectionStat, error) {
+	return fake.ConnectionStats, fake.Faults.fault("Connections")
+}
+
+func (fake *FakeProc) Processes() ([]Process, error) {
+	if err := fake.Faults.fault("Processes"); err != nil {
+		return nil, err
+	}
+
+	processes := make([]Process, len(fake.Table))
+	for i, p := range fake.Table {
+		processes[i] = p
+	}
+	return processes, nil
+}
+
+func (fake *FakeProc) NewProcess(pid int32) (Process, error) {
+	if err := fake.Faults.fault("NewProcess"); err != nil {
+		return nil, err
+	}
+	for _, p := range fake.Table {
+		if p.Pid == pid {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("process %d not found", pid)
+}
+
+func (p *FakeProcess) PID() int32 { return p.Pid }
+
+func (p *FakeProcess) Ppid() (int32, error)      { return p.ParentPid, p.Faults.fault("Ppid") }
+func (p *FakeProcess) Name() (string, error)     { return p.ProcessName, p.Faults.fault("Name") }
+func (p *FakeProcess) Cmdline() (string, error)  { return p.CommandLine, p.Faults.fault("Cmdline") }
+func (p *FakeProcess) Cwd() (string, error)      { return p.WorkingDir, p.Faults.fault("Cwd") }
+func (p *FakeProcess) Exe() (string, error)      { return p.Executable, p.Faults.fault("Exe") }
+func (p *FakeProcess) Username() (string, error) { return p.User, p.Faults.fault("Username") }
+func (p *FakeProcess) CreateTime() (int64, error) {
+	return p.Created, p.Faults.fault("CreateTime")
+}
+func (p *FakeProcess) Nice() (int32, error)         { return p.NiceValue, p.Faults.fault("Nice") }
+func (p *FakeProcess) CPUPercent() (float64, error) { return p.CPU, p.Faults.fault("CPUPercent") }
+func (p *FakeProcess) MemoryPercent() (float32, error) {
+	return p.MemoryShare, p.Faults.fault("MemoryPercent")
+}
+func (p *FakeProcess) NumThreads() (int32, error) { return p.Threads, p.Faults.fault("NumThreads") }
+func (p *FakeProcess) NumFDs() (int32, error)     { return p.FDs, p.Faults.fault("NumFDs") }
+func (p *FakeProcess) NumChildren() (int, error)  { return p.Children, p.Faults.fault("NumChildren") }
+func (p *FakeProcess) OpenFiles() ([]process.OpenFilesStat, error) {
+	return p.Files, p.Faults.fault("OpenFiles")
+}
+func (p *FakeProcess) Rlimit() ([]process.RlimitStat, error) {
+	return p.Limits, p.Faults.fault("Rlimit")
+}
+
+// Status, Times, MemoryInfo, IOCounters, NumCtxSwitches and PageFaults fail when their data is not set, as unreadable ones do
+
+func (p *FakeProcess) Status() ([]string, error) {
+	if err := p.Faults.fault("Status"); err != nil || len(p.States) == 0 {
+		return nil, missing("Status", err)
+	}
+	return p.States, nil
+}
+
+func (p *FakeProcess) Times() (*cpu.TimesStat, error) {
+	if err := p.Faults.fault("Times"); err != nil || p.CPUTimes == nil {
+		return nil, missing("Times", err)
+	}
+	return p.CPUTimes, nil
+}
+
+func (p *FakeProcess) MemoryInfo() (*process.MemoryInfoStat, error) {
+	if err := p.Faults.fault("MemoryInfo"); err != nil || p.Memory == nil {
+		return nil, missing("MemoryInfo", err)
+	}
+	return p.Memory, nil
+}
+
+func (p *FakeProcess) IOCounters() (*process.IOCountersStat, error) {
+	if err := p.Faults.fault("IOCounters"); err != nil || p.IO == nil {
+		return nil, missing("IOCounters", err)
+	}
+	return p.IO, nil
+}
+
+func (p *FakeProcess) NumCtxSwitches() (*process.NumCtxSwitchesStat, error) {
+	if err := p.Faults.fault("NumCtxSwitches"); err != nil || p.CtxSwitches == nil {
+		return nil, missing("NumCtxSwitches", err)
+	}
+	return p.CtxSwitches, nil
+}
+
+func (p *FakeProcess) PageFaults() (*process.PageFaultsStat, error) {
+	if err := p.Faults.fault("PageFaults"); err != nil || p.Paging == nil {
+		return nil, missing("PageFaults", err)
+	}
+	return p.Paging, nil
+}
+
+// missing returns the injected error, or a not-set error when the fake has no data to return
+func missing(what string, err error) error {
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("fake %s is not set", what)
+}
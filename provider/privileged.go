@@ -0,0 +1,69 @@
+package provider
+
+import (
+	"errors"
+	"github.com/ahmadreza-log/simple-monitor/privhelper"
+	"io/fs"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// privilegedDetails asks the privileged helper for a process this program was denied access to
+// It returns false when the error is not a permission error, no helper is connected, or the helper failed too
+func privilegedDetails(pid int32, err error, part string) (*privhelper.ProcessDetails, bool) {
+	if err == nil || !errors.Is(err, fs.ErrPermission) {
+		return nil, false
+	}
+	client := privhelper.Active()
+	if client == nil {
+		return nil, false
+	}
+	details, helperErr := client.Process(pid)
+	if helperErr != nil || details == nil || details.Errors[part] != "" {
+		return nil, false
+	}
+	return details, true
+}
+
+// Exe, Cwd, NumFDs, IOCounters and OpenFiles need elevated access for other users' processes,
+// so a permission error is retried through the privileged helper when one is connected
+
+func (p systemProcess) Exe() (string, error) {
+	exe, err := p.Process.Exe()
+	if details, ok := privilegedDetails(p.Pid, err, "exe"); ok {
+		return details.Exe, nil
+	}
+	return exe, err
+}
+
+func (p systemProcess) Cwd() (string, error) {
+	cwd, err := p.Process.Cwd()
+	if details, ok := privilegedDetails(p.Pid, err, "cwd"); ok {
+		return details.Cwd, nil
+	}
+	return cwd, err
+}
+
+func (p systemProcess) NumFDs() (int32, error) {
+	fds, err := p.Process.NumFDs()
+	if details, ok := privilegedDetails(p.Pid, err, "fds"); ok {
+		return details.NumFDs, nil
+	}
+	return fds, err
+}
+
+func (p systemProcess) IOCounters() (*process.IOCountersStat, error) {
+	counters, err := p.Process.IOCounters()
+	if details, ok := privilegedDetails(p.Pid, err, "io"); ok && details.IO != nil {
+		return details.IO, nil
+	}
+	return counters, err
+}
+
+func (p systemProcess) OpenFiles() ([]process.OpenFilesStat, error) {
+	files, err := p.Process.OpenFiles()
+	if details, ok := privilegedDetails(p.Pid, err, "open_files"); ok {
+		return details.OpenFiles, nil
+	}
+	return files, err
+}
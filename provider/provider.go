@@ -0,0 +1,97 @@
+// Package provider defines the interfaces the collectors read the system through, with gopsutil-backed
+// implementations and fakes for tests
+package provider
+
+import (
+	"time"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/disk"
+	"github.com/shirou/gopsutil/v3/load"
+	"github.com/shirou/gopsutil/v3/mem"
+	"github.com/shirou/gopsutil/v3/net"
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// systemCPU, systemMem, systemDisk, systemNet and systemProc read the real system through gopsutil
+type (
+	systemCPU  struct{}
+	systemMem  struct{}
+	systemDisk struct{}
+	systemNet  struct{}
+	systemProc struct{}
+)
+
+// systemProcess adapts a gopsutil process to the Process interface
+type systemProcess struct {
+	*process.Process
+}
+
+// System returns the providers that read the real system, the default of every collector
+func System() Providers {
+	return Providers{
+		CPU:  systemCPU{},
+		Mem:  systemMem{},
+		Disk: systemDisk{},
+		Net:  systemNet{},
+		Proc: systemProc{},
+	}
+}
+
+func (systemCPU) Counts(logical bool) (int, error) { return cpu.Counts(logical) }
+func (systemCPU) Info() ([]cpu.InfoStat, error)    { return cpu.Info() }
+func (systemCPU) Percent(interval time.Duration, perCPU bool) ([]float64, error) {
+	return cpu.Percent(interval, perCPU)
+}
+func (systemCPU) Times(perCPU bool) ([]cpu.TimesStat, error) { return cpu.Times(perCPU) }
+func (systemCPU) LoadAvg() (*load.AvgStat, error)            { return load.Avg() }
+func (systemCPU) LoadMisc() (*load.MiscStat, error)          { return load.Misc() }
+
+func (systemMem) VirtualMemory() (*mem.VirtualMemoryStat, error) { return mem.VirtualMemory() }
+func (systemMem) SwapMemory() (*mem.SwapMemoryStat, error)       { return mem.SwapMemory() }
+
+func (systemDisk) Partitions(all bool) ([]disk.PartitionStat, error) { return disk.Partitions(all) }
+func (systemDisk) Usage(path string) (*disk.UsageStat, error)        { return disk.Usage(path) }
+func (systemDisk) IOCounters(names ...string) (map[string]disk.IOCountersStat, error) {
+	return disk.IOCounters(names...)
+}
+
+func (systemNet) Interfaces() (net.InterfaceStatList, error)           { return net.Interfaces() }
+func (systemNet) IOCounters(perNIC bool) ([]net.IOCountersStat, error) { return net.IOCounters(perNIC) }
+func (systemNet) Connections(kind string) ([]net.ConnectionStat, error) {
+	return net.Connections(kind)
+}
+
+// Processes lists every running process
+func (systemProc) Processes() ([]Process, error) {
+	processes, err := process.Processes()
+	if err != nil {
+		return nil, err
+	}
+
+	wrapped := make([]Process, len(processes))
+	for i, p := range processes {
+		wrapped[i] = systemProcess{p}
+	}
+	return wrapped, nil
+}
+
+// NewProcess opens the process with the given PID, failing when it does not exist
+func (systemProc) NewProcess(pid int32) (Process, error) {
+	p, err := process.NewProcess(pid)
+	if err != nil {
+		return nil, err
+	}
+	return systemProcess{p}, nil
+}
+
+// PID returns the process ID
+func (p systemProcess) PID() int32 {
+	return p.Pid
+}
+
+// NumChildren counts the direct children of the process
+func (p systemProcess) NumChildren() (int, error) {
+	children, err := p.Children()
+	return len(children), err
+}
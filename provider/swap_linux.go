@@ -0,0 +1,50 @@
+//go:build linux
+
+package provider
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// MemoryInfo returns the memory use of the process, with Swap read from VmSwap in /proc/<pid>/status
+// gopsutil only reads /proc/<pid>/statm on Linux, which has no swap figure, so Swap would always be 0
+func (p systemProcess) MemoryInfo() (*process.MemoryInfoStat, error) {
+	info, err := p.Process.MemoryInfo()
+	if err != nil {
+		return nil, err
+	}
+	if swap, ok := readVmSwap(fmt.Sprintf("/proc/%d/status", p.Pid)); ok {
+		info.Swap = swap
+	}
+	return info, nil
+}
+
+// readVmSwap returns the VmSwap line of a /proc/<pid>/status file in bytes
+// Kernel threads have no VmSwap line, and ok is false for them
+func readVmSwap(path string) (uint64, bool) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, false
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		value, found := strings.CutPrefix(scanner.Text(), "VmSwap:")
+		if !found {
+			continue
+		}
+		kilobytes, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimSpace(value), " kB"), 10, 64)
+		if err != nil {
+			return 0, false
+		}
+		return kilobytes * 1024, true
+	}
+	return 0, false
+}
@@ -0,0 +1,20 @@
+//go:build windows
+
+package provider
+
+import (
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// MemoryInfo returns the memory use of the process, with Swap set to its pagefile usage
+// gopsutil fills VMS from PagefileUsage of GetProcessMemoryInfo and leaves Swap at 0; the pagefile
+// usage is the private memory the process has committed against the pagefile, the closest figure
+// Windows keeps per process
+func (p systemProcess) MemoryInfo() (*process.MemoryInfoStat, error) {
+	info, err := p.Process.MemoryInfo()
+	if err != nil {
+		return nil, err
+	}
+	info.Swap = info.VMS
+	return info, nil
+}
@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"time"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/disk"
+	"github.com/shirou/gopsutil/v3/load"
+	"github.com/shirou/gopsutil/v3/mem"
+	"github.com/shirou/gopsutil/v3/net"
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// CPUProvider reads processor counts, usage and load
+type CPUProvider interface {
+	Counts(logical bool) (int, error)
+	Info() ([]cpu.InfoStat, error)
+	Percent(interval time.Duration, perCPU bool) ([]float64, error)
+	Times(perCPU bool) ([]cpu.TimesStat, error)
+	LoadAvg() (*load.AvgStat, error)
+	LoadMisc() (*load.MiscStat, error)
+}
+
+// MemProvider reads physical memory and swap usage
+type MemProvider interface {
+	VirtualMemory() (*mem.VirtualMemoryStat, error)
+	SwapMemory() (*mem.SwapMemoryStat, error)
+}
+
+// DiskProvider reads partitions, their usage and per-device I/O counters
+type DiskProvider interface {
+	Partitions(all bool) ([]disk.PartitionStat, error)
+	Usage(path string) (*disk.UsageStat, error)
+	IOCounters(names ...string) (map[string]disk.IOCountersStat, error)
+}
+
+// NetProvider reads network interfaces, their counters and open connections
+type NetProvider interface {
+	Interfaces() (net.InterfaceStatList, error)
+	IOCounters(perNIC bool) ([]net.IOCountersStat, error)
+	Connections(kind string) ([]net.ConnectionStat, error)
+}
+
+// ProcProvider lists processes and opens one by PID
+type ProcProvider interface {
+	Processes() ([]Process, error)
+	NewProcess(pid int32) (Process, error)
+}
+
+// Process is one running process, with the subset of gopsutil's process methods the collectors use
+type Process interface {
+	PID() int32
+	Ppid() (int32, error)
+	Name() (string, error)
+	Cmdline() (string, error)
+	Cwd() (string, error)
+	Exe() (string, error)
+	Status() ([]string, error)
+	Username() (string, error)
+	CreateTime() (int64, error)
+	Nice() (int32, error)
+	CPUPercent() (float64, error)
+	Times() (*cpu.TimesStat, error)
+	MemoryInfo() (*process.MemoryInfoStat, error)
+	MemoryPercent() (float32, error)
+	NumThreads() (int32, error)
+	NumFDs() (int32, error)
+	NumChildren() (int, error)
+	IOCounters() (*process.IOCountersStat, error)
+	NumCtxSwitches() (*process.NumCtxSwitchesStat, error)
+	PageFaults() (*process.PageFaultsStat, error)
+	OpenFiles() ([]process.OpenFilesStat, error)
+	Rlimit() ([]process.RlimitStat, error)
+}
+
+// Providers bundles one provider of each kind, so a collector can be pointed at a fake system in one call
+type Providers struct {
+	CPU  CPUProvider
+	Mem  MemProvider
+	Disk DiskProvider
+	Net  NetProvider
+	Proc ProcProvider
+}
@@ -0,0 +1,225 @@
+// Package quicktest runs the all-monitors quick test on a schedule, keeps a history of the results and
+// raises an alert when a monitor cannot be collected
+package quicktest
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/ahmadreza-log/simple-monitor/alert"
+	"github.com/ahmadreza-log/simple-monitor/partial"
+	"github.com/ahmadreza-log/simple-monitor/snapshot"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// HistoryPath is where every scheduled quick test is appended, one JSON result per line
+var HistoryPath = filepath.Join("logs", "quicktests", "history.jsonl")
+
+// Name of the scheduled quick test's alert, raised while a monitor cannot be collected
+const (
+	AlertMonitor = "quicktest"
+	AlertKey     = "collection_failures"
+)
+
+// scheduler is off until a schedule is set
+var scheduler = schedulerState{config: Config{Schedule: []string{}}}
+
+// tracker follows the failures alert across scheduled runs, so hooks fire when it is raised and cleared
+var tracker = alert.NewTracker(AlertMonitor)
+
+// Run collects the monitors once into a combined snapshot and reports what could not be collected
+// A monitor whose collection failed fails the test; sections a monitor could not collect (often for
+// lack of permissions) are listed but do not
+func Run(monitors snapshot.Monitors) Result {
+	state := snapshot.Collect(monitors)
+	result := Result{
+		Time:          state.Timestamp,
+		Duration:      state.CollectionDuration,
+		Passed:        len(state.Errors) == 0,
+		Failures:      state.Errors,
+		SectionErrors: make(map[string]partial.Errors),
+	}
+	if state.Score != nil {
+		result.Score = state.Score.Value
+		result.ScoreStatus = state.Score.Status
+	}
+
+	if state.CPU != nil && len(state.CPU.SectionErrors) > 0 {
+		result.SectionErrors["cpu"] = state.CPU.SectionErrors
+	}
+	if state.Memory != nil && len(state.Memory.SectionErrors) > 0 {
+		result.SectionErrors["memory"] = state.Memory.SectionErrors
+	}
+	if state.Disk != nil && len(state.Disk.SectionErrors) > 0 {
+		result.SectionErrors["disk"] = state.Disk.SectionErrors
+	}
+	if state.Network != nil && len(state.Network.SectionErrors) > 0 {
+		result.SectionErrors["network"] = state.Network.SectionErrors
+	}
+	if state.Process != nil && len(state.Process.SectionErrors) > 0 {
+		result.SectionErrors["process"] = state.Process.SectionErrors
+	}
+
+	tested := []struct {
+		name    string
+		enabled bool
+	}{
+		{"cpu", monitors.CPU != nil},
+		{"memory", monitors.Memory != nil},
+		{"disk", monitors.Disk != nil},
+		{"network", monitors.Network != nil},
+		{"process", monitors.Process != nil},
+	}
+	for _, monitor := range tested {
+		if monitor.enabled {
+			result.Monitors = append(result.Monitors, monitor.name)
+		}
+	}
+	if len(result.SectionErrors) == 0 {
+		result.SectionErrors = nil
+	}
+	return result
+}
+
+// Summary describes a result in one line, e.g. "passed: 5 monitors, score 92 (Normal) in 1.234s"
+func (result Result) Summary() string {
+	summary := fmt.Sprintf("%d monitors, score %.0f (%s) in %s",
+		len(result.Monitors), result.Score, result.ScoreStatus, result.Duration.Round(time.Millisecond))
+	if !result.Passed {
+		return "failed: " + result.FailureText() + "; " + summary
+	}
+	return "passed: " + summary
+}
+
+// FailureText lists the failed monitors with their errors, ordered by monitor name
+func (result Result) FailureText() string {
+	names := make([]string, 0, len(result.Failures))
+	for name := range result.Failures {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, name+": "+result.Failures[name])
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Append adds a result to the history file at path as one JSON line
+func Append(path string, result Result) error {
+	line, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to encode quick test result: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// SetConfig replaces the schedule; every time must be a valid cron expression
+func SetConfig(config Config) error {
+	var times []alert.Window
+	for _, spec := range config.Schedule {
+		window, err := alert.ParseTimes(spec)
+		if err != nil {
+			return err
+		}
+		times = append(times, window)
+	}
+
+	scheduler.mutex.Lock()
+	scheduler.config = config
+	scheduler.times = times
+	scheduler.mutex.Unlock()
+	return nil
+}
+
+// GetConfig returns the schedule
+func GetConfig() Config {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+	config := scheduler.config
+	config.Schedule = append([]string{}, config.Schedule...)
+	return config
+}
+
+// Start runs a quick test at every scheduled minute until the program exits
+// monitors is called before each run for the managers to collect. Each result is appended to HistoryPath
+// and raises or clears the collection_failures alert; report, when not nil, is then called with the
+// result and the error of appending it
+func Start(monitors func() snapshot.Monitors, report func(Result, error)) {
+	scheduler.mutex.Lock()
+	started := scheduler.monitors != nil
+	scheduler.monitors = monitors
+	scheduler.report = report
+	scheduler.mutex.Unlock()
+	if started {
+		return
+	}
+
+	go func() {
+		for {
+			// Schedules have minute resolution, so the loop wakes at the start of every minute
+			// and a changed schedule applies from the next one
+			next := time.Now().Truncate(time.Minute).Add(time.Minute)
+			time.Sleep(time.Until(next))
+			if due(next) {
+				runScheduled(next)
+			}
+		}
+	}()
+}
+
+// due returns whether any scheduled time starts at minute
+func due(minute time.Time) bool {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+	for _, window := range scheduler.times {
+		if window.Starts(minute) {
+			return true
+		}
+	}
+	return false
+}
+
+// runScheduled runs one scheduled quick test, records it and alerts on its failures
+func runScheduled(minute time.Time) {
+	scheduler.mutex.Lock()
+	monitors, report := scheduler.monitors, scheduler.report
+	scheduler.mutex.Unlock()
+
+	result := Run(monitors())
+	err := Append(HistoryPath, result)
+
+	// Every failed monitor makes the alert critical; the errors are passed to hooks as the note
+	tracker.Evaluate(AlertKey, float64(len(result.Failures)), 1, 1, alert.Rule{Note: result.FailureText()}, minute)
+
+	scheduler.mutex.Lock()
+	scheduler.lastResult = &result
+	scheduler.lastError = err
+	scheduler.mutex.Unlock()
+	if report != nil {
+		report(result, err)
+	}
+}
+
+// LastResult returns the most recent scheduled quick test and the error of appending it to the history,
+// or nil before the first one
+func LastResult() (*Result, error) {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+	return scheduler.lastResult, scheduler.lastError
+}
@@ -0,0 +1,37 @@
+package quicktest
+
+import (
+	"github.com/ahmadreza-log/simple-monitor/alert"
+	"github.com/ahmadreza-log/simple-monitor/partial"
+	"github.com/ahmadreza-log/simple-monitor/snapshot"
+	"sync"
+	"time"
+)
+
+// Config holds the times scheduled quick tests run at
+type Config struct {
+	Schedule []string `json:"schedule"` // Cron times: "<minute> <hour> <day> <month> <weekday>", e.g. "0 * * * *" for hourly (empty turns scheduling off)
+}
+
+// Result is one quick test: a combined snapshot of the enabled monitors and what could not be collected
+type Result struct {
+	Time          time.Time                 `json:"time"`                     // When the snapshot was taken
+	Duration      time.Duration             `json:"duration"`                 // Time taken to collect all monitors
+	Passed        bool                      `json:"passed"`                   // Whether every monitor was collected
+	Monitors      []string                  `json:"monitors"`                 // Monitors that were tested
+	Score         float64                   `json:"score"`                    // Health score of the snapshot
+	ScoreStatus   string                    `json:"score_status"`             // Normal, Warning, Critical or Unknown
+	Failures      map[string]string         `json:"failures,omitempty"`       // Collection errors keyed by monitor name
+	SectionErrors map[string]partial.Errors `json:"section_errors,omitempty"` // Sections that could not be collected, keyed by monitor name
+}
+
+// schedulerState holds the settings and the background scheduling loop
+type schedulerState struct {
+	config     Config
+	times      []alert.Window
+	monitors   func() snapshot.Monitors
+	report     func(Result, error)
+	lastResult *Result
+	lastError  error // Why the last result could not be appended to HistoryPath
+	mutex      sync.Mutex
+}
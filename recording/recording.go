@@ -0,0 +1,299 @@
+// Package recording records live monitoring sessions for replay and publishes the live feed that
+// viewer instances follow
+package recording
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FileSuffix ends the name of every recording file
+const FileSuffix = ".rec.gz"
+
+// MaxReplayDelay caps the pause between two frames during replay
+// Gaps longer than this (e.g. the user sat in a menu between live sessions) are skipped over
+const MaxReplayDelay = 5 * time.Second
+
+// Speeds lists the replay speeds, slowest first
+var Speeds = []float64{0.25, 0.5, 1, 2, 4, 8, 16}
+
+// Start creates a new recording file in dir named after the current time
+func Start(dir string) (*Recorder, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create recordings directory: %w", err)
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("session_%s%s", time.Now().Format("20060102_150405"), FileSuffix))
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create recording file: %w", err)
+	}
+
+	compressor := gzip.NewWriter(file)
+	return &Recorder{
+		path:       path,
+		file:       file,
+		compressor: compressor,
+		encoder:    json.NewEncoder(compressor),
+	}, nil
+}
+
+// NewFeed creates a recorder without a session file that only keeps the latest frame of each monitor in dir
+func NewFeed(dir string) (*Recorder, error) {
+	recorder := &Recorder{}
+	if err := recorder.SetFeed(dir); err != nil {
+		return nil, err
+	}
+	return recorder, nil
+}
+
+// SetFeed also keeps the latest frame of each monitor in dir, as <monitor>.json
+// Frames replace the previous file through a rename, so readers never see a half-written frame
+func (recorder *Recorder) SetFeed(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create live feed directory: %w", err)
+	}
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+	recorder.feedDir = dir
+	return nil
+}
+
+// LatestFrame reads the most recently written frame in a feed directory
+func LatestFrame(dir string) (Frame, error) {
+	var frame Frame
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return frame, fmt.Errorf("failed to read live feed: %w", err)
+	}
+
+	var newest string
+	var newestTime time.Time
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		if info, err := entry.Info(); err == nil && info.ModTime().After(newestTime) {
+			newest, newestTime = entry.Name(), info.ModTime()
+		}
+	}
+	if newest == "" {
+		return frame, fmt.Errorf("live feed %s has no frames yet", dir)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, newest))
+	if err != nil {
+		return frame, fmt.Errorf("failed to read live feed: %w", err)
+	}
+	if err := json.Unmarshal(content, &frame); err != nil {
+		return frame, fmt.Errorf("failed to decode live feed %s: %w", newest, err)
+	}
+	return frame, nil
+}
+
+// writeFeed replaces the feed file of the frame's monitor
+func writeFeed(dir string, frame Frame) error {
+	content, err := json.Marshal(frame)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s feed frame: %w", frame.Monitor, err)
+	}
+	path := filepath.Join(dir, frame.Monitor+".json")
+	if err := os.WriteFile(path+".tmp", content, 0644); err != nil {
+		return fmt.Errorf("failed to write %s feed frame: %w", frame.Monitor, err)
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return fmt.Errorf("failed to write %s feed frame: %w", frame.Monitor, err)
+	}
+	return nil
+}
+
+// Record appends one collection cycle of the given monitor
+// A nil recorder records nothing, so managers can call it unconditionally
+func (recorder *Recorder) Record(monitor string, data interface{}) error {
+	if recorder == nil {
+		return nil
+	}
+
+	content, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s frame: %w", monitor, err)
+	}
+
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+	frame := Frame{Time: time.Now(), Monitor: monitor, Data: content}
+	if recorder.feedDir != "" {
+		if err := writeFeed(recorder.feedDir, frame); err != nil {
+			return err
+		}
+	}
+	if recorder.file == nil {
+		if recorder.feedDir != "" {
+			return nil
+		}
+		return errors.New("recording is closed")
+	}
+
+	if err := recorder.encoder.Encode(frame); err != nil {
+		return fmt.Errorf("failed to write %s frame: %w", monitor, err)
+	}
+	if err := recorder.compressor.Flush(); err != nil {
+		return fmt.Errorf("failed to write %s frame: %w", monitor, err)
+	}
+	recorder.frames++
+	return nil
+}
+
+// Close finishes the recording file
+func (recorder *Recorder) Close() error {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+	if recorder.file == nil {
+		return nil
+	}
+
+	err := recorder.compressor.Close()
+	if closeErr := recorder.file.Close(); err == nil {
+		err = closeErr
+	}
+	recorder.file = nil
+	if err != nil {
+		return fmt.Errorf("failed to finish recording: %w", err)
+	}
+	return nil
+}
+
+// Path returns the recording file, or "" for a recorder that only feeds viewers
+func (recorder *Recorder) Path() string {
+	return recorder.path
+}
+
+// Frames returns the number of frames recorded so far
+func (recorder *Recorder) Frames() int {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+	return recorder.frames
+}
+
+// Load reads every frame of a recording
+// A file cut off mid-frame (the recording process was killed) yields the frames before the cut
+func Load(path string) ([]Frame, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open recording: %w", err)
+	}
+	defer file.Close()
+
+	decompressor, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recording %s: %w", path, err)
+	}
+	defer decompressor.Close()
+
+	var frames []Frame
+	reader := bufio.NewReader(decompressor)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 && err == nil {
+			var frame Frame
+			if decodeErr := json.Unmarshal(line, &frame); decodeErr != nil {
+				return nil, fmt.Errorf("failed to decode frame %d of %s: %w", len(frames)+1, path, decodeErr)
+			}
+			frames = append(frames, frame)
+		}
+		if err == io.EOF || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read recording %s: %w", path, err)
+		}
+	}
+
+	if len(frames) == 0 {
+		return nil, fmt.Errorf("recording %s has no frames", path)
+	}
+	return frames, nil
+}
+
+// Describe summarizes the frames of a recording
+func Describe(path string, frames []Frame) Info {
+	info := Info{Path: path, Frames: len(frames)}
+	if len(frames) == 0 {
+		return info
+	}
+
+	seen := make(map[string]bool)
+	for _, frame := range frames {
+		if !seen[frame.Monitor] {
+			seen[frame.Monitor] = true
+			info.Monitors = append(info.Monitors, frame.Monitor)
+		}
+	}
+	info.Start = frames[0].Time
+	info.Duration = frames[len(frames)-1].Time.Sub(frames[0].Time)
+	return info
+}
+
+// List returns the recording files in dir, newest first
+func List(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recordings directory: %w", err)
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), FileSuffix) {
+			paths = append(paths, filepath.Join(dir, entry.Name()))
+		}
+	}
+	// Names carry the start time, so reverse name order is newest first
+	sort.Sort(sort.Reverse(sort.StringSlice(paths)))
+	return paths, nil
+}
+
+// Delay returns how long to wait before showing next after previous at the given speed
+func Delay(previous, next Frame, speed float64) time.Duration {
+	if speed <= 0 {
+		speed = 1
+	}
+	gap := next.Time.Sub(previous.Time)
+	if gap < 0 {
+		return 0
+	}
+	delay := time.Duration(float64(gap) / speed)
+	if delay > MaxReplayDelay {
+		return MaxReplayDelay
+	}
+	return delay
+}
+
+// NextSpeed returns the replay speed one step faster (step 1) or slower (step -1) than speed
+func NextSpeed(speed float64, step int) float64 {
+	index := 0
+	for i, candidate := range Speeds {
+		if candidate <= speed {
+			index = i
+		}
+	}
+	index += step
+	if index < 0 {
+		index = 0
+	}
+	if index >= len(Speeds) {
+		index = len(Speeds) - 1
+	}
+	return Speeds[index]
+}
@@ -0,0 +1,38 @@
+package recording
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+)
+
+// Frame is one collection cycle captured during a live session
+type Frame struct {
+	Time    time.Time       `json:"time"`    // When the cycle was collected
+	Monitor string          `json:"monitor"` // Monitor that collected it (cpu, memory, disk, network, process or events)
+	Data    json.RawMessage `json:"data"`    // The monitor data as exported to JSON
+}
+
+// Recorder appends frames to a gzip-compressed file, one JSON document per line
+// Every frame is flushed as it is written, so a session cut short by a crash can still be replayed.
+// With a feed directory it also keeps the latest frame of each monitor there for viewer instances
+type Recorder struct {
+	path       string
+	feedDir    string
+	file       *os.File
+	compressor *gzip.Writer
+	encoder    *json.Encoder
+	frames     int
+	mutex      sync.Mutex
+}
+
+// Info describes a recording file
+type Info struct {
+	Path     string        // Recording file
+	Frames   int           // Number of frames it holds
+	Monitors []string      // Monitors that appear in it, in order of first appearance
+	Start    time.Time     // Time of the first frame
+	Duration time.Duration // Time between the first and last frame
+}
@@ -0,0 +1,461 @@
+// Package redact hides usernames, command lines, IP addresses and hostnames in exports
+package redact
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"os/user"
+	"reflect"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Kinds of redacted values; each replacement is the kind, a dash and 8 hex digits of the keyed hash
+const (
+	kindUser = "user"
+	kindHost = "host"
+	kindIP   = "ip"
+	kindArgs = "args"
+)
+
+// minWordLength is the shortest username or hostname that is also replaced inside other text,
+// as shorter ones would match too many unrelated words
+const minWordLength = 3
+
+// Fields (JSON names, or keys of decoded objects) whose whole value is redacted
+var (
+	userKeys    = map[string]bool{"user": true, "username": true, "owner": true}
+	hostKeys    = map[string]bool{"hostname": true, "host": true, "target": true, "endpoint": true, "latency_targets": true}
+	addressKeys = map[string]bool{"ip_address": true, "address": true, "addresses": true, "local_address": true,
+		"remote_address": true, "gateway": true, "dns_servers": true}
+	commandKeys = map[string]bool{"command_line": true, "cmdline": true, "command": true, "restart_command": true}
+)
+
+// systemAccounts are service accounts found on every machine, which identify nobody and are left as they are,
+// as are other accounts with a user ID below firstLoginID (postgres, mysql, ...), which are often named like their process
+var systemAccounts = map[string]bool{
+	"root": true, "daemon": true, "bin": true, "sys": true, "nobody": true, "messagebus": true, "syslog": true,
+	"www-data": true, "SYSTEM": true, "LOCAL SERVICE": true, "NETWORK SERVICE": true,
+}
+
+// firstLoginID is the lowest user ID given to people rather than services
+var firstLoginID = map[string]int{"linux": 1000, "darwin": 500, "freebsd": 1000}
+
+var (
+	ipv4Pattern = regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b`)
+	ipv6Pattern = regexp.MustCompile(`(?i)[0-9a-f]{0,4}(?::[0-9a-f]{0,4}){2,7}(?:%[\w.-]+)?`)
+)
+
+// current is shared by the whole program; redaction is off until enabled
+var current settings
+
+// SetConfig replaces the redaction settings
+func SetConfig(config Config) {
+	current.mutex.Lock()
+	defer current.mutex.Unlock()
+	current.config = config
+}
+
+// GetConfig returns the redaction settings
+func GetConfig() Config {
+	current.mutex.Lock()
+	defer current.mutex.Unlock()
+	return current.config
+}
+
+// Enabled reports whether exports are redacted
+func Enabled() bool {
+	return GetConfig().Enabled
+}
+
+// salt returns the configured salt, or the random salt of this run
+func (state *settings) salt() string {
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+	if state.config.Salt != "" {
+		return state.config.Salt
+	}
+	if state.random == "" {
+		random := make([]byte, 16)
+		rand.Read(random)
+		state.random = hex.EncodeToString(random)
+	}
+	return state.random
+}
+
+// Copy replaces the value target points to with a redacted copy when redaction is on, and does nothing otherwise
+// The value itself is left untouched, as the caller usually keeps displaying it
+// Usernames and hostnames become user-<hash> and host-<hash>, IP addresses ip-<hash> (ports are kept) and
+// command-line arguments args-<hash>, wherever they appear; equal values get equal hashes, so processes,
+// connections and hosts can still be told apart and matched across the files of one export
+func Copy(target interface{}) error {
+	if !Enabled() {
+		return nil
+	}
+	pointer := reflect.ValueOf(target)
+	if pointer.Kind() != reflect.Pointer || pointer.IsNil() {
+		return fmt.Errorf("cannot redact %T: not a pointer", target)
+	}
+
+	// A JSON round trip gives a deep copy of everything an export contains
+	content, err := json.Marshal(pointer.Elem().Interface())
+	if err != nil {
+		return fmt.Errorf("failed to copy data for redaction: %w", err)
+	}
+	copied := reflect.New(pointer.Elem().Type())
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	if err := decoder.Decode(copied.Interface()); err != nil {
+		return fmt.Errorf("failed to copy data for redaction: %w", err)
+	}
+
+	redactor := newRedactor(current.salt())
+	redactor.collect(copied.Elem(), "")
+	redactor.sortWords()
+	redactor.walk(copied.Elem(), "")
+	pointer.Elem().Set(copied.Elem())
+	return nil
+}
+
+// newRedactor creates a redactor that already knows the local hostname and the current user
+func newRedactor(salt string) *redactor {
+	redactor := &redactor{salt: salt, accounts: make(map[string]bool)}
+	if hostname, err := os.Hostname(); err == nil {
+		redactor.addHost(hostname)
+	}
+	if current, err := user.Current(); err == nil {
+		redactor.addUser(current.Username)
+	}
+	return redactor
+}
+
+// addUser remembers a username, and the name without its domain, to replace in free text
+func (redactor *redactor) addUser(name string) {
+	if name == "" || redactor.isSystemAccount(name) {
+		return
+	}
+	redactor.users = append(redactor.users, name)
+	if index := strings.LastIndex(name, `\`); index >= 0 {
+		redactor.users = append(redactor.users, name[index+1:])
+	}
+}
+
+// addHost remembers a hostname, and its first label, to replace in free text
+func (redactor *redactor) addHost(name string) {
+	if name == "" || strings.EqualFold(name, "localhost") || net.ParseIP(name) != nil {
+		return
+	}
+	redactor.hosts = append(redactor.hosts, name)
+	if label, _, found := strings.Cut(name, "."); found {
+		redactor.hosts = append(redactor.hosts, label)
+	}
+}
+
+// sortWords drops duplicates and short names, and puts longer names first so they are replaced before their parts
+func (redactor *redactor) sortWords() {
+	redactor.users = longestFirst(redactor.users)
+	redactor.hosts = longestFirst(redactor.hosts)
+}
+
+func longestFirst(words []string) []string {
+	seen := make(map[string]bool)
+	var kept []string
+	for _, word := range words {
+		if len(word) >= minWordLength && !seen[word] {
+			seen[word] = true
+			kept = append(kept, word)
+		}
+	}
+	sort.SliceStable(kept, func(i, j int) bool { return len(kept[i]) > len(kept[j]) })
+	return kept
+}
+
+// collect gathers the usernames and hostnames held in user and host fields, so they are also
+// replaced where they appear in other text (home directories, log messages)
+func (redactor *redactor) collect(value reflect.Value, key string) {
+	switch value.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !value.IsNil() {
+			redactor.collect(value.Elem(), key)
+		}
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			if field := value.Type().Field(i); field.IsExported() {
+				redactor.collect(value.Field(i), jsonName(field))
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			redactor.collect(value.Index(i), key)
+		}
+	case reflect.Map:
+		iterator := value.MapRange()
+		for iterator.Next() {
+			redactor.collect(iterator.Value(), mapKey(iterator.Key(), key))
+		}
+	case reflect.String:
+		switch {
+		case userKeys[key]:
+			redactor.addUser(value.String())
+		case key == "hostname" || key == "host":
+			redactor.addHost(value.String())
+		}
+	}
+}
+
+// walk redacts every string in value, which must be settable
+func (redactor *redactor) walk(value reflect.Value, key string) {
+	switch value.Kind() {
+	case reflect.Pointer:
+		if !value.IsNil() {
+			redactor.walk(value.Elem(), key)
+		}
+	case reflect.Interface:
+		// What an interface holds cannot be changed in place, so a copy is redacted and stored back
+		if !value.IsNil() {
+			held := reflect.New(value.Elem().Type()).Elem()
+			held.Set(value.Elem())
+			redactor.walk(held, key)
+			value.Set(held)
+		}
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			if field := value.Type().Field(i); field.IsExported() {
+				redactor.walk(value.Field(i), jsonName(field))
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			redactor.walk(value.Index(i), key)
+		}
+	case reflect.Map:
+		for _, mapIndex := range value.MapKeys() {
+			element := reflect.New(value.Type().Elem()).Elem()
+			element.Set(value.MapIndex(mapIndex))
+			redactor.walk(element, mapKey(mapIndex, key))
+
+			// Keys can be names too (e.g. totals per user), so they are redacted like free text
+			newIndex := mapIndex
+			if mapIndex.Kind() == reflect.String {
+				newIndex = reflect.New(mapIndex.Type()).Elem()
+				newIndex.SetString(redactor.text(mapIndex.String()))
+				value.SetMapIndex(mapIndex, reflect.Value{})
+			}
+			value.SetMapIndex(newIndex, element)
+		}
+	case reflect.String:
+		value.SetString(redactor.field(key, value.String()))
+	}
+}
+
+// field redacts the string held by the field named key
+func (redactor *redactor) field(key, text string) string {
+	if text == "" {
+		return text
+	}
+	switch {
+	case userKeys[key]:
+		if redactor.isSystemAccount(text) {
+			return text
+		}
+		return redactor.token(kindUser, text)
+	case hostKeys[key]:
+		return redactor.address(text, true)
+	case addressKeys[key]:
+		return redactor.address(text, false)
+	case commandKeys[key]:
+		return redactor.command(text)
+	}
+	return redactor.text(text)
+}
+
+// address redacts an IP address or, with hosts, a hostname, either optionally followed by a port that is kept
+func (redactor *redactor) address(text string, hosts bool) string {
+	host, port := text, ""
+	if splitHost, splitPort, err := net.SplitHostPort(text); err == nil {
+		host, port = splitHost, ":"+splitPort
+	}
+	if ip := parseIP(host); ip != nil {
+		if keepIP(ip) {
+			return text
+		}
+		return redactor.token(kindIP, ip.String()) + port
+	}
+	if hosts && host != "" && !strings.EqualFold(host, "localhost") && !strings.ContainsAny(host, " /") {
+		return redactor.token(kindHost, strings.ToLower(host)) + port
+	}
+	return redactor.text(text)
+}
+
+// command keeps the program of a command line and replaces its arguments with one hash
+func (redactor *redactor) command(text string) string {
+	text = strings.TrimSpace(text)
+	program, arguments := text, ""
+	if strings.HasPrefix(text, `"`) {
+		if end := strings.Index(text[1:], `"`); end >= 0 {
+			program, arguments = text[:end+2], text[end+2:]
+		}
+	} else if index := strings.IndexAny(text, " \t"); index >= 0 {
+		program, arguments = text[:index], text[index:]
+	}
+
+	program = redactor.text(program)
+	if arguments = strings.TrimSpace(arguments); arguments == "" {
+		return program
+	}
+	return program + " " + redactor.token(kindArgs, arguments)
+}
+
+// text replaces the IP addresses, known usernames and known hostnames found in free text
+func (redactor *redactor) text(text string) string {
+	if text == "" {
+		return text
+	}
+	for _, pattern := range []*regexp.Regexp{ipv4Pattern, ipv6Pattern} {
+		text = pattern.ReplaceAllStringFunc(text, func(match string) string {
+			ip := parseIP(match)
+			if ip == nil || keepIP(ip) {
+				return match
+			}
+			return redactor.token(kindIP, ip.String())
+		})
+	}
+	for _, name := range redactor.users {
+		text = replaceWord(text, name, redactor.token(kindUser, name), false)
+	}
+	for _, name := range redactor.hosts {
+		text = replaceWord(text, name, redactor.token(kindHost, strings.ToLower(name)), true)
+	}
+	return text
+}
+
+// token returns the replacement of value: kind-<first 8 hex digits of the salted hash>
+func (redactor *redactor) token(kind, value string) string {
+	mac := hmac.New(sha256.New, []byte(redactor.salt))
+	mac.Write([]byte(kind + "\x00" + value))
+	return kind + "-" + hex.EncodeToString(mac.Sum(nil))[:8]
+}
+
+// replaceWord replaces word in text where it is not part of a longer word (letters, digits, _ and -)
+func replaceWord(text, word, replacement string, fold bool) string {
+	search, find := text, word
+	// Lowercasing only keeps the byte offsets for ASCII
+	if fold && isASCII(text) && isASCII(word) {
+		search, find = strings.ToLower(text), strings.ToLower(word)
+	}
+
+	var result strings.Builder
+	last := 0
+	for start := 0; ; {
+		index := strings.Index(search[start:], find)
+		if index < 0 {
+			break
+		}
+		index += start
+		end := index + len(find)
+		if !isWordByte(text, index-1, true) && !isWordByte(text, end, false) {
+			result.WriteString(text[last:index])
+			result.WriteString(replacement)
+			last = end
+		}
+		start = end
+	}
+	if last == 0 {
+		return text
+	}
+	result.WriteString(text[last:])
+	return result.String()
+}
+
+// isWordByte reports whether the rune ending (before) or starting (!before) at index continues a word
+func isWordByte(text string, index int, before bool) bool {
+	var char rune
+	if before {
+		if index < 0 {
+			return false
+		}
+		char, _ = utf8.DecodeLastRuneInString(text[:index+1])
+	} else {
+		if index >= len(text) {
+			return false
+		}
+		char, _ = utf8.DecodeRuneInString(text[index:])
+	}
+	return unicode.IsLetter(char) || unicode.IsDigit(char) || char == '_' || char == '-'
+}
+
+func isASCII(text string) bool {
+	for i := 0; i < len(text); i++ {
+		if text[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// parseIP parses an IP address, which may be in brackets or carry an IPv6 zone
+func parseIP(text string) net.IP {
+	text = strings.TrimSuffix(strings.TrimPrefix(text, "["), "]")
+	if address, _, found := strings.Cut(text, "%"); found {
+		text = address
+	}
+	return net.ParseIP(text)
+}
+
+// keepIP reports whether ip is the same on every machine (loopback and unspecified addresses)
+func keepIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsUnspecified()
+}
+
+// isSystemAccount reports whether name, with or without a domain, is a service account
+func (redactor *redactor) isSystemAccount(name string) bool {
+	if index := strings.LastIndex(name, `\`); index >= 0 {
+		name = name[index+1:]
+	}
+	if systemAccounts[name] || strings.HasPrefix(name, "systemd-") || strings.HasPrefix(name, "_") {
+		return true
+	}
+	if system, found := redactor.accounts[name]; found {
+		return system
+	}
+
+	system := false
+	if first, found := firstLoginID[runtime.GOOS]; found {
+		if account, err := user.Lookup(name); err == nil {
+			if id, err := strconv.Atoi(account.Uid); err == nil {
+				system = id < first
+			}
+		}
+	}
+	redactor.accounts[name] = system
+	return system
+}
+
+// jsonName returns the name a struct field has in the exports
+func jsonName(field reflect.StructField) string {
+	if name, _, _ := strings.Cut(field.Tag.Get("json"), ","); name != "" {
+		return name
+	}
+	return field.Name
+}
+
+// mapKey returns the field name the elements of a map are redacted as: the key of a decoded
+// JSON object, or the map's own field name for maps keyed by anything else
+func mapKey(index reflect.Value, field string) string {
+	if index.Kind() == reflect.String {
+		return index.String()
+	}
+	return field
+}
@@ -0,0 +1,152 @@
+package redact
+
+import "testing"
+
+// record has fields named like those of the exports
+type record struct {
+	User    string         `json:"user"`
+	Host    string         `json:"hostname"`
+	Address string         `json:"remote_address"`
+	Command string         `json:"command_line"`
+	Message string         `json:"message"`
+	PerUser map[string]int `json:"per_user"`
+}
+
+// withConfig applies config for the rest of the test
+func withConfig(t *testing.T, config Config) {
+	t.Helper()
+	previous := GetConfig()
+	SetConfig(config)
+	t.Cleanup(func() { SetConfig(previous) })
+}
+
+func TestCopy(t *testing.T) {
+	withConfig(t, Config{Enabled: true, Salt: "test"})
+	token := newRedactor("test").token
+
+	tests := []struct {
+		name  string
+		value record
+		want  record
+	}{
+		{
+			name:  "user",
+			value: record{User: "alice"},
+			want:  record{User: token(kindUser, "alice")},
+		},
+		{
+			name:  "service account",
+			value: record{User: "root"},
+			want:  record{User: "root"},
+		},
+		{
+			name:  "hostname with port",
+			value: record{Host: "DB1.example.com:5432"},
+			want:  record{Host: token(kindHost, "db1.example.com") + ":5432"},
+		},
+		{
+			name:  "address with port",
+			value: record{Address: "203.0.113.7:443"},
+			want:  record{Address: token(kindIP, "203.0.113.7") + ":443"},
+		},
+		{
+			name:  "loopback address",
+			value: record{Address: "127.0.0.1:8080"},
+			want:  record{Address: "127.0.0.1:8080"},
+		},
+		{
+			name:  "command line",
+			value: record{Command: "/usr/bin/python3 app.py --key secret"},
+			want:  record{Command: "/usr/bin/python3 " + token(kindArgs, "app.py --key secret")},
+		},
+		{
+			name:  "quoted program",
+			value: record{Command: `"C:\Program Files\app.exe" --verbose`},
+			want:  record{Command: `"C:\Program Files\app.exe" ` + token(kindArgs, "--verbose")},
+		},
+		{
+			name:  "names in free text",
+			value: record{User: "alice", Message: "alice logged in from 198.51.100.2 (alice-laptop, /home/alice)"},
+			want: record{User: token(kindUser, "alice"),
+				Message: token(kindUser, "alice") + " logged in from " + token(kindIP, "198.51.100.2") + " (alice-laptop, /home/" + token(kindUser, "alice") + ")"},
+		},
+		{
+			name:  "map keys",
+			value: record{User: "alice", PerUser: map[string]int{"alice": 3}},
+			want:  record{User: token(kindUser, "alice"), PerUser: map[string]int{token(kindUser, "alice"): 3}},
+		},
+	}
+	for _, tt := range tests {
+		value := tt.value
+		if err := Copy(&value); err != nil {
+			t.Errorf("%s: Copy() error = %v", tt.name, err)
+			continue
+		}
+		if value.User != tt.want.User || value.Host != tt.want.Host || value.Address != tt.want.Address ||
+			value.Command != tt.want.Command || value.Message != tt.want.Message {
+			t.Errorf("%s: Copy() = %+v, want %+v", tt.name, value, tt.want)
+		}
+		if len(value.PerUser) != len(tt.want.PerUser) {
+			t.Errorf("%s: Copy() per user = %v, want %v", tt.name, value.PerUser, tt.want.PerUser)
+		}
+		for name, count := range tt.want.PerUser {
+			if value.PerUser[name] != count {
+				t.Errorf("%s: Copy() per user = %v, want %v", tt.name, value.PerUser, tt.want.PerUser)
+			}
+		}
+	}
+}
+
+func TestCopyLeavesOriginal(t *testing.T) {
+	withConfig(t, Config{Enabled: true, Salt: "test"})
+
+	original := &record{User: "alice", PerUser: map[string]int{"alice": 1}}
+	copied := *original
+	if err := Copy(&copied); err != nil {
+		t.Fatalf("Copy() error = %v", err)
+	}
+	if original.User != "alice" || original.PerUser["alice"] != 1 {
+		t.Errorf("Copy() changed the original to %+v", original)
+	}
+}
+
+func TestCopyDisabled(t *testing.T) {
+	withConfig(t, Config{})
+
+	value := record{User: "alice", Address: "203.0.113.7"}
+	if err := Copy(&value); err != nil {
+		t.Fatalf("Copy() error = %v", err)
+	}
+	if value.User != "alice" || value.Address != "203.0.113.7" {
+		t.Errorf("Copy() with redaction off = %+v, want it unchanged", value)
+	}
+}
+
+func TestCopyRequiresPointer(t *testing.T) {
+	withConfig(t, Config{Enabled: true})
+
+	if err := Copy(record{}); err == nil {
+		t.Error("Copy() of a value succeeded, want an error")
+	}
+}
+
+func TestReplaceWord(t *testing.T) {
+	tests := []struct {
+		text string
+		word string
+		fold bool
+		want string
+	}{
+		{text: "bob and bobby", word: "bob", want: "X and bobby"},
+		{text: "bob_1 bob-2 bob.3", word: "bob", want: "bob_1 bob-2 X.3"},
+		{text: "Bob", word: "bob", want: "Bob"},
+		{text: "Bob", word: "bob", fold: true, want: "X"},
+		{text: "café bob", word: "bob", fold: true, want: "café X"},
+		{text: "ébob bob", word: "bob", want: "ébob X"},
+	}
+	for _, tt := range tests {
+		if got := replaceWord(tt.text, tt.word, "X", tt.fold); got != tt.want {
+			t.Errorf("replaceWord(%q, %q, %v) = %q, want %q", tt.text, tt.word, tt.fold, got, tt.want)
+		}
+	}
+}
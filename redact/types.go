@@ -0,0 +1,24 @@
+package redact
+
+import "sync"
+
+// Config holds the redaction settings shared by every export
+type Config struct {
+	Enabled bool   `json:"enabled"` // Whether exports and debug bundles hide usernames, command-line arguments, IP addresses and hostnames
+	Salt    string `json:"salt"`    // Secret the hashes are keyed with; empty uses a random one, so hashes only match within one run
+}
+
+// settings holds the configuration shared by the whole program
+type settings struct {
+	config Config
+	random string // Salt used while Config.Salt is empty, generated once per run
+	mutex  sync.Mutex
+}
+
+// redactor rewrites one copied value
+type redactor struct {
+	salt     string
+	users    []string        // Usernames to replace wherever they appear in text, longest first
+	hosts    []string        // Hostnames to replace wherever they appear in text, longest first
+	accounts map[string]bool // Whether each username looked up is a service account
+}
@@ -0,0 +1,188 @@
+// Package retention deletes exports and trims history older than the data retention period
+package retention
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ahmadreza-log/simple-monitor/historystore"
+	"github.com/ahmadreza-log/simple-monitor/instance"
+	"github.com/ahmadreza-log/simple-monitor/knowngood"
+	"github.com/ahmadreza-log/simple-monitor/overlay"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// HistoryDir is the subdirectory of the logs directory holding long-term history files
+// These are trimmed point by point instead of deleted, so recent history survives
+const HistoryDir = "history"
+
+// Choices lists the retention periods offered in the settings menu, in days
+var Choices = []int{1, 7, 30, 90}
+
+// schedule is shared by every monitor; pruning is off until a retention period is set
+var schedule = scheduleState{
+	config: Config{Days: 0, CheckInterval: 24 * time.Hour},
+	wake:   make(chan bool, 1),
+}
+
+// Run removes exported files under dir older than days and trims the history files in dir/history
+// With dryRun nothing is deleted and the report lists what would be
+func Run(dir string, days int, now time.Time, dryRun bool) (Report, error) {
+	report := Report{Time: now, DryRun: dryRun}
+	if days <= 0 {
+		return report, errors.New("data retention is off; choose a retention period first")
+	}
+	report.Cutoff = now.AddDate(0, 0, -days)
+
+	var emptied []string
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == dir {
+				return filepath.SkipDir
+			}
+			report.Errors = append(report.Errors, err.Error())
+			return nil
+		}
+		if entry.IsDir() {
+			if path != dir {
+				emptied = append(emptied, path)
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		if filepath.Base(filepath.Dir(path)) == HistoryDir && strings.HasSuffix(path, ".json") {
+			points, err := historystore.PruneFile(path, report.Cutoff, dryRun)
+			if err != nil {
+				report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", path, err))
+			} else if points > 0 {
+				report.History = append(report.History, History{Path: path, Points: points})
+			}
+			return nil
+		}
+
+		// The instance lock belongs to a running instance however old it is, the overlay series is drawn
+		// until it is cleared, and the known-good state is a reference kept until it is saved again
+		if path == filepath.Join(dir, instance.LockFile) || path == filepath.Join(dir, overlay.File) ||
+			path == filepath.Join(dir, knowngood.File) {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil || !info.ModTime().Before(report.Cutoff) {
+			return nil
+		}
+		if !dryRun {
+			if err := os.Remove(path); err != nil {
+				report.Errors = append(report.Errors, err.Error())
+				return nil
+			}
+		}
+		report.Files = append(report.Files, File{Path: path, Size: info.Size(), ModTime: info.ModTime()})
+		report.Bytes += info.Size()
+		return nil
+	})
+	if err != nil {
+		return report, fmt.Errorf("failed to scan %s: %w", dir, err)
+	}
+
+	// Directories left empty by the cleanup (e.g. per-module export folders) go too, deepest first
+	if !dryRun {
+		sort.Sort(sort.Reverse(sort.StringSlice(emptied)))
+		for _, path := range emptied {
+			if entries, err := os.ReadDir(path); err == nil && len(entries) == 0 {
+				os.Remove(path)
+			}
+		}
+	}
+	return report, nil
+}
+
+// SetConfig replaces the retention settings and prunes straight away when a period is set
+func SetConfig(config Config) error {
+	if config.Days < 0 {
+		return fmt.Errorf("retention of %d days is negative", config.Days)
+	}
+	if config.CheckInterval <= 0 {
+		return errors.New("retention check interval must be greater than zero")
+	}
+
+	schedule.mutex.Lock()
+	schedule.config = config
+	schedule.mutex.Unlock()
+
+	historystore.SetMaxAge(time.Duration(config.Days) * 24 * time.Hour)
+	wake()
+	return nil
+}
+
+// GetConfig returns the retention settings
+func GetConfig() Config {
+	schedule.mutex.Lock()
+	defer schedule.mutex.Unlock()
+	return schedule.config
+}
+
+// Start prunes dir now and then every check interval until the program exits
+// Nothing is removed while retention is off
+func Start(dir string) {
+	schedule.mutex.Lock()
+	started := schedule.dir != ""
+	schedule.dir = dir
+	schedule.mutex.Unlock()
+	if started {
+		return
+	}
+
+	go func() {
+		for {
+			config := GetConfig()
+			if config.Days > 0 {
+				report, err := Run(dir, config.Days, time.Now(), false)
+				if err == nil {
+					schedule.mutex.Lock()
+					schedule.lastReport = &report
+					schedule.mutex.Unlock()
+				}
+			}
+
+			select {
+			case <-schedule.wake:
+			case <-time.After(config.CheckInterval):
+			}
+		}
+	}()
+}
+
+// wake makes the pruning loop run again now, e.g. after the retention period changed
+func wake() {
+	select {
+	case schedule.wake <- true:
+	default:
+	}
+}
+
+// LastReport returns the most recent scheduled run, or nil before the first one
+func LastReport() *Report {
+	schedule.mutex.Lock()
+	defer schedule.mutex.Unlock()
+	return schedule.lastReport
+}
+
+// Describe returns the period as shown in menus, e.g. "7 days" or "keep forever"
+func Describe(days int) string {
+	switch {
+	case days <= 0:
+		return "keep forever"
+	case days == 1:
+		return "1 day"
+	default:
+		return fmt.Sprintf("%d days", days)
+	}
+}
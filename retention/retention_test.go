@@ -0,0 +1,133 @@
+package retention
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ahmadreza-log/simple-monitor/historystore"
+	"github.com/ahmadreza-log/simple-monitor/instance"
+	"github.com/ahmadreza-log/simple-monitor/knowngood"
+	"github.com/ahmadreza-log/simple-monitor/overlay"
+)
+
+func TestRun(t *testing.T) {
+	now := time.Now()
+	old, recent := now.AddDate(0, 0, -10), now.AddDate(0, 0, -1)
+
+	files := []struct {
+		path     string
+		modTime  time.Time
+		wantGone bool
+	}{
+		{path: "cpu/cpu_old.json", modTime: old, wantGone: true},
+		{path: "cpu/cpu_recent.json", modTime: recent},
+		{path: "disk/disk_old.csv", modTime: old, wantGone: true},
+		{path: instance.LockFile, modTime: old},
+		{path: overlay.File, modTime: old},
+		{path: knowngood.File, modTime: old},
+	}
+
+	tests := []struct {
+		name   string
+		dryRun bool
+	}{
+		{name: "dry run", dryRun: true},
+		{name: "run"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, file := range files {
+				path := filepath.Join(dir, file.path)
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte("data"), 0644); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.Chtimes(path, file.modTime, file.modTime); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			report, err := Run(dir, 7, now, tt.dryRun)
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			var reported []string
+			for _, file := range report.Files {
+				rel, _ := filepath.Rel(dir, file.Path)
+				reported = append(reported, filepath.ToSlash(rel))
+			}
+			for _, file := range files {
+				_, statErr := os.Stat(filepath.Join(dir, file.path))
+				if gone := os.IsNotExist(statErr); gone != (file.wantGone && !tt.dryRun) {
+					t.Errorf("%s removed = %v, want %v", file.path, gone, file.wantGone && !tt.dryRun)
+				}
+				if slices.Contains(reported, file.path) != file.wantGone {
+					t.Errorf("report lists %s = %v, want %v (report %v)", file.path, !file.wantGone, file.wantGone, reported)
+				}
+			}
+			if report.Bytes != 8 {
+				t.Errorf("Bytes = %d, want 8", report.Bytes)
+			}
+
+			// The emptied disk directory goes with its last file
+			if _, err := os.Stat(filepath.Join(dir, "disk")); os.IsNotExist(err) == tt.dryRun {
+				t.Errorf("disk directory removed = %v, want %v", os.IsNotExist(err), !tt.dryRun)
+			}
+		})
+	}
+}
+
+func TestRunTrimsHistory(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	store := historystore.NewStore([]historystore.Tier{{Name: "raw", Retention: 30 * 24 * time.Hour}})
+	for days := 10; days > 0; days-- {
+		store.Add(now.AddDate(0, 0, -days), map[string]float64{"usage": 1})
+	}
+	path := filepath.Join(dir, HistoryDir, "cpu.json")
+	if err := store.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	// The history file itself is old, but only its points are trimmed
+	os.Chtimes(path, now.AddDate(0, 0, -10), now.AddDate(0, 0, -10))
+
+	report, err := Run(dir, 7, now, false)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(report.History) != 1 || report.History[0].Points != 3 {
+		t.Errorf("History = %+v, want 3 points trimmed from %s", report.History, path)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("history file was removed: %v", err)
+	}
+}
+
+func TestRunRequiresPeriod(t *testing.T) {
+	if _, err := Run(t.TempDir(), 0, time.Now(), true); err == nil {
+		t.Error("Run() with retention off: error = nil, want one")
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	tests := []struct {
+		days int
+		want string
+	}{
+		{days: 0, want: "keep forever"},
+		{days: -1, want: "keep forever"},
+		{days: 1, want: "1 day"},
+		{days: 30, want: "30 days"},
+	}
+	for _, tt := range tests {
+		if got := Describe(tt.days); got != tt.want {
+			t.Errorf("Describe(%d) = %q, want %q", tt.days, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package retention
+
+import (
+	"sync"
+	"time"
+)
+
+// Config holds the data retention settings shared by every monitor
+type Config struct {
+	Days          int           `json:"days"`           // Age in days after which exported files and history points are removed (0 keeps everything)
+	CheckInterval time.Duration `json:"check_interval"` // How often pruning runs while the program is open
+}
+
+// File is an exported file past the retention age
+type File struct {
+	Path    string    `json:"path"`     // File location
+	Size    int64     `json:"size"`     // Size in bytes
+	ModTime time.Time `json:"mod_time"` // Last modification time
+}
+
+// History is a long-term history file with points past the retention age
+type History struct {
+	Path   string `json:"path"`   // History file location
+	Points int    `json:"points"` // Number of points older than the cutoff
+}
+
+// Report describes one pruning run
+type Report struct {
+	Time    time.Time `json:"time"`    // When the run happened
+	Cutoff  time.Time `json:"cutoff"`  // Files and points older than this are removed
+	DryRun  bool      `json:"dry_run"` // Whether nothing was actually deleted
+	Files   []File    `json:"files"`   // Exported files removed (or that would be removed)
+	History []History `json:"history"` // History files trimmed (or that would be trimmed)
+	Bytes   int64     `json:"bytes"`   // Total size of the removed files
+	Errors  []string  `json:"errors"`  // Files that could not be removed or trimmed
+}
+
+// scheduleState holds the settings and the background pruning loop
+type scheduleState struct {
+	config     Config
+	dir        string
+	lastReport *Report
+	wake       chan bool
+	mutex      sync.Mutex
+}
@@ -0,0 +1,166 @@
+// Package simulate generates the synthetic data shown with --simulate
+package simulate
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// DefaultSeed is used by --simulate so demo runs show the same spikes every time
+const DefaultSeed = 42
+
+// Bytes per megabyte, for sizing synthetic memory and disks
+const MB = 1024 * 1024
+
+// profiles is the synthetic process table, parents before children
+var profiles = []profile{
+	{name: "systemd", path: "/usr/lib/systemd/systemd", user: "root", parent: -1, cpu: 0.2, memoryMB: 12, threads: 1, cgroup: "/init.scope"},
+	{name: "sshd", path: "/usr/sbin/sshd", user: "root", parent: 0, cpu: 0.1, memoryMB: 8, threads: 1, netRate: 2e3, cgroup: "/system.slice/ssh.service", port: 22},
+	{name: "dockerd", path: "/usr/bin/dockerd", user: "root", parent: 0, cpu: 1.5, memoryMB: 95, threads: 24, diskRate: 2e5, netRate: 5e4, cgroup: "/system.slice/docker.service"},
+	{name: "postgres", path: "/usr/lib/postgresql/16/bin/postgres", user: "postgres", parent: 0, cpu: 4, memoryMB: 420, threads: 8, diskRate: 3e6, netRate: 4e5, cgroup: "/system.slice/postgresql.service", port: 5432},
+	{name: "nginx", path: "/usr/sbin/nginx", user: "www-data", parent: 0, cpu: 2, memoryMB: 30, threads: 4, diskRate: 1e5, netRate: 2.5e6, cgroup: "/system.slice/nginx.service", port: 443},
+	{name: "node", path: "/usr/bin/node", user: "demo", parent: 2, cpu: 8, memoryMB: 310, threads: 11, diskRate: 5e4, netRate: 8e5, spiky: true, cgroup: "/system.slice/docker-3f2a9c81d4e7.scope", port: 3000},
+	{name: "chrome", path: "/opt/google/chrome/chrome", user: "demo", parent: 0, cpu: 6, memoryMB: 1450, threads: 42, diskRate: 3e5, netRate: 1.2e6, cgroup: "/user.slice/user-1000.slice/session-2.scope"},
+	{name: "code", path: "/usr/share/code/code", user: "demo", parent: 0, cpu: 3, memoryMB: 780, threads: 28, diskRate: 8e4, cgroup: "/user.slice/user-1000.slice/session-2.scope"},
+	{name: "java", path: "/usr/lib/jvm/java-21/bin/java", user: "demo", parent: 2, cpu: 12, memoryMB: 1900, threads: 64, diskRate: 6e5, netRate: 3e5, spiky: true, cgroup: "/system.slice/docker-b71e05a6c2f9.scope"},
+	{name: "python3", path: "/usr/bin/python3", user: "demo", parent: 0, cpu: 5, memoryMB: 160, threads: 3, diskRate: 1.5e6, spiky: true, cgroup: "/user.slice/user-1000.slice/session-2.scope"},
+	{name: "rsync", path: "/usr/bin/rsync", user: "demo", parent: 0, cpu: 2, memoryMB: 20, threads: 1, diskRate: 2.4e7, netRate: 6e6, spiky: true, cgroup: "/system.slice/backup.service"},
+	{name: "Xorg", path: "/usr/lib/xorg/Xorg", user: "root", parent: 0, cpu: 1.2, memoryMB: 140, threads: 6, cgroup: "/system.slice/display-manager.service"},
+}
+
+// Source generates synthetic metrics that drift smoothly over time with occasional random spikes
+// Every monitor uses its own source; sources with the same seed produce the same process list
+type Source struct {
+	mutex    sync.Mutex
+	random   *rand.Rand
+	start    time.Time
+	bootTime time.Time
+
+	// Monotonic counters (bytes sent, sectors read) and when each was last advanced
+	counters    map[string]float64
+	counterTime map[string]time.Time
+}
+
+// NewSource creates a synthetic data source
+func NewSource(seed int64) *Source {
+	now := time.Now()
+	return &Source{
+		random:      rand.New(rand.NewSource(seed)),
+		start:       now,
+		bootTime:    now.Add(-(3*24*time.Hour + 7*time.Hour + 12*time.Minute)),
+		counters:    make(map[string]float64),
+		counterTime: make(map[string]time.Time),
+	}
+}
+
+// Uptime returns the simulated system uptime
+func (source *Source) Uptime() time.Duration {
+	return time.Since(source.bootTime)
+}
+
+// Wave returns a value oscillating between low and high once per period
+// Phase (0-1) shifts the wave so related metrics don't peak together
+func (source *Source) Wave(period time.Duration, phase, low, high float64) float64 {
+	cycles := time.Since(source.start).Seconds()/period.Seconds() + phase
+	return low + (high-low)*(0.5+0.5*math.Sin(2*math.Pi*cycles))
+}
+
+// Noise returns a random value between -amount and amount
+func (source *Source) Noise(amount float64) float64 {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+	return (source.random.Float64()*2 - 1) * amount
+}
+
+// Spike returns size with the given chance (0-1) and 0 otherwise
+func (source *Source) Spike(chance, size float64) float64 {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+	if source.random.Float64() < chance {
+		return size * (0.6 + 0.4*source.random.Float64())
+	}
+	return 0
+}
+
+// Between returns a random value between low and high
+func (source *Source) Between(low, high float64) float64 {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+	return low + source.random.Float64()*(high-low)
+}
+
+// Counter advances a named counter at rate per second and returns its new value
+// The first call starts the counter at one hour's worth of the rate so totals look lived-in
+func (source *Source) Counter(name string, rate float64) uint64 {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	now := time.Now()
+	last, seen := source.counterTime[name]
+	if !seen {
+		source.counters[name] = rate * 3600
+	} else if rate > 0 {
+		source.counters[name] += rate * now.Sub(last).Seconds()
+	}
+	source.counterTime[name] = now
+	return uint64(source.counters[name])
+}
+
+// Processes returns the synthetic process list with usage for this refresh
+func (source *Source) Processes() []Process {
+	processes := make([]Process, len(profiles))
+	for i, profile := range profiles {
+		phase := float64(i) / float64(len(profiles))
+		load := source.Wave(time.Duration(40+i*7)*time.Second, phase, 0.4, 1.3)
+
+		cpu := profile.cpu*load + source.Noise(profile.cpu*0.2)
+		if profile.spiky {
+			cpu += source.Spike(0.06, 55)
+		}
+
+		process := Process{
+			PID:         pidFor(i),
+			Name:        profile.name,
+			Path:        profile.path,
+			User:        profile.user,
+			Status:      "sleeping",
+			CPUPercent:  Clamp(cpu, 0, 100),
+			CPUTime:     source.Counter(profile.name+".cputime", Clamp(cpu, 0, 100)*10),
+			MemoryRSS:   uint64(profile.memoryMB * MB * (0.95 + 0.1*load)),
+			MemoryVMS:   uint64(profile.memoryMB * MB * 3.2),
+			Threads:     profile.threads,
+			OpenFiles:   profile.threads*4 + 12,
+			ReadRate:    uint64(profile.diskRate * load * 0.6),
+			WriteRate:   uint64(profile.diskRate * load * 0.4),
+			SendRate:    uint64(profile.netRate * load * 0.35),
+			RecvRate:    uint64(profile.netRate * load * 0.65),
+			Connections: int(profile.netRate/2e5) + 1,
+			ListenPort:  profile.port,
+			CreateTime:  source.bootTime.Add(time.Duration(i) * 37 * time.Minute).UnixMilli(),
+			Cgroup:      profile.cgroup,
+		}
+		if profile.parent >= 0 {
+			process.PPID = pidFor(profile.parent)
+		}
+		if process.CPUPercent > 5 {
+			process.Status = "running"
+		}
+		processes[i] = process
+	}
+	return processes
+}
+
+// pidFor returns the fixed PID of the process at index i
+func pidFor(i int) int32 {
+	if i == 0 {
+		return 1
+	}
+	return int32(400 + i*173)
+}
+
+// Clamp limits value to the range low..high
+func Clamp(value, low, high float64) float64 {
+	return math.Max(low, math.Min(high, value))
+}
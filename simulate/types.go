@@ -0,0 +1,42 @@
+package simulate
+
+// Process is a synthetic process shared by all simulated monitors
+// The same PIDs and names appear in every monitor so screenshots look consistent
+type Process struct {
+	PID         int32   `json:"pid"`         // Process ID
+	PPID        int32   `json:"ppid"`        // Parent process ID
+	Name        string  `json:"name"`        // Process name
+	Path        string  `json:"path"`        // Executable path
+	User        string  `json:"user"`        // Process owner
+	Status      string  `json:"status"`      // Process status (running, sleeping)
+	CPUPercent  float64 `json:"cpu_percent"` // CPU usage percentage
+	CPUTime     uint64  `json:"cpu_time"`    // Accumulated CPU time in milliseconds
+	MemoryRSS   uint64  `json:"memory_rss"`  // Resident memory in bytes
+	MemoryVMS   uint64  `json:"memory_vms"`  // Virtual memory in bytes
+	Threads     int32   `json:"threads"`     // Thread count
+	OpenFiles   int32   `json:"open_files"`  // Open file descriptors
+	ReadRate    uint64  `json:"read_rate"`   // Disk reads in bytes per second
+	WriteRate   uint64  `json:"write_rate"`  // Disk writes in bytes per second
+	SendRate    uint64  `json:"send_rate"`   // Network bytes sent per second
+	RecvRate    uint64  `json:"recv_rate"`   // Network bytes received per second
+	Connections int     `json:"connections"` // Open network connections
+	ListenPort  int     `json:"listen_port"` // TCP port the process listens on (0 for none)
+	CreateTime  int64   `json:"create_time"` // Start time in milliseconds since the epoch
+	Cgroup      string  `json:"cgroup"`      // Control group path, e.g. /system.slice/nginx.service
+}
+
+// profile describes how a synthetic process behaves
+type profile struct {
+	name     string  // Process name
+	path     string  // Executable path
+	user     string  // Process owner
+	parent   int     // Index of the parent profile (-1 for children of init)
+	cpu      float64 // Typical CPU usage percentage
+	memoryMB float64 // Typical resident memory in megabytes
+	threads  int32   // Thread count
+	diskRate float64 // Typical disk throughput in bytes per second
+	netRate  float64 // Typical network throughput in bytes per second
+	spiky    bool    // Whether the process occasionally bursts to high CPU
+	cgroup   string  // Control group path
+	port     int     // TCP port listened on (0 for none)
+}
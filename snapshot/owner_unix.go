@@ -0,0 +1,39 @@
+//go:build !windows
+
+package snapshot
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// checkPublishDir makes sure snapshots are only written to a real directory owned by the current user
+// and not writable by everyone, so another user cannot read them or replace the file with a link
+func checkPublishDir(dir string) error {
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return fmt.Errorf("failed to check snapshot directory: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("snapshot directory %s is not a directory", dir)
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok || int(stat.Uid) != os.Getuid() {
+		return fmt.Errorf("snapshot directory %s is not owned by the current user", dir)
+	}
+	if info.Mode().Perm()&0002 != 0 {
+		return fmt.Errorf("snapshot directory %s is writable by other users", dir)
+	}
+	return nil
+}
+
+// checkPipeOwner makes sure a named pipe belongs to the current user, so snapshots are not handed
+// to a reader that another user placed at the publish path
+func checkPipeOwner(path string, info os.FileInfo) error {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok || int(stat.Uid) != os.Getuid() {
+		return fmt.Errorf("snapshot pipe %s is not owned by the current user", path)
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+//go:build windows
+
+package snapshot
+
+import "os"
+
+// checkPublishDir accepts any directory: the default one lies in the user's profile, whose ACL
+// already keeps other users out, and Windows file ownership is not exposed through os.FileInfo
+func checkPublishDir(dir string) error {
+	return nil
+}
+
+// checkPipeOwner accepts any named pipe, for the same reason
+func checkPipeOwner(path string, info os.FileInfo) error {
+	return nil
+}
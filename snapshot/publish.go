@@ -0,0 +1,205 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+// MinPublishInterval is the shortest interval between two published snapshots
+const MinPublishInterval = 500 * time.Millisecond
+
+// publisher is shared by the whole program; publishing is off until enabled
+var publisher = publishState{
+	config: PublishConfig{Path: DefaultPublishPath(), Interval: 2 * time.Second},
+	wake:   make(chan bool, 1),
+}
+
+// DefaultPublishPath returns latest.json in a simple-monitor folder of the user's runtime directory
+// ($XDG_RUNTIME_DIR, kept in memory on Linux), or of the user's cache directory where there is none
+// Both belong to the user alone, unlike shared locations such as /dev/shm or the temp directory
+func DefaultPublishPath() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "simple-monitor", "latest.json")
+	}
+	if dir, err := os.UserCacheDir(); err == nil {
+		return filepath.Join(dir, "simple-monitor", "latest.json")
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("simple-monitor-%d", os.Getuid()), "latest.json")
+}
+
+// Publish writes state to path so other programs can read current metrics without parsing the archive
+// A regular file is replaced through a rename, so readers always see a complete document; its directory
+// must belong to the current user. A named pipe, which must belong to the current user too, receives the snapshot as one JSON line; without a reader on the pipe nothing is written
+func Publish(path string, state *SystemState) error {
+	content, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+
+	if info, err := os.Stat(path); err == nil && info.Mode()&os.ModeNamedPipe != 0 {
+		if err := checkPipeOwner(path, info); err != nil {
+			return err
+		}
+		pipe, err := os.OpenFile(path, os.O_WRONLY|syscall.O_NONBLOCK, 0)
+		if errors.Is(err, syscall.ENXIO) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to open snapshot pipe: %w", err)
+		}
+		defer pipe.Close()
+		if _, err := pipe.Write(append(content, '\n')); err != nil {
+			return fmt.Errorf("failed to write snapshot pipe: %w", err)
+		}
+		return nil
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	if err := checkPublishDir(dir); err != nil {
+		return err
+	}
+
+	// A fresh, unpredictable name that only this process can open
+	temp, err := os.CreateTemp(dir, "."+filepath.Base(path)+"-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot file: %w", err)
+	}
+	_, writeErr := temp.Write(content)
+	if closeErr := temp.Close(); writeErr == nil {
+		writeErr = closeErr
+	}
+	if writeErr != nil {
+		os.Remove(temp.Name())
+		return fmt.Errorf("failed to write snapshot file: %w", writeErr)
+	}
+	if err := os.Rename(temp.Name(), path); err != nil {
+		os.Remove(temp.Name())
+		return fmt.Errorf("failed to replace snapshot file: %w", err)
+	}
+	return nil
+}
+
+// SetPublishConfig replaces the publishing settings and publishes straight away when enabled
+func SetPublishConfig(config PublishConfig) error {
+	if config.Enabled && config.Path == "" {
+		return errors.New("snapshot publish path is empty")
+	}
+	if config.Interval < MinPublishInterval {
+		return fmt.Errorf("snapshot publish interval must be at least %s", MinPublishInterval)
+	}
+	if config.ExportFormat != "" && config.ExportFormat != FormatJSON && config.ExportFormat != FormatProtobuf {
+		return fmt.Errorf("unknown snapshot export format %q, use %s or %s", config.ExportFormat, FormatJSON, FormatProtobuf)
+	}
+
+	publisher.mutex.Lock()
+	publisher.config = config
+	publisher.lastError = nil
+	publisher.mutex.Unlock()
+
+	select {
+	case publisher.wake <- true:
+	default:
+	}
+	return nil
+}
+
+// GetPublishConfig returns the publishing settings
+func GetPublishConfig() PublishConfig {
+	publisher.mutex.Lock()
+	defer publisher.mutex.Unlock()
+	return publisher.config
+}
+
+// StartPublishing collects and publishes a snapshot every publish interval until the program exits
+// monitors is called on each cycle for the managers to collect; nothing is collected while publishing is off
+func StartPublishing(monitors func() Monitors) {
+	publisher.mutex.Lock()
+	started := publisher.monitors != nil
+	publisher.monitors = monitors
+	publisher.mutex.Unlock()
+	if started {
+		return
+	}
+
+	go func() {
+		for {
+			config := GetPublishConfig()
+			publisher.mutex.Lock()
+			subscribed := len(publisher.subscribers) > 0
+			publisher.mutex.Unlock()
+
+			if config.Enabled || subscribed {
+				state := Collect(monitors())
+				if config.Enabled {
+					err := Publish(config.Path, state)
+					publisher.mutex.Lock()
+					publisher.lastError = err
+					if err == nil {
+						publisher.lastPublish = state.Timestamp
+					}
+					publisher.mutex.Unlock()
+				}
+				deliver(state)
+			}
+
+			select {
+			case <-publisher.wake:
+			case <-time.After(config.Interval):
+			}
+		}
+	}()
+}
+
+// Subscribe returns a channel receiving every snapshot the publishing loop collects, and a function ending the subscription
+// While anyone is subscribed the loop collects every publish interval even when publishing is off.
+// A subscriber that falls behind only gets the newest snapshot; the ones it missed are dropped
+func Subscribe() (<-chan *SystemState, func()) {
+	states := make(chan *SystemState, 1)
+	publisher.mutex.Lock()
+	if publisher.subscribers == nil {
+		publisher.subscribers = make(map[chan *SystemState]bool)
+	}
+	publisher.subscribers[states] = true
+	publisher.mutex.Unlock()
+
+	// Collect straight away rather than at the end of the current interval
+	select {
+	case publisher.wake <- true:
+	default:
+	}
+
+	unsubscribe := func() {
+		publisher.mutex.Lock()
+		delete(publisher.subscribers, states)
+		publisher.mutex.Unlock()
+	}
+	return states, unsubscribe
+}
+
+// deliver hands state to every subscriber, replacing a snapshot it has not taken yet
+func deliver(state *SystemState) {
+	publisher.mutex.Lock()
+	defer publisher.mutex.Unlock()
+	for states := range publisher.subscribers {
+		select {
+		case <-states:
+		default:
+		}
+		states <- state
+	}
+}
+
+// PublishStatus returns when the latest snapshot was last published and the error of the last attempt
+func PublishStatus() (time.Time, error) {
+	publisher.mutex.Lock()
+	defer publisher.mutex.Unlock()
+	return publisher.lastPublish, publisher.lastError
+}
@@ -0,0 +1,212 @@
+// Package snapshot collects every monitor at the same instant into one SystemState, exports it and
+// publishes the latest one to a fixed path
+//
+// Collect takes one combined snapshot of the monitors it is given:
+//
+//	state := snapshot.Collect(snapshot.Monitors{
+//		CPU:    cpumonitor.NewCPUMonitorManager(),
+//		Memory: memorymonitor.NewMemoryMonitorManager(),
+//	})
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/ahmadreza-log/simple-monitor/healthscore"
+	"github.com/ahmadreza-log/simple-monitor/protoexport"
+	"github.com/ahmadreza-log/simple-monitor/redact"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Formats a combined snapshot can be exported in
+const (
+	FormatJSON     = "json"
+	FormatProtobuf = "protobuf"
+)
+
+// Exporter writes combined snapshots to JSON or protobuf files
+type Exporter struct {
+	LogsDirectory string // Base directory for log files
+	CreateSubDirs bool   // Whether to write into a system_state subdirectory
+	PrettyPrint   bool   // Whether to pretty print JSON output
+	Format        string // FormatJSON or FormatProtobuf
+}
+
+// NewExporter creates a new snapshot exporter with default configuration values
+func NewExporter() *Exporter {
+	return &Exporter{
+		LogsDirectory: "logs",
+		CreateSubDirs: true,
+		PrettyPrint:   true,
+		Format:        FormatJSON,
+	}
+}
+
+// Collect gathers all monitors at once and returns them as one document
+// Monitors are collected concurrently so the figures describe the same moment;
+// a failing monitor is recorded in Errors and does not stop the others
+func Collect(monitors Monitors) *SystemState {
+	state := &SystemState{
+		Timestamp: time.Now(),
+		Errors:    make(map[string]string),
+	}
+	if hostname, err := os.Hostname(); err == nil {
+		state.Hostname = hostname
+	}
+
+	var mutex sync.Mutex
+	var group sync.WaitGroup
+	collect := func(name string, collectFunc func() error) {
+		group.Add(1)
+		go func() {
+			defer group.Done()
+			defer func() {
+				// One broken collector must not take down the whole snapshot
+				if recovered := recover(); recovered != nil {
+					mutex.Lock()
+					state.Errors[name] = fmt.Sprintf("collector panicked: %v", recovered)
+					mutex.Unlock()
+				}
+			}()
+
+			if err := collectFunc(); err != nil {
+				mutex.Lock()
+				state.Errors[name] = err.Error()
+				mutex.Unlock()
+			}
+		}()
+	}
+
+	if monitors.CPU != nil {
+		collect("cpu", func() (err error) {
+			state.CPU, err = monitors.CPU.Collect()
+			return err
+		})
+	}
+	if monitors.Memory != nil {
+		collect("memory", func() (err error) {
+			state.Memory, err = monitors.Memory.Collect()
+			return err
+		})
+	}
+	if monitors.Disk != nil {
+		collect("disk", func() (err error) {
+			state.Disk, err = monitors.Disk.Collect()
+			return err
+		})
+	}
+	if monitors.Network != nil {
+		collect("network", func() (err error) {
+			state.Network, err = monitors.Network.Collect()
+			return err
+		})
+	}
+	if monitors.Process != nil {
+		collect("process", func() (err error) {
+			state.Process, err = monitors.Process.Collect()
+			return err
+		})
+	}
+
+	group.Wait()
+	state.CollectionDuration = time.Since(state.Timestamp)
+	state.Score = healthscore.Compute(state.CPU, state.Memory, state.Disk, state.Network, state.Process)
+
+	if len(state.Errors) == 0 {
+		state.Errors = nil
+	}
+	return state
+}
+
+// Export writes the snapshot in the exporter's format and returns the file path
+func (exporter *Exporter) Export(state *SystemState) (string, error) {
+	if exporter.Format == FormatProtobuf {
+		return exporter.ExportToProtobuf(state)
+	}
+	return exporter.ExportToJSON(state)
+}
+
+// ExportToJSON writes the snapshot to system_state_<timestamp>.json and returns the file path
+// The file name uses the shared snapshot timestamp
+func (exporter *Exporter) ExportToJSON(state *SystemState) (string, error) {
+	if err := redact.Copy(&state); err != nil {
+		return "", err
+	}
+	filePath, err := exporter.filePath(state, "json")
+	if err != nil {
+		return "", err
+	}
+
+	var jsonData []byte
+	if exporter.PrettyPrint {
+		jsonData, err = json.MarshalIndent(state, "", "  ")
+	} else {
+		jsonData, err = json.Marshal(state)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+
+	if err := os.WriteFile(filePath, jsonData, 0644); err != nil {
+		return "", fmt.Errorf("failed to write snapshot file: %w", err)
+	}
+	return filePath, nil
+}
+
+// ExportToProtobuf writes the snapshot to system_state_<timestamp>.pb as one SystemState message and returns the file path
+// The message definitions are printed by `simple-monitor proto`
+func (exporter *Exporter) ExportToProtobuf(state *SystemState) (string, error) {
+	if err := redact.Copy(&state); err != nil {
+		return "", err
+	}
+	filePath, err := exporter.filePath(state, protoexport.Extension)
+	if err != nil {
+		return "", err
+	}
+
+	content, err := protoexport.Marshal(state)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	if err := os.WriteFile(filePath, content, 0644); err != nil {
+		return "", fmt.Errorf("failed to write snapshot file: %w", err)
+	}
+	return filePath, nil
+}
+
+// filePath returns where a snapshot file with the given extension goes, creating its directory
+func (exporter *Exporter) filePath(state *SystemState, extension string) (string, error) {
+	targetDir := exporter.LogsDirectory
+	if exporter.CreateSubDirs {
+		targetDir = filepath.Join(exporter.LogsDirectory, "system_state")
+	}
+	if err := os.MkdirAll(targetDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+
+	filename := fmt.Sprintf("system_state_%s.%s", state.Timestamp.Format("2006-01-02_15-04-05"), extension)
+	return filepath.Join(targetDir, filename), nil
+}
+
+// SetFormat sets the format Export writes; anything but FormatProtobuf writes JSON
+func (exporter *Exporter) SetFormat(format string) {
+	exporter.Format = format
+}
+
+// SetLogsDirectory sets the base directory for snapshot files
+func (exporter *Exporter) SetLogsDirectory(dir string) {
+	exporter.LogsDirectory = dir
+}
+
+// SetPrettyPrint sets whether JSON output is indented
+func (exporter *Exporter) SetPrettyPrint(pretty bool) {
+	exporter.PrettyPrint = pretty
+}
+
+// SetCreateSubDirs sets whether snapshots go into a system_state subdirectory
+func (exporter *Exporter) SetCreateSubDirs(create bool) {
+	exporter.CreateSubDirs = create
+}
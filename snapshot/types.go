@@ -0,0 +1,55 @@
+package snapshot
+
+import (
+	"github.com/ahmadreza-log/simple-monitor/cpumonitor"
+	"github.com/ahmadreza-log/simple-monitor/diskmonitor"
+	"github.com/ahmadreza-log/simple-monitor/healthscore"
+	"github.com/ahmadreza-log/simple-monitor/memorymonitor"
+	"github.com/ahmadreza-log/simple-monitor/networkmonitor"
+	"github.com/ahmadreza-log/simple-monitor/processmonitor"
+	"sync"
+	"time"
+)
+
+// SystemState is one combined snapshot of all monitors captured at the same instant
+type SystemState struct {
+	Timestamp          time.Time                          `json:"timestamp"`           // Shared capture time for all monitors
+	Hostname           string                             `json:"hostname"`            // Host the snapshot was taken on
+	CollectionDuration time.Duration                      `json:"collection_duration"` // Time taken to collect all monitors
+	CPU                *cpumonitor.CPUMonitorData         `json:"cpu,omitempty"`       // CPU monitor data
+	Memory             *memorymonitor.MemoryMonitorData   `json:"memory,omitempty"`    // Memory monitor data
+	Disk               *diskmonitor.DiskMonitorData       `json:"disk,omitempty"`      // Disk monitor data
+	Network            *networkmonitor.NetworkMonitorData `json:"network,omitempty"`   // Network monitor data
+	Process            *processmonitor.ProcessMonitorData `json:"process,omitempty"`   // Process monitor data
+	Score              *healthscore.Score                 `json:"score,omitempty"`     // Health index combining the monitors' alerts
+	Errors             map[string]string                  `json:"errors,omitempty"`    // Collection errors keyed by monitor name
+}
+
+// Monitors holds the managers whose collectors feed the combined snapshot
+// Using the running managers keeps rate baselines, so rates are real rather than first-sample values
+type Monitors struct {
+	CPU     *cpumonitor.CPUMonitorManager
+	Memory  *memorymonitor.MemoryMonitorManager
+	Disk    *diskmonitor.DiskMonitorManager
+	Network *networkmonitor.NetworkMonitorManager
+	Process *processmonitor.ProcessMonitorManager
+}
+
+// PublishConfig holds the snapshot settings: publishing the latest snapshot to a fixed path and the export format
+type PublishConfig struct {
+	Enabled      bool          `json:"publish"`          // Whether the latest snapshot is published while the program is open
+	Path         string        `json:"publish_path"`     // File replaced on every cycle, or a named pipe that receives one JSON document per line
+	Interval     time.Duration `json:"publish_interval"` // How often a new snapshot is collected and published
+	ExportFormat string        `json:"export_format"`    // Format of exported combined snapshots: json or protobuf (empty means json)
+}
+
+// publishState holds the settings and the background publishing loop
+type publishState struct {
+	config      PublishConfig
+	monitors    func() Monitors
+	lastPublish time.Time
+	lastError   error
+	subscribers map[chan *SystemState]bool // Receivers of every collected snapshot, see Subscribe
+	wake        chan bool
+	mutex       sync.Mutex
+}
@@ -0,0 +1,218 @@
+package stream
+
+import (
+	"github.com/ahmadreza-log/simple-monitor/cpumonitor"
+	"github.com/ahmadreza-log/simple-monitor/diskmonitor"
+	"github.com/ahmadreza-log/simple-monitor/healthscore"
+	"github.com/ahmadreza-log/simple-monitor/memorymonitor"
+	"github.com/ahmadreza-log/simple-monitor/networkmonitor"
+	"github.com/ahmadreza-log/simple-monitor/processmonitor"
+	"github.com/ahmadreza-log/simple-monitor/snapshot"
+	"github.com/ahmadreza-log/simple-monitor/stream/monitorpb"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// systemSample converts a combined snapshot; monitors missing from it stay unset
+func systemSample(state *snapshot.SystemState) *monitorpb.SystemSample {
+	sample := &monitorpb.SystemSample{
+		Timestamp:          timestamppb.New(state.Timestamp),
+		Hostname:           state.Hostname,
+		CollectionDuration: durationpb.New(state.CollectionDuration),
+		Errors:             state.Errors,
+	}
+	if state.CPU != nil {
+		sample.Cpu = cpuSample(state.CPU, state.Hostname)
+	}
+	if state.Memory != nil {
+		sample.Memory = memorySample(state.Memory)
+	}
+	if state.Disk != nil {
+		sample.Disk = diskSample(state.Disk)
+	}
+	if state.Network != nil {
+		sample.Network = networkSample(state.Network)
+	}
+	if state.Process != nil {
+		sample.Process = processSample(state.Process)
+	}
+	if state.Score != nil {
+		sample.Score = healthScore(state.Score)
+	}
+	return sample
+}
+
+// cpuSample converts the CPU monitor's data
+func cpuSample(data *cpumonitor.CPUMonitorData, hostname string) *monitorpb.CPUSample {
+	sample := &monitorpb.CPUSample{
+		Timestamp:         timestamppb.New(data.Timestamp),
+		Hostname:          hostname,
+		OverallUsage:      data.OverallUsage,
+		UserUsage:         data.UserUsage,
+		SystemUsage:       data.SystemUsage,
+		IdleUsage:         data.IdleUsage,
+		IoWaitUsage:       data.IOWaitUsage,
+		UsageStatus:       data.UsageStatus,
+		Load_1Min:         data.LoadAverage1Min,
+		Load_5Min:         data.LoadAverage5Min,
+		Load_15Min:        data.LoadAverage15Min,
+		ForkRate:          data.ForkRate,
+		Temperature:       data.Temperature,
+		TemperatureStatus: data.TemperatureStatus,
+		Simulated:         data.Simulated,
+	}
+	for _, core := range data.Cores {
+		sample.Cores = append(sample.Cores, &monitorpb.CPUCore{
+			CoreId:       int32(core.CoreID),
+			UsagePercent: core.UsagePercent,
+			Frequency:    core.Frequency,
+			Temperature:  core.Temperature,
+		})
+	}
+	for _, process := range data.TopProcesses {
+		sample.TopProcesses = append(sample.TopProcesses, &monitorpb.ProcessUsage{
+			Pid:           process.PID,
+			Name:          process.Name,
+			CpuPercent:    process.CPUUsagePercent,
+			MemoryPercent: process.MemoryPercent,
+			MemoryBytes:   process.MemoryUsage,
+		})
+	}
+	return sample
+}
+
+// memorySample converts the memory monitor's data
+func memorySample(data *memorymonitor.MemoryMonitorData) *monitorpb.MemorySample {
+	sample := &monitorpb.MemorySample{
+		Total:            data.TotalMemory,
+		Available:        data.AvailableMemory,
+		Used:             data.UsedMemory,
+		UsedPercent:      data.MemoryPercent,
+		AvailablePercent: data.AvailablePercent,
+		Cache:            data.CacheMemory,
+		SwapTotal:        data.SwapInfo.TotalSwap,
+		SwapUsed:         data.SwapInfo.UsedSwap,
+		SwapPercent:      data.SwapInfo.SwapPercent,
+		Status:           data.MemoryStatus,
+	}
+	for _, process := range data.TopProcesses {
+		sample.TopProcesses = append(sample.TopProcesses, &monitorpb.ProcessUsage{
+			Pid:           process.PID,
+			Name:          process.Name,
+			User:          process.User,
+			MemoryPercent: process.MemoryPercent,
+			MemoryBytes:   process.RSS,
+		})
+	}
+	return sample
+}
+
+// diskSample converts the disk monitor's data
+func diskSample(data *diskmonitor.DiskMonitorData) *monitorpb.DiskSample {
+	sample := &monitorpb.DiskSample{
+		Total:        data.TotalSpace,
+		Used:         data.UsedSpace,
+		Free:         data.FreeSpace,
+		UsagePercent: data.UsagePercent,
+		ReadSpeed:    data.TotalReadSpeed,
+		WriteSpeed:   data.TotalWriteSpeed,
+		Iops:         data.AverageIOPS,
+		Utilization:  data.DiskUtilization,
+		Status:       data.DiskStatus,
+	}
+	for _, partition := range data.Partitions {
+		sample.Partitions = append(sample.Partitions, &monitorpb.Partition{
+			Device:       partition.Device,
+			Mountpoint:   partition.Mountpoint,
+			Fstype:       partition.Fstype,
+			Total:        partition.Total,
+			Used:         partition.Used,
+			Free:         partition.Free,
+			UsagePercent: partition.UsagePercent,
+			ReadOnly:     partition.ReadOnly,
+		})
+	}
+	for _, device := range data.DiskIO {
+		sample.Devices = append(sample.Devices, &monitorpb.DiskDevice{
+			Name:        device.DeviceName,
+			ReadSpeed:   device.ReadSpeed,
+			WriteSpeed:  device.WriteSpeed,
+			Iops:        device.IOPS,
+			Utilization: device.Utilization,
+		})
+	}
+	return sample
+}
+
+// networkSample converts the network monitor's data
+func networkSample(data *networkmonitor.NetworkMonitorData) *monitorpb.NetworkSample {
+	sample := &monitorpb.NetworkSample{
+		BytesSent:      data.TotalBytesSent,
+		BytesRecv:      data.TotalBytesRecv,
+		SendSpeed:      data.TotalSendSpeed,
+		RecvSpeed:      data.TotalRecvSpeed,
+		RateUnit:       data.RateUnit,
+		AverageLatency: data.AverageLatency,
+		PacketLossRate: data.PacketLossRate,
+		Status:         data.NetworkStatus,
+	}
+	for _, io := range data.InterfaceIO {
+		sample.Interfaces = append(sample.Interfaces, &monitorpb.NetworkInterface{
+			Name:      io.InterfaceName,
+			BytesSent: io.BytesSent,
+			BytesRecv: io.BytesRecv,
+			SendSpeed: io.SendSpeed,
+			RecvSpeed: io.RecvSpeed,
+			Errors:    io.SendErrors + io.RecvErrors,
+			Drops:     io.DropIn + io.DropOut,
+		})
+	}
+	return sample
+}
+
+// processSample converts the process monitor's data
+func processSample(data *processmonitor.ProcessMonitorData) *monitorpb.ProcessSample {
+	sample := &monitorpb.ProcessSample{
+		Total:    int32(data.TotalProcesses),
+		Running:  int32(data.RunningProcesses),
+		Sleeping: int32(data.SleepingProcesses),
+		Zombie:   int32(data.ZombieProcesses),
+		Threads:  data.TotalThreads,
+		Status:   data.ProcessStatus,
+	}
+	for _, process := range data.TopCPUProcesses {
+		sample.TopCpu = append(sample.TopCpu, processUsage(process))
+	}
+	for _, process := range data.TopMemoryProcesses {
+		sample.TopMemory = append(sample.TopMemory, processUsage(process))
+	}
+	return sample
+}
+
+// processUsage converts one process of the process monitor
+func processUsage(process processmonitor.ProcessInfo) *monitorpb.ProcessUsage {
+	return &monitorpb.ProcessUsage{
+		Pid:           process.PID,
+		Name:          process.Name,
+		User:          process.User,
+		CpuPercent:    process.CPUUsage,
+		MemoryPercent: process.MemoryUsage,
+		MemoryBytes:   process.MemoryRSS,
+	}
+}
+
+// healthScore converts the health score with its per-monitor components
+func healthScore(score *healthscore.Score) *monitorpb.HealthScore {
+	converted := &monitorpb.HealthScore{Value: score.Value, Status: score.Status}
+	for _, component := range score.Components {
+		converted.Components = append(converted.Components, &monitorpb.ScoreComponent{
+			Name:    component.Name,
+			Value:   component.Value,
+			Weight:  component.Weight,
+			Status:  component.Status,
+			Reasons: component.Reasons,
+		})
+	}
+	return converted
+}
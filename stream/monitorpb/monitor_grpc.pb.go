@@ -0,0 +1,214 @@
+// Live metrics streamed by simple-monitor's gRPC API
+//
+// Regenerate the Go code in stream/monitorpb with `make proto` after changing proto/monitor.proto
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.4.0
+// - protoc             v5.27.3
+// source: proto/monitor.proto
+
+package monitorpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.62.0 or later.
+const _ = grpc.SupportPackageIsVersion8
+
+const (
+	MonitorStream_SubscribeCPU_FullMethodName = "/simplemonitor.v1.MonitorStream/SubscribeCPU"
+	MonitorStream_SubscribeAll_FullMethodName = "/simplemonitor.v1.MonitorStream/SubscribeAll"
+)
+
+// MonitorStreamClient is the client API for MonitorStream service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// MonitorStream sends a sample after every collection cycle until the client cancels
+type MonitorStreamClient interface {
+	// SubscribeCPU streams the CPU monitor
+	SubscribeCPU(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (MonitorStream_SubscribeCPUClient, error)
+	// SubscribeAll streams every enabled monitor with the health score
+	SubscribeAll(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (MonitorStream_SubscribeAllClient, error)
+}
+
+type monitorStreamClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMonitorStreamClient(cc grpc.ClientConnInterface) MonitorStreamClient {
+	return &monitorStreamClient{cc}
+}
+
+func (c *monitorStreamClient) SubscribeCPU(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (MonitorStream_SubscribeCPUClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &MonitorStream_ServiceDesc.Streams[0], MonitorStream_SubscribeCPU_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &monitorStreamSubscribeCPUClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type MonitorStream_SubscribeCPUClient interface {
+	Recv() (*CPUSample, error)
+	grpc.ClientStream
+}
+
+type monitorStreamSubscribeCPUClient struct {
+	grpc.ClientStream
+}
+
+func (x *monitorStreamSubscribeCPUClient) Recv() (*CPUSample, error) {
+	m := new(CPUSample)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *monitorStreamClient) SubscribeAll(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (MonitorStream_SubscribeAllClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &MonitorStream_ServiceDesc.Streams[1], MonitorStream_SubscribeAll_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &monitorStreamSubscribeAllClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type MonitorStream_SubscribeAllClient interface {
+	Recv() (*SystemSample, error)
+	grpc.ClientStream
+}
+
+type monitorStreamSubscribeAllClient struct {
+	grpc.ClientStream
+}
+
+func (x *monitorStreamSubscribeAllClient) Recv() (*SystemSample, error) {
+	m := new(SystemSample)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// MonitorStreamServer is the server API for MonitorStream service.
+// All implementations must embed UnimplementedMonitorStreamServer
+// for forward compatibility
+//
+// MonitorStream sends a sample after every collection cycle until the client cancels
+type MonitorStreamServer interface {
+	// SubscribeCPU streams the CPU monitor
+	SubscribeCPU(*SubscribeRequest, MonitorStream_SubscribeCPUServer) error
+	// SubscribeAll streams every enabled monitor with the health score
+	SubscribeAll(*SubscribeRequest, MonitorStream_SubscribeAllServer) error
+	mustEmbedUnimplementedMonitorStreamServer()
+}
+
+// UnimplementedMonitorStreamServer must be embedded to have forward compatible implementations.
+type UnimplementedMonitorStreamServer struct {
+}
+
+func (UnimplementedMonitorStreamServer) SubscribeCPU(*SubscribeRequest, MonitorStream_SubscribeCPUServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeCPU not implemented")
+}
+func (UnimplementedMonitorStreamServer) SubscribeAll(*SubscribeRequest, MonitorStream_SubscribeAllServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeAll not implemented")
+}
+func (UnimplementedMonitorStreamServer) mustEmbedUnimplementedMonitorStreamServer() {}
+
+// UnsafeMonitorStreamServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MonitorStreamServer will
+// result in compilation errors.
+type UnsafeMonitorStreamServer interface {
+	mustEmbedUnimplementedMonitorStreamServer()
+}
+
+func RegisterMonitorStreamServer(s grpc.ServiceRegistrar, srv MonitorStreamServer) {
+	s.RegisterService(&MonitorStream_ServiceDesc, srv)
+}
+
+func _MonitorStream_SubscribeCPU_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MonitorStreamServer).SubscribeCPU(m, &monitorStreamSubscribeCPUServer{ServerStream: stream})
+}
+
+type MonitorStream_SubscribeCPUServer interface {
+	Send(*CPUSample) error
+	grpc.ServerStream
+}
+
+type monitorStreamSubscribeCPUServer struct {
+	grpc.ServerStream
+}
+
+func (x *monitorStreamSubscribeCPUServer) Send(m *CPUSample) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _MonitorStream_SubscribeAll_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MonitorStreamServer).SubscribeAll(m, &monitorStreamSubscribeAllServer{ServerStream: stream})
+}
+
+type MonitorStream_SubscribeAllServer interface {
+	Send(*SystemSample) error
+	grpc.ServerStream
+}
+
+type monitorStreamSubscribeAllServer struct {
+	grpc.ServerStream
+}
+
+func (x *monitorStreamSubscribeAllServer) Send(m *SystemSample) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// MonitorStream_ServiceDesc is the grpc.ServiceDesc for MonitorStream service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var MonitorStream_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "simplemonitor.v1.MonitorStream",
+	HandlerType: (*MonitorStreamServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeCPU",
+			Handler:       _MonitorStream_SubscribeCPU_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SubscribeAll",
+			Handler:       _MonitorStream_SubscribeAll_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "proto/monitor.proto",
+}
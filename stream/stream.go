@@ -0,0 +1,281 @@
+// Package stream serves live metrics over the gRPC streaming API defined in proto/monitor.proto
+package stream
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+	"reflect"
+	"strings"
+
+	"github.com/ahmadreza-log/simple-monitor/snapshot"
+	"github.com/ahmadreza-log/simple-monitor/stream/monitorpb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultAddress is where the API listens unless another address is configured
+const DefaultAddress = "127.0.0.1:50051"
+
+// readMethods are the methods the read role may call; they only send metrics
+// Changing settings, killing processes and triggering exports are not part of the API yet; when they are
+// added they stay admin-only by leaving them out of this list
+var readMethods = map[string]bool{
+	monitorpb.MonitorStream_SubscribeCPU_FullMethodName: true,
+	monitorpb.MonitorStream_SubscribeAll_FullMethodName: true,
+}
+
+// current is shared by the whole program; the API is off until enabled
+var current = serverState{config: Config{Address: DefaultAddress}}
+
+// SetConfig replaces the settings and, once Start was called, starts, moves or stops the server to match
+// The metrics include process names and command lines, so an address other hosts can reach is refused
+// unless clients need a token and the connection is encrypted
+func SetConfig(config Config) error {
+	if config.Address == "" {
+		config.Address = DefaultAddress
+	}
+	host, _, err := net.SplitHostPort(config.Address)
+	if err != nil {
+		return fmt.Errorf("invalid stream address %q: %w", config.Address, err)
+	}
+	if (config.TLSCert == "") != (config.TLSKey == "") {
+		return errors.New("stream tls_cert and tls_key must be set together")
+	}
+	for name, user := range config.Users {
+		if user.Role != RoleRead && user.Role != RoleAdmin {
+			return fmt.Errorf("stream user %q: unknown role %q (use %s or %s)", name, user.Role, RoleRead, RoleAdmin)
+		}
+		if hash, err := hex.DecodeString(user.PasswordSHA256); err != nil || len(hash) != sha256.Size {
+			return fmt.Errorf("stream user %q: password_sha256 must be 64 hex digits", name)
+		}
+	}
+	if !isLoopback(host) && (!config.authenticates() || config.TLSCert == "") {
+		return fmt.Errorf("stream address %q is reachable from other hosts: set a token or user, tls_cert and tls_key first, or listen on %s", config.Address, DefaultAddress)
+	}
+
+	current.mutex.Lock()
+	defer current.mutex.Unlock()
+	config.Users = maps.Clone(config.Users)
+	current.config = config
+	if current.started {
+		return current.apply()
+	}
+	return nil
+}
+
+// GetConfig returns the streaming API settings
+func GetConfig() Config {
+	current.mutex.Lock()
+	defer current.mutex.Unlock()
+	config := current.config
+	config.Users = maps.Clone(config.Users)
+	return config
+}
+
+// authenticates reports whether clients must log in; otherwise every client is admin
+func (config Config) authenticates() bool {
+	return config.Token != "" || config.ReadToken != "" || len(config.Users) > 0
+}
+
+// Start serves the API with the current settings from now on; until it is called SetConfig only stores them
+// Samples come from the snapshot publishing loop, so snapshot.StartPublishing must have been called
+func Start() error {
+	current.mutex.Lock()
+	defer current.mutex.Unlock()
+	current.started = true
+	return current.apply()
+}
+
+// Status returns the address the server listens on (empty when stopped), the number of open subscriptions
+// and why it last failed to start
+func Status() (string, int, error) {
+	current.mutex.Lock()
+	defer current.mutex.Unlock()
+	return current.address, current.clients, current.lastError
+}
+
+// isLoopback reports whether host only accepts connections from this machine
+// Other host names are not resolved and count as reachable from elsewhere
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// apply starts or stops the server to match the settings; the caller holds the mutex
+func (state *serverState) apply() error {
+	if state.server != nil && (!state.config.Enabled || !reflect.DeepEqual(state.config, state.serving)) {
+		// Stop rather than GracefulStop: subscriptions never end on their own
+		state.server.Stop()
+		state.server = nil
+		state.address = ""
+	}
+	if !state.config.Enabled || state.server != nil {
+		state.lastError = nil
+		return nil
+	}
+
+	var options []grpc.ServerOption
+	if state.config.TLSCert != "" {
+		creds, err := credentials.NewServerTLSFromFile(state.config.TLSCert, state.config.TLSKey)
+		if err != nil {
+			state.lastError = fmt.Errorf("failed to load stream TLS certificate: %w", err)
+			return state.lastError
+		}
+		options = append(options, grpc.Creds(creds))
+	}
+	config := state.config
+	options = append(options,
+		grpc.UnaryInterceptor(func(ctx context.Context, request any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			if err := authorize(ctx, config, info.FullMethod); err != nil {
+				return nil, err
+			}
+			return handler(ctx, request)
+		}),
+		grpc.StreamInterceptor(func(server any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			if err := authorize(stream.Context(), config, info.FullMethod); err != nil {
+				return err
+			}
+			return handler(server, stream)
+		}))
+
+	listener, err := net.Listen("tcp", state.config.Address)
+	if err != nil {
+		state.lastError = fmt.Errorf("failed to start stream API: %w", err)
+		return state.lastError
+	}
+	server := grpc.NewServer(options...)
+	monitorpb.RegisterMonitorStreamServer(server, &service{})
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			current.mutex.Lock()
+			if current.server == server {
+				current.server = nil
+				current.address = ""
+				current.lastError = fmt.Errorf("stream API stopped: %w", err)
+			}
+			current.mutex.Unlock()
+		}
+	}()
+	state.server = server
+	state.serving = state.config
+	state.address = listener.Addr().String()
+	state.lastError = nil
+	return nil
+}
+
+// authorize accepts a call when the client's role may call method
+// Methods not listed in readMethods, including any added later, need the admin role
+func authorize(ctx context.Context, config Config, method string) error {
+	role := RoleAdmin
+	if config.authenticates() {
+		incoming, _ := metadata.FromIncomingContext(ctx)
+		role = ""
+		for _, value := range incoming.Get("authorization") {
+			if role = clientRole(config, value); role != "" {
+				break
+			}
+		}
+		if role == "" {
+			return status.Error(codes.Unauthenticated, "missing or invalid stream API credentials")
+		}
+	}
+	if role != RoleAdmin && !readMethods[method] {
+		return status.Errorf(codes.PermissionDenied, "%s needs the %s role", method, RoleAdmin)
+	}
+	return nil
+}
+
+// clientRole returns the role the credentials in an authorization header grant, or "" when they are not valid
+func clientRole(config Config, header string) string {
+	if token, ok := strings.CutPrefix(header, "Bearer "); ok {
+		if config.Token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(config.Token)) == 1 {
+			return RoleAdmin
+		}
+		if config.ReadToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(config.ReadToken)) == 1 {
+			return RoleRead
+		}
+		return ""
+	}
+	encoded, ok := strings.CutPrefix(header, "Basic ")
+	if !ok {
+		return ""
+	}
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return ""
+	}
+	name, password, _ := strings.Cut(string(decoded), ":")
+	user, known := config.Users[name]
+	if !known {
+		return ""
+	}
+	hash := sha256.Sum256([]byte(password))
+	if subtle.ConstantTimeCompare([]byte(hex.EncodeToString(hash[:])), []byte(strings.ToLower(user.PasswordSHA256))) != 1 {
+		return ""
+	}
+	return user.Role
+}
+
+// SubscribeCPU sends the CPU monitor of every collected snapshot
+func (*service) SubscribeCPU(request *monitorpb.SubscribeRequest, stream monitorpb.MonitorStream_SubscribeCPUServer) error {
+	return subscribe(request, stream.Context().Done(), func(state *snapshot.SystemState) (bool, error) {
+		if state.CPU == nil {
+			return false, nil
+		}
+		return true, stream.Send(cpuSample(state.CPU, state.Hostname))
+	})
+}
+
+// SubscribeAll sends every collected snapshot
+func (*service) SubscribeAll(request *monitorpb.SubscribeRequest, stream monitorpb.MonitorStream_SubscribeAllServer) error {
+	return subscribe(request, stream.Context().Done(), func(state *snapshot.SystemState) (bool, error) {
+		return true, stream.Send(systemSample(state))
+	})
+}
+
+// subscribe passes snapshots to send until the client cancels or request.Count samples were sent
+// send reports whether it sent a sample; a snapshot without the subscribed monitor is skipped
+func subscribe(request *monitorpb.SubscribeRequest, done <-chan struct{}, send func(*snapshot.SystemState) (bool, error)) error {
+	states, unsubscribe := snapshot.Subscribe()
+	defer unsubscribe()
+
+	current.mutex.Lock()
+	current.clients++
+	current.mutex.Unlock()
+	defer func() {
+		current.mutex.Lock()
+		current.clients--
+		current.mutex.Unlock()
+	}()
+
+	var sent uint32
+	for request.GetCount() == 0 || sent < request.GetCount() {
+		select {
+		case <-done:
+			return nil
+		case state := <-states:
+			ok, err := send(state)
+			if err != nil {
+				return err
+			}
+			if ok {
+				sent++
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package stream
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+
+	"github.com/ahmadreza-log/simple-monitor/stream/monitorpb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuthorize(t *testing.T) {
+	hash := sha256.Sum256([]byte("secret"))
+	config := Config{
+		Token:     "admin-token",
+		ReadToken: "read-token",
+		Users: map[string]User{
+			"viewer": {PasswordSHA256: hex.EncodeToString(hash[:]), Role: RoleRead},
+			"ops":    {PasswordSHA256: hex.EncodeToString(hash[:]), Role: RoleAdmin},
+		},
+	}
+	basic := func(user, password string) string {
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(user+":"+password))
+	}
+	const adminMethod = "/simplemonitor.v1.MonitorStream/Kill"
+
+	tests := []struct {
+		name   string
+		config Config
+		header string
+		method string
+		want   codes.Code
+	}{
+		{name: "no login configured", config: Config{}, method: adminMethod, want: codes.OK},
+		{name: "missing credentials", config: config, method: monitorpb.MonitorStream_SubscribeAll_FullMethodName, want: codes.Unauthenticated},
+		{name: "wrong token", config: config, header: "Bearer nope", method: monitorpb.MonitorStream_SubscribeAll_FullMethodName, want: codes.Unauthenticated},
+		{name: "read token on a read method", config: config, header: "Bearer read-token", method: monitorpb.MonitorStream_SubscribeCPU_FullMethodName, want: codes.OK},
+		{name: "read token on an admin method", config: config, header: "Bearer read-token", method: adminMethod, want: codes.PermissionDenied},
+		{name: "admin token on an admin method", config: config, header: "Bearer admin-token", method: adminMethod, want: codes.OK},
+		{name: "basic auth read user", config: config, header: basic("viewer", "secret"), method: monitorpb.MonitorStream_SubscribeAll_FullMethodName, want: codes.OK},
+		{name: "basic auth read user on an admin method", config: config, header: basic("viewer", "secret"), method: adminMethod, want: codes.PermissionDenied},
+		{name: "basic auth admin user", config: config, header: basic("ops", "secret"), method: adminMethod, want: codes.OK},
+		{name: "basic auth wrong password", config: config, header: basic("ops", "guess"), method: adminMethod, want: codes.Unauthenticated},
+		{name: "basic auth unknown user", config: config, header: basic("root", "secret"), method: adminMethod, want: codes.Unauthenticated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.header != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", tt.header))
+			}
+			if got := status.Code(authorize(ctx, tt.config, tt.method)); got != tt.want {
+				t.Errorf("authorize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetConfigRefusesOpenRemoteAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr bool
+	}{
+		{name: "loopback without login", config: Config{Address: "127.0.0.1:0"}},
+		{name: "remote without login", config: Config{Address: "0.0.0.0:0", TLSCert: "cert.pem", TLSKey: "key.pem"}, wantErr: true},
+		{name: "remote without TLS", config: Config{Address: "0.0.0.0:0", Token: "t"}, wantErr: true},
+		{name: "remote with read token and TLS", config: Config{Address: "0.0.0.0:0", ReadToken: "t", TLSCert: "cert.pem", TLSKey: "key.pem"}},
+		{name: "unknown role", config: Config{Users: map[string]User{"a": {PasswordSHA256: hex.EncodeToString(make([]byte, 32)), Role: "root"}}}, wantErr: true},
+		{name: "short hash", config: Config{Users: map[string]User{"a": {PasswordSHA256: "abc", Role: RoleRead}}}, wantErr: true},
+	}
+
+	previous := GetConfig()
+	defer SetConfig(previous)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := SetConfig(tt.config); (err != nil) != tt.wantErr {
+				t.Errorf("SetConfig() error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
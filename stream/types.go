@@ -0,0 +1,50 @@
+package stream
+
+import (
+	"sync"
+
+	"github.com/ahmadreza-log/simple-monitor/stream/monitorpb"
+
+	"google.golang.org/grpc"
+)
+
+// Roles a client can have; admin can call every method, read only the methods in readMethods
+const (
+	RoleRead  = "read"
+	RoleAdmin = "admin"
+)
+
+// Config holds the settings of the gRPC streaming API
+// Without a token or user every client has the admin role, which is only allowed on a loopback address
+type Config struct {
+	Enabled   bool            `json:"enabled"`    // Whether the API listens while the program is open
+	Address   string          `json:"address"`    // host:port to listen on; a non-loopback address needs a token or user, tls_cert and tls_key
+	Token     string          `json:"token"`      // Secret clients send as "authorization: Bearer <token>" metadata for the admin role
+	ReadToken string          `json:"read_token"` // Secret sent the same way for the read-only role
+	Users     map[string]User `json:"users"`      // Accounts clients log in with through basic auth ("authorization: Basic ..."), by user name
+	TLSCert   string          `json:"tls_cert"`   // PEM certificate file; with tls_key the API is served over TLS
+	TLSKey    string          `json:"tls_key"`    // PEM private key file of tls_cert
+}
+
+// User is an account for basic auth
+type User struct {
+	PasswordSHA256 string `json:"password_sha256"` // Hex SHA-256 of the password, e.g. from printf %s "$PASSWORD" | sha256sum
+	Role           string `json:"role"`            // read or admin
+}
+
+// serverState holds the settings and the running gRPC server
+type serverState struct {
+	config    Config
+	started   bool         // Whether Start was called; until then settings are only stored
+	server    *grpc.Server // Running server, nil when stopped
+	serving   Config       // Settings the running server was started with
+	address   string       // Address the running server listens on
+	lastError error        // Why the server last failed to start
+	clients   int          // Open subscriptions
+	mutex     sync.Mutex
+}
+
+// service implements the MonitorStream gRPC service
+type service struct {
+	monitorpb.UnimplementedMonitorStreamServer
+}
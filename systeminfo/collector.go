@@ -1,7 +1,11 @@
 package systeminfo
 
 import (
+	"fmt"
+	"os/exec"
 	"runtime"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -11,7 +15,19 @@ type SystemInfoCollector struct {
 	// Configuration options for data collection
 	IncludeTemperature  bool          // Whether to include CPU temperature data
 	IncludeNetworkStats bool          // Whether to include detailed network statistics
+	IncludeTimeSync     bool          // Whether to include NTP synchronization status
+	IncludePCIDevices   bool          // Whether to include the PCI device and driver inventory
+	IncludePatchLevel   bool          // Whether to check the OS patch level and pending package updates
+	PatchCheckInterval  time.Duration // How often the package manager is queried for pending updates
+	PatchStaleAfter     time.Duration // Time since the last update after which the system is reported as stale
+	ClockOffsetWarning  time.Duration // Clock offset above which time sync is reported as unhealthy
+	IncludeEntropy      bool          // Whether to check available entropy in the kernel random pool (Linux only)
+	EntropyLowThreshold int           // Available entropy in bits below which the pool counts as low
+	EntropyLowAfter     time.Duration // How long entropy must stay low before it is flagged
 	RefreshInterval     time.Duration // How often to refresh the data
+
+	patchCache      *PatchLevelInfo // Last package manager check, reused until PatchCheckInterval passes
+	entropyLowSince time.Time       // When available entropy dropped below EntropyLowThreshold (zero while it is not low)
 }
 
 // NewSystemInfoCollector creates a new instance of SystemInfoCollector
@@ -20,6 +36,15 @@ func NewSystemInfoCollector() *SystemInfoCollector {
 	return &SystemInfoCollector{
 		IncludeTemperature:  true,
 		IncludeNetworkStats: true,
+		IncludeTimeSync:     true,
+		IncludePCIDevices:   true,
+		IncludePatchLevel:   true,
+		PatchCheckInterval:  time.Hour,
+		PatchStaleAfter:     30 * 24 * time.Hour,
+		ClockOffsetWarning:  500 * time.Millisecond,
+		IncludeEntropy:      true,
+		EntropyLowThreshold: 200,
+		EntropyLowAfter:     time.Minute,
 		RefreshInterval:     5 * time.Second,
 	}
 }
@@ -62,6 +87,26 @@ func (collector *SystemInfoCollector) CollectSystemInfo() (*SystemInfo, error) {
 		return nil, err
 	}
 
+	// Collect time synchronization status
+	if collector.IncludeTimeSync {
+		collector.collectTimeSync(systemInfo)
+	}
+
+	// Collect OS patch level and pending updates
+	if collector.IncludePatchLevel {
+		collector.collectPatchLevel(systemInfo)
+	}
+
+	// Collect kernel entropy
+	if collector.IncludeEntropy {
+		collector.collectEntropy(systemInfo)
+	}
+
+	// Collect PCI device and driver inventory
+	if collector.IncludePCIDevices {
+		collector.collectPCIDevices(systemInfo)
+	}
+
 	return systemInfo, nil
 }
 
@@ -153,6 +198,196 @@ func (collector *SystemInfoCollector) collectPerformanceMetrics(systemInfo *Syst
 	return nil
 }
 
+// collectTimeSync gathers NTP synchronization status, clock offset, and drift
+// Failures are recorded in TimeSyncInfo.Error instead of aborting collection,
+// since the required tools are not installed on every system
+func (collector *SystemInfoCollector) collectTimeSync(systemInfo *SystemInfo) {
+	timeSync := &systemInfo.TimeSync
+
+	var err error
+	if runtime.GOOS == "windows" {
+		err = collector.collectTimeSyncW32tm(timeSync)
+	} else {
+		// Prefer chrony since it reports drift, then fall back to systemd-timesyncd
+		if err = collector.collectTimeSyncChrony(timeSync); err != nil {
+			err = collector.collectTimeSyncTimedatectl(timeSync)
+		}
+	}
+
+	if err != nil {
+		timeSync.Error = err.Error()
+		return
+	}
+
+	// Evaluate offset against the configured threshold
+	offset := timeSync.Offset
+	if offset < 0 {
+		offset = -offset
+	}
+	timeSync.OffsetExceeded = collector.ClockOffsetWarning > 0 && offset > collector.ClockOffsetWarning
+}
+
+// collectTimeSyncChrony reads synchronization status from `chronyc tracking`
+func (collector *SystemInfoCollector) collectTimeSyncChrony(timeSync *TimeSyncInfo) error {
+	output, err := exec.Command("chronyc", "tracking").Output()
+	if err != nil {
+		return fmt.Errorf("failed to run chronyc: %w", err)
+	}
+
+	timeSync.Source = "chronyc"
+	timeSync.NTPEnabled = true
+
+	for _, line := range strings.Split(string(output), "\n") {
+		key, value, ok := splitKeyValue(line, ":")
+		if !ok {
+			continue
+		}
+
+		fields := strings.Fields(value)
+		switch key {
+		case "Reference ID":
+			// Format: "A9FEA97B (169.254.169.123)"
+			if len(fields) > 1 {
+				timeSync.ReferenceID = strings.Trim(fields[1], "()")
+			} else if len(fields) == 1 {
+				timeSync.ReferenceID = fields[0]
+			}
+		case "Stratum":
+			timeSync.Stratum, _ = strconv.Atoi(value)
+		case "System time":
+			// Format: "0.000000380 seconds slow of NTP time"
+			if len(fields) >= 3 {
+				if seconds, err := strconv.ParseFloat(fields[0], 64); err == nil {
+					if fields[2] == "slow" {
+						seconds = -seconds
+					}
+					timeSync.Offset = time.Duration(seconds * float64(time.Second))
+				}
+			}
+		case "Frequency":
+			// Format: "7.365 ppm slow"
+			if len(fields) >= 3 {
+				if ppm, err := strconv.ParseFloat(fields[0], 64); err == nil {
+					if fields[2] == "slow" {
+						ppm = -ppm
+					}
+					timeSync.DriftPPM = ppm
+				}
+			}
+		case "Leap status":
+			timeSync.Synchronized = value != "Not synchronised"
+		}
+	}
+
+	return nil
+}
+
+// collectTimeSyncTimedatectl reads synchronization status from systemd's timedatectl
+func (collector *SystemInfoCollector) collectTimeSyncTimedatectl(timeSync *TimeSyncInfo) error {
+	output, err := exec.Command("timedatectl", "show").Output()
+	if err != nil {
+		return fmt.Errorf("failed to run timedatectl: %w", err)
+	}
+
+	timeSync.Source = "timedatectl"
+
+	for _, line := range strings.Split(string(output), "\n") {
+		key, value, ok := splitKeyValue(line, "=")
+		if !ok {
+			continue
+		}
+
+		switch key {
+		case "NTP":
+			timeSync.NTPEnabled = value == "yes"
+		case "NTPSynchronized":
+			timeSync.Synchronized = value == "yes"
+		}
+	}
+
+	// Offset and drift are only available when systemd-timesyncd is in use
+	output, err = exec.Command("timedatectl", "timesync-status").Output()
+	if err != nil {
+		return nil
+	}
+
+	for _, line := range strings.Split(string(output), "\n") {
+		key, value, ok := splitKeyValue(line, ":")
+		if !ok {
+			continue
+		}
+
+		switch key {
+		case "Server":
+			timeSync.ReferenceID = value
+		case "Stratum":
+			timeSync.Stratum, _ = strconv.Atoi(value)
+		case "Offset":
+			// Format: "+1.234ms"
+			if offset, err := time.ParseDuration(value); err == nil {
+				timeSync.Offset = offset
+			}
+		case "Frequency":
+			// Format: "+12.345ppm"
+			if ppm, err := strconv.ParseFloat(strings.TrimSuffix(value, "ppm"), 64); err == nil {
+				timeSync.DriftPPM = ppm
+			}
+		}
+	}
+
+	return nil
+}
+
+// collectTimeSyncW32tm reads synchronization status from the Windows Time service
+func (collector *SystemInfoCollector) collectTimeSyncW32tm(timeSync *TimeSyncInfo) error {
+	output, err := exec.Command("w32tm", "/query", "/status", "/verbose").Output()
+	if err != nil {
+		return fmt.Errorf("failed to run w32tm: %w", err)
+	}
+
+	timeSync.Source = "w32tm"
+	timeSync.NTPEnabled = true
+
+	for _, line := range strings.Split(string(output), "\n") {
+		key, value, ok := splitKeyValue(line, ":")
+		if !ok {
+			continue
+		}
+
+		switch key {
+		case "Leap Indicator":
+			// Leap indicator 3 means the clock is not synchronized
+			timeSync.Synchronized = !strings.HasPrefix(value, "3")
+		case "Stratum":
+			if fields := strings.Fields(value); len(fields) > 0 {
+				timeSync.Stratum, _ = strconv.Atoi(fields[0])
+			}
+		case "Source":
+			timeSync.ReferenceID = strings.Split(value, ",")[0]
+			if strings.Contains(value, "Local CMOS Clock") || strings.Contains(value, "Free-running") {
+				timeSync.NTPEnabled = false
+				timeSync.Synchronized = false
+			}
+		case "Phase Offset":
+			// Format: "0.0012345s"
+			if offset, err := time.ParseDuration(value); err == nil {
+				timeSync.Offset = offset
+			}
+		}
+	}
+
+	return nil
+}
+
+// splitKeyValue splits a "key<sep>value" line and trims both parts
+func splitKeyValue(line, sep string) (string, string, bool) {
+	index := strings.Index(line, sep)
+	if index < 0 {
+		return "", "", false
+	}
+	return strings.TrimSpace(line[:index]), strings.TrimSpace(line[index+len(sep):]), true
+}
+
 // GetCPUUsage returns current CPU usage percentage
 // This method provides real-time CPU usage information
 func (collector *SystemInfoCollector) GetCPUUsage() (float64, error) {
@@ -2,6 +2,7 @@ package systeminfo
 
 import (
 	"fmt"
+	"github.com/ahmadreza-log/simple-monitor/terminal"
 	"strings"
 	"time"
 )
@@ -13,6 +14,7 @@ type SystemInfoDisplayer struct {
 	ShowDetailedInfo bool   // Whether to show detailed information
 	UseColors        bool   // Whether to use colored output
 	DateFormat       string // Date format for timestamps
+	AutoWidth        bool   // Whether to fit separators to the terminal width
 }
 
 // NewSystemInfoDisplayer creates a new instance of SystemInfoDisplayer
@@ -22,15 +24,16 @@ func NewSystemInfoDisplayer() *SystemInfoDisplayer {
 		ShowDetailedInfo: true,
 		UseColors:        true,
 		DateFormat:       "2006-01-02 15:04:05",
+		AutoWidth:        true,
 	}
 }
 
 // DisplaySystemInfo displays comprehensive system information in a formatted way
 // This is the main method that formats and displays all system information
 func (displayer *SystemInfoDisplayer) DisplaySystemInfo(systemInfo *SystemInfo) {
-	fmt.Println(strings.Repeat("=", 80))
+	fmt.Println(displayer.rule("="))
 	fmt.Println("                    🖥️  SYSTEM INFORMATION")
-	fmt.Println(strings.Repeat("=", 80))
+	fmt.Println(displayer.rule("="))
 
 	// Display basic system information
 	displayer.displayBasicInfo(systemInfo)
@@ -50,15 +53,35 @@ func (displayer *SystemInfoDisplayer) DisplaySystemInfo(systemInfo *SystemInfo)
 	// Display performance metrics
 	displayer.displayPerformanceMetrics(systemInfo)
 
-	fmt.Println(strings.Repeat("=", 80))
+	// Display time synchronization status
+	if systemInfo.TimeSync.Source != "" || systemInfo.TimeSync.Error != "" {
+		displayer.displayTimeSyncInfo(&systemInfo.TimeSync)
+	}
+
+	// Display OS patch level and pending updates
+	if !systemInfo.PatchLevel.CheckedAt.IsZero() {
+		displayer.displayPatchLevel(&systemInfo.PatchLevel)
+	}
+
+	// Display kernel entropy
+	if !systemInfo.Entropy.CheckedAt.IsZero() {
+		displayer.displayEntropy(&systemInfo.Entropy)
+	}
+
+	// Display PCI devices and drivers
+	if len(systemInfo.PCIDevices) > 0 || systemInfo.PCIError != "" {
+		displayer.displayPCIDevices(systemInfo.PCIDevices, systemInfo.PCIError)
+	}
+
+	fmt.Println(displayer.rule("="))
 	fmt.Printf("📅 Last Updated: %s\n", systemInfo.Timestamp.Format(displayer.DateFormat))
-	fmt.Println(strings.Repeat("=", 80))
+	fmt.Println(displayer.rule("="))
 }
 
 // displayBasicInfo displays basic system identification information
 func (displayer *SystemInfoDisplayer) displayBasicInfo(systemInfo *SystemInfo) {
 	fmt.Println("\n🔧 BASIC SYSTEM INFORMATION")
-	fmt.Println(strings.Repeat("-", 50))
+	fmt.Println(displayer.sectionRule())
 
 	fmt.Printf("Hostname:        %s\n", displayer.formatValue(systemInfo.HostName, "Unknown"))
 	fmt.Printf("Operating System: %s\n", displayer.formatValue(systemInfo.OperatingSystem, "Unknown"))
@@ -79,7 +102,7 @@ func (displayer *SystemInfoDisplayer) displayBasicInfo(systemInfo *SystemInfo) {
 // displayCPUInfo displays detailed CPU information and usage statistics
 func (displayer *SystemInfoDisplayer) displayCPUInfo(cpuInfo *CPUInfo) {
 	fmt.Println("\n🖥️  CPU INFORMATION")
-	fmt.Println(strings.Repeat("-", 50))
+	fmt.Println(displayer.sectionRule())
 
 	fmt.Printf("Model:           %s\n", displayer.formatValue(cpuInfo.ModelName, "Unknown"))
 	fmt.Printf("Vendor:          %s\n", displayer.formatValue(cpuInfo.VendorID, "Unknown"))
@@ -105,7 +128,7 @@ func (displayer *SystemInfoDisplayer) displayCPUInfo(cpuInfo *CPUInfo) {
 // displayMemoryInfo displays memory usage and statistics
 func (displayer *SystemInfoDisplayer) displayMemoryInfo(memoryInfo *MemoryInfo) {
 	fmt.Println("\n💾 MEMORY INFORMATION")
-	fmt.Println(strings.Repeat("-", 50))
+	fmt.Println(displayer.sectionRule())
 
 	// Display physical memory
 	fmt.Printf("Total Memory:    %s\n", displayer.formatBytes(memoryInfo.TotalMemory))
@@ -137,13 +160,13 @@ func (displayer *SystemInfoDisplayer) displayMemoryInfo(memoryInfo *MemoryInfo)
 func (displayer *SystemInfoDisplayer) displayDiskInfo(diskInfo []DiskInfo) {
 	if len(diskInfo) == 0 {
 		fmt.Println("\n💿 DISK INFORMATION")
-		fmt.Println(strings.Repeat("-", 50))
+		fmt.Println(displayer.sectionRule())
 		fmt.Println("No disk information available")
 		return
 	}
 
 	fmt.Println("\n💿 DISK INFORMATION")
-	fmt.Println(strings.Repeat("-", 50))
+	fmt.Println(displayer.sectionRule())
 
 	for i, disk := range diskInfo {
 		fmt.Printf("\n📀 Disk %d: %s\n", i+1, disk.DeviceName)
@@ -179,13 +202,13 @@ func (displayer *SystemInfoDisplayer) displayDiskInfo(diskInfo []DiskInfo) {
 func (displayer *SystemInfoDisplayer) displayNetworkInfo(networkInfo []NetworkInfo) {
 	if len(networkInfo) == 0 {
 		fmt.Println("\n🌐 NETWORK INFORMATION")
-		fmt.Println(strings.Repeat("-", 50))
+		fmt.Println(displayer.sectionRule())
 		fmt.Println("No network information available")
 		return
 	}
 
 	fmt.Println("\n🌐 NETWORK INFORMATION")
-	fmt.Println(strings.Repeat("-", 50))
+	fmt.Println(displayer.sectionRule())
 
 	for i, network := range networkInfo {
 		fmt.Printf("\n🔌 Interface %d: %s\n", i+1, network.InterfaceName)
@@ -220,7 +243,7 @@ func (displayer *SystemInfoDisplayer) displayNetworkInfo(networkInfo []NetworkIn
 // displayPerformanceMetrics displays system performance metrics
 func (displayer *SystemInfoDisplayer) displayPerformanceMetrics(systemInfo *SystemInfo) {
 	fmt.Println("\n📈 PERFORMANCE METRICS")
-	fmt.Println(strings.Repeat("-", 50))
+	fmt.Println(displayer.sectionRule())
 
 	// Display load average
 	if systemInfo.LoadAverage.Load1Minute > 0 ||
@@ -238,6 +261,135 @@ func (displayer *SystemInfoDisplayer) displayPerformanceMetrics(systemInfo *Syst
 	}
 }
 
+// displayTimeSyncInfo displays clock synchronization status and drift
+func (displayer *SystemInfoDisplayer) displayTimeSyncInfo(timeSync *TimeSyncInfo) {
+	fmt.Println("\n🕒 TIME SYNCHRONIZATION")
+	fmt.Println(displayer.sectionRule())
+
+	if timeSync.Error != "" {
+		fmt.Printf("Status:          Unknown (%s)\n", timeSync.Error)
+		return
+	}
+
+	fmt.Printf("Source:          %s\n", timeSync.Source)
+	fmt.Printf("NTP Enabled:     %s\n", displayer.formatYesNo(timeSync.NTPEnabled))
+	fmt.Printf("Synchronized:    %s\n", displayer.formatYesNo(timeSync.Synchronized))
+
+	if timeSync.ReferenceID != "" {
+		fmt.Printf("Time Server:     %s\n", timeSync.ReferenceID)
+	}
+	if timeSync.Stratum > 0 {
+		fmt.Printf("Stratum:         %d\n", timeSync.Stratum)
+	}
+
+	fmt.Printf("Clock Offset:    %s\n", timeSync.Offset)
+	if timeSync.DriftPPM != 0 {
+		fmt.Printf("Clock Drift:     %+.3f ppm\n", timeSync.DriftPPM)
+	}
+
+	// Clock skew breaks TLS validation and log correlation, so call it out
+	if timeSync.OffsetExceeded {
+		fmt.Println("⚠️  Clock offset exceeds threshold - TLS and log timestamps may be affected")
+	} else if !timeSync.Synchronized {
+		fmt.Println("⚠️  System clock is not synchronized")
+	}
+}
+
+// displayPatchLevel displays the OS build, pending updates and time since the last update
+func (displayer *SystemInfoDisplayer) displayPatchLevel(patchLevel *PatchLevelInfo) {
+	fmt.Println("\n🩹 PATCH LEVEL")
+	fmt.Println(displayer.sectionRule())
+
+	fmt.Printf("OS Version:      %s\n", displayer.formatValue(patchLevel.OSName, "Unknown"))
+	if patchLevel.OSBuild != "" {
+		fmt.Printf("OS Build:        %s\n", patchLevel.OSBuild)
+	}
+	fmt.Printf("Kernel:          %s\n", displayer.formatValue(patchLevel.KernelVersion, "Unknown"))
+
+	if patchLevel.Error != "" {
+		fmt.Printf("Pending Updates: Unknown (%s)\n", patchLevel.Error)
+	} else {
+		pending := fmt.Sprintf("%d (%s)", patchLevel.PendingUpdates, patchLevel.PackageManager)
+		if patchLevel.SecurityUpdates >= 0 {
+			pending = fmt.Sprintf("%d, %d security (%s)", patchLevel.PendingUpdates, patchLevel.SecurityUpdates, patchLevel.PackageManager)
+		}
+		fmt.Printf("Pending Updates: %s\n", pending)
+	}
+
+	if patchLevel.LastUpdate.IsZero() {
+		fmt.Println("Last Update:     Unknown")
+	} else {
+		fmt.Printf("Last Update:     %s (%s ago)\n", patchLevel.LastUpdate.Format(displayer.DateFormat),
+			displayer.formatDuration(patchLevel.SinceLastUpdate))
+	}
+
+	if patchLevel.Stale {
+		fmt.Printf("⚠️  System may be missing patches - %s\n", patchLevel.StaleReason)
+	}
+}
+
+// displayEntropy displays the entropy available to the kernel random subsystem
+func (displayer *SystemInfoDisplayer) displayEntropy(entropy *EntropyInfo) {
+	fmt.Println("\n🎲 ENTROPY")
+	fmt.Println(displayer.sectionRule())
+
+	if entropy.Error != "" {
+		fmt.Printf("Available:       Unknown (%s)\n", entropy.Error)
+		return
+	}
+
+	if entropy.PoolSize > 0 {
+		fmt.Printf("Available:       %d of %d bits\n", entropy.Available, entropy.PoolSize)
+	} else {
+		fmt.Printf("Available:       %d bits\n", entropy.Available)
+	}
+
+	// A starved pool stalls /dev/random readers and TLS handshakes on older kernels
+	if entropy.Prolonged {
+		fmt.Printf("⚠️  Entropy below %d bits for %s - TLS handshakes may stall on older kernels\n",
+			entropy.LowThreshold, displayer.formatDuration(entropy.LowFor))
+	} else if entropy.Low {
+		fmt.Printf("Low entropy for %s (below %d bits)\n", displayer.formatDuration(entropy.LowFor), entropy.LowThreshold)
+	}
+}
+
+// displayPCIDevices lists GPUs, NICs and storage controllers with their drivers
+// Bridges and other platform devices are only counted unless ShowDetailedInfo is set; exports always carry the full list
+func (displayer *SystemInfoDisplayer) displayPCIDevices(devices []PCIDeviceInfo, pciError string) {
+	fmt.Println("\n🧩 PCI DEVICES")
+	fmt.Println(displayer.sectionRule())
+
+	if pciError != "" {
+		fmt.Printf("Status:          Unknown (%s)\n", pciError)
+		return
+	}
+
+	hidden := 0
+	for _, device := range devices {
+		if !device.IsKey() && !displayer.ShowDetailedInfo {
+			hidden++
+			continue
+		}
+		fmt.Printf("%-12s %s %s (%s)\n", device.Category+":", device.Vendor, device.Name(), device.Address)
+		driver := displayer.formatValue(device.Driver, "none")
+		if device.DriverVersion != "" {
+			driver += " " + device.DriverVersion
+		}
+		fmt.Printf("             Driver: %s\n", driver)
+	}
+	if hidden > 0 {
+		fmt.Printf("+ %d bridges and other platform devices (included in exports)\n", hidden)
+	}
+}
+
+// formatYesNo formats a boolean as Yes/No
+func (displayer *SystemInfoDisplayer) formatYesNo(value bool) string {
+	if value {
+		return "Yes"
+	}
+	return "No"
+}
+
 // formatValue formats a string value with a default fallback
 func (displayer *SystemInfoDisplayer) formatValue(value, defaultValue string) string {
 	if value == "" {
@@ -284,3 +436,21 @@ func (displayer *SystemInfoDisplayer) formatDuration(duration time.Duration) str
 		return fmt.Sprintf("%d seconds", seconds)
 	}
 }
+
+// lineWidth returns the layout width: the terminal width, or 80 columns when AutoWidth is off
+func (displayer *SystemInfoDisplayer) lineWidth() int {
+	if !displayer.AutoWidth {
+		return terminal.DefaultWidth
+	}
+	return terminal.Fit(terminal.Width(), 40, 240)
+}
+
+// rule returns a separator line across the layout width
+func (displayer *SystemInfoDisplayer) rule(char string) string {
+	return strings.Repeat(char, displayer.lineWidth())
+}
+
+// sectionRule returns a section underline, shortened on narrow terminals
+func (displayer *SystemInfoDisplayer) sectionRule() string {
+	return strings.Repeat("-", terminal.Fit(displayer.lineWidth(), 0, 50))
+}
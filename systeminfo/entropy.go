@@ -0,0 +1,60 @@
+package systeminfo
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// randomProcDir holds the kernel random subsystem counters
+var randomProcDir = "/proc/sys/kernel/random"
+
+// collectEntropy reports the entropy available to the kernel random subsystem (Linux only)
+// A low reading is only flagged once it has lasted EntropyLowAfter, since the pool refills quickly
+// after short bursts; read errors are recorded in EntropyInfo.Error
+func (collector *SystemInfoCollector) collectEntropy(systemInfo *SystemInfo) {
+	if runtime.GOOS != "linux" {
+		return
+	}
+
+	entropy := &systemInfo.Entropy
+	entropy.CheckedAt = systemInfo.Timestamp
+	entropy.LowThreshold = collector.EntropyLowThreshold
+
+	available, err := readRandomCounter("entropy_avail")
+	if err != nil {
+		entropy.Error = fmt.Sprintf("failed to read available entropy: %v", err)
+		return
+	}
+	entropy.Available = available
+
+	// The pool size is informational; kernels since 5.18 report a fixed 256-bit pool
+	if poolSize, err := readRandomCounter("poolsize"); err == nil {
+		entropy.PoolSize = poolSize
+	}
+
+	if available >= collector.EntropyLowThreshold {
+		collector.entropyLowSince = time.Time{}
+		return
+	}
+
+	entropy.Low = true
+	if collector.entropyLowSince.IsZero() {
+		collector.entropyLowSince = systemInfo.Timestamp
+	}
+	entropy.LowSince = collector.entropyLowSince
+	entropy.LowFor = systemInfo.Timestamp.Sub(collector.entropyLowSince)
+	entropy.Prolonged = entropy.LowFor >= collector.EntropyLowAfter
+}
+
+// readRandomCounter reads one integer counter from /proc/sys/kernel/random
+func readRandomCounter(name string) (int, error) {
+	content, err := os.ReadFile(randomProcDir + "/" + name)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(content)))
+}
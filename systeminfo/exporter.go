@@ -3,6 +3,7 @@ package systeminfo
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/ahmadreza-log/simple-monitor/redact"
 	"os"
 	"path/filepath"
 	"time"
@@ -32,6 +33,10 @@ func NewSystemInfoExporter() *SystemInfoExporter {
 // ExportToJSON exports system information to a JSON file
 // The file will be saved with a timestamp-based filename in the appropriate subdirectory
 func (exporter *SystemInfoExporter) ExportToJSON(systemInfo *SystemInfo, moduleName string) (string, error) {
+	if err := redact.Copy(&systemInfo); err != nil {
+		return "", err
+	}
+
 	// Generate filename with current date
 	fileName := exporter.generateFileName(moduleName, "json")
 
@@ -0,0 +1,221 @@
+package systeminfo
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/host"
+)
+
+// aptHistoryLog records every apt run with its start date and the packages it upgraded
+var aptHistoryLog = "/var/log/apt/history.log"
+
+// patchCommandTimeout bounds each package manager query; they only read local metadata but can be slow
+const patchCommandTimeout = 60 * time.Second
+
+// wingetUpgradeCount matches the summary line of winget upgrade ("5 upgrades available.")
+var wingetUpgradeCount = regexp.MustCompile(`(\d+) upgrades? available`)
+
+// collectPatchLevel reports the OS build, kernel, pending package updates and time since the last update
+// Package managers are only queried every PatchCheckInterval; failures are recorded in PatchLevelInfo.Error
+func (collector *SystemInfoCollector) collectPatchLevel(systemInfo *SystemInfo) {
+	if collector.patchCache == nil || time.Since(collector.patchCache.CheckedAt) >= collector.PatchCheckInterval {
+		info := readPatchLevel()
+		collector.patchCache = &info
+	}
+
+	patchLevel := *collector.patchCache
+	if !patchLevel.LastUpdate.IsZero() {
+		patchLevel.SinceLastUpdate = systemInfo.Timestamp.Sub(patchLevel.LastUpdate)
+	}
+
+	// A system is stale when security fixes are waiting or nothing has been installed for too long
+	switch {
+	case patchLevel.SecurityUpdates > 0:
+		patchLevel.Stale = true
+		patchLevel.StaleReason = fmt.Sprintf("%d security updates pending", patchLevel.SecurityUpdates)
+	case collector.PatchStaleAfter > 0 && patchLevel.SinceLastUpdate > collector.PatchStaleAfter:
+		patchLevel.Stale = true
+		patchLevel.StaleReason = fmt.Sprintf("no updates installed for %d days", int(patchLevel.SinceLastUpdate.Hours()/24))
+	}
+	systemInfo.PatchLevel = patchLevel
+}
+
+// readPatchLevel runs the platform checks once
+func readPatchLevel() PatchLevelInfo {
+	info := PatchLevelInfo{CheckedAt: time.Now(), PendingUpdates: -1, SecurityUpdates: -1}
+
+	if hostInfo, err := host.Info(); err == nil {
+		info.OSName = strings.TrimSpace(hostInfo.Platform + " " + hostInfo.PlatformVersion)
+		info.OSFamily = hostInfo.PlatformFamily
+		info.KernelVersion = hostInfo.KernelVersion
+	}
+
+	var err error
+	switch runtime.GOOS {
+	case "linux":
+		err = readLinuxPatchLevel(&info)
+	case "windows":
+		err = readWindowsPatchLevel(&info)
+	default:
+		err = errors.New("pending update check is only available on Linux (apt, dnf) and Windows (winget)")
+	}
+	if err != nil {
+		info.Error = err.Error()
+	}
+	return info
+}
+
+// readLinuxPatchLevel counts pending updates with apt or dnf, reading only the local package metadata
+func readLinuxPatchLevel(info *PatchLevelInfo) error {
+	if _, err := exec.LookPath("apt"); err == nil {
+		info.PackageManager = "apt"
+		info.LastUpdate = lastAptUpgrade(aptHistoryLog)
+		return countAptUpdates(info)
+	}
+	if _, err := exec.LookPath("dnf"); err == nil {
+		info.PackageManager = "dnf"
+		info.LastUpdate = lastRPMInstall()
+		return countDnfUpdates(info)
+	}
+	return errors.New("no supported package manager found (apt, dnf)")
+}
+
+// countAptUpdates parses apt list --upgradable; packages from a -security pocket count as security updates
+// The package lists are as fresh as the last apt update, which this monitor does not run
+func countAptUpdates(info *PatchLevelInfo) error {
+	output, err := runPatchCommand("apt", "list", "--upgradable")
+	if err != nil {
+		return fmt.Errorf("failed to list apt updates: %w", err)
+	}
+	info.PendingUpdates, info.SecurityUpdates = 0, 0
+	for _, line := range strings.Split(output, "\n") {
+		if !strings.Contains(line, "[upgradable from") {
+			continue
+		}
+		info.PendingUpdates++
+		if source, _, found := strings.Cut(line, " "); found && strings.Contains(source, "-security") {
+			info.SecurityUpdates++
+		}
+	}
+	return nil
+}
+
+// countDnfUpdates parses dnf check-update from the metadata cache (exit status 100 means updates are available)
+func countDnfUpdates(info *PatchLevelInfo) error {
+	output, err := runPatchCommand("dnf", "check-update", "-q", "--cacheonly")
+	var exitErr *exec.ExitError
+	if err != nil && !(errors.As(err, &exitErr) && exitErr.ExitCode() == 100) {
+		return fmt.Errorf("failed to check dnf updates: %w", err)
+	}
+	info.PendingUpdates = 0
+	for _, line := range strings.Split(output, "\n") {
+		// Packages replaced by others are listed after this header and are already counted above it
+		if strings.HasPrefix(line, "Obsoleting") {
+			break
+		}
+		if len(strings.Fields(line)) == 3 {
+			info.PendingUpdates++
+		}
+	}
+
+	// Advisories are optional metadata; without them the security count stays unknown
+	if output, err := runPatchCommand("dnf", "updateinfo", "list", "--security", "-q", "--cacheonly"); err == nil {
+		info.SecurityUpdates = 0
+		for _, line := range strings.Split(output, "\n") {
+			if len(strings.Fields(line)) >= 3 {
+				info.SecurityUpdates++
+			}
+		}
+	}
+	return nil
+}
+
+// lastAptUpgrade returns the start of the most recent apt run that upgraded packages
+func lastAptUpgrade(path string) time.Time {
+	file, err := os.Open(path)
+	if err != nil {
+		return time.Time{}
+	}
+	defer file.Close()
+
+	// Each run is a block starting with "Start-Date: 2024-05-01  10:11:12"
+	var last, start time.Time
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if value, found := strings.CutPrefix(line, "Start-Date: "); found {
+			start, _ = time.ParseInLocation("2006-01-02 15:04:05", strings.Join(strings.Fields(value), " "), time.Local)
+		} else if strings.HasPrefix(line, "Upgrade: ") && start.After(last) {
+			last = start
+		}
+	}
+	return last
+}
+
+// lastRPMInstall returns when the most recently installed or updated package was installed
+func lastRPMInstall() time.Time {
+	output, err := runPatchCommand("rpm", "-qa", "--queryformat", "%{INSTALLTIME}\n")
+	if err != nil {
+		return time.Time{}
+	}
+	var latest int64
+	for _, line := range strings.Fields(output) {
+		if seconds, err := strconv.ParseInt(line, 10, 64); err == nil && seconds > latest {
+			latest = seconds
+		}
+	}
+	if latest == 0 {
+		return time.Time{}
+	}
+	return time.Unix(latest, 0)
+}
+
+// readWindowsPatchLevel reads the OS build, the newest installed hotfix and winget's pending upgrades
+// Windows Update itself is not queried; winget covers the installed applications
+func readWindowsPatchLevel(info *PatchLevelInfo) error {
+	script := `$os = Get-CimInstance Win32_OperatingSystem; $hotfix = Get-HotFix | Where-Object InstalledOn | ` +
+		`Sort-Object InstalledOn -Descending | Select-Object -First 1; ` +
+		`"$($os.BuildNumber)|$(if ($hotfix) { $hotfix.InstalledOn.ToString('yyyy-MM-dd') })"`
+	output, err := runPatchCommand("powershell", "-NoProfile", "-NonInteractive", "-Command", script)
+	if err != nil {
+		return fmt.Errorf("failed to query Windows build: %w", err)
+	}
+	build, installed, _ := strings.Cut(strings.TrimSpace(output), "|")
+	info.OSBuild = build
+	if installed != "" {
+		info.LastUpdate, _ = time.ParseInLocation("2006-01-02", installed, time.Local)
+	}
+
+	if _, err := exec.LookPath("winget"); err != nil {
+		return errors.New("winget not found; pending application updates are unknown")
+	}
+	info.PackageManager = "winget"
+	output, err = runPatchCommand("winget", "upgrade", "--accept-source-agreements", "--disable-interactivity")
+	if err != nil && output == "" {
+		return fmt.Errorf("failed to list winget upgrades: %w", err)
+	}
+	info.PendingUpdates = 0
+	if match := wingetUpgradeCount.FindStringSubmatch(output); match != nil {
+		info.PendingUpdates, _ = strconv.Atoi(match[1])
+	}
+	return nil
+}
+
+// runPatchCommand runs a package manager query with a timeout and returns its standard output
+func runPatchCommand(name string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), patchCommandTimeout)
+	defer cancel()
+	output, err := exec.CommandContext(ctx, name, args...).Output()
+	return string(output), err
+}
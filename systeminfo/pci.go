@@ -0,0 +1,287 @@
+package systeminfo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// pciDevicesDir is where Linux lists PCI devices, one directory per bus address
+var pciDevicesDir = "/sys/bus/pci/devices"
+
+// pciWindowsID extracts the vendor and device IDs from a Windows PCI instance ID (PCI\VEN_10DE&DEV_2484&...)
+var pciWindowsID = regexp.MustCompile(`(?i)VEN_([0-9A-F]{4})&DEV_([0-9A-F]{4})`)
+
+// pciIDSuffix matches the numeric ID lspci -nn appends to names ("Intel Corporation [8086]")
+var pciIDSuffix = regexp.MustCompile(`\s*\[[0-9a-fA-F]{4}\]$`)
+
+// pciCategories names the PCI base classes (the high byte of the class code)
+var pciCategories = map[int64]string{
+	0x00: "Unclassified",
+	0x01: "Storage",
+	0x02: "Network",
+	0x03: "Display",
+	0x04: "Multimedia",
+	0x05: "Memory",
+	0x06: "Bridge",
+	0x07: "Communication",
+	0x08: "System",
+	0x09: "Input",
+	0x0a: "Docking",
+	0x0b: "Processor",
+	0x0c: "Serial Bus",
+	0x0d: "Wireless",
+	0x0e: "Intelligent I/O",
+	0x0f: "Satellite",
+	0x10: "Encryption",
+	0x11: "Signal Processing",
+	0x12: "Accelerator",
+	0x13: "Instrumentation",
+}
+
+// pciWindowsCategories maps Windows device setup classes to the PCI categories used on Linux
+var pciWindowsCategories = map[string]string{
+	"DISPLAY":     "Display",
+	"NET":         "Network",
+	"SCSIADAPTER": "Storage",
+	"HDC":         "Storage",
+	"MEDIA":       "Multimedia",
+	"USB":         "Serial Bus",
+	"SYSTEM":      "System",
+	"BLUETOOTH":   "Wireless",
+}
+
+// pciVendors names common vendors when lspci (and its ID database) is not installed
+var pciVendors = map[string]string{
+	"1002": "AMD/ATI",
+	"1022": "AMD",
+	"10de": "NVIDIA",
+	"10ec": "Realtek",
+	"1344": "Micron",
+	"1414": "Microsoft",
+	"144d": "Samsung",
+	"14e4": "Broadcom",
+	"15ad": "VMware",
+	"15b3": "Mellanox",
+	"15b7": "Western Digital",
+	"17cb": "Qualcomm",
+	"1987": "Phison",
+	"1af4": "Red Hat (virtio)",
+	"1b36": "Red Hat (QEMU)",
+	"1b4b": "Marvell",
+	"1c5c": "SK hynix",
+	"2646": "Kingston",
+	"8086": "Intel",
+}
+
+// pciKeyCategories are the categories most often behind performance problems; the rest are summarized
+var pciKeyCategories = map[string]bool{
+	"Display":     true,
+	"Network":     true,
+	"Storage":     true,
+	"Wireless":    true,
+	"Accelerator": true,
+}
+
+// collectPCIDevices lists PCI/PCIe devices with the driver bound to each
+// Failures are recorded in SystemInfo.PCIError, as the inventory is optional
+func (collector *SystemInfoCollector) collectPCIDevices(systemInfo *SystemInfo) {
+	devices, err := ReadPCIDevices()
+	if err != nil {
+		systemInfo.PCIError = err.Error()
+		return
+	}
+	systemInfo.PCIDevices = devices
+}
+
+// ReadPCIDevices lists the PCI devices sorted by address
+// Linux reads sysfs (with names from lspci when installed); Windows queries the signed PCI drivers through PowerShell
+func ReadPCIDevices() ([]PCIDeviceInfo, error) {
+	var devices []PCIDeviceInfo
+	var err error
+	switch runtime.GOOS {
+	case "linux":
+		devices, err = readSysfsPCIDevices(pciDevicesDir)
+		if err == nil {
+			applyLspciNames(devices)
+		}
+	case "windows":
+		devices, err = readWindowsPCIDevices()
+	default:
+		return nil, errors.New("PCI device inventory is only available on Linux and Windows")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(devices, func(i, j int) bool { return devices[i].Address < devices[j].Address })
+	return devices, nil
+}
+
+// readSysfsPCIDevices reads class, IDs and bound driver of each device directory
+func readSysfsPCIDevices(dir string) ([]PCIDeviceInfo, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		// No PCI bus (e.g. some ARM boards and containers)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+
+	kernel := readPCIValue("/proc/sys/kernel", "osrelease")
+	var devices []PCIDeviceInfo
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		device := PCIDeviceInfo{
+			Address:  entry.Name(),
+			VendorID: strings.TrimPrefix(readPCIValue(path, "vendor"), "0x"),
+			DeviceID: strings.TrimPrefix(readPCIValue(path, "device"), "0x"),
+		}
+		if class, err := strconv.ParseInt(strings.TrimPrefix(readPCIValue(path, "class"), "0x"), 16, 64); err == nil {
+			device.Category = pciCategories[class>>16]
+			device.Class = device.Category
+		}
+		if device.Category == "" {
+			device.Category = "Other"
+		}
+		device.Vendor = pciVendors[device.VendorID]
+
+		// The driver link points at /sys/bus/pci/drivers/<name>; modules publish a version only for out-of-tree builds
+		if driver, err := os.Readlink(filepath.Join(path, "driver")); err == nil {
+			device.Driver = filepath.Base(driver)
+			device.DriverVersion = readPCIValue(filepath.Join(path, "driver", "module"), "version")
+			if device.DriverVersion == "" && kernel != "" {
+				device.DriverVersion = "kernel " + kernel
+			}
+		}
+		devices = append(devices, device)
+	}
+	return devices, nil
+}
+
+// readPCIValue reads one attribute file, returning "" when it is missing
+func readPCIValue(dir, name string) string {
+	content, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(content))
+}
+
+// applyLspciNames fills in class, vendor and device names from lspci -vmm when it is installed
+// sysfs only carries numeric IDs; the names come from the pci.ids database lspci ships with
+func applyLspciNames(devices []PCIDeviceInfo) {
+	if _, err := exec.LookPath("lspci"); err != nil {
+		return
+	}
+	output, err := exec.Command("lspci", "-vmm", "-nn", "-D").Output()
+	if err != nil {
+		return
+	}
+
+	byAddress := make(map[string]*PCIDeviceInfo, len(devices))
+	for i := range devices {
+		byAddress[devices[i].Address] = &devices[i]
+	}
+
+	// Records are blank-line separated "Key:\tValue" blocks starting with Slot
+	var device *PCIDeviceInfo
+	for _, line := range strings.Split(string(output), "\n") {
+		key, value, found := strings.Cut(line, ":\t")
+		if !found {
+			continue
+		}
+		value = pciIDSuffix.ReplaceAllString(strings.TrimSpace(value), "")
+		switch key {
+		case "Slot":
+			device = byAddress[value]
+		case "Class":
+			if device != nil {
+				device.Class = value
+			}
+		case "Vendor":
+			if device != nil {
+				device.Vendor = value
+			}
+		case "Device":
+			if device != nil {
+				device.Device = value
+			}
+		}
+	}
+}
+
+// readWindowsPCIDevices lists PCI devices from Win32_PnPSignedDriver, which carries the driver version
+// Windows does not expose bus addresses there, so the device instance ID is used instead
+func readWindowsPCIDevices() ([]PCIDeviceInfo, error) {
+	script := `Get-CimInstance Win32_PnPSignedDriver | Where-Object { $_.DeviceID -like 'PCI\*' } | ` +
+		`Select-Object DeviceID,DeviceName,Manufacturer,DeviceClass,InfName,DriverVersion | ConvertTo-Json -Compress`
+	output, err := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", script).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query PCI devices: %w", err)
+	}
+
+	var entries []struct {
+		DeviceID      string
+		DeviceName    string
+		Manufacturer  string
+		DeviceClass   string
+		InfName       string
+		DriverVersion string
+	}
+	text := strings.TrimSpace(string(output))
+	if text == "" {
+		return nil, nil
+	}
+	// A single device is returned as an object rather than an array
+	if strings.HasPrefix(text, "{") {
+		text = "[" + text + "]"
+	}
+	if err := json.Unmarshal([]byte(text), &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse PCI devices: %w", err)
+	}
+
+	var devices []PCIDeviceInfo
+	for _, entry := range entries {
+		device := PCIDeviceInfo{
+			Address:       entry.DeviceID,
+			Category:      pciWindowsCategories[strings.ToUpper(entry.DeviceClass)],
+			Class:         entry.DeviceClass,
+			Vendor:        entry.Manufacturer,
+			Device:        entry.DeviceName,
+			Driver:        entry.InfName,
+			DriverVersion: entry.DriverVersion,
+		}
+		if device.Category == "" {
+			device.Category = "Other"
+		}
+		if match := pciWindowsID.FindStringSubmatch(entry.DeviceID); match != nil {
+			device.VendorID = strings.ToLower(match[1])
+			device.DeviceID = strings.ToLower(match[2])
+		}
+		devices = append(devices, device)
+	}
+	return devices, nil
+}
+
+// IsKey reports whether the device is a GPU, NIC, storage controller or accelerator
+func (device PCIDeviceInfo) IsKey() bool {
+	return pciKeyCategories[device.Category]
+}
+
+// Name returns the device name, falling back to its vendor:device IDs
+func (device PCIDeviceInfo) Name() string {
+	if device.Device != "" {
+		return device.Device
+	}
+	return fmt.Sprintf("[%s:%s]", device.VendorID, device.DeviceID)
+}
@@ -1,7 +1,9 @@
+// Package systeminfo gathers static system information: OS, hardware, PCI devices, patch level and entropy
 package systeminfo
 
 import (
 	"fmt"
+	"github.com/ahmadreza-log/simple-monitor/hooks"
 	"time"
 )
 
@@ -44,6 +46,7 @@ func (manager *SystemInfoManager) ShowSystemInfo() error {
 		return fmt.Errorf("failed to export system information: %w", err)
 	}
 
+	hooks.Exported("system", filePath)
 	fmt.Printf("\n💾 System information saved to: %s\n", filePath)
 
 	return nil
@@ -130,6 +133,22 @@ func (manager *SystemInfoManager) ShowNetworkInfo() error {
 	return nil
 }
 
+// ShowTimeSyncInfo displays only clock synchronization status
+func (manager *SystemInfoManager) ShowTimeSyncInfo() error {
+	fmt.Println("🔍 Collecting time synchronization status...")
+
+	// Collect system information
+	systemInfo, err := manager.collector.CollectSystemInfo()
+	if err != nil {
+		return fmt.Errorf("failed to collect system information: %w", err)
+	}
+
+	// Display only time synchronization information
+	manager.displayer.displayTimeSyncInfo(&systemInfo.TimeSync)
+
+	return nil
+}
+
 // StartContinuousMonitoring starts continuous monitoring of system information
 // This method runs in a loop, refreshing system information at regular intervals
 func (manager *SystemInfoManager) StartContinuousMonitoring(interval time.Duration) error {
@@ -194,6 +213,11 @@ func (manager *SystemInfoManager) SetCollectionOptions(includeTemperature bool,
 	manager.collector.IncludeNetworkStats = includeNetworkStats
 }
 
+// SetClockOffsetWarning sets the clock offset above which time sync is reported as unhealthy
+func (manager *SystemInfoManager) SetClockOffsetWarning(threshold time.Duration) {
+	manager.collector.ClockOffsetWarning = threshold
+}
+
 // ExportSystemInfo exports system information to JSON without displaying it
 // This method is useful for automated exports or background tasks
 func (manager *SystemInfoManager) ExportSystemInfo() (string, error) {
@@ -27,6 +27,19 @@ type SystemInfo struct {
 	LoadAverage  LoadAverage `json:"load_average"`  // System load average (1m, 5m, 15m)
 	ProcessCount int         `json:"process_count"` // Number of running processes
 
+	// Clock and time synchronization health
+	TimeSync TimeSyncInfo `json:"time_sync"` // NTP synchronization status and clock offset
+
+	// OS patch level and pending package updates
+	PatchLevel PatchLevelInfo `json:"patch_level"` // OS build, kernel, pending updates and time since the last update
+
+	// Kernel random subsystem health (Linux only)
+	Entropy EntropyInfo `json:"entropy"` // Available entropy and prolonged low-entropy conditions
+
+	// PCI/PCIe hardware and the drivers bound to it
+	PCIDevices []PCIDeviceInfo `json:"pci_devices"`         // GPUs, NICs, storage controllers and other PCI devices
+	PCIError   string          `json:"pci_error,omitempty"` // Reason the PCI inventory could not be read, if any
+
 	// Timestamp when this information was collected
 	Timestamp time.Time `json:"timestamp"` // When this data was collected
 }
@@ -126,3 +139,78 @@ type LoadAverage struct {
 	Load5Minutes  float64 `json:"load_5_minutes"`  // Load average over 5 minutes
 	Load15Minutes float64 `json:"load_15_minutes"` // Load average over 15 minutes
 }
+
+// TimeSyncInfo contains clock synchronization status and drift information
+type TimeSyncInfo struct {
+	// Synchronization status
+	Source       string `json:"source"`       // Tool the data was read from (e.g., "chronyc", "timedatectl", "w32tm")
+	NTPEnabled   bool   `json:"ntp_enabled"`  // Whether network time synchronization is enabled
+	Synchronized bool   `json:"synchronized"` // Whether the clock is currently synchronized
+	ReferenceID  string `json:"reference_id"` // Time server or reference currently in use
+	Stratum      int    `json:"stratum"`      // NTP stratum of the reference (0 if unknown)
+
+	// Clock accuracy
+	Offset   time.Duration `json:"offset"`    // Current offset from reference time (positive means local clock is fast)
+	DriftPPM float64       `json:"drift_ppm"` // Clock frequency error in parts per million
+
+	// Health evaluation
+	OffsetExceeded bool   `json:"offset_exceeded"` // Whether the absolute offset exceeds the configured threshold
+	Error          string `json:"error,omitempty"` // Reason the status could not be determined, if any
+}
+
+// EntropyInfo describes the entropy available to the kernel random subsystem
+// On older kernels a starved pool makes /dev/random and early getrandom calls block, stalling TLS handshakes
+type EntropyInfo struct {
+	// Pool state
+	Available    int `json:"available"`     // Entropy available in bits (entropy_avail)
+	PoolSize     int `json:"pool_size"`     // Pool size in bits (0 if unknown)
+	LowThreshold int `json:"low_threshold"` // Available entropy below which the pool counts as low
+
+	// Health evaluation
+	Low       bool          `json:"low"`             // Whether available entropy is below the threshold
+	LowSince  time.Time     `json:"low_since"`       // When the current low-entropy condition started (zero if not low)
+	LowFor    time.Duration `json:"low_for"`         // How long available entropy has been low
+	Prolonged bool          `json:"prolonged"`       // Whether entropy has stayed low for longer than the configured time
+	CheckedAt time.Time     `json:"checked_at"`      // When the counters were read
+	Error     string        `json:"error,omitempty"` // Reason entropy could not be read, if any
+}
+
+// PCIDeviceInfo describes one PCI/PCIe device and its driver
+type PCIDeviceInfo struct {
+	// Device identification
+	Address  string `json:"address"`   // Bus address (e.g., "0000:01:00.0"), or the device instance ID on Windows
+	Category string `json:"category"`  // Device category (e.g., "Display", "Network", "Storage", "Bridge")
+	Class    string `json:"class"`     // Detailed class name when known (e.g., "VGA compatible controller")
+	Vendor   string `json:"vendor"`    // Vendor name (e.g., "NVIDIA Corporation")
+	Device   string `json:"device"`    // Device name, empty when the ID database is not available
+	VendorID string `json:"vendor_id"` // PCI vendor ID in hex (e.g., "10de")
+	DeviceID string `json:"device_id"` // PCI device ID in hex
+
+	// Driver information
+	Driver        string `json:"driver"`         // Bound driver (kernel module on Linux, INF file on Windows); empty if none
+	DriverVersion string `json:"driver_version"` // Driver version; in-tree Linux drivers report the kernel release
+}
+
+// PatchLevelInfo describes how up to date the operating system and its packages are
+type PatchLevelInfo struct {
+	// Operating system identification
+	OSName        string `json:"os_name"`        // Distribution or edition and version (e.g., "ubuntu 22.04")
+	OSFamily      string `json:"os_family"`      // Platform family (e.g., "debian", "rhel", "Standalone Workstation")
+	OSBuild       string `json:"os_build"`       // OS build number (Windows only)
+	KernelVersion string `json:"kernel_version"` // Running kernel version
+
+	// Pending updates
+	PackageManager  string `json:"package_manager"`  // Tool the updates were read from (apt, dnf or winget)
+	PendingUpdates  int    `json:"pending_updates"`  // Packages with an update available (-1 if unknown)
+	SecurityUpdates int    `json:"security_updates"` // Pending updates that fix security issues (-1 if unknown)
+
+	// Update history
+	LastUpdate      time.Time     `json:"last_update"`       // When packages were last upgraded (zero if unknown)
+	SinceLastUpdate time.Duration `json:"since_last_update"` // Time elapsed since LastUpdate
+
+	// Health evaluation
+	Stale       bool      `json:"stale"`                  // Whether security updates are pending or the last update is too old
+	StaleReason string    `json:"stale_reason,omitempty"` // Why the system is considered stale
+	CheckedAt   time.Time `json:"checked_at"`             // When the package manager was last queried
+	Error       string    `json:"error,omitempty"`        // Reason pending updates could not be determined, if any
+}
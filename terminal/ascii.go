@@ -0,0 +1,189 @@
+package terminal
+
+import (
+	"os"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// ASCIIEnv is the environment variable that turns on ASCII-only output ("1", "true" or "yes")
+const ASCIIEnv = "SIMPLE_MONITOR_ASCII"
+
+// asciiReplacer maps emoji and box-drawing characters to plain text markers
+// Multi-rune sequences come first so they win over their parts
+var asciiReplacer = strings.NewReplacer(
+	"👨‍💻", "*",
+	"✅", "[OK]",
+	"❌", "[X]",
+	"✓", "+",
+	"⚠️", "[!]",
+	"⚠", "[!]",
+	"🚨", "[ALERT]",
+	"🔥", "[HOT]",
+	"💀", "[KILLED]",
+	"🐢", "[SLOW]",
+	"💤", "[IDLE]",
+	"🛑", "[STOP]",
+	"🚀", ">>",
+	"💾", "[FILE]",
+	"👋", "",
+	"▁", "_",
+	"▂", "_",
+	"▃", "-",
+	"▄", "-",
+	"▅", "=",
+	"▆", "=",
+	"▇", "#",
+	"█", "#",
+	"░", "-",
+	"─", "-",
+	"│", "|",
+	"├", "|-",
+	"└", "`-",
+	"↳", "->",
+	"→", "->",
+	"°", "",
+)
+
+var (
+	asciiMutex     sync.Mutex
+	originalStdout *os.File
+	pipeWriter     *os.File
+	pipeDone       chan struct{}
+)
+
+// ASCIIFromEnv reports whether ASCII-only output is requested by the environment
+// It is on when SIMPLE_MONITOR_ASCII is set to a true value or the terminal is "dumb"
+func ASCIIFromEnv() bool {
+	switch strings.ToLower(os.Getenv(ASCIIEnv)) {
+	case "1", "true", "yes":
+		return true
+	case "0", "false", "no":
+		return false
+	}
+	return os.Getenv("TERM") == "dumb"
+}
+
+// ToASCII replaces emoji and box-drawing characters with plain text markers
+// Symbols without a specific marker become "*"; other text (e.g. accented process names) is kept
+func ToASCII(text string) string {
+	text = asciiReplacer.Replace(text)
+
+	var builder strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '\u200d' || r == '\ufe0f':
+			// Zero-width joiners and emoji presentation selectors have no ASCII form
+		case r >= 0x2190 && r <= 0x2bff, r >= 0x1f000:
+			builder.WriteString("*")
+		default:
+			builder.WriteRune(r)
+		}
+	}
+	return builder.String()
+}
+
+// ASCIIMode reports whether ASCII-only output is active
+func ASCIIMode() bool {
+	asciiMutex.Lock()
+	defer asciiMutex.Unlock()
+
+	return originalStdout != nil
+}
+
+// SetASCIIMode turns ASCII-only output on or off
+// While on, standard output is routed through a filter that rewrites every displayer's output,
+// so call RestoreOutput before exiting to flush it
+func SetASCIIMode(enabled bool) error {
+	asciiMutex.Lock()
+	defer asciiMutex.Unlock()
+
+	if enabled == (originalStdout != nil) {
+		return nil
+	}
+	if !enabled {
+		restoreOutput()
+		return nil
+	}
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+
+	originalStdout = os.Stdout
+	pipeWriter = writer
+	pipeDone = make(chan struct{})
+	go copyASCII(reader, originalStdout, pipeDone)
+
+	os.Stdout = writer
+	return nil
+}
+
+// RestoreOutput flushes pending ASCII output and restores the original standard output
+func RestoreOutput() {
+	asciiMutex.Lock()
+	defer asciiMutex.Unlock()
+
+	if originalStdout != nil {
+		restoreOutput()
+	}
+}
+
+// restoreOutput closes the filter pipe and waits until everything written has been copied
+func restoreOutput() {
+	os.Stdout = originalStdout
+	pipeWriter.Close()
+	<-pipeDone
+
+	originalStdout = nil
+	pipeWriter = nil
+}
+
+// copyASCII copies filtered output until the pipe is closed
+// A character split across two reads is held back until the rest arrives
+func copyASCII(reader *os.File, output *os.File, done chan struct{}) {
+	defer close(done)
+	defer reader.Close()
+
+	buffer := make([]byte, 32*1024)
+	var pending []byte
+	for {
+		count, err := reader.Read(buffer)
+		if count > 0 {
+			chunk := append(pending, buffer[:count]...)
+
+			// Keep an incomplete trailing UTF-8 sequence for the next read
+			cut := len(chunk)
+			for i := len(chunk) - 1; i >= 0 && i >= len(chunk)-utf8.UTFMax; i-- {
+				if utf8.RuneStart(chunk[i]) {
+					if !utf8.FullRune(chunk[i:]) {
+						cut = i
+					}
+					break
+				}
+			}
+
+			output.WriteString(ToASCII(string(chunk[:cut])))
+			pending = append([]byte(nil), chunk[cut:]...)
+		}
+		if err != nil {
+			if len(pending) > 0 {
+				output.Write(pending)
+			}
+			return
+		}
+	}
+}
+
+// outputFile returns the file the terminal is attached to, even while ASCII mode filters standard output
+func outputFile() *os.File {
+	asciiMutex.Lock()
+	defer asciiMutex.Unlock()
+
+	if originalStdout != nil {
+		return originalStdout
+	}
+	return os.Stdout
+}
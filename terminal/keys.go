@@ -0,0 +1,86 @@
+package terminal
+
+import (
+	"os"
+	"time"
+)
+
+// keyPollInterval is how long a key poll waits, so Close never has to interrupt a blocked read
+const keyPollInterval = 100 * time.Millisecond
+
+// PausedNotice is shown under a paused live display
+const PausedNotice = "⏸️  Paused: space resumes, n shows the next refresh"
+
+// KeyReader delivers single key presses without waiting for Enter
+// The terminal is switched out of line mode while the reader is open; Ctrl+C still raises SIGINT
+type KeyReader struct {
+	keys    chan byte
+	stop    chan struct{}
+	done    chan struct{}
+	restore func()
+}
+
+// NewKeyReader switches standard input to single-key mode and starts reading keys
+// It fails when standard input is not a terminal
+func NewKeyReader() (*KeyReader, error) {
+	restore, err := enableKeyInput()
+	if err != nil {
+		return nil, err
+	}
+
+	reader := &KeyReader{
+		keys:    make(chan byte, 16),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+		restore: restore,
+	}
+	go reader.run()
+	return reader, nil
+}
+
+// Keys returns the channel of key presses
+func (reader *KeyReader) Keys() <-chan byte {
+	return reader.keys
+}
+
+// Close stops reading keys and restores line mode
+func (reader *KeyReader) Close() {
+	close(reader.stop)
+	<-reader.done
+	reader.restore()
+}
+
+// run polls standard input until the reader is closed
+// Input is only read once it is available, so no read is left pending after Close
+func (reader *KeyReader) run() {
+	defer close(reader.done)
+
+	buffer := make([]byte, 1)
+	for {
+		select {
+		case <-reader.stop:
+			return
+		default:
+		}
+
+		ready, err := waitForKey(keyPollInterval)
+		if err != nil {
+			return
+		}
+		if !ready {
+			continue
+		}
+
+		count, err := os.Stdin.Read(buffer)
+		if err != nil {
+			return
+		}
+		if count == 1 {
+			select {
+			case reader.keys <- buffer[0]:
+			default:
+				// Drop keys nobody is reading rather than blocking
+			}
+		}
+	}
+}
@@ -0,0 +1,18 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd && !dragonfly && !windows
+
+package terminal
+
+import (
+	"errors"
+	"time"
+)
+
+// enableKeyInput is unavailable here, so live monitors fall back to Ctrl+C only
+func enableKeyInput() (func(), error) {
+	return nil, errors.New("single-key input is not available on this platform")
+}
+
+// waitForKey is never reached because enableKeyInput always fails
+func waitForKey(timeout time.Duration) (bool, error) {
+	return false, errors.New("single-key input is not available on this platform")
+}
@@ -0,0 +1,45 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package terminal
+
+import (
+	"os"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// enableKeyInput turns off line buffering and echo on standard input
+// Signal keys stay enabled so Ctrl+C still stops monitoring
+func enableKeyInput() (func(), error) {
+	fd := int(os.Stdin.Fd())
+	original, err := unix.IoctlGetTermios(fd, ioctlGetTermios)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := *original
+	settings.Lflag &^= unix.ICANON | unix.ECHO
+	settings.Cc[unix.VMIN] = 1
+	settings.Cc[unix.VTIME] = 0
+	if err := unix.IoctlSetTermios(fd, ioctlSetTermios, &settings); err != nil {
+		return nil, err
+	}
+
+	return func() {
+		unix.IoctlSetTermios(fd, ioctlSetTermios, original)
+	}, nil
+}
+
+// waitForKey reports whether a key is available within the timeout
+func waitForKey(timeout time.Duration) (bool, error) {
+	fds := []unix.PollFd{{Fd: int32(os.Stdin.Fd()), Events: unix.POLLIN}}
+	count, err := unix.Poll(fds, int(timeout/time.Millisecond))
+	if err == unix.EINTR {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
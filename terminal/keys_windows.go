@@ -0,0 +1,39 @@
+//go:build windows
+
+package terminal
+
+import (
+	"os"
+	"time"
+
+	"golang.org/x/sys/windows"
+)
+
+// enableKeyInput turns off line input and echo on the console
+// Processed input stays enabled so Ctrl+C still stops monitoring
+func enableKeyInput() (func(), error) {
+	handle := windows.Handle(os.Stdin.Fd())
+
+	var original uint32
+	if err := windows.GetConsoleMode(handle, &original); err != nil {
+		return nil, err
+	}
+
+	mode := original &^ (windows.ENABLE_LINE_INPUT | windows.ENABLE_ECHO_INPUT)
+	if err := windows.SetConsoleMode(handle, mode); err != nil {
+		return nil, err
+	}
+
+	return func() {
+		windows.SetConsoleMode(handle, original)
+	}, nil
+}
+
+// waitForKey reports whether a key is available within the timeout
+func waitForKey(timeout time.Duration) (bool, error) {
+	event, err := windows.WaitForSingleObject(windows.Handle(os.Stdin.Fd()), uint32(timeout/time.Millisecond))
+	if err != nil {
+		return false, err
+	}
+	return event == windows.WAIT_OBJECT_0, nil
+}
@@ -0,0 +1,26 @@
+package terminal
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ReadSecret reads one line from standard input without echoing it, for tokens and passwords
+// When standard input is not a terminal the line is read as is
+func ReadSecret() (string, error) {
+	restore, err := disableEcho()
+	if err == nil {
+		defer func() {
+			restore()
+			fmt.Println()
+		}()
+	}
+
+	scanner := bufio.NewScanner(os.Stdin)
+	if !scanner.Scan() {
+		return "", scanner.Err()
+	}
+	return strings.TrimSpace(scanner.Text()), nil
+}
@@ -0,0 +1,10 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd && !dragonfly && !windows
+
+package terminal
+
+import "errors"
+
+// disableEcho is unavailable here, so secrets are read with echo
+func disableEcho() (func(), error) {
+	return nil, errors.New("turning off echo is not available on this platform")
+}
@@ -0,0 +1,29 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package terminal
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// disableEcho turns off echo on standard input, keeping line editing
+func disableEcho() (func(), error) {
+	fd := int(os.Stdin.Fd())
+	original, err := unix.IoctlGetTermios(fd, ioctlGetTermios)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := *original
+	settings.Lflag &^= unix.ECHO
+	settings.Lflag |= unix.ICANON
+	if err := unix.IoctlSetTermios(fd, ioctlSetTermios, &settings); err != nil {
+		return nil, err
+	}
+
+	return func() {
+		unix.IoctlSetTermios(fd, ioctlSetTermios, original)
+	}, nil
+}
@@ -0,0 +1,28 @@
+//go:build windows
+
+package terminal
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// disableEcho turns off echo on the console, keeping line input
+func disableEcho() (func(), error) {
+	handle := windows.Handle(os.Stdin.Fd())
+
+	var original uint32
+	if err := windows.GetConsoleMode(handle, &original); err != nil {
+		return nil, err
+	}
+
+	mode := (original | windows.ENABLE_LINE_INPUT) &^ windows.ENABLE_ECHO_INPUT
+	if err := windows.SetConsoleMode(handle, mode); err != nil {
+		return nil, err
+	}
+
+	return func() {
+		windows.SetConsoleMode(handle, original)
+	}, nil
+}
@@ -0,0 +1,94 @@
+package terminal
+
+import (
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+// ColorBlindEnv is the environment variable that turns on color-blind mode ("1", "true" or "yes")
+const ColorBlindEnv = "SIMPLE_MONITOR_COLORBLIND"
+
+// Severity is the level a displayer color stands for
+type Severity int
+
+// Severity levels, from healthy to critical
+const (
+	SeverityNone Severity = iota
+	SeverityOK
+	SeverityWarning
+	SeverityHigh
+	SeverityCritical
+)
+
+// severityMarkers are the non-color markers of each level, padded to one width so columns stay aligned
+var severityMarkers = map[Severity]string{
+	SeverityOK:       "✓  ",
+	SeverityWarning:  "!  ",
+	SeverityHigh:     "!  ",
+	SeverityCritical: "!! ",
+}
+
+// colorBlindPalette replaces red/yellow/green with colors that stay apart under the common
+// red-green deficiencies: blue for healthy, yellow and orange for warnings, bold magenta for critical
+var colorBlindPalette = map[Severity]string{
+	SeverityOK:       "\033[34m",
+	SeverityWarning:  "\033[33m",
+	SeverityHigh:     "\033[38;5;208m",
+	SeverityCritical: "\033[1;35m",
+}
+
+var colorBlindMode atomic.Bool
+
+// ColorBlindFromEnv reports whether color-blind mode is requested by the environment
+func ColorBlindFromEnv() bool {
+	switch strings.ToLower(os.Getenv(ColorBlindEnv)) {
+	case "1", "true", "yes":
+		return true
+	}
+	return false
+}
+
+// ColorBlindMode reports whether the color-blind palette and severity markers are active
+func ColorBlindMode() bool {
+	return colorBlindMode.Load()
+}
+
+// SetColorBlindMode turns the color-blind palette and severity markers on or off
+func SetColorBlindMode(enabled bool) {
+	colorBlindMode.Store(enabled)
+}
+
+// SeverityMarker returns the non-color marker of a level ("✓", "!" or "!!" followed by padding)
+func SeverityMarker(level Severity) string {
+	return severityMarkers[level]
+}
+
+// SeverityLegend explains the severity markers, for the footer of a screen that shows them
+func SeverityLegend() string {
+	return "Severity: ✓ ok   ! warning   !! critical"
+}
+
+// PaletteColor returns the color of a level in the active palette, or fallback outside color-blind mode
+func PaletteColor(level Severity, fallback string) string {
+	if color, ok := colorBlindPalette[level]; ok && ColorBlindMode() {
+		return color
+	}
+	return fallback
+}
+
+// WithoutMarker removes a trailing severity marker from a displayer color
+// Bars are drawn in the color alone; the marker is shown next to the value instead
+func WithoutMarker(color string) string {
+	return strings.TrimSuffix(color, MarkerOf(color))
+}
+
+// MarkerOf returns the severity marker at the end of a displayer color, or "" when it has none
+func MarkerOf(color string) string {
+	for _, level := range []Severity{SeverityCritical, SeverityOK, SeverityWarning} {
+		if strings.HasSuffix(color, severityMarkers[level]) {
+			return severityMarkers[level]
+		}
+	}
+	return ""
+}
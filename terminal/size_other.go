@@ -0,0 +1,17 @@
+//go:build !unix && !windows
+
+package terminal
+
+import "errors"
+
+// hasResizeSignal reports whether the terminal announces size changes with a signal
+const hasResizeSignal = false
+
+// querySize is unavailable here, so COLUMNS/LINES or the defaults are used
+func querySize() (Size, error) {
+	return Size{}, errors.New("terminal size is not available on this platform")
+}
+
+// notifyResize is a no-op because there is no resize signal
+func notifyResize(onResize func()) {
+}
@@ -0,0 +1,34 @@
+//go:build unix
+
+package terminal
+
+import (
+	"os"
+	"os/signal"
+
+	"golang.org/x/sys/unix"
+)
+
+// hasResizeSignal reports whether the terminal announces size changes with SIGWINCH
+const hasResizeSignal = true
+
+// querySize reads the terminal size of standard output
+func querySize() (Size, error) {
+	winsize, err := unix.IoctlGetWinsize(int(outputFile().Fd()), unix.TIOCGWINSZ)
+	if err != nil {
+		return Size{}, err
+	}
+	return Size{Width: int(winsize.Col), Height: int(winsize.Row)}, nil
+}
+
+// notifyResize calls onResize whenever the terminal is resized
+func notifyResize(onResize func()) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, unix.SIGWINCH)
+
+	go func() {
+		for range signals {
+			onResize()
+		}
+	}()
+}
@@ -0,0 +1,25 @@
+//go:build windows
+
+package terminal
+
+import "golang.org/x/sys/windows"
+
+// hasResizeSignal reports whether the terminal announces size changes with a signal
+// Windows consoles do not, so the size is polled instead
+const hasResizeSignal = false
+
+// querySize reads the visible window size of the console attached to standard output
+func querySize() (Size, error) {
+	var info windows.ConsoleScreenBufferInfo
+	if err := windows.GetConsoleScreenBufferInfo(windows.Handle(outputFile().Fd()), &info); err != nil {
+		return Size{}, err
+	}
+	return Size{
+		Width:  int(info.Window.Right-info.Window.Left) + 1,
+		Height: int(info.Window.Bottom-info.Window.Top) + 1,
+	}, nil
+}
+
+// notifyResize is a no-op because Windows has no resize signal
+func notifyResize(onResize func()) {
+}
@@ -0,0 +1,120 @@
+// Package terminal handles terminal size, key input, ASCII and color-blind output and the window title
+package terminal
+
+import (
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Default dimensions used when the output is not a terminal and COLUMNS/LINES are not set
+const (
+	DefaultWidth  = 80
+	DefaultHeight = 24
+)
+
+// Size describes the terminal dimensions in character cells
+type Size struct {
+	Width  int `json:"width"`  // Number of columns
+	Height int `json:"height"` // Number of rows
+}
+
+var (
+	mutex     sync.Mutex
+	cached    Size
+	checkedAt time.Time
+	watching  bool
+	resized   bool
+)
+
+// GetSize returns the current terminal size
+// On platforms with SIGWINCH the size is re-read only after a resize; elsewhere it is re-read at most once per second
+func GetSize() Size {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if !watching {
+		watching = true
+		notifyResize(markResized)
+	}
+
+	stale := checkedAt.IsZero() || resized
+	if !hasResizeSignal && time.Since(checkedAt) >= time.Second {
+		stale = true
+	}
+	if stale {
+		cached = readSize()
+		checkedAt = time.Now()
+		resized = false
+	}
+	return cached
+}
+
+// Width returns the current terminal width in columns
+func Width() int {
+	return GetSize().Width
+}
+
+// Height returns the current terminal height in rows
+func Height() int {
+	return GetSize().Height
+}
+
+// markResized invalidates the cached size after a resize signal
+func markResized() {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	resized = true
+}
+
+// readSize queries the terminal, falling back to COLUMNS/LINES and then the defaults
+func readSize() Size {
+	size, err := querySize()
+	if err == nil && size.Width > 0 && size.Height > 0 {
+		return size
+	}
+
+	size = Size{Width: DefaultWidth, Height: DefaultHeight}
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		size.Width = columns
+	}
+	if lines, err := strconv.Atoi(os.Getenv("LINES")); err == nil && lines > 0 {
+		size.Height = lines
+	}
+	return size
+}
+
+// Fit clamps a width between min and max
+func Fit(width, min, max int) int {
+	if width > max {
+		width = max
+	}
+	if width < min {
+		width = min
+	}
+	return width
+}
+
+// Truncate shortens text to length, marking the cut with "..."
+func Truncate(text string, length int) string {
+	if length < 4 {
+		length = 4
+	}
+	if len(text) > length {
+		return text[:length-3] + "..."
+	}
+	return text
+}
+
+// TruncateLeft shortens text to length from the left, so the end (e.g. a file name) stays visible
+func TruncateLeft(text string, length int) string {
+	if length < 4 {
+		length = 4
+	}
+	if len(text) > length {
+		return "..." + text[len(text)-length+3:]
+	}
+	return text
+}
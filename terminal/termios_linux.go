@@ -0,0 +1,11 @@
+//go:build linux
+
+package terminal
+
+import "golang.org/x/sys/unix"
+
+// Terminal attribute requests
+const (
+	ioctlGetTermios = unix.TCGETS
+	ioctlSetTermios = unix.TCSETS
+)
@@ -0,0 +1,21 @@
+package terminal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SetTitle sets the terminal window (or tab) title with the xterm OSC escape sequence
+// It writes to the terminal directly, so the title is unaffected by ASCII-only mode
+func SetTitle(title string) error {
+	// Control characters would end the escape sequence early
+	title = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, title)
+
+	_, err := fmt.Fprintf(outputFile(), "\033]0;%s\007", title)
+	return err
+}
@@ -0,0 +1,200 @@
+// Package titlebar pins key figures (CPU, memory and the top alert) to the terminal title
+package titlebar
+
+import (
+	"fmt"
+	"github.com/ahmadreza-log/simple-monitor/terminal"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/disk"
+	"github.com/shirou/gopsutil/v3/mem"
+)
+
+// defaultTitle is written back when the updater stops
+const defaultTitle = "Simple Monitor"
+
+// Updater keeps key figures (CPU %, memory %, top alert) in the terminal title
+// The title keeps updating while menus or other monitors are shown, so the tool can sit
+// in a background tab and still show system health at a glance
+type Updater struct {
+	mutex   sync.Mutex
+	config  Config
+	metrics Metrics
+	stop    chan bool
+	done    chan bool
+}
+
+// NewUpdater creates a new title updater with default configuration values
+func NewUpdater() *Updater {
+	mountpoint := "/"
+	if runtime.GOOS == "windows" {
+		mountpoint = "C:\\"
+	}
+
+	return &Updater{
+		config: Config{
+			Interval:      2 * time.Second,
+			Mountpoint:    mountpoint,
+			CPUWarning:    90.0,
+			MemoryWarning: 90.0,
+			DiskWarning:   90.0,
+			CPUEnabled:    true,
+			MemoryEnabled: true,
+			DiskEnabled:   true,
+		},
+	}
+}
+
+// Start begins updating the title in the background
+func (updater *Updater) Start() {
+	updater.mutex.Lock()
+	defer updater.mutex.Unlock()
+
+	if updater.stop != nil {
+		return
+	}
+	updater.stop = make(chan bool)
+	updater.done = make(chan bool)
+	go updater.run(updater.stop, updater.done, updater.config.Interval)
+}
+
+// Stop stops updating and restores the default title
+func (updater *Updater) Stop() {
+	updater.mutex.Lock()
+	stop, done := updater.stop, updater.done
+	updater.stop, updater.done = nil, nil
+	updater.mutex.Unlock()
+
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+	terminal.SetTitle(defaultTitle)
+}
+
+// IsRunning returns whether the title is being updated
+func (updater *Updater) IsRunning() bool {
+	updater.mutex.Lock()
+	defer updater.mutex.Unlock()
+
+	return updater.stop != nil
+}
+
+// run refreshes the title every interval until stopped
+func (updater *Updater) run(stop, done chan bool, interval time.Duration) {
+	defer close(done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if metrics, err := updater.Collect(); err == nil {
+			terminal.SetTitle(Format(metrics))
+		}
+
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Collect gathers the figures shown in the title
+// The CPU figure is the usage since the previous call, so the first value may be 0
+func (updater *Updater) Collect() (Metrics, error) {
+	updater.mutex.Lock()
+	config := updater.config
+	updater.mutex.Unlock()
+
+	metrics := Metrics{Timestamp: time.Now(), CPUPercent: -1, MemoryPercent: -1}
+
+	if config.CPUEnabled {
+		percents, err := cpu.Percent(0, false)
+		if err != nil {
+			return metrics, fmt.Errorf("failed to get CPU usage: %w", err)
+		}
+		metrics.CPUPercent = 0
+		if len(percents) > 0 {
+			metrics.CPUPercent = percents[0]
+		}
+	}
+
+	if config.MemoryEnabled {
+		memory, err := mem.VirtualMemory()
+		if err != nil {
+			return metrics, fmt.Errorf("failed to get memory usage: %w", err)
+		}
+		metrics.MemoryPercent = memory.UsedPercent
+	}
+
+	// Disk usage is optional; a missing mountpoint just skips the disk alert
+	if config.DiskEnabled {
+		if usage, err := disk.Usage(config.Mountpoint); err == nil {
+			metrics.DiskPercent = usage.UsedPercent
+		}
+	}
+
+	// The most severe alert wins: memory exhaustion, then a full disk, then CPU saturation
+	switch {
+	case config.MemoryEnabled && metrics.MemoryPercent >= config.MemoryWarning:
+		metrics.TopAlert = fmt.Sprintf("Memory %.0f%%", metrics.MemoryPercent)
+	case config.DiskEnabled && metrics.DiskPercent >= config.DiskWarning:
+		metrics.TopAlert = fmt.Sprintf("Disk %s %.0f%%", config.Mountpoint, metrics.DiskPercent)
+	case config.CPUEnabled && metrics.CPUPercent >= config.CPUWarning:
+		metrics.TopAlert = fmt.Sprintf("CPU %.0f%%", metrics.CPUPercent)
+	}
+
+	updater.mutex.Lock()
+	updater.metrics = metrics
+	updater.mutex.Unlock()
+
+	return metrics, nil
+}
+
+// GetMetrics returns the figures last written to the title
+func (updater *Updater) GetMetrics() Metrics {
+	updater.mutex.Lock()
+	defer updater.mutex.Unlock()
+
+	return updater.metrics
+}
+
+// GetConfig returns the current configuration
+func (updater *Updater) GetConfig() Config {
+	updater.mutex.Lock()
+	defer updater.mutex.Unlock()
+
+	return updater.config
+}
+
+// UpdateConfig updates the configuration; a new interval applies after the next restart
+func (updater *Updater) UpdateConfig(config Config) {
+	updater.mutex.Lock()
+	defer updater.mutex.Unlock()
+
+	updater.config = config
+}
+
+// Format builds the title text, e.g. "CPU 12% | MEM 48% | OK - Simple Monitor"
+// Plain ASCII is used because many terminals show emoji in titles poorly; figures of disabled monitors are left out
+func Format(metrics Metrics) string {
+	var parts []string
+	if metrics.CPUPercent >= 0 {
+		parts = append(parts, fmt.Sprintf("CPU %.0f%%", metrics.CPUPercent))
+	}
+	if metrics.MemoryPercent >= 0 {
+		parts = append(parts, fmt.Sprintf("MEM %.0f%%", metrics.MemoryPercent))
+	}
+	if metrics.TopAlert != "" {
+		parts = append(parts, "ALERT "+metrics.TopAlert)
+	} else {
+		parts = append(parts, "OK")
+	}
+	return strings.Join(parts, " | ") + " - " + defaultTitle
+}
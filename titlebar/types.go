@@ -0,0 +1,24 @@
+package titlebar
+
+import "time"
+
+// Config holds the terminal title settings
+type Config struct {
+	Interval      time.Duration `json:"interval"`       // How often the title is refreshed
+	Mountpoint    string        `json:"mountpoint"`     // Filesystem checked for the disk alert
+	CPUWarning    float64       `json:"cpu_warning"`    // CPU usage (%) that raises a title alert
+	MemoryWarning float64       `json:"memory_warning"` // Memory usage (%) that raises a title alert
+	DiskWarning   float64       `json:"disk_warning"`   // Disk usage (%) that raises a title alert
+	CPUEnabled    bool          `json:"cpu_enabled"`    // Whether CPU usage is shown and alerted on (follows the CPU monitor toggle)
+	MemoryEnabled bool          `json:"memory_enabled"` // Whether memory usage is shown and alerted on (follows the memory monitor toggle)
+	DiskEnabled   bool          `json:"disk_enabled"`   // Whether the disk alert is checked (follows the disk monitor toggle)
+}
+
+// Metrics holds the figures pinned to the title
+type Metrics struct {
+	Timestamp     time.Time `json:"timestamp"`      // When the figures were collected
+	CPUPercent    float64   `json:"cpu_percent"`    // System CPU usage (-1 when the CPU monitor is disabled)
+	MemoryPercent float64   `json:"memory_percent"` // Physical memory usage (-1 when the memory monitor is disabled)
+	DiskPercent   float64   `json:"disk_percent"`   // Usage of the configured filesystem
+	TopAlert      string    `json:"top_alert"`      // Most severe current alert (empty when healthy)
+}